@@ -0,0 +1,329 @@
+package cmd
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"time"
+
+	"github.com/google/uuid"
+	_ "github.com/jackc/pgx/v5/stdlib"
+	"github.com/spf13/cobra"
+
+	"github.com/nextlevelbuilder/goclaw/internal/backup"
+	"github.com/nextlevelbuilder/goclaw/internal/config"
+	"github.com/nextlevelbuilder/goclaw/internal/upgrade"
+)
+
+func backupCmd() *cobra.Command {
+	var (
+		output    string
+		tenant    string
+		targetKey string
+		noFiles   bool
+	)
+	cmd := &cobra.Command{
+		Use:   "backup",
+		Short: "Write a backup archive of the whole instance or one tenant",
+		Long: `Archives every store (agents, context files, sessions, memory and embeddings,
+knowledge graph, teams and tasks, cron, channel instances, skills, MCP servers)
+plus the data directory and agent workspaces into a versioned tar.gz.
+
+Stored secrets stay encrypted with GOCLAW_ENCRYPTION_KEY unless --target-key
+re-encrypts them for the instance the archive will be restored into.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := config.Load(resolveConfigPath())
+			if err != nil {
+				return fmt.Errorf("load config: %w", err)
+			}
+			ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+			defer stop()
+
+			sqlDB, db, err := openBackupDB(cfg, storageBackend(cfg), "", "")
+			if err != nil {
+				return err
+			}
+			defer sqlDB.Close()
+
+			opts := backup.Options{
+				SourceKey:  os.Getenv("GOCLAW_ENCRYPTION_KEY"),
+				TargetKey:  targetKey,
+				Exclude:    backupExcludes(cfg, cfg.ResolvedDataDir()),
+				AppVersion: Version,
+			}
+			if tenant != "" {
+				if opts.TenantID, err = db.ResolveTenant(ctx, tenant); err != nil {
+					return err
+				}
+			}
+			if !noFiles {
+				opts.DataDir, opts.Workspace = cfg.ResolvedDataDir(), backupWorkspace(cfg)
+			}
+			if output == "" {
+				output = fmt.Sprintf("goclaw-backup-%s.tar.gz", time.Now().UTC().Format("20060102-150405"))
+			}
+
+			f, err := os.OpenFile(output, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+			if err != nil {
+				return fmt.Errorf("create %s: %w", output, err)
+			}
+			rep, err := backup.Backup(ctx, db, f, opts)
+			if cerr := f.Close(); err == nil {
+				err = cerr
+			}
+			if err != nil {
+				os.Remove(output)
+				return fmt.Errorf("backup: %w", err)
+			}
+			printBackupReport(rep)
+			fmt.Printf("Backup written to %s\n", output)
+			return nil
+		},
+	}
+	cmd.Flags().StringVarP(&output, "output", "o", "", "archive path (default: goclaw-backup-<timestamp>.tar.gz)")
+	cmd.Flags().StringVar(&tenant, "tenant", "", "back up a single tenant (slug or ID)")
+	cmd.Flags().StringVar(&targetKey, "target-key", "", "re-encrypt stored secrets with this key")
+	cmd.Flags().BoolVar(&noFiles, "no-files", false, "skip the data directory and workspaces")
+	return cmd
+}
+
+func restoreCmd() *cobra.Command {
+	var (
+		sourceKey string
+		noFiles   bool
+	)
+	cmd := &cobra.Command{
+		Use:   "restore <archive>",
+		Short: "Restore a backup archive into the configured database",
+		Long: `Loads a backup archive into the configured storage backend, which may differ
+from the one the archive was taken from. Rows and files that already exist are
+kept, so restoring twice is safe. Stop the gateway first; PostgreSQL must be
+migrated ('goclaw migrate up') before restoring.
+
+Pass --source-key when the archive's secrets were encrypted with a different key
+than GOCLAW_ENCRYPTION_KEY.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := config.Load(resolveConfigPath())
+			if err != nil {
+				return fmt.Errorf("load config: %w", err)
+			}
+			ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+			defer stop()
+
+			f, err := os.Open(args[0])
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+
+			sqlDB, db, err := openBackupDB(cfg, storageBackend(cfg), "", "")
+			if err != nil {
+				return err
+			}
+			defer sqlDB.Close()
+
+			opts := backup.Options{}
+			if sourceKey != "" {
+				opts.SourceKey, opts.TargetKey = sourceKey, os.Getenv("GOCLAW_ENCRYPTION_KEY")
+			}
+			if !noFiles {
+				opts.DataDir, opts.Workspace = cfg.ResolvedDataDir(), backupWorkspace(cfg)
+			}
+			rep, err := backup.Restore(ctx, db, f, opts)
+			if err != nil {
+				return fmt.Errorf("restore: %w", err)
+			}
+			if m := rep.Manifest; m != nil {
+				scope := "instance"
+				if m.TenantID != uuid.Nil {
+					scope = "tenant " + m.TenantSlug
+				}
+				fmt.Printf("Archive: %s backup from %s (%s, created %s)\n", scope, m.Backend, m.AppVersion, m.CreatedAt.Format(time.RFC3339))
+			}
+			printBackupReport(rep)
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&sourceKey, "source-key", "", "encryption key the archive's secrets were written with")
+	cmd.Flags().BoolVar(&noFiles, "no-files", false, "skip restoring the data directory and workspaces")
+	return cmd
+}
+
+func migrateBackendCmd() *cobra.Command {
+	var (
+		from, to    string
+		sqlitePath  string
+		postgresDSN string
+		tenant      string
+		targetKey   string
+	)
+	cmd := &cobra.Command{
+		Use:   "migrate-backend",
+		Short: "Copy all data from one storage backend to another",
+		Long: `Streams every table from one storage backend into another, e.g. from the
+SQLite lite edition to PostgreSQL. Files in the data directory are shared by
+both backends and are not copied. The target PostgreSQL database must be
+migrated first ('goclaw migrate up'); existing rows are kept.
+
+After a successful copy, set GOCLAW_STORAGE_BACKEND to the new backend.`,
+		Example: "  goclaw migrate-backend --from sqlite --to postgres",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if from == to {
+				return fmt.Errorf("--from and --to must differ")
+			}
+			cfg, err := config.Load(resolveConfigPath())
+			if err != nil {
+				return fmt.Errorf("load config: %w", err)
+			}
+			ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+			defer stop()
+
+			srcSQL, src, err := openBackupDB(cfg, from, sqlitePath, postgresDSN)
+			if err != nil {
+				return fmt.Errorf("open %s: %w", from, err)
+			}
+			defer srcSQL.Close()
+			dstSQL, dst, err := openBackupDB(cfg, to, sqlitePath, postgresDSN)
+			if err != nil {
+				return fmt.Errorf("open %s: %w", to, err)
+			}
+			defer dstSQL.Close()
+
+			opts := backup.Options{
+				SourceKey: os.Getenv("GOCLAW_ENCRYPTION_KEY"),
+				TargetKey: targetKey,
+			}
+			if tenant != "" {
+				if opts.TenantID, err = src.ResolveTenant(ctx, tenant); err != nil {
+					return err
+				}
+			}
+			rep, err := backup.Copy(ctx, src, dst, opts)
+			if err != nil {
+				return fmt.Errorf("migrate-backend: %w", err)
+			}
+			printBackupReport(rep)
+			fmt.Printf("Copied %s -> %s. Set GOCLAW_STORAGE_BACKEND=%s to switch.\n", from, to, to)
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&from, "from", backup.BackendSQLite, "source backend (sqlite or postgres)")
+	cmd.Flags().StringVar(&to, "to", backup.BackendPostgres, "target backend (sqlite or postgres)")
+	cmd.Flags().StringVar(&sqlitePath, "sqlite-path", "", "SQLite database path (default: GOCLAW_SQLITE_PATH or <data-dir>/goclaw.db)")
+	cmd.Flags().StringVar(&postgresDSN, "postgres-dsn", "", "PostgreSQL DSN (default: GOCLAW_POSTGRES_DSN)")
+	cmd.Flags().StringVar(&tenant, "tenant", "", "copy a single tenant (slug or ID)")
+	cmd.Flags().StringVar(&targetKey, "target-key", "", "re-encrypt stored secrets with this key")
+	return cmd
+}
+
+// storageBackend returns the configured backend (GOCLAW_STORAGE_BACKEND).
+func storageBackend(cfg *config.Config) string {
+	if cfg.Database.StorageBackend == "" {
+		return backup.BackendPostgres
+	}
+	return cfg.Database.StorageBackend
+}
+
+// openBackupDB opens a backend by name. Empty sqlitePath and dsn fall back to
+// the configured values.
+func openBackupDB(cfg *config.Config, backend, sqlitePath, dsn string) (*sql.DB, *backup.DB, error) {
+	var (
+		sqlDB *sql.DB
+		err   error
+	)
+	switch backend {
+	case backup.BackendPostgres:
+		if dsn == "" {
+			dsn = cfg.Database.PostgresDSN
+		}
+		if dsn == "" {
+			return nil, nil, fmt.Errorf("GOCLAW_POSTGRES_DSN environment variable is not set")
+		}
+		if sqlDB, err = sql.Open("pgx", dsn); err != nil {
+			return nil, nil, fmt.Errorf("connect: %w", err)
+		}
+		s, err := upgrade.CheckSchema(sqlDB)
+		if err != nil {
+			sqlDB.Close()
+			return nil, nil, fmt.Errorf("check schema: %w", err)
+		}
+		if !s.Compatible {
+			sqlDB.Close()
+			return nil, nil, fmt.Errorf("schema version %d, need %d: run 'goclaw migrate up' first", s.CurrentVersion, s.RequiredVersion)
+		}
+	case backup.BackendSQLite:
+		if sqlitePath == "" {
+			sqlitePath = sqliteDBPath(cfg)
+		}
+		if sqlDB, err = openSQLiteForBackup(sqlitePath); err != nil {
+			return nil, nil, err
+		}
+	default:
+		return nil, nil, fmt.Errorf("unknown backend %q (want sqlite or postgres)", backend)
+	}
+	db, err := backup.NewDB(sqlDB, backend)
+	if err != nil {
+		sqlDB.Close()
+		return nil, nil, err
+	}
+	return sqlDB, db, nil
+}
+
+func sqliteDBPath(cfg *config.Config) string {
+	if cfg.Database.SQLitePath != "" {
+		return config.ExpandHome(cfg.Database.SQLitePath)
+	}
+	return filepath.Join(cfg.ResolvedDataDir(), "goclaw.db")
+}
+
+// backupExcludes lists files never archived: the live SQLite database is
+// captured table by table instead, and the document extraction cache is
+// rebuilt on demand.
+func backupExcludes(cfg *config.Config, dataDir string) []string {
+	path := filepath.Join(dataDir, "goclaw.db")
+	if cfg.Database.SQLitePath != "" {
+		path = config.ExpandHome(cfg.Database.SQLitePath)
+	}
+	return []string{path, path + "-wal", path + "-shm", path + "-journal", documentCacheDir(dataDir)}
+}
+
+func backupWorkspace(cfg *config.Config) string {
+	ws := cfg.WorkspacePath()
+	if abs, err := filepath.Abs(ws); err == nil {
+		return abs
+	}
+	return ws
+}
+
+func printBackupReport(rep *backup.Report) {
+	tables := 0
+	for _, t := range rep.Tables {
+		if t.Rows > 0 || t.Skipped > 0 {
+			tables++
+		}
+	}
+	fmt.Printf("Rows: %d in %d tables\n", rep.Rows(), tables)
+	fmt.Printf("Files: %d", rep.Files)
+	if rep.SkippedFiles > 0 {
+		fmt.Printf(" (%d already present, kept)", rep.SkippedFiles)
+	}
+	fmt.Println()
+	for _, t := range rep.Tables {
+		if t.Skipped > 0 {
+			fmt.Printf("  %s: %d rows already present, kept\n", t.Name, t.Skipped)
+		}
+	}
+	if len(rep.MissingTables) > 0 {
+		fmt.Printf("Tables not in target schema (skipped): %v\n", rep.MissingTables)
+	}
+	if len(rep.DroppedColumns) > 0 {
+		fmt.Printf("Columns not in target schema (dropped): %v\n", rep.DroppedColumns)
+	}
+	if rep.SecretsNotRewrapped > 0 {
+		fmt.Printf("Warning: %d secrets could not be decrypted with the source key and were copied unchanged\n", rep.SecretsNotRewrapped)
+	}
+}
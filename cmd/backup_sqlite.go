@@ -0,0 +1,23 @@
+//go:build sqlite || sqliteonly
+
+package cmd
+
+import (
+	"database/sql"
+
+	"github.com/nextlevelbuilder/goclaw/internal/store/sqlitestore"
+)
+
+// openSQLiteForBackup opens (and creates or upgrades the schema of) a SQLite
+// database for backup, restore and migrate-backend.
+func openSQLiteForBackup(path string) (*sql.DB, error) {
+	db, err := sqlitestore.OpenDB(path)
+	if err != nil {
+		return nil, err
+	}
+	if err := sqlitestore.EnsureSchema(db); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return db, nil
+}
@@ -0,0 +1,12 @@
+//go:build !sqlite && !sqliteonly
+
+package cmd
+
+import (
+	"database/sql"
+	"errors"
+)
+
+func openSQLiteForBackup(string) (*sql.DB, error) {
+	return nil, errors.New("this binary was built without SQLite support (build with -tags sqlite)")
+}
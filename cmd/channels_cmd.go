@@ -45,6 +45,7 @@ func channelsListCmd() *cobra.Command {
 				{"zalo", cfg.Channels.Zalo.Enabled, cfg.Channels.Zalo.Token != ""},
 				{"feishu", cfg.Channels.Feishu.Enabled, cfg.Channels.Feishu.AppID != ""},
 				{"whatsapp", cfg.Channels.WhatsApp.Enabled, cfg.Channels.WhatsApp.BridgeURL != ""},
+				{"email", cfg.Channels.Email.Enabled, cfg.Channels.Email.Address != ""},
 			}
 
 			if jsonOutput {
@@ -121,6 +121,7 @@ func runDoctor() {
 		checkChannel("Zalo", cfg.Channels.Zalo.Enabled, cfg.Channels.Zalo.Token != "")
 		checkChannel("Feishu", cfg.Channels.Feishu.Enabled, cfg.Channels.Feishu.AppID != "")
 		checkChannel("WhatsApp", cfg.Channels.WhatsApp.Enabled, cfg.Channels.WhatsApp.BridgeURL != "")
+		checkChannel("Email", cfg.Channels.Email.Enabled, cfg.Channels.Email.Address != "")
 	}
 
 	// External tools
@@ -0,0 +1,314 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/nextlevelbuilder/goclaw/internal/store"
+)
+
+func evalCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "eval",
+		Short: "Run regression eval suites against agents",
+		Long: `Eval suites are stored conversations (user turns, expected tool calls and
+output assertions) replayed against an agent through the running gateway.
+Tools are stubbed by default; results, cost and latency are recorded per case.`,
+	}
+	cmd.AddCommand(evalListCmd())
+	cmd.AddCommand(evalPushCmd())
+	cmd.AddCommand(evalImportCmd())
+	cmd.AddCommand(evalRunCmd())
+	cmd.AddCommand(evalRunsCmd())
+	return cmd
+}
+
+func evalPath(agent, rest string) string {
+	return "/v1/agents/" + url.PathEscape(agent) + "/evals/" + rest
+}
+
+func evalListCmd() *cobra.Command {
+	var agent string
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List an agent's eval suites",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			suites, err := evalSuites(agent)
+			if err != nil {
+				return err
+			}
+			if len(suites) == 0 {
+				fmt.Println("No eval suites.")
+				return nil
+			}
+			tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(tw, "NAME\tCASES\tUPDATED\tID")
+			for _, s := range suites {
+				fmt.Fprintf(tw, "%s\t%d\t%s\t%s\n", s.Name, len(s.Cases), s.UpdatedAt.Local().Format("2006-01-02 15:04"), s.ID)
+			}
+			return tw.Flush()
+		},
+	}
+	cmd.Flags().StringVar(&agent, "agent", "", "agent key or ID (required)")
+	_ = cmd.MarkFlagRequired("agent")
+	return cmd
+}
+
+func evalPushCmd() *cobra.Command {
+	var agent string
+	cmd := &cobra.Command{
+		Use:   "push <suite.json>",
+		Short: "Create or replace a suite from a JSON file",
+		Long:  "Uploads a suite definition. A suite with the same name is replaced.",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			data, err := os.ReadFile(args[0])
+			if err != nil {
+				return err
+			}
+			var suite store.EvalSuite
+			if err := json.Unmarshal(data, &suite); err != nil {
+				return fmt.Errorf("parse %s: %w", args[0], err)
+			}
+			existing, err := evalSuites(agent)
+			if err != nil {
+				return err
+			}
+			var saved store.EvalSuite
+			for _, s := range existing {
+				if s.Name == suite.Name {
+					if err := gatewayJSON("PUT", evalPath(agent, "suites/"+s.ID.String()), suite, &saved); err != nil {
+						return err
+					}
+					fmt.Printf("Updated suite %q (%d cases)\n", saved.Name, len(saved.Cases))
+					return nil
+				}
+			}
+			if err := gatewayJSON("POST", evalPath(agent, "suites"), suite, &saved); err != nil {
+				return err
+			}
+			fmt.Printf("Created suite %q (%d cases), id %s\n", saved.Name, len(saved.Cases), saved.ID)
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&agent, "agent", "", "agent key or ID (required)")
+	_ = cmd.MarkFlagRequired("agent")
+	return cmd
+}
+
+func evalImportCmd() *cobra.Command {
+	var agent, session, suite, caseName string
+	cmd := &cobra.Command{
+		Use:   "import",
+		Short: "Turn a stored session into a regression case",
+		Long: `Imports a session's history as one case: each user message becomes a turn
+that expects the tools called for it and is judged against the original reply.
+Tool results are recorded as stubs. Adds the case to --suite when it exists,
+otherwise creates a new suite with that name.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			body := map[string]string{"session_key": session, "case_name": caseName}
+			if suite != "" {
+				if s, err := findEvalSuite(agent, suite); err == nil {
+					body["suite_id"] = s.ID.String()
+				} else {
+					body["name"] = suite
+				}
+			}
+			var saved store.EvalSuite
+			if err := gatewayJSON("POST", evalPath(agent, "suites/import"), body, &saved); err != nil {
+				return err
+			}
+			fmt.Printf("Suite %q now has %d cases (id %s)\n", saved.Name, len(saved.Cases), saved.ID)
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&agent, "agent", "", "agent key or ID (required)")
+	cmd.Flags().StringVar(&session, "session", "", "session key to import (required)")
+	cmd.Flags().StringVar(&suite, "suite", "", "suite name or ID to add the case to (default: new suite)")
+	cmd.Flags().StringVar(&caseName, "case", "", "case name (default: the session key)")
+	_ = cmd.MarkFlagRequired("agent")
+	_ = cmd.MarkFlagRequired("session")
+	return cmd
+}
+
+func evalRunCmd() *cobra.Command {
+	var agent string
+	var live, jsonOutput bool
+	var timeout time.Duration
+	cmd := &cobra.Command{
+		Use:   "run <suite>",
+		Short: "Run a suite and report pass/fail per case",
+		Long:  "Runs a suite (name or ID) and waits for it to finish. Exits non-zero when any case fails.",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			suite, err := findEvalSuite(agent, args[0])
+			if err != nil {
+				return err
+			}
+			mode := store.EvalToolsStub
+			if live {
+				mode = store.EvalToolsLive
+			}
+			var run store.EvalRun
+			if err := gatewayJSON("POST", evalPath(agent, "suites/"+suite.ID.String()+"/runs"), map[string]string{"tool_mode": mode}, &run); err != nil {
+				return err
+			}
+			if !jsonOutput {
+				fmt.Printf("Running %q (%d cases, tools: %s)...\n", suite.Name, run.Total, mode)
+			}
+
+			deadline := time.Now().Add(timeout)
+			for run.Status == store.EvalRunRunning {
+				if time.Now().After(deadline) {
+					return fmt.Errorf("run %s still running after %s", run.ID, timeout)
+				}
+				time.Sleep(2 * time.Second)
+				if err := gatewayJSON("GET", evalPath(agent, "runs/"+run.ID.String()), nil, &run); err != nil {
+					return err
+				}
+			}
+
+			if jsonOutput {
+				out, _ := json.MarshalIndent(run, "", "  ")
+				fmt.Println(string(out))
+			} else {
+				printEvalRun(&run)
+			}
+			if run.Status == store.EvalRunFailed {
+				return fmt.Errorf("run failed: %s", run.Error)
+			}
+			if run.Failed > 0 {
+				os.Exit(1)
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&agent, "agent", "", "agent key or ID (required)")
+	cmd.Flags().BoolVar(&live, "live", false, "run tools without a stub for real instead of returning a placeholder")
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "print the full run as JSON")
+	cmd.Flags().DurationVar(&timeout, "timeout", 30*time.Minute, "give up waiting after this long")
+	_ = cmd.MarkFlagRequired("agent")
+	return cmd
+}
+
+func evalRunsCmd() *cobra.Command {
+	var agent, suite string
+	var limit int
+	cmd := &cobra.Command{
+		Use:   "runs",
+		Short: "List recent eval runs",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			q := url.Values{}
+			if suite != "" {
+				s, err := findEvalSuite(agent, suite)
+				if err != nil {
+					return err
+				}
+				q.Set("suite_id", s.ID.String())
+			}
+			if limit > 0 {
+				q.Set("limit", fmt.Sprint(limit))
+			}
+			var resp struct {
+				Runs []store.EvalRun `json:"runs"`
+			}
+			if err := gatewayJSON("GET", evalPath(agent, "runs?"+q.Encode()), nil, &resp); err != nil {
+				return err
+			}
+			if len(resp.Runs) == 0 {
+				fmt.Println("No eval runs.")
+				return nil
+			}
+			tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(tw, "STARTED\tSUITE\tSTATUS\tPASSED\tCOST\tDURATION\tID")
+			for _, r := range resp.Runs {
+				fmt.Fprintf(tw, "%s\t%s\t%s\t%d/%d\t$%.4f\t%s\t%s\n", r.StartedAt.Local().Format("2006-01-02 15:04"), r.SuiteName,
+					r.Status, r.Passed, r.Total, r.CostUSD, time.Duration(r.DurationMS)*time.Millisecond, r.ID)
+			}
+			return tw.Flush()
+		},
+	}
+	cmd.Flags().StringVar(&agent, "agent", "", "agent key or ID (required)")
+	cmd.Flags().StringVar(&suite, "suite", "", "only runs of this suite (name or ID)")
+	cmd.Flags().IntVar(&limit, "limit", 20, "maximum runs to list")
+	_ = cmd.MarkFlagRequired("agent")
+	return cmd
+}
+
+func evalSuites(agent string) ([]store.EvalSuite, error) {
+	var resp struct {
+		Suites []store.EvalSuite `json:"suites"`
+	}
+	err := gatewayJSON("GET", evalPath(agent, "suites"), nil, &resp)
+	return resp.Suites, err
+}
+
+// findEvalSuite resolves a suite by ID or name.
+func findEvalSuite(agent, ref string) (*store.EvalSuite, error) {
+	suites, err := evalSuites(agent)
+	if err != nil {
+		return nil, err
+	}
+	for i, s := range suites {
+		if s.ID.String() == ref || s.Name == ref {
+			return &suites[i], nil
+		}
+	}
+	return nil, fmt.Errorf("suite %q not found for agent %s", ref, agent)
+}
+
+func printEvalRun(run *store.EvalRun) {
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "CASE\tRESULT\tTOKENS\tCOST\tDURATION")
+	for _, c := range run.Results {
+		result := "PASS"
+		if !c.Passed {
+			result = "FAIL"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%d/%d\t$%.4f\t%s\n", c.Name, result, c.InputTokens, c.OutputTokens, c.CostUSD,
+			time.Duration(c.DurationMS)*time.Millisecond)
+	}
+	tw.Flush()
+
+	for _, c := range run.Results {
+		if c.Passed {
+			continue
+		}
+		fmt.Printf("\n%s:\n", c.Name)
+		if c.Error != "" {
+			fmt.Printf("  error: %s\n", c.Error)
+		}
+		for i, t := range c.Turns {
+			for _, ch := range t.Checks {
+				if !ch.Passed {
+					fmt.Printf("  turn %d %s: %s\n", i+1, ch.Kind, ch.Detail)
+				}
+			}
+			if t.TraceID != nil && !allChecksPassed(t.Checks) {
+				fmt.Printf("  turn %d trace: %s\n", i+1, t.TraceID)
+			}
+		}
+	}
+
+	fmt.Printf("\n%d/%d passed, %d+%d tokens, $%.4f, %s\n", run.Passed, run.Total, run.InputTokens, run.OutputTokens,
+		run.CostUSD, time.Duration(run.DurationMS)*time.Millisecond)
+	if run.Error != "" {
+		fmt.Println("Error: " + strings.TrimSpace(run.Error))
+	}
+}
+
+func allChecksPassed(checks []store.EvalCheck) bool {
+	for _, ch := range checks {
+		if !ch.Passed {
+			return false
+		}
+	}
+	return true
+}
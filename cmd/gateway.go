@@ -18,21 +18,28 @@ import (
 	"github.com/nextlevelbuilder/goclaw/internal/bus"
 	"github.com/nextlevelbuilder/goclaw/internal/cache"
 	"github.com/nextlevelbuilder/goclaw/internal/channels"
+	"github.com/nextlevelbuilder/goclaw/internal/cluster"
 	"github.com/nextlevelbuilder/goclaw/internal/channels/discord"
+	"github.com/nextlevelbuilder/goclaw/internal/channels/email"
 	"github.com/nextlevelbuilder/goclaw/internal/channels/feishu"
 	slackchannel "github.com/nextlevelbuilder/goclaw/internal/channels/slack"
 	"github.com/nextlevelbuilder/goclaw/internal/channels/telegram"
+	webhookchannel "github.com/nextlevelbuilder/goclaw/internal/channels/webhook"
 	"github.com/nextlevelbuilder/goclaw/internal/channels/whatsapp"
 	"github.com/nextlevelbuilder/goclaw/internal/channels/zalo"
 	zalopersonal "github.com/nextlevelbuilder/goclaw/internal/channels/zalo/personal"
 	"github.com/nextlevelbuilder/goclaw/internal/config"
+	"github.com/nextlevelbuilder/goclaw/internal/docextract"
 	"github.com/nextlevelbuilder/goclaw/internal/edition"
+	"github.com/nextlevelbuilder/goclaw/internal/eval"
 	"github.com/nextlevelbuilder/goclaw/internal/gateway"
 	"github.com/nextlevelbuilder/goclaw/internal/heartbeat"
 	"github.com/nextlevelbuilder/goclaw/internal/gateway/methods"
 	httpapi "github.com/nextlevelbuilder/goclaw/internal/http"
 	mcpbridge "github.com/nextlevelbuilder/goclaw/internal/mcp"
 	"github.com/nextlevelbuilder/goclaw/internal/media"
+	"github.com/nextlevelbuilder/goclaw/internal/memory"
+	"github.com/nextlevelbuilder/goclaw/internal/metrics"
 	"github.com/nextlevelbuilder/goclaw/internal/providers"
 	"github.com/nextlevelbuilder/goclaw/internal/scheduler"
 	"github.com/nextlevelbuilder/goclaw/internal/skills"
@@ -124,6 +131,10 @@ func runGateway() {
 		defer mcpMgr.Stop()
 	}
 
+	// Tool approvals: human sign-off for tool calls matching tools.approvals rules.
+	toolApprovalMgr := tools.NewToolApprovalManager(cfg.Tools.Approvals, cfg.Gateway.OwnerIDs, msgBus)
+	toolsReg.SetApprovals(toolApprovalMgr)
+
 	pgStores, traceCollector, snapshotWorker := setupStoresAndTracing(cfg, dataDir, msgBus)
 	if traceCollector != nil {
 		defer traceCollector.Stop()
@@ -138,6 +149,9 @@ func runGateway() {
 	redisClient := initRedisClient(cfg)
 	defer shutdownRedis(redisClient)
 
+	// Cluster mode: leader election, channel leases and cross-replica event fan-out.
+	clusterNode := setupCluster(cfg, redisClient, msgBus)
+
 	// Register providers from DB (overrides config providers).
 	if pgStores.Providers != nil {
 		dbGatewayAddr := loopbackAddr(cfg.Gateway.Host, cfg.Gateway.Port)
@@ -253,6 +267,9 @@ func runGateway() {
 	toolsReg.Register(tools.NewMessageTool(workspace, agentCfg.RestrictToWorkspace))
 	// Group members tool (list members in group chats)
 	toolsReg.Register(tools.NewListGroupMembersTool())
+	// Ask user tool (interactive questions answered from the chat)
+	askUserMgr := tools.NewAskUserManager()
+	toolsReg.Register(tools.NewAskUserTool(askUserMgr))
 	slog.Info("session + message tools registered")
 
 	// Register legacy tool aliases (backward-compat names from policy.go).
@@ -313,10 +330,12 @@ func runGateway() {
 			}
 		}
 	}
-	// Wire BusAware on message tool
-	if t, ok := toolsReg.Get("message"); ok {
-		if ba, ok := t.(tools.BusAware); ok {
-			ba.SetMessageBus(msgBus)
+	// Wire BusAware on message + ask_user tools
+	for _, name := range []string{"message", "ask_user"} {
+		if t, ok := toolsReg.Get(name); ok {
+			if ba, ok := t.(tools.BusAware); ok {
+				ba.SetMessageBus(msgBus)
+			}
 		}
 	}
 
@@ -343,13 +362,23 @@ func runGateway() {
 		server.SetAgentStore(pgStores.Agents)
 	}
 
+	// Spend budgets: agents.budget_monthly_cents is always enforced;
+	// gateway.budget adds tenant/user limits and soft/hard thresholds.
+	budgetChecker := newBudgetChecker(cfg, pgStores.Tracing, msgBus)
+
+	// Speech-to-text for inbound voice notes (all channels). Always non-nil so
+	// config reload can add providers later.
+	sttMgr := setupSTT(cfg)
+
 	var mcpPool *mcpbridge.Pool
 	var mediaStore *media.Store
 	var postTurn tools.PostTurnProcessor
-	contextFileInterceptor, mcpPool, mediaStore, postTurn = wireExtras(pgStores, agentRouter, providerRegistry, msgBus, pgStores.Sessions, toolsReg, toolPE, skillsLoader, hasMemory, traceCollector, workspace, cfg.Gateway.InjectionAction, cfg, sandboxMgr, redisClient)
+	contextFileInterceptor, mcpPool, mediaStore, postTurn = wireExtras(pgStores, agentRouter, providerRegistry, msgBus, pgStores.Sessions, toolsReg, toolPE, skillsLoader, hasMemory, traceCollector, workspace, cfg.Gateway.InjectionAction, cfg, sandboxMgr, redisClient, budgetChecker, sttMgr)
 	if mcpPool != nil {
 		defer mcpPool.Stop()
 	}
+	docExtractor := docextract.New(documentCacheDir(dataDir))
+	wireDocumentExtractor(docExtractor, toolsReg)
 	gatewayAddr := loopbackAddr(cfg.Gateway.Host, cfg.Gateway.Port)
 	var mcpToolLister httpapi.MCPToolLister
 	if mcpMgr != nil {
@@ -384,6 +413,9 @@ func runGateway() {
 	if mcpUserCredsH != nil {
 		server.SetMCPUserCredentialsHandler(mcpUserCredsH)
 	}
+	if mcpPool != nil && mcpPool.OAuth() != nil {
+		server.SetMCPOAuthHandler(httpapi.NewMCPOAuthHandler(pgStores.MCP, mcpPool.OAuth()))
+	}
 	if channelInstancesH != nil {
 		server.SetChannelInstancesHandler(channelInstancesH)
 	}
@@ -425,6 +457,16 @@ func runGateway() {
 		server.SetActivityHandler(httpapi.NewActivityHandler(pgStores.Activity))
 	}
 
+	// Durable message queue dead letters API
+	if messageQueueEnabled(cfg, pgStores) {
+		server.SetMessageQueueHandler(httpapi.NewMessageQueueHandler(pgStores.MessageQueue))
+	}
+
+	// Tenant / instance backup download (restore is offline: goclaw restore)
+	if pgStores.DB != nil {
+		server.SetBackupHandler(httpapi.NewBackupHandler(pgStores.DB, dataDir, workspace, backupExcludes(cfg, dataDir), Version))
+	}
+
 	// System configs API
 	if pgStores.SystemConfigs != nil {
 		server.SetSystemConfigsHandler(httpapi.NewSystemConfigsHandler(pgStores.SystemConfigs, msgBus))
@@ -453,7 +495,9 @@ func runGateway() {
 
 	// Usage analytics API
 	if pgStores.Snapshots != nil {
-		server.SetUsageHandler(httpapi.NewUsageHandler(pgStores.Snapshots, pgStores.DB))
+		usageHandler := httpapi.NewUsageHandler(pgStores.Snapshots, pgStores.DB)
+		usageHandler.SetBudget(budgetChecker, pgStores.Agents)
+		server.SetUsageHandler(usageHandler)
 	}
 
 	// Runtime package management (install/uninstall system/pip/npm packages)
@@ -466,6 +510,13 @@ func runGateway() {
 	// Edition info (public, no auth — used by desktop UI comparison modal)
 	server.SetEditionHandler(httpapi.NewEditionHandler())
 
+	// Prometheus metrics (opt-in; admin auth or dedicated scrape token)
+	if cfg.Telemetry.Metrics.Enabled {
+		server.SetMetricsHandler(httpapi.NewMetricsHandler(metrics.Default, cfg.Telemetry.Metrics.Token))
+		registerRuntimeGauges(msgBus, server)
+		slog.Info("metrics endpoint enabled", "path", "/metrics")
+	}
+
 	if pgStores != nil && pgStores.APIKeys != nil {
 		server.SetAPIKeysHandler(httpapi.NewAPIKeysHandler(pgStores.APIKeys, msgBus))
 		server.SetAPIKeyStore(pgStores.APIKeys)
@@ -479,7 +530,12 @@ func runGateway() {
 
 	// Memory management API (wired directly, only needs MemoryStore + token)
 	if pgStores != nil && pgStores.Memory != nil {
-		server.SetMemoryHandler(httpapi.NewMemoryHandler(pgStores.Memory))
+		memoryH := httpapi.NewMemoryHandler(pgStores.Memory)
+		memoryH.SetDocumentExtractor(docExtractor)
+		retriever := memory.NewRetriever(pgStores.Memory, providerRegistry)
+		retriever.SetURLCheck(tools.CheckSSRF)
+		memoryH.SetRetriever(retriever, pgStores.Agents)
+		server.SetMemoryHandler(memoryH)
 	}
 
 	// Knowledge graph API
@@ -487,6 +543,12 @@ func runGateway() {
 		server.SetKnowledgeGraphHandler(httpapi.NewKnowledgeGraphHandler(pgStores.KnowledgeGraph, providerRegistry))
 	}
 
+	// Agent eval suites (regression tests replayed through the agent loop)
+	if pgStores != nil && pgStores.Evals != nil {
+		evalRunner := eval.NewRunner(agentRouter, pgStores.Evals, pgStores.Sessions)
+		server.SetEvalHandler(httpapi.NewEvalHandler(pgStores.Evals, pgStores.Agents, pgStores.Sessions, evalRunner, permPE.IsOwner))
+	}
+
 	// Workspace file serving endpoint — serves files by absolute path, auth-token protected.
 	// Supports media from any agent workspace (each agent has its own workspace from DB).
 	server.SetFilesHandler(httpapi.NewFilesHandler(workspace, dataDir))
@@ -514,7 +576,7 @@ func runGateway() {
 
 	// Register all RPC methods
 	server.SetLogTee(logTee)
-	pairingMethods, heartbeatMethods, chatMethods := registerAllMethods(server, agentRouter, pgStores.Sessions, pgStores.Cron, pgStores.Pairing, cfg, cfgPath, workspace, dataDir, msgBus, execApprovalMgr, pgStores.Agents, pgStores.Skills, pgStores.ConfigSecrets, pgStores.Teams, contextFileInterceptor, logTee, pgStores.Heartbeats, pgStores.ConfigPermissions, pgStores.SystemConfigs, pgStores.Tenants, pgStores.SkillTenantCfgs)
+	pairingMethods, heartbeatMethods, chatMethods := registerAllMethods(server, agentRouter, pgStores.Sessions, pgStores.Cron, pgStores.Pairing, cfg, cfgPath, workspace, dataDir, msgBus, execApprovalMgr, pgStores.Agents, pgStores.Skills, pgStores.ConfigSecrets, pgStores.Teams, contextFileInterceptor, logTee, pgStores.Heartbeats, pgStores.ConfigPermissions, pgStores.SystemConfigs, pgStores.Tenants, pgStores.SkillTenantCfgs, pgStores.ContextFileRevisions, pgStores.Providers)
 
 	// Wire post-turn processor for team task dispatch (WS chat.send + HTTP API paths).
 	if postTurn != nil {
@@ -523,15 +585,18 @@ func runGateway() {
 		wakeH.SetPostTurnProcessor(postTurn)  // HTTP: /v1/agents/{id}/wake
 	}
 
+	// WS broadcast for frames that bypass the bus; reaches every replica in cluster mode.
+	broadcastFrame := clusterFrameBroadcaster(clusterNode, server.BroadcastEvent)
+
 	// Wire pairing event broadcasts to all WS clients.
-	pairingMethods.SetBroadcaster(server.BroadcastEvent)
+	pairingMethods.SetBroadcaster(broadcastFrame)
 	// Wire pairing request callback — works for both PG and SQLite stores.
 	type pairingRequestNotifier interface {
 		SetOnRequest(func(code, senderID, channel, chatID string))
 	}
 	if ps, ok := pgStores.Pairing.(pairingRequestNotifier); ok {
 		ps.SetOnRequest(func(code, senderID, channel, chatID string) {
-			server.BroadcastEvent(*protocol.NewEvent(protocol.EventDevicePairReq, map[string]any{
+			broadcastFrame(*protocol.NewEvent(protocol.EventDevicePairReq, map[string]any{
 				"code": code, "sender_id": senderID, "channel": channel, "chat_id": chatID,
 			}))
 		})
@@ -539,6 +604,11 @@ func runGateway() {
 
 	// Channel manager
 	channelMgr := channels.NewManager(msgBus)
+	if clusterNode != nil {
+		channelMgr.SetOwnership(cluster.NewChannelOwnership(clusterNode, msgBus))
+	}
+	// Durable message queue: journal bus traffic before any channel publishes.
+	msgQueue := setupMessageQueue(cfg, pgStores, msgBus, channelMgr, clusterNode)
 
 	// Wire channel sender + tenant checker on message tool (now that channelMgr exists)
 	if t, ok := toolsReg.Get("message"); ok {
@@ -555,6 +625,8 @@ func runGateway() {
 			gl.SetGroupMemberLister(channelMgr.ListGroupMembers)
 		}
 	}
+	wireToolApprovals(toolApprovalMgr, channelMgr, server, clusterNode)
+	askUserReplies := wireAskUser(askUserMgr, channelMgr, clusterNode)
 
 	// Load channel instances from DB.
 	var instanceLoader *channels.InstanceLoader
@@ -569,6 +641,8 @@ func runGateway() {
 		instanceLoader.RegisterFactory(channels.TypeZaloPersonal, zalopersonal.FactoryWithPendingStore(pgStores.PendingMessages))
 		instanceLoader.RegisterFactory(channels.TypeWhatsApp, whatsapp.Factory)
 		instanceLoader.RegisterFactory(channels.TypeSlack, slackchannel.FactoryWithPendingStore(pgStores.PendingMessages))
+		instanceLoader.RegisterFactory(channels.TypeWebhook, webhookchannel.Factory)
+		instanceLoader.RegisterFactory(channels.TypeEmail, email.Factory)
 		if err := instanceLoader.LoadAll(context.Background()); err != nil {
 			slog.Error("failed to load channel instances from DB", "error", err)
 		}
@@ -670,6 +744,30 @@ func runGateway() {
 		slog.Info("team task event subscriber registered")
 	}
 
+	// Team task dispatch subscriber — starts tasks unblocked by review or
+	// cancellation. Member completions dispatch from the post-turn hook, but
+	// approvals, rejections and cancellations (dashboard or lead) have no
+	// member turn to hook into, so their dependents would otherwise sit pending.
+	if pgStores.Teams != nil && postTurn != nil {
+		msgBus.Subscribe(bus.TopicTeamTaskDispatch, func(evt bus.Event) {
+			switch evt.Name {
+			case protocol.EventTeamTaskApproved, protocol.EventTeamTaskRejected, protocol.EventTeamTaskCancelled:
+			default:
+				return
+			}
+			payload, ok := evt.Payload.(protocol.TeamTaskEventPayload)
+			if !ok {
+				return
+			}
+			teamID, err := uuid.Parse(payload.TeamID)
+			if err != nil {
+				return
+			}
+			// Broadcast runs subscribers inline; dispatch in the background.
+			go postTurn.DispatchUnblockedTasks(store.WithTenantID(context.Background(), evt.TenantID), teamID)
+		})
+	}
+
 	// Team progress notification subscriber — forwards task events to chat channels.
 	// Reads team.settings.notifications config; direct mode sends outbound, leader mode
 	// injects into leader agent session. Notifications are batched per chat
@@ -873,18 +971,23 @@ func runGateway() {
 	sched := scheduler.NewScheduler(
 		scheduler.DefaultLanes(),
 		scheduler.DefaultQueueConfig(),
-		makeSchedulerRunFunc(agentRouter, cfg),
+		withSessionAffinity(clusterNode, makeSchedulerRunFunc(agentRouter, cfg)),
 	)
 	defer sched.Stop()
+	if cfg.Telemetry.Metrics.Enabled {
+		registerLaneGauges(sched)
+	}
 
 	// Start cron service with job handler (routes through scheduler's cron lane)
 	pgStores.Cron.SetOnJob(makeCronJobHandler(sched, msgBus, cfg, channelMgr, pgStores.Sessions, pgStores.Agents))
 	pgStores.Cron.SetOnEvent(func(event store.CronEvent) {
-		server.BroadcastEvent(*protocol.NewEvent(protocol.EventCron, event))
+		broadcastFrame(*protocol.NewEvent(protocol.EventCron, event))
 	})
-	if err := pgStores.Cron.Start(); err != nil {
-		slog.Warn("cron service failed to start", "error", err)
-	}
+	runSingleton(clusterNode, singletonCron, func() {
+		if err := pgStores.Cron.Start(); err != nil {
+			slog.Warn("cron service failed to start", "error", err)
+		}
+	}, pgStores.Cron.Stop)
 
 	// Start heartbeat ticker (routes through scheduler's cron lane)
 	heartbeatTicker := heartbeat.NewTicker(heartbeat.TickerConfig{
@@ -898,18 +1001,19 @@ func runGateway() {
 		RunAgent:      makeHeartbeatRunFn(sched),
 	})
 	heartbeatTicker.SetOnEvent(func(event store.HeartbeatEvent) {
-		server.BroadcastEvent(*protocol.NewEvent(protocol.EventHeartbeat, event))
+		broadcastFrame(*protocol.NewEvent(protocol.EventHeartbeat, event))
 	})
-	heartbeatTicker.Start()
+	runSingleton(clusterNode, singletonHeartbeat, heartbeatTicker.Start, heartbeatTicker.Stop)
 
 	// Wire heartbeat wake function to tool + RPC + cron wakeMode
-	heartbeatTool.SetWakeFn(heartbeatTicker.Wake)
-	heartbeatMethods.SetWakeFn(heartbeatTicker.Wake)
+	heartbeatWake := clusterHeartbeatWake(clusterNode, heartbeatTicker.Wake)
+	heartbeatTool.SetWakeFn(heartbeatWake)
+	heartbeatMethods.SetWakeFn(heartbeatWake)
 	heartbeatMethods.SetAgentStore(pgStores.Agents)
 	heartbeatMethods.SetProviderStore(pgStores.Providers)
 	cronHeartbeatWakeFn = func(agentID string) {
 		if id, err := uuid.Parse(agentID); err == nil {
-			heartbeatTicker.Wake(id)
+			heartbeatWake(id)
 		}
 	}
 
@@ -987,9 +1091,23 @@ func runGateway() {
 		)
 	}
 
+	// Agents as an MCP server for external clients (IDEs, other agent frameworks).
+	// Registered here because calls run through the scheduler and quota checker.
+	if pgStores.Agents != nil {
+		agentMCPH := httpapi.NewAgentMCPHandler(pgStores.Agents, agentRouter, pgStores.Sessions, sched, msgBus, permPE.IsOwner, Version)
+		if pgStores.Memory != nil {
+			agentMCPH.SetMemoryStore(pgStores.Memory)
+		}
+		if quotaChecker != nil {
+			agentMCPH.SetQuotaChecker(quotaChecker)
+		}
+		server.SetAgentMCPHandler(agentMCPH)
+	}
+
 	// Register quota usage RPC.
 	// Pass DB so summary cards still work when quota is disabled (queries traces directly).
 	methods.NewQuotaMethods(quotaChecker, pgStores.DB).Register(server.Router())
+	methods.NewUsageBudgetMethods(budgetChecker, pgStores.Agents).Register(server.Router())
 
 	// API key management RPC
 	if pgStores.APIKeys != nil {
@@ -1029,6 +1147,9 @@ func runGateway() {
 		})
 	}
 
+	// Reload budget config and forward threshold alerts to admin chats.
+	wireBudgetSubscribers(msgBus, budgetChecker)
+
 	// Reload cron default timezone on config changes via pub/sub.
 	msgBus.Subscribe("cron-config-reload", func(evt bus.Event) {
 		if evt.Name != bus.TopicConfigChanged {
@@ -1053,6 +1174,18 @@ func runGateway() {
 		webFetchTool.UpdatePolicy(updatedCfg.Tools.WebFetch.Policy, updatedCfg.Tools.WebFetch.AllowedDomains, updatedCfg.Tools.WebFetch.BlockedDomains)
 	})
 
+	// Reload tool approval rules on config changes via pub/sub.
+	msgBus.Subscribe("tool-approval-config-reload", func(evt bus.Event) {
+		if evt.Name != bus.TopicConfigChanged {
+			return
+		}
+		updatedCfg, ok := evt.Payload.(*config.Config)
+		if !ok {
+			return
+		}
+		toolApprovalMgr.SetConfig(updatedCfg.Tools.Approvals)
+	})
+
 	// Reload TTS providers on config changes via pub/sub.
 	msgBus.Subscribe("tts-config-reload", func(evt bus.Event) {
 		if evt.Name != bus.TopicConfigChanged {
@@ -1071,10 +1204,24 @@ func runGateway() {
 		if newMgr == nil {
 			return
 		}
+		newMgr.InheritScopes(ttsTool.Manager())
 		ttsTool.UpdateManager(newMgr)
 		slog.Info("tts config reloaded", "provider", newMgr.PrimaryProvider(), "auto", string(newMgr.AutoMode()))
 	})
 
+	// Browser operator takeover (browser.* RPC) — shares the agent's per-tenant page set.
+	methods.NewBrowserMethods(browserMgr, msgBus).Register(server.Router())
+
+	// TTS runtime control (tts.* RPC): tenant/agent overrides persisted in system_configs.
+	methods.NewTTSMethods(ttsTool, pgStores.SystemConfigs, pgStores.Agents, mediaStore, msgBus).Register(server.Router())
+	loadTTSScopes(pgStores.SystemConfigs, pgStores.Tenants, ttsTool.Manager())
+	subscribeTTSScopeReload(msgBus, pgStores.SystemConfigs, ttsTool)
+
+	// STT: tenant overrides (stt.provider / stt.language) from system_configs,
+	// provider reload on config changes.
+	loadSTTScopes(pgStores.SystemConfigs, pgStores.Tenants, sttMgr)
+	subscribeSTTReload(msgBus, pgStores.SystemConfigs, pgStores.ConfigSecrets, sttMgr)
+
 	// Log orphaned providers on agent deletion. Auto-delete is unsafe because
 	// providers can be referenced by heartbeats (FK), OAuth tokens, media chains.
 	// Users should clean up orphaned providers manually via UI/API.
@@ -1093,17 +1240,26 @@ func runGateway() {
 	// Contact collector: auto-collect user info from channels with in-memory dedup cache.
 	var contactCollector *store.ContactCollector
 	if pgStores.Contacts != nil {
-		contactCollector = store.NewContactCollector(pgStores.Contacts, cache.NewInMemoryCache[bool]())
+		contactCollector = store.NewContactCollector(pgStores.Contacts, cache.Instrumented[bool]("contacts", cache.NewInMemoryCache[bool]()))
 		channelMgr.SetContactCollector(contactCollector) // propagate to all channel handlers
 	}
 
-	go consumeInboundMessages(ctx, msgBus, agentRouter, cfg, sched, channelMgr, consumerTeamStore, quotaChecker, pgStores.Sessions, pgStores.Agents, contactCollector, postTurn, subagentMgr)
+	go consumeInboundMessages(ctx, msgBus, agentRouter, cfg, sched, channelMgr, consumerTeamStore, quotaChecker, pgStores.Sessions, pgStores.Agents, contactCollector, postTurn, subagentMgr, askUserReplies, ttsTool)
+
+	// Replay messages interrupted by the previous shutdown and retry failed sends.
+	if msgQueue != nil {
+		msgQueue.Start(ctx)
+	}
 
 	// Task recovery ticker: re-dispatches stale/pending team tasks on startup and periodically.
 	var taskTicker *tasks.TaskTicker
 	if pgStores.Teams != nil {
 		taskTicker = tasks.NewTaskTicker(pgStores.Teams, pgStores.Agents, msgBus, cfg.Gateway.TaskRecoveryIntervalSec)
-		taskTicker.Start()
+		if clusterNode != nil {
+			// Other replicas may be running in_progress tasks: only recover expired locks.
+			taskTicker.SetForceRecoverOnStart(false)
+		}
+		runSingleton(clusterNode, singletonTasks, taskTicker.Start, taskTicker.Stop)
 	}
 
 	go func() {
@@ -1115,10 +1271,18 @@ func runGateway() {
 
 		// Stop channels, cron, heartbeat, and task ticker
 		channelMgr.StopAll(context.Background())
-		pgStores.Cron.Stop()
-		heartbeatTicker.Stop()
+		stopSingleton(clusterNode, singletonCron, pgStores.Cron.Stop)
+		stopSingleton(clusterNode, singletonHeartbeat, heartbeatTicker.Stop)
 		if taskTicker != nil {
-			taskTicker.Stop()
+			stopSingleton(clusterNode, singletonTasks, taskTicker.Stop)
+		}
+		// Stop redelivery; messages still in memory are replayed on the next start.
+		if msgQueue != nil {
+			msgQueue.Stop()
+		}
+		// Leave the cluster: releases the remaining leases so other replicas take over.
+		if clusterNode != nil {
+			clusterNode.Stop()
 		}
 
 		// Drain audit log queue before closing DB
@@ -1164,6 +1328,9 @@ func runGateway() {
 		mux.Handle(route.Path, route.Handler)
 		slog.Info("webhook route mounted on gateway", "path", route.Path)
 	}
+	// Generic webhook channel instances share one route; the instance is resolved
+	// per request so instances added at runtime need no remount.
+	mux.Handle(webhookchannel.InboundPath, webhookchannel.InboundHandler(channelMgr))
 
 	tsCleanup := initTailscale(ctx, cfg, mux)
 	if tsCleanup != nil {
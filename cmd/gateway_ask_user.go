@@ -0,0 +1,197 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/nextlevelbuilder/goclaw/internal/bus"
+	"github.com/nextlevelbuilder/goclaw/internal/channels"
+	"github.com/nextlevelbuilder/goclaw/internal/cluster"
+	"github.com/nextlevelbuilder/goclaw/internal/tools"
+)
+
+// askUserReplies answers pending ask_user questions with chat messages.
+type askUserReplies interface {
+	AnswerText(channel, chatID, senderID, text string) bool
+}
+
+// wireAskUser routes answers given through channel buttons, menus and forms
+// to the waiting ask_user calls, and returns what the inbound consumer uses
+// for text replies (handleAskUserReply). In cluster mode, answers to
+// questions asked on another replica are forwarded to it.
+func wireAskUser(mgr *tools.AskUserManager, channelMgr *channels.Manager, node *cluster.Node) askUserReplies {
+	var forward *clusterAskUser
+	if node != nil {
+		forward = newClusterAskUser(mgr, node)
+	}
+
+	channelMgr.SetInteractiveResolver(func(ctx context.Context, channel string, answer bus.InteractiveAnswer) (string, error) {
+		summary, err := answerAskUser(mgr, channel, answer)
+		if errors.Is(err, tools.ErrAskUserNotFound) && forward != nil {
+			summary, err = forward.answer(ctx, channel, answer)
+		}
+		switch {
+		case errors.Is(err, tools.ErrAskUserNotFound):
+			return "", errors.New("This question was already answered or has expired.")
+		case errors.Is(err, tools.ErrAskUserForbidden):
+			return "", errors.New("Only the person who was asked can answer.")
+		case err != nil:
+			return "", err
+		}
+		return summary, nil
+	})
+
+	if forward != nil {
+		return forward
+	}
+	return mgr
+}
+
+// answerAskUser answers a question pending on this replica and describes the
+// answer for the chat.
+func answerAskUser(mgr *tools.AskUserManager, channel string, answer bus.InteractiveAnswer) (string, error) {
+	msg, err := mgr.Answer(channel, answer)
+	if err != nil {
+		return "", err
+	}
+	return channels.InteractiveAnswerSummary(msg, answer), nil
+}
+
+// clusterAskUser forwards ask_user answers to the replica whose tool call
+// asked the question. Replicas announce the chats they have questions pending
+// in, so text replies are only forwarded to chats where one is waiting.
+type clusterAskUser struct {
+	mgr  *tools.AskUserManager
+	node *cluster.Node
+
+	mu     sync.Mutex
+	remote map[string]tools.PendingQuestion // by question ID
+}
+
+type clusterAskUserAnswer struct {
+	Channel string                `json:"channel"`
+	Answer  bus.InteractiveAnswer `json:"answer"`
+}
+
+type clusterAskUserText struct {
+	Channel  string `json:"channel"`
+	ChatID   string `json:"chat_id"`
+	SenderID string `json:"sender_id"`
+	Text     string `json:"text"`
+}
+
+type clusterAskUserPending struct {
+	Question tools.PendingQuestion `json:"question"`
+	Waiting  bool                  `json:"waiting"`
+}
+
+func newClusterAskUser(mgr *tools.AskUserManager, node *cluster.Node) *clusterAskUser {
+	c := &clusterAskUser{mgr: mgr, node: node, remote: make(map[string]tools.PendingQuestion)}
+	mgr.SetOnChange(func(q tools.PendingQuestion, waiting bool) {
+		node.Send(clusterKindAskUserPending, clusterAskUserPending{Question: q, Waiting: waiting})
+	})
+	node.Handle(clusterKindAskUserPending, func(_ string, raw json.RawMessage) {
+		var p clusterAskUserPending
+		if err := json.Unmarshal(raw, &p); err != nil {
+			slog.Debug("cluster: malformed ask_user notice", "error", err)
+			return
+		}
+		c.mu.Lock()
+		if p.Waiting {
+			c.remote[p.Question.ID] = p.Question
+		} else {
+			delete(c.remote, p.Question.ID)
+		}
+		c.mu.Unlock()
+	})
+	node.HandleRequest(clusterKindAskUserAnswer, func(_ string, raw json.RawMessage) (any, bool) {
+		var a clusterAskUserAnswer
+		if err := json.Unmarshal(raw, &a); err != nil {
+			return nil, false
+		}
+		summary, err := answerAskUser(mgr, a.Channel, a.Answer)
+		switch {
+		case errors.Is(err, tools.ErrAskUserNotFound):
+			return nil, false // not asked here
+		case errors.Is(err, tools.ErrAskUserForbidden):
+			return clusterResult{Forbidden: true}, true
+		case err != nil:
+			return clusterResult{Error: err.Error()}, true
+		}
+		return clusterResult{Summary: summary}, true
+	})
+	node.HandleRequest(clusterKindAskUserText, func(_ string, raw json.RawMessage) (any, bool) {
+		var t clusterAskUserText
+		if err := json.Unmarshal(raw, &t); err != nil {
+			return nil, false
+		}
+		if !mgr.AnswerText(t.Channel, t.ChatID, t.SenderID, t.Text) {
+			return nil, false
+		}
+		return clusterResult{}, true
+	})
+	return c
+}
+
+// answer forwards a button, menu or form answer to the other replicas.
+func (c *clusterAskUser) answer(ctx context.Context, channel string, answer bus.InteractiveAnswer) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, clusterForwardTimeout)
+	defer cancel()
+	raw, err := c.node.Request(ctx, clusterKindAskUserAnswer, clusterAskUserAnswer{Channel: channel, Answer: answer})
+	if errors.Is(err, cluster.ErrNoReply) {
+		return "", tools.ErrAskUserNotFound
+	}
+	if err != nil {
+		return "", err
+	}
+	var res clusterResult
+	if err := json.Unmarshal(raw, &res); err != nil {
+		return "", err
+	}
+	switch {
+	case res.Forbidden:
+		return "", tools.ErrAskUserForbidden
+	case res.Error != "":
+		return "", errors.New(res.Error)
+	}
+	return res.Summary, nil
+}
+
+// AnswerText answers a question pending on this replica, or forwards the
+// reply when another replica has a question waiting in the chat.
+func (c *clusterAskUser) AnswerText(channel, chatID, senderID, text string) bool {
+	if c.mgr.AnswerText(channel, chatID, senderID, text) {
+		return true
+	}
+	if !c.waitingElsewhere(channel, chatID) {
+		return false
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), clusterForwardTimeout)
+	defer cancel()
+	_, err := c.node.Request(ctx, clusterKindAskUserText, clusterAskUserText{Channel: channel, ChatID: chatID, SenderID: senderID, Text: text})
+	return err == nil
+}
+
+// waitingElsewhere reports whether another replica announced a question in
+// the chat. Questions past their timeout are dropped, which also clears those
+// of a replica that died before announcing the end.
+func (c *clusterAskUser) waitingElsewhere(channel, chatID string) bool {
+	now := time.Now()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	found := false
+	for id, q := range c.remote {
+		if now.After(q.ExpiresAt) {
+			delete(c.remote, id)
+			continue
+		}
+		if q.Channel == channel && q.ChatID == chatID {
+			found = true
+		}
+	}
+	return found
+}
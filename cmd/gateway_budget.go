@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"fmt"
+	"log/slog"
+
+	"github.com/nextlevelbuilder/goclaw/internal/budget"
+	"github.com/nextlevelbuilder/goclaw/internal/bus"
+	"github.com/nextlevelbuilder/goclaw/internal/config"
+	"github.com/nextlevelbuilder/goclaw/internal/store"
+	"github.com/nextlevelbuilder/goclaw/pkg/protocol"
+)
+
+// newBudgetChecker creates the spend budget checker. It is always created so
+// per-agent budget_monthly_cents stays enforced when gateway.budget is unset.
+func newBudgetChecker(cfg *config.Config, tracingStore store.TracingStore, msgBus *bus.MessageBus) *budget.Checker {
+	var bcfg config.BudgetConfig
+	if cfg.Gateway.Budget != nil {
+		bcfg = *cfg.Gateway.Budget
+	}
+	if bcfg.Enabled {
+		slog.Info("spend budgets enabled",
+			"tenant_usd", bcfg.Tenant.MonthlyUSD,
+			"user_usd", bcfg.User.MonthlyUSD,
+			"agents", len(bcfg.Agents),
+			"notify_targets", len(bcfg.Notify))
+	}
+	return budget.NewChecker(tracingStore, bcfg, msgBus)
+}
+
+// wireBudgetSubscribers reloads budget config on config changes and forwards
+// budget.threshold events to the admin chats listed in gateway.budget.notify.
+func wireBudgetSubscribers(msgBus *bus.MessageBus, checker *budget.Checker) {
+	msgBus.Subscribe("budget-config-reload", func(evt bus.Event) {
+		if evt.Name != bus.TopicConfigChanged {
+			return
+		}
+		updatedCfg, ok := evt.Payload.(*config.Config)
+		if !ok {
+			return
+		}
+		var bcfg config.BudgetConfig
+		if updatedCfg.Gateway.Budget != nil {
+			bcfg = *updatedCfg.Gateway.Budget
+		}
+		checker.UpdateConfig(bcfg)
+		slog.Info("budget config reloaded via pub/sub")
+	})
+
+	msgBus.Subscribe("budget-threshold-notify", func(evt bus.Event) {
+		if evt.Name != protocol.EventBudgetThreshold {
+			return
+		}
+		p, ok := evt.Payload.(bus.BudgetThresholdPayload)
+		if !ok {
+			return
+		}
+		content := formatBudgetAlert(p)
+		for _, t := range checker.NotifyTargets() {
+			if t.Channel == "" || t.ChatID == "" {
+				continue
+			}
+			msgBus.PublishOutbound(bus.OutboundMessage{
+				Channel: t.Channel,
+				ChatID:  t.ChatID,
+				Content: content,
+			})
+		}
+	})
+}
+
+func formatBudgetAlert(p bus.BudgetThresholdPayload) string {
+	if p.Level == budget.LevelHard {
+		return fmt.Sprintf("🛑 Budget exceeded: %s %q spent $%.2f of $%.2f in %s — calls now %s.",
+			p.Scope, p.Key, p.SpentUSD, p.LimitUSD, p.Month, budgetActionVerb(p.Action))
+	}
+	return fmt.Sprintf("⚠️ Budget warning: %s %q spent $%.2f of $%.2f in %s.",
+		p.Scope, p.Key, p.SpentUSD, p.LimitUSD, p.Month)
+}
+
+func budgetActionVerb(action string) string {
+	if action == budget.ActionDowngrade {
+		return "downgraded to a cheaper model"
+	}
+	return "refused"
+}
@@ -0,0 +1,180 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/nextlevelbuilder/goclaw/internal/agent"
+	"github.com/nextlevelbuilder/goclaw/internal/bus"
+	"github.com/nextlevelbuilder/goclaw/internal/cluster"
+	"github.com/nextlevelbuilder/goclaw/internal/config"
+	"github.com/nextlevelbuilder/goclaw/internal/scheduler"
+	"github.com/nextlevelbuilder/goclaw/pkg/protocol"
+)
+
+// Cluster message kinds used by the gateway wiring.
+const (
+	clusterKindWSFrame         = "ws.frame"
+	clusterKindHeartbeatWake   = "heartbeat.wake"
+	clusterKindApprovalResolve = "tool_approval.resolve"
+	clusterKindAskUserAnswer   = "ask_user.answer"
+	clusterKindAskUserText     = "ask_user.text"
+	clusterKindAskUserPending  = "ask_user.pending"
+)
+
+// clusterForwardTimeout bounds the wait for the replica holding a forwarded
+// approval or question. No reply means no replica holds it.
+const clusterForwardTimeout = 3 * time.Second
+
+// clusterResult is the outcome reported by the replica that handled a
+// forwarded approval decision or ask_user answer.
+type clusterResult struct {
+	Forbidden bool   `json:"forbidden,omitempty"`
+	Error     string `json:"error,omitempty"`
+	Summary   string `json:"summary,omitempty"` // ask_user: the answer as shown in chat
+}
+
+// Singleton services: each runs on exactly one replica at a time.
+const (
+	singletonCron      = "cron"
+	singletonHeartbeat = "heartbeat"
+	singletonTasks     = "tasks"
+)
+
+// setupCluster joins the cluster when cluster mode is enabled and returns nil
+// otherwise. Misconfiguration is fatal: replicas that silently fall back to
+// standalone mode would double-run cron jobs and channels.
+func setupCluster(cfg *config.Config, redisClient any, msgBus *bus.MessageBus) *cluster.Node {
+	cc := cfg.Cluster
+	if !cc.Enabled {
+		return nil
+	}
+	if cfg.Database.StorageBackend == "sqlite" || cfg.Database.PostgresDSN == "" {
+		slog.Error("cluster mode requires the PostgreSQL storage backend shared by every replica")
+		os.Exit(1)
+	}
+
+	nodeID := cc.NodeID
+	if nodeID == "" {
+		nodeID = cluster.DefaultNodeID()
+	}
+	ttl := 15 * time.Second
+	if cc.LeaseTTLSec > 0 {
+		ttl = time.Duration(cc.LeaseTTLSec) * time.Second
+	}
+
+	backendName := cc.Backend
+	if backendName == "" {
+		backendName = "postgres"
+	}
+	var backend cluster.Backend
+	var err error
+	switch backendName {
+	case "postgres":
+		backend, err = cluster.NewPostgresBackend(context.Background(), cfg.Database.PostgresDSN)
+	case "redis":
+		backend, err = cluster.NewRedisBackend(redisClient, nodeID, ttl)
+	default:
+		err = fmt.Errorf("unknown cluster backend %q", backendName)
+	}
+	if err != nil {
+		slog.Error("failed to join cluster", "backend", backendName, "error", err)
+		os.Exit(1)
+	}
+
+	node := cluster.New(nodeID, backend, ttl)
+	node.RelayBus(msgBus)
+	node.Start(context.Background())
+	slog.Info("cluster mode enabled", "node", nodeID, "backend", backendName, "lease_ttl", ttl)
+	return node
+}
+
+// runSingleton starts a service that must run on one replica only. Standalone
+// it starts right away; in cluster mode it runs while this replica holds the
+// service's lease and moves to another replica when the lease is lost.
+func runSingleton(node *cluster.Node, name string, start, stop func()) {
+	if node == nil {
+		start()
+		return
+	}
+	node.Claim(cluster.SingletonKey(name), start, stop)
+}
+
+// stopSingleton is the shutdown counterpart of runSingleton.
+func stopSingleton(node *cluster.Node, name string, stop func()) {
+	if node == nil {
+		stop()
+		return
+	}
+	node.Release(cluster.SingletonKey(name))
+}
+
+// withSessionAffinity extends the scheduler's per-session serialization to
+// the whole cluster: a run holds its session's lock for its duration, so two
+// replicas never run the same session at once.
+func withSessionAffinity(node *cluster.Node, run scheduler.RunFunc) scheduler.RunFunc {
+	if node == nil {
+		return run
+	}
+	return func(ctx context.Context, req agent.RunRequest) (*agent.RunResult, error) {
+		if req.SessionKey == "" {
+			return run(ctx, req)
+		}
+		release, err := node.LockSession(ctx, req.SessionKey)
+		if err != nil {
+			return nil, fmt.Errorf("cluster session lock: %w", err)
+		}
+		defer release()
+		return run(ctx, req)
+	}
+}
+
+// clusterFrameBroadcaster returns a WebSocket broadcast function that also
+// reaches clients connected to the other replicas. Used for frames raised by
+// singleton services (cron, heartbeat) and pairing, which bypass the bus.
+func clusterFrameBroadcaster(node *cluster.Node, local func(protocol.EventFrame)) func(protocol.EventFrame) {
+	if node == nil {
+		return local
+	}
+	node.Handle(clusterKindWSFrame, func(_ string, raw json.RawMessage) {
+		var frame protocol.EventFrame
+		if err := json.Unmarshal(raw, &frame); err != nil {
+			slog.Debug("cluster: malformed ws frame", "error", err)
+			return
+		}
+		local(frame)
+	})
+	return func(frame protocol.EventFrame) {
+		local(frame)
+		node.Send(clusterKindWSFrame, frame)
+	}
+}
+
+// clusterHeartbeatWake returns a wake function that reaches the replica
+// currently running the heartbeat ticker.
+func clusterHeartbeatWake(node *cluster.Node, wake func(uuid.UUID)) func(uuid.UUID) {
+	if node == nil {
+		return wake
+	}
+	key := cluster.SingletonKey(singletonHeartbeat)
+	node.Handle(clusterKindHeartbeatWake, func(_ string, raw json.RawMessage) {
+		var agentID uuid.UUID
+		if err := json.Unmarshal(raw, &agentID); err != nil || !node.Owns(key) {
+			return
+		}
+		wake(agentID)
+	})
+	return func(agentID uuid.UUID) {
+		if node.Owns(key) {
+			wake(agentID)
+			return
+		}
+		node.Send(clusterKindHeartbeatWake, agentID)
+	}
+}
@@ -3,7 +3,6 @@ package cmd
 import (
 	"context"
 	"encoding/json"
-	"fmt"
 	"log/slog"
 	"strings"
 	"sync"
@@ -16,6 +15,7 @@ import (
 	"github.com/nextlevelbuilder/goclaw/internal/bus"
 	"github.com/nextlevelbuilder/goclaw/internal/channels"
 	"github.com/nextlevelbuilder/goclaw/internal/config"
+	"github.com/nextlevelbuilder/goclaw/internal/metrics"
 	"github.com/nextlevelbuilder/goclaw/internal/scheduler"
 	"github.com/nextlevelbuilder/goclaw/internal/store"
 	"github.com/nextlevelbuilder/goclaw/internal/tools"
@@ -26,7 +26,7 @@ import (
 // and routes them through the scheduler/agent loop, then publishes the response back.
 // Also handles subagent announcements: routes them through the parent agent's session
 // (matching TS subagent-announce.ts pattern) so the agent can reformulate for the user.
-func consumeInboundMessages(ctx context.Context, msgBus *bus.MessageBus, agents *agent.Router, cfg *config.Config, sched *scheduler.Scheduler, channelMgr *channels.Manager, teamStore store.TeamStore, quotaChecker *channels.QuotaChecker, sessStore store.SessionStore, agentStore store.AgentStore, contactCollector *store.ContactCollector, postTurn tools.PostTurnProcessor, subagentMgr *tools.SubagentManager) {
+func consumeInboundMessages(ctx context.Context, msgBus *bus.MessageBus, agents *agent.Router, cfg *config.Config, sched *scheduler.Scheduler, channelMgr *channels.Manager, teamStore store.TeamStore, quotaChecker *channels.QuotaChecker, sessStore store.SessionStore, agentStore store.AgentStore, contactCollector *store.ContactCollector, postTurn tools.PostTurnProcessor, subagentMgr *tools.SubagentManager, askUser askUserReplies, ttsTool *tools.TtsTool) {
 	slog.Info("inbound message consumer started")
 
 	// Inbound message deduplication (matching TS src/infra/dedupe.ts + inbound-dedupe.ts).
@@ -59,6 +59,8 @@ func consumeInboundMessages(ctx context.Context, msgBus *bus.MessageBus, agents
 		ContactCollector: contactCollector,
 		SubagentMgr:      subagentMgr,
 		GetAnnounceMu:    getAnnounceMu,
+		AskUser:          askUser,
+		TTS:              ttsTool,
 	}
 
 	// Track running teammate tasks so they can be cancelled when the task is
@@ -111,24 +113,31 @@ func consumeInboundMessages(ctx context.Context, msgBus *bus.MessageBus, agents
 		}
 
 		// --- Dedup: skip duplicate inbound messages (matching TS shouldSkipDuplicateInbound) ---
-		if msgID := msg.Metadata["message_id"]; msgID != "" {
-			dedupeKey := fmt.Sprintf("%s|%s|%s|%s", msg.Channel, msg.SenderID, msg.ChatID, msgID)
+		if dedupeKey := bus.InboundDedupeKey(msg); dedupeKey != "" {
 			if dedupe.IsDuplicate(dedupeKey) {
 				slog.Debug("dedup: skipping duplicate message", "key", dedupeKey)
+				msgBus.AckInbound(msg)
 				continue
 			}
 		}
 
-		if handleSubagentAnnounce(ctx, msg, deps) {
-			continue
-		}
-		if handleTeammateMessage(ctx, msg, deps) {
-			continue
+		if !channels.IsInternalChannel(msg.Channel) {
+			metrics.ChannelMessages.Inc(metrics.TenantLabel(msg.TenantID), msg.Channel, "inbound", "ok")
 		}
-		if handleResetCommand(msg, deps) {
+
+		// Handled messages are acked right away; normal messages are acked by
+		// processNormalMessage once the agent's reply has been published.
+		if handleSubagentAnnounce(ctx, msg, deps) ||
+			handleTeammateMessage(ctx, msg, deps) ||
+			handleResetCommand(msg, deps) ||
+			handleStopCommand(msg, deps) ||
+			handleAskUserReply(msg, deps) {
+			msgBus.AckInbound(msg)
 			continue
 		}
-		if handleStopCommand(msg, deps) {
+
+		// MCP prompt commands run asynchronously and ack the message themselves.
+		if handlePromptCommand(ctx, msg, deps) {
 			continue
 		}
 
@@ -30,4 +30,6 @@ type ConsumerDeps struct {
 	SubagentMgr      *tools.SubagentManager
 	BgWg             sync.WaitGroup
 	GetAnnounceMu    func(string) *sync.Mutex
+	AskUser          askUserReplies
+	TTS              *tools.TtsTool // auto-TTS on final replies; nil disables
 }
@@ -13,6 +13,7 @@ import (
 
 	"github.com/nextlevelbuilder/goclaw/internal/agent"
 	"github.com/nextlevelbuilder/goclaw/internal/bus"
+	"github.com/nextlevelbuilder/goclaw/internal/channels"
 	"github.com/nextlevelbuilder/goclaw/internal/providers"
 	"github.com/nextlevelbuilder/goclaw/internal/safego"
 	"github.com/nextlevelbuilder/goclaw/internal/scheduler"
@@ -537,6 +538,24 @@ func handleStopCommand(
 	return true
 }
 
+// handleAskUserReply treats a chat message as the answer to a pending
+// ask_user question in the same chat (channels without buttons, and forms
+// answered by reply). Returns true if the message was consumed.
+func handleAskUserReply(
+	msg bus.InboundMessage,
+	deps *ConsumerDeps,
+) bool {
+	if deps.AskUser == nil || msg.Content == "" || msg.Metadata[tools.MetaCommand] != "" ||
+		channels.IsInternalChannel(msg.Channel) {
+		return false
+	}
+	if !deps.AskUser.AnswerText(msg.Channel, msg.ChatID, msg.SenderID, msg.Content) {
+		return false
+	}
+	slog.Info("inbound: ask_user answered by reply", "channel", msg.Channel, "chat_id", msg.ChatID)
+	return true
+}
+
 // buildTaskBoardSnapshot returns a formatted summary of batch task statuses
 // for inclusion in the announce message to the leader. Scoped by (teamID, chatID)
 // and filtered by origin_trace_id to show only tasks from the current batch.
@@ -13,6 +13,7 @@ import (
 	"github.com/nextlevelbuilder/goclaw/internal/bus"
 	"github.com/nextlevelbuilder/goclaw/internal/channels/telegram/voiceguard"
 	"github.com/nextlevelbuilder/goclaw/internal/i18n"
+	"github.com/nextlevelbuilder/goclaw/internal/metrics"
 	"github.com/nextlevelbuilder/goclaw/internal/scheduler"
 	"github.com/nextlevelbuilder/goclaw/internal/sessions"
 	"github.com/nextlevelbuilder/goclaw/internal/store"
@@ -26,6 +27,16 @@ func processNormalMessage(
 	msg bus.InboundMessage,
 	deps *ConsumerDeps,
 ) {
+	// Ack the journaled message once handled. When a run is scheduled the
+	// result goroutine acks after publishing the reply, so a crash mid-run
+	// replays the message on restart.
+	handedOff := false
+	defer func() {
+		if !handedOff {
+			deps.MsgBus.AckInbound(msg)
+		}
+	}()
+
 	// Inject tenant from channel instance into context so all store operations
 	// (agent lookup, session creation, etc.) are tenant-scoped.
 	if msg.TenantID != uuid.Nil {
@@ -161,6 +172,7 @@ func processNormalMessage(
 				"used", qResult.Used,
 				"limit", qResult.Limit,
 			)
+			metrics.QuotaRejections.Inc(metrics.TenantLabel(msg.TenantID), msg.Channel, qResult.Window)
 			deps.MsgBus.PublishOutbound(bus.OutboundMessage{
 				Channel:  msg.Channel,
 				ChatID:   msg.ChatID,
@@ -369,7 +381,9 @@ func processNormalMessage(
 	})
 
 	// Handle result asynchronously to not block the flush callback.
+	handedOff = true
 	go func(agentKey, channel, chatID, session, rID, peerKind, inboundContent string, meta map[string]string, blockReplyEnabled bool, ptd *tools.PendingTeamDispatch) {
+		defer deps.MsgBus.AckInbound(msg)
 		outcome := <-outCh
 
 		// Release team create lock — tasks already visible in DB, other goroutines can list.
@@ -462,6 +476,13 @@ func processNormalMessage(
 		}
 
 		appendMediaToOutbound(&outMsg, outcome.Result.Media)
+		if deps.TTS != nil {
+			chType := resolveChannelType(deps.ChannelMgr, channel)
+			if chType == "" {
+				chType = channel
+			}
+			applyAutoTTS(ctx, deps.TTS.Manager(), deps.AgentStore, agentKey, chType, inboundContent, &outMsg)
+		}
 
 		deps.MsgBus.PublishOutbound(outMsg)
 
@@ -0,0 +1,223 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/nextlevelbuilder/goclaw/internal/agent"
+	"github.com/nextlevelbuilder/goclaw/internal/bus"
+	"github.com/nextlevelbuilder/goclaw/internal/channels"
+	mcpbridge "github.com/nextlevelbuilder/goclaw/internal/mcp"
+	"github.com/nextlevelbuilder/goclaw/internal/store"
+)
+
+// handlePromptCommand serves MCP prompt templates in channels:
+//
+//	/prompts                            list prompts exposed by the agent's MCP grants
+//	/prompt <server>:<name> [key=value] render a prompt and send it to the agent
+//
+// Prompt calls go to the MCP server, so the command is handled off the
+// consumer loop. A rendered prompt then runs as a normal message. Returns
+// true when the message was taken over; the handler acks it.
+func handlePromptCommand(ctx context.Context, msg bus.InboundMessage, deps *ConsumerDeps) bool {
+	if channels.IsInternalChannel(msg.Channel) {
+		return false
+	}
+	cmd, rest := parsePromptCommand(msg.Content)
+	if cmd == "" {
+		return false
+	}
+
+	deps.BgWg.Add(1)
+	go func() {
+		defer deps.BgWg.Done()
+
+		if msg.TenantID != uuid.Nil {
+			ctx = store.WithTenantID(ctx, msg.TenantID)
+		} else {
+			ctx = store.WithTenantID(ctx, store.MasterTenantID)
+		}
+		agentID := msg.AgentID
+		if agentID == "" {
+			agentID = resolveAgentRoute(deps.Cfg, msg.Channel, msg.ChatID, msg.PeerKind)
+		}
+
+		reply := func(text string) {
+			deps.MsgBus.PublishOutbound(bus.OutboundMessage{
+				Channel:  msg.Channel,
+				ChatID:   msg.ChatID,
+				Content:  text,
+				Metadata: msg.Metadata,
+			})
+			deps.MsgBus.AckInbound(msg)
+		}
+
+		var mgr *mcpbridge.Manager
+		if ag, err := deps.Agents.Get(ctx, agentID); err == nil {
+			if loop, ok := ag.(*agent.Loop); ok {
+				mgr = loop.MCPManager()
+			}
+		}
+		if mgr == nil {
+			reply("No MCP prompts are available for this agent.")
+			return
+		}
+
+		if cmd == "list" {
+			reply(formatPromptList(mgr.Prompts(ctx)))
+			return
+		}
+
+		server, name, args, err := parsePromptArgs(rest)
+		if err == nil && server == "" {
+			server, err = findPromptServer(mgr.Prompts(ctx), name)
+		}
+		if err != nil {
+			reply(err.Error())
+			return
+		}
+		text, err := mgr.GetPrompt(ctx, server, name, args)
+		if err != nil {
+			slog.Warn("inbound: /prompt failed", "agent", agentID, "server", server, "prompt", name, "error", err)
+			reply("Prompt failed: " + err.Error())
+			return
+		}
+		if strings.TrimSpace(text) == "" {
+			reply(fmt.Sprintf("Prompt %s:%s rendered empty.", server, name))
+			return
+		}
+
+		slog.Info("inbound: /prompt", "agent", agentID, "server", server, "prompt", name)
+		msg.Content = text
+		processNormalMessage(ctx, msg, deps)
+	}()
+	return true
+}
+
+// parsePromptCommand returns "list" for /prompts, "get" plus the remainder
+// for /prompt, or "" for anything else. A Telegram-style @bot suffix on the
+// command is ignored.
+func parsePromptCommand(content string) (cmd, rest string) {
+	content = strings.TrimSpace(content)
+	if len(content) < 7 || !strings.EqualFold(content[:7], "/prompt") {
+		return "", ""
+	}
+	head, rest, _ := strings.Cut(content, " ")
+	head, _, _ = strings.Cut(strings.ToLower(head), "@")
+	switch head {
+	case "/prompts":
+		return "list", ""
+	case "/prompt":
+		return "get", strings.TrimSpace(rest)
+	}
+	return "", ""
+}
+
+// parsePromptArgs parses `<server>:<name> key=value key2="quoted value"`.
+// The server may be omitted when the prompt name is unique.
+func parsePromptArgs(s string) (server, name string, args map[string]string, err error) {
+	fields := splitQuoted(s)
+	if len(fields) == 0 {
+		return "", "", nil, fmt.Errorf("usage: /prompt <server>:<name> [key=value ...] (see /prompts)")
+	}
+	if srv, n, ok := strings.Cut(fields[0], ":"); ok {
+		server, name = srv, n
+	} else {
+		name = fields[0]
+	}
+	if name == "" {
+		return "", "", nil, fmt.Errorf("missing prompt name in %q", fields[0])
+	}
+	for _, f := range fields[1:] {
+		k, v, ok := strings.Cut(f, "=")
+		if !ok || k == "" {
+			return "", "", nil, fmt.Errorf("invalid argument %q, want key=value", f)
+		}
+		if args == nil {
+			args = make(map[string]string)
+		}
+		args[k] = v
+	}
+	return server, name, args, nil
+}
+
+// splitQuoted splits on whitespace, keeping single- or double-quoted runs
+// together (quotes removed).
+func splitQuoted(s string) []string {
+	var (
+		out   []string
+		cur   strings.Builder
+		quote rune
+		in    bool
+	)
+	for _, r := range s {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else {
+				cur.WriteRune(r)
+			}
+		case r == '"' || r == '\'':
+			quote, in = r, true
+		case r == ' ' || r == '\t' || r == '\n':
+			if in {
+				out = append(out, cur.String())
+				cur.Reset()
+				in = false
+			}
+		default:
+			cur.WriteRune(r)
+			in = true
+		}
+	}
+	if in {
+		out = append(out, cur.String())
+	}
+	return out
+}
+
+// findPromptServer resolves a bare prompt name to its server.
+func findPromptServer(prompts []mcpbridge.PromptInfo, name string) (string, error) {
+	var servers []string
+	for _, p := range prompts {
+		if p.Name == name {
+			servers = append(servers, p.Server)
+		}
+	}
+	switch len(servers) {
+	case 0:
+		return "", fmt.Errorf("unknown prompt %q (see /prompts)", name)
+	case 1:
+		return servers[0], nil
+	default:
+		return "", fmt.Errorf("prompt %q exists on several servers (%s); use <server>:%s", name, strings.Join(servers, ", "), name)
+	}
+}
+
+func formatPromptList(prompts []mcpbridge.PromptInfo) string {
+	if len(prompts) == 0 {
+		return "No MCP prompts are available for this agent."
+	}
+	var sb strings.Builder
+	sb.WriteString("MCP prompts:\n")
+	for _, p := range prompts {
+		fmt.Fprintf(&sb, "/prompt %s:%s", p.Server, p.Name)
+		for _, a := range p.Arguments {
+			if a.Required {
+				fmt.Fprintf(&sb, " %s=…", a.Name)
+			} else {
+				fmt.Fprintf(&sb, " [%s=…]", a.Name)
+			}
+		}
+		if p.Description != "" {
+			sb.WriteString(" — " + p.Description)
+		}
+		sb.WriteByte('\n')
+	}
+	return strings.TrimRight(sb.String(), "\n")
+}
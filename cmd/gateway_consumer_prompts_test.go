@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"testing"
+
+	mcpbridge "github.com/nextlevelbuilder/goclaw/internal/mcp"
+)
+
+func TestParsePromptCommand(t *testing.T) {
+	tests := []struct {
+		in, cmd, rest string
+	}{
+		{"/prompts", "list", ""},
+		{"/prompts@my_bot", "list", ""},
+		{"/prompt github:review pr=12", "get", "github:review pr=12"},
+		{"  /PROMPT  summarize ", "get", "summarize"},
+		{"/promptly", "", ""},
+		{"hello /prompt", "", ""},
+	}
+	for _, tt := range tests {
+		cmd, rest := parsePromptCommand(tt.in)
+		if cmd != tt.cmd || rest != tt.rest {
+			t.Errorf("parsePromptCommand(%q) = %q, %q; want %q, %q", tt.in, cmd, rest, tt.cmd, tt.rest)
+		}
+	}
+}
+
+func TestParsePromptArgs(t *testing.T) {
+	server, name, args, err := parsePromptArgs(`github:review pr=12 note="needs tests" who='a b'`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if server != "github" || name != "review" {
+		t.Errorf("server/name = %q/%q", server, name)
+	}
+	if args["pr"] != "12" || args["note"] != "needs tests" || args["who"] != "a b" {
+		t.Errorf("args = %v", args)
+	}
+
+	if _, _, _, err := parsePromptArgs("review oops"); err == nil {
+		t.Error("argument without '=' should be rejected")
+	}
+	if _, _, _, err := parsePromptArgs(""); err == nil {
+		t.Error("empty command should return usage")
+	}
+}
+
+func TestFindPromptServer(t *testing.T) {
+	prompts := []mcpbridge.PromptInfo{
+		{Server: "a", Name: "summarize"},
+		{Server: "a", Name: "review"},
+		{Server: "b", Name: "review"},
+	}
+	if s, err := findPromptServer(prompts, "summarize"); err != nil || s != "a" {
+		t.Errorf("summarize -> %q, %v", s, err)
+	}
+	if _, err := findPromptServer(prompts, "review"); err == nil {
+		t.Error("ambiguous name should be rejected")
+	}
+	if _, err := findPromptServer(prompts, "missing"); err == nil {
+		t.Error("unknown name should be rejected")
+	}
+}
@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/nextlevelbuilder/goclaw/internal/bus"
+	"github.com/nextlevelbuilder/goclaw/internal/store"
+	"github.com/nextlevelbuilder/goclaw/internal/tts"
+)
+
+// applyAutoTTS attaches a synthesized voice reply to a final channel message when
+// the tenant/agent auto-TTS mode (tts.enable / config tts.auto) asks for one.
+// [[tts]] directives are always stripped from the delivered text.
+// The audio is written to the temp dir, which the channel manager cleans up after delivery.
+func applyAutoTTS(ctx context.Context, mgr *tts.Manager, agentStore store.AgentStore, agentKey, channelType, inboundContent string, msg *bus.OutboundMessage) {
+	if mgr == nil {
+		return
+	}
+	text := msg.Content
+	msg.Content = strings.TrimSpace(tts.StripDirectives(text))
+	if !mgr.HasProviders() {
+		return
+	}
+
+	tenantID := store.TenantIDFromContext(ctx).String()
+	agentID := ""
+	if agentStore != nil {
+		// agentKey may be a slug ("default") or a UUID string (from WS clients).
+		var ag *store.AgentData
+		if id, err := uuid.Parse(agentKey); err == nil {
+			ag, _ = agentStore.GetByID(ctx, id)
+		} else {
+			ag, _ = agentStore.GetByKey(ctx, agentKey)
+		}
+		if ag != nil {
+			agentID = ag.ID.String()
+		}
+	}
+
+	isVoiceInbound := strings.Contains(inboundContent, "<media:voice") || strings.Contains(inboundContent, "<media:audio")
+	result, ok := mgr.MaybeApply(ctx, tenantID, agentID, text, channelType, isVoiceInbound, "final")
+	if !ok {
+		return
+	}
+
+	audioPath := filepath.Join(os.TempDir(), fmt.Sprintf("tts-auto-%d.%s", time.Now().UnixNano(), result.Extension))
+	if err := os.WriteFile(audioPath, result.Audio, 0644); err != nil {
+		slog.Warn("tts auto-apply: write audio failed", "error", err)
+		return
+	}
+	msg.Media = append(msg.Media, bus.MediaAttachment{URL: audioPath, ContentType: result.MimeType})
+	if channelType == "telegram" && (result.Extension == "ogg" || result.Extension == "opus") {
+		if msg.Metadata == nil {
+			msg.Metadata = make(map[string]string)
+		}
+		msg.Metadata["audio_as_voice"] = "true"
+	}
+}
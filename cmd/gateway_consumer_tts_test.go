@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/nextlevelbuilder/goclaw/internal/bus"
+	"github.com/nextlevelbuilder/goclaw/internal/store"
+	"github.com/nextlevelbuilder/goclaw/internal/tts"
+)
+
+type fakeTTSProvider struct{}
+
+func (fakeTTSProvider) Name() string { return "fake" }
+
+func (fakeTTSProvider) Synthesize(_ context.Context, _ string, _ tts.Options) (*tts.SynthResult, error) {
+	return &tts.SynthResult{Audio: []byte("audio"), Extension: "ogg", MimeType: "audio/ogg"}, nil
+}
+
+func TestApplyAutoTTS_InboundModeAttachesVoiceReply(t *testing.T) {
+	mgr := tts.NewManager(tts.ManagerConfig{Auto: tts.AutoInbound})
+	mgr.RegisterProvider(fakeTTSProvider{})
+	ctx := store.WithTenantID(context.Background(), store.MasterTenantID)
+
+	text := bus.OutboundMessage{Content: "Here is a spoken answer for you."}
+	applyAutoTTS(ctx, mgr, nil, "default", "telegram", "hello", &text)
+	if len(text.Media) != 0 {
+		t.Fatalf("text inbound should not get audio, got %v", text.Media)
+	}
+
+	voice := bus.OutboundMessage{Content: "[[tts]] Here is a spoken answer for you."}
+	applyAutoTTS(ctx, mgr, nil, "default", "telegram", "<media:voice>", &voice)
+	if len(voice.Media) != 1 {
+		t.Fatalf("voice inbound should get one audio attachment, got %v", voice.Media)
+	}
+	defer os.Remove(voice.Media[0].URL)
+	if voice.Metadata["audio_as_voice"] != "true" {
+		t.Errorf("telegram ogg reply should be sent as voice, metadata=%v", voice.Metadata)
+	}
+	if voice.Content != "Here is a spoken answer for you." {
+		t.Errorf("tts directives should be stripped, got %q", voice.Content)
+	}
+}
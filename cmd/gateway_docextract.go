@@ -0,0 +1,23 @@
+package cmd
+
+import (
+	"path/filepath"
+
+	"github.com/nextlevelbuilder/goclaw/internal/docextract"
+	"github.com/nextlevelbuilder/goclaw/internal/tools"
+)
+
+// documentCacheDir holds extracted document text keyed by content hash.
+func documentCacheDir(dataDir string) string {
+	return filepath.Join(dataDir, "cache", "documents")
+}
+
+// wireDocumentExtractor gives read_document the shared disk-cached extractor,
+// so a file asked about again (even after a restart) is not parsed twice.
+func wireDocumentExtractor(extractor *docextract.Extractor, toolsReg *tools.Registry) {
+	if t, ok := toolsReg.Get("read_document"); ok {
+		if rd, ok := t.(*tools.ReadDocumentTool); ok {
+			rd.SetExtractor(extractor)
+		}
+	}
+}
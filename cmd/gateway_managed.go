@@ -11,18 +11,22 @@ import (
 	"strings"
 
 	"github.com/nextlevelbuilder/goclaw/internal/agent"
+	"github.com/nextlevelbuilder/goclaw/internal/budget"
 	"github.com/nextlevelbuilder/goclaw/internal/bus"
+	"github.com/nextlevelbuilder/goclaw/internal/cache"
 	"github.com/nextlevelbuilder/goclaw/internal/config"
 	"github.com/nextlevelbuilder/goclaw/internal/edition"
 	httpapi "github.com/nextlevelbuilder/goclaw/internal/http"
 	kg "github.com/nextlevelbuilder/goclaw/internal/knowledgegraph"
 	mcpbridge "github.com/nextlevelbuilder/goclaw/internal/mcp"
 	"github.com/nextlevelbuilder/goclaw/internal/media"
+	"github.com/nextlevelbuilder/goclaw/internal/memory"
 	"github.com/nextlevelbuilder/goclaw/internal/providers"
 	"github.com/nextlevelbuilder/goclaw/internal/sandbox"
 	"github.com/nextlevelbuilder/goclaw/internal/skills"
 	"github.com/nextlevelbuilder/goclaw/internal/store"
 	"github.com/nextlevelbuilder/goclaw/internal/store/pg"
+	"github.com/nextlevelbuilder/goclaw/internal/stt"
 	"github.com/nextlevelbuilder/goclaw/internal/tools"
 	"github.com/nextlevelbuilder/goclaw/internal/tracing"
 	"github.com/nextlevelbuilder/goclaw/pkg/protocol"
@@ -50,14 +54,19 @@ func wireExtras(
 	appCfg *config.Config,
 	sandboxMgr sandbox.Manager,
 	redisClient any, // nil when built without -tags redis or when Redis is unconfigured
+	budgetChecker *budget.Checker,
+	sttMgr *stt.Manager,
 ) (*tools.ContextFileInterceptor, *mcpbridge.Pool, *media.Store, tools.PostTurnProcessor) {
 	// 1. Build cache instances (in-memory or Redis depending on build tags)
 	agentCtxCache, userCtxCache := makeCaches(redisClient)
+	agentCtxCache = cache.Instrumented("agent_context", agentCtxCache)
+	userCtxCache = cache.Instrumented("user_context", userCtxCache)
 
 	// 1a. Context file interceptor (created before resolver so callbacks can reference it)
 	var contextFileInterceptor *tools.ContextFileInterceptor
 	if stores.Agents != nil {
 		contextFileInterceptor = tools.NewContextFileInterceptor(stores.Agents, workspace, agentCtxCache, userCtxCache)
+		contextFileInterceptor.SetRevisionStore(stores.ContextFileRevisions)
 	}
 
 	// 1c. Persistent media storage for cross-turn image/document access
@@ -121,6 +130,8 @@ func wireExtras(
 	var mcpPool *mcpbridge.Pool
 	if stores.MCP != nil {
 		mcpPool = mcpbridge.NewPool(mcpbridge.DefaultPoolConfig())
+		// OAuth 2.1 authorization for remote MCP servers that opt in via settings.oauth.
+		mcpPool.SetOAuth(mcpbridge.NewOAuthFlows(stores.MCP, mcpOAuthRedirectURI(appCfg), msgBus))
 	}
 
 	// 6. Set up agent resolver: lazy-creates Loops from DB
@@ -162,8 +173,9 @@ func wireExtras(
 		MCPPool:                mcpPool,
 		ConfigPermStore:        stores.ConfigPermissions,
 		MediaStore:             mediaStore,
+		STT:                    sttMgr,
 		ModelPricing:           appCfg.Telemetry.ModelPricing,
-		TracingStore:           stores.Tracing,
+		Budget:                 budgetChecker,
 		MemoryStore:            stores.Memory,
 		TenantStore:            stores.Tenants,
 		BuiltinToolTenantCfgs:  stores.BuiltinToolTenantCfgs,
@@ -280,6 +292,11 @@ func wireExtras(
 			if ms, ok := searchTool.(tools.MemoryStoreAware); ok {
 				ms.SetMemoryStore(stores.Memory)
 			}
+			if mst, ok := searchTool.(*tools.MemorySearchTool); ok {
+				retriever := memory.NewRetriever(stores.Memory, providerReg)
+				retriever.SetURLCheck(tools.CheckSSRF)
+				mst.SetRetriever(retriever)
+			}
 		}
 		if getTool, ok := toolsReg.Get("memory_get"); ok {
 			if ms, ok := getTool.(tools.MemoryStoreAware); ok {
@@ -395,6 +412,16 @@ func wireExtras(
 			ci.InvalidateCache()
 		})
 	}
+	// Broadcast cron job writes so the cluster relay reaches the scheduler leader,
+	// which otherwise keeps firing from its stale job cache.
+	if cn, ok := stores.Cron.(interface{ SetOnChange(func()) }); ok {
+		cn.SetOnChange(func() {
+			msgBus.Broadcast(bus.Event{
+				Name:    protocol.EventCacheInvalidate,
+				Payload: bus.CacheInvalidatePayload{Kind: bus.CacheKindCron},
+			})
+		})
+	}
 
 	// Heartbeat cache: invalidate due cache on config changes
 	if hi, ok := stores.Heartbeats.(store.CacheInvalidatable); ok {
@@ -602,3 +629,13 @@ func buildKGExtractFunc(kgStore store.KnowledgeGraphStore, bts store.BuiltinTool
 		}
 	}
 }
+
+// mcpOAuthRedirectURI returns the callback URL registered with MCP
+// authorization servers: gateway.public_url when set, else the local address.
+func mcpOAuthRedirectURI(cfg *config.Config) string {
+	base := strings.TrimRight(cfg.Gateway.PublicURL, "/")
+	if base == "" {
+		base = "http://" + loopbackAddr(cfg.Gateway.Host, cfg.Gateway.Port)
+	}
+	return base + "/v1/mcp/oauth/callback"
+}
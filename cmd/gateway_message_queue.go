@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/nextlevelbuilder/goclaw/internal/bus"
+	"github.com/nextlevelbuilder/goclaw/internal/channels"
+	"github.com/nextlevelbuilder/goclaw/internal/cluster"
+	"github.com/nextlevelbuilder/goclaw/internal/config"
+	"github.com/nextlevelbuilder/goclaw/internal/queue"
+	"github.com/nextlevelbuilder/goclaw/internal/store"
+)
+
+// messageQueueEnabled reports whether the durable message queue is configured
+// and backed by a store.
+func messageQueueEnabled(cfg *config.Config, stores *store.Stores) bool {
+	mq := cfg.Gateway.MessageQueue
+	return mq != nil && mq.Enabled && stores.MessageQueue != nil
+}
+
+// setupMessageQueue attaches the durable message queue to the bus. Must run
+// before channels start publishing; the returned queue is started once the
+// inbound consumer runs. Returns nil when the queue is disabled.
+func setupMessageQueue(cfg *config.Config, stores *store.Stores, msgBus *bus.MessageBus, channelMgr *channels.Manager, node *cluster.Node) *queue.Queue {
+	if !messageQueueEnabled(cfg, stores) {
+		return nil
+	}
+	mq := cfg.Gateway.MessageQueue
+	qc := queue.Config{
+		MaxAttempts:   mq.MaxAttempts,
+		Retention:     time.Duration(mq.RetentionHours) * time.Hour,
+		ChannelTenant: channelMgr.ChannelTenantID,
+	}
+	// Each replica replays what it held in memory when restarted under the
+	// same cluster.node_id, and what replicas that are gone held otherwise.
+	if node != nil {
+		qc.Owner = node.ID()
+		qc.OwnerAlive = func(owner string) bool {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			return node.Alive(ctx, owner)
+		}
+	}
+	q := queue.New(stores.MessageQueue, msgBus, qc)
+	msgBus.SetJournal(q)
+	slog.Info("durable message queue enabled", "owner", qc.Owner)
+	return q
+}
@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	"github.com/nextlevelbuilder/goclaw/internal/bus"
+	"github.com/nextlevelbuilder/goclaw/internal/gateway"
+	"github.com/nextlevelbuilder/goclaw/internal/metrics"
+	"github.com/nextlevelbuilder/goclaw/internal/scheduler"
+)
+
+// registerRuntimeGauges attaches scrape-time gauges for components owned by
+// the gateway process: bus queues and WebSocket clients.
+func registerRuntimeGauges(msgBus *bus.MessageBus, server *gateway.Server) {
+	metrics.Default.NewGaugeFunc("goclaw_bus_queue_size",
+		"Messages buffered in the message bus, by queue.",
+		[]string{"queue"}, func() []metrics.Sample {
+			in, out := msgBus.QueueSizes()
+			return []metrics.Sample{
+				{Labels: []string{"inbound"}, Value: float64(in)},
+				{Labels: []string{"outbound"}, Value: float64(out)},
+			}
+		})
+	metrics.Default.NewGaugeFunc("goclaw_ws_clients",
+		"Connected WebSocket clients.",
+		nil, func() []metrics.Sample {
+			return []metrics.Sample{{Value: float64(server.ClientCount())}}
+		})
+}
+
+// registerLaneGauges attaches per-lane depth, active and concurrency gauges.
+func registerLaneGauges(sched *scheduler.Scheduler) {
+	laneGauge := func(name, help string, pick func(scheduler.LaneStats) int) {
+		metrics.Default.NewGaugeFunc(name, help, []string{"lane"}, func() []metrics.Sample {
+			stats := sched.LaneStats()
+			out := make([]metrics.Sample, 0, len(stats))
+			for _, s := range stats {
+				out = append(out, metrics.Sample{Labels: []string{s.Name}, Value: float64(pick(s))})
+			}
+			return out
+		})
+	}
+	laneGauge("goclaw_lane_pending", "Runs waiting for a slot, by scheduler lane.",
+		func(s scheduler.LaneStats) int { return s.Pending })
+	laneGauge("goclaw_lane_active", "Runs currently executing, by scheduler lane.",
+		func(s scheduler.LaneStats) int { return s.Active })
+	laneGauge("goclaw_lane_concurrency", "Configured concurrency limit, by scheduler lane.",
+		func(s scheduler.LaneStats) int { return s.Concurrency })
+}
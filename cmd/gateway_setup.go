@@ -339,7 +339,7 @@ func wireTracingAndCron(
 	return traceCollector, snapshotWorker
 }
 
-// setupMemoryEmbeddings wires embedding provider to PGMemoryStore and triggers backfill.
+// setupMemoryEmbeddings wires the embedding provider into the memory store (PG or SQLite) and triggers backfill.
 // Resolves embedding provider from DB providers with settings.embedding.enabled.
 func setupMemoryEmbeddings(
 	pgStores *store.Stores,
@@ -367,10 +367,14 @@ func setupMemoryEmbeddings(
 			}
 
 			// Wire embedding provider into team store for semantic task search.
-			if pgTeamStore, ok := pgStores.Teams.(*pg.PGTeamStore); ok {
-				pgTeamStore.SetEmbeddingProvider(embProvider)
+			type taskEmbedder interface {
+				SetEmbeddingProvider(provider store.EmbeddingProvider)
+				BackfillTaskEmbeddings(ctx context.Context) (int, error)
+			}
+			if teamStore, ok := pgStores.Teams.(taskEmbedder); ok {
+				teamStore.SetEmbeddingProvider(embProvider)
 				go func() {
-					if count, err := pgTeamStore.BackfillTaskEmbeddings(context.Background()); err != nil {
+					if count, err := teamStore.BackfillTaskEmbeddings(context.Background()); err != nil {
 						slog.Warn("task embeddings backfill failed", "error", err)
 					} else if count > 0 {
 						slog.Info("task embeddings backfill complete", "tasks_updated", count)
@@ -551,15 +555,15 @@ func setupSkillsSystem(
 		if sas, ok := pgStores.Skills.(store.SkillAccessStore); ok {
 			skillSearchTool.SetSkillAccessStore(sas)
 		}
-		if pgSkills, ok := pgStores.Skills.(*pg.PGSkillStore); ok {
+		if embSkills, ok := pgStores.Skills.(store.EmbeddingSkillSearcher); ok {
 			if embProvider := resolveEmbeddingProvider(pgStores.Providers, providerRegistry, pgStores.SystemConfigs); embProvider != nil {
-				pgSkills.SetEmbeddingProvider(embProvider)
-				skillSearchTool.SetEmbeddingSearcher(pgSkills, embProvider)
+				embSkills.SetEmbeddingProvider(embProvider)
+				skillSearchTool.SetEmbeddingSearcher(embSkills, embProvider)
 				slog.Info("skill embeddings enabled", "provider", embProvider.Name())
 
 				// Backfill embeddings for existing skills
 				go func() {
-					count, err := pgSkills.BackfillSkillEmbeddings(context.Background())
+					count, err := embSkills.BackfillSkillEmbeddings(context.Background())
 					if err != nil {
 						slog.Warn("skill embeddings backfill failed", "error", err)
 					} else if count > 0 {
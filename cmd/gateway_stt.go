@@ -0,0 +1,160 @@
+package cmd
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/google/uuid"
+
+	"github.com/nextlevelbuilder/goclaw/internal/bus"
+	"github.com/nextlevelbuilder/goclaw/internal/config"
+	"github.com/nextlevelbuilder/goclaw/internal/store"
+	"github.com/nextlevelbuilder/goclaw/internal/stt"
+)
+
+// setupSTT creates the STT manager from config and registers every provider
+// that is configured. Always returns a non-nil manager (possibly without
+// providers, in which case the agent loop skips transcription).
+func setupSTT(cfg *config.Config) *stt.Manager {
+	sttCfg := cfg.Stt
+
+	mgr := stt.NewManager(stt.ManagerConfig{
+		Primary:   sttCfg.Provider,
+		Language:  sttCfg.Language,
+		TimeoutMs: sttCfg.TimeoutMs,
+	})
+
+	if key := sttCfg.OpenAI.APIKey; key != "" || sttCfg.OpenAI.APIBase != "" {
+		mgr.RegisterProvider(stt.NewOpenAIProvider(stt.OpenAIConfig{
+			APIKey:    key,
+			APIBase:   sttCfg.OpenAI.APIBase,
+			Model:     sttCfg.OpenAI.Model,
+			TimeoutMs: sttCfg.TimeoutMs,
+		}))
+	}
+
+	if key := sttCfg.Gemini.APIKey; key != "" {
+		mgr.RegisterProvider(stt.NewGeminiProvider(stt.GeminiConfig{
+			APIKey:    key,
+			APIBase:   sttCfg.Gemini.APIBase,
+			Model:     sttCfg.Gemini.Model,
+			TimeoutMs: sttCfg.TimeoutMs,
+		}))
+	}
+
+	if url := sttCfg.Whisper.URL; url != "" {
+		mgr.RegisterProvider(stt.NewWhisperProvider(stt.WhisperConfig{
+			BaseURL:   url,
+			APIKey:    sttCfg.Whisper.APIKey,
+			TimeoutMs: sttCfg.TimeoutMs,
+		}))
+	}
+
+	proxy := sttCfg.Proxy
+	if proxy.URL == "" {
+		proxy = legacyChannelSTTProxy(cfg)
+	}
+	if proxy.URL != "" {
+		mgr.RegisterProvider(stt.NewProxyProvider(stt.ProxyConfig{
+			BaseURL:   proxy.URL,
+			APIKey:    proxy.APIKey,
+			TenantID:  proxy.TenantID,
+			TimeoutMs: sttCfg.TimeoutMs,
+		}))
+	}
+
+	if mgr.HasProviders() {
+		slog.Info("stt enabled", "provider", mgr.PrimaryProvider(), "providers", mgr.ProviderNames())
+	}
+	return mgr
+}
+
+// legacyChannelSTTProxy returns the first channel-level stt_proxy_url
+// (Telegram, Discord, Feishu) in config.json as the gateway proxy provider.
+// Channels no longer transcribe on their own; this keeps existing configs working.
+// The proxy serves every channel, so channels that configured a different
+// proxy are reported as ignored.
+func legacyChannelSTTProxy(cfg *config.Config) config.SttProxyConfig {
+	ch := cfg.Channels
+	var out config.SttProxyConfig
+	from := ""
+	for _, c := range []struct{ name, url, key, tenant string }{
+		{"telegram", ch.Telegram.STTProxyURL, ch.Telegram.STTAPIKey, ch.Telegram.STTTenantID},
+		{"discord", ch.Discord.STTProxyURL, ch.Discord.STTAPIKey, ch.Discord.STTTenantID},
+		{"feishu", ch.Feishu.STTProxyURL, ch.Feishu.STTAPIKey, ch.Feishu.STTTenantID},
+	} {
+		if c.url == "" {
+			continue
+		}
+		if from == "" {
+			slog.Warn("stt: channel stt_proxy_url is deprecated, configure stt.proxy instead", "channel", c.name)
+			out = config.SttProxyConfig{URL: c.url, APIKey: c.key, TenantID: c.tenant}
+			from = c.name
+			continue
+		}
+		if c.url != out.URL || c.key != out.APIKey || c.tenant != out.TenantID {
+			slog.Warn("stt: channel stt_proxy_url ignored, the gateway uses a single proxy",
+				"channel", c.name, "using", from)
+		}
+	}
+	return out
+}
+
+// loadSTTScopes reads tenant STT overrides (stt.provider, stt.language) from
+// system_configs for every tenant and applies them to the live STT manager.
+func loadSTTScopes(sc store.SystemConfigStore, ts store.TenantStore, mgr *stt.Manager) {
+	if sc == nil || mgr == nil {
+		return
+	}
+	tenantIDs := []uuid.UUID{store.MasterTenantID}
+	if ts != nil {
+		if tenants, err := ts.ListTenants(context.Background()); err == nil && len(tenants) > 0 {
+			tenantIDs = tenantIDs[:0]
+			for _, t := range tenants {
+				tenantIDs = append(tenantIDs, t.ID)
+			}
+		}
+	}
+	for _, id := range tenantIDs {
+		reloadSTTScope(store.WithTenantID(context.Background(), id), sc, mgr)
+	}
+}
+
+// reloadSTTScope re-reads one tenant's STT overrides (tenant from ctx).
+func reloadSTTScope(ctx context.Context, sc store.SystemConfigStore, mgr *stt.Manager) {
+	configs, err := sc.List(ctx)
+	if err != nil {
+		slog.Warn("stt: failed to load scope overrides", "tenant", store.TenantIDFromContext(ctx), "error", err)
+		return
+	}
+	mgr.SetTenantScope(store.TenantIDFromContext(ctx).String(), stt.ParseScopeConfigs(configs))
+}
+
+// subscribeSTTReload keeps the STT manager in sync with config.json changes
+// (providers, defaults) and with tenant overrides in system_configs.
+func subscribeSTTReload(msgBus *bus.MessageBus, sc store.SystemConfigStore, secrets store.ConfigSecretsStore, mgr *stt.Manager) {
+	msgBus.Subscribe("stt-config-reload", func(evt bus.Event) {
+		switch evt.Name {
+		case bus.TopicConfigChanged:
+			updatedCfg, ok := evt.Payload.(*config.Config)
+			if !ok {
+				return
+			}
+			if secrets != nil {
+				if s, err := secrets.GetAll(context.Background()); err == nil && len(s) > 0 {
+					updatedCfg.ApplyDBSecrets(s)
+				}
+			}
+			mgr.UpdateFrom(setupSTT(updatedCfg))
+		case bus.TopicSystemConfigChanged:
+			if sc == nil {
+				return
+			}
+			ctx, ok := evt.Payload.(context.Context)
+			if !ok {
+				ctx = store.WithTenantID(context.Background(), store.MasterTenantID)
+			}
+			reloadSTTScope(ctx, sc, mgr)
+		}
+	})
+}
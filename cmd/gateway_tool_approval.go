@@ -0,0 +1,141 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/nextlevelbuilder/goclaw/internal/channels"
+	"github.com/nextlevelbuilder/goclaw/internal/cluster"
+	"github.com/nextlevelbuilder/goclaw/internal/gateway"
+	"github.com/nextlevelbuilder/goclaw/internal/gateway/methods"
+	"github.com/nextlevelbuilder/goclaw/internal/tools"
+)
+
+// approvalArgsPreviewLen caps the arguments shown in chat approval requests.
+const approvalArgsPreviewLen = 500
+
+// wireToolApprovals connects tool approvals to the channels: requests are sent
+// to the target chats with buttons, and button presses resolve them. WS
+// clients use the tool.approval.* methods. In cluster mode, decisions on
+// requests pending on another replica are forwarded to it.
+func wireToolApprovals(mgr *tools.ToolApprovalManager, channelMgr *channels.Manager, server *gateway.Server, node *cluster.Node) {
+	mgr.AddNotifier(func(ctx context.Context, req *tools.ToolApprovalRequest) {
+		args, _ := json.Marshal(req.Args)
+		preview := string(args)
+		if runes := []rune(preview); len(runes) > approvalArgsPreviewLen {
+			preview = string(runes[:approvalArgsPreviewLen]) + "…"
+		}
+		msg := channels.ApprovalRequest{
+			ID:        req.ID,
+			AgentKey:  req.AgentKey,
+			Tool:      req.Tool,
+			Args:      preview,
+			Reason:    req.Reason,
+			ExpiresAt: req.ExpiresAt,
+		}
+		for _, t := range req.Targets {
+			if err := channelMgr.SendApprovalRequest(ctx, t.Channel, t.ChatID, msg); err != nil {
+				slog.Warn("tool approval: send request failed", "id", req.ID, "channel", t.Channel, "chat_id", t.ChatID, "error", err)
+			}
+		}
+	})
+
+	approvalMethods := methods.NewToolApprovalMethods(mgr)
+	forward := clusterApprovalForwarder(node, func(d clusterApprovalDecision) error {
+		if d.Caller != nil {
+			return approvalMethods.Resolve(*d.Caller, d.ID, d.Decision)
+		}
+		_, err := mgr.Resolve(d.ID, d.Decision, tools.ApprovalActor{ID: d.SenderID, Via: d.Channel})
+		return err
+	})
+	if forward != nil {
+		approvalMethods.SetForwarder(func(ctx context.Context, caller methods.ApprovalCaller, id string, decision tools.ApprovalDecision) error {
+			return forward(ctx, clusterApprovalDecision{ID: id, Decision: decision, Caller: &caller})
+		})
+	}
+
+	channelMgr.SetApprovalResolver(func(ctx context.Context, id, decision, channel, senderID string) (string, error) {
+		_, err := mgr.Resolve(id, tools.ApprovalDecision(decision), tools.ApprovalActor{ID: senderID, Via: channel})
+		if errors.Is(err, tools.ErrApprovalNotFound) && forward != nil {
+			err = forward(ctx, clusterApprovalDecision{ID: id, Decision: tools.ApprovalDecision(decision), SenderID: senderID, Channel: channel})
+		}
+		switch {
+		case errors.Is(err, tools.ErrApprovalNotFound):
+			return "", errors.New("This request was already decided or has expired.")
+		case errors.Is(err, tools.ErrApprovalForbidden):
+			return "", errors.New("You are not allowed to decide this request.")
+		case err != nil:
+			return "", err
+		}
+		switch tools.ApprovalDecision(decision) {
+		case tools.ApprovalAllowSession:
+			return fmt.Sprintf("✅ Approved for this session by %s", senderID), nil
+		case tools.ApprovalDeny:
+			return fmt.Sprintf("❌ Denied by %s", senderID), nil
+		}
+		return fmt.Sprintf("✅ Approved by %s", senderID), nil
+	})
+
+	approvalMethods.Register(server.Router())
+}
+
+// clusterApprovalDecision is a decision forwarded to the replica whose tool
+// call waits for it: a channel button press, or a WS client's decision.
+type clusterApprovalDecision struct {
+	ID       string                  `json:"id"`
+	Decision tools.ApprovalDecision  `json:"decision"`
+	SenderID string                  `json:"sender_id,omitempty"`
+	Channel  string                  `json:"channel,omitempty"`
+	Caller   *methods.ApprovalCaller `json:"caller,omitempty"`
+}
+
+// clusterApprovalForwarder serves decisions forwarded by other replicas with
+// resolve, and returns the function that forwards this replica's decisions
+// on requests it does not hold (nil when not clustered).
+func clusterApprovalForwarder(node *cluster.Node, resolve func(clusterApprovalDecision) error) func(context.Context, clusterApprovalDecision) error {
+	if node == nil {
+		return nil
+	}
+	node.HandleRequest(clusterKindApprovalResolve, func(_ string, raw json.RawMessage) (any, bool) {
+		var d clusterApprovalDecision
+		if err := json.Unmarshal(raw, &d); err != nil {
+			slog.Debug("cluster: malformed approval decision", "error", err)
+			return nil, false
+		}
+		err := resolve(d)
+		switch {
+		case errors.Is(err, tools.ErrApprovalNotFound):
+			return nil, false // not held here
+		case errors.Is(err, tools.ErrApprovalForbidden):
+			return clusterResult{Forbidden: true}, true
+		case err != nil:
+			return clusterResult{Error: err.Error()}, true
+		}
+		return clusterResult{}, true
+	})
+	return func(ctx context.Context, d clusterApprovalDecision) error {
+		ctx, cancel := context.WithTimeout(ctx, clusterForwardTimeout)
+		defer cancel()
+		raw, err := node.Request(ctx, clusterKindApprovalResolve, d)
+		if errors.Is(err, cluster.ErrNoReply) {
+			return tools.ErrApprovalNotFound
+		}
+		if err != nil {
+			return err
+		}
+		var res clusterResult
+		if err := json.Unmarshal(raw, &res); err != nil {
+			return err
+		}
+		switch {
+		case res.Forbidden:
+			return tools.ErrApprovalForbidden
+		case res.Error != "":
+			return errors.New(res.Error)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/google/uuid"
+
+	"github.com/nextlevelbuilder/goclaw/internal/bus"
+	"github.com/nextlevelbuilder/goclaw/internal/store"
+	"github.com/nextlevelbuilder/goclaw/internal/tools"
+	"github.com/nextlevelbuilder/goclaw/internal/tts"
+)
+
+// loadTTSScopes reads tenant/agent TTS overrides from system_configs for every
+// tenant and applies them to the live TTS manager.
+func loadTTSScopes(sc store.SystemConfigStore, ts store.TenantStore, mgr *tts.Manager) {
+	if sc == nil || mgr == nil {
+		return
+	}
+	tenantIDs := []uuid.UUID{store.MasterTenantID}
+	if ts != nil {
+		if tenants, err := ts.ListTenants(context.Background()); err == nil && len(tenants) > 0 {
+			tenantIDs = tenantIDs[:0]
+			for _, t := range tenants {
+				tenantIDs = append(tenantIDs, t.ID)
+			}
+		}
+	}
+	for _, id := range tenantIDs {
+		reloadTTSScope(store.WithTenantID(context.Background(), id), sc, mgr)
+	}
+}
+
+// reloadTTSScope re-reads one tenant's TTS overrides (tenant from ctx).
+func reloadTTSScope(ctx context.Context, sc store.SystemConfigStore, mgr *tts.Manager) {
+	configs, err := sc.List(ctx)
+	if err != nil {
+		slog.Warn("tts: failed to load scope overrides", "tenant", store.TenantIDFromContext(ctx), "error", err)
+		return
+	}
+	tenant, agents := tts.ParseScopeConfigs(configs)
+	mgr.SetTenantScope(store.TenantIDFromContext(ctx).String(), tenant, agents)
+}
+
+// subscribeTTSScopeReload keeps tenant/agent TTS overrides in sync with system_configs.
+// tts.* RPC and the system configs HTTP API both broadcast TopicSystemConfigChanged
+// with a tenant-scoped context payload.
+func subscribeTTSScopeReload(msgBus *bus.MessageBus, sc store.SystemConfigStore, ttsTool *tools.TtsTool) {
+	if sc == nil || ttsTool == nil {
+		return
+	}
+	msgBus.Subscribe("tts-scope-reload", func(evt bus.Event) {
+		if evt.Name != bus.TopicSystemConfigChanged {
+			return
+		}
+		ctx, ok := evt.Payload.(context.Context)
+		if !ok {
+			ctx = store.WithTenantID(context.Background(), store.MasterTenantID)
+		}
+		reloadTTSScope(ctx, sc, ttsTool.Manager())
+	})
+}
@@ -83,10 +83,25 @@ func (l *Loop) runLoop(ctx context.Context, req RunRequest) (result *RunResult,
 	history := l.sessions.GetHistory(ctx, req.SessionKey)
 	summary := l.sessions.GetSummary(ctx, req.SessionKey)
 
+	// Structured output: tell every provider the contract; OpenAI-compatible
+	// providers additionally receive response_format natively.
+	if instr := req.ResponseFormat.Instruction(); instr != "" {
+		if req.ExtraSystemPrompt != "" {
+			req.ExtraSystemPrompt += "\n\n"
+		}
+		req.ExtraSystemPrompt += instr
+	}
+
 	// buildMessages resolves context files once and also detects BOOTSTRAP.md presence
 	// (hadBootstrap) — no extra DB roundtrip needed for bootstrap detection.
 	messages, hadBootstrap := l.buildMessages(ctx, history, summary, req.Message, req.ExtraSystemPrompt, req.SessionKey, req.Channel, req.ChannelType, req.ChatTitle, req.PeerKind, req.UserID, req.HistoryLimit, req.SkillFilter, req.LightContext)
 
+	// Resuming after client tool results: history already ends with the tool
+	// messages, so drop the empty user turn buildMessages appended.
+	if req.ResumeFromHistory && len(messages) > 0 && messages[len(messages)-1].Role == "user" && req.Message == "" {
+		messages = messages[:len(messages)-1]
+	}
+
 	// 1b–2f. Persist and enrich all incoming media (images, docs, audio, video).
 	ctx, messages, mediaRefs := l.enrichInputMedia(ctx, &req, messages)
 
@@ -99,7 +114,7 @@ func (l *Loop) runLoop(ctx context.Context, req RunRequest) (result *RunResult,
 	// Use enriched content (with media IDs and paths) from the messages array
 	// instead of raw req.Message, so historical messages retain full refs (RC-1 fix).
 	var initPendingMsgs []providers.Message
-	if !req.HideInput {
+	if !req.HideInput && !req.ResumeFromHistory {
 		enrichedContent := req.Message
 		if len(messages) > 0 {
 			for i := len(messages) - 1; i >= 0; i-- {
@@ -140,19 +155,6 @@ func (l *Loop) runLoop(ctx context.Context, req RunRequest) (result *RunResult,
 		maxIter = req.MaxIterations
 	}
 
-	// Budget check: query monthly spent once before starting iterations.
-	if l.budgetMonthlyCents > 0 && l.tracingStore != nil && l.agentUUID != uuid.Nil {
-		now := time.Now().UTC()
-		spent, err := l.tracingStore.GetMonthlyAgentCost(ctx, l.agentUUID, now.Year(), now.Month())
-		if err == nil {
-			spentCents := int(spent * 100)
-			if spentCents >= l.budgetMonthlyCents {
-				slog.Warn("agent budget exceeded", "agent", l.id, "spent_cents", spentCents, "budget_cents", l.budgetMonthlyCents)
-				return nil, fmt.Errorf("monthly budget exceeded ($%.2f / $%.2f)", spent, float64(l.budgetMonthlyCents)/100)
-			}
-		}
-	}
-
 	for rs.iteration < maxIter {
 		rs.iteration++
 
@@ -232,6 +234,7 @@ func (l *Loop) runLoop(ctx context.Context, req RunRequest) (result *RunResult,
 			l.getUserMCPTools(iterCtx, req.UserID)
 		}
 		toolDefs, allowedTools, messages = l.buildFilteredTools(&req, hadBootstrap, rs.iteration, maxIter, messages)
+		toolDefs = withClientTools(toolDefs, req.ClientTools, rs.iteration == maxIter)
 
 		// Use per-request overrides if set (e.g. heartbeat uses cheaper provider/model).
 		model := l.model
@@ -243,85 +246,51 @@ func (l *Loop) runLoop(ctx context.Context, req RunRequest) (result *RunResult,
 			provider = req.ProviderOverride
 		}
 
-		chatReq := providers.ChatRequest{
-			Messages: messages,
-			Tools:    toolDefs,
-			Model:    model,
-			Options: map[string]any{
-				providers.OptMaxTokens:   l.effectiveMaxTokens(),
-				providers.OptTemperature: config.DefaultTemperature,
-				providers.OptSessionKey:  req.SessionKey,
-				providers.OptAgentID:     l.agentUUID.String(),
-				providers.OptUserID:      req.UserID,
-				providers.OptChannel:     req.Channel,
-				providers.OptChatID:      req.ChatID,
-				providers.OptPeerKind:    req.PeerKind,
-				providers.OptWorkspace:   tools.ToolWorkspaceFromCtx(ctx),
-			},
+		options := map[string]any{
+			providers.OptMaxTokens:   l.effectiveMaxTokens(),
+			providers.OptTemperature: config.DefaultTemperature,
+			providers.OptSessionKey:  req.SessionKey,
+			providers.OptAgentID:     l.agentUUID.String(),
+			providers.OptUserID:      req.UserID,
+			providers.OptChannel:     req.Channel,
+			providers.OptChatID:      req.ChatID,
+			providers.OptPeerKind:    req.PeerKind,
+			providers.OptWorkspace:   tools.ToolWorkspaceFromCtx(ctx),
 		}
 		if tid := store.TenantIDFromContext(ctx); tid != uuid.Nil {
-			chatReq.Options[providers.OptTenantID] = tid.String()
-		}
-		reasoningDecision := providers.ResolveReasoningDecision(
-			provider,
-			model,
-			l.reasoningConfig.Effort,
-			l.reasoningConfig.Fallback,
-			l.reasoningConfig.Source,
-		)
-		if effort := reasoningDecision.RequestEffort(); effort != "" {
-			chatReq.Options[providers.OptThinkingLevel] = effort
-		}
-		if reasoningDecision.Reason != "" {
-			slog.Debug("reasoning normalized",
-				"provider", provider.Name(),
-				"model", model,
-				"requested", reasoningDecision.RequestedEffort,
-				"effective", reasoningDecision.EffectiveEffort,
-				"reason", reasoningDecision.Reason)
-		}
-
-		// Call LLM (streaming or non-streaming)
-		var resp *providers.ChatResponse
-		var err error
-
-		callCtx := providers.WithChatGPTOAuthRoutingObservation(ctx, providers.NewChatGPTOAuthRoutingObservation())
-		if reasoningDecision.HasObservation() {
-			callCtx = providers.WithReasoningDecision(callCtx, reasoningDecision)
-		}
-		llmSpanStart := time.Now().UTC()
-		llmSpanID := l.emitLLMSpanStart(callCtx, llmSpanStart, rs.iteration, messages)
-
-		if req.Stream {
-			resp, err = provider.ChatStream(callCtx, chatReq, func(chunk providers.StreamChunk) {
-				if chunk.Thinking != "" {
-					emitRun(AgentEvent{
-						Type:    protocol.ChatEventThinking,
-						AgentID: l.id,
-						RunID:   req.RunID,
-						Payload: map[string]string{"content": chunk.Thinking},
-					})
-				}
-				if chunk.Content != "" {
-					emitRun(AgentEvent{
-						Type:    protocol.ChatEventChunk,
-						AgentID: l.id,
-						RunID:   req.RunID,
-						Payload: map[string]string{"content": chunk.Content},
-					})
-				}
-			})
-		} else {
-			resp, err = provider.Chat(callCtx, chatReq)
+			options[providers.OptTenantID] = tid.String()
+		}
+		if req.ResponseFormat.Structured() {
+			options[providers.OptResponseFormat] = req.ResponseFormat
+		}
+		// Forced tool choice applies to the first call only — forcing it on
+		// every iteration would never let the model answer.
+		if req.ToolChoice != nil && rs.iteration == 1 && len(toolDefs) > 0 {
+			options[providers.OptToolChoice] = req.ToolChoice
 		}
 
+		// Budget check before every call: soft thresholds notify, hard
+		// thresholds refuse the run or downgrade to a cheaper model.
+		provider, model, err := l.applyBudget(ctx, &req, emitRun, provider, model)
+		if err != nil {
+			return nil, err
+		}
+
+		// Call LLM (streaming or non-streaming), walking the fallback chain on
+		// rate limits, server errors, context overflow and timeouts.
+		resp, err := l.chatWithFallback(ctx, llmCall{
+			req:       &req,
+			iteration: rs.iteration,
+			messages:  messages,
+			tools:     toolDefs,
+			options:   options,
+			emitRun:   emitRun,
+			spent:     &rs.costUSD,
+		}, provider, model)
 		if err != nil {
-			l.emitLLMSpanEnd(callCtx, llmSpanID, llmSpanStart, nil, err)
 			return nil, fmt.Errorf("LLM call failed (iteration %d): %w", rs.iteration, err)
 		}
 
-		l.emitLLMSpanEnd(callCtx, llmSpanID, llmSpanStart, resp, nil)
-
 		// For non-streaming responses, emit thinking and content as single events
 		if !req.Stream {
 			if resp.Thinking != "" {
@@ -460,7 +429,14 @@ func (l *Loop) runLoop(ctx context.Context, req RunRequest) (result *RunResult,
 				continue
 			}
 
-			rs.finalContent = resp.Content
+			// Structured output: ask for a corrected answer on schema mismatch.
+			content, retry := l.checkResponseFormat(rs, &req, resp.Content)
+			if retry != nil {
+				messages = append(messages, providers.Message{Role: "assistant", Content: resp.Content}, *retry)
+				continue
+			}
+
+			rs.finalContent = content
 			rs.finalThinking = resp.Thinking
 			break
 		}
@@ -522,6 +498,17 @@ func (l *Loop) runLoop(ctx context.Context, req RunRequest) (result *RunResult,
 			continue // one more LLM call for summarization, then loop exits (no tool calls)
 		}
 
+		// Client tools pause the run: the caller executes them and resumes with
+		// the results. Agent tools in the same batch still run first.
+		if clientCalls, serverCalls := splitClientToolCalls(req.ClientTools, resp.ToolCalls); len(clientCalls) > 0 {
+			rs.pendingToolCalls = clientCalls
+			rs.finalContent = resp.Content
+			resp.ToolCalls = serverCalls
+			if len(serverCalls) == 0 {
+				break
+			}
+		}
+
 		// Emit activity event: tool execution phase
 		if len(resp.ToolCalls) > 0 {
 			toolNames := make([]string, len(resp.ToolCalls))
@@ -585,6 +572,7 @@ func (l *Loop) runLoop(ctx context.Context, req RunRequest) (result *RunResult,
 			stopSlowTimer()
 
 			l.emitToolSpanEnd(ctx, toolSpanID, toolSpanStart, result)
+			l.observeToolMetrics(ctx, registryName, toolSpanStart, result)
 
 			// Record tool execution time for adaptive thresholds.
 			toolTiming.Record(tc.Name, time.Since(toolSpanStart).Milliseconds())
@@ -668,6 +656,7 @@ func (l *Loop) runLoop(ctx context.Context, req RunRequest) (result *RunResult,
 					}
 					stopSlowTimer()
 					l.emitToolSpanEnd(ctx, spanID, spanStart, result)
+					l.observeToolMetrics(ctx, registryName, spanStart, result)
 					resultCh <- indexedResult{idx: idx, tc: tc, registryName: registryName, result: result, argsJSON: string(argsJSON), spanStart: spanStart}
 				}(i, tc)
 			}
@@ -729,6 +718,10 @@ func (l *Loop) runLoop(ctx context.Context, req RunRequest) (result *RunResult,
 			}
 		}
 
+		if len(rs.pendingToolCalls) > 0 {
+			break
+		}
+
 		// Mid-run injection (Point A): drain any user follow-up messages
 		// that arrived during tool execution. Append them after tool results
 		// so the next LLM call sees: [tool results...] + [user follow-ups...].
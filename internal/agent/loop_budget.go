@@ -0,0 +1,86 @@
+package agent
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/google/uuid"
+
+	"github.com/nextlevelbuilder/goclaw/internal/budget"
+	"github.com/nextlevelbuilder/goclaw/internal/providers"
+	"github.com/nextlevelbuilder/goclaw/internal/store"
+	"github.com/nextlevelbuilder/goclaw/internal/tracing"
+	"github.com/nextlevelbuilder/goclaw/pkg/protocol"
+)
+
+// budgetSubject identifies who the current run's LLM calls are billed to.
+func (l *Loop) budgetSubject(ctx context.Context, req *RunRequest) budget.Subject {
+	tid := store.TenantIDFromContext(ctx)
+	if tid == uuid.Nil {
+		tid = l.tenantID
+	}
+	return budget.Subject{
+		TenantID:         tid,
+		AgentID:          l.agentUUID,
+		AgentKey:         l.id,
+		AgentBudgetCents: l.budgetMonthlyCents,
+		UserID:           req.UserID,
+	}
+}
+
+// applyBudget runs the pre-call budget check. It returns the provider/model to
+// use for this call — swapped for the configured cheaper model on a hard
+// "downgrade" threshold — or an error when the call must be refused.
+func (l *Loop) applyBudget(ctx context.Context, req *RunRequest, emitRun func(AgentEvent), provider providers.Provider, model string) (providers.Provider, string, error) {
+	if l.budget == nil {
+		return provider, model, nil
+	}
+	dec := l.budget.Check(ctx, l.budgetSubject(ctx, req))
+	switch dec.Action {
+	case budget.ActionRefuse:
+		slog.Warn("budget exceeded, refusing LLM call",
+			"agent", l.id, "scope", dec.Status.Scope, "key", dec.Status.Key,
+			"spent_usd", dec.Status.SpentUSD, "limit_usd", dec.Status.LimitUSD)
+		return nil, "", dec.Err()
+
+	case budget.ActionDowngrade:
+		name, target := budget.SplitModel(dec.DowngradeModel, func(name string) bool {
+			return l.providerLookup != nil && l.providerLookup(name) != nil
+		})
+		next := provider
+		if name != "" {
+			next = l.providerLookup(name)
+		}
+		if next.Name() == provider.Name() && target == model {
+			return provider, model, nil
+		}
+		slog.Info("budget exceeded, downgrading model",
+			"agent", l.id, "scope", dec.Status.Scope, "key", dec.Status.Key,
+			"from", provider.Name()+"/"+model, "to", next.Name()+"/"+target)
+		emitRun(AgentEvent{
+			Type:    protocol.AgentEventActivity,
+			AgentID: l.id,
+			RunID:   req.RunID,
+			Payload: map[string]any{
+				"phase":    "budget_downgrade",
+				"scope":    dec.Status.Scope,
+				"provider": next.Name(),
+				"model":    target,
+			},
+		})
+		return next, target, nil
+	}
+	return provider, model, nil
+}
+
+// recordSpend feeds a completed call's cost into the budget cache so limits
+// bite within a run, before the trace aggregates are written. Returns the
+// cost (0 when the model has no configured pricing).
+func (l *Loop) recordSpend(ctx context.Context, req *RunRequest, provider providers.Provider, model string, usage *providers.Usage) float64 {
+	pricing := tracing.LookupPricing(l.modelPricing, provider.Name(), model)
+	cost := tracing.CalculateCost(pricing, usage)
+	if l.budget != nil && cost > 0 {
+		l.budget.Record(l.budgetSubject(ctx, req), cost)
+	}
+	return cost
+}
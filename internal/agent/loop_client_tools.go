@@ -0,0 +1,153 @@
+package agent
+
+import (
+	"fmt"
+	"log/slog"
+
+	"github.com/nextlevelbuilder/goclaw/internal/providers"
+)
+
+// maxFormatRetries caps corrective turns when the final answer does not match
+// RunRequest.ResponseFormat.
+const maxFormatRetries = 2
+
+// withClientTools appends caller-defined tools to the per-iteration tool list.
+// A client tool shadows an agent tool of the same name: the caller asked for
+// that contract explicitly. Nothing is added on the final iteration, where all
+// tools are stripped to force a text answer.
+func withClientTools(defs, client []providers.ToolDefinition, finalIteration bool) []providers.ToolDefinition {
+	if len(client) == 0 || finalIteration {
+		return defs
+	}
+	shadowed := make(map[string]bool, len(client))
+	for _, td := range client {
+		shadowed[td.Function.Name] = true
+	}
+	out := make([]providers.ToolDefinition, 0, len(defs)+len(client))
+	for _, td := range defs {
+		if !shadowed[td.Function.Name] {
+			out = append(out, td)
+		}
+	}
+	return append(out, client...)
+}
+
+// splitClientToolCalls separates calls to caller-defined tools from calls the
+// agent executes itself.
+func splitClientToolCalls(client []providers.ToolDefinition, calls []providers.ToolCall) (clientCalls, serverCalls []providers.ToolCall) {
+	if len(client) == 0 {
+		return nil, calls
+	}
+	names := make(map[string]bool, len(client))
+	for _, td := range client {
+		names[td.Function.Name] = true
+	}
+	for _, tc := range calls {
+		if names[tc.Name] {
+			clientCalls = append(clientCalls, tc)
+		} else {
+			serverCalls = append(serverCalls, tc)
+		}
+	}
+	return clientCalls, serverCalls
+}
+
+// pausedTurn returns the model turn a run paused on when it mixed agent and
+// client tool calls: the assistant message carrying all calls, followed by
+// the results of the agent calls. The caller only sees the client calls, so
+// without this the agent results would be lost on resume. Returns nil when
+// the run did not pause or the turn had only client calls.
+func pausedTurn(msgs []providers.Message, clientCalls []providers.ToolCall) []providers.Message {
+	if len(clientCalls) == 0 {
+		return nil
+	}
+	pending := make(map[string]bool, len(clientCalls))
+	for _, tc := range clientCalls {
+		pending[tc.ID] = true
+	}
+	for i := len(msgs) - 1; i >= 0; i-- {
+		m := msgs[i]
+		if m.Role != "assistant" || len(m.ToolCalls) == 0 {
+			continue
+		}
+		if !hasToolCall(m.ToolCalls, clientCalls[0].ID) {
+			return nil
+		}
+		turn := []providers.Message{m}
+		for _, r := range msgs[i+1:] {
+			if r.Role == "tool" && !pending[r.ToolCallID] && hasToolCall(m.ToolCalls, r.ToolCallID) {
+				turn = append(turn, r)
+			}
+		}
+		if len(turn) == 1 {
+			return nil
+		}
+		return turn
+	}
+	return nil
+}
+
+// ReplayPausedTurn restores a RunResult.PausedTurn into a resumed
+// conversation. The caller echoes back only the client tool calls, so its
+// last assistant message is replaced by the original one (all calls) and the
+// agent tool results are inserted ahead of the client results. history is
+// returned unchanged when its last assistant message does not belong to turn.
+func ReplayPausedTurn(history, turn []providers.Message) []providers.Message {
+	if len(turn) == 0 {
+		return history
+	}
+	for i := len(history) - 1; i >= 0; i-- {
+		m := history[i]
+		if m.Role != "assistant" {
+			continue
+		}
+		if len(m.ToolCalls) == 0 {
+			return history
+		}
+		for _, tc := range m.ToolCalls {
+			if !hasToolCall(turn[0].ToolCalls, tc.ID) {
+				return history
+			}
+		}
+		out := make([]providers.Message, 0, len(history)+len(turn)-1)
+		out = append(out, history[:i]...)
+		out = append(out, turn...)
+		return append(out, history[i+1:]...)
+	}
+	return history
+}
+
+func hasToolCall(calls []providers.ToolCall, id string) bool {
+	for _, tc := range calls {
+		if tc.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// checkResponseFormat validates a final answer against req.ResponseFormat.
+// On success it returns the normalized content and nil. On a violation it
+// returns a corrective user message while retries remain; once they are
+// exhausted the violation is recorded on rs and the content is returned as-is.
+func (l *Loop) checkResponseFormat(rs *runState, req *RunRequest, content string) (string, *providers.Message) {
+	if !req.ResponseFormat.Structured() {
+		return content, nil
+	}
+	normalized, err := req.ResponseFormat.Validate(content)
+	if err == nil {
+		rs.formatErr = ""
+		return normalized, nil
+	}
+	rs.formatErr = err.Error()
+	if rs.formatRetries >= maxFormatRetries {
+		slog.Warn("agent: final answer violates response_format", "agent", l.id, "run", req.RunID, "error", err)
+		return content, nil
+	}
+	rs.formatRetries++
+	return content, &providers.Message{
+		Role: "user",
+		Content: fmt.Sprintf("[System] Your answer does not match the required response format: %s. "+
+			"Reply again with ONLY the corrected JSON — no prose, no code fences.", err),
+	}
+}
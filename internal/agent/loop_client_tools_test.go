@@ -0,0 +1,123 @@
+package agent
+
+import (
+	"testing"
+
+	"github.com/nextlevelbuilder/goclaw/internal/providers"
+)
+
+func toolDef(name string) providers.ToolDefinition {
+	return providers.ToolDefinition{Type: "function", Function: providers.ToolFunctionSchema{Name: name}}
+}
+
+func TestWithClientTools(t *testing.T) {
+	agentTools := []providers.ToolDefinition{toolDef("exec"), toolDef("search")}
+	client := []providers.ToolDefinition{toolDef("search"), toolDef("get_weather")}
+
+	got := withClientTools(agentTools, client, false)
+	var names []string
+	for _, td := range got {
+		names = append(names, td.Function.Name)
+	}
+	want := []string{"exec", "search", "get_weather"}
+	if len(names) != len(want) {
+		t.Fatalf("tools = %v, want %v", names, want)
+	}
+	for i := range want {
+		if names[i] != want[i] {
+			t.Fatalf("tools = %v, want %v", names, want)
+		}
+	}
+
+	if got := withClientTools(nil, client, true); len(got) != 0 {
+		t.Errorf("final iteration must not add client tools, got %d", len(got))
+	}
+}
+
+func TestSplitClientToolCalls(t *testing.T) {
+	client := []providers.ToolDefinition{toolDef("get_weather")}
+	calls := []providers.ToolCall{{ID: "1", Name: "exec"}, {ID: "2", Name: "get_weather"}}
+
+	clientCalls, serverCalls := splitClientToolCalls(client, calls)
+	if len(clientCalls) != 1 || clientCalls[0].ID != "2" {
+		t.Errorf("client calls = %+v", clientCalls)
+	}
+	if len(serverCalls) != 1 || serverCalls[0].ID != "1" {
+		t.Errorf("server calls = %+v", serverCalls)
+	}
+
+	if c, s := splitClientToolCalls(nil, calls); c != nil || len(s) != 2 {
+		t.Errorf("no client tools: client=%v server=%v", c, s)
+	}
+}
+
+func TestPausedTurn_MixedCallsReplayOnResume(t *testing.T) {
+	assistant := providers.Message{
+		Role:      "assistant",
+		Content:   "checking",
+		ToolCalls: []providers.ToolCall{{ID: "1", Name: "exec"}, {ID: "2", Name: "get_weather"}},
+	}
+	run := []providers.Message{
+		{Role: "user", Content: "weather and disk?"},
+		assistant,
+		{Role: "tool", ToolCallID: "1", Content: "42G free"},
+	}
+	clientCalls := []providers.ToolCall{{ID: "2", Name: "get_weather"}}
+
+	turn := pausedTurn(run, clientCalls)
+	if len(turn) != 2 || len(turn[0].ToolCalls) != 2 || turn[1].ToolCallID != "1" {
+		t.Fatalf("paused turn = %+v", turn)
+	}
+
+	// The client echoes only its own call and answers it.
+	resumed := []providers.Message{
+		{Role: "user", Content: "weather and disk?"},
+		{Role: "assistant", Content: "checking", ToolCalls: clientCalls},
+		{Role: "tool", ToolCallID: "2", Content: "31C"},
+	}
+	got := ReplayPausedTurn(resumed, turn)
+	if len(got) != 4 {
+		t.Fatalf("replayed history = %+v", got)
+	}
+	if len(got[1].ToolCalls) != 2 {
+		t.Errorf("assistant calls = %+v, want both", got[1].ToolCalls)
+	}
+	if got[2].ToolCallID != "1" || got[3].ToolCallID != "2" {
+		t.Errorf("tool results = %q, %q; want 1, 2", got[2].ToolCallID, got[3].ToolCallID)
+	}
+
+	// Client-only turns need nothing kept, and unrelated histories are left alone.
+	if turn := pausedTurn(run[:2], clientCalls); turn != nil {
+		t.Errorf("client-only turn = %+v, want nil", turn)
+	}
+	other := []providers.Message{{Role: "assistant", ToolCalls: []providers.ToolCall{{ID: "9"}}}}
+	if got := ReplayPausedTurn(other, turn); len(got) != 1 || len(got[0].ToolCalls) != 1 {
+		t.Errorf("unrelated history changed: %+v", got)
+	}
+}
+
+func TestCheckResponseFormat(t *testing.T) {
+	l := &Loop{id: "test"}
+	req := &RunRequest{ResponseFormat: &providers.ResponseFormat{Type: providers.ResponseFormatJSONObject}}
+	rs := &runState{}
+
+	for i := 0; i < maxFormatRetries; i++ {
+		if _, fix := l.checkResponseFormat(rs, req, "not json"); fix == nil {
+			t.Fatalf("attempt %d: expected a corrective message", i+1)
+		}
+	}
+	content, fix := l.checkResponseFormat(rs, req, "still not json")
+	if fix != nil || content != "still not json" || rs.formatErr == "" {
+		t.Fatalf("retries exhausted: content=%q fix=%v formatErr=%q", content, fix, rs.formatErr)
+	}
+
+	content, fix = l.checkResponseFormat(rs, req, "```json\n{\"ok\":true}\n```")
+	if fix != nil || content != `{"ok":true}` || rs.formatErr != "" {
+		t.Errorf("valid answer: content=%q fix=%v formatErr=%q", content, fix, rs.formatErr)
+	}
+
+	plain := &RunRequest{}
+	if content, fix := l.checkResponseFormat(&runState{}, plain, "prose"); fix != nil || content != "prose" {
+		t.Errorf("no format: content=%q fix=%v", content, fix)
+	}
+}
@@ -245,6 +245,7 @@ func (l *Loop) injectContext(ctx context.Context, req *RunRequest) (contextSetup
 		AgentType:           l.agentType,
 		SenderID:            req.SenderID,
 		SelfEvolve:          l.selfEvolve,
+		RequireFileApproval: l.requireFileApproval,
 		SharedMemory:        store.IsSharedMemory(ctx),
 		SharedKG:            store.IsSharedKG(ctx),
 		RestrictToWorkspace: l.restrictToWs != nil && *l.restrictToWs,
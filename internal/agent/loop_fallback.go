@@ -0,0 +1,185 @@
+package agent
+
+import (
+	"context"
+	"log/slog"
+	"maps"
+	"time"
+
+	"github.com/nextlevelbuilder/goclaw/internal/providers"
+	"github.com/nextlevelbuilder/goclaw/pkg/protocol"
+)
+
+// ModelFallback is a resolved hop of an agent's model fallback chain
+// (other_config.fallback_chain).
+type ModelFallback struct {
+	Provider providers.Provider
+	Model    string // empty = provider default
+}
+
+// llmCall is the per-iteration input shared by every hop of an LLM call.
+type llmCall struct {
+	req       *RunRequest
+	iteration int
+	messages  []providers.Message
+	tools     []providers.ToolDefinition
+	options   map[string]any // reasoning effort is resolved per hop, not stored here
+	emitRun   func(AgentEvent)
+	spent     *float64 // accumulates the USD cost of successful calls (nil = not tracked)
+}
+
+// chatWithFallback calls the primary provider and, on a fallback-eligible
+// failure (429, 5xx, context length, timeout), walks the agent's fallback
+// chain in order. Each attempt gets its own llm_call span; fallback hops carry
+// the reason in span metadata.
+//
+// A streamed response that already delivered chunks is never retried on
+// another hop — the user has seen partial output and replaying it would
+// duplicate content.
+func (l *Loop) chatWithFallback(ctx context.Context, call llmCall, provider providers.Provider, model string) (*providers.ChatResponse, error) {
+	resp, streamed, err := l.chatOnce(ctx, call, provider, model, nil, false)
+	if err == nil || len(l.fallbackChain) == 0 {
+		return resp, err
+	}
+
+	prevProvider, prevModel := provider, model
+	for i, hop := range l.fallbackChain {
+		reason := providers.ClassifyFallback(err)
+		if reason == "" || streamed || ctx.Err() != nil {
+			return nil, err
+		}
+		hopModel := hop.Model
+		if hopModel == "" {
+			hopModel = hop.Provider.DefaultModel()
+		}
+		if hop.Provider.Name() == prevProvider.Name() && hopModel == prevModel {
+			continue
+		}
+
+		info := providers.FallbackHop{
+			Hop:          i + 1,
+			Reason:       reason,
+			FromProvider: prevProvider.Name(),
+			FromModel:    prevModel,
+			Error:        err.Error(),
+		}
+		slog.Warn("llm fallback",
+			"agent", l.id,
+			"iteration", call.iteration,
+			"hop", info.Hop,
+			"reason", reason,
+			"from", prevProvider.Name()+"/"+prevModel,
+			"to", hop.Provider.Name()+"/"+hopModel,
+			"error", err)
+		call.emitRun(AgentEvent{
+			Type:    protocol.AgentEventActivity,
+			AgentID: l.id,
+			RunID:   call.req.RunID,
+			Payload: map[string]any{
+				"phase":     "fallback",
+				"iteration": call.iteration,
+				"provider":  hop.Provider.Name(),
+				"model":     hopModel,
+				"reason":    string(reason),
+			},
+		})
+
+		resp, streamed, err = l.chatOnce(ctx, call, hop.Provider, hopModel, &info, hop.Provider.Name() != provider.Name())
+		if err == nil {
+			return resp, nil
+		}
+		prevProvider, prevModel = hop.Provider, hopModel
+	}
+	return nil, err
+}
+
+// chatOnce performs a single LLM call against one provider/model, wrapped in
+// its own llm_call span. crossProvider strips provider-native message content
+// that the target cannot accept; if any was dropped, extended thinking is
+// turned off for this call because signed thinking blocks can't be rebuilt.
+func (l *Loop) chatOnce(ctx context.Context, call llmCall, provider providers.Provider, model string, hop *providers.FallbackHop, crossProvider bool) (*providers.ChatResponse, bool, error) {
+	messages := call.messages
+	effort := l.reasoningConfig.Effort
+	if crossProvider {
+		var dropped bool
+		messages, dropped = providers.AdaptMessagesForFallback(messages)
+		if dropped && effort != "off" {
+			effort = "off"
+			hop.ThinkingDisabled = true
+		}
+	}
+
+	chatReq := providers.ChatRequest{
+		Messages: messages,
+		Tools:    call.tools,
+		Model:    model,
+		Options:  maps.Clone(call.options),
+	}
+	reasoningDecision := providers.ResolveReasoningDecision(
+		provider,
+		model,
+		effort,
+		l.reasoningConfig.Fallback,
+		l.reasoningConfig.Source,
+	)
+	if effort := reasoningDecision.RequestEffort(); effort != "" {
+		chatReq.Options[providers.OptThinkingLevel] = effort
+	}
+	if reasoningDecision.Reason != "" {
+		slog.Debug("reasoning normalized",
+			"provider", provider.Name(),
+			"model", model,
+			"requested", reasoningDecision.RequestedEffort,
+			"effective", reasoningDecision.EffectiveEffort,
+			"reason", reasoningDecision.Reason)
+	}
+
+	callCtx := providers.WithChatGPTOAuthRoutingObservation(ctx, providers.NewChatGPTOAuthRoutingObservation())
+	if reasoningDecision.HasObservation() {
+		callCtx = providers.WithReasoningDecision(callCtx, reasoningDecision)
+	}
+	if hop != nil {
+		callCtx = providers.WithFallbackHop(callCtx, *hop)
+	}
+	llmSpanStart := time.Now().UTC()
+	llmSpanID := l.emitLLMSpanStart(callCtx, llmSpanStart, call.iteration, provider, model, messages)
+
+	var resp *providers.ChatResponse
+	var err error
+	streamed := false
+	if call.req.Stream {
+		resp, err = provider.ChatStream(callCtx, chatReq, func(chunk providers.StreamChunk) {
+			if chunk.Thinking != "" {
+				streamed = true
+				call.emitRun(AgentEvent{
+					Type:    protocol.ChatEventThinking,
+					AgentID: l.id,
+					RunID:   call.req.RunID,
+					Payload: map[string]string{"content": chunk.Thinking},
+				})
+			}
+			if chunk.Content != "" {
+				streamed = true
+				call.emitRun(AgentEvent{
+					Type:    protocol.ChatEventChunk,
+					AgentID: l.id,
+					RunID:   call.req.RunID,
+					Payload: map[string]string{"content": chunk.Content},
+				})
+			}
+		})
+	} else {
+		resp, err = provider.Chat(callCtx, chatReq)
+	}
+
+	observeLLMMetrics(ctx, provider.Name(), model, llmSpanStart, resp, err)
+	if err != nil {
+		l.emitLLMSpanEnd(callCtx, llmSpanID, llmSpanStart, provider, model, nil, err)
+		return nil, streamed, err
+	}
+	l.emitLLMSpanEnd(callCtx, llmSpanID, llmSpanStart, provider, model, resp, nil)
+	if cost := l.recordSpend(ctx, call.req, provider, model, resp.Usage); call.spent != nil {
+		*call.spent += cost
+	}
+	return resp, streamed, nil
+}
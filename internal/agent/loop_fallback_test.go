@@ -0,0 +1,82 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/nextlevelbuilder/goclaw/internal/providers"
+)
+
+type fallbackStubProvider struct {
+	name  string
+	err   error
+	calls []providers.ChatRequest
+}
+
+func (p *fallbackStubProvider) Chat(_ context.Context, req providers.ChatRequest) (*providers.ChatResponse, error) {
+	p.calls = append(p.calls, req)
+	if p.err != nil {
+		return nil, p.err
+	}
+	return &providers.ChatResponse{Content: p.name, FinishReason: "stop"}, nil
+}
+
+func (p *fallbackStubProvider) ChatStream(ctx context.Context, req providers.ChatRequest, _ func(providers.StreamChunk)) (*providers.ChatResponse, error) {
+	return p.Chat(ctx, req)
+}
+
+func (p *fallbackStubProvider) DefaultModel() string { return p.name + "-default" }
+func (p *fallbackStubProvider) Name() string         { return p.name }
+
+func newFallbackTestCall() llmCall {
+	return llmCall{
+		req:      &RunRequest{RunID: "run"},
+		messages: []providers.Message{{Role: "assistant", RawAssistantContent: json.RawMessage(`[]`)}},
+		options:  map[string]any{},
+		emitRun:  func(AgentEvent) {},
+	}
+}
+
+func TestChatWithFallbackWalksChainOnEligibleErrors(t *testing.T) {
+	primary := &fallbackStubProvider{name: "anthropic", err: &providers.HTTPError{Status: 429}}
+	second := &fallbackStubProvider{name: "openrouter", err: &providers.HTTPError{Status: 503}}
+	third := &fallbackStubProvider{name: "ollama"}
+	l := &Loop{
+		id:            "agent",
+		provider:      primary,
+		model:         "claude",
+		fallbackChain: []ModelFallback{{Provider: second, Model: "x"}, {Provider: third}},
+	}
+
+	resp, err := l.chatWithFallback(context.Background(), newFallbackTestCall(), primary, "claude")
+	if err != nil {
+		t.Fatalf("chatWithFallback() error = %v", err)
+	}
+	if resp.Content != "ollama" {
+		t.Fatalf("served by %q, want ollama", resp.Content)
+	}
+	if got := third.calls[0].Model; got != "ollama-default" {
+		t.Fatalf("hop model = %q, want provider default", got)
+	}
+	if third.calls[0].Messages[0].RawAssistantContent != nil {
+		t.Fatal("cross-provider hop kept provider-native content")
+	}
+}
+
+func TestChatWithFallbackStopsOnIneligibleError(t *testing.T) {
+	primary := &fallbackStubProvider{name: "anthropic", err: &providers.HTTPError{Status: 401}}
+	second := &fallbackStubProvider{name: "openrouter"}
+	l := &Loop{
+		id:            "agent",
+		provider:      primary,
+		fallbackChain: []ModelFallback{{Provider: second}},
+	}
+
+	if _, err := l.chatWithFallback(context.Background(), newFallbackTestCall(), primary, "claude"); err == nil {
+		t.Fatal("chatWithFallback() error = nil, want auth error")
+	}
+	if len(second.calls) != 0 {
+		t.Fatalf("fallback called %d times on auth error, want 0", len(second.calls))
+	}
+}
@@ -47,8 +47,13 @@ func (l *Loop) finalizeRun(
 	// 6. Handle NO_REPLY: save to session for context but mark as silent.
 	isSilent := IsSilentReply(rs.finalContent)
 
+	// Paused on client tool calls: the assistant message carrying the calls is
+	// already buffered, and the caller will resume the conversation.
+	paused := len(rs.pendingToolCalls) > 0
+
 	// 5b. Skill evolution: postscript suggestion after complex tasks.
-	if l.skillEvolve && l.skillNudgeInterval > 0 &&
+	// Never appended to structured output — it would break the JSON.
+	if l.skillEvolve && l.skillNudgeInterval > 0 && !paused && !req.ResponseFormat.Structured() &&
 		rs.totalToolCalls >= l.skillNudgeInterval &&
 		rs.finalContent != "" && !isSilent && !rs.skillPostscriptSent {
 		rs.skillPostscriptSent = true
@@ -57,7 +62,7 @@ func (l *Loop) finalizeRun(
 	}
 
 	// 7. Fallback for empty content
-	if rs.finalContent == "" {
+	if rs.finalContent == "" && !paused {
 		if len(rs.asyncToolCalls) > 0 {
 			rs.finalContent = "..."
 		} else {
@@ -111,7 +116,9 @@ func (l *Loop) finalizeRun(
 			Path:     mr.Path,
 		})
 	}
-	rs.pendingMsgs = append(rs.pendingMsgs, assistantMsg)
+	if !paused {
+		rs.pendingMsgs = append(rs.pendingMsgs, assistantMsg)
+	}
 
 	// Bootstrap nudge: if model didn't call write_file on turn 2+, inject reminder
 	// into session history so the next turn sees it.
@@ -200,10 +207,15 @@ func (l *Loop) finalizeRun(
 		RunID:          req.RunID,
 		Iterations:     rs.iteration,
 		Usage:          &rs.totalUsage,
+		CostUSD:        rs.costUSD,
 		Media:          rs.mediaResults,
 		Deliverables:   rs.deliverables,
 		BlockReplies:   rs.blockReplies,
 		LastBlockReply: rs.lastBlockReply,
 		LoopKilled:     rs.loopKilled,
+
+		PendingToolCalls:    rs.pendingToolCalls,
+		PausedTurn:          pausedTurn(rs.pendingMsgs, rs.pendingToolCalls),
+		ResponseFormatError: rs.formatErr,
 	}
 }
@@ -87,7 +87,9 @@ func (l *Loop) enrichInputMedia(ctx context.Context, req *RunRequest, messages [
 		l.enrichDocumentPaths(messages, mediaRefs)
 	}
 
-	// 2c. Collect audio MediaRefs (historical + current) for read_audio tool.
+	// 2c. Transcribe current-turn audio (gateway STT), then collect audio
+	// MediaRefs (historical + current) for read_audio tool.
+	l.transcribeAudioRefs(ctx, mediaRefs)
 	if audioRefs := collectRefsByKind(messages, mediaRefs, "audio"); len(audioRefs) > 0 {
 		ctx = tools.WithMediaAudioRefs(ctx, audioRefs)
 		l.enrichAudioIDs(messages, mediaRefs)
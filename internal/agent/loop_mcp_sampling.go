@@ -0,0 +1,77 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync/atomic"
+
+	mcpbridge "github.com/nextlevelbuilder/goclaw/internal/mcp"
+	"github.com/nextlevelbuilder/goclaw/internal/providers"
+	"github.com/nextlevelbuilder/goclaw/internal/store"
+)
+
+// MCPManager returns the agent's MCP manager for granted DB-backed servers,
+// or nil when the agent has none. Used for MCP prompts.
+func (l *Loop) MCPManager() *mcpbridge.Manager { return l.mcpManager }
+
+// mcpSampler answers MCP sampling requests with the agent's provider and
+// model, under the agent's budget. MCP servers connect before the Loop is
+// built, so the loop is bound afterwards; requests before that are refused.
+type mcpSampler struct {
+	loop atomic.Pointer[Loop]
+}
+
+func (s *mcpSampler) Sample(ctx context.Context, req mcpbridge.SampleRequest) (*mcpbridge.SampleResult, error) {
+	l := s.loop.Load()
+	if l == nil || l.provider == nil {
+		return nil, fmt.Errorf("agent is not ready for sampling")
+	}
+
+	run := &RunRequest{UserID: store.UserIDFromContext(ctx)}
+	model := l.model
+	if req.Model != "" {
+		model = req.Model
+	}
+	provider, model, err := l.applyBudget(ctx, run, func(AgentEvent) {}, l.provider, model)
+	if err != nil {
+		return nil, err
+	}
+
+	msgs := make([]providers.Message, 0, len(req.Messages)+1)
+	if req.SystemPrompt != "" {
+		msgs = append(msgs, providers.Message{Role: "system", Content: req.SystemPrompt})
+	}
+	for _, m := range req.Messages {
+		msgs = append(msgs, providers.Message{Role: m.Role, Content: m.Content})
+	}
+	opts := map[string]any{providers.OptMaxTokens: req.MaxTokens}
+	if req.Temperature > 0 {
+		opts[providers.OptTemperature] = req.Temperature
+	}
+
+	resp, err := provider.Chat(ctx, providers.ChatRequest{Messages: msgs, Model: model, Options: opts})
+	if err != nil {
+		return nil, fmt.Errorf("sampling call: %w", err)
+	}
+	l.recordSpend(ctx, run, provider, model, resp.Usage)
+
+	slog.Debug("mcp.sampling.answered", "agent", l.id, "server", req.Server, "model", model)
+	return &mcpbridge.SampleResult{
+		Content:    resp.Content,
+		Model:      model,
+		StopReason: samplingStopReason(resp.FinishReason),
+	}, nil
+}
+
+// samplingStopReason maps provider finish reasons to MCP stop reasons.
+func samplingStopReason(finish string) string {
+	switch finish {
+	case "length":
+		return "maxTokens"
+	case "stop", "":
+		return "endTurn"
+	default:
+		return finish
+	}
+}
@@ -2,17 +2,24 @@ package agent
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"log/slog"
 	"maps"
 
+	mcpclient "github.com/mark3labs/mcp-go/client"
+
 	mcpbridge "github.com/nextlevelbuilder/goclaw/internal/mcp"
+	"github.com/nextlevelbuilder/goclaw/internal/providers"
+	"github.com/nextlevelbuilder/goclaw/internal/store"
 	"github.com/nextlevelbuilder/goclaw/internal/tools"
 )
 
 // getUserMCPTools returns per-user MCP tools for servers requiring user credentials.
 // Tools are cached per-user in mcpUserTools sync.Map and registered in the shared
-// tool registry so ExecuteWithContext can resolve them. On first call for a user,
-// connections are established via pool.AcquireUser() and BridgeTools created.
+// tool registry (as userMCPTool dispatchers) so ExecuteWithContext can resolve them.
+// On first call for a user, connections are established via pool.AcquireUser() and
+// BridgeTools created.
 func (l *Loop) getUserMCPTools(ctx context.Context, userID string) []tools.Tool {
 	if len(l.mcpUserCredSrvs) == 0 || l.mcpPool == nil || l.mcpStore == nil || userID == "" {
 		return nil
@@ -20,8 +27,8 @@ func (l *Loop) getUserMCPTools(ctx context.Context, userID string) []tools.Tool
 
 	if cached, ok := l.mcpUserTools.Load(userID); ok {
 		cachedTools := cached.([]tools.Tool)
-		// Check if any cached tool's connection was evicted by pool.
-		// If so, clear cache and re-acquire connections.
+		// Check if any cached tool's connection was evicted by pool (or an
+		// OAuth server was authorized since). If so, clear cache and re-acquire.
 		allConnected := true
 		for _, t := range cachedTools {
 			if bt, ok := t.(interface{ IsConnected() bool }); ok && !bt.IsConnected() {
@@ -40,6 +47,11 @@ func (l *Loop) getUserMCPTools(ctx context.Context, userID string) []tools.Tool
 	for _, info := range l.mcpUserCredSrvs {
 		srv := info.Server
 
+		if mcpbridge.ParseOAuthSettings(srv.Settings).Enabled {
+			userTools = append(userTools, l.oauthUserMCPTools(ctx, &srv, userID)...)
+			continue
+		}
+
 		// Check if user has credentials for this server
 		uc, err := l.mcpStore.GetUserCredentials(ctx, srv.ID, userID)
 		if err != nil || uc == nil || (uc.APIKey == "" && len(uc.Headers) == 0 && len(uc.Env) == 0) {
@@ -71,7 +83,7 @@ func (l *Loop) getUserMCPTools(ctx context.Context, userID string) []tools.Tool
 
 		// Acquire user-keyed pool connection
 		entry, err := l.mcpPool.AcquireUser(ctx, l.tenantID, srv.Name, userID,
-			srv.Transport, srv.Command, args, env, srv.URL, headers, srv.TimeoutSec)
+			srv.Transport, srv.Command, args, env, srv.URL, headers, srv.TimeoutSec, nil)
 		if err != nil {
 			slog.Warn("mcp.user_pool_acquire_failed", "server", srv.Name, "user", userID, "error", err)
 			continue
@@ -82,25 +94,117 @@ func (l *Loop) getUserMCPTools(ctx context.Context, userID string) []tools.Tool
 		// When pool evicts the connection, BridgeTool.Execute detects connected=false.
 		l.mcpPool.ReleaseUser(mcpbridge.UserPoolKey(l.tenantID, srv.Name, userID))
 
-		// Create BridgeTools pointing to user's connection and register in the
-		// shared tool registry so ExecuteWithContext can resolve them by name.
-		reg, _ := l.tools.(*tools.Registry)
+		// Create BridgeTools pointing to user's connection.
 		for _, mcpTool := range entry.MCPTools() {
-			bt := mcpbridge.NewBridgeTool(srv.Name, mcpTool, entry.Client(), srv.ToolPrefix, srv.TimeoutSec, entry.Connected())
-			// Register in registry so ExecuteWithContext can find them.
-			// Skip if already registered (another user loaded this server with same tool names).
-			if reg != nil {
-				if _, exists := reg.Get(bt.Name()); !exists {
-					reg.Register(bt)
-				}
-			}
-			userTools = append(userTools, bt)
+			userTools = append(userTools, mcpbridge.NewBridgeTool(srv.Name, mcpTool, entry.Client(), srv.ToolPrefix, srv.TimeoutSec, entry.Connected()))
 		}
 	}
 
+	// Register in registry so ExecuteWithContext can find them.
+	for _, t := range userTools {
+		l.registerUserMCPTool(t)
+	}
+
 	if len(userTools) > 0 {
 		l.mcpUserTools.Store(userID, userTools)
 		slog.Info("mcp.user_tools_loaded", "user", userID, "tools", len(userTools))
 	}
 	return userTools
 }
+
+// oauthUserMCPTools connects a user to an OAuth server with their own token.
+// Until the user has authorized the server, its only tool is the authorize
+// tool, which sends them the consent link.
+func (l *Loop) oauthUserMCPTools(ctx context.Context, srv *store.MCPServerData, userID string) []tools.Tool {
+	flows := l.mcpPool.OAuth()
+	if flows == nil {
+		return nil
+	}
+
+	cfg, err := flows.TransportConfig(ctx, srv, userID)
+	if err == nil {
+		args := mcpbridge.ParseJSONBytesToStringSlice(srv.Args)
+		headers := mcpbridge.ParseJSONBytesToStringMap(srv.Headers)
+		entry, acquireErr := l.mcpPool.AcquireUser(ctx, l.tenantID, srv.Name, userID,
+			srv.Transport, srv.Command, args, nil, srv.URL, headers, srv.TimeoutSec, cfg)
+		if acquireErr == nil {
+			l.mcpPool.ReleaseUser(mcpbridge.UserPoolKey(l.tenantID, srv.Name, userID))
+			var out []tools.Tool
+			for _, mcpTool := range entry.MCPTools() {
+				bt := mcpbridge.NewBridgeTool(srv.Name, mcpTool, entry.Client(), srv.ToolPrefix, srv.TimeoutSec, entry.Connected())
+				bt.SetOAuth(flows, srv)
+				out = append(out, bt)
+			}
+			return out
+		}
+		err = acquireErr
+	}
+
+	if errors.Is(err, mcpbridge.ErrOAuthRequired) || mcpclient.IsOAuthAuthorizationRequiredError(err) {
+		return []tools.Tool{mcpbridge.NewAuthorizeTool(flows, srv, l.tenantID, userID)}
+	}
+	slog.Warn("mcp.user_pool_acquire_failed", "server", srv.Name, "user", userID, "error", err)
+	return nil
+}
+
+// userMCPTool is the shared-registry entry for a per-user MCP tool name. All
+// users of the agent share one registry, so calls are dispatched to the
+// calling user's own tool (and connection) rather than whichever user loaded
+// the name first.
+type userMCPTool struct {
+	tools.Tool // definition of the first user's tool with this name
+	loop       *Loop
+}
+
+func (t *userMCPTool) Execute(ctx context.Context, args map[string]any) *tools.Result {
+	if ut := t.loop.userMCPTool(store.UserIDFromContext(ctx), t.Name()); ut != nil {
+		return ut.Execute(ctx, args)
+	}
+	return tools.ErrorResult(fmt.Sprintf("tool %q is not available for this user", t.Name()))
+}
+
+// registerUserMCPTool registers the dispatcher for a per-user tool name,
+// unless a regular tool already uses the name.
+func (l *Loop) registerUserMCPTool(t tools.Tool) {
+	reg, ok := l.tools.(*tools.Registry)
+	if !ok {
+		return
+	}
+	if existing, exists := reg.Get(t.Name()); exists {
+		if _, isUser := existing.(*userMCPTool); !isUser {
+			slog.Warn("mcp.tool.name_collision", "tool", t.Name(), "action", "skipped")
+		}
+		return
+	}
+	reg.Register(&userMCPTool{Tool: t, loop: l})
+}
+
+// userMCPTool returns the user's cached per-user MCP tool with the given name.
+func (l *Loop) userMCPTool(userID, name string) tools.Tool {
+	cached, ok := l.mcpUserTools.Load(userID)
+	if !ok {
+		return nil
+	}
+	for _, t := range cached.([]tools.Tool) {
+		if t.Name() == name {
+			return t
+		}
+	}
+	return nil
+}
+
+// hideOtherUsersMCPTools drops per-user MCP tool definitions that the given
+// user has not loaded (other users' servers, or authorize tools already used).
+func (l *Loop) hideOtherUsersMCPTools(defs []providers.ToolDefinition, allowed map[string]bool, userID string) []providers.ToolDefinition {
+	filtered := defs[:0:0]
+	for _, td := range defs {
+		if t, ok := l.tools.Get(td.Function.Name); ok {
+			if _, isUser := t.(*userMCPTool); isUser && l.userMCPTool(userID, td.Function.Name) == nil {
+				delete(allowed, td.Function.Name)
+				continue
+			}
+		}
+		filtered = append(filtered, td)
+	}
+	return filtered
+}
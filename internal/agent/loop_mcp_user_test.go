@@ -0,0 +1,58 @@
+package agent
+
+import (
+	"context"
+	"testing"
+
+	"github.com/nextlevelbuilder/goclaw/internal/providers"
+	"github.com/nextlevelbuilder/goclaw/internal/store"
+	"github.com/nextlevelbuilder/goclaw/internal/tools"
+)
+
+func TestUserMCPTool_DispatchesToCallingUser(t *testing.T) {
+	reg := tools.NewRegistry()
+	l := &Loop{tools: reg}
+
+	alice := &mockExecTool{name: "mcp_docs__search"}
+	bob := &mockExecTool{name: "mcp_docs__search"}
+	l.mcpUserTools.Store("alice", []tools.Tool{alice})
+	l.mcpUserTools.Store("bob", []tools.Tool{bob})
+	l.registerUserMCPTool(alice)
+	l.registerUserMCPTool(bob) // same name: the dispatcher is shared
+
+	ctx := store.WithUserID(context.Background(), "bob")
+	if res := reg.ExecuteWithContext(ctx, "mcp_docs__search", nil, "", "", "", "", nil); res.IsError {
+		t.Fatalf("bob's call failed: %s", res.ForLLM)
+	}
+	if alice.executed || !bob.executed {
+		t.Errorf("executed alice=%v bob=%v, want only bob's tool", alice.executed, bob.executed)
+	}
+
+	ctx = store.WithUserID(context.Background(), "mallory")
+	if res := reg.ExecuteWithContext(ctx, "mcp_docs__search", nil, "", "", "", "", nil); !res.IsError {
+		t.Error("user without the server reached another user's tool")
+	}
+}
+
+func TestHideOtherUsersMCPTools(t *testing.T) {
+	reg := tools.NewRegistry()
+	reg.Register(&mockExecTool{name: "read_file"})
+	l := &Loop{tools: reg}
+
+	l.mcpUserTools.Store("alice", []tools.Tool{&mockExecTool{name: "mcp_docs__search"}})
+	l.registerUserMCPTool(&mockExecTool{name: "mcp_docs__search"})
+
+	defs := []providers.ToolDefinition{
+		{Function: providers.ToolFunctionSchema{Name: "read_file"}},
+		{Function: providers.ToolFunctionSchema{Name: "mcp_docs__search"}},
+	}
+	allowed := map[string]bool{"read_file": true, "mcp_docs__search": true}
+
+	if got := l.hideOtherUsersMCPTools(defs, allowed, "alice"); len(got) != 2 {
+		t.Errorf("alice sees %d tools, want 2", len(got))
+	}
+	got := l.hideOtherUsersMCPTools(defs, allowed, "bob")
+	if len(got) != 1 || got[0].Function.Name != "read_file" || allowed["mcp_docs__search"] {
+		t.Errorf("bob sees %+v (allowed %v), want only read_file", got, allowed)
+	}
+}
@@ -0,0 +1,34 @@
+package agent
+
+import (
+	"context"
+	"time"
+
+	"github.com/nextlevelbuilder/goclaw/internal/metrics"
+	"github.com/nextlevelbuilder/goclaw/internal/providers"
+	"github.com/nextlevelbuilder/goclaw/internal/store"
+	"github.com/nextlevelbuilder/goclaw/internal/tools"
+)
+
+// observeLLMMetrics records latency, outcome and token usage of one provider
+// call. Unlike LLM spans it runs whether or not tracing is enabled.
+func observeLLMMetrics(ctx context.Context, provider, model string, start time.Time, resp *providers.ChatResponse, callErr error) {
+	var u providers.Usage
+	if resp != nil && resp.Usage != nil {
+		u = *resp.Usage
+	}
+	metrics.ObserveLLMCall(metrics.TenantLabel(store.TenantIDFromContext(ctx)), provider, model,
+		time.Since(start), callErr != nil,
+		u.PromptTokens, u.CompletionTokens, u.CacheReadTokens, u.CacheCreationTokens, u.ThinkingTokens)
+}
+
+// observeToolMetrics records one tool execution. Names the LLM invented (not
+// in the registry) are folded into "unknown" to keep label cardinality bounded.
+func (l *Loop) observeToolMetrics(ctx context.Context, registryName string, start time.Time, result *tools.Result) {
+	name := registryName
+	if _, ok := l.tools.Get(registryName); !ok {
+		name = "unknown"
+	}
+	failed := result == nil || result.IsError
+	metrics.ObserveToolCall(metrics.TenantLabel(store.TenantIDFromContext(ctx)), name, time.Since(start), failed)
+}
@@ -131,6 +131,9 @@ func (l *Loop) Run(ctx context.Context, req RunRequest) (*RunResult, error) {
 	}
 
 	result, err := l.runLoop(ctx, req)
+	if result != nil && tracing.TraceIDFromContext(ctx) != uuid.Nil {
+		result.TraceID = traceID
+	}
 
 	// Finalize the root agent span. Uses EmitSpanUpdate (channel send) so it
 	// succeeds even if ctx is cancelled. Must run before FinishTrace so
@@ -41,6 +41,9 @@ func (l *Loop) buildFilteredTools(req *RunRequest, hadBootstrap bool, iteration,
 		toolDefs = filtered
 	}
 
+	// Per-user MCP tools share the registry: hide names the current user hasn't loaded.
+	toolDefs = l.hideOtherUsersMCPTools(toolDefs, allowedTools, req.UserID)
+
 	// Bootstrap mode: restrict API tool definitions to write_file only (open agents).
 	// Predefined agents keep all tools — BOOTSTRAP.md guides behavior.
 	if hadBootstrap && l.agentType != store.AgentTypePredefined {
@@ -36,9 +36,10 @@ func (l *Loop) IsRunning() bool { return l.activeRuns.Load() > 0 }
 // ---------------------------------------------------------------------------
 
 // emitLLMSpanStart emits a "running" LLM span before the LLM call begins.
+// provider/model are the ones actually called (override or fallback hop).
 // Returns the span ID so the caller can later call emitLLMSpanEnd to finalize it.
 // Goroutine-safe: only reads immutable Loop fields and does a channel send.
-func (l *Loop) emitLLMSpanStart(ctx context.Context, start time.Time, iteration int, messages []providers.Message) uuid.UUID {
+func (l *Loop) emitLLMSpanStart(ctx context.Context, start time.Time, iteration int, provider providers.Provider, model string, messages []providers.Message) uuid.UUID {
 	collector := tracing.CollectorFromContext(ctx)
 	traceID := tracing.TraceIDFromContext(ctx)
 	if collector == nil || traceID == uuid.Nil {
@@ -50,12 +51,12 @@ func (l *Loop) emitLLMSpanStart(ctx context.Context, start time.Time, iteration
 		ID:        spanID,
 		TraceID:   traceID,
 		SpanType:  store.SpanTypeLLMCall,
-		Name:      fmt.Sprintf("%s/%s #%d", l.provider.Name(), l.model, iteration),
+		Name:      fmt.Sprintf("%s/%s #%d", provider.Name(), model, iteration),
 		StartTime: start,
 		Status:    store.SpanStatusRunning,
 		Level:     store.SpanLevelDefault,
-		Model:     l.model,
-		Provider:  l.provider.Name(),
+		Model:     model,
+		Provider:  provider.Name(),
 		CreatedAt: start,
 	}
 	if parentID := tracing.ParentSpanIDFromContext(ctx); parentID != uuid.Nil {
@@ -69,6 +70,9 @@ func (l *Loop) emitLLMSpanStart(ctx context.Context, start time.Time, iteration
 	if span.TenantID == uuid.Nil {
 		span.TenantID = store.MasterTenantID
 	}
+	if hop := providers.FallbackHopFromContext(ctx); hop != nil {
+		span.Metadata = providers.MergeFallbackMetadata(nil, *hop)
+	}
 
 	// Include input messages preview as truncated JSON.
 	if len(messages) > 0 {
@@ -96,7 +100,7 @@ func (l *Loop) emitLLMSpanStart(ctx context.Context, start time.Time, iteration
 // emitLLMSpanEnd finalizes a running LLM span with results.
 // Uses EmitSpanUpdate (channel send) — does NOT depend on ctx being alive,
 // so it works correctly even after ctx cancellation or deadline exceeded.
-func (l *Loop) emitLLMSpanEnd(ctx context.Context, spanID uuid.UUID, start time.Time, resp *providers.ChatResponse, callErr error) {
+func (l *Loop) emitLLMSpanEnd(ctx context.Context, spanID uuid.UUID, start time.Time, provider providers.Provider, model string, resp *providers.ChatResponse, callErr error) {
 	if spanID == uuid.Nil {
 		return // tracing disabled — no running span was emitted
 	}
@@ -139,7 +143,7 @@ func (l *Loop) emitLLMSpanEnd(ctx context.Context, spanID uuid.UUID, start time.
 			}
 		}
 		// Calculate cost if pricing config is available.
-		if pricing := tracing.LookupPricing(l.modelPricing, l.provider.Name(), l.model); pricing != nil {
+		if pricing := tracing.LookupPricing(l.modelPricing, provider.Name(), model); pricing != nil {
 			cost := tracing.CalculateCost(pricing, resp.Usage)
 			if cost > 0 {
 				updates["total_cost"] = cost
@@ -165,6 +169,10 @@ func (l *Loop) emitLLMSpanEnd(ctx context.Context, spanID uuid.UUID, start time.
 	if decision := providers.ReasoningDecisionFromContext(ctx); decision != nil {
 		spanMetadata = providers.MergeReasoningMetadata(spanMetadata, *decision)
 	}
+	// Metadata updates replace the column, so re-apply the fallback hop set at start.
+	if hop := providers.FallbackHopFromContext(ctx); hop != nil {
+		spanMetadata = providers.MergeFallbackMetadata(spanMetadata, *hop)
+	}
 	if len(spanMetadata) > 0 {
 		updates["metadata"] = spanMetadata
 	}
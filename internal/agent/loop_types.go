@@ -8,6 +8,7 @@ import (
 	"github.com/google/uuid"
 
 	"github.com/nextlevelbuilder/goclaw/internal/bootstrap"
+	"github.com/nextlevelbuilder/goclaw/internal/budget"
 	"github.com/nextlevelbuilder/goclaw/internal/bus"
 	"github.com/nextlevelbuilder/goclaw/internal/config"
 	mcpbridge "github.com/nextlevelbuilder/goclaw/internal/mcp"
@@ -16,6 +17,7 @@ import (
 	"github.com/nextlevelbuilder/goclaw/internal/sandbox"
 	"github.com/nextlevelbuilder/goclaw/internal/skills"
 	"github.com/nextlevelbuilder/goclaw/internal/store"
+	"github.com/nextlevelbuilder/goclaw/internal/stt"
 	"github.com/nextlevelbuilder/goclaw/internal/tools"
 	"github.com/nextlevelbuilder/goclaw/internal/tracing"
 )
@@ -71,6 +73,7 @@ type Loop struct {
 	agentType        string    // "open" or "predefined"
 	provider         providers.Provider
 	model            string
+	fallbackChain    []ModelFallback // tried in order when the primary fails with a fallback-eligible error
 	contextWindow    int
 	maxTokens        int // max output tokens per LLM call (0 = default 8192)
 	maxIterations    int
@@ -116,6 +119,7 @@ type Loop struct {
 	mcpPool         *mcpbridge.Pool       // user-keyed connection pool
 	mcpUserCredSrvs []store.MCPAccessInfo // servers needing per-user creds
 	mcpUserTools    sync.Map              // userID → []tools.Tool (cached per-user tools)
+	mcpManager      *mcpbridge.Manager    // granted DB-backed servers (prompts, resources)
 
 	// Compaction config (memory flush settings)
 	compactionCfg *config.CompactionConfig
@@ -154,6 +158,9 @@ type Loop struct {
 	// Self-evolve: predefined agents can update SOUL.md through chat
 	selfEvolve bool
 
+	// Agent edits to SOUL.md / IDENTITY.md / AGENTS.md become pending changes
+	requireFileApproval bool
+
 	// Skill learning loop: when skillEvolve=true, the loop injects nudges reminding
 	// the agent to capture reusable patterns as skills via skill_manage.
 	skillEvolve        bool
@@ -171,12 +178,17 @@ type Loop struct {
 	// Persistent media storage for cross-turn image/document access
 	mediaStore *media.Store
 
+	// Speech-to-text for inbound audio (nil or no providers = skip)
+	stt *stt.Manager
+
 	// Model pricing config for cost tracking (nil = no cost calculation)
 	modelPricing map[string]*config.ModelPricing
 
-	// Budget enforcement: monthly spending limit in cents (0 = unlimited)
+	// Budget enforcement: agent monthly limit in cents (0 = unlimited) plus
+	// configured tenant/user budgets, checked before every LLM call.
 	budgetMonthlyCents int
-	tracingStore       store.TracingStore
+	budget             *budget.Checker
+	providerLookup     func(name string) providers.Provider // resolves budget downgrade targets
 
 	// Memory store for extractive memory fallback (writes directly when LLM flush fails)
 	memStore store.MemoryStore
@@ -211,6 +223,7 @@ type LoopConfig struct {
 	ID               string
 	Provider         providers.Provider
 	Model            string
+	FallbackChain    []ModelFallback // ordered fallback hops (nil = no fallback)
 	ContextWindow    int
 	MaxTokens        int // max output tokens per LLM call (0 = default 8192)
 	MaxIterations    int
@@ -286,6 +299,9 @@ type LoopConfig struct {
 	// Self-evolve: predefined agents can update SOUL.md (style/tone) through chat
 	SelfEvolve bool
 
+	// Agent edits to identity files are held for owner approval
+	RequireFileApproval bool
+
 	// Skill evolution: agent learning loop config (from other_config JSONB)
 	SkillEvolve        bool
 	SkillNudgeInterval int // 0 = disabled, 15 = default
@@ -302,12 +318,16 @@ type LoopConfig struct {
 	// Persistent media storage for cross-turn image/document access
 	MediaStore *media.Store
 
+	// Speech-to-text for inbound audio not already transcribed by the channel
+	STT *stt.Manager
+
 	// Model pricing for cost tracking (key = "provider/model" or "model")
 	ModelPricing map[string]*config.ModelPricing
 
-	// Budget enforcement
+	// Budget enforcement (nil Budget = no enforcement)
 	BudgetMonthlyCents int
-	TracingStore       store.TracingStore
+	Budget             *budget.Checker
+	ProviderLookup     func(name string) providers.Provider // nil result = unknown provider
 
 	// Memory store for extractive memory fallback (writes directly when LLM flush fails)
 	MemoryStore store.MemoryStore
@@ -316,6 +336,7 @@ type LoopConfig struct {
 	MCPStore        store.MCPServerStore  // for credential lookup
 	MCPPool         *mcpbridge.Pool       // user-keyed connection pool
 	MCPUserCredSrvs []store.MCPAccessInfo // servers needing per-user creds
+	MCPManager      *mcpbridge.Manager    // per-agent manager for granted servers
 }
 
 const defaultMaxTokens = config.DefaultMaxTokens
@@ -358,6 +379,7 @@ func NewLoop(cfg LoopConfig) *Loop {
 		agentType:              cfg.AgentType,
 		provider:               cfg.Provider,
 		model:                  cfg.Model,
+		fallbackChain:          cfg.FallbackChain,
 		contextWindow:          cfg.ContextWindow,
 		maxTokens:              cfg.MaxTokens,
 		maxIterations:          cfg.MaxIterations,
@@ -400,19 +422,23 @@ func NewLoop(cfg LoopConfig) *Loop {
 		disabledTools:          cfg.DisabledTools,
 		reasoningConfig:        cfg.ReasoningConfig,
 		selfEvolve:             cfg.SelfEvolve,
+		requireFileApproval:    cfg.RequireFileApproval,
 		skillEvolve:            cfg.SkillEvolve,
 		skillNudgeInterval:     cfg.SkillNudgeInterval,
 		configPermStore:        cfg.ConfigPermStore,
 		teamStore:              cfg.TeamStore,
 		secureCLIStore:         cfg.SecureCLIStore,
 		mediaStore:             cfg.MediaStore,
+		stt:                    cfg.STT,
 		modelPricing:           cfg.ModelPricing,
 		budgetMonthlyCents:     cfg.BudgetMonthlyCents,
-		tracingStore:           cfg.TracingStore,
+		budget:                 cfg.Budget,
+		providerLookup:         cfg.ProviderLookup,
 		memStore:               cfg.MemoryStore,
 		mcpStore:               cfg.MCPStore,
 		mcpPool:                cfg.MCPPool,
 		mcpUserCredSrvs:        cfg.MCPUserCredSrvs,
+		mcpManager:             cfg.MCPManager,
 	}
 }
 
@@ -446,6 +472,12 @@ type RunRequest struct {
 	ProviderOverride  providers.Provider // per-request provider override (heartbeat uses different provider)
 	LightContext      bool               // skip loading context files (only inject ExtraSystemPrompt)
 
+	// OpenAI-compatible API callers (/v1/chat/completions).
+	ClientTools       []providers.ToolDefinition // caller-executed tools: calling one pauses the run and returns the call
+	ToolChoice        any                        // OpenAI tool_choice for the first LLM call (nil = auto)
+	ResponseFormat    *providers.ResponseFormat  // structured output: final answer is validated and retried on mismatch
+	ResumeFromHistory bool                       // history already ends with tool results: continue without a new user message
+
 	// Run classification
 	RunKind       string // "delegation", "announce" — empty for user-initiated runs
 	HideInput     bool   // don't persist input message in session history (announce runs)
@@ -477,11 +509,25 @@ type RunResult struct {
 	RunID          string           `json:"runId"`
 	Iterations     int              `json:"iterations"`
 	Usage          *providers.Usage `json:"usage,omitempty"`
+	CostUSD        float64          `json:"costUsd,omitempty"`        // priced LLM calls of this run (models without pricing count 0)
+	TraceID        uuid.UUID        `json:"traceId,omitempty"`        // root trace of the run (uuid.Nil when tracing is off)
 	Media          []MediaResult    `json:"media,omitempty"`          // media files from tool results (MEDIA: prefix)
 	Deliverables   []string         `json:"deliverables,omitempty"`   // actual content from tool outputs (for team task results)
 	BlockReplies   int              `json:"blockReplies,omitempty"`   // number of block.reply events emitted
 	LastBlockReply string           `json:"lastBlockReply,omitempty"` // last block reply content (for dedup)
 	LoopKilled     bool             `json:"loopKilled,omitempty"`     // true when run was terminated by loop detector
+
+	// PendingToolCalls are ClientTools calls the run paused on; the caller
+	// executes them and resumes with the results.
+	PendingToolCalls []providers.ToolCall `json:"pendingToolCalls,omitempty"`
+	// PausedTurn is set when agent tools ran in the same model turn as
+	// PendingToolCalls: the full assistant message followed by the agent
+	// tools' results. A caller that does not keep the session stores it and
+	// restores it on resume with ReplayPausedTurn.
+	PausedTurn []providers.Message `json:"pausedTurn,omitempty"`
+	// ResponseFormatError is set when the final answer still violated
+	// RunRequest.ResponseFormat after retries.
+	ResponseFormatError string `json:"responseFormatError,omitempty"`
 }
 
 // MediaResult represents a media file produced by a tool during the agent run.
@@ -499,6 +545,7 @@ type runState struct {
 	// Loop control
 	loopDetector   toolLoopState
 	totalUsage     providers.Usage
+	costUSD        float64
 	iteration      int
 	totalToolCalls int
 
@@ -541,4 +588,11 @@ type runState struct {
 	// Truncation retry counter — caps consecutive truncation/parse-error retries
 	// to prevent burning through all iterations when max_tokens is too low.
 	truncationRetries int
+
+	// Client tool calls the run paused on (RunRequest.ClientTools).
+	pendingToolCalls []providers.ToolCall
+
+	// Structured output retries and the last violation (RunRequest.ResponseFormat).
+	formatRetries int
+	formatErr     string
 }
@@ -197,7 +197,8 @@ func (l *Loop) enrichDocumentPaths(messages []providers.Message, refs []provider
 }
 
 // enrichAudioIDs updates the last user message to embed persisted media IDs
-// in <media:audio> and <media:voice> tags so the LLM can reference them.
+// in <media:audio> and <media:voice> tags so the LLM can reference them,
+// followed by any gateway STT transcript.
 func (l *Loop) enrichAudioIDs(messages []providers.Message, refs []providers.MediaRef) {
 	if len(messages) == 0 {
 		return
@@ -236,6 +237,11 @@ func (l *Loop) enrichAudioIDs(messages []providers.Message, refs []providers.Med
 			return appendTagAttrs(tag, idAttr)
 		})
 	}
+	for _, ref := range refs {
+		if ref.Kind == "audio" {
+			content = embedTranscript(content, ref)
+		}
+	}
 	messages[lastIdx].Content = content
 }
 
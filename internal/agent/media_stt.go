@@ -0,0 +1,63 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"log/slog"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/nextlevelbuilder/goclaw/internal/providers"
+	"github.com/nextlevelbuilder/goclaw/internal/store"
+	"github.com/nextlevelbuilder/goclaw/internal/stt"
+)
+
+// transcribeAudioRefs fills Transcript on current-turn audio refs that are not
+// transcribed yet. All channels rely on it for speech-to-text. Transcripts are stored on the MediaRef
+// (persisted with the user message), so history reloads never re-transcribe.
+// Failures are logged and leave the ref untranscribed; read_audio still works.
+func (l *Loop) transcribeAudioRefs(ctx context.Context, refs []providers.MediaRef) {
+	if !l.stt.HasProviders() {
+		return
+	}
+	tenantID := ""
+	if tid := store.TenantIDFromContext(ctx); tid != uuid.Nil {
+		tenantID = tid.String()
+	}
+	for i := range refs {
+		ref := &refs[i]
+		if ref.Kind != "audio" || ref.Transcript != "" || ref.Path == "" {
+			continue
+		}
+		res, err := l.stt.Transcribe(ctx, tenantID, stt.Input{Path: ref.Path, MimeType: ref.MimeType})
+		if err != nil {
+			slog.Warn("stt: transcription failed", "media_id", ref.ID, "agent", l.id, "error", err)
+			continue
+		}
+		ref.Transcript = res.Text
+		slog.Debug("stt: transcribed audio", "media_id", ref.ID, "provider", res.Provider, "length", len(res.Text))
+	}
+}
+
+// embedTranscript places an audio ref's transcript right after its id-tagged
+// <media:audio>/<media:voice> tag. Channels that deliver audio without a tag
+// (e.g. WhatsApp) get one appended. Tags already followed by a transcript are
+// left untouched.
+func embedTranscript(content string, ref providers.MediaRef) string {
+	if ref.Transcript == "" {
+		return content
+	}
+	block := "\n<transcript>" + html.EscapeString(ref.Transcript) + "</transcript>"
+	idTag := fmt.Sprintf(" id=%q>", ref.ID)
+	idx := strings.Index(content, idTag)
+	if idx < 0 {
+		return strings.TrimRight(content, "\n") + fmt.Sprintf("\n<media:audio id=%q>", ref.ID) + block
+	}
+	end := idx + len(idTag)
+	if strings.HasPrefix(content[end:], "\n<transcript>") {
+		return content
+	}
+	return content[:end] + block + content[end:]
+}
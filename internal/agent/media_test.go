@@ -320,3 +320,28 @@ func TestEnrichAudioIDs_MixedAudioAndVoice(t *testing.T) {
 		t.Fatalf("mixed audio/voice:\n got %q\nwant %q", messages[0].Content, want)
 	}
 }
+
+// TestEnrichAudioIDs_Transcripts verifies gateway STT transcripts are inlined
+// after the id-tagged tag, appended when the channel sent no tag, and not
+// duplicated when the channel already transcribed the audio.
+func TestEnrichAudioIDs_Transcripts(t *testing.T) {
+	messages := []providers.Message{{
+		Role:    "user",
+		Content: "<media:audio>\n<transcript>from channel</transcript>\n<media:audio>",
+	}}
+	refs := []providers.MediaRef{
+		{ID: "v1", Kind: "audio", Transcript: "from channel"},
+		{ID: "a2", Kind: "audio", Transcript: "a < b"},
+		{ID: "w3", Kind: "audio", Transcript: "untagged"},
+	}
+
+	var loop Loop
+	loop.enrichAudioIDs(messages, refs)
+
+	want := `<media:audio id="v1">` + "\n<transcript>from channel</transcript>\n" +
+		`<media:audio id="a2">` + "\n<transcript>a &lt; b</transcript>\n" +
+		`<media:audio id="w3">` + "\n<transcript>untagged</transcript>"
+	if messages[0].Content != want {
+		t.Fatalf("transcripts:\n got %q\nwant %q", messages[0].Content, want)
+	}
+}
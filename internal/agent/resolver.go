@@ -10,6 +10,7 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/nextlevelbuilder/goclaw/internal/bootstrap"
+	"github.com/nextlevelbuilder/goclaw/internal/budget"
 	"github.com/nextlevelbuilder/goclaw/internal/bus"
 	"github.com/nextlevelbuilder/goclaw/internal/config"
 	mcpbridge "github.com/nextlevelbuilder/goclaw/internal/mcp"
@@ -19,6 +20,7 @@ import (
 	"github.com/nextlevelbuilder/goclaw/internal/sandbox"
 	"github.com/nextlevelbuilder/goclaw/internal/skills"
 	"github.com/nextlevelbuilder/goclaw/internal/store"
+	"github.com/nextlevelbuilder/goclaw/internal/stt"
 	"github.com/nextlevelbuilder/goclaw/internal/tools"
 	"github.com/nextlevelbuilder/goclaw/internal/tracing"
 )
@@ -83,11 +85,14 @@ type ResolverDeps struct {
 	// Persistent media storage for cross-turn image/document access
 	MediaStore *media.Store
 
+	// Speech-to-text for inbound voice notes
+	STT *stt.Manager
+
 	// Model pricing for cost tracking
 	ModelPricing map[string]*config.ModelPricing
 
-	// Tracing store for budget enforcement queries
-	TracingStore store.TracingStore
+	// Spend budget enforcement (agent/tenant/user monthly limits)
+	Budget *budget.Checker
 
 	// Memory store for extractive memory fallback
 	MemoryStore store.MemoryStore
@@ -152,6 +157,8 @@ func NewManagedResolver(deps ResolverDeps) ResolverFunc {
 			}
 		}
 
+		fallbackChain := resolveFallbackChain(deps.ProviderReg, ag)
+
 		// Load bootstrap files from DB
 		contextFiles := bootstrap.LoadFromStore(ctx, deps.AgentStore, ag.ID)
 
@@ -268,16 +275,18 @@ func NewManagedResolver(deps ResolverDeps) ResolverFunc {
 		// (even those without MCP grants), because FilterTools reads from registry.List().
 		hasMCPTools := false
 		var mcpUserCredSrvs []store.MCPAccessInfo
+		var mcpMgr *mcpbridge.Manager
+		sampler := &mcpSampler{} // bound to the loop below; answers MCP sampling requests
 		if deps.MCPStore != nil {
 			if toolsReg == deps.Tools {
 				toolsReg = deps.Tools.Clone()
 			}
 			var mcpOpts []mcpbridge.ManagerOption
-			mcpOpts = append(mcpOpts, mcpbridge.WithStore(deps.MCPStore))
+			mcpOpts = append(mcpOpts, mcpbridge.WithStore(deps.MCPStore), mcpbridge.WithSampler(sampler))
 			if deps.MCPPool != nil {
 				mcpOpts = append(mcpOpts, mcpbridge.WithPool(deps.MCPPool))
 			}
-			mcpMgr := mcpbridge.NewManager(toolsReg, mcpOpts...)
+			mcpMgr = mcpbridge.NewManager(toolsReg, mcpOpts...)
 			if err := mcpMgr.LoadForAgent(ctx, ag.ID, ""); err != nil {
 				slog.Warn("failed to load MCP servers for agent", "agent", agentKey, "error", err)
 			} else {
@@ -365,6 +374,7 @@ func NewManagedResolver(deps ResolverDeps) ResolverFunc {
 			AgentType:              ag.AgentType,
 			Provider:               provider,
 			Model:                  ag.Model,
+			FallbackChain:          fallbackChain,
 			ContextWindow:          contextWindow,
 			MaxTokens:              ag.ParseMaxTokens(),
 			MaxIterations:          maxIter,
@@ -401,6 +411,7 @@ func NewManagedResolver(deps ResolverDeps) ResolverFunc {
 			DisabledTools:          disabledTools,
 			ReasoningConfig:        store.ResolveEffectiveReasoningConfig(providerReasoningDefaults, ag.ParseReasoningConfig()),
 			SelfEvolve:             ag.ParseSelfEvolve(),
+			RequireFileApproval:    ag.ParseRequireFileApproval(),
 			SkillEvolve:            ag.AgentType == store.AgentTypePredefined && ag.ParseSkillEvolve(),
 			SkillNudgeInterval:     ag.ParseSkillNudgeInterval(),
 			WorkspaceSharing:       ag.ParseWorkspaceSharing(),
@@ -409,14 +420,18 @@ func NewManagedResolver(deps ResolverDeps) ResolverFunc {
 			TeamStore:              deps.TeamStore,
 			SecureCLIStore:         deps.SecureCLIStore,
 			MediaStore:             deps.MediaStore,
+			STT:                    deps.STT,
 			ModelPricing:           deps.ModelPricing,
 			BudgetMonthlyCents:     derefInt(ag.BudgetMonthlyCents),
-			TracingStore:           deps.TracingStore,
+			Budget:                 deps.Budget,
+			ProviderLookup:         tenantProviderLookup(deps.ProviderReg, ag.TenantID),
 			MemoryStore:            deps.MemoryStore,
 			MCPStore:               deps.MCPStore,
 			MCPPool:                deps.MCPPool,
 			MCPUserCredSrvs:        mcpUserCredSrvs,
+			MCPManager:             mcpMgr,
 		})
+		sampler.loop.Store(loop)
 
 		slog.Info("resolved agent from DB", "agent", agentKey, "model", ag.Model, "provider", ag.Provider)
 		return loop, nil
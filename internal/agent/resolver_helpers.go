@@ -2,12 +2,14 @@ package agent
 
 import (
 	"fmt"
+	"log/slog"
 	"slices"
 	"strings"
 
 	"github.com/google/uuid"
 
 	"github.com/nextlevelbuilder/goclaw/internal/config"
+	"github.com/nextlevelbuilder/goclaw/internal/providers"
 	"github.com/nextlevelbuilder/goclaw/internal/store"
 	"github.com/nextlevelbuilder/goclaw/internal/tools"
 )
@@ -99,7 +101,8 @@ func buildTeamMD(team *store.TeamData, members []store.TeamMemberData, selfID uu
 		sb.WriteString("1. Identify ALL distinct deliverables\n")
 		sb.WriteString("2. Create independent tasks FIRST → get their UUIDs from the response\n")
 		sb.WriteString("3. THEN create dependent tasks with `blocked_by=[UUID]` — the system auto-dispatches when blockers complete\n")
-		sb.WriteString("   `blocked_by` only accepts real UUIDs returned by previous create calls. Never use placeholders.\n\n")
+		sb.WriteString("   `blocked_by` only accepts real UUIDs returned by previous create calls. Never use placeholders.\n")
+		sb.WriteString("   Or use `create_plan` to create the whole graph atomically in ONE call — tasks reference each other by `key` in `blocked_by`.\n\n")
 		sb.WriteString("Same member → sequential (higher priority first). Different members → parallel.\n\n")
 		sb.WriteString("**Anti-pattern (WRONG):** create task A → wait for A to finish → create task B\n")
 		sb.WriteString("**Correct pattern:** create A → create B → create C(blocked_by=[A.id, B.id]) → announce → STOP\n\n")
@@ -226,3 +229,39 @@ func agentToolPolicyWithWorkspace(policy *config.ToolPolicySpec, hasTeam bool) *
 	}
 	return policy
 }
+
+// resolveFallbackChain maps the agent's configured fallback_chain to registered
+// providers (tenant-specific first, then master). Unknown providers are skipped
+// with a warning rather than failing agent resolution.
+func resolveFallbackChain(reg *providers.Registry, ag *store.AgentData) []ModelFallback {
+	hops := ag.ParseFallbackChain()
+	if len(hops) == 0 || reg == nil {
+		return nil
+	}
+	chain := make([]ModelFallback, 0, len(hops))
+	for _, hop := range hops {
+		p, err := reg.GetForTenant(ag.TenantID, hop.Provider)
+		if err != nil {
+			slog.Warn("fallback provider not found, skipping hop",
+				"agent", ag.AgentKey, "provider", hop.Provider, "model", hop.Model)
+			continue
+		}
+		chain = append(chain, ModelFallback{Provider: p, Model: hop.Model})
+	}
+	return chain
+}
+
+// tenantProviderLookup resolves provider names for budget downgrades
+// (tenant-specific first, then master). Returns nil for unknown names.
+func tenantProviderLookup(reg *providers.Registry, tenantID uuid.UUID) func(string) providers.Provider {
+	if reg == nil {
+		return nil
+	}
+	return func(name string) providers.Provider {
+		p, err := reg.GetForTenant(tenantID, name)
+		if err != nil {
+			return nil
+		}
+		return p
+	}
+}
@@ -0,0 +1,491 @@
+// Package backup snapshots a GoClaw instance (or one tenant) into a
+// versioned archive, restores it, and copies data between storage backends.
+//
+// It works on tables rather than store interfaces so every store — agents,
+// context files, sessions, memory and embeddings, knowledge graph, teams and
+// tasks, cron, channel instances, skills, MCP servers and the rest — is
+// covered without per-store code. Tables are discovered from the live schema
+// and copied in foreign-key order; columns are matched by name, so an archive
+// restores into a newer schema (new columns take their defaults) and into the
+// other backend (values are converted per column type).
+//
+// Archive layout (tar.gz):
+//
+//	manifest.json              Manifest
+//	tables/<table>.jsonl       {"table":..., "columns":[...]} then one JSON array per row
+//	files/data/...             data directory: skills-store, team workspaces, media
+//	files/workspace/...        agent workspaces
+package backup
+
+import (
+	"archive/tar"
+	"bufio"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/nextlevelbuilder/goclaw/internal/config"
+	"github.com/nextlevelbuilder/goclaw/internal/store"
+)
+
+// FormatVersion is the archive format written by this version.
+const FormatVersion = 1
+
+const manifestName = "manifest.json"
+
+// Manifest describes an archive.
+type Manifest struct {
+	FormatVersion int       `json:"format_version"`
+	CreatedAt     time.Time `json:"created_at"`
+	AppVersion    string    `json:"app_version,omitempty"`
+	Backend       string    `json:"backend"`
+	SchemaVersion int       `json:"schema_version"`
+	TenantID      uuid.UUID `json:"tenant_id"` // uuid.Nil = whole instance
+	TenantSlug    string    `json:"tenant_slug,omitempty"`
+	Tables        []string  `json:"tables"`
+}
+
+// Options controls a backup, restore or copy.
+type Options struct {
+	// TenantID restricts a backup to one tenant (uuid.Nil = whole instance).
+	TenantID uuid.UUID
+	// SourceKey and TargetKey re-encrypt stored secrets (provider API keys,
+	// channel credentials, MCP and CLI credentials, config secrets): values
+	// encrypted with SourceKey are re-encrypted with TargetKey. Secrets are
+	// copied as-is when TargetKey is empty or equal to SourceKey.
+	SourceKey string
+	TargetKey string
+	// DataDir and Workspace are the file roots archived or restored ("" skips).
+	DataDir   string
+	Workspace string
+	// Exclude lists files never archived, such as the SQLite database itself.
+	Exclude []string
+	// AppVersion is recorded in the manifest.
+	AppVersion string
+}
+
+// TableReport counts the rows copied into one table.
+type TableReport struct {
+	Name    string `json:"name"`
+	Rows    int    `json:"rows"`
+	Skipped int    `json:"skipped,omitempty"` // already present in the target
+}
+
+// Report summarizes a backup, restore or copy.
+type Report struct {
+	Manifest       *Manifest     `json:"manifest,omitempty"`
+	Tables         []TableReport `json:"tables"`
+	DroppedColumns []string      `json:"dropped_columns,omitempty"` // table.column missing in the target
+	MissingTables  []string      `json:"missing_tables,omitempty"`  // tables missing in the target
+	Files          int           `json:"files"`
+	SkippedFiles   int           `json:"skipped_files,omitempty"`
+	// SecretsNotRewrapped counts secrets that could not be decrypted with
+	// SourceKey and were copied unchanged.
+	SecretsNotRewrapped int `json:"secrets_not_rewrapped,omitempty"`
+}
+
+// Rows returns the total number of rows copied.
+func (r *Report) Rows() int {
+	n := 0
+	for _, t := range r.Tables {
+		n += t.Rows
+	}
+	return n
+}
+
+// Backup writes an archive of db (and the file roots in opts) to w.
+func Backup(ctx context.Context, db *DB, w io.Writer, opts Options) (*Report, error) {
+	tables, err := db.tables(ctx)
+	if err != nil {
+		return nil, err
+	}
+	byName := make(map[string]*table, len(tables))
+	for _, t := range tables {
+		byName[t.Name] = t
+	}
+
+	m := &Manifest{
+		FormatVersion: FormatVersion,
+		CreatedAt:     time.Now().UTC(),
+		AppVersion:    opts.AppVersion,
+		Backend:       db.backend,
+		SchemaVersion: db.schemaVersion(ctx),
+		TenantID:      opts.TenantID,
+	}
+	if opts.TenantID != uuid.Nil {
+		if m.TenantSlug, err = db.tenantSlug(ctx, opts.TenantID); err != nil {
+			return nil, err
+		}
+	}
+	for _, t := range tables {
+		m.Tables = append(m.Tables, t.Name)
+	}
+
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+	rep := &Report{Manifest: m}
+	raw, _ := json.MarshalIndent(m, "", "  ")
+	if err := writeEntry(tw, manifestName, raw); err != nil {
+		return nil, err
+	}
+
+	rw := newRewrapper(opts.SourceKey, opts.TargetKey)
+	for _, t := range tables {
+		n, ok, err := spoolTable(ctx, db, t, byName, opts.TenantID, rw, tw)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			rep.Tables = append(rep.Tables, TableReport{Name: t.Name, Rows: n})
+		}
+	}
+
+	for _, root := range fileRoots(opts, m.TenantID, m.TenantSlug) {
+		n, err := writeFiles(tw, root)
+		if err != nil {
+			return nil, fmt.Errorf("archive files: %w", err)
+		}
+		rep.Files += n
+	}
+	if rw != nil {
+		rep.SecretsNotRewrapped = rw.failed
+	}
+
+	if err := tw.Close(); err != nil {
+		return nil, err
+	}
+	return rep, gz.Close()
+}
+
+// tableHeader is the first line of a table entry.
+type tableHeader struct {
+	Table   string   `json:"table"`
+	Columns []string `json:"columns"`
+}
+
+// spoolTable writes the rows of t to a temp file first: tar needs each
+// entry's size before its content.
+func spoolTable(ctx context.Context, db *DB, t *table, byName map[string]*table, tenantID uuid.UUID, rw *rewrapper, tw *tar.Writer) (int, bool, error) {
+	f, err := os.CreateTemp("", "goclaw-backup-*.jsonl")
+	if err != nil {
+		return 0, false, err
+	}
+	defer os.Remove(f.Name())
+	defer f.Close()
+
+	bw := bufio.NewWriter(f)
+	enc := json.NewEncoder(bw)
+	hdr := tableHeader{Table: t.Name}
+	for _, c := range t.Columns {
+		hdr.Columns = append(hdr.Columns, c.Name)
+	}
+	if err := enc.Encode(hdr); err != nil {
+		return 0, false, err
+	}
+	n := 0
+	ok, err := db.readRows(ctx, t, byName, tenantID, func(row []any) error {
+		for i, v := range row {
+			row[i] = encodeValue(rw.apply(v))
+		}
+		n++
+		return enc.Encode(row)
+	})
+	if err != nil || !ok {
+		return 0, false, err
+	}
+	if err := bw.Flush(); err != nil {
+		return 0, false, err
+	}
+	size, err := f.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return 0, false, err
+	}
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return 0, false, err
+	}
+	if err := tw.WriteHeader(&tar.Header{Name: "tables/" + t.Name + ".jsonl", Mode: 0o600, Size: size, ModTime: time.Now()}); err != nil {
+		return 0, false, err
+	}
+	if _, err := io.CopyN(tw, f, size); err != nil {
+		return 0, false, err
+	}
+	return n, true, nil
+}
+
+func writeEntry(tw *tar.Writer, name string, data []byte) error {
+	if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0o600, Size: int64(len(data)), ModTime: time.Now()}); err != nil {
+		return err
+	}
+	_, err := tw.Write(data)
+	return err
+}
+
+// ReadManifest reads the manifest of an archive without restoring it.
+func ReadManifest(r io.Reader) (*Manifest, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("not a backup archive: %w", err)
+	}
+	defer gz.Close()
+	return readManifest(tar.NewReader(gz))
+}
+
+func readManifest(tr *tar.Reader) (*Manifest, error) {
+	hdr, err := tr.Next()
+	if err != nil || hdr.Name != manifestName {
+		return nil, errors.New("not a backup archive: manifest missing")
+	}
+	var m Manifest
+	if err := json.NewDecoder(tr).Decode(&m); err != nil {
+		return nil, fmt.Errorf("read manifest: %w", err)
+	}
+	if m.FormatVersion > FormatVersion {
+		return nil, fmt.Errorf("archive format %d is newer than supported (%d); upgrade goclaw", m.FormatVersion, FormatVersion)
+	}
+	return &m, nil
+}
+
+// Restore loads an archive into db and the file roots in opts. Rows and files
+// that already exist in the target are kept. The target schema must be
+// migrated first.
+func Restore(ctx context.Context, db *DB, r io.Reader, opts Options) (*Report, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("not a backup archive: %w", err)
+	}
+	defer gz.Close()
+	tr := tar.NewReader(gz)
+	m, err := readManifest(tr)
+	if err != nil {
+		return nil, err
+	}
+	if m.Backend == db.backend {
+		if v := db.schemaVersion(ctx); m.SchemaVersion > v {
+			return nil, fmt.Errorf("archive schema version %d is newer than the database (%d); upgrade first", m.SchemaVersion, v)
+		}
+	}
+
+	w, err := db.newWriter(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer w.close()
+
+	rep := &Report{Manifest: m}
+	rw := newRewrapper(opts.SourceKey, opts.TargetKey)
+	roots := make(map[string]string)
+	for _, root := range fileRoots(opts, m.TenantID, m.TenantSlug) {
+		roots[root.Name] = root.Dir
+	}
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case strings.HasPrefix(hdr.Name, "tables/"):
+			if err := restoreTable(ctx, w, tr, rw, rep); err != nil {
+				return nil, err
+			}
+		case strings.HasPrefix(hdr.Name, "files/") && hdr.Typeflag == tar.TypeReg:
+			rest := strings.TrimPrefix(hdr.Name, "files/")
+			name, rel, _ := strings.Cut(rest, "/")
+			dir, ok := roots[name]
+			if !ok {
+				continue
+			}
+			written, err := extractFile(dir, rel, hdr, tr)
+			if err != nil {
+				return nil, err
+			}
+			if written {
+				rep.Files++
+			} else {
+				rep.SkippedFiles++
+			}
+		}
+	}
+	if rw != nil {
+		rep.SecretsNotRewrapped = rw.failed
+	}
+	return rep, nil
+}
+
+func restoreTable(ctx context.Context, w *writer, r io.Reader, rw *rewrapper, rep *Report) error {
+	dec := json.NewDecoder(r)
+	dec.UseNumber()
+	var hdr tableHeader
+	if err := dec.Decode(&hdr); err != nil {
+		return fmt.Errorf("read table header: %w", err)
+	}
+	tw, dropped, err := w.table(ctx, hdr.Table, hdr.Columns, rw)
+	if err != nil {
+		return err
+	}
+	for _, c := range dropped {
+		rep.DroppedColumns = append(rep.DroppedColumns, hdr.Table+"."+c)
+	}
+	if tw == nil {
+		rep.MissingTables = append(rep.MissingTables, hdr.Table)
+		return nil
+	}
+	for dec.More() {
+		var raw []any
+		if err := dec.Decode(&raw); err != nil {
+			tw.rollback()
+			return fmt.Errorf("read %s: %w", hdr.Table, err)
+		}
+		row := make([]any, len(raw))
+		for i, v := range raw {
+			if row[i], err = decodeValue(v); err != nil {
+				tw.rollback()
+				return fmt.Errorf("read %s.%s: %w", hdr.Table, hdr.Columns[i], err)
+			}
+		}
+		if err := tw.insert(ctx, row); err != nil {
+			tw.rollback()
+			return err
+		}
+	}
+	if err := tw.commit(); err != nil {
+		return err
+	}
+	rep.Tables = append(rep.Tables, TableReport{Name: hdr.Table, Rows: tw.inserted, Skipped: tw.skipped})
+	return nil
+}
+
+// Copy streams every table of src into dst (migrate-backend). Files are not
+// touched: both backends use the same data directory.
+func Copy(ctx context.Context, src, dst *DB, opts Options) (*Report, error) {
+	tables, err := src.tables(ctx)
+	if err != nil {
+		return nil, err
+	}
+	byName := make(map[string]*table, len(tables))
+	for _, t := range tables {
+		byName[t.Name] = t
+	}
+	w, err := dst.newWriter(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer w.close()
+
+	rep := &Report{}
+	rw := newRewrapper(opts.SourceKey, opts.TargetKey)
+	for _, t := range tables {
+		cols := make([]string, len(t.Columns))
+		for i, c := range t.Columns {
+			cols[i] = c.Name
+		}
+		tw, dropped, err := w.table(ctx, t.Name, cols, rw)
+		if err != nil {
+			return nil, err
+		}
+		for _, c := range dropped {
+			rep.DroppedColumns = append(rep.DroppedColumns, t.Name+"."+c)
+		}
+		if tw == nil {
+			rep.MissingTables = append(rep.MissingTables, t.Name)
+			continue
+		}
+		if _, err := src.readRows(ctx, t, byName, opts.TenantID, func(row []any) error {
+			return tw.insert(ctx, row)
+		}); err != nil {
+			tw.rollback()
+			return nil, err
+		}
+		if err := tw.commit(); err != nil {
+			return nil, err
+		}
+		rep.Tables = append(rep.Tables, TableReport{Name: t.Name, Rows: tw.inserted, Skipped: tw.skipped})
+	}
+	if rw != nil {
+		rep.SecretsNotRewrapped = rw.failed
+	}
+	return rep, nil
+}
+
+// fileRoots returns the directories archived for a backup scope. A tenant
+// backup covers the tenant's own data and workspace directories; the master
+// tenant's are the roots themselves minus the other tenants' subdirectories.
+func fileRoots(opts Options, tenantID uuid.UUID, slug string) []fileRoot {
+	dataDir, workspace := opts.DataDir, opts.Workspace
+	if tenantID != uuid.Nil {
+		if dataDir != "" {
+			dataDir = config.TenantDataDir(dataDir, tenantID, slug)
+		}
+		if workspace != "" {
+			workspace = config.TenantWorkspace(workspace, tenantID, slug)
+		}
+	}
+	abs := func(p string) string {
+		if a, err := filepath.Abs(p); err == nil {
+			return a
+		}
+		return p
+	}
+	var exclude []string
+	for _, p := range opts.Exclude {
+		exclude = append(exclude, abs(p))
+	}
+	var roots []fileRoot
+	if dataDir != "" {
+		ex := exclude
+		if workspace != "" {
+			ex = append(ex, abs(workspace)) // nested workspace is archived on its own
+		}
+		if tenantID == store.MasterTenantID {
+			ex = append(ex, filepath.Join(abs(dataDir), "tenants"))
+		}
+		roots = append(roots, fileRoot{Name: "data", Dir: dataDir, Exclude: ex})
+	}
+	if workspace != "" {
+		ex := exclude
+		if tenantID == store.MasterTenantID {
+			ex = append(ex, filepath.Join(abs(workspace), "tenants"))
+		}
+		roots = append(roots, fileRoot{Name: "workspace", Dir: workspace, Exclude: ex})
+	}
+	return roots
+}
+
+func (d *DB) tenantSlug(ctx context.Context, id uuid.UUID) (string, error) {
+	q := `SELECT slug FROM tenants WHERE id = $1`
+	if d.backend == BackendSQLite {
+		q = `SELECT slug FROM tenants WHERE id = ?`
+	}
+	var slug string
+	if err := d.db.QueryRowContext(ctx, q, id.String()).Scan(&slug); err != nil {
+		return "", fmt.Errorf("tenant %s: %w", id, err)
+	}
+	return slug, nil
+}
+
+// ResolveTenant returns the ID of the tenant with the given ID or slug.
+func (d *DB) ResolveTenant(ctx context.Context, ref string) (uuid.UUID, error) {
+	if id, err := uuid.Parse(ref); err == nil {
+		return id, nil
+	}
+	q := `SELECT id FROM tenants WHERE slug = $1`
+	if d.backend == BackendSQLite {
+		q = `SELECT id FROM tenants WHERE slug = ?`
+	}
+	var id string
+	if err := d.db.QueryRowContext(ctx, q, ref).Scan(&id); err != nil {
+		return uuid.Nil, fmt.Errorf("tenant %q: %w", ref, err)
+	}
+	return uuid.Parse(id)
+}
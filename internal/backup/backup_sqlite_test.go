@@ -0,0 +1,210 @@
+//go:build sqlite || sqliteonly
+
+package backup
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/nextlevelbuilder/goclaw/internal/crypto"
+	"github.com/nextlevelbuilder/goclaw/internal/store"
+	"github.com/nextlevelbuilder/goclaw/internal/store/sqlitestore"
+)
+
+const (
+	keyA = "0123456789abcdef0123456789abcdef"
+	keyB = "fedcba9876543210fedcba9876543210"
+)
+
+func openTestDB(t *testing.T) (*sql.DB, *DB) {
+	t.Helper()
+	sqlDB, err := sqlitestore.OpenDB(filepath.Join(t.TempDir(), "goclaw.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { sqlDB.Close() })
+	if err := sqlitestore.EnsureSchema(sqlDB); err != nil {
+		t.Fatal(err)
+	}
+	db, err := NewDB(sqlDB, BackendSQLite)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return sqlDB, db
+}
+
+// seed creates a second tenant and one agent with a context file and an
+// encrypted channel credential in each tenant.
+func seed(t *testing.T, sqlDB *sql.DB) (other uuid.UUID) {
+	t.Helper()
+	other = uuid.New()
+	mustExec(t, sqlDB, `INSERT INTO tenants (id, name, slug) VALUES (?, 'Acme', 'acme')`, other.String())
+	for _, tid := range []uuid.UUID{store.MasterTenantID, other} {
+		agentID := uuid.New().String()
+		mustExec(t, sqlDB, `INSERT INTO agents (id, agent_key, owner_id, model, tenant_id, is_default) VALUES (?, 'bot', 'u1', 'm', ?, 1)`, agentID, tid.String())
+		mustExec(t, sqlDB, `INSERT INTO agent_context_files (id, agent_id, file_name, content, tenant_id) VALUES (?, ?, 'SOUL.md', 'be kind', ?)`,
+			uuid.New().String(), agentID, tid.String())
+		secret, err := crypto.Encrypt(`{"token":"t-`+tid.String()+`"}`, keyA)
+		if err != nil {
+			t.Fatal(err)
+		}
+		mustExec(t, sqlDB, `INSERT INTO channel_instances (id, name, channel_type, agent_id, credentials, tenant_id) VALUES (?, 'tg', 'telegram', ?, ?, ?)`,
+			uuid.New().String(), agentID, []byte(secret), tid.String())
+	}
+	return other
+}
+
+func mustExec(t *testing.T, db *sql.DB, q string, args ...any) {
+	t.Helper()
+	if _, err := db.Exec(q, args...); err != nil {
+		t.Fatalf("%s: %v", q, err)
+	}
+}
+
+func count(t *testing.T, db *sql.DB, q string, args ...any) int {
+	t.Helper()
+	var n int
+	if err := db.QueryRow(q, args...).Scan(&n); err != nil {
+		t.Fatal(err)
+	}
+	return n
+}
+
+func TestBackupRestore_Instance(t *testing.T) {
+	ctx := context.Background()
+	srcSQL, src := openTestDB(t)
+	seed(t, srcSQL)
+
+	dataDir := t.TempDir()
+	os.MkdirAll(filepath.Join(dataDir, "skills-store", "demo"), 0o755)
+	os.WriteFile(filepath.Join(dataDir, "skills-store", "demo", "SKILL.md"), []byte("# demo"), 0o644)
+	os.WriteFile(filepath.Join(dataDir, "goclaw.db"), []byte("live db"), 0o644)
+
+	var buf bytes.Buffer
+	rep, err := Backup(ctx, src, &buf, Options{
+		DataDir: dataDir,
+		Exclude: []string{filepath.Join(dataDir, "goclaw.db")},
+	})
+	if err != nil {
+		t.Fatalf("Backup: %v", err)
+	}
+	if rep.Files != 1 {
+		t.Errorf("files = %d, want 1 (database excluded)", rep.Files)
+	}
+
+	m, err := ReadManifest(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatalf("ReadManifest: %v", err)
+	}
+	if m.Backend != BackendSQLite || m.TenantID != uuid.Nil || m.FormatVersion != FormatVersion {
+		t.Errorf("manifest = %+v", m)
+	}
+
+	dstSQL, dst := openTestDB(t)
+	restoreDir := t.TempDir()
+	rep, err = Restore(ctx, dst, bytes.NewReader(buf.Bytes()), Options{DataDir: restoreDir})
+	if err != nil {
+		t.Fatalf("Restore: %v", err)
+	}
+	if got := count(t, dstSQL, `SELECT COUNT(*) FROM agents`); got != 2 {
+		t.Errorf("agents = %d, want 2", got)
+	}
+	if got := count(t, dstSQL, `SELECT COUNT(*) FROM agent_context_files WHERE content = 'be kind'`); got != 2 {
+		t.Errorf("context files = %d, want 2", got)
+	}
+	if got := count(t, dstSQL, `SELECT COUNT(*) FROM agents WHERE is_default = 1`); got != 2 {
+		t.Errorf("booleans not preserved: %d", got)
+	}
+	if b, err := os.ReadFile(filepath.Join(restoreDir, "skills-store", "demo", "SKILL.md")); err != nil || string(b) != "# demo" {
+		t.Errorf("skill file = %q, %v", b, err)
+	}
+
+	// Restoring again keeps existing rows and files.
+	rep, err = Restore(ctx, dst, bytes.NewReader(buf.Bytes()), Options{DataDir: restoreDir})
+	if err != nil {
+		t.Fatalf("second Restore: %v", err)
+	}
+	if rep.Rows() != 0 || rep.SkippedFiles != 1 {
+		t.Errorf("second restore rows=%d skipped files=%d, want 0 and 1", rep.Rows(), rep.SkippedFiles)
+	}
+}
+
+func TestBackup_TenantScope(t *testing.T) {
+	ctx := context.Background()
+	srcSQL, src := openTestDB(t)
+	other := seed(t, srcSQL)
+
+	tid, err := src.ResolveTenant(ctx, "acme")
+	if err != nil || tid != other {
+		t.Fatalf("ResolveTenant = %v, %v", tid, err)
+	}
+
+	var buf bytes.Buffer
+	if _, err := Backup(ctx, src, &buf, Options{TenantID: other}); err != nil {
+		t.Fatalf("Backup: %v", err)
+	}
+	m, err := ReadManifest(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.TenantSlug != "acme" {
+		t.Errorf("slug = %q", m.TenantSlug)
+	}
+
+	dstSQL, dst := openTestDB(t)
+	if _, err := Restore(ctx, dst, bytes.NewReader(buf.Bytes()), Options{}); err != nil {
+		t.Fatalf("Restore: %v", err)
+	}
+	if got := count(t, dstSQL, `SELECT COUNT(*) FROM agents`); got != 1 {
+		t.Errorf("agents = %d, want 1", got)
+	}
+	if got := count(t, dstSQL, `SELECT COUNT(*) FROM agents WHERE tenant_id = ?`, other.String()); got != 1 {
+		t.Errorf("tenant agents = %d, want 1", got)
+	}
+	if got := count(t, dstSQL, `SELECT COUNT(*) FROM tenants WHERE slug = 'acme'`); got != 1 {
+		t.Errorf("tenant row not restored")
+	}
+}
+
+func TestCopy_RewrapsSecrets(t *testing.T) {
+	ctx := context.Background()
+	srcSQL, src := openTestDB(t)
+	seed(t, srcSQL)
+	dstSQL, dst := openTestDB(t)
+
+	rep, err := Copy(ctx, src, dst, Options{SourceKey: keyA, TargetKey: keyB})
+	if err != nil {
+		t.Fatalf("Copy: %v", err)
+	}
+	if rep.SecretsNotRewrapped != 0 {
+		t.Errorf("secrets not rewrapped = %d", rep.SecretsNotRewrapped)
+	}
+	rows, err := dstSQL.Query(`SELECT credentials FROM channel_instances`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+	n := 0
+	for rows.Next() {
+		var cred []byte
+		if err := rows.Scan(&cred); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := crypto.Decrypt(string(cred), keyA); err == nil {
+			t.Error("credential still decrypts with the source key")
+		}
+		if _, err := crypto.Decrypt(string(cred), keyB); err != nil {
+			t.Errorf("credential does not decrypt with the target key: %v", err)
+		}
+		n++
+	}
+	if n != 2 {
+		t.Errorf("channel instances = %d, want 2", n)
+	}
+}
@@ -0,0 +1,109 @@
+package backup
+
+import (
+	"archive/tar"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// fileRoot is a directory tree stored in the archive under files/<Name>/.
+type fileRoot struct {
+	Name    string
+	Dir     string
+	Exclude []string // absolute paths skipped with their contents
+}
+
+func (r fileRoot) excluded(p string) bool {
+	for _, ex := range r.Exclude {
+		if p == ex || strings.HasPrefix(p, ex+string(filepath.Separator)) {
+			return true
+		}
+	}
+	return false
+}
+
+// writeFiles adds the regular files below root to tw. Symlinks and special
+// files are skipped. Returns the number of files written.
+func writeFiles(tw *tar.Writer, root fileRoot) (int, error) {
+	dir, err := filepath.Abs(root.Dir)
+	if err != nil {
+		return 0, err
+	}
+	if _, err := os.Stat(dir); os.IsNotExist(err) {
+		return 0, nil
+	}
+	n := 0
+	err = filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if root.excluded(p) {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		f, err := os.Open(p)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		hdr := &tar.Header{
+			Name:    path.Join("files", root.Name, filepath.ToSlash(rel)),
+			Mode:    int64(info.Mode().Perm()),
+			Size:    info.Size(),
+			ModTime: info.ModTime(),
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		if _, err := io.CopyN(tw, f, info.Size()); err != nil {
+			return fmt.Errorf("archive %s: %w", p, err)
+		}
+		n++
+		return nil
+	})
+	return n, err
+}
+
+// extractFile writes an archived file below dir. Existing files are kept.
+// Returns false when the file was skipped.
+func extractFile(dir, rel string, hdr *tar.Header, r io.Reader) (bool, error) {
+	clean := path.Clean("/" + rel)
+	if clean == "/" || strings.Contains(rel, "..") {
+		return false, fmt.Errorf("unsafe path in archive: %q", rel)
+	}
+	dest := filepath.Join(dir, filepath.FromSlash(clean))
+	if _, err := os.Lstat(dest); err == nil {
+		return false, nil
+	}
+	if err := os.MkdirAll(filepath.Dir(dest), 0o755); err != nil {
+		return false, err
+	}
+	mode := os.FileMode(hdr.Mode).Perm() | 0o600
+	f, err := os.OpenFile(dest, os.O_WRONLY|os.O_CREATE|os.O_EXCL, mode)
+	if err != nil {
+		return false, err
+	}
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close()
+		return false, err
+	}
+	return true, f.Close()
+}
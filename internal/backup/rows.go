@@ -0,0 +1,225 @@
+package backup
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// readRows streams the rows of t (restricted to tenantID unless Nil) to fn.
+// Returns false without reading when t holds no data of the tenant.
+func (d *DB) readRows(ctx context.Context, t *table, byName map[string]*table, tenantID uuid.UUID, fn func(row []any) error) (bool, error) {
+	exprs := make([]string, len(t.Columns))
+	for i, c := range t.Columns {
+		exprs[i] = d.selectExpr(c)
+	}
+	q := fmt.Sprintf("SELECT %s FROM %s", strings.Join(exprs, ", "), quoteIdent(t.Name))
+
+	var args []any
+	if tenantID != uuid.Nil {
+		ph := "?"
+		if d.backend == BackendPostgres {
+			ph = "$1"
+		}
+		where, ok := tenantFilter(t, byName, ph)
+		if !ok {
+			return false, nil
+		}
+		q += " WHERE " + where
+		args = append(args, tenantID.String())
+	}
+	// Parents before children when rows reference their own table.
+	if t.selfReferencing() && t.has("created_at") {
+		q += ` ORDER BY "created_at"`
+	}
+
+	rows, err := d.db.QueryContext(ctx, q, args...)
+	if err != nil {
+		return false, fmt.Errorf("read %s: %w", t.Name, err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		row := make([]any, len(t.Columns))
+		ptrs := make([]any, len(row))
+		for i := range row {
+			ptrs[i] = &row[i]
+		}
+		if err := rows.Scan(ptrs...); err != nil {
+			return false, fmt.Errorf("read %s: %w", t.Name, err)
+		}
+		if err := fn(row); err != nil {
+			return false, err
+		}
+	}
+	return true, rows.Err()
+}
+
+// selectExpr reads PostgreSQL values in a backend-neutral form: arrays as
+// JSON text, and types without a native Go mapping (uuid, json, vector,
+// numeric, enums) as text.
+func (d *DB) selectExpr(c column) string {
+	col := quoteIdent(c.Name)
+	if d.backend != BackendPostgres {
+		return col
+	}
+	switch c.Type {
+	case "bool", "int2", "int4", "int8", "float4", "float8", "timestamptz", "timestamp", "date", "bytea", "text", "varchar":
+		return col
+	}
+	if strings.HasPrefix(c.Type, "_") {
+		return fmt.Sprintf("to_json(%s)::text", col)
+	}
+	return col + "::text"
+}
+
+// writer inserts rows into the tables of one database. Rows that conflict
+// with existing ones (same key) are skipped, so restoring into a database
+// that already holds seeded rows keeps the existing ones.
+type writer struct {
+	d       *DB
+	conn    *sql.Conn
+	tables  map[string]*table
+	replica bool // PostgreSQL FK triggers disabled for this session
+}
+
+func (d *DB) newWriter(ctx context.Context) (*writer, error) {
+	list, err := d.tables(ctx)
+	if err != nil {
+		return nil, err
+	}
+	conn, err := d.db.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	w := &writer{d: d, conn: conn, tables: make(map[string]*table, len(list))}
+	// Rows arrive in dependency order, except within reference cycles.
+	// SQLite cannot defer the check, so it is disabled instead. PostgreSQL
+	// only allows that to superusers; otherwise the ordering has to do.
+	if d.backend == BackendSQLite {
+		if _, err := conn.ExecContext(ctx, "PRAGMA foreign_keys = OFF"); err != nil {
+			conn.Close()
+			return nil, err
+		}
+	} else if _, err := conn.ExecContext(ctx, "SET session_replication_role = replica"); err == nil {
+		w.replica = true
+	}
+	for _, t := range list {
+		w.tables[t.Name] = t
+	}
+	return w, nil
+}
+
+func (w *writer) close() {
+	if w.d.backend == BackendSQLite {
+		_, _ = w.conn.ExecContext(context.Background(), "PRAGMA foreign_keys = ON")
+	} else if w.replica {
+		_, _ = w.conn.ExecContext(context.Background(), "SET session_replication_role = DEFAULT")
+	}
+	w.conn.Close()
+}
+
+// tableWriter inserts the rows of one table inside a transaction.
+type tableWriter struct {
+	backend  string
+	name     string
+	tx       *sql.Tx
+	cols     []column // target columns, in insert order
+	srcIndex []int    // position of each target column in a source row
+	rewrap   *rewrapper
+	stmt     *sql.Stmt // SQLite only
+	inserted int
+	skipped  int
+}
+
+// table starts writing name with rows laid out as srcCols. It returns nil
+// when the target has no such table, and the source columns it lacks.
+func (w *writer) table(ctx context.Context, name string, srcCols []string, rw *rewrapper) (*tableWriter, []string, error) {
+	t := w.tables[name]
+	if t == nil {
+		return nil, nil, nil
+	}
+	tw := &tableWriter{backend: w.d.backend, name: name, rewrap: rw}
+	var dropped []string
+	for i, name := range srcCols {
+		c, ok := t.column(name)
+		if !ok {
+			dropped = append(dropped, name)
+			continue
+		}
+		tw.cols = append(tw.cols, c)
+		tw.srcIndex = append(tw.srcIndex, i)
+	}
+	if len(tw.cols) == 0 {
+		return nil, dropped, nil
+	}
+
+	tx, err := w.conn.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	tw.tx = tx
+	if w.d.backend == BackendSQLite {
+		names := make([]string, len(tw.cols))
+		for i, c := range tw.cols {
+			names[i] = quoteIdent(c.Name)
+		}
+		q := fmt.Sprintf("INSERT OR IGNORE INTO %s (%s) VALUES (%s)",
+			quoteIdent(name), strings.Join(names, ", "), strings.TrimSuffix(strings.Repeat("?, ", len(names)), ", "))
+		if tw.stmt, err = tx.PrepareContext(ctx, q); err != nil {
+			tx.Rollback()
+			return nil, nil, fmt.Errorf("prepare %s: %w", name, err)
+		}
+	}
+	return tw, dropped, nil
+}
+
+func (tw *tableWriter) insert(ctx context.Context, row []any) error {
+	var (
+		res sql.Result
+		err error
+	)
+	if tw.backend == BackendSQLite {
+		args := make([]any, len(tw.cols))
+		for i, c := range tw.cols {
+			args[i] = sqliteParam(tw.rewrap.apply(row[tw.srcIndex[i]]), c.Type)
+		}
+		res, err = tw.stmt.ExecContext(ctx, args...)
+	} else {
+		names := make([]string, len(tw.cols))
+		exprs := make([]string, len(tw.cols))
+		args := make([]any, len(tw.cols))
+		for i, c := range tw.cols {
+			names[i] = quoteIdent(c.Name)
+			args[i], exprs[i] = pgParam(tw.rewrap.apply(row[tw.srcIndex[i]]), c.Type, i+1)
+		}
+		q := fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s) ON CONFLICT DO NOTHING",
+			quoteIdent(tw.name), strings.Join(names, ", "), strings.Join(exprs, ", "))
+		res, err = tw.tx.ExecContext(ctx, q, args...)
+	}
+	if err != nil {
+		return fmt.Errorf("insert into %s: %w", tw.name, err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		tw.skipped++
+	} else {
+		tw.inserted++
+	}
+	return nil
+}
+
+func (tw *tableWriter) commit() error {
+	if tw.stmt != nil {
+		tw.stmt.Close()
+	}
+	return tw.tx.Commit()
+}
+
+func (tw *tableWriter) rollback() {
+	if tw.stmt != nil {
+		tw.stmt.Close()
+	}
+	tw.tx.Rollback()
+}
@@ -0,0 +1,344 @@
+package backup
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Storage backends.
+const (
+	BackendPostgres = "postgres"
+	BackendSQLite   = "sqlite"
+)
+
+// skipTables are never copied: migration bookkeeping belongs to the target
+// schema, and queued bus and cluster messages and pending OAuth
+// authorizations are transient.
+var skipTables = map[string]bool{
+	"schema_migrations": true,
+	"schema_version":    true,
+	"data_migrations":   true,
+	"message_queue":     true,
+	"cluster_messages":  true,
+	"mcp_oauth_pending": true,
+}
+
+// DB is a database handle together with its backend.
+type DB struct {
+	db      *sql.DB
+	backend string
+}
+
+// NewDB wraps db for the given backend ("postgres" or "sqlite").
+func NewDB(db *sql.DB, backend string) (*DB, error) {
+	switch backend {
+	case BackendPostgres, BackendSQLite:
+		return &DB{db: db, backend: backend}, nil
+	default:
+		return nil, fmt.Errorf("unsupported backend %q", backend)
+	}
+}
+
+// BackendOf reports the backend of an open database from its driver.
+func BackendOf(db *sql.DB) string {
+	if strings.Contains(strings.ToLower(fmt.Sprintf("%T", db.Driver())), "sqlite") {
+		return BackendSQLite
+	}
+	return BackendPostgres
+}
+
+// Backend returns the backend name.
+func (d *DB) Backend() string { return d.backend }
+
+// column is a copyable column. Type is the PostgreSQL udt name or the
+// upper-cased SQLite declared type.
+type column struct {
+	Name string
+	Type string
+}
+
+type foreignKey struct {
+	Column    string
+	RefTable  string
+	RefColumn string
+}
+
+type table struct {
+	Name    string
+	Columns []column
+	FKs     []foreignKey
+}
+
+func (t *table) has(col string) bool {
+	for _, c := range t.Columns {
+		if c.Name == col {
+			return true
+		}
+	}
+	return false
+}
+
+func (t *table) column(name string) (column, bool) {
+	for _, c := range t.Columns {
+		if c.Name == name {
+			return c, true
+		}
+	}
+	return column{}, false
+}
+
+// selfReferencing reports whether rows of t may reference each other, in
+// which case they are copied in creation order.
+func (t *table) selfReferencing() bool {
+	for _, fk := range t.FKs {
+		if fk.RefTable == t.Name {
+			return true
+		}
+	}
+	return false
+}
+
+// tables returns the copyable tables in foreign-key dependency order.
+func (d *DB) tables(ctx context.Context) ([]*table, error) {
+	var (
+		byName map[string]*table
+		err    error
+	)
+	if d.backend == BackendPostgres {
+		byName, err = d.pgTables(ctx)
+	} else {
+		byName, err = d.sqliteTables(ctx)
+	}
+	if err != nil {
+		return nil, err
+	}
+	for name := range byName {
+		if skipTables[name] {
+			delete(byName, name)
+		}
+	}
+	return sortTables(byName), nil
+}
+
+func (d *DB) pgTables(ctx context.Context) (map[string]*table, error) {
+	rows, err := d.db.QueryContext(ctx,
+		`SELECT c.table_name, c.column_name, c.udt_name
+		 FROM information_schema.columns c
+		 JOIN information_schema.tables t ON t.table_schema = c.table_schema AND t.table_name = c.table_name
+		 WHERE c.table_schema = current_schema() AND t.table_type = 'BASE TABLE'
+		   AND c.is_generated <> 'ALWAYS'
+		 ORDER BY c.table_name, c.ordinal_position`)
+	if err != nil {
+		return nil, fmt.Errorf("list columns: %w", err)
+	}
+	defer rows.Close()
+	byName := make(map[string]*table)
+	for rows.Next() {
+		var tbl, col, typ string
+		if err := rows.Scan(&tbl, &col, &typ); err != nil {
+			return nil, err
+		}
+		t := byName[tbl]
+		if t == nil {
+			t = &table{Name: tbl}
+			byName[tbl] = t
+		}
+		t.Columns = append(t.Columns, column{Name: col, Type: typ})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	fkRows, err := d.db.QueryContext(ctx,
+		`SELECT cl.relname, a.attname, rcl.relname, ra.attname
+		 FROM pg_constraint con
+		 JOIN pg_class cl ON cl.oid = con.conrelid
+		 JOIN pg_class rcl ON rcl.oid = con.confrelid
+		 JOIN pg_namespace n ON n.oid = cl.relnamespace
+		 CROSS JOIN LATERAL unnest(con.conkey, con.confkey) AS k(attnum, refnum)
+		 JOIN pg_attribute a ON a.attrelid = con.conrelid AND a.attnum = k.attnum
+		 JOIN pg_attribute ra ON ra.attrelid = con.confrelid AND ra.attnum = k.refnum
+		 WHERE con.contype = 'f' AND n.nspname = current_schema()`)
+	if err != nil {
+		return nil, fmt.Errorf("list foreign keys: %w", err)
+	}
+	defer fkRows.Close()
+	for fkRows.Next() {
+		var tbl string
+		var fk foreignKey
+		if err := fkRows.Scan(&tbl, &fk.Column, &fk.RefTable, &fk.RefColumn); err != nil {
+			return nil, err
+		}
+		if t := byName[tbl]; t != nil {
+			t.FKs = append(t.FKs, fk)
+		}
+	}
+	return byName, fkRows.Err()
+}
+
+func (d *DB) sqliteTables(ctx context.Context) (map[string]*table, error) {
+	rows, err := d.db.QueryContext(ctx,
+		`SELECT name, COALESCE(sql, '') FROM sqlite_master WHERE type = 'table' AND name NOT LIKE 'sqlite_%'`)
+	if err != nil {
+		return nil, fmt.Errorf("list tables: %w", err)
+	}
+	var names, virtual []string
+	for rows.Next() {
+		var name, ddl string
+		if err := rows.Scan(&name, &ddl); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		if strings.HasPrefix(strings.ToUpper(strings.TrimSpace(ddl)), "CREATE VIRTUAL") {
+			virtual = append(virtual, name)
+			continue
+		}
+		names = append(names, name)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	byName := make(map[string]*table)
+	for _, name := range names {
+		// FTS shadow tables are maintained by their virtual table's triggers.
+		if isShadowTable(name, virtual) {
+			continue
+		}
+		t := &table{Name: name}
+		cols, err := d.db.QueryContext(ctx, fmt.Sprintf(`PRAGMA table_info(%s)`, quoteIdent(name)))
+		if err != nil {
+			return nil, fmt.Errorf("table info %s: %w", name, err)
+		}
+		for cols.Next() {
+			var (
+				cid, notNull, pk int
+				col, typ         string
+				dflt             sql.NullString
+			)
+			if err := cols.Scan(&cid, &col, &typ, &notNull, &dflt, &pk); err != nil {
+				cols.Close()
+				return nil, err
+			}
+			t.Columns = append(t.Columns, column{Name: col, Type: strings.ToUpper(typ)})
+		}
+		cols.Close()
+
+		fks, err := d.db.QueryContext(ctx, fmt.Sprintf(`PRAGMA foreign_key_list(%s)`, quoteIdent(name)))
+		if err != nil {
+			return nil, fmt.Errorf("foreign keys %s: %w", name, err)
+		}
+		for fks.Next() {
+			var (
+				id, seq                   int
+				ref, from                 string
+				to                        sql.NullString
+				onUpdate, onDelete, match string
+			)
+			if err := fks.Scan(&id, &seq, &ref, &from, &to, &onUpdate, &onDelete, &match); err != nil {
+				fks.Close()
+				return nil, err
+			}
+			refCol := to.String
+			if refCol == "" {
+				refCol = "id"
+			}
+			t.FKs = append(t.FKs, foreignKey{Column: from, RefTable: ref, RefColumn: refCol})
+		}
+		fks.Close()
+		byName[name] = t
+	}
+	return byName, nil
+}
+
+func isShadowTable(name string, virtual []string) bool {
+	for _, v := range virtual {
+		if strings.HasPrefix(name, v+"_") {
+			return true
+		}
+	}
+	return false
+}
+
+// sortTables orders tables so every table comes after the tables it
+// references. Tables in a reference cycle are appended in name order.
+func sortTables(byName map[string]*table) []*table {
+	names := make([]string, 0, len(byName))
+	for name := range byName {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	done := make(map[string]bool, len(names))
+	var out []*table
+	for len(out) < len(names) {
+		progress := false
+		for _, name := range names {
+			if done[name] {
+				continue
+			}
+			ready := true
+			for _, fk := range byName[name].FKs {
+				if fk.RefTable != name && byName[fk.RefTable] != nil && !done[fk.RefTable] {
+					ready = false
+					break
+				}
+			}
+			if ready {
+				done[name] = true
+				out = append(out, byName[name])
+				progress = true
+			}
+		}
+		if !progress {
+			for _, name := range names {
+				if !done[name] {
+					done[name] = true
+					out = append(out, byName[name])
+				}
+			}
+		}
+	}
+	return out
+}
+
+// tenantFilter returns the WHERE clause restricting t to one tenant, or
+// ok=false when t holds no tenant data (global tables such as builtin_tools).
+// Tables without a tenant_id column are filtered through their foreign key to
+// a tenant-scoped table.
+func tenantFilter(t *table, byName map[string]*table, placeholder string) (string, bool) {
+	switch {
+	case t.Name == "tenants":
+		return `"id" = ` + placeholder, true
+	case t.has("tenant_id"):
+		return `"tenant_id" = ` + placeholder, true
+	}
+	for _, fk := range t.FKs {
+		if ref := byName[fk.RefTable]; ref != nil && ref.has("tenant_id") {
+			return fmt.Sprintf(`%s IN (SELECT %s FROM %s WHERE "tenant_id" = %s)`,
+				quoteIdent(fk.Column), quoteIdent(fk.RefColumn), quoteIdent(fk.RefTable), placeholder), true
+		}
+	}
+	return "", false
+}
+
+func quoteIdent(s string) string {
+	return `"` + strings.ReplaceAll(s, `"`, `""`) + `"`
+}
+
+// schemaVersion returns the migration version of the database schema.
+func (d *DB) schemaVersion(ctx context.Context) int {
+	var v int
+	q := `SELECT version FROM schema_migrations LIMIT 1`
+	if d.backend == BackendSQLite {
+		q = `SELECT version FROM schema_version LIMIT 1`
+	}
+	if err := d.db.QueryRowContext(ctx, q).Scan(&v); err != nil {
+		return 0
+	}
+	return v
+}
@@ -0,0 +1,277 @@
+package backup
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/nextlevelbuilder/goclaw/internal/crypto"
+)
+
+// Row values are carried between backends as nil, bool, int64, float64,
+// string, []byte or time.Time. JSON, array and vector columns travel as their
+// JSON text; each writer converts to what its column type expects.
+//
+// Embeddings are stored as pgvector text in PostgreSQL and as int8-quantized
+// blobs in SQLite. They are not converted between the two: a copy across
+// backends writes NULL and the embedding backfill regenerates them.
+
+// sqliteTimeLayout matches the SQLite schema's strftime('%Y-%m-%dT%H:%M:%fZ').
+const sqliteTimeLayout = "2006-01-02T15:04:05.000Z"
+
+// timeLayouts are the timestamp formats found in SQLite text columns.
+var timeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05.000Z",
+	"2006-01-02 15:04:05.999999999 -0700 MST",
+	"2006-01-02 15:04:05.999999999-07:00",
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02",
+}
+
+func parseTime(s string) (time.Time, bool) {
+	if i := strings.Index(s, " m="); i > 0 {
+		s = s[:i]
+	}
+	for _, layout := range timeLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// encodeValue converts a row value to its archive JSON form. Bytes and
+// timestamps are wrapped so they survive the round trip.
+func encodeValue(v any) any {
+	switch x := v.(type) {
+	case []byte:
+		return map[string]string{"$b": base64.StdEncoding.EncodeToString(x)}
+	case time.Time:
+		return map[string]string{"$t": x.UTC().Format(time.RFC3339Nano)}
+	default:
+		return v
+	}
+}
+
+// decodeValue reverses encodeValue for a value decoded with UseNumber.
+func decodeValue(v any) (any, error) {
+	switch x := v.(type) {
+	case json.Number:
+		if i, err := x.Int64(); err == nil {
+			return i, nil
+		}
+		return x.Float64()
+	case map[string]any:
+		if s, ok := x["$b"].(string); ok {
+			return base64.StdEncoding.DecodeString(s)
+		}
+		if s, ok := x["$t"].(string); ok {
+			return time.Parse(time.RFC3339Nano, s)
+		}
+		return nil, fmt.Errorf("unexpected object value")
+	default:
+		return v, nil
+	}
+}
+
+// rewrapper re-encrypts secrets ("aes-gcm:" values) from one key to another.
+type rewrapper struct {
+	from, to string
+	failed   int
+}
+
+func newRewrapper(from, to string) *rewrapper {
+	if to == "" || from == to {
+		return nil
+	}
+	return &rewrapper{from: from, to: to}
+}
+
+func (r *rewrapper) apply(v any) any {
+	if r == nil {
+		return v
+	}
+	switch x := v.(type) {
+	case string:
+		if crypto.IsEncrypted(x) {
+			return r.rewrap(x)
+		}
+	case []byte:
+		if crypto.IsEncrypted(string(x)) {
+			return []byte(r.rewrap(string(x)))
+		}
+	}
+	return v
+}
+
+func (r *rewrapper) rewrap(s string) string {
+	plain, err := crypto.Decrypt(s, r.from)
+	if err == nil && !crypto.IsEncrypted(plain) {
+		if out, err := crypto.Encrypt(plain, r.to); err == nil {
+			return out
+		}
+	}
+	r.failed++
+	return s
+}
+
+// pgParam returns the parameter and placeholder expression for writing v
+// into a PostgreSQL column of type typ.
+func pgParam(v any, typ string, n int) (any, string) {
+	ph := "$" + strconv.Itoa(n)
+	if v == nil {
+		return nil, ph
+	}
+	switch {
+	case typ == "bool":
+		return toBool(v), ph
+	case typ == "int2" || typ == "int4" || typ == "int8":
+		return toInt(v), ph
+	case typ == "float4" || typ == "float8":
+		return toFloat(v), ph
+	case typ == "timestamptz" || typ == "timestamp" || typ == "date":
+		if s, ok := v.(string); ok {
+			if t, ok := parseTime(s); ok {
+				return t, ph
+			}
+		}
+		if t, ok := v.(time.Time); ok {
+			return t, ph
+		}
+		return toText(v), ph + "::" + typ
+	case typ == "bytea":
+		if s, ok := v.(string); ok {
+			return []byte(s), ph
+		}
+		return v, ph
+	case typ == "vector":
+		if _, ok := v.([]byte); ok {
+			return nil, ph // SQLite quantized embedding
+		}
+		return toText(v), ph + "::" + typ
+	case typ == "json" || typ == "jsonb":
+		s := toText(v)
+		if s == "" {
+			return nil, ph
+		}
+		return s, ph + "::" + typ
+	case strings.HasPrefix(typ, "_"):
+		s := toText(v)
+		if strings.HasPrefix(strings.TrimSpace(s), "[") {
+			return s, fmt.Sprintf("ARRAY(SELECT jsonb_array_elements_text(%s::jsonb))::%s[]", ph, typ[1:])
+		}
+		if s == "" {
+			return nil, ph
+		}
+		return s, ph + "::" + typ
+	default:
+		return toText(v), ph + "::" + typ
+	}
+}
+
+// sqliteParam returns the parameter for writing v into a SQLite column of
+// declared type typ.
+func sqliteParam(v any, typ string) any {
+	blob := strings.Contains(typ, "BLOB")
+	switch x := v.(type) {
+	case bool:
+		if x {
+			return int64(1)
+		}
+		return int64(0)
+	case time.Time:
+		return x.UTC().Format(sqliteTimeLayout)
+	case []byte:
+		if !blob {
+			return string(x)
+		}
+	case string:
+		if blob {
+			if isVectorText(x) {
+				return nil // PostgreSQL embedding
+			}
+			return []byte(x)
+		}
+	}
+	return v
+}
+
+// isVectorText reports whether s is a pgvector literal such as "[0.1,-2]".
+func isVectorText(s string) bool {
+	if len(s) < 2 || s[0] != '[' || s[len(s)-1] != ']' {
+		return false
+	}
+	for _, f := range strings.Split(s[1:len(s)-1], ",") {
+		if _, err := strconv.ParseFloat(strings.TrimSpace(f), 32); err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+func toText(v any) string {
+	switch x := v.(type) {
+	case string:
+		return x
+	case []byte:
+		return string(x)
+	case time.Time:
+		return x.UTC().Format(time.RFC3339Nano)
+	case float64:
+		return strconv.FormatFloat(x, 'f', -1, 64)
+	default:
+		return fmt.Sprint(x)
+	}
+}
+
+func toBool(v any) any {
+	switch x := v.(type) {
+	case bool:
+		return x
+	case int64:
+		return x != 0
+	case float64:
+		return x != 0
+	default:
+		s := strings.ToLower(toText(v))
+		return s == "1" || s == "t" || s == "true"
+	}
+}
+
+func toInt(v any) any {
+	switch x := v.(type) {
+	case int64:
+		return x
+	case float64:
+		return int64(math.Round(x))
+	case bool:
+		if x {
+			return int64(1)
+		}
+		return int64(0)
+	default:
+		if i, err := strconv.ParseInt(toText(v), 10, 64); err == nil {
+			return i
+		}
+		return v
+	}
+}
+
+func toFloat(v any) any {
+	switch x := v.(type) {
+	case float64:
+		return x
+	case int64:
+		return float64(x)
+	default:
+		if f, err := strconv.ParseFloat(toText(v), 64); err == nil {
+			return f
+		}
+		return v
+	}
+}
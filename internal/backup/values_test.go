@@ -0,0 +1,110 @@
+package backup
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestEncodeDecodeValue(t *testing.T) {
+	ts := time.Date(2026, 3, 1, 12, 30, 0, 123000000, time.UTC)
+	in := []any{nil, true, int64(42), 1.5, "text", []byte{0, 1, 2}, ts}
+
+	enc := make([]any, len(in))
+	for i, v := range in {
+		enc[i] = encodeValue(v)
+	}
+	raw, err := json.Marshal(enc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	var out []any
+	if err := dec.Decode(&out); err != nil {
+		t.Fatal(err)
+	}
+	for i, v := range out {
+		got, err := decodeValue(v)
+		if err != nil {
+			t.Fatalf("decode %d: %v", i, err)
+		}
+		switch want := in[i].(type) {
+		case []byte:
+			if !bytes.Equal(got.([]byte), want) {
+				t.Errorf("bytes = %v", got)
+			}
+		case time.Time:
+			if !got.(time.Time).Equal(want) {
+				t.Errorf("time = %v", got)
+			}
+		default:
+			if got != want {
+				t.Errorf("value %d = %#v, want %#v", i, got, want)
+			}
+		}
+	}
+}
+
+func TestPgParam(t *testing.T) {
+	tests := []struct {
+		v        any
+		typ      string
+		wantArg  any
+		wantExpr string
+	}{
+		{int64(1), "bool", true, "$1"},
+		{"42", "int4", int64(42), "$1"},
+		{`{"a":1}`, "jsonb", `{"a":1}`, "$1::jsonb"},
+		{`["x","y"]`, "_text", `["x","y"]`, "ARRAY(SELECT jsonb_array_elements_text($1::jsonb))::text[]"},
+		{"0193a5b0-7000-7000-8000-000000000001", "uuid", "0193a5b0-7000-7000-8000-000000000001", "$1::uuid"},
+		{nil, "uuid", nil, "$1"},
+		{"[0.1,-2]", "vector", "[0.1,-2]", "$1::vector"},
+		{[]byte{0, 0, 128, 60, 5}, "vector", nil, "$1"},
+	}
+	for _, tt := range tests {
+		arg, expr := pgParam(tt.v, tt.typ, 1)
+		if arg != tt.wantArg || expr != tt.wantExpr {
+			t.Errorf("pgParam(%v, %s) = %#v, %q; want %#v, %q", tt.v, tt.typ, arg, expr, tt.wantArg, tt.wantExpr)
+		}
+	}
+	arg, _ := pgParam("2026-03-01T12:30:00.000Z", "timestamptz", 1)
+	if _, ok := arg.(time.Time); !ok {
+		t.Errorf("timestamp arg = %T, want time.Time", arg)
+	}
+}
+
+func TestSqliteParam_Embeddings(t *testing.T) {
+	if got := sqliteParam("[0.1, -2, 3e-2]", "BLOB"); got != nil {
+		t.Errorf("pgvector into BLOB = %#v, want nil", got)
+	}
+	if got, ok := sqliteParam(`{"k":"v"}`, "BLOB").([]byte); !ok || string(got) != `{"k":"v"}` {
+		t.Errorf("text into BLOB = %#v", got)
+	}
+	if got := sqliteParam("[0.1,-2]", "TEXT"); got != "[0.1,-2]" {
+		t.Errorf("vector text into TEXT = %#v", got)
+	}
+}
+
+func TestSortTables(t *testing.T) {
+	byName := map[string]*table{
+		"agents":         {Name: "agents", FKs: []foreignKey{{Column: "tenant_id", RefTable: "tenants"}}},
+		"tenants":        {Name: "tenants"},
+		"sessions":       {Name: "sessions", FKs: []foreignKey{{Column: "agent_id", RefTable: "agents"}, {Column: "parent_id", RefTable: "sessions"}}},
+		"builtin_tools":  {Name: "builtin_tools"},
+		"cycle_a":        {Name: "cycle_a", FKs: []foreignKey{{RefTable: "cycle_b"}}},
+		"cycle_b":        {Name: "cycle_b", FKs: []foreignKey{{RefTable: "cycle_a"}}},
+		"missing_parent": {Name: "missing_parent", FKs: []foreignKey{{RefTable: "gone"}}},
+	}
+	pos := make(map[string]int)
+	for i, tbl := range sortTables(byName) {
+		pos[tbl.Name] = i
+	}
+	if len(pos) != len(byName) {
+		t.Fatalf("sorted %d tables, want %d", len(pos), len(byName))
+	}
+	if !(pos["tenants"] < pos["agents"] && pos["agents"] < pos["sessions"]) {
+		t.Errorf("dependency order violated: %v", pos)
+	}
+}
@@ -41,6 +41,7 @@ func SeedToStore(ctx context.Context, agentStore store.AgentStore, agentID uuid.
 	if agentType == store.AgentTypeOpen {
 		return nil, nil
 	}
+	ctx = store.WithContextFileAuthor(ctx, store.ContextFileAuthorSystem, "")
 
 	existing, err := agentStore.GetAgentContextFiles(ctx, agentID)
 	if err != nil {
@@ -142,6 +143,8 @@ func SeedUserFiles(ctx context.Context, agentStore store.AgentStore, agentID uui
 	if agentType == store.AgentTypePredefined {
 		files = userSeedFilesPredefined
 	}
+	// Seeded templates are system revisions even when seeding runs inside a user's first turn.
+	ctx = store.WithContextFileAuthor(ctx, store.ContextFileAuthorSystem, "")
 
 	// Check existing per-user files to avoid overwriting personalized content
 	existing, err := agentStore.GetUserContextFiles(ctx, agentID, userID)
@@ -0,0 +1,149 @@
+// Package budget enforces monthly USD spend budgets at agent, tenant and
+// per-external-user level. Spend comes from trace costs (see tracing.CalculateCost)
+// and is checked before every LLM call in the agent loop.
+package budget
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/nextlevelbuilder/goclaw/internal/config"
+	"github.com/nextlevelbuilder/goclaw/internal/store"
+)
+
+// Budget scopes.
+const (
+	ScopeAgent  = "agent"
+	ScopeTenant = "tenant"
+	ScopeUser   = "user"
+)
+
+// Threshold levels, in increasing severity.
+const (
+	LevelOK   = "ok"
+	LevelSoft = "soft"
+	LevelHard = "hard"
+)
+
+// Decision actions, in increasing severity.
+const (
+	ActionAllow     = "allow"
+	ActionWarn      = "warn"
+	ActionDowngrade = config.BudgetActionDowngrade
+	ActionRefuse    = config.BudgetActionRefuse
+)
+
+const defaultSoftPercent = 80
+
+// Subject identifies who an LLM call is billed to.
+type Subject struct {
+	TenantID         uuid.UUID
+	AgentID          uuid.UUID
+	AgentKey         string
+	AgentBudgetCents int    // agents.budget_monthly_cents (0 = unlimited)
+	UserID           string // external sender / user ID (empty = not user-billed)
+}
+
+// AgentSubject builds a Subject for an agent (nil = tenant/user only) in tenantID.
+func AgentSubject(tenantID uuid.UUID, ag *store.AgentData, userID string) Subject {
+	s := Subject{TenantID: tenantID, UserID: userID}
+	if ag != nil {
+		s.AgentID = ag.ID
+		s.AgentKey = ag.AgentKey
+		if ag.BudgetMonthlyCents != nil {
+			s.AgentBudgetCents = *ag.BudgetMonthlyCents
+		}
+	}
+	return s
+}
+
+// Status is the current burn for one scope.
+type Status struct {
+	Scope        string  `json:"scope"`
+	Key          string  `json:"key"`
+	SpentUSD     float64 `json:"spentUsd"`
+	LimitUSD     float64 `json:"limitUsd"`
+	RemainingUSD float64 `json:"remainingUsd"`
+	SoftUSD      float64 `json:"softUsd"`
+	Level        string  `json:"level"`
+	HardAction   string  `json:"hardAction"`
+}
+
+// Decision is the outcome of a pre-call budget check. When several scopes are
+// over threshold the most severe action wins.
+type Decision struct {
+	Action         string
+	Status         *Status // the scope that produced Action (nil for allow)
+	DowngradeModel string  // set when Action is ActionDowngrade
+}
+
+// Err returns the user-facing error for a refused call.
+func (d Decision) Err() error {
+	if d.Action != ActionRefuse || d.Status == nil {
+		return nil
+	}
+	return fmt.Errorf("monthly %s budget exceeded ($%.2f / $%.2f)", d.Status.Scope, d.Status.SpentUSD, d.Status.LimitUSD)
+}
+
+// evaluate classifies spent against limit.
+func evaluate(scope, key string, spent float64, limit config.BudgetLimit) Status {
+	soft := limit.SoftPercent
+	if soft <= 0 || soft > 100 {
+		soft = defaultSoftPercent
+	}
+	action := limit.HardAction
+	if action != config.BudgetActionDowngrade || limit.DowngradeModel == "" {
+		action = config.BudgetActionRefuse
+	}
+	st := Status{
+		Scope:        scope,
+		Key:          key,
+		SpentUSD:     spent,
+		LimitUSD:     limit.MonthlyUSD,
+		RemainingUSD: max(limit.MonthlyUSD-spent, 0),
+		SoftUSD:      limit.MonthlyUSD * float64(soft) / 100,
+		Level:        LevelOK,
+		HardAction:   action,
+	}
+	switch {
+	case spent >= limit.MonthlyUSD:
+		st.Level = LevelHard
+	case spent >= st.SoftUSD:
+		st.Level = LevelSoft
+	}
+	return st
+}
+
+func levelRank(level string) int {
+	switch level {
+	case LevelSoft:
+		return 1
+	case LevelHard:
+		return 2
+	}
+	return 0
+}
+
+func actionRank(action string) int {
+	switch action {
+	case ActionWarn:
+		return 1
+	case ActionDowngrade:
+		return 2
+	case ActionRefuse:
+		return 3
+	}
+	return 0
+}
+
+// SplitModel splits a downgrade target of the form "provider/model". A bare
+// model (or one whose prefix isn't a known provider, e.g. OpenRouter's
+// "anthropic/claude-…") keeps the current provider: isProvider decides.
+func SplitModel(target string, isProvider func(name string) bool) (provider, model string) {
+	if p, m, ok := strings.Cut(target, "/"); ok && isProvider != nil && isProvider(p) {
+		return p, m
+	}
+	return "", target
+}
@@ -0,0 +1,300 @@
+package budget
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/nextlevelbuilder/goclaw/internal/bus"
+	"github.com/nextlevelbuilder/goclaw/internal/config"
+	"github.com/nextlevelbuilder/goclaw/internal/store"
+	"github.com/nextlevelbuilder/goclaw/pkg/protocol"
+)
+
+// spendEntry is a cached monthly spend for one scope key.
+type spendEntry struct {
+	usd       float64
+	month     string
+	fetchedAt time.Time
+}
+
+// Checker evaluates budgets against monthly trace cost. Spend is cached per
+// scope for cacheTTL and bumped optimistically by Record after each call, since
+// trace totals are only aggregated when a run finishes.
+// Nil-safe: a nil *Checker allows everything.
+type Checker struct {
+	tracing  store.TracingStore
+	events   bus.EventPublisher
+	cfg      config.BudgetConfig
+	cache    map[string]*spendEntry
+	notified map[string]string // scope key + month → highest level already broadcast
+	cacheTTL time.Duration
+	now      func() time.Time
+	mu       sync.Mutex
+}
+
+// NewChecker creates a budget checker. events may be nil (no threshold broadcasts).
+func NewChecker(tracing store.TracingStore, cfg config.BudgetConfig, events bus.EventPublisher) *Checker {
+	return &Checker{
+		tracing:  tracing,
+		events:   events,
+		cfg:      cfg,
+		cache:    make(map[string]*spendEntry),
+		notified: make(map[string]string),
+		cacheTTL: 30 * time.Second,
+		now:      time.Now,
+	}
+}
+
+// UpdateConfig replaces the budget configuration (e.g., after config reload).
+func (c *Checker) UpdateConfig(cfg config.BudgetConfig) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.cfg = cfg
+}
+
+// Check evaluates every budget that applies to s and returns the most severe
+// decision. Crossing a threshold for the first time this month broadcasts
+// protocol.EventBudgetThreshold.
+func (c *Checker) Check(ctx context.Context, s Subject) Decision {
+	if c == nil {
+		return Decision{Action: ActionAllow}
+	}
+	dec := Decision{Action: ActionAllow}
+	for _, st := range c.Statuses(ctx, s) {
+		if st.Level == LevelOK {
+			continue
+		}
+		c.maybeNotify(s, st)
+
+		action := ActionWarn
+		var downgrade string
+		if st.Level == LevelHard {
+			action = st.HardAction
+			if action == ActionDowngrade {
+				downgrade = c.limitFor(st.Scope, s).DowngradeModel
+			}
+		}
+		if actionRank(action) > actionRank(dec.Action) {
+			dec = Decision{Action: action, Status: &st, DowngradeModel: downgrade}
+		}
+	}
+	return dec
+}
+
+// Statuses returns the current burn for every configured budget that applies to s.
+func (c *Checker) Statuses(ctx context.Context, s Subject) []Status {
+	if c == nil {
+		return nil
+	}
+	var out []Status
+	for _, scope := range []string{ScopeAgent, ScopeTenant, ScopeUser} {
+		limit := c.limitFor(scope, s)
+		if limit.IsZero() {
+			continue
+		}
+		key := scopeKey(scope, s)
+		spent, err := c.spent(ctx, scope, s)
+		if err != nil {
+			slog.Warn("budget: failed to query spend", "scope", scope, "key", key, "error", err)
+			continue // fail-open: a broken cost query must not block all runs
+		}
+		out = append(out, evaluate(scope, key, spent, limit))
+	}
+	return out
+}
+
+// Record adds the cost of a completed LLM call to every cached scope of s.
+func (c *Checker) Record(s Subject, costUSD float64) {
+	if c == nil || costUSD <= 0 {
+		return
+	}
+	month := c.now().UTC().Format("2006-01")
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, scope := range []string{ScopeAgent, ScopeTenant, ScopeUser} {
+		if e, ok := c.cache[cacheKey(scope, s)]; ok && e.month == month {
+			e.usd += costUSD
+		}
+	}
+}
+
+// limitFor resolves the configured limit for one scope.
+// Agent: config agents[key] > agents.budget_monthly_cents (always enforced).
+// Tenant/user: only when budgets are enabled; per-key override > default.
+func (c *Checker) limitFor(scope string, s Subject) config.BudgetLimit {
+	c.mu.Lock()
+	cfg := c.cfg
+	c.mu.Unlock()
+
+	switch scope {
+	case ScopeAgent:
+		if s.AgentID == uuid.Nil {
+			return config.BudgetLimit{}
+		}
+		if cfg.Enabled {
+			if l, ok := cfg.Agents[s.AgentKey]; ok && !l.IsZero() {
+				return l
+			}
+		}
+		if s.AgentBudgetCents > 0 {
+			return config.BudgetLimit{MonthlyUSD: float64(s.AgentBudgetCents) / 100}
+		}
+	case ScopeTenant:
+		if !cfg.Enabled || s.TenantID == uuid.Nil {
+			return config.BudgetLimit{}
+		}
+		if l, ok := cfg.Tenants[s.TenantID.String()]; ok && !l.IsZero() {
+			return l
+		}
+		return cfg.Tenant
+	case ScopeUser:
+		if !cfg.Enabled || s.UserID == "" {
+			return config.BudgetLimit{}
+		}
+		if l, ok := cfg.Users[userOverrideKey(s)]; ok && !l.IsZero() {
+			return l
+		}
+		// Bare user IDs predate tenant-qualified keys; they only match in the
+		// master tenant so they cannot leak into another tenant's users.
+		if s.TenantID == store.MasterTenantID {
+			if l, ok := cfg.Users[s.UserID]; ok && !l.IsZero() {
+				return l
+			}
+		}
+		return cfg.User
+	}
+	return config.BudgetLimit{}
+}
+
+// spent returns cached or fresh month-to-date spend for one scope.
+func (c *Checker) spent(ctx context.Context, scope string, s Subject) (float64, error) {
+	now := c.now().UTC()
+	month := now.Format("2006-01")
+	key := cacheKey(scope, s)
+
+	c.mu.Lock()
+	if e, ok := c.cache[key]; ok && e.month == month && now.Sub(e.fetchedAt) < c.cacheTTL {
+		usd := e.usd
+		c.mu.Unlock()
+		return usd, nil
+	}
+	c.mu.Unlock()
+
+	if c.tracing == nil {
+		return 0, nil
+	}
+	qctx := ctx
+	if s.TenantID != uuid.Nil {
+		qctx = store.WithTenantID(ctx, s.TenantID)
+	}
+	var usd float64
+	var err error
+	switch scope {
+	case ScopeAgent:
+		usd, err = c.tracing.GetMonthlyAgentCost(qctx, s.AgentID, now.Year(), now.Month())
+	case ScopeTenant:
+		usd, err = c.tracing.GetMonthlyTenantCost(qctx, now.Year(), now.Month())
+	case ScopeUser:
+		usd, err = c.tracing.GetMonthlyUserCost(qctx, s.UserID, now.Year(), now.Month())
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	c.mu.Lock()
+	c.cache[key] = &spendEntry{usd: usd, month: month, fetchedAt: now}
+	c.mu.Unlock()
+	return usd, nil
+}
+
+// maybeNotify broadcasts a threshold event once per scope, level and month,
+// and forwards it to the configured admin chats.
+func (c *Checker) maybeNotify(s Subject, st Status) {
+	month := c.now().UTC().Format("2006-01")
+	key := cacheKey(st.Scope, s) + "|" + month
+
+	c.mu.Lock()
+	if levelRank(c.notified[key]) >= levelRank(st.Level) {
+		c.mu.Unlock()
+		return
+	}
+	c.notified[key] = st.Level
+	c.mu.Unlock()
+
+	slog.Warn("budget threshold crossed",
+		"scope", st.Scope, "key", st.Key, "level", st.Level,
+		"spent_usd", st.SpentUSD, "limit_usd", st.LimitUSD)
+
+	if c.events == nil {
+		return
+	}
+	payload := bus.BudgetThresholdPayload{
+		Scope:    st.Scope,
+		Key:      st.Key,
+		Level:    st.Level,
+		SpentUSD: st.SpentUSD,
+		LimitUSD: st.LimitUSD,
+		Month:    month,
+		UserID:   s.UserID,
+		TenantID: s.TenantID,
+	}
+	if st.Level == LevelHard {
+		payload.Action = st.HardAction
+	}
+	if s.AgentID != uuid.Nil {
+		payload.AgentID = s.AgentID.String()
+	}
+	c.events.Broadcast(bus.Event{
+		Name:     protocol.EventBudgetThreshold,
+		Payload:  payload,
+		TenantID: s.TenantID,
+	})
+}
+
+// NotifyTargets returns the admin chats configured for threshold alerts.
+func (c *Checker) NotifyTargets() []config.BudgetNotifyTarget {
+	if c == nil {
+		return nil
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.cfg.Notify
+}
+
+func scopeKey(scope string, s Subject) string {
+	switch scope {
+	case ScopeAgent:
+		return s.AgentKey
+	case ScopeTenant:
+		return s.TenantID.String()
+	case ScopeUser:
+		return s.UserID
+	}
+	return ""
+}
+
+// userOverrideKey is the budget.users key for s: "<tenantID>:<userID>".
+func userOverrideKey(s Subject) string {
+	return s.TenantID.String() + ":" + s.UserID
+}
+
+// cacheKey is tenant-qualified so identical user IDs in different tenants
+// don't share spend.
+func cacheKey(scope string, s Subject) string {
+	switch scope {
+	case ScopeAgent:
+		return scope + ":" + s.AgentID.String()
+	case ScopeTenant:
+		return scope + ":" + s.TenantID.String()
+	case ScopeUser:
+		return scope + ":" + s.TenantID.String() + ":" + s.UserID
+	}
+	return scope
+}
@@ -0,0 +1,193 @@
+package budget
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/nextlevelbuilder/goclaw/internal/bus"
+	"github.com/nextlevelbuilder/goclaw/internal/config"
+	"github.com/nextlevelbuilder/goclaw/internal/store"
+)
+
+// fakeTracing returns fixed monthly costs; other TracingStore methods panic.
+type fakeTracing struct {
+	store.TracingStore
+	agent, tenant, user float64
+	calls               int
+}
+
+func (f *fakeTracing) GetMonthlyAgentCost(context.Context, uuid.UUID, int, time.Month) (float64, error) {
+	f.calls++
+	return f.agent, nil
+}
+
+func (f *fakeTracing) GetMonthlyTenantCost(context.Context, int, time.Month) (float64, error) {
+	f.calls++
+	return f.tenant, nil
+}
+
+func (f *fakeTracing) GetMonthlyUserCost(context.Context, string, int, time.Month) (float64, error) {
+	f.calls++
+	return f.user, nil
+}
+
+type recordingPublisher struct{ events []bus.Event }
+
+func (p *recordingPublisher) Subscribe(string, bus.EventHandler) {}
+func (p *recordingPublisher) Unsubscribe(string)                 {}
+func (p *recordingPublisher) Broadcast(e bus.Event)              { p.events = append(p.events, e) }
+
+func testSubject() Subject {
+	return Subject{
+		TenantID: uuid.New(),
+		AgentID:  uuid.New(),
+		AgentKey: "writer",
+		UserID:   "u1",
+	}
+}
+
+func TestCheck_AgentDBBudgetEnforcedWithoutConfig(t *testing.T) {
+	tr := &fakeTracing{agent: 5}
+	c := NewChecker(tr, config.BudgetConfig{}, nil)
+	s := testSubject()
+	s.AgentBudgetCents = 500
+
+	dec := c.Check(context.Background(), s)
+	if dec.Action != ActionRefuse {
+		t.Fatalf("Action = %q, want refuse", dec.Action)
+	}
+	if dec.Err() == nil {
+		t.Error("Err() = nil for refused decision")
+	}
+}
+
+func TestCheck_SoftThresholdWarnsAndNotifiesOnce(t *testing.T) {
+	tr := &fakeTracing{tenant: 85}
+	pub := &recordingPublisher{}
+	c := NewChecker(tr, config.BudgetConfig{
+		Enabled: true,
+		Tenant:  config.BudgetLimit{MonthlyUSD: 100},
+	}, pub)
+	s := testSubject()
+
+	for range 3 {
+		if dec := c.Check(context.Background(), s); dec.Action != ActionWarn {
+			t.Fatalf("Action = %q, want warn", dec.Action)
+		}
+	}
+	if len(pub.events) != 1 {
+		t.Fatalf("broadcast %d events, want 1", len(pub.events))
+	}
+	p := pub.events[0].Payload.(bus.BudgetThresholdPayload)
+	if p.Scope != ScopeTenant || p.Level != LevelSoft {
+		t.Errorf("payload = %+v, want tenant/soft", p)
+	}
+}
+
+func TestCheck_DowngradeAndMostSevereWins(t *testing.T) {
+	tr := &fakeTracing{agent: 12, user: 3}
+	c := NewChecker(tr, config.BudgetConfig{
+		Enabled: true,
+		Agents: map[string]config.BudgetLimit{
+			"writer": {MonthlyUSD: 10, HardAction: config.BudgetActionDowngrade, DowngradeModel: "openai/gpt-4o-mini"},
+		},
+		User: config.BudgetLimit{MonthlyUSD: 4},
+	}, nil)
+	s := testSubject()
+
+	dec := c.Check(context.Background(), s)
+	if dec.Action != ActionDowngrade || dec.DowngradeModel != "openai/gpt-4o-mini" {
+		t.Fatalf("decision = %+v, want downgrade to openai/gpt-4o-mini", dec)
+	}
+
+	// User spend crossing its hard limit outranks the agent downgrade.
+	c.Record(s, 2)
+	dec = c.Check(context.Background(), s)
+	if dec.Action != ActionRefuse || dec.Status.Scope != ScopeUser {
+		t.Fatalf("decision = %+v, want refuse on user scope", dec)
+	}
+}
+
+func TestCheck_CachesSpendUntilTTL(t *testing.T) {
+	tr := &fakeTracing{tenant: 1}
+	c := NewChecker(tr, config.BudgetConfig{Enabled: true, Tenant: config.BudgetLimit{MonthlyUSD: 100}}, nil)
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	c.now = func() time.Time { return now }
+	s := testSubject()
+	s.UserID = ""
+
+	c.Check(context.Background(), s)
+	c.Check(context.Background(), s)
+	if tr.calls != 1 {
+		t.Fatalf("tracing calls = %d, want 1 (cached)", tr.calls)
+	}
+	now = now.Add(time.Minute)
+	c.Check(context.Background(), s)
+	if tr.calls != 2 {
+		t.Fatalf("tracing calls = %d, want 2 after TTL", tr.calls)
+	}
+}
+
+func TestLimitFor_UserOverrideIsTenantScoped(t *testing.T) {
+	s := testSubject()
+	other := s
+	other.TenantID = uuid.New()
+	master := s
+	master.TenantID = store.MasterTenantID
+	c := NewChecker(nil, config.BudgetConfig{
+		Enabled: true,
+		User:    config.BudgetLimit{MonthlyUSD: 1},
+		Users: map[string]config.BudgetLimit{
+			s.TenantID.String() + ":u1": {MonthlyUSD: 50},
+			"u1":                        {MonthlyUSD: 20},
+		},
+	}, nil)
+
+	if got := c.limitFor(ScopeUser, s).MonthlyUSD; got != 50 {
+		t.Errorf("own tenant limit = %v, want 50", got)
+	}
+	if got := c.limitFor(ScopeUser, other).MonthlyUSD; got != 1 {
+		t.Errorf("other tenant limit = %v, want default 1", got)
+	}
+	if got := c.limitFor(ScopeUser, master).MonthlyUSD; got != 20 {
+		t.Errorf("master tenant bare-key limit = %v, want 20", got)
+	}
+}
+
+func TestDowngradeWithoutModelFallsBackToRefuse(t *testing.T) {
+	st := evaluate(ScopeAgent, "a", 10, config.BudgetLimit{MonthlyUSD: 10, HardAction: config.BudgetActionDowngrade})
+	if st.HardAction != ActionRefuse {
+		t.Errorf("HardAction = %q, want refuse", st.HardAction)
+	}
+}
+
+func TestSplitModel(t *testing.T) {
+	known := func(name string) bool { return name == "openai" }
+	tests := []struct {
+		in, provider, model string
+	}{
+		{"openai/gpt-4o-mini", "openai", "gpt-4o-mini"},
+		{"anthropic/claude-haiku", "", "anthropic/claude-haiku"},
+		{"gpt-4o-mini", "", "gpt-4o-mini"},
+	}
+	for _, tt := range tests {
+		p, m := SplitModel(tt.in, known)
+		if p != tt.provider || m != tt.model {
+			t.Errorf("SplitModel(%q) = %q, %q; want %q, %q", tt.in, p, m, tt.provider, tt.model)
+		}
+	}
+}
+
+func TestNilChecker(t *testing.T) {
+	var c *Checker
+	if dec := c.Check(context.Background(), testSubject()); dec.Action != ActionAllow {
+		t.Errorf("nil Check = %q, want allow", dec.Action)
+	}
+	c.Record(testSubject(), 1)
+	if c.Statuses(context.Background(), testSubject()) != nil {
+		t.Error("nil Statuses != nil")
+	}
+}
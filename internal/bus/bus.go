@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"log/slog"
 	"sync"
+
+	"github.com/google/uuid"
 )
 
 // MessageBus routes messages between channels and the agent runtime,
@@ -20,6 +22,9 @@ type MessageBus struct {
 	// Event subscribers (subscriber ID → handler)
 	subscribers map[string]EventHandler
 	subMu       sync.RWMutex
+
+	// journal persists messages for crash recovery (nil = in-memory only).
+	journal Journal
 }
 
 func New() *MessageBus {
@@ -34,16 +39,27 @@ func New() *MessageBus {
 // PublishInbound queues an inbound message from a channel.
 // Blocks if the inbound buffer is full.
 func (mb *MessageBus) PublishInbound(msg InboundMessage) {
+	if mb.journal != nil && !mb.journal.RecordInbound(&msg) {
+		return
+	}
 	mb.inbound <- msg
 }
 
 // TryPublishInbound attempts to queue an inbound message without blocking.
-// Returns false if the inbound buffer is full (message dropped).
+// Returns false if the inbound buffer is full (message dropped). With a
+// journal the message is kept and delivered later instead, and true is returned.
 func (mb *MessageBus) TryPublishInbound(msg InboundMessage) bool {
+	if mb.journal != nil && !mb.journal.RecordInbound(&msg) {
+		return true
+	}
 	select {
 	case mb.inbound <- msg:
 		return true
 	default:
+		if len(msg.QueueIDs) > 0 {
+			mb.journal.Defer(msg.QueueIDs[0])
+			return true
+		}
 		return false
 	}
 }
@@ -61,16 +77,27 @@ func (mb *MessageBus) ConsumeInbound(ctx context.Context) (InboundMessage, bool)
 // PublishOutbound queues an outbound message to a channel.
 // Blocks if the outbound buffer is full.
 func (mb *MessageBus) PublishOutbound(msg OutboundMessage) {
+	if mb.journal != nil && !mb.journal.RecordOutbound(&msg) {
+		return
+	}
 	mb.outbound <- msg
 }
 
 // TryPublishOutbound attempts to queue an outbound message without blocking.
-// Returns false if the outbound buffer is full (message dropped).
+// Returns false if the outbound buffer is full (message dropped). With a
+// journal the message is kept and delivered later instead, and true is returned.
 func (mb *MessageBus) TryPublishOutbound(msg OutboundMessage) bool {
+	if mb.journal != nil && !mb.journal.RecordOutbound(&msg) {
+		return true
+	}
 	select {
 	case mb.outbound <- msg:
 		return true
 	default:
+		if msg.QueueID != uuid.Nil {
+			mb.journal.Defer(msg.QueueID)
+			return true
+		}
 		return false
 	}
 }
@@ -85,6 +112,12 @@ func (mb *MessageBus) SubscribeOutbound(ctx context.Context) (OutboundMessage, b
 	}
 }
 
+// QueueSizes returns the number of messages waiting in the inbound and
+// outbound buffers (used by /metrics).
+func (mb *MessageBus) QueueSizes() (inbound, outbound int) {
+	return len(mb.inbound), len(mb.outbound)
+}
+
 // RegisterHandler registers a message handler for a channel.
 func (mb *MessageBus) RegisterHandler(channel string, handler MessageHandler) {
 	mb.handlerMu.Lock()
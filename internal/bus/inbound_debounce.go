@@ -11,6 +11,8 @@ import (
 	"strings"
 	"sync"
 	"time"
+
+	"github.com/google/uuid"
 )
 
 // InboundDebouncer buffers rapid inbound messages from the same sender
@@ -158,6 +160,13 @@ func mergeInboundMessages(msgs []InboundMessage) InboundMessage {
 	}
 	last.Media = allMedia
 
+	// The merged message settles every journaled message it replaces.
+	var queueIDs []uuid.UUID
+	for _, m := range msgs {
+		queueIDs = append(queueIDs, m.QueueIDs...)
+	}
+	last.QueueIDs = queueIDs
+
 	return last
 }
 
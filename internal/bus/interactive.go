@@ -0,0 +1,61 @@
+package bus
+
+// Interactive message kinds.
+const (
+	InteractiveButtons = "buttons" // one tap picks an option
+	InteractiveSingle  = "single"  // pick one option (select menu where the platform has one)
+	InteractiveMulti   = "multi"   // pick any number of options, then submit
+	InteractiveForm    = "form"    // fill in short text fields
+)
+
+// InteractiveMessage asks the user for structured input. It travels on an
+// OutboundMessage whose Content is the question; each channel renders it
+// natively (inline keyboards, Block Kit, components, cards) or as a numbered
+// text prompt. Answers come back as an InteractiveAnswer with the same ID.
+type InteractiveMessage struct {
+	ID      string              `json:"id"`
+	Kind    string              `json:"kind"`
+	Options []InteractiveOption `json:"options,omitempty"` // buttons / single / multi
+	Fields  []InteractiveField  `json:"fields,omitempty"`  // form
+	// AskedID is the sender ID (without "|username") of the user who was
+	// asked; only they may press its buttons. Empty = anyone in the chat.
+	AskedID string `json:"asked_id,omitempty"`
+}
+
+// InteractiveOption is one choice.
+type InteractiveOption struct {
+	Value string `json:"value"`
+	Label string `json:"label"`
+}
+
+// InteractiveField is one short text field of a form.
+type InteractiveField struct {
+	Name        string `json:"name"`
+	Label       string `json:"label"`
+	Placeholder string `json:"placeholder,omitempty"`
+	Required    bool   `json:"required,omitempty"`
+}
+
+// InteractiveAnswer is the user's reply to an InteractiveMessage.
+type InteractiveAnswer struct {
+	ID       string            `json:"id"`
+	SenderID string            `json:"sender_id"`
+	Choices  []string          `json:"choices,omitempty"` // selected option values
+	Fields   map[string]string `json:"fields,omitempty"`  // form values by field name
+	Text     string            `json:"text,omitempty"`    // free-text reply that matched no option
+}
+
+// IsChoice reports whether the message asks to pick options.
+func (m *InteractiveMessage) IsChoice() bool {
+	return m.Kind == InteractiveButtons || m.Kind == InteractiveSingle || m.Kind == InteractiveMulti
+}
+
+// Option returns the option with the given value.
+func (m *InteractiveMessage) Option(value string) (InteractiveOption, bool) {
+	for _, o := range m.Options {
+		if o.Value == value {
+			return o, true
+		}
+	}
+	return InteractiveOption{}, false
+}
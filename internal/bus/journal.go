@@ -0,0 +1,89 @@
+package bus
+
+import (
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+// Journal persists bus traffic so it survives a restart (see internal/queue).
+// Messages are recorded when published and acknowledged once handled; whatever
+// is still unacknowledged after a crash is delivered again.
+type Journal interface {
+	// RecordInbound journals msg, setting msg.QueueIDs. It reports false when
+	// msg duplicates one already journaled (same channel message ID), in which
+	// case it must not be delivered again.
+	RecordInbound(msg *InboundMessage) bool
+	// RecordOutbound journals msg, setting msg.QueueID. Same contract as
+	// RecordInbound.
+	RecordOutbound(msg *OutboundMessage) bool
+	// Defer hands a journaled message that did not fit in the bus buffer back
+	// to the journal, which redelivers it later.
+	Defer(id uuid.UUID)
+	// Ack marks journaled messages as handled.
+	Ack(ids ...uuid.UUID)
+	// Fail records a failed delivery of msg and reports whether the journal
+	// will retry it; false means it was dead-lettered (or is not journaled).
+	Fail(msg OutboundMessage, err error) bool
+}
+
+// SetJournal enables durable delivery. Must be called before any message is
+// published.
+func (mb *MessageBus) SetJournal(j Journal) {
+	mb.journal = j
+}
+
+// AckInbound marks an inbound message (and everything merged into it) handled.
+func (mb *MessageBus) AckInbound(msg InboundMessage) {
+	if mb.journal != nil && len(msg.QueueIDs) > 0 {
+		mb.journal.Ack(msg.QueueIDs...)
+	}
+}
+
+// AckOutbound marks an outbound message delivered (or deliberately skipped).
+func (mb *MessageBus) AckOutbound(msg OutboundMessage) {
+	if mb.journal != nil && msg.QueueID != uuid.Nil {
+		mb.journal.Ack(msg.QueueID)
+	}
+}
+
+// FailOutbound records a failed channel send. It reports whether the message
+// will be retried, in which case the caller must keep its media files.
+func (mb *MessageBus) FailOutbound(msg OutboundMessage, err error) bool {
+	if mb.journal == nil || msg.QueueID == uuid.Nil {
+		return false
+	}
+	return mb.journal.Fail(msg, err)
+}
+
+// RedeliverInbound pushes a message replayed by the journal without recording
+// it again. Returns false if the inbound buffer is full.
+func (mb *MessageBus) RedeliverInbound(msg InboundMessage) bool {
+	select {
+	case mb.inbound <- msg:
+		return true
+	default:
+		return false
+	}
+}
+
+// RedeliverOutbound pushes a message replayed by the journal without recording
+// it again. Returns false if the outbound buffer is full.
+func (mb *MessageBus) RedeliverOutbound(msg OutboundMessage) bool {
+	select {
+	case mb.outbound <- msg:
+		return true
+	default:
+		return false
+	}
+}
+
+// InboundDedupeKey identifies an inbound message by the channel's own message
+// ID. Returns "" when the channel did not provide one.
+func InboundDedupeKey(msg InboundMessage) string {
+	msgID := msg.Metadata["message_id"]
+	if msgID == "" {
+		return ""
+	}
+	return fmt.Sprintf("%s|%s|%s|%s", msg.Channel, msg.SenderID, msg.ChatID, msgID)
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"strings"
+	"time"
 
 	"github.com/google/uuid"
 )
@@ -30,6 +31,10 @@ type InboundMessage struct {
 	HistoryLimit int               `json:"history_limit,omitempty"` // max turns to keep in context (0=unlimited, from channel config)
 	ToolAllow    []string          `json:"tool_allow,omitempty"`    // per-group tool allow list (nil = no restriction)
 	Metadata     map[string]string `json:"metadata,omitempty"`
+
+	// QueueIDs are the durable queue rows this message (or the messages merged
+	// into it by the debouncer) was journaled as; empty when not journaled.
+	QueueIDs []uuid.UUID `json:"-"`
 }
 
 // OutboundMessage represents a message to be sent to a channel.
@@ -39,6 +44,11 @@ type OutboundMessage struct {
 	Content  string            `json:"content"`
 	Media    []MediaAttachment `json:"media,omitempty"`    // optional media attachments
 	Metadata map[string]string `json:"metadata,omitempty"` // channel-specific metadata
+
+	Interactive *InteractiveMessage `json:"interactive,omitempty"` // optional choices / form (Content is the question)
+
+	QueueID       uuid.UUID `json:"-"` // durable queue row (uuid.Nil = not journaled)
+	QueueAttempts int       `json:"-"` // failed delivery attempts so far
 }
 
 // MediaAttachment represents a media file to be sent with a message.
@@ -53,6 +63,7 @@ type Event struct {
 	Name     string    `json:"name"`              // event name (e.g. "agent", "chat", "health")
 	Payload  any       `json:"payload,omitempty"`
 	TenantID uuid.UUID `json:"-"` // tenant scope for event filtering (not serialized to clients)
+	Origin   string    `json:"-"` // cluster node that relayed this event ("" = raised locally)
 }
 
 // Cache invalidation kind constants.
@@ -94,6 +105,7 @@ const (
 	TopicCacheConfigPerms      = "cache:config_perms"
 	TopicAudit                 = "audit"
 	TopicTeamTaskAudit         = "team-task-audit"
+	TopicTeamTaskDispatch      = "team-task-dispatch"
 	TopicChannelStreaming      = "channel-streaming"
 	TopicConfigChanged         = "config:changed"
 	TopicSystemConfigChanged   = "system_config:changed"
@@ -111,6 +123,28 @@ type PairingRevokedPayload struct {
 	Channel  string `json:"channel"`
 }
 
+// EventWebhookDeliverySeen is broadcast when a webhook channel accepts an
+// inbound delivery, so replicas that did not serve it reject its replay.
+const EventWebhookDeliverySeen = "webhook.delivery_seen"
+
+// WebhookDeliverySeenPayload identifies an accepted webhook delivery.
+type WebhookDeliverySeenPayload struct {
+	Channel   string    `json:"channel"`
+	Signature string    `json:"signature"`
+	Expires   time.Time `json:"expires"`
+}
+
+// EventMCPOAuthAuthorized is broadcast when a user completes an MCP server
+// authorization, so every replica reloads the user's tools for that server.
+const EventMCPOAuthAuthorized = "mcp.oauth_authorized"
+
+// MCPOAuthAuthorizedPayload identifies the authorized server and user.
+type MCPOAuthAuthorizedPayload struct {
+	ServerID uuid.UUID `json:"server_id"`
+	UserID   string    `json:"user_id"`
+	At       time.Time `json:"at"`
+}
+
 // EventAgentStatusChanged is broadcast when an agent's status changes (e.g., active → inactive).
 const EventAgentStatusChanged = "agent.status_changed"
 
@@ -128,6 +162,21 @@ type AgentDeletedPayload struct {
 	TenantID uuid.UUID `json:"tenant_id,omitempty"`
 }
 
+// BudgetThresholdPayload is broadcast (protocol.EventBudgetThreshold) the first
+// time a spend budget crosses its soft or hard threshold in a calendar month.
+type BudgetThresholdPayload struct {
+	Scope    string    `json:"scope"` // "agent", "tenant", "user"
+	Key      string    `json:"key"`   // agent key, tenant ID or user ID
+	Level    string    `json:"level"` // "soft" or "hard"
+	Action   string    `json:"action,omitempty"`
+	SpentUSD float64   `json:"spent_usd"`
+	LimitUSD float64   `json:"limit_usd"`
+	Month    string    `json:"month"` // "2006-01"
+	AgentID  string    `json:"agent_id,omitempty"`
+	UserID   string    `json:"user_id,omitempty"`
+	TenantID uuid.UUID `json:"tenant_id,omitempty"`
+}
+
 // AuditEventPayload carries audit log data emitted by handlers.
 // A single subscriber persists these to the activity_logs table.
 type AuditEventPayload struct {
@@ -0,0 +1,42 @@
+package cache
+
+import (
+	"context"
+	"time"
+
+	"github.com/nextlevelbuilder/goclaw/internal/metrics"
+)
+
+// instrumentedCache counts hits and misses of an underlying Cache under a
+// fixed name (goclaw_cache_requests_total{cache=name}).
+type instrumentedCache[V any] struct {
+	name  string
+	inner Cache[V]
+}
+
+// Instrumented wraps c so its Get calls are reported to /metrics as name.
+func Instrumented[V any](name string, c Cache[V]) Cache[V] {
+	return &instrumentedCache[V]{name: name, inner: c}
+}
+
+func (c *instrumentedCache[V]) Get(ctx context.Context, key string) (V, bool) {
+	v, ok := c.inner.Get(ctx, key)
+	if ok {
+		metrics.CacheRequests.Inc(c.name, "hit")
+	} else {
+		metrics.CacheRequests.Inc(c.name, "miss")
+	}
+	return v, ok
+}
+
+func (c *instrumentedCache[V]) Set(ctx context.Context, key string, value V, ttl time.Duration) {
+	c.inner.Set(ctx, key, value, ttl)
+}
+
+func (c *instrumentedCache[V]) Delete(ctx context.Context, key string) { c.inner.Delete(ctx, key) }
+
+func (c *instrumentedCache[V]) DeleteByPrefix(ctx context.Context, prefix string) {
+	c.inner.DeleteByPrefix(ctx, prefix)
+}
+
+func (c *instrumentedCache[V]) Clear(ctx context.Context) { c.inner.Clear(ctx) }
@@ -63,6 +63,17 @@ func (c *InMemoryCache[V]) DeleteByPrefix(_ context.Context, prefix string) {
 	})
 }
 
+// DeleteExpired removes every expired entry. Get only drops expired entries it
+// touches, so callers whose keys may never be read again sweep with this.
+func (c *InMemoryCache[V]) DeleteExpired(_ context.Context) {
+	c.data.Range(func(key, raw any) bool {
+		if raw.(entry[V]).expired() {
+			c.data.Delete(key)
+		}
+		return true
+	})
+}
+
 func (c *InMemoryCache[V]) Clear(_ context.Context) {
 	c.data.Range(func(key, _ any) bool {
 		c.data.Delete(key)
@@ -90,3 +90,21 @@ func TestInMemoryCache_Clear(t *testing.T) {
 		}
 	}
 }
+
+func TestInMemoryCache_DeleteExpired(t *testing.T) {
+	c := NewInMemoryCache[int]()
+	ctx := context.Background()
+
+	c.Set(ctx, "old", 1, time.Millisecond)
+	c.Set(ctx, "keep", 2, 0)
+	time.Sleep(5 * time.Millisecond)
+
+	c.DeleteExpired(ctx)
+
+	if _, ok := c.data.Load("old"); ok {
+		t.Error("expired entry should be removed")
+	}
+	if _, ok := c.Get(ctx, "keep"); !ok {
+		t.Error("entry without TTL should remain")
+	}
+}
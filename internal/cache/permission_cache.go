@@ -18,19 +18,19 @@ type agentAccessEntry struct {
 // PermissionCache provides short-TTL caching for hot permission lookups.
 // Uses InMemoryCache[V] caches with pubsub invalidation.
 type PermissionCache struct {
-	tenantResolve *InMemoryCache[uuid.UUID]
-	tenantRole    *InMemoryCache[string]
-	agentAccess   *InMemoryCache[agentAccessEntry]
-	teamAccess    *InMemoryCache[bool]
+	tenantResolve Cache[uuid.UUID]
+	tenantRole    Cache[string]
+	agentAccess   Cache[agentAccessEntry]
+	teamAccess    Cache[bool]
 }
 
 // NewPermissionCache creates a new permission cache.
 func NewPermissionCache() *PermissionCache {
 	return &PermissionCache{
-		tenantResolve: NewInMemoryCache[uuid.UUID](),
-		tenantRole:    NewInMemoryCache[string](),
-		agentAccess:   NewInMemoryCache[agentAccessEntry](),
-		teamAccess:    NewInMemoryCache[bool](),
+		tenantResolve: Instrumented[uuid.UUID]("perm_tenant_resolve", NewInMemoryCache[uuid.UUID]()),
+		tenantRole:    Instrumented[string]("perm_tenant_role", NewInMemoryCache[string]()),
+		agentAccess:   Instrumented[agentAccessEntry]("perm_agent_access", NewInMemoryCache[agentAccessEntry]()),
+		teamAccess:    Instrumented[bool]("perm_team_access", NewInMemoryCache[bool]()),
 	}
 }
 
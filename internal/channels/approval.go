@@ -0,0 +1,145 @@
+package channels
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Approval button decisions (see tools.ApprovalDecision).
+const (
+	ApprovalAllowOnce    = "allow-once"
+	ApprovalAllowSession = "allow-session"
+	ApprovalDeny         = "deny"
+)
+
+// approvalCallbackPrefix marks button payloads of approval requests:
+// "ap:<o|s|d>:<request id>". Short enough for Telegram's 64-byte limit.
+const approvalCallbackPrefix = "ap:"
+
+// ApprovalRequest is a tool call waiting for a human decision, as shown in chat.
+type ApprovalRequest struct {
+	ID        string
+	AgentKey  string
+	Tool      string
+	Args      string // JSON preview, already truncated
+	Reason    string
+	ExpiresAt time.Time
+}
+
+// ApprovalChannel is implemented by channels that can show approval requests
+// with Approve / Approve for session / Deny buttons. Button presses are
+// passed to the channel's ApprovalResolver.
+type ApprovalChannel interface {
+	Channel
+	SendApprovalRequest(ctx context.Context, chatID string, req ApprovalRequest) error
+}
+
+// ApprovalResolver records a decision made from a channel button. senderID
+// is the platform user who pressed it. Returns a short status line to show in
+// place of the buttons.
+type ApprovalResolver func(ctx context.Context, id, decision, channel, senderID string) (string, error)
+
+// SetApprovalResolver sets the handler for approval button presses.
+func (c *BaseChannel) SetApprovalResolver(fn ApprovalResolver) { c.approvalResolver = fn }
+
+// ResolveApproval passes a button press to the approval resolver.
+func (c *BaseChannel) ResolveApproval(ctx context.Context, id, decision, senderID string) (string, error) {
+	if c.approvalResolver == nil {
+		return "", fmt.Errorf("approvals are not enabled")
+	}
+	return c.approvalResolver(ctx, id, decision, c.name, senderID)
+}
+
+// ApprovalCallbackData encodes a button payload.
+func ApprovalCallbackData(id, decision string) string {
+	code := "o"
+	switch decision {
+	case ApprovalAllowSession:
+		code = "s"
+	case ApprovalDeny:
+		code = "d"
+	}
+	return approvalCallbackPrefix + code + ":" + id
+}
+
+// ParseApprovalCallback decodes a button payload made by ApprovalCallbackData.
+func ParseApprovalCallback(data string) (id, decision string, ok bool) {
+	rest, found := strings.CutPrefix(data, approvalCallbackPrefix)
+	if !found {
+		return "", "", false
+	}
+	code, id, found := strings.Cut(rest, ":")
+	if !found || id == "" {
+		return "", "", false
+	}
+	switch code {
+	case "o":
+		return id, ApprovalAllowOnce, true
+	case "s":
+		return id, ApprovalAllowSession, true
+	case "d":
+		return id, ApprovalDeny, true
+	}
+	return "", "", false
+}
+
+// ApprovalButtonLabels returns the labels for the three decisions, in the
+// order they are shown.
+func ApprovalButtonLabels() [3][2]string {
+	return [3][2]string{
+		{ApprovalAllowOnce, "✅ Approve"},
+		{ApprovalAllowSession, "✅ Approve for session"},
+		{ApprovalDeny, "❌ Deny"},
+	}
+}
+
+// FormatApprovalText renders the request body shared by all channels.
+func FormatApprovalText(req ApprovalRequest) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "🔐 Approval needed: agent %s wants to call %s", req.AgentKey, req.Tool)
+	if req.Reason != "" {
+		sb.WriteString("\nReason: " + req.Reason)
+	}
+	if req.Args != "" {
+		sb.WriteString("\nArguments: " + req.Args)
+	}
+	if !req.ExpiresAt.IsZero() {
+		fmt.Fprintf(&sb, "\nDenied automatically if not answered within %s.", time.Until(req.ExpiresAt).Round(time.Second))
+	}
+	return sb.String()
+}
+
+// SetApprovalResolver sets the approval resolver for all current and future channels.
+func (m *Manager) SetApprovalResolver(fn ApprovalResolver) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.approvalResolver = fn
+	for _, ch := range m.channels {
+		if bc, ok := ch.(interface{ SetApprovalResolver(ApprovalResolver) }); ok {
+			bc.SetApprovalResolver(fn)
+		}
+	}
+}
+
+// SendApprovalRequest shows an approval request in a chat, with buttons when
+// the channel supports them and as plain text otherwise (decide from the
+// dashboard). Internal channels are skipped.
+func (m *Manager) SendApprovalRequest(ctx context.Context, channelName, chatID string, req ApprovalRequest) error {
+	if IsInternalChannel(channelName) {
+		return nil
+	}
+	m.mu.RLock()
+	ch, ok := m.channels[channelName]
+	owned := m.ownership == nil || m.ownership.Owns(channelName)
+	m.mu.RUnlock()
+	if !ok {
+		return fmt.Errorf("channel %s not found", channelName)
+	}
+	if ac, ok := ch.(ApprovalChannel); ok && owned && ch.IsRunning() {
+		return ac.SendApprovalRequest(ctx, chatID, req)
+	}
+	text := FormatApprovalText(req) + "\nDecide in the dashboard (request " + req.ID + ")."
+	return m.SendToChannel(ctx, channelName, chatID, text)
+}
@@ -63,6 +63,8 @@ const (
 	TypeWhatsApp     = "whatsapp"
 	TypeZaloOA       = "zalo_oa"
 	TypeZaloPersonal = "zalo_personal"
+	TypeWebhook      = "webhook"
+	TypeEmail        = "email"
 )
 
 // BotIdentityChannel is implemented by channels whose bot has a platform username
@@ -161,14 +163,16 @@ type ReactionChannel interface {
 // BaseChannel provides shared functionality for all channel implementations.
 // Channel implementations should embed this struct.
 type BaseChannel struct {
-	name             string
-	channelType      string // platform type; defaults to name if unset
-	bus              *bus.MessageBus
-	running          bool
-	allowList        []string
-	agentID          string                 // for DB instances: routes to specific agent (empty = use resolveAgentRoute)
-	tenantID         uuid.UUID              // for DB instances: tenant scope (zero = master tenant fallback)
-	contactCollector *store.ContactCollector // optional: auto-collect contacts from channel messages
+	name                string
+	channelType         string // platform type; defaults to name if unset
+	bus                 *bus.MessageBus
+	running             bool
+	allowList           []string
+	agentID             string                  // for DB instances: routes to specific agent (empty = use resolveAgentRoute)
+	tenantID            uuid.UUID               // for DB instances: tenant scope (zero = master tenant fallback)
+	contactCollector    *store.ContactCollector // optional: auto-collect contacts from channel messages
+	approvalResolver    ApprovalResolver        // optional: handles tool approval button presses
+	interactiveResolver InteractiveResolver     // optional: handles answers to interactive messages
 }
 
 // NewBaseChannel creates a new BaseChannel with the given parameters.
@@ -0,0 +1,92 @@
+package discord
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/bwmarrin/discordgo"
+
+	"github.com/nextlevelbuilder/goclaw/internal/channels"
+	"github.com/nextlevelbuilder/goclaw/internal/store"
+)
+
+// SendApprovalRequest shows a tool approval request with message buttons.
+// Implements channels.ApprovalChannel.
+func (c *Channel) SendApprovalRequest(_ context.Context, chatID string, req channels.ApprovalRequest) error {
+	if !c.IsRunning() {
+		return fmt.Errorf("discord bot not running")
+	}
+	var buttons []discordgo.MessageComponent
+	for _, b := range channels.ApprovalButtonLabels() {
+		style := discordgo.SecondaryButton
+		switch b[0] {
+		case channels.ApprovalAllowOnce:
+			style = discordgo.SuccessButton
+		case channels.ApprovalDeny:
+			style = discordgo.DangerButton
+		}
+		buttons = append(buttons, discordgo.Button{Label: b[1], Style: style, CustomID: channels.ApprovalCallbackData(req.ID, b[0])})
+	}
+	content := channels.FormatApprovalText(req)
+	if len(content) > 2000 {
+		content = content[:1997] + "..."
+	}
+	_, err := c.session.ChannelMessageSendComplex(chatID, &discordgo.MessageSend{
+		Content:    content,
+		Components: []discordgo.MessageComponent{discordgo.ActionsRow{Components: buttons}},
+	})
+	return err
+}
+
+// handleInteraction handles message component and modal interactions: tool
+// approvals and answers to interactive questions.
+func (c *Channel) handleInteraction(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	var customID string
+	switch i.Type {
+	case discordgo.InteractionMessageComponent:
+		customID = i.MessageComponentData().CustomID
+	case discordgo.InteractionModalSubmit:
+		customID = i.ModalSubmitData().CustomID
+	default:
+		return
+	}
+	user := i.User
+	if i.Member != nil && i.Member.User != nil {
+		user = i.Member.User
+	}
+	if user == nil {
+		return
+	}
+
+	ctx := store.WithTenantID(context.Background(), c.TenantID())
+	if qid, action, index, ok := channels.ParseInteractiveCallback(customID); ok {
+		c.handleInteractiveInteraction(ctx, s, i, user, qid, action, index)
+		return
+	}
+	id, decision, ok := channels.ParseApprovalCallback(customID)
+	if !ok {
+		return
+	}
+	status, err := c.ResolveApproval(ctx, id, decision, user.ID)
+	var resp *discordgo.InteractionResponse
+	if err != nil {
+		resp = &discordgo.InteractionResponse{
+			Type: discordgo.InteractionResponseChannelMessageWithSource,
+			Data: &discordgo.InteractionResponseData{Content: err.Error(), Flags: discordgo.MessageFlagsEphemeral},
+		}
+	} else {
+		// Replace the buttons with the outcome.
+		content := status
+		if i.Message != nil {
+			content = i.Message.Content + "\n\n" + status
+		}
+		resp = &discordgo.InteractionResponse{
+			Type: discordgo.InteractionResponseUpdateMessage,
+			Data: &discordgo.InteractionResponseData{Content: content, Components: []discordgo.MessageComponent{}},
+		}
+	}
+	if err := s.InteractionRespond(i.Interaction, resp); err != nil {
+		slog.Debug("discord: approval interaction response failed", "id", id, "error", err)
+	}
+}
@@ -17,18 +17,14 @@ type discordCreds struct {
 
 // discordInstanceConfig maps the non-secret config JSONB from the channel_instances table.
 type discordInstanceConfig struct {
-	DMPolicy          string   `json:"dm_policy,omitempty"`
-	GroupPolicy       string   `json:"group_policy,omitempty"`
-	AllowFrom         []string `json:"allow_from,omitempty"`
-	RequireMention    *bool    `json:"require_mention,omitempty"`
-	HistoryLimit      int      `json:"history_limit,omitempty"`
-	BlockReply        *bool    `json:"block_reply,omitempty"`
-	MediaMaxBytes     int64    `json:"media_max_bytes,omitempty"`
-	STTProxyURL       string   `json:"stt_proxy_url,omitempty"`
-	STTAPIKey         string   `json:"stt_api_key,omitempty"`
-	STTTenantID       string   `json:"stt_tenant_id,omitempty"`
-	STTTimeoutSeconds int      `json:"stt_timeout_seconds,omitempty"`
-	VoiceAgentID      string   `json:"voice_agent_id,omitempty"`
+	DMPolicy       string   `json:"dm_policy,omitempty"`
+	GroupPolicy    string   `json:"group_policy,omitempty"`
+	AllowFrom      []string `json:"allow_from,omitempty"`
+	RequireMention *bool    `json:"require_mention,omitempty"`
+	HistoryLimit   int      `json:"history_limit,omitempty"`
+	BlockReply     *bool    `json:"block_reply,omitempty"`
+	MediaMaxBytes  int64    `json:"media_max_bytes,omitempty"`
+	VoiceAgentID   string   `json:"voice_agent_id,omitempty"`
 }
 
 // Factory creates a Discord channel from DB instance data (no extra stores).
@@ -68,20 +64,16 @@ func buildChannel(name string, creds json.RawMessage, cfg json.RawMessage,
 	}
 
 	dcCfg := config.DiscordConfig{
-		Enabled:           true,
-		Token:             c.Token,
-		AllowFrom:         ic.AllowFrom,
-		DMPolicy:          ic.DMPolicy,
-		GroupPolicy:       ic.GroupPolicy,
-		RequireMention:    ic.RequireMention,
-		HistoryLimit:      ic.HistoryLimit,
-		BlockReply:        ic.BlockReply,
-		MediaMaxBytes:     ic.MediaMaxBytes,
-		STTProxyURL:       ic.STTProxyURL,
-		STTAPIKey:         ic.STTAPIKey,
-		STTTenantID:       ic.STTTenantID,
-		STTTimeoutSeconds: ic.STTTimeoutSeconds,
-		VoiceAgentID:      ic.VoiceAgentID,
+		Enabled:        true,
+		Token:          c.Token,
+		AllowFrom:      ic.AllowFrom,
+		DMPolicy:       ic.DMPolicy,
+		GroupPolicy:    ic.GroupPolicy,
+		RequireMention: ic.RequireMention,
+		HistoryLimit:   ic.HistoryLimit,
+		BlockReply:     ic.BlockReply,
+		MediaMaxBytes:  ic.MediaMaxBytes,
+		VoiceAgentID:   ic.VoiceAgentID,
 	}
 
 	// DB instances default to "pairing" for groups (secure by default).
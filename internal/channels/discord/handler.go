@@ -112,16 +112,6 @@ func (c *Channel) handleMessage(_ *discordgo.Session, m *discordgo.MessageCreate
 			mi := &mediaList[i]
 
 			switch mi.Type {
-			case media.TypeAudio, media.TypeVoice:
-				transcript, sttErr := c.transcribeAudio(context.Background(), mi.FilePath)
-				if sttErr != nil {
-					slog.Warn("discord: STT transcription failed",
-						"type", mi.Type, "error", sttErr,
-					)
-				} else {
-					mi.Transcript = transcript
-				}
-
 			case media.TypeDocument:
 				if mi.FileName != "" && mi.FilePath != "" {
 					docContent, err := media.ExtractDocumentContent(mi.FilePath, mi.FileName)
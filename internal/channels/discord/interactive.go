@@ -0,0 +1,195 @@
+package discord
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strconv"
+
+	"github.com/bwmarrin/discordgo"
+
+	"github.com/nextlevelbuilder/goclaw/internal/bus"
+	"github.com/nextlevelbuilder/goclaw/internal/channels"
+)
+
+// Discord component limits.
+const (
+	discordButtonsPerRow = 5
+	discordMaxRows       = 5
+	discordMaxSelect     = 25
+	discordModalInputs   = 5
+	discordModalLabelMax = 45
+)
+
+// SendInteractive shows a question with message components: buttons, a select
+// menu (single or multi) or, for forms, a button that opens a modal.
+// Implements channels.InteractiveChannel.
+func (c *Channel) SendInteractive(ctx context.Context, msg bus.OutboundMessage) error {
+	if !c.IsRunning() {
+		return fmt.Errorf("discord bot not running")
+	}
+	im := msg.Interactive
+	if !im.IsChoice() && len(im.Fields) > discordModalInputs {
+		// Too many fields for a modal: answer by text reply.
+		msg.Content = channels.FormatInteractiveText(msg.Content, im)
+		msg.Interactive = nil
+		return c.Send(ctx, msg)
+	}
+
+	var rows []discordgo.MessageComponent
+	switch {
+	case im.Kind == bus.InteractiveButtons && len(im.Options) <= discordButtonsPerRow*discordMaxRows:
+		var row discordgo.ActionsRow
+		for i, o := range im.Options {
+			if len(row.Components) == discordButtonsPerRow {
+				rows = append(rows, row)
+				row = discordgo.ActionsRow{}
+			}
+			row.Components = append(row.Components, discordgo.Button{
+				Label:    truncateLabel(o.Label, 80),
+				Style:    discordgo.SecondaryButton,
+				CustomID: channels.InteractiveCallbackData(im.ID, channels.InteractiveActionPick, i),
+			})
+		}
+		rows = append(rows, row)
+	case im.IsChoice():
+		if len(im.Options) > discordMaxSelect {
+			msg.Content = channels.FormatInteractiveText(msg.Content, im)
+			msg.Interactive = nil
+			return c.Send(ctx, msg)
+		}
+		menu := discordgo.SelectMenu{
+			MenuType:    discordgo.StringSelectMenu,
+			CustomID:    channels.InteractiveCallbackData(im.ID, channels.InteractiveActionSubmit, 0),
+			Placeholder: "Choose…",
+		}
+		if im.Kind == bus.InteractiveMulti {
+			minValues := 1
+			menu.MinValues = &minValues
+			menu.MaxValues = len(im.Options)
+		}
+		for i, o := range im.Options {
+			menu.Options = append(menu.Options, discordgo.SelectMenuOption{Label: truncateLabel(o.Label, 100), Value: strconv.Itoa(i)})
+		}
+		rows = append(rows, discordgo.ActionsRow{Components: []discordgo.MessageComponent{menu}})
+	default:
+		rows = append(rows, discordgo.ActionsRow{Components: []discordgo.MessageComponent{discordgo.Button{
+			Label:    "Answer",
+			Style:    discordgo.PrimaryButton,
+			CustomID: channels.InteractiveCallbackData(im.ID, channels.InteractiveActionForm, 0),
+		}}})
+	}
+
+	content := msg.Content
+	if len(content) > 2000 {
+		content = content[:1997] + "..."
+	}
+	_, err := c.session.ChannelMessageSendComplex(msg.ChatID, &discordgo.MessageSend{Content: content, Components: rows})
+	return err
+}
+
+// handleInteractiveInteraction handles components and modal submits of a
+// question. The form button opens a modal; everything else answers.
+func (c *Channel) handleInteractiveInteraction(ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate, user *discordgo.User, id, action string, index int) {
+	im, ok := channels.LookupInteractive(id)
+	if !ok {
+		c.respondEphemeral(s, i, "This question is no longer active.")
+		return
+	}
+	if !channels.InteractiveSenderAllowed(im, user.ID) {
+		c.respondEphemeral(s, i, "Only the person who was asked can answer.")
+		return
+	}
+
+	answer := bus.InteractiveAnswer{ID: id, SenderID: user.ID}
+	switch {
+	case i.Type == discordgo.InteractionModalSubmit:
+		answer.Fields = map[string]string{}
+		for _, comp := range i.ModalSubmitData().Components {
+			row, ok := comp.(*discordgo.ActionsRow)
+			if !ok {
+				continue
+			}
+			for _, rc := range row.Components {
+				if in, ok := rc.(*discordgo.TextInput); ok && in.Value != "" {
+					answer.Fields[in.CustomID] = in.Value
+				}
+			}
+		}
+	case action == channels.InteractiveActionForm:
+		c.openInteractiveModal(s, i, im)
+		return
+	case action == channels.InteractiveActionPick:
+		if answer, ok = channels.InteractivePickAnswer(id, index, user.ID); !ok {
+			c.respondEphemeral(s, i, "This question is no longer active.")
+			return
+		}
+	default:
+		for _, v := range i.MessageComponentData().Values {
+			if n, err := strconv.Atoi(v); err == nil && n >= 0 && n < len(im.Options) {
+				answer.Choices = append(answer.Choices, im.Options[n].Value)
+			}
+		}
+	}
+
+	status, err := c.AnswerInteractive(ctx, answer)
+	if err != nil {
+		c.respondEphemeral(s, i, err.Error())
+		return
+	}
+	// Replace the components with the answer.
+	content := status
+	if i.Message != nil {
+		content = i.Message.Content + "\n\n" + status
+	}
+	resp := &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseUpdateMessage,
+		Data: &discordgo.InteractionResponseData{Content: content, Components: []discordgo.MessageComponent{}},
+	}
+	if err := s.InteractionRespond(i.Interaction, resp); err != nil {
+		slog.Debug("discord: interactive response failed", "id", id, "error", err)
+	}
+}
+
+// openInteractiveModal responds with a modal holding one text input per field.
+func (c *Channel) openInteractiveModal(s *discordgo.Session, i *discordgo.InteractionCreate, im *bus.InteractiveMessage) {
+	var rows []discordgo.MessageComponent
+	for _, f := range im.Fields {
+		rows = append(rows, discordgo.ActionsRow{Components: []discordgo.MessageComponent{discordgo.TextInput{
+			CustomID:    f.Name,
+			Label:       truncateLabel(f.Label, discordModalLabelMax),
+			Style:       discordgo.TextInputShort,
+			Placeholder: truncateLabel(f.Placeholder, 100),
+			Required:    f.Required,
+		}}})
+	}
+	resp := &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseModal,
+		Data: &discordgo.InteractionResponseData{
+			CustomID:   channels.InteractiveCallbackData(im.ID, channels.InteractiveActionSubmit, 0),
+			Title:      "Answer",
+			Components: rows,
+		},
+	}
+	if err := s.InteractionRespond(i.Interaction, resp); err != nil {
+		slog.Debug("discord: interactive modal failed", "id", im.ID, "error", err)
+	}
+}
+
+func (c *Channel) respondEphemeral(s *discordgo.Session, i *discordgo.InteractionCreate, text string) {
+	resp := &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{Content: text, Flags: discordgo.MessageFlagsEphemeral},
+	}
+	if err := s.InteractionRespond(i.Interaction, resp); err != nil {
+		slog.Debug("discord: interaction response failed", "error", err)
+	}
+}
+
+// truncateLabel cuts s to max runes.
+func truncateLabel(s string, max int) string {
+	if r := []rune(s); len(r) > max {
+		return string(r[:max-1]) + "…"
+	}
+	return s
+}
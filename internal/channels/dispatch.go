@@ -9,7 +9,10 @@ import (
 	"regexp"
 	"strings"
 
+	"github.com/google/uuid"
+
 	"github.com/nextlevelbuilder/goclaw/internal/bus"
+	"github.com/nextlevelbuilder/goclaw/internal/metrics"
 )
 
 // WebhookRoute holds a path and handler pair for mounting on the main gateway mux.
@@ -33,82 +36,125 @@ func (m *Manager) dispatchOutbound(ctx context.Context) {
 			if !ok {
 				continue
 			}
+			m.dispatchOne(ctx, msg)
+		}
+	}
+}
 
-			// Skip internal channels
-			if IsInternalChannel(msg.Channel) {
-				continue
-			}
+// dispatchOne delivers one outbound message. A journaled message is acked
+// once it was sent or deliberately skipped; a failed send is handed back to
+// the durable queue for retry.
+func (m *Manager) dispatchOne(ctx context.Context, msg bus.OutboundMessage) {
+	// Skip internal channels
+	if IsInternalChannel(msg.Channel) {
+		m.bus.AckOutbound(msg)
+		return
+	}
+
+	m.mu.RLock()
+	channel, exists := m.channels[msg.Channel]
+	m.mu.RUnlock()
 
-			m.mu.RLock()
-			channel, exists := m.channels[msg.Channel]
-			m.mu.RUnlock()
+	if !exists {
+		slog.Warn("unknown channel for outbound message", "channel", msg.Channel)
+		// The channel may be starting up or reloading; retry if journaled.
+		m.bus.FailOutbound(msg, fmt.Errorf("channel %s not found", msg.Channel))
+		return
+	}
 
-			if !exists {
-				slog.Warn("unknown channel for outbound message", "channel", msg.Channel)
-				continue
-			}
+	// Cluster mode: the channel runs on another replica.
+	if m.ownership != nil && !m.ownership.Owns(msg.Channel) {
+		if err := m.ownership.Forward(msg); err != nil {
+			slog.Warn("failed to forward outbound message", "channel", msg.Channel, "error", err)
+			m.bus.FailOutbound(msg, err)
+			return
+		}
+		m.bus.AckOutbound(msg)
+		removeTempMedia(msg)
+		return
+	}
 
-			// Filter out temp media files that no longer exist (already sent by another dispatch).
-			if len(msg.Media) > 0 {
-				tmpDir := os.TempDir()
-				filtered := msg.Media[:0]
-				for _, media := range msg.Media {
-					if media.URL != "" && strings.HasPrefix(media.URL, tmpDir) {
-						if _, err := os.Stat(media.URL); err != nil {
-							slog.Debug("skipping already-delivered temp media", "path", media.URL)
-							continue
-						}
-					}
-					filtered = append(filtered, media)
-				}
-				msg.Media = filtered
-				// If only media was in this message and all files are gone, skip entirely.
-				if len(msg.Media) == 0 && msg.Content == "" {
+	// Filter out temp media files that no longer exist (already sent by another dispatch).
+	if len(msg.Media) > 0 {
+		tmpDir := os.TempDir()
+		filtered := msg.Media[:0]
+		for _, media := range msg.Media {
+			if media.URL != "" && strings.HasPrefix(media.URL, tmpDir) {
+				if _, err := os.Stat(media.URL); err != nil {
+					slog.Debug("skipping already-delivered temp media", "path", media.URL)
 					continue
 				}
 			}
+			filtered = append(filtered, media)
+		}
+		msg.Media = filtered
+		// If only media was in this message and all files are gone, skip entirely.
+		if len(msg.Media) == 0 && msg.Content == "" {
+			m.bus.AckOutbound(msg)
+			return
+		}
+	}
 
-			if err := channel.Send(ctx, msg); err != nil {
-				slog.Error("error sending message to channel",
-					"channel", msg.Channel,
-					"error", err,
-				)
-				// Try to send a text-only error notification back to the chat.
-				// Only for media failures — text-only failures likely mean the chat
-				// is inaccessible (kicked, blocked, etc.) so retrying won't help.
-				if len(msg.Media) > 0 {
-					notifyMsg := bus.OutboundMessage{
-						Channel:  msg.Channel,
-						ChatID:   msg.ChatID,
-						Content:  formatChannelSendError(err),
-						Metadata: sendErrorMeta(msg.Metadata),
-					}
-					if err2 := channel.Send(ctx, notifyMsg); err2 != nil {
-						slog.Warn("failed to send error notification",
-							"channel", msg.Channel, "error", err2)
-					}
-				}
-			} else {
-				// Cross-bot relay: after successful send, check if the message
-				// mentions another bot in the same group. Telegram doesn't deliver
-				// bot-to-bot messages, so we relay internally via the message bus.
-				m.relayCrossBotMentions(msg)
+	err := sendOutbound(ctx, channel, msg)
+	metrics.ChannelMessages.Inc(channelTenantLabel(channel), msg.Channel, "outbound", metrics.Status(err != nil))
+	if err != nil {
+		slog.Error("error sending message to channel",
+			"channel", msg.Channel,
+			"error", err,
+		)
+		// Journaled messages are retried with backoff; keep their media until
+		// the final attempt and only notify the chat once it was given up.
+		if m.bus.FailOutbound(msg, err) {
+			return
+		}
+		// Try to send a text-only error notification back to the chat.
+		// Only for media failures — text-only failures likely mean the chat
+		// is inaccessible (kicked, blocked, etc.) so retrying won't help.
+		if len(msg.Media) > 0 {
+			notifyMsg := bus.OutboundMessage{
+				Channel:  msg.Channel,
+				ChatID:   msg.ChatID,
+				Content:  formatChannelSendError(err),
+				Metadata: sendErrorMeta(msg.Metadata),
 			}
+			if err2 := channel.Send(ctx, notifyMsg); err2 != nil {
+				slog.Warn("failed to send error notification",
+					"channel", msg.Channel, "error", err2)
+			}
+		}
+	} else {
+		m.bus.AckOutbound(msg)
+		// Cross-bot relay: after successful send, check if the message
+		// mentions another bot in the same group. Telegram doesn't deliver
+		// bot-to-bot messages, so we relay internally via the message bus.
+		m.relayCrossBotMentions(msg)
+	}
 
-			// Clean up temp media files only. Workspace-generated files are preserved
-			// so they remain accessible via workspace/web UI after delivery.
-			tmpDir := os.TempDir()
-			for _, media := range msg.Media {
-				if media.URL != "" && strings.HasPrefix(media.URL, tmpDir) {
-					if err := os.Remove(media.URL); err != nil {
-						slog.Debug("failed to clean up media file", "path", media.URL, "error", err)
-					}
-				}
+	removeTempMedia(msg)
+}
+
+// removeTempMedia cleans up temp media files only. Workspace-generated files
+// are preserved so they remain accessible via workspace/web UI after delivery.
+func removeTempMedia(msg bus.OutboundMessage) {
+	tmpDir := os.TempDir()
+	for _, media := range msg.Media {
+		if media.URL != "" && strings.HasPrefix(media.URL, tmpDir) {
+			if err := os.Remove(media.URL); err != nil {
+				slog.Debug("failed to clean up media file", "path", media.URL, "error", err)
 			}
 		}
 	}
 }
 
+// channelTenantLabel returns the tenant label of a channel instance ("" for
+// config-based channels, which have no tenant).
+func channelTenantLabel(ch Channel) string {
+	if t, ok := ch.(interface{ TenantID() uuid.UUID }); ok {
+		return metrics.TenantLabel(t.TenantID())
+	}
+	return ""
+}
+
 // WebhookHandlers returns all webhook handlers from channels that implement WebhookChannel.
 // Used to mount webhook routes on the main gateway mux.
 func (m *Manager) WebhookHandlers() []WebhookRoute {
@@ -142,6 +188,9 @@ func (m *Manager) SendToChannel(ctx context.Context, channelName, chatID, conten
 		Content: content,
 	}
 
+	if m.ownership != nil && !m.ownership.Owns(channelName) {
+		return m.ownership.Forward(msg)
+	}
 	return channel.Send(ctx, msg)
 }
 
@@ -0,0 +1,496 @@
+// Package email implements an IMAP/SMTP email channel.
+//
+// Inbound: the mailbox is polled over IMAP for unseen messages (IDLE is not
+// used — one short-lived connection per poll keeps the client small and
+// survives flaky servers). Each message is parsed into text and attachments and
+// threaded by Message-ID/References, so every email thread becomes its own
+// session ("<sender>#<thread hash>" chat ID).
+//
+// Outbound: replies go out over SMTP with In-Reply-To/References set so they
+// land in the sender's existing thread. Local media paths are attached.
+package email
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/mail"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nextlevelbuilder/goclaw/internal/bus"
+	"github.com/nextlevelbuilder/goclaw/internal/channels"
+	"github.com/nextlevelbuilder/goclaw/internal/channels/media"
+	"github.com/nextlevelbuilder/goclaw/internal/config"
+	"github.com/nextlevelbuilder/goclaw/internal/store"
+)
+
+const (
+	securityNone     = "none"
+	securityTLS      = "tls"
+	securityStartTLS = "starttls"
+
+	defaultMailbox      = "INBOX"
+	defaultPollInterval = 60 * time.Second
+	defaultMediaMaxMB   = 20
+	maxFetchPerPoll     = 20
+	pairingDebounce     = 60 * time.Second
+	threadTTL           = 7 * 24 * time.Hour // reply context kept after a thread's last message
+)
+
+// threadState is what Send needs to answer inside an existing email thread.
+type threadState struct {
+	To            string    // correspondent address
+	Subject       string    // original subject (without "Re:")
+	LastMessageID string    // In-Reply-To for the next reply
+	References    []string  // References chain for the next reply
+	UpdatedAt     time.Time // last inbound message; entries expire after threadTTL
+}
+
+// Channel polls an IMAP mailbox and replies over SMTP.
+type Channel struct {
+	*channels.BaseChannel
+	cfg             config.EmailConfig
+	pollInterval    time.Duration
+	dmPolicy        string
+	allow           []string // lowercased allow_from entries
+	pairingService  store.PairingStore
+	pairingDebounce sync.Map // sender address → time.Time
+	threads         sync.Map // chatID → *threadState
+	cancel          context.CancelFunc
+	wg              sync.WaitGroup
+	stopOnce        sync.Once
+}
+
+// New creates an email channel. Address, IMAP host and SMTP host are required.
+func New(cfg config.EmailConfig, msgBus *bus.MessageBus, pairingSvc store.PairingStore) (*Channel, error) {
+	if cfg.Address == "" {
+		return nil, fmt.Errorf("email address is required")
+	}
+	addr, err := mail.ParseAddress(cfg.Address)
+	if err != nil {
+		return nil, fmt.Errorf("invalid email address %q: %w", cfg.Address, err)
+	}
+	if cfg.IMAPHost == "" || cfg.SMTPHost == "" {
+		return nil, fmt.Errorf("email imap_host and smtp_host are required")
+	}
+
+	if cfg.Username == "" {
+		cfg.Username = addr.Address
+	}
+	if cfg.Mailbox == "" {
+		cfg.Mailbox = defaultMailbox
+	}
+	if cfg.IMAPSecurity == "" {
+		cfg.IMAPSecurity = securityTLS
+	}
+	if cfg.IMAPPort <= 0 {
+		cfg.IMAPPort = 993
+		if cfg.IMAPSecurity == securityNone {
+			cfg.IMAPPort = 143
+		}
+	}
+	if cfg.SMTPSecurity == "" {
+		cfg.SMTPSecurity = securityStartTLS
+	}
+	if cfg.SMTPPort <= 0 {
+		switch cfg.SMTPSecurity {
+		case securityTLS:
+			cfg.SMTPPort = 465
+		case securityNone:
+			cfg.SMTPPort = 25
+		default:
+			cfg.SMTPPort = 587
+		}
+	}
+	if cfg.MediaMaxMB <= 0 {
+		cfg.MediaMaxMB = defaultMediaMaxMB
+	}
+	interval := defaultPollInterval
+	if cfg.PollInterval > 0 {
+		interval = time.Duration(cfg.PollInterval) * time.Second
+	}
+
+	// Addresses are case-insensitive; normalize once so IsAllowed can compare directly.
+	allow := make([]string, 0, len(cfg.AllowFrom))
+	for _, a := range cfg.AllowFrom {
+		if a = strings.ToLower(strings.TrimSpace(a)); a != "" {
+			allow = append(allow, a)
+		}
+	}
+
+	base := channels.NewBaseChannel(channels.TypeEmail, msgBus, allow)
+	dmPolicy := cfg.DMPolicy
+	if dmPolicy == "" {
+		// Anyone can send email; default to allowlist rather than the chat-app "pairing" default.
+		dmPolicy = "allowlist"
+	}
+	base.ValidatePolicy(dmPolicy, "")
+
+	return &Channel{
+		BaseChannel:    base,
+		cfg:            cfg,
+		pollInterval:   interval,
+		dmPolicy:       dmPolicy,
+		allow:          allow,
+		pairingService: pairingSvc,
+	}, nil
+}
+
+// BlockReplyEnabled returns the per-channel block_reply override. Email defaults
+// to off: each streamed block would otherwise arrive as a separate email.
+func (c *Channel) BlockReplyEnabled() *bool {
+	if c.cfg.BlockReply != nil {
+		return c.cfg.BlockReply
+	}
+	off := false
+	return &off
+}
+
+// IsAllowed matches sender addresses case-insensitively. Entries may be a full
+// address, "@example.com" or "*@example.com" (whole domain).
+func (c *Channel) IsAllowed(senderID string) bool {
+	if !c.HasAllowList() {
+		return true
+	}
+	addr := strings.ToLower(senderID)
+	_, domain, _ := strings.Cut(addr, "@")
+	for _, a := range c.allow {
+		switch {
+		case a == "*" || a == addr:
+			return true
+		case domain != "" && (a == "@"+domain || a == "*@"+domain):
+			return true
+		}
+	}
+	return false
+}
+
+// Start launches the mailbox poll loop.
+func (c *Channel) Start(ctx context.Context) error {
+	slog.Info("starting email channel", "name", c.Name(), "address", c.cfg.Address,
+		"imap", c.cfg.IMAPHost, "smtp", c.cfg.SMTPHost, "poll_interval", c.pollInterval)
+
+	// Detach from the caller's context (reloads start channels with request-scoped contexts).
+	pollCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	c.cancel = cancel
+	c.wg.Add(1)
+	go c.pollLoop(pollCtx)
+	c.SetRunning(true)
+	return nil
+}
+
+// Stop stops polling and waits for an in-flight poll to finish.
+func (c *Channel) Stop(_ context.Context) error {
+	slog.Info("stopping email channel", "name", c.Name())
+	c.stopOnce.Do(func() {
+		if c.cancel != nil {
+			c.cancel()
+		}
+		c.wg.Wait()
+	})
+	c.SetRunning(false)
+	return nil
+}
+
+func (c *Channel) pollLoop(ctx context.Context) {
+	defer c.wg.Done()
+	ticker := time.NewTicker(c.pollInterval)
+	defer ticker.Stop()
+	for {
+		if err := c.poll(ctx); err != nil && ctx.Err() == nil {
+			slog.Warn("email poll failed", "channel", c.Name(), "error", err)
+		}
+		c.sweepMaps()
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// sweepMaps drops expired thread state and pairing debounce entries.
+func (c *Channel) sweepMaps() {
+	now := time.Now()
+	c.threads.Range(func(k, v any) bool {
+		if now.Sub(v.(*threadState).UpdatedAt) > threadTTL {
+			c.threads.Delete(k)
+		}
+		return true
+	})
+	c.pairingDebounce.Range(func(k, v any) bool {
+		if now.Sub(v.(time.Time)) > pairingDebounce {
+			c.pairingDebounce.Delete(k)
+		}
+		return true
+	})
+}
+
+// poll fetches unseen messages, handles them and marks them seen. A message is
+// only marked seen after it was handled, so a crash mid-poll re-delivers it.
+func (c *Channel) poll(ctx context.Context) error {
+	cl, err := dialIMAP(ctx, c.cfg.IMAPHost, c.cfg.IMAPPort, c.cfg.IMAPSecurity, c.maxMessageBytes())
+	if err != nil {
+		return err
+	}
+	defer cl.Close()
+	defer cl.Logout()
+
+	if err := cl.Login(c.cfg.Username, c.cfg.Password); err != nil {
+		return fmt.Errorf("imap login: %w", err)
+	}
+	if err := cl.Select(c.cfg.Mailbox); err != nil {
+		return fmt.Errorf("imap select %s: %w", c.cfg.Mailbox, err)
+	}
+	uids, err := cl.SearchUnseen()
+	if err != nil {
+		return fmt.Errorf("imap search: %w", err)
+	}
+	if len(uids) > maxFetchPerPoll {
+		uids = uids[:maxFetchPerPoll]
+	}
+
+	for _, uid := range uids {
+		if ctx.Err() != nil {
+			return nil
+		}
+		raw, err := cl.FetchRaw(uid)
+		switch {
+		case errors.Is(err, errMessageTooLarge):
+			// Marked seen below so it is not fetched again on every poll.
+			slog.Warn("email: oversized message skipped", "channel", c.Name(), "uid", uid, "limit_bytes", c.maxMessageBytes())
+		case err != nil:
+			return fmt.Errorf("imap fetch %d: %w", uid, err)
+		default:
+			c.handleRaw(ctx, raw)
+		}
+		if err := cl.MarkSeen(uid); err != nil {
+			return fmt.Errorf("imap store %d: %w", uid, err)
+		}
+	}
+	return nil
+}
+
+// maxMessageBytes bounds a fetched message. Attachments are base64-encoded
+// (about 4/3 of their size), so twice the media limit leaves room for the
+// encoding overhead, headers and body text.
+func (c *Channel) maxMessageBytes() int64 {
+	return 2 * int64(c.cfg.MediaMaxMB) * 1024 * 1024
+}
+
+// handleRaw parses one message, applies policy and publishes it to the bus.
+func (c *Channel) handleRaw(ctx context.Context, raw []byte) {
+	m, err := parseMail(raw, int64(c.cfg.MediaMaxMB)*1024*1024)
+	if err != nil {
+		slog.Warn("email: unparseable message skipped", "channel", c.Name(), "error", err)
+		return
+	}
+	sender := strings.ToLower(m.From.Address)
+	if strings.EqualFold(sender, c.ownAddress()) {
+		return // our own reply copied into the mailbox
+	}
+	if m.AutoReply {
+		slog.Debug("email: auto-submitted message ignored", "sender", sender, "subject", m.Subject)
+		return
+	}
+
+	root := m.threadRoot()
+	if root == "" {
+		root = sender + "|" + m.Subject
+	}
+	chatID := chatIDFor(sender, root)
+
+	if !c.checkDMPolicy(ctx, sender, chatID) {
+		return
+	}
+
+	refs := m.References
+	if m.MessageID != "" {
+		refs = append(append([]string(nil), refs...), m.MessageID)
+	}
+	c.threads.Store(chatID, &threadState{
+		To:            m.From.Address,
+		Subject:       stripReplyPrefix(m.Subject),
+		LastMessageID: m.MessageID,
+		References:    refs,
+		UpdatedAt:     time.Now(),
+	})
+
+	content := m.Text
+	if m.Subject != "" {
+		content = "Subject: " + m.Subject + "\n\n" + content
+	}
+	mediaFiles, mediaContent := c.saveAttachments(m.Attachments)
+	if mediaContent != "" {
+		content += mediaContent
+	}
+
+	slog.Debug("email message received", "sender", sender, "chat_id", chatID,
+		"subject", m.Subject, "attachments", len(m.Attachments))
+
+	if cc := c.ContactCollector(); cc != nil {
+		cc.EnsureContact(ctx, c.Type(), c.Name(), sender, sender, m.From.Name, "", "direct", "user")
+	}
+
+	c.Bus().PublishInbound(bus.InboundMessage{
+		Channel:  c.Name(),
+		SenderID: sender,
+		ChatID:   chatID,
+		Content:  content,
+		Media:    mediaFiles,
+		PeerKind: "direct",
+		UserID:   sender,
+		AgentID:  c.AgentID(),
+		TenantID: c.TenantID(),
+		Metadata: map[string]string{
+			"message_id":   m.MessageID,
+			"subject":      m.Subject,
+			"display_name": m.From.Name,
+			"platform":     channels.TypeEmail,
+		},
+	})
+}
+
+// saveAttachments writes attachments to temp files and returns them as media
+// plus the media tags / extracted document text to append to the content.
+func (c *Channel) saveAttachments(atts []attachment) ([]bus.MediaFile, string) {
+	if len(atts) == 0 {
+		return nil, ""
+	}
+	var files []bus.MediaFile
+	var infos []media.MediaInfo
+	var extra string
+	for _, a := range atts {
+		ct := a.ContentType
+		if ct == "" || ct == "application/octet-stream" {
+			ct = media.DetectMIMEType(a.FileName)
+		}
+		f, err := os.CreateTemp("", "goclaw_email_*"+filepath.Ext(a.FileName))
+		if err != nil {
+			slog.Warn("email: failed to save attachment", "file", a.FileName, "error", err)
+			continue
+		}
+		_, werr := f.Write(a.Data)
+		f.Close()
+		if werr != nil {
+			os.Remove(f.Name())
+			slog.Warn("email: failed to save attachment", "file", a.FileName, "error", werr)
+			continue
+		}
+
+		mi := media.MediaInfo{
+			Type:        media.MediaKindFromMime(ct),
+			FilePath:    f.Name(),
+			ContentType: ct,
+			FileName:    a.FileName,
+			FileSize:    int64(len(a.Data)),
+		}
+		if mi.Type == media.TypeDocument {
+			if doc, err := media.ExtractDocumentContent(mi.FilePath, mi.FileName); err == nil && doc != "" {
+				extra += "\n\n" + doc
+			}
+		}
+		infos = append(infos, mi)
+		files = append(files, bus.MediaFile{Path: mi.FilePath, MimeType: ct})
+	}
+	if tags := media.BuildMediaTags(infos); tags != "" {
+		extra = "\n\n" + tags + extra
+	}
+	return files, extra
+}
+
+// --- DM Policy ---
+
+func (c *Channel) checkDMPolicy(ctx context.Context, sender, chatID string) bool {
+	switch c.dmPolicy {
+	case "disabled":
+		slog.Debug("email message rejected: DMs disabled", "sender", sender)
+		return false
+
+	case "open":
+		return true
+
+	case "pairing":
+		paired := false
+		if c.pairingService != nil {
+			p, err := c.pairingService.IsPaired(ctx, sender, c.Name())
+			if err != nil {
+				slog.Warn("security.pairing_check_failed, assuming paired (fail-open)",
+					"sender_id", sender, "channel", c.Name(), "error", err)
+				paired = true
+			} else {
+				paired = p
+			}
+		}
+		if paired || (c.HasAllowList() && c.IsAllowed(sender)) {
+			return true
+		}
+		c.sendPairingReply(ctx, sender, chatID)
+		return false
+
+	default: // "allowlist"
+		if !c.IsAllowed(sender) {
+			slog.Debug("email message rejected by allowlist", "sender", sender)
+			return false
+		}
+		return true
+	}
+}
+
+func (c *Channel) sendPairingReply(ctx context.Context, sender, chatID string) {
+	if c.pairingService == nil {
+		return
+	}
+	if lastSent, ok := c.pairingDebounce.Load(sender); ok {
+		if time.Since(lastSent.(time.Time)) < pairingDebounce {
+			return
+		}
+	}
+
+	code, err := c.pairingService.RequestPairing(ctx, sender, c.Name(), chatID, "default", nil)
+	if err != nil {
+		slog.Debug("email pairing request failed", "sender", sender, "error", err)
+		return
+	}
+
+	replyText := fmt.Sprintf(
+		"GoClaw: access not configured.\n\nYour email address: %s\n\nPairing code: %s\n\nAsk the bot owner to approve with:\n  goclaw pairing approve %s",
+		sender, code, code,
+	)
+	if err := c.sendMail(ctx, c.replyEnvelope(chatID), replyText, nil); err != nil {
+		slog.Warn("failed to send email pairing reply", "error", err)
+		return
+	}
+	c.pairingDebounce.Store(sender, time.Now())
+	slog.Info("email pairing reply sent", "sender", sender, "code", code)
+}
+
+func (c *Channel) ownAddress() string {
+	if a, err := mail.ParseAddress(c.cfg.Address); err == nil {
+		return a.Address
+	}
+	return c.cfg.Address
+}
+
+// stripReplyPrefix removes leading "Re:"/"Fwd:" markers so replies don't stack them.
+func stripReplyPrefix(subject string) string {
+	s := strings.TrimSpace(subject)
+	for {
+		lower := strings.ToLower(s)
+		switch {
+		case strings.HasPrefix(lower, "re:"):
+			s = strings.TrimSpace(s[3:])
+		case strings.HasPrefix(lower, "fw:"):
+			s = strings.TrimSpace(s[3:])
+		case strings.HasPrefix(lower, "fwd:"):
+			s = strings.TrimSpace(s[4:])
+		default:
+			return s
+		}
+	}
+}
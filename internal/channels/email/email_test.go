@@ -0,0 +1,381 @@
+package email
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/mail"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/nextlevelbuilder/goclaw/internal/bus"
+	"github.com/nextlevelbuilder/goclaw/internal/config"
+)
+
+const firstMail = "From: Alice <Alice@Example.com>\r\n" +
+	"To: bot@corp.test\r\n" +
+	"Subject: Hello\r\n" +
+	"Message-ID: <m1@example.com>\r\n" +
+	"MIME-Version: 1.0\r\n" +
+	"Content-Type: multipart/mixed; boundary=\"outer\"\r\n" +
+	"\r\n" +
+	"--outer\r\n" +
+	"Content-Type: multipart/alternative; boundary=\"alt\"\r\n" +
+	"\r\n" +
+	"--alt\r\n" +
+	"Content-Type: text/html; charset=utf-8\r\n" +
+	"\r\n" +
+	"<html><head><style>p{}</style></head><body><p>Can you check the <b>report</b>?</p></body></html>\r\n" +
+	"--alt--\r\n" +
+	"--outer\r\n" +
+	"Content-Type: image/png; name=\"chart.png\"\r\n" +
+	"Content-Disposition: attachment; filename=\"chart.png\"\r\n" +
+	"Content-Transfer-Encoding: base64\r\n" +
+	"\r\n" +
+	"iVBORw0KGgo=\r\n" +
+	"--outer--\r\n"
+
+const followUp = "From: alice@example.com\r\n" +
+	"Subject: Re: Hello\r\n" +
+	"Message-ID: <m2@example.com>\r\n" +
+	"In-Reply-To: <r1@corp.test>\r\n" +
+	"References: <m1@example.com> <r1@corp.test>\r\n" +
+	"Content-Type: text/plain\r\n" +
+	"\r\n" +
+	"Thanks, looks good.\r\n" +
+	"\r\n" +
+	"On Mon, Jan 1, 2026 at 10:00 Bot wrote:\r\n" +
+	"> previous answer\r\n"
+
+// fakeIMAP serves a fixed set of messages over a minimal IMAP dialogue.
+type fakeIMAP struct {
+	mu   sync.Mutex
+	msgs map[uint32]string
+	seen map[uint32]bool
+}
+
+func (s *fakeIMAP) serve(t *testing.T) int {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go s.handle(conn)
+		}
+	}()
+	return ln.Addr().(*net.TCPAddr).Port
+}
+
+func (s *fakeIMAP) handle(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	fmt.Fprint(conn, "* OK fake IMAP ready\r\n")
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		tag, cmd, _ := strings.Cut(strings.TrimSpace(line), " ")
+		s.mu.Lock()
+		switch {
+		case strings.HasPrefix(cmd, "UID SEARCH"):
+			var uids []string
+			for uid := range s.msgs {
+				if !s.seen[uid] {
+					uids = append(uids, fmt.Sprint(uid))
+				}
+			}
+			fmt.Fprintf(conn, "* SEARCH %s\r\n", strings.Join(uids, " "))
+		case strings.HasPrefix(cmd, "UID FETCH"):
+			var uid uint32
+			fmt.Sscanf(cmd, "UID FETCH %d", &uid)
+			raw := s.msgs[uid]
+			fmt.Fprintf(conn, "* 1 FETCH (UID %d BODY[] {%d}\r\n%s)\r\n", uid, len(raw), raw)
+		case strings.HasPrefix(cmd, "UID STORE"):
+			var uid uint32
+			fmt.Sscanf(cmd, "UID STORE %d", &uid)
+			s.seen[uid] = true
+		case cmd == "LOGOUT":
+			fmt.Fprint(conn, "* BYE\r\n")
+		}
+		s.mu.Unlock()
+		fmt.Fprintf(conn, "%s OK done\r\n", tag)
+		if cmd == "LOGOUT" {
+			return
+		}
+	}
+}
+
+// fakeSMTP accepts any transaction and records the DATA payloads.
+type fakeSMTP struct {
+	mu   sync.Mutex
+	sent []string
+}
+
+func (s *fakeSMTP) serve(t *testing.T) int {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go s.handle(conn)
+		}
+	}()
+	return ln.Addr().(*net.TCPAddr).Port
+}
+
+func (s *fakeSMTP) handle(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	fmt.Fprint(conn, "220 fake SMTP\r\n")
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		verb := strings.ToUpper(strings.Fields(line + " x")[0])
+		switch verb {
+		case "EHLO", "HELO":
+			fmt.Fprint(conn, "250-fake\r\n250 AUTH PLAIN\r\n")
+		case "AUTH":
+			fmt.Fprint(conn, "235 ok\r\n")
+		case "DATA":
+			fmt.Fprint(conn, "354 go\r\n")
+			var sb strings.Builder
+			for {
+				l, err := r.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if l == ".\r\n" {
+					break
+				}
+				sb.WriteString(l)
+			}
+			s.mu.Lock()
+			s.sent = append(s.sent, sb.String())
+			s.mu.Unlock()
+			fmt.Fprint(conn, "250 queued\r\n")
+		case "QUIT":
+			fmt.Fprint(conn, "221 bye\r\n")
+			return
+		default:
+			fmt.Fprint(conn, "250 ok\r\n")
+		}
+	}
+}
+
+func newTestChannel(t *testing.T, imapPort, smtpPort int, allow ...string) (*Channel, *bus.MessageBus) {
+	t.Helper()
+	mb := bus.New()
+	ch, err := New(config.EmailConfig{
+		Address:      "Bot <bot@corp.test>",
+		DisplayName:  "Bot",
+		Password:     "pw",
+		IMAPHost:     "127.0.0.1",
+		IMAPPort:     imapPort,
+		IMAPSecurity: securityNone,
+		SMTPHost:     "127.0.0.1",
+		SMTPPort:     smtpPort,
+		SMTPSecurity: securityNone,
+		AllowFrom:    allow,
+	}, mb, nil)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	ch.SetRunning(true)
+	return ch, mb
+}
+
+func consume(t *testing.T, mb *bus.MessageBus) bus.InboundMessage {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	msg, ok := mb.ConsumeInbound(ctx)
+	if !ok {
+		t.Fatal("no inbound message published")
+	}
+	return msg
+}
+
+func TestPollThenReplyInThread(t *testing.T) {
+	imapSrv := &fakeIMAP{msgs: map[uint32]string{7: firstMail}, seen: map[uint32]bool{}}
+	smtpSrv := &fakeSMTP{}
+	ch, mb := newTestChannel(t, imapSrv.serve(t), smtpSrv.serve(t), "alice@example.com")
+
+	if err := ch.poll(context.Background()); err != nil {
+		t.Fatalf("poll: %v", err)
+	}
+	msg := consume(t, mb)
+	t.Cleanup(func() {
+		for _, f := range msg.Media {
+			os.Remove(f.Path)
+		}
+	})
+
+	if msg.SenderID != "alice@example.com" || msg.ChatID != chatIDFor("alice@example.com", "<m1@example.com>") {
+		t.Errorf("sender/chat = %q / %q", msg.SenderID, msg.ChatID)
+	}
+	if !strings.Contains(msg.Content, "Subject: Hello") || !strings.Contains(msg.Content, "Can you check the report?") {
+		t.Errorf("content = %q", msg.Content)
+	}
+	if strings.Contains(msg.Content, "p{}") {
+		t.Errorf("style leaked into content: %q", msg.Content)
+	}
+	if len(msg.Media) != 1 || msg.Media[0].MimeType != "image/png" {
+		t.Fatalf("media = %+v", msg.Media)
+	}
+	imapSrv.mu.Lock()
+	if !imapSrv.seen[7] {
+		t.Error("message was not marked seen")
+	}
+	imapSrv.mu.Unlock()
+
+	// Reply lands in the same thread with an outbound attachment.
+	att := filepath.Join(t.TempDir(), "summary.txt")
+	os.WriteFile(att, []byte("totals"), 0o600)
+	err := ch.Send(context.Background(), bus.OutboundMessage{
+		ChatID:  msg.ChatID,
+		Content: "Report looks fine.",
+		Media:   []bus.MediaAttachment{{URL: att, ContentType: "text/plain"}},
+	})
+	if err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	smtpSrv.mu.Lock()
+	defer smtpSrv.mu.Unlock()
+	if len(smtpSrv.sent) != 1 {
+		t.Fatalf("sent %d messages, want 1", len(smtpSrv.sent))
+	}
+	out, err := mail.ReadMessage(strings.NewReader(smtpSrv.sent[0]))
+	if err != nil {
+		t.Fatalf("parse sent message: %v", err)
+	}
+	if got := out.Header.Get("In-Reply-To"); got != "<m1@example.com>" {
+		t.Errorf("In-Reply-To = %q", got)
+	}
+	if got := out.Header.Get("References"); got != "<m1@example.com>" {
+		t.Errorf("References = %q", got)
+	}
+	if got := out.Header.Get("Subject"); got != "Re: Hello" {
+		t.Errorf("Subject = %q", got)
+	}
+	if got := out.Header.Get("To"); !strings.Contains(got, "Alice@Example.com") {
+		t.Errorf("To = %q", got)
+	}
+	body, _ := io.ReadAll(out.Body)
+	if !strings.Contains(string(body), "Report looks fine.") || !strings.Contains(string(body), "filename=summary.txt") ||
+		!strings.Contains(string(body), "dG90YWxz") {
+		t.Errorf("body = %s", body)
+	}
+}
+
+func TestFollowUpSharesThreadAndStripsQuote(t *testing.T) {
+	ch, mb := newTestChannel(t, 0, 0)
+
+	ch.handleRaw(context.Background(), []byte(followUp))
+	msg := consume(t, mb)
+	if msg.ChatID != chatIDFor("alice@example.com", "<m1@example.com>") {
+		t.Errorf("follow-up chat = %q, want the original thread", msg.ChatID)
+	}
+	if strings.Contains(msg.Content, "previous answer") || strings.Contains(msg.Content, "wrote:") {
+		t.Errorf("quoted history not stripped: %q", msg.Content)
+	}
+
+	env := ch.replyEnvelope(msg.ChatID)
+	if env.Subject != "Re: Hello" || env.InReplyTo != "<m2@example.com>" ||
+		strings.Join(env.References, " ") != "<m1@example.com> <r1@corp.test> <m2@example.com>" {
+		t.Errorf("envelope = %+v", env)
+	}
+}
+
+func TestPolicyAndAutoReplies(t *testing.T) {
+	ch, mb := newTestChannel(t, 0, 0, "@example.com")
+
+	blocked := strings.Replace(followUp, "alice@example.com", "mallory@evil.test", 1)
+	autoReply := strings.Replace(followUp, "Subject:", "Auto-Submitted: auto-replied\r\nSubject:", 1)
+	fromSelf := strings.Replace(followUp, "alice@example.com", "BOT@corp.test", 1)
+	for _, raw := range []string{blocked, autoReply, fromSelf} {
+		ch.handleRaw(context.Background(), []byte(raw))
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if msg, ok := mb.ConsumeInbound(ctx); ok {
+		t.Errorf("unexpected inbound: %+v", msg)
+	}
+}
+
+func TestIsAllowed(t *testing.T) {
+	ch, _ := newTestChannel(t, 0, 0, "Boss@Corp.test", "*@partner.io")
+	tests := map[string]bool{
+		"boss@corp.test":    true,
+		"BOSS@CORP.TEST":    true,
+		"other@corp.test":   false,
+		"anyone@partner.io": true,
+		"x@sub.partner.io":  false,
+	}
+	for addr, want := range tests {
+		if got := ch.IsAllowed(addr); got != want {
+			t.Errorf("IsAllowed(%q) = %v, want %v", addr, got, want)
+		}
+	}
+}
+
+func TestReadResponse_LiteralLimits(t *testing.T) {
+	newClient := func(data string) *imapClient {
+		return &imapClient{r: bufio.NewReader(strings.NewReader(data)), maxLiteral: 8}
+	}
+
+	resp, err := newClient("* 1 FETCH (BODY[] {5}\r\nhello)\r\n").readResponse()
+	if err != nil || resp.oversized || len(resp.literals) != 1 || string(resp.literals[0]) != "hello" {
+		t.Fatalf("small literal: resp=%+v err=%v", resp, err)
+	}
+
+	// Oversized literals are skipped without desyncing the stream.
+	cl := newClient("* 1 FETCH (BODY[] {12}\r\nhello world!)\r\na1 OK done\r\n")
+	resp, err = cl.readResponse()
+	if err != nil || !resp.oversized || resp.literals[0] != nil {
+		t.Fatalf("oversized literal: resp=%+v err=%v", resp, err)
+	}
+	if next, err := cl.readResponse(); err != nil || next.line != "a1 OK done" {
+		t.Fatalf("next response = %q, %v", next.line, err)
+	}
+
+	if _, err := newClient("* 1 FETCH (BODY[] {99999999999999999999}\r\n").readResponse(); err == nil {
+		t.Error("unparseable literal size should fail")
+	}
+}
+
+func TestSweepMaps_ExpiresThreads(t *testing.T) {
+	ch, _ := newTestChannel(t, 0, 0)
+	ch.threads.Store("old", &threadState{UpdatedAt: time.Now().Add(-threadTTL - time.Hour)})
+	ch.threads.Store("new", &threadState{UpdatedAt: time.Now()})
+	ch.sweepMaps()
+	if _, ok := ch.threads.Load("old"); ok {
+		t.Error("expired thread state kept")
+	}
+	if _, ok := ch.threads.Load("new"); !ok {
+		t.Error("fresh thread state dropped")
+	}
+}
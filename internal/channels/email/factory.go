@@ -0,0 +1,79 @@
+package email
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/nextlevelbuilder/goclaw/internal/bus"
+	"github.com/nextlevelbuilder/goclaw/internal/channels"
+	"github.com/nextlevelbuilder/goclaw/internal/config"
+	"github.com/nextlevelbuilder/goclaw/internal/store"
+)
+
+// emailCreds maps the credentials JSON from the channel_instances table.
+type emailCreds struct {
+	Username string `json:"username,omitempty"` // default: address
+	Password string `json:"password"`
+}
+
+// emailInstanceConfig maps the non-secret config JSONB from the channel_instances table.
+type emailInstanceConfig struct {
+	Address      string   `json:"address"`
+	DisplayName  string   `json:"display_name,omitempty"`
+	IMAPHost     string   `json:"imap_host"`
+	IMAPPort     int      `json:"imap_port,omitempty"`
+	IMAPSecurity string   `json:"imap_security,omitempty"`
+	Mailbox      string   `json:"mailbox,omitempty"`
+	SMTPHost     string   `json:"smtp_host"`
+	SMTPPort     int      `json:"smtp_port,omitempty"`
+	SMTPSecurity string   `json:"smtp_security,omitempty"`
+	PollInterval int      `json:"poll_interval,omitempty"`
+	AllowFrom    []string `json:"allow_from,omitempty"`
+	DMPolicy     string   `json:"dm_policy,omitempty"`
+	MediaMaxMB   int      `json:"media_max_mb,omitempty"`
+	BlockReply   *bool    `json:"block_reply,omitempty"`
+}
+
+// Factory creates an email channel from DB instance data.
+func Factory(name string, creds json.RawMessage, cfg json.RawMessage,
+	msgBus *bus.MessageBus, pairingSvc store.PairingStore) (channels.Channel, error) {
+
+	var c emailCreds
+	if len(creds) > 0 {
+		if err := json.Unmarshal(creds, &c); err != nil {
+			return nil, fmt.Errorf("decode email credentials: %w", err)
+		}
+	}
+
+	var ic emailInstanceConfig
+	if len(cfg) > 0 {
+		if err := json.Unmarshal(cfg, &ic); err != nil {
+			return nil, fmt.Errorf("decode email config: %w", err)
+		}
+	}
+
+	ch, err := New(config.EmailConfig{
+		Enabled:      true,
+		Address:      ic.Address,
+		DisplayName:  ic.DisplayName,
+		Username:     c.Username,
+		Password:     c.Password,
+		IMAPHost:     ic.IMAPHost,
+		IMAPPort:     ic.IMAPPort,
+		IMAPSecurity: ic.IMAPSecurity,
+		Mailbox:      ic.Mailbox,
+		SMTPHost:     ic.SMTPHost,
+		SMTPPort:     ic.SMTPPort,
+		SMTPSecurity: ic.SMTPSecurity,
+		PollInterval: ic.PollInterval,
+		AllowFrom:    ic.AllowFrom,
+		DMPolicy:     ic.DMPolicy,
+		MediaMaxMB:   ic.MediaMaxMB,
+		BlockReply:   ic.BlockReply,
+	}, msgBus, pairingSvc)
+	if err != nil {
+		return nil, err
+	}
+	ch.SetName(name)
+	return ch, nil
+}
@@ -0,0 +1,214 @@
+package email
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// imapClient is a minimal IMAP4rev1 client covering what the channel needs:
+// LOGIN, SELECT, UID SEARCH, UID FETCH BODY.PEEK[], UID STORE and LOGOUT.
+// One connection is opened per poll, so there is no IDLE or reconnect logic.
+type imapClient struct {
+	conn       net.Conn
+	r          *bufio.Reader
+	w          *bufio.Writer
+	tag        int
+	maxLiteral int64 // literals larger than this are discarded unread
+}
+
+// imapResponse is one untagged server response with its literals extracted.
+// Literal payloads are replaced by "{N}" in line and appended to literals.
+// A literal over the client's maxLiteral is appended as nil and sets oversized.
+type imapResponse struct {
+	line      string
+	literals  [][]byte
+	oversized bool
+}
+
+// errMessageTooLarge reports a message whose body exceeds the client's literal cap.
+var errMessageTooLarge = errors.New("imap: message exceeds size limit")
+
+const imapIOTimeout = 60 * time.Second
+
+var literalRe = regexp.MustCompile(`\{(\d+)\}$`)
+
+// dialIMAP connects and reads the greeting. maxLiteral bounds the size of any
+// literal (message body) the client will buffer.
+func dialIMAP(ctx context.Context, host string, port int, security string, maxLiteral int64) (*imapClient, error) {
+	addr := net.JoinHostPort(host, strconv.Itoa(port))
+	d := &net.Dialer{Timeout: 30 * time.Second}
+	var conn net.Conn
+	var err error
+	if security == securityNone {
+		conn, err = d.DialContext(ctx, "tcp", addr)
+	} else {
+		conn, err = (&tls.Dialer{NetDialer: d, Config: &tls.Config{ServerName: host}}).DialContext(ctx, "tcp", addr)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("imap dial %s: %w", addr, err)
+	}
+	c := &imapClient{conn: conn, r: bufio.NewReader(conn), w: bufio.NewWriter(conn), maxLiteral: maxLiteral}
+	conn.SetDeadline(time.Now().Add(imapIOTimeout))
+	greeting, err := c.readLine()
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("imap greeting: %w", err)
+	}
+	if !strings.HasPrefix(greeting, "* OK") && !strings.HasPrefix(greeting, "* PREAUTH") {
+		conn.Close()
+		return nil, fmt.Errorf("imap greeting: %s", greeting)
+	}
+	return c, nil
+}
+
+func (c *imapClient) Close() error { return c.conn.Close() }
+
+// command sends one tagged command and collects untagged responses until the
+// tagged completion. A non-OK completion is returned as an error.
+func (c *imapClient) command(format string, args ...any) ([]imapResponse, error) {
+	c.tag++
+	tag := fmt.Sprintf("G%03d", c.tag)
+	c.conn.SetDeadline(time.Now().Add(imapIOTimeout))
+	if _, err := fmt.Fprintf(c.w, "%s %s\r\n", tag, fmt.Sprintf(format, args...)); err != nil {
+		return nil, err
+	}
+	if err := c.w.Flush(); err != nil {
+		return nil, err
+	}
+
+	var out []imapResponse
+	for {
+		resp, err := c.readResponse()
+		if err != nil {
+			return nil, err
+		}
+		if rest, ok := strings.CutPrefix(resp.line, tag+" "); ok {
+			if !strings.HasPrefix(strings.ToUpper(rest), "OK") {
+				return out, fmt.Errorf("imap: %s", rest)
+			}
+			return out, nil
+		}
+		if strings.HasPrefix(resp.line, "* ") {
+			out = append(out, resp)
+		}
+		// Continuation requests ("+ ...") are not expected for the commands we send.
+	}
+}
+
+// readResponse reads one logical response line, following {N} literals.
+func (c *imapClient) readResponse() (imapResponse, error) {
+	var resp imapResponse
+	var sb strings.Builder
+	for {
+		line, err := c.readLine()
+		if err != nil {
+			return resp, err
+		}
+		sb.WriteString(line)
+		m := literalRe.FindStringSubmatch(line)
+		if m == nil {
+			break
+		}
+		n, err := strconv.ParseInt(m[1], 10, 64)
+		if err != nil {
+			return resp, fmt.Errorf("imap literal size %q: %w", m[1], err)
+		}
+		if c.maxLiteral > 0 && n > c.maxLiteral {
+			// Skip the payload so the connection stays in sync.
+			if _, err := io.CopyN(io.Discard, c.r, n); err != nil {
+				return resp, fmt.Errorf("imap literal: %w", err)
+			}
+			resp.literals = append(resp.literals, nil)
+			resp.oversized = true
+			continue
+		}
+		lit := make([]byte, n)
+		if _, err := io.ReadFull(c.r, lit); err != nil {
+			return resp, fmt.Errorf("imap literal: %w", err)
+		}
+		resp.literals = append(resp.literals, lit)
+	}
+	resp.line = sb.String()
+	return resp, nil
+}
+
+func (c *imapClient) readLine() (string, error) {
+	line, err := c.r.ReadString('\n')
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+func (c *imapClient) Login(username, password string) error {
+	_, err := c.command("LOGIN %s %s", imapQuote(username), imapQuote(password))
+	return err
+}
+
+func (c *imapClient) Select(mailbox string) error {
+	_, err := c.command("SELECT %s", imapQuote(mailbox))
+	return err
+}
+
+// SearchUnseen returns the UIDs of unseen messages.
+func (c *imapClient) SearchUnseen() ([]uint32, error) {
+	resps, err := c.command("UID SEARCH UNSEEN")
+	if err != nil {
+		return nil, err
+	}
+	var uids []uint32
+	for _, r := range resps {
+		rest, ok := strings.CutPrefix(r.line, "* SEARCH")
+		if !ok {
+			continue
+		}
+		for _, f := range strings.Fields(rest) {
+			if n, err := strconv.ParseUint(f, 10, 32); err == nil {
+				uids = append(uids, uint32(n))
+			}
+		}
+	}
+	return uids, nil
+}
+
+// FetchRaw returns the full RFC 5322 message for uid without setting \Seen.
+func (c *imapClient) FetchRaw(uid uint32) ([]byte, error) {
+	resps, err := c.command("UID FETCH %d (BODY.PEEK[])", uid)
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range resps {
+		if strings.Contains(strings.ToUpper(r.line), "FETCH") && len(r.literals) > 0 {
+			if r.oversized {
+				return nil, errMessageTooLarge
+			}
+			return r.literals[0], nil
+		}
+	}
+	return nil, fmt.Errorf("imap: no body returned for uid %d", uid)
+}
+
+func (c *imapClient) MarkSeen(uid uint32) error {
+	_, err := c.command(`UID STORE %d +FLAGS.SILENT (\Seen)`, uid)
+	return err
+}
+
+func (c *imapClient) Logout() {
+	c.command("LOGOUT")
+}
+
+// imapQuote renders s as an IMAP quoted string.
+func imapQuote(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	return `"` + s + `"`
+}
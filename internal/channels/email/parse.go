@@ -0,0 +1,303 @@
+package email
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// inboundMail is the subset of a parsed message the channel uses.
+type inboundMail struct {
+	MessageID   string
+	InReplyTo   string
+	References  []string
+	From        *mail.Address
+	Subject     string
+	Text        string
+	AutoReply   bool // auto-responder / list / bulk mail — never answered
+	Attachments []attachment
+}
+
+type attachment struct {
+	FileName    string
+	ContentType string
+	Data        []byte
+}
+
+var headerDecoder = &mime.WordDecoder{CharsetReader: charsetReader}
+
+// parseMail parses an RFC 5322 message: headers, body text (plain preferred,
+// HTML converted) and attachments. Attachments larger than maxAttachment
+// bytes are dropped.
+func parseMail(raw []byte, maxAttachment int64) (*inboundMail, error) {
+	msg, err := mail.ReadMessage(bytes.NewReader(raw))
+	if err != nil {
+		return nil, fmt.Errorf("parse message: %w", err)
+	}
+	h := msg.Header
+
+	from, err := mail.ParseAddress(decodeHeader(h.Get("From")))
+	if err != nil {
+		return nil, fmt.Errorf("parse From: %w", err)
+	}
+	m := &inboundMail{
+		MessageID:  strings.TrimSpace(h.Get("Message-Id")),
+		InReplyTo:  firstMessageID(h.Get("In-Reply-To")),
+		References: messageIDs(h.Get("References")),
+		From:       from,
+		Subject:    decodeHeader(h.Get("Subject")),
+		AutoReply:  isAutoReply(h),
+	}
+
+	var plain, htmlBody string
+	var walk func(ct, cte, disposition string, body io.Reader) error
+	walk = func(ct, cte, disposition string, body io.Reader) error {
+		mediaType, params, err := mime.ParseMediaType(ct)
+		if err != nil {
+			mediaType, params = "text/plain", map[string]string{}
+		}
+		if strings.HasPrefix(mediaType, "multipart/") {
+			mr := multipart.NewReader(body, params["boundary"])
+			for {
+				p, err := mr.NextRawPart()
+				if err == io.EOF {
+					return nil
+				}
+				if err != nil {
+					return err
+				}
+				if err := walk(p.Header.Get("Content-Type"), p.Header.Get("Content-Transfer-Encoding"),
+					p.Header.Get("Content-Disposition"), p); err != nil {
+					return err
+				}
+			}
+		}
+
+		data, err := io.ReadAll(decodeTransfer(cte, body))
+		if err != nil {
+			return err
+		}
+		dispType, dispParams, _ := mime.ParseMediaType(disposition)
+		fileName := decodeHeader(dispParams["filename"])
+		if fileName == "" {
+			fileName = decodeHeader(params["name"])
+		}
+		isAttachment := dispType == "attachment" || (fileName != "" && !strings.HasPrefix(mediaType, "text/"))
+
+		switch {
+		case isAttachment:
+			if maxAttachment > 0 && int64(len(data)) > maxAttachment {
+				m.Text += fmt.Sprintf("\n[Attachment %s skipped: %d bytes exceeds limit]", fileName, len(data))
+				return nil
+			}
+			if fileName == "" {
+				fileName = "attachment"
+			}
+			m.Attachments = append(m.Attachments, attachment{FileName: fileName, ContentType: mediaType, Data: data})
+		case mediaType == "text/plain" && plain == "":
+			plain = toUTF8(data, params["charset"])
+		case mediaType == "text/html" && htmlBody == "":
+			htmlBody = toUTF8(data, params["charset"])
+		}
+		return nil
+	}
+
+	if err := walk(h.Get("Content-Type"), h.Get("Content-Transfer-Encoding"), h.Get("Content-Disposition"), msg.Body); err != nil {
+		return nil, fmt.Errorf("parse body: %w", err)
+	}
+
+	text := plain
+	if strings.TrimSpace(text) == "" && htmlBody != "" {
+		text = htmlToText(htmlBody)
+	}
+	m.Text = strings.TrimSpace(stripQuoted(text) + m.Text)
+	return m, nil
+}
+
+// threadRoot is the Message-ID that identifies the conversation: the first
+// References entry, else In-Reply-To, else the message's own ID.
+func (m *inboundMail) threadRoot() string {
+	switch {
+	case len(m.References) > 0:
+		return m.References[0]
+	case m.InReplyTo != "":
+		return m.InReplyTo
+	default:
+		return m.MessageID
+	}
+}
+
+// chatIDFor builds a stable chat ID ("<address>#<thread hash>") so each email
+// thread maps to its own session while Send can still recover the recipient.
+func chatIDFor(address, root string) string {
+	sum := sha1.Sum([]byte(strings.ToLower(root)))
+	return strings.ToLower(address) + "#" + hex.EncodeToString(sum[:6])
+}
+
+// addressFromChatID recovers the correspondent address from a chat ID.
+func addressFromChatID(chatID string) string {
+	addr, _, _ := strings.Cut(chatID, "#")
+	return addr
+}
+
+func decodeTransfer(cte string, r io.Reader) io.Reader {
+	switch strings.ToLower(strings.TrimSpace(cte)) {
+	case "base64":
+		return base64.NewDecoder(base64.StdEncoding, r)
+	case "quoted-printable":
+		return quotedprintable.NewReader(r)
+	default:
+		return r
+	}
+}
+
+func decodeHeader(s string) string {
+	if d, err := headerDecoder.DecodeHeader(s); err == nil {
+		return d
+	}
+	return s
+}
+
+// charsetReader supports UTF-8/ASCII and Latin-1, which covers the bulk of
+// real mail without pulling in a full charset table.
+func charsetReader(charset string, input io.Reader) (io.Reader, error) {
+	switch strings.ToLower(charset) {
+	case "", "utf-8", "utf8", "us-ascii", "ascii":
+		return input, nil
+	case "iso-8859-1", "latin1", "windows-1252":
+		data, err := io.ReadAll(input)
+		if err != nil {
+			return nil, err
+		}
+		return strings.NewReader(latin1ToUTF8(data)), nil
+	}
+	return nil, fmt.Errorf("unsupported charset %q", charset)
+}
+
+func toUTF8(data []byte, charset string) string {
+	switch strings.ToLower(charset) {
+	case "iso-8859-1", "latin1", "windows-1252":
+		return latin1ToUTF8(data)
+	}
+	return string(data)
+}
+
+func latin1ToUTF8(data []byte) string {
+	runes := make([]rune, len(data))
+	for i, b := range data {
+		runes[i] = rune(b)
+	}
+	return string(runes)
+}
+
+func messageIDs(s string) []string {
+	var ids []string
+	for _, f := range strings.Fields(s) {
+		if strings.HasPrefix(f, "<") && strings.HasSuffix(f, ">") {
+			ids = append(ids, f)
+		}
+	}
+	return ids
+}
+
+func firstMessageID(s string) string {
+	if ids := messageIDs(s); len(ids) > 0 {
+		return ids[0]
+	}
+	return strings.TrimSpace(s)
+}
+
+// isAutoReply detects auto-responders, bounces and bulk mail (RFC 3834) so the
+// agent never answers them — answering vacation replies is how mail loops start.
+func isAutoReply(h mail.Header) bool {
+	if v := strings.ToLower(h.Get("Auto-Submitted")); v != "" && v != "no" {
+		return true
+	}
+	switch strings.ToLower(h.Get("Precedence")) {
+	case "bulk", "junk", "list", "auto_reply":
+		return true
+	}
+	return h.Get("X-Autoreply") != "" || h.Get("X-Autorespond") != "" || h.Get("List-Id") != ""
+}
+
+// stripQuoted drops the quoted history most clients append to replies.
+func stripQuoted(text string) string {
+	lines := strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+	var out []string
+	for _, line := range lines {
+		t := strings.TrimSpace(line)
+		if t == "-----Original Message-----" ||
+			(strings.HasPrefix(t, "On ") && strings.HasSuffix(t, "wrote:")) {
+			break
+		}
+		if strings.HasPrefix(t, ">") {
+			continue
+		}
+		out = append(out, line)
+	}
+	return strings.TrimSpace(strings.Join(out, "\n"))
+}
+
+// htmlToText flattens an HTML body to readable text.
+func htmlToText(s string) string {
+	z := html.NewTokenizer(strings.NewReader(s))
+	var sb strings.Builder
+	skip := 0
+	for {
+		switch z.Next() {
+		case html.ErrorToken:
+			return collapseBlankLines(sb.String())
+		case html.TextToken:
+			if skip == 0 {
+				sb.Write(z.Text())
+			}
+		case html.StartTagToken, html.SelfClosingTagToken:
+			name, _ := z.TagName()
+			switch string(name) {
+			case "script", "style", "head":
+				skip++
+			case "br", "p", "div", "tr", "li", "h1", "h2", "h3", "h4", "h5", "h6", "blockquote":
+				sb.WriteByte('\n')
+			}
+		case html.EndTagToken:
+			name, _ := z.TagName()
+			switch string(name) {
+			case "script", "style", "head":
+				if skip > 0 {
+					skip--
+				}
+			case "p", "div", "tr", "li", "h1", "h2", "h3", "h4", "h5", "h6":
+				sb.WriteByte('\n')
+			}
+		}
+	}
+}
+
+func collapseBlankLines(s string) string {
+	lines := strings.Split(s, "\n")
+	var out []string
+	blank := 0
+	for _, l := range lines {
+		l = strings.TrimSpace(l)
+		if l == "" {
+			blank++
+			if blank > 1 {
+				continue
+			}
+		} else {
+			blank = 0
+		}
+		out = append(out, l)
+	}
+	return strings.TrimSpace(strings.Join(out, "\n"))
+}
@@ -0,0 +1,232 @@
+package email
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/base64"
+	"fmt"
+	"log/slog"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/nextlevelbuilder/goclaw/internal/bus"
+	"github.com/nextlevelbuilder/goclaw/internal/channels/media"
+)
+
+// envelope carries the addressing/threading headers of one outgoing email.
+type envelope struct {
+	To         string
+	Subject    string
+	InReplyTo  string
+	References []string
+}
+
+// Send delivers an agent reply. Replies to a known thread carry In-Reply-To and
+// References; otherwise the recipient is recovered from the chat ID.
+func (c *Channel) Send(ctx context.Context, msg bus.OutboundMessage) error {
+	if !c.IsRunning() {
+		return fmt.Errorf("email channel not running")
+	}
+	if strings.TrimSpace(msg.Content) == "" && len(msg.Media) == 0 {
+		return nil
+	}
+	return c.sendMail(ctx, c.replyEnvelope(msg.ChatID), msg.Content, msg.Media)
+}
+
+// replyEnvelope builds the envelope for a reply into chatID's thread.
+func (c *Channel) replyEnvelope(chatID string) envelope {
+	if v, ok := c.threads.Load(chatID); ok {
+		t := v.(*threadState)
+		subject := t.Subject
+		if subject == "" {
+			subject = "(no subject)"
+		}
+		return envelope{
+			To:         t.To,
+			Subject:    "Re: " + subject,
+			InReplyTo:  t.LastMessageID,
+			References: t.References,
+		}
+	}
+	subject := "Message from " + c.cfg.Address
+	if c.cfg.DisplayName != "" {
+		subject = "Message from " + c.cfg.DisplayName
+	}
+	return envelope{To: addressFromChatID(chatID), Subject: subject}
+}
+
+// sendMail builds the MIME message and submits it over SMTP.
+func (c *Channel) sendMail(ctx context.Context, env envelope, body string, attachments []bus.MediaAttachment) error {
+	to, err := mail.ParseAddress(env.To)
+	if err != nil {
+		return fmt.Errorf("email: invalid recipient %q: %w", env.To, err)
+	}
+	from := &mail.Address{Name: c.cfg.DisplayName, Address: c.ownAddress()}
+
+	data, err := buildMessage(from, to, env, body, attachments, time.Now())
+	if err != nil {
+		return err
+	}
+	if err := c.submit(ctx, from.Address, to.Address, data); err != nil {
+		return fmt.Errorf("email: smtp send to %s: %w", to.Address, err)
+	}
+	slog.Debug("email sent", "channel", c.Name(), "to", to.Address, "subject", env.Subject)
+	return nil
+}
+
+// submit runs one SMTP transaction honoring the configured transport security.
+func (c *Channel) submit(ctx context.Context, from, to string, data []byte) error {
+	addr := net.JoinHostPort(c.cfg.SMTPHost, strconv.Itoa(c.cfg.SMTPPort))
+	d := &net.Dialer{Timeout: 30 * time.Second}
+	tlsCfg := &tls.Config{ServerName: c.cfg.SMTPHost}
+
+	var conn net.Conn
+	var err error
+	if c.cfg.SMTPSecurity == securityTLS {
+		conn, err = (&tls.Dialer{NetDialer: d, Config: tlsCfg}).DialContext(ctx, "tcp", addr)
+	} else {
+		conn, err = d.DialContext(ctx, "tcp", addr)
+	}
+	if err != nil {
+		return err
+	}
+	conn.SetDeadline(time.Now().Add(2 * time.Minute))
+
+	cl, err := smtp.NewClient(conn, c.cfg.SMTPHost)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer cl.Close()
+
+	if c.cfg.SMTPSecurity == securityStartTLS {
+		if err := cl.StartTLS(tlsCfg); err != nil {
+			return fmt.Errorf("starttls: %w", err)
+		}
+	}
+	if c.cfg.Password != "" {
+		if err := cl.Auth(smtp.PlainAuth("", c.cfg.Username, c.cfg.Password, c.cfg.SMTPHost)); err != nil {
+			return fmt.Errorf("auth: %w", err)
+		}
+	}
+	if err := cl.Mail(from); err != nil {
+		return err
+	}
+	if err := cl.Rcpt(to); err != nil {
+		return err
+	}
+	w, err := cl.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(data); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return cl.Quit()
+}
+
+// buildMessage renders a multipart/mixed message: a UTF-8 text body followed
+// by base64 attachments. Remote media URLs are listed in the body instead.
+func buildMessage(from, to *mail.Address, env envelope, body string, attachments []bus.MediaAttachment, now time.Time) ([]byte, error) {
+	var links []string
+	type file struct {
+		name, contentType string
+		data              []byte
+	}
+	var files []file
+	for _, a := range attachments {
+		if strings.HasPrefix(a.URL, "http://") || strings.HasPrefix(a.URL, "https://") {
+			links = append(links, a.URL)
+			continue
+		}
+		data, err := os.ReadFile(a.URL)
+		if err != nil {
+			slog.Warn("email: attachment unreadable, skipped", "path", a.URL, "error", err)
+			continue
+		}
+		ct := a.ContentType
+		if ct == "" {
+			ct = media.DetectMIMEType(a.URL)
+		}
+		files = append(files, file{name: filepath.Base(a.URL), contentType: ct, data: data})
+		if a.Caption != "" {
+			body += "\n\n" + a.Caption
+		}
+	}
+	if len(links) > 0 {
+		body += "\n\n" + strings.Join(links, "\n")
+	}
+
+	_, domain, _ := strings.Cut(from.Address, "@")
+	if domain == "" {
+		domain = "localhost"
+	}
+
+	var buf bytes.Buffer
+	hdr := func(k, v string) { fmt.Fprintf(&buf, "%s: %s\r\n", k, v) }
+	hdr("From", from.String())
+	hdr("To", to.String())
+	hdr("Subject", mime.QEncoding.Encode("utf-8", env.Subject))
+	hdr("Date", now.Format(time.RFC1123Z))
+	hdr("Message-ID", "<"+uuid.NewString()+"@"+domain+">")
+	if env.InReplyTo != "" {
+		hdr("In-Reply-To", env.InReplyTo)
+	}
+	if len(env.References) > 0 {
+		hdr("References", strings.Join(env.References, " "))
+	}
+	hdr("Auto-Submitted", "auto-replied")
+	hdr("MIME-Version", "1.0")
+
+	mw := multipart.NewWriter(&buf)
+	hdr("Content-Type", `multipart/mixed; boundary="`+mw.Boundary()+`"`)
+	buf.WriteString("\r\n")
+
+	part, err := mw.CreatePart(textproto.MIMEHeader{
+		"Content-Type":              {"text/plain; charset=utf-8"},
+		"Content-Transfer-Encoding": {"quoted-printable"},
+	})
+	if err != nil {
+		return nil, err
+	}
+	qp := quotedprintable.NewWriter(part)
+	qp.Write([]byte(body))
+	qp.Close()
+
+	for _, f := range files {
+		part, err := mw.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {mime.FormatMediaType(f.contentType, map[string]string{"name": f.name})},
+			"Content-Transfer-Encoding": {"base64"},
+			"Content-Disposition":       {mime.FormatMediaType("attachment", map[string]string{"filename": f.name})},
+		})
+		if err != nil {
+			return nil, err
+		}
+		enc := base64.StdEncoding.EncodeToString(f.data)
+		for len(enc) > 76 {
+			part.Write([]byte(enc[:76] + "\r\n"))
+			enc = enc[76:]
+		}
+		part.Write([]byte(enc + "\r\n"))
+	}
+	if err := mw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
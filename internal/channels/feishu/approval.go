@@ -0,0 +1,116 @@
+package feishu
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+
+	"github.com/nextlevelbuilder/goclaw/internal/channels"
+	"github.com/nextlevelbuilder/goclaw/internal/store"
+)
+
+// CardActionEvent is the parsed structure of a card.action.trigger callback
+// (a button press on an interactive card).
+type CardActionEvent struct {
+	Header struct {
+		EventID   string `json:"event_id"`
+		EventType string `json:"event_type"`
+		Token     string `json:"token"`
+	} `json:"header"`
+	Event struct {
+		Operator struct {
+			OpenID string `json:"open_id"`
+			UserID string `json:"user_id"`
+		} `json:"operator"`
+		Action struct {
+			Tag       string            `json:"tag"`
+			Value     map[string]string `json:"value"`
+			Option    string            `json:"option,omitempty"`     // select_static choice
+			FormValue map[string]any    `json:"form_value,omitempty"` // form submit values by input name
+		} `json:"action"`
+		Context struct {
+			OpenMessageID string `json:"open_message_id"`
+			OpenChatID    string `json:"open_chat_id"`
+		} `json:"context"`
+	} `json:"event"`
+}
+
+// approvalValueKey holds the approval payload in a button's value.
+const approvalValueKey = "approval"
+
+// SendApprovalRequest shows a tool approval request as an interactive card
+// with buttons. Implements channels.ApprovalChannel.
+func (c *Channel) SendApprovalRequest(ctx context.Context, chatID string, req channels.ApprovalRequest) error {
+	if !c.IsRunning() {
+		return fmt.Errorf("feishu bot not running")
+	}
+	var buttons []map[string]any
+	for _, b := range channels.ApprovalButtonLabels() {
+		style := "default"
+		switch b[0] {
+		case channels.ApprovalAllowOnce:
+			style = "primary"
+		case channels.ApprovalDeny:
+			style = "danger"
+		}
+		buttons = append(buttons, map[string]any{
+			"tag":   "button",
+			"text":  map[string]string{"tag": "plain_text", "content": b[1]},
+			"type":  style,
+			"value": map[string]string{approvalValueKey: channels.ApprovalCallbackData(req.ID, b[0])},
+		})
+	}
+	card := map[string]any{
+		"config": map[string]any{"wide_screen_mode": true},
+		"elements": []map[string]any{
+			{"tag": "div", "text": map[string]string{"tag": "plain_text", "content": channels.FormatApprovalText(req)}},
+			{"tag": "action", "actions": buttons},
+		},
+	}
+	cardJSON, err := json.Marshal(card)
+	if err != nil {
+		return fmt.Errorf("marshal card: %w", err)
+	}
+	if _, err := c.client.SendMessage(ctx, resolveReceiveIDType(chatID), chatID, "interactive", string(cardJSON)); err != nil {
+		return fmt.Errorf("feishu send approval card: %w", err)
+	}
+	return nil
+}
+
+// handleCardAction handles card actions: tool approvals and answers to
+// interactive questions. The card is replaced with the outcome.
+func (c *Channel) handleCardAction(ctx context.Context, event *CardActionEvent) {
+	ctx = store.WithTenantID(ctx, c.TenantID())
+	if qid, action, index, ok := channels.ParseInteractiveCallback(event.Event.Action.Value[interactiveValueKey]); ok {
+		c.handleInteractiveCardAction(ctx, event, qid, action, index)
+		return
+	}
+	id, decision, ok := channels.ParseApprovalCallback(event.Event.Action.Value[approvalValueKey])
+	if !ok {
+		return
+	}
+	status, err := c.ResolveApproval(ctx, id, decision, event.Event.Operator.OpenID)
+	if err != nil {
+		status = err.Error()
+	}
+	if event.Event.Context.OpenMessageID == "" {
+		return
+	}
+	card, _ := json.Marshal(map[string]any{
+		"config":   map[string]any{"wide_screen_mode": true},
+		"elements": []map[string]any{{"tag": "div", "text": map[string]string{"tag": "plain_text", "content": status}}},
+	})
+	if err == nil {
+		if err := c.client.UpdateMessageCard(ctx, event.Event.Context.OpenMessageID, string(card)); err != nil {
+			slog.Debug("feishu: approval card update failed", "id", id, "error", err)
+		}
+		return
+	}
+	// Leave the buttons for an authorized approver; tell the presser why.
+	if chatID := event.Event.Context.OpenChatID; chatID != "" {
+		if sendErr := c.sendText(ctx, chatID, "chat_id", status); sendErr != nil {
+			slog.Debug("feishu: approval error notice failed", "id", id, "error", sendErr)
+		}
+	}
+}
@@ -219,16 +219,6 @@ func (c *Channel) handleMessageEvent(ctx context.Context, event *MessageEvent) {
 			m := &mediaList[i]
 
 			switch m.Type {
-			case media.TypeAudio, media.TypeVoice:
-				transcript, sttErr := c.transcribeAudio(ctx, m.FilePath)
-				if sttErr != nil {
-					slog.Warn("feishu: STT transcription failed",
-						"type", m.Type, "error", sttErr,
-					)
-				} else {
-					m.Transcript = transcript
-				}
-
 			case media.TypeDocument:
 				if m.FileName != "" && m.FilePath != "" {
 					docContent, err := media.ExtractDocumentContent(m.FilePath, m.FileName)
@@ -220,8 +220,15 @@ func (a *wsEventAdapter) HandleEvent(ctx context.Context, payload []byte) error
 		slog.Debug("feishu ws: parse event failed", "error", err)
 		return fmt.Errorf("parse event: %w", err)
 	}
-	if event.Header.EventType == "im.message.receive_v1" {
+	switch event.Header.EventType {
+	case "im.message.receive_v1":
 		a.ch.handleMessageEvent(ctx, &event)
+	case "card.action.trigger":
+		var action CardActionEvent
+		if err := json.Unmarshal(payload, &action); err != nil {
+			return fmt.Errorf("parse card action: %w", err)
+		}
+		go a.ch.handleCardAction(context.WithoutCancel(ctx), &action)
 	}
 	return nil
 }
@@ -262,6 +269,8 @@ func (c *Channel) WebhookHandler() (string, http.Handler) {
 	handler := NewWebhookHandler(c.cfg.VerificationToken, c.cfg.EncryptKey, func(event *MessageEvent) {
 		ctx := store.WithTenantID(context.Background(), c.TenantID())
 		c.handleMessageEvent(ctx, event)
+	}, func(event *CardActionEvent) {
+		c.handleCardAction(context.Background(), event)
 	})
 
 	return path, http.HandlerFunc(handler)
@@ -283,6 +292,8 @@ func (c *Channel) startWebhook(ctx context.Context) error {
 	handler := NewWebhookHandler(c.cfg.VerificationToken, c.cfg.EncryptKey, func(event *MessageEvent) {
 		ctx := store.WithTenantID(context.Background(), c.TenantID())
 		c.handleMessageEvent(ctx, event)
+	}, func(event *CardActionEvent) {
+		c.handleCardAction(context.Background(), event)
 	})
 
 	mux := http.NewServeMux()
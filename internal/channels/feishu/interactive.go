@@ -0,0 +1,166 @@
+package feishu
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strconv"
+
+	"github.com/nextlevelbuilder/goclaw/internal/bus"
+	"github.com/nextlevelbuilder/goclaw/internal/channels"
+)
+
+// interactiveValueKey holds the question payload in a button's or select's value.
+const interactiveValueKey = "interactive"
+
+// interactiveChoicesName is the form name of the multi-select in multi-choice cards.
+const interactiveChoicesName = "iq_choices"
+
+// SendInteractive shows a question as an interactive card: buttons or a
+// static select for single choice; a form with a multi-select or inputs and a
+// Submit button for multi choice and forms. Implements channels.InteractiveChannel.
+func (c *Channel) SendInteractive(ctx context.Context, msg bus.OutboundMessage) error {
+	if !c.IsRunning() {
+		return fmt.Errorf("feishu bot not running")
+	}
+	im := msg.Interactive
+	plain := func(s string) map[string]string { return map[string]string{"tag": "plain_text", "content": s} }
+	value := func(action string, index int) map[string]string {
+		return map[string]string{interactiveValueKey: channels.InteractiveCallbackData(im.ID, action, index)}
+	}
+	options := func() []map[string]any {
+		var opts []map[string]any
+		for i, o := range im.Options {
+			opts = append(opts, map[string]any{"text": plain(o.Label), "value": strconv.Itoa(i)})
+		}
+		return opts
+	}
+	question := map[string]any{"tag": "div", "text": plain(msg.Content)}
+
+	var card map[string]any
+	switch im.Kind {
+	case bus.InteractiveButtons, bus.InteractiveSingle:
+		var actions []map[string]any
+		if im.Kind == bus.InteractiveButtons {
+			for i, o := range im.Options {
+				actions = append(actions, map[string]any{
+					"tag": "button", "text": plain(o.Label), "type": "default",
+					"value": value(channels.InteractiveActionPick, i),
+				})
+			}
+		} else {
+			actions = append(actions, map[string]any{
+				"tag": "select_static", "placeholder": plain("Choose…"), "options": options(),
+				"value": value(channels.InteractiveActionSubmit, 0),
+			})
+		}
+		card = map[string]any{
+			"config":   map[string]any{"wide_screen_mode": true},
+			"elements": []map[string]any{question, {"tag": "action", "actions": actions}},
+		}
+	default:
+		// Multi choice and forms need a form container (card JSON 2.0).
+		var inputs []map[string]any
+		if im.Kind == bus.InteractiveMulti {
+			inputs = append(inputs, map[string]any{
+				"tag": "multi_select_static", "name": interactiveChoicesName,
+				"placeholder": plain("Choose…"), "options": options(), "required": true,
+			})
+		}
+		for _, f := range im.Fields {
+			input := map[string]any{"tag": "input", "name": f.Name, "label": plain(f.Label), "required": f.Required}
+			if f.Placeholder != "" {
+				input["placeholder"] = plain(f.Placeholder)
+			}
+			inputs = append(inputs, input)
+		}
+		inputs = append(inputs, map[string]any{
+			"tag": "button", "text": plain("Submit"), "type": "primary",
+			"action_type": "form_submit", "name": "submit",
+			"behaviors": []map[string]any{{"type": "callback", "value": value(channels.InteractiveActionSubmit, 0)}},
+		})
+		card = map[string]any{
+			"schema": "2.0",
+			"body": map[string]any{"elements": []map[string]any{
+				question,
+				{"tag": "form", "name": "iq_form", "elements": inputs},
+			}},
+		}
+	}
+
+	cardJSON, err := json.Marshal(card)
+	if err != nil {
+		return fmt.Errorf("marshal card: %w", err)
+	}
+	if _, err := c.client.SendMessage(ctx, resolveReceiveIDType(msg.ChatID), msg.ChatID, "interactive", string(cardJSON)); err != nil {
+		return fmt.Errorf("feishu send interactive card: %w", err)
+	}
+	return nil
+}
+
+// handleInteractiveCardAction answers a question from a card button, select
+// or form submit, then replaces the card with the answer.
+func (c *Channel) handleInteractiveCardAction(ctx context.Context, event *CardActionEvent, id, action string, index int) {
+	senderID := event.Event.Operator.OpenID
+	act := event.Event.Action
+	answer := bus.InteractiveAnswer{ID: id, SenderID: senderID}
+	im, ok := channels.LookupInteractive(id)
+	var err error
+	switch {
+	case !ok:
+		err = fmt.Errorf("this question is no longer active")
+	case !channels.InteractiveSenderAllowed(im, senderID):
+		err = channels.ErrInteractiveForbidden
+	case action == channels.InteractiveActionPick:
+		answer, ok = channels.InteractivePickAnswer(id, index, senderID)
+	case act.Option != "":
+		if n, err := strconv.Atoi(act.Option); err == nil && n >= 0 && n < len(im.Options) {
+			answer.Choices = []string{im.Options[n].Value}
+		}
+	default:
+		if vals, isList := act.FormValue[interactiveChoicesName].([]any); isList {
+			for _, v := range vals {
+				s, _ := v.(string)
+				if n, err := strconv.Atoi(s); err == nil && n >= 0 && n < len(im.Options) {
+					answer.Choices = append(answer.Choices, im.Options[n].Value)
+				}
+			}
+		}
+		for _, f := range im.Fields {
+			if s, _ := act.FormValue[f.Name].(string); s != "" {
+				if answer.Fields == nil {
+					answer.Fields = map[string]string{}
+				}
+				answer.Fields[f.Name] = s
+			}
+		}
+	}
+
+	var status string
+	if err == nil {
+		if ok {
+			status, err = c.AnswerInteractive(ctx, answer)
+		} else {
+			err = fmt.Errorf("this question is no longer active")
+		}
+	}
+	if err != nil {
+		if chatID := event.Event.Context.OpenChatID; chatID != "" {
+			if sendErr := c.sendText(ctx, chatID, "chat_id", err.Error()); sendErr != nil {
+				slog.Debug("feishu: interactive error notice failed", "id", id, "error", sendErr)
+			}
+		}
+		return
+	}
+	if event.Event.Context.OpenMessageID == "" {
+		return
+	}
+	card, _ := json.Marshal(map[string]any{
+		"config":   map[string]any{"wide_screen_mode": true},
+		"elements": []map[string]any{{"tag": "div", "text": map[string]string{"tag": "plain_text", "content": status}}},
+	})
+	if err := c.client.UpdateMessageCard(ctx, event.Event.Context.OpenMessageID, string(card)); err != nil {
+		slog.Debug("feishu: interactive card update failed", "id", id, "error", err)
+	}
+}
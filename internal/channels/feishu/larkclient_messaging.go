@@ -37,6 +37,18 @@ func (c *LarkClient) SendMessage(ctx context.Context, receiveIDType, receiveID,
 	return &data, nil
 }
 
+// UpdateMessageCard replaces the content of an interactive card message.
+func (c *LarkClient) UpdateMessageCard(ctx context.Context, messageID, content string) error {
+	resp, err := c.doJSON(ctx, "PATCH", "/open-apis/im/v1/messages/"+messageID, map[string]string{"content": content})
+	if err != nil {
+		return err
+	}
+	if resp.Code != 0 {
+		return fmt.Errorf("update message card: code=%d msg=%s", resp.Code, resp.Msg)
+	}
+	return nil
+}
+
 // --- IM API: Images ---
 
 func (c *LarkClient) DownloadImage(ctx context.Context, imageKey string) ([]byte, error) {
@@ -83,8 +83,9 @@ type webhookEvent struct {
 // --- Webhook HTTP handler ---
 
 // NewWebhookHandler creates an http.HandlerFunc that handles Feishu webhook events.
-// Supports: URL verification challenge, event decryption, and message dispatch.
-func NewWebhookHandler(verificationToken, encryptKey string, onMessage func(event *MessageEvent)) http.HandlerFunc {
+// Supports: URL verification challenge, event decryption, message dispatch and
+// card button callbacks (card.action.trigger).
+func NewWebhookHandler(verificationToken, encryptKey string, onMessage func(event *MessageEvent), onCardAction func(event *CardActionEvent)) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		if r.Method != "POST" {
 			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
@@ -144,9 +145,18 @@ func NewWebhookHandler(verificationToken, encryptKey string, onMessage func(even
 			return
 		}
 
-		// Only handle message events
-		if event.Header.EventType == "im.message.receive_v1" {
+		switch event.Header.EventType {
+		case "im.message.receive_v1":
 			go onMessage(&event)
+		case "card.action.trigger":
+			var action CardActionEvent
+			if err := json.Unmarshal(eventBody, &action); err == nil && onCardAction != nil {
+				go onCardAction(&action)
+			}
+			// Card callbacks expect a JSON body (empty = no toast, card unchanged).
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte("{}"))
+			return
 		}
 
 		w.WriteHeader(http.StatusOK)
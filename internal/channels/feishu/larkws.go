@@ -317,8 +317,8 @@ func (c *WSClient) handleFrame(ctx context.Context, f *wsFrame) {
 		}
 
 	case f.Method == frameTypeData:
-		// Only process "event" type frames; ignore card, etc.
-		if frameType != "" && frameType != "event" {
+		// Only process "event" and "card" (card button callback) frames.
+		if frameType != "" && frameType != "event" && frameType != "card" {
 			slog.Debug("lark ws: ignoring non-event data frame", "type", frameType)
 			return
 		}
@@ -112,9 +112,7 @@ func (l *InstanceLoader) Reload(ctx context.Context) {
 	// Stop and unregister old channels
 	for name := range l.loaded {
 		if ch, ok := l.manager.GetChannel(name); ok {
-			if err := ch.Stop(ctx); err != nil {
-				slog.Warn("failed to stop channel instance on reload", "name", name, "error", err)
-			}
+			l.manager.stopChannel(ctx, name, ch)
 		}
 		l.manager.UnregisterChannel(name)
 	}
@@ -151,15 +149,27 @@ func (l *InstanceLoader) Stop(ctx context.Context) {
 
 	for name := range l.loaded {
 		if ch, ok := l.manager.GetChannel(name); ok {
-			if err := ch.Stop(ctx); err != nil {
-				slog.Warn("failed to stop channel instance", "name", name, "error", err)
-			}
+			l.manager.stopChannel(ctx, name, ch)
 		}
 		l.manager.UnregisterChannel(name)
 	}
 	l.loaded = make(map[string]struct{})
 }
 
+// warnInstanceSTTProxy flags channel instances that still carry stt_proxy_url.
+// Channels no longer transcribe on their own and instances are tenant-owned, so the
+// setting is ignored rather than promoted to the gateway-wide proxy provider.
+func warnInstanceSTTProxy(name, channelType string, cfg json.RawMessage) {
+	var c struct {
+		STTProxyURL string `json:"stt_proxy_url"`
+	}
+	if len(cfg) == 0 || json.Unmarshal(cfg, &c) != nil || c.STTProxyURL == "" {
+		return
+	}
+	slog.Warn("channel instance stt_proxy_url is ignored; configure stt.proxy on the gateway and stt.provider per tenant instead",
+		"name", name, "type", channelType)
+}
+
 // coerceStringBools converts string "true"/"false" values to JSON booleans
 // in a raw config blob. Older UI versions saved select-based bool fields as strings.
 func coerceStringBools(data json.RawMessage) json.RawMessage {
@@ -213,6 +223,7 @@ func (l *InstanceLoader) loadInstance(ctx context.Context, inst store.ChannelIns
 	// Normalize config: convert string "true"/"false" to JSON booleans.
 	// Older UI versions saved select-based bool fields as strings.
 	cfg := coerceStringBools(inst.Config)
+	warnInstanceSTTProxy(inst.Name, inst.ChannelType, cfg)
 
 	ch, err := factory(inst.Name, inst.Credentials, cfg, l.msgBus, l.pairingSvc)
 	if err != nil {
@@ -306,11 +317,9 @@ func (l *InstanceLoader) loadInstance(ctx context.Context, inst store.ChannelIns
 	l.loaded[inst.Name] = struct{}{}
 
 	// Start the channel if requested (Reload path). LoadAll defers to StartAll.
+	// A failed start leaves the channel registered — it shows as not running.
 	if autoStart {
-		if err := ch.Start(ctx); err != nil {
-			slog.Error("channel instance start failed", "name", inst.Name, "error", err)
-			// Still registered — will show as not running.
-		}
+		l.manager.startChannel(ctx, inst.Name, ch)
 	}
 
 	slog.Info("channel instance loaded",
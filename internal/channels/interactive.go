@@ -0,0 +1,375 @@
+package channels
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nextlevelbuilder/goclaw/internal/bus"
+)
+
+// InteractiveChannel is implemented by channels that render
+// bus.InteractiveMessage natively (buttons, menus, forms). Other channels get
+// the question as a numbered text prompt and the reply is parsed from text.
+type InteractiveChannel interface {
+	Channel
+	SendInteractive(ctx context.Context, msg bus.OutboundMessage) error
+}
+
+// InteractiveResolver delivers an answer given through native controls.
+// Returns a short status line to show in place of the controls.
+type InteractiveResolver func(ctx context.Context, channel string, answer bus.InteractiveAnswer) (string, error)
+
+// SetInteractiveResolver sets the handler for answers given through native controls.
+func (c *BaseChannel) SetInteractiveResolver(fn InteractiveResolver) { c.interactiveResolver = fn }
+
+// AnswerInteractive passes an answer to the interactive resolver and forgets
+// the question's local state once it was accepted.
+func (c *BaseChannel) AnswerInteractive(ctx context.Context, answer bus.InteractiveAnswer) (string, error) {
+	if c.interactiveResolver == nil {
+		return "", fmt.Errorf("this question is no longer active")
+	}
+	status, err := c.interactiveResolver(ctx, c.name, answer)
+	if err == nil {
+		ForgetInteractive(answer.ID)
+	}
+	return status, err
+}
+
+// SetInteractiveResolver sets the interactive resolver for all current and future channels.
+func (m *Manager) SetInteractiveResolver(fn InteractiveResolver) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.interactiveResolver = fn
+	for _, ch := range m.channels {
+		if bc, ok := ch.(interface{ SetInteractiveResolver(InteractiveResolver) }); ok {
+			bc.SetInteractiveResolver(fn)
+		}
+	}
+}
+
+// sendOutbound sends msg, rendering interactive content natively when the
+// channel supports it and as numbered text otherwise.
+func sendOutbound(ctx context.Context, channel Channel, msg bus.OutboundMessage) error {
+	if msg.Interactive == nil {
+		return channel.Send(ctx, msg)
+	}
+	if ic, ok := channel.(InteractiveChannel); ok {
+		TrackInteractive(msg.Interactive)
+		return ic.SendInteractive(ctx, msg)
+	}
+	msg.Content = FormatInteractiveText(msg.Content, msg.Interactive)
+	msg.Interactive = nil
+	return channel.Send(ctx, msg)
+}
+
+// --- Button payloads ---
+
+// interactiveCallbackPrefix marks button payloads of interactive messages:
+// "iq:<id>:<action>" where action is an option index ("3"), a toggle ("t3"),
+// "ok" (submit a multi choice) or "form" (open a form). Fits Telegram's
+// 64-byte callback data limit.
+const interactiveCallbackPrefix = "iq:"
+
+// Interactive callback actions.
+const (
+	InteractiveActionPick   = "pick"
+	InteractiveActionToggle = "toggle"
+	InteractiveActionSubmit = "ok"
+	InteractiveActionForm   = "form"
+)
+
+// InteractiveCallbackData encodes a button payload. index is ignored for
+// submit and form actions.
+func InteractiveCallbackData(id, action string, index int) string {
+	switch action {
+	case InteractiveActionToggle:
+		return interactiveCallbackPrefix + id + ":t" + strconv.Itoa(index)
+	case InteractiveActionSubmit, InteractiveActionForm:
+		return interactiveCallbackPrefix + id + ":" + action
+	}
+	return interactiveCallbackPrefix + id + ":" + strconv.Itoa(index)
+}
+
+// ParseInteractiveCallback decodes a payload made by InteractiveCallbackData.
+func ParseInteractiveCallback(data string) (id, action string, index int, ok bool) {
+	rest, found := strings.CutPrefix(data, interactiveCallbackPrefix)
+	if !found {
+		return "", "", 0, false
+	}
+	i := strings.LastIndexByte(rest, ':')
+	if i <= 0 {
+		return "", "", 0, false
+	}
+	id, act := rest[:i], rest[i+1:]
+	switch {
+	case act == InteractiveActionSubmit || act == InteractiveActionForm:
+		return id, act, 0, true
+	case strings.HasPrefix(act, "t"):
+		n, err := strconv.Atoi(act[1:])
+		return id, InteractiveActionToggle, n, err == nil
+	}
+	n, err := strconv.Atoi(act)
+	return id, InteractiveActionPick, n, err == nil
+}
+
+// --- Local state (multi-choice selections) ---
+
+// interactiveTTL bounds how long sent questions are remembered locally.
+const interactiveTTL = 24 * time.Hour
+
+type interactiveState struct {
+	msg      *bus.InteractiveMessage
+	selected []bool
+	sentAt   time.Time
+}
+
+var (
+	interactiveMu    sync.Mutex
+	interactiveSent  = map[string]*interactiveState{}
+	interactivePrune time.Time
+)
+
+// TrackInteractive remembers a sent question so button presses can be mapped
+// back to options and multi-choice selections can be toggled.
+func TrackInteractive(msg *bus.InteractiveMessage) {
+	interactiveMu.Lock()
+	defer interactiveMu.Unlock()
+	now := time.Now()
+	if now.Sub(interactivePrune) > time.Hour {
+		for id, st := range interactiveSent {
+			if now.Sub(st.sentAt) > interactiveTTL {
+				delete(interactiveSent, id)
+			}
+		}
+		interactivePrune = now
+	}
+	interactiveSent[msg.ID] = &interactiveState{msg: msg, selected: make([]bool, len(msg.Options)), sentAt: now}
+}
+
+// LookupInteractive returns a tracked question.
+func LookupInteractive(id string) (*bus.InteractiveMessage, bool) {
+	interactiveMu.Lock()
+	defer interactiveMu.Unlock()
+	st, ok := interactiveSent[id]
+	if !ok {
+		return nil, false
+	}
+	return st.msg, true
+}
+
+// ErrInteractiveForbidden is returned when someone other than the asked
+// user presses a question's buttons.
+var ErrInteractiveForbidden = errors.New("only the person who was asked can answer")
+
+var errInteractiveInactive = errors.New("this question is no longer active")
+
+// InteractiveSenderAllowed reports whether senderID may act on msg: every
+// press, not just the final answer, since toggles change shared state.
+func InteractiveSenderAllowed(msg *bus.InteractiveMessage, senderID string) bool {
+	if msg.AskedID == "" {
+		return true
+	}
+	id := senderID
+	if i := strings.IndexByte(id, '|'); i > 0 {
+		id = id[:i]
+	}
+	return id == msg.AskedID
+}
+
+// ToggleInteractiveChoice flips the selection of an option of a multi-choice
+// question for senderID and returns the current selection.
+func ToggleInteractiveChoice(id string, index int, senderID string) ([]bool, error) {
+	interactiveMu.Lock()
+	defer interactiveMu.Unlock()
+	st, ok := interactiveSent[id]
+	if !ok || index < 0 || index >= len(st.selected) {
+		return nil, errInteractiveInactive
+	}
+	if !InteractiveSenderAllowed(st.msg, senderID) {
+		return nil, ErrInteractiveForbidden
+	}
+	st.selected[index] = !st.selected[index]
+	return append([]bool(nil), st.selected...), nil
+}
+
+// SelectedInteractiveChoices returns the values of the selected options.
+func SelectedInteractiveChoices(id string) []string {
+	interactiveMu.Lock()
+	defer interactiveMu.Unlock()
+	st, ok := interactiveSent[id]
+	if !ok {
+		return nil
+	}
+	var values []string
+	for i, sel := range st.selected {
+		if sel {
+			values = append(values, st.msg.Options[i].Value)
+		}
+	}
+	return values
+}
+
+// ForgetInteractive drops a question's local state.
+func ForgetInteractive(id string) {
+	interactiveMu.Lock()
+	delete(interactiveSent, id)
+	interactiveMu.Unlock()
+}
+
+// InteractivePickAnswer builds the answer for a tapped option (index into
+// the tracked question's options).
+func InteractivePickAnswer(id string, index int, senderID string) (bus.InteractiveAnswer, bool) {
+	msg, ok := LookupInteractive(id)
+	if !ok || index < 0 || index >= len(msg.Options) {
+		return bus.InteractiveAnswer{}, false
+	}
+	return bus.InteractiveAnswer{ID: id, SenderID: senderID, Choices: []string{msg.Options[index].Value}}, true
+}
+
+// --- Text rendering and parsing ---
+
+// FormatInteractiveText renders a question as plain text: numbered options
+// or form fields, plus how to reply.
+func FormatInteractiveText(question string, msg *bus.InteractiveMessage) string {
+	var sb strings.Builder
+	sb.WriteString(question)
+	if msg.IsChoice() {
+		sb.WriteString("\n")
+		for i, o := range msg.Options {
+			fmt.Fprintf(&sb, "\n%d. %s", i+1, o.Label)
+		}
+		if msg.Kind == bus.InteractiveMulti {
+			sb.WriteString("\n\nReply with the numbers of your choices (e.g. 1, 3).")
+		} else {
+			sb.WriteString("\n\nReply with a number.")
+		}
+		return sb.String()
+	}
+	if len(msg.Fields) == 1 {
+		fmt.Fprintf(&sb, "\n\nReply with your %s.", strings.ToLower(msg.Fields[0].Label))
+		return sb.String()
+	}
+	sb.WriteString("\n\nReply with one line per field:")
+	for _, f := range msg.Fields {
+		line := f.Label + ": "
+		if f.Placeholder != "" {
+			line += "<" + f.Placeholder + ">"
+		}
+		if !f.Required {
+			line += " (optional)"
+		}
+		sb.WriteString("\n" + line)
+	}
+	return sb.String()
+}
+
+// ParseInteractiveText interprets a text reply to a question. Choice replies
+// are option numbers or labels; form replies are "Label: value" lines (or the
+// whole text for a single field). Text that matches nothing is kept as Text.
+func ParseInteractiveText(msg *bus.InteractiveMessage, text string) bus.InteractiveAnswer {
+	text = strings.TrimSpace(text)
+	answer := bus.InteractiveAnswer{ID: msg.ID}
+	if msg.IsChoice() {
+		answer.Choices = parseChoiceText(msg, text)
+		if len(answer.Choices) == 0 {
+			answer.Text = text
+		}
+		return answer
+	}
+
+	answer.Fields = map[string]string{}
+	if len(msg.Fields) == 1 {
+		answer.Fields[msg.Fields[0].Name] = text
+		return answer
+	}
+	var unmatched []string
+	for line := range strings.SplitSeq(text, "\n") {
+		key, value, found := strings.Cut(line, ":")
+		name := ""
+		if found {
+			name = matchField(msg.Fields, strings.TrimSpace(key))
+		}
+		if name == "" {
+			if strings.TrimSpace(line) != "" {
+				unmatched = append(unmatched, line)
+			}
+			continue
+		}
+		answer.Fields[name] = strings.TrimSpace(value)
+	}
+	if len(answer.Fields) == 0 {
+		answer.Fields = nil
+	}
+	if len(unmatched) > 0 {
+		answer.Text = strings.Join(unmatched, "\n")
+	}
+	return answer
+}
+
+func parseChoiceText(msg *bus.InteractiveMessage, text string) []string {
+	for i, o := range msg.Options {
+		if strings.EqualFold(text, o.Label) || strings.EqualFold(text, o.Value) {
+			return []string{msg.Options[i].Value}
+		}
+	}
+	var values []string
+	for _, part := range strings.FieldsFunc(text, func(r rune) bool { return r == ',' || r == ' ' || r == ';' }) {
+		n, err := strconv.Atoi(strings.TrimSuffix(part, "."))
+		if err != nil || n < 1 || n > len(msg.Options) {
+			return nil
+		}
+		v := msg.Options[n-1].Value
+		if !containsString(values, v) {
+			values = append(values, v)
+		}
+	}
+	if msg.Kind != bus.InteractiveMulti && len(values) > 1 {
+		return nil
+	}
+	return values
+}
+
+func matchField(fields []bus.InteractiveField, key string) string {
+	for _, f := range fields {
+		if strings.EqualFold(key, f.Label) || strings.EqualFold(key, f.Name) {
+			return f.Name
+		}
+	}
+	return ""
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// InteractiveAnswerSummary is the status line shown once a question was answered.
+func InteractiveAnswerSummary(msg *bus.InteractiveMessage, answer bus.InteractiveAnswer) string {
+	var parts []string
+	for _, v := range answer.Choices {
+		if o, ok := msg.Option(v); ok {
+			parts = append(parts, o.Label)
+		}
+	}
+	for _, f := range msg.Fields {
+		if v := answer.Fields[f.Name]; v != "" {
+			parts = append(parts, f.Label+": "+v)
+		}
+	}
+	if answer.Text != "" {
+		parts = append(parts, answer.Text)
+	}
+	if len(parts) == 0 {
+		return "✅ Answered"
+	}
+	return "✅ " + strings.Join(parts, ", ")
+}
@@ -48,12 +48,26 @@ type RunContext struct {
 // Manager manages all registered channels, handling their lifecycle
 // and routing outbound messages to the correct channel.
 type Manager struct {
-	channels         map[string]Channel
-	bus              *bus.MessageBus
-	runs             sync.Map // runID string → *RunContext
-	dispatchTask     *asyncTask
-	mu               sync.RWMutex
-	contactCollector *store.ContactCollector
+	channels            map[string]Channel
+	bus                 *bus.MessageBus
+	runs                sync.Map // runID string → *RunContext
+	dispatchTask        *asyncTask
+	mu                  sync.RWMutex
+	contactCollector    *store.ContactCollector
+	approvalResolver    ApprovalResolver
+	interactiveResolver InteractiveResolver
+	ownership           Ownership // nil = single replica, every channel runs here
+}
+
+// Ownership decides which gateway replica runs each channel instance in
+// cluster mode. Claim starts the channel once this replica holds its lease and
+// stops it if the lease moves elsewhere; Forward hands an outbound message to
+// the replica that runs the channel and fails if it could not be handed over.
+type Ownership interface {
+	Claim(name string, start, stop func())
+	Release(name string)
+	Owns(name string) bool
+	Forward(msg bus.OutboundMessage) error
 }
 
 type asyncTask struct {
@@ -89,10 +103,7 @@ func (m *Manager) StartAll(ctx context.Context) error {
 	slog.Info("starting all channels")
 
 	for name, channel := range m.channels {
-		slog.Info("starting channel", "channel", name)
-		if err := channel.Start(ctx); err != nil {
-			slog.Error("failed to start channel", "channel", name, "error", err)
-		}
+		m.startChannel(ctx, name, channel)
 	}
 
 	slog.Info("all channels started")
@@ -112,16 +123,63 @@ func (m *Manager) StopAll(ctx context.Context) error {
 	}
 
 	for name, channel := range m.channels {
-		slog.Info("stopping channel", "channel", name)
-		if err := channel.Stop(ctx); err != nil {
-			slog.Error("error stopping channel", "channel", name, "error", err)
-		}
+		m.stopChannel(ctx, name, channel)
 	}
 
 	slog.Info("all channels stopped")
 	return nil
 }
 
+// SetOwnership enables cluster mode: channels start only on the replica that
+// holds their lease. Must be called before StartAll.
+func (m *Manager) SetOwnership(o Ownership) {
+	m.ownership = o
+}
+
+// Clustered reports whether channels are leased across replicas.
+func (m *Manager) Clustered() bool {
+	return m.ownership != nil
+}
+
+// Bus returns the message bus the manager dispatches from.
+func (m *Manager) Bus() *bus.MessageBus {
+	return m.bus
+}
+
+// startChannel starts a channel, or in cluster mode claims its lease and
+// starts it when (and wherever) the lease is acquired.
+func (m *Manager) startChannel(ctx context.Context, name string, channel Channel) {
+	start := func() {
+		slog.Info("starting channel", "channel", name)
+		if err := channel.Start(ctx); err != nil {
+			slog.Error("failed to start channel", "channel", name, "error", err)
+		}
+	}
+	if m.ownership == nil {
+		start()
+		return
+	}
+	m.ownership.Claim(name, start, func() {
+		slog.Info("stopping channel (lease moved)", "channel", name)
+		if err := channel.Stop(context.Background()); err != nil {
+			slog.Error("error stopping channel", "channel", name, "error", err)
+		}
+	})
+}
+
+// stopChannel stops a channel; in cluster mode only if it runs here, and its
+// lease is released so another replica can take over.
+func (m *Manager) stopChannel(ctx context.Context, name string, channel Channel) {
+	if m.ownership != nil {
+		m.ownership.Release(name)
+		return
+	}
+	slog.Info("stopping channel", "channel", name)
+	if err := channel.Stop(ctx); err != nil {
+		slog.Error("error stopping channel", "channel", name, "error", err)
+	}
+}
+
 // GetChannel returns a channel by name.
 func (m *Manager) GetChannel(name string) (Channel, bool) {
 	m.mu.RLock()
@@ -167,6 +225,16 @@ func (m *Manager) RegisterChannel(name string, channel Channel) {
 			bc.SetContactCollector(m.contactCollector)
 		}
 	}
+	if m.approvalResolver != nil {
+		if bc, ok := channel.(interface{ SetApprovalResolver(ApprovalResolver) }); ok {
+			bc.SetApprovalResolver(m.approvalResolver)
+		}
+	}
+	if m.interactiveResolver != nil {
+		if bc, ok := channel.(interface{ SetInteractiveResolver(InteractiveResolver) }); ok {
+			bc.SetInteractiveResolver(m.interactiveResolver)
+		}
+	}
 	m.channels[name] = channel
 }
 
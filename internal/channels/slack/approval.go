@@ -0,0 +1,81 @@
+package slack
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	slackapi "github.com/slack-go/slack"
+	"github.com/slack-go/slack/socketmode"
+
+	"github.com/nextlevelbuilder/goclaw/internal/channels"
+	"github.com/nextlevelbuilder/goclaw/internal/store"
+)
+
+// SendApprovalRequest shows a tool approval request with Block Kit buttons.
+// Implements channels.ApprovalChannel.
+func (c *Channel) SendApprovalRequest(_ context.Context, chatID string, req channels.ApprovalRequest) error {
+	if !c.IsRunning() {
+		return fmt.Errorf("slack bot not running")
+	}
+	text := channels.FormatApprovalText(req)
+
+	var buttons []slackapi.BlockElement
+	for _, b := range channels.ApprovalButtonLabels() {
+		btn := slackapi.NewButtonBlockElement(channels.ApprovalCallbackData(req.ID, b[0]), b[0],
+			slackapi.NewTextBlockObject(slackapi.PlainTextType, b[1], true, false))
+		switch b[0] {
+		case channels.ApprovalAllowOnce:
+			btn = btn.WithStyle(slackapi.StylePrimary)
+		case channels.ApprovalDeny:
+			btn = btn.WithStyle(slackapi.StyleDanger)
+		}
+		buttons = append(buttons, btn)
+	}
+	blocks := []slackapi.Block{
+		slackapi.NewSectionBlock(slackapi.NewTextBlockObject(slackapi.PlainTextType, text, true, false), nil, nil),
+		slackapi.NewActionBlock("approval", buttons...),
+	}
+	_, _, err := c.api.PostMessage(chatID, slackapi.MsgOptionText(text, false), slackapi.MsgOptionBlocks(blocks...))
+	return err
+}
+
+// handleInteractive handles Block Kit button presses (socket mode
+// "interactive" envelopes): tool approvals and answers to interactive questions.
+func (c *Channel) handleInteractive(evt socketmode.Event) {
+	cb, ok := evt.Data.(slackapi.InteractionCallback)
+	if !ok {
+		return
+	}
+	c.sm.Ack(*evt.Request)
+	if cb.Type != slackapi.InteractionTypeBlockActions {
+		return
+	}
+
+	ctx := store.WithTenantID(context.Background(), c.TenantID())
+	for _, action := range cb.ActionCallback.BlockActions {
+		if id, act, index, ok := channels.ParseInteractiveCallback(action.ActionID); ok {
+			c.handleInteractiveAction(ctx, cb, action, id, act, index)
+			continue
+		}
+		id, decision, ok := channels.ParseApprovalCallback(action.ActionID)
+		if !ok {
+			continue
+		}
+		status, err := c.ResolveApproval(ctx, id, decision, cb.User.ID)
+		if err != nil {
+			if _, postErr := c.api.PostEphemeral(cb.Channel.ID, cb.User.ID, slackapi.MsgOptionText(err.Error(), false)); postErr != nil {
+				slog.Debug("slack: approval error notice failed", "id", id, "error", postErr)
+			}
+			continue
+		}
+
+		// Replace the buttons with the outcome.
+		text := cb.Message.Text + "\n\n" + status
+		section := slackapi.NewSectionBlock(slackapi.NewTextBlockObject(slackapi.PlainTextType, text, true, false), nil, nil)
+		if _, _, _, err := c.api.UpdateMessage(cb.Channel.ID, cb.Message.Timestamp,
+			slackapi.MsgOptionText(text, false), slackapi.MsgOptionBlocks(section)); err != nil {
+			slog.Debug("slack: approval message update failed", "id", id, "error", err)
+		}
+	}
+}
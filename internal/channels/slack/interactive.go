@@ -0,0 +1,156 @@
+package slack
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strconv"
+
+	slackapi "github.com/slack-go/slack"
+
+	"github.com/nextlevelbuilder/goclaw/internal/bus"
+	"github.com/nextlevelbuilder/goclaw/internal/channels"
+)
+
+// Block and action IDs whose values are read from the message state on submit.
+const (
+	interactiveChoicesBlock = "iq_choices"
+	interactiveFieldBlock   = "iq_field_"
+	interactiveValueAction  = "value"
+)
+
+// SendInteractive shows a question with Block Kit: buttons, a static select
+// (single), checkboxes (multi) or input blocks (form), the last two with a
+// Submit button. Implements channels.InteractiveChannel.
+func (c *Channel) SendInteractive(_ context.Context, msg bus.OutboundMessage) error {
+	if !c.IsRunning() {
+		return fmt.Errorf("slack bot not running")
+	}
+	im := msg.Interactive
+	plain := func(s string) *slackapi.TextBlockObject {
+		return slackapi.NewTextBlockObject(slackapi.PlainTextType, s, true, false)
+	}
+	submit := slackapi.NewButtonBlockElement(channels.InteractiveCallbackData(im.ID, channels.InteractiveActionSubmit, 0), "submit", plain("Submit")).
+		WithStyle(slackapi.StylePrimary)
+	options := func() []*slackapi.OptionBlockObject {
+		var opts []*slackapi.OptionBlockObject
+		for i, o := range im.Options {
+			opts = append(opts, slackapi.NewOptionBlockObject(strconv.Itoa(i), plain(o.Label), nil))
+		}
+		return opts
+	}
+
+	blocks := []slackapi.Block{slackapi.NewSectionBlock(plain(msg.Content), nil, nil)}
+	switch im.Kind {
+	case bus.InteractiveButtons:
+		var buttons []slackapi.BlockElement
+		for i, o := range im.Options {
+			buttons = append(buttons, slackapi.NewButtonBlockElement(
+				channels.InteractiveCallbackData(im.ID, channels.InteractiveActionPick, i), o.Value, plain(o.Label)))
+		}
+		blocks = append(blocks, slackapi.NewActionBlock(interactiveChoicesBlock, buttons...))
+	case bus.InteractiveSingle:
+		sel := slackapi.NewOptionsSelectBlockElement(slackapi.OptTypeStatic, plain("Choose…"),
+			channels.InteractiveCallbackData(im.ID, channels.InteractiveActionSubmit, 0), options()...)
+		blocks = append(blocks, slackapi.NewActionBlock(interactiveChoicesBlock, sel))
+	case bus.InteractiveMulti:
+		boxes := slackapi.NewCheckboxGroupsBlockElement(interactiveValueAction, options()...)
+		blocks = append(blocks,
+			slackapi.NewActionBlock(interactiveChoicesBlock, boxes),
+			slackapi.NewActionBlock("iq_submit", submit))
+	default:
+		for _, f := range im.Fields {
+			var placeholder *slackapi.TextBlockObject
+			if f.Placeholder != "" {
+				placeholder = plain(f.Placeholder)
+			}
+			input := slackapi.NewInputBlock(interactiveFieldBlock+f.Name, plain(f.Label), nil,
+				slackapi.NewPlainTextInputBlockElement(placeholder, interactiveValueAction)).WithOptional(!f.Required)
+			blocks = append(blocks, input)
+		}
+		blocks = append(blocks, slackapi.NewActionBlock("iq_submit", submit))
+	}
+
+	opts := []slackapi.MsgOption{slackapi.MsgOptionText(msg.Content, false), slackapi.MsgOptionBlocks(blocks...)}
+	if ts := msg.Metadata["message_thread_id"]; ts != "" {
+		opts = append(opts, slackapi.MsgOptionTS(ts))
+	}
+	_, _, err := c.api.PostMessage(msg.ChatID, opts...)
+	return err
+}
+
+// handleInteractiveAction answers a question from a button press or select.
+// Checkbox and input values are not prefixed and only read from the message
+// state on submit.
+func (c *Channel) handleInteractiveAction(ctx context.Context, cb slackapi.InteractionCallback, action *slackapi.BlockAction, id, act string, index int) {
+	notice := func(text string) {
+		if _, err := c.api.PostEphemeral(cb.Channel.ID, cb.User.ID, slackapi.MsgOptionText(text, false)); err != nil {
+			slog.Debug("slack: interactive notice failed", "id", id, "error", err)
+		}
+	}
+	im, ok := channels.LookupInteractive(id)
+	if !ok {
+		notice("This question is no longer active.")
+		return
+	}
+	if !channels.InteractiveSenderAllowed(im, cb.User.ID) {
+		notice("Only the person who was asked can answer.")
+		return
+	}
+
+	answer := bus.InteractiveAnswer{ID: id, SenderID: cb.User.ID}
+	switch {
+	case act == channels.InteractiveActionPick:
+		if index < 0 || index >= len(im.Options) {
+			return
+		}
+		answer.Choices = []string{im.Options[index].Value}
+	case action.SelectedOption.Value != "":
+		if n, err := strconv.Atoi(action.SelectedOption.Value); err == nil && n >= 0 && n < len(im.Options) {
+			answer.Choices = []string{im.Options[n].Value}
+		}
+	default:
+		answer.Choices, answer.Fields = interactiveState(cb, im)
+		for _, f := range im.Fields {
+			if f.Required && answer.Fields[f.Name] == "" {
+				notice("Please fill in " + f.Label + ".")
+				return
+			}
+		}
+	}
+
+	status, err := c.AnswerInteractive(ctx, answer)
+	if err != nil {
+		notice(err.Error())
+		return
+	}
+	text := cb.Message.Text + "\n\n" + status
+	section := slackapi.NewSectionBlock(slackapi.NewTextBlockObject(slackapi.PlainTextType, text, true, false), nil, nil)
+	if _, _, _, err := c.api.UpdateMessage(cb.Channel.ID, cb.Message.Timestamp,
+		slackapi.MsgOptionText(text, false), slackapi.MsgOptionBlocks(section)); err != nil {
+		slog.Debug("slack: interactive message update failed", "id", id, "error", err)
+	}
+}
+
+// interactiveState reads checked options and input values from the message state.
+func interactiveState(cb slackapi.InteractionCallback, im *bus.InteractiveMessage) ([]string, map[string]string) {
+	if cb.BlockActionState == nil {
+		return nil, nil
+	}
+	var choices []string
+	for _, o := range cb.BlockActionState.Values[interactiveChoicesBlock][interactiveValueAction].SelectedOptions {
+		if n, err := strconv.Atoi(o.Value); err == nil && n >= 0 && n < len(im.Options) {
+			choices = append(choices, im.Options[n].Value)
+		}
+	}
+	var fields map[string]string
+	for _, f := range im.Fields {
+		if v := cb.BlockActionState.Values[interactiveFieldBlock+f.Name][interactiveValueAction].Value; v != "" {
+			if fields == nil {
+				fields = map[string]string{}
+			}
+			fields[f.Name] = v
+		}
+	}
+	return choices, fields
+}
@@ -0,0 +1,62 @@
+package telegram
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+
+	"github.com/mymmrac/telego"
+	tu "github.com/mymmrac/telego/telegoutil"
+
+	"github.com/nextlevelbuilder/goclaw/internal/channels"
+)
+
+// SendApprovalRequest shows a tool approval request with inline buttons.
+// Implements channels.ApprovalChannel.
+func (c *Channel) SendApprovalRequest(ctx context.Context, chatID string, req channels.ApprovalRequest) error {
+	id, err := parseRawChatID(chatID)
+	if err != nil {
+		return fmt.Errorf("invalid chat ID %q: %w", chatID, err)
+	}
+
+	var buttons []telego.InlineKeyboardButton
+	for _, b := range channels.ApprovalButtonLabels() {
+		buttons = append(buttons, telego.InlineKeyboardButton{Text: b[1], CallbackData: channels.ApprovalCallbackData(req.ID, b[0])})
+	}
+	msg := tu.Message(tu.ID(id), channels.FormatApprovalText(req))
+	if _, topic, ok := strings.Cut(chatID, ":topic:"); ok {
+		if threadID, err := strconv.Atoi(topic); err == nil {
+			msg.MessageThreadID = resolveThreadIDForSend(threadID)
+		}
+	}
+	msg.ReplyMarkup = &telego.InlineKeyboardMarkup{InlineKeyboard: [][]telego.InlineKeyboardButton{buttons}}
+	_, err = c.bot.SendMessage(ctx, msg)
+	return err
+}
+
+// handleApprovalCallback records an approval button press, then replaces the
+// buttons with the outcome.
+func (c *Channel) handleApprovalCallback(ctx context.Context, query *telego.CallbackQuery, id, decision string) {
+	senderID := strconv.FormatInt(query.From.ID, 10)
+	status, err := c.ResolveApproval(ctx, id, decision, senderID)
+	if err != nil {
+		c.bot.AnswerCallbackQuery(ctx, &telego.AnswerCallbackQueryParams{
+			CallbackQueryID: query.ID,
+			Text:            err.Error(),
+			ShowAlert:       true,
+		})
+		return
+	}
+	c.bot.AnswerCallbackQuery(ctx, &telego.AnswerCallbackQueryParams{CallbackQueryID: query.ID, Text: status})
+
+	if query.Message == nil || !query.Message.IsAccessible() {
+		return
+	}
+	orig := query.Message.Message()
+	edit := tu.EditMessageText(tu.ID(orig.Chat.ID), orig.MessageID, orig.Text+"\n\n"+status)
+	if _, err := c.bot.EditMessageText(ctx, edit); err != nil {
+		slog.Debug("telegram: approval message edit failed", "id", id, "error", err)
+	}
+}
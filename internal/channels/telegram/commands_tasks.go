@@ -9,6 +9,7 @@ import (
 	"github.com/mymmrac/telego"
 	tu "github.com/mymmrac/telego/telegoutil"
 
+	"github.com/nextlevelbuilder/goclaw/internal/channels"
 	"github.com/nextlevelbuilder/goclaw/internal/store"
 )
 
@@ -199,6 +200,16 @@ func (c *Channel) handleCallbackQuery(ctx context.Context, query *telego.Callbac
 	// Inject tenant scope (callback queries bypass handleBotCommand).
 	ctx = store.WithTenantID(ctx, c.TenantID())
 
+	// Approval buttons answer the query themselves (with the outcome).
+	if id, decision, ok := channels.ParseApprovalCallback(query.Data); ok {
+		c.handleApprovalCallback(ctx, query, id, decision)
+		return
+	}
+	if id, action, index, ok := channels.ParseInteractiveCallback(query.Data); ok {
+		c.handleInteractiveCallback(ctx, query, id, action, index)
+		return
+	}
+
 	c.bot.AnswerCallbackQuery(ctx, &telego.AnswerCallbackQueryParams{
 		CallbackQueryID: query.ID,
 	})
@@ -394,14 +394,6 @@ func (c *Channel) handleMessage(ctx context.Context, update telego.Update) {
 		for i := range mediaList {
 			m := &mediaList[i]
 			switch m.Type {
-			case "audio", "voice":
-				transcript, sttErr := c.transcribeAudio(ctx, m.FilePath)
-				if sttErr != nil {
-					slog.Warn("telegram: STT transcription failed",
-						"type", m.Type, "error", sttErr)
-				} else {
-					m.Transcript = transcript
-				}
 			case "document":
 				if m.FileName != "" && m.FilePath != "" {
 					docContent, err := extractDocumentContent(m.FilePath, m.FileName)
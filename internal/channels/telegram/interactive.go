@@ -0,0 +1,135 @@
+package telegram
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+
+	"github.com/mymmrac/telego"
+	tu "github.com/mymmrac/telego/telegoutil"
+
+	"github.com/nextlevelbuilder/goclaw/internal/bus"
+	"github.com/nextlevelbuilder/goclaw/internal/channels"
+)
+
+// SendInteractive shows a question with an inline keyboard: one button per
+// option, or toggles plus a Submit button for multi choice. Forms have no
+// native equivalent and are sent as text prompts answered by a reply.
+// Implements channels.InteractiveChannel.
+func (c *Channel) SendInteractive(ctx context.Context, msg bus.OutboundMessage) error {
+	im := msg.Interactive
+	if !im.IsChoice() {
+		msg.Content = channels.FormatInteractiveText(msg.Content, im)
+		msg.Interactive = nil
+		return c.Send(ctx, msg)
+	}
+
+	localKey := msg.ChatID
+	if lk := msg.Metadata["local_key"]; lk != "" {
+		localKey = lk
+	}
+	id, err := parseRawChatID(localKey)
+	if err != nil {
+		return fmt.Errorf("invalid chat ID %q: %w", msg.ChatID, err)
+	}
+	out := tu.Message(tu.ID(id), msg.Content)
+	threadID := 0
+	if v := msg.Metadata["message_thread_id"]; v != "" {
+		threadID, _ = strconv.Atoi(v)
+	} else if _, topic, ok := strings.Cut(localKey, ":topic:"); ok {
+		threadID, _ = strconv.Atoi(topic)
+	}
+	if threadID != 0 {
+		out.MessageThreadID = resolveThreadIDForSend(threadID)
+	}
+	out.ReplyMarkup = interactiveKeyboard(im, make([]bool, len(im.Options)))
+	_, err = c.bot.SendMessage(ctx, out)
+	return err
+}
+
+// interactiveKeyboard lays out one option per row; multi choice shows the
+// current selection and a Submit row.
+func interactiveKeyboard(im *bus.InteractiveMessage, selected []bool) *telego.InlineKeyboardMarkup {
+	var rows [][]telego.InlineKeyboardButton
+	for i, o := range im.Options {
+		if im.Kind != bus.InteractiveMulti {
+			rows = append(rows, tu.InlineKeyboardRow(tu.InlineKeyboardButton(o.Label).
+				WithCallbackData(channels.InteractiveCallbackData(im.ID, channels.InteractiveActionPick, i))))
+			continue
+		}
+		mark := "☐ "
+		if selected[i] {
+			mark = "☑ "
+		}
+		rows = append(rows, tu.InlineKeyboardRow(tu.InlineKeyboardButton(mark+o.Label).
+			WithCallbackData(channels.InteractiveCallbackData(im.ID, channels.InteractiveActionToggle, i))))
+	}
+	if im.Kind == bus.InteractiveMulti {
+		rows = append(rows, tu.InlineKeyboardRow(tu.InlineKeyboardButton("✔ Submit").
+			WithCallbackData(channels.InteractiveCallbackData(im.ID, channels.InteractiveActionSubmit, 0))))
+	}
+	return tu.InlineKeyboard(rows...)
+}
+
+// handleInteractiveCallback handles a button press on a question: toggles
+// re-render the keyboard, picks and submits deliver the answer and replace
+// the keyboard with it.
+func (c *Channel) handleInteractiveCallback(ctx context.Context, query *telego.CallbackQuery, id, action string, index int) {
+	senderID := strconv.FormatInt(query.From.ID, 10)
+	alert := func(text string) {
+		c.bot.AnswerCallbackQuery(ctx, &telego.AnswerCallbackQueryParams{CallbackQueryID: query.ID, Text: text, ShowAlert: true})
+	}
+	im, ok := channels.LookupInteractive(id)
+	if !ok {
+		alert("This question is no longer active.")
+		return
+	}
+	if !channels.InteractiveSenderAllowed(im, senderID) {
+		alert("Only the person who was asked can answer.")
+		return
+	}
+
+	var answer bus.InteractiveAnswer
+	switch action {
+	case channels.InteractiveActionToggle:
+		selected, err := channels.ToggleInteractiveChoice(id, index, senderID)
+		if err != nil {
+			alert("This question is no longer active.")
+			return
+		}
+		c.bot.AnswerCallbackQuery(ctx, &telego.AnswerCallbackQueryParams{CallbackQueryID: query.ID})
+		if query.Message != nil && query.Message.IsAccessible() {
+			orig := query.Message.Message()
+			edit := tu.EditMessageReplyMarkup(tu.ID(orig.Chat.ID), orig.MessageID, interactiveKeyboard(im, selected))
+			if _, err := c.bot.EditMessageReplyMarkup(ctx, edit); err != nil {
+				slog.Debug("telegram: interactive keyboard edit failed", "id", id, "error", err)
+			}
+		}
+		return
+	case channels.InteractiveActionSubmit:
+		answer = bus.InteractiveAnswer{ID: id, SenderID: senderID, Choices: channels.SelectedInteractiveChoices(id)}
+	default:
+		if answer, ok = channels.InteractivePickAnswer(id, index, senderID); !ok {
+			alert("This question is no longer active.")
+			return
+		}
+	}
+
+	status, err := c.AnswerInteractive(ctx, answer)
+	if err != nil {
+		alert(err.Error())
+		return
+	}
+	c.bot.AnswerCallbackQuery(ctx, &telego.AnswerCallbackQueryParams{CallbackQueryID: query.ID, Text: status})
+
+	if query.Message == nil || !query.Message.IsAccessible() {
+		return
+	}
+	orig := query.Message.Message()
+	edit := tu.EditMessageText(tu.ID(orig.Chat.ID), orig.MessageID, orig.Text+"\n\n"+status)
+	if _, err := c.bot.EditMessageText(ctx, edit); err != nil {
+		slog.Debug("telegram: interactive message edit failed", "id", id, "error", err)
+	}
+}
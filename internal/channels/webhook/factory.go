@@ -0,0 +1,87 @@
+package webhook
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/nextlevelbuilder/goclaw/internal/bus"
+	"github.com/nextlevelbuilder/goclaw/internal/channels"
+	"github.com/nextlevelbuilder/goclaw/internal/store"
+	"github.com/nextlevelbuilder/goclaw/internal/tools"
+)
+
+// webhookCreds maps the credentials JSON from the channel_instances table.
+type webhookCreds struct {
+	Secret        string `json:"secret"`                   // HMAC-SHA256 signing secret (inbound + outbound)
+	CallbackToken string `json:"callback_token,omitempty"` // optional bearer token sent to callback_url
+}
+
+// webhookInstanceConfig maps the non-secret config JSONB from the channel_instances table.
+type webhookInstanceConfig struct {
+	CallbackURL     string   `json:"callback_url,omitempty"`
+	SenderPath      string   `json:"sender_path,omitempty"`
+	ChatPath        string   `json:"chat_path,omitempty"`
+	ContentPath     string   `json:"content_path,omitempty"`
+	PeerKindPath    string   `json:"peer_kind_path,omitempty"`
+	SignatureHeader string   `json:"signature_header,omitempty"`
+	TimestampHeader string   `json:"timestamp_header,omitempty"`
+	ReplayWindow    int      `json:"replay_window,omitempty"` // seconds
+	MaxRetries      *int     `json:"max_retries,omitempty"`
+	AllowFrom       []string `json:"allow_from,omitempty"`
+	BlockReply      *bool    `json:"block_reply,omitempty"`
+}
+
+// Factory creates a webhook channel from DB instance data.
+func Factory(name string, creds json.RawMessage, cfg json.RawMessage,
+	msgBus *bus.MessageBus, _ store.PairingStore) (channels.Channel, error) {
+
+	var c webhookCreds
+	if len(creds) > 0 {
+		if err := json.Unmarshal(creds, &c); err != nil {
+			return nil, fmt.Errorf("decode webhook credentials: %w", err)
+		}
+	}
+	if c.Secret == "" {
+		return nil, fmt.Errorf("webhook secret is required")
+	}
+
+	var ic webhookInstanceConfig
+	if len(cfg) > 0 {
+		if err := json.Unmarshal(cfg, &ic); err != nil {
+			return nil, fmt.Errorf("decode webhook config: %w", err)
+		}
+	}
+
+	if ic.CallbackURL != "" {
+		if err := tools.CheckSSRF(ic.CallbackURL); err != nil {
+			return nil, fmt.Errorf("webhook callback_url rejected: %w", err)
+		}
+	}
+
+	maxRetries := defaultMaxRetries
+	if ic.MaxRetries != nil {
+		maxRetries = *ic.MaxRetries
+	}
+
+	ch, err := New(Config{
+		Secret:          c.Secret,
+		CallbackURL:     ic.CallbackURL,
+		CallbackToken:   c.CallbackToken,
+		SignatureHeader: ic.SignatureHeader,
+		TimestampHeader: ic.TimestampHeader,
+		ReplayWindow:    time.Duration(ic.ReplayWindow) * time.Second,
+		SenderPath:      ic.SenderPath,
+		ChatPath:        ic.ChatPath,
+		ContentPath:     ic.ContentPath,
+		PeerKindPath:    ic.PeerKindPath,
+		MaxRetries:      maxRetries,
+		AllowFrom:       ic.AllowFrom,
+		BlockReply:      ic.BlockReply,
+	}, msgBus)
+	if err != nil {
+		return nil, err
+	}
+	ch.SetName(name)
+	return ch, nil
+}
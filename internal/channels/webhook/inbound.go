@@ -0,0 +1,198 @@
+package webhook
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/nextlevelbuilder/goclaw/internal/bus"
+	"github.com/nextlevelbuilder/goclaw/internal/channels"
+)
+
+// InboundPath is the route pattern for inbound webhook deliveries on the main
+// gateway mux. {name} is the channel instance name (may contain "/").
+const InboundPath = "POST /channels/webhook/{name...}"
+
+// InboundHandler dispatches inbound requests to the webhook channel named in
+// the URL. Resolving the channel per request (instead of mounting one route per
+// instance at boot) keeps instances created or reloaded at runtime reachable.
+//
+// In cluster mode every replica serves deliveries, not only the one holding
+// the channel's lease: inbound messages go through the shared bus and replies
+// are forwarded to the lease holder. Accepted signatures are broadcast so a
+// replay sent to another replica is rejected there too.
+func InboundHandler(mgr *channels.Manager) http.Handler {
+	mgr.Bus().Subscribe("webhook.inbound", func(evt bus.Event) {
+		if evt.Name != bus.EventWebhookDeliverySeen || evt.Origin == "" {
+			return
+		}
+		p, ok := evt.Payload.(bus.WebhookDeliverySeenPayload)
+		if !ok {
+			return
+		}
+		if ch, ok := mgr.GetChannel(p.Channel); ok {
+			if wh, ok := ch.(*Channel); ok {
+				wh.markSeen(p.Signature, p.Expires)
+			}
+		}
+	})
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ch, ok := mgr.GetChannel(r.PathValue("name"))
+		wh, isWebhook := ch.(*Channel)
+		if !ok || !isWebhook || !(wh.IsRunning() || mgr.Clustered()) {
+			writeJSON(w, http.StatusNotFound, map[string]string{"error": "unknown webhook channel"})
+			return
+		}
+		wh.ServeHTTP(w, r)
+	})
+}
+
+// ServeHTTP verifies, maps and publishes one inbound delivery.
+func (c *Channel) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxInboundBody+1))
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "failed to read body"})
+		return
+	}
+	if len(body) > maxInboundBody {
+		writeJSON(w, http.StatusRequestEntityTooLarge, map[string]string{"error": "body too large"})
+		return
+	}
+
+	if status, msg := c.verify(r.Header, body, time.Now()); status != 0 {
+		slog.Warn("security.webhook_rejected", "channel", c.Name(), "reason", msg, "remote", r.RemoteAddr)
+		writeJSON(w, status, map[string]string{"error": msg})
+		return
+	}
+	c.announceSeen(r.Header)
+
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+	var doc any
+	if err := dec.Decode(&doc); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid JSON body"})
+		return
+	}
+
+	senderID, _ := lookupPath(doc, c.cfg.SenderPath)
+	content, _ := lookupPath(doc, c.cfg.ContentPath)
+	if senderID == "" || strings.TrimSpace(content) == "" {
+		writeJSON(w, http.StatusUnprocessableEntity, map[string]string{
+			"error": "sender (" + c.cfg.SenderPath + ") and content (" + c.cfg.ContentPath + ") are required",
+		})
+		return
+	}
+	chatID, _ := lookupPath(doc, c.cfg.ChatPath)
+	if chatID == "" {
+		chatID = senderID
+	}
+	peerKind := "direct"
+	if c.cfg.PeerKindPath != "" {
+		if v, _ := lookupPath(doc, c.cfg.PeerKindPath); v == "group" {
+			peerKind = "group"
+		}
+	}
+
+	if !c.IsAllowed(senderID) {
+		writeJSON(w, http.StatusForbidden, map[string]string{"error": "sender not allowed"})
+		return
+	}
+
+	metadata := map[string]string{"platform": channels.TypeWebhook}
+	if id := r.Header.Get("X-Webhook-Delivery"); id != "" {
+		metadata["message_id"] = id
+	}
+	c.HandleMessage(senderID, chatID, content, nil, metadata, peerKind)
+
+	writeJSON(w, http.StatusAccepted, map[string]any{"ok": true})
+}
+
+// verify checks the timestamp window, HMAC signature and replay cache.
+// Returns (0, "") when the request is authentic.
+func (c *Channel) verify(h http.Header, body []byte, now time.Time) (int, string) {
+	ts := h.Get(c.cfg.TimestampHeader)
+	sec, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil {
+		return http.StatusUnauthorized, "missing or invalid timestamp"
+	}
+	sent := time.Unix(sec, 0)
+	if d := now.Sub(sent); d > c.cfg.ReplayWindow || d < -c.cfg.ReplayWindow {
+		return http.StatusUnauthorized, "timestamp outside replay window"
+	}
+
+	sig := strings.TrimPrefix(strings.TrimSpace(h.Get(c.cfg.SignatureHeader)), "sha256=")
+	got, err := hex.DecodeString(sig)
+	if err != nil || len(got) == 0 {
+		return http.StatusUnauthorized, "missing or invalid signature"
+	}
+	want, _ := hex.DecodeString(sign(c.cfg.Secret, ts, body))
+	if !hmac.Equal(got, want) {
+		return http.StatusUnauthorized, "signature mismatch"
+	}
+
+	// Reject exact replays of a valid request inside the window.
+	c.seenMu.Lock()
+	defer c.seenMu.Unlock()
+	for k, exp := range c.seen {
+		if now.After(exp) {
+			delete(c.seen, k)
+		}
+	}
+	key := hex.EncodeToString(got)
+	if _, dup := c.seen[key]; dup {
+		return http.StatusConflict, "duplicate delivery"
+	}
+	c.seen[key] = sent.Add(c.cfg.ReplayWindow)
+	return 0, ""
+}
+
+// announceSeen tells the other replicas about an accepted delivery.
+func (c *Channel) announceSeen(h http.Header) {
+	got, _ := hex.DecodeString(strings.TrimPrefix(strings.TrimSpace(h.Get(c.cfg.SignatureHeader)), "sha256="))
+	key := hex.EncodeToString(got)
+	c.seenMu.Lock()
+	exp, ok := c.seen[key]
+	c.seenMu.Unlock()
+	if !ok || c.Bus() == nil {
+		return
+	}
+	c.Bus().Broadcast(bus.Event{
+		Name:     bus.EventWebhookDeliverySeen,
+		Payload:  bus.WebhookDeliverySeenPayload{Channel: c.Name(), Signature: key, Expires: exp},
+		TenantID: c.TenantID(),
+	})
+}
+
+// markSeen records a delivery accepted by another replica.
+func (c *Channel) markSeen(sig string, expires time.Time) {
+	if sig == "" || time.Now().After(expires) {
+		return
+	}
+	c.seenMu.Lock()
+	c.seen[sig] = expires
+	c.seenMu.Unlock()
+}
+
+// sign returns hex(HMAC-SHA256(secret, "<timestamp>.<body>")).
+func sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,93 @@
+package webhook
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// lookupPath resolves a simple JSONPath expression against a decoded JSON
+// document. Supported syntax is the dotted subset integrations actually use:
+// "$.a.b", "a.b", "items[0].text", "$['key with dots']". Scalars are returned
+// as strings; missing paths return ("", false).
+func lookupPath(doc any, path string) (string, bool) {
+	segs, err := parsePath(path)
+	if err != nil {
+		return "", false
+	}
+	cur := doc
+	for _, seg := range segs {
+		switch node := cur.(type) {
+		case map[string]any:
+			v, ok := node[seg]
+			if !ok {
+				return "", false
+			}
+			cur = v
+		case []any:
+			i, err := strconv.Atoi(seg)
+			if err != nil || i < 0 || i >= len(node) {
+				return "", false
+			}
+			cur = node[i]
+		default:
+			return "", false
+		}
+	}
+	return scalarString(cur)
+}
+
+func scalarString(v any) (string, bool) {
+	switch x := v.(type) {
+	case string:
+		return x, true
+	case json.Number:
+		return x.String(), true
+	case float64:
+		return strconv.FormatFloat(x, 'f', -1, 64), true
+	case bool:
+		return strconv.FormatBool(x), true
+	case nil:
+		return "", false
+	default:
+		// Objects/arrays: hand the raw JSON to the agent rather than dropping it.
+		b, err := json.Marshal(x)
+		if err != nil {
+			return "", false
+		}
+		return string(b), true
+	}
+}
+
+// parsePath splits a path into map keys / array indexes.
+func parsePath(path string) ([]string, error) {
+	p := strings.TrimSpace(path)
+	p = strings.TrimPrefix(p, "$")
+	var segs []string
+	for len(p) > 0 {
+		switch p[0] {
+		case '.':
+			p = p[1:]
+		case '[':
+			end := strings.IndexByte(p, ']')
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated [ in %q", path)
+			}
+			seg := strings.Trim(p[1:end], `'"`)
+			segs = append(segs, seg)
+			p = p[end+1:]
+		default:
+			end := strings.IndexAny(p, ".[")
+			if end < 0 {
+				end = len(p)
+			}
+			segs = append(segs, p[:end])
+			p = p[end:]
+		}
+	}
+	if len(segs) == 0 {
+		return nil, fmt.Errorf("empty path %q", path)
+	}
+	return segs, nil
+}
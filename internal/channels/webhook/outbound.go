@@ -0,0 +1,143 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/nextlevelbuilder/goclaw/internal/bus"
+	"github.com/nextlevelbuilder/goclaw/internal/tools"
+	"github.com/nextlevelbuilder/goclaw/pkg/protocol"
+)
+
+// outboundPayload is the JSON body POSTed to the callback URL.
+type outboundPayload struct {
+	Channel   string                `json:"channel"`
+	ChatID    string                `json:"chat_id"`
+	Content   string                `json:"content"`
+	Media     []bus.MediaAttachment `json:"media,omitempty"`
+	Metadata  map[string]string     `json:"metadata,omitempty"`
+	Timestamp int64                 `json:"timestamp"`
+}
+
+// permanentError marks a failure that retrying will not fix (bad URL, blocked address, 4xx).
+type permanentError struct{ err error }
+
+func (e *permanentError) Error() string { return e.err.Error() }
+
+// deliver POSTs msg to the callback URL, retrying transient failures
+// (network errors, 429, 5xx) with exponential backoff. A message that still
+// fails is recorded as a dead letter.
+func (c *Channel) deliver(ctx context.Context, msg bus.OutboundMessage) {
+	body, err := json.Marshal(outboundPayload{
+		Channel:   c.Name(),
+		ChatID:    msg.ChatID,
+		Content:   msg.Content,
+		Media:     msg.Media,
+		Metadata:  msg.Metadata,
+		Timestamp: time.Now().Unix(),
+	})
+	if err != nil {
+		c.deadLetter(msg, 0, err)
+		return
+	}
+	deliveryID := uuid.NewString()
+
+	attempts := 0
+	for {
+		attempts++
+		err = c.post(ctx, deliveryID, body)
+		if err == nil {
+			return
+		}
+		if _, permanent := err.(*permanentError); permanent || attempts > c.cfg.MaxRetries {
+			break
+		}
+		slog.Warn("webhook: delivery failed, retrying",
+			"channel", c.Name(), "attempt", attempts, "error", err)
+		select {
+		case <-ctx.Done():
+			c.deadLetter(msg, attempts, fmt.Errorf("channel stopped during retry: %w", err))
+			return
+		case <-time.After(c.backoff(attempts - 1)):
+		}
+	}
+	c.deadLetter(msg, attempts, err)
+}
+
+func (c *Channel) post(ctx context.Context, deliveryID string, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.cfg.CallbackURL, bytes.NewReader(body))
+	if err != nil {
+		return &permanentError{err: err}
+	}
+	ts := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(c.cfg.TimestampHeader, ts)
+	req.Header.Set(c.cfg.SignatureHeader, "sha256="+sign(c.cfg.Secret, ts, body))
+	req.Header.Set("X-Webhook-Delivery", deliveryID) // stable across retries for receiver-side dedup
+	if c.cfg.CallbackToken != "" {
+		req.Header.Set("Authorization", "Bearer "+c.cfg.CallbackToken)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		if errors.Is(err, tools.ErrSSRFBlocked) {
+			return &permanentError{err: err}
+		}
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		return nil
+	case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500:
+		return fmt.Errorf("callback returned HTTP %d", resp.StatusCode)
+	default:
+		return &permanentError{err: fmt.Errorf("callback returned HTTP %d", resp.StatusCode)}
+	}
+}
+
+// recordDeadLetter persists an undeliverable reply to the activity log
+// (action "webhook.dead_letter") so operators can inspect and replay it.
+func (c *Channel) recordDeadLetter(msg bus.OutboundMessage, attempts int, err error) {
+	slog.Error("webhook: delivery failed permanently",
+		"channel", c.Name(), "chat_id", msg.ChatID, "attempts", attempts, "error", err)
+
+	mb := c.Bus()
+	if mb == nil {
+		return
+	}
+	details, _ := json.Marshal(map[string]any{
+		"callback_url": c.cfg.CallbackURL,
+		"chat_id":      msg.ChatID,
+		"content":      msg.Content,
+		"media":        msg.Media,
+		"metadata":     msg.Metadata,
+		"attempts":     attempts,
+		"error":        err.Error(),
+	})
+	mb.Broadcast(bus.Event{
+		Name: protocol.EventAuditLog,
+		Payload: bus.AuditEventPayload{
+			ActorType:  "system",
+			ActorID:    "webhook",
+			Action:     "webhook.dead_letter",
+			EntityType: "channel_instance",
+			EntityID:   c.Name(),
+			Details:    details,
+			TenantID:   c.TenantID(),
+		},
+		TenantID: c.TenantID(),
+	})
+}
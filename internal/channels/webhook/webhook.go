@@ -0,0 +1,192 @@
+// Package webhook implements a generic JSON webhook channel for integrating
+// internal tools (ticketing, CI, CRM) that can only POST JSON.
+//
+// Inbound: each instance gets an authenticated URL (/channels/webhook/{name}).
+// Requests are HMAC-SHA256 signed over "<timestamp>.<body>" and rejected outside
+// the replay window; JSONPath expressions map the payload to sender/chat/content.
+//
+// Outbound: agent replies are POSTed to the instance's callback URL, signed the
+// same way, with exponential-backoff retries. Deliveries that exhaust their
+// retries are recorded in the activity log as dead letters.
+package webhook
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/nextlevelbuilder/goclaw/internal/bus"
+	"github.com/nextlevelbuilder/goclaw/internal/channels"
+	"github.com/nextlevelbuilder/goclaw/internal/tools"
+)
+
+const (
+	defaultSignatureHeader = "X-Webhook-Signature"
+	defaultTimestampHeader = "X-Webhook-Timestamp"
+	defaultReplayWindow    = 5 * time.Minute
+	defaultMaxRetries      = 3
+	maxRetriesLimit        = 10          // upper bound for the user-set max_retries
+	baseRetryBackoff       = time.Second // doubled per retry
+	maxRetryBackoff        = time.Minute // retries block the instance's delivery worker
+	defaultSenderPath      = "$.sender"
+	defaultChatPath        = "$.chat_id"
+	defaultContentPath     = "$.text"
+	maxInboundBody         = 1 << 20 // 1 MB
+	deliveryQueueSize      = 256
+)
+
+// Config is the resolved configuration of one webhook instance.
+type Config struct {
+	Secret          string        // HMAC key for inbound verification and outbound signing
+	CallbackURL     string        // outbound delivery target (empty = inbound only)
+	CallbackToken   string        // optional bearer token for the callback
+	SignatureHeader string        // default X-Webhook-Signature
+	TimestampHeader string        // default X-Webhook-Timestamp
+	ReplayWindow    time.Duration // default 5m
+	SenderPath      string        // JSONPath → InboundMessage.SenderID
+	ChatPath        string        // JSONPath → InboundMessage.ChatID (falls back to sender)
+	ContentPath     string        // JSONPath → InboundMessage.Content
+	PeerKindPath    string        // optional JSONPath → "direct" / "group"
+	MaxRetries      int           // outbound retries after the first attempt
+	AllowFrom       []string
+	BlockReply      *bool
+}
+
+// Channel is a generic inbound/outbound JSON webhook.
+type Channel struct {
+	*channels.BaseChannel
+	cfg        Config
+	client     *http.Client
+	queue      chan bus.OutboundMessage
+	backoff    func(attempt int) time.Duration
+	seen       map[string]time.Time // signature → expiry, for replay rejection
+	seenMu     sync.Mutex
+	cancel     context.CancelFunc
+	wg         sync.WaitGroup
+	stopOnce   sync.Once
+	deadLetter func(msg bus.OutboundMessage, attempts int, err error)
+}
+
+// New creates a webhook channel.
+func New(cfg Config, msgBus *bus.MessageBus) (*Channel, error) {
+	if cfg.Secret == "" {
+		return nil, fmt.Errorf("webhook secret is required")
+	}
+	if cfg.SignatureHeader == "" {
+		cfg.SignatureHeader = defaultSignatureHeader
+	}
+	if cfg.TimestampHeader == "" {
+		cfg.TimestampHeader = defaultTimestampHeader
+	}
+	if cfg.ReplayWindow <= 0 {
+		cfg.ReplayWindow = defaultReplayWindow
+	}
+	cfg.MaxRetries = min(max(cfg.MaxRetries, 0), maxRetriesLimit)
+	if cfg.SenderPath == "" {
+		cfg.SenderPath = defaultSenderPath
+	}
+	if cfg.ChatPath == "" {
+		cfg.ChatPath = defaultChatPath
+	}
+	if cfg.ContentPath == "" {
+		cfg.ContentPath = defaultContentPath
+	}
+
+	ch := &Channel{
+		BaseChannel: channels.NewBaseChannel(channels.TypeWebhook, msgBus, cfg.AllowFrom),
+		cfg:         cfg,
+		client:      newCallbackClient(),
+		queue:       make(chan bus.OutboundMessage, deliveryQueueSize),
+		backoff:     retryBackoff,
+		seen:        make(map[string]time.Time),
+	}
+	ch.deadLetter = ch.recordDeadLetter
+	return ch, nil
+}
+
+// newCallbackClient returns the HTTP client used for callback delivery. The
+// callback URL is tenant-controlled, so every dialed address goes through the
+// SSRF guard and environment proxies are bypassed.
+func newCallbackClient() *http.Client {
+	dialer := &net.Dialer{Timeout: 10 * time.Second, Control: tools.SSRFDialControl}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+	return &http.Client{Timeout: 30 * time.Second, Transport: transport}
+}
+
+// retryBackoff returns the delay before retry number attempt (0-based):
+// 1s doubling per retry, capped at maxRetryBackoff.
+func retryBackoff(attempt int) time.Duration {
+	d := baseRetryBackoff
+	for i := 0; i < attempt && d < maxRetryBackoff; i++ {
+		d *= 2
+	}
+	return min(d, maxRetryBackoff)
+}
+
+// BlockReplyEnabled returns the per-channel block_reply override (nil = inherit gateway default).
+func (c *Channel) BlockReplyEnabled() *bool { return c.cfg.BlockReply }
+
+// Start launches the outbound delivery worker. Inbound requests are served by
+// InboundHandler on the main gateway mux.
+func (c *Channel) Start(ctx context.Context) error {
+	// Detach from the caller's context: Start is called with request-scoped
+	// contexts on reload, but delivery must outlive them until Stop.
+	workerCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	c.cancel = cancel
+	c.wg.Add(1)
+	go c.deliveryLoop(workerCtx)
+	c.SetRunning(true)
+	slog.Info("webhook channel started", "name", c.Name(), "callback", c.cfg.CallbackURL != "")
+	return nil
+}
+
+// Stop stops the delivery worker. Queued messages that have not been attempted are dropped.
+func (c *Channel) Stop(_ context.Context) error {
+	c.stopOnce.Do(func() {
+		if c.cancel != nil {
+			c.cancel()
+		}
+		c.wg.Wait()
+	})
+	c.SetRunning(false)
+	return nil
+}
+
+// Send queues an agent reply for delivery to the callback URL. Delivery (and
+// retries) happen on the channel's worker so a slow callback never blocks the
+// shared outbound dispatcher.
+func (c *Channel) Send(_ context.Context, msg bus.OutboundMessage) error {
+	if !c.IsRunning() {
+		return fmt.Errorf("webhook channel %s not running", c.Name())
+	}
+	if c.cfg.CallbackURL == "" {
+		slog.Debug("webhook: no callback_url, dropping outbound message", "channel", c.Name(), "chat_id", msg.ChatID)
+		return nil
+	}
+	select {
+	case c.queue <- msg:
+		return nil
+	default:
+		err := fmt.Errorf("webhook %s delivery queue full", c.Name())
+		c.deadLetter(msg, 0, err)
+		return err
+	}
+}
+
+func (c *Channel) deliveryLoop(ctx context.Context) {
+	defer c.wg.Done()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case msg := <-c.queue:
+			c.deliver(ctx, msg)
+		}
+	}
+}
@@ -0,0 +1,295 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/nextlevelbuilder/goclaw/internal/bus"
+	"github.com/nextlevelbuilder/goclaw/internal/channels"
+)
+
+const testSecret = "s3cret"
+
+func newTestChannel(t *testing.T, cfg Config) (*Channel, *bus.MessageBus) {
+	t.Helper()
+	mb := bus.New()
+	cfg.Secret = testSecret
+	ch, err := New(cfg, mb)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	ch.SetName("webhook/ci")
+	// httptest servers listen on loopback, which the callback client's SSRF guard refuses.
+	ch.client = &http.Client{Timeout: 5 * time.Second}
+	return ch, mb
+}
+
+func signedRequest(body string, ts time.Time) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, "/channels/webhook/webhook/ci", strings.NewReader(body))
+	stamp := strconv.FormatInt(ts.Unix(), 10)
+	req.Header.Set(defaultTimestampHeader, stamp)
+	req.Header.Set(defaultSignatureHeader, "sha256="+sign(testSecret, stamp, []byte(body)))
+	return req
+}
+
+func TestInbound_MapsPayloadToInboundMessage(t *testing.T) {
+	ch, mb := newTestChannel(t, Config{
+		SenderPath:  "$.user.id",
+		ChatPath:    "$.ticket.key",
+		ContentPath: "$.events[0].comment",
+	})
+	body := `{"user":{"id":42},"ticket":{"key":"OPS-7"},"events":[{"comment":"build is red"}]}`
+
+	rec := httptest.NewRecorder()
+	ch.ServeHTTP(rec, signedRequest(body, time.Now()))
+	if rec.Code != http.StatusAccepted {
+		t.Fatalf("status = %d, body = %s", rec.Code, rec.Body)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	msg, ok := mb.ConsumeInbound(ctx)
+	if !ok {
+		t.Fatal("no inbound message published")
+	}
+	if msg.Channel != "webhook/ci" || msg.SenderID != "42" || msg.ChatID != "OPS-7" || msg.Content != "build is red" {
+		t.Errorf("inbound = %+v", msg)
+	}
+	if msg.PeerKind != "direct" {
+		t.Errorf("PeerKind = %q, want direct", msg.PeerKind)
+	}
+}
+
+func TestInbound_RejectsBadSignatureStaleAndReplay(t *testing.T) {
+	ch, _ := newTestChannel(t, Config{ReplayWindow: time.Minute})
+	body := `{"sender":"u1","text":"hi"}`
+
+	tampered := signedRequest(body, time.Now())
+	tampered.Header.Set(defaultSignatureHeader, "sha256="+sign("wrong", tampered.Header.Get(defaultTimestampHeader), []byte(body)))
+	stale := signedRequest(body, time.Now().Add(-2*time.Minute))
+
+	for name, req := range map[string]*http.Request{"tampered": tampered, "stale": stale} {
+		rec := httptest.NewRecorder()
+		ch.ServeHTTP(rec, req)
+		if rec.Code != http.StatusUnauthorized {
+			t.Errorf("%s: status = %d, want 401", name, rec.Code)
+		}
+	}
+
+	now := time.Now()
+	rec := httptest.NewRecorder()
+	ch.ServeHTTP(rec, signedRequest(body, now))
+	if rec.Code != http.StatusAccepted {
+		t.Fatalf("first delivery status = %d", rec.Code)
+	}
+	rec = httptest.NewRecorder()
+	ch.ServeHTTP(rec, signedRequest(body, now))
+	if rec.Code != http.StatusConflict {
+		t.Errorf("replayed delivery status = %d, want 409", rec.Code)
+	}
+}
+
+// remoteOwner is a cluster where another replica holds every channel lease.
+type remoteOwner struct{}
+
+func (remoteOwner) Claim(string, func(), func())      {}
+func (remoteOwner) Release(string)                    {}
+func (remoteOwner) Owns(string) bool                  { return false }
+func (remoteOwner) Forward(bus.OutboundMessage) error { return nil }
+
+func TestInboundHandler_ServesOnNonOwningReplica(t *testing.T) {
+	ch, mb := newTestChannel(t, Config{})
+	mgr := channels.NewManager(mb)
+	mgr.SetOwnership(remoteOwner{})
+	mgr.RegisterChannel("webhook/ci", ch)
+	h := http.NewServeMux()
+	h.Handle(InboundPath, InboundHandler(mgr))
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, signedRequest(`{"sender":"u1","text":"hi"}`, time.Now()))
+	if rec.Code != http.StatusAccepted {
+		t.Fatalf("status = %d, want 202 on a replica without the lease", rec.Code)
+	}
+
+	// A delivery accepted by another replica is a replay here.
+	now := time.Now()
+	req := signedRequest(`{"sender":"u1","text":"again"}`, now)
+	mb.Broadcast(bus.Event{
+		Name:    bus.EventWebhookDeliverySeen,
+		Origin:  "other-node",
+		Payload: bus.WebhookDeliverySeenPayload{Channel: "webhook/ci", Signature: strings.TrimPrefix(req.Header.Get(defaultSignatureHeader), "sha256="), Expires: now.Add(time.Minute)},
+	})
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if rec.Code != http.StatusConflict {
+		t.Errorf("replay of remote delivery status = %d, want 409", rec.Code)
+	}
+}
+
+func TestInbound_MissingFields(t *testing.T) {
+	ch, _ := newTestChannel(t, Config{})
+	rec := httptest.NewRecorder()
+	ch.ServeHTTP(rec, signedRequest(`{"sender":"u1"}`, time.Now()))
+	if rec.Code != http.StatusUnprocessableEntity {
+		t.Errorf("status = %d, want 422", rec.Code)
+	}
+}
+
+func TestOutbound_RetriesThenDelivers(t *testing.T) {
+	var calls atomic.Int32
+	var got outboundPayload
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) < 3 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer tok" || r.Header.Get(defaultSignatureHeader) == "" {
+			t.Errorf("missing auth/signature headers: %v", r.Header)
+		}
+		json.NewDecoder(r.Body).Decode(&got)
+	}))
+	defer srv.Close()
+
+	ch, _ := newTestChannel(t, Config{CallbackURL: srv.URL, CallbackToken: "tok", MaxRetries: 3})
+	ch.backoff = func(int) time.Duration { return time.Millisecond }
+	ch.deadLetter = func(bus.OutboundMessage, int, error) { t.Error("unexpected dead letter") }
+
+	ch.deliver(context.Background(), bus.OutboundMessage{ChatID: "OPS-7", Content: "fixed"})
+	if calls.Load() != 3 {
+		t.Errorf("calls = %d, want 3", calls.Load())
+	}
+	if got.ChatID != "OPS-7" || got.Content != "fixed" || got.Channel != "webhook/ci" {
+		t.Errorf("payload = %+v", got)
+	}
+}
+
+func TestOutbound_DeadLetterAfterRetries(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	ch, mb := newTestChannel(t, Config{CallbackURL: srv.URL, MaxRetries: 2})
+	ch.backoff = func(int) time.Duration { return time.Millisecond }
+
+	var mu sync.Mutex
+	var audits []bus.AuditEventPayload
+	mb.Subscribe("test", func(evt bus.Event) {
+		if p, ok := evt.Payload.(bus.AuditEventPayload); ok {
+			mu.Lock()
+			audits = append(audits, p)
+			mu.Unlock()
+		}
+	})
+
+	ch.deliver(context.Background(), bus.OutboundMessage{ChatID: "c1", Content: "lost"})
+	if calls.Load() != 3 {
+		t.Errorf("calls = %d, want 3 (1 + 2 retries)", calls.Load())
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for {
+		mu.Lock()
+		n := len(audits)
+		mu.Unlock()
+		if n > 0 || time.Now().After(deadline) {
+			break
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if len(audits) != 1 || audits[0].Action != "webhook.dead_letter" || audits[0].EntityID != "webhook/ci" {
+		t.Fatalf("audits = %+v, want one webhook.dead_letter", audits)
+	}
+}
+
+func TestOutbound_ClientErrorIsNotRetried(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer srv.Close()
+
+	ch, _ := newTestChannel(t, Config{CallbackURL: srv.URL, MaxRetries: 3})
+	ch.backoff = func(int) time.Duration { return time.Millisecond }
+	var dead int
+	ch.deadLetter = func(_ bus.OutboundMessage, attempts int, _ error) { dead = attempts }
+
+	ch.deliver(context.Background(), bus.OutboundMessage{ChatID: "c1", Content: "x"})
+	if calls.Load() != 1 || dead != 1 {
+		t.Errorf("calls = %d, dead-letter attempts = %d; want 1, 1", calls.Load(), dead)
+	}
+}
+
+func TestOutbound_PrivateCallbackIsBlocked(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+	}))
+	defer srv.Close()
+
+	ch, _ := newTestChannel(t, Config{CallbackURL: srv.URL, MaxRetries: 3})
+	ch.client = newCallbackClient()
+	ch.backoff = func(int) time.Duration { return time.Millisecond }
+	var dead int
+	ch.deadLetter = func(_ bus.OutboundMessage, attempts int, _ error) { dead = attempts }
+
+	ch.deliver(context.Background(), bus.OutboundMessage{ChatID: "c1", Content: "x"})
+	if calls.Load() != 0 || dead != 1 {
+		t.Errorf("calls = %d, dead-letter attempts = %d; want 0, 1", calls.Load(), dead)
+	}
+}
+
+func TestFactory_RejectsPrivateCallbackURL(t *testing.T) {
+	creds := json.RawMessage(`{"secret":"s"}`)
+	cfg := json.RawMessage(`{"callback_url":"http://169.254.169.254/latest"}`)
+	if _, err := Factory("webhook/ci", creds, cfg, bus.New(), nil); err == nil {
+		t.Fatal("expected private callback_url to be rejected")
+	}
+}
+
+func TestRetryLimits(t *testing.T) {
+	ch, _ := newTestChannel(t, Config{MaxRetries: 1000})
+	if ch.cfg.MaxRetries != maxRetriesLimit {
+		t.Errorf("MaxRetries = %d, want %d", ch.cfg.MaxRetries, maxRetriesLimit)
+	}
+	if got := retryBackoff(0); got != time.Second {
+		t.Errorf("retryBackoff(0) = %v, want 1s", got)
+	}
+	if got := retryBackoff(62); got != maxRetryBackoff {
+		t.Errorf("retryBackoff(62) = %v, want %v", got, maxRetryBackoff)
+	}
+}
+
+func TestLookupPath(t *testing.T) {
+	var doc any
+	json.Unmarshal([]byte(`{"a":{"b":[{"c":"x"},{"c":7}]},"dotted.key":true}`), &doc)
+	tests := []struct {
+		path, want string
+		ok         bool
+	}{
+		{"$.a.b[0].c", "x", true},
+		{"a.b[1].c", "7", true},
+		{"$['dotted.key']", "true", true},
+		{"$.a.b[5].c", "", false},
+		{"$.missing", "", false},
+	}
+	for _, tt := range tests {
+		got, ok := lookupPath(doc, tt.path)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("lookupPath(%q) = %q, %v; want %q, %v", tt.path, got, ok, tt.want, tt.ok)
+		}
+	}
+}
@@ -0,0 +1,133 @@
+package cluster
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/nextlevelbuilder/goclaw/internal/bus"
+)
+
+const kindChannelOutbound = "channel.outbound"
+
+// maxForwardedMedia caps the attachment bytes inlined into one forwarded
+// message.
+const maxForwardedMedia = 25 << 20
+
+// forwardedOutbound is an outbound message on the wire. Local media files
+// exist only on the sending replica, so their contents travel with it.
+type forwardedOutbound struct {
+	Msg   bus.OutboundMessage `json:"msg"`
+	Files map[int][]byte      `json:"files,omitempty"` // media index → file contents
+}
+
+// ChannelOwnership leases each channel instance to one replica
+// (channels.Ownership). Outbound messages produced on a replica that does not
+// run the target channel — cron jobs on the leader, HTTP-triggered runs — are
+// forwarded to the replica that does.
+type ChannelOwnership struct {
+	node *Node
+}
+
+// NewChannelOwnership wires channel leasing and outbound forwarding onto n.
+func NewChannelOwnership(n *Node, mb *bus.MessageBus) *ChannelOwnership {
+	n.Handle(kindChannelOutbound, func(from string, raw json.RawMessage) {
+		var fwd forwardedOutbound
+		if err := json.Unmarshal(raw, &fwd); err != nil {
+			slog.Debug("cluster: malformed outbound message", "error", err)
+			return
+		}
+		msg := fwd.Msg
+		if !n.Owns(ChannelKey(msg.Channel)) {
+			return
+		}
+		if err := restoreMedia(&msg, fwd.Files); err != nil {
+			slog.Warn("cluster: forwarded media not restored", "channel", msg.Channel, "from", from, "error", err)
+		}
+		if !mb.TryPublishOutbound(msg) {
+			slog.Warn("cluster: outbound queue full, forwarded message dropped", "channel", msg.Channel, "from", from)
+		}
+	})
+	return &ChannelOwnership{node: n}
+}
+
+// Claim runs the channel on this replica while it holds the channel's lease.
+func (o *ChannelOwnership) Claim(name string, start, stop func()) {
+	o.node.Claim(ChannelKey(name), start, stop)
+}
+
+// Release stops the channel here (if running) and frees its lease.
+func (o *ChannelOwnership) Release(name string) {
+	o.node.Release(ChannelKey(name))
+}
+
+// Owns reports whether the channel runs on this replica.
+func (o *ChannelOwnership) Owns(name string) bool {
+	return o.node.Owns(ChannelKey(name))
+}
+
+// Forward hands an outbound message to the replica running its channel. It
+// publishes synchronously, so an error means the message did not leave this
+// replica and the caller can retry or fail it.
+func (o *ChannelOwnership) Forward(msg bus.OutboundMessage) error {
+	fwd := forwardedOutbound{Msg: msg}
+	total := 0
+	for i, m := range msg.Media {
+		if !isLocalMedia(m.URL) {
+			continue
+		}
+		data, err := os.ReadFile(m.URL)
+		if err != nil {
+			return fmt.Errorf("read forwarded media: %w", err)
+		}
+		if total += len(data); total > maxForwardedMedia {
+			return fmt.Errorf("forwarded media exceeds %d bytes", maxForwardedMedia)
+		}
+		if fwd.Files == nil {
+			fwd.Files = make(map[int][]byte)
+		}
+		fwd.Files[i] = data
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	return o.node.Publish(ctx, kindChannelOutbound, fwd)
+}
+
+// isLocalMedia reports whether a media URL is a file path on this replica.
+func isLocalMedia(u string) bool {
+	return u != "" && !strings.Contains(u, "://")
+}
+
+// restoreMedia writes forwarded file contents to temp files and points the
+// message's media at them. The dispatcher deletes temp media after sending.
+func restoreMedia(msg *bus.OutboundMessage, files map[int][]byte) error {
+	if len(files) == 0 {
+		return nil
+	}
+	media := append([]bus.MediaAttachment(nil), msg.Media...)
+	for i, data := range files {
+		if i < 0 || i >= len(media) {
+			continue
+		}
+		f, err := os.CreateTemp("", "goclaw-fwd-*"+filepath.Ext(media[i].URL))
+		if err != nil {
+			return err
+		}
+		_, err = f.Write(data)
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+		if err != nil {
+			os.Remove(f.Name())
+			return err
+		}
+		media[i].URL = f.Name()
+	}
+	msg.Media = media
+	return nil
+}
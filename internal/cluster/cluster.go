@@ -0,0 +1,290 @@
+// Package cluster coordinates several gateway replicas that share one
+// database: leader election for singleton services (cron, heartbeats, task
+// recovery), per-channel-instance ownership leases with failover, cross-node
+// fan-out of bus events, and cross-node serialization of agent sessions.
+package cluster
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+)
+
+// Backend is the coordination primitive a cluster runs on. Locks belong to
+// the node for as long as its backend session stays healthy, so a crashed
+// replica's locks lapse on their own (PostgreSQL: the connection closes;
+// Redis: the key TTL expires).
+type Backend interface {
+	// TryLock acquires key without blocking. Re-locking a held key reports true.
+	TryLock(ctx context.Context, key string) (bool, error)
+	// Unlock releases key. Unlocking a key that is not held is a no-op.
+	Unlock(ctx context.Context, key string) error
+	// Keepalive verifies (and for TTL backends extends) every held lock and
+	// returns those lost since the previous call.
+	Keepalive(ctx context.Context) (lost []string, err error)
+	// Publish sends msg to every node, including this one.
+	Publish(ctx context.Context, msg []byte) error
+	// Listen delivers published messages to fn until ctx is done.
+	Listen(ctx context.Context, fn func(msg []byte)) error
+	Close() error
+}
+
+// Lock key namespaces.
+const (
+	singletonPrefix = "singleton:"
+	channelPrefix   = "channel:"
+	sessionPrefix   = "session:"
+	nodePrefix      = "node:"
+)
+
+// SingletonKey is the lease key of a service that must run on one replica.
+func SingletonKey(name string) string { return singletonPrefix + name }
+
+// ChannelKey is the lease key of a channel instance.
+func ChannelKey(name string) string { return channelPrefix + name }
+
+// NodeKey is the lease every node holds on its own ID while it runs.
+func NodeKey(id string) string { return nodePrefix + id }
+
+// DefaultNodeID derives a replica ID from the hostname and process ID.
+func DefaultNodeID() string {
+	host, err := os.Hostname()
+	if err != nil || host == "" {
+		host = "node"
+	}
+	return fmt.Sprintf("%s-%d", host, os.Getpid())
+}
+
+// claim is a service that runs only while this node holds its lease.
+type claim struct {
+	start, stop func()
+	owned       bool
+}
+
+// Node is this replica's view of the cluster.
+type Node struct {
+	id       string
+	backend  Backend
+	interval time.Duration
+
+	mu       sync.Mutex
+	claims   map[string]*claim
+	handlers map[string]func(from string, payload json.RawMessage)
+
+	sessMu   sync.Mutex
+	sessions map[string]*sessionLock
+
+	reqMu   sync.Mutex
+	waiters map[string]chan json.RawMessage
+
+	outbox   chan []byte
+	cancel   context.CancelFunc
+	wg       sync.WaitGroup
+	stopOnce sync.Once
+}
+
+// New creates a node. leaseTTL bounds how long a dead replica's services stay
+// orphaned: leases are checked and renewed every third of it.
+func New(id string, backend Backend, leaseTTL time.Duration) *Node {
+	if leaseTTL <= 0 {
+		leaseTTL = 15 * time.Second
+	}
+	n := &Node{
+		id:       id,
+		backend:  backend,
+		interval: leaseTTL / 3,
+		claims:   make(map[string]*claim),
+		sessions: make(map[string]*sessionLock),
+		handlers: make(map[string]func(string, json.RawMessage)),
+		waiters:  make(map[string]chan json.RawMessage),
+		outbox:   make(chan []byte, 1024),
+	}
+	n.handlers[kindReply] = n.deliverReply
+	return n
+}
+
+// ID returns the node ID.
+func (n *Node) ID() string { return n.id }
+
+// Start launches the lease keeper, the message listener and the sender.
+func (n *Node) Start(ctx context.Context) {
+	n.Claim(NodeKey(n.id), func() {}, func() {})
+	ctx, n.cancel = context.WithCancel(ctx)
+	n.wg.Add(3)
+	go n.keepLeases(ctx)
+	go n.listen(ctx)
+	go n.send(ctx)
+	slog.Info("cluster: node started", "node", n.id, "lease_check", n.interval)
+}
+
+// Stop stops every service this node owns, releases its leases and closes
+// the backend so other replicas can take over immediately.
+func (n *Node) Stop() {
+	n.stopOnce.Do(func() {
+		if n.cancel != nil {
+			n.cancel()
+		}
+		n.wg.Wait()
+
+		n.mu.Lock()
+		var stops []func()
+		for key, c := range n.claims {
+			if c.owned {
+				stops = append(stops, c.stop)
+			}
+			delete(n.claims, key)
+		}
+		n.mu.Unlock()
+		for _, stop := range stops {
+			stop()
+		}
+
+		// Closing the backend drops every lock it still holds.
+		if err := n.backend.Close(); err != nil {
+			slog.Warn("cluster: close backend", "error", err)
+		}
+		slog.Info("cluster: node stopped", "node", n.id)
+	})
+}
+
+// Claim runs start once this node holds the lease on key, and stop if the
+// lease is later lost. Acquisition is attempted right away and then on every
+// lease check, so a replica takes over within one interval of a failure.
+func (n *Node) Claim(key string, start, stop func()) {
+	n.mu.Lock()
+	if _, ok := n.claims[key]; ok {
+		n.mu.Unlock()
+		return
+	}
+	c := &claim{start: start, stop: stop}
+	n.claims[key] = c
+	n.mu.Unlock()
+
+	n.tryAcquire(context.Background(), key, c)
+}
+
+// Release gives up the lease on key, stopping the service if it runs here.
+func (n *Node) Release(key string) {
+	n.mu.Lock()
+	c, ok := n.claims[key]
+	delete(n.claims, key)
+	n.mu.Unlock()
+	if !ok {
+		return
+	}
+	if c.owned {
+		c.stop()
+		if err := n.backend.Unlock(context.Background(), key); err != nil {
+			slog.Warn("cluster: unlock", "key", key, "error", err)
+		}
+		slog.Info("cluster: lease released", "key", key, "node", n.id)
+	}
+}
+
+// Alive reports whether the node with the given ID is running, by probing
+// the lease it holds on its own ID. Errors count as alive, so callers never
+// take over a running node's work by mistake.
+func (n *Node) Alive(ctx context.Context, id string) bool {
+	if id == n.id {
+		return true
+	}
+	key := NodeKey(id)
+	ok, err := n.backend.TryLock(ctx, key)
+	if err != nil || !ok {
+		return true
+	}
+	if err := n.backend.Unlock(ctx, key); err != nil {
+		slog.Warn("cluster: unlock", "key", key, "error", err)
+	}
+	return false
+}
+
+// Owns reports whether this node currently holds the lease on key.
+func (n *Node) Owns(key string) bool {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	c, ok := n.claims[key]
+	return ok && c.owned
+}
+
+func (n *Node) tryAcquire(ctx context.Context, key string, c *claim) {
+	ok, err := n.backend.TryLock(ctx, key)
+	if err != nil {
+		slog.Warn("cluster: try lock", "key", key, "error", err)
+		return
+	}
+	if !ok {
+		return
+	}
+	n.mu.Lock()
+	// Released (or re-claimed) while the lock call was in flight.
+	if n.claims[key] != c || c.owned {
+		n.mu.Unlock()
+		if n.claims[key] != c {
+			n.backend.Unlock(ctx, key)
+		}
+		return
+	}
+	c.owned = true
+	n.mu.Unlock()
+
+	slog.Info("cluster: lease acquired", "key", key, "node", n.id)
+	c.start()
+}
+
+// keepLeases renews held locks, stops services whose lease was lost and
+// tries to pick up unowned ones.
+func (n *Node) keepLeases(ctx context.Context) {
+	defer n.wg.Done()
+	ticker := time.NewTicker(n.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			n.checkLeases(ctx)
+		}
+	}
+}
+
+func (n *Node) checkLeases(ctx context.Context) {
+	lost, err := n.backend.Keepalive(ctx)
+	if err != nil {
+		slog.Warn("cluster: keepalive", "error", err)
+	}
+	for _, key := range lost {
+		n.mu.Lock()
+		c, ok := n.claims[key]
+		wasOwned := ok && c.owned
+		if wasOwned {
+			c.owned = false
+		}
+		n.mu.Unlock()
+		if wasOwned {
+			slog.Warn("cluster: lease lost", "key", key, "node", n.id)
+			c.stop()
+		} else if n.holdsSession(key) {
+			slog.Warn("cluster: session lock lost while running", "key", key, "node", n.id)
+		}
+	}
+
+	n.mu.Lock()
+	pending := make(map[string]*claim)
+	for key, c := range n.claims {
+		if !c.owned {
+			pending[key] = c
+		}
+	}
+	n.mu.Unlock()
+	for key, c := range pending {
+		if ctx.Err() != nil {
+			return
+		}
+		n.tryAcquire(ctx, key, c)
+	}
+}
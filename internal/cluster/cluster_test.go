@@ -0,0 +1,428 @@
+package cluster
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/nextlevelbuilder/goclaw/internal/bus"
+	"github.com/nextlevelbuilder/goclaw/pkg/protocol"
+)
+
+// memHub is an in-memory coordination service shared by test nodes.
+type memHub struct {
+	mu     sync.Mutex
+	owners map[string]string
+	subs   map[chan []byte]bool
+}
+
+func newMemHub() *memHub {
+	return &memHub{owners: make(map[string]string), subs: make(map[chan []byte]bool)}
+}
+
+// steal hands key to another owner, as if this node's lease had expired.
+func (h *memHub) steal(key, owner string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.owners[key] = owner
+}
+
+type memBackend struct {
+	hub  *memHub
+	id   string
+	mu   sync.Mutex
+	held map[string]bool
+}
+
+func (h *memHub) backend(id string) *memBackend {
+	return &memBackend{hub: h, id: id, held: make(map[string]bool)}
+}
+
+func (b *memBackend) TryLock(_ context.Context, key string) (bool, error) {
+	b.hub.mu.Lock()
+	defer b.hub.mu.Unlock()
+	if owner, ok := b.hub.owners[key]; ok && owner != b.id {
+		return false, nil
+	}
+	b.hub.owners[key] = b.id
+	b.mu.Lock()
+	b.held[key] = true
+	b.mu.Unlock()
+	return true, nil
+}
+
+func (b *memBackend) Unlock(_ context.Context, key string) error {
+	b.hub.mu.Lock()
+	defer b.hub.mu.Unlock()
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.held[key] && b.hub.owners[key] == b.id {
+		delete(b.hub.owners, key)
+	}
+	delete(b.held, key)
+	return nil
+}
+
+func (b *memBackend) Keepalive(context.Context) ([]string, error) {
+	b.hub.mu.Lock()
+	defer b.hub.mu.Unlock()
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	var lost []string
+	for key := range b.held {
+		if b.hub.owners[key] != b.id {
+			lost = append(lost, key)
+			delete(b.held, key)
+		}
+	}
+	return lost, nil
+}
+
+func (b *memBackend) Publish(_ context.Context, msg []byte) error {
+	b.hub.mu.Lock()
+	defer b.hub.mu.Unlock()
+	for ch := range b.hub.subs {
+		ch <- msg
+	}
+	return nil
+}
+
+func (b *memBackend) Listen(ctx context.Context, fn func(msg []byte)) error {
+	ch := make(chan []byte, 64)
+	b.hub.mu.Lock()
+	b.hub.subs[ch] = true
+	b.hub.mu.Unlock()
+	defer func() {
+		b.hub.mu.Lock()
+		delete(b.hub.subs, ch)
+		b.hub.mu.Unlock()
+	}()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case msg := <-ch:
+			fn(msg)
+		}
+	}
+}
+
+func (b *memBackend) Close() error {
+	b.mu.Lock()
+	keys := make([]string, 0, len(b.held))
+	for key := range b.held {
+		keys = append(keys, key)
+	}
+	b.mu.Unlock()
+	for _, key := range keys {
+		b.Unlock(context.Background(), key)
+	}
+	return nil
+}
+
+// service counts start/stop calls of a claimed service.
+type service struct{ starts, stops atomic.Int32 }
+
+func (s *service) start() { s.starts.Add(1) }
+func (s *service) stop()  { s.stops.Add(1) }
+
+func TestClaim_FailoverOnStop(t *testing.T) {
+	hub := newMemHub()
+	a := New("a", hub.backend("a"), time.Minute)
+	b := New("b", hub.backend("b"), time.Minute)
+	var sa, sb service
+	key := SingletonKey("cron")
+
+	a.Claim(key, sa.start, sa.stop)
+	b.Claim(key, sb.start, sb.stop)
+	if !a.Owns(key) || b.Owns(key) {
+		t.Fatalf("first claimant should own the lease: a=%v b=%v", a.Owns(key), b.Owns(key))
+	}
+	if sa.starts.Load() != 1 || sb.starts.Load() != 0 {
+		t.Fatalf("starts: a=%d b=%d, want 1/0", sa.starts.Load(), sb.starts.Load())
+	}
+
+	a.Stop()
+	if sa.stops.Load() != 1 {
+		t.Fatalf("a stops = %d, want 1", sa.stops.Load())
+	}
+	b.checkLeases(context.Background())
+	if !b.Owns(key) || sb.starts.Load() != 1 {
+		t.Fatalf("b should take over: owns=%v starts=%d", b.Owns(key), sb.starts.Load())
+	}
+}
+
+func TestClaim_LostLeaseStopsService(t *testing.T) {
+	hub := newMemHub()
+	a := New("a", hub.backend("a"), time.Minute)
+	var sa service
+	key := ChannelKey("telegram")
+
+	a.Claim(key, sa.start, sa.stop)
+	hub.steal(key, "b")
+	a.checkLeases(context.Background())
+
+	if a.Owns(key) {
+		t.Fatal("a still owns a lease taken over by b")
+	}
+	if sa.stops.Load() != 1 {
+		t.Fatalf("stops = %d, want 1", sa.stops.Load())
+	}
+
+	// The lease comes back: the service restarts.
+	hub.steal(key, "a")
+	a.checkLeases(context.Background())
+	if !a.Owns(key) || sa.starts.Load() != 2 {
+		t.Fatalf("after recovery: owns=%v starts=%d", a.Owns(key), sa.starts.Load())
+	}
+}
+
+func TestClaim_ReleaseStopsAndUnlocks(t *testing.T) {
+	hub := newMemHub()
+	a := New("a", hub.backend("a"), time.Minute)
+	b := New("b", hub.backend("b"), time.Minute)
+	var sa, sb service
+	key := ChannelKey("discord")
+
+	a.Claim(key, sa.start, sa.stop)
+	b.Claim(key, sb.start, sb.stop)
+	a.Release(key)
+	if sa.stops.Load() != 1 || a.Owns(key) {
+		t.Fatalf("release: stops=%d owns=%v", sa.stops.Load(), a.Owns(key))
+	}
+	b.checkLeases(context.Background())
+	if !b.Owns(key) {
+		t.Fatal("b should acquire a released lease")
+	}
+}
+
+func TestAlive_ProbesNodeLease(t *testing.T) {
+	hub := newMemHub()
+	a := New("a", hub.backend("a"), time.Minute)
+	b := New("b", hub.backend("b"), time.Minute)
+	a.Start(context.Background())
+	b.Start(context.Background())
+	defer a.Stop()
+	ctx := context.Background()
+
+	if !a.Alive(ctx, "a") || !a.Alive(ctx, "b") {
+		t.Fatal("running nodes reported gone")
+	}
+	b.Stop()
+	if a.Alive(ctx, "b") {
+		t.Fatal("stopped node reported alive")
+	}
+	if a.Alive(ctx, "b-restarted-elsewhere") {
+		t.Fatal("unknown node reported alive")
+	}
+	// Probing must not leave the probed node's lease held.
+	if a.Owns(NodeKey("b")) || a.Alive(ctx, "b") {
+		t.Fatal("probe kept the lease")
+	}
+}
+
+func TestLockSession_SerializesAcrossNodes(t *testing.T) {
+	hub := newMemHub()
+	a := New("a", hub.backend("a"), time.Minute)
+	b := New("b", hub.backend("b"), time.Minute)
+	ctx := context.Background()
+
+	releaseA1, err := a.LockSession(ctx, "agent:x:main")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Runs on the same node share the lock.
+	releaseA2, err := a.LockSession(ctx, "agent:x:main")
+	if err != nil {
+		t.Fatalf("second local run: %v", err)
+	}
+
+	short, cancel := context.WithTimeout(ctx, 3*sessionLockPoll)
+	_, err = b.LockSession(short, "agent:x:main")
+	cancel()
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("remote lock while held: err = %v, want deadline exceeded", err)
+	}
+
+	// Another session is independent.
+	releaseOther, err := b.LockSession(ctx, "agent:x:other")
+	if err != nil {
+		t.Fatal(err)
+	}
+	releaseOther()
+
+	acquired := make(chan func(), 1)
+	go func() {
+		release, err := b.LockSession(ctx, "agent:x:main")
+		if err == nil {
+			acquired <- release
+		}
+	}()
+
+	releaseA1()
+	select {
+	case <-acquired:
+		t.Fatal("b acquired the session while a still runs it")
+	case <-time.After(2 * sessionLockPoll):
+	}
+
+	releaseA2()
+	select {
+	case release := <-acquired:
+		release()
+	case <-time.After(10 * sessionLockPoll):
+		t.Fatal("b did not acquire the session after a released it")
+	}
+}
+
+func TestRelayBus(t *testing.T) {
+	hub := newMemHub()
+	a := New("a", hub.backend("a"), time.Minute)
+	b := New("b", hub.backend("b"), time.Minute)
+	busA, busB := bus.New(), bus.New()
+	a.RelayBus(busA)
+	b.RelayBus(busB)
+	a.Start(context.Background())
+	b.Start(context.Background())
+	defer a.Stop()
+	defer b.Stop()
+	waitForListeners(t, hub, 2)
+
+	gotB := make(chan bus.Event, 4)
+	busB.Subscribe("test", func(evt bus.Event) { gotB <- evt })
+	var echoes atomic.Int32
+	busA.Subscribe("test", func(evt bus.Event) {
+		if evt.Origin != "" {
+			echoes.Add(1)
+		}
+	})
+
+	busA.Broadcast(bus.Event{Name: bus.TopicConfigChanged, Payload: "local only"})
+	busA.Broadcast(bus.Event{Name: bus.TopicAudit, Payload: "in-process topic"})
+	busA.Broadcast(bus.Event{Name: protocol.EventAgent, Payload: map[string]string{"type": protocol.ChatEventChunk}})
+	busA.Broadcast(bus.Event{
+		Name:    protocol.EventCacheInvalidate,
+		Payload: bus.CacheInvalidatePayload{Kind: bus.CacheKindAgent, Key: "alpha"},
+	})
+
+	select {
+	case evt := <-gotB:
+		if evt.Name != protocol.EventCacheInvalidate {
+			t.Fatalf("relayed %q, want cache invalidation (config changes, topics and chunks stay local)", evt.Name)
+		}
+		if evt.Origin != "a" {
+			t.Fatalf("origin = %q, want a", evt.Origin)
+		}
+		p, ok := evt.Payload.(bus.CacheInvalidatePayload)
+		if !ok || p.Key != "alpha" {
+			t.Fatalf("payload = %#v, want typed CacheInvalidatePayload", evt.Payload)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("event not relayed")
+	}
+
+	// The relayed copy must not bounce back to a.
+	time.Sleep(50 * time.Millisecond)
+	if n := echoes.Load(); n != 0 {
+		t.Fatalf("a received %d echoed events", n)
+	}
+}
+
+func TestChannelOwnership_ForwardCarriesMedia(t *testing.T) {
+	hub := newMemHub()
+	a := New("a", hub.backend("a"), time.Minute)
+	b := New("b", hub.backend("b"), time.Minute)
+	busB := bus.New()
+	ownA := NewChannelOwnership(a, bus.New())
+	ownB := NewChannelOwnership(b, busB)
+	a.Start(context.Background())
+	b.Start(context.Background())
+	defer a.Stop()
+	defer b.Stop()
+	waitForListeners(t, hub, 2)
+	ownB.Claim("tg", func() {}, func() {})
+
+	src := filepath.Join(t.TempDir(), "chart.png")
+	if err := os.WriteFile(src, []byte("png bytes"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	err := ownA.Forward(bus.OutboundMessage{
+		Channel: "tg",
+		ChatID:  "1",
+		Media:   []bus.MediaAttachment{{URL: src}, {URL: "https://example.com/a.jpg"}},
+	})
+	if err != nil {
+		t.Fatalf("Forward: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	msg, ok := busB.SubscribeOutbound(ctx)
+	if !ok {
+		t.Fatal("forwarded message not published on the owner")
+	}
+	if len(msg.Media) != 2 || msg.Media[1].URL != "https://example.com/a.jpg" {
+		t.Fatalf("media = %+v", msg.Media)
+	}
+	local := msg.Media[0].URL
+	defer os.Remove(local)
+	if local == src || filepath.Ext(local) != ".png" {
+		t.Fatalf("media path = %q, want a new temp file", local)
+	}
+	if data, err := os.ReadFile(local); err != nil || string(data) != "png bytes" {
+		t.Fatalf("restored media = %q, %v", data, err)
+	}
+}
+
+func TestRequest_OnlyHolderReplies(t *testing.T) {
+	hub := newMemHub()
+	a := New("a", hub.backend("a"), time.Minute)
+	b := New("b", hub.backend("b"), time.Minute)
+	c := New("c", hub.backend("c"), time.Minute)
+	for _, n := range []*Node{a, b, c} {
+		n.Start(context.Background())
+		defer n.Stop()
+	}
+	waitForListeners(t, hub, 3)
+	b.HandleRequest("item.get", func(string, json.RawMessage) (any, bool) { return nil, false })
+	c.HandleRequest("item.get", func(from string, raw json.RawMessage) (any, bool) {
+		return "c has " + string(raw) + " for " + from, true
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	reply, err := a.Request(ctx, "item.get", "x")
+	if err != nil {
+		t.Fatalf("Request: %v", err)
+	}
+	if string(reply) != `"c has \"x\" for a"` {
+		t.Fatalf("reply = %s", reply)
+	}
+
+	ctx, cancel = context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	if _, err := a.Request(ctx, "item.missing", "x"); !errors.Is(err, ErrNoReply) {
+		t.Fatalf("unanswered request: err = %v, want ErrNoReply", err)
+	}
+}
+
+func waitForListeners(t *testing.T, hub *memHub, n int) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		hub.mu.Lock()
+		got := len(hub.subs)
+		hub.mu.Unlock()
+		if got >= n {
+			return
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatalf("listeners not started")
+}
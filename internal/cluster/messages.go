@@ -0,0 +1,109 @@
+package cluster
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+// envelope is the wire format of a cluster message.
+type envelope struct {
+	From    string          `json:"from"`
+	Kind    string          `json:"kind"`
+	Payload json.RawMessage `json:"payload,omitempty"`
+}
+
+// Handle registers the handler for messages of kind sent by other nodes.
+// Handlers run on the listener goroutine and must not block.
+func (n *Node) Handle(kind string, fn func(from string, payload json.RawMessage)) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.handlers[kind] = fn
+}
+
+// Send delivers v to every other node's handler for kind. Messages are queued
+// and published in order by a single sender; when the queue is full the
+// message is dropped (events are best-effort, state lives in the database).
+func (n *Node) Send(kind string, v any) {
+	payload, err := json.Marshal(v)
+	if err != nil {
+		slog.Debug("cluster: message not serializable", "kind", kind, "error", err)
+		return
+	}
+	msg, _ := json.Marshal(envelope{From: n.id, Kind: kind, Payload: payload})
+	select {
+	case n.outbox <- msg:
+	default:
+		slog.Warn("cluster: outbox full, message dropped", "kind", kind)
+	}
+}
+
+// Publish delivers v to every other node's handler for kind right away,
+// bypassing the queue, and reports whether the message was published. Use it
+// for messages that must not be lost silently.
+func (n *Node) Publish(ctx context.Context, kind string, v any) error {
+	payload, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("cluster: message not serializable: %w", err)
+	}
+	msg, _ := json.Marshal(envelope{From: n.id, Kind: kind, Payload: payload})
+	return n.backend.Publish(ctx, msg)
+}
+
+func (n *Node) send(ctx context.Context) {
+	defer n.wg.Done()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case msg := <-n.outbox:
+			pubCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+			if err := n.backend.Publish(pubCtx, msg); err != nil && ctx.Err() == nil {
+				slog.Warn("cluster: publish", "bytes", len(msg), "error", err)
+			}
+			cancel()
+		}
+	}
+}
+
+// listen dispatches messages from other nodes, reconnecting on failure.
+func (n *Node) listen(ctx context.Context) {
+	defer n.wg.Done()
+	for {
+		err := n.backend.Listen(ctx, n.dispatch)
+		if ctx.Err() != nil {
+			return
+		}
+		slog.Warn("cluster: listener stopped, retrying", "error", err)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(time.Second):
+		}
+	}
+}
+
+func (n *Node) dispatch(raw []byte) {
+	var env envelope
+	if err := json.Unmarshal(raw, &env); err != nil {
+		slog.Debug("cluster: malformed message", "error", err)
+		return
+	}
+	if env.From == n.id {
+		return
+	}
+	n.mu.Lock()
+	fn := n.handlers[env.Kind]
+	n.mu.Unlock()
+	if fn == nil {
+		return
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			slog.Error("cluster: message handler panicked", "kind", env.Kind, "panic", r)
+		}
+	}()
+	fn(env.From, env.Payload)
+}
@@ -0,0 +1,218 @@
+//go:build !sqliteonly
+
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+const (
+	pgNotifyChannel = "goclaw_cluster"
+	pgLockNamespace = "goclaw:"
+	// NOTIFY payloads must be shorter than 8000 bytes.
+	pgMaxNotifyPayload = 7999
+	// pgStoredPrefix marks a notification that carries the id of a row in
+	// cluster_messages instead of the message (messages are JSON objects).
+	pgStoredPrefix = "@"
+	// pgStoredTTL is how long stored messages are kept for listeners.
+	pgStoredTTL = 5 * time.Minute
+)
+
+// pgBackend coordinates through PostgreSQL: session-level advisory locks on a
+// dedicated connection (released by the server if the replica dies) and
+// LISTEN/NOTIFY for messages. Messages are published on a connection of their
+// own so event traffic never delays lock renewal; those too large for NOTIFY
+// are stored in cluster_messages and announced by id.
+type pgBackend struct {
+	dsn string
+
+	mu   sync.Mutex
+	conn *pgx.Conn // lock session; nil after a failure until the next reconnect
+	held map[string]bool
+
+	pubMu   sync.Mutex
+	pubConn *pgx.Conn
+}
+
+// NewPostgresBackend connects the lock session.
+func NewPostgresBackend(ctx context.Context, dsn string) (Backend, error) {
+	b := &pgBackend{dsn: dsn, held: make(map[string]bool)}
+	if _, err := b.session(ctx); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// session returns the lock connection, reconnecting if needed (caller holds mu).
+func (b *pgBackend) session(ctx context.Context) (*pgx.Conn, error) {
+	if b.conn != nil && !b.conn.IsClosed() {
+		return b.conn, nil
+	}
+	conn, err := b.connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	b.conn = conn
+	return conn, nil
+}
+
+func (b *pgBackend) connect(ctx context.Context) (*pgx.Conn, error) {
+	cctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	conn, err := pgx.Connect(cctx, b.dsn)
+	if err != nil {
+		return nil, fmt.Errorf("cluster: connect postgres: %w", err)
+	}
+	return conn, nil
+}
+
+// dropSession closes a broken lock session; every lock it held is gone.
+func (b *pgBackend) dropSession() []string {
+	if b.conn != nil {
+		b.conn.Close(context.Background())
+		b.conn = nil
+	}
+	lost := make([]string, 0, len(b.held))
+	for key := range b.held {
+		lost = append(lost, key)
+	}
+	b.held = make(map[string]bool)
+	return lost
+}
+
+func (b *pgBackend) TryLock(ctx context.Context, key string) (bool, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.held[key] {
+		return true, nil
+	}
+	conn, err := b.session(ctx)
+	if err != nil {
+		return false, err
+	}
+	var ok bool
+	if err := conn.QueryRow(ctx, "SELECT pg_try_advisory_lock(hashtextextended($1, 0))", pgLockNamespace+key).Scan(&ok); err != nil {
+		return false, err
+	}
+	if ok {
+		b.held[key] = true
+	}
+	return ok, nil
+}
+
+func (b *pgBackend) Unlock(ctx context.Context, key string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if !b.held[key] {
+		return nil
+	}
+	delete(b.held, key)
+	if b.conn == nil || b.conn.IsClosed() {
+		return nil // session gone, lock already released
+	}
+	_, err := b.conn.Exec(ctx, "SELECT pg_advisory_unlock(hashtextextended($1, 0))", pgLockNamespace+key)
+	return err
+}
+
+// Keepalive pings the lock session. Advisory locks live exactly as long as
+// the session, so a failed ping means every held lock is lost.
+func (b *pgBackend) Keepalive(ctx context.Context) ([]string, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.conn == nil || b.conn.IsClosed() {
+		return b.dropSession(), nil
+	}
+	pctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	if err := b.conn.Ping(pctx); err != nil {
+		return b.dropSession(), err
+	}
+	return nil, nil
+}
+
+func (b *pgBackend) Publish(ctx context.Context, msg []byte) error {
+	b.pubMu.Lock()
+	defer b.pubMu.Unlock()
+	if b.pubConn == nil || b.pubConn.IsClosed() {
+		conn, err := b.connect(ctx)
+		if err != nil {
+			return err
+		}
+		b.pubConn = conn
+	}
+	payload := string(msg)
+	if len(msg) > pgMaxNotifyPayload {
+		var id int64
+		if err := b.pubConn.QueryRow(ctx,
+			"INSERT INTO cluster_messages (payload) VALUES ($1) RETURNING id", msg,
+		).Scan(&id); err != nil {
+			return fmt.Errorf("store cluster message: %w", err)
+		}
+		payload = pgStoredPrefix + strconv.FormatInt(id, 10)
+		if _, err := b.pubConn.Exec(ctx,
+			"DELETE FROM cluster_messages WHERE created_at < NOW() - make_interval(secs => $1)", pgStoredTTL.Seconds(),
+		); err != nil {
+			slog.Debug("cluster: prune stored messages", "error", err)
+		}
+	}
+	_, err := b.pubConn.Exec(ctx, "SELECT pg_notify($1, $2)", pgNotifyChannel, payload)
+	return err
+}
+
+// Listen uses its own connection: WaitForNotification blocks the session.
+func (b *pgBackend) Listen(ctx context.Context, fn func(msg []byte)) error {
+	cctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	conn, err := pgx.Connect(cctx, b.dsn)
+	cancel()
+	if err != nil {
+		return fmt.Errorf("cluster: connect listener: %w", err)
+	}
+	defer conn.Close(context.Background())
+
+	if _, err := conn.Exec(ctx, "LISTEN "+pgNotifyChannel); err != nil {
+		return err
+	}
+	for {
+		n, err := conn.WaitForNotification(ctx)
+		if err != nil {
+			return err
+		}
+		ref, stored := strings.CutPrefix(n.Payload, pgStoredPrefix)
+		if !stored {
+			fn([]byte(n.Payload))
+			continue
+		}
+		id, err := strconv.ParseInt(ref, 10, 64)
+		if err != nil {
+			continue
+		}
+		var msg []byte
+		if err := conn.QueryRow(ctx, "SELECT payload FROM cluster_messages WHERE id = $1", id).Scan(&msg); err != nil {
+			slog.Warn("cluster: load stored message", "id", id, "error", err)
+			continue
+		}
+		fn(msg)
+	}
+}
+
+func (b *pgBackend) Close() error {
+	b.pubMu.Lock()
+	if b.pubConn != nil {
+		b.pubConn.Close(context.Background())
+		b.pubConn = nil
+	}
+	b.pubMu.Unlock()
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.dropSession()
+	return nil
+}
@@ -0,0 +1,13 @@
+//go:build sqliteonly
+
+package cluster
+
+import (
+	"context"
+	"errors"
+)
+
+// NewPostgresBackend is unavailable in SQLite-only builds.
+func NewPostgresBackend(_ context.Context, _ string) (Backend, error) {
+	return nil, errors.New("cluster: the postgres backend is not compiled into sqliteonly builds")
+}
@@ -0,0 +1,151 @@
+//go:build redis
+
+package cluster
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	redisChannel   = "goclaw:cluster"
+	redisKeyPrefix = "goclaw:cluster:lock:"
+)
+
+// Lock values are the node ID, so only the holder can renew or delete them.
+var (
+	redisRenew  = redis.NewScript(`if redis.call("GET", KEYS[1]) == ARGV[1] then return redis.call("PEXPIRE", KEYS[1], ARGV[2]) else return 0 end`)
+	redisUnlock = redis.NewScript(`if redis.call("GET", KEYS[1]) == ARGV[1] then return redis.call("DEL", KEYS[1]) else return 0 end`)
+)
+
+// redisBackend coordinates through Redis: SET NX keys with a TTL renewed by
+// Keepalive, and pub/sub for messages. It shares the gateway's cache client.
+type redisBackend struct {
+	client *redis.Client
+	token  string
+	ttl    time.Duration
+
+	mu   sync.Mutex
+	held map[string]bool
+}
+
+// NewRedisBackend wraps the client returned by the gateway's Redis setup.
+func NewRedisBackend(raw any, nodeID string, ttl time.Duration) (Backend, error) {
+	client, _ := raw.(*redis.Client)
+	if client == nil {
+		return nil, errors.New("cluster: redis backend requires GOCLAW_REDIS_DSN")
+	}
+	if ttl <= 0 {
+		ttl = 15 * time.Second
+	}
+	return &redisBackend{client: client, token: nodeID, ttl: ttl, held: make(map[string]bool)}, nil
+}
+
+func (b *redisBackend) TryLock(ctx context.Context, key string) (bool, error) {
+	b.mu.Lock()
+	held := b.held[key]
+	b.mu.Unlock()
+	if held {
+		return true, nil
+	}
+	ok, err := b.client.SetNX(ctx, redisKeyPrefix+key, b.token, b.ttl).Result()
+	if err != nil || !ok {
+		return false, err
+	}
+	b.mu.Lock()
+	b.held[key] = true
+	b.mu.Unlock()
+	return true, nil
+}
+
+func (b *redisBackend) Unlock(ctx context.Context, key string) error {
+	b.mu.Lock()
+	held := b.held[key]
+	delete(b.held, key)
+	b.mu.Unlock()
+	if !held {
+		return nil
+	}
+	return redisUnlock.Run(ctx, b.client, []string{redisKeyPrefix + key}, b.token).Err()
+}
+
+// Keepalive extends every held key. A key that expired or was taken over
+// (e.g. after a long GC pause or a network partition) is reported lost.
+func (b *redisBackend) Keepalive(ctx context.Context) ([]string, error) {
+	b.mu.Lock()
+	keys := make([]string, 0, len(b.held))
+	for key := range b.held {
+		keys = append(keys, key)
+	}
+	b.mu.Unlock()
+
+	var lost []string
+	var firstErr error
+	for _, key := range keys {
+		n, err := redisRenew.Run(ctx, b.client, []string{redisKeyPrefix + key}, b.token, b.ttl.Milliseconds()).Int()
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			// Unreachable for longer than the TTL: another node may own it now.
+			continue
+		}
+		if n == 0 {
+			lost = append(lost, key)
+		}
+	}
+	if len(lost) > 0 {
+		b.mu.Lock()
+		for _, key := range lost {
+			delete(b.held, key)
+		}
+		b.mu.Unlock()
+	}
+	return lost, firstErr
+}
+
+func (b *redisBackend) Publish(ctx context.Context, msg []byte) error {
+	return b.client.Publish(ctx, redisChannel, msg).Err()
+}
+
+func (b *redisBackend) Listen(ctx context.Context, fn func(msg []byte)) error {
+	sub := b.client.Subscribe(ctx, redisChannel)
+	defer sub.Close()
+	if _, err := sub.Receive(ctx); err != nil {
+		return err
+	}
+	ch := sub.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case m, ok := <-ch:
+			if !ok {
+				return errors.New("cluster: redis subscription closed")
+			}
+			fn([]byte(m.Payload))
+		}
+	}
+}
+
+// Close releases held keys; the client itself belongs to the gateway.
+func (b *redisBackend) Close() error {
+	b.mu.Lock()
+	keys := make([]string, 0, len(b.held))
+	for key := range b.held {
+		keys = append(keys, key)
+	}
+	b.held = make(map[string]bool)
+	b.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	for _, key := range keys {
+		redisUnlock.Run(ctx, b.client, []string{redisKeyPrefix + key}, b.token)
+	}
+	return nil
+}
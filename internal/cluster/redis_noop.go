@@ -0,0 +1,13 @@
+//go:build !redis
+
+package cluster
+
+import (
+	"errors"
+	"time"
+)
+
+// NewRedisBackend is unavailable without the "redis" build tag.
+func NewRedisBackend(_ any, _ string, _ time.Duration) (Backend, error) {
+	return nil, errors.New("cluster: redis backend requires a build with -tags redis")
+}
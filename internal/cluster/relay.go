@@ -0,0 +1,174 @@
+package cluster
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/nextlevelbuilder/goclaw/internal/bus"
+	"github.com/nextlevelbuilder/goclaw/internal/store"
+	"github.com/nextlevelbuilder/goclaw/pkg/protocol"
+)
+
+const kindBusEvent = "bus.event"
+
+// relayedEvent is a bus.Event on the wire.
+type relayedEvent struct {
+	Name     string          `json:"name"`
+	TenantID uuid.UUID       `json:"tenant_id"`
+	Payload  json.RawMessage `json:"payload,omitempty"`
+}
+
+// eventCodec converts the payload of an event whose subscribers type-assert
+// it, so remote delivery reaches them with the type they expect.
+type eventCodec struct {
+	encode func(payload any) (any, bool)
+	decode func(raw json.RawMessage) (any, error)
+}
+
+func typedCodec[T any]() eventCodec {
+	return eventCodec{
+		encode: func(p any) (any, bool) { v, ok := p.(T); return v, ok },
+		decode: func(raw json.RawMessage) (any, error) {
+			var v T
+			err := json.Unmarshal(raw, &v)
+			return v, err
+		},
+	}
+}
+
+// eventCodecs lists the events every replica must act on: cache invalidation
+// (each replica has its own caches), system config reloads, pairing
+// revocation (clients connected to any replica are disconnected), accepted
+// webhook deliveries (replay rejection) and completed MCP authorizations
+// (per-replica tool caches).
+var eventCodecs = map[string]eventCodec{
+	protocol.EventCacheInvalidate: typedCodec[bus.CacheInvalidatePayload](),
+	bus.EventPairingRevoked:       typedCodec[bus.PairingRevokedPayload](),
+	bus.EventWebhookDeliverySeen:  typedCodec[bus.WebhookDeliverySeenPayload](),
+	bus.EventMCPOAuthAuthorized:   typedCodec[bus.MCPOAuthAuthorizedPayload](),
+	bus.TopicSystemConfigChanged: {
+		// Payload is the tenant-scoped request context.
+		encode: func(p any) (any, bool) {
+			ctx, ok := p.(context.Context)
+			if !ok {
+				return nil, true
+			}
+			return store.TenantIDFromContext(ctx), true
+		},
+		decode: func(raw json.RawMessage) (any, error) {
+			var tid uuid.UUID
+			if len(raw) > 0 && string(raw) != "null" {
+				if err := json.Unmarshal(raw, &tid); err != nil {
+					return nil, err
+				}
+			}
+			if tid == uuid.Nil {
+				return nil, nil
+			}
+			return store.WithTenantID(context.Background(), tid), nil
+		},
+	},
+}
+
+// wsEvents are the WebSocket notifications a client connected to another
+// replica must still see. They reach remote subscribers with a
+// json.RawMessage payload, which the WebSocket fan-out forwards as-is. Other
+// bus traffic — in-process topics (audit persistence, task dispatch, channel
+// streaming), config.json reloads (per replica) and connection-level events —
+// stays on the replica that raised it.
+var wsEvents = map[string]bool{
+	protocol.EventAgent:                true,
+	protocol.EventAgentSummoning:       true,
+	protocol.EventExecApprovalReq:      true,
+	protocol.EventExecApprovalRes:      true,
+	protocol.EventToolApprovalReq:      true,
+	protocol.EventToolApprovalRes:      true,
+	protocol.EventWorkspaceFileChanged: true,
+	protocol.EventTraceUpdated:         true,
+	protocol.EventAuditLog:             true,
+	protocol.EventSessionUpdated:       true,
+	protocol.EventTTSChanged:           true,
+	protocol.EventTenantAccessRevoked:  true,
+	protocol.EventBudgetThreshold:      true,
+}
+
+// wsEventPrefixes cover event families relayed as a whole.
+var wsEventPrefixes = []string{"team.", "delegation.", "agent_link.", "skill.dep"}
+
+// streamedAgentEvents are per-token agent event subtypes. They are far too
+// frequent for the coordination channel; remote clients still get the run
+// lifecycle, tool calls and block replies.
+var streamedAgentEvents = map[string]bool{
+	protocol.ChatEventChunk:    true,
+	protocol.ChatEventThinking: true,
+}
+
+// relayed reports whether an event is mirrored to the other replicas.
+func relayed(name string) bool {
+	if _, ok := eventCodecs[name]; ok || wsEvents[name] {
+		return true
+	}
+	for _, p := range wsEventPrefixes {
+		if strings.HasPrefix(name, p) {
+			return true
+		}
+	}
+	return false
+}
+
+// RelayBus mirrors this replica's bus events to the other replicas and
+// re-broadcasts theirs locally, marked with their origin so they are not
+// relayed again.
+func (n *Node) RelayBus(mb *bus.MessageBus) {
+	n.Handle(kindBusEvent, func(from string, raw json.RawMessage) {
+		var re relayedEvent
+		if err := json.Unmarshal(raw, &re); err != nil {
+			slog.Debug("cluster: malformed relayed event", "error", err)
+			return
+		}
+		var payload any = re.Payload
+		if codec, ok := eventCodecs[re.Name]; ok {
+			v, err := codec.decode(re.Payload)
+			if err != nil {
+				slog.Debug("cluster: relayed event payload", "event", re.Name, "error", err)
+				return
+			}
+			payload = v
+		} else if len(re.Payload) == 0 || string(re.Payload) == "null" {
+			payload = nil
+		}
+		mb.Broadcast(bus.Event{Name: re.Name, Payload: payload, TenantID: re.TenantID, Origin: from})
+	})
+
+	mb.Subscribe("cluster.relay", func(evt bus.Event) {
+		if evt.Origin != "" || !relayed(evt.Name) {
+			return
+		}
+		payload := evt.Payload
+		if codec, ok := eventCodecs[evt.Name]; ok {
+			v, ok := codec.encode(payload)
+			if !ok {
+				return
+			}
+			payload = v
+		}
+		raw, err := json.Marshal(payload)
+		if err != nil {
+			slog.Debug("cluster: event not relayable", "event", evt.Name, "error", err)
+			return
+		}
+		if evt.Name == protocol.EventAgent {
+			var sub struct {
+				Type string `json:"type"`
+			}
+			if json.Unmarshal(raw, &sub) == nil && streamedAgentEvents[sub.Type] {
+				return
+			}
+		}
+		n.Send(kindBusEvent, relayedEvent{Name: evt.Name, TenantID: evt.TenantID, Payload: raw})
+	})
+}
@@ -0,0 +1,99 @@
+package cluster
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/google/uuid"
+)
+
+// ErrNoReply is returned by Request when no node answered in time.
+var ErrNoReply = errors.New("cluster: no node replied")
+
+// kindReply carries request replies back to the requesting node.
+const kindReply = "cluster.reply"
+
+type requestMsg struct {
+	Ref     string          `json:"ref"`
+	Payload json.RawMessage `json:"payload,omitempty"`
+}
+
+type replyMsg struct {
+	Ref     string          `json:"ref"`
+	To      string          `json:"to"`
+	Payload json.RawMessage `json:"payload,omitempty"`
+}
+
+// HandleRequest registers the handler for requests of kind sent by other
+// nodes. A node that cannot serve the request (it does not hold the item)
+// returns ok=false and stays silent; the requester takes the first reply.
+// Handlers run on the listener goroutine and must not block.
+func (n *Node) HandleRequest(kind string, fn func(from string, payload json.RawMessage) (reply any, ok bool)) {
+	n.Handle(kind, func(from string, raw json.RawMessage) {
+		var req requestMsg
+		if err := json.Unmarshal(raw, &req); err != nil || req.Ref == "" {
+			slog.Debug("cluster: malformed request", "kind", kind, "error", err)
+			return
+		}
+		reply, ok := fn(from, req.Payload)
+		if !ok {
+			return
+		}
+		payload, err := json.Marshal(reply)
+		if err != nil {
+			slog.Debug("cluster: reply not serializable", "kind", kind, "error", err)
+			return
+		}
+		n.Send(kindReply, replyMsg{Ref: req.Ref, To: from, Payload: payload})
+	})
+}
+
+// Request sends v to the other nodes' handlers for kind and waits for the
+// first reply. It returns ErrNoReply when ctx ends before any node answers,
+// which also means no node holds what was asked for.
+func (n *Node) Request(ctx context.Context, kind string, v any) (json.RawMessage, error) {
+	payload, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("cluster: request not serializable: %w", err)
+	}
+	ref := uuid.NewString()
+	ch := make(chan json.RawMessage, 1)
+	n.reqMu.Lock()
+	n.waiters[ref] = ch
+	n.reqMu.Unlock()
+	defer func() {
+		n.reqMu.Lock()
+		delete(n.waiters, ref)
+		n.reqMu.Unlock()
+	}()
+
+	if err := n.Publish(ctx, kind, requestMsg{Ref: ref, Payload: payload}); err != nil {
+		return nil, err
+	}
+	select {
+	case reply := <-ch:
+		return reply, nil
+	case <-ctx.Done():
+		return nil, ErrNoReply
+	}
+}
+
+func (n *Node) deliverReply(_ string, raw json.RawMessage) {
+	var reply replyMsg
+	if err := json.Unmarshal(raw, &reply); err != nil || reply.To != n.id {
+		return
+	}
+	n.reqMu.Lock()
+	ch := n.waiters[reply.Ref]
+	n.reqMu.Unlock()
+	if ch == nil {
+		return
+	}
+	select {
+	case ch <- reply.Payload:
+	default: // another node already answered
+	}
+}
@@ -0,0 +1,97 @@
+package cluster
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// sessionLockPoll is how often a queued run re-checks a session held by
+// another replica.
+const sessionLockPoll = 250 * time.Millisecond
+
+// sessionLock is a cluster-wide session lock shared by this node's runs.
+type sessionLock struct {
+	refs     int
+	acquired chan struct{} // closed once acquisition finished (see err)
+	err      error
+	cancel   context.CancelFunc
+}
+
+// LockSession serializes runs of one session across replicas. Runs on the
+// same node share the lock, so the scheduler's local per-session concurrency
+// still applies; a run on another node waits until every local run finished.
+// The returned release must be called exactly once.
+func (n *Node) LockSession(ctx context.Context, sessionKey string) (release func(), err error) {
+	key := sessionPrefix + sessionKey
+
+	n.sessMu.Lock()
+	sl, ok := n.sessions[key]
+	if !ok {
+		acqCtx, cancel := context.WithCancel(context.Background())
+		sl = &sessionLock{acquired: make(chan struct{}), cancel: cancel}
+		n.sessions[key] = sl
+		go n.acquireSession(acqCtx, key, sl)
+	}
+	sl.refs++
+	n.sessMu.Unlock()
+
+	select {
+	case <-sl.acquired:
+	case <-ctx.Done():
+		n.unrefSession(key, sl)
+		return nil, ctx.Err()
+	}
+	if sl.err != nil {
+		n.unrefSession(key, sl)
+		return nil, sl.err
+	}
+	return func() { n.unrefSession(key, sl) }, nil
+}
+
+func (n *Node) acquireSession(ctx context.Context, key string, sl *sessionLock) {
+	defer close(sl.acquired)
+	for {
+		ok, err := n.backend.TryLock(ctx, key)
+		if err == nil && ok {
+			return
+		}
+		if err != nil && ctx.Err() == nil {
+			slog.Warn("cluster: session lock", "key", key, "error", err)
+		}
+		select {
+		case <-ctx.Done():
+			sl.err = ctx.Err()
+			return
+		case <-time.After(sessionLockPoll):
+		}
+	}
+}
+
+// unrefSession drops one reference; the last one releases the backend lock.
+// sessMu is held across the unlock so a new run cannot slip in between and
+// mistake the lock being released for one it holds.
+func (n *Node) unrefSession(key string, sl *sessionLock) {
+	n.sessMu.Lock()
+	defer n.sessMu.Unlock()
+	sl.refs--
+	if sl.refs > 0 {
+		return
+	}
+	delete(n.sessions, key)
+	sl.cancel()
+	<-sl.acquired
+	if sl.err == nil {
+		if err := n.backend.Unlock(context.Background(), key); err != nil {
+			slog.Warn("cluster: session unlock", "key", key, "error", err)
+		}
+	}
+}
+
+// holdsSession reports whether key is a session lock in use on this node.
+func (n *Node) holdsSession(key string) bool {
+	n.sessMu.Lock()
+	defer n.sessMu.Unlock()
+	_, ok := n.sessions[key]
+	return ok
+}
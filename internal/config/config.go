@@ -49,9 +49,11 @@ type Config struct {
 	Sessions  SessionsConfig  `json:"sessions"`
 	Database  DatabaseConfig  `json:"database"`
 	Tts       TtsConfig       `json:"tts"`
+	Stt       SttConfig       `json:"stt"`
 	Cron      CronConfig      `json:"cron"`
 	Telemetry TelemetryConfig `json:"telemetry"`
 	Tailscale TailscaleConfig `json:"tailscale"`
+	Cluster   ClusterConfig   `json:"cluster"`
 	Bindings  []AgentBinding  `json:"bindings,omitempty"`
 	mu        sync.RWMutex
 }
@@ -75,6 +77,17 @@ type DatabaseConfig struct {
 	SQLitePath     string `json:"-"` // from env GOCLAW_SQLITE_PATH only (default: {dataDir}/goclaw.db)
 }
 
+// ClusterConfig enables running several gateway replicas against one
+// PostgreSQL database. Singleton services (cron, heartbeats, task recovery)
+// run on an elected leader, each channel instance runs on exactly one replica,
+// and bus events fan out to every replica.
+type ClusterConfig struct {
+	Enabled     bool   `json:"enabled,omitempty"`
+	NodeID      string `json:"node_id,omitempty"`      // unique per replica (default: hostname-pid)
+	Backend     string `json:"backend,omitempty"`      // "postgres" (default) or "redis" (requires -tags redis and GOCLAW_REDIS_DSN)
+	LeaseTTLSec int    `json:"lease_ttl_sec,omitempty"` // failover window: leases are renewed every third of it (default 15)
+}
+
 // SkillsConfig configures the skills storage system.
 type SkillsConfig struct {
 	StorageDir string `json:"storage_dir,omitempty"` // directory for skill content (default: dataDir/skills-store/)
@@ -186,6 +199,32 @@ type MemoryConfig struct {
 	VectorWeight      float64 `json:"vector_weight,omitempty"`      // hybrid search vector weight (default 0.7)
 	TextWeight        float64 `json:"text_weight,omitempty"`        // hybrid search FTS weight (default 0.3)
 	MinScore          float64 `json:"min_score,omitempty"`          // minimum relevance score (default 0.35)
+
+	Retrieval *MemoryRetrievalConfig `json:"retrieval,omitempty"` // post-retrieval pipeline (nil = raw hybrid top-k)
+}
+
+// MemoryRetrievalConfig configures the stages applied to memory search
+// candidates after hybrid retrieval: query expansion, reranking, recency
+// decay, path boosts and MMR diversification. Every stage is off by default.
+type MemoryRetrievalConfig struct {
+	CandidateMultiplier int `json:"candidate_multiplier,omitempty"` // candidates fetched per result (default 4)
+
+	QueryExpansion    string `json:"query_expansion,omitempty"`    // "", "paraphrase", "hyde"
+	ExpansionCount    int    `json:"expansion_count,omitempty"`    // paraphrases to generate (default 2)
+	ExpansionProvider string `json:"expansion_provider,omitempty"` // LLM provider for expansion
+	ExpansionModel    string `json:"expansion_model,omitempty"`    // default: provider's default model
+
+	Rerank         string `json:"rerank,omitempty"`          // "", "llm", "cross_encoder"
+	RerankProvider string `json:"rerank_provider,omitempty"` // LLM provider, or provider whose key/base the cross-encoder uses
+	RerankModel    string `json:"rerank_model,omitempty"`    // e.g. "rerank-v3.5", "BAAI/bge-reranker-v2-m3"
+	RerankAPIBase  string `json:"rerank_api_base,omitempty"` // cross-encoder endpoint base; POSTs to {base}/rerank
+
+	RecencyHalfLifeDays float64 `json:"recency_half_life_days,omitempty"` // age at which the recency boost halves (0 = off)
+	RecencyWeight       float64 `json:"recency_weight,omitempty"`         // share of the score subject to decay (default 0.3)
+
+	PathBoosts map[string]float64 `json:"path_boosts,omitempty"` // path glob or prefix → score multiplier
+
+	MMRLambda float64 `json:"mmr_lambda,omitempty"` // relevance vs. diversity trade-off, 0-1 (0 = off, 0.7 typical)
 }
 
 // SandboxConfig configures Docker-based sandbox execution.
@@ -314,6 +353,14 @@ type TelemetryConfig struct {
 	ServiceName  string                     `json:"service_name,omitempty"`  // OTEL service name (default "goclaw-gateway")
 	Headers      map[string]string          `json:"headers,omitempty"`       // extra headers (e.g. auth tokens for cloud backends)
 	ModelPricing map[string]*ModelPricing    `json:"model_pricing,omitempty"` // cost per model, key = "provider/model" or just "model"
+	Metrics      MetricsConfig              `json:"metrics,omitempty"`       // Prometheus /metrics endpoint
+}
+
+// MetricsConfig controls the Prometheus scrape endpoint (GET /metrics).
+// Without a token the endpoint requires an admin-role API key or the gateway token.
+type MetricsConfig struct {
+	Enabled bool   `json:"enabled,omitempty"` // serve /metrics (default false)
+	Token   string `json:"token,omitempty"`   // dedicated bearer token for scrapers (optional)
 }
 
 // CronConfig configures the cron job system.
@@ -387,9 +434,11 @@ func (c *Config) ReplaceFrom(src *Config) {
 	c.Sessions = src.Sessions
 	c.Database = src.Database
 	c.Tts = src.Tts
+	c.Stt = src.Stt
 	c.Cron = src.Cron
 	c.Telemetry = src.Telemetry
 	c.Tailscale = src.Tailscale
+	c.Cluster = src.Cluster
 	c.Bindings = src.Bindings
 }
 
@@ -20,6 +20,7 @@ type ChannelsConfig struct {
 	Zalo              ZaloConfig               `json:"zalo"`
 	ZaloPersonal      ZaloPersonalConfig       `json:"zalo_personal"`
 	Feishu            FeishuConfig             `json:"feishu"`
+	Email             EmailConfig              `json:"email"`
 	PendingCompaction *PendingCompactionConfig `json:"pending_compaction,omitempty"` // global pending message compaction settings
 }
 
@@ -44,8 +45,8 @@ type TelegramConfig struct {
 	BlockReply     *bool               `json:"block_reply,omitempty"`     // override gateway block_reply (nil = inherit)
 	ForceIPv4      bool                `json:"force_ipv4,omitempty"`      // force IPv4 for all Telegram API requests (use when IPv6 routing is broken)
 
-	// Optional STT (Speech-to-Text) pipeline for voice/audio inbound messages.
-	// When stt_proxy_url is set, audio/voice messages are transcribed before being forwarded to the agent.
+	// Deprecated STT proxy settings. Transcription runs in the gateway STT manager;
+	// when stt.proxy.url is unset, a channel's stt_proxy_url is used as that proxy.
 	STTProxyURL       string `json:"stt_proxy_url,omitempty"`       // base URL of the STT proxy service (e.g. "https://stt.example.com")
 	STTAPIKey         string `json:"stt_api_key,omitempty"`         // Bearer token for the STT proxy
 	STTTenantID       string `json:"stt_tenant_id,omitempty"`       // optional tenant/org identifier forwarded to the STT proxy
@@ -162,6 +163,27 @@ type ZaloPersonalConfig struct {
 	BlockReply      *bool               `json:"block_reply,omitempty"`      // override gateway block_reply (nil = inherit)
 }
 
+// EmailConfig configures the IMAP/SMTP email channel.
+type EmailConfig struct {
+	Enabled      bool                `json:"enabled"`
+	Address      string              `json:"address"`                 // mailbox address replies are sent from
+	DisplayName  string              `json:"display_name,omitempty"`  // From: display name
+	Username     string              `json:"username,omitempty"`      // IMAP/SMTP login (default: address)
+	Password     string              `json:"password,omitempty"`      // IMAP/SMTP password or app password
+	IMAPHost     string              `json:"imap_host"`               // e.g. "imap.gmail.com"
+	IMAPPort     int                 `json:"imap_port,omitempty"`     // default 993 (tls) / 143 (none)
+	IMAPSecurity string              `json:"imap_security,omitempty"` // "tls" (default) or "none"
+	Mailbox      string              `json:"mailbox,omitempty"`       // default "INBOX"
+	SMTPHost     string              `json:"smtp_host"`               // e.g. "smtp.gmail.com"
+	SMTPPort     int                 `json:"smtp_port,omitempty"`     // default 587 (starttls) / 465 (tls) / 25 (none)
+	SMTPSecurity string              `json:"smtp_security,omitempty"` // "starttls" (default), "tls" or "none"
+	PollInterval int                 `json:"poll_interval,omitempty"` // seconds between mailbox polls (default 60)
+	AllowFrom    FlexibleStringSlice `json:"allow_from"`              // addresses, "@domain" or "*@domain"
+	DMPolicy     string              `json:"dm_policy,omitempty"`     // "allowlist" (default), "pairing", "open", "disabled"
+	MediaMaxMB   int                 `json:"media_max_mb,omitempty"`  // max inbound attachment size (default 20)
+	BlockReply   *bool               `json:"block_reply,omitempty"`   // override gateway block_reply (nil = off for email)
+}
+
 type FeishuConfig struct {
 	Enabled           bool                `json:"enabled"`
 	AppID             string              `json:"app_id"`
@@ -334,6 +356,43 @@ type QuotaConfig struct {
 	Groups    map[string]QuotaWindow `json:"groups,omitempty"`    // key = userID (e.g. "group:telegram:-100123")
 }
 
+// Budget hard actions.
+const (
+	BudgetActionRefuse    = "refuse"    // fail the run before the LLM call
+	BudgetActionDowngrade = "downgrade" // keep running on DowngradeModel
+)
+
+// BudgetLimit is a monthly USD spend ceiling (calendar month, UTC).
+// Zero MonthlyUSD means unlimited.
+type BudgetLimit struct {
+	MonthlyUSD     float64 `json:"monthly_usd"`
+	SoftPercent    int     `json:"soft_percent,omitempty"`    // warn + notify at this % of the limit (default 80)
+	HardAction     string  `json:"hard_action,omitempty"`     // "refuse" (default) or "downgrade"
+	DowngradeModel string  `json:"downgrade_model,omitempty"` // "provider/model" or "model" used by "downgrade"
+}
+
+// IsZero returns true if no limit is set.
+func (l BudgetLimit) IsZero() bool { return l.MonthlyUSD <= 0 }
+
+// BudgetNotifyTarget is a channel chat that receives budget threshold alerts.
+type BudgetNotifyTarget struct {
+	Channel string `json:"channel"`
+	ChatID  string `json:"chat_id"`
+}
+
+// BudgetConfig configures monthly spend budgets. Costs come from traces, so
+// telemetry.model_pricing must be set for the models in use.
+// Agent limits fall back to the agent's budget_monthly_cents column.
+type BudgetConfig struct {
+	Enabled bool                   `json:"enabled"`
+	Tenant  BudgetLimit            `json:"tenant"`            // default limit for every tenant
+	Tenants map[string]BudgetLimit `json:"tenants,omitempty"` // key = tenant ID
+	Agents  map[string]BudgetLimit `json:"agents,omitempty"`  // key = agent key
+	User    BudgetLimit            `json:"user"`              // default limit for every external user
+	Users   map[string]BudgetLimit `json:"users,omitempty"`   // key = "tenantID:userID" (e.g. "<uuid>:group:telegram:-100123"); bare userID = master tenant only
+	Notify  []BudgetNotifyTarget   `json:"notify,omitempty"`  // admin chats alerted when a threshold is crossed
+}
+
 // GatewayConfig controls the gateway server.
 type GatewayConfig struct {
 	Host              string       `json:"host"`
@@ -346,9 +405,22 @@ type GatewayConfig struct {
 	InjectionAction   string       `json:"injection_action,omitempty"`    // prompt injection action: "log", "warn" (default), "block", "off"
 	InboundDebounceMs int          `json:"inbound_debounce_ms,omitempty"` // merge rapid messages from same sender (default 1000ms, -1 = disabled)
 	Quota             *QuotaConfig `json:"quota,omitempty"`               // per-user/group request quotas
+	Budget            *BudgetConfig `json:"budget,omitempty"`             // monthly USD spend budgets (agent/tenant/user)
 	BlockReply              *bool        `json:"block_reply,omitempty"`                // deliver intermediate text during tool iterations (default false)
 	ToolStatus              *bool        `json:"tool_status,omitempty"`                // show tool name in streaming preview during tool execution (default true)
 	TaskRecoveryIntervalSec int          `json:"task_recovery_interval_sec,omitempty"` // team task recovery ticker interval in seconds (default 300 = 5min)
+	MessageQueue            *MessageQueueConfig `json:"message_queue,omitempty"`       // durable channel message queue (retries, dead letters, crash replay)
+	PublicURL               string              `json:"public_url,omitempty"`          // externally reachable base URL (OAuth callbacks); default http://localhost:{port}
+}
+
+// MessageQueueConfig configures the durable message queue. When enabled,
+// channel messages and agent replies are persisted in the database and
+// delivered at least once: failed channel sends are retried with backoff and
+// messages in flight during a crash are replayed on the next start.
+type MessageQueueConfig struct {
+	Enabled        bool `json:"enabled,omitempty"`
+	MaxAttempts    int  `json:"max_attempts,omitempty"`    // failed deliveries before dead-lettering (default 5)
+	RetentionHours int  `json:"retention_hours,omitempty"` // keep delivered messages for duplicate detection (default 24)
 }
 
 // ToolsConfig controls tool availability, policy, and web search.
@@ -359,6 +431,7 @@ type ToolsConfig struct {
 	AlsoAllow        []string                    `json:"alsoAllow,omitempty"`  // additive: adds without removing existing
 	ByProvider       map[string]*ToolPolicySpec  `json:"byProvider,omitempty"` // per-provider overrides
 	ExecApproval     ExecApprovalCfg             `json:"execApproval"`         // exec command approval settings
+	Approvals        ToolApprovalCfg             `json:"approvals"`            // human approval for matching tool calls
 	WebFetch         WebFetchPolicyConfig        `json:"web_fetch"`            // domain policy for URL fetching
 	Web              WebToolsConfig              `json:"web"`
 	Browser          BrowserToolConfig           `json:"browser"`
@@ -392,6 +465,38 @@ type ExecApprovalCfg struct {
 	Allowlist []string `json:"allowlist,omitempty"` // glob patterns for allowed commands
 }
 
+// ToolApprovalCfg requires a human decision before matching tool calls run.
+// Requests go to the chat the run came from (when its channel supports
+// approval buttons), to Notify targets and to WS clients; unanswered requests
+// are denied after TimeoutSec.
+type ToolApprovalCfg struct {
+	Rules          []ToolApprovalRule   `json:"rules,omitempty"`
+	TimeoutSec     int                  `json:"timeout_sec,omitempty"`     // default 300
+	Approvers      []string             `json:"approvers,omitempty"`       // sender IDs allowed to decide from channels (default: gateway owners)
+	AllowRequester bool                 `json:"allow_requester,omitempty"` // also let the user whose run made the call decide (default false)
+	Notify         []ToolApprovalTarget `json:"notify,omitempty"`          // extra chats that receive every request (e.g. an owner's DM)
+	NotifyOrigin   *bool                `json:"notify_origin,omitempty"`   // also ask in the originating chat (default true)
+}
+
+// ToolApprovalRule matches tool calls that need approval. Tool is a name or
+// glob ("mcp_github__*"). Args maps argument names to globs that must all
+// match the call's value ("*" spans "/"; a leading "!" negates; {chat_id}
+// and {channel} expand to the current chat); an absent argument never matches.
+// Path arguments are cleaned first, and paths escaping upward ("../x")
+// match every predicate.
+type ToolApprovalRule struct {
+	Tool   string            `json:"tool"`
+	Args   map[string]string `json:"args,omitempty"`
+	Agents []string          `json:"agents,omitempty"` // agent keys the rule applies to (empty = all)
+	Reason string            `json:"reason,omitempty"` // shown to approvers
+}
+
+// ToolApprovalTarget is a chat that receives approval requests.
+type ToolApprovalTarget struct {
+	Channel string `json:"channel"` // channel instance name
+	ChatID  string `json:"chat_id"`
+}
+
 // WebFetchPolicyConfig controls domain filtering for the web_fetch tool.
 type WebFetchPolicyConfig struct {
 	Policy         string   `json:"policy,omitempty"`          // "allow_all" (default), "allowlist"
@@ -489,6 +594,47 @@ type TtsMiniMaxConfig struct {
 	VoiceID string `json:"voice_id,omitempty"` // default "Wise_Woman"
 }
 
+// SttConfig configures speech-to-text for inbound voice notes on all channels.
+// Tenants can override provider and language via the stt.provider /
+// stt.language system configs. A channel's deprecated stt_proxy_url is used
+// as the proxy provider when proxy.url is unset.
+type SttConfig struct {
+	Provider  string           `json:"provider,omitempty"`   // "openai", "gemini", "whisper", "proxy" (default: first configured)
+	Language  string           `json:"language,omitempty"`   // ISO-639-1 hint, e.g. "en" (default: auto-detect)
+	TimeoutMs int              `json:"timeout_ms,omitempty"` // per-call timeout in ms (default 60000)
+	OpenAI    SttOpenAIConfig  `json:"openai"`
+	Gemini    SttGeminiConfig  `json:"gemini"`
+	Whisper   SttWhisperConfig `json:"whisper"`
+	Proxy     SttProxyConfig   `json:"proxy"`
+}
+
+// SttOpenAIConfig configures an OpenAI-compatible /audio/transcriptions endpoint.
+type SttOpenAIConfig struct {
+	APIKey  string `json:"api_key,omitempty"`
+	APIBase string `json:"api_base,omitempty"` // e.g. Groq or a self-hosted faster-whisper-server
+	Model   string `json:"model,omitempty"`    // default "whisper-1"
+}
+
+// SttGeminiConfig configures Gemini audio transcription.
+type SttGeminiConfig struct {
+	APIKey  string `json:"api_key,omitempty"`
+	APIBase string `json:"api_base,omitempty"`
+	Model   string `json:"model,omitempty"` // default "gemini-2.5-flash"
+}
+
+// SttWhisperConfig configures a local whisper.cpp-compatible server (POST /inference).
+type SttWhisperConfig struct {
+	URL    string `json:"url,omitempty"` // e.g. "http://localhost:8080"
+	APIKey string `json:"api_key,omitempty"`
+}
+
+// SttProxyConfig configures the legacy GoClaw STT proxy (POST /transcribe_audio).
+type SttProxyConfig struct {
+	URL      string `json:"url,omitempty"`
+	APIKey   string `json:"api_key,omitempty"`
+	TenantID string `json:"tenant_id,omitempty"`
+}
+
 // MergeChannelGroupQuotas merges per-group quota overrides from channel configs
 // (e.g., channels.telegram.groups[chatID].quota) into gateway.quota.groups.
 // This allows per-group quotas to be set at the channel level and picked up
@@ -124,12 +124,20 @@ func (c *Config) applyEnvOverrides() {
 	envStr("GOCLAW_SLACK_BOT_TOKEN", &c.Channels.Slack.BotToken)
 	envStr("GOCLAW_SLACK_APP_TOKEN", &c.Channels.Slack.AppToken)
 	envStr("GOCLAW_SLACK_USER_TOKEN", &c.Channels.Slack.UserToken)
+	envStr("GOCLAW_EMAIL_PASSWORD", &c.Channels.Email.Password)
 
 	// TTS secrets
 	envStr("GOCLAW_TTS_OPENAI_API_KEY", &c.Tts.OpenAI.APIKey)
 	envStr("GOCLAW_TTS_ELEVENLABS_API_KEY", &c.Tts.ElevenLabs.APIKey)
 	envStr("GOCLAW_TTS_MINIMAX_API_KEY", &c.Tts.MiniMax.APIKey)
 	envStr("GOCLAW_TTS_MINIMAX_GROUP_ID", &c.Tts.MiniMax.GroupID)
+	envStr("GOCLAW_STT_PROVIDER", &c.Stt.Provider)
+	envStr("GOCLAW_STT_LANGUAGE", &c.Stt.Language)
+	envStr("GOCLAW_STT_OPENAI_API_KEY", &c.Stt.OpenAI.APIKey)
+	envStr("GOCLAW_STT_GEMINI_API_KEY", &c.Stt.Gemini.APIKey)
+	envStr("GOCLAW_STT_WHISPER_URL", &c.Stt.Whisper.URL)
+	envStr("GOCLAW_STT_PROXY_URL", &c.Stt.Proxy.URL)
+	envStr("GOCLAW_STT_PROXY_API_KEY", &c.Stt.Proxy.APIKey)
 
 	// Auto-enable channels if credentials are provided via env
 	if c.Channels.Telegram.Token != "" {
@@ -175,6 +183,7 @@ func (c *Config) applyEnvOverrides() {
 
 	// Gateway host/port
 	envStr("GOCLAW_HOST", &c.Gateway.Host)
+	envStr("GOCLAW_PUBLIC_URL", &c.Gateway.PublicURL)
 	if v := os.Getenv("GOCLAW_PORT"); v != "" {
 		if port, err := strconv.Atoi(v); err == nil && port > 0 {
 			c.Gateway.Port = port
@@ -187,6 +196,21 @@ func (c *Config) applyEnvOverrides() {
 	envStr("GOCLAW_STORAGE_BACKEND", &c.Database.StorageBackend)
 	envStr("GOCLAW_SQLITE_PATH", &c.Database.SQLitePath)
 
+	// Cluster
+	if v := os.Getenv("GOCLAW_CLUSTER_ENABLED"); v != "" {
+		c.Cluster.Enabled = v == "true" || v == "1"
+	}
+	envStr("GOCLAW_CLUSTER_NODE_ID", &c.Cluster.NodeID)
+	envStr("GOCLAW_CLUSTER_BACKEND", &c.Cluster.Backend)
+
+	// Durable message queue
+	if v := os.Getenv("GOCLAW_MESSAGE_QUEUE_ENABLED"); v != "" {
+		if c.Gateway.MessageQueue == nil {
+			c.Gateway.MessageQueue = &MessageQueueConfig{}
+		}
+		c.Gateway.MessageQueue.Enabled = v == "true" || v == "1"
+	}
+
 	// Deprecation warning for GOCLAW_MODE (removed — PostgreSQL is always active)
 	if v := os.Getenv("GOCLAW_MODE"); v != "" {
 		slog.Warn("GOCLAW_MODE is deprecated; managed mode is now the only mode", "value", v)
@@ -202,6 +226,10 @@ func (c *Config) applyEnvOverrides() {
 	if v := os.Getenv("GOCLAW_TELEMETRY_INSECURE"); v != "" {
 		c.Telemetry.Insecure = v == "true" || v == "1"
 	}
+	if v := os.Getenv("GOCLAW_METRICS_ENABLED"); v != "" {
+		c.Telemetry.Metrics.Enabled = v == "true" || v == "1"
+	}
+	envStr("GOCLAW_METRICS_TOKEN", &c.Telemetry.Metrics.Token)
 
 	// Owner IDs from env (comma-separated, whitespace-trimmed)
 	if v := os.Getenv("GOCLAW_OWNER_IDS"); v != "" {
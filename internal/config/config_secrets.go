@@ -40,6 +40,7 @@ func (c *Config) MaskedCopy() *Config {
 
 	// Mask gateway token
 	maskNonEmpty(&cp.Gateway.Token)
+	maskNonEmpty(&cp.Telemetry.Metrics.Token)
 
 	// Mask channel secrets
 	maskNonEmpty(&cp.Channels.Telegram.Token)
@@ -52,11 +53,16 @@ func (c *Config) MaskedCopy() *Config {
 	maskNonEmpty(&cp.Channels.Feishu.AppSecret)
 	maskNonEmpty(&cp.Channels.Feishu.EncryptKey)
 	maskNonEmpty(&cp.Channels.Feishu.VerificationToken)
+	maskNonEmpty(&cp.Channels.Email.Password)
 
 	// Mask TTS API keys
 	maskNonEmpty(&cp.Tts.OpenAI.APIKey)
 	maskNonEmpty(&cp.Tts.ElevenLabs.APIKey)
 	maskNonEmpty(&cp.Tts.MiniMax.APIKey)
+	maskNonEmpty(&cp.Stt.OpenAI.APIKey)
+	maskNonEmpty(&cp.Stt.Gemini.APIKey)
+	maskNonEmpty(&cp.Stt.Whisper.APIKey)
+	maskNonEmpty(&cp.Stt.Proxy.APIKey)
 
 	// Mask web tool keys
 	maskNonEmpty(&cp.Tools.Web.Brave.APIKey)
@@ -90,6 +96,7 @@ func (c *Config) StripSecrets() {
 
 	// Gateway token
 	c.Gateway.Token = ""
+	c.Telemetry.Metrics.Token = ""
 
 	// Channel secrets
 	c.Channels.Telegram.Token = ""
@@ -102,11 +109,16 @@ func (c *Config) StripSecrets() {
 	c.Channels.Feishu.AppSecret = ""
 	c.Channels.Feishu.EncryptKey = ""
 	c.Channels.Feishu.VerificationToken = ""
+	c.Channels.Email.Password = ""
 
 	// TTS API keys
 	c.Tts.OpenAI.APIKey = ""
 	c.Tts.ElevenLabs.APIKey = ""
 	c.Tts.MiniMax.APIKey = ""
+	c.Stt.OpenAI.APIKey = ""
+	c.Stt.Gemini.APIKey = ""
+	c.Stt.Whisper.APIKey = ""
+	c.Stt.Proxy.APIKey = ""
 
 	// Web tool keys
 	c.Tools.Web.Brave.APIKey = ""
@@ -145,6 +157,7 @@ func (c *Config) StripMaskedSecrets() {
 
 	// Gateway token
 	stripIfMasked(&c.Gateway.Token)
+	stripIfMasked(&c.Telemetry.Metrics.Token)
 
 	// Channel secrets
 	stripIfMasked(&c.Channels.Telegram.Token)
@@ -157,11 +170,16 @@ func (c *Config) StripMaskedSecrets() {
 	stripIfMasked(&c.Channels.Feishu.AppSecret)
 	stripIfMasked(&c.Channels.Feishu.EncryptKey)
 	stripIfMasked(&c.Channels.Feishu.VerificationToken)
+	stripIfMasked(&c.Channels.Email.Password)
 
 	// TTS API keys
 	stripIfMasked(&c.Tts.OpenAI.APIKey)
 	stripIfMasked(&c.Tts.ElevenLabs.APIKey)
 	stripIfMasked(&c.Tts.MiniMax.APIKey)
+	stripIfMasked(&c.Stt.OpenAI.APIKey)
+	stripIfMasked(&c.Stt.Gemini.APIKey)
+	stripIfMasked(&c.Stt.Whisper.APIKey)
+	stripIfMasked(&c.Stt.Proxy.APIKey)
 
 	// Web tool keys
 	stripIfMasked(&c.Tools.Web.Brave.APIKey)
@@ -185,6 +203,10 @@ func (c *Config) ApplyDBSecrets(secrets map[string]string) {
 	apply("tts.elevenlabs.api_key", &c.Tts.ElevenLabs.APIKey)
 	apply("tts.minimax.api_key", &c.Tts.MiniMax.APIKey)
 	apply("tts.minimax.group_id", &c.Tts.MiniMax.GroupID)
+	apply("stt.openai.api_key", &c.Stt.OpenAI.APIKey)
+	apply("stt.gemini.api_key", &c.Stt.Gemini.APIKey)
+	apply("stt.whisper.api_key", &c.Stt.Whisper.APIKey)
+	apply("stt.proxy.api_key", &c.Stt.Proxy.APIKey)
 	apply("tools.web.brave.api_key", &c.Tools.Web.Brave.APIKey)
 	apply("tailscale.auth_key", &c.Tailscale.AuthKey)
 }
@@ -205,6 +227,10 @@ func (c *Config) ExtractDBSecrets() map[string]string {
 	collect("tts.elevenlabs.api_key", c.Tts.ElevenLabs.APIKey)
 	collect("tts.minimax.api_key", c.Tts.MiniMax.APIKey)
 	collect("tts.minimax.group_id", c.Tts.MiniMax.GroupID)
+	collect("stt.openai.api_key", c.Stt.OpenAI.APIKey)
+	collect("stt.gemini.api_key", c.Stt.Gemini.APIKey)
+	collect("stt.whisper.api_key", c.Stt.Whisper.APIKey)
+	collect("stt.proxy.api_key", c.Stt.Proxy.APIKey)
 	collect("tools.web.brave.api_key", c.Tools.Web.Brave.APIKey)
 	collect("tailscale.auth_key", c.Tailscale.AuthKey)
 
@@ -0,0 +1,243 @@
+// Package contextfiles implements the history, restore and approval flows of
+// agent context files (SOUL.md, IDENTITY.md, ...). The WebSocket RPC methods
+// and the HTTP API both go through Service, so reads, writes and cache
+// invalidation behave the same on either transport.
+package contextfiles
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"github.com/nextlevelbuilder/goclaw/internal/bus"
+	"github.com/nextlevelbuilder/goclaw/internal/store"
+	"github.com/nextlevelbuilder/goclaw/internal/textdiff"
+	"github.com/nextlevelbuilder/goclaw/internal/tracing"
+	"github.com/nextlevelbuilder/goclaw/pkg/protocol"
+)
+
+// maxRevisionScan bounds how far back Previous looks for a revision.
+const maxRevisionScan = 1000
+
+var (
+	// ErrNotFound is returned when a revision or change does not exist or
+	// belongs to another agent.
+	ErrNotFound = errors.New("not found")
+	// ErrNotPending is returned when a change was already approved or rejected.
+	ErrNotPending = errors.New("change is no longer pending")
+)
+
+// Service reads and writes context files and their revisions.
+type Service struct {
+	agents    store.AgentStore
+	revisions store.ContextFileRevisionStore
+	events    bus.EventPublisher // cache invalidation; nil disables
+}
+
+// New creates a Service. events may be nil.
+func New(agents store.AgentStore, revisions store.ContextFileRevisionStore, events bus.EventPublisher) *Service {
+	return &Service{agents: agents, revisions: revisions, events: events}
+}
+
+// History returns the revisions of one file, newest first. userID "" selects
+// the agent-level file.
+func (s *Service) History(ctx context.Context, agentID uuid.UUID, userID, name string, limit int) ([]store.ContextFileRevision, error) {
+	return s.revisions.ListRevisions(ctx, agentID, userID, name, limit)
+}
+
+// Revision returns the revision with the given ID, scoped to ag.
+func (s *Service) Revision(ctx context.Context, ag *store.AgentData, id uuid.UUID) (*store.ContextFileRevision, error) {
+	rev, err := s.revisions.GetRevision(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if rev == nil || rev.AgentID != ag.ID {
+		return nil, ErrNotFound
+	}
+	return rev, nil
+}
+
+// Current returns the live content of an agent-level (userID "") or per-user
+// context file, "" when it does not exist.
+func (s *Service) Current(ctx context.Context, agentID uuid.UUID, userID, name string) (string, error) {
+	if userID == "" {
+		files, err := s.agents.GetAgentContextFiles(ctx, agentID)
+		if err != nil {
+			return "", err
+		}
+		for _, f := range files {
+			if f.FileName == name {
+				return f.Content, nil
+			}
+		}
+		return "", nil
+	}
+	files, err := s.agents.GetUserContextFiles(ctx, agentID, userID)
+	if err != nil {
+		return "", err
+	}
+	for _, f := range files {
+		if f.FileName == name {
+			return f.Content, nil
+		}
+	}
+	return "", nil
+}
+
+// Write stores content as an agent-level or per-user context file and
+// invalidates the agent's cached loop and bootstrap files.
+func (s *Service) Write(ctx context.Context, ag *store.AgentData, userID, name, content string) error {
+	var err error
+	if userID == "" {
+		err = s.agents.SetAgentContextFile(ctx, ag.ID, name, content)
+	} else {
+		err = s.agents.SetUserContextFile(ctx, ag.ID, userID, name, content)
+	}
+	if err != nil {
+		return err
+	}
+	s.invalidate(ag)
+	return nil
+}
+
+// invalidate drops the agent's cached Loop (keyed by agent key) and its
+// cached context files (keyed by agent ID).
+func (s *Service) invalidate(ag *store.AgentData) {
+	if s.events == nil {
+		return
+	}
+	for _, p := range []bus.CacheInvalidatePayload{
+		{Kind: bus.CacheKindAgent, Key: ag.AgentKey},
+		{Kind: bus.CacheKindBootstrap, Key: ag.ID.String()},
+	} {
+		s.events.Broadcast(bus.Event{Name: protocol.EventCacheInvalidate, Payload: p})
+	}
+}
+
+// Restore writes a revision's content back as the current file. The restore
+// itself is recorded as a new revision, attributed to the author in ctx.
+func (s *Service) Restore(ctx context.Context, ag *store.AgentData, rev *store.ContextFileRevision) error {
+	return s.Write(ctx, ag, rev.UserID, rev.FileName, rev.Content)
+}
+
+// Previous returns the revision of the same file written just before rev, nil if none.
+func (s *Service) Previous(ctx context.Context, rev *store.ContextFileRevision) (*store.ContextFileRevision, error) {
+	revs, err := s.revisions.ListRevisions(ctx, rev.AgentID, rev.UserID, rev.FileName, maxRevisionScan)
+	if err != nil {
+		return nil, err
+	}
+	for i, r := range revs {
+		if r.ID == rev.ID && i+1 < len(revs) {
+			return &revs[i+1], nil
+		}
+	}
+	return nil, nil
+}
+
+// Diff is a unified diff with line stats.
+type Diff struct {
+	Unified string
+	Added   int
+	Removed int
+}
+
+func newDiff(fromName, toName, from, to string) Diff {
+	added, removed := textdiff.Stats(textdiff.Lines(from, to))
+	return Diff{Unified: textdiff.Unified(fromName, toName, from, to, 3), Added: added, Removed: removed}
+}
+
+// DiffRevision diffs rev against a base: "current" shows what restoring rev
+// would change, "" uses the previous revision of the same file, and any other
+// value is a revision ID of the same agent (ErrNotFound otherwise).
+func (s *Service) DiffRevision(ctx context.Context, ag *store.AgentData, rev *store.ContextFileRevision, against string) (Diff, error) {
+	toName := fmt.Sprintf("%s (%s)", rev.FileName, rev.ID)
+	var fromName, from string
+	switch against {
+	case "current":
+		cur, err := s.Current(ctx, ag.ID, rev.UserID, rev.FileName)
+		if err != nil {
+			return Diff{}, err
+		}
+		fromName, from = rev.FileName+" (current)", cur
+	case "":
+		prev, err := s.Previous(ctx, rev)
+		if err != nil {
+			return Diff{}, err
+		}
+		fromName = rev.FileName + " (empty)"
+		if prev != nil {
+			fromName, from = fmt.Sprintf("%s (%s)", prev.FileName, prev.ID), prev.Content
+		}
+	default:
+		id, err := uuid.Parse(against)
+		if err != nil {
+			return Diff{}, ErrNotFound
+		}
+		base, err := s.Revision(ctx, ag, id)
+		if err != nil {
+			return Diff{}, err
+		}
+		fromName, from = fmt.Sprintf("%s (%s)", base.FileName, base.ID), base.Content
+	}
+	return newDiff(fromName, toName, from, rev.Content), nil
+}
+
+// PendingChange is a change awaiting approval with its diff against the current file.
+type PendingChange struct {
+	store.ContextFileChange
+	Diff string `json:"diff"`
+}
+
+// PendingChanges returns the agent's pending changes, oldest first.
+func (s *Service) PendingChanges(ctx context.Context, ag *store.AgentData) ([]PendingChange, error) {
+	changes, err := s.revisions.ListPendingChanges(ctx, ag.ID)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]PendingChange, 0, len(changes))
+	for _, c := range changes {
+		cur, err := s.Current(ctx, ag.ID, c.UserID, c.FileName)
+		if err != nil {
+			return nil, err
+		}
+		d := newDiff(c.FileName+" (current)", c.FileName+" (proposed)", cur, c.Content)
+		out = append(out, PendingChange{ContextFileChange: c, Diff: d.Unified})
+	}
+	return out, nil
+}
+
+// ResolveChange approves (applies) or rejects a pending change of ag on
+// behalf of reviewerID. The change is claimed first so concurrent reviewers
+// cannot apply it twice (ErrNotPending). An approved change is written as the
+// agent that proposed it, in its original trace.
+func (s *Service) ResolveChange(ctx context.Context, ag *store.AgentData, changeID uuid.UUID, status, reviewerID string) (*store.ContextFileChange, error) {
+	change, err := s.revisions.GetPendingChange(ctx, changeID)
+	if err != nil {
+		return nil, err
+	}
+	if change == nil || change.AgentID != ag.ID {
+		return nil, ErrNotFound
+	}
+
+	ok, err := s.revisions.ResolvePendingChange(ctx, change.ID, status, reviewerID)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrNotPending
+	}
+
+	if status == store.ContextFileChangeApproved {
+		wctx := store.WithContextFileAuthor(ctx, change.Author, change.AuthorID)
+		if change.TraceID != nil {
+			wctx = tracing.WithTraceID(wctx, *change.TraceID)
+		}
+		if err := s.Write(wctx, ag, change.UserID, change.FileName, change.Content); err != nil {
+			return nil, err
+		}
+	}
+	change.Status = status
+	return change, nil
+}
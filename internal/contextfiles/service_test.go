@@ -0,0 +1,58 @@
+package contextfiles
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/nextlevelbuilder/goclaw/internal/bus"
+	"github.com/nextlevelbuilder/goclaw/internal/store"
+)
+
+type memAgentStore struct {
+	store.AgentStore // unused methods panic
+	files            map[string]string
+}
+
+func (s *memAgentStore) SetAgentContextFile(_ context.Context, _ uuid.UUID, name, content string) error {
+	s.files[name] = content
+	return nil
+}
+
+type recordingEvents struct {
+	bus.EventPublisher // unused methods panic
+	payloads           []bus.CacheInvalidatePayload
+}
+
+func (e *recordingEvents) Broadcast(ev bus.Event) {
+	if p, ok := ev.Payload.(bus.CacheInvalidatePayload); ok {
+		e.payloads = append(e.payloads, p)
+	}
+}
+
+func TestService_WriteInvalidatesAgentAndBootstrap(t *testing.T) {
+	agents := &memAgentStore{files: map[string]string{}}
+	events := &recordingEvents{}
+	s := New(agents, nil, events)
+	ag := &store.AgentData{BaseModel: store.BaseModel{ID: uuid.New()}, AgentKey: "helper"}
+
+	if err := s.Write(context.Background(), ag, "", "SOUL.md", "calm"); err != nil {
+		t.Fatal(err)
+	}
+	if agents.files["SOUL.md"] != "calm" {
+		t.Errorf("SOUL.md = %q, want calm", agents.files["SOUL.md"])
+	}
+	want := []bus.CacheInvalidatePayload{
+		{Kind: bus.CacheKindAgent, Key: "helper"},
+		{Kind: bus.CacheKindBootstrap, Key: ag.ID.String()},
+	}
+	if len(events.payloads) != len(want) {
+		t.Fatalf("invalidations = %+v, want %+v", events.payloads, want)
+	}
+	for i, p := range want {
+		if events.payloads[i] != p {
+			t.Errorf("invalidation %d = %+v, want %+v", i, events.payloads[i], p)
+		}
+	}
+}
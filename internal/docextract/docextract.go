@@ -0,0 +1,248 @@
+// Package docextract turns PDF, DOCX, XLSX and PPTX files into structured
+// markdown locally: pages, headings, tables, sheets with their cell ranges and
+// slides with speaker notes. It has no dependencies beyond the standard library
+// and caches results by content hash.
+package docextract
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/nextlevelbuilder/goclaw/internal/cache"
+)
+
+// Formats.
+const (
+	FormatPDF  = "pdf"
+	FormatDOCX = "docx"
+	FormatXLSX = "xlsx"
+	FormatPPTX = "pptx"
+)
+
+// Section kinds.
+const (
+	SectionPage  = "page"  // PDF page
+	SectionBody  = "body"  // DOCX document body
+	SectionSheet = "sheet" // XLSX worksheet
+	SectionSlide = "slide" // PPTX slide
+)
+
+var (
+	ErrUnsupported = errors.New("unsupported document format")
+	ErrEncrypted   = errors.New("document is encrypted")
+	ErrTooComplex  = errors.New("document expands beyond the extraction limits")
+)
+
+// Document is the extracted content of a file.
+type Document struct {
+	Format   string    `json:"format"`
+	Sections []Section `json:"sections"`
+}
+
+// Section is one page, sheet, slide or document body, as markdown.
+type Section struct {
+	Kind  string `json:"kind"`
+	Index int    `json:"index"`           // 1-based page / sheet / slide number
+	Title string `json:"title,omitempty"` // sheet name or slide title
+	Range string `json:"range,omitempty"` // used cell range of a sheet, e.g. "A1:F120"
+	Text  string `json:"text"`
+	Notes string `json:"notes,omitempty"` // slide speaker notes
+}
+
+// Markdown renders the whole document, one heading per page, sheet or slide.
+func (d *Document) Markdown() string {
+	var sb strings.Builder
+	for i, s := range d.Sections {
+		if i > 0 {
+			sb.WriteString("\n\n")
+		}
+		switch s.Kind {
+		case SectionPage:
+			fmt.Fprintf(&sb, "## Page %d\n\n", s.Index)
+		case SectionSheet:
+			fmt.Fprintf(&sb, "## Sheet: %s", s.Title)
+			if s.Range != "" {
+				fmt.Fprintf(&sb, " (%s)", s.Range)
+			}
+			sb.WriteString("\n\n")
+		case SectionSlide:
+			fmt.Fprintf(&sb, "## Slide %d", s.Index)
+			if s.Title != "" {
+				sb.WriteString(": " + s.Title)
+			}
+			sb.WriteString("\n\n")
+		}
+		sb.WriteString(s.Text)
+		if s.Notes != "" {
+			sb.WriteString("\n\n**Notes:** " + s.Notes)
+		}
+	}
+	return strings.TrimSpace(sb.String())
+}
+
+// TextLen is the number of non-whitespace bytes of extracted text. Scanned
+// PDFs without a text layer have (almost) none.
+func (d *Document) TextLen() int {
+	n := 0
+	for _, s := range d.Sections {
+		for _, text := range []string{s.Text, s.Notes} {
+			n += len(strings.Join(strings.Fields(text), ""))
+		}
+	}
+	return n
+}
+
+// Summary describes the document in a few words, e.g. "pdf, 12 pages".
+func (d *Document) Summary() string {
+	unit := map[string]string{SectionPage: "page", SectionSheet: "sheet", SectionSlide: "slide"}[kindOf(d)]
+	if unit == "" {
+		return d.Format
+	}
+	n := len(d.Sections)
+	if n != 1 {
+		unit += "s"
+	}
+	return fmt.Sprintf("%s, %d %s", d.Format, n, unit)
+}
+
+func kindOf(d *Document) string {
+	if len(d.Sections) == 0 {
+		return ""
+	}
+	return d.Sections[0].Kind
+}
+
+// Detect returns the format of data from its content, or "" if unsupported.
+func Detect(data []byte) string {
+	if bytes.HasPrefix(bytes.TrimLeft(data[:min(len(data), 1024)], "\x00\t\r\n "), []byte("%PDF-")) {
+		return FormatPDF
+	}
+	if bytes.HasPrefix(data, []byte("PK\x03\x04")) {
+		return detectOOXML(data)
+	}
+	return ""
+}
+
+// SupportsMIME reports whether files of this MIME type can be extracted.
+// Legacy binary Office formats (.doc, .xls, .ppt) are detected by content
+// and rejected by Parse.
+func SupportsMIME(mime string) bool {
+	switch mime {
+	case "application/pdf",
+		"application/vnd.openxmlformats-officedocument.wordprocessingml.document",
+		"application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+		"application/vnd.openxmlformats-officedocument.presentationml.presentation":
+		return true
+	}
+	return false
+}
+
+// Parse extracts a document without caching. PDF extraction stops when ctx
+// is done.
+func Parse(ctx context.Context, data []byte) (*Document, error) {
+	switch Detect(data) {
+	case FormatPDF:
+		return parsePDF(ctx, data)
+	case FormatDOCX:
+		return parseDOCX(data)
+	case FormatXLSX:
+		return parseXLSX(data)
+	case FormatPPTX:
+		return parsePPTX(data)
+	}
+	return nil, ErrUnsupported
+}
+
+// cacheVersion is part of the cache key; bump it when parser output changes.
+const cacheVersion = "v1"
+
+// memoryCacheTTL keeps recently extracted documents in memory.
+const memoryCacheTTL = 30 * time.Minute
+
+// Extractor parses documents and caches the results by content hash, in
+// memory and, when a cache directory is set, on disk across restarts.
+type Extractor struct {
+	mem cache.Cache[*Document]
+	dir string
+}
+
+// New creates an extractor. cacheDir may be empty for an in-memory cache only.
+func New(cacheDir string) *Extractor {
+	return &Extractor{
+		mem: cache.Instrumented("document_extract", cache.Cache[*Document](cache.NewInMemoryCache[*Document]())),
+		dir: cacheDir,
+	}
+}
+
+// Extract parses data, returning the cached result for content seen before.
+func (e *Extractor) Extract(ctx context.Context, data []byte) (*Document, error) {
+	if Detect(data) == "" {
+		return nil, ErrUnsupported
+	}
+	sum := sha256.Sum256(data)
+	key := cacheVersion + "-" + hex.EncodeToString(sum[:])
+	if doc, ok := e.mem.Get(ctx, key); ok {
+		return doc, nil
+	}
+	if doc := e.loadDisk(key); doc != nil {
+		e.mem.Set(ctx, key, doc, memoryCacheTTL)
+		return doc, nil
+	}
+
+	start := time.Now()
+	doc, err := Parse(ctx, data)
+	if err != nil {
+		return nil, err
+	}
+	slog.Debug("docextract: parsed", "format", doc.Format, "sections", len(doc.Sections), "bytes", len(data), "took", time.Since(start))
+	e.mem.Set(ctx, key, doc, memoryCacheTTL)
+	e.saveDisk(key, doc)
+	return doc, nil
+}
+
+func (e *Extractor) loadDisk(key string) *Document {
+	if e.dir == "" {
+		return nil
+	}
+	raw, err := os.ReadFile(filepath.Join(e.dir, key+".json"))
+	if err != nil {
+		return nil
+	}
+	var doc Document
+	if json.Unmarshal(raw, &doc) != nil {
+		return nil
+	}
+	return &doc
+}
+
+func (e *Extractor) saveDisk(key string, doc *Document) {
+	if e.dir == "" {
+		return
+	}
+	raw, err := json.Marshal(doc)
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(e.dir, 0o755); err != nil {
+		slog.Warn("docextract: cache dir", "dir", e.dir, "error", err)
+		return
+	}
+	tmp := filepath.Join(e.dir, key+".tmp")
+	if err := os.WriteFile(tmp, raw, 0o644); err != nil {
+		slog.Warn("docextract: cache write", "error", err)
+		return
+	}
+	if err := os.Rename(tmp, filepath.Join(e.dir, key+".json")); err != nil {
+		os.Remove(tmp)
+	}
+}
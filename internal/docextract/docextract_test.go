@@ -0,0 +1,269 @@
+package docextract
+
+import (
+	"archive/zip"
+	"bytes"
+	"compress/zlib"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func zipParts(t *testing.T, parts map[string]string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for name, content := range parts {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w.Write([]byte(content))
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// buildPDF writes the objects in order, compressing streams marked with a
+// leading "flate:" prefix.
+func buildPDF(trailer string, objects ...string) []byte {
+	var buf bytes.Buffer
+	buf.WriteString("%PDF-1.7\n")
+	for i, obj := range objects {
+		fmt.Fprintf(&buf, "%d 0 obj\n", i+1)
+		if dict, content, ok := strings.Cut(obj, "stream:"); ok {
+			var body bytes.Buffer
+			zw := zlib.NewWriter(&body)
+			zw.Write([]byte(content))
+			zw.Close()
+			fmt.Fprintf(&buf, "<< %s /Filter /FlateDecode /Length %d >>\nstream\n", dict, body.Len())
+			buf.Write(body.Bytes())
+			buf.WriteString("\nendstream")
+		} else {
+			buf.WriteString(obj)
+		}
+		buf.WriteString("\nendobj\n")
+	}
+	buf.WriteString("trailer\n" + trailer + "\n%%EOF\n")
+	return buf.Bytes()
+}
+
+const testCMap = `/CIDInit /ProcSet findresource begin
+begincmap
+1 begincodespacerange <0000> <FFFF> endcodespacerange
+1 beginbfchar <0001> <0048> endbfchar
+1 beginbfrange <0002> <0003> <0069> endbfrange
+endcmap`
+
+func testPDF() []byte {
+	return buildPDF("<< /Root 1 0 R /Size 9 >>",
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R 4 0 R] /Count 2 /Resources << /Font << /F1 5 0 R >> >> >>",
+		"<< /Type /Page /Parent 2 0 R /Contents 6 0 R >>",
+		"<< /Type /Page /Parent 2 0 R /Contents [7 0 R] /Resources << /Font << /F2 8 0 R >> >> >>",
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding << /BaseEncoding /WinAnsiEncoding /Differences [1 /fi /quoteright] >> >>",
+		"stream:BT /F1 12 Tf 72 700 Td (Quarterly \\(Q3\\) report) Tj 0 -14 Td [(Pro) 20 (\\001t) -300 (isn\\002t bad)] TJ ET",
+		"stream:BT /F2 12 Tf 72 700 Td <000100020003> Tj ET",
+		"<< /Type /Font /Subtype /Type0 /BaseFont /Custom /Encoding /Identity-H /ToUnicode 9 0 R >>",
+		"stream:"+testCMap,
+	)
+}
+
+func TestParsePDF(t *testing.T) {
+	data := testPDF()
+	if got := Detect(data); got != FormatPDF {
+		t.Fatalf("Detect = %q", got)
+	}
+	doc, err := Parse(context.Background(), data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(doc.Sections) != 2 {
+		t.Fatalf("sections = %+v", doc.Sections)
+	}
+	if want := "Quarterly (Q3) report\nProfit isn’t bad"; doc.Sections[0].Text != want {
+		t.Errorf("page 1 = %q, want %q", doc.Sections[0].Text, want)
+	}
+	if doc.Sections[1].Text != "Hij" {
+		t.Errorf("page 2 = %q, want ToUnicode-mapped %q", doc.Sections[1].Text, "Hij")
+	}
+	if doc.Summary() != "pdf, 2 pages" || !strings.HasPrefix(doc.Markdown(), "## Page 1\n\nQuarterly") {
+		t.Errorf("summary %q, markdown %q", doc.Summary(), doc.Markdown())
+	}
+}
+
+func TestParsePDF_Encrypted(t *testing.T) {
+	data := buildPDF("<< /Root 1 0 R /Encrypt 2 0 R >>",
+		"<< /Type /Catalog /Pages 3 0 R >>",
+		"<< /Filter /Standard /V 2 >>",
+	)
+	if _, err := Parse(context.Background(), data); !errors.Is(err, ErrEncrypted) {
+		t.Fatalf("err = %v, want ErrEncrypted", err)
+	}
+}
+
+func TestParsePDF_ExpansionBudget(t *testing.T) {
+	// A form drawing itself 200 times per level: 200^5 operators from a few
+	// hundred bytes.
+	data := buildPDF("<< /Root 1 0 R >>",
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		"<< /Type /Page /Parent 2 0 R /Contents 5 0 R /Resources << /XObject << /X 4 0 R >> >> >>",
+		"/Subtype /Form /Resources << /XObject << /X 4 0 R >> >> stream:"+strings.Repeat("/X Do ", 200),
+		"stream:/X Do",
+	)
+	if _, err := Parse(context.Background(), data); !errors.Is(err, ErrTooComplex) {
+		t.Fatalf("err = %v, want ErrTooComplex", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := Parse(ctx, testPDF()); !errors.Is(err, context.Canceled) {
+		t.Fatalf("cancelled err = %v, want context.Canceled", err)
+	}
+}
+
+const nsW = `xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"`
+
+func TestParseDOCX(t *testing.T) {
+	data := zipParts(t, map[string]string{
+		"word/styles.xml": `<w:styles ` + nsW + `>
+<w:style w:type="paragraph" w:styleId="berschrift1"><w:name w:val="heading 1"/></w:style>
+</w:styles>`,
+		"word/document.xml": `<w:document ` + nsW + `><w:body>
+<w:p><w:pPr><w:pStyle w:val="berschrift1"/></w:pPr><w:r><w:t>Offer</w:t></w:r></w:p>
+<w:p><w:r><w:t xml:space="preserve">Price is </w:t></w:r><w:del><w:r><w:delText>10</w:delText></w:r></w:del><w:ins><w:r><w:t>12</w:t></w:r></w:ins><w:r><w:t> EUR.</w:t></w:r></w:p>
+<w:p><w:pPr><w:numPr><w:ilvl w:val="1"/><w:numId w:val="1"/></w:numPr></w:pPr><w:r><w:t>nested item</w:t></w:r></w:p>
+<w:tbl>
+<w:tr><w:tc><w:p><w:r><w:t>Item</w:t></w:r></w:p></w:tc><w:tc><w:p><w:r><w:t>Qty</w:t></w:r></w:p></w:tc></w:tr>
+<w:tr><w:tc><w:p><w:r><w:t>A|B</w:t></w:r></w:p></w:tc><w:tc><w:p><w:r><w:t>3</w:t></w:r></w:p></w:tc></w:tr>
+</w:tbl>
+</w:body></w:document>`,
+	})
+	doc, err := Parse(context.Background(), data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "# Offer\n\nPrice is 12 EUR.\n\n  - nested item\n\n| Item | Qty |\n| --- | --- |\n| A\\|B | 3 |"
+	if got := doc.Markdown(); got != want {
+		t.Errorf("markdown =\n%s\nwant\n%s", got, want)
+	}
+}
+
+const nsRel = `xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"`
+
+func TestParseXLSX(t *testing.T) {
+	data := zipParts(t, map[string]string{
+		"xl/workbook.xml": `<workbook ` + nsRel + `><sheets>
+<sheet name="Sales" sheetId="1" r:id="rId2"/><sheet name="Empty" sheetId="2" r:id="rId1"/>
+</sheets></workbook>`,
+		"xl/_rels/workbook.xml.rels": `<Relationships>
+<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet2.xml"/>
+<Relationship Id="rId2" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="/xl/worksheets/sheet1.xml"/>
+</Relationships>`,
+		"xl/sharedStrings.xml": `<sst><si><t>Region</t></si><si><r><t>Da</t></r><r><t>te</t></r></si><si><t>North</t></si></sst>`,
+		"xl/styles.xml":        `<styleSheet><cellXfs><xf numFmtId="0"/><xf numFmtId="14"/></cellXfs></styleSheet>`,
+		"xl/worksheets/sheet1.xml": `<worksheet><sheetData>
+<row r="2"><c r="B2" t="s"><v>0</v></c><c r="C2" t="s"><v>1</v></c><c r="D2" t="inlineStr"><is><t>Won</t></is></c></row>
+<row r="3"><c r="B3" t="s"><v>2</v></c><c r="C3" s="1"><v>45292</v></c><c r="D3" t="b"><v>1</v></c></row>
+</sheetData></worksheet>`,
+		"xl/worksheets/sheet2.xml": `<worksheet><sheetData/></worksheet>`,
+	})
+	doc, err := Parse(context.Background(), data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(doc.Sections) != 2 || doc.Sections[0].Title != "Sales" || doc.Sections[0].Range != "B2:D3" {
+		t.Fatalf("sections = %+v", doc.Sections)
+	}
+	want := "| Region | Date | Won |\n| --- | --- | --- |\n| North | 2024-01-01 | TRUE |"
+	if doc.Sections[0].Text != want {
+		t.Errorf("sheet =\n%s\nwant\n%s", doc.Sections[0].Text, want)
+	}
+	if !strings.HasPrefix(doc.Markdown(), "## Sheet: Sales (B2:D3)") {
+		t.Errorf("markdown = %q", doc.Markdown())
+	}
+}
+
+func TestCellNames(t *testing.T) {
+	for _, name := range []string{"A1", "Z9", "AA10", "BC12", "XFD1048576"} {
+		col, row, ok := parseCellRef(name)
+		if !ok || cellName(col, row) != name {
+			t.Errorf("%s → (%d, %d, %v) → %s", name, col, row, ok, cellName(col, row))
+		}
+	}
+}
+
+const nsP = `xmlns:p="http://schemas.openxmlformats.org/presentationml/2006/main" xmlns:a="http://schemas.openxmlformats.org/drawingml/2006/main"`
+
+func TestParsePPTX(t *testing.T) {
+	slide := func(title, body string) string {
+		return `<p:sld ` + nsP + `><p:cSld><p:spTree>
+<p:sp><p:nvSpPr><p:nvPr><p:ph type="title"/></p:nvPr></p:nvSpPr><p:txBody><a:p><a:r><a:t>` + title + `</a:t></a:r></a:p></p:txBody></p:sp>
+<p:sp><p:txBody>` + body + `</p:txBody></p:sp>
+</p:spTree></p:cSld></p:sld>`
+	}
+	data := zipParts(t, map[string]string{
+		"ppt/presentation.xml": `<p:presentation ` + nsP + ` ` + nsRel + `><p:sldIdLst>
+<p:sldId id="257" r:id="rId3"/><p:sldId id="256" r:id="rId2"/>
+</p:sldIdLst></p:presentation>`,
+		"ppt/_rels/presentation.xml.rels": `<Relationships>
+<Relationship Id="rId2" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/slide" Target="slides/slide1.xml"/>
+<Relationship Id="rId3" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/slide" Target="slides/slide2.xml"/>
+</Relationships>`,
+		"ppt/slides/slide2.xml": slide("Agenda", `<a:p><a:r><a:t>Intro</a:t></a:r></a:p><a:p><a:pPr lvl="1"/><a:r><a:t>Goals</a:t></a:r></a:p>`),
+		"ppt/slides/_rels/slide2.xml.rels": `<Relationships>
+<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/notesSlide" Target="../notesSlides/notesSlide1.xml"/>
+</Relationships>`,
+		"ppt/notesSlides/notesSlide1.xml": `<p:notes ` + nsP + `><p:cSld><p:spTree>
+<p:sp><p:nvSpPr><p:nvPr><p:ph type="sldImg"/></p:nvPr></p:nvSpPr></p:sp>
+<p:sp><p:nvSpPr><p:nvPr><p:ph type="body"/></p:nvPr></p:nvSpPr><p:txBody><a:p><a:r><a:t>Keep it short</a:t></a:r></a:p></p:txBody></p:sp>
+</p:spTree></p:cSld></p:notes>`,
+		"ppt/slides/slide1.xml": slide("Thanks", `<a:p><a:r><a:t>Questions?</a:t></a:r></a:p>`),
+	})
+	doc, err := Parse(context.Background(), data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "## Slide 1: Agenda\n\nIntro\n- Goals\n\n**Notes:** Keep it short\n\n## Slide 2: Thanks\n\nQuestions?"
+	if got := doc.Markdown(); got != want {
+		t.Errorf("markdown =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestExtractor_Cache(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	data := testPDF()
+
+	doc, err := New(dir).Extract(ctx, data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	files, _ := filepath.Glob(filepath.Join(dir, "*.json"))
+	if len(files) != 1 {
+		t.Fatalf("cache files = %v", files)
+	}
+
+	// A fresh extractor must use the disk cache instead of parsing again.
+	doc.Sections[0].Text = "from cache"
+	raw, _ := json.Marshal(doc)
+	if err := os.WriteFile(files[0], raw, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cached, err := New(dir).Extract(ctx, data)
+	if err != nil || cached.Sections[0].Text != "from cache" {
+		t.Fatalf("cached = %+v, %v", cached, err)
+	}
+
+	if _, err := New("").Extract(ctx, []byte("plain text")); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("plain text err = %v", err)
+	}
+}
@@ -0,0 +1,148 @@
+package docextract
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// parseDOCX renders the document body as markdown: headings from paragraph
+// styles or outline levels, list items, and tables.
+func parseDOCX(data []byte) (*Document, error) {
+	pkg, err := openPackage(data)
+	if err != nil {
+		return nil, err
+	}
+	root, err := pkg.xml("word/document.xml")
+	if err != nil {
+		return nil, err
+	}
+	body := root.child("body")
+	if body == nil {
+		return nil, fmt.Errorf("docx: missing body")
+	}
+	d := &docxRenderer{headings: docxHeadingStyles(pkg)}
+	text := joinBlocks(d.blocks(body))
+	return &Document{
+		Format:   FormatDOCX,
+		Sections: []Section{{Kind: SectionBody, Index: 1, Text: text}},
+	}, nil
+}
+
+// docxHeadingStyles maps paragraph style IDs to heading levels (1-6).
+// "Title" counts as level 1.
+func docxHeadingStyles(pkg *ooxmlPackage) map[string]int {
+	out := map[string]int{"Title": 1}
+	for i := 1; i <= 6; i++ {
+		out["Heading"+strconv.Itoa(i)] = i
+	}
+	styles, err := pkg.xml("word/styles.xml")
+	if err != nil || styles == nil {
+		return out
+	}
+	for _, s := range styles.all("style") {
+		if s.attr("type") != "paragraph" {
+			continue
+		}
+		id := s.attr("styleId")
+		name := strings.ToLower(s.child("name").attr("val"))
+		switch {
+		case name == "title":
+			out[id] = 1
+		case strings.HasPrefix(name, "heading "):
+			if n, err := strconv.Atoi(strings.TrimPrefix(name, "heading ")); err == nil && n >= 1 {
+				out[id] = min(n, 6)
+			}
+		default:
+			if lvl := s.path("pPr", "outlineLvl").attr("val"); lvl != "" {
+				if n, err := strconv.Atoi(lvl); err == nil && n < 6 {
+					out[id] = n + 1
+				}
+			}
+		}
+	}
+	return out
+}
+
+type docxRenderer struct {
+	headings map[string]int
+}
+
+// blocks renders the block-level children of a body, table cell or content control.
+func (d *docxRenderer) blocks(parent *xnode) []string {
+	var out []string
+	for _, k := range parent.Kids {
+		switch k.Name {
+		case "p":
+			out = append(out, d.paragraph(k))
+		case "tbl":
+			out = append(out, d.table(k))
+		case "sdt":
+			out = append(out, d.blocks(k.child("sdtContent"))...)
+		}
+	}
+	return out
+}
+
+func (d *docxRenderer) paragraph(p *xnode) string {
+	text := strings.TrimSpace(docxRunText(p))
+	if text == "" {
+		return ""
+	}
+	ppr := p.child("pPr")
+	level := d.headings[ppr.child("pStyle").attr("val")]
+	if lvl := ppr.child("outlineLvl").attr("val"); lvl != "" {
+		if n, err := strconv.Atoi(lvl); err == nil && n < 6 {
+			level = n + 1
+		}
+	}
+	if level > 0 {
+		return strings.Repeat("#", level) + " " + strings.Join(strings.Fields(text), " ")
+	}
+	if num := ppr.child("numPr"); num != nil {
+		indent, _ := strconv.Atoi(num.child("ilvl").attr("val"))
+		return strings.Repeat("  ", indent) + "- " + text
+	}
+	return text
+}
+
+// docxRunText collects the visible text of a paragraph, skipping deleted
+// revisions, field instructions and nested drawings.
+func docxRunText(n *xnode) string {
+	var sb strings.Builder
+	var walk func(*xnode)
+	walk = func(x *xnode) {
+		for _, k := range x.Kids {
+			switch k.Name {
+			case "t":
+				sb.WriteString(k.Text)
+			case "tab":
+				sb.WriteString("\t")
+			case "br", "cr":
+				sb.WriteString("\n")
+			case "del", "delText", "instrText", "pPr", "rPr", "drawing", "pict":
+				// not visible text
+			default:
+				walk(k)
+			}
+		}
+	}
+	walk(n)
+	return sb.String()
+}
+
+func (d *docxRenderer) table(tbl *xnode) string {
+	var rows [][]string
+	for _, tr := range tbl.all("tr") {
+		var row []string
+		for _, tc := range tr.all("tc") {
+			cell := joinBlocks(d.blocks(tc))
+			row = append(row, strings.ReplaceAll(cell, "\n", " "))
+		}
+		rows = append(rows, row)
+	}
+	if len(rows) == 0 {
+		return ""
+	}
+	return markdownTable(rows)
+}
@@ -0,0 +1,271 @@
+package docextract
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+)
+
+// maxPartBytes caps the decompressed size of a single OOXML part (zip bomb guard).
+const maxPartBytes = 64 << 20
+
+// detectOOXML tells DOCX, XLSX and PPTX apart by their main part.
+func detectOOXML(data []byte) string {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return ""
+	}
+	for _, f := range zr.File {
+		switch f.Name {
+		case "word/document.xml":
+			return FormatDOCX
+		case "xl/workbook.xml":
+			return FormatXLSX
+		case "ppt/presentation.xml":
+			return FormatPPTX
+		}
+	}
+	return ""
+}
+
+// ooxmlPackage gives access to the parts of an OOXML zip.
+type ooxmlPackage struct {
+	files map[string]*zip.File
+}
+
+func openPackage(data []byte) (*ooxmlPackage, error) {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("open zip: %w", err)
+	}
+	p := &ooxmlPackage{files: make(map[string]*zip.File, len(zr.File))}
+	for _, f := range zr.File {
+		p.files[f.Name] = f
+	}
+	return p, nil
+}
+
+// read returns a part's bytes, or nil if the part does not exist.
+func (p *ooxmlPackage) read(name string) ([]byte, error) {
+	f, ok := p.files[name]
+	if !ok {
+		return nil, nil
+	}
+	rc, err := f.Open()
+	if err != nil {
+		return nil, fmt.Errorf("open %s: %w", name, err)
+	}
+	defer rc.Close()
+	data, err := io.ReadAll(io.LimitReader(rc, maxPartBytes+1))
+	if err != nil {
+		return nil, fmt.Errorf("read %s: %w", name, err)
+	}
+	if len(data) > maxPartBytes {
+		return nil, fmt.Errorf("%s is too large", name)
+	}
+	return data, nil
+}
+
+// xml parses a part into a node tree; nil if the part does not exist.
+func (p *ooxmlPackage) xml(name string) (*xnode, error) {
+	data, err := p.read(name)
+	if err != nil || data == nil {
+		return nil, err
+	}
+	return parseXML(data)
+}
+
+// rels returns the relationships of a part: id → {type, absolute target}.
+func (p *ooxmlPackage) rels(part string) map[string]relationship {
+	dir, file := path.Split(part)
+	root, err := p.xml(dir + "_rels/" + file + ".rels")
+	if err != nil || root == nil {
+		return nil
+	}
+	out := map[string]relationship{}
+	for _, r := range root.all("Relationship") {
+		target := r.attr("Target")
+		if r.attr("TargetMode") != "External" {
+			if strings.HasPrefix(target, "/") {
+				target = strings.TrimPrefix(target, "/")
+			} else {
+				target = path.Join(dir, target)
+			}
+		}
+		out[r.attr("Id")] = relationship{Type: r.attr("Type"), Target: target}
+	}
+	return out
+}
+
+type relationship struct {
+	Type   string
+	Target string
+}
+
+// --- Minimal XML tree (local names only; OOXML prefixes don't collide for the elements we read) ---
+
+type xnode struct {
+	Name  string
+	Attrs []xml.Attr
+	Kids  []*xnode
+	Text  string // character data directly inside this element
+}
+
+func parseXML(data []byte) (*xnode, error) {
+	dec := xml.NewDecoder(bytes.NewReader(data))
+	dec.Strict = false
+	root := &xnode{}
+	stack := []*xnode{root}
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("parse xml: %w", err)
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			n := &xnode{Name: t.Name.Local, Attrs: t.Attr}
+			top := stack[len(stack)-1]
+			top.Kids = append(top.Kids, n)
+			stack = append(stack, n)
+		case xml.EndElement:
+			if len(stack) > 1 {
+				stack = stack[:len(stack)-1]
+			}
+		case xml.CharData:
+			stack[len(stack)-1].Text += string(t)
+		}
+	}
+	if len(root.Kids) == 0 {
+		return nil, fmt.Errorf("parse xml: empty document")
+	}
+	return root.Kids[0], nil
+}
+
+func (n *xnode) attr(local string) string {
+	if n == nil {
+		return ""
+	}
+	for _, a := range n.Attrs {
+		if a.Name.Local == local {
+			return a.Value
+		}
+	}
+	return ""
+}
+
+// relID returns the r:id attribute, which references a relationship. It is
+// told apart from plain id attributes by its namespace.
+func (n *xnode) relID() string {
+	if n == nil {
+		return ""
+	}
+	for _, a := range n.Attrs {
+		if a.Name.Local == "id" && a.Name.Space != "" {
+			return a.Value
+		}
+	}
+	return ""
+}
+
+// text returns the character data of n; "" for a missing element.
+func (n *xnode) text() string {
+	if n == nil {
+		return ""
+	}
+	return n.Text
+}
+
+// child returns the first direct child with the given name.
+func (n *xnode) child(name string) *xnode {
+	if n == nil {
+		return nil
+	}
+	for _, k := range n.Kids {
+		if k.Name == name {
+			return k
+		}
+	}
+	return nil
+}
+
+// path follows a chain of direct children.
+func (n *xnode) path(names ...string) *xnode {
+	for _, name := range names {
+		n = n.child(name)
+	}
+	return n
+}
+
+// all returns every descendant with the given name, in document order,
+// without descending into matches.
+func (n *xnode) all(name string) []*xnode {
+	var out []*xnode
+	var walk func(*xnode)
+	walk = func(x *xnode) {
+		for _, k := range x.Kids {
+			if k.Name == name {
+				out = append(out, k)
+				continue
+			}
+			walk(k)
+		}
+	}
+	if n != nil {
+		walk(n)
+	}
+	return out
+}
+
+// --- Markdown helpers ---
+
+// markdownTable renders rows as a GitHub table; the first row is the header.
+func markdownTable(rows [][]string) string {
+	width := 0
+	for _, r := range rows {
+		width = max(width, len(r))
+	}
+	if width == 0 {
+		return ""
+	}
+	var sb strings.Builder
+	writeRow := func(r []string) {
+		sb.WriteString("|")
+		for i := range width {
+			cell := ""
+			if i < len(r) {
+				cell = tableCell(r[i])
+			}
+			sb.WriteString(" " + cell + " |")
+		}
+		sb.WriteString("\n")
+	}
+	writeRow(rows[0])
+	sb.WriteString("|" + strings.Repeat(" --- |", width) + "\n")
+	for _, r := range rows[1:] {
+		writeRow(r)
+	}
+	return strings.TrimSuffix(sb.String(), "\n")
+}
+
+func tableCell(s string) string {
+	s = strings.Join(strings.Fields(s), " ")
+	return strings.ReplaceAll(s, "|", `\|`)
+}
+
+// joinBlocks joins non-empty markdown blocks with blank lines.
+func joinBlocks(blocks []string) string {
+	var out []string
+	for _, b := range blocks {
+		if b = strings.TrimRight(b, " \t\n"); strings.TrimSpace(b) != "" {
+			out = append(out, b)
+		}
+	}
+	return strings.Join(out, "\n\n")
+}
@@ -0,0 +1,882 @@
+package docextract
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/zlib"
+	"context"
+	"encoding/ascii85"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// PDF extraction reads the text layer only: objects are found by scanning
+// for "N G obj" rather than trusting the xref table, which also copes with
+// damaged files and incremental updates (later definitions win). Scanned
+// PDFs without text come out (nearly) empty — see Document.TextLen.
+//
+// Small files can expand enormously (one compressed stream shared by every
+// page, forms drawing forms), so decoded streams are cached per file and the
+// whole document shares one budget of decoded bytes and content operators.
+
+const (
+	maxPDFPages        = 2000
+	maxPDFStreamBytes  = 64 << 20
+	maxPDFDecodedBytes = 256 << 20 // all streams of one document
+	maxPDFOperators    = 5_000_000 // content stream operators of one document
+	maxPDFNesting      = 64        // arrays / dicts inside each other
+	maxPDFFormDepth    = 5         // form XObjects drawing other form XObjects
+)
+
+// Value types produced by the lexer.
+type (
+	pdfName    string
+	pdfKeyword string // bare word: operator in content streams, "R"-less keyword elsewhere
+	pdfDict    map[string]any
+	pdfRef     struct{ num, gen int }
+	pdfStream  struct {
+		dict pdfDict
+		raw  []byte
+	}
+)
+
+// pdfString is a literal or hex string; its bytes are character codes
+// whose meaning depends on the current font.
+type pdfString []byte
+
+var errPDFEnd = errors.New("end of data")
+
+func parsePDF(ctx context.Context, data []byte) (*Document, error) {
+	f := &pdfFile{
+		ctx:     ctx,
+		data:    data,
+		objects: map[int]any{},
+		fonts:   map[pdfRef]*pdfFont{},
+		decoded: map[*pdfStream][]byte{},
+	}
+	f.scan()
+	if f.err != nil {
+		return nil, f.err
+	}
+	if f.encrypted {
+		return nil, ErrEncrypted
+	}
+	pages := f.pages()
+	if len(pages) == 0 {
+		return nil, fmt.Errorf("pdf: no pages found")
+	}
+	doc := &Document{Format: FormatPDF}
+	for i, pg := range pages {
+		t := &pdfText{f: f}
+		for _, c := range f.contents(pg.dict["Contents"]) {
+			t.run(c, pg.resources, 0)
+		}
+		if f.err != nil {
+			return nil, f.err
+		}
+		doc.Sections = append(doc.Sections, Section{Kind: SectionPage, Index: i + 1, Text: cleanPDFText(t.sb.String())})
+	}
+	return doc, nil
+}
+
+// --- File structure ---
+
+type pdfFile struct {
+	ctx       context.Context
+	data      []byte
+	objects   map[int]any
+	root      any
+	encrypted bool
+	fonts     map[pdfRef]*pdfFont
+
+	decoded      map[*pdfStream][]byte // streams decoded so far (nil = failed)
+	decodedBytes int
+	operators    int
+	err          error // budget exhausted or context done; stops extraction
+}
+
+// errPDFBudget is returned for documents that expand past the decode or
+// operator budget.
+var errPDFBudget = fmt.Errorf("pdf: %w", ErrTooComplex)
+
+// fail records the error that ends extraction. The first one wins.
+func (f *pdfFile) fail(err error) {
+	if f.err == nil {
+		f.err = err
+	}
+}
+
+// step counts one content operator against the budget and periodically
+// checks for cancellation. It reports whether extraction may go on.
+func (f *pdfFile) step() bool {
+	if f.err != nil {
+		return false
+	}
+	f.operators++
+	if f.operators > maxPDFOperators {
+		f.fail(errPDFBudget)
+		return false
+	}
+	if f.operators%4096 == 0 {
+		if err := f.ctx.Err(); err != nil {
+			f.fail(err)
+			return false
+		}
+	}
+	return true
+}
+
+var pdfObjHeader = regexp.MustCompile(`(\d+)\s+\d+\s+obj\b`)
+
+// scan collects every object definition in file order, expanding object
+// streams, and reads the trailer entries we need (Root, Encrypt).
+func (f *pdfFile) scan() {
+	for pos := 0; pos < len(f.data); {
+		loc := pdfObjHeader.FindSubmatchIndex(f.data[pos:])
+		if loc == nil {
+			break
+		}
+		num, _ := strconv.Atoi(string(f.data[pos+loc[2] : pos+loc[3]]))
+		lx := &pdfLexer{data: f.data, pos: pos + loc[1], refs: true}
+		v, err := lx.value(0)
+		if err != nil {
+			pos += loc[1]
+			continue
+		}
+		if d, ok := v.(pdfDict); ok {
+			if s, ok := lx.stream(d); ok {
+				v = s
+				switch d["Type"] {
+				case pdfName("ObjStm"):
+					f.expandObjStm(s)
+				case pdfName("XRef"):
+					f.mergeTrailer(d)
+				}
+			}
+		}
+		f.objects[num] = v
+		pos = lx.pos
+	}
+	for pos := 0; ; {
+		i := bytes.Index(f.data[pos:], []byte("trailer"))
+		if i < 0 {
+			break
+		}
+		lx := &pdfLexer{data: f.data, pos: pos + i + len("trailer"), refs: true}
+		if v, err := lx.value(0); err == nil {
+			if d, ok := v.(pdfDict); ok {
+				f.mergeTrailer(d)
+			}
+		}
+		pos += i + len("trailer")
+	}
+	if f.root == nil {
+		for _, v := range f.objects {
+			if d, ok := v.(pdfDict); ok && d["Type"] == pdfName("Catalog") {
+				f.root = d
+				break
+			}
+		}
+	}
+}
+
+func (f *pdfFile) mergeTrailer(d pdfDict) {
+	if r, ok := d["Root"]; ok {
+		f.root = r
+	}
+	if _, ok := d["Encrypt"]; ok {
+		f.encrypted = true
+	}
+}
+
+func (f *pdfFile) expandObjStm(s *pdfStream) {
+	data, err := f.decode(s)
+	if err != nil {
+		return
+	}
+	n, _ := f.num(s.dict["N"])
+	first, _ := f.num(s.dict["First"])
+	lx := &pdfLexer{data: data}
+	for range int(n) {
+		num, err1 := lx.value(0)
+		off, err2 := lx.value(0)
+		nv, ok1 := num.(float64)
+		ov, ok2 := off.(float64)
+		if err1 != nil || err2 != nil || !ok1 || !ok2 {
+			return
+		}
+		start := int(first) + int(ov)
+		if start < 0 || start >= len(data) {
+			continue
+		}
+		obj := &pdfLexer{data: data, pos: start, refs: true}
+		if v, err := obj.value(0); err == nil {
+			f.objects[int(nv)] = v
+		}
+	}
+}
+
+func (f *pdfFile) resolve(v any) any {
+	for range 16 {
+		r, ok := v.(pdfRef)
+		if !ok {
+			return v
+		}
+		v = f.objects[r.num]
+	}
+	return nil
+}
+
+// dict resolves v to a dictionary; for a stream, its dictionary.
+func (f *pdfFile) dict(v any) pdfDict {
+	switch x := f.resolve(v).(type) {
+	case pdfDict:
+		return x
+	case *pdfStream:
+		return x.dict
+	}
+	return nil
+}
+
+func (f *pdfFile) num(v any) (float64, bool) {
+	n, ok := f.resolve(v).(float64)
+	return n, ok
+}
+
+func (f *pdfFile) name(v any) string {
+	n, _ := f.resolve(v).(pdfName)
+	return string(n)
+}
+
+// array resolves v to an array; a single value becomes a one-element array.
+func (f *pdfFile) array(v any) []any {
+	switch x := f.resolve(v).(type) {
+	case nil:
+		return nil
+	case []any:
+		return x
+	default:
+		return []any{x}
+	}
+}
+
+// decode applies the stream's filters. Results are cached, so a stream shared
+// by many pages or forms is decoded once, and count against the document's
+// decode budget.
+func (f *pdfFile) decode(s *pdfStream) ([]byte, error) {
+	if data, ok := f.decoded[s]; ok {
+		if data == nil {
+			return nil, errPDFDecode
+		}
+		return data, nil
+	}
+	if f.err != nil {
+		return nil, f.err
+	}
+	if err := f.ctx.Err(); err != nil {
+		f.fail(err)
+		return nil, err
+	}
+	data, err := f.applyFilters(s, min(maxPDFStreamBytes, maxPDFDecodedBytes-f.decodedBytes))
+	if err != nil {
+		f.decoded[s] = nil
+		return nil, err
+	}
+	f.decodedBytes += len(data)
+	if f.decodedBytes >= maxPDFDecodedBytes {
+		f.fail(errPDFBudget)
+		return nil, errPDFBudget
+	}
+	f.decoded[s] = data
+	return data, nil
+}
+
+var errPDFDecode = errors.New("pdf: stream could not be decoded")
+
+// applyFilters decodes the stream, producing at most limit bytes per filter.
+func (f *pdfFile) applyFilters(s *pdfStream, limit int) ([]byte, error) {
+	data := s.raw
+	parms := f.array(s.dict["DecodeParms"])
+	for i, flt := range f.array(s.dict["Filter"]) {
+		if i < len(parms) {
+			if p, _ := f.num(f.dict(parms[i])["Predictor"]); p > 1 {
+				return nil, fmt.Errorf("pdf: predictor %v not supported", p)
+			}
+		}
+		var err error
+		switch f.name(flt) {
+		case "FlateDecode", "Fl":
+			data, err = inflate(data, limit)
+		case "ASCIIHexDecode", "AHx":
+			data, err = decodeASCIIHex(data)
+		case "ASCII85Decode", "A85":
+			data, err = decodeASCII85(data, limit)
+		default:
+			return nil, fmt.Errorf("pdf: filter %s not supported", f.name(flt))
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return data, nil
+}
+
+func inflate(data []byte, limit int) ([]byte, error) {
+	var r io.Reader
+	if zr, err := zlib.NewReader(bytes.NewReader(data)); err == nil {
+		r = zr
+	} else {
+		r = flate.NewReader(bytes.NewReader(data))
+	}
+	out, err := io.ReadAll(io.LimitReader(r, int64(limit)))
+	// Truncated streams are common; keep what was decoded.
+	if err != nil && len(out) == 0 {
+		return nil, fmt.Errorf("pdf: inflate: %w", err)
+	}
+	return out, nil
+}
+
+func decodeASCIIHex(data []byte) ([]byte, error) {
+	if i := bytes.IndexByte(data, '>'); i >= 0 {
+		data = data[:i]
+	}
+	clean := bytes.Map(func(r rune) rune {
+		if isPDFSpace(byte(r)) {
+			return -1
+		}
+		return r
+	}, data)
+	if len(clean)%2 == 1 {
+		clean = append(clean, '0')
+	}
+	out := make([]byte, hex.DecodedLen(len(clean)))
+	_, err := hex.Decode(out, clean)
+	return out, err
+}
+
+func decodeASCII85(data []byte, limit int) ([]byte, error) {
+	data = bytes.TrimPrefix(bytes.TrimSpace(data), []byte("<~"))
+	if i := bytes.Index(data, []byte("~>")); i >= 0 {
+		data = data[:i]
+	}
+	return io.ReadAll(io.LimitReader(ascii85.NewDecoder(bytes.NewReader(data)), int64(limit)))
+}
+
+type pdfPage struct {
+	dict      pdfDict
+	resources pdfDict
+}
+
+// pages walks the page tree in order, resolving inherited resources.
+func (f *pdfFile) pages() []pdfPage {
+	var out []pdfPage
+	seen := map[pdfRef]bool{}
+	var walk func(node any, res pdfDict, depth int)
+	walk = func(node any, res pdfDict, depth int) {
+		if r, ok := node.(pdfRef); ok {
+			if seen[r] {
+				return
+			}
+			seen[r] = true
+		}
+		d := f.dict(node)
+		if d == nil || depth > 32 || len(out) >= maxPDFPages {
+			return
+		}
+		if r := f.dict(d["Resources"]); r != nil {
+			res = r
+		}
+		if kids, ok := f.resolve(d["Kids"]).([]any); ok {
+			for _, k := range kids {
+				walk(k, res, depth+1)
+			}
+			return
+		}
+		if d["Type"] == pdfName("Page") || d["Contents"] != nil {
+			out = append(out, pdfPage{dict: d, resources: res})
+		}
+	}
+	walk(f.dict(f.root)["Pages"], nil, 0)
+	return out
+}
+
+// contents returns the decoded content streams of a page.
+func (f *pdfFile) contents(v any) [][]byte {
+	var out [][]byte
+	for _, c := range f.array(v) {
+		if s, ok := f.resolve(c).(*pdfStream); ok {
+			if data, err := f.decode(s); err == nil {
+				out = append(out, data)
+			}
+		}
+	}
+	return out
+}
+
+// --- Lexer ---
+
+type pdfLexer struct {
+	data []byte
+	pos  int
+	refs bool // recognise "N G R" references (not in content streams)
+}
+
+func isPDFSpace(c byte) bool {
+	return c == ' ' || c == '\n' || c == '\r' || c == '\t' || c == '\f' || c == 0
+}
+
+func isPDFDelim(c byte) bool {
+	return strings.IndexByte("()<>[]{}/%", c) >= 0
+}
+
+func (lx *pdfLexer) skipSpace() {
+	for lx.pos < len(lx.data) {
+		c := lx.data[lx.pos]
+		switch {
+		case isPDFSpace(c):
+			lx.pos++
+		case c == '%':
+			for lx.pos < len(lx.data) && lx.data[lx.pos] != '\n' && lx.data[lx.pos] != '\r' {
+				lx.pos++
+			}
+		default:
+			return
+		}
+	}
+}
+
+// value reads the next object. Closing "]" and ">>" come back as keywords.
+func (lx *pdfLexer) value(depth int) (any, error) {
+	if depth > maxPDFNesting {
+		return nil, errors.New("pdf: nesting too deep")
+	}
+	lx.skipSpace()
+	if lx.pos >= len(lx.data) {
+		return nil, errPDFEnd
+	}
+	c := lx.data[lx.pos]
+	switch {
+	case c == '/':
+		lx.pos++
+		return pdfName(lx.name()), nil
+	case c == '(':
+		lx.pos++
+		return lx.literal(), nil
+	case c == '<' && lx.peek(1) == '<':
+		lx.pos += 2
+		return lx.dictBody(depth)
+	case c == '<':
+		lx.pos++
+		return lx.hexString(), nil
+	case c == '>' && lx.peek(1) == '>':
+		lx.pos += 2
+		return pdfKeyword(">>"), nil
+	case c == '[':
+		lx.pos++
+		return lx.arrayBody(depth)
+	case c == ']' || c == '{' || c == '}' || c == ')' || c == '>':
+		lx.pos++
+		return pdfKeyword([]byte{c}), nil
+	case c == '+' || c == '-' || c == '.' || (c >= '0' && c <= '9'):
+		return lx.number(), nil
+	}
+	start := lx.pos
+	for lx.pos < len(lx.data) && !isPDFSpace(lx.data[lx.pos]) && !isPDFDelim(lx.data[lx.pos]) {
+		lx.pos++
+	}
+	switch word := string(lx.data[start:lx.pos]); word {
+	case "true":
+		return true, nil
+	case "false":
+		return false, nil
+	case "null":
+		return nil, nil
+	default:
+		return pdfKeyword(word), nil
+	}
+}
+
+func (lx *pdfLexer) peek(n int) byte {
+	if lx.pos+n < len(lx.data) {
+		return lx.data[lx.pos+n]
+	}
+	return 0
+}
+
+func (lx *pdfLexer) name() string {
+	var sb strings.Builder
+	for lx.pos < len(lx.data) {
+		c := lx.data[lx.pos]
+		if isPDFSpace(c) || isPDFDelim(c) {
+			break
+		}
+		if c == '#' && lx.pos+2 < len(lx.data) {
+			if b, err := hex.DecodeString(string(lx.data[lx.pos+1 : lx.pos+3])); err == nil {
+				sb.WriteByte(b[0])
+				lx.pos += 3
+				continue
+			}
+		}
+		sb.WriteByte(c)
+		lx.pos++
+	}
+	return sb.String()
+}
+
+func (lx *pdfLexer) number() any {
+	start := lx.pos
+	for lx.pos < len(lx.data) && strings.IndexByte("+-.0123456789", lx.data[lx.pos]) >= 0 {
+		lx.pos++
+	}
+	n, _ := strconv.ParseFloat(string(lx.data[start:lx.pos]), 64)
+	if !lx.refs || n != float64(int(n)) || n < 0 {
+		return n
+	}
+	// "num gen R"
+	save := lx.pos
+	lx.skipSpace()
+	genStart := lx.pos
+	for lx.pos < len(lx.data) && lx.data[lx.pos] >= '0' && lx.data[lx.pos] <= '9' {
+		lx.pos++
+	}
+	if lx.pos > genStart {
+		gen, _ := strconv.Atoi(string(lx.data[genStart:lx.pos]))
+		lx.skipSpace()
+		if lx.peek(0) == 'R' && (lx.pos+1 >= len(lx.data) || isPDFSpace(lx.peek(1)) || isPDFDelim(lx.peek(1))) {
+			lx.pos++
+			return pdfRef{num: int(n), gen: gen}
+		}
+	}
+	lx.pos = save
+	return n
+}
+
+func (lx *pdfLexer) literal() pdfString {
+	var out []byte
+	depth := 1
+	for lx.pos < len(lx.data) {
+		c := lx.data[lx.pos]
+		lx.pos++
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			if depth--; depth == 0 {
+				return out
+			}
+		case '\\':
+			if lx.pos >= len(lx.data) {
+				return out
+			}
+			e := lx.data[lx.pos]
+			lx.pos++
+			switch e {
+			case 'n':
+				c = '\n'
+			case 'r':
+				c = '\r'
+			case 't':
+				c = '\t'
+			case 'b':
+				c = '\b'
+			case 'f':
+				c = '\f'
+			case '\r':
+				if lx.peek(0) == '\n' {
+					lx.pos++
+				}
+				continue
+			case '\n':
+				continue
+			default:
+				if e >= '0' && e <= '7' {
+					v := int(e - '0')
+					for range 2 {
+						if d := lx.peek(0); d >= '0' && d <= '7' {
+							v = v*8 + int(d-'0')
+							lx.pos++
+						}
+					}
+					c = byte(v)
+				} else {
+					c = e
+				}
+			}
+		}
+		out = append(out, c)
+	}
+	return out
+}
+
+func (lx *pdfLexer) hexString() pdfString {
+	start := lx.pos
+	for lx.pos < len(lx.data) && lx.data[lx.pos] != '>' {
+		lx.pos++
+	}
+	out, _ := decodeASCIIHex(lx.data[start:lx.pos])
+	lx.pos++
+	return out
+}
+
+func (lx *pdfLexer) arrayBody(depth int) ([]any, error) {
+	out := []any{}
+	for {
+		v, err := lx.value(depth + 1)
+		if err != nil {
+			return out, err
+		}
+		if v == pdfKeyword("]") {
+			return out, nil
+		}
+		out = append(out, v)
+	}
+}
+
+func (lx *pdfLexer) dictBody(depth int) (pdfDict, error) {
+	out := pdfDict{}
+	for {
+		k, err := lx.value(depth + 1)
+		if err != nil {
+			return out, err
+		}
+		if k == pdfKeyword(">>") {
+			return out, nil
+		}
+		key, ok := k.(pdfName)
+		if !ok {
+			continue
+		}
+		v, err := lx.value(depth + 1)
+		if err != nil {
+			return out, err
+		}
+		if v == pdfKeyword(">>") {
+			return out, nil
+		}
+		out[string(key)] = v
+	}
+}
+
+// stream reads the stream body following a dictionary, if there is one.
+func (lx *pdfLexer) stream(d pdfDict) (*pdfStream, bool) {
+	save := lx.pos
+	lx.skipSpace()
+	if !bytes.HasPrefix(lx.data[lx.pos:], []byte("stream")) {
+		lx.pos = save
+		return nil, false
+	}
+	start := lx.pos + len("stream")
+	if lx.peekAt(start) == '\r' {
+		start++
+	}
+	if lx.peekAt(start) == '\n' {
+		start++
+	}
+	endstream := []byte("endstream")
+	if n, ok := d["Length"].(float64); ok && n >= 0 && start+int(n) <= len(lx.data) {
+		end := start + int(n)
+		rest := lx.data[end:min(end+64, len(lx.data))]
+		trimmed := bytes.TrimLeft(rest, "\r\n\t \x00")
+		if bytes.HasPrefix(trimmed, endstream) {
+			lx.pos = end + (len(rest) - len(trimmed)) + len(endstream)
+			return &pdfStream{dict: d, raw: lx.data[start:end]}, true
+		}
+	}
+	i := bytes.Index(lx.data[start:], endstream)
+	if i < 0 {
+		lx.pos = save
+		return nil, false
+	}
+	end := start + i
+	if end > start && lx.data[end-1] == '\n' {
+		end--
+	}
+	if end > start && lx.data[end-1] == '\r' {
+		end--
+	}
+	lx.pos = start + i + len(endstream)
+	return &pdfStream{dict: d, raw: lx.data[start:end]}, true
+}
+
+func (lx *pdfLexer) peekAt(i int) byte {
+	if i < len(lx.data) {
+		return lx.data[i]
+	}
+	return 0
+}
+
+// skipInlineImage moves past "ID <binary data> EI" after a BI operator.
+func (lx *pdfLexer) skipInlineImage() {
+	i := bytes.Index(lx.data[lx.pos:], []byte("ID"))
+	if i < 0 {
+		lx.pos = len(lx.data)
+		return
+	}
+	for p := lx.pos + i + 2; p < len(lx.data); {
+		j := bytes.Index(lx.data[p:], []byte("EI"))
+		if j < 0 {
+			break
+		}
+		at := p + j
+		if isPDFSpace(lx.peekAt(at-1)) && (at+2 >= len(lx.data) || isPDFSpace(lx.data[at+2])) {
+			lx.pos = at + 2
+			return
+		}
+		p = at + 2
+	}
+	lx.pos = len(lx.data)
+}
+
+// --- Content streams ---
+
+type pdfText struct {
+	f     *pdfFile
+	sb    strings.Builder
+	lineY float64
+}
+
+func (t *pdfText) last() byte {
+	if t.sb.Len() == 0 {
+		return 0
+	}
+	return t.sb.String()[t.sb.Len()-1]
+}
+
+func (t *pdfText) newline() {
+	if c := t.last(); c != 0 && c != '\n' {
+		t.sb.WriteByte('\n')
+	}
+}
+
+func (t *pdfText) space() {
+	if c := t.last(); c != 0 && c != ' ' && c != '\n' {
+		t.sb.WriteByte(' ')
+	}
+}
+
+// run interprets the text operators of a content stream.
+func (t *pdfText) run(content []byte, res pdfDict, depth int) {
+	fonts := t.f.dict(res["Font"])
+	xobjects := t.f.dict(res["XObject"])
+	var font *pdfFont
+	var operands []any
+	lx := &pdfLexer{data: content}
+	for {
+		v, err := lx.value(0)
+		if err != nil {
+			return
+		}
+		op, ok := v.(pdfKeyword)
+		if !ok {
+			if len(operands) < 64 {
+				operands = append(operands, v)
+			}
+			continue
+		}
+		if !t.f.step() {
+			return
+		}
+		switch op {
+		case "Tf":
+			if len(operands) >= 1 {
+				if n, ok := operands[0].(pdfName); ok {
+					font = t.f.font(fonts[string(n)])
+				}
+			}
+		case "Tj":
+			t.show(font, operands, 0)
+		case "'":
+			t.newline()
+			t.show(font, operands, 0)
+		case "\"":
+			t.newline()
+			t.show(font, operands, 2)
+		case "TJ":
+			if len(operands) > 0 {
+				arr, _ := operands[0].([]any)
+				for _, el := range arr {
+					switch x := el.(type) {
+					case pdfString:
+						t.sb.WriteString(font.decode(x))
+					case float64:
+						if x < -200 {
+							t.space()
+						}
+					}
+				}
+			}
+		case "Td", "TD":
+			if len(operands) >= 2 {
+				tx, _ := operands[0].(float64)
+				ty, _ := operands[1].(float64)
+				if ty != 0 {
+					t.newline()
+				} else if tx != 0 {
+					t.space()
+				}
+			}
+		case "Tm":
+			if len(operands) >= 6 {
+				y, _ := operands[5].(float64)
+				if y != t.lineY {
+					t.newline()
+				} else {
+					t.space()
+				}
+				t.lineY = y
+			}
+		case "T*":
+			t.newline()
+		case "ET":
+			t.space()
+		case "Do":
+			if len(operands) >= 1 && depth < maxPDFFormDepth {
+				n, _ := operands[0].(pdfName)
+				if s, ok := t.f.resolve(xobjects[string(n)]).(*pdfStream); ok && s.dict["Subtype"] == pdfName("Form") {
+					if data, err := t.f.decode(s); err == nil {
+						formRes := t.f.dict(s.dict["Resources"])
+						if formRes == nil {
+							formRes = res
+						}
+						t.run(data, formRes, depth+1)
+					}
+				}
+			}
+		case "BI":
+			lx.skipInlineImage()
+		}
+		operands = operands[:0]
+	}
+}
+
+func (t *pdfText) show(font *pdfFont, operands []any, i int) {
+	if i < len(operands) {
+		if s, ok := operands[i].(pdfString); ok {
+			t.sb.WriteString(font.decode(s))
+		}
+	}
+}
+
+// cleanPDFText collapses runs of spaces and blank lines.
+func cleanPDFText(s string) string {
+	var lines []string
+	blank := false
+	for _, line := range strings.Split(s, "\n") {
+		line = strings.Join(strings.Fields(line), " ")
+		if line == "" {
+			if !blank && len(lines) > 0 {
+				lines = append(lines, "")
+			}
+			blank = true
+			continue
+		}
+		blank = false
+		lines = append(lines, line)
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
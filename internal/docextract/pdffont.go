@@ -0,0 +1,350 @@
+package docextract
+
+import (
+	"strconv"
+	"strings"
+	"unicode/utf16"
+)
+
+// pdfFont maps the character codes of a font to text: through its
+// ToUnicode CMap when present, else through its simple-font encoding.
+type pdfFont struct {
+	cmap      *pdfCMap
+	composite bool // Type0: multi-byte codes, no fallback encoding
+	enc       [256]string
+}
+
+func (f *pdfFile) font(v any) *pdfFont {
+	ref, isRef := v.(pdfRef)
+	if isRef {
+		if ft, ok := f.fonts[ref]; ok {
+			return ft
+		}
+	}
+	d := f.dict(v)
+	if d == nil {
+		return nil
+	}
+	ft := &pdfFont{composite: f.name(d["Subtype"]) == "Type0", enc: winAnsiEncoding}
+	switch enc := f.resolve(d["Encoding"]).(type) {
+	case pdfName:
+		if enc == "MacRomanEncoding" {
+			ft.enc = macRomanEncoding
+		}
+	case pdfDict:
+		if f.name(enc["BaseEncoding"]) == "MacRomanEncoding" {
+			ft.enc = macRomanEncoding
+		}
+		code := 0
+		for _, el := range f.array(enc["Differences"]) {
+			switch x := f.resolve(el).(type) {
+			case float64:
+				code = int(x)
+			case pdfName:
+				if code >= 0 && code < 256 {
+					ft.enc[code] = glyphText(string(x))
+				}
+				code++
+			}
+		}
+	}
+	if s, ok := f.resolve(d["ToUnicode"]).(*pdfStream); ok {
+		if data, err := f.decode(s); err == nil {
+			ft.cmap = parseCMap(data)
+		}
+	}
+	if isRef {
+		f.fonts[ref] = ft
+	}
+	return ft
+}
+
+// decode turns a string operand into text. A nil font (no Tf yet, or a
+// missing font resource) decodes as WinAnsi.
+func (ft *pdfFont) decode(s pdfString) string {
+	if ft == nil {
+		ft = &pdfFont{enc: winAnsiEncoding}
+	}
+	var sb strings.Builder
+	for i := 0; i < len(s); {
+		n := ft.codeLen(s[i:])
+		code := s[i : i+n]
+		i += n
+		if ft.cmap != nil {
+			if text, ok := ft.cmap.lookup(code); ok {
+				sb.WriteString(text)
+				continue
+			}
+		}
+		if !ft.composite && n == 1 {
+			sb.WriteString(ft.enc[code[0]])
+		}
+	}
+	return sb.String()
+}
+
+func (ft *pdfFont) codeLen(b []byte) int {
+	if ft.cmap != nil {
+		for _, cs := range ft.cmap.spaces {
+			if len(cs.lo) <= len(b) && inCodeSpace(b[:len(cs.lo)], cs) {
+				return len(cs.lo)
+			}
+		}
+	}
+	if ft.composite && len(b) >= 2 {
+		return 2
+	}
+	return 1
+}
+
+func inCodeSpace(b []byte, cs pdfCodeSpace) bool {
+	for i := range b {
+		if b[i] < cs.lo[i] || b[i] > cs.hi[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// --- ToUnicode CMaps ---
+
+type pdfCodeSpace struct{ lo, hi []byte }
+
+type pdfCMapRange struct {
+	lo, hi uint32
+	n      int      // code length in bytes
+	base   []uint16 // destination of lo; later codes increment the last unit
+	list   []string // or one destination per code
+}
+
+type pdfCMap struct {
+	spaces []pdfCodeSpace
+	chars  map[string]string
+	ranges []pdfCMapRange
+}
+
+func parseCMap(data []byte) *pdfCMap {
+	cm := &pdfCMap{chars: map[string]string{}}
+	lx := &pdfLexer{data: data}
+	var operands []any
+	strs := func() []pdfString {
+		var out []pdfString
+		for _, o := range operands {
+			if s, ok := o.(pdfString); ok {
+				out = append(out, s)
+			}
+		}
+		return out
+	}
+	for {
+		v, err := lx.value(0)
+		if err != nil {
+			break
+		}
+		op, ok := v.(pdfKeyword)
+		if !ok {
+			operands = append(operands, v)
+			continue
+		}
+		switch op {
+		case "endcodespacerange":
+			s := strs()
+			for i := 0; i+1 < len(s); i += 2 {
+				if len(s[i]) == len(s[i+1]) && len(s[i]) > 0 {
+					cm.spaces = append(cm.spaces, pdfCodeSpace{lo: s[i], hi: s[i+1]})
+				}
+			}
+		case "endbfchar":
+			s := strs()
+			for i := 0; i+1 < len(s); i += 2 {
+				cm.chars[string(s[i])] = utf16BE(s[i+1])
+			}
+		case "endbfrange":
+			for i := 0; i+2 < len(operands); i += 3 {
+				lo, ok1 := operands[i].(pdfString)
+				hi, ok2 := operands[i+1].(pdfString)
+				if !ok1 || !ok2 || len(lo) != len(hi) || len(lo) == 0 || len(lo) > 4 {
+					continue
+				}
+				r := pdfCMapRange{lo: codeValue(lo), hi: codeValue(hi), n: len(lo)}
+				if r.hi < r.lo || r.hi-r.lo > 0xFFFF {
+					continue
+				}
+				switch dst := operands[i+2].(type) {
+				case pdfString:
+					r.base = utf16Units(dst)
+				case []any:
+					for _, d := range dst {
+						s, _ := d.(pdfString)
+						r.list = append(r.list, utf16BE(s))
+					}
+				}
+				cm.ranges = append(cm.ranges, r)
+			}
+		}
+		operands = operands[:0]
+	}
+	return cm
+}
+
+func (cm *pdfCMap) lookup(code []byte) (string, bool) {
+	if s, ok := cm.chars[string(code)]; ok {
+		return s, true
+	}
+	v := codeValue(code)
+	for _, r := range cm.ranges {
+		if r.n != len(code) || v < r.lo || v > r.hi {
+			continue
+		}
+		off := v - r.lo
+		if r.list != nil {
+			if int(off) < len(r.list) {
+				return r.list[off], true
+			}
+			return "", false
+		}
+		if len(r.base) == 0 {
+			return "", false
+		}
+		units := append([]uint16(nil), r.base...)
+		units[len(units)-1] += uint16(off)
+		return string(utf16.Decode(units)), true
+	}
+	return "", false
+}
+
+func codeValue(b []byte) uint32 {
+	var v uint32
+	for _, c := range b {
+		v = v<<8 | uint32(c)
+	}
+	return v
+}
+
+func utf16Units(b []byte) []uint16 {
+	if len(b)%2 == 1 {
+		b = append([]byte{0}, b...)
+	}
+	units := make([]uint16, len(b)/2)
+	for i := range units {
+		units[i] = uint16(b[2*i])<<8 | uint16(b[2*i+1])
+	}
+	return units
+}
+
+func utf16BE(b []byte) string {
+	return string(utf16.Decode(utf16Units(b)))
+}
+
+// --- Simple-font encodings ---
+
+var (
+	winAnsiEncoding  = buildEncoding(cp1252High, true)
+	macRomanEncoding = buildEncoding(macRomanHigh, false)
+)
+
+// cp1252High is WinAnsiEncoding for 0x80-0x9F; above that it matches Latin-1.
+var cp1252High = map[byte]rune{
+	0x80: '€', 0x82: '‚', 0x83: 'ƒ', 0x84: '„', 0x85: '…', 0x86: '†', 0x87: '‡',
+	0x88: 'ˆ', 0x89: '‰', 0x8A: 'Š', 0x8B: '‹', 0x8C: 'Œ', 0x8E: 'Ž',
+	0x91: '‘', 0x92: '’', 0x93: '“', 0x94: '”', 0x95: '•', 0x96: '–', 0x97: '—',
+	0x98: '˜', 0x99: '™', 0x9A: 'š', 0x9B: '›', 0x9C: 'œ', 0x9E: 'ž', 0x9F: 'Ÿ',
+}
+
+// macRomanHigh covers the common MacRoman characters above 0x7F.
+var macRomanHigh = map[byte]rune{
+	0x80: 'Ä', 0x81: 'Å', 0x82: 'Ç', 0x83: 'É', 0x84: 'Ñ', 0x85: 'Ö', 0x86: 'Ü',
+	0x87: 'á', 0x88: 'à', 0x89: 'â', 0x8A: 'ä', 0x8B: 'ã', 0x8C: 'å', 0x8D: 'ç',
+	0x8E: 'é', 0x8F: 'è', 0x90: 'ê', 0x91: 'ë', 0x92: 'í', 0x93: 'ì', 0x94: 'î',
+	0x95: 'ï', 0x96: 'ñ', 0x97: 'ó', 0x98: 'ò', 0x99: 'ô', 0x9A: 'ö', 0x9B: 'õ',
+	0x9C: 'ú', 0x9D: 'ù', 0x9E: 'û', 0x9F: 'ü', 0xA0: '†', 0xA1: '°', 0xA5: '•',
+	0xA7: 'ß', 0xA8: '®', 0xA9: '©', 0xAA: '™', 0xCA: ' ', 0xD0: '–', 0xD1: '—',
+	0xD2: '“', 0xD3: '”', 0xD4: '‘', 0xD5: '’', 0xC9: '…', 0xDE: 'ﬁ', 0xDF: 'ﬂ',
+}
+
+func buildEncoding(high map[byte]rune, latin1 bool) [256]string {
+	var enc [256]string
+	for c := 0x20; c < 0x7F; c++ {
+		enc[c] = string(rune(c))
+	}
+	enc['\t'], enc['\n'], enc['\r'] = " ", "\n", "\n"
+	for c := 0xA0; latin1 && c <= 0xFF; c++ {
+		enc[c] = string(rune(c))
+	}
+	for c, r := range high {
+		enc[c] = string(r)
+	}
+	return enc
+}
+
+// glyphNames maps the Adobe glyph names found in Differences arrays that
+// are not single letters.
+var glyphNames = map[string]string{
+	"space": " ", "exclam": "!", "quotedbl": "\"", "numbersign": "#", "dollar": "$",
+	"percent": "%", "ampersand": "&", "quotesingle": "'", "parenleft": "(", "parenright": ")",
+	"asterisk": "*", "plus": "+", "comma": ",", "hyphen": "-", "period": ".", "slash": "/",
+	"zero": "0", "one": "1", "two": "2", "three": "3", "four": "4", "five": "5", "six": "6",
+	"seven": "7", "eight": "8", "nine": "9", "colon": ":", "semicolon": ";", "less": "<",
+	"equal": "=", "greater": ">", "question": "?", "at": "@", "bracketleft": "[",
+	"backslash": "\\", "bracketright": "]", "asciicircum": "^", "underscore": "_", "grave": "`",
+	"braceleft": "{", "bar": "|", "braceright": "}", "asciitilde": "~",
+	"quoteleft": "‘", "quoteright": "’", "quotedblleft": "“", "quotedblright": "”",
+	"quotesinglbase": "‚", "quotedblbase": "„", "endash": "–", "emdash": "—", "bullet": "•",
+	"ellipsis": "…", "minus": "−", "periodcentered": "·", "copyright": "©", "registered": "®",
+	"trademark": "™", "degree": "°", "Euro": "€", "euro": "€", "nbspace": " ", "section": "§",
+	"paragraph": "¶", "dagger": "†", "daggerdbl": "‡", "germandbls": "ß", "sterling": "£",
+	"yen": "¥", "cent": "¢", "multiply": "×", "divide": "÷", "plusminus": "±",
+	"fi": "fi", "fl": "fl", "ff": "ff", "ffi": "ffi", "ffl": "ffl",
+	"ae": "æ", "AE": "Æ", "oe": "œ", "OE": "Œ", "oslash": "ø", "Oslash": "Ø",
+}
+
+// glyphAccents are suffixes of accented letter names ("eacute"), rendered
+// as the base letter plus a combining mark.
+var glyphAccents = map[string]string{
+	"acute": "\u0301", "grave": "\u0300", "circumflex": "\u0302", "tilde": "\u0303",
+	"dieresis": "\u0308", "ring": "\u030A", "cedilla": "\u0327", "caron": "\u030C",
+}
+
+// glyphText maps a glyph name to text: known names, single letters,
+// accented letters, uniXXXX / uXXXX[XX] names, and "f_f"-style ligatures.
+// Suffixes like ".sc" or ".alt" are ignored.
+func glyphText(name string) string {
+	if i := strings.IndexByte(name, '.'); i > 0 {
+		name = name[:i]
+	}
+	if s, ok := glyphNames[name]; ok {
+		return s
+	}
+	if len(name) == 1 {
+		return name
+	}
+	if strings.Contains(name, "_") {
+		var sb strings.Builder
+		for _, part := range strings.Split(name, "_") {
+			sb.WriteString(glyphText(part))
+		}
+		return sb.String()
+	}
+	if strings.HasPrefix(name, "uni") && len(name) >= 7 && (len(name)-3)%4 == 0 {
+		var units []uint16
+		for i := 3; i < len(name); i += 4 {
+			v, err := strconv.ParseUint(name[i:i+4], 16, 16)
+			if err != nil {
+				return ""
+			}
+			units = append(units, uint16(v))
+		}
+		return string(utf16.Decode(units))
+	}
+	if strings.HasPrefix(name, "u") && len(name) >= 5 && len(name) <= 7 {
+		if v, err := strconv.ParseUint(name[1:], 16, 32); err == nil {
+			return string(rune(v))
+		}
+	}
+	for suffix, mark := range glyphAccents {
+		if base, ok := strings.CutSuffix(name, suffix); ok && len(base) == 1 {
+			return base + mark
+		}
+	}
+	return ""
+}
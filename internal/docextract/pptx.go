@@ -0,0 +1,159 @@
+package docextract
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	relSlide      = "/slide"
+	relNotesSlide = "/notesSlide"
+)
+
+// parsePPTX renders slides in presentation order: title, text shapes as
+// paragraphs or bullets, tables, and the speaker notes.
+func parsePPTX(data []byte) (*Document, error) {
+	pkg, err := openPackage(data)
+	if err != nil {
+		return nil, err
+	}
+	pres, err := pkg.xml("ppt/presentation.xml")
+	if err != nil {
+		return nil, err
+	}
+	if pres == nil {
+		return nil, fmt.Errorf("pptx: missing presentation")
+	}
+	rels := pkg.rels("ppt/presentation.xml")
+	doc := &Document{Format: FormatPPTX}
+	for _, id := range pres.path("sldIdLst").all("sldId") {
+		rel, ok := rels[id.relID()]
+		if !ok || !strings.HasSuffix(rel.Type, relSlide) {
+			continue
+		}
+		slide, err := pkg.xml(rel.Target)
+		if err != nil || slide == nil {
+			continue
+		}
+		if slide.attr("show") == "0" {
+			continue
+		}
+		title, text := pptxShapes(slide.path("cSld", "spTree"))
+		sec := Section{
+			Kind:  SectionSlide,
+			Index: len(doc.Sections) + 1,
+			Title: title,
+			Text:  text,
+		}
+		for _, r := range pkg.rels(rel.Target) {
+			if strings.HasSuffix(r.Type, relNotesSlide) {
+				if notes, err := pkg.xml(r.Target); err == nil && notes != nil {
+					sec.Notes = pptxNotes(notes)
+				}
+				break
+			}
+		}
+		doc.Sections = append(doc.Sections, sec)
+	}
+	return doc, nil
+}
+
+// pptxShapes returns the slide title and the markdown of the other shapes.
+func pptxShapes(tree *xnode) (string, string) {
+	var title string
+	var blocks []string
+	var walk func(*xnode)
+	walk = func(n *xnode) {
+		if n == nil {
+			return
+		}
+		for _, k := range n.Kids {
+			switch k.Name {
+			case "sp":
+				ph := k.path("nvSpPr", "nvPr", "ph").attr("type")
+				if (ph == "title" || ph == "ctrTitle") && title == "" {
+					title = strings.Join(strings.Fields(pptxParagraphs(k.child("txBody"), false)), " ")
+					continue
+				}
+				if ph == "sldNum" || ph == "dt" || ph == "ftr" {
+					continue
+				}
+				blocks = append(blocks, pptxParagraphs(k.child("txBody"), true))
+			case "grpSp":
+				walk(k)
+			case "graphicFrame":
+				for _, tbl := range k.all("tbl") {
+					blocks = append(blocks, pptxTable(tbl))
+				}
+			}
+		}
+	}
+	walk(tree)
+	return title, joinBlocks(blocks)
+}
+
+// pptxParagraphs renders a text body; with bullets, indented paragraphs
+// (lvl > 0) become list items.
+func pptxParagraphs(body *xnode, bullets bool) string {
+	if body == nil {
+		return ""
+	}
+	var lines []string
+	for _, p := range body.all("p") {
+		text := strings.TrimSpace(pptxRunText(p))
+		if text == "" {
+			continue
+		}
+		if bullets {
+			if lvl, err := strconv.Atoi(p.child("pPr").attr("lvl")); err == nil && lvl > 0 {
+				text = strings.Repeat("  ", lvl-1) + "- " + text
+			}
+		}
+		lines = append(lines, text)
+	}
+	return strings.Join(lines, "\n")
+}
+
+func pptxRunText(p *xnode) string {
+	var sb strings.Builder
+	for _, k := range p.Kids {
+		switch k.Name {
+		case "r", "fld":
+			sb.WriteString(k.child("t").text())
+		case "br":
+			sb.WriteString("\n")
+		}
+	}
+	return sb.String()
+}
+
+func pptxTable(tbl *xnode) string {
+	var rows [][]string
+	for _, tr := range tbl.all("tr") {
+		var row []string
+		for _, tc := range tr.all("tc") {
+			row = append(row, pptxParagraphs(tc.child("txBody"), false))
+		}
+		rows = append(rows, row)
+	}
+	if len(rows) == 0 {
+		return ""
+	}
+	return markdownTable(rows)
+}
+
+// pptxNotes returns the text of the notes placeholder, ignoring the slide
+// image and slide number placeholders.
+func pptxNotes(notes *xnode) string {
+	var parts []string
+	for _, sp := range notes.path("cSld", "spTree").all("sp") {
+		if sp.path("nvSpPr", "nvPr", "ph").attr("type") != "body" {
+			continue
+		}
+		if text := pptxParagraphs(sp.child("txBody"), false); text != "" {
+			parts = append(parts, text)
+		}
+	}
+	return strings.Join(parts, "\n")
+}
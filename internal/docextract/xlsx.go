@@ -0,0 +1,279 @@
+package docextract
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Limits per worksheet; larger sheets are truncated with a note.
+const (
+	maxSheetRows = 500
+	maxSheetCols = 50
+)
+
+// parseXLSX renders each worksheet, in workbook order, as a markdown table
+// labelled with its used cell range.
+func parseXLSX(data []byte) (*Document, error) {
+	pkg, err := openPackage(data)
+	if err != nil {
+		return nil, err
+	}
+	wb, err := pkg.xml("xl/workbook.xml")
+	if err != nil {
+		return nil, err
+	}
+	if wb == nil {
+		return nil, fmt.Errorf("xlsx: missing workbook")
+	}
+	x := &xlsxReader{pkg: pkg}
+	x.loadSharedStrings()
+	x.loadStyles()
+	x.date1904 = wb.child("workbookPr").attr("date1904") == "1"
+
+	rels := pkg.rels("xl/workbook.xml")
+	doc := &Document{Format: FormatXLSX}
+	for _, s := range wb.path("sheets").all("sheet") {
+		if s.attr("state") == "veryHidden" {
+			continue
+		}
+		rel, ok := rels[s.relID()]
+		if !ok {
+			continue
+		}
+		sheet, err := pkg.xml(rel.Target)
+		if err != nil || sheet == nil {
+			continue
+		}
+		text, rng := x.sheet(sheet)
+		doc.Sections = append(doc.Sections, Section{
+			Kind:  SectionSheet,
+			Index: len(doc.Sections) + 1,
+			Title: s.attr("name"),
+			Range: rng,
+			Text:  text,
+		})
+	}
+	return doc, nil
+}
+
+type xlsxReader struct {
+	pkg       *ooxmlPackage
+	shared    []string
+	dateStyle []bool // cell style index (s attr) → number format is a date
+	date1904  bool
+}
+
+func (x *xlsxReader) loadSharedStrings() {
+	sst, err := x.pkg.xml("xl/sharedStrings.xml")
+	if err != nil || sst == nil {
+		return
+	}
+	for _, si := range sst.all("si") {
+		x.shared = append(x.shared, xlsxInlineText(si))
+	}
+}
+
+// xlsxInlineText joins the text runs of a shared or inline string, skipping
+// phonetic (rPh) hints.
+func xlsxInlineText(si *xnode) string {
+	var sb strings.Builder
+	var walk func(*xnode)
+	walk = func(n *xnode) {
+		for _, k := range n.Kids {
+			switch k.Name {
+			case "t":
+				sb.WriteString(k.Text)
+			case "rPh":
+			default:
+				walk(k)
+			}
+		}
+	}
+	walk(si)
+	return sb.String()
+}
+
+func (x *xlsxReader) loadStyles() {
+	styles, err := x.pkg.xml("xl/styles.xml")
+	if err != nil || styles == nil {
+		return
+	}
+	custom := map[string]string{}
+	for _, f := range styles.path("numFmts").all("numFmt") {
+		custom[f.attr("numFmtId")] = f.attr("formatCode")
+	}
+	for _, xf := range styles.path("cellXfs").all("xf") {
+		id := xf.attr("numFmtId")
+		x.dateStyle = append(x.dateStyle, isDateFormat(id, custom[id]))
+	}
+}
+
+// isDateFormat reports whether a number format displays a date or time:
+// the built-in date formats, or a custom code with date/time tokens outside
+// quoted text and brackets.
+func isDateFormat(id, code string) bool {
+	if n, err := strconv.Atoi(id); err == nil && (n >= 14 && n <= 22 || n >= 45 && n <= 47) {
+		return true
+	}
+	if code == "" {
+		return false
+	}
+	inQuote, inBracket := false, false
+	for _, r := range strings.ToLower(code) {
+		switch {
+		case r == '"':
+			inQuote = !inQuote
+		case inQuote:
+		case r == '[':
+			inBracket = true
+		case r == ']':
+			inBracket = false
+		case inBracket:
+		case strings.ContainsRune("dmyhs", r):
+			return true
+		}
+	}
+	return false
+}
+
+// sheet renders the cells of a worksheet and returns the table and its used range.
+func (x *xlsxReader) sheet(ws *xnode) (string, string) {
+	type cell struct {
+		row, col int
+		value    string
+	}
+	var cells []cell
+	minRow, minCol, maxRow, maxCol := math.MaxInt, math.MaxInt, 0, 0
+	nextRow := 1
+	for _, r := range ws.path("sheetData").all("row") {
+		rowNum := nextRow
+		if v, err := strconv.Atoi(r.attr("r")); err == nil {
+			rowNum = v
+		}
+		nextRow = rowNum + 1
+		nextCol := 1
+		for _, c := range r.all("c") {
+			col := nextCol
+			if ref := c.attr("r"); ref != "" {
+				if cc, _, ok := parseCellRef(ref); ok {
+					col = cc
+				}
+			}
+			nextCol = col + 1
+			v := x.cellValue(c)
+			if v == "" {
+				continue
+			}
+			cells = append(cells, cell{rowNum, col, v})
+			minRow, maxRow = min(minRow, rowNum), max(maxRow, rowNum)
+			minCol, maxCol = min(minCol, col), max(maxCol, col)
+		}
+	}
+	if len(cells) == 0 {
+		return "", ""
+	}
+	rng := cellName(minCol, minRow) + ":" + cellName(maxCol, maxRow)
+
+	lastRow := min(maxRow, minRow+maxSheetRows-1)
+	lastCol := min(maxCol, minCol+maxSheetCols-1)
+	grid := make([][]string, lastRow-minRow+1)
+	for i := range grid {
+		grid[i] = make([]string, lastCol-minCol+1)
+	}
+	for _, c := range cells {
+		if c.row <= lastRow && c.col <= lastCol {
+			grid[c.row-minRow][c.col-minCol] = c.value
+		}
+	}
+	// Drop empty rows inside the range; they add nothing to a table.
+	rows := grid[:0]
+	for _, r := range grid {
+		if strings.Join(r, "") != "" {
+			rows = append(rows, r)
+		}
+	}
+	text := markdownTable(rows)
+	if lastRow < maxRow || lastCol < maxCol {
+		text += fmt.Sprintf("\n\n_(truncated to %s:%s)_", cellName(minCol, minRow), cellName(lastCol, lastRow))
+	}
+	return text, rng
+}
+
+func (x *xlsxReader) cellValue(c *xnode) string {
+	v := c.child("v").text()
+	switch c.attr("t") {
+	case "s":
+		i, err := strconv.Atoi(strings.TrimSpace(v))
+		if err != nil || i < 0 || i >= len(x.shared) {
+			return ""
+		}
+		return x.shared[i]
+	case "inlineStr":
+		return xlsxInlineText(c.child("is"))
+	case "str", "e":
+		return v
+	case "b":
+		if v == "1" {
+			return "TRUE"
+		}
+		return "FALSE"
+	}
+	if v == "" {
+		return ""
+	}
+	if s, err := strconv.Atoi(c.attr("s")); err == nil && s >= 0 && s < len(x.dateStyle) && x.dateStyle[s] {
+		if f, err := strconv.ParseFloat(v, 64); err == nil {
+			return excelDate(f, x.date1904)
+		}
+	}
+	return v
+}
+
+// excelDate converts a serial date to ISO 8601, dropping a zero time part.
+func excelDate(serial float64, date1904 bool) string {
+	epoch := time.Date(1899, 12, 30, 0, 0, 0, 0, time.UTC)
+	if date1904 {
+		epoch = time.Date(1904, 1, 1, 0, 0, 0, 0, time.UTC)
+	}
+	days := math.Floor(serial)
+	secs := math.Round((serial - days) * 86400)
+	t := epoch.AddDate(0, 0, int(days)).Add(time.Duration(secs) * time.Second)
+	switch {
+	case days == 0 && !date1904:
+		return t.Format("15:04:05")
+	case secs == 0:
+		return t.Format("2006-01-02")
+	}
+	return t.Format("2006-01-02 15:04:05")
+}
+
+// parseCellRef splits "BC12" into column 55 and row 12.
+func parseCellRef(ref string) (col, row int, ok bool) {
+	i := 0
+	for i < len(ref) && ref[i] >= 'A' && ref[i] <= 'Z' {
+		col = col*26 + int(ref[i]-'A'+1)
+		i++
+	}
+	if i == 0 {
+		return 0, 0, false
+	}
+	row, err := strconv.Atoi(ref[i:])
+	if err != nil {
+		return 0, 0, false
+	}
+	return col, row, true
+}
+
+// cellName builds "BC12" from column 55 and row 12.
+func cellName(col, row int) string {
+	var name []byte
+	for col > 0 {
+		col--
+		name = append([]byte{byte('A' + col%26)}, name...)
+		col /= 26
+	}
+	return string(name) + strconv.Itoa(row)
+}
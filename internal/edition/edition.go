@@ -43,7 +43,7 @@ var Lite = Edition{
 	KGEnabled:             false,
 	RBACEnabled:           false,
 	TeamFullMode:          false,
-	VectorSearch:          false,
+	VectorSearch:          true,
 }
 
 // --- Global state ---
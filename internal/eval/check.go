@@ -0,0 +1,257 @@
+package eval
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/nextlevelbuilder/goclaw/internal/agent"
+	"github.com/nextlevelbuilder/goclaw/internal/providers"
+	"github.com/nextlevelbuilder/goclaw/internal/store"
+)
+
+// Judge grades a reply against a rubric (llm_judge assertions).
+type Judge func(ctx context.Context, rubric, message, output string) (pass bool, reason string, err error)
+
+// checkTurn evaluates a turn's tool expectations and output assertions.
+func checkTurn(ctx context.Context, judge Judge, turn store.EvalTurn, tr store.EvalTurnResult) []store.EvalCheck {
+	checks := []store.EvalCheck{}
+	for _, exp := range turn.ExpectTools {
+		checks = append(checks, checkTool(exp, tr.ToolCalls))
+	}
+	for _, a := range turn.Assertions {
+		checks = append(checks, checkAssertion(ctx, judge, a, turn.Message, tr.Output))
+	}
+	return checks
+}
+
+func checkTool(exp store.EvalToolExpectation, calls []store.EvalToolCall) store.EvalCheck {
+	called := false
+	for _, c := range calls {
+		if c.Name == exp.Name && argsSubset(exp.Args, c.Args) {
+			called = true
+			break
+		}
+	}
+	ch := store.EvalCheck{Kind: "tool", Passed: called != exp.Absent}
+	switch {
+	case exp.Absent && called:
+		ch.Detail = exp.Name + " was called but must not be"
+	case !exp.Absent && !called:
+		ch.Detail = fmt.Sprintf("expected a call to %s%s; got %s", exp.Name, argsLabel(exp.Args), callNames(calls))
+	}
+	return ch
+}
+
+func checkAssertion(ctx context.Context, judge Judge, a store.EvalAssertion, message, output string) store.EvalCheck {
+	ch := store.EvalCheck{Kind: a.Type}
+	switch a.Type {
+	case store.EvalAssertContains:
+		ch.Passed = strings.Contains(strings.ToLower(output), strings.ToLower(a.Value))
+		if !ch.Passed {
+			ch.Detail = fmt.Sprintf("output does not contain %q", a.Value)
+		}
+	case store.EvalAssertNotContains:
+		ch.Passed = !strings.Contains(strings.ToLower(output), strings.ToLower(a.Value))
+		if !ch.Passed {
+			ch.Detail = fmt.Sprintf("output contains %q", a.Value)
+		}
+	case store.EvalAssertRegex:
+		re, err := regexp.Compile(a.Value)
+		if err != nil {
+			ch.Detail = "invalid regex: " + err.Error()
+			break
+		}
+		ch.Passed = re.MatchString(output)
+		if !ch.Passed {
+			ch.Detail = fmt.Sprintf("output does not match /%s/", a.Value)
+		}
+	case store.EvalAssertJSONSchema:
+		f := &providers.ResponseFormat{Type: providers.ResponseFormatJSONObject}
+		if len(a.Schema) > 0 {
+			f = &providers.ResponseFormat{Type: providers.ResponseFormatJSONSchema, JSONSchema: &providers.JSONSchemaSpec{Name: "assertion", Schema: a.Schema}}
+		}
+		if _, err := f.Validate(output); err != nil {
+			ch.Detail = err.Error()
+		} else {
+			ch.Passed = true
+		}
+	case store.EvalAssertLLMJudge:
+		if judge == nil {
+			ch.Detail = "no judge model available"
+			break
+		}
+		pass, reason, err := judge(ctx, a.Rubric, message, output)
+		if err != nil {
+			ch.Detail = "judge failed: " + err.Error()
+			break
+		}
+		ch.Passed, ch.Detail = pass, reason
+	default:
+		ch.Detail = fmt.Sprintf("unknown assertion type %q", a.Type)
+	}
+	return ch
+}
+
+// argsSubset reports whether every key of want is in got with an equal
+// value. Values are compared after a JSON round trip so 3 and 3.0 match.
+func argsSubset(want, got map[string]any) bool {
+	for k, w := range want {
+		g, ok := got[k]
+		if !ok || !reflect.DeepEqual(normalizeJSON(w), normalizeJSON(g)) {
+			return false
+		}
+	}
+	return true
+}
+
+func normalizeJSON(v any) any {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return v
+	}
+	var out any
+	if json.Unmarshal(b, &out) != nil {
+		return v
+	}
+	return out
+}
+
+func argsLabel(args map[string]any) string {
+	if len(args) == 0 {
+		return ""
+	}
+	b, _ := json.Marshal(args)
+	return " with " + string(b)
+}
+
+func callNames(calls []store.EvalToolCall) string {
+	if len(calls) == 0 {
+		return "no tool calls"
+	}
+	names := make([]string, len(calls))
+	for i, c := range calls {
+		names[i] = c.Name
+	}
+	return strings.Join(names, ", ")
+}
+
+const judgeTimeout = 90 * time.Second
+
+const judgeSystemPrompt = `You grade an AI assistant's reply against a rubric. Judge only what the rubric asks; ignore style unless the rubric mentions it.
+Reply with only a JSON object: {"pass": true or false, "reason": "<one short sentence>"}`
+
+// judgeFor returns an LLM judge backed by the agent's provider. model ""
+// uses the agent's own model.
+func judgeFor(ag agent.Agent, model string) Judge {
+	p := ag.Provider()
+	if p == nil {
+		return nil
+	}
+	if model == "" {
+		model = ag.Model()
+	}
+	return func(ctx context.Context, rubric, message, output string) (bool, string, error) {
+		ctx, cancel := context.WithTimeout(ctx, judgeTimeout)
+		defer cancel()
+		resp, err := p.Chat(ctx, providers.ChatRequest{
+			Messages: []providers.Message{
+				{Role: "system", Content: judgeSystemPrompt},
+				{Role: "user", Content: fmt.Sprintf("## Rubric\n%s\n\n## User message\n%s\n\n## Assistant reply\n%s", rubric, message, output)},
+			},
+			Model: model,
+			Options: map[string]any{
+				providers.OptMaxTokens:   300,
+				providers.OptTemperature: 0.0,
+			},
+		})
+		if err != nil {
+			return false, "", err
+		}
+		return parseVerdict(resp.Content)
+	}
+}
+
+var verdictRe = regexp.MustCompile(`(?s)\{.*\}`)
+
+// parseVerdict extracts {"pass": bool, "reason": string} from a judge reply.
+func parseVerdict(content string) (bool, string, error) {
+	var v struct {
+		Pass   bool   `json:"pass"`
+		Reason string `json:"reason"`
+	}
+	raw := verdictRe.FindString(content)
+	if raw == "" || json.Unmarshal([]byte(raw), &v) != nil {
+		return false, "", fmt.Errorf("unparseable verdict: %.200s", content)
+	}
+	return v.Pass, v.Reason, nil
+}
+
+// ValidateSuite checks a suite definition before it is stored.
+func ValidateSuite(s *store.EvalSuite) error {
+	if strings.TrimSpace(s.Name) == "" {
+		return fmt.Errorf("suite name is required")
+	}
+	if len(s.Cases) == 0 {
+		return fmt.Errorf("suite %q has no cases", s.Name)
+	}
+	seen := map[string]bool{}
+	for i, c := range s.Cases {
+		if c.Name == "" {
+			return fmt.Errorf("case %d: name is required", i+1)
+		}
+		if seen[c.Name] {
+			return fmt.Errorf("duplicate case name %q", c.Name)
+		}
+		seen[c.Name] = true
+		if len(c.Turns) == 0 {
+			return fmt.Errorf("case %q has no turns", c.Name)
+		}
+		for j, t := range c.Turns {
+			if strings.TrimSpace(t.Message) == "" {
+				return fmt.Errorf("case %q turn %d: message is required", c.Name, j+1)
+			}
+			for _, e := range t.ExpectTools {
+				if e.Name == "" {
+					return fmt.Errorf("case %q turn %d: expected tool name is required", c.Name, j+1)
+				}
+			}
+			for _, a := range t.Assertions {
+				if err := validateAssertion(a); err != nil {
+					return fmt.Errorf("case %q turn %d: %w", c.Name, j+1, err)
+				}
+			}
+		}
+		for _, st := range c.Stubs {
+			if st.Name == "" {
+				return fmt.Errorf("case %q: stub tool name is required", c.Name)
+			}
+		}
+	}
+	return nil
+}
+
+func validateAssertion(a store.EvalAssertion) error {
+	switch a.Type {
+	case store.EvalAssertContains, store.EvalAssertNotContains:
+		if a.Value == "" {
+			return fmt.Errorf("%s assertion needs a value", a.Type)
+		}
+	case store.EvalAssertRegex:
+		if _, err := regexp.Compile(a.Value); err != nil {
+			return fmt.Errorf("invalid regex %q: %w", a.Value, err)
+		}
+	case store.EvalAssertJSONSchema:
+	case store.EvalAssertLLMJudge:
+		if strings.TrimSpace(a.Rubric) == "" {
+			return fmt.Errorf("llm_judge assertion needs a rubric")
+		}
+	default:
+		return fmt.Errorf("unknown assertion type %q", a.Type)
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+package eval
+
+import (
+	"strings"
+
+	"github.com/nextlevelbuilder/goclaw/internal/providers"
+	"github.com/nextlevelbuilder/goclaw/internal/store"
+)
+
+// referenceRubric is the llm_judge rubric generated for imported turns.
+const referenceRubric = "The reply gives the user the same substantive answer as this reference reply from a known-good conversation (wording, formatting and extra detail may differ):\n\n"
+
+// CaseFromHistory turns a stored session history into a regression case.
+// Each user message becomes a turn that expects the tools the agent called
+// for it and is judged against the reply it gave; tool results are recorded
+// as stubs so the replay sees the same data.
+func CaseFromHistory(name string, history []providers.Message) store.EvalCase {
+	c := store.EvalCase{Name: name}
+	calls := map[string]providers.ToolCall{}
+	var reply string
+
+	flush := func() {
+		if n := len(c.Turns); n > 0 && reply != "" {
+			t := &c.Turns[n-1]
+			t.Assertions = append(t.Assertions, store.EvalAssertion{Type: store.EvalAssertLLMJudge, Rubric: referenceRubric + reply})
+		}
+		reply = ""
+	}
+
+	for _, m := range history {
+		switch m.Role {
+		case "user":
+			if strings.TrimSpace(m.Content) == "" {
+				continue
+			}
+			flush()
+			c.Turns = append(c.Turns, store.EvalTurn{Message: m.Content})
+		case "assistant":
+			if len(c.Turns) == 0 {
+				continue
+			}
+			t := &c.Turns[len(c.Turns)-1]
+			for _, tc := range m.ToolCalls {
+				calls[tc.ID] = tc
+				if !expectsTool(t.ExpectTools, tc.Name) {
+					t.ExpectTools = append(t.ExpectTools, store.EvalToolExpectation{Name: tc.Name})
+				}
+			}
+			if len(m.ToolCalls) == 0 && strings.TrimSpace(m.Content) != "" {
+				reply = m.Content
+			}
+		case "tool":
+			tc, ok := calls[m.ToolCallID]
+			if !ok {
+				continue
+			}
+			c.Stubs = append(c.Stubs, store.EvalToolStub{Name: tc.Name, Args: tc.Arguments, Result: m.Content, IsError: m.IsError})
+		}
+	}
+	flush()
+	return c
+}
+
+func expectsTool(exps []store.EvalToolExpectation, name string) bool {
+	for _, e := range exps {
+		if e.Name == name {
+			return true
+		}
+	}
+	return false
+}
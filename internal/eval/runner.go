@@ -0,0 +1,254 @@
+// Package eval replays golden conversations (eval suites) against an agent
+// through the real agent loop and scores the replies.
+package eval
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/nextlevelbuilder/goclaw/internal/agent"
+	"github.com/nextlevelbuilder/goclaw/internal/sessions"
+	"github.com/nextlevelbuilder/goclaw/internal/store"
+	"github.com/nextlevelbuilder/goclaw/internal/tools"
+)
+
+// Channel is the channel name eval runs use; it keeps eval sessions and
+// traces apart from real traffic.
+const Channel = "eval"
+
+// AgentResolver looks up a running agent by key (satisfied by *agent.Router).
+type AgentResolver interface {
+	Get(ctx context.Context, agentID string) (agent.Agent, error)
+}
+
+// Runner executes eval suites and records their runs.
+type Runner struct {
+	agents   AgentResolver
+	evals    store.EvalStore
+	sessions store.SessionStore // optional: eval sessions are deleted after each case
+}
+
+// NewRunner creates a runner. sessions may be nil.
+func NewRunner(agents AgentResolver, evals store.EvalStore, sessions store.SessionStore) *Runner {
+	return &Runner{agents: agents, evals: evals, sessions: sessions}
+}
+
+// Options controls one run.
+type Options struct {
+	AgentKey  string // agent to run against (the suite's agent)
+	ToolMode  string // store.EvalTools*; "" = stub
+	UserID    string // user the turns are sent as; "" = "eval"
+	CreatedBy string
+}
+
+// Start records a running EvalRun and executes the suite in the background.
+// The returned run is the initial record; poll the store for progress.
+// Cancellation of ctx is ignored, its values (tenant, user) are kept.
+func (r *Runner) Start(ctx context.Context, suite *store.EvalSuite, opts Options) (*store.EvalRun, error) {
+	run, err := r.create(ctx, suite, opts)
+	if err != nil {
+		return nil, err
+	}
+	bg := context.WithoutCancel(ctx)
+	started := *run
+	go r.execute(bg, suite, run, opts)
+	return &started, nil
+}
+
+// Run executes the suite and returns the finished run.
+func (r *Runner) Run(ctx context.Context, suite *store.EvalSuite, opts Options) (*store.EvalRun, error) {
+	run, err := r.create(ctx, suite, opts)
+	if err != nil {
+		return nil, err
+	}
+	r.execute(ctx, suite, run, opts)
+	return run, nil
+}
+
+func (r *Runner) create(ctx context.Context, suite *store.EvalSuite, opts Options) (*store.EvalRun, error) {
+	mode := opts.ToolMode
+	if mode == "" {
+		mode = store.EvalToolsStub
+	}
+	if mode != store.EvalToolsStub && mode != store.EvalToolsLive {
+		return nil, fmt.Errorf("invalid tool mode %q: want %s or %s", mode, store.EvalToolsStub, store.EvalToolsLive)
+	}
+	if len(suite.Cases) == 0 {
+		return nil, fmt.Errorf("suite %q has no cases", suite.Name)
+	}
+	run := &store.EvalRun{
+		SuiteID:   suite.ID,
+		AgentID:   suite.AgentID,
+		SuiteName: suite.Name,
+		Status:    store.EvalRunRunning,
+		ToolMode:  mode,
+		Total:     len(suite.Cases),
+		CreatedBy: opts.CreatedBy,
+		StartedAt: time.Now().UTC(),
+	}
+	if err := r.evals.CreateRun(ctx, run); err != nil {
+		return nil, fmt.Errorf("create eval run: %w", err)
+	}
+	return run, nil
+}
+
+// execute runs every case in order and writes the final run record.
+func (r *Runner) execute(ctx context.Context, suite *store.EvalSuite, run *store.EvalRun, opts Options) {
+	if opts.UserID == "" {
+		opts.UserID = "eval"
+	}
+	opts.ToolMode = run.ToolMode
+
+	ag, err := r.agents.Get(ctx, opts.AgentKey)
+	if err != nil {
+		run.Error = fmt.Sprintf("agent %s: %v", opts.AgentKey, err)
+	} else {
+		for i, c := range suite.Cases {
+			if ctx.Err() != nil {
+				run.Error = ctx.Err().Error()
+				break
+			}
+			cr := r.runCase(ctx, ag, suite, c, sessions.BuildSessionKey(opts.AgentKey, Channel, sessions.PeerDirect, fmt.Sprintf("%s-%d", run.ID.String()[:8], i+1)), opts)
+			run.Results = append(run.Results, cr)
+			if cr.Passed {
+				run.Passed++
+			} else {
+				run.Failed++
+			}
+			run.InputTokens += cr.InputTokens
+			run.OutputTokens += cr.OutputTokens
+			run.CostUSD += cr.CostUSD
+		}
+	}
+
+	finished := time.Now().UTC()
+	run.FinishedAt = &finished
+	run.DurationMS = int(finished.Sub(run.StartedAt).Milliseconds())
+	run.Status = store.EvalRunCompleted
+	if run.Error != "" {
+		run.Status = store.EvalRunFailed
+	}
+	if err := r.evals.UpdateRun(context.WithoutCancel(ctx), run); err != nil {
+		slog.Warn("eval: failed to save run", "run", run.ID, "error", err)
+	}
+	slog.Info("eval run finished", "suite", suite.Name, "run", run.ID, "status", run.Status,
+		"passed", run.Passed, "failed", run.Failed, "cost_usd", run.CostUSD)
+}
+
+// runCase replays one conversation in a fresh session.
+func (r *Runner) runCase(ctx context.Context, ag agent.Agent, suite *store.EvalSuite, c store.EvalCase, sessionKey string, opts Options) store.EvalCaseResult {
+	cr := store.EvalCaseResult{Name: c.Name, Passed: true}
+	if r.sessions != nil {
+		defer r.sessions.Delete(context.WithoutCancel(ctx), sessionKey) //nolint:errcheck
+	}
+
+	rec := &callRecorder{stubs: c.Stubs, live: opts.ToolMode == store.EvalToolsLive}
+	ctx = tools.WithToolCallHook(ctx, rec.hook)
+	caseStart := time.Now()
+
+	for i, turn := range c.Turns {
+		rec.reset()
+		start := time.Now()
+		res, err := ag.Run(ctx, agent.RunRequest{
+			SessionKey: sessionKey,
+			Message:    turn.Message,
+			Channel:    Channel,
+			ChatID:     sessionKey,
+			PeerKind:   string(sessions.PeerDirect),
+			RunID:      uuid.NewString(),
+			UserID:     opts.UserID,
+			TraceName:  "eval " + suite.Name + "/" + c.Name,
+			TraceTags:  []string{"eval"},
+		})
+		tr := store.EvalTurnResult{Message: turn.Message, ToolCalls: rec.calls(), DurationMS: int(time.Since(start).Milliseconds())}
+		if err != nil {
+			cr.Error = fmt.Sprintf("turn %d: %v", i+1, err)
+			cr.Passed = false
+			tr.Checks = []store.EvalCheck{}
+			cr.Turns = append(cr.Turns, tr)
+			break
+		}
+		tr.Output = res.Content
+		if res.TraceID != uuid.Nil {
+			traceID := res.TraceID
+			tr.TraceID = &traceID
+		}
+		if res.Usage != nil {
+			cr.InputTokens += res.Usage.PromptTokens
+			cr.OutputTokens += res.Usage.CompletionTokens
+		}
+		cr.CostUSD += res.CostUSD
+
+		tr.Checks = checkTurn(ctx, judgeFor(ag, suite.JudgeModel), turn, tr)
+		for _, ch := range tr.Checks {
+			if !ch.Passed {
+				cr.Passed = false
+			}
+		}
+		cr.Turns = append(cr.Turns, tr)
+	}
+	cr.DurationMS = int(time.Since(caseStart).Milliseconds())
+	return cr
+}
+
+// callRecorder is the tool call hook for one case: it records every call of
+// the current turn and answers from the case's stubs.
+type callRecorder struct {
+	stubs []store.EvalToolStub
+	live  bool
+
+	mu   sync.Mutex
+	turn []store.EvalToolCall
+}
+
+func (c *callRecorder) hook(ctx context.Context, name string, args map[string]any, exec func() *tools.Result) *tools.Result {
+	stub := matchStub(c.stubs, name, args)
+	c.mu.Lock()
+	c.turn = append(c.turn, store.EvalToolCall{Name: name, Args: args, Stubbed: stub != nil || !c.live})
+	c.mu.Unlock()
+
+	switch {
+	case stub != nil && stub.IsError:
+		return tools.ErrorResult(stub.Result)
+	case stub != nil:
+		return tools.NewResult(stub.Result)
+	case c.live:
+		return exec()
+	}
+	return tools.NewResult(fmt.Sprintf("[eval] %s is stubbed in this evaluation and has no canned result; continue without it.", name))
+}
+
+func (c *callRecorder) reset() {
+	c.mu.Lock()
+	c.turn = nil
+	c.mu.Unlock()
+}
+
+func (c *callRecorder) calls() []store.EvalToolCall {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]store.EvalToolCall(nil), c.turn...)
+}
+
+// matchStub returns the first stub for name whose Args are a subset of the
+// call's arguments, falling back to the first stub with that name.
+func matchStub(stubs []store.EvalToolStub, name string, args map[string]any) *store.EvalToolStub {
+	var byName *store.EvalToolStub
+	for i := range stubs {
+		if stubs[i].Name != name {
+			continue
+		}
+		if argsSubset(stubs[i].Args, args) {
+			return &stubs[i]
+		}
+		if byName == nil {
+			byName = &stubs[i]
+		}
+	}
+	return byName
+}
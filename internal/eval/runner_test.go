@@ -0,0 +1,262 @@
+package eval
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/nextlevelbuilder/goclaw/internal/agent"
+	"github.com/nextlevelbuilder/goclaw/internal/providers"
+	"github.com/nextlevelbuilder/goclaw/internal/store"
+	"github.com/nextlevelbuilder/goclaw/internal/tools"
+)
+
+// fakeAgent calls the weather tool through a real registry (so the eval
+// hook applies) and answers with the tool output.
+type fakeAgent struct {
+	reg      *tools.Registry
+	provider providers.Provider
+	messages []string
+}
+
+func (a *fakeAgent) ID() string                   { return "weather-bot" }
+func (a *fakeAgent) IsRunning() bool              { return false }
+func (a *fakeAgent) Model() string                { return "judge-model" }
+func (a *fakeAgent) ProviderName() string         { return "fake" }
+func (a *fakeAgent) Provider() providers.Provider { return a.provider }
+
+func (a *fakeAgent) Run(ctx context.Context, req agent.RunRequest) (*agent.RunResult, error) {
+	a.messages = append(a.messages, req.SessionKey+"|"+req.Message)
+	if strings.Contains(req.Message, "hello") {
+		return &agent.RunResult{Content: "Hi there!", Usage: &providers.Usage{PromptTokens: 5, CompletionTokens: 2}}, nil
+	}
+	res := a.reg.ExecuteWithContext(ctx, "weather", map[string]any{"city": "Hanoi", "days": 1}, req.Channel, req.ChatID, req.PeerKind, req.SessionKey, nil)
+	return &agent.RunResult{
+		Content: "Forecast: " + res.ForLLM,
+		Usage:   &providers.Usage{PromptTokens: 100, CompletionTokens: 20},
+		CostUSD: 0.01,
+		TraceID: uuid.New(),
+	}, nil
+}
+
+type fakeResolver struct{ ag agent.Agent }
+
+func (r fakeResolver) Get(context.Context, string) (agent.Agent, error) { return r.ag, nil }
+
+type fakeEvalStore struct {
+	store.EvalStore // unused methods panic
+	runs            map[uuid.UUID]store.EvalRun
+}
+
+func (s *fakeEvalStore) CreateRun(_ context.Context, r *store.EvalRun) error {
+	r.ID = uuid.New()
+	s.runs[r.ID] = *r
+	return nil
+}
+
+func (s *fakeEvalStore) UpdateRun(_ context.Context, r *store.EvalRun) error {
+	s.runs[r.ID] = *r
+	return nil
+}
+
+// judgeProvider passes replies mentioning "sunny".
+type judgeProvider struct{ providers.Provider }
+
+func (judgeProvider) Chat(_ context.Context, req providers.ChatRequest) (*providers.ChatResponse, error) {
+	if strings.Contains(req.Messages[1].Content, "sunny") {
+		return &providers.ChatResponse{Content: `{"pass": true, "reason": "mentions the weather"}`}, nil
+	}
+	return &providers.ChatResponse{Content: "```json\n{\"pass\": false, \"reason\": \"off topic\"}\n```"}, nil
+}
+
+type weatherTool struct{ calls int }
+
+func (t *weatherTool) Name() string               { return "weather" }
+func (t *weatherTool) Description() string        { return "weather" }
+func (t *weatherTool) Parameters() map[string]any { return map[string]any{"type": "object"} }
+func (t *weatherTool) Execute(context.Context, map[string]any) *tools.Result {
+	t.calls++
+	return tools.NewResult("live: rainy")
+}
+
+func TestRunner_Run(t *testing.T) {
+	reg := tools.NewRegistry()
+	wt := &weatherTool{}
+	reg.Register(wt)
+	ag := &fakeAgent{reg: reg, provider: judgeProvider{}}
+	es := &fakeEvalStore{runs: map[uuid.UUID]store.EvalRun{}}
+	runner := NewRunner(fakeResolver{ag}, es, nil)
+
+	suite := &store.EvalSuite{ID: uuid.New(), Name: "weather", Cases: []store.EvalCase{
+		{
+			Name: "forecast",
+			Turns: []store.EvalTurn{
+				{Message: "hello"},
+				{
+					Message:     "weather in Hanoi?",
+					ExpectTools: []store.EvalToolExpectation{{Name: "weather", Args: map[string]any{"city": "Hanoi", "days": 1.0}}, {Name: "web_search", Absent: true}},
+					Assertions: []store.EvalAssertion{
+						{Type: store.EvalAssertContains, Value: "SUNNY"},
+						{Type: store.EvalAssertRegex, Value: `\d+°C`},
+						{Type: store.EvalAssertLLMJudge, Rubric: "talks about the weather"},
+					},
+				},
+			},
+			Stubs: []store.EvalToolStub{
+				{Name: "weather", Args: map[string]any{"city": "Paris"}, Result: "Paris: 12°C, cloudy"},
+				{Name: "weather", Args: map[string]any{"city": "Hanoi"}, Result: "31°C, sunny"},
+			},
+		},
+		{
+			Name:  "no stub",
+			Turns: []store.EvalTurn{{Message: "weather?", Assertions: []store.EvalAssertion{{Type: store.EvalAssertJSONSchema}}}},
+		},
+	}}
+
+	run, err := runner.Run(context.Background(), suite, Options{AgentKey: "weather-bot"})
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if wt.calls != 0 {
+		t.Errorf("stub mode executed the real tool %d times", wt.calls)
+	}
+	if run.Status != store.EvalRunCompleted || run.Passed != 1 || run.Failed != 1 || run.Total != 2 {
+		t.Fatalf("run = %+v, want completed 1 passed / 1 failed", run)
+	}
+	if run.InputTokens != 205 || run.CostUSD != 0.02 {
+		t.Errorf("totals: %d input tokens, $%v; want 205, $0.02", run.InputTokens, run.CostUSD)
+	}
+	if saved := es.runs[run.ID]; saved.Status != store.EvalRunCompleted || len(saved.Results) != 2 {
+		t.Errorf("saved run = %+v", saved)
+	}
+
+	forecast := run.Results[0]
+	if !forecast.Passed || len(forecast.Turns) != 2 {
+		t.Fatalf("forecast case = %+v", forecast)
+	}
+	turn := forecast.Turns[1]
+	if turn.Output != "Forecast: 31°C, sunny" || turn.TraceID == nil || len(turn.ToolCalls) != 1 || !turn.ToolCalls[0].Stubbed {
+		t.Errorf("turn = %+v", turn)
+	}
+	if len(turn.Checks) != 5 {
+		t.Errorf("checks = %+v, want 2 tool + 3 assertions", turn.Checks)
+	}
+	// Both turns of a case share one session; cases get their own.
+	if len(ag.messages) != 3 || strings.Split(ag.messages[0], "|")[0] != strings.Split(ag.messages[1], "|")[0] ||
+		strings.Split(ag.messages[1], "|")[0] == strings.Split(ag.messages[2], "|")[0] {
+		t.Errorf("sessions = %v", ag.messages)
+	}
+
+	noStub := run.Results[1]
+	if noStub.Passed || noStub.Turns[0].Checks[0].Passed || !strings.Contains(noStub.Turns[0].Output, "no canned result") {
+		t.Errorf("unstubbed case = %+v", noStub)
+	}
+
+	// Live mode runs unmatched tools for real.
+	ag.messages = nil
+	live, err := runner.Run(context.Background(), &store.EvalSuite{ID: uuid.New(), Name: "live", Cases: suite.Cases[1:]}, Options{AgentKey: "weather-bot", ToolMode: store.EvalToolsLive})
+	if err != nil {
+		t.Fatalf("Run live: %v", err)
+	}
+	if wt.calls != 1 || live.Results[0].Turns[0].Output != "Forecast: live: rainy" || live.Results[0].Turns[0].ToolCalls[0].Stubbed {
+		t.Errorf("live run = %+v (tool calls %d)", live.Results[0], wt.calls)
+	}
+
+	if _, err := runner.Run(context.Background(), suite, Options{ToolMode: "yolo"}); err == nil {
+		t.Error("invalid tool mode accepted")
+	}
+}
+
+func TestCheckAssertion(t *testing.T) {
+	schema := map[string]any{
+		"type":       "object",
+		"properties": map[string]any{"temp": map[string]any{"type": "number"}},
+		"required":   []any{"temp"},
+	}
+	tests := []struct {
+		name   string
+		a      store.EvalAssertion
+		output string
+		want   bool
+	}{
+		{"contains case-insensitive", store.EvalAssertion{Type: store.EvalAssertContains, Value: "hanoi"}, "Hanoi is hot", true},
+		{"not_contains", store.EvalAssertion{Type: store.EvalAssertNotContains, Value: "sorry"}, "Sorry, I can't", false},
+		{"regex", store.EvalAssertion{Type: store.EvalAssertRegex, Value: `^\d{4}-\d{2}-\d{2}$`}, "2026-03-01", true},
+		{"bad regex", store.EvalAssertion{Type: store.EvalAssertRegex, Value: `(`}, "x", false},
+		{"json schema", store.EvalAssertion{Type: store.EvalAssertJSONSchema, Schema: schema}, "```json\n{\"temp\": 31}\n```", true},
+		{"json schema violation", store.EvalAssertion{Type: store.EvalAssertJSONSchema, Schema: schema}, `{"temp": "hot"}`, false},
+		{"json without schema", store.EvalAssertion{Type: store.EvalAssertJSONSchema}, `not json`, false},
+		{"judge missing", store.EvalAssertion{Type: store.EvalAssertLLMJudge, Rubric: "x"}, "x", false},
+		{"unknown", store.EvalAssertion{Type: "vibes"}, "x", false},
+	}
+	for _, tt := range tests {
+		if got := checkAssertion(context.Background(), nil, tt.a, "msg", tt.output); got.Passed != tt.want {
+			t.Errorf("%s: passed = %v (%s), want %v", tt.name, got.Passed, got.Detail, tt.want)
+		}
+	}
+}
+
+func TestCaseFromHistory(t *testing.T) {
+	history := []providers.Message{
+		{Role: "user", Content: "weather in Hanoi?"},
+		{Role: "assistant", ToolCalls: []providers.ToolCall{{ID: "c1", Name: "weather", Arguments: map[string]any{"city": "Hanoi"}}}},
+		{Role: "tool", ToolCallID: "c1", Content: "31°C, sunny"},
+		{Role: "assistant", ToolCalls: []providers.ToolCall{{ID: "c2", Name: "weather", Arguments: map[string]any{"city": "Hanoi", "days": 3}}}},
+		{Role: "tool", ToolCallID: "c2", Content: "rain later", IsError: false},
+		{Role: "assistant", Content: "It's 31°C and sunny."},
+		{Role: "user", Content: "thanks"},
+		{Role: "assistant", Content: "You're welcome!"},
+	}
+	c := CaseFromHistory("imported", history)
+	if len(c.Turns) != 2 || c.Turns[0].Message != "weather in Hanoi?" {
+		t.Fatalf("turns = %+v", c.Turns)
+	}
+	if len(c.Turns[0].ExpectTools) != 1 || c.Turns[0].ExpectTools[0].Name != "weather" || len(c.Turns[1].ExpectTools) != 0 {
+		t.Errorf("tool expectations = %+v / %+v", c.Turns[0].ExpectTools, c.Turns[1].ExpectTools)
+	}
+	if len(c.Stubs) != 2 || c.Stubs[0].Result != "31°C, sunny" || c.Stubs[1].Args["days"] != 3 {
+		t.Errorf("stubs = %+v", c.Stubs)
+	}
+	a := c.Turns[0].Assertions
+	if len(a) != 1 || a[0].Type != store.EvalAssertLLMJudge || !strings.HasSuffix(a[0].Rubric, "It's 31°C and sunny.") {
+		t.Errorf("assertions = %+v", a)
+	}
+	if !strings.HasSuffix(c.Turns[1].Assertions[0].Rubric, "You're welcome!") {
+		t.Errorf("second turn rubric = %q", c.Turns[1].Assertions[0].Rubric)
+	}
+}
+
+func TestValidateSuite(t *testing.T) {
+	valid := func() *store.EvalSuite {
+		return &store.EvalSuite{Name: "s", Cases: []store.EvalCase{{Name: "c", Turns: []store.EvalTurn{{
+			Message:    "hi",
+			Assertions: []store.EvalAssertion{{Type: store.EvalAssertContains, Value: "hello"}},
+		}}}}}
+	}
+	if err := ValidateSuite(valid()); err != nil {
+		t.Fatalf("valid suite rejected: %v", err)
+	}
+
+	tests := map[string]func(s *store.EvalSuite){
+		"no name":        func(s *store.EvalSuite) { s.Name = " " },
+		"no cases":       func(s *store.EvalSuite) { s.Cases = nil },
+		"duplicate case": func(s *store.EvalSuite) { s.Cases = append(s.Cases, s.Cases[0]) },
+		"empty message":  func(s *store.EvalSuite) { s.Cases[0].Turns[0].Message = "" },
+		"bad regex": func(s *store.EvalSuite) {
+			s.Cases[0].Turns[0].Assertions[0] = store.EvalAssertion{Type: store.EvalAssertRegex, Value: "("}
+		},
+		"judge rubric": func(s *store.EvalSuite) {
+			s.Cases[0].Turns[0].Assertions[0] = store.EvalAssertion{Type: store.EvalAssertLLMJudge}
+		},
+		"unknown type": func(s *store.EvalSuite) { s.Cases[0].Turns[0].Assertions[0].Type = "vibes" },
+	}
+	for name, mutate := range tests {
+		s := valid()
+		mutate(s)
+		if err := ValidateSuite(s); err == nil {
+			t.Errorf("%s: accepted", name)
+		}
+	}
+}
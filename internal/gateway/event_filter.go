@@ -111,6 +111,14 @@ func clientCanReceiveEvent(c *Client, event bus.Event) bool {
 		return true
 	}
 
+	// Tool approval events carry call arguments: only the requesting user.
+	if strings.HasPrefix(event.Name, "tool.approval.") {
+		if uid := extractMapField(event.Payload, "userId"); uid != "" {
+			return uid == c.userID
+		}
+		return false
+	}
+
 	// Zalo personal QR events: admin-only (channel management).
 	if strings.HasPrefix(event.Name, "zalo.personal.") {
 		return false
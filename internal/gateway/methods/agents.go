@@ -8,6 +8,7 @@ import (
 	"github.com/nextlevelbuilder/goclaw/internal/agent"
 	"github.com/nextlevelbuilder/goclaw/internal/bus"
 	"github.com/nextlevelbuilder/goclaw/internal/config"
+	"github.com/nextlevelbuilder/goclaw/internal/contextfiles"
 	"github.com/nextlevelbuilder/goclaw/internal/gateway"
 	"github.com/nextlevelbuilder/goclaw/internal/i18n"
 	"github.com/nextlevelbuilder/goclaw/internal/permissions"
@@ -17,7 +18,8 @@ import (
 )
 
 // AgentsMethods handles agents.list, agents.create, agents.update, agents.delete,
-// agents.files.list/get/set, agent.identity.get.
+// agents.files.list/get/set, agents.files.history/diff/restore/pending/approve/reject,
+// agent.identity.get.
 type AgentsMethods struct {
 	agents      *agent.Router
 	cfg         *config.Config
@@ -26,12 +28,19 @@ type AgentsMethods struct {
 	agentStore  store.AgentStore
 	interceptor *tools.ContextFileInterceptor // invalidated on file writes
 	eventBus    bus.EventPublisher
+	files       *contextfiles.Service // nil = no file history RPCs
+	providers   store.ProviderStore   // fallback_chain provider checks; nil skips them
 }
 
 func NewAgentsMethods(agents *agent.Router, cfg *config.Config, cfgPath, workspace string, agentStore store.AgentStore, interceptor *tools.ContextFileInterceptor, eventBus bus.EventPublisher) *AgentsMethods {
 	return &AgentsMethods{agents: agents, cfg: cfg, cfgPath: cfgPath, workspace: workspace, agentStore: agentStore, interceptor: interceptor, eventBus: eventBus}
 }
 
+// SetProviderStore enables the provider-existence check on fallback_chain writes.
+func (m *AgentsMethods) SetProviderStore(ps store.ProviderStore) {
+	m.providers = ps
+}
+
 // isOwnerUser checks if the given user ID is in the configured owner IDs.
 func (m *AgentsMethods) isOwnerUser(userID string) bool {
 	return canSeeAll(permissions.RoleViewer, m.cfg.Gateway.OwnerIDs, userID)
@@ -48,6 +57,7 @@ func (m *AgentsMethods) Register(router *gateway.MethodRouter) {
 	router.Register(protocol.MethodAgentsFileGet, m.handleFilesGet)
 	router.Register(protocol.MethodAgentsFileSet, m.handleFilesSet)
 	router.Register(protocol.MethodAgentIdentityGet, m.handleIdentityGet)
+	m.registerFileRevisions(router)
 }
 
 type agentParams struct {
@@ -115,6 +115,11 @@ func (m *AgentsMethods) handleCreate(ctx context.Context, client *gateway.Client
 			model = m.cfg.Agents.Defaults.Model
 		}
 
+		if err := store.ValidateFallbackChainProviders(store.WithTenantID(ctx, tenantID), m.providers, params.OtherConfig); err != nil {
+			client.SendResponse(protocol.NewErrorResponse(req.ID, protocol.ErrInvalidRequest, i18n.T(locale, i18n.MsgInvalidRequest, err.Error())))
+			return
+		}
+
 		agentData := &store.AgentData{
 			AgentKey:         agentID,
 			DisplayName:      params.Name,
@@ -149,7 +154,7 @@ func (m *AgentsMethods) handleCreate(ctx context.Context, client *gateway.Client
 		// Set identity in DB bootstrap
 		if params.Name != "" || params.Emoji != "" || params.Avatar != "" {
 			content := buildIdentityContent(params.Name, params.Emoji, params.Avatar)
-			if err := m.agentStore.SetAgentContextFile(ctx, agentData.ID, "IDENTITY.md", content); err != nil {
+			if err := m.agentStore.SetAgentContextFile(fileAuthorCtx(ctx, client), agentData.ID, "IDENTITY.md", content); err != nil {
 				slog.Warn("failed to set IDENTITY.md", "agent", agentID, "error", err)
 			}
 		}
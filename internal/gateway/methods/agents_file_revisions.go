@@ -0,0 +1,340 @@
+package methods
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/google/uuid"
+
+	"github.com/nextlevelbuilder/goclaw/internal/contextfiles"
+	"github.com/nextlevelbuilder/goclaw/internal/gateway"
+	"github.com/nextlevelbuilder/goclaw/internal/i18n"
+	"github.com/nextlevelbuilder/goclaw/internal/permissions"
+	"github.com/nextlevelbuilder/goclaw/internal/store"
+	"github.com/nextlevelbuilder/goclaw/internal/textdiff"
+	"github.com/nextlevelbuilder/goclaw/pkg/protocol"
+)
+
+// SetRevisionStore enables the agents.files.history/diff/restore/pending/approve/reject methods.
+// Must be called before Register.
+func (m *AgentsMethods) SetRevisionStore(rs store.ContextFileRevisionStore) {
+	if rs == nil || m.agentStore == nil {
+		m.files = nil
+		return
+	}
+	m.files = contextfiles.New(m.agentStore, rs, m.eventBus)
+}
+
+func (m *AgentsMethods) registerFileRevisions(router *gateway.MethodRouter) {
+	if m.files == nil {
+		return
+	}
+	router.Register(protocol.MethodAgentsFileHistory, m.handleFilesHistory)
+	router.Register(protocol.MethodAgentsFileDiff, m.handleFilesDiff)
+	router.Register(protocol.MethodAgentsFileRestore, m.handleFilesRestore)
+	router.Register(protocol.MethodAgentsFilePending, m.handleFilesPending)
+	router.Register(protocol.MethodAgentsFileApprove, m.handleFilesApprove)
+	router.Register(protocol.MethodAgentsFileReject, m.handleFilesReject)
+}
+
+// fileAuthorCtx attributes context file writes made on behalf of an RPC client.
+func fileAuthorCtx(ctx context.Context, client *gateway.Client) context.Context {
+	author := store.ContextFileAuthorUser
+	if permissions.HasMinRole(client.Role(), permissions.RoleAdmin) {
+		author = store.ContextFileAuthorAdmin
+	}
+	return store.WithContextFileAuthor(ctx, author, client.UserID())
+}
+
+// canManageFiles reports whether the client may restore revisions or review
+// pending changes of the agent: its owner, a configured owner, or an admin.
+func (m *AgentsMethods) canManageFiles(client *gateway.Client, ag *store.AgentData) bool {
+	userID := client.UserID()
+	return (userID != "" && ag.OwnerID == userID) ||
+		canSeeAll(client.Role(), m.cfg.Gateway.OwnerIDs, userID)
+}
+
+// getRevision resolves a revision ID param, scoped to the agent. Returns
+// (nil, nil) when the ID is malformed or the revision does not exist.
+func (m *AgentsMethods) getRevision(ctx context.Context, ag *store.AgentData, id string) (*store.ContextFileRevision, error) {
+	revID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, nil
+	}
+	rev, err := m.files.Revision(ctx, ag, revID)
+	if errors.Is(err, contextfiles.ErrNotFound) {
+		return nil, nil
+	}
+	return rev, err
+}
+
+func revisionSummary(r store.ContextFileRevision) map[string]any {
+	out := map[string]any{
+		"id":        r.ID,
+		"name":      r.FileName,
+		"userId":    r.UserID,
+		"author":    r.Author,
+		"authorId":  r.AuthorID,
+		"size":      len(r.Content),
+		"createdAt": r.CreatedAt,
+	}
+	if r.TraceID != nil {
+		out["traceId"] = r.TraceID
+	}
+	return out
+}
+
+// --- agents.files.history ---
+
+func (m *AgentsMethods) handleFilesHistory(ctx context.Context, client *gateway.Client, req *protocol.RequestFrame) {
+	locale := store.LocaleFromContext(ctx)
+	var params struct {
+		AgentID string `json:"agentId"`
+		Name    string `json:"name"`
+		UserID  string `json:"userId"` // per-user file; "" = agent-level
+		Limit   int    `json:"limit"`
+	}
+	if req.Params != nil {
+		json.Unmarshal(req.Params, &params)
+	}
+	if params.AgentID == "" {
+		params.AgentID = "default"
+	}
+	if params.Name == "" {
+		client.SendResponse(protocol.NewErrorResponse(req.ID, protocol.ErrInvalidRequest, i18n.T(locale, i18n.MsgRequired, "name")))
+		return
+	}
+
+	ag, err := m.agentStore.GetByKey(ctx, params.AgentID)
+	if err != nil {
+		client.SendResponse(protocol.NewErrorResponse(req.ID, protocol.ErrNotFound, i18n.T(locale, i18n.MsgAgentNotFound, params.AgentID)))
+		return
+	}
+
+	revs, err := m.files.History(ctx, ag.ID, params.UserID, params.Name, params.Limit)
+	if err != nil {
+		client.SendResponse(protocol.NewErrorResponse(req.ID, protocol.ErrInternal, i18n.T(locale, i18n.MsgFailedToList, "revisions")))
+		return
+	}
+
+	// Revisions are newest first; each is compared against the next (older)
+	// one. The oldest returned revision has no stats.
+	out := make([]map[string]any, 0, len(revs))
+	for i, r := range revs {
+		entry := revisionSummary(r)
+		if i+1 < len(revs) {
+			entry["added"], entry["removed"] = textdiff.Stats(textdiff.Lines(revs[i+1].Content, r.Content))
+		}
+		out = append(out, entry)
+	}
+
+	client.SendResponse(protocol.NewOKResponse(req.ID, map[string]any{
+		"agentId":   params.AgentID,
+		"name":      params.Name,
+		"userId":    params.UserID,
+		"revisions": out,
+	}))
+}
+
+// --- agents.files.diff ---
+
+func (m *AgentsMethods) handleFilesDiff(ctx context.Context, client *gateway.Client, req *protocol.RequestFrame) {
+	locale := store.LocaleFromContext(ctx)
+	var params struct {
+		AgentID    string `json:"agentId"`
+		RevisionID string `json:"revisionId"`
+		Against    string `json:"against"` // revision ID or "current"; default = previous revision
+	}
+	if req.Params != nil {
+		json.Unmarshal(req.Params, &params)
+	}
+	if params.AgentID == "" {
+		params.AgentID = "default"
+	}
+	if params.RevisionID == "" {
+		client.SendResponse(protocol.NewErrorResponse(req.ID, protocol.ErrInvalidRequest, i18n.T(locale, i18n.MsgRequired, "revisionId")))
+		return
+	}
+
+	ag, err := m.agentStore.GetByKey(ctx, params.AgentID)
+	if err != nil {
+		client.SendResponse(protocol.NewErrorResponse(req.ID, protocol.ErrNotFound, i18n.T(locale, i18n.MsgAgentNotFound, params.AgentID)))
+		return
+	}
+	rev, err := m.getRevision(ctx, ag, params.RevisionID)
+	if err != nil || rev == nil {
+		client.SendResponse(protocol.NewErrorResponse(req.ID, protocol.ErrNotFound, i18n.T(locale, i18n.MsgNotFound, "revision", params.RevisionID)))
+		return
+	}
+
+	diff, err := m.files.DiffRevision(ctx, ag, rev, params.Against)
+	if errors.Is(err, contextfiles.ErrNotFound) {
+		client.SendResponse(protocol.NewErrorResponse(req.ID, protocol.ErrNotFound, i18n.T(locale, i18n.MsgNotFound, "revision", params.Against)))
+		return
+	}
+	if err != nil {
+		client.SendResponse(protocol.NewErrorResponse(req.ID, protocol.ErrInternal, i18n.T(locale, i18n.MsgInternalError, err.Error())))
+		return
+	}
+
+	client.SendResponse(protocol.NewOKResponse(req.ID, map[string]any{
+		"agentId":  params.AgentID,
+		"revision": revisionSummary(*rev),
+		"diff":     diff.Unified,
+		"added":    diff.Added,
+		"removed":  diff.Removed,
+	}))
+}
+
+// --- agents.files.restore ---
+
+func (m *AgentsMethods) handleFilesRestore(ctx context.Context, client *gateway.Client, req *protocol.RequestFrame) {
+	locale := store.LocaleFromContext(ctx)
+	var params struct {
+		AgentID    string `json:"agentId"`
+		RevisionID string `json:"revisionId"`
+	}
+	if req.Params != nil {
+		json.Unmarshal(req.Params, &params)
+	}
+	if params.AgentID == "" {
+		params.AgentID = "default"
+	}
+	if params.RevisionID == "" {
+		client.SendResponse(protocol.NewErrorResponse(req.ID, protocol.ErrInvalidRequest, i18n.T(locale, i18n.MsgRequired, "revisionId")))
+		return
+	}
+
+	ag, err := m.agentStore.GetByKey(ctx, params.AgentID)
+	if err != nil {
+		client.SendResponse(protocol.NewErrorResponse(req.ID, protocol.ErrNotFound, i18n.T(locale, i18n.MsgAgentNotFound, params.AgentID)))
+		return
+	}
+	if !m.canManageFiles(client, ag) {
+		client.SendResponse(protocol.NewErrorResponse(req.ID, protocol.ErrUnauthorized, i18n.T(locale, i18n.MsgOwnerOnly, "restore files")))
+		return
+	}
+	rev, err := m.getRevision(ctx, ag, params.RevisionID)
+	if err != nil || rev == nil {
+		client.SendResponse(protocol.NewErrorResponse(req.ID, protocol.ErrNotFound, i18n.T(locale, i18n.MsgNotFound, "revision", params.RevisionID)))
+		return
+	}
+
+	if err := m.files.Restore(fileAuthorCtx(ctx, client), ag, rev); err != nil {
+		client.SendResponse(protocol.NewErrorResponse(req.ID, protocol.ErrInternal, i18n.T(locale, i18n.MsgFailedToSave, "file", err.Error())))
+		return
+	}
+
+	client.SendResponse(protocol.NewOKResponse(req.ID, map[string]any{
+		"agentId":  params.AgentID,
+		"restored": revisionSummary(*rev),
+	}))
+	emitAudit(m.eventBus, client, "agent.file_restored", "agent", params.AgentID)
+}
+
+// --- agents.files.pending ---
+
+func (m *AgentsMethods) handleFilesPending(ctx context.Context, client *gateway.Client, req *protocol.RequestFrame) {
+	locale := store.LocaleFromContext(ctx)
+	var params agentParams
+	if req.Params != nil {
+		json.Unmarshal(req.Params, &params)
+	}
+	if params.AgentID == "" {
+		params.AgentID = "default"
+	}
+
+	ag, err := m.agentStore.GetByKey(ctx, params.AgentID)
+	if err != nil {
+		client.SendResponse(protocol.NewErrorResponse(req.ID, protocol.ErrNotFound, i18n.T(locale, i18n.MsgAgentNotFound, params.AgentID)))
+		return
+	}
+
+	changes, err := m.files.PendingChanges(ctx, ag)
+	if err != nil {
+		client.SendResponse(protocol.NewErrorResponse(req.ID, protocol.ErrInternal, i18n.T(locale, i18n.MsgFailedToList, "pending changes")))
+		return
+	}
+
+	out := make([]map[string]any, 0, len(changes))
+	for _, c := range changes {
+		entry := map[string]any{
+			"id":        c.ID,
+			"name":      c.FileName,
+			"userId":    c.UserID,
+			"author":    c.Author,
+			"authorId":  c.AuthorID,
+			"content":   c.Content,
+			"diff":      c.Diff,
+			"createdAt": c.CreatedAt,
+		}
+		if c.TraceID != nil {
+			entry["traceId"] = c.TraceID
+		}
+		out = append(out, entry)
+	}
+
+	client.SendResponse(protocol.NewOKResponse(req.ID, map[string]any{
+		"agentId": params.AgentID,
+		"changes": out,
+	}))
+}
+
+// --- agents.files.approve / agents.files.reject ---
+
+func (m *AgentsMethods) handleFilesApprove(ctx context.Context, client *gateway.Client, req *protocol.RequestFrame) {
+	m.resolveFileChange(ctx, client, req, store.ContextFileChangeApproved)
+}
+
+func (m *AgentsMethods) handleFilesReject(ctx context.Context, client *gateway.Client, req *protocol.RequestFrame) {
+	m.resolveFileChange(ctx, client, req, store.ContextFileChangeRejected)
+}
+
+func (m *AgentsMethods) resolveFileChange(ctx context.Context, client *gateway.Client, req *protocol.RequestFrame, status string) {
+	locale := store.LocaleFromContext(ctx)
+	var params struct {
+		AgentID  string `json:"agentId"`
+		ChangeID string `json:"changeId"`
+	}
+	if req.Params != nil {
+		json.Unmarshal(req.Params, &params)
+	}
+	if params.AgentID == "" {
+		params.AgentID = "default"
+	}
+	changeID, err := uuid.Parse(params.ChangeID)
+	if err != nil {
+		client.SendResponse(protocol.NewErrorResponse(req.ID, protocol.ErrInvalidRequest, i18n.T(locale, i18n.MsgInvalidID, "change")))
+		return
+	}
+
+	ag, err := m.agentStore.GetByKey(ctx, params.AgentID)
+	if err != nil {
+		client.SendResponse(protocol.NewErrorResponse(req.ID, protocol.ErrNotFound, i18n.T(locale, i18n.MsgAgentNotFound, params.AgentID)))
+		return
+	}
+	if !m.canManageFiles(client, ag) {
+		client.SendResponse(protocol.NewErrorResponse(req.ID, protocol.ErrUnauthorized, i18n.T(locale, i18n.MsgOwnerOnly, "review file changes")))
+		return
+	}
+	change, err := m.files.ResolveChange(ctx, ag, changeID, status, client.UserID())
+	switch {
+	case errors.Is(err, contextfiles.ErrNotFound):
+		client.SendResponse(protocol.NewErrorResponse(req.ID, protocol.ErrNotFound, i18n.T(locale, i18n.MsgNotFound, "change", params.ChangeID)))
+		return
+	case errors.Is(err, contextfiles.ErrNotPending):
+		client.SendResponse(protocol.NewErrorResponse(req.ID, protocol.ErrInvalidRequest, i18n.T(locale, i18n.MsgInvalidRequest, "change is no longer pending")))
+		return
+	case err != nil:
+		client.SendResponse(protocol.NewErrorResponse(req.ID, protocol.ErrInternal, i18n.T(locale, i18n.MsgInternalError, err.Error())))
+		return
+	}
+
+	client.SendResponse(protocol.NewOKResponse(req.ID, map[string]any{
+		"agentId":  params.AgentID,
+		"changeId": change.ID,
+		"status":   status,
+	}))
+	emitAudit(m.eventBus, client, "agent.file_change_"+status, "agent", params.AgentID)
+}
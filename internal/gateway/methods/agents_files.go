@@ -180,7 +180,7 @@ func (m *AgentsMethods) handleFilesSet(ctx context.Context, client *gateway.Clie
 			return
 		}
 
-		if err := m.agentStore.SetAgentContextFile(ctx, ag.ID, params.Name, params.Content); err != nil {
+		if err := m.agentStore.SetAgentContextFile(fileAuthorCtx(ctx, client), ag.ID, params.Name, params.Content); err != nil {
 			client.SendResponse(protocol.NewErrorResponse(req.ID, protocol.ErrInternal, i18n.T(locale, i18n.MsgFailedToSave, "file", err.Error())))
 			return
 		}
@@ -188,7 +188,7 @@ func (m *AgentsMethods) handleFilesSet(ctx context.Context, client *gateway.Clie
 		// Propagate to all existing user instances if requested (#294).
 		var propagated int
 		if params.Propagate {
-			n, err := m.agentStore.PropagateContextFile(ctx, ag.ID, params.Name)
+			n, err := m.agentStore.PropagateContextFile(fileAuthorCtx(ctx, client), ag.ID, params.Name)
 			if err != nil {
 				slog.Warn("agents.files.set: propagation failed", "agent", params.AgentID, "file", params.Name, "error", err)
 			} else {
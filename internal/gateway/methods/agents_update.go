@@ -113,6 +113,10 @@ func (m *AgentsMethods) handleUpdate(ctx context.Context, client *gateway.Client
 			updates["context_pruning"] = []byte(params.ContextPruning)
 		}
 		if len(params.OtherConfig) > 0 {
+			if err := store.ValidateFallbackChainProviders(ctx, m.providers, params.OtherConfig); err != nil {
+				client.SendResponse(protocol.NewErrorResponse(req.ID, protocol.ErrInvalidRequest, i18n.T(locale, i18n.MsgInvalidRequest, err.Error())))
+				return
+			}
 			updates["other_config"] = []byte(params.OtherConfig)
 		}
 
@@ -149,7 +153,7 @@ func (m *AgentsMethods) handleUpdate(ctx context.Context, client *gateway.Client
 				newContent = buildIdentityContent(params.Name, "", params.Avatar)
 			}
 
-			if err := m.agentStore.SetAgentContextFile(ctx, ag.ID, "IDENTITY.md", newContent); err != nil {
+			if err := m.agentStore.SetAgentContextFile(fileAuthorCtx(ctx, client), ag.ID, "IDENTITY.md", newContent); err != nil {
 				slog.Warn("failed to update agent IDENTITY.md", "agent", params.AgentID, "error", err)
 			}
 
@@ -162,7 +166,7 @@ func (m *AgentsMethods) handleUpdate(ctx context.Context, client *gateway.Client
 						if updated == uf.Content {
 							continue // no change needed
 						}
-						if err := m.agentStore.SetUserContextFile(ctx, ag.ID, uf.UserID, "IDENTITY.md", updated); err != nil {
+						if err := m.agentStore.SetUserContextFile(fileAuthorCtx(ctx, client), ag.ID, uf.UserID, "IDENTITY.md", updated); err != nil {
 							slog.Warn("failed to update user IDENTITY.md on rename", "agent", params.AgentID, "user", uf.UserID, "error", err)
 						}
 					}
@@ -1,6 +1,8 @@
 package methods
 
 import (
+	"encoding/json"
+
 	"github.com/nextlevelbuilder/goclaw/internal/bus"
 	"github.com/nextlevelbuilder/goclaw/internal/gateway"
 	"github.com/nextlevelbuilder/goclaw/pkg/protocol"
@@ -23,3 +25,25 @@ func emitAudit(pub bus.EventPublisher, client *gateway.Client, action, entityTyp
 		},
 	})
 }
+
+// emitAuditDetails is like emitAudit but scopes the record to the client's tenant
+// and attaches structured details (marshaled to JSON).
+func emitAuditDetails(pub bus.EventPublisher, client *gateway.Client, action, entityType, entityID string, details any) {
+	if pub == nil {
+		return
+	}
+	raw, _ := json.Marshal(details)
+	pub.Broadcast(bus.Event{
+		Name: protocol.EventAuditLog,
+		Payload: bus.AuditEventPayload{
+			ActorType:  "user",
+			ActorID:    client.UserID(),
+			Action:     action,
+			EntityType: entityType,
+			EntityID:   entityID,
+			IPAddress:  client.RemoteAddr(),
+			Details:    raw,
+			TenantID:   client.TenantID(),
+		},
+	})
+}
@@ -0,0 +1,281 @@
+package methods
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/nextlevelbuilder/goclaw/internal/bus"
+	"github.com/nextlevelbuilder/goclaw/internal/gateway"
+	"github.com/nextlevelbuilder/goclaw/internal/i18n"
+	"github.com/nextlevelbuilder/goclaw/internal/store"
+	"github.com/nextlevelbuilder/goclaw/pkg/browser"
+	"github.com/nextlevelbuilder/goclaw/pkg/protocol"
+)
+
+// BrowserMethods handles browser.tabs, browser.snapshot, browser.screenshot and
+// browser.act — operator takeover of the browser an agent is driving.
+//
+// Calls run against the same per-tenant page set as the browser tool, so a
+// client only ever sees tabs owned by its tenant. The browser is never started
+// from RPC: takeover only makes sense while an agent session has it running.
+type BrowserMethods struct {
+	manager  *browser.Manager // nil when browser automation is disabled
+	eventBus bus.EventPublisher
+}
+
+func NewBrowserMethods(manager *browser.Manager, eventBus bus.EventPublisher) *BrowserMethods {
+	return &BrowserMethods{manager: manager, eventBus: eventBus}
+}
+
+func (m *BrowserMethods) Register(router *gateway.MethodRouter) {
+	router.Register(protocol.MethodBrowserTabs, m.handleTabs)
+	router.Register(protocol.MethodBrowserSnapshot, m.handleSnapshot)
+	router.Register(protocol.MethodBrowserScreenshot, m.handleScreenshot)
+	router.Register(protocol.MethodBrowserAct, m.handleAct)
+}
+
+// browserCtx scopes ctx to the caller's tenant (mirrors BrowserTool.Execute)
+// and applies the manager's per-action timeout.
+func (m *BrowserMethods) browserCtx(ctx context.Context) (context.Context, context.CancelFunc) {
+	if tid := store.TenantIDFromContext(ctx); tid != uuid.Nil {
+		ctx = browser.WithTenantID(ctx, tid.String())
+	}
+	return context.WithTimeout(ctx, m.manager.ActionTimeout())
+}
+
+// ready returns false (after responding) when the browser is unavailable.
+func (m *BrowserMethods) ready(ctx context.Context, client *gateway.Client, req *protocol.RequestFrame) bool {
+	locale := store.LocaleFromContext(ctx)
+	if m.manager == nil {
+		client.SendResponse(protocol.NewErrorResponse(req.ID, protocol.ErrInvalidRequest,
+			i18n.T(locale, i18n.MsgInvalidRequest, "browser automation is not enabled")))
+		return false
+	}
+	if !m.manager.Status().Running {
+		client.SendResponse(protocol.NewErrorResponse(req.ID, protocol.ErrInvalidRequest,
+			i18n.T(locale, i18n.MsgInvalidRequest, "browser not running")))
+		return false
+	}
+	return true
+}
+
+// resolveTarget falls back to the tenant's most recently used tab — the one
+// the agent is most likely driving — when no targetId is given.
+func (m *BrowserMethods) resolveTarget(ctx context.Context, targetID string) string {
+	if targetID != "" {
+		return targetID
+	}
+	return m.manager.ActiveTab(ctx)
+}
+
+func (m *BrowserMethods) handleTabs(ctx context.Context, client *gateway.Client, req *protocol.RequestFrame) {
+	if !m.ready(ctx, client, req) {
+		return
+	}
+	bctx, cancel := m.browserCtx(ctx)
+	defer cancel()
+
+	tabs, err := m.manager.ListTabs(bctx)
+	if err != nil {
+		client.SendResponse(protocol.NewErrorResponse(req.ID, protocol.ErrInternal, err.Error()))
+		return
+	}
+	client.SendResponse(protocol.NewOKResponse(req.ID, map[string]any{
+		"tabs":   tabs,
+		"active": m.manager.ActiveTab(bctx),
+	}))
+}
+
+func (m *BrowserMethods) handleSnapshot(ctx context.Context, client *gateway.Client, req *protocol.RequestFrame) {
+	if !m.ready(ctx, client, req) {
+		return
+	}
+	var params struct {
+		TargetID    string `json:"targetId"`
+		Interactive bool   `json:"interactive"`
+		Compact     bool   `json:"compact"`
+		MaxChars    int    `json:"maxChars"`
+		Depth       int    `json:"depth"`
+	}
+	if req.Params != nil {
+		json.Unmarshal(req.Params, &params)
+	}
+
+	bctx, cancel := m.browserCtx(ctx)
+	defer cancel()
+
+	opts := browser.DefaultSnapshotOptions()
+	opts.Interactive = params.Interactive
+	opts.Compact = params.Compact
+	opts.MaxDepth = params.Depth
+	if params.MaxChars > 0 {
+		opts.MaxChars = params.MaxChars
+	}
+
+	// Snapshot refreshes the ref cache for this tab, so refs returned here are
+	// immediately usable by browser.act (and by the agent's next action).
+	snap, err := m.manager.Snapshot(bctx, m.resolveTarget(bctx, params.TargetID), opts)
+	if err != nil {
+		client.SendResponse(protocol.NewErrorResponse(req.ID, protocol.ErrInternal, fmt.Sprintf("snapshot failed: %v", err)))
+		return
+	}
+	client.SendResponse(protocol.NewOKResponse(req.ID, snap))
+}
+
+func (m *BrowserMethods) handleScreenshot(ctx context.Context, client *gateway.Client, req *protocol.RequestFrame) {
+	if !m.ready(ctx, client, req) {
+		return
+	}
+	var params struct {
+		TargetID string `json:"targetId"`
+		FullPage bool   `json:"fullPage"`
+	}
+	if req.Params != nil {
+		json.Unmarshal(req.Params, &params)
+	}
+
+	bctx, cancel := m.browserCtx(ctx)
+	defer cancel()
+
+	targetID := m.resolveTarget(bctx, params.TargetID)
+	data, err := m.manager.Screenshot(bctx, targetID, params.FullPage)
+	if err != nil {
+		client.SendResponse(protocol.NewErrorResponse(req.ID, protocol.ErrInternal, fmt.Sprintf("screenshot failed: %v", err)))
+		return
+	}
+	client.SendResponse(protocol.NewOKResponse(req.ID, map[string]any{
+		"targetId": targetID,
+		"mimeType": "image/png",
+		"data":     base64.StdEncoding.EncodeToString(data),
+	}))
+}
+
+var browserActKinds = []string{"click", "type", "press", "hover", "wait", "evaluate", "navigate"}
+
+// browserActParams is the browser.act request. Field names match the browser
+// tool's act request so dashboard and agent actions are interchangeable.
+type browserActParams struct {
+	TargetID    string `json:"targetId"`
+	Kind        string `json:"kind"` // click, type, press, hover, wait, evaluate, navigate
+	Ref         string `json:"ref"`
+	Text        string `json:"text"`
+	Key         string `json:"key"`
+	URL         string `json:"url"`
+	Submit      bool   `json:"submit"`
+	Slowly      bool   `json:"slowly"`
+	DoubleClick bool   `json:"doubleClick"`
+	Button      string `json:"button"`
+	Fn          string `json:"fn"`
+	TimeMs      int    `json:"timeMs"`
+	TextGone    string `json:"textGone"`
+}
+
+func (m *BrowserMethods) handleAct(ctx context.Context, client *gateway.Client, req *protocol.RequestFrame) {
+	locale := store.LocaleFromContext(ctx)
+	if !m.ready(ctx, client, req) {
+		return
+	}
+	var params browserActParams
+	if req.Params != nil {
+		json.Unmarshal(req.Params, &params)
+	}
+	if params.Kind == "" {
+		client.SendResponse(protocol.NewErrorResponse(req.ID, protocol.ErrInvalidRequest, i18n.T(locale, i18n.MsgRequired, "kind")))
+		return
+	}
+	if !slices.Contains(browserActKinds, params.Kind) {
+		client.SendResponse(protocol.NewErrorResponse(req.ID, protocol.ErrInvalidRequest,
+			i18n.T(locale, i18n.MsgInvalidRequest, "unknown act kind: "+params.Kind)))
+		return
+	}
+
+	bctx, cancel := m.browserCtx(ctx)
+	defer cancel()
+	params.TargetID = m.resolveTarget(bctx, params.TargetID)
+
+	start := time.Now()
+	result, err := m.runAct(bctx, params)
+	if missing, ok := err.(errMissingParam); ok {
+		client.SendResponse(protocol.NewErrorResponse(req.ID, protocol.ErrInvalidRequest, i18n.T(locale, i18n.MsgRequired, string(missing))))
+		return
+	}
+
+	details := map[string]any{
+		"kind":       params.Kind,
+		"targetId":   params.TargetID,
+		"ref":        params.Ref,
+		"durationMs": time.Since(start).Milliseconds(),
+	}
+	if params.URL != "" {
+		details["url"] = params.URL
+	}
+	if err != nil {
+		details["error"] = err.Error()
+	}
+	// Typed text and evaluated scripts may contain secrets — only their size is audited.
+	if params.Text != "" {
+		details["textLen"] = len(params.Text)
+	}
+	emitAuditDetails(m.eventBus, client, "browser.act", "browser_tab", params.TargetID, details)
+
+	if err != nil {
+		client.SendResponse(protocol.NewErrorResponse(req.ID, protocol.ErrInternal, fmt.Sprintf("%s failed: %v", params.Kind, err)))
+		return
+	}
+	client.SendResponse(protocol.NewOKResponse(req.ID, browser.ActResult{
+		OK:       true,
+		TargetID: params.TargetID,
+		URL:      params.URL,
+		Result:   result,
+	}))
+}
+
+// errMissingParam reports a required act parameter that was not supplied.
+type errMissingParam string
+
+func (e errMissingParam) Error() string { return string(e) + " is required" }
+
+func (m *BrowserMethods) runAct(ctx context.Context, p browserActParams) (string, error) {
+	switch p.Kind {
+	case "click":
+		if p.Ref == "" {
+			return "", errMissingParam("ref")
+		}
+		return "", m.manager.Click(ctx, p.TargetID, p.Ref, browser.ClickOpts{DoubleClick: p.DoubleClick, Button: p.Button})
+	case "type":
+		if p.Ref == "" {
+			return "", errMissingParam("ref")
+		}
+		return "", m.manager.Type(ctx, p.TargetID, p.Ref, p.Text, browser.TypeOpts{Submit: p.Submit, Slowly: p.Slowly})
+	case "press":
+		if p.Key == "" {
+			return "", errMissingParam("key")
+		}
+		return "", m.manager.Press(ctx, p.TargetID, p.Key)
+	case "hover":
+		if p.Ref == "" {
+			return "", errMissingParam("ref")
+		}
+		return "", m.manager.Hover(ctx, p.TargetID, p.Ref)
+	case "wait":
+		return "", m.manager.Wait(ctx, p.TargetID, browser.WaitOpts{TimeMs: p.TimeMs, Text: p.Text, TextGone: p.TextGone, URL: p.URL, Fn: p.Fn})
+	case "evaluate":
+		if p.Fn == "" {
+			return "", errMissingParam("fn")
+		}
+		return m.manager.Evaluate(ctx, p.TargetID, p.Fn)
+	case "navigate":
+		if p.URL == "" {
+			return "", errMissingParam("url")
+		}
+		return "", m.manager.Navigate(ctx, p.TargetID, p.URL)
+	default:
+		return "", fmt.Errorf("unknown act kind: %s", p.Kind)
+	}
+}
@@ -409,7 +409,7 @@ func (m *HeartbeatMethods) handleChecklistSet(ctx context.Context, client *gatew
 		return
 	}
 
-	if err := m.agentStore.SetAgentContextFile(ctx, agentUUID, "HEARTBEAT.md", params.Content); err != nil {
+	if err := m.agentStore.SetAgentContextFile(fileAuthorCtx(ctx, client), agentUUID, "HEARTBEAT.md", params.Content); err != nil {
 		client.SendResponse(protocol.NewErrorResponse(req.ID, protocol.ErrInternal, heartbeatInternalErr("op", err)))
 		return
 	}
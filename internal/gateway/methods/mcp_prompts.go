@@ -0,0 +1,105 @@
+package methods
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/nextlevelbuilder/goclaw/internal/agent"
+	"github.com/nextlevelbuilder/goclaw/internal/gateway"
+	"github.com/nextlevelbuilder/goclaw/internal/i18n"
+	mcpbridge "github.com/nextlevelbuilder/goclaw/internal/mcp"
+	"github.com/nextlevelbuilder/goclaw/internal/store"
+	"github.com/nextlevelbuilder/goclaw/pkg/protocol"
+)
+
+// MCPPromptsMethods handles mcp.prompts.list and mcp.prompts.get: prompt
+// templates from the MCP servers an agent's grants expose prompts for.
+type MCPPromptsMethods struct {
+	agents *agent.Router
+}
+
+func NewMCPPromptsMethods(agents *agent.Router) *MCPPromptsMethods {
+	return &MCPPromptsMethods{agents: agents}
+}
+
+func (m *MCPPromptsMethods) Register(router *gateway.MethodRouter) {
+	router.Register(protocol.MethodMCPPromptsList, m.handleList)
+	router.Register(protocol.MethodMCPPromptsGet, m.handleGet)
+}
+
+// manager resolves the agent's MCP manager; nil means no prompts.
+func (m *MCPPromptsMethods) manager(ctx context.Context, agentID string) (*mcpbridge.Manager, error) {
+	ag, err := m.agents.Get(ctx, agentID)
+	if err != nil {
+		return nil, err
+	}
+	if loop, ok := ag.(*agent.Loop); ok {
+		return loop.MCPManager(), nil
+	}
+	return nil, nil
+}
+
+func (m *MCPPromptsMethods) handleList(ctx context.Context, client *gateway.Client, req *protocol.RequestFrame) {
+	var params struct {
+		AgentID string `json:"agentId"`
+	}
+	if req.Params != nil {
+		json.Unmarshal(req.Params, &params)
+	}
+	if params.AgentID == "" {
+		params.AgentID = "default"
+	}
+
+	mgr, err := m.manager(ctx, params.AgentID)
+	if err != nil {
+		client.SendResponse(protocol.NewErrorResponse(req.ID, protocol.ErrNotFound, err.Error()))
+		return
+	}
+	prompts := []mcpbridge.PromptInfo{}
+	if mgr != nil {
+		if list := mgr.Prompts(ctx); list != nil {
+			prompts = list
+		}
+	}
+	client.SendResponse(protocol.NewOKResponse(req.ID, map[string]any{"prompts": prompts}))
+}
+
+func (m *MCPPromptsMethods) handleGet(ctx context.Context, client *gateway.Client, req *protocol.RequestFrame) {
+	locale := store.LocaleFromContext(ctx)
+	var params struct {
+		AgentID   string            `json:"agentId"`
+		Server    string            `json:"server"`
+		Name      string            `json:"name"`
+		Arguments map[string]string `json:"arguments"`
+	}
+	if req.Params != nil {
+		json.Unmarshal(req.Params, &params)
+	}
+	if params.AgentID == "" {
+		params.AgentID = "default"
+	}
+	if params.Server == "" || params.Name == "" {
+		client.SendResponse(protocol.NewErrorResponse(req.ID, protocol.ErrInvalidRequest, i18n.T(locale, i18n.MsgRequired, "server and name")))
+		return
+	}
+
+	mgr, err := m.manager(ctx, params.AgentID)
+	if err != nil {
+		client.SendResponse(protocol.NewErrorResponse(req.ID, protocol.ErrNotFound, err.Error()))
+		return
+	}
+	if mgr == nil {
+		client.SendResponse(protocol.NewErrorResponse(req.ID, protocol.ErrNotFound, "no MCP prompts are available for this agent"))
+		return
+	}
+	text, err := mgr.GetPrompt(ctx, params.Server, params.Name, params.Arguments)
+	if err != nil {
+		client.SendResponse(protocol.NewErrorResponse(req.ID, protocol.ErrInvalidRequest, err.Error()))
+		return
+	}
+	client.SendResponse(protocol.NewOKResponse(req.ID, map[string]any{
+		"server": params.Server,
+		"name":   params.Name,
+		"text":   text,
+	}))
+}
@@ -21,8 +21,10 @@ import (
 // maxCommentLength caps comment/reason content to prevent DB bloat.
 const maxCommentLength = 10000
 
-func taskBusEvent(name string, payload any) bus.Event {
-	return bus.Event{Name: name, Payload: payload}
+// taskBusEvent scopes a task event to the caller's tenant so it reaches
+// tenant-scoped subscribers and WS clients, not only owners.
+func taskBusEvent(ctx context.Context, name string, payload any) bus.Event {
+	return bus.Event{Name: name, TenantID: store.TenantIDFromContext(ctx), Payload: payload}
 }
 
 func taskNowUTC() string {
@@ -57,6 +59,7 @@ func (m *TeamsMethods) RegisterTasks(router *gateway.MethodRouter) {
 	router.Register(protocol.MethodTeamsTaskDelete, m.handleTaskDelete)
 	router.Register(protocol.MethodTeamsTaskDeleteBulk, m.handleTaskDeleteBulk)
 	router.Register(protocol.MethodTeamsTaskAssign, m.handleTaskAssign)
+	router.Register(protocol.MethodTeamsTaskGraph, m.handleTaskGraph)
 }
 
 // --- Task Get (with comments + events + attachments) ---
@@ -212,7 +215,7 @@ func (m *TeamsMethods) handleTaskApprove(ctx context.Context, client *gateway.Cl
 	client.SendResponse(protocol.NewOKResponse(req.ID, map[string]any{"ok": true}))
 
 	if m.msgBus != nil {
-		m.msgBus.Broadcast(taskBusEvent(protocol.EventTeamTaskApproved, protocol.TeamTaskEventPayload{
+		m.msgBus.Broadcast(taskBusEvent(ctx, protocol.EventTeamTaskApproved, protocol.TeamTaskEventPayload{
 			TeamID:    teamID.String(),
 			TaskID:    taskID.String(),
 			Status:    store.TeamTaskStatusCompleted,
@@ -269,7 +272,7 @@ func (m *TeamsMethods) handleTaskReject(ctx context.Context, client *gateway.Cli
 	client.SendResponse(protocol.NewOKResponse(req.ID, map[string]any{"ok": true}))
 
 	if m.msgBus != nil {
-		m.msgBus.Broadcast(taskBusEvent(protocol.EventTeamTaskRejected, protocol.TeamTaskEventPayload{
+		m.msgBus.Broadcast(taskBusEvent(ctx, protocol.EventTeamTaskRejected, protocol.TeamTaskEventPayload{
 			TeamID:    teamID.String(),
 			TaskID:    taskID.String(),
 			Status:    store.TeamTaskStatusCancelled,
@@ -342,7 +345,7 @@ func (m *TeamsMethods) handleTaskComment(ctx context.Context, client *gateway.Cl
 		if runes := []rune(commentPreview); len(runes) > 500 {
 			commentPreview = string(runes[:500]) + "..."
 		}
-		m.msgBus.Broadcast(taskBusEvent(protocol.EventTeamTaskCommented, protocol.TeamTaskEventPayload{
+		m.msgBus.Broadcast(taskBusEvent(ctx, protocol.EventTeamTaskCommented, protocol.TeamTaskEventPayload{
 			TeamID:      teamID.String(),
 			TaskID:      taskID.String(),
 			TaskNumber:  task.TaskNumber,
@@ -0,0 +1,58 @@
+package methods
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/google/uuid"
+
+	"github.com/nextlevelbuilder/goclaw/internal/gateway"
+	"github.com/nextlevelbuilder/goclaw/internal/i18n"
+	"github.com/nextlevelbuilder/goclaw/internal/store"
+	"github.com/nextlevelbuilder/goclaw/pkg/protocol"
+)
+
+// --- Task Graph (dependency DAG for dashboard visualization) ---
+
+// graphTaskLimit caps the tasks loaded into one graph, matching the list view.
+const graphTaskLimit = 200
+
+func (m *TeamsMethods) handleTaskGraph(ctx context.Context, client *gateway.Client, req *protocol.RequestFrame) {
+	var params teamsTaskListParams
+	locale, ok := m.parseTaskParams(ctx, client, req, &params)
+	if !ok {
+		return
+	}
+
+	teamID, err := uuid.Parse(params.TeamID)
+	if err != nil {
+		client.SendResponse(protocol.NewErrorResponse(req.ID, protocol.ErrInvalidRequest, i18n.T(locale, i18n.MsgInvalidID, "teamId")))
+		return
+	}
+
+	// Default to all statuses: completed prerequisites are part of the graph.
+	status := params.Status
+	if status == "" {
+		status = store.TeamTaskFilterAll
+	}
+	tasks, err := m.teamStore.ListTasks(ctx, teamID, "newest", status, "", params.Channel, params.ChatID, graphTaskLimit, 0)
+	if err != nil {
+		slog.Warn("teams.tasks.graph failed", "team_id", teamID, "error", err)
+		client.SendResponse(protocol.NewErrorResponse(req.ID, protocol.ErrInternal, i18n.T(locale, i18n.MsgInternalError, "")))
+		return
+	}
+	truncated := len(tasks) > graphTaskLimit
+	if truncated {
+		tasks = tasks[:graphTaskLimit]
+	}
+
+	g := store.BuildTaskGraph(tasks)
+	client.SendResponse(protocol.NewOKResponse(req.ID, map[string]any{
+		"nodes":                g.Nodes,
+		"edges":                g.Edges,
+		"critical_path":        g.CriticalPath,
+		"critical_path_weight": g.CriticalPathWeight,
+		"cycle":                g.Cycle,
+		"truncated":            truncated,
+	}))
+}
@@ -0,0 +1,151 @@
+package methods
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/google/uuid"
+
+	"github.com/nextlevelbuilder/goclaw/internal/gateway"
+	"github.com/nextlevelbuilder/goclaw/internal/i18n"
+	"github.com/nextlevelbuilder/goclaw/internal/permissions"
+	"github.com/nextlevelbuilder/goclaw/internal/store"
+	"github.com/nextlevelbuilder/goclaw/internal/tools"
+	"github.com/nextlevelbuilder/goclaw/pkg/protocol"
+)
+
+// ApprovalCaller is the WS client deciding a tool approval. It travels to the
+// replica holding the request when the request is pending elsewhere.
+type ApprovalCaller struct {
+	TenantID uuid.UUID `json:"tenant_id"`
+	UserID   string    `json:"user_id"`
+	Admin    bool      `json:"admin,omitempty"`
+	Owner    bool      `json:"owner,omitempty"`
+}
+
+// ApprovalForwarder decides a request pending on another gateway replica.
+// It returns tools.ErrApprovalNotFound when no replica holds the request.
+type ApprovalForwarder func(ctx context.Context, caller ApprovalCaller, id string, decision tools.ApprovalDecision) error
+
+// ToolApprovalMethods handles tool.approval.list, tool.approval.approve, tool.approval.deny.
+// Admins decide any request of their tenant; other users only their own.
+// Decisions are audited by the manager.
+type ToolApprovalMethods struct {
+	manager *tools.ToolApprovalManager
+	forward ApprovalForwarder // cluster mode: requests pending on other replicas
+}
+
+func NewToolApprovalMethods(manager *tools.ToolApprovalManager) *ToolApprovalMethods {
+	return &ToolApprovalMethods{manager: manager}
+}
+
+// SetForwarder routes decisions on requests this replica does not hold.
+func (m *ToolApprovalMethods) SetForwarder(fn ApprovalForwarder) { m.forward = fn }
+
+func (m *ToolApprovalMethods) Register(router *gateway.MethodRouter) {
+	router.Register(protocol.MethodToolApprovalsList, m.handleList)
+	router.Register(protocol.MethodToolApprovalsApprove, m.handleApprove)
+	router.Register(protocol.MethodToolApprovalsDeny, m.handleDeny)
+}
+
+func approvalCaller(client *gateway.Client) ApprovalCaller {
+	return ApprovalCaller{
+		TenantID: client.TenantID(),
+		UserID:   client.UserID(),
+		Admin:    permissions.HasMinRole(client.Role(), permissions.RoleAdmin),
+		Owner:    client.IsOwner(),
+	}
+}
+
+// visible reports whether the caller may see and decide the request.
+func visible(caller ApprovalCaller, req *tools.ToolApprovalRequest) bool {
+	if !caller.Owner && req.TenantID != caller.TenantID {
+		return false
+	}
+	return caller.Admin || req.UserID == caller.UserID
+}
+
+func (m *ToolApprovalMethods) handleList(_ context.Context, client *gateway.Client, req *protocol.RequestFrame) {
+	items := []*tools.ToolApprovalRequest{}
+	if m.manager != nil {
+		caller := approvalCaller(client)
+		for _, pa := range m.manager.ListPending() {
+			if visible(caller, pa) {
+				items = append(items, pa)
+			}
+		}
+	}
+	client.SendResponse(protocol.NewOKResponse(req.ID, map[string]any{
+		"pending": items,
+	}))
+}
+
+func (m *ToolApprovalMethods) handleApprove(ctx context.Context, client *gateway.Client, req *protocol.RequestFrame) {
+	var params struct {
+		ID      string `json:"id"`
+		Session bool   `json:"session"` // true = allow the tool for the rest of the session
+	}
+	if req.Params != nil {
+		json.Unmarshal(req.Params, &params)
+	}
+	decision := tools.ApprovalAllowOnce
+	if params.Session {
+		decision = tools.ApprovalAllowSession
+	}
+	m.resolve(ctx, client, req, params.ID, decision)
+}
+
+func (m *ToolApprovalMethods) handleDeny(ctx context.Context, client *gateway.Client, req *protocol.RequestFrame) {
+	var params struct {
+		ID string `json:"id"`
+	}
+	if req.Params != nil {
+		json.Unmarshal(req.Params, &params)
+	}
+	m.resolve(ctx, client, req, params.ID, tools.ApprovalDeny)
+}
+
+func (m *ToolApprovalMethods) resolve(ctx context.Context, client *gateway.Client, req *protocol.RequestFrame, id string, decision tools.ApprovalDecision) {
+	locale := store.LocaleFromContext(ctx)
+	if m.manager == nil {
+		client.SendResponse(protocol.NewErrorResponse(req.ID, protocol.ErrInvalidRequest, i18n.T(locale, i18n.MsgToolApprovalDisabled)))
+		return
+	}
+	if id == "" {
+		client.SendResponse(protocol.NewErrorResponse(req.ID, protocol.ErrInvalidRequest, i18n.T(locale, i18n.MsgRequired, "id")))
+		return
+	}
+	caller := approvalCaller(client)
+	err := m.Resolve(caller, id, decision)
+	if errors.Is(err, tools.ErrApprovalNotFound) && m.forward != nil {
+		err = m.forward(ctx, caller, id, decision)
+	}
+	if err != nil {
+		code := protocol.ErrNotFound
+		if !errors.Is(err, tools.ErrApprovalNotFound) {
+			code = protocol.ErrInvalidRequest
+		}
+		client.SendResponse(protocol.NewErrorResponse(req.ID, code, err.Error()))
+		return
+	}
+	client.SendResponse(protocol.NewOKResponse(req.ID, map[string]any{
+		"resolved": true,
+		"decision": string(decision),
+	}))
+}
+
+// Resolve decides a request pending on this replica on behalf of caller.
+// Requests the caller may not see are reported as not found.
+func (m *ToolApprovalMethods) Resolve(caller ApprovalCaller, id string, decision tools.ApprovalDecision) error {
+	pending, ok := m.manager.Get(id)
+	if !ok || !visible(caller, pending) {
+		return tools.ErrApprovalNotFound
+	}
+
+	// Admins decide for their tenant; anyone else (the requester on their
+	// own run) goes through the same approver check as channel buttons.
+	actor := tools.ApprovalActor{ID: caller.UserID, Via: "ws", Trusted: caller.Admin}
+	_, err := m.manager.Resolve(id, decision, actor)
+	return err
+}
@@ -0,0 +1,358 @@
+package methods
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/nextlevelbuilder/goclaw/internal/bus"
+	"github.com/nextlevelbuilder/goclaw/internal/gateway"
+	httpapi "github.com/nextlevelbuilder/goclaw/internal/http"
+	"github.com/nextlevelbuilder/goclaw/internal/i18n"
+	"github.com/nextlevelbuilder/goclaw/internal/media"
+	"github.com/nextlevelbuilder/goclaw/internal/store"
+	"github.com/nextlevelbuilder/goclaw/internal/tts"
+	"github.com/nextlevelbuilder/goclaw/pkg/protocol"
+)
+
+// ttsConvertMaxChars caps tts.convert input so a single RPC can't run a long synthesis.
+const ttsConvertMaxChars = 4000
+
+// TTSManagerSource returns the live TTS manager. The manager is swapped on
+// config reload, so handlers must not cache it.
+type TTSManagerSource interface {
+	Manager() *tts.Manager
+}
+
+// TTSMethods handles tts.status, tts.providers, tts.enable, tts.disable,
+// tts.setProvider and tts.convert.
+//
+// Runtime changes are persisted in system_configs (tenant-scoped) and announced
+// via TopicSystemConfigChanged so every gateway replica reloads them from the store.
+type TTSMethods struct {
+	source     TTSManagerSource
+	sysConfigs store.SystemConfigStore // nil-safe; changes are in-memory only when nil
+	agents     store.AgentStore        // nil-safe; used to resolve agent keys
+	mediaStore *media.Store            // nil-safe; tts.convert is unavailable when nil
+	eventBus   bus.EventPublisher
+}
+
+func NewTTSMethods(source TTSManagerSource, sysConfigs store.SystemConfigStore, agents store.AgentStore, mediaStore *media.Store, eventBus bus.EventPublisher) *TTSMethods {
+	return &TTSMethods{source: source, sysConfigs: sysConfigs, agents: agents, mediaStore: mediaStore, eventBus: eventBus}
+}
+
+func (m *TTSMethods) Register(router *gateway.MethodRouter) {
+	router.Register(protocol.MethodTTSStatus, m.handleStatus)
+	router.Register(protocol.MethodTTSProviders, m.handleProviders)
+	router.Register(protocol.MethodTTSEnable, m.handleEnable)
+	router.Register(protocol.MethodTTSDisable, m.handleDisable)
+	router.Register(protocol.MethodTTSSetProvider, m.handleSetProvider)
+	router.Register(protocol.MethodTTSConvert, m.handleConvert)
+}
+
+// resolveAgentID accepts an agent UUID or agent key and returns the UUID string.
+// Empty input returns "" (tenant scope). The agent must belong to the caller's
+// tenant, so one tenant cannot read or write another tenant's agent overrides.
+func (m *TTSMethods) resolveAgentID(ctx context.Context, agentID string) (string, error) {
+	if agentID == "" {
+		return "", nil
+	}
+	if m.agents == nil {
+		return "", fmt.Errorf("agent not found: %s", agentID)
+	}
+	var (
+		ag  *store.AgentData
+		err error
+	)
+	if id, perr := uuid.Parse(agentID); perr == nil {
+		ag, err = m.agents.GetByID(ctx, id)
+	} else {
+		ag, err = m.agents.GetByKey(ctx, agentID)
+	}
+	if err != nil || ag == nil || ag.TenantID != store.TenantIDFromContext(ctx) {
+		return "", fmt.Errorf("agent not found: %s", agentID)
+	}
+	return ag.ID.String(), nil
+}
+
+func (m *TTSMethods) handleStatus(ctx context.Context, client *gateway.Client, req *protocol.RequestFrame) {
+	var params struct {
+		AgentID string `json:"agentId"`
+	}
+	if req.Params != nil {
+		json.Unmarshal(req.Params, &params)
+	}
+	agentID, err := m.resolveAgentID(ctx, params.AgentID)
+	if err != nil {
+		client.SendResponse(protocol.NewErrorResponse(req.ID, protocol.ErrInvalidRequest, err.Error()))
+		return
+	}
+
+	mgr := m.source.Manager()
+	tenantID := store.TenantIDFromContext(ctx).String()
+	effective := mgr.Resolve(tenantID, agentID)
+	tenantScope, agentScope := mgr.Scope(tenantID, agentID)
+
+	client.SendResponse(protocol.NewOKResponse(req.ID, map[string]any{
+		"enabled":   effective.Auto != tts.AutoOff,
+		"auto":      effective.Auto,
+		"mode":      mgr.Mode(),
+		"provider":  effective.Provider,
+		"primary":   mgr.PrimaryProvider(),
+		"providers": mgr.ProviderNames(),
+		"overrides": map[string]any{
+			"tenant": tenantScope,
+			"agent":  agentScope,
+		},
+	}))
+}
+
+func (m *TTSMethods) handleProviders(ctx context.Context, client *gateway.Client, req *protocol.RequestFrame) {
+	type providerInfo struct {
+		Name    string      `json:"name"`
+		Primary bool        `json:"primary"`
+		Active  bool        `json:"active"`
+		Voices  []tts.Voice `json:"voices"`
+	}
+
+	mgr := m.source.Manager()
+	active := mgr.Resolve(store.TenantIDFromContext(ctx).String(), "").Provider
+	names := mgr.ProviderNames()
+	items := make([]providerInfo, 0, len(names))
+	for _, name := range names {
+		info := providerInfo{
+			Name:    name,
+			Primary: name == mgr.PrimaryProvider(),
+			Active:  name == active,
+			Voices:  []tts.Voice{},
+		}
+		if p, ok := mgr.GetProvider(name); ok {
+			if vl, ok := p.(tts.VoiceLister); ok {
+				info.Voices = vl.Voices()
+			}
+		}
+		items = append(items, info)
+	}
+
+	client.SendResponse(protocol.NewOKResponse(req.ID, map[string]any{
+		"providers": items,
+	}))
+}
+
+func (m *TTSMethods) handleEnable(ctx context.Context, client *gateway.Client, req *protocol.RequestFrame) {
+	locale := store.LocaleFromContext(ctx)
+	var params struct {
+		AgentID string `json:"agentId"`
+		Mode    string `json:"mode"` // always (default), inbound, tagged
+	}
+	if req.Params != nil {
+		json.Unmarshal(req.Params, &params)
+	}
+	if params.Mode == "" {
+		params.Mode = string(tts.AutoAlways)
+	}
+	if !tts.ValidAutoMode(params.Mode) || tts.AutoMode(params.Mode) == tts.AutoOff {
+		client.SendResponse(protocol.NewErrorResponse(req.ID, protocol.ErrInvalidRequest,
+			i18n.T(locale, i18n.MsgInvalidRequest, "mode must be always, inbound, or tagged")))
+		return
+	}
+	m.setField(ctx, client, req, params.AgentID, "auto", params.Mode, "tts.enabled")
+}
+
+func (m *TTSMethods) handleDisable(ctx context.Context, client *gateway.Client, req *protocol.RequestFrame) {
+	var params struct {
+		AgentID string `json:"agentId"`
+	}
+	if req.Params != nil {
+		json.Unmarshal(req.Params, &params)
+	}
+	m.setField(ctx, client, req, params.AgentID, "auto", string(tts.AutoOff), "tts.disabled")
+}
+
+func (m *TTSMethods) handleSetProvider(ctx context.Context, client *gateway.Client, req *protocol.RequestFrame) {
+	locale := store.LocaleFromContext(ctx)
+	var params struct {
+		AgentID  string `json:"agentId"`
+		Provider string `json:"provider"` // empty clears an agent override
+	}
+	if req.Params != nil {
+		json.Unmarshal(req.Params, &params)
+	}
+	if params.Provider == "" && params.AgentID == "" {
+		client.SendResponse(protocol.NewErrorResponse(req.ID, protocol.ErrInvalidRequest, i18n.T(locale, i18n.MsgRequired, "provider")))
+		return
+	}
+	if params.Provider != "" {
+		if _, ok := m.source.Manager().GetProvider(params.Provider); !ok {
+			client.SendResponse(protocol.NewErrorResponse(req.ID, protocol.ErrNotFound,
+				i18n.T(locale, i18n.MsgNotFound, "tts provider", params.Provider)))
+			return
+		}
+	}
+	m.setField(ctx, client, req, params.AgentID, "provider", params.Provider, "tts.provider_changed")
+}
+
+// setField persists a tenant- or agent-level override, applies it locally and
+// broadcasts the change. An empty value on an agent scope removes the override.
+func (m *TTSMethods) setField(ctx context.Context, client *gateway.Client, req *protocol.RequestFrame, rawAgentID, field, value, auditAction string) {
+	locale := store.LocaleFromContext(ctx)
+	agentID, err := m.resolveAgentID(ctx, rawAgentID)
+	if err != nil {
+		client.SendResponse(protocol.NewErrorResponse(req.ID, protocol.ErrInvalidRequest, err.Error()))
+		return
+	}
+
+	key := "tts." + field
+	if agentID != "" {
+		key = tts.AgentConfigKey(agentID, field)
+	}
+
+	tenantID := store.TenantIDFromContext(ctx)
+	if m.sysConfigs != nil {
+		if value == "" {
+			err = m.sysConfigs.Delete(ctx, key)
+		} else {
+			err = m.sysConfigs.Set(ctx, key, value)
+		}
+		if err != nil {
+			slog.Warn("tts: persist setting failed", "key", key, "error", err)
+			client.SendResponse(protocol.NewErrorResponse(req.ID, protocol.ErrInternal,
+				i18n.T(locale, i18n.MsgFailedToSave, "tts setting", err.Error())))
+			return
+		}
+		if configs, err := m.sysConfigs.List(ctx); err == nil {
+			tenant, agents := tts.ParseScopeConfigs(configs)
+			m.source.Manager().SetTenantScope(tenantID.String(), tenant, agents)
+		}
+	} else {
+		m.applyInMemory(tenantID.String(), agentID, field, value)
+	}
+
+	if m.eventBus != nil {
+		// Other replicas reload tenant system_configs on this topic.
+		freshCtx := store.WithTenantID(context.Background(), tenantID)
+		m.eventBus.Broadcast(bus.Event{Name: bus.TopicSystemConfigChanged, Payload: freshCtx})
+	}
+
+	mgr := m.source.Manager()
+	effective := mgr.Resolve(tenantID.String(), agentID)
+	result := map[string]any{
+		"ok":       true,
+		"agentId":  agentID,
+		"auto":     effective.Auto,
+		"provider": effective.Provider,
+	}
+	client.SendResponse(protocol.NewOKResponse(req.ID, result))
+
+	if m.eventBus != nil {
+		m.eventBus.Broadcast(bus.Event{Name: protocol.EventTTSChanged, Payload: result, TenantID: tenantID})
+	}
+	entityID := agentID
+	if entityID == "" {
+		entityID = tenantID.String()
+	}
+	emitAudit(m.eventBus, client, auditAction, "tts", entityID)
+}
+
+// applyInMemory updates scope overrides when no system config store is wired.
+func (m *TTSMethods) applyInMemory(tenantID, agentID, field, value string) {
+	mgr := m.source.Manager()
+	tenant, agent := mgr.Scope(tenantID, agentID)
+	target := &tenant
+	if agentID != "" {
+		target = &agent
+	}
+	switch field {
+	case "provider":
+		target.Provider = value
+	case "auto":
+		target.Auto = tts.AutoMode(value)
+	}
+	agents := map[string]tts.ScopeSettings{}
+	if agentID != "" {
+		agents[agentID] = agent
+	}
+	mgr.SetTenantScope(tenantID, tenant, agents)
+}
+
+func (m *TTSMethods) handleConvert(ctx context.Context, client *gateway.Client, req *protocol.RequestFrame) {
+	locale := store.LocaleFromContext(ctx)
+	var params struct {
+		Text     string `json:"text"`
+		AgentID  string `json:"agentId"`
+		Provider string `json:"provider"`
+		Voice    string `json:"voice"`
+		Model    string `json:"model"`
+		Format   string `json:"format"` // mp3 (default) or opus
+	}
+	if req.Params != nil {
+		json.Unmarshal(req.Params, &params)
+	}
+	if params.Text == "" {
+		client.SendResponse(protocol.NewErrorResponse(req.ID, protocol.ErrInvalidRequest, i18n.T(locale, i18n.MsgRequired, "text")))
+		return
+	}
+	if len([]rune(params.Text)) > ttsConvertMaxChars {
+		client.SendResponse(protocol.NewErrorResponse(req.ID, protocol.ErrInvalidRequest,
+			i18n.T(locale, i18n.MsgInvalidRequest, fmt.Sprintf("text exceeds %d characters", ttsConvertMaxChars))))
+		return
+	}
+	if m.mediaStore == nil {
+		client.SendResponse(protocol.NewErrorResponse(req.ID, protocol.ErrInternal,
+			i18n.T(locale, i18n.MsgInternalError, "media store not configured")))
+		return
+	}
+	agentID, err := m.resolveAgentID(ctx, params.AgentID)
+	if err != nil {
+		client.SendResponse(protocol.NewErrorResponse(req.ID, protocol.ErrInvalidRequest, err.Error()))
+		return
+	}
+
+	mgr := m.source.Manager()
+	tenantID := store.TenantIDFromContext(ctx)
+	providerName := params.Provider
+	if providerName == "" {
+		providerName = mgr.Resolve(tenantID.String(), agentID).Provider
+	} else if _, ok := mgr.GetProvider(providerName); !ok {
+		client.SendResponse(protocol.NewErrorResponse(req.ID, protocol.ErrNotFound,
+			i18n.T(locale, i18n.MsgNotFound, "tts provider", providerName)))
+		return
+	}
+
+	opts := tts.Options{Voice: params.Voice, Model: params.Model, Format: params.Format}
+	result, err := mgr.SynthesizePreferring(ctx, providerName, params.Text, opts)
+	if err != nil {
+		client.SendResponse(protocol.NewErrorResponse(req.ID, protocol.ErrInternal,
+			i18n.T(locale, i18n.MsgInternalError, "tts failed: "+err.Error())))
+		return
+	}
+
+	tmpPath := filepath.Join(os.TempDir(), fmt.Sprintf("tts-convert-%d.%s", time.Now().UnixNano(), result.Extension))
+	if err := os.WriteFile(tmpPath, result.Audio, 0644); err != nil {
+		client.SendResponse(protocol.NewErrorResponse(req.ID, protocol.ErrInternal, i18n.T(locale, i18n.MsgInternalError, err.Error())))
+		return
+	}
+	// Scope converted audio per tenant so it is cleaned up with the tenant's media.
+	mediaID, _, err := m.mediaStore.SaveFile("tts:"+tenantID.String(), tmpPath, result.MimeType)
+	if err != nil {
+		os.Remove(tmpPath)
+		client.SendResponse(protocol.NewErrorResponse(req.ID, protocol.ErrInternal, i18n.T(locale, i18n.MsgInternalError, err.Error())))
+		return
+	}
+
+	urlPath := "/v1/media/" + mediaID
+	ft := httpapi.SignFileToken(urlPath, httpapi.FileSigningKey(), httpapi.FileTokenTTL)
+	client.SendResponse(protocol.NewOKResponse(req.ID, map[string]any{
+		"mediaId":   mediaID,
+		"url":       urlPath + "?ft=" + ft,
+		"mimeType":  result.MimeType,
+		"extension": result.Extension,
+		"expiresIn": int(httpapi.FileTokenTTL.Seconds()),
+	}))
+}
@@ -0,0 +1,56 @@
+package methods
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/nextlevelbuilder/goclaw/internal/budget"
+	"github.com/nextlevelbuilder/goclaw/internal/gateway"
+	"github.com/nextlevelbuilder/goclaw/internal/store"
+	"github.com/nextlevelbuilder/goclaw/pkg/protocol"
+)
+
+// UsageBudgetMethods handles usage.budget — current month burn and remaining
+// budget for the tenant, and optionally one agent and one external user.
+// Nil-safe: returns {budgets: []} when the checker is nil.
+type UsageBudgetMethods struct {
+	checker *budget.Checker
+	agents  store.AgentStore
+}
+
+func NewUsageBudgetMethods(checker *budget.Checker, agents store.AgentStore) *UsageBudgetMethods {
+	return &UsageBudgetMethods{checker: checker, agents: agents}
+}
+
+func (m *UsageBudgetMethods) Register(router *gateway.MethodRouter) {
+	router.Register(protocol.MethodUsageBudget, m.handleBudget)
+}
+
+func (m *UsageBudgetMethods) handleBudget(ctx context.Context, client *gateway.Client, req *protocol.RequestFrame) {
+	var params struct {
+		AgentID string `json:"agentId"` // agent key or UUID
+		UserID  string `json:"userId"`
+	}
+	if req.Params != nil {
+		json.Unmarshal(req.Params, &params)
+	}
+
+	var ag *store.AgentData
+	if params.AgentID != "" && m.agents != nil {
+		var err error
+		if ag, err = resolveAgentInfo(ctx, m.agents, params.AgentID); err != nil {
+			client.SendResponse(protocol.NewErrorResponse(req.ID, protocol.ErrNotFound, "agent not found: "+params.AgentID))
+			return
+		}
+	}
+
+	statuses := m.checker.Statuses(ctx, budget.AgentSubject(store.TenantIDFromContext(ctx), ag, params.UserID))
+	if statuses == nil {
+		statuses = []budget.Status{}
+	}
+	client.SendResponse(protocol.NewOKResponse(req.ID, map[string]any{
+		"month":   time.Now().UTC().Format("2006-01"),
+		"budgets": statuses,
+	}))
+}
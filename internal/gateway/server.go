@@ -156,6 +156,7 @@ func (s *Server) BuildMux() *http.ServeMux {
 		chatHandler.SetPostTurnProcessor(s.postTurn)
 	}
 	mux.Handle("/v1/chat/completions", chatHandler)
+	mux.Handle("/v1/models", httpapi.NewModelsHandler(s.agents, s.agentStore))
 
 	// OpenResponses protocol
 	responsesHandler := httpapi.NewResponsesHandler(s.agents, s.sessions)
@@ -391,6 +392,12 @@ func (s *Server) SetMCPUserCredentialsHandler(h *httpapi.MCPUserCredentialsHandl
 	s.handlers = append(s.handlers, h)
 }
 
+// SetMCPOAuthHandler sets the MCP OAuth authorization handler.
+func (s *Server) SetMCPOAuthHandler(h *httpapi.MCPOAuthHandler) { s.handlers = append(s.handlers, h) }
+
+// SetAgentMCPHandler sets the handler exposing agents as an MCP server (/mcp/agents).
+func (s *Server) SetAgentMCPHandler(h *httpapi.AgentMCPHandler) { s.handlers = append(s.handlers, h) }
+
 // SetChannelInstancesHandler sets the channel instance CRUD handler.
 func (s *Server) SetChannelInstancesHandler(h *httpapi.ChannelInstancesHandler) {
 	s.handlers = append(s.handlers, h)
@@ -481,11 +488,24 @@ func (s *Server) SetKnowledgeGraphHandler(h *httpapi.KnowledgeGraphHandler) {
 	s.handlers = append(s.handlers, h)
 }
 
+// SetEvalHandler sets the agent eval suite handler.
+func (s *Server) SetEvalHandler(h *httpapi.EvalHandler) { s.handlers = append(s.handlers, h) }
+
 // SetActivityHandler sets the activity audit log handler.
 func (s *Server) SetActivityHandler(h *httpapi.ActivityHandler) {
 	s.handlers = append(s.handlers, h)
 }
 
+// SetMessageQueueHandler sets the durable message queue dead-letter handler.
+func (s *Server) SetMessageQueueHandler(h *httpapi.MessageQueueHandler) {
+	s.handlers = append(s.handlers, h)
+}
+
+// SetBackupHandler sets the tenant/instance backup handler.
+func (s *Server) SetBackupHandler(h *httpapi.BackupHandler) {
+	s.handlers = append(s.handlers, h)
+}
+
 // SetSystemConfigsHandler sets the system configs handler.
 func (s *Server) SetSystemConfigsHandler(h *httpapi.SystemConfigsHandler) {
 	s.handlers = append(s.handlers, h)
@@ -497,6 +517,9 @@ func (s *Server) SetUsageHandler(h *httpapi.UsageHandler) { s.handlers = append(
 // SetDocsHandler sets the OpenAPI spec + Swagger UI handler.
 func (s *Server) SetDocsHandler(h *httpapi.DocsHandler) { s.handlers = append(s.handlers, h) }
 
+// SetMetricsHandler sets the Prometheus /metrics handler.
+func (s *Server) SetMetricsHandler(h *httpapi.MetricsHandler) { s.handlers = append(s.handlers, h) }
+
 // SetEditionHandler sets the edition info handler.
 func (s *Server) SetEditionHandler(h *httpapi.EditionHandler) { s.handlers = append(s.handlers, h) }
 
@@ -536,6 +559,13 @@ func (s *Server) ClientList() []*Client {
 	return list
 }
 
+// ClientCount returns the number of connected WebSocket clients.
+func (s *Server) ClientCount() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.clients)
+}
+
 // BroadcastEvent sends an event to all connected clients.
 func (s *Server) BroadcastEvent(event protocol.EventFrame) {
 	s.mu.RLock()
@@ -611,6 +641,7 @@ func StartTestServer(s *Server, ctx context.Context) (addr string, start func())
 		chatHandler.SetPostTurnProcessor(s.postTurn)
 	}
 	mux.Handle("/v1/chat/completions", chatHandler)
+	mux.Handle("/v1/models", httpapi.NewModelsHandler(s.agents, s.agentStore))
 
 	responsesHandler := httpapi.NewResponsesHandler(s.agents, s.sessions)
 	if s.postTurn != nil {
@@ -52,8 +52,11 @@ type Ticker struct {
 	onEvent       func(store.HeartbeatEvent)
 
 	wakeCh chan uuid.UUID
-	stopCh chan struct{}
-	wg     sync.WaitGroup
+
+	runMu   sync.Mutex
+	running bool
+	stopCh  chan struct{}
+	wg      sync.WaitGroup
 }
 
 // NewTicker creates a new heartbeat ticker.
@@ -68,19 +71,32 @@ func NewTicker(cfg TickerConfig) *Ticker {
 		sched:         cfg.Sched,
 		runAgent:      cfg.RunAgent,
 		wakeCh:   make(chan uuid.UUID, 16),
-		stopCh:   make(chan struct{}),
 	}
 }
 
-// Start begins the background poll loop.
+// Start begins the background poll loop. The ticker can be started again
+// after Stop (cluster mode moves it between replicas).
 func (t *Ticker) Start() {
+	t.runMu.Lock()
+	defer t.runMu.Unlock()
+	if t.running {
+		return
+	}
+	t.running = true
+	t.stopCh = make(chan struct{})
 	t.wg.Add(1)
-	go t.loop()
+	go t.loop(t.stopCh)
 	slog.Info("heartbeat ticker started")
 }
 
 // Stop signals the poll loop to exit and waits for completion.
 func (t *Ticker) Stop() {
+	t.runMu.Lock()
+	defer t.runMu.Unlock()
+	if !t.running {
+		return
+	}
+	t.running = false
 	close(t.stopCh)
 	t.wg.Wait()
 	slog.Info("heartbeat ticker stopped")
@@ -105,14 +121,14 @@ func (t *Ticker) Wake(agentID uuid.UUID) {
 	}
 }
 
-func (t *Ticker) loop() {
+func (t *Ticker) loop(stopCh <-chan struct{}) {
 	defer t.wg.Done()
 	ticker := time.NewTicker(pollInterval)
 	defer ticker.Stop()
 
 	for {
 		select {
-		case <-t.stopCh:
+		case <-stopCh:
 			return
 		case <-ticker.C:
 			t.runDueHeartbeats()
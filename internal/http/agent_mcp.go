@@ -0,0 +1,301 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync/atomic"
+
+	"github.com/google/uuid"
+	mcpgo "github.com/mark3labs/mcp-go/mcp"
+	mcpserver "github.com/mark3labs/mcp-go/server"
+
+	"github.com/nextlevelbuilder/goclaw/internal/agent"
+	"github.com/nextlevelbuilder/goclaw/internal/bus"
+	"github.com/nextlevelbuilder/goclaw/internal/channels"
+	"github.com/nextlevelbuilder/goclaw/internal/i18n"
+	"github.com/nextlevelbuilder/goclaw/internal/permissions"
+	"github.com/nextlevelbuilder/goclaw/internal/scheduler"
+	"github.com/nextlevelbuilder/goclaw/internal/sessions"
+	"github.com/nextlevelbuilder/goclaw/internal/store"
+	"github.com/nextlevelbuilder/goclaw/pkg/protocol"
+)
+
+// agentMCPChannel is the channel name for runs started over /mcp/agents
+// (session keys, quota overrides and traces).
+const agentMCPChannel = "mcp"
+
+// AgentMCPHandler serves /mcp/agents: an MCP endpoint (streamable-http,
+// stateless) for external clients such as IDEs and other agent frameworks.
+// Every agent the caller can access is an "ask_<agent_key>" tool, and the
+// caller's sessions and memory with that agent are resources.
+//
+// Unlike /mcp/bridge, which hands built-in tools to the Claude CLI provider,
+// calls here are full agent runs: they go through the scheduler's main lane,
+// count against quotas and are traced like channel messages.
+type AgentMCPHandler struct {
+	agents   store.AgentStore
+	router   *agent.Router
+	sessions store.SessionStore
+	sched    *scheduler.Scheduler
+	memory   store.MemoryStore      // nil = no memory resources
+	quota    *channels.QuotaChecker // nil = no quota enforcement
+	msgBus   *bus.MessageBus        // nil = no progress notifications
+	isOwner  func(string) bool      // owners see every agent in the tenant
+	version  string
+}
+
+// NewAgentMCPHandler creates the handler for the agents MCP endpoint.
+func NewAgentMCPHandler(agents store.AgentStore, router *agent.Router, sess store.SessionStore, sched *scheduler.Scheduler, msgBus *bus.MessageBus, isOwner func(string) bool, version string) *AgentMCPHandler {
+	return &AgentMCPHandler{
+		agents:   agents,
+		router:   router,
+		sessions: sess,
+		sched:    sched,
+		msgBus:   msgBus,
+		isOwner:  isOwner,
+		version:  version,
+	}
+}
+
+// SetMemoryStore enables the memory resources.
+func (h *AgentMCPHandler) SetMemoryStore(ms store.MemoryStore) { h.memory = ms }
+
+// SetQuotaChecker enforces per-user request quotas on ask_* calls.
+func (h *AgentMCPHandler) SetQuotaChecker(q *channels.QuotaChecker) { h.quota = q }
+
+// RegisterRoutes mounts the MCP endpoint.
+func (h *AgentMCPHandler) RegisterRoutes(mux *http.ServeMux) {
+	mux.Handle("/mcp/agents", h)
+}
+
+// ServeHTTP authenticates the caller (gateway token or API key, operator+)
+// and serves the request with an MCP server built for the agents the caller
+// can access. The server is stateless, so building it per request keeps the
+// tool list in step with shares and key scopes.
+func (h *AgentMCPHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	locale := extractLocale(r)
+	auth := resolveAuth(r)
+	if !auth.Authenticated {
+		writeError(w, http.StatusUnauthorized, protocol.ErrUnauthorized, i18n.T(locale, i18n.MsgUnauthorized))
+		return
+	}
+	if !permissions.HasMinRole(auth.Role, permissions.RoleOperator) {
+		writeError(w, http.StatusForbidden, protocol.ErrUnauthorized, i18n.T(locale, i18n.MsgPermissionDenied, "/mcp/agents"))
+		return
+	}
+	ctx := enrichContext(r.Context(), r, auth)
+	userID := store.UserIDFromContext(ctx)
+	if userID == "" {
+		writeError(w, http.StatusBadRequest, protocol.ErrInvalidRequest, i18n.T(locale, i18n.MsgUserIDHeader))
+		return
+	}
+
+	agents, err := h.accessibleAgents(ctx, userID)
+	if err != nil {
+		slog.Error("mcp.agents.list", "user", userID, "error", err)
+		writeError(w, http.StatusInternalServerError, protocol.ErrInternal, i18n.T(locale, i18n.MsgFailedToList, "agents"))
+		return
+	}
+
+	srv := mcpserver.NewStreamableHTTPServer(h.newServer(agents), mcpserver.WithStateLess(true))
+	srv.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// accessibleAgents lists the active agents the user may run: every agent for
+// owners, otherwise owned and shared ones (same rule as GET /v1/agents).
+func (h *AgentMCPHandler) accessibleAgents(ctx context.Context, userID string) ([]store.AgentData, error) {
+	var all []store.AgentData
+	var err error
+	if h.isOwner != nil && h.isOwner(userID) {
+		all, err = h.agents.List(ctx, "")
+	} else {
+		all, err = h.agents.ListAccessible(ctx, userID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	active := all[:0]
+	for _, ag := range all {
+		if ag.Status == "" || ag.Status == store.AgentStatusActive {
+			active = append(active, ag)
+		}
+	}
+	return active, nil
+}
+
+// newServer builds the MCP server for one request.
+func (h *AgentMCPHandler) newServer(agents []store.AgentData) *mcpserver.MCPServer {
+	srv := mcpserver.NewMCPServer("goclaw-agents", h.version,
+		mcpserver.WithToolCapabilities(false),
+		mcpserver.WithResourceCapabilities(false, false),
+		mcpserver.WithInstructions("Each ask_<agent> tool delegates a task to a GoClaw agent. "+
+			"Pass the returned session to continue the same conversation."),
+	)
+	for _, ag := range agents {
+		srv.AddTool(agentMCPTool(ag), h.askHandler(ag))
+	}
+	h.addResources(srv, agents)
+	return srv
+}
+
+// agentMCPTool describes the ask_<agent_key> tool for an agent.
+func agentMCPTool(ag store.AgentData) mcpgo.Tool {
+	name := ag.DisplayName
+	if name == "" {
+		name = ag.AgentKey
+	}
+	desc := fmt.Sprintf("Ask the GoClaw agent %q to handle a task and return its answer.", name)
+	if ag.Frontmatter != "" {
+		desc += " Expertise: " + ag.Frontmatter
+	}
+	return mcpgo.NewTool("ask_"+ag.AgentKey,
+		mcpgo.WithDescription(desc),
+		mcpgo.WithString("message", mcpgo.Required(),
+			mcpgo.Description("The task or question for the agent.")),
+		mcpgo.WithString("session",
+			mcpgo.Description("Session ID returned by an earlier call, to continue that conversation. Omit to start a new one.")),
+	)
+}
+
+// agentMCPSessionKey is the session key for a caller's MCP conversation:
+// agent:{agentKey}:mcp:direct:{userID}:thread:{sessionID}.
+func agentMCPSessionKey(agentKey, userID, sessionID string) string {
+	return sessions.BuildScopedThreadSessionKey(agentKey, agentMCPChannel, sessions.PeerDirect, userID, sessionID)
+}
+
+// askHandler runs the agent through the scheduler's main lane and returns
+// its final answer, streaming progress while the run is in flight.
+func (h *AgentMCPHandler) askHandler(ag store.AgentData) mcpserver.ToolHandlerFunc {
+	return func(ctx context.Context, req mcpgo.CallToolRequest) (*mcpgo.CallToolResult, error) {
+		message := strings.TrimSpace(req.GetString("message", ""))
+		if message == "" {
+			return mcpgo.NewToolResultError("message is required"), nil
+		}
+		runID := uuid.NewString()
+		sessionID := req.GetString("session", "")
+		if sessionID == "" {
+			sessionID = runID[:8]
+		} else if !isValidSlug(sessionID) {
+			return mcpgo.NewToolResultError("invalid session: use the session ID returned by an earlier call"), nil
+		}
+		userID := store.UserIDFromContext(ctx)
+
+		loop, err := h.router.Get(ctx, ag.AgentKey)
+		if err != nil {
+			return mcpgo.NewToolResultErrorf("agent %s is not available: %v", ag.AgentKey, err), nil
+		}
+		if h.quota != nil {
+			q := h.quota.Check(ctx, userID, agentMCPChannel, loop.ProviderName())
+			if !q.Allowed {
+				return mcpgo.NewToolResultErrorf("%s request limit reached (%d/%d), try again later", q.Window, q.Used, q.Limit), nil
+			}
+			h.quota.Increment(userID)
+		}
+
+		var progressToken mcpgo.ProgressToken
+		if req.Params.Meta != nil {
+			progressToken = req.Params.Meta.ProgressToken
+		}
+		if progressToken != nil {
+			defer h.forwardProgress(ctx, runID, progressToken)()
+		}
+
+		runReq := agent.RunRequest{
+			SessionKey: agentMCPSessionKey(ag.AgentKey, userID, sessionID),
+			Message:    message,
+			Channel:    agentMCPChannel,
+			ChatID:     userID,
+			PeerKind:   string(sessions.PeerDirect),
+			RunID:      runID,
+			UserID:     userID,
+			SenderID:   userID,
+			Stream:     progressToken != nil,
+			TraceTags:  []string{agentMCPChannel},
+		}
+		slog.Info("mcp.agents.ask", "agent", ag.AgentKey, "user", userID, "session", sessionID, "run", runID)
+
+		var outcome scheduler.RunOutcome
+		select {
+		case outcome = <-h.sched.Schedule(ctx, scheduler.LaneMain, runReq):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		if outcome.Err != nil {
+			if errors.Is(outcome.Err, scheduler.ErrGatewayDraining) {
+				return mcpgo.NewToolResultError("gateway is shutting down, try again shortly"), nil
+			}
+			return mcpgo.NewToolResultErrorf("agent run failed: %v", outcome.Err), nil
+		}
+
+		content := ""
+		if outcome.Result != nil {
+			content = SignFileURLs(outcome.Result.Content, FileSigningKey())
+		}
+		return mcpgo.NewToolResultStructured(map[string]any{
+			"response": content,
+			"session":  sessionID,
+			"run_id":   runID,
+		}, content), nil
+	}
+}
+
+// forwardProgress relays the run's agent events to the client as
+// notifications/progress until the returned stop func is called.
+func (h *AgentMCPHandler) forwardProgress(ctx context.Context, runID string, token mcpgo.ProgressToken) (stop func()) {
+	srv := mcpserver.ServerFromContext(ctx)
+	if h.msgBus == nil || srv == nil {
+		return func() {}
+	}
+	var progress atomic.Int64
+	subID := "mcp-agents-progress-" + runID
+	h.msgBus.Subscribe(subID, func(e bus.Event) {
+		if e.Name != protocol.EventAgent {
+			return
+		}
+		ev, ok := e.Payload.(agent.AgentEvent)
+		if !ok || ev.RunID != runID {
+			return
+		}
+		msg := agentMCPProgressMessage(ev)
+		if msg == "" {
+			return
+		}
+		// Best-effort: the notification is dropped if the client is slow.
+		_ = srv.SendNotificationToClient(ctx, "notifications/progress", map[string]any{
+			"progressToken": token,
+			"progress":      progress.Add(1),
+			"message":       msg,
+		})
+	})
+	return func() { h.msgBus.Unsubscribe(subID) }
+}
+
+// agentMCPProgressMessage renders an agent event as a progress message, or
+// "" for events that are not worth reporting.
+func agentMCPProgressMessage(ev agent.AgentEvent) string {
+	switch ev.Type {
+	case protocol.ChatEventChunk:
+		if m, ok := ev.Payload.(map[string]string); ok {
+			return m["content"]
+		}
+	case protocol.AgentEventToolCall:
+		if m, ok := ev.Payload.(map[string]any); ok {
+			if name, _ := m["name"].(string); name != "" {
+				return "calling tool " + name
+			}
+		}
+	case protocol.AgentEventActivity:
+		if m, ok := ev.Payload.(map[string]any); ok {
+			if phase, _ := m["phase"].(string); phase != "" {
+				return phase
+			}
+		}
+	case protocol.AgentEventRunRetrying:
+		return "retrying"
+	}
+	return ""
+}
@@ -0,0 +1,162 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	mcpgo "github.com/mark3labs/mcp-go/mcp"
+	mcpserver "github.com/mark3labs/mcp-go/server"
+
+	"github.com/nextlevelbuilder/goclaw/internal/store"
+)
+
+// agentMCPURIPrefix prefixes every resource URI on /mcp/agents:
+//
+//	goclaw://agents/{agent_key}/sessions             caller's MCP sessions
+//	goclaw://agents/{agent_key}/sessions/{session}   one session's messages
+//	goclaw://agents/{agent_key}/memory               caller's + shared memory documents
+//	goclaw://agents/{agent_key}/memory/{path}        one memory document
+const agentMCPURIPrefix = "goclaw://agents/"
+
+// agentMCPSessionListLimit caps the sessions listed per agent.
+const agentMCPSessionListLimit = 100
+
+// addResources registers the per-agent list resources and the templates
+// for reading single sessions and memory documents.
+func (h *AgentMCPHandler) addResources(srv *mcpserver.MCPServer, agents []store.AgentData) {
+	byKey := make(map[string]store.AgentData, len(agents))
+	for _, ag := range agents {
+		byKey[ag.AgentKey] = ag
+		srv.AddResource(mcpgo.NewResource(agentMCPURIPrefix+ag.AgentKey+"/sessions", ag.AgentKey+" sessions",
+			mcpgo.WithResourceDescription("Your conversations with this agent over MCP."),
+			mcpgo.WithMIMEType("application/json"),
+		), h.readResource(byKey))
+		if h.memory != nil {
+			srv.AddResource(mcpgo.NewResource(agentMCPURIPrefix+ag.AgentKey+"/memory", ag.AgentKey+" memory",
+				mcpgo.WithResourceDescription("Memory documents this agent keeps for you (and shared ones)."),
+				mcpgo.WithMIMEType("application/json"),
+			), h.readResource(byKey))
+		}
+	}
+
+	srv.AddResourceTemplate(mcpgo.NewResourceTemplate(agentMCPURIPrefix+"{agent_key}/sessions/{session}", "Agent session",
+		mcpgo.WithTemplateDescription("Messages of one of your sessions with an agent."),
+		mcpgo.WithTemplateMIMEType("application/json"),
+	), mcpserver.ResourceTemplateHandlerFunc(h.readResource(byKey)))
+	if h.memory != nil {
+		srv.AddResourceTemplate(mcpgo.NewResourceTemplate(agentMCPURIPrefix+"{agent_key}/memory/{+path}", "Agent memory document",
+			mcpgo.WithTemplateDescription("One memory document of an agent, e.g. MEMORY.md."),
+			mcpgo.WithTemplateMIMEType("text/markdown"),
+		), mcpserver.ResourceTemplateHandlerFunc(h.readResource(byKey)))
+	}
+}
+
+// readResource serves every resource URI. The agent key in the URI must be
+// one of the caller's agents, and sessions are always the caller's own:
+// their keys are rebuilt from the authenticated user, never taken from the URI.
+func (h *AgentMCPHandler) readResource(agents map[string]store.AgentData) mcpserver.ResourceHandlerFunc {
+	return func(ctx context.Context, req mcpgo.ReadResourceRequest) ([]mcpgo.ResourceContents, error) {
+		uri := req.Params.URI
+		rest, ok := strings.CutPrefix(uri, agentMCPURIPrefix)
+		if !ok {
+			return nil, fmt.Errorf("unknown resource %s", uri)
+		}
+		parts := strings.SplitN(rest, "/", 3)
+		ag, ok := agents[parts[0]]
+		if !ok || len(parts) < 2 {
+			return nil, fmt.Errorf("unknown resource %s", uri)
+		}
+		userID := store.UserIDFromContext(ctx)
+
+		switch {
+		case parts[1] == "sessions" && len(parts) == 2:
+			return jsonResource(uri, h.listSessions(ctx, ag.AgentKey, userID))
+		case parts[1] == "sessions":
+			return h.readSession(ctx, uri, ag.AgentKey, userID, parts[2])
+		case parts[1] == "memory" && h.memory != nil && len(parts) == 2:
+			docs, err := h.memory.ListDocuments(ctx, ag.ID.String(), userID)
+			if err != nil {
+				return nil, fmt.Errorf("list memory: %w", err)
+			}
+			return jsonResource(uri, docs)
+		case parts[1] == "memory" && h.memory != nil:
+			path, err := url.PathUnescape(parts[2])
+			if err != nil {
+				return nil, fmt.Errorf("invalid memory path: %w", err)
+			}
+			content, err := h.memory.GetDocument(ctx, ag.ID.String(), userID, path)
+			if err != nil {
+				content, err = h.memory.GetDocument(ctx, ag.ID.String(), "", path)
+			}
+			if err != nil {
+				return nil, fmt.Errorf("memory document %s not found", path)
+			}
+			return []mcpgo.ResourceContents{mcpgo.TextResourceContents{URI: uri, MIMEType: "text/markdown", Text: content}}, nil
+		}
+		return nil, fmt.Errorf("unknown resource %s", uri)
+	}
+}
+
+// agentMCPSession is one entry of the sessions list resource.
+type agentMCPSession struct {
+	Session  string    `json:"session"`
+	Label    string    `json:"label,omitempty"`
+	Messages int       `json:"messages"`
+	Updated  time.Time `json:"updated"`
+}
+
+// listSessions returns the caller's MCP sessions with an agent.
+func (h *AgentMCPHandler) listSessions(ctx context.Context, agentKey, userID string) []agentMCPSession {
+	prefix := agentMCPSessionKey(agentKey, userID, "")
+	res := h.sessions.ListPaged(ctx, store.SessionListOpts{
+		AgentID:  agentKey,
+		Channel:  agentMCPChannel,
+		UserID:   userID,
+		TenantID: store.TenantIDFromContext(ctx),
+		Limit:    agentMCPSessionListLimit,
+	})
+	out := []agentMCPSession{}
+	for _, s := range res.Sessions {
+		id, ok := strings.CutPrefix(s.Key, prefix)
+		if !ok || id == "" {
+			continue
+		}
+		out = append(out, agentMCPSession{Session: id, Label: s.Label, Messages: s.MessageCount, Updated: s.Updated})
+	}
+	return out
+}
+
+// readSession returns the user and assistant messages of one of the
+// caller's sessions.
+func (h *AgentMCPHandler) readSession(ctx context.Context, uri, agentKey, userID, sessionID string) ([]mcpgo.ResourceContents, error) {
+	if !isValidSlug(sessionID) {
+		return nil, fmt.Errorf("invalid session %q", sessionID)
+	}
+	key := agentMCPSessionKey(agentKey, userID, sessionID)
+	if h.sessions.Get(ctx, key) == nil {
+		return nil, fmt.Errorf("session %s not found", sessionID)
+	}
+	type message struct {
+		Role    string `json:"role"`
+		Content string `json:"content"`
+	}
+	msgs := []message{}
+	for _, m := range h.sessions.GetHistory(ctx, key) {
+		if (m.Role == "user" || m.Role == "assistant") && m.Content != "" {
+			msgs = append(msgs, message{Role: m.Role, Content: m.Content})
+		}
+	}
+	return jsonResource(uri, map[string]any{"session": sessionID, "messages": msgs})
+}
+
+func jsonResource(uri string, v any) ([]mcpgo.ResourceContents, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return []mcpgo.ResourceContents{mcpgo.TextResourceContents{URI: uri, MIMEType: "application/json", Text: string(data)}}, nil
+}
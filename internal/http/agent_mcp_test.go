@@ -0,0 +1,250 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	mcpclient "github.com/mark3labs/mcp-go/client"
+	"github.com/mark3labs/mcp-go/client/transport"
+	mcpgo "github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/nextlevelbuilder/goclaw/internal/agent"
+	"github.com/nextlevelbuilder/goclaw/internal/bus"
+	"github.com/nextlevelbuilder/goclaw/internal/providers"
+	"github.com/nextlevelbuilder/goclaw/internal/scheduler"
+	"github.com/nextlevelbuilder/goclaw/internal/store"
+	"github.com/nextlevelbuilder/goclaw/pkg/protocol"
+)
+
+type agentMCPAgentStore struct {
+	store.AgentStore // unused methods panic
+	shared           map[string][]store.AgentData
+}
+
+func (s *agentMCPAgentStore) ListAccessible(_ context.Context, userID string) ([]store.AgentData, error) {
+	return s.shared[userID], nil
+}
+
+type agentMCPSessionStore struct {
+	store.SessionStore // unused methods panic
+
+	mu      sync.Mutex
+	history map[string][]providers.Message
+}
+
+func (s *agentMCPSessionStore) append(key string, msgs ...providers.Message) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.history[key] = append(s.history[key], msgs...)
+}
+
+func (s *agentMCPSessionStore) Get(_ context.Context, key string) *store.SessionData {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.history[key]; !ok {
+		return nil
+	}
+	return &store.SessionData{Key: key}
+}
+
+func (s *agentMCPSessionStore) GetHistory(_ context.Context, key string) []providers.Message {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.history[key]
+}
+
+func (s *agentMCPSessionStore) ListPaged(_ context.Context, opts store.SessionListOpts) store.SessionListResult {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var res store.SessionListResult
+	for key, msgs := range s.history {
+		if strings.HasPrefix(key, "agent:"+opts.AgentID+":") {
+			res.Sessions = append(res.Sessions, store.SessionInfo{Key: key, MessageCount: len(msgs)})
+		}
+	}
+	res.Total = len(res.Sessions)
+	return res
+}
+
+type agentMCPFakeAgent struct{ id string }
+
+func (a *agentMCPFakeAgent) ID() string { return a.id }
+func (a *agentMCPFakeAgent) Run(context.Context, agent.RunRequest) (*agent.RunResult, error) {
+	return nil, nil
+}
+func (a *agentMCPFakeAgent) IsRunning() bool              { return false }
+func (a *agentMCPFakeAgent) Model() string                { return "test-model" }
+func (a *agentMCPFakeAgent) ProviderName() string         { return "test" }
+func (a *agentMCPFakeAgent) Provider() providers.Provider { return nil }
+
+type agentMCPTestEnv struct {
+	server   *httptest.Server
+	sessions *agentMCPSessionStore
+	runs     []agent.RunRequest
+	// progressSeen is signalled by the test when a progress notification
+	// arrives; streaming runs wait for it so the final response cannot
+	// overtake the notification.
+	progressSeen chan struct{}
+}
+
+func newAgentMCPTestServer(t *testing.T) *agentMCPTestEnv {
+	t.Helper()
+	agents := &agentMCPAgentStore{shared: map[string][]store.AgentData{
+		"alice": {
+			{BaseModel: store.BaseModel{ID: uuid.New()}, AgentKey: "researcher", Frontmatter: "finds papers", Status: store.AgentStatusActive},
+			{BaseModel: store.BaseModel{ID: uuid.New()}, AgentKey: "drafter", Status: store.AgentStatusSummoning},
+		},
+	}}
+	sess := &agentMCPSessionStore{history: map[string][]providers.Message{}}
+	msgBus := bus.New()
+
+	router := agent.NewRouter()
+	router.SetResolver(func(_ context.Context, key string) (agent.Agent, error) {
+		return &agentMCPFakeAgent{id: key}, nil
+	})
+
+	env := &agentMCPTestEnv{sessions: sess, progressSeen: make(chan struct{}, 1)}
+	var mu sync.Mutex
+	queueCfg := scheduler.DefaultQueueConfig()
+	queueCfg.DebounceMs = 0
+	sched := scheduler.NewScheduler(nil, queueCfg, func(_ context.Context, req agent.RunRequest) (*agent.RunResult, error) {
+		mu.Lock()
+		env.runs = append(env.runs, req)
+		mu.Unlock()
+		msgBus.Broadcast(bus.Event{Name: protocol.EventAgent, Payload: agent.AgentEvent{
+			Type: protocol.ChatEventChunk, RunID: req.RunID, Payload: map[string]string{"content": "working"},
+		}})
+		if req.Stream {
+			select {
+			case <-env.progressSeen:
+			case <-time.After(2 * time.Second):
+			}
+		}
+		sess.append(req.SessionKey,
+			providers.Message{Role: "user", Content: req.Message},
+			providers.Message{Role: "assistant", Content: "answer to " + req.Message})
+		return &agent.RunResult{Content: "answer to " + req.Message, RunID: req.RunID}, nil
+	})
+	t.Cleanup(sched.Stop)
+
+	h := NewAgentMCPHandler(agents, router, sess, sched, msgBus, nil, "test")
+	env.server = httptest.NewServer(h)
+	t.Cleanup(env.server.Close)
+	return env
+}
+
+func newAgentMCPTestClient(t *testing.T, url, userID string) *mcpclient.Client {
+	t.Helper()
+	c, err := mcpclient.NewStreamableHttpClient(url, transport.WithHTTPHeaders(map[string]string{"X-GoClaw-User-Id": userID}))
+	if err != nil {
+		t.Fatalf("client: %v", err)
+	}
+	t.Cleanup(func() { _ = c.Close() })
+	ctx := context.Background()
+	if err := c.Start(ctx); err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	if _, err := c.Initialize(ctx, mcpgo.InitializeRequest{}); err != nil {
+		t.Fatalf("initialize: %v", err)
+	}
+	return c
+}
+
+func TestAgentMCP_AskAgent(t *testing.T) {
+	env := newAgentMCPTestServer(t)
+	c := newAgentMCPTestClient(t, env.server.URL, "alice")
+	ctx := context.Background()
+
+	list, err := c.ListTools(ctx, mcpgo.ListToolsRequest{})
+	if err != nil {
+		t.Fatalf("list tools: %v", err)
+	}
+	if len(list.Tools) != 1 || list.Tools[0].Name != "ask_researcher" || !strings.Contains(list.Tools[0].Description, "finds papers") {
+		t.Fatalf("tools = %+v, want only ask_researcher (inactive agents hidden)", list.Tools)
+	}
+
+	var progressMu sync.Mutex
+	var progress []string
+	c.OnNotification(func(n mcpgo.JSONRPCNotification) {
+		if n.Method == "notifications/progress" {
+			progressMu.Lock()
+			progress = append(progress, n.Params.AdditionalFields["message"].(string))
+			progressMu.Unlock()
+			env.progressSeen <- struct{}{}
+		}
+	})
+
+	req := mcpgo.CallToolRequest{}
+	req.Params.Name = "ask_researcher"
+	req.Params.Arguments = map[string]any{"message": "hello"}
+	req.Params.Meta = &mcpgo.Meta{ProgressToken: "p1"}
+	res, err := c.CallTool(ctx, req)
+	if err != nil || res.IsError {
+		t.Fatalf("call: %v %+v", err, res)
+	}
+	out, _ := json.Marshal(res.StructuredContent)
+	var got struct{ Response, Session string }
+	_ = json.Unmarshal(out, &got)
+	if got.Response != "answer to hello" || got.Session == "" {
+		t.Fatalf("result = %s", out)
+	}
+	progressMu.Lock()
+	if len(progress) != 1 || progress[0] != "working" {
+		t.Errorf("progress = %v, want [working]", progress)
+	}
+	progressMu.Unlock()
+
+	// Continuing the session reuses its key; runs are in the caller's MCP scope.
+	req.Params.Arguments = map[string]any{"message": "again", "session": got.Session}
+	req.Params.Meta = nil
+	if res, err := c.CallTool(ctx, req); err != nil || res.IsError {
+		t.Fatalf("second call: %v %+v", err, res)
+	}
+	want := "agent:researcher:mcp:direct:alice:thread:" + got.Session
+	if len(env.runs) != 2 || env.runs[0].SessionKey != want || env.runs[1].SessionKey != want || env.runs[0].Channel != "mcp" {
+		t.Fatalf("runs = %+v, want both on %s", env.runs, want)
+	}
+
+	req.Params.Arguments = map[string]any{"message": "x", "session": "../other"}
+	if res, err := c.CallTool(ctx, req); err != nil || !res.IsError {
+		t.Errorf("invalid session accepted: %v %+v", err, res)
+	}
+}
+
+func TestAgentMCP_SessionResources(t *testing.T) {
+	env := newAgentMCPTestServer(t)
+	env.sessions.append("agent:researcher:mcp:direct:alice:thread:abc123", providers.Message{Role: "user", Content: "hi"})
+	env.sessions.append("agent:researcher:mcp:direct:bob:thread:zzz999", providers.Message{Role: "user", Content: "secret"})
+	c := newAgentMCPTestClient(t, env.server.URL, "alice")
+	ctx := context.Background()
+
+	read := func(uri string) (string, error) {
+		req := mcpgo.ReadResourceRequest{}
+		req.Params.URI = uri
+		res, err := c.ReadResource(ctx, req)
+		if err != nil {
+			return "", err
+		}
+		return res.Contents[0].(mcpgo.TextResourceContents).Text, nil
+	}
+
+	text, err := read("goclaw://agents/researcher/sessions")
+	if err != nil || !strings.Contains(text, `"session":"abc123"`) || strings.Contains(text, "zzz999") {
+		t.Fatalf("sessions = %s, %v; want only alice's session", text, err)
+	}
+	if text, err := read("goclaw://agents/researcher/sessions/abc123"); err != nil || !strings.Contains(text, `"content":"hi"`) {
+		t.Fatalf("session = %s, %v", text, err)
+	}
+	if _, err := read("goclaw://agents/researcher/sessions/zzz999"); err == nil {
+		t.Error("read another user's session")
+	}
+	if _, err := read("goclaw://agents/drafter/sessions"); err == nil {
+		t.Error("read sessions of an agent the caller cannot run")
+	}
+}
@@ -14,6 +14,7 @@ import (
 	"github.com/nextlevelbuilder/goclaw/internal/bootstrap"
 	"github.com/nextlevelbuilder/goclaw/internal/bus"
 	"github.com/nextlevelbuilder/goclaw/internal/config"
+	"github.com/nextlevelbuilder/goclaw/internal/contextfiles"
 	"github.com/nextlevelbuilder/goclaw/internal/i18n"
 	"github.com/nextlevelbuilder/goclaw/internal/permissions"
 	"github.com/nextlevelbuilder/goclaw/internal/providers"
@@ -35,6 +36,7 @@ type AgentsHandler struct {
 	msgBus           *bus.MessageBus          // for cache invalidation events (nil = no events)
 	summoner         *AgentSummoner           // LLM-based agent setup (nil = disabled)
 	isOwner          func(string) bool        // checks if user ID is a system owner (nil = no owners configured)
+	files            *contextfiles.Service    // context file history (nil = disabled)
 }
 
 // NewAgentsHandler creates a handler for agent management endpoints.
@@ -64,6 +66,19 @@ func (h *AgentsHandler) SetImportStores(mem store.MemoryStore, kg store.Knowledg
 	h.kgStore = kg
 }
 
+// SetContextFileRevisionStore enables the context file history and approval endpoints.
+func (h *AgentsHandler) SetContextFileRevisionStore(rs store.ContextFileRevisionStore) {
+	if rs == nil {
+		h.files = nil
+		return
+	}
+	var events bus.EventPublisher
+	if h.msgBus != nil {
+		events = h.msgBus
+	}
+	h.files = contextfiles.New(h.agents, rs, events)
+}
+
 // isOwnerUser checks if the given user ID is a system owner.
 func (h *AgentsHandler) isOwnerUser(userID string) bool {
 	return userID != "" && h.isOwner != nil && h.isOwner(userID)
@@ -116,6 +131,16 @@ func (h *AgentsHandler) RegisterRoutes(mux *http.ServeMux) {
 	// Instance writes (admin+)
 	mux.HandleFunc("PUT /v1/agents/{id}/instances/{userID}/files/{fileName}", h.adminMiddleware(h.handleSetInstanceFile))
 	mux.HandleFunc("PATCH /v1/agents/{id}/instances/{userID}/metadata", h.adminMiddleware(h.handleUpdateInstanceMetadata))
+
+	// Context file history and approval of agent edits (owner checks in handlers)
+	if h.files != nil {
+		mux.HandleFunc("GET /v1/agents/{id}/files/{fileName}/revisions", h.authMiddleware(h.handleListFileRevisions))
+		mux.HandleFunc("GET /v1/agents/{id}/file-revisions/{revisionID}/diff", h.authMiddleware(h.handleFileRevisionDiff))
+		mux.HandleFunc("POST /v1/agents/{id}/file-revisions/{revisionID}/restore", h.authMiddleware(h.handleRestoreFileRevision))
+		mux.HandleFunc("GET /v1/agents/{id}/file-changes", h.authMiddleware(h.handleListFileChanges))
+		mux.HandleFunc("POST /v1/agents/{id}/file-changes/{changeID}/approve", h.authMiddleware(h.handleApproveFileChange))
+		mux.HandleFunc("POST /v1/agents/{id}/file-changes/{changeID}/reject", h.authMiddleware(h.handleRejectFileChange))
+	}
 }
 
 func (h *AgentsHandler) authMiddleware(next http.HandlerFunc) http.HandlerFunc {
@@ -227,6 +252,10 @@ func (h *AgentsHandler) handleCreate(w http.ResponseWriter, r *http.Request) {
 		writeError(w, http.StatusBadRequest, protocol.ErrInvalidRequest, i18n.T(locale, i18n.MsgInvalidRequest, err.Error()))
 		return
 	}
+	if err := store.ValidateFallbackChainProviders(r.Context(), h.providers, req.OtherConfig); err != nil {
+		writeError(w, http.StatusBadRequest, protocol.ErrInvalidRequest, i18n.T(locale, i18n.MsgInvalidRequest, err.Error()))
+		return
+	}
 
 	if err := h.agents.Create(r.Context(), &req); err != nil {
 		if strings.Contains(err.Error(), "duplicate key") || strings.Contains(err.Error(), "23505") {
@@ -348,6 +377,10 @@ func (h *AgentsHandler) handleUpdate(w http.ResponseWriter, r *http.Request) {
 		writeError(w, http.StatusBadRequest, protocol.ErrInvalidRequest, i18n.T(locale, i18n.MsgInvalidRequest, err.Error()))
 		return
 	}
+	if err := store.ValidateFallbackChainProviders(r.Context(), h.providers, validationAgent.OtherConfig); err != nil {
+		writeError(w, http.StatusBadRequest, protocol.ErrInvalidRequest, i18n.T(locale, i18n.MsgInvalidRequest, err.Error()))
+		return
+	}
 
 	if err := h.agents.Update(r.Context(), id, allowed); err != nil {
 		slog.Error("agents.update", "id", id, "error", err)
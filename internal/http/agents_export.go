@@ -104,12 +104,15 @@ type KGEntityExport struct {
 
 // KGRelationExport is a portable KG relation using external IDs.
 type KGRelationExport struct {
-	SourceExternalID string            `json:"source_external_id"`
-	TargetExternalID string            `json:"target_external_id"`
-	UserID           string            `json:"user_id,omitempty"`
-	RelationType     string            `json:"relation_type"`
-	Confidence       float64           `json:"confidence"`
-	Properties       map[string]string `json:"properties,omitempty"`
+	SourceExternalID string              `json:"source_external_id"`
+	TargetExternalID string              `json:"target_external_id"`
+	UserID           string              `json:"user_id,omitempty"`
+	RelationType     string              `json:"relation_type"`
+	Confidence       float64             `json:"confidence"`
+	Properties       map[string]string   `json:"properties,omitempty"`
+	ValidFrom        int64               `json:"valid_from,omitempty"`
+	ValidTo          int64               `json:"valid_to,omitempty"` // 0 = current fact
+	Provenance       *store.KGProvenance `json:"provenance,omitempty"`
 }
 
 // MemoryExport is a portable memory document.
@@ -472,6 +475,9 @@ func (h *AgentsHandler) writeExportArchive(ctx context.Context, w io.Writer, ag
 				RelationType:     rel.RelationType,
 				Confidence:       rel.Confidence,
 				Properties:       rel.Properties,
+				ValidFrom:        rel.ValidFrom,
+				ValidTo:          rel.ValidTo,
+				Provenance:       rel.Provenance,
 			})
 		}
 
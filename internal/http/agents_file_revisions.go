@@ -0,0 +1,191 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/google/uuid"
+
+	"github.com/nextlevelbuilder/goclaw/internal/contextfiles"
+	"github.com/nextlevelbuilder/goclaw/internal/i18n"
+	"github.com/nextlevelbuilder/goclaw/internal/store"
+)
+
+// revisionAgent resolves the {id} agent and checks the caller is its owner
+// (or a system owner). Writes the error response and returns nil on failure.
+func (h *AgentsHandler) revisionAgent(w http.ResponseWriter, r *http.Request, action string) *store.AgentData {
+	callerID := store.UserIDFromContext(r.Context())
+	locale := store.LocaleFromContext(r.Context())
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": i18n.T(locale, i18n.MsgInvalidID, "agent")})
+		return nil
+	}
+	ag, err := h.agents.GetByID(r.Context(), id)
+	if err != nil {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": i18n.T(locale, i18n.MsgNotFound, "agent", id.String())})
+		return nil
+	}
+	if callerID != "" && ag.OwnerID != callerID && !h.isOwnerUser(callerID) {
+		writeJSON(w, http.StatusForbidden, map[string]string{"error": i18n.T(locale, i18n.MsgOwnerOnly, action)})
+		return nil
+	}
+	return ag
+}
+
+// revisionFromPath resolves {revisionID}, scoped to the agent. Writes the
+// error response and returns nil on failure.
+func (h *AgentsHandler) revisionFromPath(w http.ResponseWriter, r *http.Request, ag *store.AgentData, param string) *store.ContextFileRevision {
+	locale := store.LocaleFromContext(r.Context())
+	raw := r.PathValue(param)
+	if raw == "" {
+		raw = r.URL.Query().Get(param)
+	}
+	revID, err := uuid.Parse(raw)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": i18n.T(locale, i18n.MsgInvalidID, "revision")})
+		return nil
+	}
+	rev, err := h.files.Revision(r.Context(), ag, revID)
+	if errors.Is(err, contextfiles.ErrNotFound) {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": i18n.T(locale, i18n.MsgNotFound, "revision", revID.String())})
+		return nil
+	}
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return nil
+	}
+	return rev
+}
+
+// handleListFileRevisions returns the revision history of a context file, newest first.
+// Query: user_id (per-user file; empty = agent-level), limit.
+func (h *AgentsHandler) handleListFileRevisions(w http.ResponseWriter, r *http.Request) {
+	ag := h.revisionAgent(w, r, "view file history")
+	if ag == nil {
+		return
+	}
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+	revs, err := h.files.History(r.Context(), ag.ID, r.URL.Query().Get("user_id"), r.PathValue("fileName"), limit)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return
+	}
+	if revs == nil {
+		revs = []store.ContextFileRevision{}
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"revisions": revs})
+}
+
+// handleFileRevisionDiff returns a unified diff for a revision.
+// Query: against = revision ID, "current" (what restoring would change), or
+// empty for the previous revision of the same file.
+func (h *AgentsHandler) handleFileRevisionDiff(w http.ResponseWriter, r *http.Request) {
+	locale := store.LocaleFromContext(r.Context())
+	ag := h.revisionAgent(w, r, "view file history")
+	if ag == nil {
+		return
+	}
+	rev := h.revisionFromPath(w, r, ag, "revisionID")
+	if rev == nil {
+		return
+	}
+
+	against := r.URL.Query().Get("against")
+	if against != "" && against != "current" {
+		if _, err := uuid.Parse(against); err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": i18n.T(locale, i18n.MsgInvalidID, "revision")})
+			return
+		}
+	}
+	diff, err := h.files.DiffRevision(r.Context(), ag, rev, against)
+	if errors.Is(err, contextfiles.ErrNotFound) {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": i18n.T(locale, i18n.MsgNotFound, "revision", against)})
+		return
+	}
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{
+		"revision": rev,
+		"diff":     diff.Unified,
+		"added":    diff.Added,
+		"removed":  diff.Removed,
+	})
+}
+
+// handleRestoreFileRevision writes a revision's content back as the current file.
+// The restore itself is recorded as a new revision.
+func (h *AgentsHandler) handleRestoreFileRevision(w http.ResponseWriter, r *http.Request) {
+	ag := h.revisionAgent(w, r, "restore files")
+	if ag == nil {
+		return
+	}
+	rev := h.revisionFromPath(w, r, ag, "revisionID")
+	if rev == nil {
+		return
+	}
+
+	if err := h.files.Restore(r.Context(), ag, rev); err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return
+	}
+
+	emitAudit(h.msgBus, r, "agent.file_restored", "agent", ag.ID.String())
+	writeJSON(w, http.StatusOK, map[string]string{"status": "restored"})
+}
+
+// handleListFileChanges returns agent edits awaiting owner approval, each
+// with a diff against the current file.
+func (h *AgentsHandler) handleListFileChanges(w http.ResponseWriter, r *http.Request) {
+	ag := h.revisionAgent(w, r, "review file changes")
+	if ag == nil {
+		return
+	}
+	changes, err := h.files.PendingChanges(r.Context(), ag)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"changes": changes})
+}
+
+func (h *AgentsHandler) handleApproveFileChange(w http.ResponseWriter, r *http.Request) {
+	h.resolveFileChange(w, r, store.ContextFileChangeApproved)
+}
+
+func (h *AgentsHandler) handleRejectFileChange(w http.ResponseWriter, r *http.Request) {
+	h.resolveFileChange(w, r, store.ContextFileChangeRejected)
+}
+
+// resolveFileChange approves (applies) or rejects a pending agent edit.
+func (h *AgentsHandler) resolveFileChange(w http.ResponseWriter, r *http.Request, status string) {
+	locale := store.LocaleFromContext(r.Context())
+	ag := h.revisionAgent(w, r, "review file changes")
+	if ag == nil {
+		return
+	}
+	changeID, err := uuid.Parse(r.PathValue("changeID"))
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": i18n.T(locale, i18n.MsgInvalidID, "change")})
+		return
+	}
+	_, err = h.files.ResolveChange(r.Context(), ag, changeID, status, store.UserIDFromContext(r.Context()))
+	switch {
+	case errors.Is(err, contextfiles.ErrNotFound):
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": i18n.T(locale, i18n.MsgNotFound, "change", changeID.String())})
+		return
+	case errors.Is(err, contextfiles.ErrNotPending):
+		writeJSON(w, http.StatusConflict, map[string]string{"error": i18n.T(locale, i18n.MsgInvalidRequest, "change is no longer pending")})
+		return
+	case err != nil:
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return
+	}
+
+	emitAudit(h.msgBus, r, "agent.file_change_"+status, "agent", ag.ID.String())
+	writeJSON(w, http.StatusOK, map[string]string{"status": status})
+}
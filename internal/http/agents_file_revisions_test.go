@@ -0,0 +1,109 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/nextlevelbuilder/goclaw/internal/store"
+	"github.com/nextlevelbuilder/goclaw/internal/tracing"
+)
+
+type fileRevAgentStore struct {
+	store.AgentStore // unused methods panic
+	agent            store.AgentData
+	files            map[string]string
+
+	author, authorID string
+	traceID          uuid.UUID
+}
+
+func (s *fileRevAgentStore) GetByID(_ context.Context, id uuid.UUID) (*store.AgentData, error) {
+	ag := s.agent
+	return &ag, nil
+}
+
+func (s *fileRevAgentStore) GetAgentContextFiles(context.Context, uuid.UUID) ([]store.AgentContextFileData, error) {
+	var out []store.AgentContextFileData
+	for name, content := range s.files {
+		out = append(out, store.AgentContextFileData{AgentID: s.agent.ID, FileName: name, Content: content})
+	}
+	return out, nil
+}
+
+func (s *fileRevAgentStore) SetAgentContextFile(ctx context.Context, _ uuid.UUID, name, content string) error {
+	s.files[name] = content
+	s.author, s.authorID = store.ContextFileAuthorFromContext(ctx)
+	s.traceID = tracing.TraceIDFromContext(ctx)
+	return nil
+}
+
+type fileRevStore struct {
+	store.ContextFileRevisionStore // unused methods panic
+	changes                        map[uuid.UUID]*store.ContextFileChange
+}
+
+func (s *fileRevStore) GetPendingChange(_ context.Context, id uuid.UUID) (*store.ContextFileChange, error) {
+	return s.changes[id], nil
+}
+
+func (s *fileRevStore) ResolvePendingChange(_ context.Context, id uuid.UUID, status, reviewedBy string) (bool, error) {
+	c := s.changes[id]
+	if c == nil || c.Status != store.ContextFileChangePending {
+		return false, nil
+	}
+	c.Status, c.ReviewedBy = status, reviewedBy
+	return true, nil
+}
+
+func TestAgentsHandler_ResolveFileChange(t *testing.T) {
+	agentID := uuid.New()
+	traceID := uuid.New()
+	agents := &fileRevAgentStore{
+		agent: store.AgentData{BaseModel: store.BaseModel{ID: agentID}, OwnerID: "owner"},
+		files: map[string]string{"SOUL.md": "old"},
+	}
+	approveID, rejectID := uuid.New(), uuid.New()
+	revs := &fileRevStore{changes: map[uuid.UUID]*store.ContextFileChange{
+		approveID: {ID: approveID, AgentID: agentID, FileName: "SOUL.md", Content: "new", Author: store.ContextFileAuthorAgent, AuthorID: "u1", TraceID: &traceID, Status: store.ContextFileChangePending},
+		rejectID:  {ID: rejectID, AgentID: agentID, FileName: "SOUL.md", Content: "evil", Author: store.ContextFileAuthorAgent, Status: store.ContextFileChangePending},
+	}}
+	h := NewAgentsHandler(agents, nil, nil, nil, nil, "", nil, nil, nil)
+	h.SetContextFileRevisionStore(revs)
+
+	call := func(userID string, changeID uuid.UUID, status string) int {
+		r := httptest.NewRequest(http.MethodPost, "/", nil)
+		r.SetPathValue("id", agentID.String())
+		r.SetPathValue("changeID", changeID.String())
+		r = r.WithContext(store.WithUserID(r.Context(), userID))
+		w := httptest.NewRecorder()
+		h.resolveFileChange(w, r, status)
+		return w.Code
+	}
+
+	if code := call("stranger", approveID, store.ContextFileChangeApproved); code != http.StatusForbidden {
+		t.Fatalf("non-owner approve = %d, want 403", code)
+	}
+	if code := call("owner", approveID, store.ContextFileChangeApproved); code != http.StatusOK {
+		t.Fatalf("approve = %d, want 200", code)
+	}
+	if agents.files["SOUL.md"] != "new" {
+		t.Errorf("SOUL.md = %q after approve, want new", agents.files["SOUL.md"])
+	}
+	if agents.author != store.ContextFileAuthorAgent || agents.authorID != "u1" || agents.traceID != traceID {
+		t.Errorf("approved write attributed to %s/%s trace %s, want agent/u1 trace %s", agents.author, agents.authorID, agents.traceID, traceID)
+	}
+	if code := call("owner", approveID, store.ContextFileChangeApproved); code != http.StatusConflict {
+		t.Errorf("second approve = %d, want 409", code)
+	}
+
+	if code := call("owner", rejectID, store.ContextFileChangeRejected); code != http.StatusOK {
+		t.Fatalf("reject = %d, want 200", code)
+	}
+	if agents.files["SOUL.md"] != "new" || revs.changes[rejectID].ReviewedBy != "owner" {
+		t.Errorf("reject wrote %q / reviewed by %q", agents.files["SOUL.md"], revs.changes[rejectID].ReviewedBy)
+	}
+}
@@ -524,8 +524,13 @@ func (h *AgentsHandler) ingestKGByUser(ctx context.Context, agentID string, arc
 			RelationType:   rel.RelationType,
 			Confidence:     rel.Confidence,
 			Properties:     rel.Properties,
+			ValidFrom:      rel.ValidFrom,
+			ValidTo:        rel.ValidTo,
+			Provenance:     rel.Provenance,
 		})
 	}
+	// Relations exported without provenance are attributed to the import.
+	ctx = store.WithKGProvenance(ctx, store.KGProvenance{Kind: store.KGSourceImport})
 	for uid, g := range groups {
 		if _, err := h.kgStore.IngestExtraction(ctx, agentID, uid, g.entities, g.relations); err != nil {
 			return fmt.Errorf("user %s: %w", uid, err)
@@ -0,0 +1,101 @@
+package http
+
+import (
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/nextlevelbuilder/goclaw/internal/backup"
+	"github.com/nextlevelbuilder/goclaw/internal/i18n"
+	"github.com/nextlevelbuilder/goclaw/internal/permissions"
+	"github.com/nextlevelbuilder/goclaw/internal/store"
+	"github.com/nextlevelbuilder/goclaw/pkg/protocol"
+)
+
+// BackupHandler streams tenant and instance backups (the same archive as
+// `goclaw backup`). Restores run offline through `goclaw restore`.
+type BackupHandler struct {
+	db         *sql.DB
+	dataDir    string
+	workspace  string
+	exclude    []string // files never archived (the SQLite database)
+	appVersion string
+}
+
+// NewBackupHandler creates a handler for backup endpoints.
+func NewBackupHandler(db *sql.DB, dataDir, workspace string, exclude []string, appVersion string) *BackupHandler {
+	return &BackupHandler{db: db, dataDir: dataDir, workspace: workspace, exclude: exclude, appVersion: appVersion}
+}
+
+// RegisterRoutes registers backup routes on the given mux.
+func (h *BackupHandler) RegisterRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("GET /v1/backup", requireAuth(permissions.RoleAdmin, h.handleBackup))
+}
+
+// handleBackup streams a tar.gz backup of the caller's tenant. Owners may
+// pass ?scope=instance for the whole instance; ?files=false skips the data
+// directory and workspaces.
+func (h *BackupHandler) handleBackup(w http.ResponseWriter, r *http.Request) {
+	locale := extractLocale(r)
+	q := r.URL.Query()
+
+	tenantID := store.TenantIDFromContext(r.Context())
+	if q.Get("scope") == "instance" {
+		if !store.IsOwnerRole(r.Context()) {
+			writeError(w, http.StatusForbidden, protocol.ErrUnauthorized, i18n.T(locale, i18n.MsgPermissionDenied, "instance backup"))
+			return
+		}
+		tenantID = uuid.Nil
+	} else if tenantID == uuid.Nil {
+		tenantID = store.MasterTenantID
+	}
+
+	db, err := backup.NewDB(h.db, backup.BackendOf(h.db))
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, protocol.ErrInternal, err.Error())
+		return
+	}
+	opts := backup.Options{
+		TenantID:   tenantID,
+		Exclude:    h.exclude,
+		AppVersion: h.appVersion,
+	}
+	if q.Get("files") != "false" {
+		opts.DataDir, opts.Workspace = h.dataDir, h.workspace
+	}
+
+	// Build into a temp file so errors can still be reported as JSON.
+	tmp, err := os.CreateTemp("", "goclaw-backup-*.tar.gz")
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, protocol.ErrInternal, err.Error())
+		return
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	rep, err := backup.Backup(r.Context(), db, tmp, opts)
+	if err != nil {
+		slog.Error("backup.http", "tenant_id", tenantID, "error", err)
+		writeError(w, http.StatusInternalServerError, protocol.ErrInternal, err.Error())
+		return
+	}
+	if _, err := tmp.Seek(0, 0); err != nil {
+		writeError(w, http.StatusInternalServerError, protocol.ErrInternal, err.Error())
+		return
+	}
+	slog.Info("backup.http", "tenant_id", tenantID, "rows", rep.Rows(), "files", rep.Files)
+
+	name := "goclaw-backup"
+	if rep.Manifest.TenantSlug != "" {
+		name += "-" + rep.Manifest.TenantSlug
+	}
+	name += "-" + time.Now().UTC().Format("20060102-150405") + ".tar.gz"
+	w.Header().Set("Content-Type", "application/gzip")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, name))
+	http.ServeContent(w, r, name, rep.Manifest.CreatedAt, tmp)
+}
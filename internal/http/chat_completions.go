@@ -1,6 +1,7 @@
 package http
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"log/slog"
@@ -10,8 +11,10 @@ import (
 	"github.com/google/uuid"
 
 	"github.com/nextlevelbuilder/goclaw/internal/agent"
+	"github.com/nextlevelbuilder/goclaw/internal/cache"
 	"github.com/nextlevelbuilder/goclaw/internal/i18n"
 	"github.com/nextlevelbuilder/goclaw/internal/permissions"
+	"github.com/nextlevelbuilder/goclaw/internal/providers"
 	"github.com/nextlevelbuilder/goclaw/internal/sessions"
 	"github.com/nextlevelbuilder/goclaw/internal/store"
 	"github.com/nextlevelbuilder/goclaw/internal/tools"
@@ -24,8 +27,12 @@ type ChatCompletionsHandler struct {
 	isManaged   bool
 	rateLimiter func(string) bool // rate limit check: key → allowed (nil = no limit)
 	postTurn    tools.PostTurnProcessor
+	pausedTurns *cache.InMemoryCache[[]providers.Message] // see keepPausedTurn
 }
 
+// pausedTurnTTL bounds how long a paused turn waits for the client to resume it.
+const pausedTurnTTL = time.Hour
+
 // SetPostTurnProcessor sets the post-turn processor for team task dispatch.
 func (h *ChatCompletionsHandler) SetPostTurnProcessor(pt tools.PostTurnProcessor) {
 	h.postTurn = pt
@@ -34,9 +41,10 @@ func (h *ChatCompletionsHandler) SetPostTurnProcessor(pt tools.PostTurnProcessor
 // NewChatCompletionsHandler creates a handler for the chat completions endpoint.
 func NewChatCompletionsHandler(agents *agent.Router, sess store.SessionStore, isManaged bool) *ChatCompletionsHandler {
 	return &ChatCompletionsHandler{
-		agents:    agents,
-		sessions:  sess,
-		isManaged: isManaged,
+		agents:      agents,
+		sessions:    sess,
+		isManaged:   isManaged,
+		pausedTurns: cache.NewInMemoryCache[[]providers.Message](),
 	}
 }
 
@@ -46,16 +54,21 @@ func (h *ChatCompletionsHandler) SetRateLimiter(fn func(string) bool) {
 }
 
 type chatCompletionsRequest struct {
-	Model    string        `json:"model"`
-	Messages []chatMessage `json:"messages"`
-	Stream   bool          `json:"stream"`
-	User     string        `json:"user,omitempty"`
+	Model          string                     `json:"model"`
+	Messages       []chatInputMessage         `json:"messages"`
+	Stream         bool                       `json:"stream"`
+	User           string                     `json:"user,omitempty"`
+	Tools          []providers.ToolDefinition `json:"tools,omitempty"`
+	ToolChoice     json.RawMessage            `json:"tool_choice,omitempty"`
+	ResponseFormat *providers.ResponseFormat  `json:"response_format,omitempty"`
 }
 
+// chatMessage is an output message (or streaming delta).
 type chatMessage struct {
-	Role    string `json:"role"`
-	Content string `json:"content"`
-	Name    string `json:"name,omitempty"`
+	Role      string         `json:"role"`
+	Content   string         `json:"content"`
+	Name      string         `json:"name,omitempty"`
+	ToolCalls []chatToolCall `json:"tool_calls,omitempty"`
 }
 
 type chatCompletionsResponse struct {
@@ -115,8 +128,8 @@ func (h *ChatCompletionsHandler) ServeHTTP(w http.ResponseWriter, r *http.Reques
 		}
 	}
 
-	// Limit request body size to prevent DoS
-	const maxRequestBodySize = 1 << 20 // 1MB
+	// Limit request body size to prevent DoS (inline base64 media counts against it)
+	const maxRequestBodySize = 24 << 20 // 24MB
 	r.Body = http.MaxBytesReader(w, r.Body, maxRequestBodySize)
 
 	var req chatCompletionsRequest
@@ -130,6 +143,29 @@ func (h *ChatCompletionsHandler) ServeHTTP(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
+	conv, err := buildConversation(req.Messages)
+	if err != nil {
+		http.Error(w, fmt.Sprintf(`{"error":{"message":"%s"}}`, i18n.T(locale, i18n.MsgInvalidRequest, err.Error())), http.StatusBadRequest)
+		return
+	}
+	toolChoice, toolsDisabled, err := parseToolChoice(req.ToolChoice)
+	if err == nil {
+		err = checkClientTools(req.Tools)
+	}
+	if err == nil && req.ResponseFormat != nil {
+		err = req.ResponseFormat.Check()
+	}
+	if err != nil {
+		http.Error(w, fmt.Sprintf(`{"error":{"message":"%s"}}`, i18n.T(locale, i18n.MsgInvalidRequest, err.Error())), http.StatusBadRequest)
+		return
+	}
+	if toolsDisabled {
+		req.Tools = nil
+	}
+	if len(req.Tools) == 0 {
+		toolChoice = nil
+	}
+
 	agentID := extractAgentID(r, req.Model)
 	userID := store.UserIDFromContext(r.Context()) // resolved by enrichContext (respects API key owner binding)
 	if h.isManaged && userID == "" {
@@ -143,18 +179,12 @@ func (h *ChatCompletionsHandler) ServeHTTP(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
-	// Extract the last user message
-	var lastMessage string
-	for i := len(req.Messages) - 1; i >= 0; i-- {
-		if req.Messages[i].Role == "user" {
-			lastMessage = req.Messages[i].Content
-			break
-		}
-	}
-	if lastMessage == "" {
-		http.Error(w, fmt.Sprintf(`{"error":{"message":"%s"}}`, i18n.T(locale, i18n.MsgNoUserMessage)), http.StatusBadRequest)
+	mediaFiles, cleanupMedia, err := materializeMedia(r.Context(), conv.media)
+	if err != nil {
+		http.Error(w, fmt.Sprintf(`{"error":{"message":"%s"}}`, i18n.T(locale, i18n.MsgInvalidRequest, err.Error())), http.StatusBadRequest)
 		return
 	}
+	defer cleanupMedia()
 
 	runID := uuid.NewString()
 	// Include userID in session key for multi-tenant isolation
@@ -164,44 +194,80 @@ func (h *ChatCompletionsHandler) ServeHTTP(w http.ResponseWriter, r *http.Reques
 	}
 	sessionKey := sessions.SessionKey(agentID, sessionSuffix)
 
-	slog.Info("chat completions request", "agent", agentID, "stream", req.Stream, "user", userID)
+	// A resumed turn gets back the agent tool results the client never saw.
+	if conv.resume {
+		conv.history = h.restorePausedTurn(r.Context(), agentID, userID, conv.history)
+	}
+
+	// Seed the ephemeral session with the client-supplied prior turns.
+	if len(conv.history) > 0 {
+		h.sessions.GetOrCreate(r.Context(), sessionKey)
+		h.sessions.SetHistory(r.Context(), sessionKey, conv.history)
+	}
+
+	slog.Info("chat completions request", "agent", agentID, "stream", req.Stream, "user", userID,
+		"client_tools", len(req.Tools), "media", len(mediaFiles), "resume", conv.resume)
+
+	runReq := agent.RunRequest{
+		SessionKey:        sessionKey,
+		Message:           conv.message,
+		Media:             mediaFiles,
+		Channel:           "http",
+		ChatID:            "api",
+		RunID:             runID,
+		UserID:            userID,
+		Stream:            req.Stream,
+		ExtraSystemPrompt: conv.system,
+		ClientTools:       req.Tools,
+		ToolChoice:        toolChoice,
+		ResponseFormat:    req.ResponseFormat,
+		ResumeFromHistory: conv.resume,
+	}
 
 	if req.Stream {
-		h.handleStream(w, r, loop, runID, sessionKey, lastMessage, req.Model, userID)
+		h.handleStream(w, r, loop, agentID, runReq, req.Model)
 	} else {
-		h.handleNonStream(w, r, loop, runID, sessionKey, lastMessage, req.Model, userID)
+		h.handleNonStream(w, r, loop, agentID, runReq, req.Model)
 	}
 }
 
-func (h *ChatCompletionsHandler) handleNonStream(w http.ResponseWriter, r *http.Request, loop agent.Agent, runID, sessionKey, message, model, userID string) {
+func (h *ChatCompletionsHandler) handleNonStream(w http.ResponseWriter, r *http.Request, loop agent.Agent, agentID string, runReq agent.RunRequest, model string) {
 	ctx, drainTeamDispatch := tools.InjectTeamDispatch(r.Context(), h.postTurn)
 	defer drainTeamDispatch()
 
-	result, err := loop.Run(ctx, agent.RunRequest{
-		SessionKey: sessionKey,
-		Message:    message,
-		Channel:    "http",
-		ChatID:     "api",
-		RunID:      runID,
-		UserID:     userID,
-		Stream:     false,
-	})
+	result, err := loop.Run(ctx, runReq)
 
 	if err != nil {
 		locale := store.LocaleFromContext(r.Context())
 		http.Error(w, fmt.Sprintf(`{"error":{"message":"%s"}}`, i18n.T(locale, i18n.MsgInternalError, err.Error())), http.StatusInternalServerError)
 		return
 	}
+	if result.ResponseFormatError != "" {
+		writeJSON(w, http.StatusInternalServerError, map[string]any{"error": map[string]any{
+			"message": "model output does not match response_format: " + result.ResponseFormatError,
+			"type":    "server_error",
+			"code":    "response_format_violation",
+		}})
+		return
+	}
+
+	msg := &chatMessage{Role: "assistant", Content: SignFileURLs(result.Content, FileSigningKey())}
+	finishReason := "stop"
+	if len(result.PendingToolCalls) > 0 {
+		h.keepPausedTurn(r.Context(), agentID, runReq.UserID, result)
+		msg.ToolCalls = toChatToolCalls(result.PendingToolCalls, false)
+		finishReason = "tool_calls"
+	}
 
 	resp := chatCompletionsResponse{
-		ID:      "chatcmpl-" + runID[:8],
+		ID:      "chatcmpl-" + runReq.RunID[:8],
 		Object:  "chat.completion",
 		Created: time.Now().Unix(),
 		Model:   model,
 		Choices: []chatChoice{{
 			Index:        0,
-			Message:      &chatMessage{Role: "assistant", Content: SignFileURLs(result.Content, FileSigningKey())},
-			FinishReason: "stop",
+			Message:      msg,
+			FinishReason: finishReason,
 		}},
 	}
 
@@ -217,7 +283,7 @@ func (h *ChatCompletionsHandler) handleNonStream(w http.ResponseWriter, r *http.
 	json.NewEncoder(w).Encode(resp)
 }
 
-func (h *ChatCompletionsHandler) handleStream(w http.ResponseWriter, r *http.Request, loop agent.Agent, runID, sessionKey, message, model, userID string) {
+func (h *ChatCompletionsHandler) handleStream(w http.ResponseWriter, r *http.Request, loop agent.Agent, agentID string, runReq agent.RunRequest, model string) {
 	flusher, ok := w.(http.Flusher)
 	if !ok {
 		locale := store.LocaleFromContext(r.Context())
@@ -230,7 +296,7 @@ func (h *ChatCompletionsHandler) handleStream(w http.ResponseWriter, r *http.Req
 	w.Header().Set("Connection", "keep-alive")
 	w.WriteHeader(http.StatusOK)
 
-	completionID := "chatcmpl-" + runID[:8]
+	completionID := "chatcmpl-" + runReq.RunID[:8]
 
 	// Send initial role chunk
 	writeSSEChunk(w, flusher, completionID, model, &chatMessage{Role: "assistant"}, "")
@@ -238,19 +304,20 @@ func (h *ChatCompletionsHandler) handleStream(w http.ResponseWriter, r *http.Req
 	ctx, drainTeamDispatch := tools.InjectTeamDispatch(r.Context(), h.postTurn)
 	defer drainTeamDispatch()
 
-	result, err := loop.Run(ctx, agent.RunRequest{
-		SessionKey: sessionKey,
-		Message:    message,
-		Channel:    "http",
-		ChatID:     "api",
-		RunID:      runID,
-		UserID:     userID,
-		Stream:     true,
-	})
+	result, err := loop.Run(ctx, runReq)
 
-	if err != nil {
+	switch {
+	case err != nil:
 		writeSSEChunk(w, flusher, completionID, model, &chatMessage{Content: "Error: " + err.Error()}, "stop")
-	} else {
+	case result.ResponseFormatError != "":
+		writeSSEChunk(w, flusher, completionID, model, &chatMessage{Content: "Error: model output does not match response_format: " + result.ResponseFormatError}, "stop")
+	case len(result.PendingToolCalls) > 0:
+		h.keepPausedTurn(r.Context(), agentID, runReq.UserID, result)
+		if result.Content != "" {
+			writeSSEChunk(w, flusher, completionID, model, &chatMessage{Content: SignFileURLs(result.Content, FileSigningKey())}, "")
+		}
+		writeSSEChunk(w, flusher, completionID, model, &chatMessage{ToolCalls: toChatToolCalls(result.PendingToolCalls, true)}, "tool_calls")
+	default:
 		// Send content chunk
 		writeSSEChunk(w, flusher, completionID, model, &chatMessage{Content: SignFileURLs(result.Content, FileSigningKey())}, "stop")
 	}
@@ -260,6 +327,47 @@ func (h *ChatCompletionsHandler) handleStream(w http.ResponseWriter, r *http.Req
 	flusher.Flush()
 }
 
+// pausedTurnKey identifies a paused turn until the client resumes it. Tool
+// call IDs are unique per run, so the first pending call identifies the turn.
+func pausedTurnKey(ctx context.Context, agentID, userID, callID string) string {
+	return store.TenantIDFromContext(ctx).String() + "|" + agentID + "|" + userID + "|" + callID
+}
+
+// keepPausedTurn holds the agent tool results of a turn that also called
+// client tools. Each request runs in its own session, so they would otherwise
+// be gone when the client resumes with its own results. Paused turns live in
+// memory rather than the session store so they never show up as sessions;
+// turns that are never resumed expire after pausedTurnTTL.
+func (h *ChatCompletionsHandler) keepPausedTurn(ctx context.Context, agentID, userID string, result *agent.RunResult) {
+	if len(result.PausedTurn) == 0 {
+		return
+	}
+	h.pausedTurns.DeleteExpired(ctx)
+	key := pausedTurnKey(ctx, agentID, userID, result.PendingToolCalls[0].ID)
+	h.pausedTurns.Set(ctx, key, result.PausedTurn, pausedTurnTTL)
+}
+
+// restorePausedTurn merges a stored paused turn into a resumed conversation
+// (see agent.ReplayPausedTurn) and drops it.
+func (h *ChatCompletionsHandler) restorePausedTurn(ctx context.Context, agentID, userID string, history []providers.Message) []providers.Message {
+	for i := len(history) - 1; i >= 0; i-- {
+		if history[i].Role != "assistant" {
+			continue
+		}
+		if len(history[i].ToolCalls) == 0 {
+			return history
+		}
+		key := pausedTurnKey(ctx, agentID, userID, history[i].ToolCalls[0].ID)
+		turn, ok := h.pausedTurns.Get(ctx, key)
+		if !ok {
+			return history
+		}
+		h.pausedTurns.Delete(ctx, key)
+		return agent.ReplayPausedTurn(history, turn)
+	}
+	return history
+}
+
 func writeSSEChunk(w http.ResponseWriter, flusher http.Flusher, id, model string, delta *chatMessage, finishReason string) {
 	chunk := map[string]any{
 		"id":      id,
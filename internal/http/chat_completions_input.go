@@ -0,0 +1,456 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/nextlevelbuilder/goclaw/internal/bus"
+	"github.com/nextlevelbuilder/goclaw/internal/media"
+	"github.com/nextlevelbuilder/goclaw/internal/providers"
+	"github.com/nextlevelbuilder/goclaw/internal/tools"
+)
+
+// Limits for OpenAI-style multimodal input.
+const (
+	maxChatMediaParts  = 10
+	maxChatMediaBytes  = 10 << 20 // per part, decoded
+	chatMediaFetchTime = 30 * time.Second
+)
+
+// chatInputMessage is one entry of an OpenAI chat completions "messages" array.
+type chatInputMessage struct {
+	Role       string         `json:"role"`
+	Content    chatContent    `json:"content"`
+	Name       string         `json:"name,omitempty"`
+	ToolCalls  []chatToolCall `json:"tool_calls,omitempty"`
+	ToolCallID string         `json:"tool_call_id,omitempty"`
+}
+
+// chatContent is a message content field: a plain string or an array of
+// content parts (text, image_url, input_audio, file).
+type chatContent struct {
+	Parts []chatContentPart
+}
+
+type chatContentPart struct {
+	Type       string          `json:"type"`
+	Text       string          `json:"text,omitempty"`
+	ImageURL   *chatImageURL   `json:"image_url,omitempty"`
+	InputAudio *chatInputAudio `json:"input_audio,omitempty"`
+	File       *chatFile       `json:"file,omitempty"`
+}
+
+type chatImageURL struct {
+	URL    string `json:"url"`
+	Detail string `json:"detail,omitempty"`
+}
+
+type chatInputAudio struct {
+	Data   string `json:"data"`   // base64
+	Format string `json:"format"` // "wav", "mp3"
+}
+
+type chatFile struct {
+	FileData string `json:"file_data,omitempty"` // data URL or bare base64
+	Filename string `json:"filename,omitempty"`
+}
+
+// chatToolCall is an OpenAI tool call, used in assistant messages (input and
+// output) and in streaming deltas.
+type chatToolCall struct {
+	Index    *int             `json:"index,omitempty"`
+	ID       string           `json:"id"`
+	Type     string           `json:"type"`
+	Function chatToolCallFunc `json:"function"`
+}
+
+type chatToolCallFunc struct {
+	Name      string `json:"name"`
+	Arguments string `json:"arguments"` // JSON-encoded
+}
+
+func (c *chatContent) UnmarshalJSON(b []byte) error {
+	b = bytes.TrimSpace(b)
+	switch {
+	case len(b) == 0 || string(b) == "null":
+		c.Parts = nil
+		return nil
+	case b[0] == '"':
+		var s string
+		if err := json.Unmarshal(b, &s); err != nil {
+			return err
+		}
+		c.Parts = []chatContentPart{{Type: "text", Text: s}}
+		return nil
+	case b[0] == '[':
+		return json.Unmarshal(b, &c.Parts)
+	}
+	return fmt.Errorf("content must be a string or an array of content parts")
+}
+
+// Text joins the text parts.
+func (c chatContent) Text() string {
+	var texts []string
+	for _, p := range c.Parts {
+		if p.Type == "text" && p.Text != "" {
+			texts = append(texts, p.Text)
+		}
+	}
+	return strings.Join(texts, "\n")
+}
+
+// mediaParts returns the non-text parts.
+func (c chatContent) mediaParts() []chatContentPart {
+	var out []chatContentPart
+	for _, p := range c.Parts {
+		if p.Type != "text" {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+// chatConversation is a client "messages" array split for an agent run.
+type chatConversation struct {
+	system  string              // system/developer messages, joined
+	history []providers.Message // prior turns, seeded into the run session
+	message string              // current user text ("" when resuming)
+	media   []chatContentPart   // current user media parts
+	resume  bool                // trailing tool results: continue from history
+}
+
+// buildConversation maps OpenAI messages onto an agent run. The conversation
+// must end with a user message (a new turn) or with tool results answering
+// client tool calls (a resumed turn).
+func buildConversation(msgs []chatInputMessage) (*chatConversation, error) {
+	conv := &chatConversation{}
+	var systems []string
+	var turns []chatInputMessage
+	for _, m := range msgs {
+		switch m.Role {
+		case "system", "developer":
+			if t := m.Content.Text(); t != "" {
+				systems = append(systems, t)
+			}
+		case "user", "assistant", "tool":
+			turns = append(turns, m)
+		default:
+			return nil, fmt.Errorf("unsupported message role %q", m.Role)
+		}
+	}
+	conv.system = strings.Join(systems, "\n\n")
+	if len(turns) == 0 {
+		return nil, fmt.Errorf("no user message found")
+	}
+
+	last := turns[len(turns)-1]
+	prior := turns[:len(turns)-1]
+	switch last.Role {
+	case "user":
+		conv.message = last.Content.Text()
+		conv.media = last.Content.mediaParts()
+		if conv.message == "" && len(conv.media) == 0 {
+			return nil, fmt.Errorf("no user message found")
+		}
+		if len(conv.media) > maxChatMediaParts {
+			return nil, fmt.Errorf("at most %d media parts are allowed per message", maxChatMediaParts)
+		}
+	case "tool":
+		conv.resume = true
+		prior = turns
+	default:
+		return nil, fmt.Errorf("the last message must be from the user or a tool result")
+	}
+
+	for _, m := range prior {
+		msg, err := historyMessage(m)
+		if err != nil {
+			return nil, err
+		}
+		conv.history = append(conv.history, msg)
+	}
+	return conv, nil
+}
+
+// historyMessage converts a prior turn. Media in earlier turns is not
+// re-uploaded; a placeholder keeps the model aware it was there.
+func historyMessage(m chatInputMessage) (providers.Message, error) {
+	text := m.Content.Text()
+	if m.Role == "user" {
+		for _, p := range m.Content.mediaParts() {
+			text = strings.TrimSpace(text + "\n[" + p.Type + " attachment]")
+		}
+	}
+	msg := providers.Message{Role: m.Role, Content: text}
+	switch m.Role {
+	case "assistant":
+		for _, tc := range m.ToolCalls {
+			args := map[string]any{}
+			if tc.Function.Arguments != "" {
+				if err := json.Unmarshal([]byte(tc.Function.Arguments), &args); err != nil {
+					return msg, fmt.Errorf("tool call %s: arguments must be a JSON object", tc.ID)
+				}
+			}
+			msg.ToolCalls = append(msg.ToolCalls, providers.ToolCall{ID: tc.ID, Name: tc.Function.Name, Arguments: args})
+		}
+	case "tool":
+		if m.ToolCallID == "" {
+			return msg, fmt.Errorf("tool message requires tool_call_id")
+		}
+		msg.ToolCallID = m.ToolCallID
+	}
+	return msg, nil
+}
+
+var toolNameRe = regexp.MustCompile(`^[a-zA-Z0-9_-]{1,64}$`)
+
+// checkClientTools validates caller-defined tools.
+func checkClientTools(defs []providers.ToolDefinition) error {
+	seen := make(map[string]bool, len(defs))
+	for i := range defs {
+		if defs[i].Type == "" {
+			defs[i].Type = "function"
+		}
+		if defs[i].Type != "function" {
+			return fmt.Errorf("tools[%d]: unsupported type %q", i, defs[i].Type)
+		}
+		name := defs[i].Function.Name
+		if !toolNameRe.MatchString(name) {
+			return fmt.Errorf("tools[%d]: invalid function name %q", i, name)
+		}
+		if seen[name] {
+			return fmt.Errorf("tools[%d]: duplicate function name %q", i, name)
+		}
+		seen[name] = true
+		if defs[i].Function.Parameters == nil {
+			defs[i].Function.Parameters = map[string]any{"type": "object", "properties": map[string]any{}}
+		}
+	}
+	return nil
+}
+
+// parseToolChoice decodes tool_choice. It returns the provider value (nil for
+// "auto") and whether tools are disabled ("none").
+func parseToolChoice(raw json.RawMessage) (any, bool, error) {
+	raw = bytes.TrimSpace(raw)
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil, false, nil
+	}
+	var s string
+	if json.Unmarshal(raw, &s) == nil {
+		switch s {
+		case "auto":
+			return nil, false, nil
+		case "none":
+			return nil, true, nil
+		case "required":
+			return s, false, nil
+		}
+		return nil, false, fmt.Errorf("unsupported tool_choice %q", s)
+	}
+	var obj struct {
+		Type     string `json:"type"`
+		Function struct {
+			Name string `json:"name"`
+		} `json:"function"`
+	}
+	if err := json.Unmarshal(raw, &obj); err != nil || obj.Type != "function" || obj.Function.Name == "" {
+		return nil, false, fmt.Errorf("tool_choice must be \"auto\", \"none\", \"required\" or {\"type\":\"function\",\"function\":{\"name\":...}}")
+	}
+	return map[string]any{"type": "function", "function": map[string]any{"name": obj.Function.Name}}, false, nil
+}
+
+// materializeMedia writes media parts to temp files for the agent media
+// pipeline, which copies them into the user's workspace. The returned cleanup
+// removes the temp files.
+func materializeMedia(ctx context.Context, parts []chatContentPart) ([]bus.MediaFile, func(), error) {
+	var files []bus.MediaFile
+	cleanup := func() {
+		for _, f := range files {
+			os.Remove(f.Path)
+		}
+	}
+	for i, p := range parts {
+		data, mimeType, name, err := decodeMediaPart(ctx, p)
+		if err != nil {
+			cleanup()
+			return nil, func() {}, fmt.Errorf("content part %d (%s): %w", i, p.Type, err)
+		}
+		ext := media.ExtFromMime(mimeType)
+		if ext == "" {
+			ext = filepath.Ext(name)
+		}
+		f, err := os.CreateTemp("", "goclaw_http_*"+ext)
+		if err != nil {
+			cleanup()
+			return nil, func() {}, err
+		}
+		_, werr := f.Write(data)
+		cerr := f.Close()
+		files = append(files, bus.MediaFile{Path: f.Name(), MimeType: mimeType})
+		if werr != nil || cerr != nil {
+			cleanup()
+			return nil, func() {}, fmt.Errorf("write temp file: %v", firstErr(werr, cerr))
+		}
+	}
+	return files, cleanup, nil
+}
+
+func firstErr(errs ...error) error {
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// decodeMediaPart returns the bytes, MIME type and (optional) filename of a part.
+func decodeMediaPart(ctx context.Context, p chatContentPart) ([]byte, string, string, error) {
+	switch p.Type {
+	case "image_url":
+		if p.ImageURL == nil || p.ImageURL.URL == "" {
+			return nil, "", "", fmt.Errorf("image_url.url is required")
+		}
+		if strings.HasPrefix(p.ImageURL.URL, "data:") {
+			data, mimeType, err := decodeDataURL(p.ImageURL.URL)
+			return data, mimeType, "", err
+		}
+		data, mimeType, err := fetchMediaURL(ctx, p.ImageURL.URL)
+		return data, mimeType, "", err
+	case "input_audio":
+		if p.InputAudio == nil || p.InputAudio.Data == "" {
+			return nil, "", "", fmt.Errorf("input_audio.data is required")
+		}
+		data, err := decodeBase64Limited(p.InputAudio.Data)
+		if err != nil {
+			return nil, "", "", err
+		}
+		switch p.InputAudio.Format {
+		case "wav":
+			return data, "audio/wav", "", nil
+		case "mp3":
+			return data, "audio/mpeg", "", nil
+		}
+		return nil, "", "", fmt.Errorf("unsupported input_audio.format %q", p.InputAudio.Format)
+	case "file":
+		if p.File == nil || p.File.FileData == "" {
+			return nil, "", "", fmt.Errorf("file.file_data is required (file_id uploads are not supported)")
+		}
+		if strings.HasPrefix(p.File.FileData, "data:") {
+			data, mimeType, err := decodeDataURL(p.File.FileData)
+			return data, mimeType, p.File.Filename, err
+		}
+		data, err := decodeBase64Limited(p.File.FileData)
+		if err != nil {
+			return nil, "", "", err
+		}
+		mimeType := mime.TypeByExtension(filepath.Ext(p.File.Filename))
+		if mimeType == "" {
+			mimeType = http.DetectContentType(data)
+		}
+		return data, mimeType, p.File.Filename, nil
+	}
+	return nil, "", "", fmt.Errorf("unsupported content part type")
+}
+
+// decodeDataURL decodes "data:<mime>;base64,<payload>".
+func decodeDataURL(u string) ([]byte, string, error) {
+	meta, payload, ok := strings.Cut(strings.TrimPrefix(u, "data:"), ",")
+	if !ok || !strings.HasSuffix(meta, ";base64") {
+		return nil, "", fmt.Errorf("data URL must be base64-encoded")
+	}
+	data, err := decodeBase64Limited(payload)
+	if err != nil {
+		return nil, "", err
+	}
+	mimeType := strings.TrimSuffix(meta, ";base64")
+	if mimeType == "" {
+		mimeType = http.DetectContentType(data)
+	}
+	return data, mimeType, nil
+}
+
+func decodeBase64Limited(s string) ([]byte, error) {
+	if base64.StdEncoding.DecodedLen(len(s)) > maxChatMediaBytes {
+		return nil, fmt.Errorf("media exceeds %d MB", maxChatMediaBytes>>20)
+	}
+	data, err := base64.StdEncoding.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid base64: %v", err)
+	}
+	return data, nil
+}
+
+// fetchMediaURL downloads a remote image, refusing private addresses.
+func fetchMediaURL(ctx context.Context, rawURL string) ([]byte, string, error) {
+	if !strings.HasPrefix(rawURL, "https://") && !strings.HasPrefix(rawURL, "http://") {
+		return nil, "", fmt.Errorf("url must be http(s) or a data URL")
+	}
+	if err := tools.CheckSSRF(rawURL); err != nil {
+		return nil, "", err
+	}
+	ctx, cancel := context.WithTimeout(ctx, chatMediaFetchTime)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, "", err
+	}
+	client := &http.Client{
+		CheckRedirect: func(r *http.Request, via []*http.Request) error {
+			if len(via) >= 3 {
+				return fmt.Errorf("too many redirects")
+			}
+			return tools.CheckSSRF(r.URL.String())
+		},
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, "", fmt.Errorf("fetch failed: HTTP %d", resp.StatusCode)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxChatMediaBytes+1))
+	if err != nil {
+		return nil, "", err
+	}
+	if len(data) > maxChatMediaBytes {
+		return nil, "", fmt.Errorf("media exceeds %d MB", maxChatMediaBytes>>20)
+	}
+	mimeType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if !strings.HasPrefix(mimeType, "image/") {
+		mimeType = http.DetectContentType(data)
+	}
+	return data, mimeType, nil
+}
+
+// toChatToolCalls renders agent tool calls in OpenAI wire format.
+func toChatToolCalls(calls []providers.ToolCall, withIndex bool) []chatToolCall {
+	out := make([]chatToolCall, len(calls))
+	for i, tc := range calls {
+		args, _ := json.Marshal(tc.Arguments)
+		out[i] = chatToolCall{
+			ID:       tc.ID,
+			Type:     "function",
+			Function: chatToolCallFunc{Name: tc.Name, Arguments: string(args)},
+		}
+		if withIndex {
+			idx := i
+			out[i].Index = &idx
+		}
+	}
+	return out
+}
@@ -0,0 +1,194 @@
+package http
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/nextlevelbuilder/goclaw/internal/providers"
+)
+
+func TestChatContentUnmarshal(t *testing.T) {
+	var msgs []chatInputMessage
+	raw := `[
+		{"role":"user","content":"plain"},
+		{"role":"user","content":[{"type":"text","text":"look"},{"type":"image_url","image_url":{"url":"data:image/png;base64,AAAA"}}]},
+		{"role":"assistant","content":null,"tool_calls":[{"id":"c1","type":"function","function":{"name":"f","arguments":"{}"}}]}
+	]`
+	if err := json.Unmarshal([]byte(raw), &msgs); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if msgs[0].Content.Text() != "plain" {
+		t.Errorf("string content = %q", msgs[0].Content.Text())
+	}
+	if msgs[1].Content.Text() != "look" || len(msgs[1].Content.mediaParts()) != 1 {
+		t.Errorf("parts content = %q, media %d", msgs[1].Content.Text(), len(msgs[1].Content.mediaParts()))
+	}
+	if len(msgs[2].Content.Parts) != 0 || len(msgs[2].ToolCalls) != 1 {
+		t.Errorf("null content with tool_calls not decoded: %+v", msgs[2])
+	}
+
+	var bad chatInputMessage
+	if err := json.Unmarshal([]byte(`{"role":"user","content":42}`), &bad); err == nil {
+		t.Error("numeric content must be rejected")
+	}
+}
+
+func decodeMessages(t *testing.T, raw string) []chatInputMessage {
+	t.Helper()
+	var msgs []chatInputMessage
+	if err := json.Unmarshal([]byte(raw), &msgs); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	return msgs
+}
+
+func TestBuildConversation(t *testing.T) {
+	t.Run("new_turn", func(t *testing.T) {
+		conv, err := buildConversation(decodeMessages(t, `[
+			{"role":"system","content":"Be brief."},
+			{"role":"developer","content":"Use metric units."},
+			{"role":"user","content":"hi"},
+			{"role":"assistant","content":"hello"},
+			{"role":"user","content":"weather?"}
+		]`))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if conv.system != "Be brief.\n\nUse metric units." {
+			t.Errorf("system = %q", conv.system)
+		}
+		if conv.message != "weather?" || conv.resume {
+			t.Errorf("message = %q, resume = %v", conv.message, conv.resume)
+		}
+		if len(conv.history) != 2 || conv.history[1].Role != "assistant" {
+			t.Errorf("history = %+v", conv.history)
+		}
+	})
+
+	t.Run("resume_after_tool_results", func(t *testing.T) {
+		conv, err := buildConversation(decodeMessages(t, `[
+			{"role":"user","content":"weather in Hanoi?"},
+			{"role":"assistant","content":null,"tool_calls":[{"id":"c1","type":"function","function":{"name":"get_weather","arguments":"{\"city\":\"Hanoi\"}"}}]},
+			{"role":"tool","tool_call_id":"c1","content":"31C"}
+		]`))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !conv.resume || conv.message != "" {
+			t.Fatalf("resume = %v, message = %q", conv.resume, conv.message)
+		}
+		if len(conv.history) != 3 {
+			t.Fatalf("history len = %d, want 3", len(conv.history))
+		}
+		tc := conv.history[1].ToolCalls
+		if len(tc) != 1 || tc[0].Name != "get_weather" || tc[0].Arguments["city"] != "Hanoi" {
+			t.Errorf("assistant tool calls = %+v", tc)
+		}
+		if conv.history[2].ToolCallID != "c1" {
+			t.Errorf("tool result id = %q", conv.history[2].ToolCallID)
+		}
+	})
+
+	for name, raw := range map[string]string{
+		"ends_with_assistant": `[{"role":"user","content":"a"},{"role":"assistant","content":"b"}]`,
+		"unknown_role":        `[{"role":"function","content":"a"}]`,
+		"only_system":         `[{"role":"system","content":"a"}]`,
+		"tool_without_id":     `[{"role":"user","content":"a"},{"role":"tool","content":"b"}]`,
+		"bad_arguments":       `[{"role":"assistant","tool_calls":[{"id":"c","type":"function","function":{"name":"f","arguments":"[1]"}}]},{"role":"tool","tool_call_id":"c","content":"x"}]`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			if _, err := buildConversation(decodeMessages(t, raw)); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
+
+func TestParseToolChoice(t *testing.T) {
+	tests := []struct {
+		raw      string
+		wantNone bool
+		wantNil  bool
+		wantErr  bool
+	}{
+		{``, false, true, false},
+		{`"auto"`, false, true, false},
+		{`"none"`, true, true, false},
+		{`"required"`, false, false, false},
+		{`{"type":"function","function":{"name":"get_weather"}}`, false, false, false},
+		{`"sometimes"`, false, true, true},
+		{`{"type":"function"}`, false, true, true},
+	}
+	for _, tt := range tests {
+		got, none, err := parseToolChoice(json.RawMessage(tt.raw))
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseToolChoice(%s) error = %v, wantErr %v", tt.raw, err, tt.wantErr)
+			continue
+		}
+		if none != tt.wantNone || (got == nil) != tt.wantNil {
+			t.Errorf("parseToolChoice(%s) = %v, none=%v", tt.raw, got, none)
+		}
+	}
+}
+
+func TestMaterializeMedia(t *testing.T) {
+	audio := base64.StdEncoding.EncodeToString([]byte("RIFF....WAVE"))
+	parts := []chatContentPart{
+		{Type: "image_url", ImageURL: &chatImageURL{URL: "data:image/png;base64," + base64.StdEncoding.EncodeToString([]byte("\x89PNG\r\n\x1a\n"))}},
+		{Type: "input_audio", InputAudio: &chatInputAudio{Data: audio, Format: "wav"}},
+	}
+	files, cleanup, err := materializeMedia(context.Background(), parts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 2 || files[0].MimeType != "image/png" || files[1].MimeType != "audio/wav" {
+		t.Fatalf("files = %+v", files)
+	}
+	cleanup()
+	for _, f := range files {
+		if _, err := os.Stat(f.Path); !os.IsNotExist(err) {
+			t.Errorf("temp file %s not removed", f.Path)
+		}
+	}
+
+	for name, p := range map[string]chatContentPart{
+		"private_url":  {Type: "image_url", ImageURL: &chatImageURL{URL: "http://127.0.0.1/x.png"}},
+		"audio_format": {Type: "input_audio", InputAudio: &chatInputAudio{Data: audio, Format: "flac"}},
+		"file_id_only": {Type: "file", File: &chatFile{Filename: "a.pdf"}},
+	} {
+		if _, _, err := materializeMedia(context.Background(), []chatContentPart{p}); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
+
+func TestCheckClientTools(t *testing.T) {
+	tools := decodeTools(t, `[{"type":"function","function":{"name":"get_weather"}}]`)
+	if err := checkClientTools(tools); err != nil {
+		t.Fatal(err)
+	}
+	if tools[0].Function.Parameters == nil {
+		t.Error("missing parameters must default to an empty object schema")
+	}
+	for _, raw := range []string{
+		`[{"type":"function","function":{"name":"bad name"}}]`,
+		`[{"type":"retrieval","function":{"name":"x"}}]`,
+		`[{"function":{"name":"a"}},{"function":{"name":"a"}}]`,
+	} {
+		if err := checkClientTools(decodeTools(t, raw)); err == nil {
+			t.Errorf("checkClientTools(%s) expected error", raw)
+		}
+	}
+}
+
+func decodeTools(t *testing.T, raw string) []providers.ToolDefinition {
+	t.Helper()
+	var defs []providers.ToolDefinition
+	if err := json.Unmarshal([]byte(raw), &defs); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	return defs
+}
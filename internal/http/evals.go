@@ -0,0 +1,432 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/google/uuid"
+
+	"github.com/nextlevelbuilder/goclaw/internal/eval"
+	"github.com/nextlevelbuilder/goclaw/internal/i18n"
+	"github.com/nextlevelbuilder/goclaw/internal/permissions"
+	"github.com/nextlevelbuilder/goclaw/internal/sessions"
+	"github.com/nextlevelbuilder/goclaw/internal/store"
+)
+
+// maxEvalSuiteBodySize caps suite uploads (imported sessions can be long).
+const maxEvalSuiteBodySize = 4 << 20
+
+// EvalHandler manages eval suites and runs (regression tests for agents).
+type EvalHandler struct {
+	evals    store.EvalStore
+	agents   store.AgentStore
+	sessions store.SessionStore
+	runner   *eval.Runner
+	isOwner  func(string) bool
+}
+
+// NewEvalHandler creates a handler for the eval suite endpoints.
+func NewEvalHandler(evals store.EvalStore, agents store.AgentStore, sessions store.SessionStore, runner *eval.Runner, isOwner func(string) bool) *EvalHandler {
+	return &EvalHandler{evals: evals, agents: agents, sessions: sessions, runner: runner, isOwner: isOwner}
+}
+
+// RegisterRoutes registers all eval routes on the given mux.
+func (h *EvalHandler) RegisterRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("GET /v1/agents/{agentID}/evals/suites", h.read(h.handleListSuites))
+	mux.HandleFunc("POST /v1/agents/{agentID}/evals/suites", h.write(h.handleCreateSuite))
+	mux.HandleFunc("POST /v1/agents/{agentID}/evals/suites/import", h.write(h.handleImportSession))
+	mux.HandleFunc("GET /v1/agents/{agentID}/evals/suites/{suiteID}", h.read(h.handleGetSuite))
+	mux.HandleFunc("PUT /v1/agents/{agentID}/evals/suites/{suiteID}", h.write(h.handleUpdateSuite))
+	mux.HandleFunc("DELETE /v1/agents/{agentID}/evals/suites/{suiteID}", h.write(h.handleDeleteSuite))
+	mux.HandleFunc("POST /v1/agents/{agentID}/evals/suites/{suiteID}/runs", h.write(h.handleStartRun))
+	mux.HandleFunc("GET /v1/agents/{agentID}/evals/runs", h.read(h.handleListRuns))
+	mux.HandleFunc("GET /v1/agents/{agentID}/evals/runs/{runID}", h.read(h.handleGetRun))
+}
+
+func (h *EvalHandler) read(next http.HandlerFunc) http.HandlerFunc {
+	return requireAuth("", next)
+}
+
+// write guards endpoints that change suites or spend tokens on runs.
+func (h *EvalHandler) write(next http.HandlerFunc) http.HandlerFunc {
+	return requireAuth(permissions.RoleOperator, next)
+}
+
+// isOwnerUser checks if the given user ID is a system owner.
+func (h *EvalHandler) isOwnerUser(userID string) bool {
+	return userID != "" && h.isOwner != nil && h.isOwner(userID)
+}
+
+// canSeeAllSessions mirrors the WS sessions.* rule: admins and owners may use
+// any session in the tenant, everyone else only their own.
+func (h *EvalHandler) canSeeAllSessions(r *http.Request) bool {
+	if permissions.HasMinRole(permissions.Role(store.RoleFromContext(r.Context())), permissions.RoleAdmin) {
+		return true
+	}
+	return h.isOwnerUser(store.UserIDFromContext(r.Context()))
+}
+
+// agentFromPath resolves {agentID} (UUID or agent key) and checks that the
+// caller can access the agent. Writes the error response and returns nil on failure.
+func (h *EvalHandler) agentFromPath(w http.ResponseWriter, r *http.Request) *store.AgentData {
+	locale := extractLocale(r)
+	raw := r.PathValue("agentID")
+	var ag *store.AgentData
+	var err error
+	if id, perr := uuid.Parse(raw); perr == nil {
+		ag, err = h.agents.GetByID(r.Context(), id)
+	} else {
+		ag, err = h.agents.GetByKey(r.Context(), raw)
+	}
+	if err != nil || ag == nil {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": i18n.T(locale, i18n.MsgNotFound, "agent", raw)})
+		return nil
+	}
+	if userID := store.UserIDFromContext(r.Context()); userID != "" && !h.isOwnerUser(userID) {
+		if ok, _, _ := h.agents.CanAccess(r.Context(), ag.ID, userID); !ok {
+			writeJSON(w, http.StatusForbidden, map[string]string{"error": i18n.T(locale, i18n.MsgNoAccess, "agent")})
+			return nil
+		}
+	}
+	return ag
+}
+
+// suiteFromPath resolves {suiteID}, scoped to the agent.
+func (h *EvalHandler) suiteFromPath(w http.ResponseWriter, r *http.Request, ag *store.AgentData) *store.EvalSuite {
+	locale := extractLocale(r)
+	id, err := uuid.Parse(r.PathValue("suiteID"))
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": i18n.T(locale, i18n.MsgInvalidID, "suite")})
+		return nil
+	}
+	suite, err := h.evals.GetSuite(r.Context(), id)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return nil
+	}
+	if suite == nil || suite.AgentID != ag.ID {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": i18n.T(locale, i18n.MsgNotFound, "suite", id.String())})
+		return nil
+	}
+	return suite
+}
+
+// decodeSuite reads a suite body and validates it.
+func (h *EvalHandler) decodeSuite(w http.ResponseWriter, r *http.Request) *store.EvalSuite {
+	locale := extractLocale(r)
+	var suite store.EvalSuite
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxEvalSuiteBodySize)).Decode(&suite); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": i18n.T(locale, i18n.MsgInvalidJSON)})
+		return nil
+	}
+	if err := eval.ValidateSuite(&suite); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": i18n.T(locale, i18n.MsgInvalidRequest, err.Error())})
+		return nil
+	}
+	return &suite
+}
+
+// suiteNameTaken reports whether another suite of the agent uses name.
+func (h *EvalHandler) suiteNameTaken(r *http.Request, agentID, exceptID uuid.UUID, name string) (bool, error) {
+	suites, err := h.evals.ListSuites(r.Context(), agentID)
+	if err != nil {
+		return false, err
+	}
+	for _, s := range suites {
+		if s.Name == name && s.ID != exceptID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (h *EvalHandler) handleListSuites(w http.ResponseWriter, r *http.Request) {
+	ag := h.agentFromPath(w, r)
+	if ag == nil {
+		return
+	}
+	suites, err := h.evals.ListSuites(r.Context(), ag.ID)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return
+	}
+	if suites == nil {
+		suites = []store.EvalSuite{}
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"suites": suites})
+}
+
+func (h *EvalHandler) handleGetSuite(w http.ResponseWriter, r *http.Request) {
+	ag := h.agentFromPath(w, r)
+	if ag == nil {
+		return
+	}
+	if suite := h.suiteFromPath(w, r, ag); suite != nil {
+		writeJSON(w, http.StatusOK, suite)
+	}
+}
+
+func (h *EvalHandler) handleCreateSuite(w http.ResponseWriter, r *http.Request) {
+	ag := h.agentFromPath(w, r)
+	if ag == nil {
+		return
+	}
+	suite := h.decodeSuite(w, r)
+	if suite == nil {
+		return
+	}
+	h.createSuite(w, r, ag, suite)
+}
+
+func (h *EvalHandler) createSuite(w http.ResponseWriter, r *http.Request, ag *store.AgentData, suite *store.EvalSuite) {
+	locale := extractLocale(r)
+	taken, err := h.suiteNameTaken(r, ag.ID, uuid.Nil, suite.Name)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return
+	}
+	if taken {
+		writeJSON(w, http.StatusConflict, map[string]string{"error": i18n.T(locale, i18n.MsgAlreadyExists, "suite", suite.Name)})
+		return
+	}
+	suite.AgentID = ag.ID
+	suite.CreatedBy = store.UserIDFromContext(r.Context())
+	if err := h.evals.CreateSuite(r.Context(), suite); err != nil {
+		slog.Warn("evals.create_suite failed", "agent", ag.AgentKey, "error", err)
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return
+	}
+	writeJSON(w, http.StatusCreated, suite)
+}
+
+func (h *EvalHandler) handleUpdateSuite(w http.ResponseWriter, r *http.Request) {
+	locale := extractLocale(r)
+	ag := h.agentFromPath(w, r)
+	if ag == nil {
+		return
+	}
+	existing := h.suiteFromPath(w, r, ag)
+	if existing == nil {
+		return
+	}
+	suite := h.decodeSuite(w, r)
+	if suite == nil {
+		return
+	}
+	taken, err := h.suiteNameTaken(r, ag.ID, existing.ID, suite.Name)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return
+	}
+	if taken {
+		writeJSON(w, http.StatusConflict, map[string]string{"error": i18n.T(locale, i18n.MsgAlreadyExists, "suite", suite.Name)})
+		return
+	}
+	existing.Name, existing.Description, existing.JudgeModel, existing.Cases = suite.Name, suite.Description, suite.JudgeModel, suite.Cases
+	if err := h.evals.UpdateSuite(r.Context(), existing); err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return
+	}
+	writeJSON(w, http.StatusOK, existing)
+}
+
+func (h *EvalHandler) handleDeleteSuite(w http.ResponseWriter, r *http.Request) {
+	ag := h.agentFromPath(w, r)
+	if ag == nil {
+		return
+	}
+	suite := h.suiteFromPath(w, r, ag)
+	if suite == nil {
+		return
+	}
+	if err := h.evals.DeleteSuite(r.Context(), suite.ID); err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]string{"status": "deleted"})
+}
+
+// handleImportSession turns a stored session into a regression case, either
+// appended to an existing suite (suite_id) or as a new suite (name).
+func (h *EvalHandler) handleImportSession(w http.ResponseWriter, r *http.Request) {
+	locale := extractLocale(r)
+	ag := h.agentFromPath(w, r)
+	if ag == nil {
+		return
+	}
+	var body struct {
+		SessionKey string `json:"session_key"`
+		CaseName   string `json:"case_name"`
+		SuiteID    string `json:"suite_id"`
+		Name       string `json:"name"`
+		JudgeModel string `json:"judge_model"`
+	}
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1<<16)).Decode(&body); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": i18n.T(locale, i18n.MsgInvalidJSON)})
+		return
+	}
+	if body.SessionKey == "" {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": i18n.T(locale, i18n.MsgRequired, "session_key")})
+		return
+	}
+	if agentKey, _ := sessions.ParseSessionKey(body.SessionKey); agentKey != ag.AgentKey {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": i18n.T(locale, i18n.MsgNotFound, "session", body.SessionKey)})
+		return
+	}
+	if !h.canSeeAllSessions(r) {
+		sess := h.sessions.Get(r.Context(), body.SessionKey)
+		if sess == nil {
+			writeJSON(w, http.StatusNotFound, map[string]string{"error": i18n.T(locale, i18n.MsgNotFound, "session", body.SessionKey)})
+			return
+		}
+		if sess.UserID != store.UserIDFromContext(r.Context()) {
+			writeJSON(w, http.StatusForbidden, map[string]string{"error": i18n.T(locale, i18n.MsgPermissionDenied, "session")})
+			return
+		}
+	}
+
+	history := h.sessions.GetHistory(r.Context(), body.SessionKey)
+	if body.CaseName == "" {
+		body.CaseName = body.SessionKey
+	}
+	c := eval.CaseFromHistory(body.CaseName, history)
+	if len(c.Turns) == 0 {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": i18n.T(locale, i18n.MsgInvalidRequest, "session has no user messages")})
+		return
+	}
+
+	if body.SuiteID == "" {
+		if body.Name == "" {
+			body.Name = "imported " + body.SessionKey
+		}
+		suite := &store.EvalSuite{
+			Name:        body.Name,
+			Description: "Imported from session " + body.SessionKey,
+			JudgeModel:  body.JudgeModel,
+			Cases:       []store.EvalCase{c},
+		}
+		h.createSuite(w, r, ag, suite)
+		return
+	}
+
+	r.SetPathValue("suiteID", body.SuiteID)
+	suite := h.suiteFromPath(w, r, ag)
+	if suite == nil {
+		return
+	}
+	for _, existing := range suite.Cases {
+		if existing.Name == c.Name {
+			writeJSON(w, http.StatusConflict, map[string]string{"error": i18n.T(locale, i18n.MsgAlreadyExists, "case", c.Name)})
+			return
+		}
+	}
+	suite.Cases = append(suite.Cases, c)
+	if err := h.evals.UpdateSuite(r.Context(), suite); err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return
+	}
+	writeJSON(w, http.StatusOK, suite)
+}
+
+// handleStartRun starts a run in the background (202 + run record), or
+// with ?wait=true blocks until it finishes (200 + full results).
+func (h *EvalHandler) handleStartRun(w http.ResponseWriter, r *http.Request) {
+	locale := extractLocale(r)
+	ag := h.agentFromPath(w, r)
+	if ag == nil {
+		return
+	}
+	suite := h.suiteFromPath(w, r, ag)
+	if suite == nil {
+		return
+	}
+	var body struct {
+		ToolMode string `json:"tool_mode"`
+	}
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1<<10)).Decode(&body); err != nil && !errors.Is(err, io.EOF) {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": i18n.T(locale, i18n.MsgInvalidJSON)})
+			return
+		}
+	}
+	switch body.ToolMode {
+	case "", store.EvalToolsStub, store.EvalToolsLive:
+	default:
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": i18n.T(locale, i18n.MsgInvalidRequest, "tool_mode must be stub or live")})
+		return
+	}
+
+	userID := store.UserIDFromContext(r.Context())
+	opts := eval.Options{AgentKey: ag.AgentKey, ToolMode: body.ToolMode, UserID: userID, CreatedBy: userID}
+	wait, _ := strconv.ParseBool(r.URL.Query().Get("wait"))
+	var run *store.EvalRun
+	var err error
+	if wait {
+		run, err = h.runner.Run(r.Context(), suite, opts)
+	} else {
+		run, err = h.runner.Start(r.Context(), suite, opts)
+	}
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return
+	}
+	if wait {
+		writeJSON(w, http.StatusOK, run)
+		return
+	}
+	writeJSON(w, http.StatusAccepted, run)
+}
+
+func (h *EvalHandler) handleListRuns(w http.ResponseWriter, r *http.Request) {
+	locale := extractLocale(r)
+	ag := h.agentFromPath(w, r)
+	if ag == nil {
+		return
+	}
+	suiteID := uuid.Nil
+	if raw := r.URL.Query().Get("suite_id"); raw != "" {
+		id, err := uuid.Parse(raw)
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": i18n.T(locale, i18n.MsgInvalidID, "suite")})
+			return
+		}
+		suiteID = id
+	}
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+	runs, err := h.evals.ListRuns(r.Context(), ag.ID, suiteID, limit)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return
+	}
+	if runs == nil {
+		runs = []store.EvalRun{}
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"runs": runs})
+}
+
+func (h *EvalHandler) handleGetRun(w http.ResponseWriter, r *http.Request) {
+	locale := extractLocale(r)
+	ag := h.agentFromPath(w, r)
+	if ag == nil {
+		return
+	}
+	id, err := uuid.Parse(r.PathValue("runID"))
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": i18n.T(locale, i18n.MsgInvalidID, "run")})
+		return
+	}
+	run, err := h.evals.GetRun(r.Context(), id)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return
+	}
+	if run == nil || run.AgentID != ag.ID {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": i18n.T(locale, i18n.MsgNotFound, "run", id.String())})
+		return
+	}
+	writeJSON(w, http.StatusOK, run)
+}
@@ -59,15 +59,56 @@ func (h *KnowledgeGraphHandler) handleGetEntity(w http.ResponseWriter, r *http.R
 	entityID := r.PathValue("entityID")
 	userID := r.URL.Query().Get("user_id")
 
+	asOf, err := store.ParseKGTime(r.URL.Query().Get("as_of"))
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": i18n.T(locale, i18n.MsgInvalidRequest, err.Error())})
+		return
+	}
+
 	entity, err := h.store.GetEntity(r.Context(), agentID, userID, entityID)
 	if err != nil {
 		writeJSON(w, http.StatusNotFound, map[string]string{"error": i18n.T(locale, i18n.MsgNotFound, "entity", entityID)})
 		return
 	}
 
-	relations, err := h.store.ListRelations(r.Context(), agentID, userID, entityID)
+	// as_of: relations valid at that time instead of the current ones.
+	relations := []store.Relation{}
+	if asOf > 0 {
+		history, _ := h.store.ListRelationHistory(r.Context(), agentID, userID, entityID)
+		for _, rel := range history {
+			if rel.ValidAt(asOf) {
+				relations = append(relations, rel)
+			}
+		}
+	} else if current, err := h.store.ListRelations(r.Context(), agentID, userID, entityID); err == nil && current != nil {
+		relations = current
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{
+		"entity":    entity,
+		"relations": relations,
+	})
+}
+
+// handleEntityHistory returns every version of an entity's relations,
+// including ended and superseded facts, newest valid_from first.
+func (h *KnowledgeGraphHandler) handleEntityHistory(w http.ResponseWriter, r *http.Request) {
+	locale := extractLocale(r)
+	agentID := r.PathValue("agentID")
+	entityID := r.PathValue("entityID")
+	userID := r.URL.Query().Get("user_id")
+
+	entity, err := h.store.GetEntity(r.Context(), agentID, userID, entityID)
 	if err != nil {
-		relations = []store.Relation{}
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": i18n.T(locale, i18n.MsgNotFound, "entity", entityID)})
+		return
+	}
+
+	relations, err := h.store.ListRelationHistory(r.Context(), agentID, userID, entityID)
+	if err != nil {
+		slog.Warn("kg.relation_history failed", "error", err)
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return
 	}
 	if relations == nil {
 		relations = []store.Relation{}
@@ -127,6 +168,7 @@ func (h *KnowledgeGraphHandler) handleTraverse(w http.ResponseWriter, r *http.Re
 		EntityID string `json:"entity_id"`
 		UserID   string `json:"user_id"`
 		MaxDepth int    `json:"max_depth"`
+		AsOf     string `json:"as_of"` // date or RFC 3339; empty = current facts
 	}
 	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
 		writeJSON(w, http.StatusBadRequest, map[string]string{"error": i18n.T(locale, i18n.MsgInvalidJSON)})
@@ -143,7 +185,13 @@ func (h *KnowledgeGraphHandler) handleTraverse(w http.ResponseWriter, r *http.Re
 		body.MaxDepth = 3
 	}
 
-	results, err := h.store.Traverse(r.Context(), agentID, body.UserID, body.EntityID, body.MaxDepth)
+	asOf, err := store.ParseKGTime(body.AsOf)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": i18n.T(locale, i18n.MsgInvalidRequest, err.Error())})
+		return
+	}
+
+	results, err := h.store.Traverse(r.Context(), agentID, body.UserID, body.EntityID, body.MaxDepth, asOf)
 	if err != nil {
 		slog.Warn("kg.traverse failed", "error", err)
 		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
@@ -165,6 +213,7 @@ func (h *KnowledgeGraphHandler) handleExtract(w http.ResponseWriter, r *http.Req
 		Provider string  `json:"provider"`
 		Model    string  `json:"model"`
 		MinConf  float64 `json:"min_confidence"`
+		Source   string  `json:"source"` // optional document name recorded as provenance
 	}
 	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
 		writeJSON(w, http.StatusBadRequest, map[string]string{"error": i18n.T(locale, i18n.MsgInvalidJSON)})
@@ -202,7 +251,8 @@ func (h *KnowledgeGraphHandler) handleExtract(w http.ResponseWriter, r *http.Req
 		result.Relations[i].UserID = body.UserID
 	}
 
-	entityIDs, err := h.store.IngestExtraction(r.Context(), agentID, body.UserID, result.Entities, result.Relations)
+	ctx := store.WithKGProvenance(r.Context(), store.KGProvenance{Kind: store.KGSourceManual, Document: body.Source})
+	entityIDs, err := h.store.IngestExtraction(ctx, agentID, body.UserID, result.Entities, result.Relations)
 	if err != nil {
 		slog.Warn("kg.ingest_extraction failed", "error", err)
 		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
@@ -20,9 +20,10 @@ func (h *MCPHandler) handleGrantAgent(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var req struct {
-		AgentID   string          `json:"agent_id"`
-		ToolAllow json.RawMessage `json:"tool_allow,omitempty"`
-		ToolDeny  json.RawMessage `json:"tool_deny,omitempty"`
+		AgentID      string                      `json:"agent_id"`
+		ToolAllow    json.RawMessage             `json:"tool_allow,omitempty"`
+		ToolDeny     json.RawMessage             `json:"tool_deny,omitempty"`
+		Capabilities *store.MCPGrantCapabilities `json:"capabilities,omitempty"` // resources, prompts, sampling
 	}
 	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1<<20)).Decode(&req); err != nil {
 		writeJSON(w, http.StatusBadRequest, map[string]string{"error": i18n.T(locale, i18n.MsgInvalidJSON)})
@@ -43,6 +44,9 @@ func (h *MCPHandler) handleGrantAgent(w http.ResponseWriter, r *http.Request) {
 		ToolDeny:  req.ToolDeny,
 		GrantedBy: store.UserIDFromContext(r.Context()),
 	}
+	if req.Capabilities != nil {
+		grant.ConfigOverrides, _ = json.Marshal(req.Capabilities)
+	}
 
 	if err := h.store.GrantToAgent(r.Context(), &grant); err != nil {
 		slog.Error("mcp.grant_agent", "error", err)
@@ -0,0 +1,138 @@
+package http
+
+import (
+	"fmt"
+	"html"
+	"log/slog"
+	"net/http"
+
+	"github.com/google/uuid"
+
+	mcpbridge "github.com/nextlevelbuilder/goclaw/internal/mcp"
+	"github.com/nextlevelbuilder/goclaw/internal/store"
+)
+
+// MCPOAuthHandler handles OAuth authorization of remote MCP servers: the
+// browser callback and self-service start/status/disconnect for the caller.
+type MCPOAuthHandler struct {
+	store store.MCPServerStore
+	flows *mcpbridge.OAuthFlows
+}
+
+// NewMCPOAuthHandler creates a handler for MCP OAuth endpoints.
+func NewMCPOAuthHandler(s store.MCPServerStore, flows *mcpbridge.OAuthFlows) *MCPOAuthHandler {
+	return &MCPOAuthHandler{store: s, flows: flows}
+}
+
+// RegisterRoutes registers MCP OAuth routes. The callback is public: the
+// authorization server redirects the user's browser there, and the one-time
+// state identifies the flow.
+func (h *MCPOAuthHandler) RegisterRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("GET /v1/mcp/oauth/callback", h.handleCallback)
+	mux.HandleFunc("POST /v1/mcp/servers/{id}/oauth/start", requireAuth("", h.handleStart))
+	mux.HandleFunc("GET /v1/mcp/servers/{id}/oauth/status", requireAuth("", h.handleStatus))
+	mux.HandleFunc("DELETE /v1/mcp/servers/{id}/oauth", requireAuth("", h.handleRevoke))
+}
+
+func (h *MCPOAuthHandler) handleCallback(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	state, code := q.Get("state"), q.Get("code")
+	if state == "" {
+		writeMCPOAuthPage(w, http.StatusBadRequest, "Authorization Failed", "Missing state parameter.")
+		return
+	}
+
+	name, err := h.flows.Complete(r.Context(), state, code)
+	if err != nil {
+		msg := err.Error()
+		if e := q.Get("error"); e != "" {
+			msg = e
+			if d := q.Get("error_description"); d != "" {
+				msg += ": " + d
+			}
+		}
+		writeMCPOAuthPage(w, http.StatusBadRequest, "Authorization Failed", msg)
+		return
+	}
+	writeMCPOAuthPage(w, http.StatusOK, "Connected", fmt.Sprintf("Your account is connected to %s. You can close this window and return to the chat.", name))
+}
+
+func writeMCPOAuthPage(w http.ResponseWriter, status int, title, msg string) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(status)
+	fmt.Fprintf(w, `<html><body><h2>%s</h2><p>%s</p></body></html>`, html.EscapeString(title), html.EscapeString(msg))
+}
+
+// oauthServer resolves the {id} path server and checks it uses OAuth.
+// Writes the error response and returns nil on failure.
+func (h *MCPOAuthHandler) oauthServer(w http.ResponseWriter, r *http.Request) (*store.MCPServerData, string) {
+	serverID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid server ID"})
+		return nil, ""
+	}
+	userID := store.UserIDFromContext(r.Context())
+	if userID == "" {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "user context required"})
+		return nil, ""
+	}
+	srv, err := h.store.GetServer(r.Context(), serverID)
+	if err != nil || srv == nil {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "server not found"})
+		return nil, ""
+	}
+	if !mcpbridge.ParseOAuthSettings(srv.Settings).Enabled {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "server does not use OAuth"})
+		return nil, ""
+	}
+	return srv, userID
+}
+
+func (h *MCPOAuthHandler) handleStart(w http.ResponseWriter, r *http.Request) {
+	srv, userID := h.oauthServer(w, r)
+	if srv == nil {
+		return
+	}
+	authURL, err := h.flows.Start(r.Context(), srv, userID)
+	if err != nil {
+		slog.Warn("mcp.oauth.start_failed", "server", srv.Name, "user", userID, "error", err)
+		writeJSON(w, http.StatusBadGateway, map[string]string{"error": err.Error()})
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]string{"auth_url": authURL})
+}
+
+func (h *MCPOAuthHandler) handleStatus(w http.ResponseWriter, r *http.Request) {
+	srv, userID := h.oauthServer(w, r)
+	if srv == nil {
+		return
+	}
+	tok, err := h.flows.Status(r.Context(), srv.ID, userID)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return
+	}
+	if tok == nil {
+		writeJSON(w, http.StatusOK, map[string]any{"authorized": false})
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{
+		"authorized":  true,
+		"scope":       tok.Scope,
+		"expires_at":  tok.ExpiresAt,
+		"refreshable": tok.RefreshToken != "",
+		"updated_at":  tok.UpdatedAt,
+	})
+}
+
+func (h *MCPOAuthHandler) handleRevoke(w http.ResponseWriter, r *http.Request) {
+	srv, userID := h.oauthServer(w, r)
+	if srv == nil {
+		return
+	}
+	if err := h.flows.Revoke(r.Context(), srv.ID, userID); err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]string{"status": "disconnected"})
+}
@@ -3,12 +3,17 @@ package http
 import (
 	"net/http"
 
+	"github.com/nextlevelbuilder/goclaw/internal/docextract"
+	"github.com/nextlevelbuilder/goclaw/internal/memory"
 	"github.com/nextlevelbuilder/goclaw/internal/store"
 )
 
 // MemoryHandler handles memory document management endpoints.
 type MemoryHandler struct {
-	store store.MemoryStore
+	store     store.MemoryStore
+	extractor *docextract.Extractor // optional: PDF/DOCX/XLSX/PPTX uploads
+	retriever *memory.Retriever     // optional: per-agent retrieval pipeline for search
+	agents    store.AgentStore      // resolves the agent's memory_config for search
 }
 
 // NewMemoryHandler creates a handler for memory management endpoints.
@@ -16,6 +21,17 @@ func NewMemoryHandler(s store.MemoryStore) *MemoryHandler {
 	return &MemoryHandler{store: s}
 }
 
+// SetDocumentExtractor enables uploading office documents and PDFs as raw
+// bytes to the PUT document endpoint; they are stored as extracted markdown.
+func (h *MemoryHandler) SetDocumentExtractor(e *docextract.Extractor) { h.extractor = e }
+
+// SetRetriever runs searches through the agent's retrieval pipeline
+// (memory_config.retrieval, loaded from agents) and enables debug traces.
+func (h *MemoryHandler) SetRetriever(r *memory.Retriever, agents store.AgentStore) {
+	h.retriever = r
+	h.agents = agents
+}
+
 // RegisterRoutes registers all memory routes on the given mux.
 func (h *MemoryHandler) RegisterRoutes(mux *http.ServeMux) {
 	mux.HandleFunc("GET /v1/memory/documents", h.auth(h.handleListAllDocuments))
@@ -1,11 +1,18 @@
 package http
 
 import (
+	"context"
 	"encoding/json"
+	"io"
 	"log/slog"
 	"net/http"
 
+	"github.com/google/uuid"
+
+	"github.com/nextlevelbuilder/goclaw/internal/config"
+	"github.com/nextlevelbuilder/goclaw/internal/docextract"
 	"github.com/nextlevelbuilder/goclaw/internal/i18n"
+	"github.com/nextlevelbuilder/goclaw/internal/memory"
 	"github.com/nextlevelbuilder/goclaw/internal/store"
 )
 
@@ -59,16 +66,34 @@ func (h *MemoryHandler) handleGetDocument(w http.ResponseWriter, r *http.Request
 	writeJSON(w, http.StatusOK, detail)
 }
 
+// maxMemoryUploadBytes caps PUT document bodies, including raw document uploads.
+const maxMemoryUploadBytes = 20 << 20
+
 func (h *MemoryHandler) handlePutDocument(w http.ResponseWriter, r *http.Request) {
 	locale := extractLocale(r)
 	agentID := r.PathValue("agentID")
 	path := r.PathValue("path")
 
+	raw, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxMemoryUploadBytes))
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": i18n.T(locale, i18n.MsgInvalidRequest, err.Error())})
+		return
+	}
+
 	var body struct {
 		Content string `json:"content"`
 		UserID  string `json:"user_id"`
 	}
-	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+	if h.extractor != nil && docextract.Detect(raw) != "" {
+		// Raw document upload: store the extracted markdown; user_id comes from the query.
+		doc, err := h.extractor.Extract(r.Context(), raw)
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": i18n.T(locale, i18n.MsgInvalidRequest, err.Error())})
+			return
+		}
+		body.Content = doc.Markdown()
+		body.UserID = r.URL.Query().Get("user_id")
+	} else if err := json.Unmarshal(raw, &body); err != nil {
 		writeJSON(w, http.StatusBadRequest, map[string]string{"error": i18n.T(locale, i18n.MsgInvalidJSON)})
 		return
 	}
@@ -166,10 +191,12 @@ func (h *MemoryHandler) handleSearch(w http.ResponseWriter, r *http.Request) {
 	agentID := r.PathValue("agentID")
 
 	var body struct {
-		Query      string  `json:"query"`
-		UserID     string  `json:"user_id"`
-		MaxResults int     `json:"max_results"`
-		MinScore   float64 `json:"min_score"`
+		Query      string                        `json:"query"`
+		UserID     string                        `json:"user_id"`
+		MaxResults int                           `json:"max_results"`
+		MinScore   float64                       `json:"min_score"`
+		Debug      bool                          `json:"debug"`
+		Retrieval  *config.MemoryRetrievalConfig `json:"retrieval"` // overrides the agent's pipeline, for tuning (owner only)
 	}
 	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
 		writeJSON(w, http.StatusBadRequest, map[string]string{"error": i18n.T(locale, i18n.MsgInvalidJSON)})
@@ -179,11 +206,61 @@ func (h *MemoryHandler) handleSearch(w http.ResponseWriter, r *http.Request) {
 		writeJSON(w, http.StatusBadRequest, map[string]string{"error": i18n.T(locale, i18n.MsgRequired, "query")})
 		return
 	}
+	// The override names providers and endpoints the server will call, so
+	// only owners may tune with it; everyone else gets the agent's pipeline.
+	if body.Retrieval != nil && !store.IsOwnerRole(r.Context()) {
+		writeJSON(w, http.StatusForbidden, map[string]string{"error": i18n.T(locale, i18n.MsgPermissionDenied, "retrieval override")})
+		return
+	}
 
-	results, err := h.store.Search(r.Context(), body.Query, agentID, body.UserID, store.MemorySearchOptions{
+	opts := store.MemorySearchOptions{
 		MaxResults: body.MaxResults,
 		MinScore:   body.MinScore,
-	})
+	}
+	if h.retriever == nil {
+		results, err := h.store.Search(r.Context(), body.Query, agentID, body.UserID, opts)
+		h.writeSearchResults(w, results, nil, err)
+		return
+	}
+
+	retrieval := body.Retrieval
+	if mc := h.agentMemoryConfig(r.Context(), agentID); mc != nil {
+		opts.VectorWeight, opts.TextWeight = mc.VectorWeight, mc.TextWeight
+		if opts.MaxResults <= 0 {
+			opts.MaxResults = mc.MaxResults
+		}
+		if opts.MinScore <= 0 {
+			opts.MinScore = mc.MinScore
+		}
+		if retrieval == nil {
+			retrieval = mc.Retrieval
+		}
+	}
+	results, trace, err := h.retriever.Search(r.Context(), body.Query, agentID, body.UserID, opts, retrieval)
+	if !body.Debug {
+		trace = nil
+	}
+	h.writeSearchResults(w, results, trace, err)
+}
+
+// agentMemoryConfig returns the agent's memory_config, or nil if it has
+// none or cannot be loaded.
+func (h *MemoryHandler) agentMemoryConfig(ctx context.Context, agentID string) *config.MemoryConfig {
+	if h.agents == nil {
+		return nil
+	}
+	id, err := uuid.Parse(agentID)
+	if err != nil {
+		return nil
+	}
+	ag, err := h.agents.GetByID(ctx, id)
+	if err != nil || ag == nil {
+		return nil
+	}
+	return ag.ParseMemoryConfig()
+}
+
+func (h *MemoryHandler) writeSearchResults(w http.ResponseWriter, results []store.MemorySearchResult, trace *memory.RetrievalTrace, err error) {
 	if err != nil {
 		slog.Warn("memory.search failed", "error", err)
 		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
@@ -192,8 +269,12 @@ func (h *MemoryHandler) handleSearch(w http.ResponseWriter, r *http.Request) {
 	if results == nil {
 		results = []store.MemorySearchResult{}
 	}
-	writeJSON(w, http.StatusOK, map[string]any{
+	resp := map[string]any{
 		"results": results,
 		"count":   len(results),
-	})
+	}
+	if trace != nil {
+		resp["debug"] = trace
+	}
+	writeJSON(w, http.StatusOK, resp)
 }
@@ -0,0 +1,97 @@
+package http
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/google/uuid"
+
+	"github.com/nextlevelbuilder/goclaw/internal/i18n"
+	"github.com/nextlevelbuilder/goclaw/internal/permissions"
+	"github.com/nextlevelbuilder/goclaw/internal/store"
+	"github.com/nextlevelbuilder/goclaw/pkg/protocol"
+)
+
+// MessageQueueHandler exposes the durable message queue's dead letters:
+// channel messages and replies that could not be delivered after every retry.
+type MessageQueueHandler struct {
+	queue store.MessageQueueStore
+}
+
+// NewMessageQueueHandler creates a handler for dead-letter endpoints.
+func NewMessageQueueHandler(queue store.MessageQueueStore) *MessageQueueHandler {
+	return &MessageQueueHandler{queue: queue}
+}
+
+// RegisterRoutes registers dead-letter routes on the given mux.
+func (h *MessageQueueHandler) RegisterRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("GET /v1/message-queue/dead-letters", requireAuth(permissions.RoleAdmin, h.handleList))
+	mux.HandleFunc("POST /v1/message-queue/dead-letters/{id}/retry", requireAuth(permissions.RoleAdmin, h.handleRetry))
+	mux.HandleFunc("DELETE /v1/message-queue/dead-letters/{id}", requireAuth(permissions.RoleAdmin, h.handleDelete))
+}
+
+func (h *MessageQueueHandler) handleList(w http.ResponseWriter, r *http.Request) {
+	locale := extractLocale(r)
+	direction := r.URL.Query().Get("direction")
+	if direction != "" && direction != store.QueueDirectionInbound && direction != store.QueueDirectionOutbound {
+		writeError(w, http.StatusBadRequest, protocol.ErrInvalidRequest, i18n.T(locale, i18n.MsgInvalidRequest, "direction must be inbound or outbound"))
+		return
+	}
+	limit, offset := 50, 0
+	if v := r.URL.Query().Get("limit"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 && n <= 200 {
+			limit = n
+		}
+	}
+	if v := r.URL.Query().Get("offset"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n >= 0 {
+			offset = n
+		}
+	}
+
+	msgs, total, err := h.queue.ListDead(r.Context(), direction, limit, offset)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, protocol.ErrInternal, err.Error())
+		return
+	}
+	if msgs == nil {
+		msgs = []store.QueuedMessage{}
+	}
+	writeJSON(w, http.StatusOK, map[string]any{
+		"messages": msgs,
+		"total":    total,
+		"limit":    limit,
+		"offset":   offset,
+	})
+}
+
+// handleRetry moves a dead letter back into the queue; it is delivered on the
+// next poll with a fresh retry budget.
+func (h *MessageQueueHandler) handleRetry(w http.ResponseWriter, r *http.Request) {
+	h.withDeadLetter(w, r, h.queue.Redrive, "retried")
+}
+
+func (h *MessageQueueHandler) handleDelete(w http.ResponseWriter, r *http.Request) {
+	h.withDeadLetter(w, r, h.queue.Delete, "deleted")
+}
+
+func (h *MessageQueueHandler) withDeadLetter(w http.ResponseWriter, r *http.Request, op func(ctx context.Context, id uuid.UUID) error, status string) {
+	locale := extractLocale(r)
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, protocol.ErrInvalidRequest, i18n.T(locale, i18n.MsgInvalidID, "message"))
+		return
+	}
+	if err := op(r.Context(), id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			writeError(w, http.StatusNotFound, protocol.ErrNotFound, i18n.T(locale, i18n.MsgNotFound, "dead letter", id.String()))
+			return
+		}
+		writeError(w, http.StatusInternalServerError, protocol.ErrInternal, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]string{"status": status})
+}
@@ -0,0 +1,55 @@
+package http
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/nextlevelbuilder/goclaw/internal/metrics"
+	"github.com/nextlevelbuilder/goclaw/internal/permissions"
+	"github.com/nextlevelbuilder/goclaw/internal/store"
+)
+
+// MetricsHandler serves GET /metrics in the Prometheus text format.
+type MetricsHandler struct {
+	registry    *metrics.Registry
+	scrapeToken string // optional dedicated bearer token; empty = admin auth required
+}
+
+// NewMetricsHandler creates the /metrics handler. When scrapeToken is set,
+// scrapers authenticate with it; admin API keys and the gateway token are
+// accepted either way.
+func NewMetricsHandler(registry *metrics.Registry, scrapeToken string) *MetricsHandler {
+	return &MetricsHandler{registry: registry, scrapeToken: scrapeToken}
+}
+
+func (h *MetricsHandler) RegisterRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("GET /metrics", h.handleMetrics)
+}
+
+func (h *MetricsHandler) handleMetrics(w http.ResponseWriter, r *http.Request) {
+	if h.scrapeToken != "" && tokenMatch(extractBearerToken(r), h.scrapeToken) {
+		h.write(w)
+		return
+	}
+	requireAuth(permissions.RoleAdmin, func(w http.ResponseWriter, r *http.Request) {
+		if store.IsOwnerRole(r.Context()) {
+			h.write(w)
+			return
+		}
+		// Tenant admins only see their own tenant's series.
+		tenant := metrics.TenantLabel(store.TenantIDFromContext(r.Context()))
+		w.Header().Set("Content-Type", metricsContentType)
+		if err := h.registry.WriteTenantText(w, tenant); err != nil {
+			slog.Debug("metrics: write failed", "error", err)
+		}
+	})(w, r)
+}
+
+const metricsContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+func (h *MetricsHandler) write(w http.ResponseWriter) {
+	w.Header().Set("Content-Type", metricsContentType)
+	if err := h.registry.WriteText(w); err != nil {
+		slog.Debug("metrics: write failed", "error", err)
+	}
+}
@@ -0,0 +1,93 @@
+package http
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/nextlevelbuilder/goclaw/internal/agent"
+	"github.com/nextlevelbuilder/goclaw/internal/i18n"
+	"github.com/nextlevelbuilder/goclaw/internal/permissions"
+	"github.com/nextlevelbuilder/goclaw/internal/store"
+)
+
+// ModelsHandler handles GET /v1/models (OpenAI-compatible). Each agent the
+// caller can reach is listed as a model named "goclaw:<agent_key>", the form
+// accepted by the "model" field of /v1/chat/completions.
+type ModelsHandler struct {
+	agents     *agent.Router
+	agentStore store.AgentStore // nil in standalone mode
+}
+
+// NewModelsHandler creates a handler for the models endpoint.
+func NewModelsHandler(agents *agent.Router, agentStore store.AgentStore) *ModelsHandler {
+	return &ModelsHandler{agents: agents, agentStore: agentStore}
+}
+
+type modelObject struct {
+	ID      string `json:"id"`
+	Object  string `json:"object"`
+	Created int64  `json:"created"`
+	OwnedBy string `json:"owned_by"`
+}
+
+func (h *ModelsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	locale := extractLocale(r)
+
+	if r.Method != http.MethodGet {
+		http.Error(w, i18n.T(locale, i18n.MsgMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	auth := resolveAuth(r)
+	if !auth.Authenticated {
+		http.Error(w, fmt.Sprintf(`{"error":{"message":"%s","type":"invalid_request_error"}}`, i18n.T(locale, i18n.MsgInvalidAuth)), http.StatusUnauthorized)
+		return
+	}
+	if !permissions.HasMinRole(auth.Role, permissions.RoleViewer) {
+		http.Error(w, fmt.Sprintf(`{"error":{"message":"%s","type":"invalid_request_error"}}`, i18n.T(locale, i18n.MsgPermissionDenied, "/v1/models")), http.StatusForbidden)
+		return
+	}
+	r = r.WithContext(enrichContext(r.Context(), r, auth))
+
+	models, err := h.listModels(r, auth.Role)
+	if err != nil {
+		slog.Error("models.list", "error", err)
+		http.Error(w, fmt.Sprintf(`{"error":{"message":"%s"}}`, i18n.T(locale, i18n.MsgFailedToList, "models")), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"object": "list", "data": models})
+}
+
+func (h *ModelsHandler) listModels(r *http.Request, role permissions.Role) ([]modelObject, error) {
+	models := []modelObject{}
+	if h.agentStore == nil {
+		now := time.Now().Unix()
+		for _, info := range h.agents.ListInfo() {
+			models = append(models, modelObject{ID: "goclaw:" + info.ID, Object: "model", Created: now, OwnedBy: "goclaw"})
+		}
+	} else {
+		ctx := r.Context()
+		userID := store.UserIDFromContext(ctx)
+		var agents []store.AgentData
+		var err error
+		if permissions.HasMinRole(role, permissions.RoleAdmin) || userID == "" {
+			agents, err = h.agentStore.List(ctx, "") // tenant-scoped
+		} else {
+			agents, err = h.agentStore.ListAccessible(ctx, userID)
+		}
+		if err != nil {
+			return nil, err
+		}
+		for _, a := range agents {
+			if a.Status != "" && a.Status != store.AgentStatusActive {
+				continue
+			}
+			models = append(models, modelObject{ID: "goclaw:" + a.AgentKey, Object: "model", Created: a.CreatedAt.Unix(), OwnedBy: "goclaw"})
+		}
+	}
+	sort.Slice(models, func(i, j int) bool { return models[i].ID < models[j].ID })
+	return models, nil
+}
@@ -37,10 +37,10 @@ func NewResponsesHandler(agents *agent.Router, sess store.SessionStore) *Respons
 }
 
 type responsesRequest struct {
-	Model     string        `json:"model"`
-	Messages  []chatMessage `json:"messages"`
-	Stream    bool          `json:"stream"`
-	MaxTokens int           `json:"max_tokens,omitempty"`
+	Model     string             `json:"model"`
+	Messages  []chatInputMessage `json:"messages"`
+	Stream    bool               `json:"stream"`
+	MaxTokens int                `json:"max_tokens,omitempty"`
 }
 
 func (h *ResponsesHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
@@ -90,7 +90,7 @@ func (h *ResponsesHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	var lastMessage string
 	for i := len(req.Messages) - 1; i >= 0; i-- {
 		if req.Messages[i].Role == "user" {
-			lastMessage = req.Messages[i].Content
+			lastMessage = req.Messages[i].Content.Text()
 			break
 		}
 	}
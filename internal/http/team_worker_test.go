@@ -157,6 +157,7 @@ func (m *mockWorkerTeamStore) GetTeamForAgent(context.Context, uuid.UUID) (*stor
 func (m *mockWorkerTeamStore) KnownUserIDs(context.Context, uuid.UUID, int) ([]string, error) { return nil, nil }
 func (m *mockWorkerTeamStore) ListTaskScopes(context.Context, uuid.UUID) ([]store.ScopeEntry, error) { return nil, nil }
 func (m *mockWorkerTeamStore) CreateTask(context.Context, *store.TeamTaskData) error        { return nil }
+func (m *mockWorkerTeamStore) CreateTasks(context.Context, []*store.TeamTaskData) error    { return nil }
 func (m *mockWorkerTeamStore) UpdateTask(context.Context, uuid.UUID, map[string]any) error  { return nil }
 func (m *mockWorkerTeamStore) GetTasksByIDs(context.Context, []uuid.UUID) ([]store.TeamTaskData, error) { return nil, nil }
 func (m *mockWorkerTeamStore) SearchTasks(context.Context, uuid.UUID, string, int, string) ([]store.TeamTaskData, error) { return nil, nil }
@@ -469,6 +469,7 @@ func (h *AgentsHandler) writeAgentSectionsToTar(ctx context.Context, tw *tar.Wri
 				TargetExternalID: idToExternal[rel.TargetEntityID],
 				UserID:           rel.UserID, RelationType: rel.RelationType,
 				Confidence: rel.Confidence, Properties: rel.Properties,
+				ValidFrom: rel.ValidFrom, ValidTo: rel.ValidTo, Provenance: rel.Provenance,
 			})
 		}
 		if len(exportRelations) > 0 {
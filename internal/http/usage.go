@@ -9,6 +9,7 @@ import (
 
 	"github.com/google/uuid"
 
+	"github.com/nextlevelbuilder/goclaw/internal/budget"
 	"github.com/nextlevelbuilder/goclaw/internal/store"
 )
 
@@ -16,16 +17,25 @@ import (
 type UsageHandler struct {
 	snapshots store.SnapshotStore
 	db        *sql.DB
+	budget    *budget.Checker
+	agents    store.AgentStore
 }
 
 func NewUsageHandler(snapshots store.SnapshotStore, db *sql.DB) *UsageHandler {
 	return &UsageHandler{snapshots: snapshots, db: db}
 }
 
+// SetBudget enables GET /v1/usage/budget (monthly burn vs. configured budgets).
+func (h *UsageHandler) SetBudget(checker *budget.Checker, agents store.AgentStore) {
+	h.budget = checker
+	h.agents = agents
+}
+
 func (h *UsageHandler) RegisterRoutes(mux *http.ServeMux) {
 	mux.HandleFunc("GET /v1/usage/timeseries", h.authMiddleware(h.handleTimeSeries))
 	mux.HandleFunc("GET /v1/usage/breakdown", h.authMiddleware(h.handleBreakdown))
 	mux.HandleFunc("GET /v1/usage/summary", h.authMiddleware(h.handleSummary))
+	mux.HandleFunc("GET /v1/usage/budget", h.authMiddleware(h.handleBudget))
 }
 
 func (h *UsageHandler) authMiddleware(next http.HandlerFunc) http.HandlerFunc {
@@ -62,6 +72,34 @@ func (h *UsageHandler) handleTimeSeries(w http.ResponseWriter, r *http.Request)
 	writeJSON(w, http.StatusOK, map[string]any{"points": points})
 }
 
+// handleBudget returns current month burn and remaining budget for the caller's
+// tenant, plus ?agent_id= (key or UUID) and ?user_id= when given.
+func (h *UsageHandler) handleBudget(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var ag *store.AgentData
+	if ref := r.URL.Query().Get("agent_id"); ref != "" && h.agents != nil {
+		var err error
+		if id, perr := uuid.Parse(ref); perr == nil {
+			ag, err = h.agents.GetByID(ctx, id)
+		} else {
+			ag, err = h.agents.GetByKey(ctx, ref)
+		}
+		if err != nil {
+			writeJSON(w, http.StatusNotFound, map[string]string{"error": "agent not found"})
+			return
+		}
+	}
+
+	statuses := h.budget.Statuses(ctx, budget.AgentSubject(store.TenantIDFromContext(ctx), ag, r.URL.Query().Get("user_id")))
+	if statuses == nil {
+		statuses = []budget.Status{}
+	}
+	writeJSON(w, http.StatusOK, map[string]any{
+		"month":   time.Now().UTC().Format("2006-01"),
+		"budgets": statuses,
+	})
+}
+
 func (h *UsageHandler) handleBreakdown(w http.ResponseWriter, r *http.Request) {
 	q := parseSnapshotFilters(r)
 	if q.From.IsZero() || q.To.IsZero() {
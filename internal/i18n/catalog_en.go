@@ -44,6 +44,7 @@ func init() {
 
 		// Exec approval
 		MsgExecApprovalDisabled: "exec approval is not enabled",
+		MsgToolApprovalDisabled: "tool approval is not enabled",
 
 		// Pairing
 		MsgSenderChannelRequired: "senderId and channel are required",
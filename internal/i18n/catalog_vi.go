@@ -44,6 +44,7 @@ func init() {
 
 		// Exec approval
 		MsgExecApprovalDisabled: "phê duyệt thực thi chưa được bật",
+		MsgToolApprovalDisabled: "phê duyệt công cụ chưa được bật",
 
 		// Pairing
 		MsgSenderChannelRequired: "senderId và channel là bắt buộc",
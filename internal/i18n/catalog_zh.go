@@ -44,6 +44,7 @@ func init() {
 
 		// Exec approval
 		MsgExecApprovalDisabled: "执行审批未启用",
+		MsgToolApprovalDisabled: "工具审批未启用",
 
 		// Pairing
 		MsgSenderChannelRequired: "senderId 和 channel 是必填项",
@@ -45,6 +45,7 @@ const (
 
 	// --- Exec approval ---
 	MsgExecApprovalDisabled = "error.exec_approval_disabled" // "exec approval is not enabled"
+	MsgToolApprovalDisabled = "error.tool_approval_disabled" // "tool approval is not enabled"
 
 	// --- Pairing ---
 	MsgSenderChannelRequired = "error.sender_channel_required" // "senderId and channel are required"
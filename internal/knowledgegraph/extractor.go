@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"log/slog"
 	"strings"
+	"time"
 
 	"github.com/nextlevelbuilder/goclaw/internal/providers"
 	"github.com/nextlevelbuilder/goclaw/internal/store"
@@ -17,6 +18,14 @@ type ExtractionResult struct {
 	Relations []store.Relation `json:"relations"`
 }
 
+// extractedRelation is a relation as emitted by the LLM: validity is given as
+// optional dates rather than unix milliseconds.
+type extractedRelation struct {
+	store.Relation
+	Since string `json:"since"`
+	Until string `json:"until"`
+}
+
 // Extractor extracts entities and relations from text using an LLM.
 type Extractor struct {
 	provider      providers.Provider
@@ -62,7 +71,7 @@ func (e *Extractor) Extract(ctx context.Context, text string) (*ExtractionResult
 func (e *Extractor) extractChunk(ctx context.Context, text string) (*ExtractionResult, error) {
 	req := providers.ChatRequest{
 		Messages: []providers.Message{
-			{Role: "system", Content: extractionSystemPrompt},
+			{Role: "system", Content: extractionSystemPrompt + "\n\nToday's date: " + time.Now().UTC().Format("2006-01-02")},
 			{Role: "user", Content: text},
 		},
 		Model: e.model,
@@ -95,7 +104,10 @@ func (e *Extractor) extractChunk(ctx context.Context, text string) (*ExtractionR
 	}
 
 	// Parse JSON response
-	var result ExtractionResult
+	var result struct {
+		Entities  []store.Entity      `json:"entities"`
+		Relations []extractedRelation `json:"relations"`
+	}
 	content := strings.TrimSpace(resp.Content)
 	content = stripCodeBlock(content)
 
@@ -132,11 +144,15 @@ func (e *Extractor) extractChunk(ctx context.Context, text string) (*ExtractionR
 			filtered.Entities = append(filtered.Entities, ent)
 		}
 	}
-	for _, rel := range result.Relations {
-		if rel.Confidence >= e.minConfidence {
+	for _, ext := range result.Relations {
+		if ext.Confidence >= e.minConfidence {
+			rel := ext.Relation
 			rel.SourceEntityID = strings.ToLower(strings.TrimSpace(rel.SourceEntityID))
 			rel.TargetEntityID = strings.ToLower(strings.TrimSpace(rel.TargetEntityID))
 			rel.RelationType = strings.ToLower(strings.TrimSpace(rel.RelationType))
+			// Unparseable dates are dropped: the fact is still recorded, as of now.
+			rel.ValidFrom, _ = store.ParseKGTime(ext.Since)
+			rel.ValidTo, _ = store.ParseKGTime(ext.Until)
 			filtered.Relations = append(filtered.Relations, rel)
 		}
 	}
@@ -18,7 +18,9 @@ Output valid JSON with this schema:
       "source_entity_id": "external_id of source",
       "relation_type": "RELATION_TYPE",
       "target_entity_id": "external_id of target",
-      "confidence": 0.0-1.0
+      "confidence": 0.0-1.0,
+      "since": "YYYY-MM-DD (optional)",
+      "until": "YYYY-MM-DD (optional)"
     }
   ]
 }
@@ -69,6 +71,13 @@ Choosing between similar types:
 - Do NOT use related_to as a default — if you cannot determine a specific relation, omit it
 - Output ONLY the JSON object, no markdown, no code blocks
 
+## Time
+- Facts can change: people move, change teams, meetings get rescheduled. Always extract the fact as it is stated
+- "since": when the fact started to hold, ONLY if the text gives a date ("moved to Berlin in March 2024" → "2024-03"). Use YYYY-MM-DD, YYYY-MM or YYYY
+- "until": when the fact stopped holding, ONLY if the text says it ended ("left Acme last June"). Omit for facts that still hold
+- Omit both when the text gives no timing — the current time is assumed
+- Resolve relative dates ("last June", "two weeks ago") against today's date given at the end of this prompt
+
 ## Example
 
 Input: "Talked to Minh about the GoClaw migration. He'll handle the database schema changes by Friday. The team uses PostgreSQL with pgvector. I wrote the migration guide yesterday."
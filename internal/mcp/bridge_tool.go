@@ -9,6 +9,7 @@ import (
 
 	mcpclient "github.com/mark3labs/mcp-go/client"
 	mcpgo "github.com/mark3labs/mcp-go/mcp"
+	"github.com/nextlevelbuilder/goclaw/internal/store"
 	"github.com/nextlevelbuilder/goclaw/internal/tools"
 )
 
@@ -24,6 +25,10 @@ type BridgeTool struct {
 	client         *mcpclient.Client
 	timeoutSec     int
 	connected      *atomic.Bool
+	calls          *callTracker   // connection's in-flight calls (sampling routing)
+	sampling       *samplingGrant // non-nil when the agent's grant allows sampling
+	oauth          *OAuthFlows    // non-nil for OAuth servers: prompt for consent when the token is missing
+	oauthServer    *store.MCPServerData
 }
 
 // NewBridgeTool creates a BridgeTool from an MCP Tool definition.
@@ -93,6 +98,12 @@ func (t *BridgeTool) OriginalName() string { return t.toolName }
 // IsConnected returns whether the underlying MCP server connection is healthy.
 func (t *BridgeTool) IsConnected() bool { return t.connected.Load() }
 
+// SetOAuth marks the tool as belonging to an OAuth server. Calls rejected for
+// a missing or revoked authorization then send the user a consent link.
+func (t *BridgeTool) SetOAuth(flows *OAuthFlows, srv *store.MCPServerData) {
+	t.oauth, t.oauthServer = flows, srv
+}
+
 func (t *BridgeTool) Execute(ctx context.Context, args map[string]any) *tools.Result {
 	if !t.connected.Load() {
 		return tools.ErrorResult(fmt.Sprintf("MCP server %q is disconnected", t.serverName))
@@ -101,6 +112,12 @@ func (t *BridgeTool) Execute(ctx context.Context, args map[string]any) *tools.Re
 	callCtx, cancel := context.WithTimeout(ctx, time.Duration(t.timeoutSec)*time.Second)
 	defer cancel()
 
+	// Sampling requests the server makes during this call are answered on
+	// behalf of this agent, under its grant.
+	if t.sampling != nil && t.calls != nil {
+		defer t.calls.begin(callCtx, t.sampling)()
+	}
+
 	// Strip empty-value optional args. LLMs often send "" for optional fields
 	// instead of omitting them, causing MCP servers to reject invalid values
 	// (e.g. empty string for UUID fields).
@@ -115,6 +132,9 @@ func (t *BridgeTool) Execute(ctx context.Context, args map[string]any) *tools.Re
 		if callCtx.Err() == context.DeadlineExceeded {
 			return tools.ErrorResult(fmt.Sprintf("MCP tool %q timeout after %ds", t.registeredName, t.timeoutSec))
 		}
+		if t.oauth != nil && mcpclient.IsOAuthAuthorizationRequiredError(err) {
+			return t.oauth.authorizationResult(ctx, t.oauthServer)
+		}
 		return tools.ErrorResult(fmt.Sprintf("MCP tool %q error: %v", t.registeredName, err))
 	}
 
@@ -12,6 +12,7 @@ import (
 	"time"
 
 	mcpclient "github.com/mark3labs/mcp-go/client"
+	mcpgo "github.com/mark3labs/mcp-go/mcp"
 	"github.com/nextlevelbuilder/goclaw/internal/config"
 	"github.com/nextlevelbuilder/goclaw/internal/store"
 	"github.com/nextlevelbuilder/goclaw/internal/tools"
@@ -51,6 +52,11 @@ type serverState struct {
 	timeoutSec int
 	cancel     context.CancelFunc
 
+	caps     mcpgo.ServerCapabilities // advertised during initialize
+	calls    *callTracker             // in-flight tool calls (sampling routing)
+	watch    *resourceWatch           // resource change notifications
+	sampling bool                     // client advertised the sampling capability
+
 	mu              sync.Mutex
 	reconnAttempts  int
 	healthFailures  int // consecutive ping failures (resets on success)
@@ -93,6 +99,11 @@ type Manager struct {
 	// LoadForAgent("") for later per-request tool resolution. These servers are NOT
 	// connected at startup — connections are created per-user via pool.AcquireUser().
 	userCredServers []store.MCPAccessInfo
+
+	// Capabilities beyond tools (resources, prompts, sampling) granted per server.
+	// Only DB-backed servers carry grants; config-file servers expose tools only.
+	grants  map[string]store.MCPGrantCapabilities
+	sampler Sampler // answers sampling requests; nil disables sampling
 }
 
 // ManagerOption configures the Manager.
@@ -120,6 +131,14 @@ func WithPool(p *Pool) ManagerOption {
 	}
 }
 
+// WithSampler sets the handler for server-initiated sampling requests.
+// Without it, sampling grants are ignored.
+func WithSampler(s Sampler) ManagerOption {
+	return func(m *Manager) {
+		m.sampler = s
+	}
+}
+
 // NewManager creates a new MCP Manager.
 func NewManager(registry *tools.Registry, opts ...ManagerOption) *Manager {
 	m := &Manager{
@@ -146,7 +165,7 @@ func (m *Manager) Start(ctx context.Context) error {
 			continue
 		}
 
-		if err := m.connectServer(ctx, name, cfg.Transport, cfg.Command, cfg.Args, cfg.Env, cfg.URL, resolveEnvVars(cfg.Headers), cfg.ToolPrefix, cfg.TimeoutSec); err != nil {
+		if err := m.connectServer(ctx, name, cfg.Transport, cfg.Command, cfg.Args, cfg.Env, cfg.URL, resolveEnvVars(cfg.Headers), cfg.ToolPrefix, cfg.TimeoutSec, false); err != nil {
 			slog.Warn("mcp.server.connect_failed", "server", name, "error", err)
 			errs = append(errs, fmt.Sprintf("%s: %v", name, err))
 		}
@@ -175,6 +194,11 @@ func (m *Manager) resolveServerCredentials(ctx context.Context, info store.MCPAc
 		return nil
 	}
 
+	// OAuth servers only connect per user, with the user's own token.
+	if ParseOAuthSettings(srv.Settings).Enabled {
+		return nil
+	}
+
 	// Skip server if it requires per-user credentials and user has none
 	if requireUserCreds(srv.Settings) {
 		if userID == "" {
@@ -244,22 +268,34 @@ func (m *Manager) resolveServerCredentials(ctx context.Context, info store.MCPAc
 }
 
 // connectAndFilter establishes the MCP connection (pool or per-agent mode)
-// and applies tool allow/deny filtering from server grants.
-func (m *Manager) connectAndFilter(ctx context.Context, rs *resolvedServer) error {
+// and applies tool allow/deny filtering and capabilities from server grants.
+func (m *Manager) connectAndFilter(ctx context.Context, agentID uuid.UUID, rs *resolvedServer) error {
 	srv := rs.info.Server
+	caps := rs.info.Capabilities
+
+	sampling := caps.SamplingEnabled() && m.sampler != nil
+	if sampling && !samplingSupported(srv.Transport) {
+		slog.Warn("mcp.sampling.unsupported_transport", "server", srv.Name, "transport", srv.Transport)
+		sampling = false
+	}
 
 	if m.pool != nil && !rs.hasUserCreds {
-		// Pool mode: acquire shared connection, create per-agent BridgeTools
+		// Pool mode: acquire shared connection, create per-agent BridgeTools.
+		// Sampling-enabled connections are pooled per agent.
 		tid := store.TenantIDFromContext(ctx)
+		var samplingAgent uuid.UUID
+		if sampling {
+			samplingAgent = agentID
+		}
 		if err := m.connectViaPool(ctx, tid, srv.Name, srv.Transport, srv.Command,
-			rs.args, rs.env, srv.URL, rs.headers, srv.ToolPrefix, srv.TimeoutSec); err != nil {
+			rs.args, rs.env, srv.URL, rs.headers, srv.ToolPrefix, srv.TimeoutSec, samplingAgent); err != nil {
 			return err
 		}
 	} else {
 		// Per-agent mode: create per-agent connection
 		if err := m.connectServer(ctx, srv.Name, srv.Transport, srv.Command,
 			rs.args, rs.env, srv.URL, rs.headers,
-			srv.ToolPrefix, srv.TimeoutSec); err != nil {
+			srv.ToolPrefix, srv.TimeoutSec, sampling); err != nil {
 			return err
 		}
 	}
@@ -269,9 +305,65 @@ func (m *Manager) connectAndFilter(ctx context.Context, rs *resolvedServer) erro
 		m.filterTools(srv.Name, rs.info.ToolAllow, rs.info.ToolDeny)
 	}
 
+	m.applyCapabilities(srv.Name, srv.ToolPrefix, srv.TimeoutSec, caps, sampling)
 	return nil
 }
 
+// applyCapabilities records a server's grant and exposes the granted
+// capabilities the server supports: the resources tool, and sampling for
+// calls made through its bridged tools. Prompts are served on demand.
+func (m *Manager) applyCapabilities(serverName, toolPrefix string, timeoutSec int, caps store.MCPGrantCapabilities, sampling bool) {
+	m.mu.Lock()
+	ss, ok := m.servers[serverName]
+	if !ok {
+		m.mu.Unlock()
+		return
+	}
+	if m.grants == nil {
+		m.grants = make(map[string]store.MCPGrantCapabilities)
+	}
+	m.grants[serverName] = caps
+	_, isPool := m.poolServers[serverName]
+	toolNames := ss.toolNames
+	if isPool {
+		toolNames = m.poolToolNames[serverName]
+	}
+	m.mu.Unlock()
+
+	if sampling && ss.sampling {
+		grant := newSamplingGrant(m.sampler, *caps.Sampling)
+		for _, name := range toolNames {
+			if t, ok := m.registry.Get(name); ok {
+				if bt, ok := t.(*BridgeTool); ok {
+					bt.sampling = grant
+				}
+			}
+		}
+	}
+
+	if !caps.Resources || ss.caps.Resources == nil {
+		return
+	}
+	rt := NewResourceTool(serverName, toolPrefix, ss, timeoutSec)
+	if _, exists := m.registry.Get(rt.Name()); exists {
+		slog.Warn("mcp.tool.name_collision", "server", serverName, "tool", rt.Name(), "action", "skipped")
+		return
+	}
+	m.registry.Register(rt)
+
+	m.mu.Lock()
+	toolNames = append(toolNames, rt.Name())
+	if isPool {
+		m.poolToolNames[serverName] = toolNames
+	} else {
+		ss.toolNames = toolNames
+	}
+	m.mu.Unlock()
+
+	tools.RegisterToolGroup("mcp:"+serverName, toolNames)
+	m.updateMCPGroup()
+}
+
 // LoadForAgent connects MCP servers accessible by a specific agent+user.
 // Previously registered MCP tools for this manager are cleared and reloaded.
 func (m *Manager) LoadForAgent(ctx context.Context, agentID uuid.UUID, userID string) error {
@@ -301,7 +393,7 @@ func (m *Manager) LoadForAgent(ctx context.Context, agentID uuid.UUID, userID st
 		if rs == nil {
 			continue
 		}
-		if err := m.connectAndFilter(ctx, rs); err != nil {
+		if err := m.connectAndFilter(ctx, agentID, rs); err != nil {
 			slog.Warn("mcp.server.connect_failed", "server", info.Server.Name, "error", err)
 		}
 	}
@@ -352,6 +444,9 @@ func (m *Manager) maybeEnterSearchMode() {
 				if bridge, ok := bt.(*BridgeTool); ok {
 					m.deferredTools[name] = bridge
 					m.registry.Unregister(name)
+				} else {
+					// Non-bridge tools (resources) can't be deferred; keep them inline.
+					kept = append(kept, name)
 				}
 			}
 		}
@@ -508,6 +603,7 @@ func (m *Manager) Stop() {
 	m.servers = make(map[string]*serverState)
 	m.poolServers = nil
 	m.poolToolNames = nil
+	m.grants = nil
 }
 
 // ServerStatus returns the status of all connected MCP servers.
@@ -541,14 +637,16 @@ func resolveEnvVars(m map[string]string) map[string]string {
 	return out
 }
 
-// requireUserCreds checks if an MCP server's settings mandate per-user credentials.
+// requireUserCreds checks if an MCP server's settings mandate per-user
+// credentials, either configured keys or an OAuth authorization.
 func requireUserCreds(settings json.RawMessage) bool {
 	if len(settings) == 0 {
 		return false
 	}
 	var s struct {
-		RequireUserCredentials bool `json:"require_user_credentials"`
+		RequireUserCredentials bool          `json:"require_user_credentials"`
+		OAuth                  OAuthSettings `json:"oauth"`
 	}
 	_ = json.Unmarshal(settings, &s)
-	return s.RequireUserCredentials
+	return s.RequireUserCredentials || s.OAuth.Enabled
 }
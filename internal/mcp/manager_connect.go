@@ -18,18 +18,29 @@ import (
 // discovers tools. Returns a connected serverState with discovered tool
 // definitions. The caller is responsible for registering tools and starting
 // the health loop. This function is shared by both Manager and Pool.
-func connectAndDiscover(ctx context.Context, name, transportType, command string, args []string, env map[string]string, url string, headers map[string]string, timeoutSec int) (*serverState, []mcpgo.Tool, error) {
-	client, err := createClient(transportType, command, args, env, url, headers)
+//
+// When sampling is true the client advertises the sampling capability and
+// routes server-initiated sampling requests to the tool call in flight.
+// A non-nil oauth config authorizes remote transports with a user's token.
+func connectAndDiscover(ctx context.Context, name, transportType, command string, args []string, env map[string]string, url string, headers map[string]string, timeoutSec int, sampling bool, oauth *transport.OAuthConfig) (*serverState, []mcpgo.Tool, error) {
+	calls := &callTracker{server: name}
+	var opts []mcpclient.ClientOption
+	if sampling {
+		opts = append(opts, mcpclient.WithSamplingHandler(calls))
+	}
+	client, err := createClient(transportType, command, args, env, url, headers, oauth, opts...)
 	if err != nil {
 		return nil, nil, fmt.Errorf("create client: %w", err)
 	}
 
-	if transportType != "stdio" {
-		if err := client.Start(ctx); err != nil {
-			_ = client.Close()
-			return nil, nil, fmt.Errorf("start transport: %w", err)
-		}
+	// Start installs the notification and server-request handlers; for stdio
+	// the transport is already running and only the handlers are set.
+	if err := client.Start(ctx); err != nil {
+		_ = client.Close()
+		return nil, nil, fmt.Errorf("start transport: %w", err)
 	}
+	watch := newResourceWatch()
+	client.OnNotification(watch.handle)
 
 	initReq := mcpgo.InitializeRequest{}
 	initReq.Params.ProtocolVersion = mcpgo.LATEST_PROTOCOL_VERSION
@@ -38,7 +49,8 @@ func connectAndDiscover(ctx context.Context, name, transportType, command string
 		Version: "1.0.0",
 	}
 
-	if _, err := client.Initialize(ctx, initReq); err != nil {
+	initResult, err := client.Initialize(ctx, initReq)
+	if err != nil {
 		_ = client.Close()
 		return nil, nil, fmt.Errorf("initialize: %w", err)
 	}
@@ -58,6 +70,10 @@ func connectAndDiscover(ctx context.Context, name, transportType, command string
 		transport:  transportType,
 		client:     client,
 		timeoutSec: timeoutSec,
+		caps:       initResult.Capabilities,
+		calls:      calls,
+		watch:      watch,
+		sampling:   sampling,
 	}
 	ss.connected.Store(true)
 
@@ -65,8 +81,8 @@ func connectAndDiscover(ctx context.Context, name, transportType, command string
 }
 
 // connectServer creates a client, initializes the connection, discovers tools, and registers them.
-func (m *Manager) connectServer(ctx context.Context, name, transportType, command string, args []string, env map[string]string, url string, headers map[string]string, toolPrefix string, timeoutSec int) error {
-	ss, mcpTools, err := connectAndDiscover(ctx, name, transportType, command, args, env, url, headers, timeoutSec)
+func (m *Manager) connectServer(ctx context.Context, name, transportType, command string, args []string, env map[string]string, url string, headers map[string]string, toolPrefix string, timeoutSec int, sampling bool) error {
+	ss, mcpTools, err := connectAndDiscover(ctx, name, transportType, command, args, env, url, headers, timeoutSec, sampling, nil)
 	if err != nil {
 		return err
 	}
@@ -106,6 +122,7 @@ func (m *Manager) registerBridgeTools(ss *serverState, mcpTools []mcpgo.Tool, se
 	var registeredNames []string
 	for _, mcpTool := range mcpTools {
 		bt := NewBridgeTool(serverName, mcpTool, ss.client, toolPrefix, timeoutSec, &ss.connected)
+		bt.calls = ss.calls
 
 		if _, exists := m.registry.Get(bt.Name()); exists {
 			slog.Warn("mcp.tool.name_collision",
@@ -124,8 +141,9 @@ func (m *Manager) registerBridgeTools(ss *serverState, mcpTools []mcpgo.Tool, se
 
 // connectViaPool acquires a shared connection from the pool and creates
 // per-agent BridgeTools pointing to the shared client/connected pointers.
-func (m *Manager) connectViaPool(ctx context.Context, tenantID uuid.UUID, name, transportType, command string, args []string, env map[string]string, url string, headers map[string]string, toolPrefix string, timeoutSec int) error {
-	entry, err := m.pool.Acquire(ctx, tenantID, name, transportType, command, args, env, url, headers, timeoutSec)
+// A non-nil samplingAgent selects that agent's sampling-enabled connection.
+func (m *Manager) connectViaPool(ctx context.Context, tenantID uuid.UUID, name, transportType, command string, args []string, env map[string]string, url string, headers map[string]string, toolPrefix string, timeoutSec int, samplingAgent uuid.UUID) error {
+	entry, err := m.pool.Acquire(ctx, tenantID, name, transportType, command, args, env, url, headers, timeoutSec, samplingAgent)
 	if err != nil {
 		return err
 	}
@@ -149,7 +167,7 @@ func (m *Manager) connectViaPool(ctx context.Context, tenantID uuid.UUID, name,
 	if m.poolKeys == nil {
 		m.poolKeys = make(map[string]string)
 	}
-	m.poolKeys[name] = poolKey(tenantID, name)
+	m.poolKeys[name] = samplingPoolKey(tenantID, name, samplingAgent)
 	m.mu.Unlock()
 
 	if len(registeredNames) > 0 {
@@ -172,6 +190,7 @@ func (m *Manager) registerPoolBridgeTools(entry *poolEntry, serverName, toolPref
 	var registeredNames []string
 	for _, mcpTool := range entry.tools {
 		bt := NewBridgeTool(serverName, mcpTool, entry.state.client, toolPrefix, timeoutSec, &entry.state.connected)
+		bt.calls = entry.state.calls
 
 		if _, exists := m.registry.Get(bt.Name()); exists {
 			slog.Warn("mcp.tool.name_collision",
@@ -190,25 +209,50 @@ func (m *Manager) registerPoolBridgeTools(entry *poolEntry, serverName, toolPref
 }
 
 // createClient creates the appropriate MCP client based on transport type.
-func createClient(transportType, command string, args []string, env map[string]string, url string, headers map[string]string) (*mcpclient.Client, error) {
+// Client options (e.g. a sampling handler) are applied to every transport;
+// only stdio and streamable-http can carry server-initiated requests.
+// oauth, when set, authorizes sse and streamable-http requests.
+func createClient(transportType, command string, args []string, env map[string]string, url string, headers map[string]string, oauth *transport.OAuthConfig, clientOpts ...mcpclient.ClientOption) (*mcpclient.Client, error) {
 	switch transportType {
 	case "stdio":
 		envSlice := mapToEnvSlice(env)
-		return mcpclient.NewStdioMCPClient(command, envSlice, args...)
+		trans := transport.NewStdio(command, envSlice, args...)
+		// The subprocess outlives the connect context, so start it detached.
+		if err := trans.Start(context.Background()); err != nil {
+			return nil, fmt.Errorf("failed to start stdio transport: %w", err)
+		}
+		return mcpclient.NewClient(trans, clientOpts...), nil
 
 	case "sse":
 		var opts []transport.ClientOption
 		if len(headers) > 0 {
 			opts = append(opts, mcpclient.WithHeaders(headers))
 		}
-		return mcpclient.NewSSEMCPClient(url, opts...)
+		if oauth != nil {
+			opts = append(opts, transport.WithOAuth(*oauth))
+		}
+		trans, err := transport.NewSSE(url, opts...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create SSE transport: %w", err)
+		}
+		return mcpclient.NewClient(trans, clientOpts...), nil
 
 	case "streamable-http":
 		var opts []transport.StreamableHTTPCOption
 		if len(headers) > 0 {
 			opts = append(opts, transport.WithHTTPHeaders(headers))
 		}
-		return mcpclient.NewStreamableHttpClient(url, opts...)
+		if oauth != nil {
+			opts = append(opts, transport.WithHTTPOAuth(*oauth))
+		}
+		trans, err := transport.NewStreamableHTTP(url, opts...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create streamable-http transport: %w", err)
+		}
+		if trans.GetSessionId() != "" {
+			clientOpts = append(clientOpts, mcpclient.WithSession())
+		}
+		return mcpclient.NewClient(trans, clientOpts...), nil
 
 	default:
 		return nil, fmt.Errorf("unsupported transport: %q", transportType)
@@ -105,6 +105,7 @@ func (m *Manager) unregisterAllTools() {
 	m.servers = make(map[string]*serverState)
 	m.poolServers = nil
 	m.poolToolNames = nil
+	m.grants = nil
 	tools.UnregisterToolGroup("mcp")
 }
 
@@ -120,7 +121,7 @@ func DiscoverTools(ctx context.Context, transportType, command string, args []st
 	ctx, cancel := context.WithTimeout(ctx, 15*time.Second)
 	defer cancel()
 
-	client, err := createClient(transportType, command, args, env, url, headers)
+	client, err := createClient(transportType, command, args, env, url, headers, nil)
 	if err != nil {
 		return nil, fmt.Errorf("create client: %w", err)
 	}
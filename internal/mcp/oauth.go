@@ -0,0 +1,499 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mark3labs/mcp-go/client/transport"
+
+	"github.com/nextlevelbuilder/goclaw/internal/bus"
+	"github.com/nextlevelbuilder/goclaw/internal/store"
+	"github.com/nextlevelbuilder/goclaw/internal/tools"
+)
+
+const (
+	oauthFlowTTL     = 10 * time.Minute // authorization links expire after this
+	oauthRefreshSkew = time.Minute      // refresh this long before expiry
+	oauthClientName  = "GoClaw"
+	oauthHTTPTimeout = 30 * time.Second
+)
+
+// ErrOAuthRequired means the user has not authorized the server (or the
+// authorization was revoked) and must go through the consent flow.
+var ErrOAuthRequired = errors.New("authorization required")
+
+// OAuthSettings is the "oauth" block of an MCP server's settings. Servers
+// with OAuth enabled connect per user with the user's own token.
+type OAuthSettings struct {
+	Enabled     bool     `json:"enabled"`
+	Scopes      []string `json:"scopes,omitempty"`
+	ClientID    string   `json:"client_id,omitempty"`    // pre-registered client; skips dynamic registration (secret in api_key)
+	MetadataURL string   `json:"metadata_url,omitempty"` // authorization server metadata; discovered when empty
+}
+
+// ParseOAuthSettings reads the "oauth" block from server settings JSON.
+func ParseOAuthSettings(settings json.RawMessage) OAuthSettings {
+	var s struct {
+		OAuth OAuthSettings `json:"oauth"`
+	}
+	if len(settings) > 0 {
+		_ = json.Unmarshal(settings, &s)
+	}
+	return s.OAuth
+}
+
+// OAuthFlows runs OAuth 2.1 authorization for remote MCP servers: metadata
+// discovery, dynamic client registration, per-user PKCE authorization and
+// token exchange. Tokens are stored encrypted per user and refreshed by the
+// connection's token store.
+//
+// Pending authorizations are stored, so the callback may reach any gateway
+// replica; completed ones are broadcast so every replica reloads the user's
+// tools.
+type OAuthFlows struct {
+	store       store.MCPServerStore
+	redirectURI string
+	msgBus      *bus.MessageBus
+	httpClient  *http.Client
+
+	mu         sync.Mutex
+	authorized map[string]time.Time // tenant/server/user → last completed authorization
+}
+
+// NewOAuthFlows creates the OAuth flow manager. redirectURI is the gateway's
+// public callback URL (/v1/mcp/oauth/callback); msgBus is used to tell users
+// in their chat when authorization completes and may be nil.
+func NewOAuthFlows(s store.MCPServerStore, redirectURI string, msgBus *bus.MessageBus) *OAuthFlows {
+	f := &OAuthFlows{
+		store:       s,
+		redirectURI: redirectURI,
+		msgBus:      msgBus,
+		httpClient:  &http.Client{Timeout: oauthHTTPTimeout},
+		authorized:  make(map[string]time.Time),
+	}
+	if msgBus != nil {
+		msgBus.Subscribe("mcp.oauth", func(evt bus.Event) {
+			if evt.Name != bus.EventMCPOAuthAuthorized {
+				return
+			}
+			if p, ok := evt.Payload.(bus.MCPOAuthAuthorizedPayload); ok {
+				f.markAuthorized(evt.TenantID, p.ServerID, p.UserID, p.At)
+			}
+		})
+	}
+	return f
+}
+
+// RedirectURI returns the callback URL registered with authorization servers.
+func (f *OAuthFlows) RedirectURI() string { return f.redirectURI }
+
+func oauthUserKey(tenantID, serverID uuid.UUID, userID string) string {
+	return tenantID.String() + "/" + serverID.String() + "/" + userID
+}
+
+// oauthBaseURL returns scheme://host of a server URL, where mcp-go looks for
+// protected resource and authorization server metadata.
+func oauthBaseURL(serverURL string) string {
+	u, err := url.Parse(serverURL)
+	if err != nil || u.Host == "" {
+		return serverURL
+	}
+	return u.Scheme + "://" + u.Host
+}
+
+func (f *OAuthFlows) baseConfig(settings OAuthSettings) transport.OAuthConfig {
+	return transport.OAuthConfig{
+		RedirectURI:           f.redirectURI,
+		Scopes:                settings.Scopes,
+		AuthServerMetadataURL: settings.MetadataURL,
+		PKCEEnabled:           true,
+		HTTPClient:            f.httpClient,
+	}
+}
+
+// client returns the OAuth client for a server: the pre-registered one from
+// settings, the stored registration, or (when register is set) a new dynamic
+// registration. Returns nil without error when none exists and register is off.
+func (f *OAuthFlows) client(ctx context.Context, srv *store.MCPServerData, settings OAuthSettings, register bool) (*store.MCPOAuthClient, error) {
+	if settings.ClientID != "" {
+		return &store.MCPOAuthClient{ClientID: settings.ClientID, ClientSecret: srv.APIKey, RedirectURI: f.redirectURI}, nil
+	}
+	c, err := f.store.GetOAuthClient(ctx, srv.ID)
+	if err != nil {
+		return nil, fmt.Errorf("load oauth client: %w", err)
+	}
+	if c != nil && c.RedirectURI == f.redirectURI {
+		return c, nil
+	}
+	if !register {
+		return nil, nil
+	}
+
+	h := transport.NewOAuthHandler(f.baseConfig(settings))
+	h.SetBaseURL(oauthBaseURL(srv.URL))
+	if err := h.RegisterClient(ctx, oauthClientName); err != nil {
+		return nil, fmt.Errorf("register oauth client with %s: %w", srv.Name, err)
+	}
+	c = &store.MCPOAuthClient{ClientID: h.GetClientID(), ClientSecret: h.GetClientSecret(), RedirectURI: f.redirectURI}
+	if err := f.store.SetOAuthClient(ctx, srv.ID, *c); err != nil {
+		return nil, fmt.Errorf("save oauth client: %w", err)
+	}
+	slog.Info("mcp.oauth.client_registered", "server", srv.Name, "client_id", c.ClientID)
+	return c, nil
+}
+
+// TransportConfig returns the OAuth configuration for connecting a user to a
+// server. The token store refreshes the access token shortly before it
+// expires. Returns ErrOAuthRequired when the user has not authorized.
+func (f *OAuthFlows) TransportConfig(ctx context.Context, srv *store.MCPServerData, userID string) (*transport.OAuthConfig, error) {
+	settings := ParseOAuthSettings(srv.Settings)
+	c, err := f.client(ctx, srv, settings, false)
+	if err != nil {
+		return nil, err
+	}
+	if c == nil {
+		return nil, ErrOAuthRequired
+	}
+	tok, err := f.store.GetOAuthToken(ctx, srv.ID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("load oauth token: %w", err)
+	}
+	if tok == nil {
+		return nil, ErrOAuthRequired
+	}
+
+	ts := &oauthTokenStore{store: f.store, tenantID: store.TenantIDFromContext(ctx), serverID: srv.ID, userID: userID}
+	cfg := f.baseConfig(settings)
+	cfg.ClientID = c.ClientID
+	cfg.ClientSecret = c.ClientSecret
+	cfg.TokenStore = ts
+
+	// The refresher has its own handler so refreshes happen under the token
+	// store's lock, once, rather than in every concurrent request.
+	refresher := transport.NewOAuthHandler(cfg)
+	refresher.SetBaseURL(oauthBaseURL(srv.URL))
+	ts.refresh = refresher.RefreshToken
+	return &cfg, nil
+}
+
+// Start begins an authorization for userID and returns the URL the user must
+// open. The chat in ctx (if any) is told when the authorization completes.
+func (f *OAuthFlows) Start(ctx context.Context, srv *store.MCPServerData, userID string) (string, error) {
+	if srv.Transport != "sse" && srv.Transport != "streamable-http" {
+		return "", fmt.Errorf("OAuth is only supported for remote (sse, streamable-http) servers")
+	}
+	settings := ParseOAuthSettings(srv.Settings)
+	c, err := f.client(ctx, srv, settings, true)
+	if err != nil {
+		return "", err
+	}
+
+	tenantID := store.TenantIDFromContext(ctx)
+	h := f.authHandler(srv, settings, c, tenantID, userID)
+
+	verifier, err := transport.GenerateCodeVerifier()
+	if err != nil {
+		return "", fmt.Errorf("generate code verifier: %w", err)
+	}
+	state, err := transport.GenerateState()
+	if err != nil {
+		return "", fmt.Errorf("generate state: %w", err)
+	}
+	authURL, err := h.GetAuthorizationURL(ctx, state, transport.GenerateCodeChallenge(verifier))
+	if err != nil {
+		return "", fmt.Errorf("discover authorization endpoint for %s: %w", srv.Name, err)
+	}
+	authURL = withResourceIndicator(authURL, srv.URL)
+
+	p := store.MCPOAuthPending{
+		State:     state,
+		TenantID:  tenantID,
+		ServerID:  srv.ID,
+		UserID:    userID,
+		Verifier:  verifier,
+		ExpiresAt: time.Now().Add(oauthFlowTTL),
+	}
+	if tools.ToolPeerKindFromCtx(ctx) != "group" {
+		p.Channel, p.ChatID = tools.ToolChannelFromCtx(ctx), tools.ToolChatIDFromCtx(ctx)
+	}
+	if err := f.store.CreateOAuthPending(ctx, p); err != nil {
+		return "", fmt.Errorf("save authorization request: %w", err)
+	}
+
+	slog.Info("mcp.oauth.started", "server", srv.Name, "user", userID)
+	return authURL, nil
+}
+
+// authHandler returns the OAuth handler that authorizes userID with the
+// server and saves the resulting token.
+func (f *OAuthFlows) authHandler(srv *store.MCPServerData, settings OAuthSettings, c *store.MCPOAuthClient, tenantID uuid.UUID, userID string) *transport.OAuthHandler {
+	cfg := f.baseConfig(settings)
+	cfg.ClientID = c.ClientID
+	cfg.ClientSecret = c.ClientSecret
+	cfg.TokenStore = &oauthTokenStore{store: f.store, tenantID: tenantID, serverID: srv.ID, userID: userID}
+	h := transport.NewOAuthHandler(cfg)
+	h.SetBaseURL(oauthBaseURL(srv.URL))
+	return h
+}
+
+// withResourceIndicator adds the RFC 8707 resource parameter naming the MCP
+// server, so the issued token is bound to it.
+func withResourceIndicator(authURL, resource string) string {
+	u, err := url.Parse(authURL)
+	if err != nil {
+		return authURL
+	}
+	q := u.Query()
+	if q.Get("resource") == "" {
+		q.Set("resource", resource)
+		u.RawQuery = q.Encode()
+	}
+	return u.String()
+}
+
+// Complete exchanges the authorization code from the callback for tokens and
+// stores them for the user who started the flow. An empty code (the user
+// declined) just ends the flow. Returns the server name.
+func (f *OAuthFlows) Complete(ctx context.Context, state, code string) (string, error) {
+	p, err := f.store.TakeOAuthPending(ctx, state)
+	if err != nil {
+		return "", fmt.Errorf("load authorization request: %w", err)
+	}
+	if p == nil {
+		return "", fmt.Errorf("unknown or expired authorization request; ask the agent for a new link")
+	}
+
+	ctx = store.WithTenantID(ctx, p.TenantID)
+	srv, err := f.store.GetServer(ctx, p.ServerID)
+	if err != nil || srv == nil {
+		return "", fmt.Errorf("MCP server of this authorization request no longer exists")
+	}
+	if code == "" {
+		return srv.Name, fmt.Errorf("no authorization code received")
+	}
+	settings := ParseOAuthSettings(srv.Settings)
+	c, err := f.client(ctx, srv, settings, false)
+	if err != nil {
+		return srv.Name, err
+	}
+	if c == nil {
+		return srv.Name, fmt.Errorf("OAuth client for %s is no longer registered; ask the agent for a new link", srv.Name)
+	}
+
+	h := f.authHandler(srv, settings, c, p.TenantID, p.UserID)
+	h.SetExpectedState(state)
+	if err := h.ProcessAuthorizationResponse(ctx, code, state, p.Verifier); err != nil {
+		slog.Warn("mcp.oauth.exchange_failed", "server", srv.Name, "user", p.UserID, "error", err)
+		return srv.Name, fmt.Errorf("token exchange with %s failed: %w", srv.Name, err)
+	}
+
+	now := time.Now()
+	f.markAuthorized(p.TenantID, srv.ID, p.UserID, now)
+	slog.Info("mcp.oauth.authorized", "server", srv.Name, "user", p.UserID)
+	if f.msgBus != nil {
+		f.msgBus.Broadcast(bus.Event{
+			Name:     bus.EventMCPOAuthAuthorized,
+			Payload:  bus.MCPOAuthAuthorizedPayload{ServerID: srv.ID, UserID: p.UserID, At: now},
+			TenantID: p.TenantID,
+		})
+		if p.Channel != "" && p.ChatID != "" {
+			f.msgBus.PublishOutbound(bus.OutboundMessage{
+				Channel: p.Channel,
+				ChatID:  p.ChatID,
+				Content: fmt.Sprintf("Connected to %s. You can ask me again now.", srv.Name),
+			})
+		}
+	}
+	return srv.Name, nil
+}
+
+// markAuthorized records a completed authorization, here or on another replica.
+func (f *OAuthFlows) markAuthorized(tenantID, serverID uuid.UUID, userID string, at time.Time) {
+	key := oauthUserKey(tenantID, serverID, userID)
+	f.mu.Lock()
+	if at.After(f.authorized[key]) {
+		f.authorized[key] = at
+	}
+	f.mu.Unlock()
+}
+
+// authorizedSince reports whether the user completed an authorization for
+// the server after t.
+func (f *OAuthFlows) authorizedSince(tenantID, serverID uuid.UUID, userID string, t time.Time) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.authorized[oauthUserKey(tenantID, serverID, userID)].After(t)
+}
+
+// Status returns the user's stored token for the server, or nil.
+func (f *OAuthFlows) Status(ctx context.Context, serverID uuid.UUID, userID string) (*store.MCPOAuthToken, error) {
+	return f.store.GetOAuthToken(ctx, serverID, userID)
+}
+
+// Revoke deletes the user's token. Open connections fail their next request
+// and prompt for authorization again.
+func (f *OAuthFlows) Revoke(ctx context.Context, serverID uuid.UUID, userID string) error {
+	return f.store.DeleteOAuthToken(ctx, serverID, userID)
+}
+
+// authorizationResult starts an authorization for the calling user and
+// returns the tool result asking them to open the link. In a direct chat the
+// link is sent to the user as-is; in groups it is not posted, because whoever
+// opens it would connect their account on this user's behalf.
+func (f *OAuthFlows) authorizationResult(ctx context.Context, srv *store.MCPServerData) *tools.Result {
+	userID := store.UserIDFromContext(ctx)
+	if userID == "" {
+		return tools.ErrorResult(fmt.Sprintf("MCP server %q requires a signed-in user to authorize access", srv.Name))
+	}
+	if tools.ToolPeerKindFromCtx(ctx) == "group" {
+		return tools.ErrorResult(fmt.Sprintf("MCP server %q needs the user's permission. "+
+			"Ask the user to message you directly (not in this group) to connect their account.", srv.Name))
+	}
+
+	authURL, err := f.Start(ctx, srv, userID)
+	if err != nil {
+		slog.Warn("mcp.oauth.start_failed", "server", srv.Name, "user", userID, "error", err)
+		return tools.ErrorResult(fmt.Sprintf("could not start authorization for MCP server %q: %v", srv.Name, err))
+	}
+
+	channel, chatID := tools.ToolChannelFromCtx(ctx), tools.ToolChatIDFromCtx(ctx)
+	if channel != "" && chatID != "" && f.msgBus != nil {
+		f.msgBus.PublishOutbound(bus.OutboundMessage{
+			Channel: channel,
+			ChatID:  chatID,
+			Content: fmt.Sprintf("%s needs your permission. Open this link to connect your account (valid %d minutes):\n%s",
+				srv.Name, int(oauthFlowTTL.Minutes()), authURL),
+		})
+		return tools.NewResult(fmt.Sprintf("The user must authorize MCP server %q before its tools can be used. "+
+			"An authorization link has been sent to the user in this chat; do not repeat it. "+
+			"Tell the user to open it and let you know when done, then retry.", srv.Name))
+	}
+	return tools.NewResult(fmt.Sprintf("The user must authorize MCP server %q before its tools can be used. "+
+		"Give the user this link exactly as written and ask them to let you know when done, then retry:\n%s", srv.Name, authURL))
+}
+
+// AuthorizeTool stands in for an OAuth server's tools until the user has
+// authorized it. Calling it sends the user an authorization link.
+type AuthorizeTool struct {
+	flows    *OAuthFlows
+	srv      *store.MCPServerData
+	name     string
+	tenantID uuid.UUID
+	userID   string
+	created  time.Time
+}
+
+// NewAuthorizeTool creates the "{prefix}__authorize" tool for a server and user.
+func NewAuthorizeTool(flows *OAuthFlows, srv *store.MCPServerData, tenantID uuid.UUID, userID string) *AuthorizeTool {
+	return &AuthorizeTool{
+		flows:    flows,
+		srv:      srv,
+		name:     ensureMCPPrefix(srv.ToolPrefix, srv.Name) + "__authorize",
+		tenantID: tenantID,
+		userID:   userID,
+		created:  time.Now(),
+	}
+}
+
+func (t *AuthorizeTool) Name() string { return t.name }
+
+func (t *AuthorizeTool) Description() string {
+	label := t.srv.DisplayName
+	if label == "" {
+		label = t.srv.Name
+	}
+	return fmt.Sprintf("Connect the user's account to %s. Its tools become available once the user approves access. "+
+		"Call this when the user asks for something %s can do.", label, label)
+}
+
+func (t *AuthorizeTool) Parameters() map[string]any {
+	return map[string]any{"type": "object", "properties": map[string]any{}}
+}
+
+// ServerName returns the name of the MCP server this tool authorizes.
+func (t *AuthorizeTool) ServerName() string { return t.srv.Name }
+
+// IsConnected reports false once the user has completed authorization, so
+// cached per-user tools are reloaded with the server's real tools.
+func (t *AuthorizeTool) IsConnected() bool {
+	return !t.flows.authorizedSince(t.tenantID, t.srv.ID, t.userID, t.created)
+}
+
+func (t *AuthorizeTool) Execute(ctx context.Context, _ map[string]any) *tools.Result {
+	return t.flows.authorizationResult(ctx, t.srv)
+}
+
+// oauthTokenStore is a transport.TokenStore backed by the MCP server store,
+// scoped to one tenant, server and user. It refreshes tokens shortly before
+// they expire, one refresh at a time per connection.
+type oauthTokenStore struct {
+	store    store.MCPServerStore
+	tenantID uuid.UUID
+	serverID uuid.UUID
+	userID   string
+	refresh  func(ctx context.Context, refreshToken string) (*transport.Token, error)
+
+	mu sync.Mutex
+}
+
+func (s *oauthTokenStore) GetToken(ctx context.Context) (*transport.Token, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t, err := s.store.GetOAuthToken(store.WithTenantID(ctx, s.tenantID), s.serverID, s.userID)
+	if err != nil {
+		return nil, err
+	}
+	if t == nil {
+		return nil, transport.ErrNoToken
+	}
+	tok := &transport.Token{
+		AccessToken:  t.AccessToken,
+		TokenType:    t.TokenType,
+		RefreshToken: t.RefreshToken,
+		Scope:        t.Scope,
+		ExpiresAt:    t.ExpiresAt,
+	}
+	if t.ExpiresAt.IsZero() || time.Until(t.ExpiresAt) > oauthRefreshSkew {
+		return tok, nil
+	}
+	if t.RefreshToken == "" || s.refresh == nil {
+		return nil, transport.ErrNoToken
+	}
+	fresh, err := s.refresh(ctx, t.RefreshToken)
+	if err != nil {
+		// A rejected refresh token needs a new authorization.
+		slog.Warn("mcp.oauth.refresh_failed", "server_id", s.serverID, "user", s.userID, "error", err)
+		return nil, transport.ErrNoToken
+	}
+	slog.Debug("mcp.oauth.refreshed", "server_id", s.serverID, "user", s.userID)
+	return fresh, nil
+}
+
+func (s *oauthTokenStore) SaveToken(ctx context.Context, tok *transport.Token) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	expires := tok.ExpiresAt
+	if expires.IsZero() && tok.ExpiresIn > 0 {
+		expires = time.Now().Add(time.Duration(tok.ExpiresIn) * time.Second)
+	}
+	return s.store.SetOAuthToken(store.WithTenantID(ctx, s.tenantID), s.serverID, s.userID, store.MCPOAuthToken{
+		AccessToken:  tok.AccessToken,
+		RefreshToken: tok.RefreshToken,
+		TokenType:    tok.TokenType,
+		Scope:        tok.Scope,
+		ExpiresAt:    expires,
+	})
+}
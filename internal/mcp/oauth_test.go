@@ -0,0 +1,314 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mark3labs/mcp-go/client/transport"
+
+	"github.com/nextlevelbuilder/goclaw/internal/store"
+)
+
+// oauthMemStore implements the OAuth part of store.MCPServerStore in memory.
+type oauthMemStore struct {
+	store.MCPServerStore // unused methods panic
+
+	mu      sync.Mutex
+	servers map[uuid.UUID]*store.MCPServerData
+	clients map[uuid.UUID]store.MCPOAuthClient
+	tokens  map[string]store.MCPOAuthToken
+	pending map[string]store.MCPOAuthPending
+}
+
+func newOAuthMemStore(servers ...*store.MCPServerData) *oauthMemStore {
+	s := &oauthMemStore{
+		servers: map[uuid.UUID]*store.MCPServerData{},
+		clients: map[uuid.UUID]store.MCPOAuthClient{},
+		tokens:  map[string]store.MCPOAuthToken{},
+		pending: map[string]store.MCPOAuthPending{},
+	}
+	for _, srv := range servers {
+		s.servers[srv.ID] = srv
+	}
+	return s
+}
+
+func (s *oauthMemStore) GetServer(_ context.Context, id uuid.UUID) (*store.MCPServerData, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	srv, ok := s.servers[id]
+	if !ok {
+		return nil, errors.New("not found")
+	}
+	return srv, nil
+}
+
+func (s *oauthMemStore) GetOAuthClient(_ context.Context, serverID uuid.UUID) (*store.MCPOAuthClient, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	c, ok := s.clients[serverID]
+	if !ok {
+		return nil, nil
+	}
+	return &c, nil
+}
+
+func (s *oauthMemStore) SetOAuthClient(_ context.Context, serverID uuid.UUID, c store.MCPOAuthClient) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.clients[serverID] = c
+	return nil
+}
+
+func (s *oauthMemStore) GetOAuthToken(_ context.Context, serverID uuid.UUID, userID string) (*store.MCPOAuthToken, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	t, ok := s.tokens[serverID.String()+"/"+userID]
+	if !ok {
+		return nil, nil
+	}
+	return &t, nil
+}
+
+func (s *oauthMemStore) SetOAuthToken(_ context.Context, serverID uuid.UUID, userID string, t store.MCPOAuthToken) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tokens[serverID.String()+"/"+userID] = t
+	return nil
+}
+
+func (s *oauthMemStore) DeleteOAuthToken(_ context.Context, serverID uuid.UUID, userID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.tokens, serverID.String()+"/"+userID)
+	return nil
+}
+
+func (s *oauthMemStore) CreateOAuthPending(_ context.Context, p store.MCPOAuthPending) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.pending[p.State] = p
+	return nil
+}
+
+func (s *oauthMemStore) TakeOAuthPending(_ context.Context, state string) (*store.MCPOAuthPending, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	p, ok := s.pending[state]
+	delete(s.pending, state)
+	if !ok || time.Now().After(p.ExpiresAt) {
+		return nil, nil
+	}
+	return &p, nil
+}
+
+// fakeAuthServer serves authorization server metadata, dynamic registration
+// and a token endpoint that accepts the code "good" and any refresh token.
+func fakeAuthServer(t *testing.T, refreshes *atomic.Int32) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	var base string
+	mux.HandleFunc("GET /.well-known/oauth-authorization-server", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(transport.AuthServerMetadata{
+			Issuer:                base,
+			AuthorizationEndpoint: base + "/authorize",
+			TokenEndpoint:         base + "/token",
+			RegistrationEndpoint:  base + "/register",
+		})
+	})
+	mux.HandleFunc("POST /register", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(map[string]string{"client_id": "dyn-client"})
+	})
+	mux.HandleFunc("POST /token", func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		switch r.Form.Get("grant_type") {
+		case "authorization_code":
+			if r.Form.Get("code") != "good" || r.Form.Get("code_verifier") == "" || r.Form.Get("client_id") != "dyn-client" {
+				w.WriteHeader(http.StatusBadRequest)
+				_, _ = w.Write([]byte(`{"error":"invalid_grant"}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"access_token":"a1","refresh_token":"r1","token_type":"bearer","expires_in":3600,"scope":"read"}`))
+		case "refresh_token":
+			refreshes.Add(1)
+			_, _ = w.Write([]byte(`{"access_token":"a2","refresh_token":"r2","token_type":"bearer","expires_in":3600}`))
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	})
+	ts := httptest.NewServer(mux)
+	base = ts.URL
+	t.Cleanup(ts.Close)
+	return ts
+}
+
+func TestOAuthFlows_AuthorizeAndRefresh(t *testing.T) {
+	var refreshes atomic.Int32
+	as := fakeAuthServer(t, &refreshes)
+
+	srv := &store.MCPServerData{
+		BaseModel: store.BaseModel{ID: uuid.New()},
+		Name:      "docs",
+		Transport: "streamable-http",
+		URL:       as.URL + "/mcp",
+		Settings:  json.RawMessage(`{"oauth":{"enabled":true,"scopes":["read"]}}`),
+	}
+	ms := newOAuthMemStore(srv)
+	flows := NewOAuthFlows(ms, "https://gw.example.com/v1/mcp/oauth/callback", nil)
+	ctx := store.WithTenantID(context.Background(), store.MasterTenantID)
+
+	if _, err := flows.TransportConfig(ctx, srv, "alice"); !errors.Is(err, ErrOAuthRequired) {
+		t.Fatalf("TransportConfig before authorization: err = %v, want ErrOAuthRequired", err)
+	}
+
+	authURL, err := flows.Start(ctx, srv, "alice")
+	if err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	u, _ := url.Parse(authURL)
+	q := u.Query()
+	if u.Path != "/authorize" || q.Get("client_id") != "dyn-client" || q.Get("code_challenge_method") != "S256" ||
+		q.Get("scope") != "read" || q.Get("resource") != srv.URL || q.Get("state") == "" {
+		t.Fatalf("unexpected authorization URL %s", authURL)
+	}
+	if c, _ := ms.GetOAuthClient(ctx, srv.ID); c == nil || c.ClientID != "dyn-client" {
+		t.Fatalf("registered client not stored: %+v", c)
+	}
+
+	if _, err := flows.Complete(ctx, "bogus", "good"); err == nil {
+		t.Fatal("Complete with unknown state succeeded")
+	}
+	before := time.Now().Add(-time.Second)
+	name, err := flows.Complete(context.Background(), q.Get("state"), "good")
+	if err != nil || name != "docs" {
+		t.Fatalf("Complete = %q, %v", name, err)
+	}
+	if _, err := flows.Complete(ctx, q.Get("state"), "good"); err == nil {
+		t.Fatal("Complete succeeded twice for the same state")
+	}
+	if !flows.authorizedSince(store.MasterTenantID, srv.ID, "alice", before) {
+		t.Error("authorizedSince = false after Complete")
+	}
+
+	tok, _ := ms.GetOAuthToken(ctx, srv.ID, "alice")
+	if tok == nil || tok.AccessToken != "a1" || tok.RefreshToken != "r1" || tok.ExpiresAt.IsZero() {
+		t.Fatalf("stored token = %+v", tok)
+	}
+
+	cfg, err := flows.TransportConfig(ctx, srv, "alice")
+	if err != nil {
+		t.Fatalf("TransportConfig: %v", err)
+	}
+	got, err := cfg.TokenStore.GetToken(ctx)
+	if err != nil || got.AccessToken != "a1" || refreshes.Load() != 0 {
+		t.Fatalf("GetToken = %+v, %v (refreshes %d); want a1 without refresh", got, err, refreshes.Load())
+	}
+
+	// Close to expiry: the token store refreshes and persists the new token.
+	tok.ExpiresAt = time.Now().Add(10 * time.Second)
+	_ = ms.SetOAuthToken(ctx, srv.ID, "alice", *tok)
+	got, err = cfg.TokenStore.GetToken(ctx)
+	if err != nil || got.AccessToken != "a2" || refreshes.Load() != 1 {
+		t.Fatalf("GetToken near expiry = %+v, %v (refreshes %d); want refreshed a2", got, err, refreshes.Load())
+	}
+	if tok, _ := ms.GetOAuthToken(ctx, srv.ID, "alice"); tok == nil || tok.AccessToken != "a2" || tok.RefreshToken != "r2" {
+		t.Fatalf("refreshed token not stored: %+v", tok)
+	}
+
+	if err := flows.Revoke(ctx, srv.ID, "alice"); err != nil {
+		t.Fatalf("Revoke: %v", err)
+	}
+	if _, err := cfg.TokenStore.GetToken(ctx); !errors.Is(err, transport.ErrNoToken) {
+		t.Errorf("GetToken after revoke: err = %v, want ErrNoToken", err)
+	}
+}
+
+func TestOAuthFlows_DeclinedAuthorization(t *testing.T) {
+	var refreshes atomic.Int32
+	as := fakeAuthServer(t, &refreshes)
+
+	srv := &store.MCPServerData{
+		BaseModel: store.BaseModel{ID: uuid.New()},
+		Name:      "docs",
+		Transport: "sse",
+		URL:       as.URL + "/sse",
+		Settings:  json.RawMessage(`{"oauth":{"enabled":true}}`),
+	}
+	ms := newOAuthMemStore(srv)
+	flows := NewOAuthFlows(ms, "https://gw.example.com/v1/mcp/oauth/callback", nil)
+	ctx := store.WithTenantID(context.Background(), store.MasterTenantID)
+
+	authURL, err := flows.Start(ctx, srv, "bob")
+	if err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	u, _ := url.Parse(authURL)
+	if _, err := flows.Complete(ctx, u.Query().Get("state"), ""); err == nil {
+		t.Fatal("Complete without a code succeeded")
+	}
+	if tok, _ := ms.GetOAuthToken(ctx, srv.ID, "bob"); tok != nil {
+		t.Errorf("token stored after declined authorization: %+v", tok)
+	}
+
+	stdio := &store.MCPServerData{Name: "local", Transport: "stdio"}
+	if _, err := flows.Start(ctx, stdio, "bob"); err == nil {
+		t.Error("Start for a stdio server succeeded")
+	}
+}
+
+// A callback may land on a replica other than the one that issued the link:
+// the flow is completed from the shared store.
+func TestOAuthFlows_CompleteOnAnotherReplica(t *testing.T) {
+	var refreshes atomic.Int32
+	as := fakeAuthServer(t, &refreshes)
+
+	srv := &store.MCPServerData{
+		BaseModel: store.BaseModel{ID: uuid.New()},
+		Name:      "docs",
+		Transport: "streamable-http",
+		URL:       as.URL + "/mcp",
+		Settings:  json.RawMessage(`{"oauth":{"enabled":true}}`),
+	}
+	ms := newOAuthMemStore(srv)
+	issuer := NewOAuthFlows(ms, "https://gw.example.com/v1/mcp/oauth/callback", nil)
+	other := NewOAuthFlows(ms, "https://gw.example.com/v1/mcp/oauth/callback", nil)
+	ctx := store.WithTenantID(context.Background(), store.MasterTenantID)
+
+	authURL, err := issuer.Start(ctx, srv, "carol")
+	if err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	u, _ := url.Parse(authURL)
+	if name, err := other.Complete(context.Background(), u.Query().Get("state"), "good"); err != nil || name != "docs" {
+		t.Fatalf("Complete on other replica = %q, %v", name, err)
+	}
+	if tok, _ := ms.GetOAuthToken(ctx, srv.ID, "carol"); tok == nil || tok.AccessToken != "a1" {
+		t.Fatalf("stored token = %+v", tok)
+	}
+	if len(ms.pending) != 0 {
+		t.Errorf("pending flows left after Complete: %d", len(ms.pending))
+	}
+}
+
+func TestParseOAuthSettings(t *testing.T) {
+	s := ParseOAuthSettings(json.RawMessage(`{"require_user_credentials":false,"oauth":{"enabled":true,"scopes":["a","b"],"client_id":"pre"}}`))
+	if !s.Enabled || len(s.Scopes) != 2 || s.ClientID != "pre" {
+		t.Errorf("ParseOAuthSettings = %+v", s)
+	}
+	if ParseOAuthSettings(nil).Enabled || ParseOAuthSettings(json.RawMessage(`not json`)).Enabled {
+		t.Error("empty or invalid settings enabled OAuth")
+	}
+	if !requireUserCreds(json.RawMessage(`{"oauth":{"enabled":true}}`)) {
+		t.Error("requireUserCreds = false for an OAuth server")
+	}
+}
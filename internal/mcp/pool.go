@@ -11,6 +11,7 @@ import (
 
 	"github.com/google/uuid"
 	mcpclient "github.com/mark3labs/mcp-go/client"
+	"github.com/mark3labs/mcp-go/client/transport"
 	mcpgo "github.com/mark3labs/mcp-go/mcp"
 )
 
@@ -57,6 +58,7 @@ type Pool struct {
 	cfg         PoolConfig
 	slot        chan struct{} // semaphore for MaxSize
 	stopCh      chan struct{}
+	oauth       *OAuthFlows // OAuth authorization for remote servers (nil = disabled)
 }
 
 // NewPool creates a shared MCP connection pool with idle eviction.
@@ -95,11 +97,34 @@ func NewPool(cfg PoolConfig) *Pool {
 	return p
 }
 
+// SetOAuth enables OAuth authorization for remote servers that opt in.
+func (p *Pool) SetOAuth(f *OAuthFlows) { p.oauth = f }
+
+// OAuth returns the pool's OAuth flows, or nil when disabled.
+func (p *Pool) OAuth() *OAuthFlows { return p.oauth }
+
 // poolKey builds a tenant-scoped key for pool lookups.
 func poolKey(tenantID uuid.UUID, name string) string {
 	return tenantID.String() + "/" + name
 }
 
+// samplingPoolKey builds the shared-pool key. Connections that advertise the
+// sampling capability belong to one agent (samplingAgent): sampling requests
+// carry no reference to the call that triggered them, so a connection shared
+// across agents could bill one agent's grant for another's call. Tools-only
+// connections (samplingAgent == uuid.Nil) stay shared across the tenant.
+func samplingPoolKey(tenantID uuid.UUID, name string, samplingAgent uuid.UUID) string {
+	if samplingAgent != uuid.Nil {
+		return samplingKeyPrefix(tenantID, name) + samplingAgent.String()
+	}
+	return poolKey(tenantID, name)
+}
+
+// samplingKeyPrefix is the common prefix of a server's per-agent sampling keys.
+func samplingKeyPrefix(tenantID uuid.UUID, name string) string {
+	return poolKey(tenantID, name) + "#sampling/"
+}
+
 // UserPoolKey builds a tenant+user-scoped key for user pool lookups.
 // Exported for callers that need to construct release keys.
 func UserPoolKey(tenantID uuid.UUID, serverName, userID string) string {
@@ -113,9 +138,11 @@ func userSlotKey(tenantID uuid.UUID, serverName string) string {
 
 // Acquire returns a shared connection for the named server scoped to a tenant.
 // If no connection exists, it connects using the provided config.
+// A non-nil samplingAgent acquires that agent's sampling-enabled connection.
 // Blocks up to AcquireTimeout if pool is at MaxSize.
-func (p *Pool) Acquire(ctx context.Context, tenantID uuid.UUID, name, transportType, command string, args []string, env map[string]string, url string, headers map[string]string, timeoutSec int) (*poolEntry, error) {
-	key := poolKey(tenantID, name)
+func (p *Pool) Acquire(ctx context.Context, tenantID uuid.UUID, name, transportType, command string, args []string, env map[string]string, url string, headers map[string]string, timeoutSec int, samplingAgent uuid.UUID) (*poolEntry, error) {
+	key := samplingPoolKey(tenantID, name, samplingAgent)
+	sampling := samplingAgent != uuid.Nil
 
 	p.mu.Lock()
 	if entry, ok := p.servers[key]; ok && entry.state.connected.Load() {
@@ -149,7 +176,7 @@ func (p *Pool) Acquire(ctx context.Context, tenantID uuid.UUID, name, transportT
 	}
 
 	// Connect outside the lock (may be slow)
-	ss, mcpTools, err := connectAndDiscover(ctx, name, transportType, command, args, env, url, headers, timeoutSec)
+	ss, mcpTools, err := connectAndDiscover(ctx, name, transportType, command, args, env, url, headers, timeoutSec, sampling, nil)
 	if err != nil {
 		// Return slot on failure
 		select {
@@ -198,7 +225,8 @@ func (p *Pool) Acquire(ctx context.Context, tenantID uuid.UUID, name, transportT
 // AcquireUser returns a per-user connection for the named server scoped to a tenant+user.
 // If no connection exists, it connects using the provided config.
 // Blocks up to UserAcquireTimeout if per-server user slot limit is reached.
-func (p *Pool) AcquireUser(ctx context.Context, tenantID uuid.UUID, name, userID, transportType, command string, args []string, env map[string]string, url string, headers map[string]string, timeoutSec int) (*poolEntry, error) {
+// oauth carries the user's token store for servers using OAuth authorization.
+func (p *Pool) AcquireUser(ctx context.Context, tenantID uuid.UUID, name, userID, transportType, command string, args []string, env map[string]string, url string, headers map[string]string, timeoutSec int, oauth *transport.OAuthConfig) (*poolEntry, error) {
 	key := UserPoolKey(tenantID, name, userID)
 	slotKey := userSlotKey(tenantID, name)
 
@@ -242,7 +270,7 @@ func (p *Pool) AcquireUser(ctx context.Context, tenantID uuid.UUID, name, userID
 	}
 
 	// Connect outside the lock (may be slow)
-	ss, mcpTools, err := connectAndDiscover(ctx, name, transportType, command, args, env, url, headers, timeoutSec)
+	ss, mcpTools, err := connectAndDiscover(ctx, name, transportType, command, args, env, url, headers, timeoutSec, false, oauth)
 	if err != nil {
 		// Return slot on failure
 		select {
@@ -412,26 +440,27 @@ func (p *Pool) Stop() {
 // Evict closes a specific pooled connection by tenant + server name.
 // Called when server credentials are rotated to force reconnection with new credentials.
 func (p *Pool) Evict(tenantID uuid.UUID, serverName string) {
-	key := poolKey(tenantID, serverName)
 	p.mu.Lock()
 	defer p.mu.Unlock()
 
-	entry, ok := p.servers[key]
-	if !ok {
-		return
-	}
-	if entry.state.cancel != nil {
-		entry.state.cancel()
-	}
-	if entry.state.client != nil {
-		_ = entry.state.client.Close()
-	}
-	delete(p.servers, key)
-	select {
-	case <-p.slot:
-	default:
+	plain, prefix := poolKey(tenantID, serverName), samplingKeyPrefix(tenantID, serverName)
+	for key, entry := range p.servers {
+		if key != plain && !strings.HasPrefix(key, prefix) {
+			continue
+		}
+		if entry.state.cancel != nil {
+			entry.state.cancel()
+		}
+		if entry.state.client != nil {
+			_ = entry.state.client.Close()
+		}
+		delete(p.servers, key)
+		select {
+		case <-p.slot:
+		default:
+		}
+		slog.Info("mcp.pool.evicted_on_rotation", "key", key)
 	}
-	slog.Info("mcp.pool.evicted_on_rotation", "key", key)
 }
 
 // evictLoop runs periodically to close idle connections over MaxIdle count.
@@ -0,0 +1,129 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"time"
+
+	mcpgo "github.com/mark3labs/mcp-go/mcp"
+)
+
+const promptTimeout = 15 * time.Second
+
+// PromptInfo describes an MCP prompt template available to an agent.
+type PromptInfo struct {
+	Server      string          `json:"server"`
+	Name        string          `json:"name"`
+	Description string          `json:"description,omitempty"`
+	Arguments   []PromptArgInfo `json:"arguments,omitempty"`
+}
+
+// PromptArgInfo describes one prompt argument.
+type PromptArgInfo struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Required    bool   `json:"required,omitempty"`
+}
+
+// promptServer returns the connection for a server whose grant exposes
+// prompts and which advertises the prompts capability.
+func (m *Manager) promptServer(name string) (*serverState, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	ss, ok := m.servers[name]
+	if !ok || !m.grants[name].Prompts || ss.caps.Prompts == nil {
+		return nil, false
+	}
+	return ss, true
+}
+
+// Prompts lists prompt templates from every connected server whose grant
+// exposes prompts. Servers that fail to answer are skipped.
+func (m *Manager) Prompts(ctx context.Context) []PromptInfo {
+	m.mu.RLock()
+	names := make([]string, 0, len(m.grants))
+	for name, g := range m.grants {
+		if g.Prompts {
+			names = append(names, name)
+		}
+	}
+	m.mu.RUnlock()
+	sort.Strings(names)
+
+	var out []PromptInfo
+	for _, name := range names {
+		ss, ok := m.promptServer(name)
+		if !ok || !ss.connected.Load() {
+			continue
+		}
+		callCtx, cancel := context.WithTimeout(ctx, promptTimeout)
+		res, err := ss.client.ListPrompts(callCtx, mcpgo.ListPromptsRequest{})
+		cancel()
+		if err != nil {
+			slog.Warn("mcp.prompts.list_failed", "server", name, "error", err)
+			continue
+		}
+		for _, p := range res.Prompts {
+			info := PromptInfo{Server: name, Name: p.Name, Description: p.Description}
+			for _, a := range p.Arguments {
+				info.Arguments = append(info.Arguments, PromptArgInfo{Name: a.Name, Description: a.Description, Required: a.Required})
+			}
+			out = append(out, info)
+		}
+	}
+	return out
+}
+
+// GetPrompt renders a prompt template with the given arguments into a single
+// message text suitable for use as the user's turn.
+func (m *Manager) GetPrompt(ctx context.Context, server, name string, args map[string]string) (string, error) {
+	ss, ok := m.promptServer(server)
+	if !ok {
+		return "", fmt.Errorf("prompts from MCP server %q are not available to this agent", server)
+	}
+	if !ss.connected.Load() {
+		return "", fmt.Errorf("MCP server %q is disconnected", server)
+	}
+
+	callCtx, cancel := context.WithTimeout(ctx, promptTimeout)
+	defer cancel()
+	req := mcpgo.GetPromptRequest{}
+	req.Params.Name = name
+	req.Params.Arguments = args
+	res, err := ss.client.GetPrompt(callCtx, req)
+	if err != nil {
+		return "", fmt.Errorf("get prompt %s:%s: %w", server, name, err)
+	}
+	return renderPrompt(res.Messages), nil
+}
+
+// renderPrompt flattens prompt messages. A single user message is returned
+// as-is; multi-turn prompts keep role labels so the structure survives.
+func renderPrompt(msgs []mcpgo.PromptMessage) string {
+	if len(msgs) == 1 && msgs[0].Role == mcpgo.RoleUser {
+		return promptContentText(msgs[0].Content)
+	}
+	var parts []string
+	for _, msg := range msgs {
+		parts = append(parts, fmt.Sprintf("[%s]\n%s", msg.Role, promptContentText(msg.Content)))
+	}
+	return strings.Join(parts, "\n\n")
+}
+
+func promptContentText(c mcpgo.Content) string {
+	switch v := c.(type) {
+	case mcpgo.TextContent:
+		return v.Text
+	case *mcpgo.TextContent:
+		return v.Text
+	case mcpgo.EmbeddedResource:
+		return resourceContentsText([]mcpgo.ResourceContents{v.Resource})
+	case *mcpgo.EmbeddedResource:
+		return resourceContentsText([]mcpgo.ResourceContents{v.Resource})
+	default:
+		return samplingText(c)
+	}
+}
@@ -0,0 +1,340 @@
+package mcp
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	mcpgo "github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/nextlevelbuilder/goclaw/internal/tools"
+)
+
+const (
+	maxResourceChanges = 200   // change notifications kept per connection
+	maxResourceChars   = 50000 // read output cap before truncation
+)
+
+// resourceChange is one resources/updated or resources/list_changed notification.
+type resourceChange struct {
+	Seq         uint64
+	URI         string // empty for list_changed
+	ListChanged bool
+	At          time.Time
+}
+
+// resourceWatch collects resource change notifications for one connection.
+// Agents poll it through the resources tool's "changes" action.
+type resourceWatch struct {
+	mu      sync.Mutex
+	seq     uint64
+	changes []resourceChange
+}
+
+func newResourceWatch() *resourceWatch {
+	return &resourceWatch{}
+}
+
+// handle is registered as the client's notification handler.
+func (w *resourceWatch) handle(n mcpgo.JSONRPCNotification) {
+	switch n.Method {
+	case mcpgo.MethodNotificationResourceUpdated:
+		uri, _ := n.Params.AdditionalFields["uri"].(string)
+		if uri != "" {
+			w.record(uri, false)
+		}
+	case mcpgo.MethodNotificationResourcesListChanged:
+		w.record("", true)
+	}
+}
+
+func (w *resourceWatch) record(uri string, listChanged bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.seq++
+	w.changes = append(w.changes, resourceChange{Seq: w.seq, URI: uri, ListChanged: listChanged, At: time.Now()})
+	if len(w.changes) > maxResourceChanges {
+		w.changes = w.changes[len(w.changes)-maxResourceChanges:]
+	}
+}
+
+// since returns changes after seq and the latest sequence number.
+func (w *resourceWatch) since(seq uint64) ([]resourceChange, uint64) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	var out []resourceChange
+	for _, c := range w.changes {
+		if c.Seq > seq {
+			out = append(out, c)
+		}
+	}
+	return out, w.seq
+}
+
+// ResourceTool exposes an MCP server's resources to the agent as a single
+// tool: list, list templates, read, and subscribe to change notifications.
+// Registered only when the agent's grant enables resources.
+type ResourceTool struct {
+	serverName     string
+	registeredName string
+	ss             *serverState
+	timeoutSec     int
+
+	mu         sync.Mutex
+	subscribed map[string]struct{} // URIs this agent subscribed to
+}
+
+// NewResourceTool creates the resources tool for a connected server.
+// It is named "{prefix}__resources" alongside the server's bridged tools.
+func NewResourceTool(serverName, prefix string, ss *serverState, timeoutSec int) *ResourceTool {
+	if timeoutSec <= 0 {
+		timeoutSec = 60
+	}
+	return &ResourceTool{
+		serverName:     serverName,
+		registeredName: ensureMCPPrefix(prefix, serverName) + "__resources",
+		ss:             ss,
+		timeoutSec:     timeoutSec,
+		subscribed:     make(map[string]struct{}),
+	}
+}
+
+func (t *ResourceTool) Name() string { return t.registeredName }
+
+func (t *ResourceTool) Description() string {
+	d := fmt.Sprintf("Access resources (files, records, documents) published by MCP server %q. "+
+		"Actions: list, templates, read (uri)", t.serverName)
+	if t.canSubscribe() {
+		d += ", subscribe/unsubscribe (uri), changes (since: cursor from the previous call)"
+	}
+	return d + "."
+}
+
+func (t *ResourceTool) Parameters() map[string]any {
+	actions := []string{"list", "templates", "read"}
+	if t.canSubscribe() {
+		actions = append(actions, "subscribe", "unsubscribe", "changes")
+	}
+	return map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"action": map[string]any{
+				"type":        "string",
+				"enum":        actions,
+				"description": "Operation to perform",
+			},
+			"uri": map[string]any{
+				"type":        "string",
+				"description": "Resource URI (read, subscribe, unsubscribe)",
+			},
+			"since": map[string]any{
+				"type":        "integer",
+				"description": "Change cursor returned by a previous 'changes' call (default 0)",
+			},
+		},
+		"required": []string{"action"},
+	}
+}
+
+// ServerName returns the name of the MCP server this tool belongs to.
+func (t *ResourceTool) ServerName() string { return t.serverName }
+
+func (t *ResourceTool) canSubscribe() bool {
+	return t.ss.caps.Resources != nil && t.ss.caps.Resources.Subscribe
+}
+
+func (t *ResourceTool) Execute(ctx context.Context, args map[string]any) *tools.Result {
+	if !t.ss.connected.Load() {
+		return tools.ErrorResult(fmt.Sprintf("MCP server %q is disconnected", t.serverName))
+	}
+	action, _ := args["action"].(string)
+	uri, _ := args["uri"].(string)
+	uri = strings.TrimSpace(uri)
+
+	callCtx, cancel := context.WithTimeout(ctx, time.Duration(t.timeoutSec)*time.Second)
+	defer cancel()
+
+	switch action {
+	case "list":
+		return t.list(callCtx)
+	case "templates":
+		return t.templates(callCtx)
+	case "read":
+		if uri == "" {
+			return tools.ErrorResult("uri is required for read")
+		}
+		return t.read(callCtx, uri)
+	case "subscribe", "unsubscribe":
+		if !t.canSubscribe() {
+			return tools.ErrorResult(fmt.Sprintf("MCP server %q does not support resource subscriptions", t.serverName))
+		}
+		if uri == "" {
+			return tools.ErrorResult("uri is required for " + action)
+		}
+		return t.subscribe(callCtx, uri, action == "subscribe")
+	case "changes":
+		return t.changes(args)
+	default:
+		return tools.ErrorResult(fmt.Sprintf("unknown action %q", action))
+	}
+}
+
+func (t *ResourceTool) list(ctx context.Context) *tools.Result {
+	res, err := t.ss.client.ListResources(ctx, mcpgo.ListResourcesRequest{})
+	if err != nil {
+		return tools.ErrorResult(fmt.Sprintf("list resources: %v", err))
+	}
+	if len(res.Resources) == 0 {
+		return tools.NewResult("No resources.")
+	}
+	var sb strings.Builder
+	for _, r := range res.Resources {
+		fmt.Fprintf(&sb, "- %s", r.URI)
+		if r.Name != "" && r.Name != r.URI {
+			fmt.Fprintf(&sb, " (%s)", r.Name)
+		}
+		if r.MIMEType != "" {
+			fmt.Fprintf(&sb, " [%s]", r.MIMEType)
+		}
+		if r.Description != "" {
+			sb.WriteString(": " + r.Description)
+		}
+		sb.WriteByte('\n')
+	}
+	return tools.NewResult(wrapMCPContent(sb.String(), t.serverName, "resources/list"))
+}
+
+func (t *ResourceTool) templates(ctx context.Context) *tools.Result {
+	res, err := t.ss.client.ListResourceTemplates(ctx, mcpgo.ListResourceTemplatesRequest{})
+	if err != nil {
+		return tools.ErrorResult(fmt.Sprintf("list resource templates: %v", err))
+	}
+	if len(res.ResourceTemplates) == 0 {
+		return tools.NewResult("No resource templates.")
+	}
+	var sb strings.Builder
+	for _, rt := range res.ResourceTemplates {
+		tmpl := ""
+		if rt.URITemplate != nil && rt.URITemplate.Template != nil {
+			tmpl = rt.URITemplate.Raw()
+		}
+		fmt.Fprintf(&sb, "- %s (%s)", tmpl, rt.Name)
+		if rt.Description != "" {
+			sb.WriteString(": " + rt.Description)
+		}
+		sb.WriteByte('\n')
+	}
+	sb.WriteString("\nFill in a template's {placeholders} and pass the result as uri to read.")
+	return tools.NewResult(wrapMCPContent(sb.String(), t.serverName, "resources/templates"))
+}
+
+func (t *ResourceTool) read(ctx context.Context, uri string) *tools.Result {
+	req := mcpgo.ReadResourceRequest{}
+	req.Params.URI = uri
+	res, err := t.ss.client.ReadResource(ctx, req)
+	if err != nil {
+		return tools.ErrorResult(fmt.Sprintf("read resource %s: %v", uri, err))
+	}
+	text := resourceContentsText(res.Contents)
+	if len(text) > maxResourceChars {
+		text = text[:maxResourceChars] + fmt.Sprintf("\n[truncated: resource is %d characters]", len(text))
+	}
+	return tools.NewResult(wrapMCPContent(text, t.serverName, "resources/read "+uri))
+}
+
+func (t *ResourceTool) subscribe(ctx context.Context, uri string, on bool) *tools.Result {
+	if on {
+		req := mcpgo.SubscribeRequest{}
+		req.Params.URI = uri
+		if err := t.ss.client.Subscribe(ctx, req); err != nil {
+			return tools.ErrorResult(fmt.Sprintf("subscribe %s: %v", uri, err))
+		}
+		t.mu.Lock()
+		t.subscribed[uri] = struct{}{}
+		t.mu.Unlock()
+		_, cursor := t.ss.watch.since(0)
+		return tools.NewResult(fmt.Sprintf("Subscribed to %s. Call action=changes with since=%d to see updates.", uri, cursor))
+	}
+
+	req := mcpgo.UnsubscribeRequest{}
+	req.Params.URI = uri
+	if err := t.ss.client.Unsubscribe(ctx, req); err != nil {
+		return tools.ErrorResult(fmt.Sprintf("unsubscribe %s: %v", uri, err))
+	}
+	t.mu.Lock()
+	delete(t.subscribed, uri)
+	t.mu.Unlock()
+	return tools.NewResult("Unsubscribed from " + uri + ".")
+}
+
+// changes reports notifications for this agent's subscriptions (and resource
+// list changes) since the given cursor.
+func (t *ResourceTool) changes(args map[string]any) *tools.Result {
+	var since uint64
+	if v, ok := args["since"].(float64); ok && v > 0 {
+		since = uint64(v)
+	}
+	all, cursor := t.ss.watch.since(since)
+
+	t.mu.Lock()
+	updated := make(map[string]time.Time)
+	listChanged := false
+	for _, c := range all {
+		if c.ListChanged {
+			listChanged = true
+			continue
+		}
+		if _, ok := t.subscribed[c.URI]; ok {
+			updated[c.URI] = c.At
+		}
+	}
+	t.mu.Unlock()
+
+	var sb strings.Builder
+	if len(updated) == 0 && !listChanged {
+		sb.WriteString("No changes.")
+	}
+	uris := make([]string, 0, len(updated))
+	for u := range updated {
+		uris = append(uris, u)
+	}
+	sort.Strings(uris)
+	for _, u := range uris {
+		fmt.Fprintf(&sb, "- updated %s at %s\n", u, updated[u].UTC().Format(time.RFC3339))
+	}
+	if listChanged {
+		sb.WriteString("- the resource list changed; call action=list to refresh\n")
+	}
+	fmt.Fprintf(&sb, "\ncursor: %d", cursor)
+	return tools.NewResult(sb.String())
+}
+
+// resourceContentsText renders resource contents as text; binary blobs are
+// summarized rather than inlined.
+func resourceContentsText(contents []mcpgo.ResourceContents) string {
+	var parts []string
+	for _, c := range contents {
+		switch v := c.(type) {
+		case mcpgo.TextResourceContents:
+			parts = append(parts, v.Text)
+		case *mcpgo.TextResourceContents:
+			parts = append(parts, v.Text)
+		case mcpgo.BlobResourceContents:
+			parts = append(parts, blobSummary(v.URI, v.MIMEType, v.Blob))
+		case *mcpgo.BlobResourceContents:
+			parts = append(parts, blobSummary(v.URI, v.MIMEType, v.Blob))
+		}
+	}
+	return strings.Join(parts, "\n")
+}
+
+func blobSummary(uri, mime, blob string) string {
+	if mime == "" {
+		mime = "application/octet-stream"
+	}
+	return fmt.Sprintf("[binary resource %s: %s, %d bytes]", uri, mime, base64.StdEncoding.DecodedLen(len(blob)))
+}
@@ -0,0 +1,78 @@
+package mcp
+
+import (
+	"strings"
+	"testing"
+
+	mcpgo "github.com/mark3labs/mcp-go/mcp"
+)
+
+func notification(method string, fields map[string]any) mcpgo.JSONRPCNotification {
+	n := mcpgo.JSONRPCNotification{}
+	n.Method = method
+	n.Params.AdditionalFields = fields
+	return n
+}
+
+func TestResourceWatch(t *testing.T) {
+	w := newResourceWatch()
+	w.handle(notification(mcpgo.MethodNotificationResourceUpdated, map[string]any{"uri": "file:///a"}))
+	w.handle(notification(mcpgo.MethodNotificationResourcesListChanged, nil))
+	w.handle(notification("notifications/tools/list_changed", nil))
+
+	all, cursor := w.since(0)
+	if len(all) != 2 || cursor != 2 {
+		t.Fatalf("changes = %+v, cursor = %d", all, cursor)
+	}
+	if all[0].URI != "file:///a" || !all[1].ListChanged {
+		t.Errorf("changes = %+v", all)
+	}
+	if later, _ := w.since(cursor); len(later) != 0 {
+		t.Errorf("since(cursor) = %+v, want none", later)
+	}
+
+	for range maxResourceChanges + 10 {
+		w.record("file:///b", false)
+	}
+	if all, _ := w.since(0); len(all) != maxResourceChanges {
+		t.Errorf("kept %d changes, want cap %d", len(all), maxResourceChanges)
+	}
+}
+
+func TestResourceTool_ChangesFiltersSubscriptions(t *testing.T) {
+	ss := &serverState{name: "docs", watch: newResourceWatch()}
+	ss.caps.Resources = &struct {
+		Subscribe   bool `json:"subscribe,omitempty"`
+		ListChanged bool `json:"listChanged,omitempty"`
+	}{Subscribe: true}
+	rt := NewResourceTool("docs", "", ss, 0)
+	if rt.Name() != "mcp_docs__resources" {
+		t.Errorf("name = %q", rt.Name())
+	}
+	rt.subscribed["file:///mine"] = struct{}{}
+
+	ss.watch.record("file:///mine", false)
+	ss.watch.record("file:///other", false)
+
+	out := rt.changes(map[string]any{}).ForLLM
+	if !strings.Contains(out, "file:///mine") || strings.Contains(out, "file:///other") {
+		t.Errorf("changes output = %q", out)
+	}
+	if !strings.Contains(out, "cursor: 2") {
+		t.Errorf("missing cursor: %q", out)
+	}
+	out = rt.changes(map[string]any{"since": float64(2)}).ForLLM
+	if !strings.HasPrefix(out, "No changes.") {
+		t.Errorf("changes since cursor = %q", out)
+	}
+}
+
+func TestResourceContentsText(t *testing.T) {
+	got := resourceContentsText([]mcpgo.ResourceContents{
+		mcpgo.TextResourceContents{URI: "a", Text: "hello"},
+		mcpgo.BlobResourceContents{URI: "b", MIMEType: "image/png", Blob: "AAAA"},
+	})
+	if !strings.HasPrefix(got, "hello\n") || !strings.Contains(got, "[binary resource b: image/png, 3 bytes]") {
+		t.Errorf("text = %q", got)
+	}
+}
@@ -0,0 +1,230 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	mcpgo "github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/nextlevelbuilder/goclaw/internal/store"
+)
+
+const (
+	defaultSamplingMaxTokens = 1024
+	defaultSamplingPerHour   = 30
+)
+
+// Sampler answers server-initiated sampling requests with an LLM call.
+// The agent owning the Manager supplies it via WithSampler; it is expected
+// to use the agent's provider and enforce the agent's budget.
+type Sampler interface {
+	Sample(ctx context.Context, req SampleRequest) (*SampleResult, error)
+}
+
+// SampleMessage is one conversation turn in a sampling request.
+type SampleMessage struct {
+	Role    string // "user" or "assistant"
+	Content string
+}
+
+// SampleRequest is a sampling/createMessage request after policy is applied.
+type SampleRequest struct {
+	Server       string
+	SystemPrompt string
+	Messages     []SampleMessage
+	MaxTokens    int      // already capped by the grant policy
+	Temperature  float64  // 0 = provider default
+	ModelHints   []string // server preferences, informational
+	Model        string   // grant override; empty = the agent's model
+}
+
+// SampleResult is the LLM reply returned to the MCP server.
+type SampleResult struct {
+	Content    string
+	Model      string
+	StopReason string
+}
+
+// samplingGrant binds an agent's sampler to one server under the grant's
+// policy, including a sliding one-hour request limit.
+type samplingGrant struct {
+	sampler Sampler
+	policy  store.MCPSamplingPolicy
+
+	mu     sync.Mutex
+	window []time.Time // request times within the last hour
+}
+
+func newSamplingGrant(s Sampler, p store.MCPSamplingPolicy) *samplingGrant {
+	if p.MaxTokens <= 0 {
+		p.MaxTokens = defaultSamplingMaxTokens
+	}
+	if p.MaxRequestsPerHour <= 0 {
+		p.MaxRequestsPerHour = defaultSamplingPerHour
+	}
+	return &samplingGrant{sampler: s, policy: p}
+}
+
+// allow records a request at now if the hourly limit permits it.
+func (g *samplingGrant) allow(now time.Time) bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	cutoff := now.Add(-time.Hour)
+	i := 0
+	for i < len(g.window) && !g.window[i].After(cutoff) {
+		i++
+	}
+	g.window = g.window[i:]
+	if len(g.window) >= g.policy.MaxRequestsPerHour {
+		return false
+	}
+	g.window = append(g.window, now)
+	return true
+}
+
+// inflightCall is a tool call waiting on an MCP server.
+type inflightCall struct {
+	ctx   context.Context
+	grant *samplingGrant
+}
+
+// callTracker records in-flight tool calls on one connection. Sampling
+// requests carry no reference to the call that triggered them, so they are
+// attributed to the most recent call still waiting on the server. Pooled
+// sampling connections belong to a single agent (samplingPoolKey), so that call
+// is always one of that agent's. It is the connection's mcp-go SamplingHandler.
+type callTracker struct {
+	server string
+
+	mu    sync.Mutex
+	seq   uint64
+	calls map[uint64]inflightCall
+}
+
+// begin registers a call and returns the function that ends it.
+func (c *callTracker) begin(ctx context.Context, grant *samplingGrant) func() {
+	c.mu.Lock()
+	c.seq++
+	id := c.seq
+	if c.calls == nil {
+		c.calls = make(map[uint64]inflightCall)
+	}
+	c.calls[id] = inflightCall{ctx: ctx, grant: grant}
+	c.mu.Unlock()
+
+	return func() {
+		c.mu.Lock()
+		delete(c.calls, id)
+		c.mu.Unlock()
+	}
+}
+
+// current returns the most recent in-flight call.
+func (c *callTracker) current() (inflightCall, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var (
+		best   inflightCall
+		bestID uint64
+	)
+	for id, call := range c.calls {
+		if id > bestID && call.ctx.Err() == nil {
+			best, bestID = call, id
+		}
+	}
+	return best, bestID != 0
+}
+
+// CreateMessage implements mcpclient.SamplingHandler.
+func (c *callTracker) CreateMessage(_ context.Context, req mcpgo.CreateMessageRequest) (*mcpgo.CreateMessageResult, error) {
+	call, ok := c.current()
+	if !ok {
+		slog.Warn("mcp.sampling.rejected", "server", c.server, "reason", "no_call_in_flight")
+		return nil, fmt.Errorf("sampling is only available while one of this server's tools is running")
+	}
+	g := call.grant
+	if !g.allow(time.Now()) {
+		slog.Warn("mcp.sampling.rejected", "server", c.server, "reason", "rate_limited", "limit", g.policy.MaxRequestsPerHour)
+		return nil, fmt.Errorf("sampling rate limit reached (%d requests per hour)", g.policy.MaxRequestsPerHour)
+	}
+
+	sreq := toSampleRequest(c.server, req.CreateMessageParams, g.policy)
+	if len(sreq.Messages) == 0 {
+		return nil, fmt.Errorf("sampling request has no messages")
+	}
+
+	// The call context carries the tenant and user the agent is running for.
+	res, err := g.sampler.Sample(call.ctx, sreq)
+	if err != nil {
+		slog.Warn("mcp.sampling.failed", "server", c.server, "error", err)
+		return nil, err
+	}
+	slog.Info("mcp.sampling.completed", "server", c.server, "model", res.Model, "max_tokens", sreq.MaxTokens)
+
+	return &mcpgo.CreateMessageResult{
+		SamplingMessage: mcpgo.SamplingMessage{
+			Role:    mcpgo.RoleAssistant,
+			Content: mcpgo.NewTextContent(res.Content),
+		},
+		Model:      res.Model,
+		StopReason: res.StopReason,
+	}, nil
+}
+
+// toSampleRequest converts MCP sampling params, capping tokens at the policy
+// limit. Non-text content is replaced by a placeholder.
+func toSampleRequest(server string, p mcpgo.CreateMessageParams, policy store.MCPSamplingPolicy) SampleRequest {
+	req := SampleRequest{
+		Server:       server,
+		SystemPrompt: p.SystemPrompt,
+		MaxTokens:    policy.MaxTokens,
+		Temperature:  p.Temperature,
+		Model:        policy.Model,
+	}
+	if p.MaxTokens > 0 && p.MaxTokens < req.MaxTokens {
+		req.MaxTokens = p.MaxTokens
+	}
+	if p.ModelPreferences != nil {
+		for _, h := range p.ModelPreferences.Hints {
+			if h.Name != "" {
+				req.ModelHints = append(req.ModelHints, h.Name)
+			}
+		}
+	}
+	for _, m := range p.Messages {
+		role := string(m.Role)
+		if role != string(mcpgo.RoleAssistant) {
+			role = string(mcpgo.RoleUser)
+		}
+		req.Messages = append(req.Messages, SampleMessage{Role: role, Content: samplingText(m.Content)})
+	}
+	return req
+}
+
+func samplingText(content any) string {
+	switch v := content.(type) {
+	case mcpgo.TextContent:
+		return v.Text
+	case *mcpgo.TextContent:
+		return v.Text
+	case string:
+		return v
+	case nil:
+		return ""
+	default:
+		kind := strings.TrimPrefix(fmt.Sprintf("%T", content), "*")
+		return fmt.Sprintf("[%s omitted]", strings.TrimPrefix(kind, "mcp."))
+	}
+}
+
+// samplingSupported reports whether a transport can carry server-initiated
+// requests. The SSE client transport cannot.
+func samplingSupported(transportType string) bool {
+	return transportType == "stdio" || transportType == "streamable-http"
+}
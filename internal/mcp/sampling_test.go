@@ -0,0 +1,138 @@
+package mcp
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	mcpgo "github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/nextlevelbuilder/goclaw/internal/store"
+)
+
+type fakeSampler struct {
+	got  []SampleRequest
+	ctxs []context.Context
+}
+
+func (f *fakeSampler) Sample(ctx context.Context, req SampleRequest) (*SampleResult, error) {
+	f.got = append(f.got, req)
+	f.ctxs = append(f.ctxs, ctx)
+	return &SampleResult{Content: "answer", Model: "m1", StopReason: "endTurn"}, nil
+}
+
+func samplingRequest(maxTokens int, msgs ...mcpgo.SamplingMessage) mcpgo.CreateMessageRequest {
+	req := mcpgo.CreateMessageRequest{}
+	req.MaxTokens = maxTokens
+	req.Messages = msgs
+	return req
+}
+
+func TestSamplingGrant_HourlyLimit(t *testing.T) {
+	g := newSamplingGrant(nil, store.MCPSamplingPolicy{Enabled: true, MaxRequestsPerHour: 2})
+	now := time.Now()
+	if !g.allow(now) || !g.allow(now.Add(time.Minute)) {
+		t.Fatal("first two requests should be allowed")
+	}
+	if g.allow(now.Add(2 * time.Minute)) {
+		t.Error("third request within the hour should be refused")
+	}
+	if !g.allow(now.Add(time.Hour + time.Second)) {
+		t.Error("request after the window slides should be allowed")
+	}
+}
+
+func TestToSampleRequest_CapsTokensAndConvertsContent(t *testing.T) {
+	policy := store.MCPSamplingPolicy{MaxTokens: 500, Model: "cheap"}
+	req := samplingRequest(4000,
+		mcpgo.SamplingMessage{Role: mcpgo.RoleUser, Content: mcpgo.NewTextContent("hi")},
+		mcpgo.SamplingMessage{Role: mcpgo.RoleAssistant, Content: mcpgo.NewImageContent("AAAA", "image/png")},
+	)
+	got := toSampleRequest("srv", req.CreateMessageParams, policy)
+	if got.MaxTokens != 500 {
+		t.Errorf("MaxTokens = %d, want policy cap 500", got.MaxTokens)
+	}
+	if got.Model != "cheap" || got.Server != "srv" {
+		t.Errorf("model/server = %q/%q", got.Model, got.Server)
+	}
+	if len(got.Messages) != 2 || got.Messages[0].Content != "hi" || got.Messages[1].Role != "assistant" {
+		t.Fatalf("messages = %+v", got.Messages)
+	}
+	if !strings.Contains(got.Messages[1].Content, "omitted") {
+		t.Errorf("image content = %q, want placeholder", got.Messages[1].Content)
+	}
+
+	got = toSampleRequest("srv", samplingRequest(100).CreateMessageParams, policy)
+	if got.MaxTokens != 100 {
+		t.Errorf("MaxTokens = %d, want server's smaller request 100", got.MaxTokens)
+	}
+}
+
+func TestCallTracker_RoutesToInflightCall(t *testing.T) {
+	ct := &callTracker{server: "srv"}
+	req := samplingRequest(10, mcpgo.SamplingMessage{Role: mcpgo.RoleUser, Content: mcpgo.NewTextContent("q")})
+
+	if _, err := ct.CreateMessage(context.Background(), req); err == nil {
+		t.Fatal("sampling without a call in flight should be refused")
+	}
+
+	fs := &fakeSampler{}
+	grant := newSamplingGrant(fs, store.MCPSamplingPolicy{Enabled: true, MaxRequestsPerHour: 1})
+	type ctxKey struct{}
+	callCtx := context.WithValue(context.Background(), ctxKey{}, "agent-call")
+	end := ct.begin(callCtx, grant)
+
+	res, err := ct.CreateMessage(context.Background(), req)
+	if err != nil {
+		t.Fatalf("CreateMessage: %v", err)
+	}
+	if text, ok := res.Content.(mcpgo.TextContent); !ok || text.Text != "answer" || res.Model != "m1" {
+		t.Errorf("result = %+v", res)
+	}
+	if len(fs.ctxs) != 1 || fs.ctxs[0].Value(ctxKey{}) != "agent-call" {
+		t.Error("sampler should run with the in-flight call's context")
+	}
+	if _, err := ct.CreateMessage(context.Background(), req); err == nil {
+		t.Error("second request should hit the hourly limit")
+	}
+
+	end()
+	if _, ok := ct.current(); ok {
+		t.Error("call should be removed after end")
+	}
+}
+
+func TestSamplingPoolKey_PerAgent(t *testing.T) {
+	tenant, agentA, agentB := uuid.New(), uuid.New(), uuid.New()
+	shared := samplingPoolKey(tenant, "srv", uuid.Nil)
+	keyA := samplingPoolKey(tenant, "srv", agentA)
+	keyB := samplingPoolKey(tenant, "srv", agentB)
+	if shared != poolKey(tenant, "srv") || keyA == shared || keyA == keyB {
+		t.Fatalf("keys not distinct: shared=%q a=%q b=%q", shared, keyA, keyB)
+	}
+
+	p := &Pool{servers: make(map[string]*poolEntry), slot: make(chan struct{}, 4)}
+	other := samplingPoolKey(tenant, "srv2", agentA)
+	for _, key := range []string{shared, keyA, keyB, other} {
+		p.servers[key] = &poolEntry{state: &serverState{}}
+	}
+	p.Evict(tenant, "srv")
+	if len(p.servers) != 1 || p.servers[other] == nil {
+		t.Errorf("after Evict, servers = %v; want only %q", p.servers, other)
+	}
+}
+
+func TestParseMCPGrantCapabilities(t *testing.T) {
+	c := store.ParseMCPGrantCapabilities([]byte(`{"resources":true,"sampling":{"enabled":true,"max_tokens":256}}`))
+	if !c.Resources || c.Prompts || !c.SamplingEnabled() || c.Sampling.MaxTokens != 256 {
+		t.Errorf("caps = %+v", c)
+	}
+	if c := store.ParseMCPGrantCapabilities(nil); c.Resources || c.SamplingEnabled() {
+		t.Errorf("empty overrides should grant nothing: %+v", c)
+	}
+	if c := store.ParseMCPGrantCapabilities([]byte(`not json`)); c.Prompts {
+		t.Errorf("invalid overrides should grant nothing: %+v", c)
+	}
+}
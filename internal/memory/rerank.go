@@ -0,0 +1,301 @@
+package memory
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"strings"
+
+	"github.com/nextlevelbuilder/goclaw/internal/config"
+	"github.com/nextlevelbuilder/goclaw/internal/providers"
+)
+
+// rerankPassageChars caps each passage sent to an LLM reranker.
+const rerankPassageChars = 1000
+
+// credentialed is implemented by providers that expose their API key and
+// base URL (OpenAI-compatible ones); the cross-encoder reuses them.
+type credentialed interface {
+	APIKey() string
+	APIBase() string
+}
+
+const paraphrasePrompt = `You rewrite search queries for a personal notes search engine.
+Write %d alternative phrasings of the user's query that could match notes about the same thing: use synonyms, related terms and different wording. Keep the query's language.
+Reply with one query per line and nothing else — no numbering, no quotes.`
+
+const hydePrompt = `You help a notes search engine find relevant notes.
+Write a short passage (2-4 sentences) as it might appear in the user's notes that answers the query. Invent plausible specifics if needed. Keep the query's language.
+Reply with the passage only.`
+
+const rerankPrompt = `You judge search results for relevance.
+For each numbered passage, rate how well it answers or relates to the query, from 0 (unrelated) to 10 (directly answers it).
+Reply with JSON only: {"scores": [<score for passage 1>, <score for passage 2>, ...]} with exactly one number per passage, in order.`
+
+// expandQuery asks an LLM for alternative queries: paraphrases, or a
+// hypothetical answer passage (HyDE) that is searched for instead of the
+// question. The original query is not included.
+func (r *Retriever) expandQuery(ctx context.Context, query string, cfg *config.MemoryRetrievalConfig) ([]string, error) {
+	var system string
+	n := 1
+	switch cfg.QueryExpansion {
+	case "paraphrase":
+		n = cfg.ExpansionCount
+		if n <= 0 {
+			n = defaultExpansionCount
+		}
+		n = min(n, maxExpansionCount)
+		system = fmt.Sprintf(paraphrasePrompt, n)
+	case "hyde":
+		system = hydePrompt
+	default:
+		return nil, fmt.Errorf("unknown query_expansion %q", cfg.QueryExpansion)
+	}
+
+	out, err := r.chat(ctx, cfg.ExpansionProvider, cfg.ExpansionModel, system, query, 400)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.QueryExpansion == "hyde" {
+		if out = strings.TrimSpace(out); out == "" {
+			return nil, fmt.Errorf("empty hypothetical passage")
+		}
+		return []string{out}, nil
+	}
+	var queries []string
+	for _, line := range strings.Split(out, "\n") {
+		line = strings.Trim(strings.TrimSpace(line), `"-*•`)
+		line = strings.TrimSpace(line)
+		if line == "" || strings.EqualFold(line, query) {
+			continue
+		}
+		queries = append(queries, line)
+		if len(queries) == n {
+			break
+		}
+	}
+	if len(queries) == 0 {
+		return nil, fmt.Errorf("no paraphrases in response")
+	}
+	return queries, nil
+}
+
+// rerank replaces the candidates' scores with reranker relevance in [0,1].
+// On error the scores are left untouched.
+func (r *Retriever) rerank(ctx context.Context, query string, cands []*candidate, cfg *config.MemoryRetrievalConfig) error {
+	docs := make([]string, len(cands))
+	for i, c := range cands {
+		docs[i] = c.result.Snippet
+	}
+	var scores []float64
+	var err error
+	switch cfg.Rerank {
+	case "llm":
+		scores, err = r.llmRerank(ctx, query, docs, cfg)
+	case "cross_encoder":
+		scores, err = r.crossEncoderRerank(ctx, query, docs, cfg)
+	default:
+		err = fmt.Errorf("unknown rerank %q", cfg.Rerank)
+	}
+	if err != nil {
+		return err
+	}
+	for i, c := range cands {
+		c.result.Score = scores[i]
+		c.scores[StageRerank] = scores[i]
+	}
+	return nil
+}
+
+func (r *Retriever) llmRerank(ctx context.Context, query string, docs []string, cfg *config.MemoryRetrievalConfig) ([]float64, error) {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Query: %s\n", query)
+	for i, d := range docs {
+		if len(d) > rerankPassageChars {
+			d = d[:rerankPassageChars] + "…"
+		}
+		fmt.Fprintf(&sb, "\n[%d]\n%s\n", i+1, d)
+	}
+	out, err := r.chat(ctx, cfg.RerankProvider, cfg.RerankModel, rerankPrompt, sb.String(), 20+8*len(docs))
+	if err != nil {
+		return nil, err
+	}
+	return parseLLMScores(out, len(docs))
+}
+
+// parseLLMScores reads {"scores": [...]} from an LLM reply, tolerating code
+// fences and surrounding prose, and scales 0-10 scores to 0-1.
+func parseLLMScores(out string, n int) ([]float64, error) {
+	start, end := strings.Index(out, "{"), strings.LastIndex(out, "}")
+	if start < 0 || end < start {
+		return nil, fmt.Errorf("no JSON object in reranker reply")
+	}
+	var parsed struct {
+		Scores []float64 `json:"scores"`
+	}
+	if err := json.Unmarshal([]byte(out[start:end+1]), &parsed); err != nil {
+		return nil, fmt.Errorf("parse reranker reply: %w", err)
+	}
+	if len(parsed.Scores) != n {
+		return nil, fmt.Errorf("reranker returned %d scores for %d passages", len(parsed.Scores), n)
+	}
+	for i, s := range parsed.Scores {
+		parsed.Scores[i] = math.Max(0, math.Min(s, 10)) / 10
+	}
+	return parsed.Scores, nil
+}
+
+// crossEncoderRerank calls a /rerank endpoint in the Cohere/Jina/Voyage
+// shape, which most hosted and self-hosted rerankers accept. Credentials come
+// from RerankProvider when it is an OpenAI-compatible provider of the caller's
+// tenant. RerankAPIBase overrides its base URL; it must pass the SSRF check,
+// and the provider's key is only sent to the provider's own base.
+func (r *Retriever) crossEncoderRerank(ctx context.Context, query string, docs []string, cfg *config.MemoryRetrievalConfig) ([]float64, error) {
+	base, key := cfg.RerankAPIBase, ""
+	if base != "" {
+		if r.checkURL == nil {
+			return nil, fmt.Errorf("rerank_api_base is not allowed")
+		}
+		if err := r.checkURL(base); err != nil {
+			return nil, fmt.Errorf("rerank_api_base: %w", err)
+		}
+	}
+	if cfg.RerankProvider != "" && r.registry != nil {
+		p, err := r.registry.GetOwn(ctx, cfg.RerankProvider)
+		if err != nil {
+			return nil, fmt.Errorf("rerank provider %q: %w", cfg.RerankProvider, err)
+		}
+		if c, ok := p.(credentialed); ok {
+			own := c.APIBase()
+			if base == "" {
+				base = own
+			}
+			if strings.TrimRight(base, "/") == strings.TrimRight(own, "/") {
+				key = c.APIKey()
+			}
+		}
+	}
+	if base == "" {
+		return nil, fmt.Errorf("cross_encoder rerank needs rerank_api_base or an OpenAI-compatible rerank_provider")
+	}
+
+	body, _ := json.Marshal(map[string]any{
+		"model":            cfg.RerankModel,
+		"query":            query,
+		"documents":        docs,
+		"top_n":            len(docs),
+		"return_documents": false,
+	})
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimRight(base, "/")+"/rerank", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if key != "" {
+		req.Header.Set("Authorization", "Bearer "+key)
+	}
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("rerank request failed")
+	}
+	defer resp.Body.Close()
+	raw, err := io.ReadAll(io.LimitReader(resp.Body, 4<<20))
+	if err != nil {
+		return nil, fmt.Errorf("read rerank response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		// The body is not echoed: it comes from a configurable host.
+		return nil, fmt.Errorf("rerank API error %d", resp.StatusCode)
+	}
+	return parseRerankResponse(raw, len(docs))
+}
+
+type rerankEntry struct {
+	Index          int      `json:"index"`
+	RelevanceScore *float64 `json:"relevance_score"`
+	Score          *float64 `json:"score"`
+}
+
+// parseRerankResponse accepts {"results": [...]} (Cohere, Jina), {"data":
+// [...]} (Voyage) or a bare array of {index, relevance_score|score}.
+// Raw logits outside [0,1] are squashed with a sigmoid. Documents the
+// endpoint left out score 0.
+func parseRerankResponse(raw []byte, n int) ([]float64, error) {
+	var entries []rerankEntry
+	if err := json.Unmarshal(raw, &entries); err != nil {
+		var wrapped struct {
+			Results []rerankEntry `json:"results"`
+			Data    []rerankEntry `json:"data"`
+		}
+		if err := json.Unmarshal(raw, &wrapped); err != nil {
+			return nil, fmt.Errorf("parse rerank response: %w", err)
+		}
+		entries = wrapped.Results
+		if len(entries) == 0 {
+			entries = wrapped.Data
+		}
+	}
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("rerank response has no results")
+	}
+	scores := make([]float64, n)
+	present := make([]bool, n)
+	logits := false
+	for _, e := range entries {
+		if e.Index < 0 || e.Index >= n {
+			return nil, fmt.Errorf("rerank result index %d out of range", e.Index)
+		}
+		s := e.RelevanceScore
+		if s == nil {
+			s = e.Score
+		}
+		if s == nil {
+			return nil, fmt.Errorf("rerank result %d has no score", e.Index)
+		}
+		scores[e.Index], present[e.Index] = *s, true
+		if *s < 0 || *s > 1 {
+			logits = true
+		}
+	}
+	if logits {
+		for i, s := range scores {
+			if present[i] {
+				scores[i] = 1 / (1 + math.Exp(-s))
+			}
+		}
+	}
+	return scores, nil
+}
+
+// chat runs a single-turn completion on the named provider.
+func (r *Retriever) chat(ctx context.Context, providerName, model, system, user string, maxTokens int) (string, error) {
+	if providerName == "" {
+		return "", fmt.Errorf("no provider configured")
+	}
+	if r.registry == nil {
+		return "", fmt.Errorf("no provider registry")
+	}
+	p, err := r.registry.GetOwn(ctx, providerName)
+	if err != nil {
+		return "", fmt.Errorf("provider %q: %w", providerName, err)
+	}
+	if model == "" {
+		model = p.DefaultModel()
+	}
+	resp, err := p.Chat(ctx, providers.ChatRequest{
+		Messages: []providers.Message{
+			{Role: "system", Content: system},
+			{Role: "user", Content: user},
+		},
+		Model:   model,
+		Options: map[string]any{"max_tokens": maxTokens, "temperature": 0.2},
+	})
+	if err != nil {
+		return "", err
+	}
+	return resp.Content, nil
+}
@@ -0,0 +1,380 @@
+package memory
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"path"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/nextlevelbuilder/goclaw/internal/config"
+	"github.com/nextlevelbuilder/goclaw/internal/providers"
+	"github.com/nextlevelbuilder/goclaw/internal/store"
+)
+
+// Retrieval stage names, in pipeline order. All but StageExpand key
+// CandidateTrace.Scores.
+const (
+	StageExpand    = "expand"
+	StageRetrieve  = "retrieve"
+	StageRerank    = "rerank"
+	StageRecency   = "recency"
+	StagePathBoost = "path_boost"
+	StageMMR       = "mmr"
+)
+
+const (
+	defaultMaxResults          = 6
+	defaultCandidateMultiplier = 4
+	maxCandidates              = 50
+	defaultExpansionCount      = 2
+	maxExpansionCount          = 5
+	defaultRecencyWeight       = 0.3
+)
+
+// Retriever runs memory searches through the post-retrieval pipeline
+// configured per agent: query expansion, reranking, recency decay, path
+// boosts and MMR diversification. A stage that is unconfigured is skipped;
+// one that fails is skipped and noted in the trace, so a broken reranker
+// degrades to plain hybrid results rather than failing the search.
+type Retriever struct {
+	store    store.MemoryStore
+	registry *providers.Registry
+	client   *http.Client
+	now      func() time.Time
+	checkURL func(string) error // validates rerank_api_base; nil rejects custom bases
+}
+
+// NewRetriever creates a retriever over the given memory store. registry
+// resolves the LLM and reranker providers named in the config; it may be
+// nil, which disables the stages that need one.
+func NewRetriever(s store.MemoryStore, registry *providers.Registry) *Retriever {
+	return &Retriever{
+		store:    s,
+		registry: registry,
+		client:   &http.Client{Timeout: 30 * time.Second},
+		now:      time.Now,
+	}
+}
+
+// SetURLCheck sets the SSRF check applied to a configured rerank_api_base
+// before it is called. Without one, custom rerank bases are refused.
+func (r *Retriever) SetURLCheck(check func(string) error) {
+	r.checkURL = check
+}
+
+// RetrievalTrace records what each stage did to a search, for the debug
+// mode of the memory search API.
+type RetrievalTrace struct {
+	Queries    []string         `json:"queries"` // original query first, then expansions
+	Stages     []StageTrace     `json:"stages"`
+	Candidates []CandidateTrace `json:"candidates"` // every candidate, best first
+}
+
+// StageTrace describes one pipeline stage run.
+type StageTrace struct {
+	Name       string `json:"name"`
+	DurationMS int64  `json:"duration_ms"`
+	Skipped    bool   `json:"skipped,omitempty"`
+	Note       string `json:"note,omitempty"`
+	Error      string `json:"error,omitempty"`
+}
+
+// CandidateTrace shows a candidate's score after each stage that ran.
+type CandidateTrace struct {
+	Path      string             `json:"path"`
+	StartLine int                `json:"start_line"`
+	Scope     string             `json:"scope,omitempty"`
+	UpdatedAt int64              `json:"updated_at,omitempty"`
+	Query     string             `json:"query,omitempty"` // query that retrieved it best, when expanded
+	Scores    map[string]float64 `json:"scores"`
+	Final     float64            `json:"final"`
+	Rank      int                `json:"rank"` // 1-based position in the results; 0 = not returned
+}
+
+type candidate struct {
+	result store.MemorySearchResult
+	query  string
+	scores map[string]float64
+	rank   int
+}
+
+// RetrievalEnabled reports whether cfg turns on any post-retrieval stage.
+func RetrievalEnabled(cfg *config.MemoryRetrievalConfig) bool {
+	return cfg != nil && (cfg.QueryExpansion != "" || cfg.Rerank != "" || cfg.RecencyHalfLifeDays > 0 ||
+		len(cfg.PathBoosts) > 0 || cfg.MMRLambda > 0)
+}
+
+// Search retrieves a candidate pool from the store, runs it through the
+// stages enabled in cfg and returns the top opts.MaxResults results along
+// with a trace of every stage. With a nil or empty cfg it is a plain store
+// search.
+func (r *Retriever) Search(ctx context.Context, query, agentID, userID string, opts store.MemorySearchOptions, cfg *config.MemoryRetrievalConfig) ([]store.MemorySearchResult, *RetrievalTrace, error) {
+	trace := &RetrievalTrace{Queries: []string{query}}
+	limit := opts.MaxResults
+	if limit <= 0 {
+		limit = defaultMaxResults
+	}
+	if !RetrievalEnabled(cfg) {
+		start := time.Now()
+		results, err := r.store.Search(ctx, query, agentID, userID, opts)
+		if err != nil {
+			return nil, nil, err
+		}
+		trace.addStage(StageRetrieve, start, "", nil)
+		cands := make([]*candidate, len(results))
+		for i, res := range results {
+			cands[i] = &candidate{result: res, scores: map[string]float64{StageRetrieve: res.Score}, rank: i + 1}
+		}
+		trace.Candidates = candidateTraces(cands, len(trace.Queries) > 1)
+		return results, trace, nil
+	}
+
+	// Query expansion happens before retrieval; its trace entry is kept
+	// ahead of it so stages read in execution order.
+	if cfg.QueryExpansion != "" {
+		start := time.Now()
+		expanded, err := r.expandQuery(ctx, query, cfg)
+		note := ""
+		if err == nil {
+			trace.Queries = append(trace.Queries, expanded...)
+			note = cfg.QueryExpansion
+		}
+		trace.addStage(StageExpand, start, note, err)
+	}
+
+	start := time.Now()
+	cands, err := r.retrieve(ctx, trace.Queries, agentID, userID, opts, limit*candidateMultiplier(cfg))
+	if err != nil {
+		return nil, nil, err
+	}
+	trace.addStage(StageRetrieve, start, "", nil)
+
+	if cfg.Rerank != "" && len(cands) > 0 {
+		start := time.Now()
+		err := r.rerank(ctx, query, cands, cfg)
+		trace.addStage(StageRerank, start, cfg.Rerank, err)
+	}
+	if cfg.RecencyHalfLifeDays > 0 {
+		start := time.Now()
+		applyRecency(cands, cfg, r.now())
+		trace.addStage(StageRecency, start, "", nil)
+	}
+	if len(cfg.PathBoosts) > 0 {
+		start := time.Now()
+		applyPathBoosts(cands, cfg.PathBoosts)
+		trace.addStage(StagePathBoost, start, "", nil)
+	}
+
+	sortCandidates(cands)
+	var selected []*candidate
+	if cfg.MMRLambda > 0 {
+		start := time.Now()
+		selected = selectMMR(cands, limit, min(cfg.MMRLambda, 1))
+		trace.addStage(StageMMR, start, "", nil)
+	} else {
+		selected = cands[:min(limit, len(cands))]
+	}
+
+	results := make([]store.MemorySearchResult, len(selected))
+	for i, c := range selected {
+		c.rank = i + 1
+		results[i] = c.result
+	}
+	trace.Candidates = candidateTraces(cands, len(trace.Queries) > 1)
+	return results, trace, nil
+}
+
+func (t *RetrievalTrace) addStage(name string, start time.Time, note string, err error) {
+	st := StageTrace{Name: name, DurationMS: time.Since(start).Milliseconds(), Note: note}
+	if err != nil {
+		st.Skipped = true
+		st.Error = err.Error()
+	}
+	t.Stages = append(t.Stages, st)
+}
+
+func candidateMultiplier(cfg *config.MemoryRetrievalConfig) int {
+	if cfg.CandidateMultiplier > 0 {
+		return cfg.CandidateMultiplier
+	}
+	return defaultCandidateMultiplier
+}
+
+// retrieve searches the store once per query and fuses the results by
+// chunk, keeping each chunk's best score. An expanded query that fails is
+// ignored; only the original query's error is returned.
+func (r *Retriever) retrieve(ctx context.Context, queries []string, agentID, userID string, opts store.MemorySearchOptions, pool int) ([]*candidate, error) {
+	type key struct {
+		path      string
+		startLine int
+	}
+	byKey := make(map[key]*candidate)
+	var cands []*candidate
+	opts.MaxResults = min(pool, maxCandidates)
+	for i, q := range queries {
+		results, err := r.store.Search(ctx, q, agentID, userID, opts)
+		if err != nil {
+			if i == 0 {
+				return nil, err
+			}
+			continue
+		}
+		for _, res := range results {
+			k := key{res.Path, res.StartLine}
+			if c, ok := byKey[k]; ok {
+				if res.Score > c.result.Score {
+					c.result, c.query = res, q
+				}
+				continue
+			}
+			c := &candidate{result: res, query: q}
+			byKey[k] = c
+			cands = append(cands, c)
+		}
+	}
+	for _, c := range cands {
+		c.scores = map[string]float64{StageRetrieve: c.result.Score}
+	}
+	sortCandidates(cands)
+	return cands, nil
+}
+
+// applyRecency decays the part of each score given by RecencyWeight with
+// the age of the source document: a document one half-life old keeps half
+// of that part. Candidates with an unknown age are left as they are.
+func applyRecency(cands []*candidate, cfg *config.MemoryRetrievalConfig, now time.Time) {
+	w := cfg.RecencyWeight
+	if w <= 0 {
+		w = defaultRecencyWeight
+	}
+	w = min(w, 1)
+	for _, c := range cands {
+		if c.result.UpdatedAt > 0 {
+			ageDays := max(now.Sub(time.UnixMilli(c.result.UpdatedAt)).Hours()/24, 0)
+			c.result.Score *= (1 - w) + w*math.Pow(0.5, ageDays/cfg.RecencyHalfLifeDays)
+		}
+		c.scores[StageRecency] = c.result.Score
+	}
+}
+
+// applyPathBoosts multiplies each score by the boost of every pattern its
+// path matches. A pattern matches as a path.Match glob or as a prefix.
+func applyPathBoosts(cands []*candidate, boosts map[string]float64) {
+	for _, c := range cands {
+		for pattern, boost := range boosts {
+			if boost <= 0 {
+				continue
+			}
+			if ok, _ := path.Match(pattern, c.result.Path); ok || strings.HasPrefix(c.result.Path, pattern) {
+				c.result.Score *= boost
+			}
+		}
+		c.scores[StagePathBoost] = c.result.Score
+	}
+}
+
+// selectMMR picks up to limit candidates by maximal marginal relevance:
+// each pick maximizes lambda*relevance - (1-lambda)*max similarity to the
+// picks so far, so near-duplicate chunks make way for different ones.
+// Relevance is the score normalized to the best candidate; similarity is
+// the Jaccard overlap of the snippets' word sets.
+func selectMMR(cands []*candidate, limit int, lambda float64) []*candidate {
+	if len(cands) == 0 {
+		return nil
+	}
+	top := cands[0].result.Score
+	if top <= 0 {
+		top = 1
+	}
+	words := make([]map[string]struct{}, len(cands))
+	for i, c := range cands {
+		words[i] = wordSet(c.result.Snippet)
+	}
+	picked := make([]bool, len(cands))
+	var selected []*candidate
+	var selectedIdx []int
+	for len(selected) < limit && len(selected) < len(cands) {
+		best, bestScore := -1, math.Inf(-1)
+		for i, c := range cands {
+			if picked[i] {
+				continue
+			}
+			sim := 0.0
+			for _, j := range selectedIdx {
+				sim = max(sim, jaccard(words[i], words[j]))
+			}
+			score := lambda*c.result.Score/top - (1-lambda)*sim
+			if score > bestScore {
+				best, bestScore = i, score
+			}
+		}
+		picked[best] = true
+		cands[best].scores[StageMMR] = bestScore
+		selected = append(selected, cands[best])
+		selectedIdx = append(selectedIdx, best)
+	}
+	return selected
+}
+
+func wordSet(s string) map[string]struct{} {
+	set := make(map[string]struct{})
+	for _, w := range strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	}) {
+		set[w] = struct{}{}
+	}
+	return set
+}
+
+func jaccard(a, b map[string]struct{}) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	inter := 0
+	for w := range a {
+		if _, ok := b[w]; ok {
+			inter++
+		}
+	}
+	return float64(inter) / float64(len(a)+len(b)-inter)
+}
+
+// sortCandidates orders candidates by score, best first, keeping the
+// store's order for ties.
+func sortCandidates(cands []*candidate) {
+	sort.SliceStable(cands, func(i, j int) bool { return cands[i].result.Score > cands[j].result.Score })
+}
+
+// candidateTraces lists returned candidates in result order, followed by
+// the rest by score.
+func candidateTraces(cands []*candidate, expanded bool) []CandidateTrace {
+	ordered := make([]*candidate, len(cands))
+	copy(ordered, cands)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		ri, rj := ordered[i].rank, ordered[j].rank
+		if ri == 0 || rj == 0 {
+			return ri != 0 && rj == 0
+		}
+		return ri < rj
+	})
+	out := make([]CandidateTrace, len(ordered))
+	for i, c := range ordered {
+		out[i] = CandidateTrace{
+			Path:      c.result.Path,
+			StartLine: c.result.StartLine,
+			Scope:     c.result.Scope,
+			UpdatedAt: c.result.UpdatedAt,
+			Scores:    c.scores,
+			Final:     c.result.Score,
+			Rank:      c.rank,
+		}
+		if expanded {
+			out[i].Query = c.query
+		}
+	}
+	return out
+}
@@ -0,0 +1,304 @@
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/nextlevelbuilder/goclaw/internal/config"
+	"github.com/nextlevelbuilder/goclaw/internal/providers"
+	"github.com/nextlevelbuilder/goclaw/internal/store"
+)
+
+// fakeMemoryStore serves canned results per query; other methods are unused.
+type fakeMemoryStore struct {
+	store.MemoryStore
+	results map[string][]store.MemorySearchResult
+	queries []string
+	lastMax int
+}
+
+func (f *fakeMemoryStore) Search(_ context.Context, query, _, _ string, opts store.MemorySearchOptions) ([]store.MemorySearchResult, error) {
+	f.queries = append(f.queries, query)
+	f.lastMax = opts.MaxResults
+	return f.results[query], nil
+}
+
+// fakeChatProvider answers every chat with reply.
+type fakeChatProvider struct {
+	name  string
+	reply string
+}
+
+func (p *fakeChatProvider) Chat(context.Context, providers.ChatRequest) (*providers.ChatResponse, error) {
+	return &providers.ChatResponse{Content: p.reply}, nil
+}
+func (p *fakeChatProvider) ChatStream(ctx context.Context, req providers.ChatRequest, _ func(providers.StreamChunk)) (*providers.ChatResponse, error) {
+	return p.Chat(ctx, req)
+}
+func (p *fakeChatProvider) DefaultModel() string { return "fake" }
+func (p *fakeChatProvider) Name() string         { return p.name }
+
+func newTestRegistry(ps ...providers.Provider) *providers.Registry {
+	reg := providers.NewRegistry(store.TenantIDFromContext)
+	for _, p := range ps {
+		reg.Register(p)
+	}
+	return reg
+}
+
+func res(path string, line int, score float64, snippet string) store.MemorySearchResult {
+	return store.MemorySearchResult{Path: path, StartLine: line, Score: score, Snippet: snippet, Source: "memory"}
+}
+
+func paths(results []store.MemorySearchResult) []string {
+	out := make([]string, len(results))
+	for i, r := range results {
+		out[i] = r.Path
+	}
+	return out
+}
+
+func TestRetriever_PlainSearch(t *testing.T) {
+	fs := &fakeMemoryStore{results: map[string][]store.MemorySearchResult{
+		"q": {res("a.md", 1, 0.9, "alpha"), res("b.md", 1, 0.5, "beta")},
+	}}
+	r := NewRetriever(fs, nil)
+	results, trace, err := r.Search(context.Background(), "q", "agent", "", store.MemorySearchOptions{MaxResults: 3}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := paths(results); strings.Join(got, ",") != "a.md,b.md" {
+		t.Errorf("results = %v", got)
+	}
+	if fs.lastMax != 3 {
+		t.Errorf("store MaxResults = %d, want the caller's 3 without a pipeline", fs.lastMax)
+	}
+	if len(trace.Stages) != 1 || trace.Stages[0].Name != StageRetrieve || trace.Candidates[1].Rank != 2 {
+		t.Errorf("trace = %+v", trace)
+	}
+}
+
+func TestRetriever_RecencyAndPathBoost(t *testing.T) {
+	now := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+	old := res("notes/old.md", 1, 1.0, "old plan")
+	old.UpdatedAt = now.AddDate(0, 0, -60).UnixMilli()
+	fresh := res("notes/new.md", 1, 0.9, "new plan")
+	fresh.UpdatedAt = now.UnixMilli()
+	fs := &fakeMemoryStore{results: map[string][]store.MemorySearchResult{
+		"plan": {old, fresh, res("archive/x.md", 1, 0.95, "archived plan")},
+	}}
+	r := NewRetriever(fs, nil)
+	r.now = func() time.Time { return now }
+
+	cfg := &config.MemoryRetrievalConfig{
+		RecencyHalfLifeDays: 30,
+		RecencyWeight:       0.5,
+		PathBoosts:          map[string]float64{"archive/": 0.5},
+	}
+	results, trace, err := r.Search(context.Background(), "plan", "agent", "", store.MemorySearchOptions{MaxResults: 3}, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(paths(results), ","); got != "notes/new.md,notes/old.md,archive/x.md" {
+		t.Errorf("order = %s", got)
+	}
+	// 60 days at a 30-day half-life: 0.5 + 0.5*0.25 = 0.625 of the score.
+	if got := results[1].Score; got < 0.624 || got > 0.626 {
+		t.Errorf("old score = %v, want 0.625", got)
+	}
+	if fs.lastMax != 12 {
+		t.Errorf("candidate pool = %d, want 3*4", fs.lastMax)
+	}
+	c := trace.Candidates[2]
+	if c.Scores[StageRetrieve] != 0.95 || c.Scores[StagePathBoost] != 0.475 || c.Rank != 3 {
+		t.Errorf("archive trace = %+v", c)
+	}
+}
+
+func TestRetriever_MMRDropsNearDuplicates(t *testing.T) {
+	fs := &fakeMemoryStore{results: map[string][]store.MemorySearchResult{
+		"deploy": {
+			res("runbook.md", 1, 1.0, "deploy the api with make deploy then check the health endpoint"),
+			res("runbook.md", 10, 0.98, "deploy the api with make deploy then check the health endpoint again"),
+			res("infra.md", 1, 0.7, "production cluster runs on three nodes behind a load balancer"),
+		},
+	}}
+	r := NewRetriever(fs, nil)
+	cfg := &config.MemoryRetrievalConfig{MMRLambda: 0.5}
+	results, trace, err := r.Search(context.Background(), "deploy", "agent", "", store.MemorySearchOptions{MaxResults: 2}, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 2 || results[0].StartLine != 1 || results[1].Path != "infra.md" {
+		t.Errorf("results = %+v", results)
+	}
+	if last := trace.Candidates[2]; last.Rank != 0 || last.StartLine != 10 {
+		t.Errorf("dropped candidate = %+v", last)
+	}
+}
+
+func TestRetriever_QueryExpansionAndLLMRerank(t *testing.T) {
+	fs := &fakeMemoryStore{results: map[string][]store.MemorySearchResult{
+		"car":        {res("a.md", 1, 0.8, "bought a bike")},
+		"automobile": {res("b.md", 1, 0.6, "new sedan delivered"), res("a.md", 1, 0.9, "bought a bike")},
+	}}
+	reg := newTestRegistry(
+		&fakeChatProvider{name: "expander", reply: "- automobile\ncar\n"},
+		&fakeChatProvider{name: "judge", reply: "```json\n{\"scores\": [2, 9]}\n```"},
+	)
+	r := NewRetriever(fs, reg)
+	cfg := &config.MemoryRetrievalConfig{
+		QueryExpansion: "paraphrase", ExpansionProvider: "expander",
+		Rerank: "llm", RerankProvider: "judge",
+	}
+	results, trace, err := r.Search(context.Background(), "car", "agent", "", store.MemorySearchOptions{MaxResults: 2}, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(trace.Queries, ",") != "car,automobile" {
+		t.Errorf("queries = %v", trace.Queries)
+	}
+	// Fused pool: a.md (0.9 via "automobile") then b.md; the judge prefers b.md.
+	if got := strings.Join(paths(results), ","); got != "b.md,a.md" {
+		t.Errorf("order = %s", got)
+	}
+	if results[0].Score != 0.9 || trace.Candidates[1].Query != "automobile" {
+		t.Errorf("results = %+v, trace = %+v", results, trace.Candidates)
+	}
+}
+
+func TestRetriever_FailedStageIsSkipped(t *testing.T) {
+	fs := &fakeMemoryStore{results: map[string][]store.MemorySearchResult{
+		"q": {res("a.md", 1, 0.9, "alpha"), res("b.md", 1, 0.5, "beta")},
+	}}
+	r := NewRetriever(fs, newTestRegistry())
+	cfg := &config.MemoryRetrievalConfig{Rerank: "llm", RerankProvider: "missing"}
+	results, trace, err := r.Search(context.Background(), "q", "agent", "", store.MemorySearchOptions{}, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 2 || results[0].Path != "a.md" {
+		t.Errorf("results = %+v", results)
+	}
+	st := trace.Stages[len(trace.Stages)-1]
+	if st.Name != StageRerank || !st.Skipped || st.Error == "" {
+		t.Errorf("rerank stage = %+v", st)
+	}
+}
+
+func TestRetriever_CrossEncoder(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/rerank" {
+			http.NotFound(w, r)
+			return
+		}
+		var body struct {
+			Query     string   `json:"query"`
+			Documents []string `json:"documents"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		if body.Query != "q" || len(body.Documents) != 2 {
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+		w.Write([]byte(`{"results":[{"index":1,"relevance_score":0.95},{"index":0,"relevance_score":0.1}]}`))
+	}))
+	defer srv.Close()
+
+	fs := &fakeMemoryStore{results: map[string][]store.MemorySearchResult{
+		"q": {res("a.md", 1, 0.9, "alpha"), res("b.md", 1, 0.5, "beta")},
+	}}
+	r := NewRetriever(fs, nil)
+	cfg := &config.MemoryRetrievalConfig{Rerank: "cross_encoder", RerankAPIBase: srv.URL + "/v1/"}
+
+	// Custom bases are refused until an SSRF check is configured.
+	_, trace, err := r.Search(context.Background(), "q", "agent", "", store.MemorySearchOptions{}, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if st := trace.Stages[len(trace.Stages)-1]; !st.Skipped || !strings.Contains(st.Error, "not allowed") {
+		t.Errorf("rerank stage without URL check = %+v", st)
+	}
+
+	r.SetURLCheck(func(string) error { return nil })
+	results, _, err := r.Search(context.Background(), "q", "agent", "", store.MemorySearchOptions{}, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if results[0].Path != "b.md" || results[0].Score != 0.95 {
+		t.Errorf("results = %+v", results)
+	}
+}
+
+// keyedProvider is an OpenAI-compatible provider exposing its credentials.
+type keyedProvider struct {
+	fakeChatProvider
+	key, base string
+}
+
+func (p *keyedProvider) APIKey() string  { return p.key }
+func (p *keyedProvider) APIBase() string { return p.base }
+
+func TestRetriever_CrossEncoderKeepsProviderKey(t *testing.T) {
+	var gotAuth string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAuth = r.Header.Get("Authorization")
+		http.Error(w, "secret upstream detail", http.StatusTeapot)
+	}))
+	defer srv.Close()
+
+	fs := &fakeMemoryStore{results: map[string][]store.MemorySearchResult{
+		"q": {res("a.md", 1, 0.9, "alpha")},
+	}}
+	reg := newTestRegistry(&keyedProvider{fakeChatProvider: fakeChatProvider{name: "openai"}, key: "sk-master", base: "https://api.example.com/v1"})
+	r := NewRetriever(fs, reg)
+	r.SetURLCheck(func(string) error { return nil })
+
+	// A base other than the provider's own never receives its key, and the
+	// upstream body is not echoed into the trace.
+	cfg := &config.MemoryRetrievalConfig{Rerank: "cross_encoder", RerankProvider: "openai", RerankAPIBase: srv.URL}
+	_, trace, err := r.Search(context.Background(), "q", "agent", "", store.MemorySearchOptions{}, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if gotAuth != "" {
+		t.Errorf("Authorization sent to foreign base: %q", gotAuth)
+	}
+	if st := trace.Stages[len(trace.Stages)-1]; strings.Contains(st.Error, "secret") {
+		t.Errorf("upstream body echoed: %q", st.Error)
+	}
+
+	// Master-tenant providers are not resolved for other tenants.
+	other := store.WithTenantID(context.Background(), uuid.New())
+	_, trace, err = r.Search(other, "q", "agent", "", store.MemorySearchOptions{}, &config.MemoryRetrievalConfig{Rerank: "cross_encoder", RerankProvider: "openai"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if st := trace.Stages[len(trace.Stages)-1]; !strings.Contains(st.Error, "provider not found") {
+		t.Errorf("cross-tenant provider stage = %+v", st)
+	}
+}
+
+func TestParseRerankResponse(t *testing.T) {
+	// TEI-style bare array with raw logits.
+	scores, err := parseRerankResponse([]byte(`[{"index":0,"score":2.0},{"index":2,"score":-2.0}]`), 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if scores[0] < 0.88 || scores[2] > 0.12 || scores[1] != 0 {
+		t.Errorf("scores = %v", scores)
+	}
+	if _, err := parseRerankResponse([]byte(`{"data":[{"index":5,"relevance_score":0.5}]}`), 3); err == nil {
+		t.Error("out-of-range index accepted")
+	}
+	if _, err := parseLLMScores(`{"scores":[1,2]}`, 3); err == nil {
+		t.Error("short score list accepted")
+	}
+}
@@ -0,0 +1,110 @@
+// Package metrics exposes gateway runtime metrics in the Prometheus text
+// format. It imports no other goclaw package, so any package can record into
+// it without import cycles.
+//
+// Counters and histograms are recorded at the call site (LLM calls, tool
+// calls, channel traffic, cache lookups, quota rejections). Point-in-time
+// values owned by other components (lane depth, bus queues, WebSocket clients)
+// are registered as gauge functions by the gateway and read at scrape time.
+//
+// Labels are kept to bounded sets: tenant IDs, provider/model names, tool
+// names and channel instance names. Per-user or per-session labels are never
+// used.
+package metrics
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Default is the registry served on /metrics.
+var Default = NewRegistry()
+
+// Latency buckets in seconds: LLM calls run from sub-second to minutes.
+var (
+	llmBuckets  = []float64{0.25, 0.5, 1, 2, 5, 10, 20, 30, 60, 120, 300}
+	toolBuckets = []float64{0.01, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60, 300}
+)
+
+var (
+	// LLMRequests counts provider calls by outcome ("ok" / "error").
+	LLMRequests = Default.NewCounterVec("goclaw_llm_requests_total",
+		"LLM provider calls by tenant, provider, model and status.",
+		"tenant", "provider", "model", "status")
+
+	// LLMLatency tracks provider call duration.
+	LLMLatency = Default.NewHistogramVec("goclaw_llm_request_duration_seconds",
+		"LLM provider call latency in seconds.", llmBuckets,
+		"provider", "model")
+
+	// LLMTokens counts tokens by kind ("input", "output", "cache_read", "cache_creation", "thinking").
+	LLMTokens = Default.NewCounterVec("goclaw_llm_tokens_total",
+		"LLM tokens by tenant, provider, model and kind.",
+		"tenant", "provider", "model", "kind")
+
+	// ToolCalls counts tool executions by outcome ("ok" / "error").
+	ToolCalls = Default.NewCounterVec("goclaw_tool_calls_total",
+		"Tool executions by tenant, tool and status.",
+		"tenant", "tool", "status")
+
+	// ToolDuration tracks tool execution time.
+	ToolDuration = Default.NewHistogramVec("goclaw_tool_call_duration_seconds",
+		"Tool execution time in seconds.", toolBuckets,
+		"tool")
+
+	// ChannelMessages counts channel traffic. direction is "inbound" or
+	// "outbound"; status is "ok" or "error" (outbound send failures).
+	ChannelMessages = Default.NewCounterVec("goclaw_channel_messages_total",
+		"Channel messages by tenant, channel, direction and status.",
+		"tenant", "channel", "direction", "status")
+
+	// CacheRequests counts cache lookups by result ("hit" / "miss").
+	CacheRequests = Default.NewCounterVec("goclaw_cache_requests_total",
+		"Cache lookups by cache name and result.",
+		"cache", "result")
+
+	// QuotaRejections counts requests refused by per-user quotas.
+	QuotaRejections = Default.NewCounterVec("goclaw_quota_rejections_total",
+		"Requests rejected by per-user quotas, by tenant, channel and window.",
+		"tenant", "channel", "window")
+)
+
+// TenantLabel renders a tenant ID as a label value ("" when unset).
+func TenantLabel(id uuid.UUID) string {
+	if id == uuid.Nil {
+		return ""
+	}
+	return id.String()
+}
+
+// Status maps an error/failed flag to the "ok" / "error" status label.
+func Status(failed bool) string {
+	if failed {
+		return "error"
+	}
+	return "ok"
+}
+
+// ObserveLLMCall records one provider call. Token kinds with zero counts are skipped.
+func ObserveLLMCall(tenant, provider, model string, d time.Duration, failed bool, input, output, cacheRead, cacheCreation, thinking int) {
+	LLMRequests.Inc(tenant, provider, model, Status(failed))
+	LLMLatency.Observe(d.Seconds(), provider, model)
+	for kind, n := range map[string]int{
+		"input":          input,
+		"output":         output,
+		"cache_read":     cacheRead,
+		"cache_creation": cacheCreation,
+		"thinking":       thinking,
+	} {
+		if n > 0 {
+			LLMTokens.Add(float64(n), tenant, provider, model, kind)
+		}
+	}
+}
+
+// ObserveToolCall records one tool execution.
+func ObserveToolCall(tenant, tool string, d time.Duration, failed bool) {
+	ToolCalls.Inc(tenant, tool, Status(failed))
+	ToolDuration.Observe(d.Seconds(), tool)
+}
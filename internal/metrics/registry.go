@@ -0,0 +1,370 @@
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Registry holds metric families and renders them in the Prometheus text
+// exposition format (version 0.0.4).
+type Registry struct {
+	mu       sync.RWMutex
+	families map[string]family
+}
+
+// family is one named metric with HELP/TYPE lines.
+type family interface {
+	help() string
+	kind() string
+	labelNames() []string
+	write(w *bufio.Writer, name string, keep rowFilter)
+}
+
+// rowFilter selects the series of a family to render; nil keeps them all.
+type rowFilter func(labelNames, labelValues []string) bool
+
+// Sample is one gauge value reported by a GaugeFunc.
+type Sample struct {
+	Labels []string // values, in the order of the GaugeFunc's label names
+	Value  float64
+}
+
+// NewRegistry creates an empty registry.
+func NewRegistry() *Registry {
+	return &Registry{families: make(map[string]family)}
+}
+
+func (r *Registry) register(name string, f family) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, dup := r.families[name]; dup {
+		panic("metrics: duplicate registration of " + name)
+	}
+	r.families[name] = f
+}
+
+// NewCounterVec registers a monotonically increasing counter partitioned by labels.
+func (r *Registry) NewCounterVec(name, help string, labels ...string) *CounterVec {
+	c := &CounterVec{helpText: help, labels: labels, values: make(map[string]*counterValue)}
+	r.register(name, c)
+	return c
+}
+
+// NewHistogramVec registers a histogram partitioned by labels. buckets are
+// upper bounds in ascending order; +Inf is implicit.
+func (r *Registry) NewHistogramVec(name, help string, buckets []float64, labels ...string) *HistogramVec {
+	h := &HistogramVec{helpText: help, labels: labels, buckets: buckets, values: make(map[string]*histogramValue)}
+	r.register(name, h)
+	return h
+}
+
+// NewGaugeFunc registers a gauge whose samples are computed at scrape time.
+// Re-registering a name replaces the previous function, so components that are
+// rebuilt (e.g. on config reload) can re-attach their gauges.
+func (r *Registry) NewGaugeFunc(name, help string, labels []string, fn func() []Sample) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.families[name] = &gaugeFunc{helpText: help, labels: labels, fn: fn}
+}
+
+// WriteText renders every registered family, sorted by name.
+func (r *Registry) WriteText(out io.Writer) error {
+	return r.writeText(out, "", nil)
+}
+
+// WriteTenantText renders only series whose tenant label equals tenant.
+// Families without a tenant label describe the whole instance and are left
+// out, so one tenant's scrape never reveals another's traffic.
+func (r *Registry) WriteTenantText(out io.Writer, tenant string) error {
+	return r.writeText(out, TenantLabelName, func(names, values []string) bool {
+		for i, n := range names {
+			if n == TenantLabelName {
+				return values[i] == tenant
+			}
+		}
+		return false
+	})
+}
+
+// TenantLabelName is the label that partitions series by tenant.
+const TenantLabelName = "tenant"
+
+func (r *Registry) writeText(out io.Writer, requiredLabel string, keep rowFilter) error {
+	r.mu.RLock()
+	names := make([]string, 0, len(r.families))
+	for n := range r.families {
+		names = append(names, n)
+	}
+	fams := make(map[string]family, len(r.families))
+	for n, f := range r.families {
+		fams[n] = f
+	}
+	r.mu.RUnlock()
+	sort.Strings(names)
+
+	w := bufio.NewWriter(out)
+	for _, n := range names {
+		f := fams[n]
+		if requiredLabel != "" && !hasLabel(f.labelNames(), requiredLabel) {
+			continue
+		}
+		fmt.Fprintf(w, "# HELP %s %s\n", n, escapeHelp(f.help()))
+		fmt.Fprintf(w, "# TYPE %s %s\n", n, f.kind())
+		f.write(w, n, keep)
+	}
+	return w.Flush()
+}
+
+func hasLabel(names []string, label string) bool {
+	for _, n := range names {
+		if n == label {
+			return true
+		}
+	}
+	return false
+}
+
+// ---------------------------------------------------------------------------
+// Counter
+// ---------------------------------------------------------------------------
+
+// CounterVec is a set of counters sharing a name and label names.
+type CounterVec struct {
+	helpText string
+	labels   []string
+	mu       sync.Mutex
+	values   map[string]*counterValue
+}
+
+type counterValue struct {
+	labels []string
+	v      float64
+}
+
+// Inc adds 1 to the counter identified by labelValues.
+func (c *CounterVec) Inc(labelValues ...string) { c.Add(1, labelValues...) }
+
+// Add adds v (must be >= 0) to the counter identified by labelValues.
+func (c *CounterVec) Add(v float64, labelValues ...string) {
+	if c == nil || v < 0 {
+		return
+	}
+	labelValues = fitLabels(c.labels, labelValues)
+	key := strings.Join(labelValues, "\xff")
+	c.mu.Lock()
+	cv, ok := c.values[key]
+	if !ok {
+		cv = &counterValue{labels: labelValues}
+		c.values[key] = cv
+	}
+	cv.v += v
+	c.mu.Unlock()
+}
+
+// Value returns the current value for labelValues (0 if never incremented).
+func (c *CounterVec) Value(labelValues ...string) float64 {
+	labelValues = fitLabels(c.labels, labelValues)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if cv, ok := c.values[strings.Join(labelValues, "\xff")]; ok {
+		return cv.v
+	}
+	return 0
+}
+
+func (c *CounterVec) help() string         { return c.helpText }
+func (c *CounterVec) kind() string         { return "counter" }
+func (c *CounterVec) labelNames() []string { return c.labels }
+
+func (c *CounterVec) write(w *bufio.Writer, name string, keep rowFilter) {
+	c.mu.Lock()
+	rows := make([]*counterValue, 0, len(c.values))
+	for _, v := range c.values {
+		rows = append(rows, &counterValue{labels: v.labels, v: v.v})
+	}
+	c.mu.Unlock()
+	sort.Slice(rows, func(i, j int) bool { return lessLabels(rows[i].labels, rows[j].labels) })
+	for _, r := range rows {
+		if keep != nil && !keep(c.labels, r.labels) {
+			continue
+		}
+		writeSample(w, name, c.labels, r.labels, "", "", r.v)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Histogram
+// ---------------------------------------------------------------------------
+
+// HistogramVec is a set of histograms sharing a name, buckets and label names.
+type HistogramVec struct {
+	helpText string
+	labels   []string
+	buckets  []float64
+	mu       sync.Mutex
+	values   map[string]*histogramValue
+}
+
+type histogramValue struct {
+	labels []string
+	counts []uint64 // per bucket (non-cumulative); len(buckets)+1, last is +Inf
+	sum    float64
+	count  uint64
+}
+
+// Observe records v in the histogram identified by labelValues.
+func (h *HistogramVec) Observe(v float64, labelValues ...string) {
+	if h == nil {
+		return
+	}
+	labelValues = fitLabels(h.labels, labelValues)
+	key := strings.Join(labelValues, "\xff")
+	idx := sort.SearchFloat64s(h.buckets, v)
+	h.mu.Lock()
+	hv, ok := h.values[key]
+	if !ok {
+		hv = &histogramValue{labels: labelValues, counts: make([]uint64, len(h.buckets)+1)}
+		h.values[key] = hv
+	}
+	hv.counts[idx]++
+	hv.sum += v
+	hv.count++
+	h.mu.Unlock()
+}
+
+func (h *HistogramVec) help() string         { return h.helpText }
+func (h *HistogramVec) kind() string         { return "histogram" }
+func (h *HistogramVec) labelNames() []string { return h.labels }
+
+func (h *HistogramVec) write(w *bufio.Writer, name string, keep rowFilter) {
+	h.mu.Lock()
+	rows := make([]histogramValue, 0, len(h.values))
+	for _, v := range h.values {
+		rows = append(rows, histogramValue{labels: v.labels, counts: append([]uint64(nil), v.counts...), sum: v.sum, count: v.count})
+	}
+	h.mu.Unlock()
+	sort.Slice(rows, func(i, j int) bool { return lessLabels(rows[i].labels, rows[j].labels) })
+	for _, r := range rows {
+		if keep != nil && !keep(h.labels, r.labels) {
+			continue
+		}
+		var cum uint64
+		for i, b := range h.buckets {
+			cum += r.counts[i]
+			writeSample(w, name+"_bucket", h.labels, r.labels, "le", formatFloat(b), float64(cum))
+		}
+		writeSample(w, name+"_bucket", h.labels, r.labels, "le", "+Inf", float64(r.count))
+		writeSample(w, name+"_sum", h.labels, r.labels, "", "", r.sum)
+		writeSample(w, name+"_count", h.labels, r.labels, "", "", float64(r.count))
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Gauge (scrape-time function)
+// ---------------------------------------------------------------------------
+
+type gaugeFunc struct {
+	helpText string
+	labels   []string
+	fn       func() []Sample
+}
+
+func (g *gaugeFunc) help() string         { return g.helpText }
+func (g *gaugeFunc) kind() string         { return "gauge" }
+func (g *gaugeFunc) labelNames() []string { return g.labels }
+
+func (g *gaugeFunc) write(w *bufio.Writer, name string, keep rowFilter) {
+	samples := g.fn()
+	sort.Slice(samples, func(i, j int) bool { return lessLabels(samples[i].Labels, samples[j].Labels) })
+	for _, s := range samples {
+		labels := fitLabels(g.labels, s.Labels)
+		if keep != nil && !keep(g.labels, labels) {
+			continue
+		}
+		writeSample(w, name, g.labels, labels, "", "", s.Value)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Formatting helpers
+// ---------------------------------------------------------------------------
+
+func writeSample(w *bufio.Writer, name string, labelNames, labelValues []string, extraName, extraValue string, v float64) {
+	w.WriteString(name)
+	if len(labelNames) > 0 || extraName != "" {
+		w.WriteByte('{')
+		first := true
+		for i, ln := range labelNames {
+			if !first {
+				w.WriteByte(',')
+			}
+			first = false
+			w.WriteString(ln)
+			w.WriteString(`="`)
+			w.WriteString(escapeLabel(labelValues[i]))
+			w.WriteByte('"')
+		}
+		if extraName != "" {
+			if !first {
+				w.WriteByte(',')
+			}
+			w.WriteString(extraName)
+			w.WriteString(`="`)
+			w.WriteString(extraValue)
+			w.WriteByte('"')
+		}
+		w.WriteByte('}')
+	}
+	w.WriteByte(' ')
+	w.WriteString(formatFloat(v))
+	w.WriteByte('\n')
+}
+
+// fitLabels pads or truncates values to the declared label count so a caller
+// mistake never produces malformed output.
+func fitLabels(names, values []string) []string {
+	if len(values) == len(names) {
+		return values
+	}
+	out := make([]string, len(names))
+	copy(out, values)
+	return out
+}
+
+func lessLabels(a, b []string) bool {
+	for i := range a {
+		if i >= len(b) {
+			return false
+		}
+		if a[i] != b[i] {
+			return a[i] < b[i]
+		}
+	}
+	return len(a) < len(b)
+}
+
+func formatFloat(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	case math.IsNaN(v):
+		return "NaN"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+var (
+	labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+	helpEscaper  = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+)
+
+func escapeLabel(s string) string { return labelEscaper.Replace(s) }
+func escapeHelp(s string) string  { return helpEscaper.Replace(s) }
@@ -0,0 +1,112 @@
+package metrics
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestWriteText_CounterAndGauge(t *testing.T) {
+	r := NewRegistry()
+	c := r.NewCounterVec("test_requests_total", "Requests.", "tenant", "status")
+	c.Inc("t1", "ok")
+	c.Add(2, "t1", "ok")
+	c.Inc("t2", "error")
+	r.NewGaugeFunc("test_queue_size", "Queue size.", []string{"queue"}, func() []Sample {
+		return []Sample{{Labels: []string{"outbound"}, Value: 1}, {Labels: []string{"inbound"}, Value: 4}}
+	})
+
+	var sb strings.Builder
+	if err := r.WriteText(&sb); err != nil {
+		t.Fatal(err)
+	}
+	want := `# HELP test_queue_size Queue size.
+# TYPE test_queue_size gauge
+test_queue_size{queue="inbound"} 4
+test_queue_size{queue="outbound"} 1
+# HELP test_requests_total Requests.
+# TYPE test_requests_total counter
+test_requests_total{tenant="t1",status="ok"} 3
+test_requests_total{tenant="t2",status="error"} 1
+`
+	if got := sb.String(); got != want {
+		t.Errorf("output mismatch:\n got:\n%s\nwant:\n%s", got, want)
+	}
+	if v := c.Value("t1", "ok"); v != 3 {
+		t.Errorf("Value = %v, want 3", v)
+	}
+}
+
+func TestWriteText_Histogram(t *testing.T) {
+	r := NewRegistry()
+	h := r.NewHistogramVec("test_duration_seconds", "Latency.", []float64{0.5, 1}, "op")
+	h.Observe(0.2, "a")
+	h.Observe(0.5, "a")
+	h.Observe(3, "a")
+
+	var sb strings.Builder
+	_ = r.WriteText(&sb)
+	for _, line := range []string{
+		`test_duration_seconds_bucket{op="a",le="0.5"} 2`,
+		`test_duration_seconds_bucket{op="a",le="1"} 2`,
+		`test_duration_seconds_bucket{op="a",le="+Inf"} 3`,
+		`test_duration_seconds_sum{op="a"} 3.7`,
+		`test_duration_seconds_count{op="a"} 3`,
+	} {
+		if !strings.Contains(sb.String(), line+"\n") {
+			t.Errorf("missing line %q in:\n%s", line, sb.String())
+		}
+	}
+}
+
+func TestWriteText_EscapesLabels(t *testing.T) {
+	r := NewRegistry()
+	c := r.NewCounterVec("test_total", "Multi\nline.", "name")
+	c.Inc("a\"b\\c\nd")
+	c.Inc() // missing label values are padded, never malformed
+
+	var sb strings.Builder
+	_ = r.WriteText(&sb)
+	out := sb.String()
+	if !strings.Contains(out, `# HELP test_total Multi\nline.`) {
+		t.Errorf("help not escaped:\n%s", out)
+	}
+	if !strings.Contains(out, `test_total{name="a\"b\\c\nd"} 1`) {
+		t.Errorf("label not escaped:\n%s", out)
+	}
+	if !strings.Contains(out, `test_total{name=""} 1`) {
+		t.Errorf("missing padded label:\n%s", out)
+	}
+}
+
+func TestDuplicateRegistrationPanics(t *testing.T) {
+	r := NewRegistry()
+	r.NewCounterVec("dup_total", "x")
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic on duplicate registration")
+		}
+	}()
+	r.NewCounterVec("dup_total", "x")
+}
+
+func TestWriteTenantText_FiltersToTenant(t *testing.T) {
+	r := NewRegistry()
+	c := r.NewCounterVec("test_requests_total", "Requests.", "tenant", "status")
+	c.Inc("t1", "ok")
+	c.Inc("t2", "ok")
+	r.NewGaugeFunc("test_queue_size", "Queue size.", []string{"queue"}, func() []Sample {
+		return []Sample{{Labels: []string{"inbound"}, Value: 4}}
+	})
+
+	var sb strings.Builder
+	if err := r.WriteTenantText(&sb, "t1"); err != nil {
+		t.Fatal(err)
+	}
+	want := `# HELP test_requests_total Requests.
+# TYPE test_requests_total counter
+test_requests_total{tenant="t1",status="ok"} 1
+`
+	if got := sb.String(); got != want {
+		t.Errorf("output mismatch:\n got:\n%s\nwant:\n%s", got, want)
+	}
+}
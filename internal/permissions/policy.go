@@ -177,10 +177,15 @@ func isAdminMethod(method string) bool {
 		protocol.MethodTeamsTaskGet,
 		protocol.MethodTeamsTaskComments,
 		protocol.MethodTeamsTaskEvents,
+		protocol.MethodTeamsTaskGraph,
 		protocol.MethodAPIKeysList,
 		protocol.MethodAPIKeysCreate,
 		protocol.MethodAPIKeysRevoke,
 		protocol.MethodSkillsUpdate,
+		protocol.MethodTTSEnable,
+		protocol.MethodTTSDisable,
+		protocol.MethodTTSSetProvider,
+		protocol.MethodBrowserAct,
 	}
 	return slices.Contains(adminMethods, method)
 }
@@ -200,12 +205,21 @@ func isWriteMethod(method string) bool {
 		"device.pair.",
 		"approvals.",
 		"exec.approval.",
+		"tool.approval.",
 		protocol.MethodSend,
 		protocol.MethodTeamsTaskApprove,
 		protocol.MethodTeamsTaskReject,
 		protocol.MethodTeamsTaskComment,
 		protocol.MethodTeamsTaskCreate,
 		protocol.MethodTeamsTaskAssign,
+		protocol.MethodTTSConvert,
+		protocol.MethodBrowserTabs,
+		protocol.MethodBrowserSnapshot,
+		protocol.MethodBrowserScreenshot,
+		protocol.MethodMCPPromptsGet,
+		protocol.MethodAgentsFileRestore,
+		protocol.MethodAgentsFileApprove,
+		protocol.MethodAgentsFileReject,
 	}
 	for _, prefix := range writePrefixes {
 		if strings.HasPrefix(method, prefix) {
@@ -0,0 +1,167 @@
+package providers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"strings"
+)
+
+// FallbackReason explains why an LLM call moved on to the next hop of an
+// agent's fallback chain. Empty means the error is not eligible for fallback
+// (auth failures, bad requests, cancellations) and should surface as-is.
+type FallbackReason string
+
+const (
+	FallbackRateLimit     FallbackReason = "rate_limit"
+	FallbackServerError   FallbackReason = "server_error"
+	FallbackContextLength FallbackReason = "context_length"
+	FallbackTimeout       FallbackReason = "timeout"
+)
+
+// contextLengthMarkers are substrings providers use when a request exceeds the
+// model's context window. Matched case-insensitively against the error body.
+var contextLengthMarkers = []string{
+	"context_length_exceeded",
+	"context length",
+	"context window",
+	"maximum context",
+	"prompt is too long",
+	"too many tokens",
+	"input is too long",
+}
+
+// ClassifyFallback reports whether err should trigger a fallback hop and why.
+// Providers retry transient failures internally (RetryDo), so by the time an
+// error reaches the caller the primary has already been given its chance.
+func ClassifyFallback(err error) FallbackReason {
+	if err == nil || errors.Is(err, context.Canceled) {
+		return ""
+	}
+
+	var httpErr *HTTPError
+	if errors.As(err, &httpErr) {
+		switch {
+		case httpErr.Status == 429:
+			return FallbackRateLimit
+		case httpErr.Status >= 500:
+			return FallbackServerError
+		case httpErr.Status == 400 || httpErr.Status == 413:
+			if isContextLengthMessage(httpErr.Body) {
+				return FallbackContextLength
+			}
+		}
+		return ""
+	}
+
+	if errors.Is(err, context.DeadlineExceeded) {
+		return FallbackTimeout
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return FallbackTimeout
+	}
+
+	// Providers that don't wrap HTTPError (CLI bridges, SDK errors) still
+	// surface recognizable messages.
+	msg := strings.ToLower(err.Error())
+	switch {
+	case isContextLengthMessage(msg):
+		return FallbackContextLength
+	case strings.Contains(msg, "rate limit") || strings.Contains(msg, "rate_limit") || strings.Contains(msg, "overloaded"):
+		return FallbackRateLimit
+	case strings.Contains(msg, "timeout") || strings.Contains(msg, "timed out"):
+		return FallbackTimeout
+	}
+	return ""
+}
+
+func isContextLengthMessage(s string) bool {
+	lower := strings.ToLower(s)
+	for _, marker := range contextLengthMarkers {
+		if strings.Contains(lower, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+// AdaptMessagesForFallback prepares a conversation built for one provider to be
+// replayed against another. Tool schemas need no work here — every provider
+// normalizes them for its own SchemaProfile when building the request — but
+// provider-native artifacts do: Anthropic raw content blocks (with thinking
+// signatures) and per-call metadata such as Gemini thought signatures are
+// rejected or meaningless elsewhere.
+//
+// The input slice is never modified. droppedNative reports whether any
+// assistant message lost provider-native content; callers should then disable
+// extended thinking for the hop, since thinking-enabled tool loops require the
+// original signed blocks.
+func AdaptMessagesForFallback(msgs []Message) (out []Message, droppedNative bool) {
+	out = make([]Message, len(msgs))
+	copy(out, msgs)
+	for i := range out {
+		if len(out[i].RawAssistantContent) > 0 {
+			out[i].RawAssistantContent = nil
+			droppedNative = true
+		}
+		if len(out[i].ToolCalls) == 0 {
+			continue
+		}
+		var calls []ToolCall
+		for j, tc := range out[i].ToolCalls {
+			if len(tc.Metadata) == 0 {
+				continue
+			}
+			if calls == nil {
+				calls = make([]ToolCall, len(out[i].ToolCalls))
+				copy(calls, out[i].ToolCalls)
+			}
+			calls[j].Metadata = nil
+			droppedNative = true
+		}
+		if calls != nil {
+			out[i].ToolCalls = calls
+		}
+	}
+	return out, droppedNative
+}
+
+// FallbackMetadataKey is the span metadata key carrying FallbackHop details.
+const FallbackMetadataKey = "fallback"
+
+// FallbackHop describes a fallback attempt for tracing: which hop of the chain
+// is running, what it replaced and why.
+type FallbackHop struct {
+	Hop              int            `json:"hop"` // 1-based position in the fallback chain
+	Reason           FallbackReason `json:"reason"`
+	FromProvider     string         `json:"from_provider"`
+	FromModel        string         `json:"from_model,omitempty"`
+	Error            string         `json:"error,omitempty"`
+	ThinkingDisabled bool           `json:"thinking_disabled,omitempty"`
+}
+
+type fallbackHopKey struct{}
+
+func WithFallbackHop(ctx context.Context, hop FallbackHop) context.Context {
+	return context.WithValue(ctx, fallbackHopKey{}, &hop)
+}
+
+func FallbackHopFromContext(ctx context.Context) *FallbackHop {
+	hop, _ := ctx.Value(fallbackHopKey{}).(*FallbackHop)
+	return hop
+}
+
+func MergeFallbackMetadata(existing json.RawMessage, hop FallbackHop) json.RawMessage {
+	payload := map[string]any{}
+	if len(existing) > 0 {
+		_ = json.Unmarshal(existing, &payload)
+	}
+	payload[FallbackMetadataKey] = hop
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return existing
+	}
+	return json.RawMessage(data)
+}
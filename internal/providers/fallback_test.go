@@ -0,0 +1,69 @@
+package providers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestClassifyFallback(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want FallbackReason
+	}{
+		{"nil", nil, ""},
+		{"http_429", &HTTPError{Status: 429}, FallbackRateLimit},
+		{"http_503", &HTTPError{Status: 503}, FallbackServerError},
+		{"http_529_overloaded", &HTTPError{Status: 529}, FallbackServerError},
+		{"http_400_context", &HTTPError{Status: 400, Body: `{"error":{"code":"context_length_exceeded"}}`}, FallbackContextLength},
+		{"http_400_prompt_too_long", &HTTPError{Status: 400, Body: "prompt is too long: 210000 tokens > 200000 maximum"}, FallbackContextLength},
+		{"http_400_other", &HTTPError{Status: 400, Body: "invalid tool schema"}, ""},
+		{"http_401", &HTTPError{Status: 401}, ""},
+		{"wrapped_429", fmt.Errorf("anthropic: %w", &HTTPError{Status: 429}), FallbackRateLimit},
+		{"deadline", fmt.Errorf("call: %w", context.DeadlineExceeded), FallbackTimeout},
+		{"canceled", context.Canceled, ""},
+		{"string_timeout", errors.New("claude cli: timed out waiting for response"), FallbackTimeout},
+		{"string_generic", errors.New("something went wrong"), ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ClassifyFallback(tt.err); got != tt.want {
+				t.Errorf("ClassifyFallback(%v) = %q, want %q", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAdaptMessagesForFallbackStripsNativeContent(t *testing.T) {
+	msgs := []Message{
+		{Role: "user", Content: "hi"},
+		{
+			Role:                "assistant",
+			RawAssistantContent: json.RawMessage(`[{"type":"thinking"}]`),
+			ToolCalls: []ToolCall{
+				{ID: "1", Name: "exec", Metadata: map[string]string{"thought_signature": "sig"}},
+			},
+		},
+	}
+
+	out, dropped := AdaptMessagesForFallback(msgs)
+	if !dropped {
+		t.Fatal("droppedNative = false, want true")
+	}
+	if out[1].RawAssistantContent != nil || out[1].ToolCalls[0].Metadata != nil {
+		t.Fatalf("native content not stripped: %+v", out[1])
+	}
+	if msgs[1].RawAssistantContent == nil || msgs[1].ToolCalls[0].Metadata == nil {
+		t.Fatal("input messages were modified")
+	}
+}
+
+func TestAdaptMessagesForFallbackPlainHistory(t *testing.T) {
+	msgs := []Message{{Role: "user", Content: "hi"}, {Role: "assistant", Content: "hello"}}
+	if _, dropped := AdaptMessagesForFallback(msgs); dropped {
+		t.Fatal("droppedNative = true for plain history")
+	}
+}
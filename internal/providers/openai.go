@@ -396,6 +396,13 @@ func (p *OpenAIProvider) buildRequestBody(model string, req ChatRequest, stream
 	if len(req.Tools) > 0 {
 		body["tools"] = CleanToolSchemas(p.schemaProviderName(), req.Tools)
 		body["tool_choice"] = "auto"
+		if v, ok := req.Options[OptToolChoice]; ok && v != nil {
+			body["tool_choice"] = v
+		}
+	}
+
+	if rf, ok := req.Options[OptResponseFormat].(*ResponseFormat); ok && rf.Structured() {
+		body["response_format"] = rf
 	}
 
 	if stream {
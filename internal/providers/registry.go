@@ -107,6 +107,19 @@ func (r *Registry) Get(ctx context.Context, name string) (Provider, error) {
 	return r.GetForTenant(r.tenantFromContext(ctx), name)
 }
 
+// GetOwn returns a provider registered for the context's tenant, without
+// falling back to master-tenant providers. Use it where the caller chooses
+// the provider name and must not reach another tenant's credentials.
+func (r *Registry) GetOwn(ctx context.Context, name string) (Provider, error) {
+	tenantID := r.tenantFromContext(ctx)
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if p, ok := r.providers[compoundKey(tenantID, name)]; ok {
+		return p, nil
+	}
+	return nil, fmt.Errorf("provider not found: %s", name)
+}
+
 // GetForTenant returns a provider by name for a specific tenant.
 // Falls back to master tenant if not found for the given tenant.
 func (r *Registry) GetForTenant(tenantID uuid.UUID, name string) (Provider, error) {
@@ -0,0 +1,356 @@
+package providers
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Structured output types (OpenAI response_format.type).
+const (
+	ResponseFormatText       = "text"
+	ResponseFormatJSONObject = "json_object"
+	ResponseFormatJSONSchema = "json_schema"
+)
+
+// ResponseFormat requests structured output. It mirrors OpenAI's
+// response_format so it can be forwarded verbatim to OpenAI-compatible
+// providers; other providers rely on the prompt instruction and the agent
+// loop validating the final answer.
+type ResponseFormat struct {
+	Type       string          `json:"type"`
+	JSONSchema *JSONSchemaSpec `json:"json_schema,omitempty"`
+}
+
+// JSONSchemaSpec is the json_schema member of a response_format.
+type JSONSchemaSpec struct {
+	Name        string         `json:"name"`
+	Description string         `json:"description,omitempty"`
+	Schema      map[string]any `json:"schema,omitempty"`
+	Strict      *bool          `json:"strict,omitempty"`
+}
+
+// Structured reports whether the format constrains output to JSON.
+func (f *ResponseFormat) Structured() bool {
+	return f != nil && (f.Type == ResponseFormatJSONObject || f.Type == ResponseFormatJSONSchema)
+}
+
+// Check validates the format itself (not model output).
+func (f *ResponseFormat) Check() error {
+	switch f.Type {
+	case "", ResponseFormatText, ResponseFormatJSONObject:
+		return nil
+	case ResponseFormatJSONSchema:
+		if f.JSONSchema == nil || f.JSONSchema.Name == "" {
+			return fmt.Errorf("response_format.json_schema.name is required")
+		}
+		return nil
+	}
+	return fmt.Errorf("unsupported response_format.type %q", f.Type)
+}
+
+// Instruction returns a system prompt section describing the required output.
+func (f *ResponseFormat) Instruction() string {
+	if !f.Structured() {
+		return ""
+	}
+	var sb strings.Builder
+	sb.WriteString("## Response Format\n\n")
+	sb.WriteString("Your final answer MUST be a single valid JSON value and nothing else — no prose, no markdown code fences.")
+	if f.Type == ResponseFormatJSONSchema && f.JSONSchema != nil {
+		if f.JSONSchema.Description != "" {
+			sb.WriteString("\n\n" + f.JSONSchema.Description)
+		}
+		if len(f.JSONSchema.Schema) > 0 {
+			schema, _ := json.MarshalIndent(f.JSONSchema.Schema, "", "  ")
+			fmt.Fprintf(&sb, " It must conform to the JSON Schema %q:\n\n%s", f.JSONSchema.Name, schema)
+		}
+	}
+	return sb.String()
+}
+
+var jsonFenceRe = regexp.MustCompile("(?s)^```(?:json)?\\s*\n?(.*?)\\s*```$")
+
+// Validate checks model output against the format. It returns the output
+// normalized to bare JSON (code fences stripped) or a description of the first
+// violation found.
+func (f *ResponseFormat) Validate(content string) (string, error) {
+	if !f.Structured() {
+		return content, nil
+	}
+	out := strings.TrimSpace(content)
+	if m := jsonFenceRe.FindStringSubmatch(out); m != nil {
+		out = strings.TrimSpace(m[1])
+	}
+	var v any
+	if err := json.Unmarshal([]byte(out), &v); err != nil {
+		return content, fmt.Errorf("output is not valid JSON: %v", err)
+	}
+	if f.Type == ResponseFormatJSONObject {
+		if _, ok := v.(map[string]any); !ok {
+			return content, fmt.Errorf("output must be a JSON object")
+		}
+		return out, nil
+	}
+	if f.JSONSchema == nil || len(f.JSONSchema.Schema) == 0 {
+		return out, nil
+	}
+	sv := schemaValidator{root: f.JSONSchema.Schema}
+	if err := sv.validate(f.JSONSchema.Schema, v, "$", 0); err != nil {
+		return content, err
+	}
+	return out, nil
+}
+
+// schemaValidator checks a decoded JSON value against the subset of JSON
+// Schema used for structured output: type, enum, const, properties, required,
+// additionalProperties, items, anyOf/oneOf/allOf, string/array length, numeric
+// bounds and local $ref into $defs/definitions.
+type schemaValidator struct {
+	root map[string]any
+}
+
+func (sv schemaValidator) validate(schema map[string]any, v any, path string, depth int) error {
+	if depth > maxSchemaDepth {
+		return fmt.Errorf("%s: schema nesting too deep", path)
+	}
+	if ref, ok := schema["$ref"].(string); ok {
+		target, err := sv.resolve(ref)
+		if err != nil {
+			return err
+		}
+		return sv.validate(target, v, path, depth+1)
+	}
+
+	if t, ok := schema["type"]; ok && !matchesType(t, v) {
+		return fmt.Errorf("%s: expected %v, got %s", path, t, jsonTypeOf(v))
+	}
+	if enum, ok := schema["enum"].([]any); ok && !containsJSON(enum, v) {
+		return fmt.Errorf("%s: value must be one of %s", path, compactJSON(enum))
+	}
+	if c, ok := schema["const"]; ok && !equalJSON(c, v) {
+		return fmt.Errorf("%s: value must be %s", path, compactJSON(c))
+	}
+
+	for _, key := range []string{"allOf", "anyOf", "oneOf"} {
+		subs, ok := schema[key].([]any)
+		if !ok {
+			continue
+		}
+		matched := 0
+		var firstErr error
+		for _, s := range subs {
+			sm, _ := s.(map[string]any)
+			if err := sv.validate(sm, v, path, depth+1); err != nil {
+				if firstErr == nil {
+					firstErr = err
+				}
+				if key == "allOf" {
+					return err
+				}
+				continue
+			}
+			matched++
+		}
+		switch {
+		case key == "anyOf" && matched == 0:
+			return fmt.Errorf("%s: matches none of anyOf (%v)", path, firstErr)
+		case key == "oneOf" && matched != 1:
+			return fmt.Errorf("%s: must match exactly one of oneOf (matched %d)", path, matched)
+		}
+	}
+
+	switch val := v.(type) {
+	case map[string]any:
+		return sv.validateObject(schema, val, path, depth)
+	case []any:
+		if n, ok := numberOf(schema["minItems"]); ok && float64(len(val)) < n {
+			return fmt.Errorf("%s: expected at least %v items", path, n)
+		}
+		if n, ok := numberOf(schema["maxItems"]); ok && float64(len(val)) > n {
+			return fmt.Errorf("%s: expected at most %v items", path, n)
+		}
+		if items, ok := schema["items"].(map[string]any); ok {
+			for i, item := range val {
+				if err := sv.validate(items, item, fmt.Sprintf("%s[%d]", path, i), depth+1); err != nil {
+					return err
+				}
+			}
+		}
+	case string:
+		n := float64(len([]rune(val)))
+		if m, ok := numberOf(schema["minLength"]); ok && n < m {
+			return fmt.Errorf("%s: shorter than %v characters", path, m)
+		}
+		if m, ok := numberOf(schema["maxLength"]); ok && n > m {
+			return fmt.Errorf("%s: longer than %v characters", path, m)
+		}
+		if p, ok := schema["pattern"].(string); ok {
+			if re, err := regexp.Compile(p); err == nil && !re.MatchString(val) {
+				return fmt.Errorf("%s: does not match pattern %q", path, p)
+			}
+		}
+	case float64:
+		if m, ok := numberOf(schema["minimum"]); ok && val < m {
+			return fmt.Errorf("%s: must be >= %v", path, m)
+		}
+		if m, ok := numberOf(schema["maximum"]); ok && val > m {
+			return fmt.Errorf("%s: must be <= %v", path, m)
+		}
+		if m, ok := numberOf(schema["exclusiveMinimum"]); ok && val <= m {
+			return fmt.Errorf("%s: must be > %v", path, m)
+		}
+		if m, ok := numberOf(schema["exclusiveMaximum"]); ok && val >= m {
+			return fmt.Errorf("%s: must be < %v", path, m)
+		}
+	}
+	return nil
+}
+
+func (sv schemaValidator) validateObject(schema map[string]any, obj map[string]any, path string, depth int) error {
+	if req, ok := schema["required"].([]any); ok {
+		for _, r := range req {
+			if name, ok := r.(string); ok {
+				if _, present := obj[name]; !present {
+					return fmt.Errorf("%s: missing required property %q", path, name)
+				}
+			}
+		}
+	}
+	props, _ := schema["properties"].(map[string]any)
+	keys := make([]string, 0, len(obj))
+	for k := range obj {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		if ps, ok := props[k].(map[string]any); ok {
+			if err := sv.validate(ps, obj[k], path+"."+k, depth+1); err != nil {
+				return err
+			}
+			continue
+		}
+		switch ap := schema["additionalProperties"].(type) {
+		case bool:
+			if !ap {
+				return fmt.Errorf("%s: unexpected property %q", path, k)
+			}
+		case map[string]any:
+			if err := sv.validate(ap, obj[k], path+"."+k, depth+1); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// resolve follows a local JSON pointer ("#/$defs/x", "#/definitions/x", "#").
+func (sv schemaValidator) resolve(ref string) (map[string]any, error) {
+	if ref == "#" {
+		return sv.root, nil
+	}
+	ptr, ok := strings.CutPrefix(ref, "#/")
+	if !ok {
+		return nil, fmt.Errorf("unsupported $ref %q (only local references are allowed)", ref)
+	}
+	var cur any = sv.root
+	for _, part := range strings.Split(ptr, "/") {
+		part = strings.ReplaceAll(strings.ReplaceAll(part, "~1", "/"), "~0", "~")
+		m, ok := cur.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("unresolvable $ref %q", ref)
+		}
+		cur = m[part]
+	}
+	target, ok := cur.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("unresolvable $ref %q", ref)
+	}
+	return target, nil
+}
+
+// matchesType checks v against a "type" keyword (string or array of strings).
+func matchesType(t any, v any) bool {
+	switch tt := t.(type) {
+	case string:
+		return matchesTypeName(tt, v)
+	case []any:
+		for _, x := range tt {
+			if s, ok := x.(string); ok && matchesTypeName(s, v) {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+func matchesTypeName(name string, v any) bool {
+	switch name {
+	case "object":
+		_, ok := v.(map[string]any)
+		return ok
+	case "array":
+		_, ok := v.([]any)
+		return ok
+	case "string":
+		_, ok := v.(string)
+		return ok
+	case "number":
+		_, ok := v.(float64)
+		return ok
+	case "integer":
+		f, ok := v.(float64)
+		return ok && f == math.Trunc(f)
+	case "boolean":
+		_, ok := v.(bool)
+		return ok
+	case "null":
+		return v == nil
+	}
+	return true
+}
+
+func jsonTypeOf(v any) string {
+	switch v.(type) {
+	case map[string]any:
+		return "object"
+	case []any:
+		return "array"
+	case string:
+		return "string"
+	case float64:
+		return "number"
+	case bool:
+		return "boolean"
+	case nil:
+		return "null"
+	}
+	return fmt.Sprintf("%T", v)
+}
+
+func numberOf(v any) (float64, bool) {
+	f, ok := v.(float64)
+	return f, ok
+}
+
+func containsJSON(list []any, v any) bool {
+	for _, x := range list {
+		if equalJSON(x, v) {
+			return true
+		}
+	}
+	return false
+}
+
+func equalJSON(a, b any) bool {
+	return compactJSON(a) == compactJSON(b)
+}
+
+func compactJSON(v any) string {
+	b, _ := json.Marshal(v)
+	return string(b)
+}
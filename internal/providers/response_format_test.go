@@ -0,0 +1,111 @@
+package providers
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func testResponseFormat(t *testing.T, schema string) *ResponseFormat {
+	t.Helper()
+	var s map[string]any
+	if err := json.Unmarshal([]byte(schema), &s); err != nil {
+		t.Fatalf("bad test schema: %v", err)
+	}
+	return &ResponseFormat{Type: ResponseFormatJSONSchema, JSONSchema: &JSONSchemaSpec{Name: "answer", Schema: s}}
+}
+
+func TestResponseFormatValidate(t *testing.T) {
+	rf := testResponseFormat(t, `{
+		"type": "object",
+		"properties": {
+			"city": {"type": "string", "minLength": 1},
+			"temp": {"type": "number"},
+			"unit": {"enum": ["c", "f"]},
+			"tags": {"type": "array", "items": {"$ref": "#/$defs/tag"}, "maxItems": 2}
+		},
+		"required": ["city", "temp"],
+		"additionalProperties": false,
+		"$defs": {"tag": {"type": "string"}}
+	}`)
+
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+		want    string
+	}{
+		{"valid", `{"city":"Hanoi","temp":31.5,"unit":"c"}`, "", `{"city":"Hanoi","temp":31.5,"unit":"c"}`},
+		{"fenced", "```json\n{\"city\":\"Hue\",\"temp\":28}\n```", "", `{"city":"Hue","temp":28}`},
+		{"not_json", `The weather is hot.`, "not valid JSON", ""},
+		{"missing_required", `{"city":"Hanoi"}`, `missing required property "temp"`, ""},
+		{"wrong_type", `{"city":"Hanoi","temp":"hot"}`, "$.temp: expected number", ""},
+		{"enum", `{"city":"Hanoi","temp":1,"unit":"k"}`, "$.unit: value must be one of", ""},
+		{"extra_property", `{"city":"Hanoi","temp":1,"wind":3}`, `unexpected property "wind"`, ""},
+		{"ref_items", `{"city":"Hanoi","temp":1,"tags":["a",2]}`, "$.tags[1]: expected string", ""},
+		{"max_items", `{"city":"Hanoi","temp":1,"tags":["a","b","c"]}`, "at most 2 items", ""},
+		{"min_length", `{"city":"","temp":1}`, "$.city: shorter than", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := rf.Validate(tt.content)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Validate() error = %v, want containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Validate() unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Validate() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResponseFormatJSONObjectAndText(t *testing.T) {
+	obj := &ResponseFormat{Type: ResponseFormatJSONObject}
+	if _, err := obj.Validate(`[1,2]`); err == nil {
+		t.Error("json_object must reject arrays")
+	}
+	if _, err := obj.Validate(`{"ok":true}`); err != nil {
+		t.Errorf("json_object rejected an object: %v", err)
+	}
+
+	text := &ResponseFormat{Type: ResponseFormatText}
+	if got, err := text.Validate("plain words"); err != nil || got != "plain words" {
+		t.Errorf("text format must pass content through, got %q, %v", got, err)
+	}
+	if text.Instruction() != "" {
+		t.Error("text format must not add a prompt instruction")
+	}
+
+	if err := (&ResponseFormat{Type: ResponseFormatJSONSchema}).Check(); err == nil {
+		t.Error("json_schema without a name must fail Check")
+	}
+	if err := (&ResponseFormat{Type: "xml"}).Check(); err == nil {
+		t.Error("unknown type must fail Check")
+	}
+}
+
+func TestResponseFormatUnionTypes(t *testing.T) {
+	rf := testResponseFormat(t, `{
+		"type": "object",
+		"properties": {
+			"id": {"anyOf": [{"type": "integer"}, {"type": "string", "pattern": "^id-"}]},
+			"note": {"type": ["string", "null"]}
+		}
+	}`)
+	for _, ok := range []string{`{"id":3}`, `{"id":"id-7","note":null}`} {
+		if _, err := rf.Validate(ok); err != nil {
+			t.Errorf("Validate(%s) unexpected error: %v", ok, err)
+		}
+	}
+	for _, bad := range []string{`{"id":3.5}`, `{"id":"x"}`, `{"note":1}`} {
+		if _, err := rf.Validate(bad); err == nil {
+			t.Errorf("Validate(%s) expected error", bad)
+		}
+	}
+}
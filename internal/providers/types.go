@@ -14,6 +14,8 @@ const (
 	OptReasoningEffort = "reasoning_effort"
 	OptEnableThinking  = "enable_thinking"
 	OptThinkingBudget  = "thinking_budget"
+	OptResponseFormat  = "response_format" // *ResponseFormat — forwarded by OpenAI-compatible providers
+	OptToolChoice      = "tool_choice"     // OpenAI tool_choice value ("required" or {"type":"function",...})
 )
 
 // TokenSource provides an OAuth access token (with auto-refresh).
@@ -111,6 +113,10 @@ type MediaRef struct {
 	MimeType string `json:"mime_type"`      // e.g. "image/jpeg", "application/pdf"
 	Kind     string `json:"kind"`           // "image", "video", "audio", "document"
 	Path     string `json:"path,omitempty"` // absolute workspace path (persisted for /v1/files/ serving)
+
+	// Transcript is the STT output for audio refs, stored so history reloads
+	// and read_audio never re-transcribe the same file.
+	Transcript string `json:"transcript,omitempty"`
 }
 
 // Message represents a conversation message.
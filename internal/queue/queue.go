@@ -0,0 +1,336 @@
+// Package queue implements the durable message queue behind the message bus
+// (bus.Journal). Channel messages and agent replies are persisted when
+// published and marked done once handled, giving at-least-once delivery:
+// replies whose channel send fails are retried with backoff until they are
+// dead-lettered, and messages still in flight when the process died are
+// replayed on the next start.
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/nextlevelbuilder/goclaw/internal/bus"
+	"github.com/nextlevelbuilder/goclaw/internal/channels"
+	"github.com/nextlevelbuilder/goclaw/internal/store"
+)
+
+const (
+	defaultMaxAttempts = 5
+	defaultRetention   = 24 * time.Hour
+
+	pollInterval   = time.Second
+	pruneInterval  = time.Hour
+	orphanInterval = 30 * time.Second
+	claimBatch     = 100
+	storeTimeout   = 5 * time.Second
+
+	baseBackoff = 5 * time.Second
+	maxBackoff  = 10 * time.Minute
+)
+
+// Config configures the queue.
+type Config struct {
+	// Owner identifies this replica's in-memory bus; messages it held when it
+	// crashed are replayed by the next process with the same owner
+	// ("" standalone, the cluster node ID otherwise).
+	Owner string
+	// OwnerAlive reports whether another replica is still running (cluster
+	// mode). Messages held by replicas that are gone — crashed, or restarted
+	// under a new node ID — are replayed here. nil = standalone.
+	OwnerAlive func(owner string) bool
+	// MaxAttempts is the number of failed deliveries before a message is
+	// dead-lettered (default 5).
+	MaxAttempts int
+	// Retention is how long delivered messages are kept for idempotency
+	// checks (default 24h).
+	Retention time.Duration
+	// ChannelTenant resolves the tenant of a channel instance (optional).
+	ChannelTenant func(channel string) (uuid.UUID, bool)
+}
+
+// Queue persists bus messages in a store.MessageQueueStore.
+type Queue struct {
+	store store.MessageQueueStore
+	bus   *bus.MessageBus
+	cfg   Config
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// New creates a queue. Attach it with mb.SetJournal before channels start and
+// call Start once the bus consumers run.
+func New(s store.MessageQueueStore, mb *bus.MessageBus, cfg Config) *Queue {
+	if cfg.MaxAttempts <= 0 {
+		cfg.MaxAttempts = defaultMaxAttempts
+	}
+	if cfg.Retention <= 0 {
+		cfg.Retention = defaultRetention
+	}
+	return &Queue{store: s, bus: mb, cfg: cfg}
+}
+
+// Start replays messages left in flight by a previous run of this replica
+// (and, in cluster mode, by replicas that are gone), then redelivers due
+// messages and prunes old ones until Stop.
+func (q *Queue) Start(ctx context.Context) {
+	q.recover(ctx, q.cfg.Owner)
+	q.recoverOrphans(ctx)
+
+	ctx, q.cancel = context.WithCancel(ctx)
+	q.wg.Add(1)
+	go q.loop(ctx)
+	slog.Info("queue: durable message queue started", "owner", q.cfg.Owner, "max_attempts", q.cfg.MaxAttempts)
+}
+
+// Stop stops redelivery. Messages still in memory stay queued and are
+// replayed on the next start.
+func (q *Queue) Stop() {
+	if q.cancel != nil {
+		q.cancel()
+	}
+	q.wg.Wait()
+}
+
+func (q *Queue) loop(ctx context.Context) {
+	defer q.wg.Done()
+	poll := time.NewTicker(pollInterval)
+	defer poll.Stop()
+	prune := time.NewTicker(pruneInterval)
+	defer prune.Stop()
+	orphans := time.NewTicker(orphanInterval)
+	defer orphans.Stop()
+
+	q.redeliver(ctx)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-poll.C:
+			q.redeliver(ctx)
+		case <-orphans.C:
+			q.recoverOrphans(ctx)
+		case <-prune.C:
+			if n, err := q.store.PruneDone(ctx, time.Now().Add(-q.cfg.Retention)); err != nil {
+				slog.Warn("queue: prune delivered messages", "error", err)
+			} else if n > 0 {
+				slog.Debug("queue: pruned delivered messages", "count", n)
+			}
+		}
+	}
+}
+
+// recover returns the messages owner held in memory to pending.
+func (q *Queue) recover(ctx context.Context, owner string) {
+	n, err := q.store.RecoverOwned(ctx, owner, q.cfg.MaxAttempts)
+	if err != nil {
+		slog.Warn("queue: recover in-flight messages", "owner", owner, "error", err)
+	} else if n > 0 {
+		slog.Info("queue: replaying messages interrupted by restart", "owner", owner, "count", n)
+	}
+}
+
+// recoverOrphans replays messages held by replicas that are no longer running.
+func (q *Queue) recoverOrphans(ctx context.Context) {
+	if q.cfg.OwnerAlive == nil {
+		return
+	}
+	owners, err := q.store.QueuedOwners(ctx)
+	if err != nil {
+		if ctx.Err() == nil {
+			slog.Warn("queue: list queued owners", "error", err)
+		}
+		return
+	}
+	for _, owner := range owners {
+		if owner != q.cfg.Owner && !q.cfg.OwnerAlive(owner) {
+			q.recover(ctx, owner)
+		}
+	}
+}
+
+// redeliver claims due messages and pushes them onto the bus. Messages that
+// do not fit are released for the next poll.
+func (q *Queue) redeliver(ctx context.Context) {
+	due, err := q.store.ClaimDue(ctx, q.cfg.Owner, claimBatch)
+	if err != nil {
+		if ctx.Err() == nil {
+			slog.Warn("queue: claim due messages", "error", err)
+		}
+		return
+	}
+	full := false
+	for _, m := range due {
+		if !full {
+			ok, err := q.push(m)
+			if err != nil {
+				slog.Warn("queue: dead-lettering malformed message", "id", m.ID, "error", err)
+				q.fail(m.ID, err.Error(), time.Now(), 1)
+				continue
+			}
+			if ok {
+				continue
+			}
+			full = true
+		}
+		if err := q.store.Release(ctx, m.ID, time.Now().Add(pollInterval)); err != nil {
+			slog.Warn("queue: release message", "id", m.ID, "error", err)
+		}
+	}
+}
+
+// push decodes m and puts it on the bus without journaling it again.
+func (q *Queue) push(m store.QueuedMessage) (bool, error) {
+	switch m.Direction {
+	case store.QueueDirectionInbound:
+		var msg bus.InboundMessage
+		if err := json.Unmarshal(m.Payload, &msg); err != nil {
+			return false, err
+		}
+		msg.QueueIDs = []uuid.UUID{m.ID}
+		return q.bus.RedeliverInbound(msg), nil
+	case store.QueueDirectionOutbound:
+		var msg bus.OutboundMessage
+		if err := json.Unmarshal(m.Payload, &msg); err != nil {
+			return false, err
+		}
+		msg.QueueID = m.ID
+		msg.QueueAttempts = m.Attempts
+		return q.bus.RedeliverOutbound(msg), nil
+	default:
+		return false, fmt.Errorf("unknown direction %q", m.Direction)
+	}
+}
+
+// RecordInbound implements bus.Journal. Internal channels (subagent
+// announces, teammate and system messages) are not journaled: they are
+// re-created by the task and subagent recovery paths.
+func (q *Queue) RecordInbound(msg *bus.InboundMessage) bool {
+	if channels.IsInternalChannel(msg.Channel) {
+		return true
+	}
+	tenantID := msg.TenantID
+	if tenantID == uuid.Nil {
+		tenantID = q.channelTenant(msg.Channel)
+	}
+	id, ok := q.record(store.QueueDirectionInbound, msg.Channel, bus.InboundDedupeKey(*msg), tenantID, msg)
+	if id != uuid.Nil {
+		msg.QueueIDs = []uuid.UUID{id}
+	}
+	return ok
+}
+
+// RecordOutbound implements bus.Journal. Empty messages only clear typing
+// and placeholder indicators and are not worth retrying.
+func (q *Queue) RecordOutbound(msg *bus.OutboundMessage) bool {
+	if channels.IsInternalChannel(msg.Channel) || (msg.Content == "" && len(msg.Media) == 0) {
+		return true
+	}
+	id, ok := q.record(store.QueueDirectionOutbound, msg.Channel, "", q.channelTenant(msg.Channel), msg)
+	if id != uuid.Nil {
+		msg.QueueID = id
+	}
+	return ok
+}
+
+// record journals payload. When the store is unavailable the message is
+// still delivered from memory, just without durability.
+func (q *Queue) record(direction, channel, key string, tenantID uuid.UUID, payload any) (uuid.UUID, bool) {
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		slog.Warn("queue: message not serializable, delivering without journal", "channel", channel, "error", err)
+		return uuid.Nil, true
+	}
+	m := &store.QueuedMessage{
+		TenantID:       tenantID,
+		Direction:      direction,
+		Channel:        channel,
+		IdempotencyKey: key,
+		Payload:        raw,
+		Owner:          q.cfg.Owner,
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), storeTimeout)
+	defer cancel()
+	inserted, err := q.store.Enqueue(ctx, m)
+	if err != nil {
+		slog.Warn("queue: journal message, delivering without journal", "direction", direction, "channel", channel, "error", err)
+		return uuid.Nil, true
+	}
+	if !inserted {
+		slog.Debug("queue: skipping duplicate message", "direction", direction, "key", key)
+		return uuid.Nil, false
+	}
+	return m.ID, true
+}
+
+// Defer implements bus.Journal.
+func (q *Queue) Defer(id uuid.UUID) {
+	ctx, cancel := context.WithTimeout(context.Background(), storeTimeout)
+	defer cancel()
+	if err := q.store.Release(ctx, id, time.Now()); err != nil {
+		slog.Warn("queue: defer message", "id", id, "error", err)
+	}
+}
+
+// Ack implements bus.Journal.
+func (q *Queue) Ack(ids ...uuid.UUID) {
+	ctx, cancel := context.WithTimeout(context.Background(), storeTimeout)
+	defer cancel()
+	for _, id := range ids {
+		if err := q.store.Complete(ctx, id); err != nil {
+			slog.Warn("queue: ack message", "id", id, "error", err)
+		}
+	}
+}
+
+// Fail implements bus.Journal: the send is retried with exponential backoff
+// until MaxAttempts failures.
+func (q *Queue) Fail(msg bus.OutboundMessage, sendErr error) bool {
+	attempt := msg.QueueAttempts + 1
+	status := q.fail(msg.QueueID, sendErr.Error(), time.Now().Add(Backoff(attempt)), q.cfg.MaxAttempts)
+	switch status {
+	case store.QueueStatusPending:
+		slog.Info("queue: send failed, will retry", "channel", msg.Channel, "attempt", attempt, "retry_in", Backoff(attempt))
+		return true
+	case store.QueueStatusDead:
+		slog.Warn("queue: send failed, message dead-lettered", "channel", msg.Channel, "id", msg.QueueID, "attempts", attempt, "error", sendErr)
+	}
+	return false
+}
+
+func (q *Queue) fail(id uuid.UUID, lastErr string, retryAt time.Time, maxAttempts int) string {
+	ctx, cancel := context.WithTimeout(context.Background(), storeTimeout)
+	defer cancel()
+	status, err := q.store.Fail(ctx, id, lastErr, retryAt, maxAttempts)
+	if err != nil {
+		slog.Warn("queue: record failed delivery", "id", id, "error", err)
+		return ""
+	}
+	return status
+}
+
+func (q *Queue) channelTenant(channel string) uuid.UUID {
+	if q.cfg.ChannelTenant != nil {
+		if tid, ok := q.cfg.ChannelTenant(channel); ok && tid != uuid.Nil {
+			return tid
+		}
+	}
+	return store.MasterTenantID
+}
+
+// Backoff returns the delay before retry number attempt (1-based):
+// 5s doubling per attempt, capped at 10 minutes.
+func Backoff(attempt int) time.Duration {
+	d := baseBackoff
+	for i := 1; i < attempt && d < maxBackoff; i++ {
+		d *= 2
+	}
+	return min(d, maxBackoff)
+}
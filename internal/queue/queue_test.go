@@ -0,0 +1,301 @@
+package queue
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/nextlevelbuilder/goclaw/internal/bus"
+	"github.com/nextlevelbuilder/goclaw/internal/store"
+)
+
+// memStore is an in-memory store.MessageQueueStore.
+type memStore struct {
+	mu   sync.Mutex
+	rows map[uuid.UUID]*store.QueuedMessage
+}
+
+func newMemStore() *memStore {
+	return &memStore{rows: make(map[uuid.UUID]*store.QueuedMessage)}
+}
+
+func (s *memStore) Enqueue(_ context.Context, m *store.QueuedMessage) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if m.IdempotencyKey != "" {
+		for _, r := range s.rows {
+			if r.Direction == m.Direction && r.IdempotencyKey == m.IdempotencyKey {
+				return false, nil
+			}
+		}
+	}
+	m.ID = uuid.New()
+	m.Status = store.QueueStatusQueued
+	m.CreatedAt = time.Now()
+	cp := *m
+	s.rows[m.ID] = &cp
+	return true, nil
+}
+
+func (s *memStore) ClaimDue(_ context.Context, owner string, limit int) ([]store.QueuedMessage, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var out []store.QueuedMessage
+	for _, r := range s.rows {
+		if len(out) < limit && r.Status == store.QueueStatusPending && !r.NextAttemptAt.After(time.Now()) {
+			r.Status, r.Owner = store.QueueStatusQueued, owner
+			out = append(out, *r)
+		}
+	}
+	return out, nil
+}
+
+func (s *memStore) set(id uuid.UUID, fn func(r *store.QueuedMessage)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if r, ok := s.rows[id]; ok {
+		fn(r)
+	}
+}
+
+func (s *memStore) get(id uuid.UUID) store.QueuedMessage {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return *s.rows[id]
+}
+
+func (s *memStore) Complete(_ context.Context, id uuid.UUID) error {
+	s.set(id, func(r *store.QueuedMessage) { r.Status = store.QueueStatusDone })
+	return nil
+}
+
+func (s *memStore) Release(_ context.Context, id uuid.UUID, at time.Time) error {
+	s.set(id, func(r *store.QueuedMessage) {
+		if r.Status == store.QueueStatusQueued {
+			r.Status, r.NextAttemptAt = store.QueueStatusPending, at
+		}
+	})
+	return nil
+}
+
+func (s *memStore) Fail(_ context.Context, id uuid.UUID, lastErr string, retryAt time.Time, maxAttempts int) (string, error) {
+	var status string
+	s.set(id, func(r *store.QueuedMessage) {
+		r.Attempts++
+		r.LastError = lastErr
+		r.NextAttemptAt = retryAt
+		r.Status = store.QueueStatusPending
+		if r.Attempts >= maxAttempts {
+			r.Status = store.QueueStatusDead
+		}
+		status = r.Status
+	})
+	return status, nil
+}
+
+func (s *memStore) RecoverOwned(_ context.Context, owner string, maxAttempts int) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var n int64
+	for _, r := range s.rows {
+		if r.Owner == owner && r.Status == store.QueueStatusQueued {
+			r.Attempts++
+			r.Status, r.NextAttemptAt = store.QueueStatusPending, time.Now()
+			if r.Attempts >= maxAttempts {
+				r.Status = store.QueueStatusDead
+			}
+			n++
+		}
+	}
+	return n, nil
+}
+
+func (s *memStore) QueuedOwners(context.Context) ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	seen := map[string]bool{}
+	var owners []string
+	for _, r := range s.rows {
+		if r.Status == store.QueueStatusQueued && !seen[r.Owner] {
+			seen[r.Owner] = true
+			owners = append(owners, r.Owner)
+		}
+	}
+	return owners, nil
+}
+
+func (s *memStore) PruneDone(context.Context, time.Time) (int64, error) { return 0, nil }
+func (s *memStore) ListDead(context.Context, string, int, int) ([]store.QueuedMessage, int, error) {
+	return nil, 0, nil
+}
+func (s *memStore) Redrive(context.Context, uuid.UUID) error { return nil }
+func (s *memStore) Delete(context.Context, uuid.UUID) error  { return nil }
+
+func TestRecordInbound_DeduplicatesByMessageID(t *testing.T) {
+	s := newMemStore()
+	mb := bus.New()
+	mb.SetJournal(New(s, mb, Config{}))
+
+	msg := bus.InboundMessage{Channel: "telegram", SenderID: "u1", ChatID: "c1", Content: "hi", Metadata: map[string]string{"message_id": "42"}}
+	mb.PublishInbound(msg)
+	mb.PublishInbound(msg) // webhook retry
+
+	if in, _ := mb.QueueSizes(); in != 1 {
+		t.Fatalf("inbound queue = %d, want 1", in)
+	}
+	got, _ := mb.ConsumeInbound(context.Background())
+	if len(got.QueueIDs) != 1 {
+		t.Fatalf("QueueIDs = %v, want one id", got.QueueIDs)
+	}
+	if got.Metadata["message_id"] != "42" || got.Content != "hi" {
+		t.Errorf("consumed message = %+v", got)
+	}
+
+	mb.AckInbound(got)
+	if r := s.get(got.QueueIDs[0]); r.Status != store.QueueStatusDone {
+		t.Errorf("status after ack = %q, want done", r.Status)
+	}
+}
+
+func TestRecord_SkipsInternalAndEmptyMessages(t *testing.T) {
+	s := newMemStore()
+	mb := bus.New()
+	mb.SetJournal(New(s, mb, Config{}))
+
+	mb.PublishInbound(bus.InboundMessage{Channel: "system", Content: "announce"})
+	mb.PublishOutbound(bus.OutboundMessage{Channel: "telegram", ChatID: "c1"}) // indicator cleanup
+	if len(s.rows) != 0 {
+		t.Fatalf("journaled %d messages, want 0", len(s.rows))
+	}
+	if in, out := mb.QueueSizes(); in != 1 || out != 1 {
+		t.Errorf("queue sizes = %d/%d, want 1/1", in, out)
+	}
+}
+
+func TestFail_RetriesThenDeadLetters(t *testing.T) {
+	s := newMemStore()
+	mb := bus.New()
+	mb.SetJournal(New(s, mb, Config{MaxAttempts: 2}))
+
+	mb.PublishOutbound(bus.OutboundMessage{Channel: "telegram", ChatID: "c1", Content: "reply"})
+	msg, _ := mb.SubscribeOutbound(context.Background())
+	if msg.QueueID == uuid.Nil {
+		t.Fatal("outbound message not journaled")
+	}
+
+	sendErr := errors.New("telegram: 502")
+	if !mb.FailOutbound(msg, sendErr) {
+		t.Fatal("first failure should be retried")
+	}
+	r := s.get(msg.QueueID)
+	if r.Status != store.QueueStatusPending || r.LastError != sendErr.Error() {
+		t.Fatalf("after first failure: status %q error %q", r.Status, r.LastError)
+	}
+	if d := time.Until(r.NextAttemptAt); d < 4*time.Second || d > Backoff(1) {
+		t.Errorf("retry in %v, want about %v", d, Backoff(1))
+	}
+
+	msg.QueueAttempts = r.Attempts
+	if mb.FailOutbound(msg, sendErr) {
+		t.Fatal("second failure should dead-letter")
+	}
+	if r := s.get(msg.QueueID); r.Status != store.QueueStatusDead || r.Attempts != 2 {
+		t.Errorf("after second failure: status %q attempts %d", r.Status, r.Attempts)
+	}
+}
+
+func TestTryPublish_DefersWhenFull(t *testing.T) {
+	s := newMemStore()
+	mb := bus.New()
+	mb.SetJournal(New(s, mb, Config{}))
+
+	for i := 0; i < 1000; i++ {
+		mb.RedeliverOutbound(bus.OutboundMessage{Channel: "telegram"})
+	}
+	if !mb.TryPublishOutbound(bus.OutboundMessage{Channel: "telegram", ChatID: "c1", Content: "late"}) {
+		t.Fatal("TryPublishOutbound dropped a journaled message")
+	}
+	if len(s.rows) != 1 {
+		t.Fatalf("journaled %d messages, want 1", len(s.rows))
+	}
+	for _, r := range s.rows {
+		if r.Status != store.QueueStatusPending {
+			t.Errorf("deferred message status = %q, want pending", r.Status)
+		}
+	}
+}
+
+func TestStart_ReplaysInterruptedMessages(t *testing.T) {
+	s := newMemStore()
+
+	// A previous process journaled an inbound message and crashed mid-run.
+	crashed := bus.New()
+	crashed.SetJournal(New(s, crashed, Config{Owner: "node-a"}))
+	crashed.PublishInbound(bus.InboundMessage{Channel: "telegram", ChatID: "c1", Content: "hello", Metadata: map[string]string{"message_id": "7"}})
+
+	mb := bus.New()
+	q := New(s, mb, Config{Owner: "node-a"})
+	mb.SetJournal(q)
+	q.Start(context.Background())
+	defer q.Stop()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	got, ok := mb.ConsumeInbound(ctx)
+	if !ok {
+		t.Fatal("interrupted message was not replayed")
+	}
+	if got.Content != "hello" || len(got.QueueIDs) != 1 {
+		t.Fatalf("replayed message = %+v", got)
+	}
+	if r := s.get(got.QueueIDs[0]); r.Attempts != 1 || r.Status != store.QueueStatusQueued {
+		t.Errorf("replayed row: attempts %d status %q", r.Attempts, r.Status)
+	}
+}
+
+func TestStart_ReplaysMessagesOfGoneReplicas(t *testing.T) {
+	s := newMemStore()
+
+	// node-b is still running; node-old restarted under a new pid-based ID.
+	for _, owner := range []string{"node-b", "node-old"} {
+		mb := bus.New()
+		mb.SetJournal(New(s, mb, Config{Owner: owner}))
+		mb.PublishInbound(bus.InboundMessage{Channel: "telegram", ChatID: "c1", Content: "from " + owner})
+	}
+
+	mb := bus.New()
+	q := New(s, mb, Config{Owner: "node-a", OwnerAlive: func(owner string) bool { return owner == "node-b" }})
+	mb.SetJournal(q)
+	q.Start(context.Background())
+	defer q.Stop()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	got, ok := mb.ConsumeInbound(ctx)
+	if !ok || got.Content != "from node-old" {
+		t.Fatalf("replayed = %+v, %v; want the message of the gone replica", got, ok)
+	}
+	if r := s.get(got.QueueIDs[0]); r.Owner != "node-a" {
+		t.Errorf("replayed row owner = %q, want node-a", r.Owner)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, r := range s.rows {
+		if r.Owner == "node-b" && r.Status != store.QueueStatusQueued {
+			t.Errorf("live replica's message status = %q, want queued", r.Status)
+		}
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	cases := map[int]time.Duration{1: 5 * time.Second, 2: 10 * time.Second, 4: 40 * time.Second, 8: 10 * time.Minute, 50: 10 * time.Minute}
+	for attempt, want := range cases {
+		if got := Backoff(attempt); got != want {
+			t.Errorf("Backoff(%d) = %v, want %v", attempt, got, want)
+		}
+	}
+}
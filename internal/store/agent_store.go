@@ -3,6 +3,8 @@ package store
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"strings"
 
 	"github.com/google/uuid"
@@ -238,6 +240,96 @@ func (a *AgentData) ParseMaxTokens() int {
 	return cfg.MaxTokens
 }
 
+// ModelFallbackHop is one entry of an agent's ordered model fallback chain.
+// Model may be empty to use the provider's default model.
+type ModelFallbackHop struct {
+	Provider string `json:"provider"`
+	Model    string `json:"model,omitempty"`
+}
+
+// ParseFallbackChain extracts fallback_chain from other_config JSONB.
+// Entries without a provider and exact duplicates are dropped; returns nil if
+// not configured.
+func (a *AgentData) ParseFallbackChain() []ModelFallbackHop {
+	if len(a.OtherConfig) == 0 {
+		return nil
+	}
+	var cfg struct {
+		FallbackChain []ModelFallbackHop `json:"fallback_chain"`
+	}
+	if json.Unmarshal(a.OtherConfig, &cfg) != nil || len(cfg.FallbackChain) == 0 {
+		return nil
+	}
+	seen := make(map[ModelFallbackHop]bool, len(cfg.FallbackChain))
+	var chain []ModelFallbackHop
+	for _, hop := range cfg.FallbackChain {
+		hop.Provider = strings.TrimSpace(hop.Provider)
+		hop.Model = strings.TrimSpace(hop.Model)
+		if hop.Provider == "" || seen[hop] {
+			continue
+		}
+		seen[hop] = true
+		chain = append(chain, hop)
+	}
+	return chain
+}
+
+// ValidateFallbackChain checks the shape of other_config.fallback_chain.
+// ParseFallbackChain is lenient at runtime; writes should be strict so a typo
+// doesn't silently disable a hop.
+func ValidateFallbackChain(otherConfig json.RawMessage) error {
+	if len(otherConfig) == 0 {
+		return nil
+	}
+	var cfg struct {
+		FallbackChain json.RawMessage `json:"fallback_chain"`
+	}
+	if json.Unmarshal(otherConfig, &cfg) != nil || len(cfg.FallbackChain) == 0 || string(cfg.FallbackChain) == "null" {
+		return nil
+	}
+	var hops []ModelFallbackHop
+	if err := json.Unmarshal(cfg.FallbackChain, &hops); err != nil {
+		return errors.New("fallback_chain must be an array of {provider, model} objects")
+	}
+	for i, hop := range hops {
+		if strings.TrimSpace(hop.Provider) == "" {
+			return fmt.Errorf("fallback_chain[%d]: provider is required", i)
+		}
+	}
+	return nil
+}
+
+// ValidateFallbackChainProviders checks the shape of other_config.fallback_chain
+// (ValidateFallbackChain) and that every hop names a provider visible to the
+// tenant in ctx: its own or a master-tenant provider. Shared by the HTTP and
+// WebSocket agent write paths. A nil providerStore skips the existence check.
+func ValidateFallbackChainProviders(ctx context.Context, providerStore ProviderStore, otherConfig json.RawMessage) error {
+	if err := ValidateFallbackChain(otherConfig); err != nil {
+		return err
+	}
+	if providerStore == nil {
+		return nil
+	}
+	tenantIDs := []uuid.UUID{TenantIDFromContext(ctx)}
+	if tenantIDs[0] != MasterTenantID {
+		tenantIDs = append(tenantIDs, MasterTenantID)
+	}
+	agent := AgentData{OtherConfig: otherConfig}
+	for _, hop := range agent.ParseFallbackChain() {
+		found := false
+		for _, tid := range tenantIDs {
+			if _, err := providerStore.GetProviderByName(WithTenantID(ctx, tid), hop.Provider); err == nil {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("fallback_chain: provider %q not found", hop.Provider)
+		}
+	}
+	return nil
+}
+
 // ParseSelfEvolve extracts self_evolve from other_config JSONB.
 // When true, predefined agents can update their SOUL.md (style/tone) through chat.
 func (a *AgentData) ParseSelfEvolve() bool {
@@ -253,6 +345,22 @@ func (a *AgentData) ParseSelfEvolve() bool {
 	return cfg.SelfEvolve
 }
 
+// ParseRequireFileApproval extracts require_file_approval from other_config JSONB.
+// When true, the agent's own edits to SOUL.md, IDENTITY.md and AGENTS.md are
+// held as pending changes until the owner approves them.
+func (a *AgentData) ParseRequireFileApproval() bool {
+	if len(a.OtherConfig) == 0 {
+		return false
+	}
+	var cfg struct {
+		RequireFileApproval bool `json:"require_file_approval"`
+	}
+	if json.Unmarshal(a.OtherConfig, &cfg) != nil {
+		return false
+	}
+	return cfg.RequireFileApproval
+}
+
 // ParseSkillEvolve extracts skill_evolve from other_config JSONB.
 // When true, the agent's learning loop is enabled: system prompt includes skill
 // creation guidance, and the loop injects nudges at tool count milestones.
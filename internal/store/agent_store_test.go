@@ -1,9 +1,13 @@
 package store
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"reflect"
 	"testing"
+
+	"github.com/google/uuid"
 )
 
 func TestParseReasoningConfigDefaultsToOff(t *testing.T) {
@@ -362,3 +366,57 @@ func TestResolveEffectiveChatGPTOAuthRoutingIgnoresCustomMembersWhenProviderOwns
 		t.Fatalf("ExtraProviderNames = %#v, want provider defaults %#v", got.ExtraProviderNames, defaults.ExtraProviderNames)
 	}
 }
+
+func TestParseFallbackChainNormalizesEntries(t *testing.T) {
+	agent := &AgentData{
+		OtherConfig: json.RawMessage(`{"fallback_chain":[
+			{"provider":" openrouter ","model":"x"},
+			{"provider":"","model":"ignored"},
+			{"provider":"openrouter","model":"x"},
+			{"provider":"ollama"}
+		]}`),
+	}
+
+	got := agent.ParseFallbackChain()
+	want := []ModelFallbackHop{{Provider: "openrouter", Model: "x"}, {Provider: "ollama"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("ParseFallbackChain() = %#v, want %#v", got, want)
+	}
+}
+
+func TestParseFallbackChainUnsetReturnsNil(t *testing.T) {
+	agent := &AgentData{OtherConfig: json.RawMessage(`{"max_tokens":100}`)}
+	if got := agent.ParseFallbackChain(); got != nil {
+		t.Fatalf("ParseFallbackChain() = %#v, want nil", got)
+	}
+}
+
+type namedProviders struct {
+	ProviderStore // unused methods panic
+	byTenant      map[uuid.UUID]string
+}
+
+func (p namedProviders) GetProviderByName(ctx context.Context, name string) (*LLMProviderData, error) {
+	if p.byTenant[TenantIDFromContext(ctx)] == name {
+		return &LLMProviderData{Name: name}, nil
+	}
+	return nil, errors.New("not found")
+}
+
+func TestValidateFallbackChainProviders(t *testing.T) {
+	tenant := uuid.New()
+	ps := namedProviders{byTenant: map[uuid.UUID]string{tenant: "mine", MasterTenantID: "shared"}}
+	ctx := WithTenantID(context.Background(), tenant)
+
+	ok := json.RawMessage(`{"fallback_chain":[{"provider":"mine"},{"provider":"shared","model":"m"}]}`)
+	if err := ValidateFallbackChainProviders(ctx, ps, ok); err != nil {
+		t.Fatalf("own and master providers: %v", err)
+	}
+	unknown := json.RawMessage(`{"fallback_chain":[{"provider":"typo"}]}`)
+	if err := ValidateFallbackChainProviders(ctx, ps, unknown); err == nil {
+		t.Fatal("unknown provider accepted")
+	}
+	if err := ValidateFallbackChainProviders(ctx, nil, unknown); err != nil {
+		t.Fatalf("nil store must skip existence check: %v", err)
+	}
+}
@@ -153,6 +153,15 @@ func SelfEvolveFromContext(ctx context.Context) bool {
 	return false
 }
 
+// RequireFileApprovalFromContext reports whether the running agent's edits to
+// its identity files must be approved by the owner. Returns false outside agent runs.
+func RequireFileApprovalFromContext(ctx context.Context) bool {
+	if rc := RunContextFromCtx(ctx); rc != nil {
+		return rc.RequireFileApproval
+	}
+	return false
+}
+
 // WithSharedMemory returns a context flagged for shared memory (skip per-user scoping).
 func WithSharedMemory(ctx context.Context) context.Context {
 	return context.WithValue(ctx, SharedMemoryKey, true)
@@ -0,0 +1,116 @@
+package store
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Context file revision authors.
+const (
+	ContextFileAuthorAgent  = "agent"  // the agent edited the file itself (write_file/edit)
+	ContextFileAuthorUser   = "user"   // a non-admin user via RPC or the HTTP API
+	ContextFileAuthorAdmin  = "admin"  // an admin or owner via RPC or the HTTP API
+	ContextFileAuthorSystem = "system" // seeding, summoning, imports and other internal writes
+)
+
+// Pending context file change statuses.
+const (
+	ContextFileChangePending  = "pending"
+	ContextFileChangeApproved = "approved"
+	ContextFileChangeRejected = "rejected"
+)
+
+// ContextFileRevision is one recorded version of an agent-level or per-user
+// context file. A revision is written by AgentStore.SetAgentContextFile,
+// SetUserContextFile and PropagateContextFile whenever the content changes.
+type ContextFileRevision struct {
+	ID        uuid.UUID  `json:"id"`
+	AgentID   uuid.UUID  `json:"agent_id"`
+	UserID    string     `json:"user_id,omitempty"` // "" = agent-level file
+	FileName  string     `json:"file_name"`
+	Content   string     `json:"content"`
+	Author    string     `json:"author"`              // ContextFileAuthor*
+	AuthorID  string     `json:"author_id,omitempty"` // user ID of the writer, when known
+	TraceID   *uuid.UUID `json:"trace_id,omitempty"`  // agent run that made the edit
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// ContextFileChange is an agent-initiated edit to an identity file that is
+// held for owner approval (agents with require_file_approval).
+type ContextFileChange struct {
+	ID         uuid.UUID  `json:"id"`
+	AgentID    uuid.UUID  `json:"agent_id"`
+	UserID     string     `json:"user_id,omitempty"` // "" = agent-level file
+	FileName   string     `json:"file_name"`
+	Content    string     `json:"content"`
+	Author     string     `json:"author"`
+	AuthorID   string     `json:"author_id,omitempty"`
+	TraceID    *uuid.UUID `json:"trace_id,omitempty"`
+	Status     string     `json:"status"` // ContextFileChange*
+	ReviewedBy string     `json:"reviewed_by,omitempty"`
+	ReviewedAt *time.Time `json:"reviewed_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// ContextFileRevisionStore reads context file history and manages pending
+// changes. Revisions themselves are written by AgentStore. All methods are
+// tenant-scoped.
+type ContextFileRevisionStore interface {
+	// ListRevisions returns the revisions of one file, newest first.
+	// userID "" selects the agent-level file.
+	ListRevisions(ctx context.Context, agentID uuid.UUID, userID, fileName string, limit int) ([]ContextFileRevision, error)
+
+	// GetRevision returns a revision by ID, or (nil, nil) if not found.
+	GetRevision(ctx context.Context, id uuid.UUID) (*ContextFileRevision, error)
+
+	// CreatePendingChange stores c as pending. ID and CreatedAt are set.
+	CreatePendingChange(ctx context.Context, c *ContextFileChange) error
+
+	// ListPendingChanges returns an agent's pending changes, oldest first.
+	ListPendingChanges(ctx context.Context, agentID uuid.UUID) ([]ContextFileChange, error)
+
+	// GetPendingChange returns a change by ID (any status), or (nil, nil) if not found.
+	GetPendingChange(ctx context.Context, id uuid.UUID) (*ContextFileChange, error)
+
+	// ResolvePendingChange moves a pending change to approved or rejected.
+	// Returns false if the change does not exist or was already resolved.
+	ResolvePendingChange(ctx context.Context, id uuid.UUID, status, reviewedBy string) (bool, error)
+}
+
+type contextFileAuthorKey struct{}
+
+type contextFileAuthor struct {
+	author string
+	id     string
+}
+
+// WithContextFileAuthor tags context file writes made with ctx with the given
+// author kind (ContextFileAuthor*) and writer user ID.
+func WithContextFileAuthor(ctx context.Context, author, authorID string) context.Context {
+	return context.WithValue(ctx, contextFileAuthorKey{}, contextFileAuthor{author: author, id: authorID})
+}
+
+// ContextFileAuthorFromContext returns who is writing context files with ctx:
+// the author set by WithContextFileAuthor, otherwise "agent" inside agent runs,
+// "admin" or "user" by role for authenticated callers, and "system" otherwise.
+func ContextFileAuthorFromContext(ctx context.Context) (author, authorID string) {
+	if v, ok := ctx.Value(contextFileAuthorKey{}).(contextFileAuthor); ok {
+		return v.author, v.id
+	}
+	if rc := RunContextFromCtx(ctx); rc != nil {
+		if rc.SenderID != "" {
+			return ContextFileAuthorAgent, rc.SenderID
+		}
+		return ContextFileAuthorAgent, rc.UserID
+	}
+	switch RoleFromContext(ctx) {
+	case "":
+		return ContextFileAuthorSystem, ""
+	case RoleOwner, "admin":
+		return ContextFileAuthorAdmin, UserIDFromContext(ctx)
+	default:
+		return ContextFileAuthorUser, UserIDFromContext(ctx)
+	}
+}
@@ -0,0 +1,165 @@
+package store
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Eval assertion types.
+const (
+	EvalAssertContains    = "contains"     // output contains Value (case-insensitive)
+	EvalAssertNotContains = "not_contains" // output does not contain Value (case-insensitive)
+	EvalAssertRegex       = "regex"        // output matches the regular expression Value
+	EvalAssertJSONSchema  = "json_schema"  // output is JSON conforming to Schema
+	EvalAssertLLMJudge    = "llm_judge"    // a judge model grades the output against Rubric
+)
+
+// Eval tool modes.
+const (
+	EvalToolsStub = "stub" // every tool call returns a canned result; nothing is executed
+	EvalToolsLive = "live" // tools run for real (within the agent's sandbox); stubs still override
+)
+
+// Eval run statuses.
+const (
+	EvalRunRunning   = "running"
+	EvalRunCompleted = "completed"
+	EvalRunFailed    = "failed"
+)
+
+// EvalSuite is a set of golden conversations replayed against one agent.
+type EvalSuite struct {
+	ID          uuid.UUID  `json:"id"`
+	AgentID     uuid.UUID  `json:"agent_id"`
+	Name        string     `json:"name"`
+	Description string     `json:"description,omitempty"`
+	JudgeModel  string     `json:"judge_model,omitempty"` // model for llm_judge assertions ("" = the agent's model)
+	Cases       []EvalCase `json:"cases"`
+	CreatedBy   string     `json:"created_by,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+// EvalCase is one conversation: its turns run in order in a fresh session.
+type EvalCase struct {
+	Name  string         `json:"name"`
+	Turns []EvalTurn     `json:"turns"`
+	Stubs []EvalToolStub `json:"stubs,omitempty"`
+}
+
+// EvalTurn is one user message and what the agent's reply must satisfy.
+type EvalTurn struct {
+	Message     string                `json:"message"`
+	ExpectTools []EvalToolExpectation `json:"expect_tools,omitempty"`
+	Assertions  []EvalAssertion       `json:"assertions,omitempty"`
+}
+
+// EvalToolExpectation requires (or, with Absent, forbids) a tool call during
+// the turn. Args is a subset match: every listed key must be present with an
+// equal value.
+type EvalToolExpectation struct {
+	Name   string         `json:"name"`
+	Args   map[string]any `json:"args,omitempty"`
+	Absent bool           `json:"absent,omitempty"`
+}
+
+// EvalToolStub is the canned result for tool calls matching Name and the
+// subset Args. The first matching stub wins.
+type EvalToolStub struct {
+	Name    string         `json:"name"`
+	Args    map[string]any `json:"args,omitempty"`
+	Result  string         `json:"result"`
+	IsError bool           `json:"is_error,omitempty"`
+}
+
+// EvalAssertion is a check on a turn's final output.
+type EvalAssertion struct {
+	Type   string         `json:"type"` // EvalAssert*
+	Value  string         `json:"value,omitempty"`
+	Schema map[string]any `json:"schema,omitempty"`
+	Rubric string         `json:"rubric,omitempty"`
+}
+
+// EvalRun is one execution of a suite, with per-case results.
+type EvalRun struct {
+	ID           uuid.UUID        `json:"id"`
+	SuiteID      uuid.UUID        `json:"suite_id"`
+	AgentID      uuid.UUID        `json:"agent_id"`
+	SuiteName    string           `json:"suite_name"`
+	Status       string           `json:"status"`    // EvalRun*
+	ToolMode     string           `json:"tool_mode"` // EvalTools*
+	Total        int              `json:"total"`
+	Passed       int              `json:"passed"`
+	Failed       int              `json:"failed"`
+	InputTokens  int              `json:"input_tokens"`
+	OutputTokens int              `json:"output_tokens"`
+	CostUSD      float64          `json:"cost_usd"`
+	DurationMS   int              `json:"duration_ms"`
+	Error        string           `json:"error,omitempty"`
+	Results      []EvalCaseResult `json:"results,omitempty"`
+	CreatedBy    string           `json:"created_by,omitempty"`
+	StartedAt    time.Time        `json:"started_at"`
+	FinishedAt   *time.Time       `json:"finished_at,omitempty"`
+}
+
+// EvalCaseResult is the outcome of one case.
+type EvalCaseResult struct {
+	Name         string           `json:"name"`
+	Passed       bool             `json:"passed"`
+	Error        string           `json:"error,omitempty"`
+	InputTokens  int              `json:"input_tokens"`
+	OutputTokens int              `json:"output_tokens"`
+	CostUSD      float64          `json:"cost_usd"`
+	DurationMS   int              `json:"duration_ms"`
+	Turns        []EvalTurnResult `json:"turns"`
+}
+
+// EvalTurnResult records what the agent did for one turn.
+type EvalTurnResult struct {
+	Message    string         `json:"message"`
+	Output     string         `json:"output"`
+	TraceID    *uuid.UUID     `json:"trace_id,omitempty"`
+	ToolCalls  []EvalToolCall `json:"tool_calls,omitempty"`
+	Checks     []EvalCheck    `json:"checks"`
+	DurationMS int            `json:"duration_ms"`
+}
+
+// EvalToolCall is a tool call made during a turn.
+type EvalToolCall struct {
+	Name    string         `json:"name"`
+	Args    map[string]any `json:"args,omitempty"`
+	Stubbed bool           `json:"stubbed,omitempty"`
+}
+
+// EvalCheck is the verdict of one expectation or assertion.
+type EvalCheck struct {
+	Kind   string `json:"kind"` // "tool" or an EvalAssert* type
+	Passed bool   `json:"passed"`
+	Detail string `json:"detail,omitempty"`
+}
+
+// EvalStore persists eval suites and runs. All methods are tenant-scoped.
+type EvalStore interface {
+	// CreateSuite inserts s. ID and timestamps are set.
+	CreateSuite(ctx context.Context, s *EvalSuite) error
+	// UpdateSuite replaces the name, description, judge model and cases of s.ID.
+	UpdateSuite(ctx context.Context, s *EvalSuite) error
+	// GetSuite returns a suite by ID, or (nil, nil) if not found.
+	GetSuite(ctx context.Context, id uuid.UUID) (*EvalSuite, error)
+	// ListSuites returns an agent's suites ordered by name.
+	ListSuites(ctx context.Context, agentID uuid.UUID) ([]EvalSuite, error)
+	// DeleteSuite removes a suite and its runs.
+	DeleteSuite(ctx context.Context, id uuid.UUID) error
+
+	// CreateRun inserts r. ID is set.
+	CreateRun(ctx context.Context, r *EvalRun) error
+	// UpdateRun writes r's status, totals and results.
+	UpdateRun(ctx context.Context, r *EvalRun) error
+	// GetRun returns a run with its results, or (nil, nil) if not found.
+	GetRun(ctx context.Context, id uuid.UUID) (*EvalRun, error)
+	// ListRuns returns an agent's runs newest first, without per-case
+	// results. suiteID uuid.Nil lists runs of every suite.
+	ListRuns(ctx context.Context, agentID, suiteID uuid.UUID, limit int) ([]EvalRun, error)
+}
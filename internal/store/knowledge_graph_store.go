@@ -1,6 +1,12 @@
 package store
 
-import "context"
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
 
 // Entity represents a node in the knowledge graph.
 type Entity struct {
@@ -19,6 +25,11 @@ type Entity struct {
 }
 
 // Relation represents an edge between two entities.
+//
+// Relations are facts with a validity interval: ValidFrom is when the fact
+// started to hold (unix ms; defaults to ingestion time) and ValidTo when it
+// stopped (0 = still current). A fact closed because a contradicting one was
+// ingested points at its successor via SupersededBy.
 type Relation struct {
 	ID             string            `json:"id"`
 	AgentID        string            `json:"agent_id"`
@@ -28,9 +39,88 @@ type Relation struct {
 	TargetEntityID string            `json:"target_entity_id"`
 	Confidence     float64           `json:"confidence"`
 	Properties     map[string]string `json:"properties,omitempty"`
+	ValidFrom      int64             `json:"valid_from,omitempty"`
+	ValidTo        int64             `json:"valid_to,omitempty"`
+	SupersededBy   string            `json:"superseded_by,omitempty"`
+	Provenance     *KGProvenance     `json:"provenance,omitempty"`
 	CreatedAt      int64             `json:"created_at"`
 }
 
+// ValidAt reports whether the fact held at t (unix ms). t <= 0 means now,
+// i.e. the fact is current.
+func (r *Relation) ValidAt(t int64) bool {
+	if t <= 0 {
+		return r.ValidTo == 0
+	}
+	return r.ValidFrom <= t && (r.ValidTo == 0 || r.ValidTo > t)
+}
+
+// KG provenance kinds: where a fact was learned.
+const (
+	KGSourceMemory   = "memory"   // extracted from a memory document written during a session
+	KGSourceDocument = "document" // extracted from an uploaded or imported document
+	KGSourceManual   = "manual"   // submitted through the extract API
+	KGSourceImport   = "import"   // restored from an agent export archive
+)
+
+// KGProvenance links a fact to the session message or document it came from.
+type KGProvenance struct {
+	Kind       string `json:"kind,omitempty"`
+	SessionKey string `json:"session_key,omitempty"`
+	TraceID    string `json:"trace_id,omitempty"` // agent run that produced the source message
+	Document   string `json:"document,omitempty"` // memory path or document name
+}
+
+type kgProvenanceKey struct{}
+
+// WithKGProvenance attaches the provenance recorded on facts ingested with ctx
+// (relations that carry their own Provenance keep it).
+func WithKGProvenance(ctx context.Context, p KGProvenance) context.Context {
+	return context.WithValue(ctx, kgProvenanceKey{}, p)
+}
+
+// KGProvenanceFromContext returns the provenance set by WithKGProvenance, nil if none.
+func KGProvenanceFromContext(ctx context.Context) *KGProvenance {
+	if p, ok := ctx.Value(kgProvenanceKey{}).(KGProvenance); ok {
+		return &p
+	}
+	return nil
+}
+
+// exclusiveRelationTypes are single-valued per source entity: a person is
+// located in one place at a time. Ingesting a new target closes the current fact.
+var exclusiveRelationTypes = map[string]bool{
+	"located_in":    true,
+	"based_at":      true,
+	"reports_to":    true,
+	"scheduled_for": true,
+}
+
+// IsExclusiveRelation reports whether a new relation of this type supersedes
+// the current one from the same source entity.
+func IsExclusiveRelation(relationType string) bool {
+	return exclusiveRelationTypes[relationType]
+}
+
+// ParseKGTime parses a point in time for fact validity and as-of queries:
+// RFC 3339, a date ("2026-03-01"), a month ("2026-03"), a year, or unix
+// milliseconds. Empty input returns 0.
+func ParseKGTime(s string) (int64, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, nil
+	}
+	for _, layout := range []string{time.RFC3339Nano, "2006-01-02T15:04:05", "2006-01-02", "2006-01", "2006"} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t.UnixMilli(), nil
+		}
+	}
+	if ms, err := strconv.ParseInt(s, 10, 64); err == nil && ms > 0 {
+		return ms, nil
+	}
+	return 0, fmt.Errorf("invalid time %q: want YYYY-MM-DD, RFC 3339 or unix milliseconds", s)
+}
+
 // TraversalResult is a connected entity with path info.
 type TraversalResult struct {
 	Entity Entity   `json:"entity"`
@@ -71,15 +161,25 @@ type KnowledgeGraphStore interface {
 	ListEntities(ctx context.Context, agentID, userID string, opts EntityListOptions) ([]Entity, error)
 	SearchEntities(ctx context.Context, agentID, userID, query string, limit int) ([]Entity, error)
 
+	// UpsertRelation inserts a current fact or refreshes the matching current one.
 	UpsertRelation(ctx context.Context, relation *Relation) error
 	DeleteRelation(ctx context.Context, agentID, userID, relationID string) error
+	// ListRelations returns the current relations of an entity.
 	ListRelations(ctx context.Context, agentID, userID, entityID string) ([]Relation, error)
-	// ListAllRelations returns all relations for an agent (optionally scoped by user).
+	// ListRelationHistory returns every version of an entity's relations,
+	// including closed and superseded facts, newest ValidFrom first.
+	ListRelationHistory(ctx context.Context, agentID, userID, entityID string) ([]Relation, error)
+	// ListAllRelations returns all current relations for an agent (optionally scoped by user).
 	ListAllRelations(ctx context.Context, agentID, userID string, limit int) ([]Relation, error)
 
-	Traverse(ctx context.Context, agentID, userID, startEntityID string, maxDepth int) ([]TraversalResult, error)
+	// Traverse walks relations valid at asOf (unix ms; 0 = current facts only).
+	Traverse(ctx context.Context, agentID, userID, startEntityID string, maxDepth int, asOf int64) ([]TraversalResult, error)
 
 	// IngestExtraction upserts entities and relations from an LLM extraction.
+	// Relations with ValidTo set are recorded as ended facts; a current relation
+	// of an exclusive type (see IsExclusiveRelation) supersedes the source
+	// entity's current one with a different target. Relations without
+	// Provenance get the one from ctx (WithKGProvenance).
 	// Returns the DB UUIDs of all upserted entities for downstream processing (e.g. dedup).
 	IngestExtraction(ctx context.Context, agentID, userID string, entities []Entity, relations []Relation) ([]string, error)
 	PruneByConfidence(ctx context.Context, agentID, userID string, minConfidence float64) (int, error)
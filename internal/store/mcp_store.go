@@ -71,9 +71,42 @@ type MCPAccessRequest struct {
 
 // MCPAccessInfo combines server data with grant-level tool filters for runtime resolution.
 type MCPAccessInfo struct {
-	Server    MCPServerData `json:"server"`
-	ToolAllow []string      `json:"tool_allow,omitempty"` // effective allow list (nil = all)
-	ToolDeny  []string      `json:"tool_deny,omitempty"`  // effective deny list
+	Server       MCPServerData        `json:"server"`
+	ToolAllow    []string             `json:"tool_allow,omitempty"` // effective allow list (nil = all)
+	ToolDeny     []string             `json:"tool_deny,omitempty"`  // effective deny list
+	Capabilities MCPGrantCapabilities `json:"capabilities"`         // from the agent grant's config_overrides
+}
+
+// MCPGrantCapabilities controls which MCP capabilities beyond tools an agent
+// grant exposes. Stored in MCPAgentGrant.ConfigOverrides; everything is off
+// unless granted.
+type MCPGrantCapabilities struct {
+	Resources bool               `json:"resources,omitempty"` // list/read/subscribe resources tool
+	Prompts   bool               `json:"prompts,omitempty"`   // prompt templates (/prompt, mcp.prompts.*)
+	Sampling  *MCPSamplingPolicy `json:"sampling,omitempty"`  // server-initiated LLM requests
+}
+
+// MCPSamplingPolicy limits sampling requests a server may make through an agent.
+type MCPSamplingPolicy struct {
+	Enabled            bool   `json:"enabled"`
+	MaxTokens          int    `json:"max_tokens,omitempty"`            // per-request output cap (default 1024)
+	MaxRequestsPerHour int    `json:"max_requests_per_hour,omitempty"` // per agent+server (default 30)
+	Model              string `json:"model,omitempty"`                 // override the agent's model
+}
+
+// SamplingEnabled reports whether the grant allows sampling.
+func (c MCPGrantCapabilities) SamplingEnabled() bool {
+	return c.Sampling != nil && c.Sampling.Enabled
+}
+
+// ParseMCPGrantCapabilities reads the capability settings from a grant's
+// config_overrides JSON. Invalid or empty input grants nothing.
+func ParseMCPGrantCapabilities(configOverrides []byte) MCPGrantCapabilities {
+	var c MCPGrantCapabilities
+	if len(configOverrides) > 0 {
+		_ = json.Unmarshal(configOverrides, &c)
+	}
+	return c
 }
 
 // MCPUserCredentials holds per-user credential overrides for an MCP server.
@@ -83,6 +116,38 @@ type MCPUserCredentials struct {
 	Env     map[string]string `json:"env,omitempty"`      // decrypted
 }
 
+// MCPOAuthClient is the OAuth client registered with a remote MCP server's
+// authorization server (dynamic client registration), one per server.
+type MCPOAuthClient struct {
+	ClientID     string `json:"client_id"`
+	ClientSecret string `json:"client_secret,omitempty"` // decrypted; empty for public clients
+	RedirectURI  string `json:"redirect_uri"`            // registered callback; re-register when it changes
+}
+
+// MCPOAuthToken is a user's OAuth token for a remote MCP server.
+type MCPOAuthToken struct {
+	AccessToken  string    `json:"-"` // decrypted
+	RefreshToken string    `json:"-"` // decrypted
+	TokenType    string    `json:"token_type,omitempty"`
+	Scope        string    `json:"scope,omitempty"`
+	ExpiresAt    time.Time `json:"expires_at"` // zero = no expiry
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// MCPOAuthPending is an authorization a user has started and the
+// authorization server has not yet called back for. It is stored so that the
+// callback can be completed by any gateway replica.
+type MCPOAuthPending struct {
+	State     string
+	TenantID  uuid.UUID
+	ServerID  uuid.UUID
+	UserID    string
+	Verifier  string // PKCE code verifier, decrypted
+	Channel   string // chat to notify on completion; empty = none
+	ChatID    string
+	ExpiresAt time.Time
+}
+
 // MCPServerStore manages MCP server configs and access grants.
 type MCPServerStore interface {
 	// Server CRUD
@@ -118,4 +183,16 @@ type MCPServerStore interface {
 	GetUserCredentials(ctx context.Context, serverID uuid.UUID, userID string) (*MCPUserCredentials, error)
 	SetUserCredentials(ctx context.Context, serverID uuid.UUID, userID string, creds MCPUserCredentials) error
 	DeleteUserCredentials(ctx context.Context, serverID uuid.UUID, userID string) error
+
+	// OAuth (remote servers using OAuth 2.1 authorization)
+	GetOAuthClient(ctx context.Context, serverID uuid.UUID) (*MCPOAuthClient, error)
+	SetOAuthClient(ctx context.Context, serverID uuid.UUID, c MCPOAuthClient) error
+	GetOAuthToken(ctx context.Context, serverID uuid.UUID, userID string) (*MCPOAuthToken, error)
+	SetOAuthToken(ctx context.Context, serverID uuid.UUID, userID string, t MCPOAuthToken) error
+	DeleteOAuthToken(ctx context.Context, serverID uuid.UUID, userID string) error
+	// CreateOAuthPending stores a started authorization and drops expired ones.
+	CreateOAuthPending(ctx context.Context, p MCPOAuthPending) error
+	// TakeOAuthPending removes and returns the unexpired authorization with the
+	// given state, across tenants. Returns (nil, nil) if there is none.
+	TakeOAuthPending(ctx context.Context, state string) (*MCPOAuthPending, error)
 }
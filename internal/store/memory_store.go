@@ -19,7 +19,8 @@ type MemorySearchResult struct {
 	Score     float64 `json:"score"`
 	Snippet   string  `json:"snippet"`
 	Source    string  `json:"source"`
-	Scope     string  `json:"scope,omitempty"` // "global" or "personal"
+	Scope     string  `json:"scope,omitempty"`      // "global" or "personal"
+	UpdatedAt int64   `json:"updated_at,omitempty"` // unix ms of the source document's last change (0 = unknown)
 }
 
 // MemorySearchOptions configures a memory search query.
@@ -0,0 +1,89 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Message queue directions.
+const (
+	QueueDirectionInbound  = "inbound"
+	QueueDirectionOutbound = "outbound"
+)
+
+// Message queue statuses.
+const (
+	QueueStatusQueued  = "queued"  // handed to an in-memory bus queue on Owner
+	QueueStatusPending = "pending" // waiting in the table for (re)delivery at NextAttemptAt
+	QueueStatusDone    = "done"    // delivered; kept until pruned for idempotency checks
+	QueueStatusDead    = "dead"    // gave up after MaxAttempts; shown in the dead-letter view
+)
+
+// QueuedMessage is a bus message persisted by the durable message queue.
+type QueuedMessage struct {
+	ID             uuid.UUID       `json:"id"`
+	TenantID       uuid.UUID       `json:"tenant_id"`
+	Direction      string          `json:"direction"`
+	Channel        string          `json:"channel"`
+	IdempotencyKey string          `json:"idempotency_key,omitempty"`
+	Payload        json.RawMessage `json:"payload"` // bus.InboundMessage or bus.OutboundMessage
+	Status         string          `json:"status"`
+	Attempts       int             `json:"attempts"`
+	LastError      string          `json:"last_error,omitempty"`
+	Owner          string          `json:"owner,omitempty"` // gateway replica holding it in memory ("" = standalone)
+	NextAttemptAt  time.Time       `json:"next_attempt_at"`
+	CreatedAt      time.Time       `json:"created_at"`
+	UpdatedAt      time.Time       `json:"updated_at"`
+}
+
+// MessageQueueStore persists bus messages for at-least-once delivery.
+// Queue operations are unscoped (the queue serves every tenant); the
+// dead-letter methods are tenant-scoped.
+type MessageQueueStore interface {
+	// Enqueue inserts msg as queued by msg.Owner. It returns false without
+	// inserting when a message with the same direction and non-empty
+	// idempotency key already exists.
+	Enqueue(ctx context.Context, msg *QueuedMessage) (bool, error)
+
+	// ClaimDue moves up to limit pending messages whose NextAttemptAt has
+	// passed to queued (owned by owner) and returns them, oldest first.
+	ClaimDue(ctx context.Context, owner string, limit int) ([]QueuedMessage, error)
+
+	// Complete marks a message done.
+	Complete(ctx context.Context, id uuid.UUID) error
+
+	// Release returns a queued message to pending at the given time without
+	// counting an attempt (the in-memory queue was full).
+	Release(ctx context.Context, id uuid.UUID, at time.Time) error
+
+	// Fail records a failed delivery attempt. The message is pending again
+	// until retryAt, or dead once its attempts reach maxAttempts. Returns the
+	// resulting status.
+	Fail(ctx context.Context, id uuid.UUID, lastErr string, retryAt time.Time, maxAttempts int) (string, error)
+
+	// RecoverOwned returns messages left queued by owner (a previous run of
+	// this replica that crashed) to pending, counting the interrupted attempt.
+	// Messages that reach maxAttempts become dead.
+	RecoverOwned(ctx context.Context, owner string, maxAttempts int) (int64, error)
+
+	// QueuedOwners returns the distinct owners of queued messages, i.e. the
+	// replicas holding messages in memory.
+	QueuedOwners(ctx context.Context) ([]string, error)
+
+	// PruneDone deletes done messages last updated before cutoff.
+	PruneDone(ctx context.Context, cutoff time.Time) (int64, error)
+
+	// ListDead returns dead messages, newest first, and the total count.
+	// direction "" lists both directions.
+	ListDead(ctx context.Context, direction string, limit, offset int) ([]QueuedMessage, int, error)
+
+	// Redrive moves a dead message back to pending for immediate delivery,
+	// resetting its attempts.
+	Redrive(ctx context.Context, id uuid.UUID) error
+
+	// Delete removes a dead message.
+	Delete(ctx context.Context, id uuid.UUID) error
+}
@@ -43,14 +43,36 @@ func (s *PGAgentStore) GetAgentContextFiles(ctx context.Context, agentID uuid.UU
 	return result, nil
 }
 
+// SetAgentContextFile upserts an agent-level file and records a revision when
+// the content changed.
 func (s *PGAgentStore) SetAgentContextFile(ctx context.Context, agentID uuid.UUID, fileName, content string) error {
-	_, err := s.db.ExecContext(ctx,
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var prev sql.NullString
+	err = tx.QueryRowContext(ctx,
+		"SELECT content FROM agent_context_files WHERE agent_id = $1 AND file_name = $2 FOR UPDATE",
+		agentID, fileName).Scan(&prev)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx,
 		`INSERT INTO agent_context_files (id, agent_id, file_name, content, updated_at, tenant_id)
 		 VALUES ($1, $2, $3, $4, $5, $6)
 		 ON CONFLICT (agent_id, file_name) DO UPDATE SET content = EXCLUDED.content, updated_at = EXCLUDED.updated_at`,
 		store.GenNewID(), agentID, fileName, content, time.Now(), tenantIDForInsert(ctx),
-	)
-	return err
+	); err != nil {
+		return err
+	}
+	if !prev.Valid || prev.String != content {
+		if err := insertContextFileRevision(ctx, tx, agentID, "", fileName, content); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
 }
 
 // PropagateContextFile copies an agent-level context file to all existing user
@@ -60,8 +82,31 @@ func (s *PGAgentStore) PropagateContextFile(ctx context.Context, agentID uuid.UU
 	if err != nil {
 		return 0, err
 	}
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	// Record a revision for every user copy whose content is about to change.
+	uClause, uArgs, _, err := scopeClauseAlias(ctx, 6, "u")
+	if err != nil {
+		return 0, err
+	}
+	author, authorID := store.ContextFileAuthorFromContext(ctx)
+	if _, err := tx.ExecContext(ctx,
+		`INSERT INTO context_file_revisions (tenant_id, agent_id, user_id, file_name, content, author, author_id, created_at)
+		 SELECT u.tenant_id, u.agent_id, u.user_id, u.file_name, a.content, $3, $4, $5
+		 FROM user_context_files u
+		 JOIN agent_context_files a ON a.agent_id = u.agent_id AND a.file_name = u.file_name
+		 WHERE u.agent_id = $1 AND u.file_name = $2 AND u.content <> a.content`+uClause,
+		append([]any{agentID, fileName, author, authorID, time.Now()}, uArgs...)...,
+	); err != nil {
+		return 0, err
+	}
+
 	// $4 (tenant_id) is referenced twice in the query but only needs one arg value.
-	res, err := s.db.ExecContext(ctx,
+	res, err := tx.ExecContext(ctx,
 		`UPDATE user_context_files
 		 SET content = src.content, updated_at = $3
 		 FROM (
@@ -76,7 +121,7 @@ func (s *PGAgentStore) PropagateContextFile(ctx context.Context, agentID uuid.UU
 		return 0, err
 	}
 	n, _ := res.RowsAffected()
-	return int(n), nil
+	return int(n), tx.Commit()
 }
 
 // --- Per-user Context Files ---
@@ -105,14 +150,36 @@ func (s *PGAgentStore) GetUserContextFiles(ctx context.Context, agentID uuid.UUI
 	return result, nil
 }
 
+// SetUserContextFile upserts a per-user file and records a revision when the
+// content changed.
 func (s *PGAgentStore) SetUserContextFile(ctx context.Context, agentID uuid.UUID, userID, fileName, content string) error {
-	_, err := s.db.ExecContext(ctx,
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var prev sql.NullString
+	err = tx.QueryRowContext(ctx,
+		"SELECT content FROM user_context_files WHERE agent_id = $1 AND user_id = $2 AND file_name = $3 FOR UPDATE",
+		agentID, userID, fileName).Scan(&prev)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx,
 		`INSERT INTO user_context_files (id, agent_id, user_id, file_name, content, updated_at, tenant_id)
 		 VALUES ($1, $2, $3, $4, $5, $6, $7)
 		 ON CONFLICT (agent_id, user_id, file_name) DO UPDATE SET content = EXCLUDED.content, updated_at = EXCLUDED.updated_at`,
 		store.GenNewID(), agentID, userID, fileName, content, time.Now(), tenantIDForInsert(ctx),
-	)
-	return err
+	); err != nil {
+		return err
+	}
+	if !prev.Valid || prev.String != content {
+		if err := insertContextFileRevision(ctx, tx, agentID, userID, fileName, content); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
 }
 
 func (s *PGAgentStore) ListUserContextFilesByName(ctx context.Context, agentID uuid.UUID, fileName string) ([]store.UserContextFileData, error) {
@@ -263,8 +263,7 @@ func ExportKGRelations(ctx context.Context, db *sql.DB, agentID uuid.UUID) ([]st
 	for {
 		args := append(append([]any{}, baseArgs...), cursor, exportBatchSize)
 		rows, err := db.QueryContext(ctx,
-			"SELECT id, agent_id, user_id, source_entity_id, relation_type, target_entity_id,"+
-				" confidence, properties, created_at"+
+			"SELECT "+kgRelationCols+
 				" FROM kg_relations WHERE agent_id = $1"+tc+
 				" AND id > $"+itoa(cursorParam)+
 				" ORDER BY id LIMIT $"+itoa(limitParam),
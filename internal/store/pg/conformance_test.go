@@ -0,0 +1,29 @@
+package pg
+
+import (
+	"os"
+	"testing"
+
+	"github.com/nextlevelbuilder/goclaw/internal/store"
+	"github.com/nextlevelbuilder/goclaw/internal/store/storetest"
+)
+
+// TestPGStores_Conformance runs the shared store suite against a migrated
+// Postgres database. Skipped unless PG_TEST_DSN is set.
+func TestPGStores_Conformance(t *testing.T) {
+	dsn := os.Getenv("PG_TEST_DSN")
+	if dsn == "" {
+		t.Skip("PG_TEST_DSN not set")
+	}
+	stores, err := NewPGStores(store.StoreConfig{
+		PostgresDSN:      dsn,
+		SkillsStorageDir: t.TempDir(),
+		EncryptionKey:    "01234567890123456789012345678901",
+	})
+	if err != nil {
+		t.Skipf("Postgres not available: %v", err)
+	}
+	t.Cleanup(func() { _ = stores.DB.Close() })
+
+	storetest.Run(t, stores)
+}
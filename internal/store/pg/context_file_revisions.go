@@ -0,0 +1,170 @@
+package pg
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/nextlevelbuilder/goclaw/internal/store"
+	"github.com/nextlevelbuilder/goclaw/internal/tracing"
+)
+
+// PGContextFileRevisionStore implements store.ContextFileRevisionStore.
+type PGContextFileRevisionStore struct {
+	db *sql.DB
+}
+
+func NewPGContextFileRevisionStore(db *sql.DB) *PGContextFileRevisionStore {
+	return &PGContextFileRevisionStore{db: db}
+}
+
+// insertContextFileRevision records content as a revision of a context file,
+// attributed to the writer in ctx. userID "" = agent-level file.
+func insertContextFileRevision(ctx context.Context, tx *sql.Tx, agentID uuid.UUID, userID, fileName, content string) error {
+	author, authorID := store.ContextFileAuthorFromContext(ctx)
+	traceID := tracing.TraceIDFromContext(ctx)
+	_, err := tx.ExecContext(ctx,
+		`INSERT INTO context_file_revisions (id, tenant_id, agent_id, user_id, file_name, content, author, author_id, trace_id, created_at)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`,
+		store.GenNewID(), tenantIDForInsert(ctx), agentID, userID, fileName, content, author, authorID,
+		nilUUID(&traceID), time.Now(),
+	)
+	return err
+}
+
+const contextFileRevisionCols = `id, agent_id, user_id, file_name, content, author, author_id, trace_id, created_at`
+
+func scanContextFileRevision(row interface{ Scan(...any) error }) (store.ContextFileRevision, error) {
+	var r store.ContextFileRevision
+	err := row.Scan(&r.ID, &r.AgentID, &r.UserID, &r.FileName, &r.Content, &r.Author, &r.AuthorID, &r.TraceID, &r.CreatedAt)
+	return r, err
+}
+
+func (s *PGContextFileRevisionStore) ListRevisions(ctx context.Context, agentID uuid.UUID, userID, fileName string, limit int) ([]store.ContextFileRevision, error) {
+	if limit <= 0 {
+		limit = 50
+	}
+	tClause, tArgs, _, err := scopeClause(ctx, 5)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT `+contextFileRevisionCols+` FROM context_file_revisions
+		 WHERE agent_id = $1 AND user_id = $2 AND file_name = $3`+tClause+`
+		 ORDER BY created_at DESC, id DESC LIMIT $4`,
+		append([]any{agentID, userID, fileName, limit}, tArgs...)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []store.ContextFileRevision
+	for rows.Next() {
+		r, err := scanContextFileRevision(rows)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, r)
+	}
+	return result, rows.Err()
+}
+
+func (s *PGContextFileRevisionStore) GetRevision(ctx context.Context, id uuid.UUID) (*store.ContextFileRevision, error) {
+	tClause, tArgs, _, err := scopeClause(ctx, 2)
+	if err != nil {
+		return nil, err
+	}
+	r, err := scanContextFileRevision(s.db.QueryRowContext(ctx,
+		`SELECT `+contextFileRevisionCols+` FROM context_file_revisions WHERE id = $1`+tClause,
+		append([]any{id}, tArgs...)...))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
+
+const contextFileChangeCols = `id, agent_id, user_id, file_name, content, author, author_id, trace_id, status, reviewed_by, reviewed_at, created_at`
+
+func scanContextFileChange(row interface{ Scan(...any) error }) (store.ContextFileChange, error) {
+	var c store.ContextFileChange
+	err := row.Scan(&c.ID, &c.AgentID, &c.UserID, &c.FileName, &c.Content, &c.Author, &c.AuthorID, &c.TraceID,
+		&c.Status, &c.ReviewedBy, &c.ReviewedAt, &c.CreatedAt)
+	return c, err
+}
+
+func (s *PGContextFileRevisionStore) CreatePendingChange(ctx context.Context, c *store.ContextFileChange) error {
+	c.ID = store.GenNewID()
+	c.Status = store.ContextFileChangePending
+	c.CreatedAt = time.Now()
+	_, err := s.db.ExecContext(ctx,
+		`INSERT INTO context_file_changes (id, tenant_id, agent_id, user_id, file_name, content, author, author_id, trace_id, status, created_at)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)`,
+		c.ID, tenantIDForInsert(ctx), c.AgentID, c.UserID, c.FileName, c.Content, c.Author, c.AuthorID, c.TraceID, c.Status, c.CreatedAt,
+	)
+	return err
+}
+
+func (s *PGContextFileRevisionStore) ListPendingChanges(ctx context.Context, agentID uuid.UUID) ([]store.ContextFileChange, error) {
+	tClause, tArgs, _, err := scopeClause(ctx, 3)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT `+contextFileChangeCols+` FROM context_file_changes
+		 WHERE agent_id = $1 AND status = $2`+tClause+`
+		 ORDER BY created_at, id`,
+		append([]any{agentID, store.ContextFileChangePending}, tArgs...)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []store.ContextFileChange
+	for rows.Next() {
+		c, err := scanContextFileChange(rows)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, c)
+	}
+	return result, rows.Err()
+}
+
+func (s *PGContextFileRevisionStore) GetPendingChange(ctx context.Context, id uuid.UUID) (*store.ContextFileChange, error) {
+	tClause, tArgs, _, err := scopeClause(ctx, 2)
+	if err != nil {
+		return nil, err
+	}
+	c, err := scanContextFileChange(s.db.QueryRowContext(ctx,
+		`SELECT `+contextFileChangeCols+` FROM context_file_changes WHERE id = $1`+tClause,
+		append([]any{id}, tArgs...)...))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+func (s *PGContextFileRevisionStore) ResolvePendingChange(ctx context.Context, id uuid.UUID, status, reviewedBy string) (bool, error) {
+	tClause, tArgs, _, err := scopeClause(ctx, 6)
+	if err != nil {
+		return false, err
+	}
+	res, err := s.db.ExecContext(ctx,
+		`UPDATE context_file_changes SET status = $2, reviewed_by = $3, reviewed_at = $4
+		 WHERE id = $1 AND status = $5`+tClause,
+		append([]any{id, status, reviewedBy, time.Now(), store.ContextFileChangePending}, tArgs...)...)
+	if err != nil {
+		return false, err
+	}
+	n, _ := res.RowsAffected()
+	return n > 0, nil
+}
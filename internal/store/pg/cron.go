@@ -22,6 +22,7 @@ type PGCronStore struct {
 	cancelCtx context.CancelFunc // cancelled on Stop()
 	onJob     func(job *store.CronJob) (*store.CronJobResult, error)
 	onEvent   func(event store.CronEvent)
+	onChange  func() // after CRUD writes (see SetOnChange)
 	running   bool
 	stop      chan struct{}
 
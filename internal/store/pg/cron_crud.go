@@ -77,7 +77,7 @@ func (s *PGCronStore) AddJob(ctx context.Context, name string, schedule store.Cr
 		return nil, fmt.Errorf("create cron job: %w", err)
 	}
 
-	s.cacheLoaded = false // invalidate cache
+	s.jobsChanged()
 
 	job, _ := s.GetJob(ctx, id.String())
 	return job, nil
@@ -176,7 +176,7 @@ func (s *PGCronStore) RemoveJob(ctx context.Context, jobID string) error {
 	if n, _ := res.RowsAffected(); n == 0 {
 		return fmt.Errorf("job not found")
 	}
-	s.cacheLoaded = false
+	s.jobsChanged()
 	return nil
 }
 
@@ -215,6 +215,6 @@ func (s *PGCronStore) EnableJob(ctx context.Context, jobID string, enabled bool)
 		return err
 	}
 
-	s.InvalidateCache()
+	s.jobsChanged()
 	return nil
 }
@@ -64,6 +64,26 @@ func (s *PGCronStore) InvalidateCache() {
 	s.mu.Unlock()
 }
 
+// SetOnChange registers a callback run after jobs are created, updated,
+// toggled or removed. The gateway uses it to broadcast a cron cache
+// invalidation so the replica running the scheduler drops its job cache too.
+func (s *PGCronStore) SetOnChange(fn func()) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.onChange = fn
+}
+
+// jobsChanged drops the job cache after a CRUD write and runs the onChange callback.
+func (s *PGCronStore) jobsChanged() {
+	s.mu.Lock()
+	s.cacheLoaded = false
+	fn := s.onChange
+	s.mu.Unlock()
+	if fn != nil {
+		fn()
+	}
+}
+
 // recomputeStaleJobs fixes enabled jobs that have next_run_at = NULL.
 // This happens when the gateway was stopped/crashed while a job was executing,
 // or when the previously computed next_run_at was consumed but never recomputed.
@@ -109,7 +109,7 @@ func (s *PGCronStore) UpdateJob(ctx context.Context, jobID string, patch store.C
 		return nil, err
 	}
 
-	s.InvalidateCache()
+	s.jobsChanged()
 	job, _ := s.scanJob(ctx, id)
 	return job, nil
 }
@@ -0,0 +1,215 @@
+package pg
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/nextlevelbuilder/goclaw/internal/store"
+)
+
+// PGEvalStore implements store.EvalStore.
+type PGEvalStore struct {
+	db *sql.DB
+}
+
+func NewPGEvalStore(db *sql.DB) *PGEvalStore {
+	return &PGEvalStore{db: db}
+}
+
+// marshalJSONList encodes a slice for a JSONB list column; nil becomes [].
+func marshalJSONList[T any](v []T) []byte {
+	if len(v) == 0 {
+		return []byte("[]")
+	}
+	b, _ := json.Marshal(v)
+	return b
+}
+
+const evalSuiteCols = `id, agent_id, name, description, judge_model, cases, created_by, created_at, updated_at`
+
+func scanEvalSuite(row interface{ Scan(...any) error }) (store.EvalSuite, error) {
+	var s store.EvalSuite
+	var cases []byte
+	err := row.Scan(&s.ID, &s.AgentID, &s.Name, &s.Description, &s.JudgeModel, &cases, &s.CreatedBy, &s.CreatedAt, &s.UpdatedAt)
+	if err == nil && len(cases) > 0 {
+		err = json.Unmarshal(cases, &s.Cases)
+	}
+	return s, err
+}
+
+func (s *PGEvalStore) CreateSuite(ctx context.Context, suite *store.EvalSuite) error {
+	suite.ID = store.GenNewID()
+	suite.CreatedAt = time.Now()
+	suite.UpdatedAt = suite.CreatedAt
+	_, err := s.db.ExecContext(ctx,
+		`INSERT INTO eval_suites (id, tenant_id, agent_id, name, description, judge_model, cases, created_by, created_at, updated_at)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`,
+		suite.ID, tenantIDForInsert(ctx), suite.AgentID, suite.Name, suite.Description, suite.JudgeModel,
+		marshalJSONList(suite.Cases), suite.CreatedBy, suite.CreatedAt, suite.UpdatedAt,
+	)
+	return err
+}
+
+func (s *PGEvalStore) UpdateSuite(ctx context.Context, suite *store.EvalSuite) error {
+	tClause, tArgs, _, err := scopeClause(ctx, 7)
+	if err != nil {
+		return err
+	}
+	suite.UpdatedAt = time.Now()
+	_, err = s.db.ExecContext(ctx,
+		`UPDATE eval_suites SET name = $2, description = $3, judge_model = $4, cases = $5, updated_at = $6
+		 WHERE id = $1`+tClause,
+		append([]any{suite.ID, suite.Name, suite.Description, suite.JudgeModel, marshalJSONList(suite.Cases), suite.UpdatedAt}, tArgs...)...)
+	return err
+}
+
+func (s *PGEvalStore) GetSuite(ctx context.Context, id uuid.UUID) (*store.EvalSuite, error) {
+	tClause, tArgs, _, err := scopeClause(ctx, 2)
+	if err != nil {
+		return nil, err
+	}
+	suite, err := scanEvalSuite(s.db.QueryRowContext(ctx,
+		`SELECT `+evalSuiteCols+` FROM eval_suites WHERE id = $1`+tClause,
+		append([]any{id}, tArgs...)...))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &suite, nil
+}
+
+func (s *PGEvalStore) ListSuites(ctx context.Context, agentID uuid.UUID) ([]store.EvalSuite, error) {
+	tClause, tArgs, _, err := scopeClause(ctx, 2)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT `+evalSuiteCols+` FROM eval_suites WHERE agent_id = $1`+tClause+` ORDER BY name`,
+		append([]any{agentID}, tArgs...)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []store.EvalSuite
+	for rows.Next() {
+		suite, err := scanEvalSuite(rows)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, suite)
+	}
+	return result, rows.Err()
+}
+
+func (s *PGEvalStore) DeleteSuite(ctx context.Context, id uuid.UUID) error {
+	tClause, tArgs, _, err := scopeClause(ctx, 2)
+	if err != nil {
+		return err
+	}
+	_, err = s.db.ExecContext(ctx, `DELETE FROM eval_suites WHERE id = $1`+tClause, append([]any{id}, tArgs...)...)
+	return err
+}
+
+const evalRunSummaryCols = `id, suite_id, agent_id, suite_name, status, tool_mode, total, passed, failed,
+	input_tokens, output_tokens, cost_usd, duration_ms, error, created_by, started_at, finished_at`
+
+func scanEvalRun(row interface{ Scan(...any) error }, withResults bool) (store.EvalRun, error) {
+	var r store.EvalRun
+	var results []byte
+	dest := []any{&r.ID, &r.SuiteID, &r.AgentID, &r.SuiteName, &r.Status, &r.ToolMode, &r.Total, &r.Passed, &r.Failed,
+		&r.InputTokens, &r.OutputTokens, &r.CostUSD, &r.DurationMS, &r.Error, &r.CreatedBy, &r.StartedAt, &r.FinishedAt}
+	if withResults {
+		dest = append(dest, &results)
+	}
+	err := row.Scan(dest...)
+	if err == nil && len(results) > 0 {
+		err = json.Unmarshal(results, &r.Results)
+	}
+	return r, err
+}
+
+func (s *PGEvalStore) CreateRun(ctx context.Context, r *store.EvalRun) error {
+	r.ID = store.GenNewID()
+	if r.StartedAt.IsZero() {
+		r.StartedAt = time.Now()
+	}
+	_, err := s.db.ExecContext(ctx,
+		`INSERT INTO eval_runs (id, tenant_id, suite_id, agent_id, suite_name, status, tool_mode, total, created_by, started_at)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`,
+		r.ID, tenantIDForInsert(ctx), r.SuiteID, r.AgentID, r.SuiteName, r.Status, r.ToolMode, r.Total, r.CreatedBy, r.StartedAt,
+	)
+	return err
+}
+
+func (s *PGEvalStore) UpdateRun(ctx context.Context, r *store.EvalRun) error {
+	tClause, tArgs, _, err := scopeClause(ctx, 13)
+	if err != nil {
+		return err
+	}
+	_, err = s.db.ExecContext(ctx,
+		`UPDATE eval_runs SET status = $2, total = $3, passed = $4, failed = $5, input_tokens = $6, output_tokens = $7,
+			cost_usd = $8, duration_ms = $9, error = $10, results = $11, finished_at = $12
+		 WHERE id = $1`+tClause,
+		append([]any{r.ID, r.Status, r.Total, r.Passed, r.Failed, r.InputTokens, r.OutputTokens,
+			r.CostUSD, r.DurationMS, r.Error, marshalJSONList(r.Results), r.FinishedAt}, tArgs...)...)
+	return err
+}
+
+func (s *PGEvalStore) GetRun(ctx context.Context, id uuid.UUID) (*store.EvalRun, error) {
+	tClause, tArgs, _, err := scopeClause(ctx, 2)
+	if err != nil {
+		return nil, err
+	}
+	r, err := scanEvalRun(s.db.QueryRowContext(ctx,
+		`SELECT `+evalRunSummaryCols+`, results FROM eval_runs WHERE id = $1`+tClause,
+		append([]any{id}, tArgs...)...), true)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
+
+func (s *PGEvalStore) ListRuns(ctx context.Context, agentID, suiteID uuid.UUID, limit int) ([]store.EvalRun, error) {
+	if limit <= 0 {
+		limit = 50
+	}
+	where := "agent_id = $1"
+	args := []any{agentID, limit}
+	if suiteID != uuid.Nil {
+		where += " AND suite_id = $3"
+		args = append(args, suiteID)
+	}
+	tClause, tArgs, _, err := scopeClause(ctx, len(args)+1)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT `+evalRunSummaryCols+` FROM eval_runs WHERE `+where+tClause+`
+		 ORDER BY started_at DESC, id DESC LIMIT $2`,
+		append(args, tArgs...)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []store.EvalRun
+	for rows.Next() {
+		r, err := scanEvalRun(rows, false)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, r)
+	}
+	return result, rows.Err()
+}
@@ -51,5 +51,8 @@ func NewPGStores(cfg store.StoreConfig) (*store.Stores, error) {
 		SkillTenantCfgs:       NewPGSkillTenantConfigStore(db),
 		SystemConfigs:         NewPGSystemConfigStore(db),
 		SubagentTasks:         NewPGSubagentTaskStore(db),
+		MessageQueue:          NewPGMessageQueueStore(db),
+		ContextFileRevisions:  NewPGContextFileRevisionStore(db),
+		Evals:                 NewPGEvalStore(db),
 	}, nil
 }
@@ -308,13 +308,14 @@ func (s *PGKnowledgeGraphStore) MergeEntities(ctx context.Context, agentID, user
 		{"target_entity_id", "source_entity_id"},
 	} {
 		col, otherCol := cols[0], cols[1]
-		// Delete would-be-duplicate relations (same type, same endpoints after re-point)
+		// Delete would-be-duplicate current relations (same type, same endpoints
+		// after re-point); history rows never conflict.
 		delQ := fmt.Sprintf(`
 			DELETE FROM kg_relations r1
-			WHERE r1.%s = $1 AND r1.agent_id = $2
+			WHERE r1.%s = $1 AND r1.agent_id = $2 AND r1.valid_to IS NULL
 			AND EXISTS (
 				SELECT 1 FROM kg_relations r2
-				WHERE r2.%s = $3
+				WHERE r2.%s = $3 AND r2.valid_to IS NULL
 				AND r2.agent_id = r1.agent_id
 				AND r2.user_id = r1.user_id
 				AND r2.relation_type = r1.relation_type
@@ -13,27 +13,19 @@ import (
 )
 
 func (s *PGKnowledgeGraphStore) UpsertRelation(ctx context.Context, relation *store.Relation) error {
-	aid := mustParseUUID(relation.AgentID)
-	src := mustParseUUID(relation.SourceEntityID)
-	tgt := mustParseUUID(relation.TargetEntityID)
-	props, err := json.Marshal(relation.Properties)
+	tx, err := s.db.BeginTx(ctx, nil)
 	if err != nil {
-		props = []byte("{}")
+		return err
 	}
-	id := uuid.Must(uuid.NewV7())
-	now := time.Now()
-	tid := tenantIDForInsert(ctx)
-	_, err = s.db.ExecContext(ctx, `
-		INSERT INTO kg_relations
-			(id, agent_id, user_id, source_entity_id, relation_type, target_entity_id, confidence, properties, tenant_id, created_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
-		ON CONFLICT (agent_id, user_id, source_entity_id, relation_type, target_entity_id) DO UPDATE SET
-			confidence  = EXCLUDED.confidence,
-			properties  = EXCLUDED.properties,
-			tenant_id   = EXCLUDED.tenant_id`,
-		id, aid, relation.UserID, src, relation.RelationType, tgt, relation.Confidence, props, tid, now,
-	)
-	return err
+	defer tx.Rollback() //nolint:errcheck
+
+	if err := ingestRelation(ctx, tx, relation,
+		mustParseUUID(relation.AgentID), mustParseUUID(relation.SourceEntityID), mustParseUUID(relation.TargetEntityID),
+		tenantIDForInsert(ctx), time.Now(),
+	); err != nil {
+		return err
+	}
+	return tx.Commit()
 }
 
 func (s *PGKnowledgeGraphStore) DeleteRelation(ctx context.Context, agentID, userID, relationID string) error {
@@ -72,10 +64,9 @@ func (s *PGKnowledgeGraphStore) ListRelations(ctx context.Context, agentID, user
 		if err != nil {
 			return nil, err
 		}
-		q = `SELECT id, agent_id, user_id, source_entity_id, relation_type, target_entity_id,
-		       confidence, properties, created_at
+		q = `SELECT ` + kgRelationCols + `
 		FROM kg_relations
-		WHERE agent_id = $1
+		WHERE agent_id = $1 AND valid_to IS NULL
 		  AND (source_entity_id = $2 OR target_entity_id = $2)` + tc + `
 		ORDER BY created_at DESC`
 		args = append([]any{aid, eid}, tcArgs...)
@@ -84,10 +75,9 @@ func (s *PGKnowledgeGraphStore) ListRelations(ctx context.Context, agentID, user
 		if err != nil {
 			return nil, err
 		}
-		q = `SELECT id, agent_id, user_id, source_entity_id, relation_type, target_entity_id,
-		       confidence, properties, created_at
+		q = `SELECT ` + kgRelationCols + `
 		FROM kg_relations
-		WHERE agent_id = $1 AND user_id = $2
+		WHERE agent_id = $1 AND user_id = $2 AND valid_to IS NULL
 		  AND (source_entity_id = $3 OR target_entity_id = $3)` + tc + `
 		ORDER BY created_at DESC`
 		args = append([]any{aid, userID, eid}, tcArgs...)
@@ -106,7 +96,7 @@ func (s *PGKnowledgeGraphStore) ListAllRelations(ctx context.Context, agentID, u
 	if limit <= 0 {
 		limit = 200
 	}
-	where := "agent_id = $1"
+	where := "agent_id = $1 AND valid_to IS NULL"
 	args := []any{aid}
 	idx := 2
 	if !store.IsSharedKG(ctx) && userID != "" {
@@ -125,8 +115,7 @@ func (s *PGKnowledgeGraphStore) ListAllRelations(ctx context.Context, agentID, u
 	}
 	args = append(args, limit)
 	q := fmt.Sprintf(`
-		SELECT id, agent_id, user_id, source_entity_id, relation_type, target_entity_id,
-		       confidence, properties, created_at
+		SELECT `+kgRelationCols+`
 		FROM kg_relations WHERE %s
 		ORDER BY created_at DESC LIMIT $%d`, where, idx)
 	rows, err := s.db.QueryContext(ctx, q, args...)
@@ -217,18 +206,7 @@ func (s *PGKnowledgeGraphStore) IngestExtraction(ctx context.Context, agentID, u
 		if !ok1 || !ok2 {
 			continue // skip relations referencing unknown entities
 		}
-		props, _ := json.Marshal(r.Properties)
-		id := uuid.Must(uuid.NewV7())
-		if _, err := tx.ExecContext(ctx, `
-			INSERT INTO kg_relations
-				(id, agent_id, user_id, source_entity_id, relation_type, target_entity_id, confidence, properties, tenant_id, created_at)
-			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
-			ON CONFLICT (agent_id, user_id, source_entity_id, relation_type, target_entity_id) DO UPDATE SET
-				confidence  = EXCLUDED.confidence,
-				properties  = EXCLUDED.properties,
-				tenant_id   = EXCLUDED.tenant_id`,
-			id, aid, userID, src, r.RelationType, tgt, r.Confidence, props, tid, now,
-		); err != nil {
+		if err := ingestRelation(ctx, tx, r, aid, src, tgt, tid, now); err != nil {
 			return nil, err
 		}
 	}
@@ -300,7 +278,7 @@ func (s *PGKnowledgeGraphStore) Stats(ctx context.Context, agentID, userID strin
 		return nil, err
 	}
 	if err := s.db.QueryRowContext(ctx,
-		`SELECT COUNT(*) FROM kg_relations WHERE agent_id = $1`+userFilter+tenantFilter, args...,
+		`SELECT COUNT(*) FROM kg_relations WHERE agent_id = $1 AND valid_to IS NULL`+userFilter+tenantFilter, args...,
 	).Scan(&stats.RelationCount); err != nil {
 		return nil, err
 	}
@@ -367,19 +345,33 @@ func scanEntities(rows *sql.Rows) ([]store.Entity, error) {
 	return result, rows.Err()
 }
 
+// scanRelations scans rows selected with kgRelationCols.
 func scanRelations(rows *sql.Rows) ([]store.Relation, error) {
 	var result []store.Relation
 	for rows.Next() {
 		var r store.Relation
-		var props []byte
-		var createdAt time.Time
+		var props, prov []byte
+		var validFrom, createdAt time.Time
+		var validTo sql.NullTime
+		var supersededBy *uuid.UUID
 		if err := rows.Scan(
 			&r.ID, &r.AgentID, &r.UserID, &r.SourceEntityID, &r.RelationType,
-			&r.TargetEntityID, &r.Confidence, &props, &createdAt,
+			&r.TargetEntityID, &r.Confidence, &props, &validFrom, &validTo, &supersededBy, &prov, &createdAt,
 		); err != nil {
 			continue
 		}
 		json.Unmarshal(props, &r.Properties) //nolint:errcheck
+		r.ValidFrom = validFrom.UnixMilli()
+		if validTo.Valid {
+			r.ValidTo = validTo.Time.UnixMilli()
+		}
+		if supersededBy != nil {
+			r.SupersededBy = supersededBy.String()
+		}
+		var p store.KGProvenance
+		if json.Unmarshal(prov, &p) == nil && p != (store.KGProvenance{}) {
+			r.Provenance = &p
+		}
 		r.CreatedAt = createdAt.UnixMilli()
 		result = append(result, r)
 	}
@@ -0,0 +1,175 @@
+package pg
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/nextlevelbuilder/goclaw/internal/store"
+)
+
+// kgRelationCols is the column list read by scanRelations.
+const kgRelationCols = `id, agent_id, user_id, source_entity_id, relation_type, target_entity_id,
+	confidence, properties, valid_from, valid_to, superseded_by, provenance, created_at`
+
+// relationFact holds the resolved values of one relation being ingested.
+type relationFact struct {
+	rel           *store.Relation
+	aid, src, tgt uuid.UUID
+	tid           uuid.UUID
+	props, prov   []byte
+	now           time.Time
+}
+
+// ingestRelation records one fact in tx and sets r.ID to its row.
+//
+// An ended fact (ValidTo set) closes the matching current fact, or is kept as
+// history when there is none. A current fact is upserted against the current
+// version of the same (source, type, target); for exclusive relation types it
+// closes the source's other current facts, unless one of them is newer, in
+// which case the incoming fact is recorded as already superseded.
+func ingestRelation(ctx context.Context, tx *sql.Tx, r *store.Relation, aid, src, tgt, tid uuid.UUID, now time.Time) error {
+	f := relationFact{rel: r, aid: aid, src: src, tgt: tgt, tid: tid, now: now, prov: []byte("{}")}
+	var err error
+	if f.props, err = json.Marshal(r.Properties); err != nil {
+		f.props = []byte("{}")
+	}
+	prov := r.Provenance
+	if prov == nil {
+		prov = store.KGProvenanceFromContext(ctx)
+	}
+	if prov != nil {
+		f.prov, _ = json.Marshal(prov)
+	}
+	validFrom := now
+	if r.ValidFrom > 0 {
+		validFrom = time.UnixMilli(r.ValidFrom)
+	}
+
+	if r.ValidTo > 0 {
+		validTo := time.UnixMilli(r.ValidTo)
+		err := tx.QueryRowContext(ctx, `
+			UPDATE kg_relations SET valid_to = $1
+			WHERE agent_id = $2 AND user_id = $3 AND source_entity_id = $4 AND relation_type = $5
+			  AND target_entity_id = $6 AND valid_to IS NULL AND valid_from < $1
+			RETURNING id`,
+			validTo, aid, r.UserID, src, r.RelationType, tgt,
+		).Scan(&r.ID)
+		if !errors.Is(err, sql.ErrNoRows) {
+			return err
+		}
+		if validFrom.After(validTo) {
+			validFrom = validTo
+		}
+		return insertClosedRelation(ctx, tx, f, validFrom, validTo, nil)
+	}
+
+	exclusive := store.IsExclusiveRelation(r.RelationType)
+	if exclusive {
+		var newerID uuid.UUID
+		var newerFrom time.Time
+		err := tx.QueryRowContext(ctx, `
+			SELECT id, valid_from FROM kg_relations
+			WHERE agent_id = $1 AND user_id = $2 AND source_entity_id = $3 AND relation_type = $4
+			  AND target_entity_id <> $5 AND valid_to IS NULL AND valid_from > $6
+			ORDER BY valid_from LIMIT 1`,
+			aid, r.UserID, src, r.RelationType, tgt, validFrom,
+		).Scan(&newerID, &newerFrom)
+		if err == nil {
+			return insertClosedRelation(ctx, tx, f, validFrom, newerFrom, &newerID)
+		}
+		if !errors.Is(err, sql.ErrNoRows) {
+			return err
+		}
+	}
+
+	var id uuid.UUID
+	if err := tx.QueryRowContext(ctx, `
+		INSERT INTO kg_relations
+			(id, agent_id, user_id, source_entity_id, relation_type, target_entity_id, confidence, properties, tenant_id, valid_from, provenance, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+		ON CONFLICT (agent_id, user_id, source_entity_id, relation_type, target_entity_id) WHERE valid_to IS NULL DO UPDATE SET
+			confidence  = EXCLUDED.confidence,
+			properties  = EXCLUDED.properties,
+			tenant_id   = EXCLUDED.tenant_id,
+			valid_from  = LEAST(kg_relations.valid_from, EXCLUDED.valid_from)
+		RETURNING id`,
+		uuid.Must(uuid.NewV7()), aid, r.UserID, src, r.RelationType, tgt, r.Confidence, f.props, tid, validFrom, f.prov, now,
+	).Scan(&id); err != nil {
+		return err
+	}
+	r.ID = id.String()
+
+	if exclusive {
+		if _, err := tx.ExecContext(ctx, `
+			UPDATE kg_relations SET valid_to = GREATEST(valid_from, $1), superseded_by = $2
+			WHERE agent_id = $3 AND user_id = $4 AND source_entity_id = $5 AND relation_type = $6
+			  AND target_entity_id <> $7 AND valid_to IS NULL`,
+			validFrom, id, aid, r.UserID, src, r.RelationType, tgt,
+		); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// insertClosedRelation records a fact that no longer holds. Re-ingesting the
+// same closed version (e.g. importing an archive twice) is a no-op.
+func insertClosedRelation(ctx context.Context, tx *sql.Tx, f relationFact, validFrom, validTo time.Time, supersededBy *uuid.UUID) error {
+	r := f.rel
+	err := tx.QueryRowContext(ctx, `
+		SELECT id FROM kg_relations
+		WHERE agent_id = $1 AND user_id = $2 AND source_entity_id = $3 AND relation_type = $4
+		  AND target_entity_id = $5 AND valid_to = $6
+		LIMIT 1`,
+		f.aid, r.UserID, f.src, r.RelationType, f.tgt, validTo,
+	).Scan(&r.ID)
+	if !errors.Is(err, sql.ErrNoRows) {
+		return err
+	}
+
+	id := uuid.Must(uuid.NewV7())
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO kg_relations
+			(id, agent_id, user_id, source_entity_id, relation_type, target_entity_id, confidence, properties, tenant_id,
+			 valid_from, valid_to, superseded_by, provenance, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)`,
+		id, f.aid, r.UserID, f.src, r.RelationType, f.tgt, r.Confidence, f.props, f.tid,
+		validFrom, validTo, nilUUID(supersededBy), f.prov, f.now,
+	); err != nil {
+		return err
+	}
+	r.ID = id.String()
+	return nil
+}
+
+// ListRelationHistory returns every version of an entity's relations, newest first.
+func (s *PGKnowledgeGraphStore) ListRelationHistory(ctx context.Context, agentID, userID, entityID string) ([]store.Relation, error) {
+	aid := mustParseUUID(agentID)
+	eid := mustParseUUID(entityID)
+
+	where := "agent_id = $1 AND (source_entity_id = $2 OR target_entity_id = $2)"
+	args := []any{aid, eid}
+	if !store.IsSharedKG(ctx) {
+		where += " AND user_id = $3"
+		args = append(args, userID)
+	}
+	tc, tcArgs, _, err := scopeClause(ctx, len(args)+1)
+	if err != nil {
+		return nil, err
+	}
+	args = append(args, tcArgs...)
+
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT `+kgRelationCols+` FROM kg_relations WHERE `+where+tc+`
+		 ORDER BY valid_from DESC, created_at DESC`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanRelations(rows)
+}
@@ -12,8 +12,9 @@ import (
 
 // Traverse walks the knowledge graph from startEntityID up to maxDepth hops
 // using a recursive CTE. Returns all reachable entities (excluding the start node).
+// Only relations valid at asOf are followed (0 = current facts).
 // A 5-second statement timeout is applied for safety.
-func (s *PGKnowledgeGraphStore) Traverse(ctx context.Context, agentID, userID, startEntityID string, maxDepth int) ([]store.TraversalResult, error) {
+func (s *PGKnowledgeGraphStore) Traverse(ctx context.Context, agentID, userID, startEntityID string, maxDepth int, asOf int64) ([]store.TraversalResult, error) {
 	if maxDepth <= 0 {
 		maxDepth = 3
 	}
@@ -40,6 +41,7 @@ func (s *PGKnowledgeGraphStore) Traverse(ctx context.Context, agentID, userID, s
 			return nil, tcErr
 		}
 		depthN := 3 + len(tcArgs)
+		relValid, validArgs := relationValidClause(asOf, depthN+1)
 		q = fmt.Sprintf(`
 		WITH RECURSIVE paths AS (
 			SELECT
@@ -67,7 +69,7 @@ func (s *PGKnowledgeGraphStore) Traverse(ctx context.Context, agentID, userID, s
 					ELSE '~' || r.relation_type
 				END
 			FROM paths p
-			JOIN kg_relations r ON (r.source_entity_id = p.id OR r.target_entity_id = p.id) AND r.agent_id = $2
+			JOIN kg_relations r ON (r.source_entity_id = p.id OR r.target_entity_id = p.id) AND r.agent_id = $2%s
 			JOIN kg_entities  e ON e.id = (CASE WHEN r.source_entity_id = p.id THEN r.target_entity_id ELSE r.source_entity_id END) AND e.agent_id = $2
 			WHERE p.depth < $%d
 			  AND NOT e.id::text = ANY(p.path)
@@ -78,9 +80,10 @@ func (s *PGKnowledgeGraphStore) Traverse(ctx context.Context, agentID, userID, s
 			properties, source_id, confidence,
 			created_at, updated_at,
 			depth, path, via
-		FROM paths WHERE depth > 1`, tc, depthN)
+		FROM paths WHERE depth > 1`, tc, relValid, depthN)
 		args = append([]any{startID, aid}, tcArgs...)
 		args = append(args, maxDepth)
+		args = append(args, validArgs...)
 	} else {
 		// fixed params: $1=startID, $2=aid, $3=userID; tenant at $4 (if needed); maxDepth last
 		tc, tcArgs, _, tcErr := scopeClause(ctx, 4)
@@ -88,6 +91,7 @@ func (s *PGKnowledgeGraphStore) Traverse(ctx context.Context, agentID, userID, s
 			return nil, tcErr
 		}
 		depthN := 4 + len(tcArgs)
+		relValid, validArgs := relationValidClause(asOf, depthN+1)
 		q = fmt.Sprintf(`
 		WITH RECURSIVE paths AS (
 			SELECT
@@ -115,7 +119,7 @@ func (s *PGKnowledgeGraphStore) Traverse(ctx context.Context, agentID, userID, s
 					ELSE '~' || r.relation_type
 				END
 			FROM paths p
-			JOIN kg_relations r ON (r.source_entity_id = p.id OR r.target_entity_id = p.id) AND r.user_id = $3
+			JOIN kg_relations r ON (r.source_entity_id = p.id OR r.target_entity_id = p.id) AND r.user_id = $3%s
 			JOIN kg_entities  e ON e.id = (CASE WHEN r.source_entity_id = p.id THEN r.target_entity_id ELSE r.source_entity_id END) AND e.user_id = $3
 			WHERE p.depth < $%d
 			  AND NOT e.id::text = ANY(p.path)
@@ -126,9 +130,10 @@ func (s *PGKnowledgeGraphStore) Traverse(ctx context.Context, agentID, userID, s
 			properties, source_id, confidence,
 			created_at, updated_at,
 			depth, path, via
-		FROM paths WHERE depth > 1`, tc, depthN)
+		FROM paths WHERE depth > 1`, tc, relValid, depthN)
 		args = append([]any{startID, aid, userID}, tcArgs...)
 		args = append(args, maxDepth)
+		args = append(args, validArgs...)
 	}
 
 	rows, err := tx.QueryContext(ctx, q, args...)
@@ -174,3 +179,14 @@ func (s *PGKnowledgeGraphStore) Traverse(ctx context.Context, agentID, userID, s
 
 	return results, tx.Commit()
 }
+
+// relationValidClause restricts the traversal's relation alias r to facts
+// valid at asOf (unix ms), or to current facts when asOf is 0. param is the
+// placeholder index for asOf.
+func relationValidClause(asOf int64, param int) (string, []any) {
+	if asOf <= 0 {
+		return " AND r.valid_to IS NULL", nil
+	}
+	return fmt.Sprintf(" AND r.valid_from <= $%d AND (r.valid_to IS NULL OR r.valid_to > $%d)", param, param),
+		[]any{time.UnixMilli(asOf)}
+}
@@ -0,0 +1,190 @@
+package pg
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/nextlevelbuilder/goclaw/internal/crypto"
+	"github.com/nextlevelbuilder/goclaw/internal/store"
+)
+
+// GetOAuthClient returns the registered OAuth client for an MCP server.
+// Returns (nil, nil) if none is registered.
+func (s *PGMCPServerStore) GetOAuthClient(ctx context.Context, serverID uuid.UUID) (*store.MCPOAuthClient, error) {
+	tid := tenantIDForInsert(ctx)
+	var c store.MCPOAuthClient
+	var secret sql.NullString
+	err := s.db.QueryRowContext(ctx,
+		`SELECT client_id, client_secret, redirect_uri FROM mcp_oauth_clients
+		 WHERE server_id = $1 AND tenant_id = $2`,
+		serverID, tid,
+	).Scan(&c.ClientID, &secret, &c.RedirectURI)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	c.ClientSecret = s.decryptSecret(secret)
+	return &c, nil
+}
+
+// SetOAuthClient creates or replaces the OAuth client for an MCP server.
+func (s *PGMCPServerStore) SetOAuthClient(ctx context.Context, serverID uuid.UUID, c store.MCPOAuthClient) error {
+	tid := tenantIDForInsert(ctx)
+	secret, err := s.encryptSecret(c.ClientSecret)
+	if err != nil {
+		return fmt.Errorf("encrypt mcp oauth client_secret: %w", err)
+	}
+	_, err = s.db.ExecContext(ctx,
+		`INSERT INTO mcp_oauth_clients (id, server_id, tenant_id, client_id, client_secret, redirect_uri, created_at, updated_at)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $7)
+		 ON CONFLICT (server_id, tenant_id) DO UPDATE SET
+		   client_id = $4, client_secret = $5, redirect_uri = $6, updated_at = $7`,
+		uuid.Must(uuid.NewV7()), serverID, tid, c.ClientID, secret, c.RedirectURI, time.Now(),
+	)
+	return err
+}
+
+// GetOAuthToken returns a user's OAuth token for an MCP server.
+// Returns (nil, nil) if the user has not authorized the server.
+func (s *PGMCPServerStore) GetOAuthToken(ctx context.Context, serverID uuid.UUID, userID string) (*store.MCPOAuthToken, error) {
+	tid := tenantIDForInsert(ctx)
+	var t store.MCPOAuthToken
+	var access string
+	var refresh sql.NullString
+	var expiresAt sql.NullTime
+	err := s.db.QueryRowContext(ctx,
+		`SELECT access_token, refresh_token, token_type, scope, expires_at, updated_at FROM mcp_oauth_tokens
+		 WHERE server_id = $1 AND user_id = $2 AND tenant_id = $3`,
+		serverID, userID, tid,
+	).Scan(&access, &refresh, &t.TokenType, &t.Scope, &expiresAt, &t.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	t.AccessToken = s.decryptSecret(sql.NullString{String: access, Valid: true})
+	t.RefreshToken = s.decryptSecret(refresh)
+	if expiresAt.Valid {
+		t.ExpiresAt = expiresAt.Time
+	}
+	return &t, nil
+}
+
+// SetOAuthToken creates or replaces a user's OAuth token for an MCP server.
+func (s *PGMCPServerStore) SetOAuthToken(ctx context.Context, serverID uuid.UUID, userID string, t store.MCPOAuthToken) error {
+	tid := tenantIDForInsert(ctx)
+	access, err := s.encryptSecret(t.AccessToken)
+	if err != nil {
+		return fmt.Errorf("encrypt mcp oauth access_token: %w", err)
+	}
+	refresh, err := s.encryptSecret(t.RefreshToken)
+	if err != nil {
+		return fmt.Errorf("encrypt mcp oauth refresh_token: %w", err)
+	}
+	var expiresAt sql.NullTime
+	if !t.ExpiresAt.IsZero() {
+		expiresAt = sql.NullTime{Time: t.ExpiresAt, Valid: true}
+	}
+	tokenType := t.TokenType
+	if tokenType == "" {
+		tokenType = "Bearer"
+	}
+	_, err = s.db.ExecContext(ctx,
+		`INSERT INTO mcp_oauth_tokens (id, server_id, user_id, tenant_id, access_token, refresh_token, token_type, scope, expires_at, created_at, updated_at)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $10)
+		 ON CONFLICT (server_id, user_id, tenant_id) DO UPDATE SET
+		   access_token = $5, refresh_token = $6, token_type = $7, scope = $8, expires_at = $9, updated_at = $10`,
+		uuid.Must(uuid.NewV7()), serverID, userID, tid, access.String, refresh, tokenType, t.Scope, expiresAt, time.Now(),
+	)
+	return err
+}
+
+// DeleteOAuthToken removes a user's OAuth token for an MCP server.
+func (s *PGMCPServerStore) DeleteOAuthToken(ctx context.Context, serverID uuid.UUID, userID string) error {
+	tid := tenantIDForInsert(ctx)
+	_, err := s.db.ExecContext(ctx,
+		`DELETE FROM mcp_oauth_tokens WHERE server_id = $1 AND user_id = $2 AND tenant_id = $3`,
+		serverID, userID, tid,
+	)
+	return err
+}
+
+// CreateOAuthPending stores a started authorization and drops expired ones.
+func (s *PGMCPServerStore) CreateOAuthPending(ctx context.Context, p store.MCPOAuthPending) error {
+	verifier, err := s.encryptSecret(p.Verifier)
+	if err != nil {
+		return fmt.Errorf("encrypt mcp oauth code verifier: %w", err)
+	}
+	now := time.Now()
+	if _, err := s.db.ExecContext(ctx, `DELETE FROM mcp_oauth_pending WHERE expires_at < $1`, now); err != nil {
+		return err
+	}
+	_, err = s.db.ExecContext(ctx,
+		`INSERT INTO mcp_oauth_pending (state, tenant_id, server_id, user_id, verifier, channel, chat_id, expires_at, created_at)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`,
+		p.State, p.TenantID, p.ServerID, p.UserID, verifier.String, p.Channel, p.ChatID, p.ExpiresAt, now,
+	)
+	return err
+}
+
+// TakeOAuthPending removes and returns the unexpired authorization with the
+// given state. The callback carries no tenant, so the lookup is by state only.
+func (s *PGMCPServerStore) TakeOAuthPending(ctx context.Context, state string) (*store.MCPOAuthPending, error) {
+	p := store.MCPOAuthPending{State: state}
+	var verifier string
+	err := s.db.QueryRowContext(ctx,
+		`DELETE FROM mcp_oauth_pending WHERE state = $1
+		 RETURNING tenant_id, server_id, user_id, verifier, channel, chat_id, expires_at`,
+		state,
+	).Scan(&p.TenantID, &p.ServerID, &p.UserID, &verifier, &p.Channel, &p.ChatID, &p.ExpiresAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if time.Now().After(p.ExpiresAt) {
+		return nil, nil
+	}
+	p.Verifier = s.decryptSecret(sql.NullString{String: verifier, Valid: true})
+	return &p, nil
+}
+
+// encryptSecret encrypts a secret column value. Empty input is stored as NULL;
+// without an encryption key the value is stored as-is, like api_key.
+func (s *PGMCPServerStore) encryptSecret(v string) (sql.NullString, error) {
+	if v == "" {
+		return sql.NullString{}, nil
+	}
+	if s.encKey == "" {
+		return sql.NullString{String: v, Valid: true}, nil
+	}
+	enc, err := crypto.Encrypt(v, s.encKey)
+	if err != nil {
+		return sql.NullString{}, err
+	}
+	return sql.NullString{String: enc, Valid: true}, nil
+}
+
+// decryptSecret reverses encryptSecret.
+func (s *PGMCPServerStore) decryptSecret(v sql.NullString) string {
+	if !v.Valid || v.String == "" {
+		return ""
+	}
+	if s.encKey == "" {
+		return v.String
+	}
+	dec, err := crypto.Decrypt(v.String, s.encKey)
+	if err != nil {
+		return ""
+	}
+	return dec
+}
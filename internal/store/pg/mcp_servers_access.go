@@ -175,7 +175,7 @@ func (s *PGMCPServerStore) ListAccessible(ctx context.Context, agentID uuid.UUID
 	rows, err := s.db.QueryContext(ctx,
 		`SELECT ms.id, ms.name, ms.display_name, ms.transport, ms.command, ms.args, ms.url, ms.headers, ms.env,
 		 ms.api_key, ms.tool_prefix, ms.timeout_sec, ms.settings, ms.enabled, ms.created_by, ms.created_at, ms.updated_at,
-		 mag.tool_allow, mag.tool_deny
+		 mag.tool_allow, mag.tool_deny, mag.config_overrides
 		 FROM mcp_servers ms
 		 INNER JOIN mcp_agent_grants mag ON ms.id = mag.server_id AND mag.agent_id = $1 AND mag.enabled = true
 		 LEFT JOIN mcp_user_grants mug ON ms.id = mug.server_id AND mug.user_id = $2
@@ -192,14 +192,14 @@ func (s *PGMCPServerStore) ListAccessible(ctx context.Context, agentID uuid.UUID
 		var srv store.MCPServerData
 		var displayName, command, url, apiKey, toolPrefix *string
 		var args, headers, env *[]byte
-		var toolAllowJSON, toolDenyJSON *[]byte
+		var toolAllowJSON, toolDenyJSON, overridesJSON *[]byte
 
 		if err := rows.Scan(
 			&srv.ID, &srv.Name, &displayName, &srv.Transport, &command,
 			&args, &url, &headers, &env,
 			&apiKey, &toolPrefix, &srv.TimeoutSec,
 			&srv.Settings, &srv.Enabled, &srv.CreatedBy, &srv.CreatedAt, &srv.UpdatedAt,
-			&toolAllowJSON, &toolDenyJSON,
+			&toolAllowJSON, &toolDenyJSON, &overridesJSON,
 		); err != nil {
 			continue
 		}
@@ -218,7 +218,7 @@ func (s *PGMCPServerStore) ListAccessible(ctx context.Context, agentID uuid.UUID
 			srv.APIKey = derefStr(apiKey)
 		}
 
-		info := store.MCPAccessInfo{Server: srv}
+		info := store.MCPAccessInfo{Server: srv, Capabilities: store.ParseMCPGrantCapabilities(derefBytes(overridesJSON))}
 		if toolAllowJSON != nil {
 			json.Unmarshal(*toolAllowJSON, &info.ToolAllow)
 		}
@@ -2,6 +2,7 @@ package pg
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
 
 	"github.com/nextlevelbuilder/goclaw/internal/store"
@@ -75,8 +76,13 @@ type scoredChunk struct {
 	Text      string
 	Score     float64
 	UserID    *string
+	UpdatedAt sql.NullTime
 }
 
+// chunkUpdatedAtExpr selects when a chunk's content last changed: its
+// document's updated_at, or the chunk's own for chunks without a document.
+const chunkUpdatedAtExpr = `COALESCE((SELECT d.updated_at FROM memory_documents d WHERE d.id = memory_chunks.document_id), memory_chunks.updated_at)`
+
 func (s *PGMemoryStore) ftsSearch(ctx context.Context, query string, agentID any, userID string, limit int) ([]scoredChunk, error) {
 	var q string
 	var args []any
@@ -89,12 +95,12 @@ func (s *PGMemoryStore) ftsSearch(ctx context.Context, query string, agentID any
 			return nil, err
 		}
 		limitN := 5 + len(tcArgs)
-		q = fmt.Sprintf(`SELECT path, start_line, end_line, text, user_id,
+		q = fmt.Sprintf(`SELECT path, start_line, end_line, text, user_id, %s,
 				ts_rank(tsv, plainto_tsquery('simple', $1)) AS score
 			FROM memory_chunks
 			WHERE agent_id = $2 AND tsv @@ plainto_tsquery('simple', $3)
 			AND (user_id IS NULL OR user_id = $4)%s
-			ORDER BY score DESC LIMIT $%d`, tc, limitN)
+			ORDER BY score DESC LIMIT $%d`, chunkUpdatedAtExpr, tc, limitN)
 		args = append([]any{query, agentID, query, userID}, tcArgs...)
 		args = append(args, limit)
 	} else {
@@ -105,12 +111,12 @@ func (s *PGMemoryStore) ftsSearch(ctx context.Context, query string, agentID any
 			return nil, err
 		}
 		limitN := 4 + len(tcArgs)
-		q = fmt.Sprintf(`SELECT path, start_line, end_line, text, user_id,
+		q = fmt.Sprintf(`SELECT path, start_line, end_line, text, user_id, %s,
 				ts_rank(tsv, plainto_tsquery('simple', $1)) AS score
 			FROM memory_chunks
 			WHERE agent_id = $2 AND tsv @@ plainto_tsquery('simple', $3)
 			AND user_id IS NULL%s
-			ORDER BY score DESC LIMIT $%d`, tc, limitN)
+			ORDER BY score DESC LIMIT $%d`, chunkUpdatedAtExpr, tc, limitN)
 		args = append([]any{query, agentID, query}, tcArgs...)
 		args = append(args, limit)
 	}
@@ -124,7 +130,7 @@ func (s *PGMemoryStore) ftsSearch(ctx context.Context, query string, agentID any
 	var results []scoredChunk
 	for rows.Next() {
 		var r scoredChunk
-		rows.Scan(&r.Path, &r.StartLine, &r.EndLine, &r.Text, &r.UserID, &r.Score)
+		rows.Scan(&r.Path, &r.StartLine, &r.EndLine, &r.Text, &r.UserID, &r.UpdatedAt, &r.Score)
 		results = append(results, r)
 	}
 	return results, nil
@@ -145,12 +151,12 @@ func (s *PGMemoryStore) vectorSearch(ctx context.Context, embedding []float32, a
 		}
 		orderN := 4 + len(tcArgs)
 		limitN := orderN + 1
-		q = fmt.Sprintf(`SELECT path, start_line, end_line, text, user_id,
+		q = fmt.Sprintf(`SELECT path, start_line, end_line, text, user_id, %s,
 				1 - (embedding <=> $1::vector) AS score
 			FROM memory_chunks
 			WHERE agent_id = $2 AND embedding IS NOT NULL
 			AND (user_id IS NULL OR user_id = $3)%s
-			ORDER BY embedding <=> $%d::vector LIMIT $%d`, tc, orderN, limitN)
+			ORDER BY embedding <=> $%d::vector LIMIT $%d`, chunkUpdatedAtExpr, tc, orderN, limitN)
 		args = append([]any{vecStr, agentID, userID}, tcArgs...)
 		args = append(args, vecStr, limit)
 	} else {
@@ -162,12 +168,12 @@ func (s *PGMemoryStore) vectorSearch(ctx context.Context, embedding []float32, a
 		}
 		orderN := 3 + len(tcArgs)
 		limitN := orderN + 1
-		q = fmt.Sprintf(`SELECT path, start_line, end_line, text, user_id,
+		q = fmt.Sprintf(`SELECT path, start_line, end_line, text, user_id, %s,
 				1 - (embedding <=> $1::vector) AS score
 			FROM memory_chunks
 			WHERE agent_id = $2 AND embedding IS NOT NULL
 			AND user_id IS NULL%s
-			ORDER BY embedding <=> $%d::vector LIMIT $%d`, tc, orderN, limitN)
+			ORDER BY embedding <=> $%d::vector LIMIT $%d`, chunkUpdatedAtExpr, tc, orderN, limitN)
 		args = append([]any{vecStr, agentID}, tcArgs...)
 		args = append(args, vecStr, limit)
 	}
@@ -181,7 +187,7 @@ func (s *PGMemoryStore) vectorSearch(ctx context.Context, embedding []float32, a
 	var results []scoredChunk
 	for rows.Next() {
 		var r scoredChunk
-		rows.Scan(&r.Path, &r.StartLine, &r.EndLine, &r.Text, &r.UserID, &r.Score)
+		rows.Scan(&r.Path, &r.StartLine, &r.EndLine, &r.Text, &r.UserID, &r.UpdatedAt, &r.Score)
 		results = append(results, r)
 	}
 	return results, nil
@@ -212,6 +218,9 @@ func hybridMerge(fts, vec []scoredChunk, textWeight, vectorWeight float64, curre
 			if scope == "personal" {
 				existing.Scope = "personal"
 				existing.Snippet = r.Text
+				if r.UpdatedAt.Valid {
+					existing.UpdatedAt = r.UpdatedAt.Time.UnixMilli()
+				}
 			}
 		} else {
 			seen[k] = &store.MemorySearchResult{
@@ -223,6 +232,9 @@ func hybridMerge(fts, vec []scoredChunk, textWeight, vectorWeight float64, curre
 				Source:    "memory",
 				Scope:     scope,
 			}
+			if r.UpdatedAt.Valid {
+				seen[k].UpdatedAt = r.UpdatedAt.Time.UnixMilli()
+			}
 		}
 	}
 
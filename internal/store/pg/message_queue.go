@@ -0,0 +1,251 @@
+package pg
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/nextlevelbuilder/goclaw/internal/store"
+)
+
+// PGMessageQueueStore implements store.MessageQueueStore backed by Postgres.
+type PGMessageQueueStore struct {
+	db *sql.DB
+}
+
+// NewPGMessageQueueStore creates a new PGMessageQueueStore.
+func NewPGMessageQueueStore(db *sql.DB) *PGMessageQueueStore {
+	return &PGMessageQueueStore{db: db}
+}
+
+const messageQueueSelectCols = `id, tenant_id, direction, channel, idempotency_key, payload, status,
+	attempts, last_error, owner, next_attempt_at, created_at, updated_at`
+
+func scanQueuedMessage(row interface{ Scan(...any) error }) (store.QueuedMessage, error) {
+	var m store.QueuedMessage
+	var payload []byte
+	err := row.Scan(&m.ID, &m.TenantID, &m.Direction, &m.Channel, &m.IdempotencyKey, &payload, &m.Status,
+		&m.Attempts, &m.LastError, &m.Owner, &m.NextAttemptAt, &m.CreatedAt, &m.UpdatedAt)
+	m.Payload = payload
+	return m, err
+}
+
+func (s *PGMessageQueueStore) Enqueue(ctx context.Context, msg *store.QueuedMessage) (bool, error) {
+	if msg.ID == uuid.Nil {
+		msg.ID = store.GenNewID()
+	}
+	if msg.TenantID == uuid.Nil {
+		msg.TenantID = store.MasterTenantID
+	}
+	now := time.Now()
+	msg.Status = store.QueueStatusQueued
+	msg.NextAttemptAt, msg.CreatedAt, msg.UpdatedAt = now, now, now
+
+	res, err := s.db.ExecContext(ctx,
+		`INSERT INTO message_queue (id, tenant_id, direction, channel, idempotency_key, payload, status, owner, next_attempt_at, created_at, updated_at)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		 ON CONFLICT (direction, idempotency_key) WHERE idempotency_key <> '' DO NOTHING`,
+		msg.ID, msg.TenantID, msg.Direction, msg.Channel, msg.IdempotencyKey, []byte(msg.Payload),
+		msg.Status, msg.Owner, now, now, now,
+	)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+func (s *PGMessageQueueStore) ClaimDue(ctx context.Context, owner string, limit int) ([]store.QueuedMessage, error) {
+	// SKIP LOCKED lets replicas claim concurrently without handing a row to two of them.
+	rows, err := s.db.QueryContext(ctx,
+		`WITH claimed AS (
+		     UPDATE message_queue SET status = $1, owner = $2, updated_at = NOW()
+		     WHERE id IN (
+		         SELECT id FROM message_queue
+		         WHERE status = $3 AND next_attempt_at <= NOW()
+		         ORDER BY next_attempt_at
+		         LIMIT $4
+		         FOR UPDATE SKIP LOCKED
+		     )
+		     RETURNING `+messageQueueSelectCols+`
+		 )
+		 SELECT `+messageQueueSelectCols+` FROM claimed ORDER BY created_at`,
+		store.QueueStatusQueued, owner, store.QueueStatusPending, limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []store.QueuedMessage
+	for rows.Next() {
+		m, err := scanQueuedMessage(rows)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, m)
+	}
+	return result, rows.Err()
+}
+
+func (s *PGMessageQueueStore) Complete(ctx context.Context, id uuid.UUID) error {
+	_, err := s.db.ExecContext(ctx,
+		`UPDATE message_queue SET status = $1, updated_at = NOW() WHERE id = $2`,
+		store.QueueStatusDone, id,
+	)
+	return err
+}
+
+func (s *PGMessageQueueStore) Release(ctx context.Context, id uuid.UUID, at time.Time) error {
+	_, err := s.db.ExecContext(ctx,
+		`UPDATE message_queue SET status = $1, next_attempt_at = $2, updated_at = NOW()
+		 WHERE id = $3 AND status = $4`,
+		store.QueueStatusPending, at, id, store.QueueStatusQueued,
+	)
+	return err
+}
+
+func (s *PGMessageQueueStore) Fail(ctx context.Context, id uuid.UUID, lastErr string, retryAt time.Time, maxAttempts int) (string, error) {
+	var status string
+	err := s.db.QueryRowContext(ctx,
+		`UPDATE message_queue SET
+		     attempts = attempts + 1,
+		     last_error = $1,
+		     next_attempt_at = $2,
+		     status = CASE WHEN attempts + 1 >= $3 THEN $4 ELSE $5 END,
+		     updated_at = NOW()
+		 WHERE id = $6
+		 RETURNING status`,
+		lastErr, retryAt, maxAttempts, store.QueueStatusDead, store.QueueStatusPending, id,
+	).Scan(&status)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", fmt.Errorf("queued message %s not found", id)
+	}
+	return status, err
+}
+
+func (s *PGMessageQueueStore) RecoverOwned(ctx context.Context, owner string, maxAttempts int) (int64, error) {
+	res, err := s.db.ExecContext(ctx,
+		`UPDATE message_queue SET
+		     attempts = attempts + 1,
+		     last_error = CASE WHEN attempts + 1 >= $1 THEN 'interrupted by restart' ELSE last_error END,
+		     status = CASE WHEN attempts + 1 >= $1 THEN $2 ELSE $3 END,
+		     next_attempt_at = NOW(),
+		     updated_at = NOW()
+		 WHERE owner = $4 AND status = $5`,
+		maxAttempts, store.QueueStatusDead, store.QueueStatusPending, owner, store.QueueStatusQueued,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+func (s *PGMessageQueueStore) QueuedOwners(ctx context.Context) ([]string, error) {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT DISTINCT owner FROM message_queue WHERE status = $1`, store.QueueStatusQueued)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var owners []string
+	for rows.Next() {
+		var owner string
+		if err := rows.Scan(&owner); err != nil {
+			return nil, err
+		}
+		owners = append(owners, owner)
+	}
+	return owners, rows.Err()
+}
+
+func (s *PGMessageQueueStore) PruneDone(ctx context.Context, cutoff time.Time) (int64, error) {
+	res, err := s.db.ExecContext(ctx,
+		`DELETE FROM message_queue WHERE status = $1 AND updated_at < $2`,
+		store.QueueStatusDone, cutoff,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+func (s *PGMessageQueueStore) ListDead(ctx context.Context, direction string, limit, offset int) ([]store.QueuedMessage, int, error) {
+	tid, err := requireTenantID(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+	where := `WHERE tenant_id = $1 AND status = $2`
+	args := []any{tid, store.QueueStatusDead}
+	if direction != "" {
+		where += ` AND direction = $3`
+		args = append(args, direction)
+	}
+
+	var total int
+	if err := s.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM message_queue `+where, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	n := len(args)
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT `+messageQueueSelectCols+` FROM message_queue `+where+
+			fmt.Sprintf(` ORDER BY updated_at DESC LIMIT $%d OFFSET $%d`, n+1, n+2),
+		append(args, limit, offset)...,
+	)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var result []store.QueuedMessage
+	for rows.Next() {
+		m, err := scanQueuedMessage(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		result = append(result, m)
+	}
+	return result, total, rows.Err()
+}
+
+func (s *PGMessageQueueStore) Redrive(ctx context.Context, id uuid.UUID) error {
+	tid, err := requireTenantID(ctx)
+	if err != nil {
+		return err
+	}
+	res, err := s.db.ExecContext(ctx,
+		`UPDATE message_queue SET status = $1, attempts = 0, next_attempt_at = NOW(), updated_at = NOW()
+		 WHERE id = $2 AND tenant_id = $3 AND status = $4`,
+		store.QueueStatusPending, id, tid, store.QueueStatusDead,
+	)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func (s *PGMessageQueueStore) Delete(ctx context.Context, id uuid.UUID) error {
+	tid, err := requireTenantID(ctx)
+	if err != nil {
+		return err
+	}
+	res, err := s.db.ExecContext(ctx,
+		`DELETE FROM message_queue WHERE id = $1 AND tenant_id = $2 AND status = $3`,
+		id, tid, store.QueueStatusDead,
+	)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
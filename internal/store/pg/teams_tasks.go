@@ -79,15 +79,14 @@ func (s *PGTeamStore) ListTaskScopes(ctx context.Context, teamID uuid.UUID) ([]s
 // ============================================================
 
 func (s *PGTeamStore) CreateTask(ctx context.Context, task *store.TeamTaskData) error {
-	if task.ID == uuid.Nil {
-		task.ID = store.GenNewID()
-	}
-	now := time.Now()
-	task.CreatedAt = now
-	task.UpdatedAt = now
+	return s.CreateTasks(ctx, []*store.TeamTaskData{task})
+}
 
-	if task.TaskType == "" {
-		task.TaskType = "general"
+// CreateTasks inserts all tasks in one transaction so a multi-task plan is
+// created atomically. Task numbers are assigned in slice order.
+func (s *PGTeamStore) CreateTasks(ctx context.Context, tasks []*store.TeamTaskData) error {
+	if len(tasks) == 0 {
+		return nil
 	}
 
 	// Wrap entire operation in a transaction for atomicity.
@@ -97,16 +96,55 @@ func (s *PGTeamStore) CreateTask(ctx context.Context, task *store.TeamTaskData)
 	}
 	defer tx.Rollback()
 
-	// Lock team row to serialize task_number generation (prevents races).
-	if _, err := tx.ExecContext(ctx,
-		`SELECT 1 FROM agent_teams WHERE id = $1 FOR UPDATE`, task.TeamID,
-	); err != nil {
-		return fmt.Errorf("lock team: %w", err)
+	// Lock team rows to serialize task_number generation (prevents races).
+	locked := make(map[uuid.UUID]bool)
+	for _, task := range tasks {
+		if locked[task.TeamID] {
+			continue
+		}
+		if _, err := tx.ExecContext(ctx,
+			`SELECT 1 FROM agent_teams WHERE id = $1 FOR UPDATE`, task.TeamID,
+		); err != nil {
+			return fmt.Errorf("lock team: %w", err)
+		}
+		locked[task.TeamID] = true
+	}
+
+	now := time.Now()
+	for _, task := range tasks {
+		if err := insertTaskTx(ctx, tx, task, now); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	// Fire-and-forget: generate embeddings for the new tasks' subjects.
+	for _, task := range tasks {
+		go s.generateTaskEmbedding(context.Background(), task.ID, task.Subject)
+	}
+
+	return nil
+}
+
+// insertTaskTx assigns the task number and identifier, then inserts one task.
+// The caller must hold the team row lock.
+func insertTaskTx(ctx context.Context, tx *sql.Tx, task *store.TeamTaskData, now time.Time) error {
+	if task.ID == uuid.Nil {
+		task.ID = store.GenNewID()
+	}
+	task.CreatedAt = now
+	task.UpdatedAt = now
+
+	if task.TaskType == "" {
+		task.TaskType = "general"
 	}
 
 	// Scope task_number per (team_id, chat_id) so each conversation starts from 1.
 	var taskNumber int
-	err = tx.QueryRowContext(ctx,
+	err := tx.QueryRowContext(ctx,
 		`SELECT COALESCE(MAX(task_number), 0) + 1 FROM team_tasks WHERE team_id = $1 AND COALESCE(chat_id, '') = $2`,
 		task.TeamID, task.ChatID,
 	).Scan(&taskNumber)
@@ -143,18 +181,7 @@ func (s *PGTeamStore) CreateTask(ctx context.Context, task *store.TeamTaskData)
 		task.LockedAt, task.LockExpiresAt,
 		now, now, tenantIDForInsert(ctx),
 	)
-	if err != nil {
-		return err
-	}
-
-	if err := tx.Commit(); err != nil {
-		return err
-	}
-
-	// Fire-and-forget: generate embedding for the new task's subject.
-	go s.generateTaskEmbedding(context.Background(), task.ID, task.Subject)
-
-	return nil
+	return err
 }
 
 // allowedTaskUpdateCols is the whitelist of columns that UpdateTask accepts.
@@ -199,6 +226,23 @@ func (s *PGTeamStore) UpdateTask(ctx context.Context, taskID uuid.UUID, updates
 		return updateErr
 	}
 
+	// Keep status consistent with the new prerequisite list (pending ⇄ blocked).
+	if _, ok := updates["blocked_by"]; ok {
+		q := `UPDATE team_tasks SET status = CASE
+		   WHEN status = 'pending' AND cardinality(blocked_by) > 0 THEN 'blocked'
+		   WHEN status = 'blocked' AND cardinality(blocked_by) = 0 THEN 'pending'
+		   ELSE status END
+		 WHERE id = $1 AND status IN ('pending', 'blocked')`
+		args := []any{taskID}
+		if !store.IsCrossTenant(ctx) {
+			q += ` AND tenant_id = $2`
+			args = append(args, store.TenantIDFromContext(ctx))
+		}
+		if _, err := s.db.ExecContext(ctx, q, args...); err != nil {
+			return err
+		}
+	}
+
 	// Re-embed when subject changes.
 	if newSubject, ok := updates["subject"].(string); ok && newSubject != "" {
 		go s.generateTaskEmbedding(context.Background(), taskID, newSubject)
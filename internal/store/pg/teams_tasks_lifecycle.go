@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 
 	"github.com/nextlevelbuilder/goclaw/internal/store"
 )
@@ -120,7 +121,7 @@ func (s *PGTeamStore) CancelTask(ctx context.Context, taskID, teamID uuid.UUID,
 		return fmt.Errorf("task not found, already completed/cancelled, or wrong team")
 	}
 
-	if err := unblockDependentTasks(ctx, tx, taskID); err != nil {
+	if err := resolveDependentTasks(ctx, tx, taskID, store.TeamTaskStatusCancelled); err != nil {
 		return err
 	}
 	return tx.Commit()
@@ -155,7 +156,7 @@ func (s *PGTeamStore) FailTask(ctx context.Context, taskID, teamID uuid.UUID, er
 		return fmt.Errorf("task not in progress or not found")
 	}
 
-	if err := unblockDependentTasks(ctx, tx, taskID); err != nil {
+	if err := resolveDependentTasks(ctx, tx, taskID, store.TeamTaskStatusFailed); err != nil {
 		return err
 	}
 	return tx.Commit()
@@ -189,7 +190,7 @@ func (s *PGTeamStore) FailPendingTask(ctx context.Context, taskID, teamID uuid.U
 		return fmt.Errorf("task not pending/blocked or not found")
 	}
 
-	if err := unblockDependentTasks(ctx, tx, taskID); err != nil {
+	if err := resolveDependentTasks(ctx, tx, taskID, store.TeamTaskStatusFailed); err != nil {
 		return err
 	}
 	return tx.Commit()
@@ -211,6 +212,68 @@ func unblockDependentTasks(ctx context.Context, tx *sql.Tx, taskID uuid.UUID) er
 	return err
 }
 
+// resolveDependentTasks applies the team's dependency policy after taskID ended
+// with a failed/cancelled status: either unblock dependents (default) or
+// cascade the same status through every waiting descendant.
+// Must be called within a transaction.
+func resolveDependentTasks(ctx context.Context, tx *sql.Tx, taskID uuid.UUID, status string) error {
+	var settings []byte
+	var identifier string
+	err := tx.QueryRowContext(ctx,
+		`SELECT tm.settings, COALESCE(t.identifier, '')
+		 FROM team_tasks t JOIN agent_teams tm ON tm.id = t.team_id
+		 WHERE t.id = $1`, taskID,
+	).Scan(&settings, &identifier)
+	if err != nil && err != sql.ErrNoRows {
+		return err
+	}
+	if !store.ParseTaskDependencyPolicy(settings).Cascades(status) {
+		return unblockDependentTasks(ctx, tx, taskID)
+	}
+	return cascadeDependentTasks(ctx, tx, taskID, status, identifier)
+}
+
+// cascadeDependentTasks moves every blocked/pending task that transitively
+// depends on taskID to status. Must be called within a transaction.
+func cascadeDependentTasks(ctx context.Context, tx *sql.Tx, taskID uuid.UUID, status, identifier string) error {
+	tid := tenantIDForInsert(ctx)
+	result := fmt.Sprintf("Cancelled: prerequisite task %s was cancelled", identifier)
+	if status == store.TeamTaskStatusFailed {
+		result = fmt.Sprintf("FAILED: prerequisite task %s failed", identifier)
+	}
+	now := time.Now()
+	frontier := []uuid.UUID{taskID}
+	for len(frontier) > 0 {
+		rows, err := tx.QueryContext(ctx,
+			`UPDATE team_tasks SET status = $1, result = $2, locked_at = NULL, lock_expires_at = NULL,
+			   followup_at = NULL, followup_count = 0, followup_message = NULL, followup_channel = NULL, followup_chat_id = NULL,
+			   updated_at = $3
+			 WHERE blocked_by && $4 AND status IN ($5, $6) AND tenant_id = $7
+			 RETURNING id`,
+			status, result, now, pq.Array(frontier),
+			store.TeamTaskStatusBlocked, store.TeamTaskStatusPending, tid,
+		)
+		if err != nil {
+			return err
+		}
+		var next []uuid.UUID
+		for rows.Next() {
+			var id uuid.UUID
+			if err := rows.Scan(&id); err != nil {
+				rows.Close()
+				return err
+			}
+			next = append(next, id)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return err
+		}
+		frontier = next
+	}
+	return nil
+}
+
 func (s *PGTeamStore) ReviewTask(ctx context.Context, taskID, teamID uuid.UUID) error {
 	tid := tenantIDForInsert(ctx)
 	res, err := s.db.ExecContext(ctx,
@@ -296,7 +359,7 @@ func (s *PGTeamStore) RejectTask(ctx context.Context, taskID, teamID uuid.UUID,
 		return fmt.Errorf("task not in review or not found")
 	}
 
-	if err := unblockDependentTasks(ctx, tx, taskID); err != nil {
+	if err := resolveDependentTasks(ctx, tx, taskID, store.TeamTaskStatusCancelled); err != nil {
 		return err
 	}
 	return tx.Commit()
@@ -490,6 +490,44 @@ func (s *PGTracingStore) GetMonthlyAgentCost(ctx context.Context, agentID uuid.U
 	return cost, err
 }
 
+func (s *PGTracingStore) GetMonthlyUserCost(ctx context.Context, userID string, year int, month time.Month) (float64, error) {
+	return s.monthlyCost(ctx, "user_id = $1", []any{userID}, year, month)
+}
+
+func (s *PGTracingStore) GetMonthlyTenantCost(ctx context.Context, year int, month time.Month) (float64, error) {
+	if store.TenantIDFromContext(ctx) == uuid.Nil && !store.IsCrossTenant(ctx) {
+		return 0, nil
+	}
+	return s.monthlyCost(ctx, "", nil, year, month)
+}
+
+// monthlyCost sums root-trace cost for a calendar month (UTC), filtered by an
+// optional condition whose placeholders start at $1, plus the context tenant.
+func (s *PGTracingStore) monthlyCost(ctx context.Context, cond string, args []any, year int, month time.Month) (float64, error) {
+	start := time.Date(year, month, 1, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 1, 0)
+
+	n := len(args)
+	q := fmt.Sprintf(`SELECT COALESCE(SUM(total_cost), 0) FROM traces
+		 WHERE created_at >= $%d AND created_at < $%d AND parent_trace_id IS NULL`, n+1, n+2)
+	if cond != "" {
+		q += " AND " + cond
+	}
+	qArgs := append(args, start, end)
+
+	if !store.IsCrossTenant(ctx) {
+		tid := store.TenantIDFromContext(ctx)
+		if tid != uuid.Nil {
+			q += fmt.Sprintf(" AND tenant_id = $%d", n+3)
+			qArgs = append(qArgs, tid)
+		}
+	}
+
+	var cost float64
+	err := s.db.QueryRowContext(ctx, q, qArgs...).Scan(&cost)
+	return cost, err
+}
+
 func (s *PGTracingStore) GetCostSummary(ctx context.Context, opts store.CostSummaryOpts) ([]store.CostSummaryRow, error) {
 	var conditions []string
 	var args []any
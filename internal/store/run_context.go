@@ -30,6 +30,7 @@ type RunContext struct {
 
 	// Flags
 	SelfEvolve          bool
+	RequireFileApproval bool // agent edits to identity files need owner approval
 	SharedMemory        bool
 	SharedKG            bool
 	RestrictToWorkspace bool
@@ -55,7 +55,8 @@ type SkillAccessStore interface {
 }
 
 // EmbeddingSkillSearcher is an optional interface for stores that support
-// vector-based skill search. PGSkillStore implements this; FileSkillStore does not.
+// vector-based skill search. PGSkillStore and SQLiteSkillStore implement this;
+// FileSkillStore does not.
 type EmbeddingSkillSearcher interface {
 	SearchByEmbedding(ctx context.Context, embedding []float32, limit int) ([]SkillSearchResult, error)
 	SetEmbeddingProvider(provider EmbeddingProvider)
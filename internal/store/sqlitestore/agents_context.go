@@ -45,14 +45,36 @@ func (s *SQLiteAgentStore) GetAgentContextFiles(ctx context.Context, agentID uui
 	return result, rows.Err()
 }
 
+// SetAgentContextFile upserts an agent-level file and records a revision when
+// the content changed.
 func (s *SQLiteAgentStore) SetAgentContextFile(ctx context.Context, agentID uuid.UUID, fileName, content string) error {
-	_, err := s.db.ExecContext(ctx,
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var prev sql.NullString
+	err = tx.QueryRowContext(ctx,
+		"SELECT content FROM agent_context_files WHERE agent_id = ? AND file_name = ?",
+		agentID, fileName).Scan(&prev)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx,
 		`INSERT INTO agent_context_files (id, agent_id, file_name, content, updated_at, tenant_id)
 		 VALUES (?, ?, ?, ?, ?, ?)
 		 ON CONFLICT (agent_id, file_name) DO UPDATE SET content = excluded.content, updated_at = excluded.updated_at`,
 		store.GenNewID(), agentID, fileName, content, time.Now(), tenantIDForInsert(ctx),
-	)
-	return err
+	); err != nil {
+		return err
+	}
+	if !prev.Valid || prev.String != content {
+		if err := insertContextFileRevision(ctx, tx, agentID, "", fileName, content); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
 }
 
 // PropagateContextFile copies an agent-level context file to all existing user
@@ -77,8 +99,38 @@ func (s *SQLiteAgentStore) PropagateContextFile(ctx context.Context, agentID uui
 		return 0, err
 	}
 
-	// Step 2: update all matching user context files.
-	res, err := s.db.ExecContext(ctx,
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	// Step 2: record a revision for every user copy that is about to change.
+	rows, err := tx.QueryContext(ctx,
+		"SELECT user_id FROM user_context_files WHERE agent_id = ? AND file_name = ? AND content <> ?"+tClause,
+		append([]any{agentID, fileName, content}, tArgs...)...,
+	)
+	if err != nil {
+		return 0, err
+	}
+	var changed []string
+	for rows.Next() {
+		var userID string
+		if err := rows.Scan(&userID); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		changed = append(changed, userID)
+	}
+	rows.Close()
+	for _, userID := range changed {
+		if err := insertContextFileRevision(ctx, tx, agentID, userID, fileName, content); err != nil {
+			return 0, err
+		}
+	}
+
+	// Step 3: update all matching user context files.
+	res, err := tx.ExecContext(ctx,
 		"UPDATE user_context_files SET content = ?, updated_at = ? WHERE agent_id = ? AND file_name = ?"+tClause,
 		append([]any{content, time.Now(), agentID, fileName}, tArgs...)...,
 	)
@@ -86,7 +138,7 @@ func (s *SQLiteAgentStore) PropagateContextFile(ctx context.Context, agentID uui
 		return 0, err
 	}
 	n, _ := res.RowsAffected()
-	return int(n), nil
+	return int(n), tx.Commit()
 }
 
 // --- Per-user Context Files ---
@@ -115,14 +167,36 @@ func (s *SQLiteAgentStore) GetUserContextFiles(ctx context.Context, agentID uuid
 	return result, rows.Err()
 }
 
+// SetUserContextFile upserts a per-user file and records a revision when the
+// content changed.
 func (s *SQLiteAgentStore) SetUserContextFile(ctx context.Context, agentID uuid.UUID, userID, fileName, content string) error {
-	_, err := s.db.ExecContext(ctx,
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var prev sql.NullString
+	err = tx.QueryRowContext(ctx,
+		"SELECT content FROM user_context_files WHERE agent_id = ? AND user_id = ? AND file_name = ?",
+		agentID, userID, fileName).Scan(&prev)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx,
 		`INSERT INTO user_context_files (id, agent_id, user_id, file_name, content, updated_at, tenant_id)
 		 VALUES (?, ?, ?, ?, ?, ?, ?)
 		 ON CONFLICT (agent_id, user_id, file_name) DO UPDATE SET content = excluded.content, updated_at = excluded.updated_at`,
 		store.GenNewID(), agentID, userID, fileName, content, time.Now(), tenantIDForInsert(ctx),
-	)
-	return err
+	); err != nil {
+		return err
+	}
+	if !prev.Valid || prev.String != content {
+		if err := insertContextFileRevision(ctx, tx, agentID, userID, fileName, content); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
 }
 
 func (s *SQLiteAgentStore) ListUserContextFilesByName(ctx context.Context, agentID uuid.UUID, fileName string) ([]store.UserContextFileData, error) {
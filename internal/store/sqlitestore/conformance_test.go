@@ -0,0 +1,25 @@
+//go:build sqlite || sqliteonly
+
+package sqlitestore
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/nextlevelbuilder/goclaw/internal/store"
+	"github.com/nextlevelbuilder/goclaw/internal/store/storetest"
+)
+
+func TestSQLiteStores_Conformance(t *testing.T) {
+	stores, err := NewSQLiteStores(store.StoreConfig{
+		SQLitePath:       filepath.Join(t.TempDir(), "conformance.db"),
+		SkillsStorageDir: t.TempDir(),
+		EncryptionKey:    "01234567890123456789012345678901",
+	})
+	if err != nil {
+		t.Fatalf("NewSQLiteStores: %v", err)
+	}
+	t.Cleanup(func() { _ = stores.DB.Close() })
+
+	storetest.Run(t, stores)
+}
@@ -0,0 +1,182 @@
+//go:build sqlite || sqliteonly
+
+package sqlitestore
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/nextlevelbuilder/goclaw/internal/store"
+	"github.com/nextlevelbuilder/goclaw/internal/tracing"
+)
+
+// SQLiteContextFileRevisionStore implements store.ContextFileRevisionStore.
+// Revisions are ordered by rowid (insertion order): created_at mixes the
+// driver's time format with strftime defaults from the baseline migration.
+type SQLiteContextFileRevisionStore struct {
+	db *sql.DB
+}
+
+func NewSQLiteContextFileRevisionStore(db *sql.DB) *SQLiteContextFileRevisionStore {
+	return &SQLiteContextFileRevisionStore{db: db}
+}
+
+// insertContextFileRevision records content as a revision of a context file,
+// attributed to the writer in ctx. userID "" = agent-level file.
+func insertContextFileRevision(ctx context.Context, tx *sql.Tx, agentID uuid.UUID, userID, fileName, content string) error {
+	author, authorID := store.ContextFileAuthorFromContext(ctx)
+	traceID := tracing.TraceIDFromContext(ctx)
+	_, err := tx.ExecContext(ctx,
+		`INSERT INTO context_file_revisions (id, tenant_id, agent_id, user_id, file_name, content, author, author_id, trace_id, created_at)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		store.GenNewID(), tenantIDForInsert(ctx), agentID, userID, fileName, content, author, authorID,
+		nilUUID(&traceID), time.Now().UTC(),
+	)
+	return err
+}
+
+const contextFileRevisionCols = `id, agent_id, user_id, file_name, content, author, author_id, trace_id, created_at`
+
+func scanContextFileRevision(row interface{ Scan(...any) error }) (store.ContextFileRevision, error) {
+	var r store.ContextFileRevision
+	createdAt := &sqliteTime{}
+	err := row.Scan(&r.ID, &r.AgentID, &r.UserID, &r.FileName, &r.Content, &r.Author, &r.AuthorID, &r.TraceID, createdAt)
+	r.CreatedAt = createdAt.Time
+	return r, err
+}
+
+func (s *SQLiteContextFileRevisionStore) ListRevisions(ctx context.Context, agentID uuid.UUID, userID, fileName string, limit int) ([]store.ContextFileRevision, error) {
+	if limit <= 0 {
+		limit = 50
+	}
+	tClause, tArgs, err := scopeClause(ctx)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT `+contextFileRevisionCols+` FROM context_file_revisions
+		 WHERE agent_id = ? AND user_id = ? AND file_name = ?`+tClause+`
+		 ORDER BY rowid DESC LIMIT ?`,
+		append(append([]any{agentID, userID, fileName}, tArgs...), limit)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []store.ContextFileRevision
+	for rows.Next() {
+		r, err := scanContextFileRevision(rows)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, r)
+	}
+	return result, rows.Err()
+}
+
+func (s *SQLiteContextFileRevisionStore) GetRevision(ctx context.Context, id uuid.UUID) (*store.ContextFileRevision, error) {
+	tClause, tArgs, err := scopeClause(ctx)
+	if err != nil {
+		return nil, err
+	}
+	r, err := scanContextFileRevision(s.db.QueryRowContext(ctx,
+		`SELECT `+contextFileRevisionCols+` FROM context_file_revisions WHERE id = ?`+tClause,
+		append([]any{id}, tArgs...)...))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
+
+const contextFileChangeCols = `id, agent_id, user_id, file_name, content, author, author_id, trace_id, status, reviewed_by, reviewed_at, created_at`
+
+func scanContextFileChange(row interface{ Scan(...any) error }) (store.ContextFileChange, error) {
+	var c store.ContextFileChange
+	var reviewedAt nullSqliteTime
+	createdAt := &sqliteTime{}
+	err := row.Scan(&c.ID, &c.AgentID, &c.UserID, &c.FileName, &c.Content, &c.Author, &c.AuthorID, &c.TraceID,
+		&c.Status, &c.ReviewedBy, &reviewedAt, createdAt)
+	if reviewedAt.Valid {
+		c.ReviewedAt = &reviewedAt.Time
+	}
+	c.CreatedAt = createdAt.Time
+	return c, err
+}
+
+func (s *SQLiteContextFileRevisionStore) CreatePendingChange(ctx context.Context, c *store.ContextFileChange) error {
+	c.ID = store.GenNewID()
+	c.Status = store.ContextFileChangePending
+	c.CreatedAt = time.Now().UTC()
+	_, err := s.db.ExecContext(ctx,
+		`INSERT INTO context_file_changes (id, tenant_id, agent_id, user_id, file_name, content, author, author_id, trace_id, status, created_at)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		c.ID, tenantIDForInsert(ctx), c.AgentID, c.UserID, c.FileName, c.Content, c.Author, c.AuthorID, c.TraceID, c.Status, c.CreatedAt,
+	)
+	return err
+}
+
+func (s *SQLiteContextFileRevisionStore) ListPendingChanges(ctx context.Context, agentID uuid.UUID) ([]store.ContextFileChange, error) {
+	tClause, tArgs, err := scopeClause(ctx)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT `+contextFileChangeCols+` FROM context_file_changes
+		 WHERE agent_id = ? AND status = ?`+tClause+`
+		 ORDER BY rowid`,
+		append([]any{agentID, store.ContextFileChangePending}, tArgs...)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []store.ContextFileChange
+	for rows.Next() {
+		c, err := scanContextFileChange(rows)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, c)
+	}
+	return result, rows.Err()
+}
+
+func (s *SQLiteContextFileRevisionStore) GetPendingChange(ctx context.Context, id uuid.UUID) (*store.ContextFileChange, error) {
+	tClause, tArgs, err := scopeClause(ctx)
+	if err != nil {
+		return nil, err
+	}
+	c, err := scanContextFileChange(s.db.QueryRowContext(ctx,
+		`SELECT `+contextFileChangeCols+` FROM context_file_changes WHERE id = ?`+tClause,
+		append([]any{id}, tArgs...)...))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+func (s *SQLiteContextFileRevisionStore) ResolvePendingChange(ctx context.Context, id uuid.UUID, status, reviewedBy string) (bool, error) {
+	tClause, tArgs, err := scopeClause(ctx)
+	if err != nil {
+		return false, err
+	}
+	res, err := s.db.ExecContext(ctx,
+		`UPDATE context_file_changes SET status = ?, reviewed_by = ?, reviewed_at = ?
+		 WHERE id = ? AND status = ?`+tClause,
+		append([]any{status, reviewedBy, time.Now().UTC(), id, store.ContextFileChangePending}, tArgs...)...)
+	if err != nil {
+		return false, err
+	}
+	n, _ := res.RowsAffected()
+	return n > 0, nil
+}
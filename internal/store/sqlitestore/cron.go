@@ -28,6 +28,7 @@ type SQLiteCronStore struct {
 	cancelCtx context.CancelFunc
 	onJob     func(job *store.CronJob) (*store.CronJobResult, error)
 	onEvent   func(event store.CronEvent)
+	onChange  func() // after CRUD writes (see SetOnChange)
 	running   bool
 	stop      chan struct{}
 
@@ -80,7 +80,7 @@ func (s *SQLiteCronStore) AddJob(ctx context.Context, name string, schedule stor
 		return nil, fmt.Errorf("create cron job: %w", err)
 	}
 
-	s.InvalidateCache()
+	s.jobsChanged()
 	job, _ := s.GetJob(ctx, id.String())
 	return job, nil
 }
@@ -177,7 +177,7 @@ func (s *SQLiteCronStore) RemoveJob(ctx context.Context, jobID string) error {
 	if n, _ := res.RowsAffected(); n == 0 {
 		return fmt.Errorf("job not found")
 	}
-	s.InvalidateCache()
+	s.jobsChanged()
 	return nil
 }
 
@@ -219,7 +219,7 @@ func (s *SQLiteCronStore) EnableJob(ctx context.Context, jobID string, enabled b
 		return err
 	}
 
-	s.InvalidateCache()
+	s.jobsChanged()
 	return nil
 }
 
@@ -321,7 +321,7 @@ func (s *SQLiteCronStore) UpdateJob(ctx context.Context, jobID string, patch sto
 		return nil, err
 	}
 
-	s.InvalidateCache()
+	s.jobsChanged()
 	job, _ := s.scanJob(ctx, id)
 	return job, nil
 }
@@ -65,6 +65,35 @@ func TestSQLiteCronStore_ReenableRestoresNextRun(t *testing.T) {
 	}
 }
 
+func TestSQLiteCronStore_OnChangeFiresOnJobWrites(t *testing.T) {
+	cronStore, ctx, _ := newTestSQLiteCronStore(t)
+	everyMS := int64(time.Minute / time.Millisecond)
+
+	var changes atomic.Int32
+	cronStore.SetOnChange(func() { changes.Add(1) })
+
+	job, err := cronStore.AddJob(ctx, "job-onchange", store.CronSchedule{
+		Kind:    "every",
+		EveryMS: &everyMS,
+	}, "hello", false, "", "", "", "user-1")
+	if err != nil {
+		t.Fatalf("AddJob error: %v", err)
+	}
+	if job == nil {
+		job = mustOnlyJob(t, cronStore, ctx)
+	}
+	if err := cronStore.EnableJob(ctx, job.ID, false); err != nil {
+		t.Fatalf("disable error: %v", err)
+	}
+	if err := cronStore.RemoveJob(ctx, job.ID); err != nil {
+		t.Fatalf("RemoveJob error: %v", err)
+	}
+
+	if got := changes.Load(); got != 3 {
+		t.Fatalf("expected 3 change notifications, got %d", got)
+	}
+}
+
 func TestSQLiteCronStore_EnableAlreadyEnabledPreservesNextRun(t *testing.T) {
 	cronStore, ctx, _ := newTestSQLiteCronStore(t)
 	everyMS := int64(time.Hour / time.Millisecond)
@@ -69,6 +69,26 @@ func (s *SQLiteCronStore) InvalidateCache() {
 	s.mu.Unlock()
 }
 
+// SetOnChange registers a callback run after jobs are created, updated,
+// toggled or removed. The gateway uses it to broadcast a cron cache
+// invalidation so the replica running the scheduler drops its job cache too.
+func (s *SQLiteCronStore) SetOnChange(fn func()) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.onChange = fn
+}
+
+// jobsChanged drops the job cache after a CRUD write and runs the onChange callback.
+func (s *SQLiteCronStore) jobsChanged() {
+	s.mu.Lock()
+	s.cacheLoaded = false
+	fn := s.onChange
+	s.mu.Unlock()
+	if fn != nil {
+		fn()
+	}
+}
+
 // recomputeStaleJobs fixes enabled jobs with next_run_at = NULL on startup.
 func (s *SQLiteCronStore) recomputeStaleJobs() {
 	rows, err := s.db.QueryContext(s.baseCtx,
@@ -0,0 +1,230 @@
+//go:build sqlite || sqliteonly
+
+package sqlitestore
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/nextlevelbuilder/goclaw/internal/store"
+)
+
+// SQLiteEvalStore implements store.EvalStore. Runs are ordered by rowid
+// (insertion order), like context file revisions.
+type SQLiteEvalStore struct {
+	db *sql.DB
+}
+
+func NewSQLiteEvalStore(db *sql.DB) *SQLiteEvalStore {
+	return &SQLiteEvalStore{db: db}
+}
+
+// marshalJSONList encodes a slice for a JSON list column; nil becomes [].
+func marshalJSONList[T any](v []T) string {
+	if len(v) == 0 {
+		return "[]"
+	}
+	b, _ := json.Marshal(v)
+	return string(b)
+}
+
+const evalSuiteCols = `id, agent_id, name, description, judge_model, cases, created_by, created_at, updated_at`
+
+func scanEvalSuite(row interface{ Scan(...any) error }) (store.EvalSuite, error) {
+	var s store.EvalSuite
+	var cases string
+	createdAt, updatedAt := scanTimePair()
+	err := row.Scan(&s.ID, &s.AgentID, &s.Name, &s.Description, &s.JudgeModel, &cases, &s.CreatedBy, createdAt, updatedAt)
+	if err == nil && cases != "" {
+		err = json.Unmarshal([]byte(cases), &s.Cases)
+	}
+	s.CreatedAt, s.UpdatedAt = createdAt.Time, updatedAt.Time
+	return s, err
+}
+
+func (s *SQLiteEvalStore) CreateSuite(ctx context.Context, suite *store.EvalSuite) error {
+	suite.ID = store.GenNewID()
+	suite.CreatedAt = time.Now().UTC()
+	suite.UpdatedAt = suite.CreatedAt
+	_, err := s.db.ExecContext(ctx,
+		`INSERT INTO eval_suites (id, tenant_id, agent_id, name, description, judge_model, cases, created_by, created_at, updated_at)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		suite.ID, tenantIDForInsert(ctx), suite.AgentID, suite.Name, suite.Description, suite.JudgeModel,
+		marshalJSONList(suite.Cases), suite.CreatedBy, suite.CreatedAt, suite.UpdatedAt,
+	)
+	return err
+}
+
+func (s *SQLiteEvalStore) UpdateSuite(ctx context.Context, suite *store.EvalSuite) error {
+	tClause, tArgs, err := scopeClause(ctx)
+	if err != nil {
+		return err
+	}
+	suite.UpdatedAt = time.Now().UTC()
+	_, err = s.db.ExecContext(ctx,
+		`UPDATE eval_suites SET name = ?, description = ?, judge_model = ?, cases = ?, updated_at = ?
+		 WHERE id = ?`+tClause,
+		append([]any{suite.Name, suite.Description, suite.JudgeModel, marshalJSONList(suite.Cases), suite.UpdatedAt, suite.ID}, tArgs...)...)
+	return err
+}
+
+func (s *SQLiteEvalStore) GetSuite(ctx context.Context, id uuid.UUID) (*store.EvalSuite, error) {
+	tClause, tArgs, err := scopeClause(ctx)
+	if err != nil {
+		return nil, err
+	}
+	suite, err := scanEvalSuite(s.db.QueryRowContext(ctx,
+		`SELECT `+evalSuiteCols+` FROM eval_suites WHERE id = ?`+tClause,
+		append([]any{id}, tArgs...)...))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &suite, nil
+}
+
+func (s *SQLiteEvalStore) ListSuites(ctx context.Context, agentID uuid.UUID) ([]store.EvalSuite, error) {
+	tClause, tArgs, err := scopeClause(ctx)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT `+evalSuiteCols+` FROM eval_suites WHERE agent_id = ?`+tClause+` ORDER BY name`,
+		append([]any{agentID}, tArgs...)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []store.EvalSuite
+	for rows.Next() {
+		suite, err := scanEvalSuite(rows)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, suite)
+	}
+	return result, rows.Err()
+}
+
+func (s *SQLiteEvalStore) DeleteSuite(ctx context.Context, id uuid.UUID) error {
+	tClause, tArgs, err := scopeClause(ctx)
+	if err != nil {
+		return err
+	}
+	_, err = s.db.ExecContext(ctx, `DELETE FROM eval_suites WHERE id = ?`+tClause, append([]any{id}, tArgs...)...)
+	return err
+}
+
+const evalRunSummaryCols = `id, suite_id, agent_id, suite_name, status, tool_mode, total, passed, failed,
+	input_tokens, output_tokens, cost_usd, duration_ms, error, created_by, started_at, finished_at`
+
+func scanEvalRun(row interface{ Scan(...any) error }, withResults bool) (store.EvalRun, error) {
+	var r store.EvalRun
+	var results string
+	startedAt := &sqliteTime{}
+	var finishedAt nullSqliteTime
+	dest := []any{&r.ID, &r.SuiteID, &r.AgentID, &r.SuiteName, &r.Status, &r.ToolMode, &r.Total, &r.Passed, &r.Failed,
+		&r.InputTokens, &r.OutputTokens, &r.CostUSD, &r.DurationMS, &r.Error, &r.CreatedBy, startedAt, &finishedAt}
+	if withResults {
+		dest = append(dest, &results)
+	}
+	err := row.Scan(dest...)
+	if err == nil && results != "" {
+		err = json.Unmarshal([]byte(results), &r.Results)
+	}
+	r.StartedAt = startedAt.Time
+	if finishedAt.Valid {
+		r.FinishedAt = &finishedAt.Time
+	}
+	return r, err
+}
+
+func (s *SQLiteEvalStore) CreateRun(ctx context.Context, r *store.EvalRun) error {
+	r.ID = store.GenNewID()
+	if r.StartedAt.IsZero() {
+		r.StartedAt = time.Now().UTC()
+	}
+	_, err := s.db.ExecContext(ctx,
+		`INSERT INTO eval_runs (id, tenant_id, suite_id, agent_id, suite_name, status, tool_mode, total, created_by, started_at)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		r.ID, tenantIDForInsert(ctx), r.SuiteID, r.AgentID, r.SuiteName, r.Status, r.ToolMode, r.Total, r.CreatedBy, r.StartedAt,
+	)
+	return err
+}
+
+func (s *SQLiteEvalStore) UpdateRun(ctx context.Context, r *store.EvalRun) error {
+	tClause, tArgs, err := scopeClause(ctx)
+	if err != nil {
+		return err
+	}
+	var finishedAt any
+	if r.FinishedAt != nil {
+		finishedAt = r.FinishedAt.UTC()
+	}
+	_, err = s.db.ExecContext(ctx,
+		`UPDATE eval_runs SET status = ?, total = ?, passed = ?, failed = ?, input_tokens = ?, output_tokens = ?,
+			cost_usd = ?, duration_ms = ?, error = ?, results = ?, finished_at = ?
+		 WHERE id = ?`+tClause,
+		append([]any{r.Status, r.Total, r.Passed, r.Failed, r.InputTokens, r.OutputTokens,
+			r.CostUSD, r.DurationMS, r.Error, marshalJSONList(r.Results), finishedAt, r.ID}, tArgs...)...)
+	return err
+}
+
+func (s *SQLiteEvalStore) GetRun(ctx context.Context, id uuid.UUID) (*store.EvalRun, error) {
+	tClause, tArgs, err := scopeClause(ctx)
+	if err != nil {
+		return nil, err
+	}
+	r, err := scanEvalRun(s.db.QueryRowContext(ctx,
+		`SELECT `+evalRunSummaryCols+`, results FROM eval_runs WHERE id = ?`+tClause,
+		append([]any{id}, tArgs...)...), true)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
+
+func (s *SQLiteEvalStore) ListRuns(ctx context.Context, agentID, suiteID uuid.UUID, limit int) ([]store.EvalRun, error) {
+	if limit <= 0 {
+		limit = 50
+	}
+	where := "agent_id = ?"
+	args := []any{agentID}
+	if suiteID != uuid.Nil {
+		where += " AND suite_id = ?"
+		args = append(args, suiteID)
+	}
+	tClause, tArgs, err := scopeClause(ctx)
+	if err != nil {
+		return nil, err
+	}
+	args = append(append(args, tArgs...), limit)
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT `+evalRunSummaryCols+` FROM eval_runs WHERE `+where+tClause+`
+		 ORDER BY rowid DESC LIMIT ?`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []store.EvalRun
+	for rows.Next() {
+		r, err := scanEvalRun(rows, false)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, r)
+	}
+	return result, rows.Err()
+}
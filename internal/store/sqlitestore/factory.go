@@ -51,8 +51,12 @@ func NewSQLiteStores(cfg store.StoreConfig) (*store.Stores, error) {
 		APIKeys:          NewSQLiteAPIKeyStore(db),
 		ConfigPermissions: NewSQLiteConfigPermissionStore(db),
 		Memory:         NewSQLiteMemoryStore(db),
-		SubagentTasks:  NewSQLiteSubagentTaskStore(),
-		// Phase 2 Batch B+C stores (nil = gracefully skipped by gateway):
-		// AgentLinks, KnowledgeGraph, SecureCLI
+		SubagentTasks:  NewSQLiteSubagentTaskStore(db),
+		AgentLinks:     NewSQLiteAgentLinkStore(db),
+		KnowledgeGraph: NewSQLiteKnowledgeGraphStore(db),
+		SecureCLI:      NewSQLiteSecureCLIStore(db, cfg.EncryptionKey),
+		MessageQueue:   NewSQLiteMessageQueueStore(db),
+		ContextFileRevisions: NewSQLiteContextFileRevisionStore(db),
+		Evals:                NewSQLiteEvalStore(db),
 	}, nil
 }
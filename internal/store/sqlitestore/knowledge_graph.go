@@ -0,0 +1,292 @@
+//go:build sqlite || sqliteonly
+
+package sqlitestore
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/nextlevelbuilder/goclaw/internal/store"
+)
+
+// SQLiteKnowledgeGraphStore implements store.KnowledgeGraphStore backed by SQLite.
+// Entity search uses the kg_entities_fts FTS5 index (kept in sync by triggers);
+// there is no embedding column, so search is keyword-only.
+type SQLiteKnowledgeGraphStore struct {
+	db *sql.DB
+}
+
+func NewSQLiteKnowledgeGraphStore(db *sql.DB) *SQLiteKnowledgeGraphStore {
+	return &SQLiteKnowledgeGraphStore{db: db}
+}
+
+// SetEmbeddingProvider is a no-op for SQLite — vector search not supported.
+func (s *SQLiteKnowledgeGraphStore) SetEmbeddingProvider(_ store.EmbeddingProvider) {}
+
+func (s *SQLiteKnowledgeGraphStore) Close() error { return nil }
+
+const entitySelectCols = `id, agent_id, user_id, external_id, name, entity_type, COALESCE(description, ''),
+	COALESCE(properties, '{}'), COALESCE(source_id, ''), confidence, created_at, updated_at`
+
+// entityUpsertSQL inserts or refreshes an entity keyed by (agent_id, user_id, external_id)
+// and returns the id of the stored row (the existing one on conflict).
+const entityUpsertSQL = `
+	INSERT INTO kg_entities
+		(id, agent_id, user_id, external_id, name, entity_type, description, properties, source_id, confidence, tenant_id, created_at, updated_at)
+	VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	ON CONFLICT (agent_id, user_id, external_id) DO UPDATE SET
+		name        = excluded.name,
+		entity_type = excluded.entity_type,
+		description = excluded.description,
+		properties  = excluded.properties,
+		source_id   = excluded.source_id,
+		confidence  = excluded.confidence,
+		tenant_id   = excluded.tenant_id,
+		updated_at  = excluded.updated_at
+	RETURNING id`
+
+func (s *SQLiteKnowledgeGraphStore) UpsertEntity(ctx context.Context, entity *store.Entity) error {
+	props, err := json.Marshal(entity.Properties)
+	if err != nil {
+		props = []byte("{}")
+	}
+	now := time.Now().UTC()
+	var actualID string
+	return s.db.QueryRowContext(ctx, entityUpsertSQL,
+		uuid.Must(uuid.NewV7()), entity.AgentID, entity.UserID, entity.ExternalID, entity.Name, entity.EntityType,
+		entity.Description, string(props), entity.SourceID, entity.Confidence, tenantIDForInsert(ctx), now, now,
+	).Scan(&actualID)
+}
+
+func (s *SQLiteKnowledgeGraphStore) GetEntity(ctx context.Context, agentID, userID, entityID string) (*store.Entity, error) {
+	tc, tcArgs, err := scopeClause(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if store.IsSharedKG(ctx) {
+		row := s.db.QueryRowContext(ctx,
+			`SELECT `+entitySelectCols+` FROM kg_entities WHERE id = ? AND agent_id = ?`+tc,
+			append([]any{entityID, agentID}, tcArgs...)...,
+		)
+		return scanEntity(row)
+	}
+	row := s.db.QueryRowContext(ctx,
+		`SELECT `+entitySelectCols+` FROM kg_entities WHERE id = ? AND agent_id = ? AND user_id = ?`+tc,
+		append([]any{entityID, agentID, userID}, tcArgs...)...,
+	)
+	return scanEntity(row)
+}
+
+func (s *SQLiteKnowledgeGraphStore) DeleteEntity(ctx context.Context, agentID, userID, entityID string) error {
+	tc, tcArgs, err := scopeClause(ctx)
+	if err != nil {
+		return err
+	}
+	if store.IsSharedKG(ctx) {
+		_, err = s.db.ExecContext(ctx,
+			`DELETE FROM kg_entities WHERE id = ? AND agent_id = ?`+tc,
+			append([]any{entityID, agentID}, tcArgs...)...,
+		)
+		return err
+	}
+	_, err = s.db.ExecContext(ctx,
+		`DELETE FROM kg_entities WHERE id = ? AND agent_id = ? AND user_id = ?`+tc,
+		append([]any{entityID, agentID, userID}, tcArgs...)...,
+	)
+	return err
+}
+
+func (s *SQLiteKnowledgeGraphStore) ListEntities(ctx context.Context, agentID, userID string, opts store.EntityListOptions) ([]store.Entity, error) {
+	limit := opts.Limit
+	if limit <= 0 {
+		limit = 50
+	}
+
+	// Build dynamic WHERE clause: always filter by agent_id, optionally by user_id and entity_type
+	where := "agent_id = ?"
+	args := []any{agentID}
+	if !store.IsSharedKG(ctx) && userID != "" {
+		where += " AND user_id = ?"
+		args = append(args, userID)
+	}
+	if opts.EntityType != "" {
+		where += " AND entity_type = ?"
+		args = append(args, opts.EntityType)
+	}
+	tc, tcArgs, err := scopeClause(ctx)
+	if err != nil {
+		return nil, err
+	}
+	where += tc
+	args = append(args, tcArgs...)
+	args = append(args, limit, opts.Offset)
+
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT `+entitySelectCols+` FROM kg_entities WHERE `+where+`
+		 ORDER BY updated_at DESC LIMIT ? OFFSET ?`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanEntities(rows)
+}
+
+// SearchEntities performs FTS5 search over entity name and description,
+// ranked by bm25. Every query word must match (like PG's plainto_tsquery).
+func (s *SQLiteKnowledgeGraphStore) SearchEntities(ctx context.Context, agentID, userID, query string, limit int) ([]store.Entity, error) {
+	if limit <= 0 {
+		limit = 20
+	}
+	match := ftsMatchQuery(query)
+	if match == "" {
+		return nil, nil
+	}
+
+	where := "kg_entities_fts MATCH ? AND e.agent_id = ?"
+	args := []any{match, agentID}
+	if !store.IsSharedKG(ctx) && userID != "" {
+		where += " AND e.user_id = ?"
+		args = append(args, userID)
+	}
+	tc, tcArgs, err := scopeClauseAlias(ctx, "e")
+	if err != nil {
+		return nil, err
+	}
+	where += tc
+	args = append(args, tcArgs...)
+	args = append(args, limit)
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT e.id, e.agent_id, e.user_id, e.external_id, e.name, e.entity_type, COALESCE(e.description, ''),
+		       COALESCE(e.properties, '{}'), COALESCE(e.source_id, ''), e.confidence, e.created_at, e.updated_at
+		FROM kg_entities_fts
+		JOIN kg_entities e ON e.id = kg_entities_fts.entity_id
+		WHERE `+where+`
+		ORDER BY kg_entities_fts.rank LIMIT ?`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanEntities(rows)
+}
+
+// ftsMatchQuery turns free text into an FTS5 MATCH expression: each word is
+// quoted (so FTS5 operators and punctuation in user input are inert) and the
+// terms are implicitly AND-ed. Returns "" when there is nothing to search for.
+func ftsMatchQuery(query string) string {
+	words := strings.Fields(query)
+	terms := make([]string, 0, len(words))
+	for _, w := range words {
+		terms = append(terms, `"`+strings.ReplaceAll(w, `"`, `""`)+`"`)
+	}
+	return strings.Join(terms, " ")
+}
+
+func (s *SQLiteKnowledgeGraphStore) Stats(ctx context.Context, agentID, userID string) (*store.GraphStats, error) {
+	stats := &store.GraphStats{EntityTypes: make(map[string]int)}
+
+	filter := ""
+	args := []any{agentID}
+	if userID != "" {
+		filter = " AND user_id = ?"
+		args = append(args, userID)
+	}
+	tc, tcArgs, err := scopeClause(ctx)
+	if err != nil {
+		return nil, err
+	}
+	filter += tc
+	args = append(args, tcArgs...)
+
+	if err := s.db.QueryRowContext(ctx,
+		`SELECT COUNT(*) FROM kg_entities WHERE agent_id = ?`+filter, args...,
+	).Scan(&stats.EntityCount); err != nil {
+		return nil, err
+	}
+	if err := s.db.QueryRowContext(ctx,
+		`SELECT COUNT(*) FROM kg_relations WHERE agent_id = ? AND valid_to IS NULL`+filter, args...,
+	).Scan(&stats.RelationCount); err != nil {
+		return nil, err
+	}
+
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT entity_type, COUNT(*) FROM kg_entities WHERE agent_id = ?`+filter+` GROUP BY entity_type`, args...,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var t string
+		var c int
+		if err := rows.Scan(&t, &c); err != nil {
+			continue
+		}
+		stats.EntityTypes[t] = c
+	}
+	return stats, nil
+}
+
+// --- scan helpers ---
+
+func scanEntity(row interface{ Scan(...any) error }) (*store.Entity, error) {
+	var e store.Entity
+	var props []byte
+	createdAt, updatedAt := scanTimePair()
+	if err := row.Scan(
+		&e.ID, &e.AgentID, &e.UserID, &e.ExternalID, &e.Name, &e.EntityType,
+		&e.Description, &props, &e.SourceID, &e.Confidence, createdAt, updatedAt,
+	); err != nil {
+		return nil, err
+	}
+	json.Unmarshal(props, &e.Properties) //nolint:errcheck
+	e.CreatedAt = createdAt.Time.UnixMilli()
+	e.UpdatedAt = updatedAt.Time.UnixMilli()
+	return &e, nil
+}
+
+func scanEntities(rows *sql.Rows) ([]store.Entity, error) {
+	var result []store.Entity
+	for rows.Next() {
+		e, err := scanEntity(rows)
+		if err != nil {
+			continue
+		}
+		result = append(result, *e)
+	}
+	return result, rows.Err()
+}
+
+// scanRelations scans rows selected with relationSelectCols.
+func scanRelations(rows *sql.Rows) ([]store.Relation, error) {
+	var result []store.Relation
+	for rows.Next() {
+		var r store.Relation
+		var props, prov []byte
+		validFrom, createdAt := &sqliteTime{}, &sqliteTime{}
+		var validTo nullSqliteTime
+		if err := rows.Scan(
+			&r.ID, &r.AgentID, &r.UserID, &r.SourceEntityID, &r.RelationType,
+			&r.TargetEntityID, &r.Confidence, &props, validFrom, &validTo, &r.SupersededBy, &prov, createdAt,
+		); err != nil {
+			continue
+		}
+		json.Unmarshal(props, &r.Properties) //nolint:errcheck
+		r.ValidFrom = validFrom.Time.UnixMilli()
+		if validTo.Valid {
+			r.ValidTo = validTo.Time.UnixMilli()
+		}
+		var p store.KGProvenance
+		if json.Unmarshal(prov, &p) == nil && p != (store.KGProvenance{}) {
+			r.Provenance = &p
+		}
+		r.CreatedAt = createdAt.Time.UnixMilli()
+		result = append(result, r)
+	}
+	return result, rows.Err()
+}
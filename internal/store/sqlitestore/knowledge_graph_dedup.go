@@ -0,0 +1,364 @@
+//go:build sqlite || sqliteonly
+
+package sqlitestore
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+
+	kg "github.com/nextlevelbuilder/goclaw/internal/knowledgegraph"
+	"github.com/nextlevelbuilder/goclaw/internal/store"
+)
+
+// SQLite has no entity embeddings, so dedup relies on name similarity alone
+// (Jaro-Winkler within the same entity type). Name similarity is too weak a
+// signal to merge automatically — matches are only flagged as candidates for
+// manual review via ListDedupCandidates / MergeEntities.
+const (
+	dedupCandidateThreshold = 0.90
+	// dedupScanMaxEntities bounds the pairwise name comparison per entity type.
+	dedupScanMaxEntities = 2000
+)
+
+type dedupPeer struct {
+	id   string
+	name string
+}
+
+// DedupAfterExtraction flags newly upserted entities whose names closely match
+// an existing entity of the same type. Never auto-merges (merged is always 0).
+func (s *SQLiteKnowledgeGraphStore) DedupAfterExtraction(ctx context.Context, agentID, userID string, newEntityIDs []string) (int, int, error) {
+	if len(newEntityIDs) == 0 {
+		return 0, 0, nil
+	}
+	tc, tcArgs, err := scopeClause(ctx)
+	if err != nil {
+		return 0, 0, err
+	}
+	shared := store.IsSharedKG(ctx)
+	flagged := 0
+
+	for _, eid := range newEntityIDs {
+		var name, entityType string
+		if err := s.db.QueryRowContext(ctx,
+			`SELECT name, entity_type FROM kg_entities WHERE id = ? AND agent_id = ?`+tc,
+			append([]any{eid, agentID}, tcArgs...)...,
+		).Scan(&name, &entityType); err != nil {
+			continue // entity may have been deleted/merged already
+		}
+
+		peers, err := s.sameTypeEntities(ctx, agentID, userID, entityType, shared)
+		if err != nil {
+			slog.Warn("kg.dedup: peer query failed", "entity_id", eid, "error", err)
+			continue
+		}
+		for _, p := range peers {
+			if p.id == eid {
+				continue
+			}
+			sim := kg.JaroWinkler(name, p.name)
+			if sim < dedupCandidateThreshold {
+				continue
+			}
+			if err := s.insertDedupCandidate(ctx, agentID, userID, eid, p.id, sim); err != nil {
+				slog.Warn("kg.dedup: flag candidate failed", "error", err)
+				continue
+			}
+			flagged++
+		}
+	}
+	return 0, flagged, nil
+}
+
+// sameTypeEntities returns id + name of the most recently updated entities of
+// one type in scope, capped at dedupScanMaxEntities.
+func (s *SQLiteKnowledgeGraphStore) sameTypeEntities(ctx context.Context, agentID, userID, entityType string, shared bool) ([]dedupPeer, error) {
+	where := "agent_id = ? AND entity_type = ?"
+	args := []any{agentID, entityType}
+	if !shared && userID != "" {
+		where += " AND user_id = ?"
+		args = append(args, userID)
+	}
+	tc, tcArgs, err := scopeClause(ctx)
+	if err != nil {
+		return nil, err
+	}
+	where += tc
+	args = append(args, tcArgs...)
+	args = append(args, dedupScanMaxEntities)
+
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT id, name FROM kg_entities WHERE `+where+` ORDER BY updated_at DESC LIMIT ?`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var peers []dedupPeer
+	for rows.Next() {
+		var p dedupPeer
+		if err := rows.Scan(&p.id, &p.name); err != nil {
+			continue
+		}
+		peers = append(peers, p)
+	}
+	return peers, rows.Err()
+}
+
+func (s *SQLiteKnowledgeGraphStore) insertDedupCandidate(ctx context.Context, agentID, userID, entityAID, entityBID string, similarity float64) error {
+	// Ensure consistent ordering (smaller ID first) to avoid duplicates
+	if entityAID > entityBID {
+		entityAID, entityBID = entityBID, entityAID
+	}
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO kg_dedup_candidates (id, tenant_id, agent_id, user_id, entity_a_id, entity_b_id, similarity, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (entity_a_id, entity_b_id) DO NOTHING`,
+		uuid.Must(uuid.NewV7()), tenantIDForInsert(ctx), agentID, userID, entityAID, entityBID, similarity, time.Now().UTC(),
+	)
+	return err
+}
+
+// ScanDuplicates compares entity names pairwise within each entity type and
+// flags pairs at or above threshold. Returns number of candidates found.
+func (s *SQLiteKnowledgeGraphStore) ScanDuplicates(ctx context.Context, agentID, userID string, threshold float64, limit int) (int, error) {
+	if threshold <= 0 {
+		threshold = dedupCandidateThreshold
+	}
+	if limit <= 0 {
+		limit = 100
+	}
+	shared := store.IsSharedKG(ctx)
+
+	where := "agent_id = ?"
+	args := []any{agentID}
+	if !shared && userID != "" {
+		where += " AND user_id = ?"
+		args = append(args, userID)
+	}
+	tc, tcArgs, err := scopeClause(ctx)
+	if err != nil {
+		return 0, err
+	}
+	where += tc
+	args = append(args, tcArgs...)
+
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT DISTINCT entity_type FROM kg_entities WHERE `+where, args...)
+	if err != nil {
+		return 0, fmt.Errorf("kg.scan_duplicates: query failed: %w", err)
+	}
+	var types []string
+	for rows.Next() {
+		var t string
+		if err := rows.Scan(&t); err == nil {
+			types = append(types, t)
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	found := 0
+	for _, t := range types {
+		peers, err := s.sameTypeEntities(ctx, agentID, userID, t, shared)
+		if err != nil {
+			return found, fmt.Errorf("kg.scan_duplicates: query failed: %w", err)
+		}
+		for i := 0; i < len(peers); i++ {
+			for j := i + 1; j < len(peers); j++ {
+				sim := kg.JaroWinkler(peers[i].name, peers[j].name)
+				if sim <= threshold {
+					continue
+				}
+				if err := s.insertDedupCandidate(ctx, agentID, userID, peers[i].id, peers[j].id, sim); err != nil {
+					slog.Warn("kg.scan_duplicates: insert candidate failed", "error", err)
+					continue
+				}
+				found++
+				if found >= limit {
+					return found, nil
+				}
+			}
+		}
+	}
+	return found, nil
+}
+
+// MergeEntities merges sourceID into targetID: re-points all relations from
+// source to target, deletes the source entity. The write transaction
+// (BEGIN IMMEDIATE) serializes concurrent merges.
+func (s *SQLiteKnowledgeGraphStore) MergeEntities(ctx context.Context, agentID, userID, targetID, sourceID string) error {
+	tc, tcArgs, err := scopeClause(ctx)
+	if err != nil {
+		return err
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	// Verify both entities exist and belong to the same agent + tenant scope.
+	// When userID is empty, skip user_id filter (admin/shared view).
+	shared := store.IsSharedKG(ctx) || userID == ""
+	for _, eid := range []string{targetID, sourceID} {
+		q := `SELECT EXISTS(SELECT 1 FROM kg_entities WHERE id = ? AND agent_id = ?`
+		args := []any{eid, agentID}
+		if !shared {
+			q += ` AND user_id = ?`
+			args = append(args, userID)
+		}
+		q += tc + `)`
+		args = append(args, tcArgs...)
+
+		var exists bool
+		if err := tx.QueryRowContext(ctx, q, args...).Scan(&exists); err != nil {
+			return fmt.Errorf("kg.merge: entity check failed: %w", err)
+		}
+		if !exists {
+			return fmt.Errorf("kg.merge: entity %s not found or access denied", eid)
+		}
+	}
+
+	// Re-point relations from source to target.
+	// First delete relations that would become duplicates after re-pointing,
+	// then update the remaining ones.
+	for _, cols := range [][2]string{
+		{"source_entity_id", "target_entity_id"},
+		{"target_entity_id", "source_entity_id"},
+	} {
+		col, otherCol := cols[0], cols[1]
+		// Delete would-be-duplicate current relations (same type, same endpoints
+		// after re-point); history rows never conflict.
+		delQ := fmt.Sprintf(`
+			DELETE FROM kg_relations
+			WHERE %s = ? AND agent_id = ? AND valid_to IS NULL
+			AND EXISTS (
+				SELECT 1 FROM kg_relations r2
+				WHERE r2.%s = ? AND r2.valid_to IS NULL
+				AND r2.agent_id = kg_relations.agent_id
+				AND r2.user_id = kg_relations.user_id
+				AND r2.relation_type = kg_relations.relation_type
+				AND r2.%s = kg_relations.%s
+			)`, col, col, otherCol, otherCol)
+		if _, err := tx.ExecContext(ctx, delQ+tc, append([]any{sourceID, agentID, targetID}, tcArgs...)...); err != nil {
+			return fmt.Errorf("kg.merge: dedup relations %s failed: %w", col, err)
+		}
+		// Update remaining relations
+		updQ := fmt.Sprintf(`UPDATE kg_relations SET %s = ? WHERE %s = ? AND agent_id = ?`, col, col)
+		if _, err := tx.ExecContext(ctx, updQ+tc, append([]any{targetID, sourceID, agentID}, tcArgs...)...); err != nil {
+			return fmt.Errorf("kg.merge: re-point %s failed: %w", col, err)
+		}
+	}
+
+	// Delete the source entity (CASCADE removes any remaining orphan relations)
+	if _, err := tx.ExecContext(ctx, `DELETE FROM kg_entities WHERE id = ?`, sourceID); err != nil {
+		return fmt.Errorf("kg.merge: delete source failed: %w", err)
+	}
+
+	// Mark any dedup candidates referencing the source as merged
+	if _, err := tx.ExecContext(ctx, `
+		UPDATE kg_dedup_candidates SET status = 'merged'
+		WHERE (entity_a_id = ? OR entity_b_id = ?) AND status = 'pending'`, sourceID, sourceID); err != nil {
+		slog.Warn("kg.merge: update candidates failed", "error", err)
+	}
+
+	return tx.Commit()
+}
+
+// ListDedupCandidates returns pending dedup candidates for review.
+func (s *SQLiteKnowledgeGraphStore) ListDedupCandidates(ctx context.Context, agentID, userID string, limit int) ([]store.DedupCandidate, error) {
+	if limit <= 0 {
+		limit = 50
+	}
+
+	where := "c.agent_id = ? AND c.status = 'pending'"
+	args := []any{agentID}
+	if !store.IsSharedKG(ctx) && userID != "" {
+		where += " AND c.user_id = ?"
+		args = append(args, userID)
+	}
+	tc, tcArgs, err := scopeClauseAlias(ctx, "c")
+	if err != nil {
+		return nil, err
+	}
+	where += tc
+	args = append(args, tcArgs...)
+	args = append(args, limit)
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT c.id, c.similarity, c.status, c.created_at,
+		       a.id, a.agent_id, a.user_id, a.external_id, a.name, a.entity_type,
+		       COALESCE(a.description, ''), COALESCE(a.properties, '{}'), COALESCE(a.source_id, ''), a.confidence, a.created_at, a.updated_at,
+		       b.id, b.agent_id, b.user_id, b.external_id, b.name, b.entity_type,
+		       COALESCE(b.description, ''), COALESCE(b.properties, '{}'), COALESCE(b.source_id, ''), b.confidence, b.created_at, b.updated_at
+		FROM kg_dedup_candidates c
+		JOIN kg_entities a ON c.entity_a_id = a.id
+		JOIN kg_entities b ON c.entity_b_id = b.id
+		WHERE `+where+`
+		ORDER BY c.similarity DESC, c.created_at DESC
+		LIMIT ?`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var results []store.DedupCandidate
+	for rows.Next() {
+		var dc store.DedupCandidate
+		var propsA, propsB []byte
+		caA, uaA := scanTimePair()
+		caB, uaB := scanTimePair()
+		createdAt := &sqliteTime{}
+		if err := rows.Scan(
+			&dc.ID, &dc.Similarity, &dc.Status, createdAt,
+			&dc.EntityA.ID, &dc.EntityA.AgentID, &dc.EntityA.UserID, &dc.EntityA.ExternalID,
+			&dc.EntityA.Name, &dc.EntityA.EntityType, &dc.EntityA.Description, &propsA,
+			&dc.EntityA.SourceID, &dc.EntityA.Confidence, caA, uaA,
+			&dc.EntityB.ID, &dc.EntityB.AgentID, &dc.EntityB.UserID, &dc.EntityB.ExternalID,
+			&dc.EntityB.Name, &dc.EntityB.EntityType, &dc.EntityB.Description, &propsB,
+			&dc.EntityB.SourceID, &dc.EntityB.Confidence, caB, uaB,
+		); err != nil {
+			continue
+		}
+		json.Unmarshal(propsA, &dc.EntityA.Properties) //nolint:errcheck
+		json.Unmarshal(propsB, &dc.EntityB.Properties) //nolint:errcheck
+		dc.EntityA.CreatedAt = caA.Time.UnixMilli()
+		dc.EntityA.UpdatedAt = uaA.Time.UnixMilli()
+		dc.EntityB.CreatedAt = caB.Time.UnixMilli()
+		dc.EntityB.UpdatedAt = uaB.Time.UnixMilli()
+		dc.CreatedAt = createdAt.Time.Unix()
+		results = append(results, dc)
+	}
+	return results, rows.Err()
+}
+
+// DismissCandidate marks a dedup candidate as dismissed.
+// Scoped by agent_id + tenant to prevent cross-agent/cross-tenant dismissal.
+func (s *SQLiteKnowledgeGraphStore) DismissCandidate(ctx context.Context, agentID, candidateID string) error {
+	tc, tcArgs, err := scopeClause(ctx)
+	if err != nil {
+		return err
+	}
+	res, err := s.db.ExecContext(ctx,
+		`UPDATE kg_dedup_candidates SET status = 'dismissed' WHERE id = ? AND agent_id = ? AND status = 'pending'`+tc,
+		append([]any{candidateID, agentID}, tcArgs...)...,
+	)
+	if err != nil {
+		return err
+	}
+	n, _ := res.RowsAffected()
+	if n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
@@ -0,0 +1,182 @@
+//go:build sqlite || sqliteonly
+
+package sqlitestore
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/nextlevelbuilder/goclaw/internal/store"
+)
+
+const relationSelectCols = `id, agent_id, user_id, source_entity_id, relation_type, target_entity_id,
+	confidence, COALESCE(properties, '{}'), valid_from, valid_to, COALESCE(superseded_by, ''), provenance, created_at`
+
+func (s *SQLiteKnowledgeGraphStore) UpsertRelation(ctx context.Context, relation *store.Relation) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	if err := ingestRelation(ctx, tx, relation, relation.SourceEntityID, relation.TargetEntityID,
+		tenantIDForInsert(ctx), time.Now().UTC()); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (s *SQLiteKnowledgeGraphStore) DeleteRelation(ctx context.Context, agentID, userID, relationID string) error {
+	tc, tcArgs, err := scopeClause(ctx)
+	if err != nil {
+		return err
+	}
+	if store.IsSharedKG(ctx) {
+		_, err = s.db.ExecContext(ctx,
+			`DELETE FROM kg_relations WHERE id = ? AND agent_id = ?`+tc,
+			append([]any{relationID, agentID}, tcArgs...)...,
+		)
+		return err
+	}
+	_, err = s.db.ExecContext(ctx,
+		`DELETE FROM kg_relations WHERE id = ? AND agent_id = ? AND user_id = ?`+tc,
+		append([]any{relationID, agentID, userID}, tcArgs...)...,
+	)
+	return err
+}
+
+func (s *SQLiteKnowledgeGraphStore) ListRelations(ctx context.Context, agentID, userID, entityID string) ([]store.Relation, error) {
+	tc, tcArgs, err := scopeClause(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	where := "agent_id = ? AND valid_to IS NULL"
+	args := []any{agentID}
+	if !store.IsSharedKG(ctx) {
+		where += " AND user_id = ?"
+		args = append(args, userID)
+	}
+	where += " AND (source_entity_id = ? OR target_entity_id = ?)" + tc
+	args = append(args, entityID, entityID)
+	args = append(args, tcArgs...)
+
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT `+relationSelectCols+` FROM kg_relations WHERE `+where+` ORDER BY created_at DESC`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanRelations(rows)
+}
+
+func (s *SQLiteKnowledgeGraphStore) ListAllRelations(ctx context.Context, agentID, userID string, limit int) ([]store.Relation, error) {
+	if limit <= 0 {
+		limit = 200
+	}
+	where := "agent_id = ? AND valid_to IS NULL"
+	args := []any{agentID}
+	if !store.IsSharedKG(ctx) && userID != "" {
+		where += " AND user_id = ?"
+		args = append(args, userID)
+	}
+	tc, tcArgs, err := scopeClause(ctx)
+	if err != nil {
+		return nil, err
+	}
+	where += tc
+	args = append(args, tcArgs...)
+	args = append(args, limit)
+
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT `+relationSelectCols+` FROM kg_relations WHERE `+where+`
+		 ORDER BY created_at DESC LIMIT ?`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanRelations(rows)
+}
+
+func (s *SQLiteKnowledgeGraphStore) IngestExtraction(ctx context.Context, agentID, userID string, entities []store.Entity, relations []store.Relation) ([]string, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	now := time.Now().UTC()
+	tid := tenantIDForInsert(ctx)
+
+	// Upsert entities and build external_id → DB ID lookup for relations
+	extIDToID := make(map[string]string, len(entities))
+	for i := range entities {
+		e := &entities[i]
+		e.AgentID = agentID
+		e.UserID = userID
+		props, _ := json.Marshal(e.Properties)
+		// RETURNING gives the actual ID (could be the existing row on conflict)
+		var actualID string
+		if err := tx.QueryRowContext(ctx, entityUpsertSQL,
+			uuid.Must(uuid.NewV7()), agentID, userID, e.ExternalID, e.Name, e.EntityType,
+			e.Description, string(props), e.SourceID, e.Confidence, tid, now, now,
+		).Scan(&actualID); err != nil {
+			return nil, err
+		}
+		extIDToID[e.ExternalID] = actualID
+	}
+
+	for i := range relations {
+		r := &relations[i]
+		r.AgentID = agentID
+		r.UserID = userID
+		// Resolve external_id references to actual DB IDs
+		src, ok1 := extIDToID[r.SourceEntityID]
+		tgt, ok2 := extIDToID[r.TargetEntityID]
+		if !ok1 || !ok2 {
+			continue // skip relations referencing unknown entities
+		}
+		if err := ingestRelation(ctx, tx, r, src, tgt, tid, now); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	// Collect upserted entity IDs for downstream processing (e.g. dedup)
+	entityIDs := make([]string, 0, len(extIDToID))
+	for _, id := range extIDToID {
+		entityIDs = append(entityIDs, id)
+	}
+	return entityIDs, nil
+}
+
+func (s *SQLiteKnowledgeGraphStore) PruneByConfidence(ctx context.Context, agentID, userID string, minConfidence float64) (int, error) {
+	tc, tcArgs, err := scopeClause(ctx)
+	if err != nil {
+		return 0, err
+	}
+	var res sql.Result
+	if store.IsSharedKG(ctx) {
+		res, err = s.db.ExecContext(ctx,
+			`DELETE FROM kg_entities WHERE agent_id = ? AND confidence < ?`+tc,
+			append([]any{agentID, minConfidence}, tcArgs...)...,
+		)
+	} else {
+		res, err = s.db.ExecContext(ctx,
+			`DELETE FROM kg_entities WHERE agent_id = ? AND user_id = ? AND confidence < ?`+tc,
+			append([]any{agentID, userID, minConfidence}, tcArgs...)...,
+		)
+	}
+	if err != nil {
+		return 0, err
+	}
+	n, _ := res.RowsAffected()
+	return int(n), nil
+}
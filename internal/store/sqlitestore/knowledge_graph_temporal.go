@@ -0,0 +1,180 @@
+//go:build sqlite || sqliteonly
+
+package sqlitestore
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/nextlevelbuilder/goclaw/internal/store"
+)
+
+// kgTime formats validity bounds like the schema's strftime defaults, so
+// valid_from/valid_to comparisons can be done on the TEXT columns.
+func kgTime(ms int64) string {
+	return time.UnixMilli(ms).UTC().Format("2006-01-02T15:04:05.000Z")
+}
+
+// relationFact holds the resolved values of one relation being ingested.
+type relationFact struct {
+	rel         *store.Relation
+	src, tgt    string
+	tid         uuid.UUID
+	props, prov string
+	now         time.Time
+}
+
+// ingestRelation records one fact in tx and sets r.ID to its row.
+//
+// An ended fact (ValidTo set) closes the matching current fact, or is kept as
+// history when there is none. A current fact is upserted against the current
+// version of the same (source, type, target); for exclusive relation types it
+// closes the source's other current facts, unless one of them is newer, in
+// which case the incoming fact is recorded as already superseded.
+func ingestRelation(ctx context.Context, tx *sql.Tx, r *store.Relation, src, tgt string, tid uuid.UUID, now time.Time) error {
+	f := relationFact{rel: r, src: src, tgt: tgt, tid: tid, now: now, props: "{}", prov: "{}"}
+	if props, err := json.Marshal(r.Properties); err == nil {
+		f.props = string(props)
+	}
+	prov := r.Provenance
+	if prov == nil {
+		prov = store.KGProvenanceFromContext(ctx)
+	}
+	if prov != nil {
+		if b, err := json.Marshal(prov); err == nil {
+			f.prov = string(b)
+		}
+	}
+	validFrom := kgTime(now.UnixMilli())
+	if r.ValidFrom > 0 {
+		validFrom = kgTime(r.ValidFrom)
+	}
+
+	if r.ValidTo > 0 {
+		validTo := kgTime(r.ValidTo)
+		err := tx.QueryRowContext(ctx, `
+			UPDATE kg_relations SET valid_to = ?
+			WHERE agent_id = ? AND user_id = ? AND source_entity_id = ? AND relation_type = ?
+			  AND target_entity_id = ? AND valid_to IS NULL AND valid_from < ?
+			RETURNING id`,
+			validTo, r.AgentID, r.UserID, src, r.RelationType, tgt, validTo,
+		).Scan(&r.ID)
+		if !errors.Is(err, sql.ErrNoRows) {
+			return err
+		}
+		if validFrom > validTo {
+			validFrom = validTo
+		}
+		return insertClosedRelation(ctx, tx, f, validFrom, validTo, "")
+	}
+
+	exclusive := store.IsExclusiveRelation(r.RelationType)
+	if exclusive {
+		var newerID, newerFrom string
+		err := tx.QueryRowContext(ctx, `
+			SELECT id, valid_from FROM kg_relations
+			WHERE agent_id = ? AND user_id = ? AND source_entity_id = ? AND relation_type = ?
+			  AND target_entity_id <> ? AND valid_to IS NULL AND valid_from > ?
+			ORDER BY valid_from LIMIT 1`,
+			r.AgentID, r.UserID, src, r.RelationType, tgt, validFrom,
+		).Scan(&newerID, &newerFrom)
+		if err == nil {
+			return insertClosedRelation(ctx, tx, f, validFrom, newerFrom, newerID)
+		}
+		if !errors.Is(err, sql.ErrNoRows) {
+			return err
+		}
+	}
+
+	if err := tx.QueryRowContext(ctx, `
+		INSERT INTO kg_relations
+			(id, agent_id, user_id, source_entity_id, relation_type, target_entity_id, confidence, properties, tenant_id, valid_from, provenance, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (agent_id, user_id, source_entity_id, relation_type, target_entity_id) WHERE valid_to IS NULL DO UPDATE SET
+			confidence = excluded.confidence,
+			properties = excluded.properties,
+			tenant_id  = excluded.tenant_id,
+			valid_from = MIN(kg_relations.valid_from, excluded.valid_from)
+		RETURNING id`,
+		uuid.Must(uuid.NewV7()), r.AgentID, r.UserID, src, r.RelationType, tgt, r.Confidence, f.props, tid, validFrom, f.prov, now,
+	).Scan(&r.ID); err != nil {
+		return err
+	}
+
+	if exclusive {
+		if _, err := tx.ExecContext(ctx, `
+			UPDATE kg_relations SET valid_to = MAX(valid_from, ?), superseded_by = ?
+			WHERE agent_id = ? AND user_id = ? AND source_entity_id = ? AND relation_type = ?
+			  AND target_entity_id <> ? AND valid_to IS NULL`,
+			validFrom, r.ID, r.AgentID, r.UserID, src, r.RelationType, tgt,
+		); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// insertClosedRelation records a fact that no longer holds. Re-ingesting the
+// same closed version (e.g. importing an archive twice) is a no-op.
+func insertClosedRelation(ctx context.Context, tx *sql.Tx, f relationFact, validFrom, validTo, supersededBy string) error {
+	r := f.rel
+	err := tx.QueryRowContext(ctx, `
+		SELECT id FROM kg_relations
+		WHERE agent_id = ? AND user_id = ? AND source_entity_id = ? AND relation_type = ?
+		  AND target_entity_id = ? AND valid_to = ?
+		LIMIT 1`,
+		r.AgentID, r.UserID, f.src, r.RelationType, f.tgt, validTo,
+	).Scan(&r.ID)
+	if !errors.Is(err, sql.ErrNoRows) {
+		return err
+	}
+
+	var superseded any
+	if supersededBy != "" {
+		superseded = supersededBy
+	}
+	id := uuid.Must(uuid.NewV7()).String()
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO kg_relations
+			(id, agent_id, user_id, source_entity_id, relation_type, target_entity_id, confidence, properties, tenant_id,
+			 valid_from, valid_to, superseded_by, provenance, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		id, r.AgentID, r.UserID, f.src, r.RelationType, f.tgt, r.Confidence, f.props, f.tid,
+		validFrom, validTo, superseded, f.prov, f.now,
+	); err != nil {
+		return err
+	}
+	r.ID = id
+	return nil
+}
+
+// ListRelationHistory returns every version of an entity's relations, newest first.
+func (s *SQLiteKnowledgeGraphStore) ListRelationHistory(ctx context.Context, agentID, userID, entityID string) ([]store.Relation, error) {
+	tc, tcArgs, err := scopeClause(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	where := "agent_id = ?"
+	args := []any{agentID}
+	if !store.IsSharedKG(ctx) {
+		where += " AND user_id = ?"
+		args = append(args, userID)
+	}
+	where += " AND (source_entity_id = ? OR target_entity_id = ?)" + tc
+	args = append(args, entityID, entityID)
+	args = append(args, tcArgs...)
+
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT `+relationSelectCols+` FROM kg_relations WHERE `+where+` ORDER BY valid_from DESC, rowid DESC`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanRelations(rows)
+}
@@ -0,0 +1,141 @@
+//go:build sqlite || sqliteonly
+
+package sqlitestore
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/nextlevelbuilder/goclaw/internal/store"
+)
+
+// Traverse walks the knowledge graph from startEntityID up to maxDepth hops
+// using a recursive CTE. Returns all reachable entities (excluding the start node).
+//
+// SQLite has no array type, so the visited path is carried as a comma-joined
+// list of entity IDs; cycle detection uses instr() on that list (IDs are
+// fixed-length UUIDs, so substring matches are exact). A 5-second deadline
+// replaces PG's statement_timeout. Only relations valid at asOf are followed
+// (0 = current facts).
+func (s *SQLiteKnowledgeGraphStore) Traverse(ctx context.Context, agentID, userID, startEntityID string, maxDepth int, asOf int64) ([]store.TraversalResult, error) {
+	if maxDepth <= 0 {
+		maxDepth = 3
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	tc, tcArgs, err := scopeClauseAlias(ctx, "e")
+	if err != nil {
+		return nil, err
+	}
+
+	// Shared KG walks every user's edges; otherwise both the relation and the
+	// reached entity must belong to userID (mirrors the PG variants).
+	anchor := "e.id = ? AND e.agent_id = ?"
+	args := []any{startEntityID, agentID}
+	stepRel := "r.agent_id = ?"
+	stepEnt := "e.agent_id = ?"
+	relArgs := []any{agentID}
+	entArgs := []any{agentID}
+	if !store.IsSharedKG(ctx) {
+		anchor += " AND e.user_id = ?"
+		args = append(args, userID)
+		stepRel += " AND r.user_id = ?"
+		stepEnt += " AND e.user_id = ?"
+		relArgs = append(relArgs, userID)
+		entArgs = append(entArgs, userID)
+	}
+	if asOf > 0 {
+		at := kgTime(asOf)
+		stepRel += " AND r.valid_from <= ? AND (r.valid_to IS NULL OR r.valid_to > ?)"
+		relArgs = append(relArgs, at, at)
+	} else {
+		stepRel += " AND r.valid_to IS NULL"
+	}
+	args = append(args, tcArgs...)
+	args = append(args, relArgs...)
+	args = append(args, entArgs...)
+	args = append(args, maxDepth)
+
+	q := `
+		WITH RECURSIVE paths(id, agent_id, user_id, external_id, name, entity_type, description,
+		                     properties, source_id, confidence, created_at, updated_at, depth, path, via) AS (
+			SELECT
+				e.id, e.agent_id, e.user_id, e.external_id,
+				e.name, e.entity_type, COALESCE(e.description, ''),
+				COALESCE(e.properties, '{}'), COALESCE(e.source_id, ''), e.confidence,
+				e.created_at, e.updated_at,
+				1,
+				e.id,
+				''
+			FROM kg_entities e
+			WHERE ` + anchor + tc + `
+
+			UNION ALL
+
+			SELECT
+				e.id, e.agent_id, e.user_id, e.external_id,
+				e.name, e.entity_type, COALESCE(e.description, ''),
+				COALESCE(e.properties, '{}'), COALESCE(e.source_id, ''), e.confidence,
+				e.created_at, e.updated_at,
+				p.depth + 1,
+				p.path || ',' || e.id,
+				CASE WHEN r.source_entity_id = p.id
+					THEN r.relation_type
+					ELSE '~' || r.relation_type
+				END
+			FROM paths p
+			JOIN kg_relations r ON (r.source_entity_id = p.id OR r.target_entity_id = p.id) AND ` + stepRel + `
+			JOIN kg_entities  e ON e.id = (CASE WHEN r.source_entity_id = p.id THEN r.target_entity_id ELSE r.source_entity_id END) AND ` + stepEnt + `
+			WHERE p.depth < ?
+			  AND instr(p.path, e.id) = 0
+		)
+		SELECT
+			id, agent_id, user_id, external_id,
+			name, entity_type, description,
+			properties, source_id, confidence,
+			created_at, updated_at,
+			depth, path, via
+		FROM paths WHERE depth > 1`
+
+	rows, err := s.db.QueryContext(ctx, q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var results []store.TraversalResult
+	for rows.Next() {
+		var e store.Entity
+		var props []byte
+		createdAt, updatedAt := scanTimePair()
+		var depth int
+		var path, via string
+
+		if err := rows.Scan(
+			&e.ID, &e.AgentID, &e.UserID, &e.ExternalID,
+			&e.Name, &e.EntityType, &e.Description,
+			&props, &e.SourceID, &e.Confidence,
+			createdAt, updatedAt,
+			&depth, &path, &via,
+		); err != nil {
+			continue
+		}
+		if len(props) > 0 {
+			json.Unmarshal(props, &e.Properties) //nolint:errcheck
+		}
+		e.CreatedAt = createdAt.Time.UnixMilli()
+		e.UpdatedAt = updatedAt.Time.UnixMilli()
+
+		results = append(results, store.TraversalResult{
+			Entity: e,
+			Depth:  depth,
+			Path:   strings.Split(path, ","),
+			Via:    via,
+		})
+	}
+	return results, rows.Err()
+}
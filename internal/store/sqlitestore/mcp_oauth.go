@@ -0,0 +1,202 @@
+//go:build sqlite || sqliteonly
+
+package sqlitestore
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/nextlevelbuilder/goclaw/internal/crypto"
+	"github.com/nextlevelbuilder/goclaw/internal/store"
+)
+
+// GetOAuthClient returns the registered OAuth client for an MCP server.
+// Returns (nil, nil) if none is registered.
+func (s *SQLiteMCPServerStore) GetOAuthClient(ctx context.Context, serverID uuid.UUID) (*store.MCPOAuthClient, error) {
+	tid := tenantIDForInsert(ctx)
+	var c store.MCPOAuthClient
+	var secret sql.NullString
+	err := s.db.QueryRowContext(ctx,
+		`SELECT client_id, client_secret, redirect_uri FROM mcp_oauth_clients
+		 WHERE server_id = ? AND tenant_id = ?`,
+		serverID, tid,
+	).Scan(&c.ClientID, &secret, &c.RedirectURI)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	c.ClientSecret = s.decryptSecret(secret)
+	return &c, nil
+}
+
+// SetOAuthClient creates or replaces the OAuth client for an MCP server.
+func (s *SQLiteMCPServerStore) SetOAuthClient(ctx context.Context, serverID uuid.UUID, c store.MCPOAuthClient) error {
+	tid := tenantIDForInsert(ctx)
+	secret, err := s.encryptSecret(c.ClientSecret)
+	if err != nil {
+		return fmt.Errorf("encrypt mcp oauth client_secret: %w", err)
+	}
+	now := time.Now().UTC()
+	_, err = s.db.ExecContext(ctx,
+		`INSERT INTO mcp_oauth_clients (id, server_id, tenant_id, client_id, client_secret, redirect_uri, created_at, updated_at)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		 ON CONFLICT (server_id, tenant_id) DO UPDATE SET
+		   client_id = excluded.client_id, client_secret = excluded.client_secret,
+		   redirect_uri = excluded.redirect_uri, updated_at = excluded.updated_at`,
+		store.GenNewID(), serverID, tid, c.ClientID, secret, c.RedirectURI, now, now,
+	)
+	return err
+}
+
+// GetOAuthToken returns a user's OAuth token for an MCP server.
+// Returns (nil, nil) if the user has not authorized the server.
+func (s *SQLiteMCPServerStore) GetOAuthToken(ctx context.Context, serverID uuid.UUID, userID string) (*store.MCPOAuthToken, error) {
+	tid := tenantIDForInsert(ctx)
+	var t store.MCPOAuthToken
+	var access string
+	var refresh sql.NullString
+	var expiresAt nullSqliteTime
+	updatedAt := &sqliteTime{}
+	err := s.db.QueryRowContext(ctx,
+		`SELECT access_token, refresh_token, token_type, scope, expires_at, updated_at FROM mcp_oauth_tokens
+		 WHERE server_id = ? AND user_id = ? AND tenant_id = ?`,
+		serverID, userID, tid,
+	).Scan(&access, &refresh, &t.TokenType, &t.Scope, &expiresAt, updatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	t.AccessToken = s.decryptSecret(sql.NullString{String: access, Valid: true})
+	t.RefreshToken = s.decryptSecret(refresh)
+	if expiresAt.Valid {
+		t.ExpiresAt = expiresAt.Time
+	}
+	t.UpdatedAt = updatedAt.Time
+	return &t, nil
+}
+
+// SetOAuthToken creates or replaces a user's OAuth token for an MCP server.
+func (s *SQLiteMCPServerStore) SetOAuthToken(ctx context.Context, serverID uuid.UUID, userID string, t store.MCPOAuthToken) error {
+	tid := tenantIDForInsert(ctx)
+	access, err := s.encryptSecret(t.AccessToken)
+	if err != nil {
+		return fmt.Errorf("encrypt mcp oauth access_token: %w", err)
+	}
+	refresh, err := s.encryptSecret(t.RefreshToken)
+	if err != nil {
+		return fmt.Errorf("encrypt mcp oauth refresh_token: %w", err)
+	}
+	var expiresAt *time.Time
+	if !t.ExpiresAt.IsZero() {
+		e := t.ExpiresAt.UTC()
+		expiresAt = &e
+	}
+	tokenType := t.TokenType
+	if tokenType == "" {
+		tokenType = "Bearer"
+	}
+	now := time.Now().UTC()
+	_, err = s.db.ExecContext(ctx,
+		`INSERT INTO mcp_oauth_tokens (id, server_id, user_id, tenant_id, access_token, refresh_token, token_type, scope, expires_at, created_at, updated_at)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		 ON CONFLICT (server_id, user_id, tenant_id) DO UPDATE SET
+		   access_token = excluded.access_token, refresh_token = excluded.refresh_token,
+		   token_type = excluded.token_type, scope = excluded.scope,
+		   expires_at = excluded.expires_at, updated_at = excluded.updated_at`,
+		store.GenNewID(), serverID, userID, tid, access.String, refresh, tokenType, t.Scope, expiresAt, now, now,
+	)
+	return err
+}
+
+// DeleteOAuthToken removes a user's OAuth token for an MCP server.
+func (s *SQLiteMCPServerStore) DeleteOAuthToken(ctx context.Context, serverID uuid.UUID, userID string) error {
+	tid := tenantIDForInsert(ctx)
+	_, err := s.db.ExecContext(ctx,
+		`DELETE FROM mcp_oauth_tokens WHERE server_id = ? AND user_id = ? AND tenant_id = ?`,
+		serverID, userID, tid,
+	)
+	return err
+}
+
+// CreateOAuthPending stores a started authorization and drops expired ones.
+func (s *SQLiteMCPServerStore) CreateOAuthPending(ctx context.Context, p store.MCPOAuthPending) error {
+	verifier, err := s.encryptSecret(p.Verifier)
+	if err != nil {
+		return fmt.Errorf("encrypt mcp oauth code verifier: %w", err)
+	}
+	now := time.Now().UTC()
+	if _, err := s.db.ExecContext(ctx, `DELETE FROM mcp_oauth_pending WHERE expires_at < ?`, now); err != nil {
+		return err
+	}
+	_, err = s.db.ExecContext(ctx,
+		`INSERT INTO mcp_oauth_pending (state, tenant_id, server_id, user_id, verifier, channel, chat_id, expires_at, created_at)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		p.State, p.TenantID, p.ServerID, p.UserID, verifier.String, p.Channel, p.ChatID, p.ExpiresAt.UTC(), now,
+	)
+	return err
+}
+
+// TakeOAuthPending removes and returns the unexpired authorization with the
+// given state. The callback carries no tenant, so the lookup is by state only.
+func (s *SQLiteMCPServerStore) TakeOAuthPending(ctx context.Context, state string) (*store.MCPOAuthPending, error) {
+	p := store.MCPOAuthPending{State: state}
+	var verifier string
+	expiresAt := &sqliteTime{}
+	err := s.db.QueryRowContext(ctx,
+		`DELETE FROM mcp_oauth_pending WHERE state = ?
+		 RETURNING tenant_id, server_id, user_id, verifier, channel, chat_id, expires_at`,
+		state,
+	).Scan(&p.TenantID, &p.ServerID, &p.UserID, &verifier, &p.Channel, &p.ChatID, expiresAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	p.ExpiresAt = expiresAt.Time
+	if time.Now().After(p.ExpiresAt) {
+		return nil, nil
+	}
+	p.Verifier = s.decryptSecret(sql.NullString{String: verifier, Valid: true})
+	return &p, nil
+}
+
+// encryptSecret encrypts a secret column value. Empty input is stored as NULL;
+// without an encryption key the value is stored as-is, like api_key.
+func (s *SQLiteMCPServerStore) encryptSecret(v string) (sql.NullString, error) {
+	if v == "" {
+		return sql.NullString{}, nil
+	}
+	if s.encKey == "" {
+		return sql.NullString{String: v, Valid: true}, nil
+	}
+	enc, err := crypto.Encrypt(v, s.encKey)
+	if err != nil {
+		return sql.NullString{}, err
+	}
+	return sql.NullString{String: enc, Valid: true}, nil
+}
+
+// decryptSecret reverses encryptSecret.
+func (s *SQLiteMCPServerStore) decryptSecret(v sql.NullString) string {
+	if !v.Valid || v.String == "" {
+		return ""
+	}
+	if s.encKey == "" {
+		return v.String
+	}
+	dec, err := crypto.Decrypt(v.String, s.encKey)
+	if err != nil {
+		return ""
+	}
+	return dec
+}
@@ -11,13 +11,15 @@ import (
 )
 
 // SQLiteMemoryStore implements store.MemoryStore backed by SQLite.
-// Vector search is not available in the Lite edition (VectorSearch: false).
-// FTS uses simple LIKE queries instead of tsvector.
+// Full-text search uses the memory_chunks_fts FTS5 index (LIKE as fallback);
+// vector search brute-forces int8-quantized chunk embeddings held in an
+// in-process index. Both are merged with the same weighting as the PG store.
 type SQLiteMemoryStore struct {
 	db       *sql.DB
 	provider store.EmbeddingProvider
 	mu       sync.RWMutex
 	cfg      SQLiteMemoryConfig
+	vectors  *vectorIndex
 }
 
 // SQLiteMemoryConfig configures the SQLite memory store.
@@ -35,20 +37,30 @@ func DefaultSQLiteMemoryConfig() SQLiteMemoryConfig {
 		MaxChunkLen:  1000,
 		ChunkOverlap: 200,
 		MaxResults:   6,
-		TextWeight:   1.0,
-		VectorWeight: 0.0, // no vector search in SQLite edition
+		TextWeight:   0.3,
+		VectorWeight: 0.7,
 	}
 }
 
 // NewSQLiteMemoryStore creates a new SQLite-backed memory store.
 func NewSQLiteMemoryStore(db *sql.DB) *SQLiteMemoryStore {
-	return &SQLiteMemoryStore{db: db, cfg: DefaultSQLiteMemoryConfig()}
+	return &SQLiteMemoryStore{db: db, cfg: DefaultSQLiteMemoryConfig(), vectors: newVectorIndex()}
 }
 
-// SetEmbeddingProvider stores the provider reference but embeddings are not
-// persisted in SQLite (no vector column). Kept for interface compatibility.
+// SetEmbeddingProvider enables chunk embeddings on indexing and vector
+// search. Chunks indexed before it was set are picked up by BackfillEmbeddings.
 func (s *SQLiteMemoryStore) SetEmbeddingProvider(provider store.EmbeddingProvider) {
+	s.mu.Lock()
 	s.provider = provider
+	s.mu.Unlock()
+	s.vectors.invalidateAll()
+}
+
+// embeddingProvider returns the current provider (thread-safe).
+func (s *SQLiteMemoryStore) embeddingProvider() store.EmbeddingProvider {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.provider
 }
 
 // UpdateChunkConfig updates chunk splitting parameters at runtime.
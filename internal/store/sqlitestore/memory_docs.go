@@ -93,6 +93,7 @@ func (s *SQLiteMemoryStore) DeleteDocument(ctx context.Context, agentID, userID,
 	if n == 0 {
 		return fmt.Errorf("document not found: %s", path)
 	}
+	s.vectors.invalidate(agentID)
 	return nil
 }
 
@@ -138,7 +139,8 @@ func (s *SQLiteMemoryStore) ListDocuments(ctx context.Context, agentID, userID s
 	return result, nil
 }
 
-// IndexDocument chunks a document and stores chunks (without embeddings in SQLite).
+// IndexDocument chunks a document and stores chunks, with int8-quantized
+// embeddings when an embedding provider is set.
 func (s *SQLiteMemoryStore) IndexDocument(ctx context.Context, agentID, userID, path string) error {
 	content, err := s.GetDocument(ctx, agentID, userID, path)
 	if err != nil {
@@ -189,26 +191,37 @@ func (s *SQLiteMemoryStore) IndexDocument(ctx context.Context, agentID, userID,
 		return nil
 	}
 
+	// Generate embeddings with cache
+	var embeddings [][]byte
+	if provider := s.embeddingProvider(); provider != nil {
+		embeddings = s.embedChunks(ctx, provider, path, chunks)
+	}
+
 	tid := tenantIDForInsert(ctx).String()
 	var uid *string
 	if userID != "" {
 		uid = &userID
 	}
 
-	for _, tc := range chunks {
+	for i, tc := range chunks {
 		hash := memory.ContentHash(tc.Text)
 		chunkID := uuid.Must(uuid.NewV7()).String()
 		now := time.Now().UTC()
+		var embedding []byte
+		if i < len(embeddings) {
+			embedding = embeddings[i]
+		}
 
 		if _, err := s.db.ExecContext(ctx,
-			`INSERT INTO memory_chunks (id, agent_id, document_id, user_id, path, start_line, end_line, hash, text, tenant_id, updated_at)
-			 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+			`INSERT INTO memory_chunks (id, agent_id, document_id, user_id, path, start_line, end_line, hash, text, embedding, tenant_id, updated_at)
+			 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 			 ON CONFLICT DO NOTHING`,
-			chunkID, agentID, docID, uid, path, tc.StartLine, tc.EndLine, hash, tc.Text, tid, now,
+			chunkID, agentID, docID, uid, path, tc.StartLine, tc.EndLine, hash, tc.Text, embedding, tid, now,
 		); err != nil {
 			slog.Warn("memory: insert chunk failed", "path", path, "error", err)
 		}
 	}
+	s.vectors.invalidate(agentID)
 	return nil
 }
 
@@ -299,8 +312,7 @@ func (s *SQLiteMemoryStore) ListAllDocuments(ctx context.Context, agentID string
 	return result, nil
 }
 
-// GetDocumentDetail returns full document info with chunk count.
-// EmbeddedCount is always 0 (no embedding column in SQLite).
+// GetDocumentDetail returns full document info with chunk and embedding counts.
 func (s *SQLiteMemoryStore) GetDocumentDetail(ctx context.Context, agentID, userID, path string) (*store.DocumentDetail, error) {
 	var q string
 	var args []any
@@ -311,7 +323,7 @@ func (s *SQLiteMemoryStore) GetDocumentDetail(ctx context.Context, agentID, user
 			return nil, err
 		}
 		q = `SELECT d.path, d.content, d.hash, d.user_id, d.created_at, d.updated_at,
-				COUNT(c.id) AS chunk_count
+				COUNT(c.id) AS chunk_count, COUNT(c.embedding) AS embedded_count
 			 FROM memory_documents d
 			 LEFT JOIN memory_chunks c ON c.document_id = d.id
 			 WHERE d.agent_id = ? AND d.path = ? AND d.user_id IS NULL` + tc + `
@@ -323,7 +335,7 @@ func (s *SQLiteMemoryStore) GetDocumentDetail(ctx context.Context, agentID, user
 			return nil, err
 		}
 		q = `SELECT d.path, d.content, d.hash, d.user_id, d.created_at, d.updated_at,
-				COUNT(c.id) AS chunk_count
+				COUNT(c.id) AS chunk_count, COUNT(c.embedding) AS embedded_count
 			 FROM memory_documents d
 			 LEFT JOIN memory_chunks c ON c.document_id = d.id
 			 WHERE d.agent_id = ? AND d.path = ? AND d.user_id = ?` + tc + `
@@ -333,10 +345,10 @@ func (s *SQLiteMemoryStore) GetDocumentDetail(ctx context.Context, agentID, user
 
 	var detail store.DocumentDetail
 	var uid *string
-	var createdAt, updatedAt time.Time
+	var createdAt, updatedAt sqliteTime
 	err := s.db.QueryRowContext(ctx, q, args...).Scan(
 		&detail.Path, &detail.Content, &detail.Hash, &uid,
-		&createdAt, &updatedAt, &detail.ChunkCount,
+		&createdAt, &updatedAt, &detail.ChunkCount, &detail.EmbeddedCount,
 	)
 	if err != nil {
 		return nil, err
@@ -344,9 +356,8 @@ func (s *SQLiteMemoryStore) GetDocumentDetail(ctx context.Context, agentID, user
 	if uid != nil {
 		detail.UserID = *uid
 	}
-	detail.CreatedAt = createdAt.UnixMilli()
-	detail.UpdatedAt = updatedAt.UnixMilli()
-	// EmbeddedCount always 0 — no embedding column in SQLite
+	detail.CreatedAt = createdAt.Time.UnixMilli()
+	detail.UpdatedAt = updatedAt.Time.UnixMilli()
 	return &detail, nil
 }
 
@@ -360,7 +371,7 @@ func (s *SQLiteMemoryStore) ListChunks(ctx context.Context, agentID, userID, pat
 		if err != nil {
 			return nil, err
 		}
-		q = `SELECT c.id, c.start_line, c.end_line, c.text
+		q = `SELECT c.id, c.start_line, c.end_line, c.text, c.embedding IS NOT NULL
 			 FROM memory_chunks c
 			 JOIN memory_documents d ON c.document_id = d.id
 			 WHERE d.agent_id = ? AND d.path = ? AND d.user_id IS NULL` + tc + `
@@ -371,7 +382,7 @@ func (s *SQLiteMemoryStore) ListChunks(ctx context.Context, agentID, userID, pat
 		if err != nil {
 			return nil, err
 		}
-		q = `SELECT c.id, c.start_line, c.end_line, c.text
+		q = `SELECT c.id, c.start_line, c.end_line, c.text, c.embedding IS NOT NULL
 			 FROM memory_chunks c
 			 JOIN memory_documents d ON c.document_id = d.id
 			 WHERE d.agent_id = ? AND d.path = ? AND d.user_id = ?` + tc + `
@@ -388,10 +399,9 @@ func (s *SQLiteMemoryStore) ListChunks(ctx context.Context, agentID, userID, pat
 	var result []store.ChunkInfo
 	for rows.Next() {
 		var ci store.ChunkInfo
-		if err := rows.Scan(&ci.ID, &ci.StartLine, &ci.EndLine, &ci.TextPreview); err != nil {
+		if err := rows.Scan(&ci.ID, &ci.StartLine, &ci.EndLine, &ci.TextPreview, &ci.HasEmbedding); err != nil {
 			continue
 		}
-		// HasEmbedding always false — no embedding column in SQLite
 		result = append(result, ci)
 	}
 	if err := rows.Err(); err != nil {
@@ -399,3 +409,83 @@ func (s *SQLiteMemoryStore) ListChunks(ctx context.Context, agentID, userID, pat
 	}
 	return result, nil
 }
+
+// BackfillEmbeddings finds all chunks without embeddings and generates them.
+// Processes in batches to avoid memory spikes. Safe to call multiple times.
+func (s *SQLiteMemoryStore) BackfillEmbeddings(ctx context.Context) (int, error) {
+	provider := s.embeddingProvider()
+	if provider == nil {
+		return 0, fmt.Errorf("no embedding provider configured")
+	}
+
+	const batchSize = 50
+	total := 0
+	defer func() {
+		if total > 0 {
+			s.vectors.invalidateAll()
+		}
+	}()
+
+	for {
+		rows, err := s.db.QueryContext(ctx,
+			"SELECT id, text FROM memory_chunks WHERE embedding IS NULL ORDER BY id ASC LIMIT ?", batchSize)
+		if err != nil {
+			return total, fmt.Errorf("query chunks without embeddings: %w", err)
+		}
+
+		type chunkRow struct {
+			ID   string
+			Text string
+		}
+		var chunks []chunkRow
+		for rows.Next() {
+			var c chunkRow
+			if err := rows.Scan(&c.ID, &c.Text); err != nil {
+				continue
+			}
+			chunks = append(chunks, c)
+		}
+		rows.Close()
+
+		if len(chunks) == 0 {
+			break
+		}
+
+		texts := make([]string, len(chunks))
+		for i, c := range chunks {
+			texts[i] = c.Text
+		}
+
+		embeddings, err := provider.Embed(ctx, texts)
+		if err != nil {
+			return total, fmt.Errorf("generate embeddings: %w", err)
+		}
+
+		updated := 0
+		for i, chunk := range chunks {
+			if i >= len(embeddings) {
+				break
+			}
+			blob := encodeEmbedding(embeddings[i])
+			if blob == nil {
+				continue
+			}
+			if _, err := s.db.ExecContext(ctx,
+				"UPDATE memory_chunks SET embedding = ? WHERE id = ?",
+				blob, chunk.ID,
+			); err != nil {
+				return total, fmt.Errorf("update chunk embedding id=%s: %w", chunk.ID, err)
+			}
+			updated++
+		}
+		total += updated
+
+		// Chunks the provider returned nothing usable for stay NULL; stop
+		// rather than fetching the same batch again.
+		if updated == 0 || len(chunks) < batchSize {
+			break
+		}
+	}
+
+	return total, nil
+}
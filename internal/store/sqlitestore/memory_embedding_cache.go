@@ -0,0 +1,141 @@
+//go:build sqlite || sqliteonly
+
+package sqlitestore
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/nextlevelbuilder/goclaw/internal/memory"
+	"github.com/nextlevelbuilder/goclaw/internal/store"
+)
+
+// embeddingCacheEntry holds data for a single cache row.
+type embeddingCacheEntry struct {
+	Hash      string
+	Embedding []byte // encoded with encodeEmbedding
+	Dims      int
+}
+
+// lookupEmbeddingCache fetches cached (encoded) embeddings for the given
+// content hashes. Missing hashes are simply absent from the map.
+func (s *SQLiteMemoryStore) lookupEmbeddingCache(ctx context.Context, hashes []string, provider, model string) (map[string][]byte, error) {
+	if len(hashes) == 0 {
+		return nil, nil
+	}
+	result := make(map[string][]byte, len(hashes))
+	// Batches stay well under SQLite's bound-parameter limit.
+	const batchSize = 500
+	for start := 0; start < len(hashes); start += batchSize {
+		batch := hashes[start:min(start+batchSize, len(hashes))]
+		args := make([]any, 0, len(batch)+2)
+		for _, h := range batch {
+			args = append(args, h)
+		}
+		args = append(args, provider, model)
+		placeholders := strings.TrimSuffix(strings.Repeat("?,", len(batch)), ",")
+
+		rows, err := s.db.QueryContext(ctx,
+			"SELECT hash, embedding FROM embedding_cache WHERE hash IN ("+placeholders+") AND provider = ? AND model = ? AND embedding IS NOT NULL",
+			args...)
+		if err != nil {
+			return nil, fmt.Errorf("lookup embedding cache: %w", err)
+		}
+		for rows.Next() {
+			var hash string
+			var blob []byte
+			if err := rows.Scan(&hash, &blob); err != nil {
+				slog.Warn("embedding cache scan error", "error", err)
+				continue
+			}
+			result[hash] = blob
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// writeEmbeddingCache upserts embedding cache entries.
+func (s *SQLiteMemoryStore) writeEmbeddingCache(ctx context.Context, entries []embeddingCacheEntry, provider, model string) error {
+	now := time.Now().UTC()
+	tid := tenantIDForInsert(ctx).String()
+	for _, e := range entries {
+		if _, err := s.db.ExecContext(ctx,
+			`INSERT INTO embedding_cache (hash, provider, model, embedding, dims, created_at, updated_at, tenant_id)
+			 VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+			 ON CONFLICT (hash, provider, model) DO UPDATE SET embedding = excluded.embedding, dims = excluded.dims, updated_at = excluded.updated_at`,
+			e.Hash, provider, model, e.Embedding, e.Dims, now, now, tid,
+		); err != nil {
+			return fmt.Errorf("write embedding cache: %w", err)
+		}
+	}
+	return nil
+}
+
+// embedChunks returns encoded embeddings aligned with chunks, reusing cached
+// ones and embedding the rest in one provider call. Entries are nil where no
+// embedding could be produced; on provider failure the chunks are stored
+// without vectors and picked up later by BackfillEmbeddings.
+func (s *SQLiteMemoryStore) embedChunks(ctx context.Context, provider store.EmbeddingProvider, path string, chunks []memory.TextChunk) [][]byte {
+	providerName, providerModel := provider.Name(), provider.Model()
+	hashes := make([]string, len(chunks))
+	for i, c := range chunks {
+		hashes[i] = memory.ContentHash(c.Text)
+	}
+
+	cached, err := s.lookupEmbeddingCache(ctx, hashes, providerName, providerModel)
+	if err != nil {
+		slog.Warn("embedding cache lookup failed, falling back to full API call", "path", path, "error", err)
+		cached = nil
+	}
+
+	out := make([][]byte, len(chunks))
+	var uncachedIdxs []int
+	var uncachedTexts []string
+	for i, c := range chunks {
+		if blob, ok := cached[hashes[i]]; ok {
+			out[i] = blob
+			continue
+		}
+		uncachedIdxs = append(uncachedIdxs, i)
+		uncachedTexts = append(uncachedTexts, c.Text)
+	}
+	if len(cached) > 0 {
+		slog.Debug("embedding cache hit", "path", path, "cached", len(cached), "uncached", len(uncachedTexts))
+	}
+	if len(uncachedTexts) == 0 {
+		return out
+	}
+
+	fresh, err := provider.Embed(ctx, uncachedTexts)
+	if err != nil {
+		slog.Warn("memory embedding failed, storing chunks without vectors", "path", path, "chunks", len(chunks), "error", err)
+		return out
+	}
+	if len(fresh) != len(uncachedTexts) {
+		slog.Warn("embedding API returned mismatched count", "expected", len(uncachedTexts), "got", len(fresh))
+	}
+	var entries []embeddingCacheEntry
+	for j, emb := range fresh {
+		if j >= len(uncachedIdxs) {
+			break
+		}
+		blob := encodeEmbedding(emb)
+		if blob == nil {
+			continue
+		}
+		idx := uncachedIdxs[j]
+		out[idx] = blob
+		entries = append(entries, embeddingCacheEntry{Hash: hashes[idx], Embedding: blob, Dims: len(emb)})
+	}
+	if err := s.writeEmbeddingCache(ctx, entries, providerName, providerModel); err != nil {
+		slog.Warn("embedding cache write failed", "path", path, "error", err)
+	}
+	return out
+}
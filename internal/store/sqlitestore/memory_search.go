@@ -4,28 +4,59 @@ package sqlitestore
 
 import (
 	"context"
+	"database/sql"
+	"log/slog"
+	"sort"
 	"strings"
 
 	"github.com/nextlevelbuilder/goclaw/internal/store"
 )
 
-// Search performs LIKE-based text search over memory_chunks.
-// Vector search is not available in the SQLite edition — VectorSearch is always false.
-// Merges global (user_id IS NULL) + per-user chunks, with user boost.
+// Search performs hybrid search (FTS5 + vector) over memory_chunks.
+// Merges global (user_id IS NULL) + per-user chunks, with user boost —
+// the same scoring as the PG store.
 func (s *SQLiteMemoryStore) Search(ctx context.Context, query string, agentID, userID string, opts store.MemorySearchOptions) ([]store.MemorySearchResult, error) {
 	maxResults := opts.MaxResults
 	if maxResults <= 0 {
 		maxResults = s.cfg.MaxResults
 	}
 
-	results, err := s.likeSearch(ctx, query, agentID, userID, maxResults*2)
+	textResults, err := s.textSearch(ctx, query, agentID, userID, maxResults*2)
 	if err != nil {
 		return nil, err
 	}
 
+	// Vector search if provider available
+	var vecResults []scoredChunk
+	if provider := s.embeddingProvider(); provider != nil {
+		embeddings, err := provider.Embed(ctx, []string{query})
+		if err == nil && len(embeddings) > 0 {
+			vecResults, err = s.vectorSearch(ctx, embeddings[0], agentID, userID, maxResults*2)
+			if err != nil {
+				slog.Warn("memory: vector search failed", "error", err)
+				vecResults = nil
+			}
+		}
+	}
+
+	// Merge results — use per-query overrides if set, else store defaults
+	textW, vecW := s.cfg.TextWeight, s.cfg.VectorWeight
+	if opts.TextWeight > 0 {
+		textW = opts.TextWeight
+	}
+	if opts.VectorWeight > 0 {
+		vecW = opts.VectorWeight
+	}
+	if len(textResults) == 0 && len(vecResults) > 0 {
+		textW, vecW = 0, 1.0
+	} else if len(vecResults) == 0 && len(textResults) > 0 {
+		textW, vecW = 1.0, 0
+	}
+	merged := hybridMerge(textResults, vecResults, textW, vecW)
+
 	// Apply filters and cap results
 	var filtered []store.MemorySearchResult
-	for _, r := range results {
+	for _, r := range merged {
 		if opts.MinScore > 0 && r.Score < opts.MinScore {
 			continue
 		}
@@ -40,75 +71,244 @@ func (s *SQLiteMemoryStore) Search(ctx context.Context, query string, agentID, u
 	return filtered, nil
 }
 
+// scoredChunk is a chunk hit from one search leg, before merging.
+type scoredChunk struct {
+	Path      string
+	StartLine int
+	EndLine   int
+	Text      string
+	Score     float64
+	UserID    *string
+	UpdatedAt nullSqliteTime
+}
+
+// chunkUpdatedAtExpr selects when a chunk's content last changed: its
+// document's updated_at, or the chunk's own for chunks without a document.
+const chunkUpdatedAtExpr = `COALESCE((SELECT d.updated_at FROM memory_documents d WHERE d.id = memory_chunks.document_id), memory_chunks.updated_at)`
+
+// chunkColumns are the columns scanChunks expects, in order, before score.
+const chunkColumns = `memory_chunks.path, memory_chunks.start_line, memory_chunks.end_line, memory_chunks.text, memory_chunks.user_id, ` + chunkUpdatedAtExpr
+
+// userFilter restricts chunks to global ones, plus the user's own if set.
+func userFilter(userID string) (string, []any) {
+	if userID == "" {
+		return " AND memory_chunks.user_id IS NULL", nil
+	}
+	return " AND (memory_chunks.user_id IS NULL OR memory_chunks.user_id = ?)", []any{userID}
+}
+
+// textSearch ranks chunks with FTS5 BM25, mapped into 0..1. Queries FTS5
+// cannot tokenize into a match (e.g. CJK text without spaces, which FTS5
+// indexes as whole runs) fall back to a substring LIKE search.
+func (s *SQLiteMemoryStore) textSearch(ctx context.Context, query, agentID, userID string, limit int) ([]scoredChunk, error) {
+	results, err := s.ftsSearch(ctx, query, agentID, userID, limit)
+	if err != nil {
+		slog.Debug("memory: FTS search failed, using LIKE", "error", err)
+	} else if len(results) > 0 {
+		return results, nil
+	}
+	return s.likeSearch(ctx, query, agentID, userID, limit)
+}
+
+func (s *SQLiteMemoryStore) ftsSearch(ctx context.Context, query, agentID, userID string, limit int) ([]scoredChunk, error) {
+	match := ftsMatchQuery(query)
+	if match == "" {
+		return nil, nil
+	}
+	tc, tcArgs, err := scopeClauseAlias(ctx, "memory_chunks")
+	if err != nil {
+		return nil, err
+	}
+	uf, ufArgs := userFilter(userID)
+	args := append([]any{match, agentID}, ufArgs...)
+	args = append(args, tcArgs...)
+	args = append(args, limit)
+
+	// bm25() is negative, more negative = better; -bm25/(1-bm25) maps it
+	// onto 0..1 so it combines with cosine scores like PG's ts_rank does.
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT `+chunkColumns+`, -bm25(memory_chunks_fts) AS rank
+		 FROM memory_chunks_fts
+		 JOIN memory_chunks ON memory_chunks.id = memory_chunks_fts.chunk_id
+		 WHERE memory_chunks_fts MATCH ? AND memory_chunks.agent_id = ?`+uf+tc+`
+		 ORDER BY rank DESC LIMIT ?`, args...)
+	if err != nil {
+		return nil, err
+	}
+	results, err := scanChunks(rows)
+	for i := range results {
+		results[i].Score = max(results[i].Score, 0) / (1 + max(results[i].Score, 0))
+	}
+	return results, err
+}
+
 // likeSearch performs a case-insensitive LIKE search across chunk text.
-// Returns results scored 1.0 (global) or 1.2 (personal, boosted).
-func (s *SQLiteMemoryStore) likeSearch(ctx context.Context, query, agentID, userID string, limit int) ([]store.MemorySearchResult, error) {
+// Every hit scores 1.0 (the personal boost is applied when merging).
+func (s *SQLiteMemoryStore) likeSearch(ctx context.Context, query, agentID, userID string, limit int) ([]scoredChunk, error) {
 	pattern := "%" + escapeLike(query) + "%"
+	tc, tcArgs, err := scopeClause(ctx)
+	if err != nil {
+		return nil, err
+	}
+	uf, ufArgs := userFilter(userID)
+	args := append([]any{agentID}, ufArgs...)
+	args = append(args, pattern)
+	args = append(args, tcArgs...)
+	args = append(args, limit)
 
-	var q string
-	var args []any
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT `+chunkColumns+`, 1.0
+		 FROM memory_chunks
+		 WHERE agent_id = ?`+uf+`
+		 AND text LIKE ? ESCAPE '\'`+tc+`
+		 ORDER BY user_id DESC
+		 LIMIT ?`, args...)
+	if err != nil {
+		return nil, err
+	}
+	return scanChunks(rows)
+}
 
-	if userID != "" {
-		tc, tcArgs, err := scopeClause(ctx)
-		if err != nil {
-			return nil, err
+// vectorSearchBatch is how many ranked chunk IDs are resolved per query
+// when walking the ranking to fill limit in-scope results.
+const vectorSearchBatch = 64
+
+// vectorSearch ranks the agent's chunk embeddings against the query with
+// the in-process index, then resolves the best-ranked IDs to rows that are
+// in scope (user, tenant) and still exist.
+func (s *SQLiteMemoryStore) vectorSearch(ctx context.Context, embedding []float32, agentID, userID string, limit int) ([]scoredChunk, error) {
+	idx, err := s.vectors.get(ctx, s.db, agentID)
+	if err != nil {
+		return nil, err
+	}
+	hits := idx.rank(embedding)
+	if len(hits) == 0 {
+		return nil, nil
+	}
+	tc, tcArgs, err := scopeClause(ctx)
+	if err != nil {
+		return nil, err
+	}
+	uf, ufArgs := userFilter(userID)
+
+	var results []scoredChunk
+	for start := 0; start < len(hits) && len(results) < limit; start += vectorSearchBatch {
+		batch := hits[start:min(start+vectorSearchBatch, len(hits))]
+		scores := make(map[string]float64, len(batch))
+		args := make([]any, 0, len(batch)+len(ufArgs)+len(tcArgs)+1)
+		for _, h := range batch {
+			scores[h.id] = h.score
+			args = append(args, h.id)
 		}
-		q = `SELECT path, start_line, end_line, text, user_id
+		args = append(args, agentID)
+		args = append(args, ufArgs...)
+		args = append(args, tcArgs...)
+
+		placeholders := strings.TrimSuffix(strings.Repeat("?,", len(batch)), ",")
+		rows, err := s.db.QueryContext(ctx,
+			`SELECT memory_chunks.id, `+chunkColumns+`
 			 FROM memory_chunks
-			 WHERE agent_id = ? AND (user_id IS NULL OR user_id = ?)
-			 AND text LIKE ? ESCAPE '\'` + tc + `
-			 ORDER BY user_id DESC
-			 LIMIT ?`
-		args = append([]any{agentID, userID, pattern}, tcArgs...)
-		args = append(args, limit)
-	} else {
-		tc, tcArgs, err := scopeClause(ctx)
+			 WHERE id IN (`+placeholders+`) AND agent_id = ?`+uf+tc, args...)
 		if err != nil {
 			return nil, err
 		}
-		q = `SELECT path, start_line, end_line, text, user_id
-			 FROM memory_chunks
-			 WHERE agent_id = ? AND user_id IS NULL
-			 AND text LIKE ? ESCAPE '\'` + tc + `
-			 LIMIT ?`
-		args = append([]any{agentID, pattern}, tcArgs...)
-		args = append(args, limit)
+		var found []scoredChunk
+		for rows.Next() {
+			var id string
+			var r scoredChunk
+			if err := rows.Scan(&id, &r.Path, &r.StartLine, &r.EndLine, &r.Text, &r.UserID, &r.UpdatedAt); err != nil {
+				continue
+			}
+			r.Score = scores[id]
+			found = append(found, r)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return nil, err
+		}
+		sort.Slice(found, func(i, j int) bool { return found[i].Score > found[j].Score })
+		results = append(results, found...)
 	}
-
-	rows, err := s.db.QueryContext(ctx, q, args...)
-	if err != nil {
-		return nil, err
+	if len(results) > limit {
+		results = results[:limit]
 	}
-	defer rows.Close()
+	return results, nil
+}
 
-	var results []store.MemorySearchResult
+func scanChunks(rows *sql.Rows) ([]scoredChunk, error) {
+	defer rows.Close()
+	var results []scoredChunk
 	for rows.Next() {
-		var path, text string
-		var startLine, endLine int
-		var uid *string
-		if err := rows.Scan(&path, &startLine, &endLine, &text, &uid); err != nil {
+		var r scoredChunk
+		if err := rows.Scan(&r.Path, &r.StartLine, &r.EndLine, &r.Text, &r.UserID, &r.UpdatedAt, &r.Score); err != nil {
 			continue
 		}
+		results = append(results, r)
+	}
+	return results, rows.Err()
+}
+
+// hybridMerge combines text and vector results with weighted scoring.
+// Per-user results get a 1.2x boost. Deduplication: user copy wins over global.
+// Mirrors the PG store's merge so both editions rank alike.
+func hybridMerge(text, vec []scoredChunk, textWeight, vectorWeight float64) []store.MemorySearchResult {
+	type key struct {
+		Path      string
+		StartLine int
+	}
+	seen := make(map[key]*store.MemorySearchResult)
+	var order []key
+
+	addResult := func(r scoredChunk, weight float64) {
+		k := key{r.Path, r.StartLine}
 		scope := "global"
-		score := 1.0
-		if uid != nil && *uid != "" {
+		boost := 1.0
+		if r.UserID != nil && *r.UserID != "" {
 			scope = "personal"
-			score = 1.2 // personal boost, mirrors PG hybrid merge
+			boost = 1.2
+		}
+		score := r.Score * weight * boost
+		var updatedAt int64
+		if r.UpdatedAt.Valid {
+			updatedAt = r.UpdatedAt.Time.UnixMilli()
 		}
-		results = append(results, store.MemorySearchResult{
-			Path:      path,
-			StartLine: startLine,
-			EndLine:   endLine,
+
+		if existing, ok := seen[k]; ok {
+			existing.Score += score
+			// User copy wins
+			if scope == "personal" {
+				existing.Scope = "personal"
+				existing.Snippet = r.Text
+				existing.UpdatedAt = updatedAt
+			}
+			return
+		}
+		seen[k] = &store.MemorySearchResult{
+			Path:      r.Path,
+			StartLine: r.StartLine,
+			EndLine:   r.EndLine,
 			Score:     score,
-			Snippet:   text,
+			Snippet:   r.Text,
 			Source:    "memory",
 			Scope:     scope,
-		})
+			UpdatedAt: updatedAt,
+		}
+		order = append(order, k)
 	}
-	if err := rows.Err(); err != nil {
-		return nil, err
+
+	for _, r := range text {
+		addResult(r, textWeight)
 	}
-	return results, nil
+	for _, r := range vec {
+		addResult(r, vectorWeight)
+	}
+
+	results := make([]store.MemorySearchResult, 0, len(order))
+	for _, k := range order {
+		results = append(results, *seen[k])
+	}
+	sort.SliceStable(results, func(i, j int) bool { return results[i].Score > results[j].Score })
+	return results
 }
 
 // escapeLike escapes special LIKE metacharacters: % _ \
@@ -0,0 +1,207 @@
+//go:build sqlite || sqliteonly
+
+package sqlitestore
+
+import (
+	"context"
+	"math"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/nextlevelbuilder/goclaw/internal/store"
+)
+
+// conceptEmbedder maps words onto a few concept axes so that synonyms
+// embed close together without sharing any text.
+type conceptEmbedder struct {
+	calls int
+}
+
+var conceptAxes = map[string]int{
+	"car": 0, "sedan": 0, "automobile": 0,
+	"bike": 1, "cycling": 1, "bicycle": 1,
+	"cook": 2, "recipe": 2, "dinner": 2,
+}
+
+func (e *conceptEmbedder) Name() string  { return "concept" }
+func (e *conceptEmbedder) Model() string { return "concept-3" }
+func (e *conceptEmbedder) Embed(_ context.Context, texts []string) ([][]float32, error) {
+	e.calls++
+	out := make([][]float32, len(texts))
+	for i, text := range texts {
+		v := []float32{0.01, 0.01, 0.01, 0.01}
+		for _, w := range strings.Fields(strings.ToLower(text)) {
+			if axis, ok := conceptAxes[strings.Trim(w, ".,")]; ok {
+				v[axis]++
+			}
+		}
+		out[i] = v
+	}
+	return out, nil
+}
+
+func newTestSQLiteMemoryStore(t *testing.T) (context.Context, *SQLiteMemoryStore, string) {
+	t.Helper()
+
+	db, err := OpenDB(filepath.Join(t.TempDir(), "memory.db"))
+	if err != nil {
+		t.Fatalf("OpenDB error: %v", err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+	if err := EnsureSchema(db); err != nil {
+		t.Fatalf("EnsureSchema error: %v", err)
+	}
+
+	agentID := uuid.Must(uuid.NewV7()).String()
+	if _, err := db.Exec(
+		`INSERT INTO agents (id, agent_key, owner_id, model, tenant_id) VALUES (?, 'mem', 'owner', 'm', ?)`,
+		agentID, store.MasterTenantID.String(),
+	); err != nil {
+		t.Fatalf("insert agent: %v", err)
+	}
+	return store.WithTenantID(context.Background(), store.MasterTenantID), NewSQLiteMemoryStore(db), agentID
+}
+
+func putAndIndex(t *testing.T, ctx context.Context, s *SQLiteMemoryStore, agentID, path, content string) {
+	t.Helper()
+	if err := s.PutDocument(ctx, agentID, "", path, content); err != nil {
+		t.Fatalf("PutDocument %s: %v", path, err)
+	}
+	if err := s.IndexDocument(ctx, agentID, "", path); err != nil {
+		t.Fatalf("IndexDocument %s: %v", path, err)
+	}
+}
+
+func TestEncodeEmbedding_RoundTrip(t *testing.T) {
+	v := []float32{0.3, -1.2, 0.05, 2.5, 0}
+	scale, q, ok := decodeEmbedding(encodeEmbedding(v))
+	if !ok || len(q) != len(v) {
+		t.Fatalf("decode failed: ok=%v len=%d", ok, len(q))
+	}
+	var norm, dot float64
+	for i, f := range v {
+		norm += float64(f) * float64(f)
+		dot += float64(f) * float64(q[i]) * float64(scale)
+	}
+	if cos := dot / math.Sqrt(norm); cos < 0.999 {
+		t.Errorf("cosine with original = %v, want ~1", cos)
+	}
+	if encodeEmbedding([]float32{0, 0}) != nil {
+		t.Error("zero vector encoded")
+	}
+}
+
+func TestSQLiteMemoryStore_FTSSearch(t *testing.T) {
+	ctx, s, agentID := newTestSQLiteMemoryStore(t)
+	putAndIndex(t, ctx, s, agentID, "bike.md", "Cycling to work on my bike every morning.")
+	putAndIndex(t, ctx, s, agentID, "food.md", "Dinner recipe: slow cooked beans.")
+
+	results, err := s.Search(ctx, "bike morning", agentID, "", store.MemorySearchOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 1 || results[0].Path != "bike.md" {
+		t.Fatalf("results = %+v", results)
+	}
+	if sc := results[0].Score; sc <= 0 || sc >= 1 {
+		t.Errorf("score = %v, want BM25 mapped into (0,1)", sc)
+	}
+	if results[0].UpdatedAt == 0 {
+		t.Error("UpdatedAt not set")
+	}
+
+	// Substrings FTS5 cannot tokenize fall back to LIKE.
+	results, err = s.Search(ctx, "ooked bea", agentID, "", store.MemorySearchOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 1 || results[0].Path != "food.md" {
+		t.Errorf("LIKE fallback results = %+v", results)
+	}
+}
+
+func TestSQLiteMemoryStore_HybridSearch(t *testing.T) {
+	ctx, s, agentID := newTestSQLiteMemoryStore(t)
+	emb := &conceptEmbedder{}
+	s.SetEmbeddingProvider(emb)
+	putAndIndex(t, ctx, s, agentID, "car.md", "Bought a new sedan last week.")
+	putAndIndex(t, ctx, s, agentID, "bike.md", "Cycling to work on my bike.")
+
+	// No text overlap with "automobile": only the vector leg can match.
+	results, err := s.Search(ctx, "automobile", agentID, "", store.MemorySearchOptions{MaxResults: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 1 || results[0].Path != "car.md" || results[0].Score < 0.9 {
+		t.Fatalf("results = %+v", results)
+	}
+
+	// Re-indexing unchanged text is served from the embedding cache.
+	before := emb.calls
+	if err := s.PutDocument(ctx, agentID, "", "car.md", "Bought a new sedan last week."); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.IndexDocument(ctx, agentID, "", "car.md"); err != nil {
+		t.Fatal(err)
+	}
+	if emb.calls != before {
+		t.Errorf("embed calls = %d, want %d (cache hit)", emb.calls, before)
+	}
+
+	// Deleted documents drop out of the vector leg.
+	if err := s.DeleteDocument(ctx, agentID, "", "car.md"); err != nil {
+		t.Fatal(err)
+	}
+	results, err = s.Search(ctx, "automobile", agentID, "", store.MemorySearchOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, r := range results {
+		if r.Path == "car.md" {
+			t.Errorf("deleted document still returned: %+v", results)
+		}
+	}
+}
+
+func TestSQLiteMemoryStore_BackfillEmbeddings(t *testing.T) {
+	ctx, s, agentID := newTestSQLiteMemoryStore(t)
+	putAndIndex(t, ctx, s, agentID, "car.md", "Bought a new sedan last week.")
+	putAndIndex(t, ctx, s, agentID, "food.md", "Dinner recipe: slow cooked beans.")
+
+	s.SetEmbeddingProvider(&conceptEmbedder{})
+	n, err := s.BackfillEmbeddings(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 2 {
+		t.Errorf("backfilled %d chunks, want 2", n)
+	}
+	detail, err := s.GetDocumentDetail(ctx, agentID, "", "car.md")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if detail.EmbeddedCount != detail.ChunkCount || detail.ChunkCount == 0 {
+		t.Errorf("detail = %+v, want all chunks embedded", detail)
+	}
+	chunks, err := s.ListChunks(ctx, agentID, "", "food.md")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(chunks) == 0 || !chunks[0].HasEmbedding {
+		t.Errorf("chunks = %+v", chunks)
+	}
+
+	results, err := s.Search(ctx, "automobile", agentID, "", store.MemorySearchOptions{MaxResults: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 1 || results[0].Path != "car.md" {
+		t.Errorf("results after backfill = %+v", results)
+	}
+	if n, err := s.BackfillEmbeddings(ctx); err != nil || n != 0 {
+		t.Errorf("second backfill = %d, %v; want 0, nil", n, err)
+	}
+}
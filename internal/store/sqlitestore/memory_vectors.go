@@ -0,0 +1,189 @@
+//go:build sqlite || sqliteonly
+
+package sqlitestore
+
+import (
+	"context"
+	"database/sql"
+	"encoding/binary"
+	"log/slog"
+	"math"
+	"sort"
+	"sync"
+)
+
+// Chunk embeddings are stored as int8-quantized BLOBs: a little-endian
+// float32 scale followed by one int8 per dimension. Vectors are normalized
+// before quantization, so cosine similarity is a scaled dot product. int8
+// keeps a 1536-dim embedding at ~1.5KB and ranks close to float32.
+
+// encodeEmbedding normalizes and quantizes v. Returns nil for an empty or
+// zero vector.
+func encodeEmbedding(v []float32) []byte {
+	var norm float64
+	for _, f := range v {
+		norm += float64(f) * float64(f)
+	}
+	if len(v) == 0 || norm == 0 {
+		return nil
+	}
+	norm = math.Sqrt(norm)
+	var maxAbs float64
+	for _, f := range v {
+		maxAbs = max(maxAbs, math.Abs(float64(f)/norm))
+	}
+	scale := maxAbs / 127
+	buf := make([]byte, 4+len(v))
+	binary.LittleEndian.PutUint32(buf, math.Float32bits(float32(scale)))
+	for i, f := range v {
+		buf[4+i] = byte(int8(math.Round(float64(f) / norm / scale)))
+	}
+	return buf
+}
+
+// decodeEmbedding splits a stored BLOB into its scale and quantized values.
+func decodeEmbedding(b []byte) (float32, []int8, bool) {
+	if len(b) < 5 {
+		return 0, nil, false
+	}
+	scale := math.Float32frombits(binary.LittleEndian.Uint32(b))
+	q := make([]int8, len(b)-4)
+	for i, c := range b[4:] {
+		q[i] = int8(c)
+	}
+	return scale, q, true
+}
+
+// agentVectors is the in-memory index of one agent's chunk embeddings,
+// searched by brute force. At desktop scale (tens of thousands of chunks)
+// a full int8 scan takes a few milliseconds and needs no tuning or
+// rebuilds, unlike a graph index. Skill and team-task search build one per
+// query from the rows in scope.
+type agentVectors struct {
+	ids    []string
+	scales []float32
+	dims   int
+	data   []int8 // len(ids) * dims, row-major
+}
+
+// add appends a stored embedding. It reports false for a malformed BLOB or
+// one whose dimension differs from the first added (left over from an
+// earlier embedding model).
+func (v *agentVectors) add(id string, blob []byte) bool {
+	scale, q, ok := decodeEmbedding(blob)
+	if !ok {
+		return false
+	}
+	if v.dims == 0 {
+		v.dims = len(q)
+	}
+	if len(q) != v.dims {
+		return false
+	}
+	v.ids = append(v.ids, id)
+	v.scales = append(v.scales, scale)
+	v.data = append(v.data, q...)
+	return true
+}
+
+type vectorHit struct {
+	id    string
+	score float64
+}
+
+// rank scores every vector against query (cosine similarity) and returns
+// them best first.
+func (v *agentVectors) rank(query []float32) []vectorHit {
+	if len(query) != v.dims || len(v.ids) == 0 {
+		return nil
+	}
+	var norm float64
+	for _, f := range query {
+		norm += float64(f) * float64(f)
+	}
+	if norm == 0 {
+		return nil
+	}
+	q := make([]float32, len(query))
+	inv := float32(1 / math.Sqrt(norm))
+	for i, f := range query {
+		q[i] = f * inv
+	}
+
+	hits := make([]vectorHit, len(v.ids))
+	for i, id := range v.ids {
+		row := v.data[i*v.dims : (i+1)*v.dims]
+		var dot float32
+		for j, c := range row {
+			dot += q[j] * float32(c)
+		}
+		hits[i] = vectorHit{id: id, score: float64(dot * v.scales[i])}
+	}
+	sort.Slice(hits, func(a, b int) bool { return hits[a].score > hits[b].score })
+	return hits
+}
+
+// vectorIndex caches agentVectors per agent. Entries are loaded on first
+// search and dropped whenever this store writes embeddings for the agent.
+// Only chunk IDs and vectors are cached: scope (user, tenant) and deletions
+// are resolved by the row lookup that follows, so writes made elsewhere
+// (user merges, agent deletion) cannot leak stale chunks into results.
+type vectorIndex struct {
+	mu     sync.Mutex
+	agents map[string]*agentVectors
+}
+
+func newVectorIndex() *vectorIndex {
+	return &vectorIndex{agents: make(map[string]*agentVectors)}
+}
+
+func (x *vectorIndex) invalidate(agentID string) {
+	x.mu.Lock()
+	delete(x.agents, agentID)
+	x.mu.Unlock()
+}
+
+func (x *vectorIndex) invalidateAll() {
+	x.mu.Lock()
+	clear(x.agents)
+	x.mu.Unlock()
+}
+
+// get returns the agent's index, loading it from db if needed. Embeddings
+// whose dimension differs from the first one loaded (left over from an
+// earlier embedding model) are skipped.
+func (x *vectorIndex) get(ctx context.Context, db *sql.DB, agentID string) (*agentVectors, error) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	if v, ok := x.agents[agentID]; ok {
+		return v, nil
+	}
+
+	rows, err := db.QueryContext(ctx,
+		`SELECT id, embedding FROM memory_chunks WHERE agent_id = ? AND embedding IS NOT NULL`, agentID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	v := &agentVectors{}
+	skipped := 0
+	for rows.Next() {
+		var id string
+		var blob []byte
+		if err := rows.Scan(&id, &blob); err != nil {
+			continue
+		}
+		if !v.add(id, blob) {
+			skipped++
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if skipped > 0 {
+		slog.Warn("memory: skipped chunk embeddings with mismatched dimensions", "agent", agentID, "dims", v.dims, "skipped", skipped)
+	}
+	x.agents[agentID] = v
+	return v, nil
+}
@@ -0,0 +1,268 @@
+//go:build sqlite || sqliteonly
+
+package sqlitestore
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/nextlevelbuilder/goclaw/internal/store"
+)
+
+// SQLiteMessageQueueStore implements store.MessageQueueStore backed by SQLite.
+type SQLiteMessageQueueStore struct {
+	db *sql.DB
+}
+
+func NewSQLiteMessageQueueStore(db *sql.DB) *SQLiteMessageQueueStore {
+	return &SQLiteMessageQueueStore{db: db}
+}
+
+// queueTime formats timestamps with a fixed width so due-time comparisons
+// can be done on the TEXT column.
+func queueTime(t time.Time) string {
+	return t.UTC().Format("2006-01-02T15:04:05.000000Z")
+}
+
+const messageQueueSelectCols = `id, tenant_id, direction, channel, idempotency_key, payload, status,
+	attempts, last_error, owner, next_attempt_at, created_at, updated_at`
+
+func scanQueuedMessage(row interface{ Scan(...any) error }) (store.QueuedMessage, error) {
+	var m store.QueuedMessage
+	var payload string
+	var nextAttempt sqliteTime
+	createdAt, updatedAt := scanTimePair()
+	err := row.Scan(&m.ID, &m.TenantID, &m.Direction, &m.Channel, &m.IdempotencyKey, &payload, &m.Status,
+		&m.Attempts, &m.LastError, &m.Owner, &nextAttempt, createdAt, updatedAt)
+	m.Payload = []byte(payload)
+	m.NextAttemptAt = nextAttempt.Time
+	m.CreatedAt = createdAt.Time
+	m.UpdatedAt = updatedAt.Time
+	return m, err
+}
+
+func (s *SQLiteMessageQueueStore) Enqueue(ctx context.Context, msg *store.QueuedMessage) (bool, error) {
+	if msg.ID == uuid.Nil {
+		msg.ID = store.GenNewID()
+	}
+	if msg.TenantID == uuid.Nil {
+		msg.TenantID = store.MasterTenantID
+	}
+	now := time.Now().UTC()
+	msg.Status = store.QueueStatusQueued
+	msg.NextAttemptAt, msg.CreatedAt, msg.UpdatedAt = now, now, now
+
+	ts := queueTime(now)
+	res, err := s.db.ExecContext(ctx,
+		`INSERT INTO message_queue (id, tenant_id, direction, channel, idempotency_key, payload, status, owner, next_attempt_at, created_at, updated_at)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		 ON CONFLICT (direction, idempotency_key) WHERE idempotency_key <> '' DO NOTHING`,
+		msg.ID, msg.TenantID, msg.Direction, msg.Channel, msg.IdempotencyKey, string(msg.Payload),
+		msg.Status, msg.Owner, ts, ts, ts,
+	)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+func (s *SQLiteMessageQueueStore) ClaimDue(ctx context.Context, owner string, limit int) ([]store.QueuedMessage, error) {
+	now := queueTime(time.Now())
+	rows, err := s.db.QueryContext(ctx,
+		`UPDATE message_queue SET status = ?, owner = ?, updated_at = ?
+		 WHERE id IN (
+		     SELECT id FROM message_queue
+		     WHERE status = ? AND next_attempt_at <= ?
+		     ORDER BY next_attempt_at
+		     LIMIT ?
+		 )
+		 RETURNING `+messageQueueSelectCols,
+		store.QueueStatusQueued, owner, now, store.QueueStatusPending, now, limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []store.QueuedMessage
+	for rows.Next() {
+		m, err := scanQueuedMessage(rows)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, m)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	// RETURNING order is unspecified.
+	sort.Slice(result, func(i, j int) bool { return result[i].CreatedAt.Before(result[j].CreatedAt) })
+	return result, nil
+}
+
+func (s *SQLiteMessageQueueStore) Complete(ctx context.Context, id uuid.UUID) error {
+	_, err := s.db.ExecContext(ctx,
+		`UPDATE message_queue SET status = ?, updated_at = ? WHERE id = ?`,
+		store.QueueStatusDone, queueTime(time.Now()), id,
+	)
+	return err
+}
+
+func (s *SQLiteMessageQueueStore) Release(ctx context.Context, id uuid.UUID, at time.Time) error {
+	_, err := s.db.ExecContext(ctx,
+		`UPDATE message_queue SET status = ?, next_attempt_at = ?, updated_at = ?
+		 WHERE id = ? AND status = ?`,
+		store.QueueStatusPending, queueTime(at), queueTime(time.Now()), id, store.QueueStatusQueued,
+	)
+	return err
+}
+
+func (s *SQLiteMessageQueueStore) Fail(ctx context.Context, id uuid.UUID, lastErr string, retryAt time.Time, maxAttempts int) (string, error) {
+	var status string
+	err := s.db.QueryRowContext(ctx,
+		`UPDATE message_queue SET
+		     attempts = attempts + 1,
+		     last_error = ?,
+		     next_attempt_at = ?,
+		     status = CASE WHEN attempts + 1 >= ? THEN ? ELSE ? END,
+		     updated_at = ?
+		 WHERE id = ?
+		 RETURNING status`,
+		lastErr, queueTime(retryAt), maxAttempts, store.QueueStatusDead, store.QueueStatusPending,
+		queueTime(time.Now()), id,
+	).Scan(&status)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", fmt.Errorf("queued message %s not found", id)
+	}
+	return status, err
+}
+
+func (s *SQLiteMessageQueueStore) RecoverOwned(ctx context.Context, owner string, maxAttempts int) (int64, error) {
+	now := queueTime(time.Now())
+	res, err := s.db.ExecContext(ctx,
+		`UPDATE message_queue SET
+		     attempts = attempts + 1,
+		     last_error = CASE WHEN attempts + 1 >= ? THEN 'interrupted by restart' ELSE last_error END,
+		     status = CASE WHEN attempts + 1 >= ? THEN ? ELSE ? END,
+		     next_attempt_at = ?,
+		     updated_at = ?
+		 WHERE owner = ? AND status = ?`,
+		maxAttempts, maxAttempts, store.QueueStatusDead, store.QueueStatusPending, now, now,
+		owner, store.QueueStatusQueued,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+func (s *SQLiteMessageQueueStore) QueuedOwners(ctx context.Context) ([]string, error) {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT DISTINCT owner FROM message_queue WHERE status = ?`, store.QueueStatusQueued)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var owners []string
+	for rows.Next() {
+		var owner string
+		if err := rows.Scan(&owner); err != nil {
+			return nil, err
+		}
+		owners = append(owners, owner)
+	}
+	return owners, rows.Err()
+}
+
+func (s *SQLiteMessageQueueStore) PruneDone(ctx context.Context, cutoff time.Time) (int64, error) {
+	res, err := s.db.ExecContext(ctx,
+		`DELETE FROM message_queue WHERE status = ? AND updated_at < ?`,
+		store.QueueStatusDone, queueTime(cutoff),
+	)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+func (s *SQLiteMessageQueueStore) ListDead(ctx context.Context, direction string, limit, offset int) ([]store.QueuedMessage, int, error) {
+	tid, err := requireTenantID(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+	where := `WHERE tenant_id = ? AND status = ?`
+	args := []any{tid, store.QueueStatusDead}
+	if direction != "" {
+		where += ` AND direction = ?`
+		args = append(args, direction)
+	}
+
+	var total int
+	if err := s.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM message_queue `+where, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT `+messageQueueSelectCols+` FROM message_queue `+where+` ORDER BY updated_at DESC LIMIT ? OFFSET ?`,
+		append(args, limit, offset)...,
+	)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var result []store.QueuedMessage
+	for rows.Next() {
+		m, err := scanQueuedMessage(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		result = append(result, m)
+	}
+	return result, total, rows.Err()
+}
+
+func (s *SQLiteMessageQueueStore) Redrive(ctx context.Context, id uuid.UUID) error {
+	tid, err := requireTenantID(ctx)
+	if err != nil {
+		return err
+	}
+	now := queueTime(time.Now())
+	res, err := s.db.ExecContext(ctx,
+		`UPDATE message_queue SET status = ?, attempts = 0, next_attempt_at = ?, updated_at = ?
+		 WHERE id = ? AND tenant_id = ? AND status = ?`,
+		store.QueueStatusPending, now, now, id, tid, store.QueueStatusDead,
+	)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func (s *SQLiteMessageQueueStore) Delete(ctx context.Context, id uuid.UUID) error {
+	tid, err := requireTenantID(ctx)
+	if err != nil {
+		return err
+	}
+	res, err := s.db.ExecContext(ctx,
+		`DELETE FROM message_queue WHERE id = ? AND tenant_id = ? AND status = ?`,
+		id, tid, store.QueueStatusDead,
+	)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
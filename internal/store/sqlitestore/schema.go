@@ -14,7 +14,7 @@ var schemaSQL string
 
 // SchemaVersion is the current SQLite schema version.
 // Bump this when adding new migration steps below.
-const SchemaVersion = 4
+const SchemaVersion = 13
 
 // migrations maps version → SQL to apply when upgrading FROM that version.
 // schema.sql always represents the LATEST full schema (for fresh DBs).
@@ -72,6 +72,253 @@ WHERE payload IS NOT NULL;`,
 CREATE INDEX IF NOT EXISTS idx_subagent_tasks_parent_status ON subagent_tasks(tenant_id, parent_agent_key, status);
 CREATE INDEX IF NOT EXISTS idx_subagent_tasks_session ON subagent_tasks(session_key);
 CREATE INDEX IF NOT EXISTS idx_subagent_tasks_created ON subagent_tasks(tenant_id, created_at);`,
+	// Version 4 → 5: KG entity FTS index + dedup candidates, per-user secure CLI credentials.
+	4: `CREATE VIRTUAL TABLE IF NOT EXISTS kg_entities_fts USING fts5(entity_id UNINDEXED, name, description);
+
+CREATE TRIGGER IF NOT EXISTS kg_entities_fts_ai AFTER INSERT ON kg_entities BEGIN
+    INSERT INTO kg_entities_fts(entity_id, name, description) VALUES (new.id, new.name, COALESCE(new.description, ''));
+END;
+CREATE TRIGGER IF NOT EXISTS kg_entities_fts_ad AFTER DELETE ON kg_entities BEGIN
+    DELETE FROM kg_entities_fts WHERE entity_id = old.id;
+END;
+CREATE TRIGGER IF NOT EXISTS kg_entities_fts_au AFTER UPDATE OF name, description ON kg_entities BEGIN
+    DELETE FROM kg_entities_fts WHERE entity_id = old.id;
+    INSERT INTO kg_entities_fts(entity_id, name, description) VALUES (new.id, new.name, COALESCE(new.description, ''));
+END;
+INSERT INTO kg_entities_fts(entity_id, name, description) SELECT id, name, COALESCE(description, '') FROM kg_entities;
+CREATE TABLE IF NOT EXISTS kg_dedup_candidates (
+    id          TEXT NOT NULL PRIMARY KEY,
+    tenant_id   TEXT REFERENCES tenants(id) ON DELETE CASCADE,
+    agent_id    TEXT NOT NULL REFERENCES agents(id) ON DELETE CASCADE,
+    user_id     VARCHAR(255) NOT NULL DEFAULT '',
+    entity_a_id TEXT NOT NULL REFERENCES kg_entities(id) ON DELETE CASCADE,
+    entity_b_id TEXT NOT NULL REFERENCES kg_entities(id) ON DELETE CASCADE,
+    similarity  REAL NOT NULL,
+    status      VARCHAR(20) NOT NULL DEFAULT 'pending',
+    created_at  TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ', 'now')),
+    UNIQUE(entity_a_id, entity_b_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_kg_dedup_agent ON kg_dedup_candidates(agent_id, status);
+CREATE TABLE IF NOT EXISTS secure_cli_user_credentials (
+    id            TEXT NOT NULL PRIMARY KEY,
+    binary_id     TEXT NOT NULL REFERENCES secure_cli_binaries(id) ON DELETE CASCADE,
+    user_id       VARCHAR(255) NOT NULL,
+    encrypted_env BLOB NOT NULL,
+    metadata      TEXT NOT NULL DEFAULT '{}',
+    tenant_id     TEXT NOT NULL REFERENCES tenants(id),
+    created_at    TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ', 'now')),
+    updated_at    TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ', 'now')),
+    UNIQUE(binary_id, user_id, tenant_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_scuc_tenant ON secure_cli_user_credentials(tenant_id);
+CREATE INDEX IF NOT EXISTS idx_scuc_binary ON secure_cli_user_credentials(binary_id);`,
+	// Version 5 → 6: durable message queue.
+	5: `CREATE TABLE IF NOT EXISTS message_queue (
+    id               TEXT PRIMARY KEY,
+    tenant_id        TEXT NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    direction        VARCHAR(10) NOT NULL,
+    channel          VARCHAR(255) NOT NULL,
+    idempotency_key  VARCHAR(500) NOT NULL DEFAULT '',
+    payload          TEXT NOT NULL,
+    status           VARCHAR(20) NOT NULL DEFAULT 'queued',
+    attempts         INTEGER NOT NULL DEFAULT 0,
+    last_error       TEXT NOT NULL DEFAULT '',
+    owner            VARCHAR(255) NOT NULL DEFAULT '',
+    next_attempt_at  TEXT NOT NULL,
+    created_at       TEXT NOT NULL,
+    updated_at       TEXT NOT NULL
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_message_queue_idempotency ON message_queue(direction, idempotency_key) WHERE idempotency_key <> '';
+CREATE INDEX IF NOT EXISTS idx_message_queue_due ON message_queue(status, next_attempt_at);
+CREATE INDEX IF NOT EXISTS idx_message_queue_owner ON message_queue(owner, status);
+CREATE INDEX IF NOT EXISTS idx_message_queue_status ON message_queue(tenant_id, status, updated_at);`,
+	// Version 6 → 7: OAuth clients and per-user tokens for remote MCP servers.
+	6: `CREATE TABLE IF NOT EXISTS mcp_oauth_clients (
+    id             TEXT NOT NULL PRIMARY KEY,
+    server_id      TEXT NOT NULL REFERENCES mcp_servers(id) ON DELETE CASCADE,
+    tenant_id      TEXT NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    client_id      TEXT NOT NULL,
+    client_secret  TEXT,
+    redirect_uri   TEXT NOT NULL,
+    created_at     TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ', 'now')),
+    updated_at     TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ', 'now')),
+    UNIQUE(server_id, tenant_id)
+);
+
+CREATE TABLE IF NOT EXISTS mcp_oauth_tokens (
+    id             TEXT NOT NULL PRIMARY KEY,
+    server_id      TEXT NOT NULL REFERENCES mcp_servers(id) ON DELETE CASCADE,
+    user_id        VARCHAR(255) NOT NULL,
+    tenant_id      TEXT NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    access_token   TEXT NOT NULL,
+    refresh_token  TEXT,
+    token_type     VARCHAR(50) NOT NULL DEFAULT 'Bearer',
+    scope          TEXT NOT NULL DEFAULT '',
+    expires_at     TEXT,
+    created_at     TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ', 'now')),
+    updated_at     TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ', 'now')),
+    UNIQUE(server_id, user_id, tenant_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_mcp_oauth_tokens_tenant ON mcp_oauth_tokens(tenant_id);`,
+	// Version 7 → 8: context file revision history and pending changes.
+	// Existing files are recorded as baseline revisions.
+	7: `CREATE TABLE IF NOT EXISTS context_file_revisions (
+    id          TEXT NOT NULL PRIMARY KEY,
+    tenant_id   TEXT NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    agent_id    TEXT NOT NULL REFERENCES agents(id) ON DELETE CASCADE,
+    user_id     VARCHAR(255) NOT NULL DEFAULT '',
+    file_name   VARCHAR(255) NOT NULL,
+    content     TEXT NOT NULL DEFAULT '',
+    author      VARCHAR(20) NOT NULL,
+    author_id   VARCHAR(255) NOT NULL DEFAULT '',
+    trace_id    TEXT,
+    created_at  TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ', 'now'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_context_file_revisions_file ON context_file_revisions(agent_id, user_id, file_name, created_at);
+CREATE INDEX IF NOT EXISTS idx_context_file_revisions_tenant ON context_file_revisions(tenant_id);
+
+CREATE TABLE IF NOT EXISTS context_file_changes (
+    id           TEXT NOT NULL PRIMARY KEY,
+    tenant_id    TEXT NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    agent_id     TEXT NOT NULL REFERENCES agents(id) ON DELETE CASCADE,
+    user_id      VARCHAR(255) NOT NULL DEFAULT '',
+    file_name    VARCHAR(255) NOT NULL,
+    content      TEXT NOT NULL DEFAULT '',
+    author       VARCHAR(20) NOT NULL,
+    author_id    VARCHAR(255) NOT NULL DEFAULT '',
+    trace_id     TEXT,
+    status       VARCHAR(20) NOT NULL DEFAULT 'pending',
+    reviewed_by  VARCHAR(255) NOT NULL DEFAULT '',
+    reviewed_at  TEXT,
+    created_at   TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ', 'now'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_context_file_changes_agent ON context_file_changes(agent_id, status, created_at);
+CREATE INDEX IF NOT EXISTS idx_context_file_changes_tenant ON context_file_changes(tenant_id);
+
+INSERT INTO context_file_revisions (id, tenant_id, agent_id, user_id, file_name, content, author, created_at)
+SELECT lower(hex(randomblob(4))||'-'||hex(randomblob(2))||'-'||hex(randomblob(2))||'-'||hex(randomblob(2))||'-'||hex(randomblob(6))), tenant_id, agent_id, '', file_name, content, 'system', COALESCE(updated_at, strftime('%Y-%m-%dT%H:%M:%fZ', 'now'))
+FROM agent_context_files;
+
+INSERT INTO context_file_revisions (id, tenant_id, agent_id, user_id, file_name, content, author, created_at)
+SELECT lower(hex(randomblob(4))||'-'||hex(randomblob(2))||'-'||hex(randomblob(2))||'-'||hex(randomblob(2))||'-'||hex(randomblob(6))), tenant_id, agent_id, user_id, file_name, content, 'system', COALESCE(updated_at, strftime('%Y-%m-%dT%H:%M:%fZ', 'now'))
+FROM user_context_files;`,
+	// Version 8 → 9: temporal KG relations (validity interval, supersession,
+	// provenance). The table-level UNIQUE becomes a partial index over current
+	// facts, which needs a table rebuild.
+	8: `CREATE TABLE kg_relations_new (
+    id               TEXT NOT NULL PRIMARY KEY,
+    agent_id         TEXT NOT NULL REFERENCES agents(id) ON DELETE CASCADE,
+    user_id          VARCHAR(255) NOT NULL DEFAULT '',
+    source_entity_id TEXT NOT NULL REFERENCES kg_entities(id) ON DELETE CASCADE,
+    relation_type    VARCHAR(200) NOT NULL,
+    target_entity_id TEXT NOT NULL REFERENCES kg_entities(id) ON DELETE CASCADE,
+    confidence       REAL NOT NULL DEFAULT 1.0,
+    properties       TEXT DEFAULT '{}',
+    team_id          TEXT REFERENCES agent_teams(id) ON DELETE SET NULL,
+    tenant_id        TEXT NOT NULL REFERENCES tenants(id),
+    valid_from       TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ', 'now')),
+    valid_to         TEXT,
+    superseded_by    TEXT,
+    provenance       TEXT NOT NULL DEFAULT '{}',
+    created_at       TEXT DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ', 'now'))
+);
+
+INSERT INTO kg_relations_new (id, agent_id, user_id, source_entity_id, relation_type, target_entity_id, confidence, properties, team_id, tenant_id, valid_from, created_at)
+SELECT id, agent_id, user_id, source_entity_id, relation_type, target_entity_id, confidence, properties, team_id, tenant_id,
+       COALESCE(strftime('%Y-%m-%dT%H:%M:%fZ', substr(created_at, 1, 19)), strftime('%Y-%m-%dT%H:%M:%fZ', 'now')), created_at
+FROM kg_relations;
+
+DROP TABLE kg_relations;
+ALTER TABLE kg_relations_new RENAME TO kg_relations;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_kg_relations_current ON kg_relations(agent_id, user_id, source_entity_id, relation_type, target_entity_id) WHERE valid_to IS NULL;
+CREATE INDEX IF NOT EXISTS idx_kg_relations_source ON kg_relations(source_entity_id, relation_type);
+CREATE INDEX IF NOT EXISTS idx_kg_relations_target ON kg_relations(target_entity_id);
+CREATE INDEX IF NOT EXISTS idx_kg_relations_team ON kg_relations(team_id) WHERE team_id IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_kg_relations_tenant ON kg_relations(tenant_id);
+CREATE INDEX IF NOT EXISTS idx_kg_relations_validity ON kg_relations(agent_id, user_id, valid_from, valid_to);`,
+	// Version 9 → 10: eval harness suites and runs.
+	9: `CREATE TABLE IF NOT EXISTS eval_suites (
+    id           TEXT NOT NULL PRIMARY KEY,
+    tenant_id    TEXT NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    agent_id     TEXT NOT NULL REFERENCES agents(id) ON DELETE CASCADE,
+    name         VARCHAR(255) NOT NULL,
+    description  TEXT NOT NULL DEFAULT '',
+    judge_model  VARCHAR(255) NOT NULL DEFAULT '',
+    cases        TEXT NOT NULL DEFAULT '[]',
+    created_by   VARCHAR(255) NOT NULL DEFAULT '',
+    created_at   TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ', 'now')),
+    updated_at   TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ', 'now')),
+    UNIQUE(agent_id, name)
+);
+
+CREATE INDEX IF NOT EXISTS idx_eval_suites_tenant ON eval_suites(tenant_id);
+
+CREATE TABLE IF NOT EXISTS eval_runs (
+    id             TEXT NOT NULL PRIMARY KEY,
+    tenant_id      TEXT NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    suite_id       TEXT NOT NULL REFERENCES eval_suites(id) ON DELETE CASCADE,
+    agent_id       TEXT NOT NULL REFERENCES agents(id) ON DELETE CASCADE,
+    suite_name     VARCHAR(255) NOT NULL,
+    status         VARCHAR(20) NOT NULL DEFAULT 'running',
+    tool_mode      VARCHAR(20) NOT NULL DEFAULT 'stub',
+    total          INTEGER NOT NULL DEFAULT 0,
+    passed         INTEGER NOT NULL DEFAULT 0,
+    failed         INTEGER NOT NULL DEFAULT 0,
+    input_tokens   INTEGER NOT NULL DEFAULT 0,
+    output_tokens  INTEGER NOT NULL DEFAULT 0,
+    cost_usd       REAL NOT NULL DEFAULT 0,
+    duration_ms    INTEGER NOT NULL DEFAULT 0,
+    error          TEXT NOT NULL DEFAULT '',
+    results        TEXT NOT NULL DEFAULT '[]',
+    created_by     VARCHAR(255) NOT NULL DEFAULT '',
+    started_at     TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ', 'now')),
+    finished_at    TEXT
+);
+
+CREATE INDEX IF NOT EXISTS idx_eval_runs_agent ON eval_runs(agent_id, started_at);
+CREATE INDEX IF NOT EXISTS idx_eval_runs_suite ON eval_runs(suite_id, started_at);
+CREATE INDEX IF NOT EXISTS idx_eval_runs_tenant ON eval_runs(tenant_id);`,
+	// Version 10 → 11: memory chunk + embedding cache vectors (int8 BLOB), chunk FTS index.
+	10: `ALTER TABLE memory_chunks ADD COLUMN embedding BLOB;
+ALTER TABLE embedding_cache ADD COLUMN embedding BLOB;
+
+CREATE VIRTUAL TABLE IF NOT EXISTS memory_chunks_fts USING fts5(chunk_id UNINDEXED, text);
+
+CREATE TRIGGER IF NOT EXISTS memory_chunks_fts_ai AFTER INSERT ON memory_chunks BEGIN
+    INSERT INTO memory_chunks_fts(chunk_id, text) VALUES (new.id, new.text);
+END;
+CREATE TRIGGER IF NOT EXISTS memory_chunks_fts_ad AFTER DELETE ON memory_chunks BEGIN
+    DELETE FROM memory_chunks_fts WHERE chunk_id = old.id;
+END;
+CREATE TRIGGER IF NOT EXISTS memory_chunks_fts_au AFTER UPDATE OF text ON memory_chunks BEGIN
+    DELETE FROM memory_chunks_fts WHERE chunk_id = old.id;
+    INSERT INTO memory_chunks_fts(chunk_id, text) VALUES (new.id, new.text);
+END;
+INSERT INTO memory_chunks_fts(chunk_id, text) SELECT id, text FROM memory_chunks;`,
+	// Version 11 → 12: pending MCP OAuth authorizations.
+	11: `CREATE TABLE IF NOT EXISTS mcp_oauth_pending (
+    state       VARCHAR(255) NOT NULL PRIMARY KEY,
+    tenant_id   TEXT NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    server_id   TEXT NOT NULL REFERENCES mcp_servers(id) ON DELETE CASCADE,
+    user_id     VARCHAR(255) NOT NULL,
+    verifier    TEXT NOT NULL,
+    channel     VARCHAR(255) NOT NULL DEFAULT '',
+    chat_id     VARCHAR(255) NOT NULL DEFAULT '',
+    expires_at  TEXT NOT NULL,
+    created_at  TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ', 'now'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_mcp_oauth_pending_expires ON mcp_oauth_pending(expires_at);`,
+	// Version 12 → 13: skill and team task vectors (int8 BLOB).
+	12: `ALTER TABLE skills ADD COLUMN embedding BLOB;
+ALTER TABLE team_tasks ADD COLUMN embedding BLOB;`,
 }
 
 // EnsureSchema creates tables if they don't exist and applies incremental migrations.
@@ -305,7 +305,8 @@ CREATE INDEX IF NOT EXISTS idx_memory_documents_tenant ON memory_documents(tenan
 
 -- ============================================================
 -- Table: memory_chunks
--- Note: embedding (vector) and tsv (tsvector) columns omitted
+-- Note: embedding is an int8-quantized BLOB (see memory_vectors.go) instead
+-- of pgvector; tsv is replaced by the memory_chunks_fts FTS5 index below
 -- ============================================================
 
 CREATE TABLE IF NOT EXISTS memory_chunks (
@@ -318,6 +319,7 @@ CREATE TABLE IF NOT EXISTS memory_chunks (
     end_line    INT NOT NULL DEFAULT 0,
     hash        VARCHAR(64) NOT NULL,
     text        TEXT NOT NULL,
+    embedding   BLOB,
     team_id     TEXT REFERENCES agent_teams(id) ON DELETE SET NULL,
     tenant_id   TEXT NOT NULL REFERENCES tenants(id),
     created_at  TEXT DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ', 'now')),
@@ -330,15 +332,31 @@ CREATE INDEX IF NOT EXISTS idx_mem_document ON memory_chunks(document_id);
 CREATE INDEX IF NOT EXISTS idx_memchunk_team ON memory_chunks(team_id) WHERE team_id IS NOT NULL;
 CREATE INDEX IF NOT EXISTS idx_memory_chunks_tenant ON memory_chunks(tenant_id);
 
+-- Full-text index over chunk text (FTS5 stands in for PG tsvector).
+-- Kept in sync with memory_chunks via triggers below.
+CREATE VIRTUAL TABLE IF NOT EXISTS memory_chunks_fts USING fts5(chunk_id UNINDEXED, text);
+
+CREATE TRIGGER IF NOT EXISTS memory_chunks_fts_ai AFTER INSERT ON memory_chunks BEGIN
+    INSERT INTO memory_chunks_fts(chunk_id, text) VALUES (new.id, new.text);
+END;
+CREATE TRIGGER IF NOT EXISTS memory_chunks_fts_ad AFTER DELETE ON memory_chunks BEGIN
+    DELETE FROM memory_chunks_fts WHERE chunk_id = old.id;
+END;
+CREATE TRIGGER IF NOT EXISTS memory_chunks_fts_au AFTER UPDATE OF text ON memory_chunks BEGIN
+    DELETE FROM memory_chunks_fts WHERE chunk_id = old.id;
+    INSERT INTO memory_chunks_fts(chunk_id, text) VALUES (new.id, new.text);
+END;
+
 -- ============================================================
 -- Table: embedding_cache
--- Note: embedding (vector) column omitted
+-- Note: embedding is an int8-quantized BLOB, like memory_chunks.embedding
 -- ============================================================
 
 CREATE TABLE IF NOT EXISTS embedding_cache (
     hash       VARCHAR(64) NOT NULL,
     provider   VARCHAR(50) NOT NULL,
     model      VARCHAR(200) NOT NULL,
+    embedding  BLOB,
     dims       INT NOT NULL DEFAULT 0,
     tenant_id  TEXT NOT NULL REFERENCES tenants(id),
     created_at TEXT DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ', 'now')),
@@ -350,7 +368,7 @@ CREATE INDEX IF NOT EXISTS idx_embedding_cache_tenant ON embedding_cache(tenant_
 
 -- ============================================================
 -- Table: skills
--- Note: embedding (vector) column omitted
+-- Note: embedding stored as an int8-quantized BLOB (see memory_vectors.go)
 -- ============================================================
 
 CREATE TABLE IF NOT EXISTS skills (
@@ -370,6 +388,7 @@ CREATE TABLE IF NOT EXISTS skills (
     is_system   BOOLEAN NOT NULL DEFAULT 0,
     deps        TEXT NOT NULL DEFAULT '{}',
     enabled     BOOLEAN NOT NULL DEFAULT 1,
+    embedding   BLOB,
     tenant_id   TEXT NOT NULL REFERENCES tenants(id),
     created_at  TEXT DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ', 'now')),
     updated_at  TEXT DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ', 'now'))
@@ -733,6 +752,53 @@ CREATE TABLE IF NOT EXISTS mcp_user_credentials (
 CREATE INDEX IF NOT EXISTS idx_mcp_user_credentials_tenant ON mcp_user_credentials(tenant_id);
 CREATE INDEX IF NOT EXISTS idx_mcp_user_credentials_server ON mcp_user_credentials(server_id);
 
+-- ============================================================
+-- Table: mcp_oauth_clients / mcp_oauth_tokens / mcp_oauth_pending (OAuth for remote MCP servers)
+-- ============================================================
+
+CREATE TABLE IF NOT EXISTS mcp_oauth_clients (
+    id             TEXT NOT NULL PRIMARY KEY,
+    server_id      TEXT NOT NULL REFERENCES mcp_servers(id) ON DELETE CASCADE,
+    tenant_id      TEXT NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    client_id      TEXT NOT NULL,
+    client_secret  TEXT,
+    redirect_uri   TEXT NOT NULL,
+    created_at     TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ', 'now')),
+    updated_at     TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ', 'now')),
+    UNIQUE(server_id, tenant_id)
+);
+
+CREATE TABLE IF NOT EXISTS mcp_oauth_tokens (
+    id             TEXT NOT NULL PRIMARY KEY,
+    server_id      TEXT NOT NULL REFERENCES mcp_servers(id) ON DELETE CASCADE,
+    user_id        VARCHAR(255) NOT NULL,
+    tenant_id      TEXT NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    access_token   TEXT NOT NULL,
+    refresh_token  TEXT,
+    token_type     VARCHAR(50) NOT NULL DEFAULT 'Bearer',
+    scope          TEXT NOT NULL DEFAULT '',
+    expires_at     TEXT,
+    created_at     TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ', 'now')),
+    updated_at     TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ', 'now')),
+    UNIQUE(server_id, user_id, tenant_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_mcp_oauth_tokens_tenant ON mcp_oauth_tokens(tenant_id);
+
+CREATE TABLE IF NOT EXISTS mcp_oauth_pending (
+    state       VARCHAR(255) NOT NULL PRIMARY KEY,
+    tenant_id   TEXT NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    server_id   TEXT NOT NULL REFERENCES mcp_servers(id) ON DELETE CASCADE,
+    user_id     VARCHAR(255) NOT NULL,
+    verifier    TEXT NOT NULL,
+    channel     VARCHAR(255) NOT NULL DEFAULT '',
+    chat_id     VARCHAR(255) NOT NULL DEFAULT '',
+    expires_at  TEXT NOT NULL,
+    created_at  TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ', 'now'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_mcp_oauth_pending_expires ON mcp_oauth_pending(expires_at);
+
 -- ============================================================
 -- Table: channel_instances
 -- ============================================================
@@ -817,7 +883,7 @@ CREATE INDEX IF NOT EXISTS idx_agent_team_members_tenant ON agent_team_members(t
 
 -- ============================================================
 -- Table: team_tasks
--- Note: tsv (tsvector) and embedding (vector) columns omitted
+-- Note: tsv (tsvector) column omitted; embedding stored as an int8 BLOB
 --       blocked_by stored as TEXT (JSON array of UUIDs)
 -- ============================================================
 
@@ -854,6 +920,7 @@ CREATE TABLE IF NOT EXISTS team_tasks (
     confidence_score     REAL,
     comment_count        INT NOT NULL DEFAULT 0,
     attachment_count     INT NOT NULL DEFAULT 0,
+    embedding            BLOB,
     tenant_id            TEXT NOT NULL REFERENCES tenants(id),
     created_at           TEXT DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ', 'now')),
     updated_at           TEXT DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ', 'now'))
@@ -982,6 +1049,21 @@ CREATE INDEX IF NOT EXISTS idx_kg_entities_type ON kg_entities(agent_id, user_id
 CREATE INDEX IF NOT EXISTS idx_kg_entities_team ON kg_entities(team_id) WHERE team_id IS NOT NULL;
 CREATE INDEX IF NOT EXISTS idx_kg_entities_tenant ON kg_entities(tenant_id);
 
+-- Full-text index over entity name/description (FTS5 stands in for PG tsvector).
+-- Kept in sync with kg_entities via triggers below.
+CREATE VIRTUAL TABLE IF NOT EXISTS kg_entities_fts USING fts5(entity_id UNINDEXED, name, description);
+
+CREATE TRIGGER IF NOT EXISTS kg_entities_fts_ai AFTER INSERT ON kg_entities BEGIN
+    INSERT INTO kg_entities_fts(entity_id, name, description) VALUES (new.id, new.name, COALESCE(new.description, ''));
+END;
+CREATE TRIGGER IF NOT EXISTS kg_entities_fts_ad AFTER DELETE ON kg_entities BEGIN
+    DELETE FROM kg_entities_fts WHERE entity_id = old.id;
+END;
+CREATE TRIGGER IF NOT EXISTS kg_entities_fts_au AFTER UPDATE OF name, description ON kg_entities BEGIN
+    DELETE FROM kg_entities_fts WHERE entity_id = old.id;
+    INSERT INTO kg_entities_fts(entity_id, name, description) VALUES (new.id, new.name, COALESCE(new.description, ''));
+END;
+
 -- ============================================================
 -- Table: kg_relations
 -- ============================================================
@@ -997,14 +1079,38 @@ CREATE TABLE IF NOT EXISTS kg_relations (
     properties       TEXT DEFAULT '{}',
     team_id          TEXT REFERENCES agent_teams(id) ON DELETE SET NULL,
     tenant_id        TEXT NOT NULL REFERENCES tenants(id),
-    created_at       TEXT DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ', 'now')),
-    UNIQUE(agent_id, user_id, source_entity_id, relation_type, target_entity_id)
+    valid_from       TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ', 'now')),
+    valid_to         TEXT,
+    superseded_by    TEXT,
+    provenance       TEXT NOT NULL DEFAULT '{}',
+    created_at       TEXT DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ', 'now'))
 );
 
+CREATE UNIQUE INDEX IF NOT EXISTS idx_kg_relations_current ON kg_relations(agent_id, user_id, source_entity_id, relation_type, target_entity_id) WHERE valid_to IS NULL;
 CREATE INDEX IF NOT EXISTS idx_kg_relations_source ON kg_relations(source_entity_id, relation_type);
 CREATE INDEX IF NOT EXISTS idx_kg_relations_target ON kg_relations(target_entity_id);
 CREATE INDEX IF NOT EXISTS idx_kg_relations_team ON kg_relations(team_id) WHERE team_id IS NOT NULL;
 CREATE INDEX IF NOT EXISTS idx_kg_relations_tenant ON kg_relations(tenant_id);
+CREATE INDEX IF NOT EXISTS idx_kg_relations_validity ON kg_relations(agent_id, user_id, valid_from, valid_to);
+
+-- ============================================================
+-- Table: kg_dedup_candidates
+-- ============================================================
+
+CREATE TABLE IF NOT EXISTS kg_dedup_candidates (
+    id          TEXT NOT NULL PRIMARY KEY,
+    tenant_id   TEXT REFERENCES tenants(id) ON DELETE CASCADE,
+    agent_id    TEXT NOT NULL REFERENCES agents(id) ON DELETE CASCADE,
+    user_id     VARCHAR(255) NOT NULL DEFAULT '',
+    entity_a_id TEXT NOT NULL REFERENCES kg_entities(id) ON DELETE CASCADE,
+    entity_b_id TEXT NOT NULL REFERENCES kg_entities(id) ON DELETE CASCADE,
+    similarity  REAL NOT NULL,
+    status      VARCHAR(20) NOT NULL DEFAULT 'pending',
+    created_at  TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ', 'now')),
+    UNIQUE(entity_a_id, entity_b_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_kg_dedup_agent ON kg_dedup_candidates(agent_id, status);
 
 -- ============================================================
 -- Table: channel_pending_messages
@@ -1187,6 +1293,25 @@ CREATE INDEX IF NOT EXISTS idx_secure_cli_agent_id ON secure_cli_binaries(agent_
 CREATE UNIQUE INDEX IF NOT EXISTS idx_secure_cli_unique_binary_agent ON secure_cli_binaries(binary_name, COALESCE(agent_id, ''));
 CREATE INDEX IF NOT EXISTS idx_secure_cli_binaries_tenant ON secure_cli_binaries(tenant_id);
 
+-- ============================================================
+-- Table: secure_cli_user_credentials
+-- ============================================================
+
+CREATE TABLE IF NOT EXISTS secure_cli_user_credentials (
+    id            TEXT NOT NULL PRIMARY KEY,
+    binary_id     TEXT NOT NULL REFERENCES secure_cli_binaries(id) ON DELETE CASCADE,
+    user_id       VARCHAR(255) NOT NULL,
+    encrypted_env BLOB NOT NULL,
+    metadata      TEXT NOT NULL DEFAULT '{}',
+    tenant_id     TEXT NOT NULL REFERENCES tenants(id),
+    created_at    TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ', 'now')),
+    updated_at    TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ', 'now')),
+    UNIQUE(binary_id, user_id, tenant_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_scuc_tenant ON secure_cli_user_credentials(tenant_id);
+CREATE INDEX IF NOT EXISTS idx_scuc_binary ON secure_cli_user_credentials(binary_id);
+
 -- ============================================================
 -- Table: api_keys
 -- ============================================================
@@ -1346,3 +1471,115 @@ CREATE TABLE IF NOT EXISTS subagent_tasks (
 CREATE INDEX IF NOT EXISTS idx_subagent_tasks_parent_status ON subagent_tasks(tenant_id, parent_agent_key, status);
 CREATE INDEX IF NOT EXISTS idx_subagent_tasks_session ON subagent_tasks(session_key);
 CREATE INDEX IF NOT EXISTS idx_subagent_tasks_created ON subagent_tasks(tenant_id, created_at);
+
+-- ============================================================
+-- Table: message_queue (durable bus queue)
+-- ============================================================
+
+CREATE TABLE IF NOT EXISTS message_queue (
+    id               TEXT PRIMARY KEY,
+    tenant_id        TEXT NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    direction        VARCHAR(10) NOT NULL,
+    channel          VARCHAR(255) NOT NULL,
+    idempotency_key  VARCHAR(500) NOT NULL DEFAULT '',
+    payload          TEXT NOT NULL,
+    status           VARCHAR(20) NOT NULL DEFAULT 'queued',
+    attempts         INTEGER NOT NULL DEFAULT 0,
+    last_error       TEXT NOT NULL DEFAULT '',
+    owner            VARCHAR(255) NOT NULL DEFAULT '',
+    next_attempt_at  TEXT NOT NULL,
+    created_at       TEXT NOT NULL,
+    updated_at       TEXT NOT NULL
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_message_queue_idempotency ON message_queue(direction, idempotency_key) WHERE idempotency_key <> '';
+CREATE INDEX IF NOT EXISTS idx_message_queue_due ON message_queue(status, next_attempt_at);
+CREATE INDEX IF NOT EXISTS idx_message_queue_owner ON message_queue(owner, status);
+CREATE INDEX IF NOT EXISTS idx_message_queue_status ON message_queue(tenant_id, status, updated_at);
+
+-- ============================================================
+-- Table: context_file_revisions / context_file_changes
+-- (context file history and approval queue)
+-- ============================================================
+
+CREATE TABLE IF NOT EXISTS context_file_revisions (
+    id          TEXT NOT NULL PRIMARY KEY,
+    tenant_id   TEXT NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    agent_id    TEXT NOT NULL REFERENCES agents(id) ON DELETE CASCADE,
+    user_id     VARCHAR(255) NOT NULL DEFAULT '',
+    file_name   VARCHAR(255) NOT NULL,
+    content     TEXT NOT NULL DEFAULT '',
+    author      VARCHAR(20) NOT NULL,
+    author_id   VARCHAR(255) NOT NULL DEFAULT '',
+    trace_id    TEXT,
+    created_at  TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ', 'now'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_context_file_revisions_file ON context_file_revisions(agent_id, user_id, file_name, created_at);
+CREATE INDEX IF NOT EXISTS idx_context_file_revisions_tenant ON context_file_revisions(tenant_id);
+
+CREATE TABLE IF NOT EXISTS context_file_changes (
+    id           TEXT NOT NULL PRIMARY KEY,
+    tenant_id    TEXT NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    agent_id     TEXT NOT NULL REFERENCES agents(id) ON DELETE CASCADE,
+    user_id      VARCHAR(255) NOT NULL DEFAULT '',
+    file_name    VARCHAR(255) NOT NULL,
+    content      TEXT NOT NULL DEFAULT '',
+    author       VARCHAR(20) NOT NULL,
+    author_id    VARCHAR(255) NOT NULL DEFAULT '',
+    trace_id     TEXT,
+    status       VARCHAR(20) NOT NULL DEFAULT 'pending',
+    reviewed_by  VARCHAR(255) NOT NULL DEFAULT '',
+    reviewed_at  TEXT,
+    created_at   TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ', 'now'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_context_file_changes_agent ON context_file_changes(agent_id, status, created_at);
+CREATE INDEX IF NOT EXISTS idx_context_file_changes_tenant ON context_file_changes(tenant_id);
+
+-- ============================================================
+-- Table: eval_suites / eval_runs
+-- (agent evaluation harness)
+-- ============================================================
+
+CREATE TABLE IF NOT EXISTS eval_suites (
+    id           TEXT NOT NULL PRIMARY KEY,
+    tenant_id    TEXT NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    agent_id     TEXT NOT NULL REFERENCES agents(id) ON DELETE CASCADE,
+    name         VARCHAR(255) NOT NULL,
+    description  TEXT NOT NULL DEFAULT '',
+    judge_model  VARCHAR(255) NOT NULL DEFAULT '',
+    cases        TEXT NOT NULL DEFAULT '[]',
+    created_by   VARCHAR(255) NOT NULL DEFAULT '',
+    created_at   TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ', 'now')),
+    updated_at   TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ', 'now')),
+    UNIQUE(agent_id, name)
+);
+
+CREATE INDEX IF NOT EXISTS idx_eval_suites_tenant ON eval_suites(tenant_id);
+
+CREATE TABLE IF NOT EXISTS eval_runs (
+    id             TEXT NOT NULL PRIMARY KEY,
+    tenant_id      TEXT NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    suite_id       TEXT NOT NULL REFERENCES eval_suites(id) ON DELETE CASCADE,
+    agent_id       TEXT NOT NULL REFERENCES agents(id) ON DELETE CASCADE,
+    suite_name     VARCHAR(255) NOT NULL,
+    status         VARCHAR(20) NOT NULL DEFAULT 'running',
+    tool_mode      VARCHAR(20) NOT NULL DEFAULT 'stub',
+    total          INTEGER NOT NULL DEFAULT 0,
+    passed         INTEGER NOT NULL DEFAULT 0,
+    failed         INTEGER NOT NULL DEFAULT 0,
+    input_tokens   INTEGER NOT NULL DEFAULT 0,
+    output_tokens  INTEGER NOT NULL DEFAULT 0,
+    cost_usd       REAL NOT NULL DEFAULT 0,
+    duration_ms    INTEGER NOT NULL DEFAULT 0,
+    error          TEXT NOT NULL DEFAULT '',
+    results        TEXT NOT NULL DEFAULT '[]',
+    created_by     VARCHAR(255) NOT NULL DEFAULT '',
+    started_at     TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ', 'now')),
+    finished_at    TEXT
+);
+
+CREATE INDEX IF NOT EXISTS idx_eval_runs_agent ON eval_runs(agent_id, started_at);
+CREATE INDEX IF NOT EXISTS idx_eval_runs_suite ON eval_runs(suite_id, started_at);
+CREATE INDEX IF NOT EXISTS idx_eval_runs_tenant ON eval_runs(tenant_id);
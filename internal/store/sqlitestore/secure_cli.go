@@ -0,0 +1,282 @@
+//go:build sqlite || sqliteonly
+
+package sqlitestore
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/nextlevelbuilder/goclaw/internal/crypto"
+	"github.com/nextlevelbuilder/goclaw/internal/store"
+)
+
+// SQLiteSecureCLIStore implements store.SecureCLIStore backed by SQLite.
+// Env blobs are AES-256-GCM encrypted with the same key and helper as the PG store.
+type SQLiteSecureCLIStore struct {
+	db     *sql.DB
+	encKey string
+}
+
+func NewSQLiteSecureCLIStore(db *sql.DB, encryptionKey string) *SQLiteSecureCLIStore {
+	return &SQLiteSecureCLIStore{db: db, encKey: encryptionKey}
+}
+
+const secureCLISelectCols = `b.id, b.binary_name, b.binary_path, b.description, b.encrypted_env,
+ b.deny_args, b.deny_verbose, b.timeout_seconds, b.tips, b.agent_id, b.enabled, b.created_by, b.created_at, b.updated_at`
+
+func (s *SQLiteSecureCLIStore) Create(ctx context.Context, b *store.SecureCLIBinary) error {
+	if err := store.ValidateUserID(b.CreatedBy); err != nil {
+		return err
+	}
+	if b.ID == uuid.Nil {
+		b.ID = store.GenNewID()
+	}
+
+	envBytes, err := s.encryptEnv(b.EncryptedEnv)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	b.CreatedAt = now
+	b.UpdatedAt = now
+
+	_, err = s.db.ExecContext(ctx,
+		`INSERT INTO secure_cli_binaries (id, binary_name, binary_path, description, encrypted_env,
+		 deny_args, deny_verbose, timeout_seconds, tips, agent_id, enabled, created_by, created_at, updated_at, tenant_id)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		b.ID, b.BinaryName, nilStr(derefStr(b.BinaryPath)), b.Description,
+		envBytes,
+		string(jsonOrEmptyArray(b.DenyArgs)), string(jsonOrEmptyArray(b.DenyVerbose)),
+		b.TimeoutSeconds, b.Tips,
+		nilUUID(b.AgentID), b.Enabled,
+		b.CreatedBy, now, now, tenantIDForInsert(ctx),
+	)
+	return err
+}
+
+func (s *SQLiteSecureCLIStore) Get(ctx context.Context, id uuid.UUID) (*store.SecureCLIBinary, error) {
+	if store.IsCrossTenant(ctx) {
+		row := s.db.QueryRowContext(ctx,
+			`SELECT `+secureCLISelectCols+`, NULL FROM secure_cli_binaries b WHERE b.id = ?`, id)
+		return s.scanRow(row)
+	}
+	tenantID := store.TenantIDFromContext(ctx)
+	if tenantID == uuid.Nil {
+		return nil, sql.ErrNoRows
+	}
+	row := s.db.QueryRowContext(ctx,
+		`SELECT `+secureCLISelectCols+`, NULL FROM secure_cli_binaries b WHERE b.id = ? AND b.tenant_id = ?`, id, tenantID)
+	return s.scanRow(row)
+}
+
+// secureCLIAllowedFields is the allowlist of columns that can be updated via execMapUpdate.
+// Defense-in-depth: prevents column name injection even if caller skips validation.
+var secureCLIAllowedFields = map[string]bool{
+	"binary_name": true, "binary_path": true, "description": true,
+	"encrypted_env": true, "deny_args": true, "deny_verbose": true,
+	"timeout_seconds": true, "tips": true, "agent_id": true, "enabled": true,
+	"updated_at": true,
+}
+
+func (s *SQLiteSecureCLIStore) Update(ctx context.Context, id uuid.UUID, updates map[string]any) error {
+	// Filter unknown fields to prevent column name injection
+	for k := range updates {
+		if !secureCLIAllowedFields[k] {
+			delete(updates, k)
+		}
+	}
+
+	// Encrypt env if present in updates
+	if envVal, ok := updates["encrypted_env"]; ok {
+		if envStr, isStr := envVal.(string); isStr && envStr != "" && s.encKey != "" {
+			encrypted, err := crypto.Encrypt(envStr, s.encKey)
+			if err != nil {
+				return fmt.Errorf("encrypt env: %w", err)
+			}
+			updates["encrypted_env"] = []byte(encrypted)
+		}
+	}
+	updates["updated_at"] = time.Now().UTC()
+	if store.IsCrossTenant(ctx) {
+		return execMapUpdate(ctx, s.db, "secure_cli_binaries", id, updates)
+	}
+	tid := store.TenantIDFromContext(ctx)
+	if tid == uuid.Nil {
+		return fmt.Errorf("tenant_id required for update")
+	}
+	return execMapUpdateWhereTenant(ctx, s.db, "secure_cli_binaries", updates, id, tid)
+}
+
+func (s *SQLiteSecureCLIStore) Delete(ctx context.Context, id uuid.UUID) error {
+	if store.IsCrossTenant(ctx) {
+		_, err := s.db.ExecContext(ctx, "DELETE FROM secure_cli_binaries WHERE id = ?", id)
+		return err
+	}
+	tid := store.TenantIDFromContext(ctx)
+	if tid == uuid.Nil {
+		return fmt.Errorf("tenant_id required")
+	}
+	_, err := s.db.ExecContext(ctx, "DELETE FROM secure_cli_binaries WHERE id = ? AND tenant_id = ?", id, tid)
+	return err
+}
+
+func (s *SQLiteSecureCLIStore) List(ctx context.Context) ([]store.SecureCLIBinary, error) {
+	return s.list(ctx, "", nil)
+}
+
+func (s *SQLiteSecureCLIStore) ListByAgent(ctx context.Context, agentID uuid.UUID) ([]store.SecureCLIBinary, error) {
+	return s.list(ctx, "(b.agent_id = ? OR b.agent_id IS NULL) AND b.enabled = 1", []any{agentID})
+}
+
+func (s *SQLiteSecureCLIStore) ListEnabled(ctx context.Context) ([]store.SecureCLIBinary, error) {
+	return s.list(ctx, "b.enabled = 1", nil)
+}
+
+// list runs a tenant-scoped SELECT with an optional extra condition.
+// Agent-specific configs sort after the global one for the same binary.
+func (s *SQLiteSecureCLIStore) list(ctx context.Context, cond string, args []any) ([]store.SecureCLIBinary, error) {
+	where := "1 = 1"
+	if cond != "" {
+		where = cond
+	}
+	if !store.IsCrossTenant(ctx) {
+		tenantID := store.TenantIDFromContext(ctx)
+		if tenantID == uuid.Nil {
+			return nil, nil
+		}
+		where += " AND b.tenant_id = ?"
+		args = append(args, tenantID)
+	}
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT `+secureCLISelectCols+`, NULL FROM secure_cli_binaries b
+		 WHERE `+where+`
+		 ORDER BY b.binary_name, b.agent_id NULLS LAST`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []store.SecureCLIBinary
+	for rows.Next() {
+		b, err := s.scanRow(rows)
+		if err != nil {
+			continue
+		}
+		result = append(result, *b)
+	}
+	return result, rows.Err()
+}
+
+// LookupByBinary finds the best credential config for a binary name.
+// Agent-specific config takes priority over global (agent_id IS NULL).
+// If userID is non-empty, also fetches per-user env overrides via LEFT JOIN (zero extra queries).
+func (s *SQLiteSecureCLIStore) LookupByBinary(ctx context.Context, binaryName string, agentID *uuid.UUID, userID string) (*store.SecureCLIBinary, error) {
+	tid := store.TenantIDFromContext(ctx)
+	isCross := store.IsCrossTenant(ctx)
+	if !isCross && tid == uuid.Nil {
+		return nil, nil
+	}
+
+	query := `SELECT ` + secureCLISelectCols
+	var args []any
+	if userID != "" {
+		query += `, uc.encrypted_env FROM secure_cli_binaries b
+		 LEFT JOIN secure_cli_user_credentials uc ON uc.binary_id = b.id AND uc.user_id = ? AND uc.tenant_id = ?`
+		args = append(args, userID, tid)
+	} else {
+		query += `, NULL FROM secure_cli_binaries b`
+	}
+
+	query += ` WHERE b.binary_name = ? AND b.enabled = 1`
+	args = append(args, binaryName)
+	if agentID != nil {
+		query += ` AND (b.agent_id = ? OR b.agent_id IS NULL)`
+		args = append(args, *agentID)
+	} else {
+		query += ` AND b.agent_id IS NULL`
+	}
+	if !isCross {
+		query += ` AND b.tenant_id = ?`
+		args = append(args, tid)
+	}
+	query += ` ORDER BY b.agent_id NULLS LAST LIMIT 1`
+
+	b, err := s.scanRow(s.db.QueryRowContext(ctx, query, args...))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return b, err
+}
+
+// scanRow scans secureCLISelectCols plus a trailing per-user env column
+// (NULL when the query has no user credentials join).
+func (s *SQLiteSecureCLIStore) scanRow(row interface{ Scan(...any) error }) (*store.SecureCLIBinary, error) {
+	var b store.SecureCLIBinary
+	var binaryPath *string
+	var agentID *uuid.UUID
+	var denyArgs, denyVerbose []byte
+	var env, userEnv []byte
+	createdAt, updatedAt := scanTimePair()
+
+	err := row.Scan(
+		&b.ID, &b.BinaryName, &binaryPath, &b.Description, &env,
+		&denyArgs, &denyVerbose,
+		&b.TimeoutSeconds, &b.Tips, &agentID,
+		&b.Enabled, &b.CreatedBy, createdAt, updatedAt,
+		&userEnv,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	b.BinaryPath = binaryPath
+	b.AgentID = agentID
+	b.DenyArgs = denyArgs
+	b.DenyVerbose = denyVerbose
+	b.CreatedAt = createdAt.Time
+	b.UpdatedAt = updatedAt.Time
+
+	// Decrypt base env
+	if len(env) > 0 && s.encKey != "" {
+		decrypted, err := crypto.Decrypt(string(env), s.encKey)
+		if err != nil {
+			slog.Warn("secure_cli: failed to decrypt env", "binary", b.BinaryName, "error", err)
+		} else {
+			b.EncryptedEnv = []byte(decrypted)
+		}
+	} else {
+		b.EncryptedEnv = env
+	}
+
+	// Decrypt per-user env
+	if len(userEnv) > 0 && s.encKey != "" {
+		if decrypted, err := crypto.Decrypt(string(userEnv), s.encKey); err == nil {
+			b.UserEnv = []byte(decrypted)
+		}
+	}
+
+	return &b, nil
+}
+
+// encryptEnv encrypts a plaintext env JSON blob for storage. Without an
+// encryption key the blob is stored as-is (matches the PG store). Never
+// returns nil: encrypted_env is NOT NULL.
+func (s *SQLiteSecureCLIStore) encryptEnv(plain []byte) ([]byte, error) {
+	if len(plain) > 0 && s.encKey != "" {
+		encrypted, err := crypto.Encrypt(string(plain), s.encKey)
+		if err != nil {
+			return nil, fmt.Errorf("encrypt env: %w", err)
+		}
+		return []byte(encrypted), nil
+	}
+	if plain == nil {
+		return []byte{}, nil
+	}
+	return plain, nil
+}
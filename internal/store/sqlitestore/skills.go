@@ -34,6 +34,8 @@ type SQLiteSkillStore struct {
 
 	listCache map[uuid.UUID]*skillListCacheEntry
 	ttl       time.Duration
+
+	embProvider store.EmbeddingProvider // nil = no vector search
 }
 
 type skillListCacheEntry struct {
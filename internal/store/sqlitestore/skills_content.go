@@ -240,6 +240,7 @@ func (s *SQLiteSkillStore) UpsertSystemSkill(ctx context.Context, p store.SkillC
 			return uuid.Nil, false, "", fmt.Errorf("update system skill: %w", err)
 		}
 		s.BumpVersion()
+		s.embedAsync(existingID, p.Name, p.Description)
 		return existingID, true, p.FilePath, nil
 	}
 
@@ -257,6 +258,7 @@ func (s *SQLiteSkillStore) UpsertSystemSkill(ctx context.Context, p store.SkillC
 		return uuid.Nil, false, "", fmt.Errorf("insert system skill: %w", err)
 	}
 	s.BumpVersion()
+	s.embedAsync(id, p.Name, p.Description)
 	return id, true, p.FilePath, nil
 }
 
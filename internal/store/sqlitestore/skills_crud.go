@@ -169,6 +169,7 @@ func (s *SQLiteSkillStore) CreateSkillManaged(ctx context.Context, p store.Skill
 	}
 
 	s.BumpVersion()
+	s.embedAsync(returnedID, p.Name, p.Description)
 	return returnedID, nil
 }
 
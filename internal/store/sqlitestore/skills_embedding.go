@@ -0,0 +1,187 @@
+//go:build sqlite || sqliteonly
+
+package sqlitestore
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/google/uuid"
+
+	"github.com/nextlevelbuilder/goclaw/internal/store"
+)
+
+// SetEmbeddingProvider sets the embedding provider for vector-based skill search.
+func (s *SQLiteSkillStore) SetEmbeddingProvider(provider store.EmbeddingProvider) {
+	s.embProvider = provider
+}
+
+// skillEmbeddingText is the text embedded for a skill: its name and description.
+func skillEmbeddingText(name, description string) string {
+	if description == "" {
+		return name
+	}
+	return name + ": " + description
+}
+
+// SearchByEmbedding ranks the active, visible skills in scope by cosine
+// similarity to embedding. Skills are few, so their vectors are scanned per
+// query rather than cached.
+func (s *SQLiteSkillStore) SearchByEmbedding(ctx context.Context, embedding []float32, limit int) ([]store.SkillSearchResult, error) {
+	if limit <= 0 {
+		limit = 5
+	}
+	q := `SELECT id, name, slug, COALESCE(description, ''), version, file_path, embedding
+		FROM skills
+		WHERE status = 'active' AND enabled = 1 AND embedding IS NOT NULL
+		  AND visibility != 'private'`
+	var args []any
+	if !store.IsCrossTenant(ctx) {
+		tid := store.TenantIDFromContext(ctx)
+		if tid == uuid.Nil {
+			tid = store.MasterTenantID
+		}
+		q += " AND (is_system = 1 OR tenant_id = ?)"
+		args = append(args, tid)
+	}
+	rows, err := s.db.QueryContext(ctx, q, args...)
+	if err != nil {
+		return nil, fmt.Errorf("embedding skill search: %w", err)
+	}
+	defer rows.Close()
+
+	vecs := &agentVectors{}
+	byID := make(map[string]store.SkillSearchResult)
+	for rows.Next() {
+		var id string
+		var r store.SkillSearchResult
+		var version int
+		var filePath *string
+		var blob []byte
+		if err := rows.Scan(&id, &r.Name, &r.Slug, &r.Description, &version, &filePath, &blob); err != nil {
+			continue
+		}
+		if !vecs.add(id, blob) {
+			continue
+		}
+		// Use DB file_path when available; fall back to baseDir construction.
+		if filePath != nil && *filePath != "" {
+			r.Path = *filePath + "/SKILL.md"
+		} else {
+			r.Path = fmt.Sprintf("%s/%s/%d/SKILL.md", s.baseDir, r.Slug, version)
+		}
+		byID[id] = r
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	hits := vecs.rank(embedding)
+	if len(hits) > limit {
+		hits = hits[:limit]
+	}
+	results := make([]store.SkillSearchResult, 0, len(hits))
+	for _, h := range hits {
+		r := byID[h.id]
+		r.Score = h.score
+		results = append(results, r)
+	}
+	return results, nil
+}
+
+// BackfillSkillEmbeddings generates embeddings for all active skills that don't have one yet.
+func (s *SQLiteSkillStore) BackfillSkillEmbeddings(ctx context.Context) (int, error) {
+	if s.embProvider == nil {
+		return 0, nil
+	}
+
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT id, name, COALESCE(description, '') FROM skills WHERE status = 'active' AND enabled = 1 AND embedding IS NULL`)
+	if err != nil {
+		return 0, err
+	}
+	type skillRow struct {
+		id   string
+		name string
+		desc string
+	}
+	var pending []skillRow
+	for rows.Next() {
+		var r skillRow
+		if err := rows.Scan(&r.id, &r.name, &r.desc); err != nil {
+			continue
+		}
+		pending = append(pending, r)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+	if len(pending) == 0 {
+		return 0, nil
+	}
+
+	slog.Info("backfilling skill embeddings", "count", len(pending))
+	const batchSize = 50
+	updated := 0
+	for start := 0; start < len(pending); start += batchSize {
+		batch := pending[start:min(start+batchSize, len(pending))]
+		texts := make([]string, len(batch))
+		for i, sk := range batch {
+			texts[i] = skillEmbeddingText(sk.name, sk.desc)
+		}
+		embeddings, err := s.embProvider.Embed(ctx, texts)
+		if err != nil {
+			return updated, fmt.Errorf("embed skills: %w", err)
+		}
+		for i, emb := range embeddings {
+			if i >= len(batch) {
+				break
+			}
+			blob := encodeEmbedding(emb)
+			if blob == nil {
+				continue
+			}
+			if _, err := s.db.ExecContext(ctx, `UPDATE skills SET embedding = ? WHERE id = ?`, blob, batch[i].id); err != nil {
+				slog.Warn("skill embedding update failed", "skill", batch[i].name, "error", err)
+				continue
+			}
+			updated++
+		}
+	}
+	return updated, nil
+}
+
+// embedAsync embeds a created or updated skill in the background. The
+// provider is read before the goroutine starts so it never races with
+// SetEmbeddingProvider.
+func (s *SQLiteSkillStore) embedAsync(id uuid.UUID, name string, description *string) {
+	if s.embProvider == nil {
+		return
+	}
+	desc := ""
+	if description != nil {
+		desc = *description
+	}
+	go s.generateEmbedding(context.Background(), s.embProvider, id, name, desc)
+}
+
+// generateEmbedding creates an embedding for a skill's name+description and stores it.
+func (s *SQLiteSkillStore) generateEmbedding(ctx context.Context, provider store.EmbeddingProvider, id uuid.UUID, name, desc string) {
+	embeddings, err := provider.Embed(ctx, []string{skillEmbeddingText(name, desc)})
+	if err != nil {
+		slog.Warn("skill embedding generation failed", "skill", name, "error", err)
+		return
+	}
+	if len(embeddings) == 0 {
+		return
+	}
+	blob := encodeEmbedding(embeddings[0])
+	if blob == nil {
+		return
+	}
+	if _, err := s.db.ExecContext(ctx, `UPDATE skills SET embedding = ? WHERE id = ?`, blob, id); err != nil {
+		slog.Warn("skill embedding store failed", "skill", name, "error", err)
+	}
+}
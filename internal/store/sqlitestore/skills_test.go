@@ -67,6 +67,46 @@ func TestSQLiteSkillStore_CreateSkillManaged_PersistsArchivedDependencyState(t *
 	}
 }
 
+func TestSQLiteSkillStore_SearchByEmbedding_RanksBackfilledSkills(t *testing.T) {
+	ctx, skillStore := newTestSQLiteSkillStore(t)
+	for _, p := range []struct{ name, slug, desc string }{
+		{"Sedan Finder", "sedan-finder", "Compare car listings."},
+		{"Route Planner", "route-planner", "Plan bicycle trips."},
+	} {
+		desc := p.desc
+		if _, err := skillStore.CreateSkillManaged(ctx, store.SkillCreateParams{
+			Name:        p.name,
+			Slug:        p.slug,
+			Description: &desc,
+			OwnerID:     "user-1",
+			Visibility:  "public",
+			FilePath:    filepath.Join(t.TempDir(), p.slug, "1"),
+		}); err != nil {
+			t.Fatalf("CreateSkillManaged error: %v", err)
+		}
+	}
+
+	// Skills created before a provider was configured are embedded by backfill.
+	emb := &conceptEmbedder{}
+	skillStore.SetEmbeddingProvider(emb)
+	n, err := skillStore.BackfillSkillEmbeddings(ctx)
+	if err != nil || n != 2 {
+		t.Fatalf("BackfillSkillEmbeddings = %d, %v; want 2", n, err)
+	}
+
+	vecs, err := emb.Embed(ctx, []string{"automobile"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	results, err := skillStore.SearchByEmbedding(ctx, vecs[0], 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 1 || results[0].Slug != "sedan-finder" || results[0].Score < 0.9 {
+		t.Fatalf("results = %+v", results)
+	}
+}
+
 func newTestSQLiteSkillStore(t *testing.T) (context.Context, *SQLiteSkillStore) {
 	t.Helper()
 
@@ -4,6 +4,10 @@ package sqlitestore
 
 import (
 	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"time"
 
 	"github.com/google/uuid"
@@ -11,28 +15,212 @@ import (
 	"github.com/nextlevelbuilder/goclaw/internal/store"
 )
 
-// SQLiteSubagentTaskStore is a no-op implementation for the desktop/lite edition.
-// Subagent task persistence is a standard-edition feature.
-type SQLiteSubagentTaskStore struct{}
+// SQLiteSubagentTaskStore implements store.SubagentTaskStore backed by SQLite.
+type SQLiteSubagentTaskStore struct {
+	db *sql.DB
+}
+
+func NewSQLiteSubagentTaskStore(db *sql.DB) *SQLiteSubagentTaskStore {
+	return &SQLiteSubagentTaskStore{db: db}
+}
+
+const subagentTaskInsertCols = `tenant_id, parent_agent_key, session_key, subject, description,
+	status, result, depth, model, provider, iterations, input_tokens, output_tokens,
+	origin_channel, origin_chat_id, origin_peer_kind, origin_user_id, spawned_by, metadata,
+	created_at, updated_at`
+
+// Create persists a new subagent task at spawn time.
+func (s *SQLiteSubagentTaskStore) Create(ctx context.Context, task *store.SubagentTaskData) error {
+	tid := tenantIDForInsert(ctx)
+
+	metaJSON := []byte("{}")
+	if len(task.Metadata) > 0 {
+		if b, err := json.Marshal(task.Metadata); err == nil {
+			metaJSON = b
+		}
+	}
+
+	now := time.Now().UTC()
+	q := fmt.Sprintf(`INSERT INTO subagent_tasks (id, %s)
+		VALUES (?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?)
+		ON CONFLICT (id) DO NOTHING`, subagentTaskInsertCols)
+
+	_, err := s.db.ExecContext(ctx, q,
+		task.ID, tid, task.ParentAgentKey, task.SessionKey, task.Subject, task.Description,
+		task.Status, task.Result, task.Depth, task.Model, task.Provider,
+		task.Iterations, task.InputTokens, task.OutputTokens,
+		task.OriginChannel, task.OriginChatID, task.OriginPeerKind, task.OriginUserID,
+		task.SpawnedBy, string(metaJSON), now, now,
+	)
+	return err
+}
+
+const subagentTaskSelectCols = `id, tenant_id, parent_agent_key, session_key, subject, description,
+	status, result, depth, model, provider, iterations, input_tokens, output_tokens,
+	origin_channel, origin_chat_id, origin_peer_kind, origin_user_id, spawned_by,
+	completed_at, archived_at, COALESCE(metadata, '{}'), created_at, updated_at`
+
+// scanSubagentTask scans a single row into SubagentTaskData.
+func scanSubagentTask(row interface{ Scan(...any) error }) (*store.SubagentTaskData, error) {
+	var t store.SubagentTaskData
+	var metaJSON []byte
+	var completedAt, archivedAt nullSqliteTime
+	createdAt, updatedAt := scanTimePair()
+	err := row.Scan(
+		&t.ID, &t.TenantID, &t.ParentAgentKey, &t.SessionKey, &t.Subject, &t.Description,
+		&t.Status, &t.Result, &t.Depth, &t.Model, &t.Provider,
+		&t.Iterations, &t.InputTokens, &t.OutputTokens,
+		&t.OriginChannel, &t.OriginChatID, &t.OriginPeerKind, &t.OriginUserID, &t.SpawnedBy,
+		&completedAt, &archivedAt, &metaJSON, createdAt, updatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if completedAt.Valid {
+		t.CompletedAt = &completedAt.Time
+	}
+	if archivedAt.Valid {
+		t.ArchivedAt = &archivedAt.Time
+	}
+	t.CreatedAt = createdAt.Time
+	t.UpdatedAt = updatedAt.Time
+	if len(metaJSON) > 2 { // skip "{}"
+		_ = json.Unmarshal(metaJSON, &t.Metadata)
+	}
+	return &t, nil
+}
+
+// Get retrieves a single task by ID (tenant-scoped).
+func (s *SQLiteSubagentTaskStore) Get(ctx context.Context, id uuid.UUID) (*store.SubagentTaskData, error) {
+	tid, err := requireTenantID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	q := fmt.Sprintf(`SELECT %s FROM subagent_tasks WHERE id = ? AND tenant_id = ?`, subagentTaskSelectCols)
+	t, err := scanSubagentTask(s.db.QueryRowContext(ctx, q, id, tid))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	return t, err
+}
+
+// UpdateStatus updates status, result, iterations, and token counts.
+func (s *SQLiteSubagentTaskStore) UpdateStatus(
+	ctx context.Context, id uuid.UUID,
+	status string, result *string, iterations int,
+	inputTokens, outputTokens int64,
+) error {
+	tid, err := requireTenantID(ctx)
+	if err != nil {
+		return err
+	}
 
-func NewSQLiteSubagentTaskStore() *SQLiteSubagentTaskStore { return &SQLiteSubagentTaskStore{} }
+	now := time.Now().UTC()
+	var completedAt *time.Time
+	if status != "running" {
+		completedAt = &now
+	}
 
-func (s *SQLiteSubagentTaskStore) Create(context.Context, *store.SubagentTaskData) error { return nil }
-func (s *SQLiteSubagentTaskStore) Get(context.Context, uuid.UUID) (*store.SubagentTaskData, error) {
-	return nil, nil
+	q := `UPDATE subagent_tasks SET
+		status = ?, result = ?, iterations = ?,
+		input_tokens = ?, output_tokens = ?,
+		completed_at = ?, updated_at = ?
+		WHERE id = ? AND tenant_id = ?`
+	_, err = s.db.ExecContext(ctx, q,
+		status, result, iterations, inputTokens, outputTokens,
+		completedAt, now, id, tid,
+	)
+	return err
 }
-func (s *SQLiteSubagentTaskStore) UpdateStatus(context.Context, uuid.UUID, string, *string, int, int64, int64) error {
-	return nil
+
+// ListByParent returns tasks for a parent agent key, optionally filtered by status.
+func (s *SQLiteSubagentTaskStore) ListByParent(
+	ctx context.Context, parentAgentKey string, statusFilter string,
+) ([]store.SubagentTaskData, error) {
+	tid, err := requireTenantID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	where := "tenant_id = ? AND parent_agent_key = ?"
+	args := []any{tid, parentAgentKey}
+	if statusFilter != "" {
+		where += " AND status = ?"
+		args = append(args, statusFilter)
+	}
+	q := fmt.Sprintf(`SELECT %s FROM subagent_tasks WHERE %s
+		ORDER BY created_at DESC LIMIT 50`, subagentTaskSelectCols, where)
+	rows, err := s.db.QueryContext(ctx, q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return collectSubagentTasks(rows)
 }
-func (s *SQLiteSubagentTaskStore) ListByParent(context.Context, string, string) ([]store.SubagentTaskData, error) {
-	return nil, nil
+
+// ListBySession returns tasks for a specific session key (tenant-scoped).
+func (s *SQLiteSubagentTaskStore) ListBySession(
+	ctx context.Context, sessionKey string,
+) ([]store.SubagentTaskData, error) {
+	tid, err := requireTenantID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	q := fmt.Sprintf(`SELECT %s FROM subagent_tasks
+		WHERE tenant_id = ? AND session_key = ?
+		ORDER BY created_at DESC LIMIT 50`, subagentTaskSelectCols)
+	rows, err := s.db.QueryContext(ctx, q, tid, sessionKey)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return collectSubagentTasks(rows)
 }
-func (s *SQLiteSubagentTaskStore) ListBySession(context.Context, string) ([]store.SubagentTaskData, error) {
-	return nil, nil
+
+// Archive marks old completed/failed/cancelled tasks as archived.
+func (s *SQLiteSubagentTaskStore) Archive(ctx context.Context, olderThan time.Duration) (int64, error) {
+	now := time.Now().UTC()
+	cutoff := now.Add(-olderThan)
+	q := `UPDATE subagent_tasks SET archived_at = ?, updated_at = ?
+		WHERE status IN ('completed', 'failed', 'cancelled')
+		AND archived_at IS NULL AND completed_at < ?`
+	res, err := s.db.ExecContext(ctx, q, now, now, cutoff)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
 }
-func (s *SQLiteSubagentTaskStore) Archive(context.Context, time.Duration) (int64, error) {
-	return 0, nil
+
+// UpdateMetadata merges metadata on an existing task.
+func (s *SQLiteSubagentTaskStore) UpdateMetadata(ctx context.Context, id uuid.UUID, metadata map[string]any) error {
+	tid, err := requireTenantID(ctx)
+	if err != nil {
+		return err
+	}
+
+	metaJSON, err := json.Marshal(metadata)
+	if err != nil {
+		return err
+	}
+
+	q := `UPDATE subagent_tasks SET metadata = json_patch(COALESCE(metadata, '{}'), ?), updated_at = ?
+		WHERE id = ? AND tenant_id = ?`
+	_, err = s.db.ExecContext(ctx, q, string(metaJSON), time.Now().UTC(), id, tid)
+	return err
 }
-func (s *SQLiteSubagentTaskStore) UpdateMetadata(context.Context, uuid.UUID, map[string]any) error {
-	return nil
+
+// collectSubagentTasks scans rows into a slice.
+func collectSubagentTasks(rows *sql.Rows) ([]store.SubagentTaskData, error) {
+	var tasks []store.SubagentTaskData
+	for rows.Next() {
+		t, err := scanSubagentTask(rows)
+		if err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, *t)
+	}
+	return tasks, rows.Err()
 }
@@ -17,11 +17,14 @@ import (
 
 // SQLiteTeamStore implements store.TeamStore backed by SQLite.
 type SQLiteTeamStore struct {
-	db *sql.DB
+	db          *sql.DB
+	embProvider store.EmbeddingProvider // nil = LIKE-only task search
 }
 
-// SetEmbeddingProvider is a no-op for SQLite — vector search not supported.
-func (s *SQLiteTeamStore) SetEmbeddingProvider(_ store.EmbeddingProvider) {}
+// SetEmbeddingProvider enables vector task search.
+func (s *SQLiteTeamStore) SetEmbeddingProvider(provider store.EmbeddingProvider) {
+	s.embProvider = provider
+}
 
 func NewSQLiteTeamStore(db *sql.DB) *SQLiteTeamStore {
 	return &SQLiteTeamStore{db: db}
@@ -7,6 +7,7 @@ import (
 	"database/sql"
 	"encoding/json"
 	"fmt"
+	"log/slog"
 	"strings"
 	"time"
 	"unicode"
@@ -77,10 +78,44 @@ func (s *SQLiteTeamStore) ListTaskScopes(ctx context.Context, teamID uuid.UUID)
 // ============================================================
 
 func (s *SQLiteTeamStore) CreateTask(ctx context.Context, task *store.TeamTaskData) error {
+	return s.CreateTasks(ctx, []*store.TeamTaskData{task})
+}
+
+// CreateTasks inserts all tasks in one transaction so a multi-task plan is
+// created atomically. Task numbers are assigned in slice order.
+func (s *SQLiteTeamStore) CreateTasks(ctx context.Context, tasks []*store.TeamTaskData) error {
+	if len(tasks) == 0 {
+		return nil
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+	for _, task := range tasks {
+		if err := insertTaskTx(ctx, tx, task, now); err != nil {
+			return err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	// Fire-and-forget: generate embeddings for the new tasks' subjects.
+	for _, task := range tasks {
+		s.embedTaskAsync(task.ID, task.Subject)
+	}
+	return nil
+}
+
+// insertTaskTx assigns the task number and identifier, then inserts one task.
+func insertTaskTx(ctx context.Context, tx *sql.Tx, task *store.TeamTaskData, now time.Time) error {
 	if task.ID == uuid.Nil {
 		task.ID = store.GenNewID()
 	}
-	now := time.Now()
 	task.CreatedAt = now
 	task.UpdatedAt = now
 
@@ -88,15 +123,9 @@ func (s *SQLiteTeamStore) CreateTask(ctx context.Context, task *store.TeamTaskDa
 		task.TaskType = "general"
 	}
 
-	tx, err := s.db.BeginTx(ctx, nil)
-	if err != nil {
-		return err
-	}
-	defer tx.Rollback()
-
 	// Scope task_number per (team_id, chat_id).
 	var taskNumber int
-	err = tx.QueryRowContext(ctx,
+	err := tx.QueryRowContext(ctx,
 		`SELECT COALESCE(MAX(task_number), 0) + 1 FROM team_tasks WHERE team_id = ? AND COALESCE(chat_id, '') = ?`,
 		task.TeamID, task.ChatID,
 	).Scan(&taskNumber)
@@ -130,11 +159,7 @@ func (s *SQLiteTeamStore) CreateTask(ctx context.Context, task *store.TeamTaskDa
 		task.LockedAt, task.LockExpiresAt,
 		now, now, tenantIDForInsert(ctx),
 	)
-	if err != nil {
-		return err
-	}
-
-	return tx.Commit()
+	return err
 }
 
 // allowedTaskUpdateCols is the whitelist of columns that UpdateTask accepts.
@@ -169,14 +194,42 @@ func (s *SQLiteTeamStore) UpdateTask(ctx context.Context, taskID uuid.UUID, upda
 		}
 	}
 	updates["updated_at"] = time.Now()
+	_, syncStatus := updates["blocked_by"]
 	if store.IsCrossTenant(ctx) {
-		return execMapUpdate(ctx, s.db, "team_tasks", taskID, updates)
+		if err := execMapUpdate(ctx, s.db, "team_tasks", taskID, updates); err != nil {
+			return err
+		}
+	} else {
+		tid := store.TenantIDFromContext(ctx)
+		if tid == uuid.Nil {
+			return fmt.Errorf("tenant_id required for update")
+		}
+		if err := execMapUpdateWhereTenant(ctx, s.db, "team_tasks", updates, taskID, tid); err != nil {
+			return err
+		}
+	}
+
+	// Re-embed when subject changes.
+	if newSubject, ok := updates["subject"].(string); ok && newSubject != "" {
+		s.embedTaskAsync(taskID, newSubject)
 	}
-	tid := store.TenantIDFromContext(ctx)
-	if tid == uuid.Nil {
-		return fmt.Errorf("tenant_id required for update")
+	if !syncStatus {
+		return nil
+	}
+
+	// Keep status consistent with the new prerequisite list (pending ⇄ blocked).
+	q := `UPDATE team_tasks SET status = CASE
+	   WHEN status = 'pending' AND json_array_length(COALESCE(blocked_by, '[]')) > 0 THEN 'blocked'
+	   WHEN status = 'blocked' AND json_array_length(COALESCE(blocked_by, '[]')) = 0 THEN 'pending'
+	   ELSE status END
+	 WHERE id = ? AND status IN ('pending', 'blocked')`
+	args := []any{taskID}
+	if !store.IsCrossTenant(ctx) {
+		q += ` AND tenant_id = ?`
+		args = append(args, store.TenantIDFromContext(ctx))
 	}
-	return execMapUpdateWhereTenant(ctx, s.db, "team_tasks", updates, taskID, tid)
+	_, err := s.db.ExecContext(ctx, q, args...)
+	return err
 }
 
 func (s *SQLiteTeamStore) ListTasks(ctx context.Context, teamID uuid.UUID, orderBy string, statusFilter string, userID string, channel string, chatID string, limit int, offset int) ([]store.TeamTaskData, error) {
@@ -287,7 +340,8 @@ func (s *SQLiteTeamStore) GetTasksByIDs(ctx context.Context, ids []uuid.UUID) ([
 	return scanTaskRowsJoined(rows)
 }
 
-// SearchTasks uses LIKE-based search (SQLite has no tsvector/FTS without extension).
+// SearchTasks uses LIKE-based search (SQLite has no tsvector/FTS without
+// extension), merged with vector similarity when an embedding provider is set.
 func (s *SQLiteTeamStore) SearchTasks(ctx context.Context, teamID uuid.UUID, query string, limit int, userID string) ([]store.TeamTaskData, error) {
 	if limit <= 0 {
 		limit = 20
@@ -332,7 +386,11 @@ func (s *SQLiteTeamStore) SearchTasks(ctx context.Context, teamID uuid.UUID, que
 		tenantWhere = " AND t.tenant_id = ?"
 		args = append(args, tid)
 	}
-	args = append(args, limit)
+	likeLimit := limit
+	if s.embProvider != nil {
+		likeLimit = limit * 2 // fetch more for hybrid merge
+	}
+	args = append(args, likeLimit)
 
 	rows, err := s.db.QueryContext(ctx,
 		`SELECT `+taskSelectCols+`
@@ -345,7 +403,40 @@ func (s *SQLiteTeamStore) SearchTasks(ctx context.Context, teamID uuid.UUID, que
 		return nil, err
 	}
 	defer rows.Close()
-	return scanTaskRowsJoined(rows)
+	likeResults, err := scanTaskRowsJoined(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	// Helper: truncate LIKE results to limit for LIKE-only fallback.
+	truncatedLike := func() ([]store.TeamTaskData, error) {
+		if len(likeResults) > limit {
+			return likeResults[:limit], nil
+		}
+		return likeResults, nil
+	}
+
+	if s.embProvider == nil {
+		return truncatedLike()
+	}
+
+	// Hybrid search: combine LIKE + vector similarity.
+	embeddings, err := s.embProvider.Embed(ctx, []string{query})
+	if err != nil {
+		slog.Warn("task search embedding failed, falling back to LIKE", "error", err)
+		return truncatedLike()
+	}
+	if len(embeddings) == 0 || len(embeddings[0]) == 0 {
+		return truncatedLike()
+	}
+
+	vecResults, err := s.SearchTasksByEmbedding(ctx, teamID, embeddings[0], limit*2, userID)
+	if err != nil {
+		slog.Warn("task vector search failed, falling back to LIKE", "error", err)
+		return truncatedLike()
+	}
+
+	return hybridMergeTaskResults(likeResults, vecResults, 0.3, 0.7, limit), nil
 }
 
 func (s *SQLiteTeamStore) DeleteTask(ctx context.Context, taskID, teamID uuid.UUID) error {
@@ -667,13 +667,3 @@ func (s *SQLiteTeamStore) HasActiveMemberTasks(ctx context.Context, teamID uuid.
 	).Scan(&exists)
 	return exists, err
 }
-
-// BackfillTaskEmbeddings is a no-op for SQLite — vector search not supported.
-func (s *SQLiteTeamStore) BackfillTaskEmbeddings(_ context.Context) (int, error) {
-	return 0, nil
-}
-
-// SearchTasksByEmbedding is a no-op for SQLite — falls back to LIKE search.
-func (s *SQLiteTeamStore) SearchTasksByEmbedding(_ context.Context, _ uuid.UUID, _ []float32, _ int, _ string) ([]store.TeamTaskData, error) {
-	return nil, nil
-}
@@ -0,0 +1,245 @@
+//go:build sqlite || sqliteonly
+
+package sqlitestore
+
+import (
+	"cmp"
+	"context"
+	"log/slog"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/nextlevelbuilder/goclaw/internal/store"
+)
+
+// embedTaskAsync embeds a task's subject in the background. The provider is
+// read before the goroutine starts so it never races with SetEmbeddingProvider.
+func (s *SQLiteTeamStore) embedTaskAsync(taskID uuid.UUID, subject string) {
+	if s.embProvider == nil || subject == "" {
+		return
+	}
+	go s.generateTaskEmbedding(context.Background(), s.embProvider, taskID, subject)
+}
+
+// generateTaskEmbedding creates an embedding for a task's subject and stores it.
+// Logs warnings on error.
+func (s *SQLiteTeamStore) generateTaskEmbedding(ctx context.Context, provider store.EmbeddingProvider, taskID uuid.UUID, subject string) {
+	embeddings, err := provider.Embed(ctx, []string{subject})
+	if err != nil {
+		slog.Warn("task embedding generation failed", "task_id", taskID, "error", err)
+		return
+	}
+	if len(embeddings) == 0 {
+		return
+	}
+	blob := encodeEmbedding(embeddings[0])
+	if blob == nil {
+		return
+	}
+	if _, err := s.db.ExecContext(ctx,
+		`UPDATE team_tasks SET embedding = ? WHERE id = ?`, blob, taskID,
+	); err != nil {
+		slog.Warn("task embedding store failed", "task_id", taskID, "error", err)
+	}
+}
+
+// BackfillTaskEmbeddings generates embeddings for all tasks that don't have one yet.
+func (s *SQLiteTeamStore) BackfillTaskEmbeddings(ctx context.Context) (int, error) {
+	if s.embProvider == nil {
+		return 0, nil
+	}
+
+	const batchSize = 50
+	total := 0
+
+	for {
+		rows, err := s.db.QueryContext(ctx,
+			`SELECT id, subject FROM team_tasks
+			 WHERE embedding IS NULL AND status NOT IN ('cancelled')
+			 ORDER BY created_at DESC
+			 LIMIT ?`, batchSize)
+		if err != nil {
+			return total, err
+		}
+
+		type taskRow struct {
+			id      uuid.UUID
+			subject string
+		}
+		var pending []taskRow
+		for rows.Next() {
+			var r taskRow
+			if err := rows.Scan(&r.id, &r.subject); err != nil {
+				continue
+			}
+			pending = append(pending, r)
+		}
+		rows.Close()
+
+		if len(pending) == 0 {
+			break
+		}
+
+		slog.Info("backfilling task embeddings", "batch", len(pending), "total_so_far", total)
+
+		// Batch embed all subjects at once.
+		texts := make([]string, len(pending))
+		for i, p := range pending {
+			texts[i] = p.subject
+		}
+		embeddings, err := s.embProvider.Embed(ctx, texts)
+		if err != nil {
+			slog.Warn("task embedding batch failed", "error", err)
+			break
+		}
+
+		updated := 0
+		for i, emb := range embeddings {
+			if i >= len(pending) {
+				break
+			}
+			blob := encodeEmbedding(emb)
+			if blob == nil {
+				continue
+			}
+			if _, err := s.db.ExecContext(ctx,
+				`UPDATE team_tasks SET embedding = ? WHERE id = ?`, blob, pending[i].id,
+			); err != nil {
+				slog.Warn("task embedding update failed", "task_id", pending[i].id, "error", err)
+				continue
+			}
+			updated++
+		}
+		total += updated
+
+		// Stop when the batch was the last one, or when nothing could be
+		// stored (the same rows would be selected again).
+		if len(pending) < batchSize || updated == 0 {
+			break
+		}
+	}
+
+	if total > 0 {
+		slog.Info("task embeddings backfill complete", "updated", total)
+	}
+	return total, nil
+}
+
+// SearchTasksByEmbedding ranks the team's tasks by cosine similarity to
+// embedding. Vectors are scanned per query (see agentVectors); the matching
+// rows are then loaded in rank order.
+func (s *SQLiteTeamStore) SearchTasksByEmbedding(ctx context.Context, teamID uuid.UUID, embedding []float32, limit int, userID string) ([]store.TeamTaskData, error) {
+	if limit <= 0 {
+		limit = 5
+	}
+	tid := tenantIDForInsert(ctx)
+
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT id, embedding FROM team_tasks
+		 WHERE team_id = ? AND embedding IS NOT NULL
+		   AND (? = '' OR user_id = ?)
+		   AND tenant_id = ?`,
+		teamID, userID, userID, tid)
+	if err != nil {
+		return nil, err
+	}
+	vecs := &agentVectors{}
+	for rows.Next() {
+		var id string
+		var blob []byte
+		if err := rows.Scan(&id, &blob); err != nil {
+			continue
+		}
+		vecs.add(id, blob)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	hits := vecs.rank(embedding)
+	if len(hits) > limit {
+		hits = hits[:limit]
+	}
+	if len(hits) == 0 {
+		return nil, nil
+	}
+
+	placeholders := make([]string, len(hits))
+	args := make([]any, len(hits))
+	rankOf := make(map[string]int, len(hits))
+	for i, h := range hits {
+		placeholders[i] = "?"
+		args[i] = h.id
+		rankOf[h.id] = i
+	}
+	taskRows, err := s.db.QueryContext(ctx,
+		`SELECT `+taskSelectCols+`
+		 `+taskJoinClause+`
+		 WHERE t.id IN (`+strings.Join(placeholders, ",")+`)`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer taskRows.Close()
+	tasks, err := scanTaskRowsJoined(taskRows)
+	if err != nil {
+		return nil, err
+	}
+	slices.SortFunc(tasks, func(a, b store.TeamTaskData) int {
+		return cmp.Compare(rankOf[a.ID.String()], rankOf[b.ID.String()])
+	})
+	return tasks, nil
+}
+
+// hybridMergeTaskResults merges LIKE and vector search results by task ID.
+// LIKE results get textWeight, vector results get vecWeight. Duplicates are combined.
+func hybridMergeTaskResults(textResults, vecResults []store.TeamTaskData, textWeight, vecWeight float64, limit int) []store.TeamTaskData {
+	type scored struct {
+		task     store.TeamTaskData
+		textRank float64
+		vecRank  float64
+		combined float64
+	}
+
+	byID := make(map[uuid.UUID]*scored)
+
+	// LIKE results: rank by position (first = best).
+	for i, t := range textResults {
+		rank := 1.0 - float64(i)/float64(max(len(textResults), 1))
+		byID[t.ID] = &scored{task: t, textRank: rank}
+	}
+
+	// Vector results: rank by position (first = best cosine similarity).
+	for i, t := range vecResults {
+		rank := 1.0 - float64(i)/float64(max(len(vecResults), 1))
+		if s, ok := byID[t.ID]; ok {
+			s.vecRank = rank
+		} else {
+			byID[t.ID] = &scored{task: t, vecRank: rank}
+		}
+	}
+
+	// Compute combined scores.
+	results := make([]scored, 0, len(byID))
+	for _, s := range byID {
+		s.combined = s.textRank*textWeight + s.vecRank*vecWeight
+		results = append(results, *s)
+	}
+
+	// Sort by combined score descending.
+	slices.SortFunc(results, func(a, b scored) int {
+		return cmp.Compare(b.combined, a.combined) // descending
+	})
+
+	if len(results) > limit {
+		results = results[:limit]
+	}
+
+	out := make([]store.TeamTaskData, len(results))
+	for i, s := range results {
+		out[i] = s.task
+	}
+	return out
+}
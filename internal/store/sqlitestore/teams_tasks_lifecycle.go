@@ -121,7 +121,7 @@ func (s *SQLiteTeamStore) CancelTask(ctx context.Context, taskID, teamID uuid.UU
 		return fmt.Errorf("task not found, already completed/cancelled, or wrong team")
 	}
 
-	if err := unblockDependentTasksSQLite(ctx, tx, taskID); err != nil {
+	if err := resolveDependentTasksSQLite(ctx, tx, taskID, store.TeamTaskStatusCancelled); err != nil {
 		return err
 	}
 	return tx.Commit()
@@ -155,7 +155,7 @@ func (s *SQLiteTeamStore) FailTask(ctx context.Context, taskID, teamID uuid.UUID
 		return fmt.Errorf("task not in progress or not found")
 	}
 
-	if err := unblockDependentTasksSQLite(ctx, tx, taskID); err != nil {
+	if err := resolveDependentTasksSQLite(ctx, tx, taskID, store.TeamTaskStatusFailed); err != nil {
 		return err
 	}
 	return tx.Commit()
@@ -188,7 +188,7 @@ func (s *SQLiteTeamStore) FailPendingTask(ctx context.Context, taskID, teamID uu
 		return fmt.Errorf("task not pending/blocked or not found")
 	}
 
-	if err := unblockDependentTasksSQLite(ctx, tx, taskID); err != nil {
+	if err := resolveDependentTasksSQLite(ctx, tx, taskID, store.TeamTaskStatusFailed); err != nil {
 		return err
 	}
 	return tx.Commit()
@@ -277,7 +277,7 @@ func (s *SQLiteTeamStore) RejectTask(ctx context.Context, taskID, teamID uuid.UU
 		return fmt.Errorf("task not in review or not found")
 	}
 
-	if err := unblockDependentTasksSQLite(ctx, tx, taskID); err != nil {
+	if err := resolveDependentTasksSQLite(ctx, tx, taskID, store.TeamTaskStatusCancelled); err != nil {
 		return err
 	}
 	return tx.Commit()
@@ -347,3 +347,69 @@ func unblockDependentTasksSQLite(ctx context.Context, tx *sql.Tx, taskID uuid.UU
 	}
 	return nil
 }
+
+// resolveDependentTasksSQLite applies the team's dependency policy after taskID
+// ended with a failed/cancelled status: either unblock dependents (default) or
+// cascade the same status through every waiting descendant.
+func resolveDependentTasksSQLite(ctx context.Context, tx *sql.Tx, taskID uuid.UUID, status string) error {
+	var settings []byte
+	var identifier string
+	err := tx.QueryRowContext(ctx,
+		`SELECT tm.settings, COALESCE(t.identifier, '')
+		 FROM team_tasks t JOIN agent_teams tm ON tm.id = t.team_id
+		 WHERE t.id = ?`, taskID,
+	).Scan(&settings, &identifier)
+	if err != nil && err != sql.ErrNoRows {
+		return err
+	}
+	if !store.ParseTaskDependencyPolicy(settings).Cascades(status) {
+		return unblockDependentTasksSQLite(ctx, tx, taskID)
+	}
+
+	tid := tenantIDForInsert(ctx)
+	result := fmt.Sprintf("Cancelled: prerequisite task %s was cancelled", identifier)
+	if status == store.TeamTaskStatusFailed {
+		result = fmt.Sprintf("FAILED: prerequisite task %s failed", identifier)
+	}
+	now := time.Now()
+	frontier := []uuid.UUID{taskID}
+	for len(frontier) > 0 {
+		var next []uuid.UUID
+		for _, blocker := range frontier {
+			rows, err := tx.QueryContext(ctx,
+				`SELECT id FROM team_tasks
+				 WHERE status IN ('blocked', 'pending') AND tenant_id = ?
+				   AND EXISTS (SELECT 1 FROM json_each(blocked_by) WHERE json_each.value = ?)`,
+				tid, blocker.String(),
+			)
+			if err != nil {
+				return err
+			}
+			for rows.Next() {
+				var id uuid.UUID
+				if err := rows.Scan(&id); err != nil {
+					rows.Close()
+					return err
+				}
+				next = append(next, id)
+			}
+			rows.Close()
+			if err := rows.Err(); err != nil {
+				return err
+			}
+		}
+		for _, id := range next {
+			if _, err := tx.ExecContext(ctx,
+				`UPDATE team_tasks SET status = ?, result = ?, locked_at = NULL, lock_expires_at = NULL,
+				   followup_at = NULL, followup_count = 0, followup_message = NULL, followup_channel = NULL, followup_chat_id = NULL,
+				   updated_at = ?
+				 WHERE id = ? AND tenant_id = ?`,
+				status, result, now, id, tid,
+			); err != nil {
+				return err
+			}
+		}
+		frontier = next
+	}
+	return nil
+}
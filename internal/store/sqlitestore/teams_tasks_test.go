@@ -0,0 +1,56 @@
+//go:build sqlite || sqliteonly
+
+package sqlitestore
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/nextlevelbuilder/goclaw/internal/store"
+)
+
+func TestSQLiteTeamStore_SearchTasks_MatchesByEmbedding(t *testing.T) {
+	db, err := OpenDB(filepath.Join(t.TempDir(), "teams.db"))
+	if err != nil {
+		t.Fatalf("OpenDB error: %v", err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+	if err := EnsureSchema(db); err != nil {
+		t.Fatalf("EnsureSchema error: %v", err)
+	}
+	leadID := uuid.Must(uuid.NewV7())
+	if _, err := db.Exec(
+		`INSERT INTO agents (id, agent_key, owner_id, model, tenant_id) VALUES (?, 'lead', 'owner', 'm', ?)`,
+		leadID, store.MasterTenantID,
+	); err != nil {
+		t.Fatalf("insert agent: %v", err)
+	}
+	ctx := store.WithTenantID(context.Background(), store.MasterTenantID)
+	s := NewSQLiteTeamStore(db)
+	team := &store.TeamData{Name: "ops", LeadAgentID: leadID, Status: "active", CreatedBy: "owner"}
+	if err := s.CreateTeam(ctx, team); err != nil {
+		t.Fatalf("CreateTeam error: %v", err)
+	}
+	car := &store.TeamTaskData{TeamID: team.ID, Subject: "Service the sedan", Status: store.TeamTaskStatusPending, Metadata: map[string]any{"k": "v"}}
+	bike := &store.TeamTaskData{TeamID: team.ID, Subject: "Fix the bicycle", Status: store.TeamTaskStatusPending, Metadata: map[string]any{"k": "v"}}
+	if err := s.CreateTasks(ctx, []*store.TeamTaskData{car, bike}); err != nil {
+		t.Fatalf("CreateTasks error: %v", err)
+	}
+
+	s.SetEmbeddingProvider(&conceptEmbedder{})
+	if n, err := s.BackfillTaskEmbeddings(ctx); err != nil || n != 2 {
+		t.Fatalf("BackfillTaskEmbeddings = %d, %v; want 2", n, err)
+	}
+
+	// No text overlap with "automobile": only the vector leg can match.
+	tasks, err := s.SearchTasks(ctx, team.ID, "automobile", 1, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tasks) != 1 || tasks[0].ID != car.ID {
+		t.Fatalf("tasks = %+v", tasks)
+	}
+}
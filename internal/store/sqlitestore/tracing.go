@@ -185,6 +185,41 @@ func (s *SQLiteTracingStore) GetMonthlyAgentCost(ctx context.Context, agentID uu
 	return cost, err
 }
 
+func (s *SQLiteTracingStore) GetMonthlyUserCost(ctx context.Context, userID string, year int, month time.Month) (float64, error) {
+	return s.monthlyCost(ctx, "user_id = ?", []any{userID}, year, month)
+}
+
+func (s *SQLiteTracingStore) GetMonthlyTenantCost(ctx context.Context, year int, month time.Month) (float64, error) {
+	if store.TenantIDFromContext(ctx) == uuid.Nil && !store.IsCrossTenant(ctx) {
+		return 0, nil
+	}
+	return s.monthlyCost(ctx, "", nil, year, month)
+}
+
+// monthlyCost sums root-trace cost for a calendar month (UTC), filtered by an
+// optional condition plus the context tenant.
+func (s *SQLiteTracingStore) monthlyCost(ctx context.Context, cond string, args []any, year int, month time.Month) (float64, error) {
+	start := time.Date(year, month, 1, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 1, 0)
+	q := `SELECT COALESCE(SUM(total_cost), 0) FROM traces
+		 WHERE parent_trace_id IS NULL`
+	if cond != "" {
+		q += " AND " + cond
+	}
+	q += " AND created_at >= ? AND created_at < ?"
+	qArgs := append(args, start, end)
+	if !store.IsCrossTenant(ctx) {
+		tid := store.TenantIDFromContext(ctx)
+		if tid != uuid.Nil {
+			q += " AND tenant_id = ?"
+			qArgs = append(qArgs, tid)
+		}
+	}
+	var cost float64
+	err := s.db.QueryRowContext(ctx, q, qArgs...).Scan(&cost)
+	return cost, err
+}
+
 func (s *SQLiteTracingStore) GetCostSummary(ctx context.Context, opts store.CostSummaryOpts) ([]store.CostSummaryRow, error) {
 	var conditions []string
 	var args []any
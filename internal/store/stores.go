@@ -33,4 +33,7 @@ type Stores struct {
 	SkillTenantCfgs        SkillTenantConfigStore
 	SystemConfigs          SystemConfigStore
 	SubagentTasks          SubagentTaskStore
+	MessageQueue           MessageQueueStore
+	ContextFileRevisions   ContextFileRevisionStore
+	Evals                  EvalStore
 }
@@ -0,0 +1,95 @@
+package storetest
+
+import (
+	"testing"
+
+	"github.com/nextlevelbuilder/goclaw/internal/store"
+)
+
+// RunAgentLinks checks link CRUD, direction-aware delegation and target search.
+func RunAgentLinks(t *testing.T, stores *store.Stores) {
+	if stores.AgentLinks == nil {
+		t.Skip("AgentLinks store not available")
+	}
+	ctx := Context()
+	ls := stores.AgentLinks
+
+	lead := CreateAgent(t, stores, ctx, "lead")
+	worker := CreateAgent(t, stores, ctx, "worker")
+
+	link := &store.AgentLinkData{
+		SourceAgentID: lead.ID,
+		TargetAgentID: worker.ID,
+		Direction:     store.LinkDirectionOutbound,
+		Description:   "research helper",
+		MaxConcurrent: 2,
+		Status:        store.LinkStatusActive,
+		CreatedBy:     "storetest",
+	}
+	if err := ls.CreateLink(ctx, link); err != nil {
+		t.Fatalf("CreateLink: %v", err)
+	}
+
+	got, err := ls.GetLink(ctx, link.ID)
+	if err != nil {
+		t.Fatalf("GetLink: %v", err)
+	}
+	if got.SourceAgentID != lead.ID || got.TargetAgentID != worker.ID {
+		t.Errorf("GetLink = %s→%s, want %s→%s", got.SourceAgentID, got.TargetAgentID, lead.ID, worker.ID)
+	}
+	if got.MaxConcurrent != 2 {
+		t.Errorf("GetLink MaxConcurrent = %d, want 2", got.MaxConcurrent)
+	}
+
+	if ok, err := ls.CanDelegate(ctx, lead.ID, worker.ID); err != nil || !ok {
+		t.Errorf("CanDelegate(lead→worker) = %v, %v; want true", ok, err)
+	}
+	if ok, err := ls.CanDelegate(ctx, worker.ID, lead.ID); err != nil || ok {
+		t.Errorf("CanDelegate(worker→lead) = %v, %v; want false for outbound link", ok, err)
+	}
+
+	targets, err := ls.DelegateTargets(ctx, lead.ID)
+	if err != nil {
+		t.Fatalf("DelegateTargets: %v", err)
+	}
+	if len(targets) != 1 || targets[0].TargetAgentID != worker.ID {
+		t.Errorf("DelegateTargets = %+v, want single target %s", targets, worker.ID)
+	}
+
+	found, err := ls.SearchDelegateTargets(ctx, lead.ID, worker.AgentKey, 5)
+	if err != nil {
+		t.Fatalf("SearchDelegateTargets: %v", err)
+	}
+	if len(found) != 1 {
+		t.Errorf("SearchDelegateTargets(%q) returned %d results, want 1", worker.AgentKey, len(found))
+	}
+
+	if err := ls.UpdateLink(ctx, link.ID, map[string]any{"direction": store.LinkDirectionBidirectional}); err != nil {
+		t.Fatalf("UpdateLink: %v", err)
+	}
+	if ok, err := ls.CanDelegate(ctx, worker.ID, lead.ID); err != nil || !ok {
+		t.Errorf("CanDelegate(worker→lead) after bidirectional = %v, %v; want true", ok, err)
+	}
+	between, err := ls.GetLinkBetween(ctx, worker.ID, lead.ID)
+	if err != nil || between == nil || between.ID != link.ID {
+		t.Errorf("GetLinkBetween(worker, lead) = %+v, %v; want link %s", between, err, link.ID)
+	}
+
+	from, err := ls.ListLinksFrom(ctx, lead.ID)
+	if err != nil || len(from) != 1 {
+		t.Errorf("ListLinksFrom = %d links, %v; want 1", len(from), err)
+	} else if from[0].SourceAgentKey != lead.AgentKey || from[0].TargetAgentKey != worker.AgentKey {
+		t.Errorf("ListLinksFrom joined keys = %q→%q, want %q→%q", from[0].SourceAgentKey, from[0].TargetAgentKey, lead.AgentKey, worker.AgentKey)
+	}
+	to, err := ls.ListLinksTo(ctx, worker.ID)
+	if err != nil || len(to) != 1 {
+		t.Errorf("ListLinksTo = %d links, %v; want 1", len(to), err)
+	}
+
+	if err := ls.DeleteLink(ctx, link.ID); err != nil {
+		t.Fatalf("DeleteLink: %v", err)
+	}
+	if ok, _ := ls.CanDelegate(ctx, lead.ID, worker.ID); ok {
+		t.Error("CanDelegate after DeleteLink = true, want false")
+	}
+}
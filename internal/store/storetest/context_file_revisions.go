@@ -0,0 +1,75 @@
+package storetest
+
+import (
+	"testing"
+
+	"github.com/nextlevelbuilder/goclaw/internal/store"
+)
+
+// RunContextFileRevisions checks that context file writes are recorded as
+// revisions (skipping unchanged content, attributing the author) and the
+// pending change lifecycle.
+func RunContextFileRevisions(t *testing.T, stores *store.Stores) {
+	if stores.ContextFileRevisions == nil {
+		t.Skip("context file revision store not available")
+	}
+	ctx := Context()
+	rs := stores.ContextFileRevisions
+	ag := CreateAgent(t, stores, ctx, "revs")
+
+	adminCtx := store.WithContextFileAuthor(ctx, store.ContextFileAuthorAdmin, "alice")
+	for _, content := range []string{"v1", "v1", "v2"} {
+		if err := stores.Agents.SetAgentContextFile(adminCtx, ag.ID, "SOUL.md", content); err != nil {
+			t.Fatalf("SetAgentContextFile(%s): %v", content, err)
+		}
+	}
+	revs, err := rs.ListRevisions(ctx, ag.ID, "", "SOUL.md", 0)
+	if err != nil {
+		t.Fatalf("ListRevisions: %v", err)
+	}
+	if len(revs) != 2 || revs[0].Content != "v2" || revs[1].Content != "v1" {
+		t.Fatalf("revisions = %+v, want [v2 v1] (unchanged write skipped)", revs)
+	}
+	if revs[0].Author != store.ContextFileAuthorAdmin || revs[0].AuthorID != "alice" {
+		t.Errorf("author = %s/%s, want admin/alice", revs[0].Author, revs[0].AuthorID)
+	}
+	got, err := rs.GetRevision(ctx, revs[1].ID)
+	if err != nil || got == nil || got.Content != "v1" || got.FileName != "SOUL.md" {
+		t.Fatalf("GetRevision = %+v, %v", got, err)
+	}
+
+	// Per-user files are tracked separately; propagation records each changed copy.
+	if err := stores.Agents.SetUserContextFile(ctx, ag.ID, "bob", "SOUL.md", "bob's soul"); err != nil {
+		t.Fatalf("SetUserContextFile: %v", err)
+	}
+	if n, err := stores.Agents.PropagateContextFile(adminCtx, ag.ID, "SOUL.md"); err != nil || n != 1 {
+		t.Fatalf("PropagateContextFile = %d, %v", n, err)
+	}
+	userRevs, err := rs.ListRevisions(ctx, ag.ID, "bob", "SOUL.md", 10)
+	if err != nil || len(userRevs) != 2 || userRevs[0].Content != "v2" || userRevs[1].Author != store.ContextFileAuthorSystem {
+		t.Fatalf("user revisions = %+v, %v; want propagated v2 over system-written original", userRevs, err)
+	}
+
+	// Pending changes.
+	change := &store.ContextFileChange{AgentID: ag.ID, FileName: "IDENTITY.md", Content: "Name: Rex", Author: store.ContextFileAuthorAgent, AuthorID: "bob"}
+	if err := rs.CreatePendingChange(ctx, change); err != nil {
+		t.Fatalf("CreatePendingChange: %v", err)
+	}
+	pending, err := rs.ListPendingChanges(ctx, ag.ID)
+	if err != nil || len(pending) != 1 || pending[0].ID != change.ID || pending[0].Status != store.ContextFileChangePending {
+		t.Fatalf("ListPendingChanges = %+v, %v", pending, err)
+	}
+	if ok, err := rs.ResolvePendingChange(ctx, change.ID, store.ContextFileChangeRejected, "alice"); err != nil || !ok {
+		t.Fatalf("ResolvePendingChange = %v, %v", ok, err)
+	}
+	if ok, _ := rs.ResolvePendingChange(ctx, change.ID, store.ContextFileChangeApproved, "alice"); ok {
+		t.Error("resolved an already rejected change")
+	}
+	c, err := rs.GetPendingChange(ctx, change.ID)
+	if err != nil || c == nil || c.Status != store.ContextFileChangeRejected || c.ReviewedBy != "alice" || c.ReviewedAt == nil {
+		t.Fatalf("GetPendingChange = %+v, %v", c, err)
+	}
+	if pending, _ := rs.ListPendingChanges(ctx, ag.ID); len(pending) != 0 {
+		t.Errorf("pending after reject = %+v", pending)
+	}
+}
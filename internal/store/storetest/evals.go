@@ -0,0 +1,104 @@
+package storetest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/nextlevelbuilder/goclaw/internal/store"
+)
+
+// RunEvals checks eval suite CRUD (cases round-trip as JSON) and the run
+// lifecycle: create, update with results, list summaries and cascade delete.
+func RunEvals(t *testing.T, stores *store.Stores) {
+	if stores.Evals == nil {
+		t.Skip("eval store not available")
+	}
+	ctx := Context()
+	es := stores.Evals
+	ag := CreateAgent(t, stores, ctx, "eval")
+
+	suite := &store.EvalSuite{
+		AgentID: ag.ID,
+		Name:    "smoke",
+		Cases: []store.EvalCase{{
+			Name: "weather",
+			Turns: []store.EvalTurn{{
+				Message:     "weather in Hanoi?",
+				ExpectTools: []store.EvalToolExpectation{{Name: "web_search", Args: map[string]any{"query": "Hanoi weather"}}},
+				Assertions:  []store.EvalAssertion{{Type: store.EvalAssertContains, Value: "Hanoi"}},
+			}},
+			Stubs: []store.EvalToolStub{{Name: "web_search", Result: "31°C, sunny"}},
+		}},
+	}
+	if err := es.CreateSuite(ctx, suite); err != nil {
+		t.Fatalf("CreateSuite: %v", err)
+	}
+	got, err := es.GetSuite(ctx, suite.ID)
+	if err != nil || got == nil {
+		t.Fatalf("GetSuite = %+v, %v", got, err)
+	}
+	if len(got.Cases) != 1 || got.Cases[0].Stubs[0].Result != "31°C, sunny" || got.Cases[0].Turns[0].ExpectTools[0].Args["query"] != "Hanoi weather" {
+		t.Errorf("cases did not round-trip: %+v", got.Cases)
+	}
+
+	suite.Description = "updated"
+	suite.Cases = append(suite.Cases, store.EvalCase{Name: "greeting", Turns: []store.EvalTurn{{Message: "hi"}}})
+	if err := es.UpdateSuite(ctx, suite); err != nil {
+		t.Fatalf("UpdateSuite: %v", err)
+	}
+	suites, err := es.ListSuites(ctx, ag.ID)
+	if err != nil || len(suites) != 1 || suites[0].Description != "updated" || len(suites[0].Cases) != 2 {
+		t.Fatalf("ListSuites = %+v, %v", suites, err)
+	}
+
+	var runs []*store.EvalRun
+	for range 2 {
+		r := &store.EvalRun{SuiteID: suite.ID, AgentID: ag.ID, SuiteName: suite.Name, Status: store.EvalRunRunning, ToolMode: store.EvalToolsStub, Total: 2}
+		if err := es.CreateRun(ctx, r); err != nil {
+			t.Fatalf("CreateRun: %v", err)
+		}
+		runs = append(runs, r)
+	}
+	traceID := uuid.New()
+	finished := time.Now()
+	last := runs[1]
+	last.Status, last.Passed, last.Failed, last.CostUSD, last.FinishedAt = store.EvalRunCompleted, 1, 1, 0.0125, &finished
+	last.Results = []store.EvalCaseResult{{Name: "weather", Passed: true, Turns: []store.EvalTurnResult{{
+		Message: "weather in Hanoi?", Output: "Hanoi: 31°C", TraceID: &traceID,
+		ToolCalls: []store.EvalToolCall{{Name: "web_search", Stubbed: true}},
+		Checks:    []store.EvalCheck{{Kind: "tool", Passed: true}},
+	}}}}
+	if err := es.UpdateRun(ctx, last); err != nil {
+		t.Fatalf("UpdateRun: %v", err)
+	}
+	r, err := es.GetRun(ctx, last.ID)
+	if err != nil || r == nil {
+		t.Fatalf("GetRun = %+v, %v", r, err)
+	}
+	if r.Status != store.EvalRunCompleted || r.Passed != 1 || r.CostUSD != 0.0125 || r.FinishedAt == nil {
+		t.Errorf("run totals = %+v", r)
+	}
+	if len(r.Results) != 1 || r.Results[0].Turns[0].TraceID == nil || *r.Results[0].Turns[0].TraceID != traceID {
+		t.Errorf("results did not round-trip: %+v", r.Results)
+	}
+
+	list, err := es.ListRuns(ctx, ag.ID, suite.ID, 10)
+	if err != nil || len(list) != 2 || list[0].ID != last.ID || list[0].Results != nil {
+		t.Fatalf("ListRuns = %+v, %v; want newest first without results", list, err)
+	}
+	if all, _ := es.ListRuns(ctx, ag.ID, uuid.Nil, 1); len(all) != 1 {
+		t.Errorf("ListRuns limit 1 = %d runs", len(all))
+	}
+
+	if err := es.DeleteSuite(ctx, suite.ID); err != nil {
+		t.Fatalf("DeleteSuite: %v", err)
+	}
+	if s, _ := es.GetSuite(ctx, suite.ID); s != nil {
+		t.Error("suite still exists after delete")
+	}
+	if r, _ := es.GetRun(ctx, last.ID); r != nil {
+		t.Error("run survived suite delete")
+	}
+}
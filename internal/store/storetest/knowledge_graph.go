@@ -0,0 +1,115 @@
+package storetest
+
+import (
+	"testing"
+
+	"github.com/nextlevelbuilder/goclaw/internal/store"
+)
+
+// RunKnowledgeGraph checks entity upsert/search, relation traversal and merge.
+func RunKnowledgeGraph(t *testing.T, stores *store.Stores) {
+	if stores.KnowledgeGraph == nil {
+		t.Skip("KnowledgeGraph store not available")
+	}
+	ctx := Context()
+	kg := stores.KnowledgeGraph
+	ag := CreateAgent(t, stores, ctx, "kg")
+	agentID := ag.ID.String()
+	const userID = "storetest-user"
+
+	for _, e := range []store.Entity{
+		{ExternalID: "alice", Name: "Alice Nguyen", EntityType: "person", Description: "platform engineer", Confidence: 0.9},
+		{ExternalID: "goclaw", Name: "GoClaw", EntityType: "project", Description: "agent gateway", Confidence: 0.9},
+		{ExternalID: "golang", Name: "Go", EntityType: "language", Confidence: 0.9},
+		{ExternalID: "alice-dup", Name: "Alice Nguyen", EntityType: "person", Confidence: 0.5},
+	} {
+		e.AgentID, e.UserID = agentID, userID
+		if err := kg.UpsertEntity(ctx, &e); err != nil {
+			t.Fatalf("UpsertEntity(%s): %v", e.ExternalID, err)
+		}
+	}
+
+	entities, err := kg.ListEntities(ctx, agentID, userID, store.EntityListOptions{Limit: 10})
+	if err != nil {
+		t.Fatalf("ListEntities: %v", err)
+	}
+	ids := make(map[string]string, len(entities))
+	for _, e := range entities {
+		ids[e.ExternalID] = e.ID
+	}
+	if len(ids) != 4 {
+		t.Fatalf("ListEntities returned %d entities, want 4", len(ids))
+	}
+
+	got, err := kg.GetEntity(ctx, agentID, userID, ids["goclaw"])
+	if err != nil || got.Name != "GoClaw" {
+		t.Fatalf("GetEntity = %+v, %v; want GoClaw", got, err)
+	}
+
+	hits, err := kg.SearchEntities(ctx, agentID, userID, "gateway", 5)
+	if err != nil {
+		t.Fatalf("SearchEntities: %v", err)
+	}
+	if len(hits) != 1 || hits[0].ID != ids["goclaw"] {
+		t.Errorf("SearchEntities(gateway) = %+v, want GoClaw only", hits)
+	}
+
+	for _, r := range []store.Relation{
+		{SourceEntityID: ids["alice"], RelationType: "works_on", TargetEntityID: ids["goclaw"], Confidence: 0.9},
+		{SourceEntityID: ids["goclaw"], RelationType: "written_in", TargetEntityID: ids["golang"], Confidence: 0.9},
+		{SourceEntityID: ids["alice-dup"], RelationType: "uses", TargetEntityID: ids["golang"], Confidence: 0.5},
+	} {
+		r.AgentID, r.UserID = agentID, userID
+		if err := kg.UpsertRelation(ctx, &r); err != nil {
+			t.Fatalf("UpsertRelation(%s): %v", r.RelationType, err)
+		}
+	}
+
+	// alice → goclaw → golang, plus golang ← alice-dup reached via a reverse edge.
+	paths, err := kg.Traverse(ctx, agentID, userID, ids["alice"], 3, 0)
+	if err != nil {
+		t.Fatalf("Traverse: %v", err)
+	}
+	depth := make(map[string]int, len(paths))
+	for _, p := range paths {
+		depth[p.Entity.ExternalID] = p.Depth
+	}
+	if depth["goclaw"] != 2 || depth["golang"] != 3 {
+		t.Errorf("Traverse depths = %v, want goclaw=2 golang=3", depth)
+	}
+	if _, ok := depth["alice"]; ok {
+		t.Error("Traverse returned the start entity")
+	}
+
+	if err := kg.MergeEntities(ctx, agentID, userID, ids["alice"], ids["alice-dup"]); err != nil {
+		t.Fatalf("MergeEntities: %v", err)
+	}
+	if e, _ := kg.GetEntity(ctx, agentID, userID, ids["alice-dup"]); e != nil {
+		t.Error("merged source entity still exists")
+	}
+	rels, err := kg.ListRelations(ctx, agentID, userID, ids["alice"])
+	if err != nil {
+		t.Fatalf("ListRelations: %v", err)
+	}
+	if len(rels) != 2 {
+		t.Errorf("ListRelations after merge = %d, want 2 (works_on + re-pointed uses)", len(rels))
+	}
+
+	stats, err := kg.Stats(ctx, agentID, userID)
+	if err != nil {
+		t.Fatalf("Stats: %v", err)
+	}
+	if stats.EntityCount != 3 || stats.RelationCount != 3 {
+		t.Errorf("Stats = %d entities / %d relations, want 3 / 3", stats.EntityCount, stats.RelationCount)
+	}
+
+	if err := kg.DeleteEntity(ctx, agentID, userID, ids["golang"]); err != nil {
+		t.Fatalf("DeleteEntity: %v", err)
+	}
+	hits, _ = kg.SearchEntities(ctx, agentID, userID, "Go", 5)
+	for _, h := range hits {
+		if h.ID == ids["golang"] {
+			t.Error("deleted entity still returned by SearchEntities")
+		}
+	}
+}
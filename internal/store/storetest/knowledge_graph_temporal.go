@@ -0,0 +1,129 @@
+package storetest
+
+import (
+	"testing"
+
+	"github.com/nextlevelbuilder/goclaw/internal/store"
+)
+
+// RunKnowledgeGraphTemporal checks fact validity: supersession of exclusive
+// relations, ended facts, provenance, history and as-of traversal.
+func RunKnowledgeGraphTemporal(t *testing.T, stores *store.Stores) {
+	if stores.KnowledgeGraph == nil {
+		t.Skip("KnowledgeGraph store not available")
+	}
+	kg := stores.KnowledgeGraph
+	ag := CreateAgent(t, stores, Context(), "kgt")
+	agentID := ag.ID.String()
+	const userID = "storetest-user"
+	ctx := store.WithKGProvenance(Context(), store.KGProvenance{Kind: store.KGSourceMemory, SessionKey: "s1", Document: "memory/notes.md"})
+
+	ms := func(s string) int64 {
+		v, err := store.ParseKGTime(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return v
+	}
+	entities := []store.Entity{
+		{ExternalID: "alice", Name: "Alice", EntityType: "person", Confidence: 1},
+		{ExternalID: "hanoi", Name: "Hanoi", EntityType: "location", Confidence: 1},
+		{ExternalID: "berlin", Name: "Berlin", EntityType: "location", Confidence: 1},
+		{ExternalID: "acme", Name: "Acme", EntityType: "organization", Confidence: 1},
+	}
+	ingest := func(rels ...store.Relation) {
+		t.Helper()
+		if _, err := kg.IngestExtraction(ctx, agentID, userID, append([]store.Entity(nil), entities...), rels); err != nil {
+			t.Fatalf("IngestExtraction: %v", err)
+		}
+	}
+
+	ingest(
+		store.Relation{SourceEntityID: "alice", RelationType: "located_in", TargetEntityID: "hanoi", Confidence: 1, ValidFrom: ms("2020-03-01")},
+		store.Relation{SourceEntityID: "alice", RelationType: "works_on", TargetEntityID: "acme", Confidence: 1, ValidFrom: ms("2021-01-01")},
+	)
+	// Moving supersedes the exclusive located_in fact.
+	ingest(store.Relation{SourceEntityID: "alice", RelationType: "located_in", TargetEntityID: "berlin", Confidence: 1, ValidFrom: ms("2024-06-01")})
+	// An older, already superseded fact re-learned later is kept as history once.
+	ingest(store.Relation{SourceEntityID: "alice", RelationType: "located_in", TargetEntityID: "hanoi", Confidence: 1, ValidFrom: ms("2020-03-01")})
+
+	all, err := kg.ListEntities(ctx, agentID, userID, store.EntityListOptions{Limit: 10})
+	if err != nil {
+		t.Fatalf("ListEntities: %v", err)
+	}
+	ids := make(map[string]string, len(all))
+	for _, e := range all {
+		ids[e.ExternalID] = e.ID
+	}
+
+	current, err := kg.ListRelations(ctx, agentID, userID, ids["alice"])
+	if err != nil {
+		t.Fatalf("ListRelations: %v", err)
+	}
+	if len(current) != 2 {
+		t.Fatalf("current relations = %+v, want located_in berlin + works_on", current)
+	}
+	var berlinID string
+	for _, r := range current {
+		if r.TargetEntityID == ids["hanoi"] {
+			t.Errorf("superseded fact still current: %+v", r)
+		}
+		if r.TargetEntityID == ids["berlin"] {
+			berlinID = r.ID
+		}
+	}
+
+	history, err := kg.ListRelationHistory(ctx, agentID, userID, ids["alice"])
+	if err != nil {
+		t.Fatalf("ListRelationHistory: %v", err)
+	}
+	if len(history) != 3 {
+		t.Fatalf("history = %d rows, want 3", len(history))
+	}
+	if history[0].TargetEntityID != ids["berlin"] || history[0].ValidFrom != ms("2024-06-01") {
+		t.Errorf("newest fact = %+v, want berlin since 2024-06-01", history[0])
+	}
+	for _, r := range history {
+		if r.TargetEntityID == ids["hanoi"] && (r.ValidTo != ms("2024-06-01") || r.SupersededBy != berlinID) {
+			t.Errorf("hanoi fact = valid_to %d superseded_by %q, want closed by the berlin fact", r.ValidTo, r.SupersededBy)
+		}
+		if r.Provenance == nil || r.Provenance.Kind != store.KGSourceMemory || r.Provenance.Document != "memory/notes.md" {
+			t.Errorf("provenance = %+v, want memory/notes.md", r.Provenance)
+		}
+	}
+
+	reached := func(asOf int64) map[string]bool {
+		t.Helper()
+		paths, err := kg.Traverse(ctx, agentID, userID, ids["alice"], 2, asOf)
+		if err != nil {
+			t.Fatalf("Traverse(asOf=%d): %v", asOf, err)
+		}
+		out := make(map[string]bool, len(paths))
+		for _, p := range paths {
+			out[p.Entity.ExternalID] = true
+		}
+		return out
+	}
+	if got := reached(ms("2023-01-01")); !got["hanoi"] || got["berlin"] || !got["acme"] {
+		t.Errorf("Traverse as of 2023 reached %v, want hanoi + acme", got)
+	}
+	if got := reached(ms("2020-06-01")); !got["hanoi"] || got["acme"] {
+		t.Errorf("Traverse as of mid-2020 reached %v, want hanoi only", got)
+	}
+	if got := reached(0); got["hanoi"] || !got["berlin"] {
+		t.Errorf("Traverse now reached %v, want berlin + acme", got)
+	}
+
+	// An ended fact closes the current one.
+	ingest(store.Relation{SourceEntityID: "alice", RelationType: "works_on", TargetEntityID: "acme", Confidence: 1, ValidTo: ms("2025-01-01")})
+	current, _ = kg.ListRelations(ctx, agentID, userID, ids["alice"])
+	if len(current) != 1 || current[0].TargetEntityID != ids["berlin"] {
+		t.Errorf("current after ended fact = %+v, want berlin only", current)
+	}
+	if got := reached(ms("2024-12-01")); !got["acme"] {
+		t.Errorf("Traverse as of Dec 2024 reached %v, want acme before the fact ended", got)
+	}
+	if stats, err := kg.Stats(ctx, agentID, userID); err != nil || stats.RelationCount != 1 {
+		t.Errorf("Stats = %+v, %v; want 1 current relation", stats, err)
+	}
+}
@@ -0,0 +1,129 @@
+package storetest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/nextlevelbuilder/goclaw/internal/store"
+)
+
+// RunMCPOAuth checks OAuth client registration, per-user token round-trips
+// (encryption, upsert, expiry, delete) and pending authorizations for remote
+// MCP servers.
+func RunMCPOAuth(t *testing.T, stores *store.Stores) {
+	if stores.MCP == nil {
+		t.Skip("MCP store not available")
+	}
+	ctx := Context()
+	ms := stores.MCP
+
+	srv := &store.MCPServerData{
+		Name:       "oauth-" + uuid.NewString()[:8],
+		Transport:  "streamable-http",
+		URL:        "https://mcp.example.com/mcp",
+		TimeoutSec: 30,
+		Settings:   []byte(`{"oauth":{"enabled":true}}`),
+		Enabled:    true,
+		CreatedBy:  "storetest",
+	}
+	if err := ms.CreateServer(ctx, srv); err != nil {
+		t.Fatalf("CreateServer: %v", err)
+	}
+	t.Cleanup(func() { _ = ms.DeleteServer(ctx, srv.ID) })
+
+	if c, err := ms.GetOAuthClient(ctx, srv.ID); err != nil || c != nil {
+		t.Fatalf("GetOAuthClient before registration = %+v, %v; want nil, nil", c, err)
+	}
+	for _, c := range []store.MCPOAuthClient{
+		{ClientID: "first", RedirectURI: "http://localhost/cb"},
+		{ClientID: "second", ClientSecret: "s3cret", RedirectURI: "https://gw.example.com/cb"},
+	} {
+		if err := ms.SetOAuthClient(ctx, srv.ID, c); err != nil {
+			t.Fatalf("SetOAuthClient(%s): %v", c.ClientID, err)
+		}
+	}
+	c, err := ms.GetOAuthClient(ctx, srv.ID)
+	if err != nil || c == nil {
+		t.Fatalf("GetOAuthClient = %+v, %v", c, err)
+	}
+	if c.ClientID != "second" || c.ClientSecret != "s3cret" || c.RedirectURI != "https://gw.example.com/cb" {
+		t.Errorf("GetOAuthClient = %+v, want the replaced registration", c)
+	}
+
+	const userID = "storetest-user"
+	if tok, err := ms.GetOAuthToken(ctx, srv.ID, userID); err != nil || tok != nil {
+		t.Fatalf("GetOAuthToken before authorization = %+v, %v; want nil, nil", tok, err)
+	}
+	expires := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+	if err := ms.SetOAuthToken(ctx, srv.ID, userID, store.MCPOAuthToken{
+		AccessToken: "a1", RefreshToken: "r1", Scope: "read", ExpiresAt: expires,
+	}); err != nil {
+		t.Fatalf("SetOAuthToken: %v", err)
+	}
+	tok, err := ms.GetOAuthToken(ctx, srv.ID, userID)
+	if err != nil || tok == nil {
+		t.Fatalf("GetOAuthToken = %+v, %v", tok, err)
+	}
+	if tok.AccessToken != "a1" || tok.RefreshToken != "r1" || tok.TokenType != "Bearer" || tok.Scope != "read" {
+		t.Errorf("GetOAuthToken = %+v, want decrypted token with default type", tok)
+	}
+	if !tok.ExpiresAt.Equal(expires) {
+		t.Errorf("ExpiresAt = %v, want %v", tok.ExpiresAt, expires)
+	}
+
+	// Refresh without expiry: the row is replaced and expiry cleared.
+	if err := ms.SetOAuthToken(ctx, srv.ID, userID, store.MCPOAuthToken{AccessToken: "a2", TokenType: "bearer"}); err != nil {
+		t.Fatalf("SetOAuthToken (refresh): %v", err)
+	}
+	tok, err = ms.GetOAuthToken(ctx, srv.ID, userID)
+	if err != nil || tok == nil || tok.AccessToken != "a2" || tok.RefreshToken != "" || !tok.ExpiresAt.IsZero() {
+		t.Fatalf("GetOAuthToken after refresh = %+v, %v", tok, err)
+	}
+	if other, _ := ms.GetOAuthToken(ctx, srv.ID, "someone-else"); other != nil {
+		t.Errorf("token leaked to another user: %+v", other)
+	}
+
+	if err := ms.DeleteOAuthToken(ctx, srv.ID, userID); err != nil {
+		t.Fatalf("DeleteOAuthToken: %v", err)
+	}
+	if tok, err := ms.GetOAuthToken(ctx, srv.ID, userID); err != nil || tok != nil {
+		t.Errorf("GetOAuthToken after delete = %+v, %v; want nil, nil", tok, err)
+	}
+
+	pending := store.MCPOAuthPending{
+		State:     "state-" + uuid.NewString(),
+		TenantID:  store.TenantIDFromContext(ctx),
+		ServerID:  srv.ID,
+		UserID:    userID,
+		Verifier:  "v3rifier",
+		Channel:   "telegram",
+		ChatID:    "42",
+		ExpiresAt: time.Now().Add(10 * time.Minute),
+	}
+	expired := pending
+	expired.State = "state-" + uuid.NewString()
+	expired.ExpiresAt = time.Now().Add(-time.Minute)
+	for _, p := range []store.MCPOAuthPending{expired, pending} {
+		if err := ms.CreateOAuthPending(ctx, p); err != nil {
+			t.Fatalf("CreateOAuthPending: %v", err)
+		}
+	}
+	// The callback carries no tenant: take works from a bare context.
+	got, err := ms.TakeOAuthPending(context.Background(), pending.State)
+	if err != nil || got == nil {
+		t.Fatalf("TakeOAuthPending = %+v, %v", got, err)
+	}
+	if got.TenantID != pending.TenantID || got.ServerID != srv.ID || got.UserID != userID ||
+		got.Verifier != "v3rifier" || got.Channel != "telegram" || got.ChatID != "42" {
+		t.Errorf("TakeOAuthPending = %+v, want %+v", got, pending)
+	}
+	if got, err := ms.TakeOAuthPending(ctx, pending.State); err != nil || got != nil {
+		t.Errorf("second TakeOAuthPending = %+v, %v; want nil, nil", got, err)
+	}
+	if got, err := ms.TakeOAuthPending(ctx, expired.State); err != nil || got != nil {
+		t.Errorf("TakeOAuthPending(expired) = %+v, %v; want nil, nil", got, err)
+	}
+}
@@ -0,0 +1,164 @@
+package storetest
+
+import (
+	"encoding/json"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/nextlevelbuilder/goclaw/internal/store"
+)
+
+// RunMessageQueue checks the durable queue lifecycle: idempotent enqueue,
+// claiming due messages, retries up to the dead letter, crash recovery,
+// redrive and pruning.
+func RunMessageQueue(t *testing.T, stores *store.Stores) {
+	if stores.MessageQueue == nil {
+		t.Skip("MessageQueue store not available")
+	}
+	ctx := Context()
+	mq := stores.MessageQueue
+	owner := "storetest-" + uuid.NewString()[:8]
+	key := "telegram|u1|c1|" + uuid.NewString()
+
+	msg := &store.QueuedMessage{
+		Direction:      store.QueueDirectionInbound,
+		Channel:        "telegram",
+		IdempotencyKey: key,
+		Payload:        json.RawMessage(`{"content":"hello"}`),
+		Owner:          owner,
+	}
+	inserted, err := mq.Enqueue(ctx, msg)
+	if err != nil || !inserted {
+		t.Fatalf("Enqueue = %v, %v; want inserted", inserted, err)
+	}
+	dup := &store.QueuedMessage{Direction: store.QueueDirectionInbound, Channel: "telegram", IdempotencyKey: key, Payload: json.RawMessage(`{}`)}
+	if inserted, err := mq.Enqueue(ctx, dup); err != nil || inserted {
+		t.Fatalf("Enqueue duplicate = %v, %v; want not inserted", inserted, err)
+	}
+	// The same key in the other direction is independent.
+	other := &store.QueuedMessage{Direction: store.QueueDirectionOutbound, Channel: "telegram", IdempotencyKey: key, Payload: json.RawMessage(`{}`)}
+	if inserted, err := mq.Enqueue(ctx, other); err != nil || !inserted {
+		t.Fatalf("Enqueue other direction = %v, %v; want inserted", inserted, err)
+	}
+	if err := mq.Complete(ctx, other.ID); err != nil {
+		t.Fatalf("Complete: %v", err)
+	}
+
+	// Queued messages are not claimable until released.
+	if got := claimByID(t, mq, owner, msg.ID); got != nil {
+		t.Fatal("ClaimDue returned a queued message")
+	}
+	if err := mq.Release(ctx, msg.ID, time.Now().Add(-time.Second)); err != nil {
+		t.Fatalf("Release: %v", err)
+	}
+	got := claimByID(t, mq, owner, msg.ID)
+	if got == nil {
+		t.Fatal("ClaimDue did not return the released message")
+	}
+	if got.Status != store.QueueStatusQueued || got.Owner != owner || string(got.Payload) == "" {
+		t.Errorf("claimed = status %q owner %q payload %q", got.Status, got.Owner, got.Payload)
+	}
+
+	// Failed attempts retry until maxAttempts, then dead-letter.
+	status, err := mq.Fail(ctx, msg.ID, "boom", time.Now().Add(time.Hour), 2)
+	if err != nil || status != store.QueueStatusPending {
+		t.Fatalf("Fail #1 = %q, %v; want pending", status, err)
+	}
+	if got := claimByID(t, mq, owner, msg.ID); got != nil {
+		t.Fatal("ClaimDue returned a message before its retry time")
+	}
+	if err := mq.Release(ctx, msg.ID, time.Now()); err != nil { // no-op: not queued
+		t.Fatalf("Release pending: %v", err)
+	}
+	status, err = mq.Fail(ctx, msg.ID, "boom again", time.Now(), 2)
+	if err != nil || status != store.QueueStatusDead {
+		t.Fatalf("Fail #2 = %q, %v; want dead", status, err)
+	}
+
+	dead, total, err := mq.ListDead(ctx, store.QueueDirectionInbound, 50, 0)
+	if err != nil {
+		t.Fatalf("ListDead: %v", err)
+	}
+	found := findQueued(dead, msg.ID)
+	if found == nil || total < 1 {
+		t.Fatalf("ListDead missing message (total %d)", total)
+	}
+	if found.Attempts != 2 || found.LastError != "boom again" {
+		t.Errorf("dead letter = attempts %d error %q", found.Attempts, found.LastError)
+	}
+	if dead, _, _ := mq.ListDead(ctx, store.QueueDirectionOutbound, 50, 0); findQueued(dead, msg.ID) != nil {
+		t.Error("ListDead(outbound) returned an inbound message")
+	}
+
+	// Redrive makes it deliverable again with a fresh attempt budget.
+	if err := mq.Redrive(ctx, msg.ID); err != nil {
+		t.Fatalf("Redrive: %v", err)
+	}
+	if err := mq.Redrive(ctx, msg.ID); err == nil {
+		t.Error("Redrive of a non-dead message should fail")
+	}
+	got = claimByID(t, mq, owner, msg.ID)
+	if got == nil || got.Attempts != 0 {
+		t.Fatalf("claim after redrive = %+v", got)
+	}
+
+	// Crash recovery: queued messages of the owner become pending again.
+	if owners, err := mq.QueuedOwners(ctx); err != nil || !slices.Contains(owners, owner) {
+		t.Fatalf("QueuedOwners = %v, %v; want %q listed", owners, err, owner)
+	}
+	n, err := mq.RecoverOwned(ctx, owner, 5)
+	if err != nil || n < 1 {
+		t.Fatalf("RecoverOwned = %d, %v", n, err)
+	}
+	got = claimByID(t, mq, owner, msg.ID)
+	if got == nil || got.Attempts != 1 {
+		t.Fatalf("claim after recovery = %+v", got)
+	}
+	// An exhausted message is dead-lettered instead.
+	if _, err := mq.RecoverOwned(ctx, owner, 2); err != nil {
+		t.Fatalf("RecoverOwned: %v", err)
+	}
+	if dead, _, _ := mq.ListDead(ctx, "", 50, 0); findQueued(dead, msg.ID) == nil {
+		t.Error("exhausted message not dead-lettered on recovery")
+	}
+
+	if err := mq.Delete(ctx, msg.ID); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if dead, _, _ := mq.ListDead(ctx, "", 50, 0); findQueued(dead, msg.ID) != nil {
+		t.Error("deleted dead letter still listed")
+	}
+
+	// Done messages are pruned; the idempotency key is free again afterwards.
+	if _, err := mq.PruneDone(ctx, time.Now().Add(time.Minute)); err != nil {
+		t.Fatalf("PruneDone: %v", err)
+	}
+	again := &store.QueuedMessage{Direction: store.QueueDirectionOutbound, Channel: "telegram", IdempotencyKey: key, Payload: json.RawMessage(`{}`)}
+	if inserted, err := mq.Enqueue(ctx, again); err != nil || !inserted {
+		t.Fatalf("Enqueue after prune = %v, %v; want inserted", inserted, err)
+	}
+	_ = mq.Complete(ctx, again.ID)
+	_, _ = mq.PruneDone(ctx, time.Now().Add(time.Minute))
+}
+
+// claimByID claims due messages and returns the one with id, if any.
+func claimByID(t *testing.T, mq store.MessageQueueStore, owner string, id uuid.UUID) *store.QueuedMessage {
+	t.Helper()
+	claimed, err := mq.ClaimDue(Context(), owner, 1000)
+	if err != nil {
+		t.Fatalf("ClaimDue: %v", err)
+	}
+	return findQueued(claimed, id)
+}
+
+func findQueued(msgs []store.QueuedMessage, id uuid.UUID) *store.QueuedMessage {
+	for i := range msgs {
+		if msgs[i].ID == id {
+			return &msgs[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,112 @@
+package storetest
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/nextlevelbuilder/goclaw/internal/store"
+)
+
+// RunSecureCLI checks env encryption round-trips, agent-over-global lookup
+// priority and per-user credential overrides. The backend must be configured
+// with an encryption key.
+func RunSecureCLI(t *testing.T, stores *store.Stores) {
+	if stores.SecureCLI == nil {
+		t.Skip("SecureCLI store not available")
+	}
+	ctx := Context()
+	sc := stores.SecureCLI
+	ag := CreateAgent(t, stores, ctx, "cli")
+	name := "gh-" + uuid.NewString()[:8]
+
+	global := &store.SecureCLIBinary{
+		BinaryName:     name,
+		Description:    "GitHub CLI",
+		EncryptedEnv:   []byte(`{"GH_TOKEN":"global"}`),
+		TimeoutSeconds: 30,
+		Enabled:        true,
+		CreatedBy:      "storetest",
+	}
+	if err := sc.Create(ctx, global); err != nil {
+		t.Fatalf("Create global: %v", err)
+	}
+	t.Cleanup(func() { _ = sc.Delete(ctx, global.ID) })
+
+	got, err := sc.Get(ctx, global.ID)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if string(got.EncryptedEnv) != `{"GH_TOKEN":"global"}` {
+		t.Errorf("Get env = %q, want decrypted plaintext", got.EncryptedEnv)
+	}
+
+	agentID := ag.ID
+	scoped := &store.SecureCLIBinary{
+		BinaryName:     name,
+		EncryptedEnv:   []byte(`{"GH_TOKEN":"agent"}`),
+		TimeoutSeconds: 30,
+		AgentID:        &agentID,
+		Enabled:        true,
+		CreatedBy:      "storetest",
+	}
+	if err := sc.Create(ctx, scoped); err != nil {
+		t.Fatalf("Create agent-scoped: %v", err)
+	}
+
+	b, err := sc.LookupByBinary(ctx, name, &agentID, "")
+	if err != nil || b == nil || b.ID != scoped.ID {
+		t.Fatalf("LookupByBinary(agent) = %+v, %v; want agent-scoped config", b, err)
+	}
+	b, err = sc.LookupByBinary(ctx, name, nil, "")
+	if err != nil || b == nil || b.ID != global.ID {
+		t.Fatalf("LookupByBinary(global) = %+v, %v; want global config", b, err)
+	}
+
+	const userID = "storetest-user"
+	if err := sc.SetUserCredentials(ctx, global.ID, userID, []byte(`{"GH_TOKEN":"v1"}`)); err != nil {
+		t.Fatalf("SetUserCredentials: %v", err)
+	}
+	if err := sc.SetUserCredentials(ctx, global.ID, userID, []byte(`{"GH_TOKEN":"mine"}`)); err != nil {
+		t.Fatalf("SetUserCredentials (overwrite): %v", err)
+	}
+	uc, err := sc.GetUserCredentials(ctx, global.ID, userID)
+	if err != nil || uc == nil || string(uc.EncryptedEnv) != `{"GH_TOKEN":"mine"}` {
+		t.Fatalf("GetUserCredentials = %+v, %v; want overwritten env", uc, err)
+	}
+	list, err := sc.ListUserCredentials(ctx, global.ID)
+	if err != nil || len(list) != 1 {
+		t.Errorf("ListUserCredentials = %d, %v; want 1", len(list), err)
+	}
+
+	b, err = sc.LookupByBinary(ctx, name, nil, userID)
+	if err != nil || b == nil {
+		t.Fatalf("LookupByBinary(user) = %v, %v", b, err)
+	}
+	if string(b.UserEnv) != `{"GH_TOKEN":"mine"}` {
+		t.Errorf("LookupByBinary UserEnv = %q, want per-user env", b.UserEnv)
+	}
+
+	if err := sc.Update(ctx, scoped.ID, map[string]any{"enabled": false}); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	enabled, err := sc.ListByAgent(ctx, agentID)
+	if err != nil {
+		t.Fatalf("ListByAgent: %v", err)
+	}
+	for _, e := range enabled {
+		if e.ID == scoped.ID {
+			t.Error("ListByAgent returned a disabled config")
+		}
+	}
+
+	if err := sc.DeleteUserCredentials(ctx, global.ID, userID); err != nil {
+		t.Fatalf("DeleteUserCredentials: %v", err)
+	}
+	if uc, _ := sc.GetUserCredentials(ctx, global.ID, userID); uc != nil {
+		t.Error("GetUserCredentials after delete returned a row")
+	}
+	if err := sc.Delete(ctx, scoped.ID); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+}
@@ -0,0 +1,55 @@
+// Package storetest is a backend-agnostic conformance suite for store
+// implementations. Each backend (PG, SQLite) runs the same checks against a
+// live *store.Stores from its own _test.go, so behavioural drift between the
+// two shows up as a test failure rather than a production surprise.
+package storetest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/nextlevelbuilder/goclaw/internal/store"
+)
+
+// Run executes every conformance check against stores. Stores that are nil
+// on the given backend are skipped.
+func Run(t *testing.T, stores *store.Stores) {
+	t.Helper()
+	t.Run("AgentLinks", func(t *testing.T) { RunAgentLinks(t, stores) })
+	t.Run("ContextFileRevisions", func(t *testing.T) { RunContextFileRevisions(t, stores) })
+	t.Run("Evals", func(t *testing.T) { RunEvals(t, stores) })
+	t.Run("KnowledgeGraph", func(t *testing.T) { RunKnowledgeGraph(t, stores) })
+	t.Run("KnowledgeGraphTemporal", func(t *testing.T) { RunKnowledgeGraphTemporal(t, stores) })
+	t.Run("MCPOAuth", func(t *testing.T) { RunMCPOAuth(t, stores) })
+	t.Run("MessageQueue", func(t *testing.T) { RunMessageQueue(t, stores) })
+	t.Run("SecureCLI", func(t *testing.T) { RunSecureCLI(t, stores) })
+	t.Run("SubagentTasks", func(t *testing.T) { RunSubagentTasks(t, stores) })
+}
+
+// Context returns a master-tenant context, the scope every check runs in.
+func Context() context.Context {
+	return store.WithTenantID(context.Background(), store.MasterTenantID)
+}
+
+// CreateAgent inserts a throwaway agent with a unique key and returns it.
+// Keys are randomised so the suite can rerun against a persistent database.
+func CreateAgent(t *testing.T, stores *store.Stores, ctx context.Context, prefix string) *store.AgentData {
+	t.Helper()
+	ag := &store.AgentData{
+		TenantID:    store.MasterTenantID,
+		AgentKey:    prefix + "-" + uuid.NewString()[:8],
+		DisplayName: prefix,
+		OwnerID:     "storetest",
+		Provider:    "openai",
+		Model:       "gpt-4o-mini",
+		AgentType:   store.AgentTypePredefined,
+		Status:      store.AgentStatusActive,
+	}
+	if err := stores.Agents.Create(ctx, ag); err != nil {
+		t.Fatalf("create agent %s: %v", ag.AgentKey, err)
+	}
+	t.Cleanup(func() { _ = stores.Agents.Delete(ctx, ag.ID) })
+	return ag
+}
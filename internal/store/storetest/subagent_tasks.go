@@ -0,0 +1,90 @@
+package storetest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/nextlevelbuilder/goclaw/internal/store"
+)
+
+// RunSubagentTasks checks the subagent task lifecycle: create, status update,
+// metadata merge and parent/session listing.
+func RunSubagentTasks(t *testing.T, stores *store.Stores) {
+	if stores.SubagentTasks == nil {
+		t.Skip("SubagentTasks store not available")
+	}
+	ctx := Context()
+	ts := stores.SubagentTasks
+	parent := "parent-" + uuid.NewString()[:8]
+	session := "session-" + uuid.NewString()[:8]
+
+	task := &store.SubagentTaskData{
+		ParentAgentKey: parent,
+		SessionKey:     &session,
+		Subject:        "summarise",
+		Description:    "summarise the changelog",
+		Status:         "running",
+		Depth:          1,
+		Metadata:       map[string]any{"origin": "storetest"},
+	}
+	task.ID = store.GenNewID()
+	if err := ts.Create(ctx, task); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	// Create is idempotent on ID.
+	if err := ts.Create(ctx, task); err != nil {
+		t.Fatalf("Create (duplicate): %v", err)
+	}
+
+	got, err := ts.Get(ctx, task.ID)
+	if err != nil || got == nil {
+		t.Fatalf("Get = %v, %v", got, err)
+	}
+	if got.Status != "running" || got.CompletedAt != nil {
+		t.Errorf("Get status = %q completed=%v, want running/nil", got.Status, got.CompletedAt)
+	}
+
+	result := "done"
+	if err := ts.UpdateStatus(ctx, task.ID, "completed", &result, 3, 100, 50); err != nil {
+		t.Fatalf("UpdateStatus: %v", err)
+	}
+	if err := ts.UpdateMetadata(ctx, task.ID, map[string]any{"cost": 1.5}); err != nil {
+		t.Fatalf("UpdateMetadata: %v", err)
+	}
+
+	got, err = ts.Get(ctx, task.ID)
+	if err != nil || got == nil {
+		t.Fatalf("Get after update = %v, %v", got, err)
+	}
+	if got.Status != "completed" || got.CompletedAt == nil || got.Iterations != 3 || got.OutputTokens != 50 {
+		t.Errorf("Get after update = %+v, want completed with counters", got)
+	}
+	if got.Result == nil || *got.Result != result {
+		t.Errorf("Result = %v, want %q", got.Result, result)
+	}
+	if got.Metadata["origin"] != "storetest" || got.Metadata["cost"] != 1.5 {
+		t.Errorf("Metadata = %v, want merged origin+cost", got.Metadata)
+	}
+
+	byParent, err := ts.ListByParent(ctx, parent, "completed")
+	if err != nil || len(byParent) != 1 {
+		t.Errorf("ListByParent(completed) = %d, %v; want 1", len(byParent), err)
+	}
+	if running, _ := ts.ListByParent(ctx, parent, "running"); len(running) != 0 {
+		t.Errorf("ListByParent(running) = %d, want 0", len(running))
+	}
+	bySession, err := ts.ListBySession(ctx, session)
+	if err != nil || len(bySession) != 1 {
+		t.Errorf("ListBySession = %d, %v; want 1", len(bySession), err)
+	}
+
+	// A negative age puts the cutoff in the future so the fresh task qualifies.
+	if n, err := ts.Archive(ctx, -time.Minute); err != nil || n < 1 {
+		t.Errorf("Archive = %d, %v; want >= 1", n, err)
+	}
+	if got, _ := ts.Get(ctx, task.ID); got == nil || got.ArchivedAt == nil {
+		t.Error("task not archived")
+	}
+}
@@ -212,6 +212,9 @@ type TeamCRUDStore interface {
 // TaskStore manages task CRUD, lifecycle transitions, and progress.
 type TaskStore interface {
 	CreateTask(ctx context.Context, task *TeamTaskData) error
+	// CreateTasks inserts a batch of tasks atomically (all or none). IDs may be
+	// pre-set so tasks in the batch can reference each other in BlockedBy.
+	CreateTasks(ctx context.Context, tasks []*TeamTaskData) error
 	UpdateTask(ctx context.Context, taskID uuid.UUID, updates map[string]any) error
 	ListTasks(ctx context.Context, teamID uuid.UUID, orderBy string, statusFilter string, userID string, channel string, chatID string, limit int, offset int) ([]TeamTaskData, error)
 	GetTask(ctx context.Context, taskID uuid.UUID) (*TeamTaskData, error)
@@ -0,0 +1,338 @@
+package store
+
+import (
+	"encoding/json"
+	"sort"
+
+	"github.com/google/uuid"
+)
+
+// Task metadata keys used by the dependency graph.
+const (
+	// TaskMetaOriginalBlockedBy preserves the blocked_by list at creation time.
+	// BlockedBy shrinks as prerequisites resolve; this key keeps the full edge set.
+	TaskMetaOriginalBlockedBy = "original_blocked_by"
+	// TaskMetaEstimateMinutes is an optional effort estimate used as the node
+	// weight for critical-path computation (default 1).
+	TaskMetaEstimateMinutes = "estimate_minutes"
+)
+
+// Dependency policy values for team settings "dependency_policy".
+const (
+	TaskDependencyUnblock = "unblock" // dependents proceed without the prerequisite (default)
+	TaskDependencyCascade = "cascade" // dependents inherit the prerequisite's terminal status
+)
+
+// TaskDependencyPolicy controls what happens to waiting dependents when a
+// prerequisite fails or is cancelled. Completion always unblocks.
+type TaskDependencyPolicy struct {
+	OnFailure string `json:"on_failure,omitempty"`
+	OnCancel  string `json:"on_cancel,omitempty"`
+}
+
+// ParseTaskDependencyPolicy reads settings.dependency_policy from team settings.
+// Missing or unknown values fall back to "unblock" (legacy behavior).
+func ParseTaskDependencyPolicy(settings json.RawMessage) TaskDependencyPolicy {
+	p := TaskDependencyPolicy{OnFailure: TaskDependencyUnblock, OnCancel: TaskDependencyUnblock}
+	if len(settings) == 0 {
+		return p
+	}
+	var s struct {
+		Policy *TaskDependencyPolicy `json:"dependency_policy"`
+	}
+	if json.Unmarshal(settings, &s) != nil || s.Policy == nil {
+		return p
+	}
+	if s.Policy.OnFailure == TaskDependencyCascade {
+		p.OnFailure = TaskDependencyCascade
+	}
+	if s.Policy.OnCancel == TaskDependencyCascade {
+		p.OnCancel = TaskDependencyCascade
+	}
+	return p
+}
+
+// Cascades reports whether dependents of a task that ended with status should
+// end the same way instead of being unblocked.
+func (p TaskDependencyPolicy) Cascades(status string) bool {
+	switch status {
+	case TeamTaskStatusFailed:
+		return p.OnFailure == TaskDependencyCascade
+	case TeamTaskStatusCancelled:
+		return p.OnCancel == TaskDependencyCascade
+	}
+	return false
+}
+
+// IsTerminalTaskStatus reports whether a task status is final.
+func IsTerminalTaskStatus(status string) bool {
+	switch status {
+	case TeamTaskStatusCompleted, TeamTaskStatusFailed, TeamTaskStatusCancelled:
+		return true
+	}
+	return false
+}
+
+// TaskDependencies returns the prerequisite IDs of a task: the creation-time
+// list from metadata merged with any still-unresolved BlockedBy entries.
+func TaskDependencies(t *TeamTaskData) []uuid.UUID {
+	seen := make(map[uuid.UUID]bool)
+	var out []uuid.UUID
+	add := func(id uuid.UUID) {
+		if id != uuid.Nil && !seen[id] {
+			seen[id] = true
+			out = append(out, id)
+		}
+	}
+	if raw, ok := t.Metadata[TaskMetaOriginalBlockedBy].([]any); ok {
+		for _, v := range raw {
+			if s, ok := v.(string); ok {
+				if id, err := uuid.Parse(s); err == nil {
+					add(id)
+				}
+			}
+		}
+	} else if raw, ok := t.Metadata[TaskMetaOriginalBlockedBy].([]string); ok {
+		for _, s := range raw {
+			if id, err := uuid.Parse(s); err == nil {
+				add(id)
+			}
+		}
+	}
+	for _, id := range t.BlockedBy {
+		add(id)
+	}
+	return out
+}
+
+// FindDependencyCycle returns one cycle in deps (task → prerequisites) as an
+// ordered list where each task depends on the next and the last depends on the
+// first. Returns nil when the graph is acyclic. Prerequisites missing from deps
+// are treated as leaves.
+func FindDependencyCycle(deps map[uuid.UUID][]uuid.UUID) []uuid.UUID {
+	const (
+		white = iota
+		grey
+		black
+	)
+	color := make(map[uuid.UUID]int, len(deps))
+	var stack []uuid.UUID
+
+	var visit func(id uuid.UUID) []uuid.UUID
+	visit = func(id uuid.UUID) []uuid.UUID {
+		color[id] = grey
+		stack = append(stack, id)
+		for _, dep := range deps[id] {
+			switch color[dep] {
+			case grey:
+				for i := len(stack) - 1; i >= 0; i-- {
+					if stack[i] == dep {
+						return append([]uuid.UUID(nil), stack[i:]...)
+					}
+				}
+			case white:
+				if cycle := visit(dep); cycle != nil {
+					return cycle
+				}
+			}
+		}
+		stack = stack[:len(stack)-1]
+		color[id] = black
+		return nil
+	}
+
+	for _, id := range sortedIDs(deps) {
+		if color[id] == white {
+			if cycle := visit(id); cycle != nil {
+				return cycle
+			}
+		}
+	}
+	return nil
+}
+
+// TaskGraphNode is one task in a dependency graph.
+type TaskGraphNode struct {
+	ID            uuid.UUID  `json:"id"`
+	Identifier    string     `json:"identifier,omitempty"`
+	TaskNumber    int        `json:"task_number,omitempty"`
+	Subject       string     `json:"subject"`
+	Status        string     `json:"status"`
+	Priority      int        `json:"priority"`
+	OwnerAgentKey string     `json:"owner_agent_key,omitempty"`
+	ParentID      *uuid.UUID `json:"parent_id,omitempty"`
+	Weight        int        `json:"weight"`   // estimate_minutes, default 1
+	Depth         int        `json:"depth"`    // longest prerequisite chain above this node
+	Critical      bool       `json:"critical"` // on the critical path
+}
+
+// Task graph edge kinds.
+const (
+	TaskEdgeBlocks = "blocks" // From must finish before To starts
+	TaskEdgeParent = "parent" // From is the parent of subtask To
+)
+
+// TaskGraphEdge is a directed edge between two tasks.
+type TaskGraphEdge struct {
+	From      uuid.UUID `json:"from"`
+	To        uuid.UUID `json:"to"`
+	Kind      string    `json:"kind"`
+	Satisfied bool      `json:"satisfied"` // prerequisite completed (blocks edges only)
+}
+
+// TaskGraph is the dependency graph of a set of team tasks.
+type TaskGraph struct {
+	Nodes []TaskGraphNode `json:"nodes"`
+	Edges []TaskGraphEdge `json:"edges"`
+	// CriticalPath is the heaviest chain of unfinished tasks, in execution order.
+	CriticalPath       []uuid.UUID `json:"critical_path"`
+	CriticalPathWeight int         `json:"critical_path_weight"`
+	// Cycle is set when the stored dependencies contain a cycle (depth and
+	// critical path are then computed on the acyclic remainder).
+	Cycle []uuid.UUID `json:"cycle,omitempty"`
+}
+
+// BuildTaskGraph builds nodes, edges and the critical path for tasks.
+// Edges that reference tasks outside the set are dropped.
+func BuildTaskGraph(tasks []TeamTaskData) *TaskGraph {
+	g := &TaskGraph{Nodes: make([]TaskGraphNode, 0, len(tasks)), Edges: []TaskGraphEdge{}, CriticalPath: []uuid.UUID{}}
+	index := make(map[uuid.UUID]int, len(tasks))
+	for i := range tasks {
+		index[tasks[i].ID] = i
+	}
+
+	deps := make(map[uuid.UUID][]uuid.UUID, len(tasks))
+	for i := range tasks {
+		t := &tasks[i]
+		g.Nodes = append(g.Nodes, TaskGraphNode{
+			ID:            t.ID,
+			Identifier:    t.Identifier,
+			TaskNumber:    t.TaskNumber,
+			Subject:       t.Subject,
+			Status:        t.Status,
+			Priority:      t.Priority,
+			OwnerAgentKey: t.OwnerAgentKey,
+			ParentID:      t.ParentID,
+			Weight:        taskWeight(t),
+		})
+		deps[t.ID] = nil
+		for _, dep := range TaskDependencies(t) {
+			j, ok := index[dep]
+			if !ok || dep == t.ID {
+				continue
+			}
+			deps[t.ID] = append(deps[t.ID], dep)
+			g.Edges = append(g.Edges, TaskGraphEdge{
+				From:      dep,
+				To:        t.ID,
+				Kind:      TaskEdgeBlocks,
+				Satisfied: tasks[j].Status == TeamTaskStatusCompleted,
+			})
+		}
+		if t.ParentID != nil {
+			if _, ok := index[*t.ParentID]; ok {
+				g.Edges = append(g.Edges, TaskGraphEdge{From: *t.ParentID, To: t.ID, Kind: TaskEdgeParent})
+			}
+		}
+	}
+
+	g.Cycle = FindDependencyCycle(deps)
+	order := topoOrder(deps)
+
+	// Depth over all nodes; critical path over unfinished nodes only.
+	depth := make(map[uuid.UUID]int, len(order))
+	dist := make(map[uuid.UUID]int, len(order))
+	prev := make(map[uuid.UUID]uuid.UUID, len(order))
+	var end uuid.UUID
+	for _, id := range order {
+		node := &g.Nodes[index[id]]
+		level, best, bestPrev := 0, 0, uuid.Nil
+		for _, dep := range deps[id] {
+			if d, ok := depth[dep]; ok && d+1 > level {
+				level = d + 1
+			}
+			if d, ok := dist[dep]; ok && d > best {
+				best, bestPrev = d, dep
+			}
+		}
+		depth[id] = level
+		node.Depth = level
+		if IsTerminalTaskStatus(node.Status) {
+			continue
+		}
+		dist[id] = best + node.Weight
+		if bestPrev != uuid.Nil {
+			prev[id] = bestPrev
+		}
+		if dist[id] > g.CriticalPathWeight {
+			g.CriticalPathWeight, end = dist[id], id
+		}
+	}
+	for id := end; id != uuid.Nil; id = prev[id] {
+		g.CriticalPath = append(g.CriticalPath, id)
+		g.Nodes[index[id]].Critical = true
+	}
+	for i, j := 0, len(g.CriticalPath)-1; i < j; i, j = i+1, j-1 {
+		g.CriticalPath[i], g.CriticalPath[j] = g.CriticalPath[j], g.CriticalPath[i]
+	}
+	return g
+}
+
+// taskWeight returns the critical-path weight of a task.
+func taskWeight(t *TeamTaskData) int {
+	if v, ok := t.Metadata[TaskMetaEstimateMinutes].(float64); ok && v >= 1 {
+		return int(v)
+	}
+	if v, ok := t.Metadata[TaskMetaEstimateMinutes].(int); ok && v >= 1 {
+		return v
+	}
+	return 1
+}
+
+// topoOrder returns the nodes of deps in prerequisite-first order (Kahn's
+// algorithm). Nodes on a cycle are omitted.
+func topoOrder(deps map[uuid.UUID][]uuid.UUID) []uuid.UUID {
+	inDegree := make(map[uuid.UUID]int, len(deps))
+	dependents := make(map[uuid.UUID][]uuid.UUID, len(deps))
+	for id, ds := range deps {
+		if _, ok := inDegree[id]; !ok {
+			inDegree[id] = 0
+		}
+		for _, dep := range ds {
+			if _, ok := deps[dep]; !ok {
+				continue
+			}
+			inDegree[id]++
+			dependents[dep] = append(dependents[dep], id)
+		}
+	}
+	var queue []uuid.UUID
+	for _, id := range sortedIDs(deps) {
+		if inDegree[id] == 0 {
+			queue = append(queue, id)
+		}
+	}
+	order := make([]uuid.UUID, 0, len(deps))
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		order = append(order, id)
+		for _, next := range dependents[id] {
+			inDegree[next]--
+			if inDegree[next] == 0 {
+				queue = append(queue, next)
+			}
+		}
+	}
+	return order
+}
+
+// sortedIDs returns the keys of m in a stable order.
+func sortedIDs(m map[uuid.UUID][]uuid.UUID) []uuid.UUID {
+	ids := make([]uuid.UUID, 0, len(m))
+	for id := range m {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i].String() < ids[j].String() })
+	return ids
+}
@@ -0,0 +1,133 @@
+package store
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestParseTaskDependencyPolicy(t *testing.T) {
+	tests := []struct {
+		name             string
+		settings         string
+		failed, canceled bool
+	}{
+		{"empty", ``, false, false},
+		{"no_policy", `{"version":2}`, false, false},
+		{"cascade_failure", `{"dependency_policy":{"on_failure":"cascade"}}`, true, false},
+		{"cascade_both", `{"dependency_policy":{"on_failure":"cascade","on_cancel":"cascade"}}`, true, true},
+		{"unknown_value", `{"dependency_policy":{"on_failure":"explode"}}`, false, false},
+		{"invalid_json", `{`, false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := ParseTaskDependencyPolicy(json.RawMessage(tt.settings))
+			if got := p.Cascades(TeamTaskStatusFailed); got != tt.failed {
+				t.Errorf("Cascades(failed) = %v, want %v", got, tt.failed)
+			}
+			if got := p.Cascades(TeamTaskStatusCancelled); got != tt.canceled {
+				t.Errorf("Cascades(cancelled) = %v, want %v", got, tt.canceled)
+			}
+			if p.Cascades(TeamTaskStatusCompleted) {
+				t.Error("completion must never cascade")
+			}
+		})
+	}
+}
+
+func TestFindDependencyCycle(t *testing.T) {
+	a, b, c, d := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+
+	acyclic := map[uuid.UUID][]uuid.UUID{
+		a: nil,
+		b: {a},
+		c: {a, b},
+		d: {uuid.New()}, // prerequisite outside the set
+	}
+	if cycle := FindDependencyCycle(acyclic); cycle != nil {
+		t.Fatalf("expected no cycle, got %v", cycle)
+	}
+
+	cyclic := map[uuid.UUID][]uuid.UUID{
+		a: {c},
+		b: {a},
+		c: {b},
+		d: {a},
+	}
+	cycle := FindDependencyCycle(cyclic)
+	if len(cycle) != 3 {
+		t.Fatalf("expected 3-node cycle, got %v", cycle)
+	}
+	for i, id := range cycle {
+		next := cycle[(i+1)%len(cycle)]
+		found := false
+		for _, dep := range cyclic[id] {
+			if dep == next {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("cycle step %s → %s is not a dependency edge", id, next)
+		}
+	}
+
+	if cycle := FindDependencyCycle(map[uuid.UUID][]uuid.UUID{a: {a}}); len(cycle) != 1 {
+		t.Errorf("expected self-cycle, got %v", cycle)
+	}
+}
+
+func TestBuildTaskGraph(t *testing.T) {
+	// design → (api, ui) → release; api is long, design already done.
+	design := TeamTaskData{Subject: "design", Status: TeamTaskStatusCompleted}
+	api := TeamTaskData{Subject: "api", Status: TeamTaskStatusPending, Metadata: map[string]any{TaskMetaEstimateMinutes: float64(30)}}
+	ui := TeamTaskData{Subject: "ui", Status: TeamTaskStatusPending, Metadata: map[string]any{TaskMetaEstimateMinutes: float64(10)}}
+	release := TeamTaskData{Subject: "release", Status: TeamTaskStatusBlocked}
+	for _, task := range []*TeamTaskData{&design, &api, &ui, &release} {
+		task.ID = uuid.New()
+	}
+	// api/ui were created blocked on design; it has since completed and been removed from BlockedBy.
+	api.Metadata[TaskMetaOriginalBlockedBy] = []any{design.ID.String()}
+	ui.Metadata[TaskMetaOriginalBlockedBy] = []any{design.ID.String()}
+	release.BlockedBy = []uuid.UUID{api.ID, ui.ID}
+	sub := TeamTaskData{Subject: "sub", Status: TeamTaskStatusPending, ParentID: &release.ID}
+	sub.ID = uuid.New()
+
+	g := BuildTaskGraph([]TeamTaskData{design, api, ui, release, sub})
+
+	if len(g.Nodes) != 5 {
+		t.Fatalf("nodes = %d, want 5", len(g.Nodes))
+	}
+	var blocks, parents, satisfied int
+	for _, e := range g.Edges {
+		switch e.Kind {
+		case TaskEdgeBlocks:
+			blocks++
+			if e.Satisfied {
+				satisfied++
+			}
+		case TaskEdgeParent:
+			parents++
+		}
+	}
+	if blocks != 4 || parents != 1 || satisfied != 2 {
+		t.Errorf("edges: blocks=%d parents=%d satisfied=%d, want 4/1/2", blocks, parents, satisfied)
+	}
+
+	want := []uuid.UUID{api.ID, release.ID}
+	if len(g.CriticalPath) != len(want) || g.CriticalPath[0] != want[0] || g.CriticalPath[1] != want[1] {
+		t.Errorf("critical path = %v, want %v", g.CriticalPath, want)
+	}
+	if g.CriticalPathWeight != 31 {
+		t.Errorf("critical path weight = %d, want 31", g.CriticalPathWeight)
+	}
+	for _, n := range g.Nodes {
+		wantDepth := map[uuid.UUID]int{design.ID: 0, api.ID: 1, ui.ID: 1, release.ID: 2, sub.ID: 0}[n.ID]
+		if n.Depth != wantDepth {
+			t.Errorf("%s depth = %d, want %d", n.Subject, n.Depth, wantDepth)
+		}
+	}
+	if g.Cycle != nil {
+		t.Errorf("unexpected cycle %v", g.Cycle)
+	}
+}
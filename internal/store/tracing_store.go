@@ -155,6 +155,10 @@ type TracingStore interface {
 
 	// Cost aggregation
 	GetMonthlyAgentCost(ctx context.Context, agentID uuid.UUID, year int, month time.Month) (float64, error)
+	// GetMonthlyUserCost sums root-trace cost for one user (external sender ID) in the context tenant.
+	GetMonthlyUserCost(ctx context.Context, userID string, year int, month time.Month) (float64, error)
+	// GetMonthlyTenantCost sums root-trace cost across the context tenant.
+	GetMonthlyTenantCost(ctx context.Context, year int, month time.Month) (float64, error)
 	GetCostSummary(ctx context.Context, opts CostSummaryOpts) ([]CostSummaryRow, error)
 
 	// Maintenance
@@ -0,0 +1,116 @@
+package stt
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// geminiMaxInlineBytes is the inline_data request limit for generateContent.
+const geminiMaxInlineBytes = 20 << 20
+
+// GeminiProvider implements STT by asking a Gemini model to transcribe inline audio.
+type GeminiProvider struct {
+	apiKey  string
+	apiBase string
+	model   string // default "gemini-2.5-flash"
+	client  *http.Client
+}
+
+// GeminiConfig configures the Gemini STT provider.
+type GeminiConfig struct {
+	APIKey    string
+	APIBase   string
+	Model     string
+	TimeoutMs int
+}
+
+// NewGeminiProvider creates a Gemini STT provider.
+func NewGeminiProvider(cfg GeminiConfig) *GeminiProvider {
+	p := &GeminiProvider{
+		apiKey:  cfg.APIKey,
+		apiBase: strings.TrimRight(cfg.APIBase, "/"),
+		model:   cfg.Model,
+		client:  newHTTPClient(cfg.TimeoutMs),
+	}
+	if p.apiBase == "" {
+		p.apiBase = "https://generativelanguage.googleapis.com/v1beta"
+	}
+	if p.model == "" {
+		p.model = "gemini-2.5-flash"
+	}
+	return p
+}
+
+func (p *GeminiProvider) Name() string { return "gemini" }
+
+// Transcribe calls POST {apiBase}/models/{model}:generateContent with the audio
+// as inline_data and a verbatim-transcription instruction.
+func (p *GeminiProvider) Transcribe(ctx context.Context, in Input, opts Options) (*Result, error) {
+	audio, err := os.ReadFile(in.Path)
+	if err != nil {
+		return nil, fmt.Errorf("gemini stt: open audio file: %w", err)
+	}
+	if len(audio) > geminiMaxInlineBytes {
+		return nil, fmt.Errorf("gemini stt: audio file too large (%d bytes, max %d)", len(audio), geminiMaxInlineBytes)
+	}
+	model := opts.Model
+	if model == "" {
+		model = p.model
+	}
+
+	prompt := "Transcribe this audio verbatim. Output only the transcript text, with no commentary, labels or timestamps. If there is no speech, output nothing."
+	if opts.Language != "" {
+		prompt += " The spoken language is most likely " + opts.Language + "."
+	}
+	reqBody := map[string]any{
+		"contents": []map[string]any{{
+			"role": "user",
+			"parts": []map[string]any{
+				{"inline_data": map[string]string{
+					"mime_type": audioMimeType(in),
+					"data":      base64.StdEncoding.EncodeToString(audio),
+				}},
+				{"text": prompt},
+			},
+		}},
+		"generationConfig": map[string]any{"temperature": 0},
+	}
+	data, err := json.Marshal(reqBody)
+	if err != nil {
+		return nil, fmt.Errorf("gemini stt: marshal request: %w", err)
+	}
+
+	url := fmt.Sprintf("%s/models/%s:generateContent", p.apiBase, model)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("gemini stt: create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("x-goog-api-key", p.apiKey)
+
+	var out struct {
+		Candidates []struct {
+			Content struct {
+				Parts []struct {
+					Text string `json:"text"`
+				} `json:"parts"`
+			} `json:"content"`
+		} `json:"candidates"`
+	}
+	if err := postJSONResponse(ctx, p.client, req, &out); err != nil {
+		return nil, fmt.Errorf("gemini stt: %w", err)
+	}
+	var sb strings.Builder
+	if len(out.Candidates) > 0 {
+		for _, part := range out.Candidates[0].Content.Parts {
+			sb.WriteString(part.Text)
+		}
+	}
+	return &Result{Text: strings.TrimSpace(sb.String()), Provider: p.Name()}, nil
+}
@@ -0,0 +1,175 @@
+package stt
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ErrNoProviders is returned when transcription is requested but no provider is registered.
+var ErrNoProviders = errors.New("stt: no providers configured")
+
+// Manager orchestrates STT providers: per-tenant provider/language selection,
+// fallback across providers and a global concurrency cap.
+type Manager struct {
+	mu        sync.RWMutex
+	providers map[string]Provider
+	primary   string // primary provider name
+	language  string // default language hint
+	timeoutMs int    // per-call timeout (default 60000)
+
+	tenants map[string]ScopeSettings // tenant ID → overrides
+
+	sem chan struct{} // bounds concurrent transcriptions
+}
+
+// ManagerConfig configures the STT manager.
+type ManagerConfig struct {
+	Primary       string // primary provider name
+	Language      string // default language hint (empty = auto-detect)
+	TimeoutMs     int    // default 60000
+	MaxConcurrent int    // default 4
+}
+
+// NewManager creates an STT manager.
+func NewManager(cfg ManagerConfig) *Manager {
+	m := &Manager{
+		providers: make(map[string]Provider),
+		tenants:   make(map[string]ScopeSettings),
+		primary:   cfg.Primary,
+		language:  cfg.Language,
+		timeoutMs: cfg.TimeoutMs,
+	}
+	if m.timeoutMs <= 0 {
+		m.timeoutMs = 60000
+	}
+	n := cfg.MaxConcurrent
+	if n <= 0 {
+		n = 4
+	}
+	m.sem = make(chan struct{}, n)
+	return m
+}
+
+// RegisterProvider adds an STT provider.
+func (m *Manager) RegisterProvider(p Provider) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.providers[p.Name()] = p
+	// If no primary set, use first registered
+	if m.primary == "" {
+		m.primary = p.Name()
+	}
+}
+
+// UpdateFrom replaces providers and defaults with those of a freshly built
+// manager, keeping tenant overrides. Used on config reload so holders of this
+// manager (agent loops) pick up the change without being rebuilt.
+func (m *Manager) UpdateFrom(src *Manager) {
+	if src == nil || src == m {
+		return
+	}
+	src.mu.RLock()
+	providers := make(map[string]Provider, len(src.providers))
+	for k, v := range src.providers {
+		providers[k] = v
+	}
+	primary, language, timeoutMs := src.primary, src.language, src.timeoutMs
+	src.mu.RUnlock()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.providers = providers
+	m.primary = primary
+	m.language = language
+	m.timeoutMs = timeoutMs
+}
+
+// HasProviders returns true if at least one provider is registered.
+func (m *Manager) HasProviders() bool {
+	if m == nil {
+		return false
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return len(m.providers) > 0
+}
+
+// ProviderNames returns the registered provider names, sorted.
+func (m *Manager) ProviderNames() []string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	names := make([]string, 0, len(m.providers))
+	for name := range m.providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// PrimaryProvider returns the primary provider name.
+func (m *Manager) PrimaryProvider() string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.primary
+}
+
+// Transcribe converts audio to text for a tenant, trying the tenant's provider
+// first and then the remaining providers. tenantID may be empty.
+func (m *Manager) Transcribe(ctx context.Context, tenantID string, in Input) (*Result, error) {
+	scope := m.Resolve(tenantID)
+
+	m.mu.RLock()
+	order := make([]Provider, 0, len(m.providers))
+	if p, ok := m.providers[scope.Provider]; ok {
+		order = append(order, p)
+	}
+	names := make([]string, 0, len(m.providers))
+	for name := range m.providers {
+		if name != scope.Provider {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		order = append(order, m.providers[name])
+	}
+	timeout := time.Duration(m.timeoutMs) * time.Millisecond
+	m.mu.RUnlock()
+
+	if len(order) == 0 {
+		return nil, ErrNoProviders
+	}
+
+	// Acquire a concurrency slot (blocks while MaxConcurrent calls are in flight).
+	select {
+	case m.sem <- struct{}{}:
+		defer func() { <-m.sem }()
+	case <-ctx.Done():
+		return nil, fmt.Errorf("stt: waiting for concurrency slot: %w", ctx.Err())
+	}
+
+	opts := Options{Language: scope.Language, TenantID: tenantID}
+	var errs []error
+	for i, p := range order {
+		callCtx, cancel := context.WithTimeout(ctx, timeout)
+		res, err := p.Transcribe(callCtx, in, opts)
+		cancel()
+		if err == nil {
+			if i > 0 {
+				slog.Info("stt fallback succeeded", "provider", p.Name())
+			}
+			return res, nil
+		}
+		slog.Warn("stt provider failed", "provider", p.Name(), "error", err)
+		errs = append(errs, err)
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	return nil, fmt.Errorf("all stt providers failed: %w", errors.Join(errs...))
+}
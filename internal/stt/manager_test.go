@@ -0,0 +1,92 @@
+package stt
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+type fakeProvider struct {
+	name  string
+	err   error
+	calls []Options
+}
+
+func (f *fakeProvider) Name() string { return f.name }
+
+func (f *fakeProvider) Transcribe(_ context.Context, _ Input, opts Options) (*Result, error) {
+	f.calls = append(f.calls, opts)
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &Result{Text: "hello from " + f.name, Provider: f.name}, nil
+}
+
+func TestManager_ResolveTenantScope(t *testing.T) {
+	m := NewManager(ManagerConfig{Primary: "openai", Language: "en"})
+	m.RegisterProvider(&fakeProvider{name: "openai"})
+	m.RegisterProvider(&fakeProvider{name: "whisper"})
+
+	m.SetTenantScope("t1", ParseScopeConfigs(map[string]string{
+		ConfigKeyProvider: "whisper",
+		ConfigKeyLanguage: "vi",
+	}))
+	m.SetTenantScope("t2", ScopeSettings{Provider: "gone"}) // unregistered → ignored
+
+	if got := m.Resolve("t1"); got != (ScopeSettings{Provider: "whisper", Language: "vi"}) {
+		t.Errorf("t1 = %+v", got)
+	}
+	if got := m.Resolve("t2"); got != (ScopeSettings{Provider: "openai", Language: "en"}) {
+		t.Errorf("t2 = %+v", got)
+	}
+
+	m.SetTenantScope("t1", ScopeSettings{})
+	if got := m.Resolve("t1"); got.Provider != "openai" {
+		t.Errorf("cleared t1 = %+v", got)
+	}
+}
+
+func TestManager_TranscribeFallsBack(t *testing.T) {
+	primary := &fakeProvider{name: "gemini", err: errors.New("boom")}
+	backup := &fakeProvider{name: "whisper"}
+	m := NewManager(ManagerConfig{Primary: "gemini"})
+	m.RegisterProvider(primary)
+	m.RegisterProvider(backup)
+	m.SetTenantScope("t1", ScopeSettings{Language: "de"})
+
+	res, err := m.Transcribe(context.Background(), "t1", Input{Path: "x.ogg"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Provider != "whisper" || res.Text != "hello from whisper" {
+		t.Errorf("result = %+v", res)
+	}
+	if len(primary.calls) != 1 || primary.calls[0].Language != "de" || primary.calls[0].TenantID != "t1" {
+		t.Errorf("primary calls = %+v", primary.calls)
+	}
+}
+
+func TestManager_NoProviders(t *testing.T) {
+	m := NewManager(ManagerConfig{})
+	if _, err := m.Transcribe(context.Background(), "", Input{Path: "x.ogg"}); !errors.Is(err, ErrNoProviders) {
+		t.Errorf("err = %v, want ErrNoProviders", err)
+	}
+}
+
+func TestManager_UpdateFromKeepsScopes(t *testing.T) {
+	m := NewManager(ManagerConfig{Primary: "openai"})
+	m.RegisterProvider(&fakeProvider{name: "openai"})
+	m.SetTenantScope("t1", ScopeSettings{Provider: "whisper"})
+
+	next := NewManager(ManagerConfig{Primary: "gemini", Language: "fr"})
+	next.RegisterProvider(&fakeProvider{name: "gemini"})
+	next.RegisterProvider(&fakeProvider{name: "whisper"})
+	m.UpdateFrom(next)
+
+	if got := m.Resolve("t1"); got != (ScopeSettings{Provider: "whisper", Language: "fr"}) {
+		t.Errorf("t1 after reload = %+v", got)
+	}
+	if got := m.PrimaryProvider(); got != "gemini" {
+		t.Errorf("primary = %q", got)
+	}
+}
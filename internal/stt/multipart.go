@@ -0,0 +1,113 @@
+package stt
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// maxResponseBytes caps provider response bodies (transcripts are small).
+const maxResponseBytes = 1 << 20
+
+// maxAudioBytes caps uploaded audio. Matches the OpenAI 25 MB limit, which is
+// also above every channel's default media cap.
+const maxAudioBytes = 25 << 20
+
+// multipartUpload builds a multipart/form-data body containing the audio file
+// under fileField plus the given text fields (empty values are skipped).
+func multipartUpload(path, fileField string, fields [][2]string) (*bytes.Buffer, string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, "", fmt.Errorf("open audio file: %w", err)
+	}
+	defer f.Close()
+	if fi, err := f.Stat(); err == nil && fi.Size() > maxAudioBytes {
+		return nil, "", fmt.Errorf("audio file too large (%d bytes, max %d)", fi.Size(), maxAudioBytes)
+	}
+
+	var body bytes.Buffer
+	w := multipart.NewWriter(&body)
+	fw, err := w.CreateFormFile(fileField, filepath.Base(path))
+	if err != nil {
+		return nil, "", fmt.Errorf("create form file: %w", err)
+	}
+	if _, err := io.Copy(fw, f); err != nil {
+		return nil, "", fmt.Errorf("write audio to form: %w", err)
+	}
+	for _, kv := range fields {
+		if kv[1] == "" {
+			continue
+		}
+		if err := w.WriteField(kv[0], kv[1]); err != nil {
+			return nil, "", fmt.Errorf("write form field %s: %w", kv[0], err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		return nil, "", fmt.Errorf("close multipart writer: %w", err)
+	}
+	return &body, w.FormDataContentType(), nil
+}
+
+// postJSONResponse sends req and decodes a JSON response into out.
+func postJSONResponse(ctx context.Context, client *http.Client, req *http.Request, out any) error {
+	resp, err := client.Do(req.WithContext(ctx))
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseBytes))
+	if err != nil {
+		return fmt.Errorf("read response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("status %d: %s", resp.StatusCode, truncate(string(data), 300))
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("parse response: %w", err)
+	}
+	return nil
+}
+
+// audioMimeType returns the MIME type for an audio file, preferring the
+// caller-supplied value and falling back to the file extension.
+func audioMimeType(in Input) string {
+	if in.MimeType != "" {
+		return in.MimeType
+	}
+	switch strings.ToLower(filepath.Ext(in.Path)) {
+	case ".ogg", ".oga", ".opus":
+		return "audio/ogg"
+	case ".mp3":
+		return "audio/mpeg"
+	case ".m4a", ".mp4":
+		return "audio/mp4"
+	case ".wav":
+		return "audio/wav"
+	case ".webm":
+		return "audio/webm"
+	case ".flac":
+		return "audio/flac"
+	case ".amr":
+		return "audio/amr"
+	}
+	if t := mime.TypeByExtension(filepath.Ext(in.Path)); t != "" {
+		return t
+	}
+	return "application/octet-stream"
+}
+
+func truncate(s string, n int) string {
+	if len(s) > n {
+		return s[:n] + "..."
+	}
+	return s
+}
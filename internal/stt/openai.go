@@ -0,0 +1,96 @@
+package stt
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// OpenAIProvider implements STT via the OpenAI-compatible audio/transcriptions
+// API. Works with any server exposing the same endpoint (Groq, faster-whisper-server, vLLM).
+type OpenAIProvider struct {
+	apiKey  string
+	apiBase string
+	model   string // default "whisper-1"
+	client  *http.Client
+}
+
+// OpenAIConfig configures the OpenAI-compatible STT provider.
+type OpenAIConfig struct {
+	APIKey    string
+	APIBase   string
+	Model     string
+	TimeoutMs int
+}
+
+// NewOpenAIProvider creates an OpenAI-compatible STT provider.
+func NewOpenAIProvider(cfg OpenAIConfig) *OpenAIProvider {
+	p := &OpenAIProvider{
+		apiKey:  cfg.APIKey,
+		apiBase: strings.TrimRight(cfg.APIBase, "/"),
+		model:   cfg.Model,
+	}
+	if p.apiBase == "" {
+		p.apiBase = "https://api.openai.com/v1"
+	}
+	if p.model == "" {
+		p.model = "whisper-1"
+	}
+	p.client = newHTTPClient(cfg.TimeoutMs)
+	return p
+}
+
+func (p *OpenAIProvider) Name() string { return "openai" }
+
+// Transcribe calls POST {apiBase}/audio/transcriptions with {file, model, language}.
+func (p *OpenAIProvider) Transcribe(ctx context.Context, in Input, opts Options) (*Result, error) {
+	model := opts.Model
+	if model == "" {
+		model = p.model
+	}
+	body, contentType, err := multipartUpload(in.Path, "file", [][2]string{
+		{"model", model},
+		{"language", opts.Language},
+		{"response_format", "json"},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("openai stt: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.apiBase+"/audio/transcriptions", body)
+	if err != nil {
+		return nil, fmt.Errorf("openai stt: create request: %w", err)
+	}
+	req.Header.Set("Content-Type", contentType)
+	if p.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+p.apiKey)
+	}
+
+	var out struct {
+		Text     string `json:"text"`
+		Language string `json:"language"`
+	}
+	if err := postJSONResponse(ctx, p.client, req, &out); err != nil {
+		return nil, fmt.Errorf("openai stt: %w", err)
+	}
+	return &Result{Text: strings.TrimSpace(out.Text), Language: out.Language, Provider: p.Name()}, nil
+}
+
+// sharedTransport pools connections across all STT providers, including the
+// short-lived proxy providers built per call by channel-level STT overrides.
+var sharedTransport = &http.Transport{
+	Proxy:               http.ProxyFromEnvironment,
+	MaxIdleConnsPerHost: 4,
+	IdleConnTimeout:     90 * time.Second,
+}
+
+// newHTTPClient returns a client with the given timeout in ms (default 60s;
+// transcribing long voice notes is slower than synthesis).
+func newHTTPClient(timeoutMs int) *http.Client {
+	if timeoutMs <= 0 {
+		timeoutMs = 60000
+	}
+	return &http.Client{Timeout: time.Duration(timeoutMs) * time.Millisecond, Transport: sharedTransport}
+}
@@ -0,0 +1,154 @@
+package stt
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeAudio(t *testing.T) string {
+	t.Helper()
+	p := filepath.Join(t.TempDir(), "voice.ogg")
+	if err := os.WriteFile(p, []byte("OggS-fake-audio"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+func TestOpenAIProvider_Transcribe(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/audio/transcriptions" {
+			t.Errorf("path = %s", r.URL.Path)
+		}
+		if got := r.Header.Get("Authorization"); got != "Bearer sk-test" {
+			t.Errorf("auth = %q", got)
+		}
+		if err := r.ParseMultipartForm(1 << 20); err != nil {
+			t.Fatal(err)
+		}
+		if r.FormValue("model") != "whisper-1" || r.FormValue("language") != "vi" {
+			t.Errorf("form = %v", r.MultipartForm.Value)
+		}
+		if _, _, err := r.FormFile("file"); err != nil {
+			t.Errorf("missing file: %v", err)
+		}
+		json.NewEncoder(w).Encode(map[string]string{"text": " xin chào "})
+	}))
+	defer srv.Close()
+
+	p := NewOpenAIProvider(OpenAIConfig{APIKey: "sk-test", APIBase: srv.URL + "/v1"})
+	res, err := p.Transcribe(context.Background(), Input{Path: writeAudio(t)}, Options{Language: "vi"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Text != "xin chào" || res.Provider != "openai" {
+		t.Errorf("result = %+v", res)
+	}
+}
+
+func TestWhisperProvider_Transcribe(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/inference" {
+			t.Errorf("path = %s", r.URL.Path)
+		}
+		r.ParseMultipartForm(1 << 20)
+		if r.FormValue("language") != "auto" {
+			t.Errorf("language = %q", r.FormValue("language"))
+		}
+		w.Write([]byte(`{"text":"hello world\n"}`))
+	}))
+	defer srv.Close()
+
+	res, err := NewWhisperProvider(WhisperConfig{BaseURL: srv.URL}).
+		Transcribe(context.Background(), Input{Path: writeAudio(t)}, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Text != "hello world" {
+		t.Errorf("text = %q", res.Text)
+	}
+}
+
+func TestGeminiProvider_Transcribe(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/models/gemini-2.5-flash:generateContent" {
+			t.Errorf("path = %s", r.URL.Path)
+		}
+		if r.Header.Get("x-goog-api-key") != "g-key" {
+			t.Errorf("missing api key header")
+		}
+		var body map[string]any
+		json.NewDecoder(r.Body).Decode(&body)
+		raw, _ := json.Marshal(body)
+		if !strings.Contains(string(raw), `"mime_type":"audio/ogg"`) || !strings.Contains(string(raw), "most likely ja") {
+			t.Errorf("body = %s", raw)
+		}
+		w.Write([]byte(`{"candidates":[{"content":{"parts":[{"text":"こんにちは"}]}}]}`))
+	}))
+	defer srv.Close()
+
+	res, err := NewGeminiProvider(GeminiConfig{APIKey: "g-key", APIBase: srv.URL}).
+		Transcribe(context.Background(), Input{Path: writeAudio(t)}, Options{Language: "ja"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Text != "こんにちは" {
+		t.Errorf("text = %q", res.Text)
+	}
+}
+
+func TestProxyProvider_Transcribe(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/transcribe_audio" {
+			t.Errorf("request = %s %s", r.Method, r.URL.Path)
+		}
+		if got := r.Header.Get("Authorization"); got != "Bearer proxy-key" {
+			t.Errorf("auth = %q", got)
+		}
+		if err := r.ParseMultipartForm(1 << 20); err != nil {
+			t.Fatal(err)
+		}
+		if r.FormValue("tenant_id") != "acme" {
+			t.Errorf("tenant_id = %q", r.FormValue("tenant_id"))
+		}
+		if _, _, err := r.FormFile("file"); err != nil {
+			t.Errorf("missing file: %v", err)
+		}
+		json.NewEncoder(w).Encode(map[string]string{"transcript": "hello world"})
+	}))
+	defer srv.Close()
+
+	p := NewProxyProvider(ProxyConfig{BaseURL: srv.URL + "/", APIKey: "proxy-key", TenantID: "acme"})
+	res, err := p.Transcribe(context.Background(), Input{Path: writeAudio(t)}, Options{TenantID: "ignored"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Text != "hello world" || res.Provider != "proxy" {
+		t.Errorf("result = %+v", res)
+	}
+
+	if _, err := p.Transcribe(context.Background(), Input{Path: "/nonexistent/voice.ogg"}, Options{}); err == nil {
+		t.Error("missing audio file should fail")
+	}
+}
+
+func TestProxyProvider_ErrorStatus(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/transcribe_audio" {
+			t.Errorf("path = %s", r.URL.Path)
+		}
+		http.Error(w, "overloaded", http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	_, err := NewProxyProvider(ProxyConfig{BaseURL: srv.URL}).
+		Transcribe(context.Background(), Input{Path: writeAudio(t)}, Options{})
+	if err == nil || !strings.Contains(err.Error(), "503") {
+		t.Errorf("err = %v, want 503", err)
+	}
+}
@@ -0,0 +1,69 @@
+package stt
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// ProxyProvider implements the legacy GoClaw STT proxy protocol:
+// POST {baseURL}/transcribe_audio with multipart {file, tenant_id} → {"transcript": "..."}.
+type ProxyProvider struct {
+	baseURL  string
+	apiKey   string
+	tenantID string // static tenant identifier sent when Options.TenantID is empty
+	client   *http.Client
+}
+
+// ProxyConfig configures the legacy STT proxy provider.
+type ProxyConfig struct {
+	BaseURL   string
+	APIKey    string
+	TenantID  string
+	TimeoutMs int
+}
+
+// NewProxyProvider creates a legacy STT proxy provider.
+func NewProxyProvider(cfg ProxyConfig) *ProxyProvider {
+	return &ProxyProvider{
+		baseURL:  strings.TrimRight(cfg.BaseURL, "/"),
+		apiKey:   cfg.APIKey,
+		tenantID: cfg.TenantID,
+		client:   newHTTPClient(cfg.TimeoutMs),
+	}
+}
+
+func (p *ProxyProvider) Name() string { return "proxy" }
+
+// Transcribe uploads the audio to the proxy's /transcribe_audio endpoint.
+func (p *ProxyProvider) Transcribe(ctx context.Context, in Input, opts Options) (*Result, error) {
+	tenantID := p.tenantID
+	if tenantID == "" {
+		tenantID = opts.TenantID
+	}
+	body, contentType, err := multipartUpload(in.Path, "file", [][2]string{
+		{"tenant_id", tenantID},
+		{"language", opts.Language},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("stt proxy: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.baseURL+"/transcribe_audio", body)
+	if err != nil {
+		return nil, fmt.Errorf("stt proxy: create request: %w", err)
+	}
+	req.Header.Set("Content-Type", contentType)
+	if p.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+p.apiKey)
+	}
+
+	var out struct {
+		Transcript string `json:"transcript"`
+	}
+	if err := postJSONResponse(ctx, p.client, req, &out); err != nil {
+		return nil, fmt.Errorf("stt proxy: %w", err)
+	}
+	return &Result{Text: out.Transcript, Provider: p.Name()}, nil
+}
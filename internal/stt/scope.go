@@ -0,0 +1,56 @@
+package stt
+
+// ScopeSettings overrides the manager-wide provider and language for a tenant.
+// Empty fields inherit the manager defaults.
+type ScopeSettings struct {
+	Provider string `json:"provider,omitempty"`
+	Language string `json:"language,omitempty"`
+}
+
+// IsZero reports whether the settings carry no overrides.
+func (s ScopeSettings) IsZero() bool {
+	return s.Provider == "" && s.Language == ""
+}
+
+// System config keys used to persist tenant overrides (system_configs rows are tenant-scoped).
+const (
+	ConfigKeyProvider = "stt.provider"
+	ConfigKeyLanguage = "stt.language"
+)
+
+// ParseScopeConfigs extracts tenant-level STT overrides from a tenant's system_configs map.
+func ParseScopeConfigs(configs map[string]string) ScopeSettings {
+	return ScopeSettings{
+		Provider: configs[ConfigKeyProvider],
+		Language: configs[ConfigKeyLanguage],
+	}
+}
+
+// SetTenantScope replaces the overrides for a tenant.
+// Called after the tenant's system_configs are (re)loaded.
+func (m *Manager) SetTenantScope(tenantID string, s ScopeSettings) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if s.IsZero() {
+		delete(m.tenants, tenantID)
+		return
+	}
+	m.tenants[tenantID] = s
+}
+
+// Resolve returns the effective provider and language for a tenant. Providers
+// that are not registered are skipped so a stale override never disables
+// transcription.
+func (m *Manager) Resolve(tenantID string) ScopeSettings {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	out := ScopeSettings{Provider: m.primary, Language: m.language}
+	s := m.tenants[tenantID]
+	if _, ok := m.providers[s.Provider]; ok {
+		out.Provider = s.Provider
+	}
+	if s.Language != "" {
+		out.Language = s.Language
+	}
+	return out
+}
@@ -0,0 +1,36 @@
+// Package stt provides speech-to-text transcription for GoClaw.
+// Mirrors the tts package: pluggable providers behind a Manager that resolves
+// per-tenant overrides and falls back across registered providers.
+//
+// Supported providers: OpenAI-compatible /audio/transcriptions (OpenAI, Groq,
+// faster-whisper-server, ...), Gemini, a local whisper.cpp-style HTTP server,
+// and the legacy GoClaw STT proxy (/transcribe_audio).
+package stt
+
+import "context"
+
+// Provider transcribes an audio file into text.
+type Provider interface {
+	Name() string
+	Transcribe(ctx context.Context, in Input, opts Options) (*Result, error)
+}
+
+// Input identifies the audio to transcribe.
+type Input struct {
+	Path     string // local file path
+	MimeType string // e.g. "audio/ogg"; detected from extension when empty
+}
+
+// Options controls transcription parameters.
+type Options struct {
+	Language string // ISO-639-1 hint (e.g. "en", "vi"); empty = auto-detect
+	Model    string // provider-specific model override
+	TenantID string // forwarded to providers that bill/route per tenant (proxy)
+}
+
+// Result is the output of a transcription.
+type Result struct {
+	Text     string
+	Language string // detected language when the provider reports it
+	Provider string // provider that produced the transcript
+}
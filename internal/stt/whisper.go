@@ -0,0 +1,67 @@
+package stt
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// WhisperProvider implements STT against a local whisper.cpp-compatible HTTP
+// server (POST /inference). No audio leaves the host.
+type WhisperProvider struct {
+	baseURL string
+	apiKey  string // optional, for servers behind an auth proxy
+	client  *http.Client
+}
+
+// WhisperConfig configures the local whisper server provider.
+type WhisperConfig struct {
+	BaseURL   string // e.g. "http://localhost:8080"
+	APIKey    string
+	TimeoutMs int
+}
+
+// NewWhisperProvider creates a local whisper server provider.
+func NewWhisperProvider(cfg WhisperConfig) *WhisperProvider {
+	return &WhisperProvider{
+		baseURL: strings.TrimRight(cfg.BaseURL, "/"),
+		apiKey:  cfg.APIKey,
+		client:  newHTTPClient(cfg.TimeoutMs),
+	}
+}
+
+func (p *WhisperProvider) Name() string { return "whisper" }
+
+// Transcribe calls POST {baseURL}/inference with {file, language, response_format=json}.
+func (p *WhisperProvider) Transcribe(ctx context.Context, in Input, opts Options) (*Result, error) {
+	lang := opts.Language
+	if lang == "" {
+		lang = "auto"
+	}
+	body, contentType, err := multipartUpload(in.Path, "file", [][2]string{
+		{"language", lang},
+		{"response_format", "json"},
+		{"temperature", "0.0"},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("whisper stt: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.baseURL+"/inference", body)
+	if err != nil {
+		return nil, fmt.Errorf("whisper stt: create request: %w", err)
+	}
+	req.Header.Set("Content-Type", contentType)
+	if p.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+p.apiKey)
+	}
+
+	var out struct {
+		Text string `json:"text"`
+	}
+	if err := postJSONResponse(ctx, p.client, req, &out); err != nil {
+		return nil, fmt.Errorf("whisper stt: %w", err)
+	}
+	return &Result{Text: strings.TrimSpace(out.Text), Provider: p.Name()}, nil
+}
@@ -33,8 +33,14 @@ type TaskTicker struct {
 	msgBus   *bus.MessageBus
 	interval time.Duration
 
-	stopCh chan struct{}
-	wg     sync.WaitGroup
+	// forceRecoverOnStart resets every in_progress task on start. Disabled in
+	// cluster mode, where other replicas may still be running those tasks.
+	forceRecoverOnStart bool
+
+	runMu   sync.Mutex
+	running bool
+	stopCh  chan struct{}
+	wg      sync.WaitGroup
 
 	mu               sync.Mutex
 	lastFollowupSent map[uuid.UUID]time.Time // taskID → last followup sent time
@@ -46,42 +52,63 @@ func NewTaskTicker(teams store.TeamStore, agents store.AgentStore, msgBus *bus.M
 		interval = time.Duration(intervalSec) * time.Second
 	}
 	return &TaskTicker{
-		teams:            teams,
-		agents:           agents,
-		msgBus:           msgBus,
-		interval:         interval,
-		stopCh:           make(chan struct{}),
-		lastFollowupSent: make(map[uuid.UUID]time.Time),
+		teams:               teams,
+		agents:              agents,
+		msgBus:              msgBus,
+		interval:            interval,
+		forceRecoverOnStart: true,
+		lastFollowupSent:    make(map[uuid.UUID]time.Time),
 	}
 }
 
-// Start launches the background recovery loop.
+// SetForceRecoverOnStart controls whether Start resets every in_progress task
+// (default true: after a restart no agent is running them).
+func (t *TaskTicker) SetForceRecoverOnStart(v bool) {
+	t.runMu.Lock()
+	defer t.runMu.Unlock()
+	t.forceRecoverOnStart = v
+}
+
+// Start launches the background recovery loop. It can be started again after Stop.
 func (t *TaskTicker) Start() {
+	t.runMu.Lock()
+	defer t.runMu.Unlock()
+	if t.running {
+		return
+	}
+	t.running = true
+	t.stopCh = make(chan struct{})
 	t.wg.Add(1)
-	go t.loop()
+	go t.loop(t.stopCh, t.forceRecoverOnStart)
 	slog.Info("task ticker started", "interval", t.interval)
 }
 
 // Stop signals the ticker to stop and waits for completion.
 func (t *TaskTicker) Stop() {
+	t.runMu.Lock()
+	defer t.runMu.Unlock()
+	if !t.running {
+		return
+	}
+	t.running = false
 	close(t.stopCh)
 	t.wg.Wait()
 	slog.Info("task ticker stopped")
 }
 
-func (t *TaskTicker) loop() {
+func (t *TaskTicker) loop(stopCh <-chan struct{}, forceRecover bool) {
 	defer t.wg.Done()
 
 	// On startup: force-recover ALL in_progress tasks (lock may not be expired yet,
-	// but no agent is running after a restart).
-	t.recoverAll(true)
+	// but no agent is running after a restart). Otherwise only expired locks.
+	t.recoverAll(forceRecover)
 
 	ticker := time.NewTicker(t.interval)
 	defer ticker.Stop()
 
 	for {
 		select {
-		case <-t.stopCh:
+		case <-stopCh:
 			return
 		case <-ticker.C:
 			// Periodic: only recover tasks with expired locks.
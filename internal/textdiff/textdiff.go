@@ -0,0 +1,212 @@
+// Package textdiff computes line-based diffs of small text documents such as
+// agent context files, rendered in unified diff format.
+package textdiff
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Op is the kind of a diff line.
+type Op int
+
+const (
+	Equal Op = iota
+	Delete
+	Insert
+)
+
+// Line is one line of a diff.
+type Line struct {
+	Op   Op
+	Text string
+}
+
+// maxLCSCells bounds the LCS table. Larger changed regions (after trimming the
+// common prefix and suffix) are reported as a full replacement.
+const maxLCSCells = 4 << 20
+
+// Lines returns the line diff turning a into b.
+func Lines(a, b string) []Line {
+	x, y := splitLines(a), splitLines(b)
+
+	prefix := 0
+	for prefix < len(x) && prefix < len(y) && x[prefix] == y[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(x)-prefix && suffix < len(y)-prefix && x[len(x)-1-suffix] == y[len(y)-1-suffix] {
+		suffix++
+	}
+
+	out := make([]Line, 0, len(x)+len(y))
+	for _, s := range x[:prefix] {
+		out = append(out, Line{Equal, s})
+	}
+	out = append(out, diffMiddle(x[prefix:len(x)-suffix], y[prefix:len(y)-suffix])...)
+	for _, s := range x[len(x)-suffix:] {
+		out = append(out, Line{Equal, s})
+	}
+	return out
+}
+
+// diffMiddle diffs the changed region with a longest-common-subsequence table.
+func diffMiddle(x, y []string) []Line {
+	n, m := len(x), len(y)
+	if n == 0 || m == 0 || n*m > maxLCSCells {
+		out := make([]Line, 0, n+m)
+		for _, s := range x {
+			out = append(out, Line{Delete, s})
+		}
+		for _, s := range y {
+			out = append(out, Line{Insert, s})
+		}
+		return out
+	}
+
+	// lcs[i][j] = LCS length of x[i:] and y[j:].
+	lcs := make([][]int32, n+1)
+	for i := range lcs {
+		lcs[i] = make([]int32, m+1)
+	}
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			if x[i] == y[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	out := make([]Line, 0, n+m)
+	i, j := 0, 0
+	for i < n && j < m {
+		switch {
+		case x[i] == y[j]:
+			out = append(out, Line{Equal, x[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			out = append(out, Line{Delete, x[i]})
+			i++
+		default:
+			out = append(out, Line{Insert, y[j]})
+			j++
+		}
+	}
+	for ; i < n; i++ {
+		out = append(out, Line{Delete, x[i]})
+	}
+	for ; j < m; j++ {
+		out = append(out, Line{Insert, y[j]})
+	}
+	return out
+}
+
+// Stats counts inserted and deleted lines.
+func Stats(lines []Line) (added, removed int) {
+	for _, l := range lines {
+		switch l.Op {
+		case Insert:
+			added++
+		case Delete:
+			removed++
+		}
+	}
+	return added, removed
+}
+
+// Unified renders the diff from a to b in unified format with the given
+// number of context lines. Returns "" when a and b are equal.
+func Unified(fromName, toName, a, b string, context int) string {
+	lines := Lines(a, b)
+	if added, removed := Stats(lines); added == 0 && removed == 0 {
+		return ""
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "--- %s\n+++ %s\n", fromName, toName)
+
+	// Old/new line numbers (1-based) of each diff line.
+	oldNo := make([]int, len(lines))
+	newNo := make([]int, len(lines))
+	o, n := 1, 1
+	for k, l := range lines {
+		oldNo[k], newNo[k] = o, n
+		if l.Op != Insert {
+			o++
+		}
+		if l.Op != Delete {
+			n++
+		}
+	}
+
+	for k := 0; k < len(lines); {
+		if lines[k].Op == Equal {
+			k++
+			continue
+		}
+		// Grow the hunk while changes are within 2*context lines of each other.
+		start := max(k-context, 0)
+		end := k
+		for end < len(lines) {
+			if lines[end].Op != Equal {
+				end++
+				continue
+			}
+			next := end
+			for next < len(lines) && lines[next].Op == Equal {
+				next++
+			}
+			if next == len(lines) || next-end > 2*context {
+				end = min(end+context, len(lines))
+				break
+			}
+			end = next
+		}
+
+		var oldLen, newLen int
+		for _, l := range lines[start:end] {
+			if l.Op != Insert {
+				oldLen++
+			}
+			if l.Op != Delete {
+				newLen++
+			}
+		}
+		fmt.Fprintf(&sb, "@@ -%s +%s @@\n", hunkRange(oldNo[start], oldLen), hunkRange(newNo[start], newLen))
+		for _, l := range lines[start:end] {
+			switch l.Op {
+			case Equal:
+				sb.WriteByte(' ')
+			case Delete:
+				sb.WriteByte('-')
+			case Insert:
+				sb.WriteByte('+')
+			}
+			sb.WriteString(l.Text)
+			sb.WriteByte('\n')
+		}
+		k = end
+	}
+	return sb.String()
+}
+
+// hunkRange formats a hunk range; empty ranges point at the line before.
+func hunkRange(start, length int) string {
+	if length == 0 {
+		start--
+	}
+	if length == 1 {
+		return fmt.Sprint(start)
+	}
+	return fmt.Sprintf("%d,%d", start, length)
+}
+
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+}
@@ -0,0 +1,58 @@
+package textdiff
+
+import "testing"
+
+func TestUnified(t *testing.T) {
+	a := "# Soul\n\nBe kind.\nBe brief.\nUse emoji.\n"
+	b := "# Soul\n\nBe kind.\nBe thorough.\nUse emoji.\nSign off with a haiku.\n"
+	want := `--- a/SOUL.md
++++ b/SOUL.md
+@@ -3,3 +3,4 @@
+ Be kind.
+-Be brief.
++Be thorough.
+ Use emoji.
++Sign off with a haiku.
+`
+	if got := Unified("a/SOUL.md", "b/SOUL.md", a, b, 1); got != want {
+		t.Errorf("Unified() =\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestUnified_SeparateHunks(t *testing.T) {
+	a := "1\n2\n3\n4\n5\n6\n7\n8\n9\n"
+	b := "1\nX\n3\n4\n5\n6\n7\nY\n9\n"
+	want := `--- a
++++ b
+@@ -1,3 +1,3 @@
+ 1
+-2
++X
+ 3
+@@ -7,3 +7,3 @@
+ 7
+-8
++Y
+ 9
+`
+	if got := Unified("a", "b", a, b, 1); got != want {
+		t.Errorf("Unified() =\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestUnified_FromEmpty(t *testing.T) {
+	want := "--- a\n+++ b\n@@ -0,0 +1,2 @@\n+x\n+y\n"
+	if got := Unified("a", "b", "", "x\ny\n", 3); got != want {
+		t.Errorf("Unified() = %q, want %q", got, want)
+	}
+	if got := Unified("a", "b", "same\n", "same\n", 3); got != "" {
+		t.Errorf("Unified() of equal input = %q, want empty", got)
+	}
+}
+
+func TestStats(t *testing.T) {
+	added, removed := Stats(Lines("a\nb\nc\n", "a\nc\nd\ne\n"))
+	if added != 2 || removed != 1 {
+		t.Errorf("Stats() = +%d -%d, want +2 -1", added, removed)
+	}
+}
@@ -0,0 +1,357 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/nextlevelbuilder/goclaw/internal/bus"
+	"github.com/nextlevelbuilder/goclaw/internal/channels"
+	"github.com/nextlevelbuilder/goclaw/internal/store"
+)
+
+const (
+	defaultAskUserTimeout = 5 * time.Minute
+	maxAskUserTimeout     = 30 * time.Minute
+	maxAskUserOptions     = 25
+	maxAskUserFields      = 10
+)
+
+var (
+	ErrAskUserNotFound  = errors.New("this question is no longer active")
+	ErrAskUserForbidden = errors.New("only the person who was asked can answer")
+)
+
+// askUserPending is a question waiting for its answer.
+type askUserPending struct {
+	msg      *bus.InteractiveMessage
+	channel  string
+	chatID   string
+	senderID string // who may answer; empty = anyone in the chat
+	expires  time.Time
+	answerCh chan bus.InteractiveAnswer
+}
+
+// PendingQuestion identifies a question waiting for its answer.
+type PendingQuestion struct {
+	ID        string    `json:"id"`
+	Channel   string    `json:"channel"`
+	ChatID    string    `json:"chat_id"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// AskUserManager tracks questions asked by the ask_user tool and routes
+// answers to the waiting tool call: button presses arrive through the
+// channels' interactive resolver, text replies through AnswerText.
+type AskUserManager struct {
+	mu       sync.Mutex
+	pending  map[string]*askUserPending
+	onChange func(q PendingQuestion, waiting bool)
+}
+
+func NewAskUserManager() *AskUserManager {
+	return &AskUserManager{pending: make(map[string]*askUserPending)}
+}
+
+// SetOnChange registers fn, called when a question starts (waiting=true) and
+// stops waiting for its answer. Used to tell other replicas which chats have
+// a question pending here.
+func (m *AskUserManager) SetOnChange(fn func(q PendingQuestion, waiting bool)) {
+	m.mu.Lock()
+	m.onChange = fn
+	m.mu.Unlock()
+}
+
+func (m *AskUserManager) add(p *askUserPending) {
+	m.mu.Lock()
+	m.pending[p.msg.ID] = p
+	fn := m.onChange
+	m.mu.Unlock()
+	if fn != nil {
+		fn(p.question(), true)
+	}
+}
+
+func (m *AskUserManager) remove(p *askUserPending) {
+	m.mu.Lock()
+	delete(m.pending, p.msg.ID)
+	fn := m.onChange
+	m.mu.Unlock()
+	if fn != nil {
+		fn(p.question(), false)
+	}
+}
+
+func (p *askUserPending) question() PendingQuestion {
+	return PendingQuestion{ID: p.msg.ID, Channel: p.channel, ChatID: p.chatID, ExpiresAt: p.expires}
+}
+
+// Answer delivers an answer given on a channel. Returns the question so the
+// caller can describe the answer.
+func (m *AskUserManager) Answer(channel string, answer bus.InteractiveAnswer) (*bus.InteractiveMessage, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	p, ok := m.pending[answer.ID]
+	if !ok || p.channel != channel {
+		return nil, ErrAskUserNotFound
+	}
+	if p.senderID != "" && senderIDPart(answer.SenderID) != p.senderID {
+		return nil, ErrAskUserForbidden
+	}
+	if err := validateAskUserAnswer(p.msg, answer); err != nil {
+		return nil, err
+	}
+	delete(m.pending, answer.ID)
+	p.answerCh <- answer
+	return p.msg, nil
+}
+
+// AnswerText treats a chat message as the answer to the latest question
+// pending in that chat, if the sender may answer it. Reports whether the
+// message was consumed.
+func (m *AskUserManager) AnswerText(channel, chatID, senderID, text string) bool {
+	m.mu.Lock()
+	var msg *bus.InteractiveMessage
+	for _, p := range m.pending {
+		if p.channel == channel && p.chatID == chatID &&
+			(p.senderID == "" || p.senderID == senderIDPart(senderID)) {
+			msg = p.msg
+		}
+	}
+	m.mu.Unlock()
+	if msg == nil {
+		return false
+	}
+	answer := channels.ParseInteractiveText(msg, text)
+	answer.SenderID = senderID
+	_, err := m.Answer(channel, answer)
+	return err == nil
+}
+
+// validateAskUserAnswer rejects empty choices and missing required fields.
+// Free text is always accepted.
+func validateAskUserAnswer(msg *bus.InteractiveMessage, answer bus.InteractiveAnswer) error {
+	if answer.Text != "" {
+		return nil
+	}
+	if msg.IsChoice() {
+		if len(answer.Choices) == 0 {
+			return errors.New("select at least one option")
+		}
+		for _, v := range answer.Choices {
+			if _, ok := msg.Option(v); !ok {
+				return fmt.Errorf("unknown option %q", v)
+			}
+		}
+		return nil
+	}
+	for _, f := range msg.Fields {
+		if f.Required && answer.Fields[f.Name] == "" {
+			return fmt.Errorf("%s is required", f.Label)
+		}
+	}
+	return nil
+}
+
+// AskUserTool asks the user in the current chat a question with buttons,
+// a choice list or a short form, and waits for the answer.
+type AskUserTool struct {
+	manager *AskUserManager
+	msgBus  *bus.MessageBus
+}
+
+func NewAskUserTool(manager *AskUserManager) *AskUserTool {
+	return &AskUserTool{manager: manager}
+}
+
+func (t *AskUserTool) SetMessageBus(b *bus.MessageBus) { t.msgBus = b }
+
+func (t *AskUserTool) Name() string { return "ask_user" }
+func (t *AskUserTool) Description() string {
+	return "Ask the user in the current chat a question and wait for the answer. Shows buttons, a choice list or a short form where the channel supports it (numbered text otherwise). Returns the selected option values, form values or the user's free-text reply; returns status \"timeout\" if nobody answers in time. Use it when you need a decision or missing details before continuing — not for rhetorical questions."
+}
+
+func (t *AskUserTool) Parameters() map[string]any {
+	return map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"question": map[string]any{
+				"type":        "string",
+				"description": "The question to show",
+			},
+			"kind": map[string]any{
+				"type":        "string",
+				"enum":        []string{bus.InteractiveButtons, bus.InteractiveSingle, bus.InteractiveMulti, bus.InteractiveForm},
+				"description": "buttons: one tap picks an option (few short options); single: pick one from a list; multi: pick any number; form: fill in fields. Default: form if fields are given, else buttons.",
+			},
+			"options": map[string]any{
+				"type":        "array",
+				"items":       map[string]any{"type": "string"},
+				"description": "Choices for buttons/single/multi (max 25)",
+			},
+			"fields": map[string]any{
+				"type": "array",
+				"items": map[string]any{
+					"type": "object",
+					"properties": map[string]any{
+						"name":        map[string]any{"type": "string", "description": "Key in the returned fields"},
+						"label":       map[string]any{"type": "string"},
+						"placeholder": map[string]any{"type": "string"},
+						"required":    map[string]any{"type": "boolean"},
+					},
+					"required": []string{"name"},
+				},
+				"description": "Short text fields for kind=form (max 10)",
+			},
+			"timeout_sec": map[string]any{
+				"type":        "integer",
+				"description": "How long to wait for the answer (default 300, max 1800)",
+			},
+		},
+		"required": []string{"question"},
+	}
+}
+
+func (t *AskUserTool) Execute(ctx context.Context, args map[string]any) *Result {
+	if t.manager == nil || t.msgBus == nil {
+		return ErrorResult("ask_user is not available")
+	}
+	channel := ToolChannelFromCtx(ctx)
+	chatID := ToolChatIDFromCtx(ctx)
+	if channel == "" || chatID == "" || channels.IsInternalChannel(channel) {
+		return ErrorResult("ask_user only works in a chat channel; ask the question in your reply instead")
+	}
+
+	question, _ := args["question"].(string)
+	if question == "" {
+		return ErrorResult("question is required")
+	}
+	msg, err := parseAskUserArgs(args)
+	if err != nil {
+		return ErrorResult(err.Error())
+	}
+	timeout := defaultAskUserTimeout
+	if v, ok := args["timeout_sec"].(float64); ok && v > 0 {
+		timeout = min(time.Duration(v)*time.Second, maxAskUserTimeout)
+	}
+
+	msg.AskedID = senderIDPart(store.SenderIDFromContext(ctx))
+	p := &askUserPending{
+		msg:      msg,
+		channel:  channel,
+		chatID:   chatID,
+		senderID: msg.AskedID,
+		expires:  time.Now().Add(timeout),
+		answerCh: make(chan bus.InteractiveAnswer, 1),
+	}
+	t.manager.add(p)
+	defer t.manager.remove(p)
+
+	out := bus.OutboundMessage{Channel: channel, ChatID: chatID, Content: question, Interactive: msg}
+	if isGroupContext(ctx) {
+		out.Metadata = map[string]string{"group_id": chatID}
+	}
+	if lk := ToolLocalKeyFromCtx(ctx); lk != "" {
+		if out.Metadata == nil {
+			out.Metadata = map[string]string{}
+		}
+		out.Metadata["local_key"] = lk
+	}
+	t.msgBus.PublishOutbound(out)
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case answer := <-p.answerCh:
+		return NewResult(formatAskUserAnswer(msg, answer))
+	case <-timer.C:
+		return NewResult(fmt.Sprintf(`{"status":"timeout","message":"The user did not answer within %s. Continue without the answer or ask again later."}`, timeout))
+	case <-ctx.Done():
+		return ErrorResult("ask_user cancelled: " + ctx.Err().Error())
+	}
+}
+
+// parseAskUserArgs builds the interactive message from the tool arguments.
+func parseAskUserArgs(args map[string]any) (*bus.InteractiveMessage, error) {
+	msg := &bus.InteractiveMessage{ID: uuid.NewString()}
+	if raw, ok := args["options"].([]any); ok {
+		for _, o := range raw {
+			if s, ok := o.(string); ok && s != "" {
+				msg.Options = append(msg.Options, bus.InteractiveOption{Value: s, Label: s})
+			}
+		}
+	}
+	if raw, ok := args["fields"].([]any); ok {
+		for _, f := range raw {
+			m, ok := f.(map[string]any)
+			if !ok {
+				continue
+			}
+			field := bus.InteractiveField{}
+			field.Name, _ = m["name"].(string)
+			field.Label, _ = m["label"].(string)
+			field.Placeholder, _ = m["placeholder"].(string)
+			field.Required, _ = m["required"].(bool)
+			if field.Name == "" {
+				return nil, errors.New("every field needs a name")
+			}
+			if field.Label == "" {
+				field.Label = field.Name
+			}
+			msg.Fields = append(msg.Fields, field)
+		}
+	}
+
+	msg.Kind, _ = args["kind"].(string)
+	if msg.Kind == "" {
+		msg.Kind = bus.InteractiveButtons
+		if len(msg.Fields) > 0 {
+			msg.Kind = bus.InteractiveForm
+		}
+	}
+	switch {
+	case msg.IsChoice():
+		if len(msg.Options) < 2 {
+			return nil, fmt.Errorf("kind %q needs at least 2 options", msg.Kind)
+		}
+		if len(msg.Options) > maxAskUserOptions {
+			return nil, fmt.Errorf("at most %d options are supported", maxAskUserOptions)
+		}
+		msg.Fields = nil
+	case msg.Kind == bus.InteractiveForm:
+		if len(msg.Fields) == 0 {
+			return nil, errors.New("kind \"form\" needs at least 1 field")
+		}
+		if len(msg.Fields) > maxAskUserFields {
+			return nil, fmt.Errorf("at most %d fields are supported", maxAskUserFields)
+		}
+		msg.Options = nil
+	default:
+		return nil, fmt.Errorf("unknown kind %q", msg.Kind)
+	}
+	return msg, nil
+}
+
+// formatAskUserAnswer renders the answer as the tool result.
+func formatAskUserAnswer(msg *bus.InteractiveMessage, answer bus.InteractiveAnswer) string {
+	out := map[string]any{"status": "answered"}
+	if len(answer.Choices) > 0 {
+		out["choices"] = answer.Choices
+	}
+	if len(answer.Fields) > 0 {
+		out["fields"] = answer.Fields
+	}
+	if answer.Text != "" {
+		out["text"] = answer.Text
+		if msg.IsChoice() && len(answer.Choices) == 0 {
+			out["note"] = "The user replied in their own words instead of picking an option."
+		}
+	}
+	data, _ := json.Marshal(out)
+	return string(data)
+}
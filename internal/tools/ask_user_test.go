@@ -0,0 +1,175 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/nextlevelbuilder/goclaw/internal/bus"
+)
+
+var testAskUserMgr = NewAskUserManager()
+
+// askAndWait runs ask_user in the background (as approvalCtx's sender in
+// telegram chat 100) and returns the published question together with a
+// channel delivering the tool result.
+func askAndWait(t *testing.T, args map[string]any) (*bus.InteractiveMessage, <-chan *Result) {
+	t.Helper()
+	msgBus := bus.New()
+	tool := NewAskUserTool(testAskUserMgr)
+	tool.SetMessageBus(msgBus)
+
+	done := make(chan *Result, 1)
+	go func() { done <- tool.Execute(approvalCtx(), args) }()
+
+	subCtx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	out, ok := msgBus.SubscribeOutbound(subCtx)
+	if !ok {
+		t.Fatal("no outbound question published")
+	}
+	if out.Interactive == nil || out.Channel != "telegram" || out.ChatID != "100" {
+		t.Fatalf("outbound = %+v", out)
+	}
+	return out.Interactive, done
+}
+
+func decodeAskUserResult(t *testing.T, res *Result) map[string]any {
+	t.Helper()
+	if res.IsError {
+		t.Fatalf("ask_user error: %s", res.ForLLM)
+	}
+	var out map[string]any
+	if err := json.Unmarshal([]byte(res.ForLLM), &out); err != nil {
+		t.Fatalf("result is not JSON: %s", res.ForLLM)
+	}
+	return out
+}
+
+func TestParseAskUserArgs(t *testing.T) {
+	msg, err := parseAskUserArgs(map[string]any{"options": []any{"Yes", "No"}})
+	if err != nil || msg.Kind != bus.InteractiveButtons || len(msg.Options) != 2 {
+		t.Fatalf("default kind: %+v, %v", msg, err)
+	}
+	msg, err = parseAskUserArgs(map[string]any{"fields": []any{map[string]any{"name": "email", "required": true}}})
+	if err != nil || msg.Kind != bus.InteractiveForm || msg.Fields[0].Label != "email" {
+		t.Fatalf("form: %+v, %v", msg, err)
+	}
+	for _, args := range []map[string]any{
+		{"kind": "single", "options": []any{"only"}},
+		{"kind": "form"},
+		{"kind": "slider", "options": []any{"a", "b"}},
+		{"fields": []any{map[string]any{"label": "no name"}}},
+	} {
+		if _, err := parseAskUserArgs(args); err == nil {
+			t.Errorf("parseAskUserArgs(%v) accepted", args)
+		}
+	}
+}
+
+func TestAskUser_ButtonAnswer(t *testing.T) {
+	msg, done := askAndWait(t, map[string]any{
+		"question": "Deploy now?",
+		"kind":     "multi",
+		"options":  []any{"staging", "production"},
+	})
+	if msg.AskedID != "100" {
+		t.Fatalf("AskedID = %q, want the asking sender", msg.AskedID)
+	}
+
+	if _, err := testAskUserMgr.Answer("telegram", bus.InteractiveAnswer{ID: msg.ID, SenderID: "555", Choices: []string{"staging"}}); !errors.Is(err, ErrAskUserForbidden) {
+		t.Fatalf("stranger answer err = %v, want forbidden", err)
+	}
+	if _, err := testAskUserMgr.Answer("telegram", bus.InteractiveAnswer{ID: msg.ID, SenderID: "100|alice"}); err == nil {
+		t.Fatal("empty selection accepted")
+	}
+	if _, err := testAskUserMgr.Answer("discord", bus.InteractiveAnswer{ID: msg.ID, SenderID: "100", Choices: []string{"staging"}}); !errors.Is(err, ErrAskUserNotFound) {
+		t.Fatalf("other channel answer err = %v, want not found", err)
+	}
+	if _, err := testAskUserMgr.Answer("telegram", bus.InteractiveAnswer{ID: msg.ID, SenderID: "100|alice", Choices: []string{"staging", "production"}}); err != nil {
+		t.Fatalf("answer: %v", err)
+	}
+
+	out := decodeAskUserResult(t, <-done)
+	if out["status"] != "answered" || len(out["choices"].([]any)) != 2 {
+		t.Errorf("result = %v", out)
+	}
+	if _, err := testAskUserMgr.Answer("telegram", bus.InteractiveAnswer{ID: msg.ID, SenderID: "100", Choices: []string{"staging"}}); !errors.Is(err, ErrAskUserNotFound) {
+		t.Errorf("second answer err = %v, want not found", err)
+	}
+}
+
+func TestAskUser_TextReply(t *testing.T) {
+	msg, done := askAndWait(t, map[string]any{
+		"question": "Where should I ship it?",
+		"fields": []any{
+			map[string]any{"name": "city", "label": "City", "required": true},
+			map[string]any{"name": "zip", "label": "ZIP"},
+		},
+	})
+
+	if testAskUserMgr.AnswerText("telegram", "200", "100", "City: Hanoi") {
+		t.Fatal("reply in another chat consumed")
+	}
+	if testAskUserMgr.AnswerText("telegram", "100", "100", "ZIP: 10000") {
+		t.Fatal("reply missing a required field consumed")
+	}
+	if !testAskUserMgr.AnswerText("telegram", "100", "100|alice", "City: Hanoi\nzip: 10000") {
+		t.Fatal("reply not consumed")
+	}
+
+	out := decodeAskUserResult(t, <-done)
+	fields, _ := out["fields"].(map[string]any)
+	if fields["city"] != "Hanoi" || fields["zip"] != "10000" {
+		t.Errorf("fields = %v (question %s)", out, msg.ID)
+	}
+}
+
+func TestAskUser_TimeoutAndInternalChannel(t *testing.T) {
+	start := time.Now()
+	_, done := askAndWait(t, map[string]any{
+		"question":    "Pick one",
+		"options":     []any{"a", "b"},
+		"timeout_sec": float64(1),
+	})
+	out := decodeAskUserResult(t, <-done)
+	if out["status"] != "timeout" || time.Since(start) > 5*time.Second {
+		t.Errorf("result = %v after %s", out, time.Since(start))
+	}
+	if testAskUserMgr.AnswerText("telegram", "100", "100", "1") {
+		t.Error("expired question still answerable")
+	}
+
+	tool := NewAskUserTool(testAskUserMgr)
+	tool.SetMessageBus(bus.New())
+	if res := tool.Execute(WithToolChannel(approvalCtx(), "ws"), map[string]any{"question": "x", "options": []any{"a", "b"}}); !res.IsError {
+		t.Error("internal channel accepted")
+	}
+}
+
+func TestAskUser_OnChangeAnnouncesQuestion(t *testing.T) {
+	type change struct {
+		q       PendingQuestion
+		waiting bool
+	}
+	changes := make(chan change, 2)
+	testAskUserMgr.SetOnChange(func(q PendingQuestion, waiting bool) { changes <- change{q, waiting} })
+	defer testAskUserMgr.SetOnChange(nil)
+
+	msg, done := askAndWait(t, map[string]any{"question": "Ship it?", "options": []any{"Yes", "No"}})
+	started := <-changes
+	if !started.waiting || started.q.ID != msg.ID || started.q.Channel != "telegram" || started.q.ChatID != "100" ||
+		!started.q.ExpiresAt.After(time.Now()) {
+		t.Fatalf("start = %+v", started)
+	}
+
+	if !testAskUserMgr.AnswerText("telegram", "100", "100", "Yes") {
+		t.Fatal("reply not consumed")
+	}
+	<-done
+	if ended := <-changes; ended.waiting || ended.q.ID != msg.ID {
+		t.Fatalf("end = %+v", ended)
+	}
+}
@@ -0,0 +1,22 @@
+package tools
+
+import "context"
+
+// ToolCallHook wraps every tool execution made with a context. exec runs the
+// real tool; a hook may call it, replace its result, or skip it entirely
+// (e.g. the eval runner stubbing tools with canned results). name is the
+// canonical tool name.
+type ToolCallHook func(ctx context.Context, name string, args map[string]any, exec func() *Result) *Result
+
+type toolCallHookKey struct{}
+
+// WithToolCallHook installs hook for tool calls made with ctx.
+func WithToolCallHook(ctx context.Context, hook ToolCallHook) context.Context {
+	return context.WithValue(ctx, toolCallHookKey{}, hook)
+}
+
+// ToolCallHookFromCtx returns the hook installed by WithToolCallHook, or nil.
+func ToolCallHookFromCtx(ctx context.Context) ToolCallHook {
+	h, _ := ctx.Value(toolCallHookKey{}).(ToolCallHook)
+	return h
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/nextlevelbuilder/goclaw/internal/bootstrap"
 	"github.com/nextlevelbuilder/goclaw/internal/cache"
 	"github.com/nextlevelbuilder/goclaw/internal/store"
+	"github.com/nextlevelbuilder/goclaw/internal/tracing"
 )
 
 // protectedFileSet defines files that require group file writer permission in group chats.
@@ -67,6 +68,25 @@ func isContextFile(path, workspace string) (fileName string, ok bool) {
 	return "", false
 }
 
+// approvalFileSet is the set of identity files whose agent edits are held
+// for owner approval when the agent has require_file_approval enabled.
+var approvalFileSet = map[string]bool{
+	bootstrap.SoulFile:     true,
+	bootstrap.IdentityFile: true,
+	bootstrap.AgentsFile:   true,
+}
+
+// PendingFileChangeError is returned by WriteFile when an edit was queued for
+// owner approval instead of being written.
+type PendingFileChangeError struct {
+	FileName string
+	ChangeID uuid.UUID
+}
+
+func (e *PendingFileChangeError) Error() string {
+	return fmt.Sprintf("change to %s submitted for owner approval (change %s); it takes effect once approved, do not write it again", e.FileName, e.ChangeID)
+}
+
 const defaultContextCacheTTL = 5 * time.Minute
 
 // ContextFileInterceptor routes context file reads/writes to the agent store.
@@ -79,6 +99,7 @@ type ContextFileInterceptor struct {
 	userCache        cache.Cache[[]store.AgentContextFileData] // user-level files, keyed by "agentID:userID"
 	ttl              time.Duration
 	permStore store.ConfigPermissionStore // nil = no group write restriction
+	revisions store.ContextFileRevisionStore // pending changes for require_file_approval (nil = writes fail closed)
 }
 
 // NewContextFileInterceptor creates an interceptor backed by the given agent store.
@@ -102,6 +123,11 @@ func (b *ContextFileInterceptor) SetConfigPermStore(s store.ConfigPermissionStor
 	b.permStore = s
 }
 
+// SetRevisionStore sets the store that holds identity file edits for approval.
+func (b *ContextFileInterceptor) SetRevisionStore(s store.ContextFileRevisionStore) {
+	b.revisions = s
+}
+
 // ReadFile attempts to read a context file from the DB (with cache).
 // Routes based on agent type from context:
 //   - "open": all files per-user → fallback to agent-level
@@ -199,6 +225,11 @@ func (b *ContextFileInterceptor) WriteFile(ctx context.Context, path, content st
 
 	userID := store.UserIDFromContext(ctx)
 	agentType := store.AgentTypeFromContext(ctx)
+	authorID := store.SenderIDFromContext(ctx)
+	if authorID == "" {
+		authorID = userID
+	}
+	ctx = store.WithContextFileAuthor(ctx, store.ContextFileAuthorAgent, authorID)
 
 	// Permission check: protected files in group context require allowlist membership.
 	// Exception: during bootstrap onboarding (BOOTSTRAP.md still exists for this user),
@@ -252,37 +283,75 @@ func (b *ContextFileInterceptor) WriteFile(ctx context.Context, path, content st
 			"agent_id", agentID,
 			"user_id", userID,
 		)
-		err := b.agentStore.SetAgentContextFile(ctx, agentID, fileName, content)
-		if err == nil {
-			b.InvalidateAgent(agentID)
-		}
-		return true, err
+		return true, b.setAgentFile(ctx, agentID, fileName, content)
 	}
 
 	// Open agent: all files per-user
 	if agentType == store.AgentTypeOpen && userID != "" {
-		err := b.agentStore.SetUserContextFile(ctx, agentID, userID, fileName, content)
-		if err == nil {
-			b.invalidateUser(agentID, userID)
-		}
-		return true, err
+		return true, b.setUserFile(ctx, agentID, userID, fileName, content)
 	}
 
 	// Predefined agent: only USER.md per-user
 	if agentType == store.AgentTypePredefined && userID != "" && fileName == bootstrap.UserFile {
-		err := b.agentStore.SetUserContextFile(ctx, agentID, userID, fileName, content)
-		if err == nil {
-			b.invalidateUser(agentID, userID)
-		}
-		return true, err
+		return true, b.setUserFile(ctx, agentID, userID, fileName, content)
 	}
 
 	// Default: agent-level
+	return true, b.setAgentFile(ctx, agentID, fileName, content)
+}
+
+// setAgentFile writes an agent-level file, or holds it for approval.
+func (b *ContextFileInterceptor) setAgentFile(ctx context.Context, agentID uuid.UUID, fileName, content string) error {
+	if held, err := b.holdForApproval(ctx, agentID, "", fileName, content); held {
+		return err
+	}
 	err := b.agentStore.SetAgentContextFile(ctx, agentID, fileName, content)
 	if err == nil {
 		b.InvalidateAgent(agentID)
 	}
-	return true, err
+	return err
+}
+
+// setUserFile writes a per-user file, or holds it for approval.
+func (b *ContextFileInterceptor) setUserFile(ctx context.Context, agentID uuid.UUID, userID, fileName, content string) error {
+	if held, err := b.holdForApproval(ctx, agentID, userID, fileName, content); held {
+		return err
+	}
+	err := b.agentStore.SetUserContextFile(ctx, agentID, userID, fileName, content)
+	if err == nil {
+		b.invalidateUser(agentID, userID)
+	}
+	return err
+}
+
+// holdForApproval queues the edit as a pending change when the running agent
+// requires approval for identity files. held is false when the write should
+// go ahead; otherwise err is a *PendingFileChangeError or a store error.
+func (b *ContextFileInterceptor) holdForApproval(ctx context.Context, agentID uuid.UUID, userID, fileName, content string) (held bool, err error) {
+	if !approvalFileSet[fileName] || !store.RequireFileApprovalFromContext(ctx) {
+		return false, nil
+	}
+	if b.revisions == nil {
+		return true, fmt.Errorf("%s requires owner approval, but approvals are not available", fileName)
+	}
+	author, authorID := store.ContextFileAuthorFromContext(ctx)
+	change := &store.ContextFileChange{
+		AgentID:  agentID,
+		UserID:   userID,
+		FileName: fileName,
+		Content:  content,
+		Author:   author,
+		AuthorID: authorID,
+	}
+	if traceID := tracing.TraceIDFromContext(ctx); traceID != uuid.Nil {
+		change.TraceID = &traceID
+	}
+	if err := b.revisions.CreatePendingChange(ctx, change); err != nil {
+		return true, fmt.Errorf("queue %s for approval: %w", fileName, err)
+	}
+	slog.Info("context file change held for approval",
+		"agent_id", agentID, "user_id", userID, "file", fileName, "change_id", change.ID)
+	return true, &PendingFileChangeError{FileName: fileName, ChangeID: change.ID}
 }
 
 // LoadContextFiles loads context files for a specific user+agent combination.
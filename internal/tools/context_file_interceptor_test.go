@@ -2,6 +2,7 @@ package tools
 
 import (
 	"context"
+	"errors"
 	"sync/atomic"
 	"testing"
 	"time"
@@ -291,3 +292,57 @@ func TestInterceptor_TTLExpiry(t *testing.T) {
 		t.Errorf("after TTL expiry expected 2 store calls, got %d", n)
 	}
 }
+
+type stubRevisionStore struct {
+	store.ContextFileRevisionStore // unused methods panic
+	changes []store.ContextFileChange
+}
+
+func (s *stubRevisionStore) CreatePendingChange(_ context.Context, c *store.ContextFileChange) error {
+	c.ID = uuid.New()
+	s.changes = append(s.changes, *c)
+	return nil
+}
+
+// TestInterceptor_RequireFileApproval verifies that agent edits to identity
+// files are held as pending changes while other files are written directly.
+func TestInterceptor_RequireFileApproval(t *testing.T) {
+	agentID := uuid.New()
+	as := &stubAgentStore{}
+	rs := &stubRevisionStore{}
+	intc := NewContextFileInterceptor(as, "",
+		cache.NewInMemoryCache[[]store.AgentContextFileData](),
+		cache.NewInMemoryCache[[]store.AgentContextFileData](),
+	)
+	intc.SetRevisionStore(rs)
+
+	ctx := store.WithRunContext(context.Background(), &store.RunContext{
+		AgentID:             agentID,
+		UserID:              "u1",
+		AgentType:           store.AgentTypeOpen,
+		RequireFileApproval: true,
+	})
+	ctx = store.WithAgentID(ctx, agentID)
+	ctx = store.WithUserID(ctx, "u1")
+	ctx = store.WithAgentType(ctx, store.AgentTypeOpen)
+
+	handled, err := intc.WriteFile(ctx, "SOUL.md", "be terse")
+	var pending *PendingFileChangeError
+	if !handled || !errors.As(err, &pending) {
+		t.Fatalf("WriteFile(SOUL.md) = %v, %v; want pending change", handled, err)
+	}
+	if as.setUserCallN.Load() != 0 {
+		t.Error("SOUL.md was written despite require_file_approval")
+	}
+	if len(rs.changes) != 1 || rs.changes[0].ID != pending.ChangeID || rs.changes[0].UserID != "u1" ||
+		rs.changes[0].Author != store.ContextFileAuthorAgent || rs.changes[0].Content != "be terse" {
+		t.Fatalf("changes = %+v", rs.changes)
+	}
+
+	if _, err := intc.WriteFile(ctx, "USER.md", "likes cats"); err != nil {
+		t.Fatalf("WriteFile(USER.md): %v", err)
+	}
+	if as.setUserCallN.Load() != 1 || len(rs.changes) != 1 {
+		t.Errorf("USER.md should be written directly (writes=%d, changes=%d)", as.setUserCallN.Load(), len(rs.changes))
+	}
+}
@@ -2,6 +2,7 @@ package tools
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -118,6 +119,10 @@ func (t *EditTool) Execute(ctx context.Context, args map[string]any) *Result {
 				return result
 			}
 			if _, err := t.contextFileIntc.WriteFile(ctx, path, newContent); err != nil {
+				var pending *PendingFileChangeError
+				if errors.As(err, &pending) {
+					return SilentResult(pending.Error())
+				}
 				return ErrorResult(fmt.Sprintf("failed to write context file: %v", err))
 			}
 			return SilentResult(fmt.Sprintf("Context file edited: %s", path))
@@ -2,6 +2,7 @@ package tools
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -112,6 +113,10 @@ func (t *WriteFileTool) Execute(ctx context.Context, args map[string]any) *Resul
 	// Virtual FS: route context files to DB
 	if t.contextFileIntc != nil {
 		if handled, err := t.contextFileIntc.WriteFile(ctx, path, content); handled {
+			var pending *PendingFileChangeError
+			if errors.As(err, &pending) {
+				return SilentResult(pending.Error())
+			}
 			if err != nil {
 				return ErrorResult(fmt.Sprintf("failed to write context file: %v", err))
 			}
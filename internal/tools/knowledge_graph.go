@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"log/slog"
 	"strings"
+	"time"
 
 	"github.com/google/uuid"
 
@@ -29,7 +30,7 @@ func (t *KnowledgeGraphSearchTool) SetKGStore(ks store.KnowledgeGraphStore) {
 func (t *KnowledgeGraphSearchTool) Name() string { return "knowledge_graph_search" }
 
 func (t *KnowledgeGraphSearchTool) Description() string {
-	return "Search the knowledge graph to find people, projects, organizations, and how they connect. Better than memory_search when you need: who works with whom, what projects someone is involved in, dependencies between tasks, or any multi-hop relationship question. Use specific names (e.g. 'Minh', 'GoClaw') — not generic words. Use query='*' to list all known entities. Use entity_id to traverse connections from a specific entity."
+	return "Search the knowledge graph to find people, projects, organizations, and how they connect. Better than memory_search when you need: who works with whom, what projects someone is involved in, dependencies between tasks, or any multi-hop relationship question. Use specific names (e.g. 'Minh', 'GoClaw') — not generic words. Use query='*' to list all known entities. Use entity_id to traverse connections from a specific entity. Facts have validity periods: use as_of to ask what was true at a past date, or history=true with entity_id to see how an entity's facts changed over time."
 }
 
 func (t *KnowledgeGraphSearchTool) Parameters() map[string]any {
@@ -52,6 +53,14 @@ func (t *KnowledgeGraphSearchTool) Parameters() map[string]any {
 				"type":        "number",
 				"description": "Maximum traversal depth (default 2, max 3)",
 			},
+			"as_of": map[string]any{
+				"type":        "string",
+				"description": "Answer with facts valid at this date (YYYY-MM-DD or RFC 3339) instead of now",
+			},
+			"history": map[string]any{
+				"type":        "boolean",
+				"description": "With entity_id: list every version of the entity's facts, including ended and superseded ones",
+			},
 		},
 		"required": []string{"query"},
 	}
@@ -79,9 +88,18 @@ func (t *KnowledgeGraphSearchTool) Execute(ctx context.Context, args map[string]
 		maxDepth = min(int(md), 3)
 	}
 
+	asOfStr, _ := args["as_of"].(string)
+	asOf, err := store.ParseKGTime(asOfStr)
+	if err != nil {
+		return ErrorResult(err.Error())
+	}
+
 	// Traversal mode: entity_id provided
 	if entityID != "" {
-		return t.executeTraversal(ctx, agentID.String(), userID, entityID, maxDepth, query)
+		if history, _ := args["history"].(bool); history {
+			return t.executeHistory(ctx, agentID.String(), userID, entityID)
+		}
+		return t.executeTraversal(ctx, agentID.String(), userID, entityID, maxDepth, query, asOf)
 	}
 
 	// List-all mode: query="*"
@@ -90,12 +108,12 @@ func (t *KnowledgeGraphSearchTool) Execute(ctx context.Context, args map[string]
 	}
 
 	// Search mode
-	return t.executeSearch(ctx, agentID.String(), userID, query, args)
+	return t.executeSearch(ctx, agentID.String(), userID, query, args, asOf)
 }
 
-func (t *KnowledgeGraphSearchTool) executeTraversal(ctx context.Context, agentID, userID, entityID string, maxDepth int, query string) *Result {
+func (t *KnowledgeGraphSearchTool) executeTraversal(ctx context.Context, agentID, userID, entityID string, maxDepth int, query string, asOf int64) *Result {
 	// Tier 1: outgoing deep traversal
-	results, err := t.kgStore.Traverse(ctx, agentID, userID, entityID, maxDepth)
+	results, err := t.kgStore.Traverse(ctx, agentID, userID, entityID, maxDepth, asOf)
 	if err != nil {
 		return ErrorResult(fmt.Sprintf("graph traversal failed: %v", err))
 	}
@@ -106,7 +124,7 @@ func (t *KnowledgeGraphSearchTool) executeTraversal(ctx context.Context, agentID
 			results = results[:maxTraversalResults]
 		}
 		var sb strings.Builder
-		sb.WriteString(fmt.Sprintf("Graph traversal from %q (max depth %d):\n\n", entityID, maxDepth))
+		sb.WriteString(fmt.Sprintf("Graph traversal from %q (max depth %d%s):\n\n", entityID, maxDepth, asOfLabel(asOf)))
 		for _, r := range results {
 			sb.WriteString(fmt.Sprintf("- [depth %d] %s (%s)", r.Depth, r.Entity.Name, r.Entity.EntityType))
 			if r.Via != "" {
@@ -131,7 +149,7 @@ func (t *KnowledgeGraphSearchTool) executeTraversal(ctx context.Context, agentID
 	}
 
 	// Tier 2: direct connections (bidirectional, 1-hop, cap 10)
-	relations, relErr := t.kgStore.ListRelations(ctx, agentID, userID, entityID)
+	relations, relErr := t.relationsAt(ctx, agentID, userID, entityID, asOf)
 	if relErr != nil {
 		slog.Warn("kg.listRelations failed", "entity_id", entityID, "error", relErr)
 	}
@@ -144,11 +162,11 @@ func (t *KnowledgeGraphSearchTool) executeTraversal(ctx context.Context, agentID
 		nameCache := make(map[string]string)
 		entityName := t.resolveEntityName(ctx, agentID, userID, entityID, nameCache)
 		var sb strings.Builder
-		sb.WriteString(fmt.Sprintf("Direct connections of %q:\n\n", entityName))
+		sb.WriteString(fmt.Sprintf("Direct connections of %q%s:\n\n", entityName, asOfLabel(asOf)))
 		for _, rel := range relations {
 			srcName := t.resolveEntityName(ctx, agentID, userID, rel.SourceEntityID, nameCache)
 			tgtName := t.resolveEntityName(ctx, agentID, userID, rel.TargetEntityID, nameCache)
-			sb.WriteString(fmt.Sprintf("  %s —[%s]→ %s\n", srcName, rel.RelationType, tgtName))
+			sb.WriteString(fmt.Sprintf("  %s —[%s]→ %s%s\n", srcName, rel.RelationType, tgtName, validityLabel(rel)))
 		}
 		if totalCount > maxDirectConnections {
 			sb.WriteString(fmt.Sprintf("\n(%d more connections not shown)\n", totalCount-maxDirectConnections))
@@ -158,7 +176,7 @@ func (t *KnowledgeGraphSearchTool) executeTraversal(ctx context.Context, agentID
 
 	// Tier 3: fallback to search if query provided
 	if query != "" && query != "*" {
-		return t.executeSearch(ctx, agentID, userID, query, nil)
+		return t.executeSearch(ctx, agentID, userID, query, nil, asOf)
 	}
 
 	return NewResult(fmt.Sprintf("No connected entities found from entity_id=%q.", entityID))
@@ -184,7 +202,7 @@ func (t *KnowledgeGraphSearchTool) executeListAll(ctx context.Context, agentID,
 	return NewResult(sb.String())
 }
 
-func (t *KnowledgeGraphSearchTool) executeSearch(ctx context.Context, agentID, userID, query string, args map[string]any) *Result {
+func (t *KnowledgeGraphSearchTool) executeSearch(ctx context.Context, agentID, userID, query string, args map[string]any, asOf int64) *Result {
 	entities, err := t.kgStore.SearchEntities(ctx, agentID, userID, query, 10)
 	if err != nil {
 		return ErrorResult(fmt.Sprintf("entity search failed: %v", err))
@@ -225,7 +243,7 @@ func (t *KnowledgeGraphSearchTool) executeSearch(ctx context.Context, agentID, u
 		}
 
 		// Fetch relations to show connections with names (cap 5 per entity)
-		relations, err := t.kgStore.ListRelations(ctx, agentID, userID, e.ID)
+		relations, err := t.relationsAt(ctx, agentID, userID, e.ID, asOf)
 		if err == nil && len(relations) > 0 {
 			const maxRelationsPerEntity = 5
 			sb.WriteString("  Relations:\n")
@@ -233,7 +251,7 @@ func (t *KnowledgeGraphSearchTool) executeSearch(ctx context.Context, agentID, u
 			for _, rel := range relations[:shown] {
 				srcName := t.resolveEntityName(ctx, agentID, userID, rel.SourceEntityID, entityNames)
 				tgtName := t.resolveEntityName(ctx, agentID, userID, rel.TargetEntityID, entityNames)
-				sb.WriteString(fmt.Sprintf("    %s —[%s]→ %s\n", srcName, rel.RelationType, tgtName))
+				sb.WriteString(fmt.Sprintf("    %s —[%s]→ %s%s\n", srcName, rel.RelationType, tgtName, validityLabel(rel)))
 			}
 			if len(relations) > maxRelationsPerEntity {
 				sb.WriteString(fmt.Sprintf("    (+%d more, use entity_id=%q to see all)\n", len(relations)-maxRelationsPerEntity, e.ID))
@@ -243,6 +261,82 @@ func (t *KnowledgeGraphSearchTool) executeSearch(ctx context.Context, agentID, u
 	return NewResult(sb.String())
 }
 
+// executeHistory lists every version of an entity's facts, newest first.
+func (t *KnowledgeGraphSearchTool) executeHistory(ctx context.Context, agentID, userID, entityID string) *Result {
+	relations, err := t.kgStore.ListRelationHistory(ctx, agentID, userID, entityID)
+	if err != nil {
+		return ErrorResult(fmt.Sprintf("relation history failed: %v", err))
+	}
+	nameCache := make(map[string]string)
+	entityName := t.resolveEntityName(ctx, agentID, userID, entityID, nameCache)
+	if len(relations) == 0 {
+		return NewResult(fmt.Sprintf("No facts recorded for %q.", entityName))
+	}
+
+	const maxHistoryEntries = 30
+	totalCount := len(relations)
+	if totalCount > maxHistoryEntries {
+		relations = relations[:maxHistoryEntries]
+	}
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("Fact history of %q (newest first):\n\n", entityName))
+	for _, rel := range relations {
+		srcName := t.resolveEntityName(ctx, agentID, userID, rel.SourceEntityID, nameCache)
+		tgtName := t.resolveEntityName(ctx, agentID, userID, rel.TargetEntityID, nameCache)
+		status := "current"
+		if rel.SupersededBy != "" {
+			status = "superseded"
+		} else if rel.ValidTo != 0 {
+			status = "ended"
+		}
+		sb.WriteString(fmt.Sprintf("  [%s] %s —[%s]→ %s%s\n", status, srcName, rel.RelationType, tgtName, validityLabel(rel)))
+	}
+	if totalCount > maxHistoryEntries {
+		sb.WriteString(fmt.Sprintf("\n(%d older facts not shown)\n", totalCount-maxHistoryEntries))
+	}
+	return NewResult(sb.String())
+}
+
+// relationsAt returns an entity's relations valid at asOf (0 = current facts).
+func (t *KnowledgeGraphSearchTool) relationsAt(ctx context.Context, agentID, userID, entityID string, asOf int64) ([]store.Relation, error) {
+	if asOf <= 0 {
+		return t.kgStore.ListRelations(ctx, agentID, userID, entityID)
+	}
+	history, err := t.kgStore.ListRelationHistory(ctx, agentID, userID, entityID)
+	if err != nil {
+		return nil, err
+	}
+	var out []store.Relation
+	for _, rel := range history {
+		if rel.ValidAt(asOf) {
+			out = append(out, rel)
+		}
+	}
+	return out, nil
+}
+
+func asOfLabel(asOf int64) string {
+	if asOf <= 0 {
+		return ""
+	}
+	return ", as of " + kgDate(asOf)
+}
+
+// validityLabel renders a fact's validity period, e.g. " (2021-05-01 → 2024-03-10)".
+func validityLabel(rel store.Relation) string {
+	switch {
+	case rel.ValidTo != 0:
+		return fmt.Sprintf(" (%s → %s)", kgDate(rel.ValidFrom), kgDate(rel.ValidTo))
+	case rel.ValidFrom != 0:
+		return fmt.Sprintf(" (since %s)", kgDate(rel.ValidFrom))
+	}
+	return ""
+}
+
+func kgDate(ms int64) string {
+	return time.UnixMilli(ms).UTC().Format("2006-01-02")
+}
+
 // resolveEntityName returns a human-readable name for an entity ID, using cache or DB lookup.
 func (t *KnowledgeGraphSearchTool) resolveEntityName(ctx context.Context, agentID, userID, entityID string, cache map[string]string) string {
 	if name, ok := cache[entityID]; ok {
@@ -72,6 +72,16 @@ func (m *mockKGStore) UpsertRelation(_ context.Context, r *store.Relation) error
 func (m *mockKGStore) DeleteRelation(context.Context, string, string, string) error { return nil }
 
 func (m *mockKGStore) ListRelations(_ context.Context, _, _, entityID string) ([]store.Relation, error) {
+	var out []store.Relation
+	for _, r := range m.relations {
+		if (r.SourceEntityID == entityID || r.TargetEntityID == entityID) && r.ValidTo == 0 {
+			out = append(out, r)
+		}
+	}
+	return out, nil
+}
+
+func (m *mockKGStore) ListRelationHistory(_ context.Context, _, _, entityID string) ([]store.Relation, error) {
 	var out []store.Relation
 	for _, r := range m.relations {
 		if r.SourceEntityID == entityID || r.TargetEntityID == entityID {
@@ -85,7 +95,7 @@ func (m *mockKGStore) ListAllRelations(context.Context, string, string, int) ([]
 	return m.relations, nil
 }
 
-func (m *mockKGStore) Traverse(_ context.Context, _, _, startEntityID string, _ int) ([]store.TraversalResult, error) {
+func (m *mockKGStore) Traverse(_ context.Context, _, _, startEntityID string, _ int, _ int64) ([]store.TraversalResult, error) {
 	return m.traversal[startEntityID], nil
 }
 
@@ -199,7 +209,7 @@ func TestKGTraversal_Tier1_OutgoingEdges(t *testing.T) {
 	tool.SetKGStore(ms)
 
 	ctx := kgContext()
-	result := tool.executeTraversal(ctx, testAgentID.String(), testUserID, ids["A"], 2, "Viettx")
+	result := tool.executeTraversal(ctx, testAgentID.String(), testUserID, ids["A"], 2, "Viettx", 0)
 	text := result.ForLLM
 
 	if !strings.Contains(text, "GoClaw") {
@@ -220,7 +230,7 @@ func TestKGTraversal_Tier2_OnlyIncomingEdges(t *testing.T) {
 
 	ctx := kgContext()
 	// D=Kuwait has 0 outgoing, 1 incoming (C→D)
-	result := tool.executeTraversal(ctx, testAgentID.String(), testUserID, ids["D"], 2, "Kuwait")
+	result := tool.executeTraversal(ctx, testAgentID.String(), testUserID, ids["D"], 2, "Kuwait", 0)
 	text := result.ForLLM
 
 	if !strings.Contains(text, "Direct connections") {
@@ -241,7 +251,7 @@ func TestKGTraversal_Tier3_IsolatedWithQuery(t *testing.T) {
 
 	ctx := kgContext()
 	// E=Chiến sự TĐ: 0 outgoing, 0 incoming, but searchable by name
-	result := tool.executeTraversal(ctx, testAgentID.String(), testUserID, ids["E"], 2, "Chiến sự")
+	result := tool.executeTraversal(ctx, testAgentID.String(), testUserID, ids["E"], 2, "Chiến sự", 0)
 	text := result.ForLLM
 
 	if !strings.Contains(text, "Chiến sự Trung Đông") {
@@ -259,7 +269,7 @@ func TestKGTraversal_Tier3_IsolatedNoQuery(t *testing.T) {
 
 	ctx := kgContext()
 	// E=isolated, no query fallback
-	result := tool.executeTraversal(ctx, testAgentID.String(), testUserID, ids["E"], 2, "")
+	result := tool.executeTraversal(ctx, testAgentID.String(), testUserID, ids["E"], 2, "", 0)
 	text := result.ForLLM
 
 	if !strings.Contains(text, "No connected entities found") {
@@ -288,7 +298,7 @@ func TestKGTraversal_Tier2_CappedAt10(t *testing.T) {
 	tool.SetKGStore(ms)
 
 	ctx := kgContext()
-	result := tool.executeTraversal(ctx, testAgentID.String(), testUserID, entityX, 2, "")
+	result := tool.executeTraversal(ctx, testAgentID.String(), testUserID, entityX, 2, "", 0)
 	text := result.ForLLM
 
 	count := strings.Count(text, "—[connects_to]→")
@@ -307,7 +317,7 @@ func TestKGTraversal_Tier1_SkipsTier2WhenTraversalHasResults(t *testing.T) {
 
 	ctx := kgContext()
 	// B=GoClaw has 1 outgoing (B→C) and 1 incoming (A→B)
-	result := tool.executeTraversal(ctx, testAgentID.String(), testUserID, ids["B"], 2, "GoClaw")
+	result := tool.executeTraversal(ctx, testAgentID.String(), testUserID, ids["B"], 2, "GoClaw", 0)
 	text := result.ForLLM
 
 	if !strings.Contains(text, "Dầu thô") {
@@ -339,7 +349,7 @@ func TestKGTraversal_Tier2_RelationFormat(t *testing.T) {
 	tool.SetKGStore(ms)
 
 	ctx := kgContext()
-	result := tool.executeTraversal(ctx, testAgentID.String(), testUserID, idF, 2, "")
+	result := tool.executeTraversal(ctx, testAgentID.String(), testUserID, idF, 2, "", 0)
 	text := result.ForLLM
 
 	// Outgoing: F —[owns]→ G
@@ -371,7 +381,7 @@ func TestKGTraversal_Tier1_CappedAt20(t *testing.T) {
 	tool.SetKGStore(ms)
 
 	ctx := kgContext()
-	result := tool.executeTraversal(ctx, testAgentID.String(), testUserID, startID, 2, "")
+	result := tool.executeTraversal(ctx, testAgentID.String(), testUserID, startID, 2, "", 0)
 	text := result.ForLLM
 
 	count := strings.Count(text, "links_to")
@@ -403,7 +413,7 @@ func TestKGSearch_RelationsCappedAt5(t *testing.T) {
 	tool.SetKGStore(ms)
 
 	ctx := kgContext()
-	result := tool.executeSearch(ctx, testAgentID.String(), testUserID, "HubEntity", nil)
+	result := tool.executeSearch(ctx, testAgentID.String(), testUserID, "HubEntity", nil, 0)
 	text := result.ForLLM
 
 	relCount := strings.Count(text, "—[connects]→")
@@ -417,3 +427,42 @@ func TestKGSearch_RelationsCappedAt5(t *testing.T) {
 		t.Error("expected hint to use entity_id for full relations")
 	}
 }
+
+func TestKGTraversal_AsOfAndHistory(t *testing.T) {
+	ms := newMockKGStore()
+	alice, hanoi, berlin := uuid.NewString(), uuid.NewString(), uuid.NewString()
+	for id, name := range map[string]string{alice: "Alice", hanoi: "Hanoi", berlin: "Berlin"} {
+		ms.entities[id] = store.Entity{ID: id, AgentID: testAgentID.String(), UserID: testUserID, Name: name}
+	}
+	march2020, _ := store.ParseKGTime("2020-03-01")
+	june2024, _ := store.ParseKGTime("2024-06-01")
+	berlinID := uuid.NewString()
+	ms.relations = []store.Relation{
+		{ID: berlinID, SourceEntityID: alice, RelationType: "located_in", TargetEntityID: berlin, ValidFrom: june2024},
+		{ID: uuid.NewString(), SourceEntityID: alice, RelationType: "located_in", TargetEntityID: hanoi, ValidFrom: march2020, ValidTo: june2024, SupersededBy: berlinID},
+	}
+
+	tool := NewKnowledgeGraphSearchTool()
+	tool.SetKGStore(ms)
+	ctx := kgContext()
+
+	now := tool.executeTraversal(ctx, testAgentID.String(), testUserID, alice, 2, "", 0).ForLLM
+	if !strings.Contains(now, "Alice —[located_in]→ Berlin") || strings.Contains(now, "Hanoi") {
+		t.Errorf("current facts should show Berlin only, got: %s", now)
+	}
+
+	asOf, _ := store.ParseKGTime("2023-01-01")
+	past := tool.executeTraversal(ctx, testAgentID.String(), testUserID, alice, 2, "", asOf).ForLLM
+	if !strings.Contains(past, "Alice —[located_in]→ Hanoi (2020-03-01 → 2024-06-01)") || strings.Contains(past, "Berlin") {
+		t.Errorf("facts as of 2023 should show Hanoi only, got: %s", past)
+	}
+
+	history := tool.Execute(ctx, map[string]any{"query": "Alice", "entity_id": alice, "history": true}).ForLLM
+	if !strings.Contains(history, "[current] Alice —[located_in]→ Berlin") || !strings.Contains(history, "[superseded] Alice —[located_in]→ Hanoi") {
+		t.Errorf("history should list both versions, got: %s", history)
+	}
+
+	if res := tool.Execute(ctx, map[string]any{"query": "Alice", "as_of": "last march"}); !res.IsError {
+		t.Error("invalid as_of should be rejected")
+	}
+}
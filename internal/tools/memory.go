@@ -8,13 +8,16 @@ import (
 
 	"github.com/google/uuid"
 
+	"github.com/nextlevelbuilder/goclaw/internal/config"
+	"github.com/nextlevelbuilder/goclaw/internal/memory"
 	"github.com/nextlevelbuilder/goclaw/internal/store"
 )
 
 // MemorySearchTool implements the memory_search tool for hybrid semantic + FTS search.
 type MemorySearchTool struct {
-	memStore store.MemoryStore // Postgres-backed
-	hasKG    bool              // knowledge_graph_search tool is available
+	memStore  store.MemoryStore // Postgres-backed
+	retriever *memory.Retriever // post-retrieval pipeline (nil = raw store search)
+	hasKG     bool              // knowledge_graph_search tool is available
 }
 
 func NewMemorySearchTool() *MemorySearchTool {
@@ -26,6 +29,12 @@ func (t *MemorySearchTool) SetMemoryStore(ms store.MemoryStore) {
 	t.memStore = ms
 }
 
+// SetRetriever routes searches through the reranking/MMR/recency pipeline
+// configured in the agent's memory_config.retrieval.
+func (t *MemorySearchTool) SetRetriever(r *memory.Retriever) {
+	t.retriever = r
+}
+
 // SetHasKG enables the KG hint in search results.
 func (t *MemorySearchTool) SetHasKG(has bool) {
 	t.hasKG = has
@@ -84,7 +93,9 @@ func (t *MemorySearchTool) Execute(ctx context.Context, args map[string]any) *Re
 		MinScore:   minScore,
 	}
 	// Apply per-agent memory config overrides if set
+	var retrievalCfg *config.MemoryRetrievalConfig
 	if mc := MemoryConfigFromCtx(ctx); mc != nil {
+		retrievalCfg = mc.Retrieval
 		if mc.MaxResults > 0 && searchOpts.MaxResults <= 0 {
 			searchOpts.MaxResults = mc.MaxResults
 		}
@@ -98,16 +109,23 @@ func (t *MemorySearchTool) Execute(ctx context.Context, args map[string]any) *Re
 			searchOpts.MinScore = mc.MinScore
 		}
 	}
+	search := func(agent, user string) ([]store.MemorySearchResult, error) {
+		if t.retriever != nil {
+			results, _, err := t.retriever.Search(ctx, query, agent, user, searchOpts, retrievalCfg)
+			return results, err
+		}
+		return t.memStore.Search(ctx, query, agent, user, searchOpts)
+	}
 	agentStr := agentID.String()
-	results, err := t.memStore.Search(ctx, query, agentStr, userID, searchOpts)
+	results, err := search(agentStr, userID)
 	if err != nil {
 		return ErrorResult(fmt.Sprintf("memory search failed: %v", err))
 	}
 	// Fallback: also search leader's memory for team members and merge results.
 	if leaderID := LeaderAgentIDFromCtx(ctx); leaderID != "" && leaderID != agentStr {
-		leaderResults, lerr := t.memStore.Search(ctx, query, leaderID, userID, searchOpts)
+		leaderResults, lerr := search(leaderID, userID)
 		if lerr != nil && userID != "" {
-			leaderResults, _ = t.memStore.Search(ctx, query, leaderID, "", searchOpts)
+			leaderResults, _ = search(leaderID, "")
 		}
 		results = append(results, leaderResults...)
 	}
@@ -11,6 +11,7 @@ import (
 
 	"github.com/nextlevelbuilder/goclaw/internal/bootstrap"
 	"github.com/nextlevelbuilder/goclaw/internal/store"
+	"github.com/nextlevelbuilder/goclaw/internal/tracing"
 )
 
 // effectiveWorkspace returns the per-user workspace from ctx if available,
@@ -202,7 +203,13 @@ func (m *MemoryInterceptor) WriteFile(ctx context.Context, path, content string,
 	kgTriggered := false
 	if m.kgExtractFn != nil && content != "" {
 		kgUserID := store.KGUserID(ctx)
-		go m.kgExtractFn(context.WithoutCancel(ctx), agentStr, kgUserID, content)
+		// Facts extracted from this write link back to the document and the
+		// session run that wrote it.
+		prov := store.KGProvenance{Kind: store.KGSourceMemory, SessionKey: ToolSessionKeyFromCtx(ctx), Document: relPath}
+		if traceID := tracing.TraceIDFromContext(ctx); traceID != uuid.Nil {
+			prov.TraceID = traceID.String()
+		}
+		go m.kgExtractFn(store.WithKGProvenance(context.WithoutCancel(ctx), prov), agentStr, kgUserID, content)
 		kgTriggered = true
 	}
 
@@ -7,6 +7,7 @@ import (
 	"os"
 	"strings"
 
+	"github.com/nextlevelbuilder/goclaw/internal/docextract"
 	"github.com/nextlevelbuilder/goclaw/internal/providers"
 )
 
@@ -60,23 +61,37 @@ var documentModelDefaults = map[string]string{
 	"dashscope":  "qwen-vl-max",
 }
 
-// ReadDocumentTool uses a document-capable provider to analyze files
-// attached to the current conversation. Follows same pattern as ReadImageTool.
+// documentMinTextPerPage is the least extracted text (non-whitespace bytes per
+// page) for a local PDF extraction to count; below it the PDF is most likely
+// scanned and goes to the provider chain.
+const documentMinTextPerPage = 20
+
+// ReadDocumentTool analyzes files attached to the current conversation.
+// PDF, DOCX, XLSX and PPTX files are extracted locally first; everything
+// else (and scans without a text layer) goes to a document-capable provider.
+// Follows same pattern as ReadImageTool.
 type ReadDocumentTool struct {
 	registry    *providers.Registry
 	mediaLoader MediaPathLoader
+	extractor   *docextract.Extractor
 }
 
 func NewReadDocumentTool(registry *providers.Registry, mediaLoader MediaPathLoader) *ReadDocumentTool {
-	return &ReadDocumentTool{registry: registry, mediaLoader: mediaLoader}
+	return &ReadDocumentTool{registry: registry, mediaLoader: mediaLoader, extractor: docextract.New("")}
 }
 
+// SetExtractor replaces the default in-memory extractor, e.g. with one that
+// also caches on disk.
+func (t *ReadDocumentTool) SetExtractor(e *docextract.Extractor) { t.extractor = e }
+
 func (t *ReadDocumentTool) Name() string { return "read_document" }
 
 func (t *ReadDocumentTool) Description() string {
 	return "Analyze documents (PDF, DOCX, images of documents, etc.) attached to the conversation. " +
 		"Use when you see <media:document> tags and need to extract or analyze document content. " +
-		"Specify what you want to extract or analyze."
+		"Specify what you want to extract or analyze. " +
+		"PDF, Word, Excel and PowerPoint files with a text layer are returned as extracted markdown " +
+		"(pages, headings, tables, sheets, slides with notes) — answer from that text."
 }
 
 func (t *ReadDocumentTool) Parameters() map[string]any {
@@ -131,6 +146,12 @@ func (t *ReadDocumentTool) Execute(ctx context.Context, args map[string]any) *Re
 		return NewResult(content)
 	}
 
+	// Local extraction: structured text for office formats and PDFs with a
+	// text layer, cached by content hash.
+	if content, ok := t.extractLocally(ctx, data); ok {
+		return NewResult(content)
+	}
+
 	chain := ResolveMediaProviderChain(ctx, "read_document", "", "",
 		documentProviderPriority, documentModelDefaults, t.registry)
 
@@ -155,3 +176,31 @@ func (t *ReadDocumentTool) Execute(ctx context.Context, args map[string]any) *Re
 	result.Model = chainResult.Model
 	return result
 }
+
+// extractLocally returns the document as markdown if it can be extracted
+// without a provider.
+func (t *ReadDocumentTool) extractLocally(ctx context.Context, data []byte) (string, bool) {
+	if t.extractor == nil || docextract.Detect(data) == "" {
+		return "", false
+	}
+	doc, err := t.extractor.Extract(ctx, data)
+	if err != nil {
+		slog.Info("read_document: local extraction failed, using providers", "error", err)
+		return "", false
+	}
+	minText := 1
+	if doc.Format == docextract.FormatPDF {
+		minText = documentMinTextPerPage * len(doc.Sections)
+	}
+	if doc.TextLen() < minText {
+		slog.Info("read_document: too little text extracted, using providers", "format", doc.Format, "text_len", doc.TextLen())
+		return "", false
+	}
+
+	content := doc.Markdown()
+	if len(content) > documentMaxTextBytes {
+		content = content[:documentMaxTextBytes] + "\n\n[... truncated at 500KB ...]"
+	}
+	slog.Info("read_document: extracted locally", "summary", doc.Summary(), "size", len(content))
+	return fmt.Sprintf("[Extracted locally: %s]\n\n%s", doc.Summary(), content), true
+}
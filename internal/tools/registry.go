@@ -19,8 +19,9 @@ type Registry struct {
 	aliases     map[string]string // alias name → canonical tool name
 	disabled    map[string]bool   // tools disabled via admin UI (kept in registry, excluded from List)
 	mu          sync.RWMutex
-	rateLimiter *ToolRateLimiter // nil = no rate limiting
-	scrubbing   bool             // scrub credentials from output (default true)
+	rateLimiter *ToolRateLimiter     // nil = no rate limiting
+	scrubbing   bool                 // scrub credentials from output (default true)
+	approvals   *ToolApprovalManager // nil = no tool approval rules
 
 	// deferredActivator is called when a tool is not in the registry but may be
 	// a deferred MCP tool. Returns true if the tool was successfully activated.
@@ -66,6 +67,11 @@ func (r *Registry) SetScrubbing(enabled bool) {
 	r.scrubbing = enabled
 }
 
+// SetApprovals enables human approval for tool calls matching the manager's rules.
+func (r *Registry) SetApprovals(m *ToolApprovalManager) {
+	r.approvals = m
+}
+
 // Register adds a tool to the registry.
 func (r *Registry) Register(tool Tool) {
 	r.mu.Lock()
@@ -188,7 +194,21 @@ func (r *Registry) ExecuteWithContext(ctx context.Context, name string, args map
 	}
 
 	start := time.Now()
-	result := safeExecute(tool, ctx, args)
+	exec := func() *Result {
+		// Approval gating happens inside exec so stubbed (eval) calls skip it.
+		if r.approvals != nil {
+			if denied := r.approvals.Gate(ctx, tool.Name(), args); denied != nil {
+				return denied
+			}
+		}
+		return safeExecute(tool, ctx, args)
+	}
+	var result *Result
+	if hook := ToolCallHookFromCtx(ctx); hook != nil {
+		result = hook(ctx, tool.Name(), args, exec)
+	} else {
+		result = exec()
+	}
 	duration := time.Since(start)
 
 	// Scrub credentials from tool output before returning to LLM
@@ -289,7 +309,7 @@ func (r *Registry) Count() int {
 }
 
 // Clone creates a shallow copy of the registry with all registered tools and aliases.
-// The clone shares the rate limiter (thread-safe) and scrubbing setting.
+// The clone shares the rate limiter (thread-safe), approvals and scrubbing setting.
 // Used by subagent toolsFactory so subagents inherit parent tools (web_fetch, web_search, etc.).
 func (r *Registry) Clone() *Registry {
 	r.mu.RLock()
@@ -300,6 +320,7 @@ func (r *Registry) Clone() *Registry {
 		disabled:    make(map[string]bool, len(r.disabled)),
 		rateLimiter: r.rateLimiter,
 		scrubbing:   r.scrubbing,
+		approvals:   r.approvals,
 	}
 	maps.Copy(clone.tools, r.tools)
 	maps.Copy(clone.aliases, r.aliases)
@@ -377,3 +377,32 @@ func TestRegistry_TryActivateDeferred_NilActivatorAfterSet(t *testing.T) {
 		t.Error("expected false after setting nil activator")
 	}
 }
+
+func TestRegistry_ExecuteWithContext_CallHook(t *testing.T) {
+	reg := NewRegistry()
+	ran := 0
+	reg.Register(&mockTool{name: "real", execFn: func(ctx context.Context, args map[string]any) *Result {
+		ran++
+		return NewResult("real result")
+	}})
+	reg.RegisterAlias("alias", "real")
+
+	var seen []string
+	ctx := WithToolCallHook(context.Background(), func(ctx context.Context, name string, args map[string]any, exec func() *Result) *Result {
+		seen = append(seen, name)
+		if args["stub"] == true {
+			return NewResult("stubbed")
+		}
+		return exec()
+	})
+
+	if r := reg.Execute(ctx, "alias", map[string]any{"stub": true}); r.ForLLM != "stubbed" || ran != 0 {
+		t.Errorf("stubbed call = %q (ran %d), want stub without executing", r.ForLLM, ran)
+	}
+	if r := reg.Execute(ctx, "real", map[string]any{"stub": false}); r.ForLLM != "real result" || ran != 1 {
+		t.Errorf("pass-through call = %q (ran %d), want real result", r.ForLLM, ran)
+	}
+	if strings.Join(seen, ",") != "real,real" {
+		t.Errorf("hook saw %v, want canonical names", seen)
+	}
+}
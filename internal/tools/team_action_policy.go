@@ -15,7 +15,7 @@ type TeamActionPolicy interface {
 type FullTeamPolicy struct{}
 
 var fullActions = []string{
-	"list", "get", "create", "claim", "complete", "cancel",
+	"list", "get", "create", "create_plan", "claim", "complete", "cancel",
 	"approve", "reject", "search", "review", "comment",
 	"progress", "attach", "update", "ask_user", "clear_ask_user", "retry",
 	"release_to_worker", "interrupt_worker", "inject_message",
@@ -35,7 +35,7 @@ func (FullTeamPolicy) MemberGuidance() string {
 type LiteTeamPolicy struct{}
 
 var liteActions = []string{
-	"list", "get", "create", "claim", "complete", "cancel",
+	"list", "get", "create", "create_plan", "claim", "complete", "cancel",
 	"progress", "search", "update", "retry",
 }
 
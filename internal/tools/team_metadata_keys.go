@@ -42,4 +42,6 @@ const (
 	TaskMetaOriginTrace    = "origin_trace_id"
 	TaskMetaOriginRootSpan = "origin_root_span_id"
 	TaskMetaTeamWorkspace  = "team_workspace"
+	TaskMetaPlanID         = "plan_id"  // shared by all tasks created in one create_plan call
+	TaskMetaPlanKey        = "plan_key" // task's local key within its plan
 )
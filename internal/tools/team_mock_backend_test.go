@@ -64,6 +64,9 @@ func (b *baseNoopTeamStore) ListTaskScopes(_ context.Context, _ uuid.UUID) ([]st
 func (b *baseNoopTeamStore) CreateTask(_ context.Context, _ *store.TeamTaskData) error {
 	return fmt.Errorf("not implemented: CreateTask")
 }
+func (b *baseNoopTeamStore) CreateTasks(_ context.Context, _ []*store.TeamTaskData) error {
+	return fmt.Errorf("not implemented: CreateTasks")
+}
 func (b *baseNoopTeamStore) UpdateTask(_ context.Context, _ uuid.UUID, _ map[string]any) error {
 	return fmt.Errorf("not implemented: UpdateTask")
 }
@@ -291,8 +294,25 @@ func (s *mockTaskStore) GetTasksByIDs(_ context.Context, ids []uuid.UUID) ([]sto
 func (s *mockTaskStore) CreateTask(_ context.Context, task *store.TeamTaskData) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
+	task.ID = uuid.Nil
+	s.insertLocked(task)
+	return nil
+}
+
+func (s *mockTaskStore) CreateTasks(_ context.Context, tasks []*store.TeamTaskData) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, task := range tasks {
+		s.insertLocked(task)
+	}
+	return nil
+}
+
+func (s *mockTaskStore) insertLocked(task *store.TeamTaskData) {
 	now := time.Now()
-	task.ID = uuid.New()
+	if task.ID == uuid.Nil {
+		task.ID = uuid.New()
+	}
 	s.taskSeq++
 	task.TaskNumber = s.taskSeq
 	task.CreatedAt = now
@@ -302,7 +322,6 @@ func (s *mockTaskStore) CreateTask(_ context.Context, task *store.TeamTaskData)
 	}
 	cp := *task
 	s.tasks[task.ID] = &cp
-	return nil
 }
 
 func (s *mockTaskStore) UpdateTask(_ context.Context, taskID uuid.UUID, updates map[string]any) error {
@@ -337,6 +356,11 @@ func (s *mockTaskStore) UpdateTask(_ context.Context, taskID uuid.UUID, updates
 		case "blocked_by":
 			if ids, ok := v.([]uuid.UUID); ok {
 				t.BlockedBy = ids
+				if t.Status == store.TeamTaskStatusPending && len(ids) > 0 {
+					t.Status = store.TeamTaskStatusBlocked
+				} else if t.Status == store.TeamTaskStatusBlocked && len(ids) == 0 {
+					t.Status = store.TeamTaskStatusPending
+				}
 			}
 		case "owner_agent_id":
 			if id, ok := v.(uuid.UUID); ok {
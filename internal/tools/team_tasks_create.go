@@ -182,28 +182,9 @@ func (t *TeamTasksTool) executeCreate(ctx context.Context, args map[string]any)
 		for i, id := range blockedBy {
 			ids[i] = id.String()
 		}
-		taskMeta["original_blocked_by"] = ids
-	}
-	// Store peer kind so dispatches preserve the correct session scope (group vs direct).
-	if pk := ToolPeerKindFromCtx(ctx); pk != "" {
-		taskMeta[TaskMetaPeerKind] = pk
-	}
-	// Store local key so forum-topic routing works on deferred/unblocked dispatches.
-	if lk := ToolLocalKeyFromCtx(ctx); lk != "" {
-		taskMeta[TaskMetaLocalKey] = lk
-	}
-	// Store origin session key so deferred dispatches route announces correctly.
-	// WS sessions use non-standard key format that BuildScopedSessionKey() cannot reproduce.
-	if sk := ToolSessionKeyFromCtx(ctx); sk != "" {
-		taskMeta[TaskMetaOriginSession] = sk
-	}
-	// Store leader's trace context so unblocked dispatch links back to the leader's trace.
-	if traceID := tracing.TraceIDFromContext(ctx); traceID != uuid.Nil {
-		taskMeta[TaskMetaOriginTrace] = traceID.String()
-	}
-	if rootSpanID := tracing.ParentSpanIDFromContext(ctx); rootSpanID != uuid.Nil {
-		taskMeta[TaskMetaOriginRootSpan] = rootSpanID.String()
+		taskMeta[store.TaskMetaOriginalBlockedBy] = ids
 	}
+	setTaskOriginMeta(ctx, taskMeta)
 
 	task := &store.TeamTaskData{
 		TeamID:           team.ID,
@@ -285,26 +266,7 @@ func (t *TeamTasksTool) executeCreate(ctx context.Context, args map[string]any)
 	// Track for post-turn dispatch. If no post-turn hook (e.g. HTTP API), dispatch immediately.
 	// Member requests with auto_dispatch=false stay pending for leader review — skip dispatch.
 	if status == store.TeamTaskStatusPending && !skipAutoDispatch {
-		if ptd := PendingTeamDispatchFromCtx(ctx); ptd != nil {
-			ptd.Add(team.ID, task.ID)
-		} else {
-			// Fallback: assign (pending → in_progress + lock) then dispatch.
-			if err := t.manager.Store().AssignTask(ctx, task.ID, assigneeID, team.ID); err != nil {
-				slog.Warn("executeCreate: fallback assign failed", "task_id", task.ID, "error", err)
-			} else {
-				t.manager.BroadcastTeamEvent(ctx, protocol.EventTeamTaskDispatched, BuildTaskEventPayload(
-					team.ID.String(), task.ID.String(),
-					store.TeamTaskStatusInProgress,
-					"system", "fallback_dispatch",
-					WithTaskInfo(task.TaskNumber, task.Subject),
-					WithOwnerAgentKey(t.manager.AgentKeyFromID(ctx, assigneeID)),
-					WithChannel(task.Channel),
-					WithChatID(task.ChatID),
-					WithPeerKind(ToolPeerKindFromCtx(ctx)),
-				))
-				t.manager.DispatchTaskToAgent(ctx, task, team, assigneeID)
-			}
-		}
+		t.queueTaskDispatch(ctx, team, task, assigneeID)
 	}
 
 	assigneeName := t.manager.AgentDisplayName(ctx, t.manager.AgentKeyFromID(ctx, assigneeID))
@@ -327,3 +289,28 @@ func (t *TeamTasksTool) executeCreate(ctx context.Context, args map[string]any)
 	}
 	return NewResult(msg)
 }
+
+// setTaskOriginMeta records where a task was created so deferred dispatches
+// (post-turn, unblocked, recovered) route and trace like the creating turn.
+func setTaskOriginMeta(ctx context.Context, taskMeta map[string]any) {
+	// Store peer kind so dispatches preserve the correct session scope (group vs direct).
+	if pk := ToolPeerKindFromCtx(ctx); pk != "" {
+		taskMeta[TaskMetaPeerKind] = pk
+	}
+	// Store local key so forum-topic routing works on deferred/unblocked dispatches.
+	if lk := ToolLocalKeyFromCtx(ctx); lk != "" {
+		taskMeta[TaskMetaLocalKey] = lk
+	}
+	// Store origin session key so deferred dispatches route announces correctly.
+	// WS sessions use non-standard key format that BuildScopedSessionKey() cannot reproduce.
+	if sk := ToolSessionKeyFromCtx(ctx); sk != "" {
+		taskMeta[TaskMetaOriginSession] = sk
+	}
+	// Store leader's trace context so unblocked dispatch links back to the leader's trace.
+	if traceID := tracing.TraceIDFromContext(ctx); traceID != uuid.Nil {
+		taskMeta[TaskMetaOriginTrace] = traceID.String()
+	}
+	if rootSpanID := tracing.ParentSpanIDFromContext(ctx); rootSpanID != uuid.Nil {
+		taskMeta[TaskMetaOriginRootSpan] = rootSpanID.String()
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"log/slog"
 	"path/filepath"
+	"strings"
 
 	"github.com/google/uuid"
 
@@ -296,7 +297,22 @@ func (t *TeamTasksTool) executeUpdate(ctx context.Context, args map[string]any)
 				}
 			}
 		}
+		if cycle := t.dependencyCycle(ctx, task, blockedBy); cycle != "" {
+			return ErrorResult("blocked_by would create a circular dependency: " + cycle + ". Remove one of these links.")
+		}
 		updates["blocked_by"] = blockedBy
+
+		// Keep the full edge set for the task graph and blocker-result forwarding.
+		meta := make(map[string]any, len(task.Metadata)+1)
+		for k, v := range task.Metadata {
+			meta[k] = v
+		}
+		ids := make([]string, len(blockedBy))
+		for i, id := range blockedBy {
+			ids[i] = id.String()
+		}
+		meta[store.TaskMetaOriginalBlockedBy] = ids
+		updates["metadata"] = meta
 	}
 	if len(updates) == 0 {
 		return ErrorResult("no updates provided (set description, subject, or blocked_by)")
@@ -306,6 +322,14 @@ func (t *TeamTasksTool) executeUpdate(ctx context.Context, args map[string]any)
 		return ErrorResult("failed to update task: " + err.Error())
 	}
 
+	// Clearing the last blocker moves the task back to pending — dispatch it.
+	if _, ok := updates["blocked_by"]; ok && task.Status == store.TeamTaskStatusBlocked && task.OwnerAgentID != nil {
+		if fresh, err := t.manager.Store().GetTask(ctx, taskID); err == nil && fresh.Status == store.TeamTaskStatusPending {
+			task = fresh
+			t.queueTaskDispatch(ctx, team, task, *task.OwnerAgentID)
+		}
+	}
+
 	t.manager.BroadcastTeamEvent(ctx, protocol.EventTeamTaskUpdated, BuildTaskEventPayload(
 		team.ID.String(), taskID.String(),
 		task.Status,
@@ -316,3 +340,54 @@ func (t *TeamTasksTool) executeUpdate(ctx context.Context, args map[string]any)
 
 	return NewResult(fmt.Sprintf("Task #%d \"%s\" updated (id: %s).", task.TaskNumber, task.Subject, taskID))
 }
+
+// dependencyCycle reports whether making task depend on blockedBy closes a
+// cycle. It walks the unresolved blocked_by chains of the new prerequisites and
+// returns the cycle as "T-001 → T-002 → T-001", or "" when acyclic.
+func (t *TeamTasksTool) dependencyCycle(ctx context.Context, task *store.TeamTaskData, blockedBy []uuid.UUID) string {
+	deps := map[uuid.UUID][]uuid.UUID{task.ID: blockedBy}
+	names := map[uuid.UUID]string{task.ID: task.Identifier}
+	frontier := blockedBy
+	for len(frontier) > 0 {
+		var fetch []uuid.UUID
+		for _, id := range frontier {
+			if _, seen := deps[id]; !seen {
+				fetch = append(fetch, id)
+			}
+		}
+		if len(fetch) == 0 {
+			break
+		}
+		found, err := t.manager.Store().GetTasksByIDs(ctx, fetch)
+		if err != nil {
+			slog.Warn("team_tasks.update: dependency walk failed", "task_id", task.ID, "error", err)
+			break
+		}
+		frontier = nil
+		for i := range found {
+			deps[found[i].ID] = found[i].BlockedBy
+			names[found[i].ID] = found[i].Identifier
+			frontier = append(frontier, found[i].BlockedBy...)
+		}
+		// Unknown IDs are leaves; mark them so they are not fetched again.
+		for _, id := range fetch {
+			if _, ok := deps[id]; !ok {
+				deps[id] = nil
+			}
+		}
+	}
+
+	cycle := store.FindDependencyCycle(deps)
+	if cycle == nil {
+		return ""
+	}
+	parts := make([]string, 0, len(cycle)+1)
+	for _, id := range cycle {
+		name := names[id]
+		if name == "" {
+			name = id.String()
+		}
+		parts = append(parts, name)
+	}
+	return strings.Join(append(parts, parts[0]), " → ")
+}
@@ -0,0 +1,309 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/nextlevelbuilder/goclaw/internal/store"
+	"github.com/nextlevelbuilder/goclaw/pkg/protocol"
+)
+
+// maxPlanTasks caps a single create_plan call.
+const maxPlanTasks = 30
+
+// planItem is one parsed entry of create_plan's tasks array.
+type planItem struct {
+	key             string
+	subject         string
+	description     string
+	assigneeID      uuid.UUID
+	priority        int
+	deps            []string // plan keys or existing task UUIDs
+	requireApproval bool
+	estimate        int
+}
+
+// executeCreatePlan creates several tasks with dependencies in one atomic
+// write. Tasks reference each other by plan key in blocked_by; existing task
+// UUIDs are also accepted. Ready tasks are dispatched after the turn, the rest
+// start automatically as their prerequisites complete.
+func (t *TeamTasksTool) executeCreatePlan(ctx context.Context, args map[string]any) *Result {
+	team, agentID, err := t.manager.ResolveTeam(ctx)
+	if err != nil {
+		return ErrorResult(err.Error())
+	}
+	if err := t.manager.RequireLead(ctx, team, agentID); err != nil {
+		return ErrorResult(err.Error())
+	}
+
+	// Same duplicate-prevention gate as create.
+	if ptd := PendingTeamDispatchFromCtx(ctx); ptd != nil && !ptd.HasListed() {
+		return ErrorResult("You must check existing tasks first. Call team_tasks(action=\"search\", query=\"<keywords>\") or action=\"list\" before creating a plan.")
+	}
+
+	rawTasks, _ := args["tasks"].([]any)
+	if len(rawTasks) == 0 {
+		return ErrorResult("tasks is required for create_plan — an array of {key, subject, description, assignee, blocked_by?, priority?, require_approval?, estimate_minutes?}")
+	}
+	if len(rawTasks) > maxPlanTasks {
+		return ErrorResult(fmt.Sprintf("a plan may contain at most %d tasks (got %d) — split it into phases", maxPlanTasks, len(rawTasks)))
+	}
+
+	members, err := t.manager.CachedListMembers(ctx, team.ID, agentID)
+	if err != nil {
+		return ErrorResult("failed to verify team membership: " + err.Error())
+	}
+	memberSet := make(map[uuid.UUID]bool, len(members))
+	for _, m := range members {
+		memberSet[m.AgentID] = true
+	}
+
+	items := make([]planItem, 0, len(rawTasks))
+	keyIndex := make(map[string]int, len(rawTasks))
+	for i, raw := range rawTasks {
+		obj, ok := raw.(map[string]any)
+		if !ok {
+			return ErrorResult(fmt.Sprintf("tasks[%d] must be an object", i))
+		}
+		it := planItem{key: strconv.Itoa(i + 1)}
+		if k, ok := obj["key"].(string); ok && strings.TrimSpace(k) != "" {
+			it.key = strings.TrimSpace(k)
+		}
+		if _, dup := keyIndex[it.key]; dup {
+			return ErrorResult(fmt.Sprintf("duplicate plan key %q", it.key))
+		}
+		it.subject, _ = obj["subject"].(string)
+		if it.subject == "" {
+			return ErrorResult(fmt.Sprintf("tasks[%d] (%s): subject is required", i, it.key))
+		}
+		it.description, _ = obj["description"].(string)
+		if p, ok := obj["priority"].(float64); ok {
+			it.priority = int(p)
+		}
+		if e, ok := obj["estimate_minutes"].(float64); ok && e >= 1 {
+			it.estimate = int(e)
+		}
+		it.requireApproval, _ = obj["require_approval"].(bool)
+		if deps, ok := obj["blocked_by"].([]any); ok {
+			for _, d := range deps {
+				if s, ok := d.(string); ok && strings.TrimSpace(s) != "" {
+					it.deps = append(it.deps, strings.TrimSpace(s))
+				}
+			}
+		}
+
+		assigneeKey, _ := obj["assignee"].(string)
+		if assigneeKey == "" {
+			return ErrorResult(fmt.Sprintf("tasks[%d] (%s): assignee is required", i, it.key))
+		}
+		it.assigneeID, err = t.manager.ResolveAgentByKey(ctx, assigneeKey)
+		if err != nil {
+			return ErrorResult(fmt.Sprintf("tasks[%d] (%s): assignee %q not found: %v", i, it.key, assigneeKey, err))
+		}
+		if !memberSet[it.assigneeID] {
+			return ErrorResult(fmt.Sprintf("tasks[%d] (%s): agent %q is not a member of this team", i, it.key, assigneeKey))
+		}
+		if it.assigneeID == team.LeadAgentID {
+			return ErrorResult(fmt.Sprintf("tasks[%d] (%s): team lead cannot assign tasks to itself — delegate to a team member instead", i, it.key))
+		}
+
+		keyIndex[it.key] = i
+		items = append(items, it)
+	}
+
+	// Pre-generate IDs so in-plan dependencies can be wired before insert.
+	ids := make([]uuid.UUID, len(items))
+	for i := range items {
+		ids[i] = store.GenNewID()
+	}
+
+	// Resolve blocked_by: plan keys first, then existing task UUIDs.
+	deps := make([][]uuid.UUID, len(items))
+	var external []uuid.UUID
+	for i, it := range items {
+		seen := make(map[uuid.UUID]bool)
+		for _, ref := range it.deps {
+			var id uuid.UUID
+			if j, ok := keyIndex[ref]; ok {
+				id = ids[j]
+			} else if parsed, err := uuid.Parse(ref); err == nil {
+				id = parsed
+				external = append(external, id)
+			} else {
+				return ErrorResult(fmt.Sprintf("tasks[%d] (%s): blocked_by %q is neither a plan key nor a task UUID", i, it.key, ref))
+			}
+			if !seen[id] {
+				seen[id] = true
+				deps[i] = append(deps[i], id)
+			}
+		}
+	}
+	if len(external) > 0 {
+		existing, err := t.manager.Store().GetTasksByIDs(ctx, external)
+		if err != nil {
+			return ErrorResult("failed to validate blocked_by: " + err.Error())
+		}
+		found := make(map[uuid.UUID]*store.TeamTaskData, len(existing))
+		for i := range existing {
+			found[existing[i].ID] = &existing[i]
+		}
+		for _, id := range external {
+			dt, ok := found[id]
+			if !ok {
+				return ErrorResult(fmt.Sprintf("blocked_by task %s not found", id))
+			}
+			if dt.TeamID != team.ID {
+				return ErrorResult(fmt.Sprintf("blocked_by task %s belongs to a different team", id))
+			}
+			if store.IsTerminalTaskStatus(dt.Status) {
+				return ErrorResult(fmt.Sprintf(
+					"blocked_by task %s (%s) is already %s. Do not block on finished tasks — pass its result in the description instead.",
+					id, dt.Subject, dt.Status))
+			}
+		}
+	}
+
+	// Cycle detection within the plan (existing tasks cannot depend on new ones).
+	graph := make(map[uuid.UUID][]uuid.UUID, len(items))
+	keyByID := make(map[uuid.UUID]string, len(items))
+	for i := range items {
+		graph[ids[i]] = deps[i]
+		keyByID[ids[i]] = items[i].key
+	}
+	if cycle := store.FindDependencyCycle(graph); cycle != nil {
+		keys := make([]string, 0, len(cycle)+1)
+		for _, id := range cycle {
+			keys = append(keys, keyByID[id])
+		}
+		keys = append(keys, keys[0])
+		return ErrorResult("plan has a circular dependency: " + strings.Join(keys, " → ") + ". Remove one of these blocked_by links.")
+	}
+
+	chatID := ToolChatIDFromCtx(ctx)
+	teamWsDir := ResolveWorkspace(t.manager.DataDir(),
+		TenantLayer(store.TenantIDFromContext(ctx), store.TenantSlugFromContext(ctx)),
+		TeamLayer(team.ID),
+		UserChatLayer(chatID, IsSharedWorkspace(team.Settings)),
+	)
+	planID := store.GenNewID().String()
+
+	tasks := make([]*store.TeamTaskData, len(items))
+	for i, it := range items {
+		status := store.TeamTaskStatusPending
+		if it.requireApproval {
+			status = store.TeamTaskStatusInReview
+		} else if len(deps[i]) > 0 {
+			status = store.TeamTaskStatusBlocked
+		}
+		meta := map[string]any{
+			TaskMetaTeamWorkspace: teamWsDir,
+			TaskMetaPlanID:        planID,
+			TaskMetaPlanKey:       it.key,
+		}
+		if len(deps[i]) > 0 {
+			orig := make([]string, len(deps[i]))
+			for j, id := range deps[i] {
+				orig[j] = id.String()
+			}
+			meta[store.TaskMetaOriginalBlockedBy] = orig
+		}
+		if it.estimate > 0 {
+			meta[store.TaskMetaEstimateMinutes] = it.estimate
+		}
+		setTaskOriginMeta(ctx, meta)
+
+		assignee := it.assigneeID
+		tasks[i] = &store.TeamTaskData{
+			BaseModel:        store.BaseModel{ID: ids[i]},
+			TeamID:           team.ID,
+			Subject:          it.subject,
+			Description:      it.description,
+			Status:           status,
+			OwnerAgentID:     &assignee,
+			BlockedBy:        deps[i],
+			Priority:         it.priority,
+			UserID:           store.UserIDFromContext(ctx),
+			Channel:          ToolChannelFromCtx(ctx),
+			TaskType:         "general",
+			CreatedByAgentID: &agentID,
+			ChatID:           chatID,
+			Metadata:         meta,
+		}
+	}
+
+	if err := t.manager.Store().CreateTasks(ctx, tasks); err != nil {
+		return ErrorResult("failed to create plan: " + err.Error())
+	}
+
+	agentKey := t.manager.AgentKeyFromID(ctx, agentID)
+	for _, task := range tasks {
+		t.manager.BroadcastTeamEvent(ctx, protocol.EventTeamTaskCreated, BuildTaskEventPayload(
+			team.ID.String(), task.ID.String(),
+			task.Status,
+			"agent", agentKey,
+			WithSubject(task.Subject),
+			WithContextInfo(ctx),
+			WithTimestamp(task.CreatedAt.UTC().Format("2006-01-02T15:04:05Z")),
+		))
+	}
+	for _, task := range tasks {
+		if task.Status == store.TeamTaskStatusPending {
+			t.queueTaskDispatch(ctx, team, task, *task.OwnerAgentID)
+		}
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Plan created: %d tasks (plan_id=%s)\n", len(tasks), planID)
+	for i, task := range tasks {
+		fmt.Fprintf(&sb, "- [%s] %s %s (id=%s, status=%s, assignee=%s)",
+			items[i].key, task.Identifier, task.Subject, task.ID, task.Status,
+			t.manager.AgentKeyFromID(ctx, *task.OwnerAgentID))
+		if len(items[i].deps) > 0 {
+			fmt.Fprintf(&sb, " after %s", strings.Join(items[i].deps, ", "))
+		}
+		sb.WriteString("\n")
+	}
+	created := make([]store.TeamTaskData, len(tasks))
+	for i, task := range tasks {
+		created[i] = *task
+	}
+	if g := store.BuildTaskGraph(created); len(g.CriticalPath) > 1 {
+		keys := make([]string, len(g.CriticalPath))
+		for i, id := range g.CriticalPath {
+			keys[i] = keyByID[id]
+		}
+		fmt.Fprintf(&sb, "Critical path: %s\n", strings.Join(keys, " → "))
+	}
+	sb.WriteString("Tasks without prerequisites start after this turn; blocked tasks start automatically when their prerequisites complete.")
+	return NewResult(sb.String())
+}
+
+// queueTaskDispatch schedules a pending task for post-turn dispatch. Without a
+// post-turn hook (e.g. HTTP API) it assigns and dispatches immediately.
+func (t *TeamTasksTool) queueTaskDispatch(ctx context.Context, team *store.TeamData, task *store.TeamTaskData, assigneeID uuid.UUID) {
+	if ptd := PendingTeamDispatchFromCtx(ctx); ptd != nil {
+		ptd.Add(team.ID, task.ID)
+		return
+	}
+	// Fallback: assign (pending → in_progress + lock) then dispatch.
+	if err := t.manager.Store().AssignTask(ctx, task.ID, assigneeID, team.ID); err != nil {
+		slog.Warn("team_tasks: fallback assign failed", "task_id", task.ID, "error", err)
+		return
+	}
+	t.manager.BroadcastTeamEvent(ctx, protocol.EventTeamTaskDispatched, BuildTaskEventPayload(
+		team.ID.String(), task.ID.String(),
+		store.TeamTaskStatusInProgress,
+		"system", "fallback_dispatch",
+		WithTaskInfo(task.TaskNumber, task.Subject),
+		WithOwnerAgentKey(t.manager.AgentKeyFromID(ctx, assigneeID)),
+		WithChannel(task.Channel),
+		WithChatID(task.ChatID),
+		WithPeerKind(ToolPeerKindFromCtx(ctx)),
+	))
+	t.manager.DispatchTaskToAgent(ctx, task, team, assigneeID)
+}
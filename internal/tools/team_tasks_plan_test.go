@@ -0,0 +1,156 @@
+package tools
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/nextlevelbuilder/goclaw/internal/store"
+)
+
+func TestCreatePlan(t *testing.T) {
+	planCtx := func() (*mockBackend, *PendingTeamDispatch, func(map[string]any) *Result) {
+		mb, tool, _, _, ctx := newTestTeamSetup()
+		ptd := NewPendingTeamDispatch()
+		ptd.MarkListed()
+		ctx = WithPendingTeamDispatch(ctx, ptd)
+		return mb, ptd, func(args map[string]any) *Result { return tool.Execute(ctx, args) }
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		mb, ptd, exec := planCtx()
+		result := exec(map[string]any{
+			"action": "create_plan",
+			"tasks": []any{
+				map[string]any{"key": "design", "subject": "Design", "assignee": "member-agent"},
+				map[string]any{"key": "api", "subject": "API", "assignee": "member-agent", "blocked_by": []any{"design"}, "estimate_minutes": float64(30)},
+				map[string]any{"key": "ui", "subject": "UI", "assignee": "member2-agent", "blocked_by": []any{"design"}},
+				map[string]any{"key": "ship", "subject": "Ship", "assignee": "member2-agent", "blocked_by": []any{"api", "ui"}},
+			},
+		})
+		if result.IsError {
+			t.Fatalf("unexpected error: %s", result.ForLLM)
+		}
+		if !strings.Contains(result.ForLLM, "Critical path: design → api → ship") {
+			t.Errorf("expected critical path in result, got: %s", result.ForLLM)
+		}
+
+		mb.taskStore.mu.Lock()
+		byKey := make(map[string]*store.TeamTaskData)
+		for _, task := range mb.taskStore.tasks {
+			key, _ := task.Metadata[TaskMetaPlanKey].(string)
+			byKey[key] = task
+		}
+		mb.taskStore.mu.Unlock()
+		if len(byKey) != 4 {
+			t.Fatalf("expected 4 tasks, got %d", len(byKey))
+		}
+		if s := byKey["design"].Status; s != store.TeamTaskStatusPending {
+			t.Errorf("design status = %s, want pending", s)
+		}
+		for _, key := range []string{"api", "ui", "ship"} {
+			if s := byKey[key].Status; s != store.TeamTaskStatusBlocked {
+				t.Errorf("%s status = %s, want blocked", key, s)
+			}
+		}
+		ship := byKey["ship"]
+		if len(ship.BlockedBy) != 2 || ship.BlockedBy[0] != byKey["api"].ID || ship.BlockedBy[1] != byKey["ui"].ID {
+			t.Errorf("ship blocked_by = %v, want [api ui]", ship.BlockedBy)
+		}
+
+		queued := ptd.Drain()[testTeamID]
+		if len(queued) != 1 || queued[0] != byKey["design"].ID {
+			t.Errorf("expected only design queued for dispatch, got %v", queued)
+		}
+	})
+
+	t.Run("CycleRejected", func(t *testing.T) {
+		mb, _, exec := planCtx()
+		result := exec(map[string]any{
+			"action": "create_plan",
+			"tasks": []any{
+				map[string]any{"key": "a", "subject": "A", "assignee": "member-agent", "blocked_by": []any{"b"}},
+				map[string]any{"key": "b", "subject": "B", "assignee": "member-agent", "blocked_by": []any{"a"}},
+			},
+		})
+		if !result.IsError || !strings.Contains(result.ForLLM, "circular dependency") {
+			t.Fatalf("expected cycle error, got: %s", result.ForLLM)
+		}
+		mb.taskStore.mu.Lock()
+		defer mb.taskStore.mu.Unlock()
+		if len(mb.taskStore.tasks) != 0 {
+			t.Errorf("expected no tasks created, got %d", len(mb.taskStore.tasks))
+		}
+	})
+
+	t.Run("UnknownKey", func(t *testing.T) {
+		_, _, exec := planCtx()
+		result := exec(map[string]any{
+			"action": "create_plan",
+			"tasks": []any{
+				map[string]any{"key": "a", "subject": "A", "assignee": "member-agent", "blocked_by": []any{"missing"}},
+			},
+		})
+		if !result.IsError || !strings.Contains(result.ForLLM, "neither a plan key nor a task UUID") {
+			t.Errorf("expected unknown key error, got: %s", result.ForLLM)
+		}
+	})
+
+	t.Run("ExistingPrerequisite", func(t *testing.T) {
+		mb, _, exec := planCtx()
+		existing := makeTask(mb, testTeamID, testMemberID, store.TeamTaskStatusInProgress)
+		result := exec(map[string]any{
+			"action": "create_plan",
+			"tasks": []any{
+				map[string]any{"subject": "Follow-up", "assignee": "member2-agent", "blocked_by": []any{existing.String()}},
+			},
+		})
+		if result.IsError {
+			t.Fatalf("unexpected error: %s", result.ForLLM)
+		}
+		mb.taskStore.mu.Lock()
+		defer mb.taskStore.mu.Unlock()
+		for id, task := range mb.taskStore.tasks {
+			if id == existing {
+				continue
+			}
+			if task.Status != store.TeamTaskStatusBlocked || len(task.BlockedBy) != 1 || task.BlockedBy[0] != existing {
+				t.Errorf("follow-up status=%s blocked_by=%v, want blocked on %s", task.Status, task.BlockedBy, existing)
+			}
+		}
+	})
+
+	t.Run("MemberBlocked", func(t *testing.T) {
+		_, tool, _, memberID, ctx := newTestTeamSetup()
+		ptd := NewPendingTeamDispatch()
+		ptd.MarkListed()
+		memberCtx := WithPendingTeamDispatch(ctx, ptd)
+		memberCtx = WithTaskActionFlags(store.WithAgentID(memberCtx, memberID), &TaskActionFlags{})
+		result := tool.Execute(memberCtx, map[string]any{
+			"action": "create_plan",
+			"tasks":  []any{map[string]any{"subject": "X", "assignee": "member2-agent"}},
+		})
+		if !result.IsError {
+			t.Error("expected error for member creating a plan")
+		}
+	})
+}
+
+func TestUpdateDependencyCycle(t *testing.T) {
+	mb, tool, _, memberID, ctx := newTestTeamSetup()
+	a := makeTask(mb, testTeamID, memberID, store.TeamTaskStatusPending)
+	b := makeTask(mb, testTeamID, memberID, store.TeamTaskStatusBlocked)
+	mb.taskStore.mu.Lock()
+	mb.taskStore.tasks[b].BlockedBy = []uuid.UUID{a}
+	mb.taskStore.mu.Unlock()
+
+	result := tool.Execute(ctx, map[string]any{
+		"action":     "update",
+		"task_id":    a.String(),
+		"blocked_by": []any{b.String()},
+	})
+	if !result.IsError || !strings.Contains(result.ForLLM, "circular dependency") {
+		t.Fatalf("expected cycle error, got: %s", result.ForLLM)
+	}
+}
@@ -73,6 +73,24 @@ func (t *TeamTasksTool) Parameters() map[string]any {
 				"items":       map[string]any{"type": "string"},
 				"description": "Task IDs that must complete first (for create/update)",
 			},
+			"tasks": map[string]any{
+				"type":        "array",
+				"description": "Tasks for create_plan, created atomically. blocked_by may reference other plan keys or existing task IDs; cycles are rejected.",
+				"items": map[string]any{
+					"type": "object",
+					"properties": map[string]any{
+						"key":              map[string]any{"type": "string", "description": "Local key referenced by other plan tasks (default: 1-based position)"},
+						"subject":          map[string]any{"type": "string"},
+						"description":      map[string]any{"type": "string"},
+						"assignee":         map[string]any{"type": "string", "description": "Agent key of the team member"},
+						"blocked_by":       map[string]any{"type": "array", "items": map[string]any{"type": "string"}},
+						"priority":         map[string]any{"type": "number"},
+						"require_approval": map[string]any{"type": "boolean"},
+						"estimate_minutes": map[string]any{"type": "number", "description": "Effort estimate used for critical-path computation"},
+					},
+					"required": []string{"subject", "assignee"},
+				},
+			},
 			"require_approval": map[string]any{
 				"type":        "boolean",
 				"description": "Require user approval before claim (for create, default false)",
@@ -122,6 +140,7 @@ func (t *TeamTasksTool) buildActionDescription() string {
 		"list":           "- list: status?, page?\n",
 		"get":            "- get: task_id\n",
 		"create":         "- create: subject, description, assignee, priority?, blocked_by?, require_approval?, task_type?\n",
+		"create_plan":    "- create_plan: tasks=[{key, subject, description, assignee, blocked_by? (plan keys or task IDs), priority?, require_approval?, estimate_minutes?}]\n",
 		"claim":          "- claim: task_id\n",
 		"complete":       "- complete: task_id?, result\n",
 		"cancel":         "- cancel: task_id, text\n",
@@ -179,6 +198,8 @@ func (t *TeamTasksTool) Execute(ctx context.Context, args map[string]any) *Resul
 		return t.executeGet(ctx, args)
 	case "create":
 		return t.executeCreate(ctx, args)
+	case "create_plan":
+		return t.executeCreatePlan(ctx, args)
 	case "claim":
 		return t.executeClaim(ctx, args)
 	case "complete":
@@ -252,7 +252,7 @@ func (m *TeamToolManager) buildBlockerResultsSummary(ctx context.Context, task *
 	if task.Metadata == nil {
 		return ""
 	}
-	rawBlockers, ok := task.Metadata["original_blocked_by"]
+	rawBlockers, ok := task.Metadata[store.TaskMetaOriginalBlockedBy]
 	if !ok {
 		return ""
 	}
@@ -344,6 +344,8 @@ func (m *TeamToolManager) restoreTraceContext(ctx context.Context, task *store.T
 // and get dispatched after the current one completes. This ensures priority
 // ordering and prevents the cancellation bug where CancelSession kills innocent
 // queued tasks sharing the same session.
+// Owners that already have a task in progress are skipped — they pick up their
+// next task when the current run ends, which triggers another round.
 // Called after task completion/cancellation to start newly-unblocked work
 // instead of waiting for the ticker (up to 5 min delay).
 func (m *TeamToolManager) DispatchUnblockedTasks(ctx context.Context, teamID uuid.UUID) {
@@ -355,6 +357,14 @@ func (m *TeamToolManager) DispatchUnblockedTasks(ctx context.Context, teamID uui
 	if err != nil {
 		return
 	}
+	// nil = idle lookup failed; fall back to dispatching to every owner.
+	var idle map[uuid.UUID]bool
+	if members, err := m.teamStore.ListIdleMembers(ctx, teamID); err == nil {
+		idle = make(map[uuid.UUID]bool, len(members))
+		for _, mem := range members {
+			idle[mem.AgentID] = true
+		}
+	}
 	// Track which owners already have a task dispatched this round.
 	// ListRecoverableTasks orders by priority DESC, created_at — so the first
 	// pending task per owner is automatically the highest priority.
@@ -383,6 +393,9 @@ func (m *TeamToolManager) DispatchUnblockedTasks(ctx context.Context, teamID uui
 		if dispatched[ownerID] {
 			continue // skip — this owner already has a higher-priority task dispatched
 		}
+		if idle != nil && !idle[ownerID] {
+			continue // busy — dispatched when the owner's current task ends
+		}
 		// Assign (pending → in_progress + lock) so consumer can auto-complete.
 		if err := m.teamStore.AssignTask(ctx, task.ID, ownerID, teamID); err != nil {
 			slog.Warn("DispatchUnblockedTasks: assign failed", "task_id", task.ID, "error", err)
@@ -0,0 +1,451 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/nextlevelbuilder/goclaw/internal/bus"
+	"github.com/nextlevelbuilder/goclaw/internal/config"
+	"github.com/nextlevelbuilder/goclaw/internal/store"
+	"github.com/nextlevelbuilder/goclaw/pkg/protocol"
+)
+
+// ApprovalAllowSession approves the call and every later call of the same
+// tool in the same session.
+const ApprovalAllowSession ApprovalDecision = "allow-session"
+
+const defaultToolApprovalTimeout = 5 * time.Minute
+
+var (
+	ErrApprovalNotFound  = errors.New("approval not found or already resolved")
+	ErrApprovalForbidden = errors.New("not allowed to decide this approval")
+)
+
+// ToolApprovalRequest is a tool call waiting for a human decision.
+type ToolApprovalRequest struct {
+	ID         string                      `json:"id"`
+	Tool       string                      `json:"tool"`
+	Args       map[string]any              `json:"args,omitempty"`
+	Reason     string                      `json:"reason,omitempty"`
+	AgentKey   string                      `json:"agentKey,omitempty"`
+	SessionKey string                      `json:"sessionKey,omitempty"`
+	Channel    string                      `json:"channel,omitempty"`
+	ChatID     string                      `json:"chatId,omitempty"`
+	UserID     string                      `json:"userId,omitempty"` // user whose run made the call
+	SenderID   string                      `json:"senderId,omitempty"`
+	Targets    []config.ToolApprovalTarget `json:"targets,omitempty"` // chats the request is sent to
+	TenantID   uuid.UUID                   `json:"-"`
+	CreatedAt  time.Time                   `json:"createdAt"`
+	ExpiresAt  time.Time                   `json:"expiresAt"`
+
+	resultCh chan ApprovalDecision
+}
+
+// ApprovalActor is whoever answers an approval request.
+type ApprovalActor struct {
+	ID      string // channel sender ID or gateway user ID
+	Via     string // channel name, or "ws"
+	Trusted bool   // already authorized by the caller (e.g. a gateway admin)
+}
+
+// ToolApprovalNotifier delivers a new request to humans (channel buttons).
+type ToolApprovalNotifier func(ctx context.Context, req *ToolApprovalRequest)
+
+// ToolApprovalManager gates tool calls that match approval rules: the call
+// blocks until an approver decides or the request times out (denied).
+type ToolApprovalManager struct {
+	events bus.EventPublisher // WS events + audit log (nil = none)
+	owners []string           // gateway owner IDs, default approvers
+
+	mu        sync.Mutex
+	cfg       config.ToolApprovalCfg
+	pending   map[string]*ToolApprovalRequest
+	grants    map[string]map[string]bool // session key → tool → approved for the session
+	notifiers []ToolApprovalNotifier
+}
+
+// NewToolApprovalManager creates a manager for the given rules.
+func NewToolApprovalManager(cfg config.ToolApprovalCfg, owners []string, events bus.EventPublisher) *ToolApprovalManager {
+	return &ToolApprovalManager{
+		cfg:     cfg,
+		owners:  owners,
+		events:  events,
+		pending: make(map[string]*ToolApprovalRequest),
+		grants:  make(map[string]map[string]bool),
+	}
+}
+
+// SetConfig replaces the rules (config hot reload). Pending requests and
+// session grants are kept.
+func (m *ToolApprovalManager) SetConfig(cfg config.ToolApprovalCfg) {
+	m.mu.Lock()
+	m.cfg = cfg
+	m.mu.Unlock()
+}
+
+// AddNotifier registers a delivery function for new requests.
+func (m *ToolApprovalManager) AddNotifier(fn ToolApprovalNotifier) {
+	m.mu.Lock()
+	m.notifiers = append(m.notifiers, fn)
+	m.mu.Unlock()
+}
+
+// Match returns the first rule matching the call, or nil.
+func (m *ToolApprovalManager) Match(ctx context.Context, tool string, args map[string]any) *config.ToolApprovalRule {
+	m.mu.Lock()
+	rules := m.cfg.Rules
+	m.mu.Unlock()
+
+	agentKey := ToolAgentKeyFromCtx(ctx)
+	for i := range rules {
+		r := &rules[i]
+		if len(r.Agents) > 0 && !slices.Contains(r.Agents, agentKey) {
+			continue
+		}
+		if !approvalGlob(r.Tool, tool) {
+			continue
+		}
+		if approvalArgsMatch(ctx, r.Args, args) {
+			return r
+		}
+	}
+	return nil
+}
+
+// Gate asks for approval when the call matches a rule. It returns nil when
+// the call may run, or an error result for the model when it was denied.
+func (m *ToolApprovalManager) Gate(ctx context.Context, tool string, args map[string]any) *Result {
+	rule := m.Match(ctx, tool, args)
+	if rule == nil {
+		return nil
+	}
+	sessionKey := ToolSessionKeyFromCtx(ctx)
+
+	m.mu.Lock()
+	granted := sessionKey != "" && m.grants[sessionKey][tool]
+	timeout := time.Duration(m.cfg.TimeoutSec) * time.Second
+	targets := m.targetsLocked(ctx)
+	notifiers := slices.Clone(m.notifiers)
+	m.mu.Unlock()
+	if granted {
+		return nil
+	}
+	if timeout <= 0 {
+		timeout = defaultToolApprovalTimeout
+	}
+
+	now := time.Now()
+	req := &ToolApprovalRequest{
+		ID:         uuid.NewString(),
+		Tool:       tool,
+		Args:       args,
+		Reason:     rule.Reason,
+		AgentKey:   ToolAgentKeyFromCtx(ctx),
+		SessionKey: sessionKey,
+		Channel:    ToolChannelFromCtx(ctx),
+		ChatID:     ToolChatIDFromCtx(ctx),
+		UserID:     store.UserIDFromContext(ctx),
+		SenderID:   store.SenderIDFromContext(ctx),
+		Targets:    targets,
+		TenantID:   store.TenantIDFromContext(ctx),
+		CreatedAt:  now,
+		ExpiresAt:  now.Add(timeout),
+		resultCh:   make(chan ApprovalDecision, 1),
+	}
+	m.mu.Lock()
+	m.pending[req.ID] = req
+	m.mu.Unlock()
+
+	slog.Info("tool approval requested", "id", req.ID, "tool", tool, "agent", req.AgentKey, "session", sessionKey)
+	m.broadcast(protocol.EventToolApprovalReq, req, req.TenantID)
+	m.audit(req, "tool_approval.requested", ApprovalActor{ID: req.AgentKey}, "")
+	for _, notify := range notifiers {
+		notify(context.WithoutCancel(ctx), req)
+	}
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	var decision ApprovalDecision
+	var outcome string
+	select {
+	case decision = <-req.resultCh:
+	case <-timer.C:
+		outcome = "timed out after " + timeout.String()
+	case <-ctx.Done():
+		outcome = "run cancelled"
+	}
+	if outcome != "" {
+		if m.remove(req.ID) == nil {
+			// Resolved concurrently with the timeout; the decision wins.
+			decision = <-req.resultCh
+		} else {
+			decision = ApprovalDeny
+			m.broadcast(protocol.EventToolApprovalRes, map[string]any{"id": req.ID, "decision": ApprovalDeny, "userId": req.UserID, "expired": true}, req.TenantID)
+			m.audit(req, "tool_approval.expired", ApprovalActor{ID: "system"}, outcome)
+		}
+	}
+
+	if decision == ApprovalDeny {
+		if outcome == "" {
+			outcome = "denied by an approver"
+		}
+		return ErrorResult(fmt.Sprintf("The %s call was not approved (%s), so it did not run. Do not retry it; tell the user it needs approval.", tool, outcome))
+	}
+	return nil
+}
+
+// Resolve records a decision for a pending request.
+func (m *ToolApprovalManager) Resolve(id string, decision ApprovalDecision, actor ApprovalActor) (*ToolApprovalRequest, error) {
+	switch decision {
+	case ApprovalAllowOnce, ApprovalAllowSession, ApprovalDeny:
+	default:
+		return nil, fmt.Errorf("invalid decision %q", decision)
+	}
+
+	m.mu.Lock()
+	req, ok := m.pending[id]
+	if !ok {
+		m.mu.Unlock()
+		return nil, ErrApprovalNotFound
+	}
+	if !m.canDecideLocked(req, actor) {
+		m.mu.Unlock()
+		return nil, ErrApprovalForbidden
+	}
+	delete(m.pending, id)
+	if decision == ApprovalAllowSession && req.SessionKey != "" {
+		if m.grants[req.SessionKey] == nil {
+			m.grants[req.SessionKey] = make(map[string]bool)
+		}
+		m.grants[req.SessionKey][req.Tool] = true
+	}
+	m.mu.Unlock()
+
+	req.resultCh <- decision
+	slog.Info("tool approval resolved", "id", id, "tool", req.Tool, "decision", decision, "by", actor.ID, "via", actor.Via)
+	m.broadcast(protocol.EventToolApprovalRes, map[string]any{"id": id, "decision": decision, "userId": req.UserID, "by": actor.ID}, req.TenantID)
+	action := "tool_approval.approved"
+	if decision == ApprovalDeny {
+		action = "tool_approval.denied"
+	}
+	m.audit(req, action, actor, string(decision))
+	return req, nil
+}
+
+// Get returns a pending request.
+func (m *ToolApprovalManager) Get(id string) (*ToolApprovalRequest, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	req, ok := m.pending[id]
+	return req, ok
+}
+
+// ListPending returns pending requests, oldest first.
+func (m *ToolApprovalManager) ListPending() []*ToolApprovalRequest {
+	m.mu.Lock()
+	result := make([]*ToolApprovalRequest, 0, len(m.pending))
+	for _, req := range m.pending {
+		result = append(result, req)
+	}
+	m.mu.Unlock()
+	slices.SortFunc(result, func(a, b *ToolApprovalRequest) int { return a.CreatedAt.Compare(b.CreatedAt) })
+	return result
+}
+
+func (m *ToolApprovalManager) remove(id string) *ToolApprovalRequest {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	req, ok := m.pending[id]
+	if !ok {
+		return nil
+	}
+	delete(m.pending, id)
+	return req
+}
+
+// canDecideLocked: configured approvers, or by default the gateway owners.
+// The user whose run made the call may decide only with AllowRequester:
+// the request is usually posted in their own chat, so allowing it by
+// default would let an untrusted sender approve their own request.
+func (m *ToolApprovalManager) canDecideLocked(req *ToolApprovalRequest, actor ApprovalActor) bool {
+	if actor.Trusted {
+		return true
+	}
+	id := senderIDPart(actor.ID)
+	if id == "" {
+		return false
+	}
+	if m.cfg.AllowRequester && (id == senderIDPart(req.SenderID) || id == req.UserID) {
+		return true
+	}
+	approvers := m.cfg.Approvers
+	if len(approvers) == 0 {
+		approvers = m.owners
+	}
+	return slices.ContainsFunc(approvers, func(s string) bool { return senderIDPart(s) == id })
+}
+
+// targetsLocked lists the chats a request from ctx goes to.
+func (m *ToolApprovalManager) targetsLocked(ctx context.Context) []config.ToolApprovalTarget {
+	var targets []config.ToolApprovalTarget
+	channel, chatID := ToolChannelFromCtx(ctx), ToolChatIDFromCtx(ctx)
+	if (m.cfg.NotifyOrigin == nil || *m.cfg.NotifyOrigin) && channel != "" && chatID != "" {
+		targets = append(targets, config.ToolApprovalTarget{Channel: channel, ChatID: chatID})
+	}
+	for _, t := range m.cfg.Notify {
+		if !slices.Contains(targets, t) {
+			targets = append(targets, t)
+		}
+	}
+	return targets
+}
+
+func (m *ToolApprovalManager) broadcast(name string, payload any, tenantID uuid.UUID) {
+	if m.events == nil {
+		return
+	}
+	m.events.Broadcast(bus.Event{Name: name, Payload: payload, TenantID: tenantID})
+}
+
+// audit records the request lifecycle in the activity log.
+func (m *ToolApprovalManager) audit(req *ToolApprovalRequest, action string, actor ApprovalActor, outcome string) {
+	if m.events == nil {
+		return
+	}
+	actorType := "user"
+	if action == "tool_approval.requested" {
+		actorType = "agent"
+	} else if action == "tool_approval.expired" {
+		actorType = "system"
+	}
+	details, _ := json.Marshal(map[string]any{
+		"tool":        req.Tool,
+		"args":        req.Args,
+		"reason":      req.Reason,
+		"agent_key":   req.AgentKey,
+		"session_key": req.SessionKey,
+		"channel":     req.Channel,
+		"chat_id":     req.ChatID,
+		"user_id":     req.UserID,
+		"via":         actor.Via,
+		"outcome":     outcome,
+	})
+	m.events.Broadcast(bus.Event{
+		Name: protocol.EventAuditLog,
+		Payload: bus.AuditEventPayload{
+			ActorType:  actorType,
+			ActorID:    actor.ID,
+			Action:     action,
+			EntityType: "tool_call",
+			EntityID:   req.ID,
+			Details:    details,
+			TenantID:   req.TenantID,
+		},
+		TenantID: req.TenantID,
+	})
+}
+
+// senderIDPart strips the "|username" suffix of compound sender IDs.
+func senderIDPart(id string) string {
+	if i := strings.IndexByte(id, '|'); i > 0 {
+		return id[:i]
+	}
+	return id
+}
+
+// approvalArgsMatch reports whether every predicate matches the call. A
+// predicate on an argument the call does not set never matches. Path
+// arguments are cleaned before matching so "memory/../SOUL.md" is seen as
+// "SOUL.md"; one that escapes upward matches any predicate, so the call
+// is always gated.
+func approvalArgsMatch(ctx context.Context, preds map[string]string, args map[string]any) bool {
+	for name, pattern := range preds {
+		v, ok := args[name]
+		if !ok || v == nil {
+			return false
+		}
+		s := approvalArgString(v)
+		if approvalPathArg(name) {
+			var escapes bool
+			if s, escapes = cleanApprovalPath(s); escapes {
+				continue
+			}
+		}
+		negate := strings.HasPrefix(pattern, "!")
+		pattern = strings.TrimPrefix(pattern, "!")
+		pattern = strings.NewReplacer("{chat_id}", ToolChatIDFromCtx(ctx), "{channel}", ToolChannelFromCtx(ctx)).Replace(pattern)
+		if approvalGlob(pattern, s) == negate {
+			return false
+		}
+	}
+	return true
+}
+
+// approvalPathArg reports whether an argument name holds a file path.
+func approvalPathArg(name string) bool {
+	switch name {
+	case "path", "file", "dir", "directory", "cwd", "working_dir":
+		return true
+	}
+	return strings.HasSuffix(name, "_path") || strings.HasSuffix(name, "_file") || strings.HasSuffix(name, "_dir")
+}
+
+// cleanApprovalPath resolves "." and ".." segments (and backslashes) and
+// reports whether the path climbs above its starting directory.
+func cleanApprovalPath(p string) (string, bool) {
+	p = path.Clean(strings.ReplaceAll(p, `\`, "/"))
+	return p, p == ".." || strings.HasPrefix(p, "../")
+}
+
+func approvalArgString(v any) string {
+	if s, ok := v.(string); ok {
+		return s
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(b)
+}
+
+var approvalGlobCache sync.Map // pattern → *regexp.Regexp
+
+// approvalGlob matches s against a glob where "*" matches any run of
+// characters (including "/") and "?" matches exactly one.
+func approvalGlob(pattern, s string) bool {
+	if pattern == "*" {
+		return true
+	}
+	if !strings.ContainsAny(pattern, "*?") {
+		return pattern == s
+	}
+	re, ok := approvalGlobCache.Load(pattern)
+	if !ok {
+		var sb strings.Builder
+		sb.WriteString("^")
+		for _, r := range pattern {
+			switch r {
+			case '*':
+				sb.WriteString(".*")
+			case '?':
+				sb.WriteString(".")
+			default:
+				sb.WriteString(regexp.QuoteMeta(string(r)))
+			}
+		}
+		sb.WriteString("$")
+		re, _ = approvalGlobCache.LoadOrStore(pattern, regexp.MustCompile("(?s)"+sb.String()))
+	}
+	return re.(*regexp.Regexp).MatchString(s)
+}
@@ -0,0 +1,206 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/nextlevelbuilder/goclaw/internal/bus"
+	"github.com/nextlevelbuilder/goclaw/internal/config"
+	"github.com/nextlevelbuilder/goclaw/internal/store"
+	"github.com/nextlevelbuilder/goclaw/pkg/protocol"
+)
+
+type recordingPublisher struct {
+	mu     sync.Mutex
+	events []bus.Event
+}
+
+func (p *recordingPublisher) Subscribe(string, bus.EventHandler) {}
+func (p *recordingPublisher) Unsubscribe(string)                 {}
+func (p *recordingPublisher) Broadcast(e bus.Event) {
+	p.mu.Lock()
+	p.events = append(p.events, e)
+	p.mu.Unlock()
+}
+
+func (p *recordingPublisher) auditActions() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	var actions []string
+	for _, e := range p.events {
+		if e.Name == protocol.EventAuditLog {
+			actions = append(actions, e.Payload.(bus.AuditEventPayload).Action)
+		}
+	}
+	return actions
+}
+
+func approvalCtx() context.Context {
+	ctx := WithToolAgentKey(context.Background(), "assistant")
+	ctx = WithToolChannel(ctx, "telegram")
+	ctx = WithToolChatID(ctx, "100")
+	ctx = WithToolSessionKey(ctx, "agent:assistant:telegram:direct:100")
+	ctx = store.WithUserID(ctx, "100")
+	return store.WithSenderID(ctx, "100|alice")
+}
+
+func TestToolApprovalManager_Match(t *testing.T) {
+	mgr := NewToolApprovalManager(config.ToolApprovalCfg{Rules: []config.ToolApprovalRule{
+		{Tool: "message", Args: map[string]string{"target": "!{chat_id}"}},
+		{Tool: "write_file", Args: map[string]string{"path": "!memory/*"}},
+		{Tool: "mcp_github__*", Agents: []string{"coder"}},
+	}}, nil, nil)
+	ctx := approvalCtx()
+
+	cases := []struct {
+		tool string
+		args map[string]any
+		want bool
+	}{
+		{"message", map[string]any{"target": "100"}, false},
+		{"message", map[string]any{"target": "200"}, true},
+		{"message", map[string]any{"content": "hi"}, false}, // absent arg never matches
+		{"write_file", map[string]any{"path": "memory/notes/today.md"}, false},
+		{"write_file", map[string]any{"path": "src/main.go"}, true},
+		{"write_file", map[string]any{"path": "memory/../SOUL.md"}, true}, // traversal out of memory/
+		{"write_file", map[string]any{"path": "./memory/a/../b.md"}, false},
+		{"write_file", map[string]any{"path": "memory/../../etc/passwd"}, true}, // escapes upward
+		{"mcp_github__create_issue", map[string]any{}, false},                   // other agent
+		{"read_file", map[string]any{"path": "x"}, false},
+	}
+	for _, tc := range cases {
+		if got := mgr.Match(ctx, tc.tool, tc.args) != nil; got != tc.want {
+			t.Errorf("Match(%s, %v) = %v, want %v", tc.tool, tc.args, got, tc.want)
+		}
+	}
+	if mgr.Match(WithToolAgentKey(ctx, "coder"), "mcp_github__create_issue", nil) == nil {
+		t.Error("agent-scoped rule did not match its agent")
+	}
+}
+
+// waitPending returns the first pending request once the gate has created it.
+func waitPending(t *testing.T, mgr *ToolApprovalManager) *ToolApprovalRequest {
+	t.Helper()
+	for range 200 {
+		if p := mgr.ListPending(); len(p) > 0 {
+			return p[0]
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatal("no pending approval")
+	return nil
+}
+
+func TestToolApprovalManager_GateDecisions(t *testing.T) {
+	events := &recordingPublisher{}
+	mgr := NewToolApprovalManager(config.ToolApprovalCfg{
+		Rules: []config.ToolApprovalRule{{Tool: "team_tasks", Args: map[string]string{"action": "delete"}}},
+	}, []string{"999"}, events)
+	ctx := approvalCtx()
+	args := map[string]any{"action": "delete", "task_id": "t1"}
+
+	var notified []string
+	mgr.AddNotifier(func(_ context.Context, req *ToolApprovalRequest) { notified = append(notified, req.ID) })
+
+	// Deny.
+	done := make(chan *Result, 1)
+	go func() { done <- mgr.Gate(ctx, "team_tasks", args) }()
+	req := waitPending(t, mgr)
+	if len(req.Targets) != 1 || req.Targets[0].ChatID != "100" {
+		t.Fatalf("targets = %+v, want the originating chat", req.Targets)
+	}
+	if _, err := mgr.Resolve(req.ID, ApprovalDeny, ApprovalActor{ID: "555", Via: "telegram"}); !errors.Is(err, ErrApprovalForbidden) {
+		t.Fatalf("stranger resolve err = %v, want forbidden", err)
+	}
+	if _, err := mgr.Resolve(req.ID, ApprovalDeny, ApprovalActor{ID: "999|owner", Via: "telegram"}); err != nil {
+		t.Fatalf("owner resolve: %v", err)
+	}
+	if res := <-done; res == nil || !res.IsError || !strings.Contains(res.ForLLM, "denied") {
+		t.Fatalf("denied gate result = %+v", res)
+	}
+
+	// Approve for the session: later calls skip the prompt. The requester
+	// cannot approve their own call by default.
+	go func() { done <- mgr.Gate(ctx, "team_tasks", args) }()
+	req = waitPending(t, mgr)
+	if _, err := mgr.Resolve(req.ID, ApprovalAllowSession, ApprovalActor{ID: "100", Via: "telegram"}); !errors.Is(err, ErrApprovalForbidden) {
+		t.Fatalf("requester resolve err = %v, want forbidden", err)
+	}
+	if _, err := mgr.Resolve(req.ID, ApprovalAllowSession, ApprovalActor{ID: "999", Via: "telegram"}); err != nil {
+		t.Fatalf("owner resolve: %v", err)
+	}
+	if res := <-done; res != nil {
+		t.Fatalf("approved gate result = %+v, want nil", res)
+	}
+	if res := mgr.Gate(ctx, "team_tasks", args); res != nil {
+		t.Fatalf("granted gate result = %+v, want nil", res)
+	}
+	if len(notified) != 2 {
+		t.Errorf("notified %d times, want 2", len(notified))
+	}
+	if _, err := mgr.Resolve(req.ID, ApprovalAllowOnce, ApprovalActor{Trusted: true}); !errors.Is(err, ErrApprovalNotFound) {
+		t.Errorf("second resolve err = %v, want not found", err)
+	}
+
+	want := []string{"tool_approval.requested", "tool_approval.denied", "tool_approval.requested", "tool_approval.approved"}
+	if got := events.auditActions(); strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("audit actions = %v, want %v", got, want)
+	}
+}
+
+func TestToolApprovalManager_AllowRequester(t *testing.T) {
+	mgr := NewToolApprovalManager(config.ToolApprovalCfg{
+		Rules:          []config.ToolApprovalRule{{Tool: "exec"}},
+		AllowRequester: true,
+	}, []string{"999"}, nil)
+	done := make(chan *Result, 1)
+	go func() { done <- mgr.Gate(approvalCtx(), "exec", map[string]any{"command": "ls"}) }()
+	req := waitPending(t, mgr)
+	if _, err := mgr.Resolve(req.ID, ApprovalAllowOnce, ApprovalActor{ID: "100|alice", Via: "telegram"}); err != nil {
+		t.Fatalf("requester resolve with allow_requester: %v", err)
+	}
+	if res := <-done; res != nil {
+		t.Fatalf("approved gate result = %+v, want nil", res)
+	}
+}
+
+func TestToolApprovalManager_Timeout(t *testing.T) {
+	events := &recordingPublisher{}
+	mgr := NewToolApprovalManager(config.ToolApprovalCfg{Rules: []config.ToolApprovalRule{{Tool: "*"}}}, nil, events)
+	ctx, cancel := context.WithTimeout(approvalCtx(), 50*time.Millisecond)
+	defer cancel()
+
+	res := mgr.Gate(ctx, "exec", map[string]any{"command": "ls"})
+	if res == nil || !res.IsError {
+		t.Fatalf("gate result = %+v, want denial", res)
+	}
+	if len(mgr.ListPending()) != 0 {
+		t.Error("expired request still pending")
+	}
+	if got := events.auditActions(); len(got) != 2 || got[1] != "tool_approval.expired" {
+		t.Errorf("audit actions = %v", got)
+	}
+}
+
+func TestRegistry_ApprovalGate(t *testing.T) {
+	reg := NewRegistry()
+	reg.Register(&mockTool{name: "write_file"})
+	mgr := NewToolApprovalManager(config.ToolApprovalCfg{
+		Rules:      []config.ToolApprovalRule{{Tool: "write_file", Args: map[string]string{"path": "!memory/*"}}},
+		TimeoutSec: 1,
+	}, nil, nil)
+	reg.SetApprovals(mgr)
+
+	ctx := approvalCtx()
+	if res := reg.Execute(ctx, "write_file", map[string]any{"path": "memory/a.md"}); res.IsError {
+		t.Fatalf("unmatched call blocked: %s", res.ForLLM)
+	}
+	// Unanswered: denied when the 1s timeout elapses.
+	if res := reg.Clone().Execute(ctx, "write_file", map[string]any{"path": "etc/passwd"}); !res.IsError {
+		t.Fatal("denied call ran")
+	}
+}
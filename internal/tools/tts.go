@@ -8,6 +8,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/nextlevelbuilder/goclaw/internal/store"
 	"github.com/nextlevelbuilder/goclaw/internal/tts"
 )
 
@@ -32,6 +33,13 @@ func (t *TtsTool) UpdateManager(mgr *tts.Manager) {
 	t.manager = mgr
 }
 
+// Manager returns the current TTS manager (used by tts.* RPC methods).
+func (t *TtsTool) Manager() *tts.Manager {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.manager
+}
+
 func (t *TtsTool) Name() string { return "tts" }
 
 func (t *TtsTool) Description() string {
@@ -94,7 +102,9 @@ func (t *TtsTool) Execute(ctx context.Context, args map[string]any) *Result {
 		}
 		result, err = p.Synthesize(ctx, text, opts)
 	} else {
-		result, err = mgr.SynthesizeWithFallback(ctx, text, opts)
+		// Honor tenant/agent provider overrides set via tts.setProvider.
+		scope := mgr.Resolve(store.TenantIDFromContext(ctx).String(), store.AgentIDFromContext(ctx).String())
+		result, err = mgr.SynthesizePreferring(ctx, scope.Provider, text, opts)
 	}
 
 	if err != nil {
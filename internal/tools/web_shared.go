@@ -1,11 +1,13 @@
 package tools
 
 import (
+	"errors"
 	"fmt"
 	"net"
 	"net/url"
 	"strings"
 	"sync"
+	"syscall"
 	"time"
 	"unicode/utf8"
 )
@@ -199,6 +201,24 @@ func CheckSSRF(rawURL string) error {
 	return nil
 }
 
+// ErrSSRFBlocked is returned (wrapped) by SSRFDialControl when a connection targets
+// a private/reserved address.
+var ErrSSRFBlocked = errors.New("ssrf: private address not allowed")
+
+// SSRFDialControl is a net.Dialer Control hook that refuses connections to
+// private/reserved IPs. Unlike CheckSSRF it sees the address actually dialed,
+// so a hostname re-resolving to an internal IP after validation is still blocked.
+func SSRFDialControl(_, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	if isPrivateIP(host) {
+		return fmt.Errorf("%w: %s", ErrSSRFBlocked, host)
+	}
+	return nil
+}
+
 // --- External Content Wrapping (matching TS src/security/external-content.ts) ---
 
 const (
@@ -47,14 +47,19 @@ func (p *EdgeProvider) Name() string { return "edge" }
 
 // Synthesize runs the edge-tts CLI to generate audio.
 // Output is always MP3 (edge-tts default format: audio-24khz-48kbitrate-mono-mp3).
-func (p *EdgeProvider) Synthesize(ctx context.Context, text string, _ Options) (*SynthResult, error) {
+func (p *EdgeProvider) Synthesize(ctx context.Context, text string, opts Options) (*SynthResult, error) {
+	voice := opts.Voice
+	if voice == "" {
+		voice = p.voice
+	}
+
 	// Create temp file for output
 	tmpDir := os.TempDir()
 	outPath := filepath.Join(tmpDir, fmt.Sprintf("tts-%d.mp3", time.Now().UnixNano()))
 	defer os.Remove(outPath)
 
 	args := []string{
-		"--voice", p.voice,
+		"--voice", voice,
 		"--text", text,
 		"--write-media", outPath,
 	}
@@ -5,7 +5,9 @@ import (
 	"fmt"
 	"log/slog"
 	"regexp"
+	"sort"
 	"strings"
+	"sync"
 )
 
 // Manager orchestrates TTS providers and auto-apply logic.
@@ -17,6 +19,12 @@ type Manager struct {
 	mode      Mode     // "final" or "all"
 	maxLength int      // max text length before truncation (default 1500)
 	timeoutMs int      // provider timeout (default 30000)
+
+	// Runtime overrides set via tts.* RPC (persisted in system_configs).
+	mu          sync.RWMutex
+	tenants     map[string]ScopeSettings // tenant ID → overrides
+	agents      map[string]ScopeSettings // agent ID → overrides
+	agentTenant map[string]string        // agent ID → owning tenant ID
 }
 
 // ManagerConfig configures the TTS manager.
@@ -31,12 +39,15 @@ type ManagerConfig struct {
 // NewManager creates a TTS manager.
 func NewManager(cfg ManagerConfig) *Manager {
 	m := &Manager{
-		providers: make(map[string]Provider),
-		primary:   cfg.Primary,
-		auto:      cfg.Auto,
-		mode:      cfg.Mode,
-		maxLength: cfg.MaxLength,
-		timeoutMs: cfg.TimeoutMs,
+		providers:   make(map[string]Provider),
+		tenants:     make(map[string]ScopeSettings),
+		agents:      make(map[string]ScopeSettings),
+		agentTenant: make(map[string]string),
+		primary:     cfg.Primary,
+		auto:        cfg.Auto,
+		mode:        cfg.Mode,
+		maxLength:   cfg.MaxLength,
+		timeoutMs:   cfg.TimeoutMs,
 	}
 	if m.auto == "" {
 		m.auto = AutoOff
@@ -68,6 +79,19 @@ func (m *Manager) GetProvider(name string) (Provider, bool) {
 	return p, ok
 }
 
+// ProviderNames returns the registered provider names, sorted.
+func (m *Manager) ProviderNames() []string {
+	names := make([]string, 0, len(m.providers))
+	for name := range m.providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Mode returns the reply-kind filter mode.
+func (m *Manager) Mode() Mode { return m.mode }
+
 // PrimaryProvider returns the primary provider name.
 func (m *Manager) PrimaryProvider() string { return m.primary }
 
@@ -92,18 +116,28 @@ func (m *Manager) Synthesize(ctx context.Context, text string, opts Options) (*S
 // SynthesizeWithFallback tries the primary provider, then falls back to others.
 // Matching TS resolveTtsProviderOrder().
 func (m *Manager) SynthesizeWithFallback(ctx context.Context, text string, opts Options) (*SynthResult, error) {
-	// Try primary first
-	if p, ok := m.providers[m.primary]; ok {
+	return m.SynthesizePreferring(ctx, m.primary, text, opts)
+}
+
+// SynthesizePreferring tries the named provider first, then falls back to the others.
+// An empty or unknown name behaves like SynthesizeWithFallback.
+func (m *Manager) SynthesizePreferring(ctx context.Context, preferred, text string, opts Options) (*SynthResult, error) {
+	if _, ok := m.providers[preferred]; !ok {
+		preferred = m.primary
+	}
+
+	// Try preferred first
+	if p, ok := m.providers[preferred]; ok {
 		result, err := p.Synthesize(ctx, text, opts)
 		if err == nil {
 			return result, nil
 		}
-		slog.Warn("tts primary provider failed, trying fallback", "provider", m.primary, "error", err)
+		slog.Warn("tts primary provider failed, trying fallback", "provider", preferred, "error", err)
 	}
 
 	// Try other providers
 	for name, p := range m.providers {
-		if name == m.primary {
+		if name == preferred {
 			continue
 		}
 		result, err := p.Synthesize(ctx, text, opts)
@@ -122,12 +156,14 @@ func (m *Manager) SynthesizeWithFallback(ctx context.Context, text string, opts
 // Matching TS maybeApplyTtsToPayload().
 //
 // Parameters:
+//   - tenantID, agentID: scope whose overrides (Resolve) pick the auto mode and provider
 //   - text: the reply text to potentially convert
 //   - channel: origin channel (affects output format, e.g. "telegram" → opus)
 //   - isVoiceInbound: whether the user's message was audio/voice
 //   - kind: "tool", "block", or "final"
-func (m *Manager) MaybeApply(ctx context.Context, text, channel string, isVoiceInbound bool, kind string) (*SynthResult, bool) {
-	if m.auto == AutoOff {
+func (m *Manager) MaybeApply(ctx context.Context, tenantID, agentID, text, channel string, isVoiceInbound bool, kind string) (*SynthResult, bool) {
+	scope := m.Resolve(tenantID, agentID)
+	if scope.Auto == AutoOff {
 		return nil, false
 	}
 
@@ -137,7 +173,7 @@ func (m *Manager) MaybeApply(ctx context.Context, text, channel string, isVoiceI
 	}
 
 	// Auto-mode check
-	switch m.auto {
+	switch scope.Auto {
 	case AutoInbound:
 		if !isVoiceInbound {
 			return nil, false
@@ -154,7 +190,7 @@ func (m *Manager) MaybeApply(ctx context.Context, text, channel string, isVoiceI
 
 	// Content validation (matching TS checks)
 	cleanText := stripMarkdown(text)
-	cleanText = stripTtsDirectives(cleanText)
+	cleanText = StripDirectives(cleanText)
 	cleanText = strings.TrimSpace(cleanText)
 
 	if len(cleanText) < 10 {
@@ -175,9 +211,9 @@ func (m *Manager) MaybeApply(ctx context.Context, text, channel string, isVoiceI
 		opts.Format = "opus" // Telegram voice bubbles need opus
 	}
 
-	result, err := m.SynthesizeWithFallback(ctx, cleanText, opts)
+	result, err := m.SynthesizePreferring(ctx, scope.Provider, cleanText, opts)
 	if err != nil {
-		slog.Warn("tts auto-apply failed", "error", err)
+		slog.Warn("tts auto-apply failed", "provider", scope.Provider, "error", err)
 		return nil, false
 	}
 
@@ -209,9 +245,9 @@ func stripMarkdown(text string) string {
 	return text
 }
 
-// stripTtsDirectives removes [[tts...]] directives from text.
+// StripDirectives removes [[tts...]] directives from text.
 // Matching TS parseTtsDirectives().
-func stripTtsDirectives(text string) string {
+func StripDirectives(text string) string {
 	// Remove [[tts:text]]...[[/tts:text]] blocks (keep inner text)
 	text = regexp.MustCompile(`(?s)\[\[tts:text\]\](.*?)\[\[/tts:text\]\]`).ReplaceAllString(text, "$1")
 	// Remove [[tts]] and [[tts:...]] tags
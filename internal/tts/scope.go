@@ -0,0 +1,152 @@
+package tts
+
+import (
+	"strings"
+)
+
+// ScopeSettings overrides the manager-wide provider and auto mode for a
+// tenant or a single agent. Empty fields inherit from the enclosing scope
+// (agent → tenant → manager defaults).
+type ScopeSettings struct {
+	Provider string   `json:"provider,omitempty"`
+	Auto     AutoMode `json:"auto,omitempty"`
+}
+
+// IsZero reports whether the settings carry no overrides.
+func (s ScopeSettings) IsZero() bool {
+	return s.Provider == "" && s.Auto == ""
+}
+
+// System config keys used to persist scope overrides.
+// Tenant-level overrides reuse the global keys (system_configs rows are tenant-scoped);
+// agent overrides are namespaced by agent UUID.
+const (
+	ConfigKeyProvider    = "tts.provider"
+	ConfigKeyAuto        = "tts.auto"
+	configKeyAgentPrefix = "tts.agent."
+)
+
+// AgentConfigKey returns the system config key for an agent-level override field
+// ("provider" or "auto").
+func AgentConfigKey(agentID, field string) string {
+	return configKeyAgentPrefix + agentID + "." + field
+}
+
+// ValidAutoMode reports whether s is a recognized auto mode.
+func ValidAutoMode(s string) bool {
+	switch AutoMode(s) {
+	case AutoOff, AutoAlways, AutoInbound, AutoTagged:
+		return true
+	}
+	return false
+}
+
+// ParseScopeConfigs extracts tenant- and agent-level TTS overrides from a
+// tenant's system_configs map. Unknown auto modes are ignored.
+func ParseScopeConfigs(configs map[string]string) (tenant ScopeSettings, agents map[string]ScopeSettings) {
+	agents = make(map[string]ScopeSettings)
+	tenant.Provider = configs[ConfigKeyProvider]
+	if v := configs[ConfigKeyAuto]; ValidAutoMode(v) {
+		tenant.Auto = AutoMode(v)
+	}
+	for key, val := range configs {
+		rest, ok := strings.CutPrefix(key, configKeyAgentPrefix)
+		if !ok || val == "" {
+			continue
+		}
+		dot := strings.LastIndexByte(rest, '.')
+		if dot <= 0 {
+			continue
+		}
+		agentID, field := rest[:dot], rest[dot+1:]
+		s := agents[agentID]
+		switch field {
+		case "provider":
+			s.Provider = val
+		case "auto":
+			if !ValidAutoMode(val) {
+				continue
+			}
+			s.Auto = AutoMode(val)
+		default:
+			continue
+		}
+		agents[agentID] = s
+	}
+	return tenant, agents
+}
+
+// SetTenantScope replaces the overrides for a tenant and all of its agents.
+// Called after the tenant's system_configs are (re)loaded.
+func (m *Manager) SetTenantScope(tenantID string, tenant ScopeSettings, agents map[string]ScopeSettings) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if tenant.IsZero() {
+		delete(m.tenants, tenantID)
+	} else {
+		m.tenants[tenantID] = tenant
+	}
+	for id := range m.agents {
+		if m.agentTenant[id] == tenantID {
+			delete(m.agents, id)
+			delete(m.agentTenant, id)
+		}
+	}
+	for id, s := range agents {
+		if s.IsZero() {
+			continue
+		}
+		m.agents[id] = s
+		m.agentTenant[id] = tenantID
+	}
+}
+
+// Resolve returns the effective provider and auto mode for a tenant/agent pair.
+// Either ID may be empty. Providers that are not registered are skipped so a
+// stale override never disables synthesis. Agent overrides apply only within
+// the tenant that owns the agent.
+func (m *Manager) Resolve(tenantID, agentID string) ScopeSettings {
+	out := ScopeSettings{Provider: m.primary, Auto: m.auto}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var agent ScopeSettings
+	if m.agentTenant[agentID] == tenantID {
+		agent = m.agents[agentID]
+	}
+	for _, s := range []ScopeSettings{m.tenants[tenantID], agent} {
+		if _, ok := m.providers[s.Provider]; ok {
+			out.Provider = s.Provider
+		}
+		if s.Auto != "" {
+			out.Auto = s.Auto
+		}
+	}
+	return out
+}
+
+// Scope returns the raw (unresolved) overrides stored for a tenant and agent.
+func (m *Manager) Scope(tenantID, agentID string) (tenant, agent ScopeSettings) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.tenants[tenantID], m.agents[agentID]
+}
+
+// InheritScopes copies scope overrides from a previous manager.
+// Used when the manager is rebuilt on config reload so runtime toggles survive.
+func (m *Manager) InheritScopes(old *Manager) {
+	if old == nil || old == m {
+		return
+	}
+	old.mu.RLock()
+	defer old.mu.RUnlock()
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for k, v := range old.tenants {
+		m.tenants[k] = v
+	}
+	for k, v := range old.agents {
+		m.agents[k] = v
+		m.agentTenant[k] = old.agentTenant[k]
+	}
+}
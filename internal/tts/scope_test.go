@@ -0,0 +1,125 @@
+package tts
+
+import (
+	"context"
+	"testing"
+)
+
+type stubProvider struct{ name string }
+
+func (p stubProvider) Name() string { return p.name }
+func (p stubProvider) Synthesize(context.Context, string, Options) (*SynthResult, error) {
+	return &SynthResult{Audio: []byte(p.name), Extension: "mp3", MimeType: "audio/mpeg"}, nil
+}
+
+func newTestManager() *Manager {
+	m := NewManager(ManagerConfig{Primary: "edge"})
+	m.RegisterProvider(stubProvider{"edge"})
+	m.RegisterProvider(stubProvider{"openai"})
+	m.RegisterProvider(stubProvider{"elevenlabs"})
+	return m
+}
+
+func TestParseScopeConfigs(t *testing.T) {
+	tenant, agents := ParseScopeConfigs(map[string]string{
+		"tts.provider":          "openai",
+		"tts.auto":              "bogus",
+		"tts.mode":              "final",
+		"tts.agent.a1.provider": "elevenlabs",
+		"tts.agent.a1.auto":     "inbound",
+		"tts.agent.a2.auto":     "nope",
+		"tts.agent.a3.voice":    "alloy",
+		"tts.agent.malformed":   "x",
+		"tts.agent.a4.provider": "",
+		"embedding.provider":    "openai",
+	})
+	if tenant.Provider != "openai" || tenant.Auto != "" {
+		t.Fatalf("tenant = %+v, want provider=openai auto=''", tenant)
+	}
+	if got := agents["a1"]; got.Provider != "elevenlabs" || got.Auto != AutoInbound {
+		t.Fatalf("agents[a1] = %+v", got)
+	}
+	for _, id := range []string{"a2", "a3", "a4", "malformed"} {
+		if _, ok := agents[id]; ok {
+			t.Fatalf("agents[%s] should be absent, got %+v", id, agents[id])
+		}
+	}
+}
+
+func TestResolve_Precedence(t *testing.T) {
+	m := newTestManager()
+	m.SetTenantScope("t1", ScopeSettings{Provider: "openai", Auto: AutoAlways}, map[string]ScopeSettings{
+		"a1": {Provider: "elevenlabs"},
+		"a2": {Provider: "missing", Auto: AutoTagged},
+	})
+
+	if got := m.Resolve("", ""); got.Provider != "edge" || got.Auto != AutoOff {
+		t.Fatalf("defaults = %+v", got)
+	}
+	if got := m.Resolve("t1", ""); got.Provider != "openai" || got.Auto != AutoAlways {
+		t.Fatalf("tenant = %+v", got)
+	}
+	if got := m.Resolve("t1", "a1"); got.Provider != "elevenlabs" || got.Auto != AutoAlways {
+		t.Fatalf("agent a1 = %+v", got)
+	}
+	// Unregistered provider override is ignored; auto still applies.
+	if got := m.Resolve("t1", "a2"); got.Provider != "openai" || got.Auto != AutoTagged {
+		t.Fatalf("agent a2 = %+v", got)
+	}
+}
+
+func TestSetTenantScope_ReplacesAgents(t *testing.T) {
+	m := newTestManager()
+	m.SetTenantScope("t1", ScopeSettings{}, map[string]ScopeSettings{"a1": {Provider: "openai"}})
+	m.SetTenantScope("t2", ScopeSettings{}, map[string]ScopeSettings{"b1": {Provider: "openai"}})
+
+	// Reloading t1 without a1 must drop it but keep t2's agents.
+	m.SetTenantScope("t1", ScopeSettings{}, nil)
+	if _, a := m.Scope("t1", "a1"); !a.IsZero() {
+		t.Fatalf("a1 should be cleared, got %+v", a)
+	}
+	if _, b := m.Scope("t2", "b1"); b.Provider != "openai" {
+		t.Fatalf("b1 should survive, got %+v", b)
+	}
+}
+
+func TestInheritScopes(t *testing.T) {
+	old := newTestManager()
+	old.SetTenantScope("t1", ScopeSettings{Auto: AutoInbound}, map[string]ScopeSettings{"a1": {Provider: "openai"}})
+
+	m := newTestManager()
+	m.InheritScopes(old)
+	if got := m.Resolve("t1", "a1"); got.Provider != "openai" || got.Auto != AutoInbound {
+		t.Fatalf("inherited = %+v", got)
+	}
+}
+
+func TestSynthesizePreferring(t *testing.T) {
+	m := newTestManager()
+	res, err := m.SynthesizePreferring(context.Background(), "openai", "hello", Options{})
+	if err != nil || string(res.Audio) != "openai" {
+		t.Fatalf("preferred: res=%v err=%v", res, err)
+	}
+	res, err = m.SynthesizePreferring(context.Background(), "unknown", "hello", Options{})
+	if err != nil || string(res.Audio) != "edge" {
+		t.Fatalf("unknown falls back to primary: res=%v err=%v", res, err)
+	}
+}
+
+func TestMaybeApply_UsesScope(t *testing.T) {
+	m := newTestManager()
+	m.SetTenantScope("t1", ScopeSettings{Auto: AutoAlways}, map[string]ScopeSettings{"a1": {Provider: "openai"}})
+	text := "This reply is long enough to be spoken aloud."
+
+	if _, ok := m.MaybeApply(context.Background(), "t2", "", text, "ws", false, "final"); ok {
+		t.Fatal("global auto=off should not apply for a tenant without overrides")
+	}
+	res, ok := m.MaybeApply(context.Background(), "t1", "a1", text, "ws", false, "final")
+	if !ok || string(res.Audio) != "openai" {
+		t.Fatalf("agent scope: res=%v ok=%v, want openai audio", res, ok)
+	}
+	// An agent ID from another tenant does not carry its overrides over.
+	if got := m.Resolve("t2", "a1"); got.Provider != "edge" || got.Auto != AutoOff {
+		t.Fatalf("cross-tenant agent = %+v", got)
+	}
+}
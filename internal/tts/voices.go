@@ -0,0 +1,78 @@
+package tts
+
+// VoiceLister is implemented by providers that can enumerate their voices.
+// Lists are static catalogs of well-known voices — providers still accept
+// any voice ID their upstream API understands.
+type VoiceLister interface {
+	Voices() []Voice
+}
+
+// Voice describes a selectable provider voice.
+type Voice struct {
+	ID       string `json:"id"`
+	Name     string `json:"name,omitempty"`
+	Language string `json:"language,omitempty"`
+	Default  bool   `json:"default,omitempty"`
+}
+
+// markDefault returns a copy of voices with Default set on the entry matching id.
+// If id is not in the catalog, it is prepended so the configured voice is always listed.
+func markDefault(voices []Voice, id string) []Voice {
+	out := make([]Voice, 0, len(voices)+1)
+	found := false
+	for _, v := range voices {
+		if v.ID == id {
+			v.Default = true
+			found = true
+		}
+		out = append(out, v)
+	}
+	if !found && id != "" {
+		out = append([]Voice{{ID: id, Default: true}}, out...)
+	}
+	return out
+}
+
+var openAIVoices = []Voice{
+	{ID: "alloy"}, {ID: "ash"}, {ID: "ballad"}, {ID: "coral"}, {ID: "echo"},
+	{ID: "fable"}, {ID: "nova"}, {ID: "onyx"}, {ID: "sage"}, {ID: "shimmer"},
+}
+
+// Voices returns the OpenAI built-in voices.
+func (p *OpenAIProvider) Voices() []Voice { return markDefault(openAIVoices, p.voice) }
+
+var edgeVoices = []Voice{
+	{ID: "en-US-MichelleNeural", Name: "Michelle", Language: "en-US"},
+	{ID: "en-US-GuyNeural", Name: "Guy", Language: "en-US"},
+	{ID: "en-US-AriaNeural", Name: "Aria", Language: "en-US"},
+	{ID: "en-GB-SoniaNeural", Name: "Sonia", Language: "en-GB"},
+	{ID: "vi-VN-HoaiMyNeural", Name: "HoaiMy", Language: "vi-VN"},
+	{ID: "vi-VN-NamMinhNeural", Name: "NamMinh", Language: "vi-VN"},
+	{ID: "zh-CN-XiaoxiaoNeural", Name: "Xiaoxiao", Language: "zh-CN"},
+	{ID: "ja-JP-NanamiNeural", Name: "Nanami", Language: "ja-JP"},
+	{ID: "de-DE-KatjaNeural", Name: "Katja", Language: "de-DE"},
+	{ID: "fr-FR-DeniseNeural", Name: "Denise", Language: "fr-FR"},
+}
+
+// Voices returns a curated subset of Edge neural voices.
+func (p *EdgeProvider) Voices() []Voice { return markDefault(edgeVoices, p.voice) }
+
+var elevenLabsVoices = []Voice{
+	{ID: "pMsXgVXv3BLzUgSXRplE", Name: "Serena"},
+	{ID: "21m00Tcm4TlvDq8ikWAM", Name: "Rachel"},
+	{ID: "EXAVITQu4vr4xnSDxMaL", Name: "Bella"},
+	{ID: "ErXwobaYiN019PkySvjV", Name: "Antoni"},
+	{ID: "TxGEqnHWrfWFTfGW9XjX", Name: "Josh"},
+}
+
+// Voices returns ElevenLabs premade voices.
+func (p *ElevenLabsProvider) Voices() []Voice { return markDefault(elevenLabsVoices, p.voiceID) }
+
+var miniMaxVoices = []Voice{
+	{ID: "Wise_Woman"}, {ID: "Friendly_Person"}, {ID: "Inspirational_girl"},
+	{ID: "Deep_Voice_Man"}, {ID: "Calm_Woman"}, {ID: "Casual_Guy"},
+	{ID: "Lively_Girl"}, {ID: "Patient_Man"}, {ID: "Young_Knight"},
+}
+
+// Voices returns MiniMax system voices.
+func (p *MiniMaxProvider) Voices() []Voice { return markDefault(miniMaxVoices, p.voiceID) }
@@ -0,0 +1,33 @@
+-- Durable message queue: bus messages persisted for at-least-once delivery,
+-- crash replay, outbound retries and a dead-letter view.
+CREATE TABLE IF NOT EXISTS message_queue (
+    id               UUID PRIMARY KEY DEFAULT uuid_generate_v7(),
+    tenant_id        UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    direction        VARCHAR(10) NOT NULL,
+    channel          VARCHAR(255) NOT NULL,
+    idempotency_key  VARCHAR(500) NOT NULL DEFAULT '',
+    payload          JSONB NOT NULL,
+    status           VARCHAR(20) NOT NULL DEFAULT 'queued',
+    attempts         INT NOT NULL DEFAULT 0,
+    last_error       TEXT NOT NULL DEFAULT '',
+    owner            VARCHAR(255) NOT NULL DEFAULT '',
+    next_attempt_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    created_at       TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at       TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Idempotency: one row per channel message ID and direction.
+CREATE UNIQUE INDEX idx_message_queue_idempotency
+    ON message_queue(direction, idempotency_key) WHERE idempotency_key <> '';
+
+-- Redelivery pump: due pending rows.
+CREATE INDEX idx_message_queue_due
+    ON message_queue(next_attempt_at) WHERE status = 'pending';
+
+-- Crash replay: rows a replica held in memory.
+CREATE INDEX idx_message_queue_owner
+    ON message_queue(owner) WHERE status = 'queued';
+
+-- Dead-letter view and pruning.
+CREATE INDEX idx_message_queue_status
+    ON message_queue(tenant_id, status, updated_at DESC);
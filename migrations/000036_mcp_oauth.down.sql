@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS mcp_oauth_tokens;
+DROP TABLE IF EXISTS mcp_oauth_clients;
@@ -0,0 +1,31 @@
+-- OAuth 2.1 authorization for remote MCP servers: one dynamically registered
+-- client per server, and one refreshable token per server and user.
+-- Secrets and tokens are encrypted by the application.
+CREATE TABLE IF NOT EXISTS mcp_oauth_clients (
+    id             UUID PRIMARY KEY DEFAULT uuid_generate_v7(),
+    server_id      UUID NOT NULL REFERENCES mcp_servers(id) ON DELETE CASCADE,
+    tenant_id      UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    client_id      TEXT NOT NULL,
+    client_secret  TEXT,
+    redirect_uri   TEXT NOT NULL,
+    created_at     TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at     TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (server_id, tenant_id)
+);
+
+CREATE TABLE IF NOT EXISTS mcp_oauth_tokens (
+    id             UUID PRIMARY KEY DEFAULT uuid_generate_v7(),
+    server_id      UUID NOT NULL REFERENCES mcp_servers(id) ON DELETE CASCADE,
+    user_id        VARCHAR(255) NOT NULL,
+    tenant_id      UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    access_token   TEXT NOT NULL,
+    refresh_token  TEXT,
+    token_type     VARCHAR(50) NOT NULL DEFAULT 'Bearer',
+    scope          TEXT NOT NULL DEFAULT '',
+    expires_at     TIMESTAMPTZ,
+    created_at     TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at     TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (server_id, user_id, tenant_id)
+);
+
+CREATE INDEX idx_mcp_oauth_tokens_tenant ON mcp_oauth_tokens(tenant_id);
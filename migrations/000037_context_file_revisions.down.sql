@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS context_file_changes;
+DROP TABLE IF EXISTS context_file_revisions;
@@ -0,0 +1,48 @@
+-- Version history for agent-level and per-user context files (SOUL.md,
+-- IDENTITY.md, ...). Every content change is one row; user_id '' marks the
+-- agent-level file.
+CREATE TABLE IF NOT EXISTS context_file_revisions (
+    id          UUID PRIMARY KEY DEFAULT uuid_generate_v7(),
+    tenant_id   UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    agent_id    UUID NOT NULL REFERENCES agents(id) ON DELETE CASCADE,
+    user_id     VARCHAR(255) NOT NULL DEFAULT '',
+    file_name   VARCHAR(255) NOT NULL,
+    content     TEXT NOT NULL DEFAULT '',
+    author      VARCHAR(20) NOT NULL,
+    author_id   VARCHAR(255) NOT NULL DEFAULT '',
+    trace_id    UUID,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_context_file_revisions_file ON context_file_revisions(agent_id, user_id, file_name, created_at DESC);
+CREATE INDEX idx_context_file_revisions_tenant ON context_file_revisions(tenant_id);
+
+-- Agent edits to identity files held for owner approval.
+CREATE TABLE IF NOT EXISTS context_file_changes (
+    id           UUID PRIMARY KEY DEFAULT uuid_generate_v7(),
+    tenant_id    UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    agent_id     UUID NOT NULL REFERENCES agents(id) ON DELETE CASCADE,
+    user_id      VARCHAR(255) NOT NULL DEFAULT '',
+    file_name    VARCHAR(255) NOT NULL,
+    content      TEXT NOT NULL DEFAULT '',
+    author       VARCHAR(20) NOT NULL,
+    author_id    VARCHAR(255) NOT NULL DEFAULT '',
+    trace_id     UUID,
+    status       VARCHAR(20) NOT NULL DEFAULT 'pending',
+    reviewed_by  VARCHAR(255) NOT NULL DEFAULT '',
+    reviewed_at  TIMESTAMPTZ,
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_context_file_changes_agent ON context_file_changes(agent_id, status, created_at);
+CREATE INDEX idx_context_file_changes_tenant ON context_file_changes(tenant_id);
+
+-- Baseline: current content of every file becomes its first revision, so
+-- edits made after the upgrade can be rolled back.
+INSERT INTO context_file_revisions (tenant_id, agent_id, user_id, file_name, content, author, created_at)
+SELECT tenant_id, agent_id, '', file_name, content, 'system', COALESCE(updated_at, NOW())
+FROM agent_context_files;
+
+INSERT INTO context_file_revisions (tenant_id, agent_id, user_id, file_name, content, author, created_at)
+SELECT tenant_id, agent_id, user_id, file_name, content, 'system', COALESCE(updated_at, NOW())
+FROM user_context_files;
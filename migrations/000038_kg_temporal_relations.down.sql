@@ -0,0 +1,12 @@
+-- Closed facts cannot satisfy the old unique constraint; drop the history.
+DELETE FROM kg_relations WHERE valid_to IS NOT NULL;
+
+DROP INDEX IF EXISTS idx_kg_relations_validity;
+DROP INDEX IF EXISTS idx_kg_relations_current;
+ALTER TABLE kg_relations ADD CONSTRAINT kg_relations_agent_id_user_id_source_entity_id_relation_typ_key
+    UNIQUE (agent_id, user_id, source_entity_id, relation_type, target_entity_id);
+
+ALTER TABLE kg_relations DROP COLUMN IF EXISTS provenance;
+ALTER TABLE kg_relations DROP COLUMN IF EXISTS superseded_by;
+ALTER TABLE kg_relations DROP COLUMN IF EXISTS valid_to;
+ALTER TABLE kg_relations DROP COLUMN IF EXISTS valid_from;
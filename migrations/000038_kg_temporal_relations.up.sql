@@ -0,0 +1,27 @@
+-- Temporal knowledge graph: relations become facts with a validity interval.
+-- valid_to IS NULL marks the current version; closed versions are kept as
+-- history. superseded_by points at the fact that replaced a closed one, and
+-- provenance records the session message or document a fact came from.
+ALTER TABLE kg_relations ADD COLUMN valid_from    TIMESTAMPTZ;
+ALTER TABLE kg_relations ADD COLUMN valid_to      TIMESTAMPTZ;
+ALTER TABLE kg_relations ADD COLUMN superseded_by UUID;
+ALTER TABLE kg_relations ADD COLUMN provenance    JSONB NOT NULL DEFAULT '{}';
+
+UPDATE kg_relations SET valid_from = COALESCE(created_at, NOW());
+ALTER TABLE kg_relations ALTER COLUMN valid_from SET DEFAULT NOW();
+ALTER TABLE kg_relations ALTER COLUMN valid_from SET NOT NULL;
+
+-- Only one current version per (source, type, target); history rows may repeat.
+DO $$
+DECLARE c TEXT;
+BEGIN
+    SELECT conname INTO c FROM pg_constraint
+    WHERE conrelid = 'kg_relations'::regclass AND contype = 'u';
+    IF c IS NOT NULL THEN
+        EXECUTE format('ALTER TABLE kg_relations DROP CONSTRAINT %I', c);
+    END IF;
+END $$;
+
+CREATE UNIQUE INDEX idx_kg_relations_current ON kg_relations(agent_id, user_id, source_entity_id, relation_type, target_entity_id)
+    WHERE valid_to IS NULL;
+CREATE INDEX idx_kg_relations_validity ON kg_relations(agent_id, user_id, valid_from, valid_to);
@@ -0,0 +1,45 @@
+-- Golden conversations replayed against an agent by the eval harness.
+-- Cases (turns, tool expectations, assertions, stubs) are stored as JSON.
+CREATE TABLE IF NOT EXISTS eval_suites (
+    id           UUID PRIMARY KEY DEFAULT uuid_generate_v7(),
+    tenant_id    UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    agent_id     UUID NOT NULL REFERENCES agents(id) ON DELETE CASCADE,
+    name         VARCHAR(255) NOT NULL,
+    description  TEXT NOT NULL DEFAULT '',
+    judge_model  VARCHAR(255) NOT NULL DEFAULT '',
+    cases        JSONB NOT NULL DEFAULT '[]',
+    created_by   VARCHAR(255) NOT NULL DEFAULT '',
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at   TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (agent_id, name)
+);
+
+CREATE INDEX idx_eval_suites_tenant ON eval_suites(tenant_id);
+
+-- One execution of a suite. results holds per-case verdicts, tool calls and
+-- trace IDs; the totals are denormalized for listing.
+CREATE TABLE IF NOT EXISTS eval_runs (
+    id             UUID PRIMARY KEY DEFAULT uuid_generate_v7(),
+    tenant_id      UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    suite_id       UUID NOT NULL REFERENCES eval_suites(id) ON DELETE CASCADE,
+    agent_id       UUID NOT NULL REFERENCES agents(id) ON DELETE CASCADE,
+    suite_name     VARCHAR(255) NOT NULL,
+    status         VARCHAR(20) NOT NULL DEFAULT 'running',
+    tool_mode      VARCHAR(20) NOT NULL DEFAULT 'stub',
+    total          INT NOT NULL DEFAULT 0,
+    passed         INT NOT NULL DEFAULT 0,
+    failed         INT NOT NULL DEFAULT 0,
+    input_tokens   INT NOT NULL DEFAULT 0,
+    output_tokens  INT NOT NULL DEFAULT 0,
+    cost_usd       NUMERIC(12,6) NOT NULL DEFAULT 0,
+    duration_ms    INT NOT NULL DEFAULT 0,
+    error          TEXT NOT NULL DEFAULT '',
+    results        JSONB NOT NULL DEFAULT '[]',
+    created_by     VARCHAR(255) NOT NULL DEFAULT '',
+    started_at     TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    finished_at    TIMESTAMPTZ
+);
+
+CREATE INDEX idx_eval_runs_agent ON eval_runs(agent_id, started_at DESC);
+CREATE INDEX idx_eval_runs_suite ON eval_runs(suite_id, started_at DESC);
+CREATE INDEX idx_eval_runs_tenant ON eval_runs(tenant_id);
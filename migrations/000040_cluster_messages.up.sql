@@ -0,0 +1,10 @@
+-- Cluster messages too large for a NOTIFY payload (8000 bytes). The sender
+-- stores the message here and notifies its id; rows are pruned after a few
+-- minutes, once every listener has read them.
+CREATE TABLE IF NOT EXISTS cluster_messages (
+    id          BIGSERIAL PRIMARY KEY,
+    payload     BYTEA NOT NULL,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_cluster_messages_created ON cluster_messages(created_at);
@@ -0,0 +1,16 @@
+-- MCP OAuth authorizations awaiting the authorization server's callback.
+-- Stored so the callback can land on any gateway replica; rows expire after
+-- ten minutes and are removed when taken or when a new flow starts.
+CREATE TABLE IF NOT EXISTS mcp_oauth_pending (
+    state       VARCHAR(255) PRIMARY KEY,
+    tenant_id   UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    server_id   UUID NOT NULL REFERENCES mcp_servers(id) ON DELETE CASCADE,
+    user_id     VARCHAR(255) NOT NULL,
+    verifier    TEXT NOT NULL,
+    channel     VARCHAR(255) NOT NULL DEFAULT '',
+    chat_id     VARCHAR(255) NOT NULL DEFAULT '',
+    expires_at  TIMESTAMPTZ NOT NULL,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_mcp_oauth_pending_expires ON mcp_oauth_pending(expires_at);
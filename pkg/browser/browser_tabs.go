@@ -224,3 +224,37 @@ func (m *Manager) ConsoleMessages(ctx context.Context, targetID string) []Consol
 	m.console[targetID] = nil
 	return result
 }
+
+// ActiveTab returns the targetID of the tenant's most recently used tab, or ""
+// when the tenant has no tracked tabs. Operators use this to follow the tab an
+// agent is currently driving without knowing its targetID.
+func (m *Manager) ActiveTab(ctx context.Context) string {
+	tenantID := tenantIDFromCtx(ctx)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.activeTabLocked(tenantID)
+}
+
+// activeTabLocked picks the most recently touched page owned by tenantID.
+// Must be called with mu held.
+func (m *Manager) activeTabLocked(tenantID string) string {
+	isMaster := tenantID == "" || tenantID == MasterTenantID
+
+	var activeID string
+	var activeTime time.Time
+	for tid := range m.pages {
+		owner, hasOwner := m.pageTenants[tid]
+		if !isMaster && owner != tenantID {
+			continue
+		}
+		if isMaster && hasOwner && owner != MasterTenantID {
+			continue
+		}
+		lu := m.pageLastUsed[tid]
+		if activeID == "" || lu.After(activeTime) {
+			activeID = tid
+			activeTime = lu
+		}
+	}
+	return activeID
+}
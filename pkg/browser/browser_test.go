@@ -7,6 +7,7 @@ import (
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 )
 
 // --- resolveToIPv4 ---
@@ -222,3 +223,32 @@ func TestManagerStatusWhenStopped(t *testing.T) {
 		t.Error("Status.Running should be false when browser is nil")
 	}
 }
+
+func TestActiveTabLocked_TenantScoped(t *testing.T) {
+	m := New()
+	now := time.Now()
+	m.pages["a"] = nil
+	m.pages["b"] = nil
+	m.pages["c"] = nil
+	m.pages["m"] = nil
+	m.pageTenants["a"] = "tenant-1"
+	m.pageTenants["b"] = "tenant-1"
+	m.pageTenants["c"] = "tenant-2"
+	m.pageLastUsed["a"] = now.Add(-time.Minute)
+	m.pageLastUsed["b"] = now
+	m.pageLastUsed["c"] = now.Add(time.Minute)
+	m.pageLastUsed["m"] = now.Add(-time.Hour)
+
+	if got := m.activeTabLocked("tenant-1"); got != "b" {
+		t.Errorf("tenant-1 active tab = %q, want b", got)
+	}
+	if got := m.activeTabLocked("tenant-2"); got != "c" {
+		t.Errorf("tenant-2 active tab = %q, want c", got)
+	}
+	if got := m.activeTabLocked(""); got != "m" {
+		t.Errorf("master active tab = %q, want m", got)
+	}
+	if got := m.activeTabLocked("tenant-3"); got != "" {
+		t.Errorf("unknown tenant active tab = %q, want empty", got)
+	}
+}
@@ -9,6 +9,8 @@ const (
 	EventHeartbeat          = "heartbeat"
 	EventExecApprovalReq    = "exec.approval.requested"
 	EventExecApprovalRes    = "exec.approval.resolved"
+	EventToolApprovalReq    = "tool.approval.requested"
+	EventToolApprovalRes    = "tool.approval.resolved"
 	EventPresence           = "presence"
 	EventTick               = "tick"
 	EventShutdown           = "shutdown"
@@ -109,8 +111,14 @@ const (
 	EventZaloPersonalQRCode = "zalo.personal.qr.code"
 	EventZaloPersonalQRDone = "zalo.personal.qr.done"
 
+	// TTS runtime settings changed via tts.enable/disable/setProvider.
+	EventTTSChanged = "tts.changed"
+
 	// Tenant access revocation — forces affected user's UI to logout.
 	EventTenantAccessRevoked = "tenant.access.revoked"
+
+	// Spend budget soft/hard threshold crossed (admin-only).
+	EventBudgetThreshold = "budget.threshold"
 )
 
 // Agent event subtypes (in payload.type)
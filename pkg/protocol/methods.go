@@ -26,6 +26,14 @@ const (
 	MethodAgentsFileGet  = "agents.files.get"
 	MethodAgentsFileSet  = "agents.files.set"
 
+	// Context file revisions (history, rollback, approval of agent edits)
+	MethodAgentsFileHistory = "agents.files.history"
+	MethodAgentsFileDiff    = "agents.files.diff"
+	MethodAgentsFileRestore = "agents.files.restore"
+	MethodAgentsFilePending = "agents.files.pending"
+	MethodAgentsFileApprove = "agents.files.approve"
+	MethodAgentsFileReject  = "agents.files.reject"
+
 	// Config
 	MethodConfigGet    = "config.get"
 	MethodConfigApply  = "config.apply"
@@ -76,8 +84,13 @@ const (
 	MethodApprovalsApprove = "exec.approval.approve"
 	MethodApprovalsDeny    = "exec.approval.deny"
 
+	MethodToolApprovalsList    = "tool.approval.list"
+	MethodToolApprovalsApprove = "tool.approval.approve"
+	MethodToolApprovalsDeny    = "tool.approval.deny"
+
 	MethodUsageGet     = "usage.get"
 	MethodUsageSummary = "usage.summary"
+	MethodUsageBudget  = "usage.budget"
 
 	MethodQuotaUsage = "quota.usage"
 
@@ -139,6 +152,7 @@ const (
 	MethodTeamsTaskDeleteBulk = "teams.tasks.delete-bulk"
 	MethodTeamsTaskAssign            = "teams.tasks.assign"
 	MethodTeamsTaskActiveBySession   = "teams.tasks.active-by-session"
+	MethodTeamsTaskGraph             = "teams.tasks.graph"
 	MethodTeamsMembersAdd    = "teams.members.add"
 	MethodTeamsMembersRemove = "teams.members.remove"
 	MethodTeamsUpdate        = "teams.update"
@@ -158,6 +172,12 @@ const (
 	MethodTeamsEventsList = "teams.events.list"
 )
 
+// MCP prompt templates
+const (
+	MethodMCPPromptsList = "mcp.prompts.list"
+	MethodMCPPromptsGet  = "mcp.prompts.get"
+)
+
 // API key management
 const (
 	MethodAPIKeysList   = "api_keys.list"
@@ -179,6 +199,7 @@ const (
 	MethodBrowserAct        = "browser.act"
 	MethodBrowserSnapshot   = "browser.snapshot"
 	MethodBrowserScreenshot = "browser.screenshot"
+	MethodBrowserTabs       = "browser.tabs"
 
 	// Zalo Personal
 	MethodZaloPersonalQRStart   = "zalo.personal.qr.start"
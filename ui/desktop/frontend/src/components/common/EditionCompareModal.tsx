@@ -36,7 +36,8 @@ const FEATURES: FeatureGroup[] = [
       { key: 'tools', lite: true, standard: true },
       { key: 'mcpServers', lite: true, standard: true },
       { key: 'skills', lite: true, standard: true },
-      { key: 'memory', lite: 'FTS5', standard: 'pgvector' },
+      { key: 'memory', lite: 'FTS5 + int8 vectors', standard: 'pgvector' },
+      { key: 'vectorSearch', lite: true, standard: true },
       { key: 'cron', lite: true, standard: true },
       { key: 'traces', lite: 'Compact', standard: 'Full' },
     ],
@@ -49,7 +50,6 @@ const FEATURES: FeatureGroup[] = [
       { key: 'storage', lite: false, standard: true },
       { key: 'skillManage', lite: false, standard: true },
       { key: 'knowledgeGraph', lite: false, standard: true },
-      { key: 'vectorSearch', lite: false, standard: true },
       { key: 'rbac', lite: false, standard: true },
       { key: 'multiTenant', lite: false, standard: true },
       { key: 'tenantUsers', lite: false, standard: true },
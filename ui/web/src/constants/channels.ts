@@ -6,4 +6,6 @@ export const CHANNEL_TYPES = [
   { value: "zalo_oa", label: "Zalo OA" },
   { value: "zalo_personal", label: "Zalo Personal" },
   { value: "whatsapp", label: "WhatsApp" },
+  { value: "webhook", label: "Webhook" },
+  { value: "email", label: "Email (IMAP/SMTP)" },
 ] as const;
@@ -71,6 +71,14 @@ export const credentialsSchema: Record<string, FieldDef[]> = {
   whatsapp: [
     { key: "bridge_url", label: "Bridge URL", type: "text", required: true, placeholder: "http://bridge:3000" },
   ],
+  webhook: [
+    { key: "secret", label: "Signing Secret", type: "password", required: true, help: "HMAC-SHA256 key: verifies inbound requests and signs outbound callbacks" },
+    { key: "callback_token", label: "Callback Bearer Token", type: "password", help: "Sent as Authorization: Bearer on outbound callbacks" },
+  ],
+  email: [
+    { key: "username", label: "Username", type: "text", help: "IMAP/SMTP login (defaults to the mailbox address)" },
+    { key: "password", label: "Password", type: "password", required: true, help: "Mailbox password or app password" },
+  ],
 };
 
 // --- Config schemas ---
@@ -154,6 +162,32 @@ export const configSchema: Record<string, FieldDef[]> = {
     { key: "allow_from", label: "Allowed Users", type: "tags", help: "WhatsApp user IDs" },
     { key: "block_reply", label: "Block Reply", type: "select", options: blockReplyOptions, defaultValue: "inherit", help: "Deliver intermediate text during tool iterations" },
   ],
+  webhook: [
+    { key: "callback_url", label: "Callback URL", type: "text", placeholder: "https://tools.internal/goclaw/reply", help: "Agent replies are POSTed here. Leave empty for inbound-only." },
+    { key: "sender_path", label: "Sender Path", type: "text", placeholder: "$.sender", help: "JSONPath to the sender ID in the inbound payload" },
+    { key: "chat_path", label: "Chat Path", type: "text", placeholder: "$.chat_id", help: "JSONPath to the conversation ID (defaults to sender)" },
+    { key: "content_path", label: "Content Path", type: "text", placeholder: "$.text", help: "JSONPath to the message text" },
+    { key: "peer_kind_path", label: "Peer Kind Path", type: "text", help: "Optional JSONPath; value \"group\" marks a group conversation" },
+    { key: "replay_window", label: "Replay Window (seconds)", type: "number", defaultValue: 300 },
+    { key: "max_retries", label: "Callback Retries", type: "number", defaultValue: 3 },
+    { key: "allow_from", label: "Allowed Senders", type: "tags", help: "Sender IDs (empty = any signed request)" },
+    { key: "block_reply", label: "Block Reply", type: "select", options: blockReplyOptions, defaultValue: "inherit", help: "Deliver intermediate text during tool iterations" },
+  ],
+  email: [
+    { key: "address", label: "Mailbox Address", type: "text", required: true, placeholder: "assistant@example.com" },
+    { key: "display_name", label: "Display Name", type: "text", help: "Shown in the From: header of replies" },
+    { key: "imap_host", label: "IMAP Host", type: "text", required: true, placeholder: "imap.example.com" },
+    { key: "imap_port", label: "IMAP Port", type: "number", help: "Default 993 (TLS) or 143 (none)" },
+    { key: "imap_security", label: "IMAP Security", type: "select", options: [{ value: "tls", label: "TLS" }, { value: "none", label: "None" }], defaultValue: "tls" },
+    { key: "mailbox", label: "Mailbox", type: "text", defaultValue: "INBOX" },
+    { key: "smtp_host", label: "SMTP Host", type: "text", required: true, placeholder: "smtp.example.com" },
+    { key: "smtp_port", label: "SMTP Port", type: "number", help: "Default 587 (STARTTLS), 465 (TLS) or 25 (none)" },
+    { key: "smtp_security", label: "SMTP Security", type: "select", options: [{ value: "starttls", label: "STARTTLS" }, { value: "tls", label: "TLS" }, { value: "none", label: "None" }], defaultValue: "starttls" },
+    { key: "poll_interval", label: "Poll Interval (seconds)", type: "number", defaultValue: 60 },
+    { key: "dm_policy", label: "Sender Policy", type: "select", options: dmPolicyOptions, defaultValue: "allowlist" },
+    { key: "allow_from", label: "Allowed Senders", type: "tags", help: "Addresses, or @domain to allow a whole domain" },
+    { key: "media_max_mb", label: "Max Attachment Size (MB)", type: "number", defaultValue: 20 },
+  ],
 };
 
 // --- Group override schema (Telegram per-group/topic overrides) ---
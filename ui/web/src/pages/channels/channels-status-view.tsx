@@ -16,6 +16,8 @@ const channelTypeLabels: Record<string, string> = {
   zalo_oa: "Zalo OA",
   zalo_personal: "Zalo Personal",
   whatsapp: "WhatsApp",
+  webhook: "Webhook",
+  email: "Email",
 };
 
 export { channelTypeLabels };
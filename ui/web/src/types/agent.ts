@@ -67,6 +67,23 @@ export interface MemoryConfig {
   vector_weight?: number;
   text_weight?: number;
   min_score?: number;
+  retrieval?: MemoryRetrievalConfig;
+}
+
+export interface MemoryRetrievalConfig {
+  candidate_multiplier?: number;
+  query_expansion?: "" | "paraphrase" | "hyde";
+  expansion_count?: number;
+  expansion_provider?: string;
+  expansion_model?: string;
+  rerank?: "" | "llm" | "cross_encoder";
+  rerank_provider?: string;
+  rerank_model?: string;
+  rerank_api_base?: string;
+  recency_half_life_days?: number;
+  recency_weight?: number;
+  path_boosts?: Record<string, number>;
+  mmr_lambda?: number;
 }
 
 export interface WorkspaceSharingConfig {
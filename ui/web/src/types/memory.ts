@@ -32,4 +32,5 @@ export interface MemorySearchResult {
   score: number;
   snippet: string;
   scope?: string;
+  updated_at?: number;
 }